@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeJSON(t *testing.T) {
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	spec.ServeJSON(rec, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi = %q, want 3.x", doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/v1/videos/{id}"]["get"]; !ok {
+		t.Error("spec does not document GET /v1/videos/{id}")
+	}
+}
+
+func TestServeDocs(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ServeDocs(rec, httptest.NewRequest(http.MethodGet, "/v1/docs", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !strings.Contains(rec.Body.String(), `url: "openapi.json"`) {
+		t.Error("docs page does not load openapi.json")
+	}
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+
+	"github.com/hszk-dev/gostream/internal/api/handler"
+)
+
+// errorCodeExtension names the error code the handlers use for a parameter or property,
+// so a value the spec rejects gets the same response the handler would send.
+const errorCodeExtension = "x-error-code"
+
+// defaultErrorCode is sent when no x-error-code applies, as for malformed JSON.
+const defaultErrorCode = "invalid_request"
+
+// Validate returns middleware that rejects requests whose parameters or JSON body do not
+// match the specification with 400 and the handler's error code. Routes the spec does not
+// describe are passed through, and only application/json bodies are read, so multipart
+// uploads and raw subtitle files still stream to their handlers.
+func (s *Spec) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := s.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				ExcludeRequestBody:  !hasJSONBody(route.Operation, r),
+				SkipSettingDefaults: true,
+				AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			code, message := describe(err)
+			handler.Error(w, http.StatusBadRequest, code, message)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hasJSONBody reports whether r sends the JSON body op accepts. Other bodies are left to
+// the handler, which checks their media type itself.
+func hasJSONBody(op *openapi3.Operation, r *http.Request) bool {
+	if op.RequestBody == nil || op.RequestBody.Value.Content.Get("application/json") == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// describe returns the error code and message for a failed validation.
+func describe(err error) (string, string) {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return defaultErrorCode, err.Error()
+	}
+
+	reason := reqErr.Reason
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		reason = schemaErr.Reason
+	} else if reqErr.Err != nil {
+		reason = reqErr.Err.Error()
+	}
+
+	switch {
+	case reqErr.Parameter != nil:
+		p := reqErr.Parameter
+		code := extensionCode(p.Extensions, defaultErrorCode)
+		return code, fmt.Sprintf("Invalid %s parameter %q: %s", p.In, p.Name, reason)
+	case reqErr.RequestBody != nil && schemaErr != nil:
+		code := bodyErrorCode(reqErr.RequestBody, schemaErr)
+		if field := strings.Join(schemaErr.JSONPointer(), "."); field != "" && schemaErr.SchemaField != "required" {
+			return code, fmt.Sprintf("Invalid request body field %q: %s", field, reason)
+		}
+		return code, fmt.Sprintf("Invalid request body: %s", reason)
+	case reqErr.RequestBody != nil:
+		return defaultErrorCode, fmt.Sprintf("Invalid request body: %s", reason)
+	default:
+		return defaultErrorCode, fmt.Sprintf("Invalid request: %s", reason)
+	}
+}
+
+// bodyErrorCode walks the body schema down to the rejected value and returns the
+// deepest x-error-code on the way. Values of the wrong JSON type fail to decode in the
+// handlers before any field is checked, so they keep the default code.
+func bodyErrorCode(body *openapi3.RequestBody, schemaErr *openapi3.SchemaError) string {
+	if schemaErr.SchemaField == "type" {
+		return defaultErrorCode
+	}
+	media := body.Content.Get("application/json")
+	if media == nil || media.Schema == nil {
+		return defaultErrorCode
+	}
+
+	schema := media.Schema.Value
+	code := extensionCode(schema.Extensions, defaultErrorCode)
+	for _, key := range schemaErr.JSONPointer() {
+		var next *openapi3.SchemaRef
+		if prop, ok := schema.Properties[key]; ok {
+			next = prop
+		} else if _, err := strconv.Atoi(key); err == nil && schema.Items != nil {
+			next = schema.Items
+		}
+		if next == nil || next.Value == nil {
+			break
+		}
+		schema = next.Value
+		code = extensionCode(schema.Extensions, code)
+	}
+	return code
+}
+
+func extensionCode(extensions map[string]any, fallback string) string {
+	if code, ok := extensions[errorCodeExtension].(string); ok && code != "" {
+		return code
+	}
+	return fallback
+}
@@ -0,0 +1,312 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	const videoID = "0b8e1c3a-4f7e-4b7a-9a6e-1f2d3c4b5a69"
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+	}{
+		{
+			name:        "valid create",
+			method:      http.MethodPost,
+			target:      "/v1/videos",
+			contentType: "application/json",
+			body:        `{"title":"My Video","file_name":"video.mp4"}`,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "missing title",
+			method:      http.MethodPost,
+			target:      "/v1/videos",
+			contentType: "application/json",
+			body:        `{"file_name":"video.mp4"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_title",
+		},
+		{
+			name:        "title too long",
+			method:      http.MethodPost,
+			target:      "/v1/videos",
+			contentType: "application/json",
+			body:        `{"title":"` + strings.Repeat("a", 256) + `","file_name":"video.mp4"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_title",
+		},
+		{
+			name:        "preview too long",
+			method:      http.MethodPost,
+			target:      "/v1/videos",
+			contentType: "application/json",
+			body:        `{"title":"My Video","file_name":"video.mp4","preview_seconds":601}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_preview_seconds",
+		},
+		{
+			name:        "wrong JSON type",
+			method:      http.MethodPost,
+			target:      "/v1/videos",
+			contentType: "application/json",
+			body:        `{"title":42,"file_name":"video.mp4"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_request",
+		},
+		{
+			name:        "malformed JSON",
+			method:      http.MethodPost,
+			target:      "/v1/videos",
+			contentType: "application/json",
+			body:        `{"title":`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_request",
+		},
+		{
+			name:        "multipart body is not read",
+			method:      http.MethodPost,
+			target:      "/v1/videos",
+			contentType: "multipart/form-data; boundary=x",
+			body:        "not multipart",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "invalid video ID",
+			method:     http.MethodGet,
+			target:     "/v1/videos/not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_video_id",
+		},
+		{
+			name:       "search is not a video ID",
+			method:     http.MethodGet,
+			target:     "/v1/videos/search?q=cats",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing search query",
+			method:     http.MethodGet,
+			target:     "/v1/videos/search",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_query",
+		},
+		{
+			name:       "invalid tenant ID",
+			method:     http.MethodGet,
+			target:     "/v1/tenants/not-a-uuid/domains",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_tenant_id",
+		},
+		{
+			name:       "limit below one",
+			method:     http.MethodGet,
+			target:     "/v1/users/" + videoID + "/videos?limit=0",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_limit",
+		},
+		{
+			name:       "limit not a number",
+			method:     http.MethodGet,
+			target:     "/v1/users/" + videoID + "/videos?limit=ten",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_limit",
+		},
+		{
+			name:       "invalid user ID for usage",
+			method:     http.MethodGet,
+			target:     "/v1/users/not-a-uuid/usage",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_user_id",
+		},
+		{
+			name:       "invalid dry_run",
+			method:     http.MethodPost,
+			target:     "/v1/videos/" + videoID + "/process?dry_run=maybe",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_dry_run",
+		},
+		{
+			name:        "empty upload parts",
+			method:      http.MethodPost,
+			target:      "/v1/videos/" + videoID + "/uploads/abc/complete",
+			contentType: "application/json",
+			body:        `{"parts":[]}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_parts",
+		},
+		{
+			name:        "negative progress position",
+			method:      http.MethodPut,
+			target:      "/v1/videos/" + videoID + "/progress",
+			contentType: "application/json",
+			body:        `{"position_seconds":-1}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_position",
+		},
+		{
+			name:        "subtitle file is not read",
+			method:      http.MethodPost,
+			target:      "/v1/videos/" + videoID + "/subtitles?language=en",
+			contentType: "text/vtt",
+			body:        "WEBVTT\n",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "missing subtitle language",
+			method:     http.MethodPost,
+			target:     "/v1/videos/" + videoID + "/subtitles",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_language",
+		},
+		{
+			name:        "valid metadata update",
+			method:      http.MethodPatch,
+			target:      "/v1/videos/" + videoID,
+			contentType: "application/json",
+			body:        `{"description":"","tags":["Go","go"]}`,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "description too long",
+			method:      http.MethodPatch,
+			target:      "/v1/videos/" + videoID,
+			contentType: "application/json",
+			body:        `{"description":"` + strings.Repeat("a", 5001) + `"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_description",
+		},
+		{
+			name:        "tag not a string",
+			method:      http.MethodPatch,
+			target:      "/v1/videos/" + videoID,
+			contentType: "application/json",
+			body:        `{"tags":["go",1]}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_request",
+		},
+		{
+			name:        "unknown visibility",
+			method:      http.MethodPatch,
+			target:      "/v1/videos/" + videoID,
+			contentType: "application/json",
+			body:        `{"visibility":"hidden"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_visibility",
+		},
+		{
+			name:       "invalid video ID for a view",
+			method:     http.MethodPost,
+			target:     "/v1/videos/not-a-uuid/views",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_video_id",
+		},
+		{
+			name:       "invalid playlist ID",
+			method:     http.MethodGet,
+			target:     "/v1/playlists/not-a-uuid/videos",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_playlist_id",
+		},
+		{
+			name:        "valid playlist",
+			method:      http.MethodPost,
+			target:      "/v1/playlists",
+			contentType: "application/json",
+			body:        `{"title":"Go course","video_ids":["` + videoID + `"]}`,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "malformed playlist video ID",
+			method:      http.MethodPost,
+			target:      "/v1/playlists",
+			contentType: "application/json",
+			body:        `{"title":"Go course","video_ids":["nope"]}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_video_ids",
+		},
+		{
+			name:        "duplicate playlist videos",
+			method:      http.MethodPut,
+			target:      "/v1/playlists/" + videoID + "/videos",
+			contentType: "application/json",
+			body:        `{"video_ids":["` + videoID + `","` + videoID + `"]}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_video_ids",
+		},
+		{
+			name:        "missing playlist videos",
+			method:      http.MethodPut,
+			target:      "/v1/playlists/" + videoID + "/videos",
+			contentType: "application/json",
+			body:        `{}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_video_ids",
+		},
+		{
+			name:       "undocumented route passes through",
+			method:     http.MethodGet,
+			target:     "/v1/unknown",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			spec.Validate(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				if gotBody != tt.body {
+					t.Errorf("handler body = %q, want %q", gotBody, tt.body)
+				}
+				return
+			}
+
+			var resp struct {
+				Error   string `json:"error"`
+				Message string `json:"message"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error != tt.wantCode {
+				t.Errorf("error = %q, want %q (%s)", resp.Error, tt.wantCode, resp.Message)
+			}
+			if resp.Message == "" {
+				t.Error("message is empty")
+			}
+		})
+	}
+}
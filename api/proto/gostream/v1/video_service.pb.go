@@ -0,0 +1,1054 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.29.3
+// source: gostream/v1/video_service.proto
+
+package gostreamv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VideoStatus int32
+
+const (
+	VideoStatus_VIDEO_STATUS_UNSPECIFIED    VideoStatus = 0
+	VideoStatus_VIDEO_STATUS_PENDING_UPLOAD VideoStatus = 1
+	VideoStatus_VIDEO_STATUS_UPLOADED       VideoStatus = 2
+	VideoStatus_VIDEO_STATUS_PROCESSING     VideoStatus = 3
+	VideoStatus_VIDEO_STATUS_READY          VideoStatus = 4
+	VideoStatus_VIDEO_STATUS_FAILED         VideoStatus = 5
+	VideoStatus_VIDEO_STATUS_DELETED        VideoStatus = 6
+	VideoStatus_VIDEO_STATUS_ARCHIVED       VideoStatus = 7
+	VideoStatus_VIDEO_STATUS_EXPIRED        VideoStatus = 8
+)
+
+// Enum value maps for VideoStatus.
+var (
+	VideoStatus_name = map[int32]string{
+		0: "VIDEO_STATUS_UNSPECIFIED",
+		1: "VIDEO_STATUS_PENDING_UPLOAD",
+		2: "VIDEO_STATUS_UPLOADED",
+		3: "VIDEO_STATUS_PROCESSING",
+		4: "VIDEO_STATUS_READY",
+		5: "VIDEO_STATUS_FAILED",
+		6: "VIDEO_STATUS_DELETED",
+		7: "VIDEO_STATUS_ARCHIVED",
+		8: "VIDEO_STATUS_EXPIRED",
+	}
+	VideoStatus_value = map[string]int32{
+		"VIDEO_STATUS_UNSPECIFIED":    0,
+		"VIDEO_STATUS_PENDING_UPLOAD": 1,
+		"VIDEO_STATUS_UPLOADED":       2,
+		"VIDEO_STATUS_PROCESSING":     3,
+		"VIDEO_STATUS_READY":          4,
+		"VIDEO_STATUS_FAILED":         5,
+		"VIDEO_STATUS_DELETED":        6,
+		"VIDEO_STATUS_ARCHIVED":       7,
+		"VIDEO_STATUS_EXPIRED":        8,
+	}
+)
+
+func (x VideoStatus) Enum() *VideoStatus {
+	p := new(VideoStatus)
+	*p = x
+	return p
+}
+
+func (x VideoStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VideoStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_gostream_v1_video_service_proto_enumTypes[0].Descriptor()
+}
+
+func (VideoStatus) Type() protoreflect.EnumType {
+	return &file_gostream_v1_video_service_proto_enumTypes[0]
+}
+
+func (x VideoStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VideoStatus.Descriptor instead.
+func (VideoStatus) EnumDescriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{0}
+}
+
+type Video struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SortableId     string                 `protobuf:"bytes,2,opt,name=sortable_id,json=sortableId,proto3" json:"sortable_id,omitempty"`
+	ShareSlug      string                 `protobuf:"bytes,3,opt,name=share_slug,json=shareSlug,proto3" json:"share_slug,omitempty"`
+	TitleSlug      string                 `protobuf:"bytes,4,opt,name=title_slug,json=titleSlug,proto3" json:"title_slug,omitempty"`
+	UserId         string                 `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title          string                 `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Status         VideoStatus            `protobuf:"varint,7,opt,name=status,proto3,enum=gostream.v1.VideoStatus" json:"status,omitempty"`
+	OriginalUrl    string                 `protobuf:"bytes,8,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	OriginalSize   int64                  `protobuf:"varint,9,opt,name=original_size,json=originalSize,proto3" json:"original_size,omitempty"`
+	HlsUrl         string                 `protobuf:"bytes,10,opt,name=hls_url,json=hlsUrl,proto3" json:"hls_url,omitempty"`
+	DashUrl        string                 `protobuf:"bytes,11,opt,name=dash_url,json=dashUrl,proto3" json:"dash_url,omitempty"`
+	PreviewSeconds int32                  `protobuf:"varint,12,opt,name=preview_seconds,json=previewSeconds,proto3" json:"preview_seconds,omitempty"`
+	PreviewUrl     string                 `protobuf:"bytes,13,opt,name=preview_url,json=previewUrl,proto3" json:"preview_url,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Unset for videos that never expire.
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Why a FAILED video failed; empty otherwise.
+	FailureCode   string `protobuf:"bytes,17,opt,name=failure_code,json=failureCode,proto3" json:"failure_code,omitempty"`
+	FailureReason string `protobuf:"bytes,18,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	// Percentage encoded, only set by GetVideo while PROCESSING. It stops at 99 while the
+	// output is uploaded.
+	TranscodeProgress *int32 `protobuf:"varint,19,opt,name=transcode_progress,json=transcodeProgress,proto3,oneof" json:"transcode_progress,omitempty"`
+	// Set when the video was served from cache past its TTL during a database outage.
+	Stale         bool `protobuf:"varint,20,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Video) Reset() {
+	*x = Video{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Video) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Video) ProtoMessage() {}
+
+func (x *Video) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Video.ProtoReflect.Descriptor instead.
+func (*Video) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Video) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Video) GetSortableId() string {
+	if x != nil {
+		return x.SortableId
+	}
+	return ""
+}
+
+func (x *Video) GetShareSlug() string {
+	if x != nil {
+		return x.ShareSlug
+	}
+	return ""
+}
+
+func (x *Video) GetTitleSlug() string {
+	if x != nil {
+		return x.TitleSlug
+	}
+	return ""
+}
+
+func (x *Video) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Video) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Video) GetStatus() VideoStatus {
+	if x != nil {
+		return x.Status
+	}
+	return VideoStatus_VIDEO_STATUS_UNSPECIFIED
+}
+
+func (x *Video) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *Video) GetOriginalSize() int64 {
+	if x != nil {
+		return x.OriginalSize
+	}
+	return 0
+}
+
+func (x *Video) GetHlsUrl() string {
+	if x != nil {
+		return x.HlsUrl
+	}
+	return ""
+}
+
+func (x *Video) GetDashUrl() string {
+	if x != nil {
+		return x.DashUrl
+	}
+	return ""
+}
+
+func (x *Video) GetPreviewSeconds() int32 {
+	if x != nil {
+		return x.PreviewSeconds
+	}
+	return 0
+}
+
+func (x *Video) GetPreviewUrl() string {
+	if x != nil {
+		return x.PreviewUrl
+	}
+	return ""
+}
+
+func (x *Video) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Video) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Video) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Video) GetFailureCode() string {
+	if x != nil {
+		return x.FailureCode
+	}
+	return ""
+}
+
+func (x *Video) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *Video) GetTranscodeProgress() int32 {
+	if x != nil && x.TranscodeProgress != nil {
+		return *x.TranscodeProgress
+	}
+	return 0
+}
+
+func (x *Video) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+type CreateVideoRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	FileName       string                 `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	PreviewSeconds int32                  `protobuf:"varint,4,opt,name=preview_seconds,json=previewSeconds,proto3" json:"preview_seconds,omitempty"`
+	// Unset never expires the video.
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Makes retries return the video the first request created; see Idempotency-Key.
+	IdempotencyKey string `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateVideoRequest) Reset() {
+	*x = CreateVideoRequest{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVideoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVideoRequest) ProtoMessage() {}
+
+func (x *CreateVideoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVideoRequest.ProtoReflect.Descriptor instead.
+func (*CreateVideoRequest) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateVideoRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateVideoRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateVideoRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *CreateVideoRequest) GetPreviewSeconds() int32 {
+	if x != nil {
+		return x.PreviewSeconds
+	}
+	return 0
+}
+
+func (x *CreateVideoRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CreateVideoRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type CreateVideoResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Video     *Video                 `protobuf:"bytes,1,opt,name=video,proto3" json:"video,omitempty"`
+	UploadUrl string                 `protobuf:"bytes,2,opt,name=upload_url,json=uploadUrl,proto3" json:"upload_url,omitempty"`
+	// Set when an earlier request with the same idempotency key created the video.
+	Replayed      bool `protobuf:"varint,3,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVideoResponse) Reset() {
+	*x = CreateVideoResponse{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVideoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVideoResponse) ProtoMessage() {}
+
+func (x *CreateVideoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVideoResponse.ProtoReflect.Descriptor instead.
+func (*CreateVideoResponse) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateVideoResponse) GetVideo() *Video {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+func (x *CreateVideoResponse) GetUploadUrl() string {
+	if x != nil {
+		return x.UploadUrl
+	}
+	return ""
+}
+
+func (x *CreateVideoResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+type GetVideoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Selects the storage region's CDN in playback URLs, like the X-Viewer-Region header.
+	ViewerRegion  string `protobuf:"bytes,2,opt,name=viewer_region,json=viewerRegion,proto3" json:"viewer_region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVideoRequest) Reset() {
+	*x = GetVideoRequest{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVideoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVideoRequest) ProtoMessage() {}
+
+func (x *GetVideoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVideoRequest.ProtoReflect.Descriptor instead.
+func (*GetVideoRequest) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetVideoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetVideoRequest) GetViewerRegion() string {
+	if x != nil {
+		return x.ViewerRegion
+	}
+	return ""
+}
+
+type GetVideoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Video         *Video                 `protobuf:"bytes,1,opt,name=video,proto3" json:"video,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVideoResponse) Reset() {
+	*x = GetVideoResponse{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVideoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVideoResponse) ProtoMessage() {}
+
+func (x *GetVideoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVideoResponse.ProtoReflect.Descriptor instead.
+func (*GetVideoResponse) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetVideoResponse) GetVideo() *Video {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+type ListVideosRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Zero uses the default page size.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The next_cursor of the previous page; empty starts from the newest video.
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVideosRequest) Reset() {
+	*x = ListVideosRequest{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVideosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVideosRequest) ProtoMessage() {}
+
+func (x *ListVideosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVideosRequest.ProtoReflect.Descriptor instead.
+func (*ListVideosRequest) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListVideosRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListVideosRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListVideosRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListVideosResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Videos []*Video               `protobuf:"bytes,1,rep,name=videos,proto3" json:"videos,omitempty"`
+	// Empty when there are no more videos.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVideosResponse) Reset() {
+	*x = ListVideosResponse{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVideosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVideosResponse) ProtoMessage() {}
+
+func (x *ListVideosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVideosResponse.ProtoReflect.Descriptor instead.
+func (*ListVideosResponse) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListVideosResponse) GetVideos() []*Video {
+	if x != nil {
+		return x.Videos
+	}
+	return nil
+}
+
+func (x *ListVideosResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type TriggerProcessRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty uses the default ABR profile.
+	AbrProfile    string `protobuf:"bytes,2,opt,name=abr_profile,json=abrProfile,proto3" json:"abr_profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerProcessRequest) Reset() {
+	*x = TriggerProcessRequest{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerProcessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerProcessRequest) ProtoMessage() {}
+
+func (x *TriggerProcessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerProcessRequest.ProtoReflect.Descriptor instead.
+func (*TriggerProcessRequest) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TriggerProcessRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TriggerProcessRequest) GetAbrProfile() string {
+	if x != nil {
+		return x.AbrProfile
+	}
+	return ""
+}
+
+type TriggerProcessResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerProcessResponse) Reset() {
+	*x = TriggerProcessResponse{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerProcessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerProcessResponse) ProtoMessage() {}
+
+func (x *TriggerProcessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerProcessResponse.ProtoReflect.Descriptor instead.
+func (*TriggerProcessResponse) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{8}
+}
+
+type WatchVideoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchVideoRequest) Reset() {
+	*x = WatchVideoRequest{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchVideoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchVideoRequest) ProtoMessage() {}
+
+func (x *WatchVideoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchVideoRequest.ProtoReflect.Descriptor instead.
+func (*WatchVideoRequest) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchVideoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type VideoEvent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	VideoId string                 `protobuf:"bytes,1,opt,name=video_id,json=videoId,proto3" json:"video_id,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*VideoEvent_Status
+	//	*VideoEvent_TranscodeProgress
+	Event         isVideoEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VideoEvent) Reset() {
+	*x = VideoEvent{}
+	mi := &file_gostream_v1_video_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VideoEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VideoEvent) ProtoMessage() {}
+
+func (x *VideoEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_gostream_v1_video_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VideoEvent.ProtoReflect.Descriptor instead.
+func (*VideoEvent) Descriptor() ([]byte, []int) {
+	return file_gostream_v1_video_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VideoEvent) GetVideoId() string {
+	if x != nil {
+		return x.VideoId
+	}
+	return ""
+}
+
+func (x *VideoEvent) GetEvent() isVideoEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *VideoEvent) GetStatus() VideoStatus {
+	if x != nil {
+		if x, ok := x.Event.(*VideoEvent_Status); ok {
+			return x.Status
+		}
+	}
+	return VideoStatus_VIDEO_STATUS_UNSPECIFIED
+}
+
+func (x *VideoEvent) GetTranscodeProgress() int32 {
+	if x != nil {
+		if x, ok := x.Event.(*VideoEvent_TranscodeProgress); ok {
+			return x.TranscodeProgress
+		}
+	}
+	return 0
+}
+
+type isVideoEvent_Event interface {
+	isVideoEvent_Event()
+}
+
+type VideoEvent_Status struct {
+	// The video moved to this status.
+	Status VideoStatus `protobuf:"varint,2,opt,name=status,proto3,enum=gostream.v1.VideoStatus,oneof"`
+}
+
+type VideoEvent_TranscodeProgress struct {
+	// Percentage encoded while PROCESSING.
+	TranscodeProgress int32 `protobuf:"varint,3,opt,name=transcode_progress,json=transcodeProgress,proto3,oneof"`
+}
+
+func (*VideoEvent_Status) isVideoEvent_Event() {}
+
+func (*VideoEvent_TranscodeProgress) isVideoEvent_Event() {}
+
+var File_gostream_v1_video_service_proto protoreflect.FileDescriptor
+
+const file_gostream_v1_video_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1fgostream/v1/video_service.proto\x12\vgostream.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf9\x05\n" +
+	"\x05Video\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vsortable_id\x18\x02 \x01(\tR\n" +
+	"sortableId\x12\x1d\n" +
+	"\n" +
+	"share_slug\x18\x03 \x01(\tR\tshareSlug\x12\x1d\n" +
+	"\n" +
+	"title_slug\x18\x04 \x01(\tR\ttitleSlug\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x120\n" +
+	"\x06status\x18\a \x01(\x0e2\x18.gostream.v1.VideoStatusR\x06status\x12!\n" +
+	"\foriginal_url\x18\b \x01(\tR\voriginalUrl\x12#\n" +
+	"\roriginal_size\x18\t \x01(\x03R\foriginalSize\x12\x17\n" +
+	"\ahls_url\x18\n" +
+	" \x01(\tR\x06hlsUrl\x12\x19\n" +
+	"\bdash_url\x18\v \x01(\tR\adashUrl\x12'\n" +
+	"\x0fpreview_seconds\x18\f \x01(\x05R\x0epreviewSeconds\x12\x1f\n" +
+	"\vpreview_url\x18\r \x01(\tR\n" +
+	"previewUrl\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12!\n" +
+	"\ffailure_code\x18\x11 \x01(\tR\vfailureCode\x12%\n" +
+	"\x0efailure_reason\x18\x12 \x01(\tR\rfailureReason\x122\n" +
+	"\x12transcode_progress\x18\x13 \x01(\x05H\x00R\x11transcodeProgress\x88\x01\x01\x12\x14\n" +
+	"\x05stale\x18\x14 \x01(\bR\x05staleB\x15\n" +
+	"\x13_transcode_progress\"\xed\x01\n" +
+	"\x12CreateVideoRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1b\n" +
+	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12'\n" +
+	"\x0fpreview_seconds\x18\x04 \x01(\x05R\x0epreviewSeconds\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\"z\n" +
+	"\x13CreateVideoResponse\x12(\n" +
+	"\x05video\x18\x01 \x01(\v2\x12.gostream.v1.VideoR\x05video\x12\x1d\n" +
+	"\n" +
+	"upload_url\x18\x02 \x01(\tR\tuploadUrl\x12\x1a\n" +
+	"\breplayed\x18\x03 \x01(\bR\breplayed\"F\n" +
+	"\x0fGetVideoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rviewer_region\x18\x02 \x01(\tR\fviewerRegion\"<\n" +
+	"\x10GetVideoResponse\x12(\n" +
+	"\x05video\x18\x01 \x01(\v2\x12.gostream.v1.VideoR\x05video\"Z\n" +
+	"\x11ListVideosRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"a\n" +
+	"\x12ListVideosResponse\x12*\n" +
+	"\x06videos\x18\x01 \x03(\v2\x12.gostream.v1.VideoR\x06videos\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"H\n" +
+	"\x15TriggerProcessRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vabr_profile\x18\x02 \x01(\tR\n" +
+	"abrProfile\"\x18\n" +
+	"\x16TriggerProcessResponse\"#\n" +
+	"\x11WatchVideoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x95\x01\n" +
+	"\n" +
+	"VideoEvent\x12\x19\n" +
+	"\bvideo_id\x18\x01 \x01(\tR\avideoId\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.gostream.v1.VideoStatusH\x00R\x06status\x12/\n" +
+	"\x12transcode_progress\x18\x03 \x01(\x05H\x00R\x11transcodeProgressB\a\n" +
+	"\x05event*\x84\x02\n" +
+	"\vVideoStatus\x12\x1c\n" +
+	"\x18VIDEO_STATUS_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bVIDEO_STATUS_PENDING_UPLOAD\x10\x01\x12\x19\n" +
+	"\x15VIDEO_STATUS_UPLOADED\x10\x02\x12\x1b\n" +
+	"\x17VIDEO_STATUS_PROCESSING\x10\x03\x12\x16\n" +
+	"\x12VIDEO_STATUS_READY\x10\x04\x12\x17\n" +
+	"\x13VIDEO_STATUS_FAILED\x10\x05\x12\x18\n" +
+	"\x14VIDEO_STATUS_DELETED\x10\x06\x12\x19\n" +
+	"\x15VIDEO_STATUS_ARCHIVED\x10\a\x12\x18\n" +
+	"\x14VIDEO_STATUS_EXPIRED\x10\b2\x9c\x03\n" +
+	"\fVideoService\x12P\n" +
+	"\vCreateVideo\x12\x1f.gostream.v1.CreateVideoRequest\x1a .gostream.v1.CreateVideoResponse\x12G\n" +
+	"\bGetVideo\x12\x1c.gostream.v1.GetVideoRequest\x1a\x1d.gostream.v1.GetVideoResponse\x12M\n" +
+	"\n" +
+	"ListVideos\x12\x1e.gostream.v1.ListVideosRequest\x1a\x1f.gostream.v1.ListVideosResponse\x12Y\n" +
+	"\x0eTriggerProcess\x12\".gostream.v1.TriggerProcessRequest\x1a#.gostream.v1.TriggerProcessResponse\x12G\n" +
+	"\n" +
+	"WatchVideo\x12\x1e.gostream.v1.WatchVideoRequest\x1a\x17.gostream.v1.VideoEvent0\x01B?Z=github.com/hszk-dev/gostream/api/proto/gostream/v1;gostreamv1b\x06proto3"
+
+var (
+	file_gostream_v1_video_service_proto_rawDescOnce sync.Once
+	file_gostream_v1_video_service_proto_rawDescData []byte
+)
+
+func file_gostream_v1_video_service_proto_rawDescGZIP() []byte {
+	file_gostream_v1_video_service_proto_rawDescOnce.Do(func() {
+		file_gostream_v1_video_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gostream_v1_video_service_proto_rawDesc), len(file_gostream_v1_video_service_proto_rawDesc)))
+	})
+	return file_gostream_v1_video_service_proto_rawDescData
+}
+
+var file_gostream_v1_video_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_gostream_v1_video_service_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_gostream_v1_video_service_proto_goTypes = []any{
+	(VideoStatus)(0),               // 0: gostream.v1.VideoStatus
+	(*Video)(nil),                  // 1: gostream.v1.Video
+	(*CreateVideoRequest)(nil),     // 2: gostream.v1.CreateVideoRequest
+	(*CreateVideoResponse)(nil),    // 3: gostream.v1.CreateVideoResponse
+	(*GetVideoRequest)(nil),        // 4: gostream.v1.GetVideoRequest
+	(*GetVideoResponse)(nil),       // 5: gostream.v1.GetVideoResponse
+	(*ListVideosRequest)(nil),      // 6: gostream.v1.ListVideosRequest
+	(*ListVideosResponse)(nil),     // 7: gostream.v1.ListVideosResponse
+	(*TriggerProcessRequest)(nil),  // 8: gostream.v1.TriggerProcessRequest
+	(*TriggerProcessResponse)(nil), // 9: gostream.v1.TriggerProcessResponse
+	(*WatchVideoRequest)(nil),      // 10: gostream.v1.WatchVideoRequest
+	(*VideoEvent)(nil),             // 11: gostream.v1.VideoEvent
+	(*timestamppb.Timestamp)(nil),  // 12: google.protobuf.Timestamp
+}
+var file_gostream_v1_video_service_proto_depIdxs = []int32{
+	0,  // 0: gostream.v1.Video.status:type_name -> gostream.v1.VideoStatus
+	12, // 1: gostream.v1.Video.created_at:type_name -> google.protobuf.Timestamp
+	12, // 2: gostream.v1.Video.updated_at:type_name -> google.protobuf.Timestamp
+	12, // 3: gostream.v1.Video.expires_at:type_name -> google.protobuf.Timestamp
+	12, // 4: gostream.v1.CreateVideoRequest.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 5: gostream.v1.CreateVideoResponse.video:type_name -> gostream.v1.Video
+	1,  // 6: gostream.v1.GetVideoResponse.video:type_name -> gostream.v1.Video
+	1,  // 7: gostream.v1.ListVideosResponse.videos:type_name -> gostream.v1.Video
+	0,  // 8: gostream.v1.VideoEvent.status:type_name -> gostream.v1.VideoStatus
+	2,  // 9: gostream.v1.VideoService.CreateVideo:input_type -> gostream.v1.CreateVideoRequest
+	4,  // 10: gostream.v1.VideoService.GetVideo:input_type -> gostream.v1.GetVideoRequest
+	6,  // 11: gostream.v1.VideoService.ListVideos:input_type -> gostream.v1.ListVideosRequest
+	8,  // 12: gostream.v1.VideoService.TriggerProcess:input_type -> gostream.v1.TriggerProcessRequest
+	10, // 13: gostream.v1.VideoService.WatchVideo:input_type -> gostream.v1.WatchVideoRequest
+	3,  // 14: gostream.v1.VideoService.CreateVideo:output_type -> gostream.v1.CreateVideoResponse
+	5,  // 15: gostream.v1.VideoService.GetVideo:output_type -> gostream.v1.GetVideoResponse
+	7,  // 16: gostream.v1.VideoService.ListVideos:output_type -> gostream.v1.ListVideosResponse
+	9,  // 17: gostream.v1.VideoService.TriggerProcess:output_type -> gostream.v1.TriggerProcessResponse
+	11, // 18: gostream.v1.VideoService.WatchVideo:output_type -> gostream.v1.VideoEvent
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_gostream_v1_video_service_proto_init() }
+func file_gostream_v1_video_service_proto_init() {
+	if File_gostream_v1_video_service_proto != nil {
+		return
+	}
+	file_gostream_v1_video_service_proto_msgTypes[0].OneofWrappers = []any{}
+	file_gostream_v1_video_service_proto_msgTypes[10].OneofWrappers = []any{
+		(*VideoEvent_Status)(nil),
+		(*VideoEvent_TranscodeProgress)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gostream_v1_video_service_proto_rawDesc), len(file_gostream_v1_video_service_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gostream_v1_video_service_proto_goTypes,
+		DependencyIndexes: file_gostream_v1_video_service_proto_depIdxs,
+		EnumInfos:         file_gostream_v1_video_service_proto_enumTypes,
+		MessageInfos:      file_gostream_v1_video_service_proto_msgTypes,
+	}.Build()
+	File_gostream_v1_video_service_proto = out.File
+	file_gostream_v1_video_service_proto_goTypes = nil
+	file_gostream_v1_video_service_proto_depIdxs = nil
+}
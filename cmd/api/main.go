@@ -2,25 +2,45 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/hszk-dev/gostream/api/openapi"
+	gostreamv1 "github.com/hszk-dev/gostream/api/proto/gostream/v1"
 
 	"github.com/hszk-dev/gostream/internal/api/handler"
 	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/api/rpc"
 	"github.com/hszk-dev/gostream/internal/config"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
 	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cdn"
+	"github.com/hszk-dev/gostream/internal/infrastructure/entitlement"
+	"github.com/hszk-dev/gostream/internal/infrastructure/logging"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
 	"github.com/hszk-dev/gostream/internal/infrastructure/postgres"
 	"github.com/hszk-dev/gostream/internal/infrastructure/queue"
+	"github.com/hszk-dev/gostream/internal/infrastructure/secrets"
 	"github.com/hszk-dev/gostream/internal/infrastructure/storage"
+	"github.com/hszk-dev/gostream/internal/transcoder"
 	"github.com/hszk-dev/gostream/internal/usecase"
 )
 
@@ -39,9 +59,15 @@ func run() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+	logHandler, err := logging.NewHandler(os.Stdout, logging.Config{
+		Level:     cfg.Log.Level,
+		Format:    cfg.Log.Format,
+		AddSource: cfg.Log.AddSource,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure logging: %w", err)
+	}
+	logger := slog.New(logHandler)
 	slog.SetDefault(logger)
 
 	// Initialize infrastructure clients
@@ -52,6 +78,17 @@ func run() error {
 	defer pgClient.Close()
 	logger.Info("connected to PostgreSQL")
 
+	// Video lookups and listings that tolerate replication lag are read from the replica
+	var pgReplica *postgres.Client
+	if cfg.Database.ReplicaDSN != "" {
+		pgReplica, err = postgres.NewClient(ctx, postgres.DefaultClientConfig(cfg.Database.ReplicaDSN))
+		if err != nil {
+			return fmt.Errorf("failed to connect to PostgreSQL read replica: %w", err)
+		}
+		defer pgReplica.Close()
+		logger.Info("connected to PostgreSQL read replica")
+	}
+
 	storageClient, err := storage.NewClient(ctx, storage.ClientConfig{
 		Endpoint:       cfg.MinIO.Endpoint,
 		PublicEndpoint: cfg.MinIO.PublicEndpoint,
@@ -65,12 +102,61 @@ func run() error {
 	}
 	logger.Info("connected to MinIO")
 
-	queueClient, err := queue.NewClient(ctx, queue.DefaultClientConfig(cfg.RabbitMQ.URL()))
+	// Reads fall back to other nodes or the secondary region when the primary is down
+	var secondaryStorage repository.ObjectStorage
+	if cfg.Fallback.Secondary && cfg.Secondary.Enabled() {
+		secondaryStorage, err = storage.NewClient(ctx, storage.ClientConfig{
+			Endpoint:  cfg.Secondary.Endpoint,
+			AccessKey: cfg.Secondary.AccessKey,
+			SecretKey: cfg.Secondary.SecretKey,
+			Bucket:    cfg.Secondary.Bucket,
+			UseSSL:    cfg.Secondary.UseSSL,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect to secondary MinIO: %w", err)
+		}
+	}
+	objectStorage := newFallbackStorage(ctx, logger, cfg, storageClient, secondaryStorage)
+
+	queueCipher, err := queue.ParsePayloadCipher(cfg.RabbitMQ.EncryptionKeys)
+	if err != nil {
+		return fmt.Errorf("invalid RABBITMQ_ENCRYPTION_KEYS: %w", err)
+	}
+	if cfg.RabbitMQ.RequireEncryption {
+		if queueCipher == nil {
+			return errors.New("RABBITMQ_REQUIRE_ENCRYPTION needs RABBITMQ_ENCRYPTION_KEYS")
+		}
+		queueCipher.RequireEncryption()
+	}
+	queueCfg := queue.DefaultClientConfig(cfg.RabbitMQ.URL())
+	queueCfg.Cipher = queueCipher
+	queueCfg.RetryBaseDelay = cfg.RabbitMQ.RetryBaseDelay
+	queueCfg.RetryMultiplier = cfg.RabbitMQ.RetryMultiplier
+	queueCfg.RetryMaxDelay = cfg.RabbitMQ.RetryMaxDelay
+	queueCfg.ReconnectBaseDelay = cfg.RabbitMQ.ReconnectBaseDelay
+	queueCfg.ReconnectMaxDelay = cfg.RabbitMQ.ReconnectMaxDelay
+	queueCfg.DeleteQueueName = cfg.RabbitMQ.DeleteQueue
+
+	jsCfg := queue.JetStreamConfig{
+		URL:          cfg.NATS.URL,
+		TLSCAFile:    cfg.NATS.TLSCAFile,
+		TLSCertFile:  cfg.NATS.TLSCertFile,
+		TLSKeyFile:   cfg.NATS.TLSKeyFile,
+		Stream:       cfg.NATS.Stream,
+		AckWait:      cfg.NATS.AckWait,
+		Replicas:     cfg.NATS.Replicas,
+		PollInterval: cfg.NATS.PollInterval,
+	}
+
+	queueClient, err := queue.Open(ctx, cfg.Queue.Backend, queueCfg, jsCfg)
 	if err != nil {
-		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to connect to %s: %w", cfg.Queue.Backend, err)
 	}
 	defer queueClient.Close()
-	logger.Info("connected to RabbitMQ")
+	logger.Info("connected to message queue",
+		slog.String("backend", cfg.Queue.Backend),
+		slog.Bool("payload_encryption", queueCipher != nil),
+	)
 
 	// Initialize Redis client
 	redisClient := redis.NewClient(&redis.Options{
@@ -87,18 +173,272 @@ func run() error {
 
 	// Initialize repositories and services
 	videoRepo := postgres.NewVideoRepository(pgClient.Pool())
-	videoCache := cache.NewRedisVideoCache(redisClient)
+	if pgReplica != nil {
+		videoRepo = postgres.NewVideoRepositoryWithReplica(pgClient.Pool(), pgReplica.Pool())
+	}
+	videoCache := cache.NewRedisVideoCache(redisClient, cfg.Redis.TTLJitter)
+	// Written by workers; the API only reads it, so the TTL is never applied here
+	transcodeProgress := cache.NewRedisTranscodeProgressStore(redisClient, cfg.Worker.ProgressTTL)
+	transcodeJobRepo := postgres.NewTranscodeJobRepository(pgClient.Pool())
 
-	baseVideoSvc := usecase.NewVideoService(videoRepo, storageClient, queueClient, usecase.DefaultVideoServiceConfig())
-	videoSvc := usecase.NewCachedVideoService(baseVideoSvc, videoCache, usecase.CachedVideoServiceConfig{
-		CacheTTL:   cfg.Redis.TTL,
-		CDNBaseURL: cfg.CDN.BaseURL,
+	admission := usecase.NewAdmissionController(transcodeJobRepo, queueClient, usecase.AdmissionConfig{
+		MaxFailureRatio: cfg.Admission.MaxFailureRatio,
+		FailureWindow:   cfg.Admission.FailureWindow,
+		MinSamples:      cfg.Admission.MinSamples,
+		MaxBacklog:      cfg.Admission.MaxBacklog,
+		RetryAfter:      cfg.Admission.RetryAfter,
+		CacheTTL:        cfg.Admission.CacheTTL,
+	})
+	estimator := usecase.NewTranscodeEstimator(transcodeJobRepo, usecase.TranscodeEstimatorConfig{
+		Window:     cfg.Estimate.Window,
+		MinSamples: cfg.Estimate.MinSamples,
+	})
+	idStrategy := model.IDStrategy(cfg.Share.IDStrategy)
+	if !idStrategy.IsValid() {
+		return fmt.Errorf("unknown video ID strategy: %s", cfg.Share.IDStrategy)
+	}
+	videoSvcCfg := usecase.DefaultVideoServiceConfig()
+	videoSvcCfg.IDStrategy = idStrategy
+	videoSvcCfg.TitleSlugs = cfg.Share.TitleSlugs
+	videoSvcCfg.TaskTTL = cfg.RabbitMQ.TaskTTL
+	videoSvcCfg.OriginalURLExpiry = cfg.URLAudit.OriginalURLExpiry
+	videoSvcCfg.MultipartURLExpiry = cfg.URLAudit.MultipartURLExpiry
+	videoSvcCfg.InlineUploadMaxBytes = cfg.Server.InlineUploadMaxBytes
+	videoSvcCfg.StorageQuotaBytes = cfg.Quota.MaxBytesPerUser
+	videoSvcCfg.ABRProfiles, err = transcoder.ParseABRProfiles(cfg.ABR.Profiles, cfg.ABR.DefaultProfile)
+	if err != nil {
+		return fmt.Errorf("invalid ABR profiles: %w", err)
+	}
+	if cfg.MinIO.PrefixSecret != "" {
+		// A shorter secret could be brute-forced from a single published prefix
+		if len(cfg.MinIO.PrefixSecret) < 32 {
+			return fmt.Errorf("MINIO_PREFIX_SECRET must be at least 32 bytes, got %d", len(cfg.MinIO.PrefixSecret))
+		}
+		videoSvcCfg.StorageKeySecret = []byte(cfg.MinIO.PrefixSecret)
+	}
+	urlIssuer := usecase.NewURLIssuer(objectStorage, postgres.NewIssuedURLRepository(pgClient.Pool()), usecase.URLIssuerConfig{
+		RateLimit:  cfg.URLAudit.RateLimit,
+		RateWindow: cfg.URLAudit.RateWindow,
+		Retention:  cfg.URLAudit.Retention,
+	})
+	videoEvents := cache.NewRedisVideoEventBus(redisClient)
+	statusEvents := postgres.NewVideoStatusEventRepository(pgClient.Pool())
+	outbox := postgres.NewOutboxRepository(pgClient.Pool())
+	// Visibility changes purge the CDN from the API; the worker purges everything else
+	cdnPurger, err := newCDNPurger(cfg.CDNPurge, cfg.CDN.BaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize CDN purger: %w", err)
+	}
+	baseVideoSvc := usecase.NewVideoService(videoRepo, objectStorage, queueClient, admission, transcoder.NewFFprobe(cfg.Server.FFprobePath), estimator, urlIssuer, transcodeProgress, videoEvents, statusEvents, outbox, cdnPurger, videoSvcCfg)
+	domainRepo := postgres.NewCustomDomainRepository(pgClient.Pool())
+	domainSvc := usecase.NewCustomDomainService(domainRepo, net.DefaultResolver, usecase.CustomDomainServiceConfig{
+		ResolveCacheTTL: cfg.CDN.DomainCacheTTL,
+		DNSTimeout:      usecase.DefaultCustomDomainServiceConfig().DNSTimeout,
+	})
+
+	outputBuckets, err := newOutputBucketService(cfg.Output, pgClient)
+	if err != nil {
+		return fmt.Errorf("failed to initialize output buckets: %w", err)
+	}
+	if outputBuckets != nil {
+		logger.Info("tenant output buckets enabled")
+	}
+
+	popularity := metrics.NewKeyPopularity(metrics.KeyPopularityConfig{
+		Window:   cfg.Popularity.Window,
+		HotHits:  cfg.Popularity.HotHits,
+		WarmHits: cfg.Popularity.WarmHits,
+		MaxKeys:  cfg.Popularity.MaxKeys,
+	})
+	videoSvc := usecase.NewCachedVideoService(baseVideoSvc, videoCache, domainSvc, popularity, outputBuckets, usecase.CachedVideoServiceConfig{
+		CacheTTL:            cfg.Redis.TTL,
+		StaleTTL:            cfg.Redis.StaleTTL,
+		EarlyRefreshBeta:    cfg.Redis.EarlyRefreshBeta,
+		CDNBaseURL:          cfg.CDN.BaseURL,
+		SecondaryCDNBaseURL: cfg.CDN.SecondaryBaseURL,
+		SecondaryRegions:    cfg.CDN.SecondaryRegions,
+	})
+
+	entitlementChecker, err := newEntitlementChecker(cfg.Entitlement, pgClient)
+	if err != nil {
+		return fmt.Errorf("failed to initialize entitlement checker: %w", err)
+	}
+	logger.Info("entitlement provider configured", slog.String("provider", cfg.Entitlement.Provider))
+
+	playbackTokenStore := cache.NewRedisPlaybackTokenStore(redisClient)
+	streamSessionStore := cache.NewRedisStreamSessionStore(redisClient)
+	archiveSvc := usecase.NewArchiveService(videoRepo, postgres.NewArchiveRepository(pgClient.Pool()), videoCache, videoEvents, statusEvents, usecase.ArchiveServiceConfig{
+		RestoreTime: cfg.Archive.RestoreTime,
+	})
+	userRepo := postgres.NewUserRepository(pgClient.Pool())
+	playbackSvc := usecase.NewPlaybackTokenService(videoRepo, playbackTokenStore, streamSessionStore, entitlementChecker, archiveSvc, userRepo, usecase.PlaybackTokenServiceConfig{
+		TokenTTL:             cfg.Playback.TokenTTL,
+		MaxConcurrentStreams: cfg.Playback.MaxConcurrentStreams,
+		PlanStreamLimits:     cfg.Playback.PlanStreamLimits,
+		SessionTimeout:       cfg.Playback.SessionTimeout,
+	})
+
+	var signedPlaybackSvc usecase.SignedPlaybackService
+	if len(cfg.Playback.SigningKeys) > 0 {
+		keys := make([][]byte, len(cfg.Playback.SigningKeys))
+		for i, key := range cfg.Playback.SigningKeys {
+			// A shorter key could be brute-forced from a single issued URL
+			if len(key) < 32 {
+				return fmt.Errorf("PLAYBACK_SIGNING_KEYS entries must be at least 32 bytes, got %d", len(key))
+			}
+			keys[i] = []byte(key)
+		}
+		signedPlaybackSvc = usecase.NewSignedPlaybackService(videoRepo, entitlementChecker, usecase.SignedPlaybackConfig{
+			Keys:    keys,
+			TTL:     cfg.Playback.SignedURLTTL,
+			BaseURL: cfg.Playback.SignedBaseURL,
+		})
+		logger.Info("signed playback URLs enabled", slog.Int("keys", len(keys)))
+	}
+
+	progressRepo := postgres.NewProgressRepository(pgClient.Pool())
+	progressStore := cache.NewRedisProgressStore(redisClient, cfg.Progress.CacheTTL)
+	progressSvc := usecase.NewProgressService(videoSvc, progressRepo, progressStore, usecase.ProgressServiceConfig{
+		FlushBatchSize: cfg.Progress.FlushBatchSize,
+	})
+	viewSvc := usecase.NewViewService(videoSvc, postgres.NewVideoStatsRepository(pgClient.Pool()), cache.NewRedisViewCounter(redisClient), usecase.ViewServiceConfig{
+		FlushBatchSize: cfg.Views.FlushBatchSize,
+	})
+
+	setSLOObjectives(cfg.SLO)
+	availability := metrics.NewAvailabilityWindow(cfg.SLO.MaxWindow)
+	renditionRepo := postgres.NewRenditionRepository(pgClient.Pool())
+	queueMonitor, err := newQueueMonitor(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid RABBITMQ_MANAGEMENT_URL: %w", err)
+	}
+	adminSvc := usecase.NewAdminService(videoRepo, transcodeJobRepo, renditionRepo, objectStorage, availability, popularity, queueMonitor, queueClient, videoCache, videoEvents, statusEvents, usecase.AdminServiceConfig{
+		TranscodeSuccessObjective: cfg.SLO.TranscodeSuccessObjective,
+		TimeToReadyObjective:      cfg.SLO.TimeToReadyObjective,
+		APIAvailabilityObjective:  cfg.SLO.APIAvailabilityObjective,
+		SLOWindow:                 cfg.SLO.Window,
+		MaxSLOWindow:              cfg.SLO.MaxWindow,
+		Queues:                    monitoredQueues(queueCfg, cfg.RabbitMQ.ManagementQueues),
+		DeadLetterQueue:           queueCfg.DeadLetterQueue,
+		TaskTTL:                   cfg.RabbitMQ.TaskTTL,
+	})
+
+	analyticsSvc := usecase.NewAnalyticsExportService(
+		postgres.NewAnalyticsRepository(pgClient.Pool()),
+		postgres.NewExportBookmarkRepository(pgClient.Pool()),
+		objectStorage,
+		usecase.AnalyticsExportConfig{
+			Prefix:         cfg.Analytics.Prefix,
+			Lag:            cfg.Analytics.Lag,
+			Backfill:       cfg.Analytics.Backfill,
+			MaxHoursPerRun: cfg.Analytics.MaxHoursPerRun,
+		},
+	)
+
+	flushCtx, stopFlush := context.WithCancel(ctx)
+	defer stopFlush()
+	flushDone := make(chan struct{})
+	go func() {
+		defer close(flushDone)
+		runProgressFlusher(flushCtx, logger, progressSvc, cfg.Progress.FlushInterval)
+	}()
+	viewFlushDone := make(chan struct{})
+	go func() {
+		defer close(viewFlushDone)
+		runViewFlusher(flushCtx, logger, viewSvc, cfg.Views.FlushInterval)
+	}()
+
+	// Every replica may run the export: partitions are overwritten with identical
+	// content and bookmarks only move forward
+	if cfg.Analytics.ExportInterval > 0 {
+		go runAnalyticsExporter(flushCtx, logger, analyticsSvc, cfg.Analytics.ExportInterval)
+	}
+
+	// Deletes are idempotent, so every replica may run the cleanup
+	if cfg.URLAudit.CleanupInterval > 0 {
+		go runIssuedURLCleanup(flushCtx, logger, urlIssuer, cfg.URLAudit.CleanupInterval)
+	}
+
+	// Expiring a video twice is rejected by its status transition, so every replica may run it
+	if cfg.Expiry.Interval > 0 {
+		expirer := usecase.NewVideoExpirer(videoRepo, queueClient, playbackSvc, videoCache, videoEvents, statusEvents, usecase.VideoExpirerConfig{
+			Grace:     cfg.Expiry.Grace,
+			BatchSize: cfg.Expiry.BatchSize,
+		})
+		go runVideoExpirer(flushCtx, logger, expirer, cfg.Expiry.Interval)
+	}
+
+	// Claims skip rows locked by other replicas, so every replica may run the relay
+	if cfg.Outbox.RelayInterval > 0 {
+		relay := usecase.NewOutboxRelay(outbox, queueClient, usecase.OutboxRelayConfig{
+			BatchSize: cfg.Outbox.BatchSize,
+			Lease:     cfg.Outbox.Lease,
+		})
+		go runOutboxRelay(flushCtx, logger, relay, cfg.Outbox.RelayInterval)
+	}
+
+	userSvc := usecase.NewUserService(userRepo, postgres.NewAPIKeyRepository(pgClient.Pool()), usecase.UserServiceConfig{
+		APIKeyCacheTTL: cfg.Auth.APIKeyCacheTTL,
+	})
+
+	maintenanceSvc := usecase.NewMaintenanceService(cache.NewRedisMaintenanceStore(redisClient), usecase.MaintenanceConfig{
+		CacheTTL: cfg.Maintenance.CacheTTL,
 	})
 
 	// Initialize handlers
-	videoHandler := handler.NewVideoHandler(videoSvc)
+	// Every dependency is critical: playback tokens and live status live in Redis
+	healthChecks := []handler.HealthCheck{
+		{Name: "postgres", Ping: pgClient.Ping, Critical: true},
+		{Name: "minio", Ping: objectStorage.Ping, Critical: true},
+		{Name: cfg.Queue.Backend, Ping: queueClient.Ping, Critical: true},
+		{Name: "redis", Ping: func(ctx context.Context) error { return redisClient.Ping(ctx).Err() }, Critical: true},
+	}
+	if pgReplica != nil {
+		healthChecks = append(healthChecks, handler.HealthCheck{Name: "postgres_replica", Ping: pgReplica.Ping, Critical: true})
+	}
+	healthHandler := handler.NewHealthHandler(handler.DefaultReadyCheckTimeout, healthChecks...).WithMaintenance(maintenanceSvc)
+	videoHandler := handler.NewVideoHandler(videoSvc, entitlementChecker != nil)
+	videoWatcher := usecase.NewVideoWatcher(videoSvc, videoEvents)
+	videoEventsHandler := handler.NewVideoEventsHandler(videoWatcher)
+	playbackHandler := handler.NewPlaybackHandler(playbackSvc, usecase.NewManifestService(videoSvc, playbackSvc, objectStorage, renditionRepo))
+	signedPlaybackHandler := handler.NewSignedPlaybackHandler(signedPlaybackSvc)
+	progressHandler := handler.NewProgressHandler(progressSvc)
+	adminHandler := handler.NewAdminHandler(adminSvc)
+	shareHandler := handler.NewShareHandler(videoSvc, cfg.Share.RedirectTemplate)
+	domainHandler := handler.NewCustomDomainHandler(domainSvc)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsSvc)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceSvc)
+	archiveHandler := handler.NewArchiveHandler(archiveSvc)
+	keyHandler := handler.NewKeyHandler(usecase.NewKeyService(playbackSvc, postgres.NewEncryptionKeyRepository(pgClient.Pool())))
+	outputBucketHandler := handler.NewOutputBucketHandler(outputBuckets)
+	userHandler := handler.NewUserHandler(userSvc)
+	subtitleHandler := handler.NewSubtitleHandler(usecase.NewSubtitleService(videoRepo, postgres.NewSubtitleRepository(pgClient.Pool()), objectStorage, queueClient))
+	viewHandler := handler.NewViewHandler(viewSvc)
+	playlistHandler := handler.NewPlaylistHandler(usecase.NewPlaylistService(postgres.NewPlaylistRepository(pgClient.Pool()), videoSvc), entitlementChecker != nil)
 
-	r := setupRouter(logger, videoHandler)
+	rateLimits := cache.NewRedisRateLimitStore(redisClient)
+	adminAuth, err := newAdminAuth(logger, cfg.Auth.AdminTokens)
+	if err != nil {
+		return err
+	}
+	auth := authMiddlewares{
+		apiKey:     middleware.APIKey(userSvc),
+		videoOwner: middleware.VideoOwner(videoSvc),
+		admin:      adminAuth,
+	}
+	limits := rateLimiters{
+		api:     middleware.RateLimit(rateLimits, "api", middleware.TokenBucket{Rate: cfg.RateLimit.Rate, Burst: cfg.RateLimit.Burst}),
+		create:  middleware.RateLimit(rateLimits, "create", middleware.TokenBucket{Rate: cfg.RateLimit.CreateRate, Burst: cfg.RateLimit.CreateBurst}),
+		process: middleware.RateLimit(rateLimits, "process", middleware.TokenBucket{Rate: cfg.RateLimit.ProcessRate, Burst: cfg.RateLimit.ProcessBurst}),
+	}
+
+	spec, err := openapi.Load()
+	if err != nil {
+		return err
+	}
+	validate := newRequestValidation(logger, spec, cfg.Server.ValidateRequests)
+
+	r := setupRouter(logger, availability, middleware.Maintenance(maintenanceSvc, cfg.Maintenance.RetryAfter), auth, limits, spec, validate, healthHandler, videoHandler, videoEventsHandler, playbackHandler, signedPlaybackHandler, progressHandler, adminHandler, shareHandler, domainHandler, analyticsHandler, maintenanceHandler, archiveHandler, subtitleHandler, keyHandler, outputBucketHandler, userHandler, playlistHandler, viewHandler)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
@@ -107,7 +447,7 @@ func run() error {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
 	go func() {
 		logger.Info("starting server", slog.Int("port", cfg.Server.Port))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -115,6 +455,21 @@ func run() error {
 		}
 	}()
 
+	var grpcSrv *grpc.Server
+	if cfg.Server.GRPCPort > 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPCPort))
+		if err != nil {
+			return fmt.Errorf("failed to listen for gRPC: %w", err)
+		}
+		grpcSrv = newGRPCServer(logger, userSvc, videoSvc, videoWatcher, entitlementChecker != nil)
+		go func() {
+			logger.Info("starting gRPC server", slog.Int("port", cfg.Server.GRPCPort))
+			if err := grpcSrv.Serve(lis); err != nil {
+				errCh <- fmt.Errorf("gRPC server error: %w", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
@@ -128,30 +483,513 @@ func run() error {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	if grpcSrv != nil {
+		stopGRPCServer(shutdownCtx, grpcSrv)
+	}
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		return fmt.Errorf("server shutdown error: %w", err)
 	}
 
+	// Stop the periodic flushers, then persist heartbeats and views received since their last run
+	stopFlush()
+	<-flushDone
+	<-viewFlushDone
+	if n, err := progressSvc.FlushProgress(shutdownCtx); err != nil {
+		logger.Error("final progress flush failed", slog.String("error", err.Error()))
+	} else if n > 0 {
+		logger.Info("flushed playback progress", slog.Int("count", n))
+	}
+	if n, err := viewSvc.FlushViews(shutdownCtx); err != nil {
+		logger.Error("final view flush failed", slog.String("error", err.Error()))
+	} else if n > 0 {
+		logger.Info("flushed video views", slog.Int("videos", n))
+	}
+
 	logger.Info("server stopped")
 	return nil
 }
 
-func setupRouter(logger *slog.Logger, videoHandler *handler.VideoHandler) *chi.Mux {
+// newFallbackStorage wraps the primary storage client so reads fall back to the
+// MINIO_FALLBACK_ENDPOINTS nodes and then, when MINIO_FALLBACK_SECONDARY is set, to the
+// secondary region. Fallback nodes that are unreachable at startup are skipped with a
+// warning rather than failing it. Without fallback origins every call goes to the primary.
+func newFallbackStorage(ctx context.Context, logger *slog.Logger, cfg *config.Config, primary *storage.Client, secondary repository.ObjectStorage) *storage.FallbackStorage {
+	origins := []storage.Origin{{Name: "primary", Storage: primary}}
+	for _, endpoint := range cfg.Fallback.Endpoints {
+		client, err := storage.NewClient(ctx, storage.ClientConfig{
+			Endpoint:  endpoint,
+			AccessKey: cfg.MinIO.AccessKey,
+			SecretKey: cfg.MinIO.SecretKey,
+			Bucket:    cfg.MinIO.Bucket,
+			UseSSL:    cfg.MinIO.UseSSL,
+		})
+		if err != nil {
+			logger.Warn("skipping unreachable fallback MinIO", slog.String("endpoint", endpoint), slog.Any("error", err))
+			continue
+		}
+		origins = append(origins, storage.Origin{Name: endpoint, Storage: client})
+	}
+	if cfg.Fallback.Secondary && secondary != nil {
+		origins = append(origins, storage.Origin{Name: "secondary", Storage: secondary})
+	}
+
+	return storage.NewFallbackStorage(origins, storage.FallbackConfig{
+		FailureThreshold: cfg.Fallback.FailureThreshold,
+		DemoteFor:        cfg.Fallback.DemoteFor,
+	})
+}
+
+// newEntitlementChecker builds the configured entitlement provider.
+// Returns nil for "none", which disables entitlement checks.
+func newEntitlementChecker(cfg config.EntitlementConfig, pgClient *postgres.Client) (repository.EntitlementChecker, error) {
+	switch cfg.Provider {
+	case "", "none":
+		return nil, nil
+	case "local":
+		return postgres.NewEntitlementRepository(pgClient.Pool()), nil
+	case "http":
+		return entitlement.NewHTTPChecker(entitlement.HTTPCheckerConfig{
+			URL:     cfg.HTTPURL,
+			Token:   cfg.HTTPToken,
+			Timeout: cfg.HTTPTimeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown entitlement provider %q", cfg.Provider)
+	}
+}
+
+// newCDNPurger selects the CDN purge provider.
+// Returns nil when purging is disabled, leaving output to expire with its cache TTL.
+func newCDNPurger(cfg config.CDNPurgeConfig, baseURL string) (repository.CDNPurger, error) {
+	var (
+		purger repository.CDNPurger
+		err    error
+	)
+
+	switch cfg.Provider {
+	case "", "none":
+		return nil, nil
+	case metrics.CDNProviderCloudFront:
+		purger, err = cdn.NewCloudFrontPurger(cdn.CloudFrontConfig{
+			DistributionID:  cfg.CloudFrontDistributionID,
+			AccessKeyID:     cfg.CloudFrontAccessKeyID,
+			SecretAccessKey: cfg.CloudFrontSecretKey,
+			Timeout:         cfg.Timeout,
+		})
+	case metrics.CDNProviderFastly:
+		purger, err = cdn.NewFastlyPurger(cdn.FastlyConfig{
+			ServiceID: cfg.FastlyServiceID,
+			APIToken:  cfg.FastlyAPIToken,
+			Timeout:   cfg.Timeout,
+		})
+	case metrics.CDNProviderBunny:
+		purger, err = cdn.NewBunnyPurger(cdn.BunnyConfig{
+			APIKey:  cfg.BunnyAPIKey,
+			BaseURL: baseURL,
+			Timeout: cfg.Timeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown CDN purge provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cdn.NewRetryingPurger(cfg.Provider, purger, cdn.RetryConfig{
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     cfg.Backoff,
+	}), nil
+}
+
+// newOutputBucketService returns nil when no secrets key is configured, which disables
+// tenant output buckets.
+func newOutputBucketService(cfg config.OutputBucketConfig, pgClient *postgres.Client) (usecase.OutputBucketService, error) {
+	store, err := secrets.ParseLocalStore(cfg.SecretsKeys)
+	if err != nil || store == nil {
+		return nil, err
+	}
+	return usecase.NewOutputBucketService(
+		postgres.NewOutputBucketRepository(pgClient.Pool()),
+		store,
+		storage.NewOutputBucketConnector(),
+		usecase.OutputBucketServiceConfig{
+			ResolveCacheTTL: cfg.CacheTTL,
+			CheckTimeout:    cfg.CheckTimeout,
+		},
+	), nil
+}
+
+// newQueueMonitor returns nil when no management URL is configured or the broker is not
+// RabbitMQ, which disables GET /v1/admin/queues.
+func newQueueMonitor(cfg *config.Config) (repository.QueueMonitor, error) {
+	if cfg.RabbitMQ.ManagementURL == "" || (cfg.Queue.Backend != queue.BackendRabbitMQ && cfg.Queue.Backend != "") {
+		return nil, nil
+	}
+	return queue.NewManagementClient(queue.ManagementConfig{
+		URL:       cfg.RabbitMQ.ManagementURL,
+		User:      cfg.RabbitMQ.User,
+		Password:  cfg.RabbitMQ.Password,
+		VHost:     cfg.RabbitMQ.VHost,
+		AgeSample: cfg.RabbitMQ.ManagementAgeSample,
+		Timeout:   cfg.RabbitMQ.ManagementTimeout,
+	})
+}
+
+// monitoredQueues lists the transcode queue, the extra queues and the delete queue once each.
+func monitoredQueues(queueCfg queue.ClientConfig, extra []string) []string {
+	queues := []string{queueCfg.QueueName}
+	for _, name := range slices.Concat(extra, []string{queueCfg.DeleteQueueName}) {
+		if name != "" && !slices.Contains(queues, name) {
+			queues = append(queues, name)
+		}
+	}
+	return queues
+}
+
+// setSLOObjectives exports the configured objectives so burn-rate recording rules can reference them.
+func setSLOObjectives(cfg config.SLOConfig) {
+	metrics.SLOObjective.WithLabelValues(metrics.SLITranscodeSuccess).Set(cfg.TranscodeSuccessObjective)
+	metrics.SLOObjective.WithLabelValues(metrics.SLITimeToReady).Set(cfg.TimeToReadyObjective.Seconds())
+	metrics.SLOObjective.WithLabelValues(metrics.SLIAPIAvailability).Set(cfg.APIAvailabilityObjective)
+}
+
+// runProgressFlusher periodically persists buffered playback progress until ctx is cancelled.
+func runProgressFlusher(ctx context.Context, logger *slog.Logger, svc usecase.ProgressService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := svc.FlushProgress(ctx)
+			if err != nil {
+				logger.Error("progress flush failed", slog.String("error", err.Error()))
+				continue
+			}
+			if n > 0 {
+				logger.Debug("flushed playback progress", slog.Int("count", n))
+			}
+		}
+	}
+}
+
+// runViewFlusher periodically persists buffered video views until ctx is cancelled.
+func runViewFlusher(ctx context.Context, logger *slog.Logger, svc usecase.ViewService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := svc.FlushViews(ctx)
+			if err != nil {
+				logger.Error("view flush failed", slog.String("error", err.Error()))
+				continue
+			}
+			if n > 0 {
+				logger.Debug("flushed video views", slog.Int("videos", n))
+			}
+		}
+	}
+}
+
+// runAnalyticsExporter periodically exports closed hours of analytics events until ctx is cancelled.
+func runAnalyticsExporter(ctx context.Context, logger *slog.Logger, svc usecase.AnalyticsExportService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := svc.Export(ctx)
+			if err != nil {
+				logger.Error("analytics export failed", slog.String("error", err.Error()))
+				continue
+			}
+			if len(result.Partitions) > 0 {
+				logger.Info("exported analytics partitions", slog.Int("count", len(result.Partitions)))
+			}
+		}
+	}
+}
+
+// runIssuedURLCleanup periodically deletes expired presigned URL audit records until ctx is cancelled.
+func runIssuedURLCleanup(ctx context.Context, logger *slog.Logger, issuer usecase.URLIssuer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := issuer.CleanupExpired(ctx)
+			if err != nil {
+				logger.Error("issued URL cleanup failed", slog.String("error", err.Error()))
+				continue
+			}
+			if n > 0 {
+				logger.Info("deleted expired issued URL records", slog.Int64("count", n))
+			}
+		}
+	}
+}
+
+// runVideoExpirer periodically unpublishes videos past their expiry until ctx is cancelled.
+func runVideoExpirer(ctx context.Context, logger *slog.Logger, expirer usecase.VideoExpirer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := expirer.Run(ctx)
+			if err != nil {
+				logger.Error("video expiry failed", slog.String("error", err.Error()))
+				continue
+			}
+			if result.Expired > 0 || result.Deleted > 0 {
+				logger.Info("expired videos",
+					slog.Int("expired", result.Expired),
+					slog.Int("deleted", result.Deleted),
+				)
+			}
+		}
+	}
+}
+
+// runOutboxRelay periodically publishes transcode tasks left in the outbox until ctx is cancelled.
+func runOutboxRelay(ctx context.Context, logger *slog.Logger, relay usecase.OutboxRelay, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := relay.Run(ctx)
+			if err != nil {
+				logger.Error("outbox relay failed", slog.String("error", err.Error()))
+				continue
+			}
+			if result.Published > 0 || result.Failed > 0 {
+				logger.Info("relayed outbox transcode tasks",
+					slog.Int("published", result.Published),
+					slog.Int("failed", result.Failed),
+				)
+			}
+		}
+	}
+}
+
+// authMiddlewares authenticate API keys and hold their users to their own videos, and
+// authenticate operators on the admin API.
+type authMiddlewares struct {
+	apiKey     func(http.Handler) http.Handler
+	videoOwner func(http.Handler) http.Handler
+	// admin is nil when ADMIN_TOKENS is not set, and the admin API is then not served.
+	admin func(http.Handler) http.Handler
+}
+
+// newAdminAuth returns the middleware checking ADMIN_TOKENS on the admin API, or nil
+// when no token is configured, in which case the admin API is not mounted at all.
+func newAdminAuth(logger *slog.Logger, tokens []string) (func(http.Handler) http.Handler, error) {
+	if len(tokens) == 0 {
+		logger.Warn("ADMIN_TOKENS is not set; the admin API is disabled")
+		return nil, nil
+	}
+
+	keys := make([][]byte, len(tokens))
+	for i, token := range tokens {
+		// A shorter token could be guessed by an attacker reaching the admin API
+		if len(token) < 32 {
+			return nil, fmt.Errorf("ADMIN_TOKENS entries must be at least 32 bytes, got %d", len(token))
+		}
+		keys[i] = []byte(token)
+	}
+	logger.Info("admin API token authentication enabled", slog.Int("tokens", len(keys)))
+	return middleware.AdminToken(keys), nil
+}
+
+// grpcKeepaliveTime is how often the gRPC server pings idle connections, so proxies and
+// load balancers keep WatchVideo streams open during a long transcode.
+const grpcKeepaliveTime = time.Minute
+
+// newGRPCServer serves the gostream.v1 gRPC API, authenticating calls like the REST API
+// and reporting itself SERVING on the standard health service.
+func newGRPCServer(logger *slog.Logger, auth middleware.APIKeyAuthenticator, videos rpc.Videos, watcher usecase.VideoWatcher, entitlementsEnforced bool) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rpc.UnaryRecoverer(logger), rpc.UnaryAuth(auth)),
+		grpc.ChainStreamInterceptor(rpc.StreamRecoverer(logger), rpc.StreamAuth(auth)),
+		grpc.KeepaliveParams(keepalive.ServerParameters{Time: grpcKeepaliveTime}),
+	)
+	gostreamv1.RegisterVideoServiceServer(srv, rpc.NewVideoServer(videos, watcher, entitlementsEnforced))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	return srv
+}
+
+// stopGRPCServer waits for in-flight calls until ctx is done, then closes the rest.
+// WatchVideo streams only end with their transcode, so they are usually cut off.
+func stopGRPCServer(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
+
+// newRequestValidation returns the middleware checking /v1 requests against the OpenAPI
+// spec, or one letting every request through to the handlers' own checks when disabled.
+func newRequestValidation(logger *slog.Logger, spec *openapi.Spec, enabled bool) func(http.Handler) http.Handler {
+	if !enabled {
+		logger.Info("OpenAPI request validation disabled")
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return spec.Validate
+}
+
+// rateLimiters are the rate limit middlewares of the /v1 routes: api for every route
+// but the nginx auth_request targets, and tighter buckets on top of it for the expensive
+// ones.
+type rateLimiters struct {
+	api     func(http.Handler) http.Handler
+	create  func(http.Handler) http.Handler
+	process func(http.Handler) http.Handler
+}
+
+func setupRouter(logger *slog.Logger, availability *metrics.AvailabilityWindow, maintenance func(http.Handler) http.Handler, auth authMiddlewares, limits rateLimiters, spec *openapi.Spec, validate func(http.Handler) http.Handler, healthHandler *handler.HealthHandler, videoHandler *handler.VideoHandler, videoEventsHandler *handler.VideoEventsHandler, playbackHandler *handler.PlaybackHandler, signedPlaybackHandler *handler.SignedPlaybackHandler, progressHandler *handler.ProgressHandler, adminHandler *handler.AdminHandler, shareHandler *handler.ShareHandler, domainHandler *handler.CustomDomainHandler, analyticsHandler *handler.AnalyticsHandler, maintenanceHandler *handler.MaintenanceHandler, archiveHandler *handler.ArchiveHandler, subtitleHandler *handler.SubtitleHandler, keyHandler *handler.KeyHandler, outputBucketHandler *handler.OutputBucketHandler, userHandler *handler.UserHandler, playlistHandler *handler.PlaylistHandler, viewHandler *handler.ViewHandler) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(chimw.RequestID)
 	r.Use(middleware.RequestID)
+	r.Use(middleware.UserID)
+	r.Use(auth.apiKey)
+	r.Use(middleware.LogContext)
 	r.Use(middleware.Logger(logger))
+	// Outside SLI: planned downtime does not count against availability
+	r.Use(maintenance)
+	r.Use(middleware.SLI(availability))
+	r.Use(middleware.Metrics)
 	r.Use(middleware.Recoverer(logger))
 
 	r.Get("/health", handler.Health)
+	r.Get("/healthz", handler.Health)
+	r.Get("/readyz", healthHandler.Ready)
 	r.Handle("/metrics", promhttp.Handler())
 
 	r.Route("/v1", func(r chi.Router) {
+		r.Use(limits.api)
+		r.Use(validate)
+		r.Get("/openapi.json", spec.ServeJSON)
+		r.Get("/docs", openapi.ServeDocs)
 		r.Route("/videos", func(r chi.Router) {
-			r.Post("/", videoHandler.Create)
-			r.Post("/{id}/process", videoHandler.TriggerProcess)
+			r.With(limits.create).Post("/", videoHandler.Create)
+			r.With(auth.videoOwner).Post("/{id}/upload-complete", videoHandler.CompleteUpload)
+			r.With(auth.videoOwner).Post("/{id}/uploads", videoHandler.InitiateUpload)
+			r.With(auth.videoOwner).Post("/{id}/uploads/{uploadID}/complete", videoHandler.CompleteMultipartUpload)
+			r.With(auth.videoOwner, limits.process).Post("/{id}/process", videoHandler.TriggerProcess)
+			r.With(auth.videoOwner, limits.process).Post("/{id}/retranscode", videoHandler.Retranscode)
+			r.With(auth.videoOwner).Post("/{id}/archive", archiveHandler.Archive)
+			r.Get("/{id}/archive", archiveHandler.Get)
+			r.With(auth.videoOwner).Post("/{id}/restore", archiveHandler.Restore)
+			r.With(auth.videoOwner).Post("/{id}/subtitles", subtitleHandler.Upload)
+			r.Get("/{id}/subtitles", subtitleHandler.List)
+			r.Get("/search", videoHandler.Search)
 			r.Get("/{id}", videoHandler.Get)
+			r.With(auth.videoOwner).Patch("/{id}", videoHandler.Update)
+			r.Get("/{id}/events", videoEventsHandler.Stream)
+			r.Get("/{id}/status-events", videoHandler.ListStatusEvents)
+			r.With(auth.videoOwner).Delete("/{id}", videoHandler.Delete)
+			r.With(auth.videoOwner).Put("/{id}/expiration", videoHandler.SetExpiration)
+			r.Get("/{id}/original-url", videoHandler.GetOriginalURL)
+			r.Post("/{id}/playback-token", playbackHandler.IssueToken)
+			r.Get("/{id}/playback", signedPlaybackHandler.Issue)
+			r.Get("/{id}/hls/*", playbackHandler.Playlist)
+			r.Get("/{id}/key", keyHandler.Get)
+			r.With(auth.videoOwner).Delete("/{id}/playback-tokens", playbackHandler.RevokeVideoTokens)
+			r.Put("/{id}/progress", progressHandler.SaveProgress)
+			r.Get("/{id}/progress", progressHandler.GetProgress)
+			r.Post("/{id}/views", viewHandler.RecordView)
+			r.Get("/{id}/stats", viewHandler.GetStats)
+		})
+		r.Get("/v/{slug}", shareHandler.Redirect)
+		r.Get("/u/{userID}/v/{slug}", shareHandler.RedirectTitleSlug)
+		r.Get("/users/{id}/videos", videoHandler.ListByUser)
+		r.Get("/users/{id}/usage", videoHandler.Usage)
+		r.Get("/users/{id}/playlists", playlistHandler.ListByUser)
+		r.Route("/playlists", func(r chi.Router) {
+			r.Post("/", playlistHandler.Create)
+			r.Get("/{id}", playlistHandler.Get)
+			r.Patch("/{id}", playlistHandler.Update)
+			r.Delete("/{id}", playlistHandler.Delete)
+			r.Put("/{id}/videos", playlistHandler.SetVideos)
+			r.Get("/{id}/videos", playlistHandler.Videos)
+		})
+		r.Delete("/users/{id}/playback-tokens", playbackHandler.RevokeOwnUserTokens)
+		r.Delete("/playback-tokens/{token}", playbackHandler.RevokeToken)
+		r.Get("/playback/authorize", playbackHandler.Authorize)
+		r.Get("/playback/verify", signedPlaybackHandler.Verify)
+		r.Route("/tenants/{id}/domains", func(r chi.Router) {
+			r.Post("/", domainHandler.Register)
+			r.Get("/", domainHandler.List)
+			r.Post("/{domainID}/verify", domainHandler.Verify)
+			r.Put("/{domainID}/certificate", domainHandler.SetCertificate)
+			r.Delete("/{domainID}", domainHandler.Delete)
+		})
+		r.Route("/tenants/{id}/output-bucket", func(r chi.Router) {
+			r.Put("/", outputBucketHandler.Configure)
+			r.Get("/", outputBucketHandler.Get)
+			r.Post("/check", outputBucketHandler.Check)
+			r.Delete("/", outputBucketHandler.Delete)
+		})
+		r.Route("/me", func(r chi.Router) {
+			r.Get("/history", progressHandler.ListHistory)
+			r.Delete("/history/{videoID}", progressHandler.RemoveFromHistory)
+			r.Post("/api-keys", userHandler.IssueOwnKey)
+			r.Get("/api-keys", userHandler.ListOwnKeys)
+			r.Delete("/api-keys/{keyID}", userHandler.RevokeOwnKey)
+		})
+		// Operator diagnostics and repairs; expected to be exposed only on the internal
+		// network, and never served without ADMIN_TOKENS
+		if auth.admin == nil {
+			return
+		}
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(auth.admin)
+			r.Get("/videos", adminHandler.ListVideos)
+			r.Get("/videos/{id}/transcode-jobs", adminHandler.ListTranscodeJobs)
+			r.Get("/videos/{id}/transcode-jobs/{jobID}/log", adminHandler.TranscodeJobLog)
+			r.Get("/videos/{id}/renditions", adminHandler.ListRenditions)
+			r.Post("/videos/{id}/verify-output", adminHandler.VerifyOutput)
+			r.Post("/videos/{id}/status", adminHandler.ForceStatus)
+			r.Post("/videos/{id}/requeue", adminHandler.RequeueTranscode)
+			r.Delete("/videos/{id}/cache", adminHandler.FlushVideoCache)
+			r.Get("/slo", adminHandler.SLOSnapshot)
+			r.Get("/cache/hot-keys", adminHandler.HotKeys)
+			r.Get("/queues", adminHandler.QueueStats)
+			r.Post("/analytics/exports", analyticsHandler.Export)
+			r.Get("/maintenance", maintenanceHandler.Get)
+			r.Put("/maintenance", maintenanceHandler.Schedule)
+			r.Delete("/maintenance", maintenanceHandler.End)
+			r.Post("/users", userHandler.Create)
+			r.Get("/users/{id}", userHandler.Get)
+			r.Post("/users/{id}/api-keys", userHandler.IssueKey)
+			r.Delete("/users/{id}/playback-tokens", playbackHandler.RevokeUserTokens)
 		})
 	})
 
@@ -0,0 +1,186 @@
+// Command doctor checks that a gostream installation is ready to run: FFmpeg features,
+// bucket CORS, queue permissions, migration status, Redis latency and clock skew.
+//
+//	doctor [-timeout 10s] [-migrations db/migrations] [-max-clock-skew 2s]
+//
+// Settings are read from the same environment as the API and worker. Each problem is
+// printed with a remedy, and the exit status is 1 if any check failed.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/hszk-dev/gostream/internal/config"
+	"github.com/hszk-dev/gostream/internal/doctor"
+	"github.com/hszk-dev/gostream/internal/infrastructure/logging"
+	"github.com/hszk-dev/gostream/internal/infrastructure/postgres"
+	"github.com/hszk-dev/gostream/internal/infrastructure/queue"
+	"github.com/hszk-dev/gostream/internal/infrastructure/storage"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+const (
+	redisPingSamples     = 20
+	redisLatencyWarnOver = 5 * time.Millisecond
+)
+
+func main() {
+	passed, err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+func run(args []string) (bool, error) {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 10*time.Second, "time limit for each check")
+	migrationsDir := fs.String("migrations", "db/migrations", "directory holding the migrations of this build")
+	maxSkew := fs.Duration("max-clock-skew", 2*time.Second, "clock difference to warn about")
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logHandler, err := logging.NewHandler(os.Stderr, logging.Config{
+		Level:     cfg.Log.Level,
+		Format:    cfg.Log.Format,
+		AddSource: cfg.Log.AddSource,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to configure logging: %w", err)
+	}
+	slog.SetDefault(slog.New(logHandler))
+
+	profile, err := cfg.Worker.WorkerProfile()
+	if err != nil {
+		return false, fmt.Errorf("invalid worker profile: %w", err)
+	}
+	outputFormats, err := transcoder.ParseOutputFormats(cfg.Worker.OutputFormats)
+	if err != nil {
+		return false, fmt.Errorf("invalid output formats: %w", err)
+	}
+	queueCfg, err := queueConfig(cfg, profile)
+	if err != nil {
+		return false, err
+	}
+	jsCfg := queue.JetStreamConfig{
+		URL:          cfg.NATS.URL,
+		TLSCAFile:    cfg.NATS.TLSCAFile,
+		TLSCertFile:  cfg.NATS.TLSCertFile,
+		TLSKeyFile:   cfg.NATS.TLSKeyFile,
+		Stream:       cfg.NATS.Stream,
+		AckWait:      cfg.NATS.AckWait,
+		Replicas:     cfg.NATS.Replicas,
+		PollInterval: cfg.NATS.PollInterval,
+	}
+
+	ffmpegCfg := transcoder.DefaultFFmpegConfig()
+	checks := []doctor.Check{
+		doctor.FFmpegCheck(ffmpegCfg.FFmpegPath, ffmpegCfg.FFprobePath, profile.Codecs, outputFormats),
+	}
+
+	storageClient, err := storage.NewClient(ctx, storage.ClientConfig{
+		Endpoint:  cfg.MinIO.Endpoint,
+		AccessKey: cfg.MinIO.AccessKey,
+		SecretKey: cfg.MinIO.SecretKey,
+		Bucket:    cfg.MinIO.Bucket,
+		UseSSL:    cfg.MinIO.UseSSL,
+	})
+	if err != nil {
+		checks = append(checks, doctor.Unavailable("bucket CORS", err,
+			"Check MINIO_ENDPOINT, MINIO_ACCESS_KEY, MINIO_SECRET_KEY and that MINIO_BUCKET exists"))
+	} else {
+		checks = append(checks, doctor.BucketCORSCheck(cfg.MinIO.Bucket, storageClient))
+	}
+
+	checks = append(checks, doctor.QueueCheck(func(ctx context.Context) (doctor.QueueInspector, error) {
+		return queue.Open(ctx, cfg.Queue.Backend, queueCfg, jsCfg)
+	}))
+
+	latest, err := doctor.LatestMigration(*migrationsDir)
+	if err != nil {
+		slog.Warn("cannot compare schema version with this build", slog.String("error", err.Error()))
+	}
+	pgClient, err := postgres.NewClient(ctx, postgres.DefaultClientConfig(cfg.Database.DSN()))
+	if err != nil {
+		checks = append(checks, doctor.Unavailable("migrations", err,
+			"Check that PostgreSQL is running and the DB_* settings are correct"))
+	} else {
+		defer pgClient.Close()
+		checks = append(checks, doctor.MigrationsCheck(postgres.NewBackupRepository(pgClient.Pool()), latest))
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr(),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	defer redisClient.Close()
+	checks = append(checks, doctor.RedisLatencyCheck(func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	}, redisPingSamples, redisLatencyWarnOver))
+
+	if pgClient != nil {
+		checks = append(checks, doctor.ClockSkewCheck("PostgreSQL", pgClient.Now, *maxSkew))
+	}
+	scheme := "http"
+	if cfg.MinIO.UseSSL {
+		scheme = "https"
+	}
+	checks = append(checks, doctor.ClockSkewCheck("object storage",
+		doctor.HTTPDate(http.DefaultClient, scheme+"://"+cfg.MinIO.Endpoint+"/minio/health/live"), *maxSkew))
+
+	return doctor.Report(os.Stdout, doctor.Run(ctx, checks, *timeout)), nil
+}
+
+// queueConfig builds the client configuration the worker uses, so connecting declares
+// the same queues with the same arguments.
+func queueConfig(cfg *config.Config, profile config.WorkerProfile) (queue.ClientConfig, error) {
+	queueCipher, err := queue.ParsePayloadCipher(cfg.RabbitMQ.EncryptionKeys)
+	if err != nil {
+		return queue.ClientConfig{}, fmt.Errorf("invalid RABBITMQ_ENCRYPTION_KEYS: %w", err)
+	}
+	if cfg.RabbitMQ.RequireEncryption {
+		if queueCipher == nil {
+			return queue.ClientConfig{}, errors.New("RABBITMQ_REQUIRE_ENCRYPTION needs RABBITMQ_ENCRYPTION_KEYS")
+		}
+		queueCipher.RequireEncryption()
+	}
+	consumeQueues, err := queue.ParseConsumeQueues(profile.Queues)
+	if err != nil {
+		return queue.ClientConfig{}, fmt.Errorf("invalid worker queues: %w", err)
+	}
+
+	queueCfg := queue.DefaultClientConfig(cfg.RabbitMQ.URL())
+	queueCfg.Cipher = queueCipher
+	queueCfg.RetryBaseDelay = cfg.RabbitMQ.RetryBaseDelay
+	queueCfg.RetryMultiplier = cfg.RabbitMQ.RetryMultiplier
+	queueCfg.RetryMaxDelay = cfg.RabbitMQ.RetryMaxDelay
+	queueCfg.ReconnectBaseDelay = cfg.RabbitMQ.ReconnectBaseDelay
+	queueCfg.ReconnectMaxDelay = cfg.RabbitMQ.ReconnectMaxDelay
+	queueCfg.DeleteQueueName = cfg.RabbitMQ.DeleteQueue
+	queueCfg.ConsumeQueues = consumeQueues
+	return queueCfg, nil
+}
@@ -2,20 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/hszk-dev/gostream/internal/api/handler"
 	"github.com/hszk-dev/gostream/internal/config"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
 	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cdn"
+	"github.com/hszk-dev/gostream/internal/infrastructure/logging"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
 	"github.com/hszk-dev/gostream/internal/infrastructure/postgres"
 	"github.com/hszk-dev/gostream/internal/infrastructure/queue"
+	"github.com/hszk-dev/gostream/internal/infrastructure/secrets"
 	"github.com/hszk-dev/gostream/internal/infrastructure/storage"
 	"github.com/hszk-dev/gostream/internal/transcoder"
 	"github.com/hszk-dev/gostream/internal/usecase"
@@ -37,11 +46,22 @@ func run() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+	logHandler, err := logging.NewHandler(os.Stdout, logging.Config{
+		Level:     cfg.Log.Level,
+		Format:    cfg.Log.Format,
+		AddSource: cfg.Log.AddSource,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure logging: %w", err)
+	}
+	logger := slog.New(logHandler)
 	slog.SetDefault(logger)
 
+	profile, err := cfg.Worker.WorkerProfile()
+	if err != nil {
+		return fmt.Errorf("invalid worker profile: %w", err)
+	}
+
 	// Ensure temp directory exists
 	if err := os.MkdirAll(cfg.Worker.TempDir, 0755); err != nil {
 		return fmt.Errorf("failed to create temp directory: %w", err)
@@ -67,12 +87,80 @@ func run() error {
 	}
 	logger.Info("connected to MinIO")
 
-	queueClient, err := queue.NewClient(ctx, queue.DefaultClientConfig(cfg.RabbitMQ.URL()))
+	// Optional secondary region for HLS output (latency for distant viewers, DR)
+	var replicaStorage repository.ObjectStorage
+	if cfg.Secondary.Enabled() {
+		secondaryClient, err := storage.NewClient(ctx, storage.ClientConfig{
+			Endpoint:  cfg.Secondary.Endpoint,
+			AccessKey: cfg.Secondary.AccessKey,
+			SecretKey: cfg.Secondary.SecretKey,
+			Bucket:    cfg.Secondary.Bucket,
+			UseSSL:    cfg.Secondary.UseSSL,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect to secondary MinIO: %w", err)
+		}
+		replicaStorage = secondaryClient
+		logger.Info("connected to secondary MinIO", slog.String("endpoint", cfg.Secondary.Endpoint))
+	}
+
+	// Reads fall back to other nodes or the secondary region when the primary is down
+	objectStorage := newFallbackStorage(ctx, logger, cfg, storageClient, replicaStorage)
+
+	queueCipher, err := queue.ParsePayloadCipher(cfg.RabbitMQ.EncryptionKeys)
 	if err != nil {
-		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return fmt.Errorf("invalid RABBITMQ_ENCRYPTION_KEYS: %w", err)
+	}
+	if cfg.RabbitMQ.RequireEncryption {
+		if queueCipher == nil {
+			return errors.New("RABBITMQ_REQUIRE_ENCRYPTION needs RABBITMQ_ENCRYPTION_KEYS")
+		}
+		queueCipher.RequireEncryption()
+	}
+	consumeQueues, err := queue.ParseConsumeQueues(profile.Queues)
+	if err != nil {
+		return fmt.Errorf("invalid worker queues: %w", err)
+	}
+	queueCfg := queue.DefaultClientConfig(cfg.RabbitMQ.URL())
+	queueCfg.Cipher = queueCipher
+	queueCfg.RetryBaseDelay = cfg.RabbitMQ.RetryBaseDelay
+	queueCfg.RetryMultiplier = cfg.RabbitMQ.RetryMultiplier
+	queueCfg.RetryMaxDelay = cfg.RabbitMQ.RetryMaxDelay
+	queueCfg.ReconnectBaseDelay = cfg.RabbitMQ.ReconnectBaseDelay
+	queueCfg.ReconnectMaxDelay = cfg.RabbitMQ.ReconnectMaxDelay
+	queueCfg.DeleteQueueName = cfg.RabbitMQ.DeleteQueue
+	queueCfg.ConsumeQueues = consumeQueues
+	queueCfg.Concurrency = profile.Concurrency
+	queueCfg.Prefetch = profile.Concurrency
+
+	jsCfg := queue.JetStreamConfig{
+		URL:          cfg.NATS.URL,
+		TLSCAFile:    cfg.NATS.TLSCAFile,
+		TLSCertFile:  cfg.NATS.TLSCertFile,
+		TLSKeyFile:   cfg.NATS.TLSKeyFile,
+		Stream:       cfg.NATS.Stream,
+		AckWait:      cfg.NATS.AckWait,
+		Replicas:     cfg.NATS.Replicas,
+		PollInterval: cfg.NATS.PollInterval,
+	}
+
+	queueClient, err := queue.Open(ctx, cfg.Queue.Backend, queueCfg, jsCfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", cfg.Queue.Backend, err)
 	}
 	defer queueClient.Close()
-	logger.Info("connected to RabbitMQ")
+	logger.Info("connected to message queue",
+		slog.String("backend", cfg.Queue.Backend),
+		slog.Bool("payload_encryption", queueCipher != nil),
+	)
+
+	// Deletes are consumed on a connection of their own so their QoS does not
+	// cut into the transcode prefetch
+	deleteQueueClient, err := queue.Open(ctx, cfg.Queue.Backend, queueCfg, jsCfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s for deletes: %w", cfg.Queue.Backend, err)
+	}
+	defer deleteQueueClient.Close()
 
 	// Initialize Redis client for cache invalidation
 	redisClient := redis.NewClient(&redis.Options{
@@ -87,23 +175,199 @@ func run() error {
 	}
 	logger.Info("connected to Redis")
 
-	// Initialize transcoder
-	tc := transcoder.NewFFmpegTranscoder(transcoder.DefaultFFmpegConfig())
+	// Initialize transcoder with the first encoder of the profile this FFmpeg build provides
+	ffmpegCfg := transcoder.DefaultFFmpegConfig()
+	ffmpegCfg.VideoCodec, err = transcoder.SelectVideoEncoder(ctx, ffmpegCfg.FFmpegPath, profile.Codecs)
+	if err != nil {
+		return fmt.Errorf("failed to select video encoder: %w", err)
+	}
+	ffmpegCfg.PlaylistOrder, err = transcoder.ParsePlaylistOrder(cfg.Worker.PlaylistOrder)
+	if err != nil {
+		return fmt.Errorf("invalid playlist order: %w", err)
+	}
+	ffmpegCfg.DefaultVariant = cfg.Worker.DefaultVariant
+	outputFormats, err := transcoder.ParseOutputFormats(cfg.Worker.OutputFormats)
+	if err != nil {
+		return fmt.Errorf("invalid output formats: %w", err)
+	}
+	ffmpegLogs, err := usecase.ParseFFmpegLogMode(cfg.Worker.FFmpegLogs)
+	if err != nil {
+		return fmt.Errorf("invalid FFmpeg log mode: %w", err)
+	}
+	ffmpegCfg.MaxParallelVariants = cfg.Worker.ParallelVariants
+	abrProfiles, err := transcoder.ParseABRProfiles(cfg.ABR.Profiles, cfg.ABR.DefaultProfile)
+	if err != nil {
+		return fmt.Errorf("invalid ABR profiles: %w", err)
+	}
+
+	// TranscodeService probes each original itself to size the ABR ladder
+	prober := transcoder.NewFFprobe(ffmpegCfg.FFprobePath)
+	ffmpegCfg.FFprobePath = ""
+	tc := transcoder.NewFFmpegTranscoder(ffmpegCfg)
+	logger.Info("worker profile loaded",
+		slog.String("profile", profile.Name),
+		slog.Int("concurrency", profile.Concurrency),
+		slog.Int("parallel_variants", ffmpegCfg.MaxParallelVariants),
+		slog.String("video_codec", ffmpegCfg.VideoCodec),
+		slog.String("playlist_order", string(ffmpegCfg.PlaylistOrder)),
+		slog.Any("abr_profiles", abrProfiles.Names()),
+		slog.Any("queues", profile.Queues),
+	)
+
+	cdnPurger, err := newCDNPurger(cfg.CDNPurge, cfg.CDN.BaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize CDN purger: %w", err)
+	}
+	if cdnPurger != nil {
+		logger.Info("CDN purging enabled", slog.String("provider", cfg.CDNPurge.Provider))
+	}
 
 	// Initialize repository and service
 	videoRepo := postgres.NewVideoRepository(pgClient.Pool())
-	videoCache := cache.NewRedisVideoCache(redisClient)
+	videoCache := cache.NewRedisVideoCache(redisClient, cfg.Redis.TTLJitter)
+	transcodeJobRepo := postgres.NewTranscodeJobRepository(pgClient.Pool())
+	renditionRepo := postgres.NewRenditionRepository(pgClient.Pool())
+	var encryptionKeys repository.EncryptionKeyRepository
+	if cfg.Worker.HLSEncryption {
+		encryptionKeys = postgres.NewEncryptionKeyRepository(pgClient.Pool())
+		logger.Info("HLS segment encryption enabled")
+	}
+
+	outputBuckets, err := newOutputBucketService(cfg.Output, pgClient)
+	if err != nil {
+		return fmt.Errorf("failed to initialize output buckets: %w", err)
+	}
+	if outputBuckets != nil {
+		logger.Info("tenant output buckets enabled")
+	}
+
+	var cacheWriteThroughTTL time.Duration
+	if cfg.Worker.CacheWriteThrough {
+		cacheWriteThroughTTL = cfg.Worker.CacheWriteThroughTTL
+		logger.Info("video cache write-through enabled", "ttl", cacheWriteThroughTTL)
+	}
+
+	videoEvents := cache.NewRedisVideoEventBus(redisClient)
+	statusEvents := postgres.NewVideoStatusEventRepository(pgClient.Pool())
+	supersededOutputs := postgres.NewSupersededOutputRepository(pgClient.Pool())
 	transcodeSvc := usecase.NewTranscodeService(
 		videoRepo,
-		storageClient,
+		objectStorage,
+		replicaStorage,
 		tc,
+		prober,
 		videoCache,
+		cache.NewRedisTranscodeProgressStore(redisClient, cfg.Worker.ProgressTTL),
+		videoEvents,
+		cache.NewRedisEncodeSlotStore(redisClient),
+		cdnPurger,
+		transcodeJobRepo,
+		renditionRepo,
+		postgres.NewSubtitleRepository(pgClient.Pool()),
+		encryptionKeys,
+		usecase.NewTranscodeEstimator(transcodeJobRepo, usecase.TranscodeEstimatorConfig{
+			Window:     cfg.Estimate.Window,
+			MinSamples: cfg.Estimate.MinSamples,
+		}),
+		outputBuckets,
+		statusEvents,
+		supersededOutputs,
 		usecase.TranscodeServiceConfig{
-			TempDir:    cfg.Worker.TempDir,
-			MaxRetries: cfg.Worker.MaxRetries,
+			TempDir:               cfg.Worker.TempDir,
+			MaxRetries:            cfg.Worker.MaxRetries,
+			DownloadConcurrency:   cfg.Worker.DownloadConcurrency,
+			DownloadChunkSize:     cfg.Worker.DownloadChunkSize,
+			OutputFormats:         outputFormats,
+			ABRProfiles:           abrProfiles,
+			AudioOnlyBitrate:      cfg.Worker.AudioOnlyBitrate,
+			AudioOnlySources:      cfg.Worker.AudioOnlySources,
+			MaxTenantEncodes:      cfg.Worker.TenantMaxEncodes,
+			TenantEncodeLimits:    cfg.Worker.TenantEncodeLimits,
+			EncodeSlotTimeout:     cfg.Worker.EncodeSlotTimeout,
+			FFmpegLogs:            ffmpegLogs,
+			TaskTimeout:           cfg.Worker.TaskTimeout,
+			TaskTimeoutFactor:     cfg.Worker.TaskTimeoutFactor,
+			CacheWriteThroughTTL:  cacheWriteThroughTTL,
+			CacheStaleTTL:         cfg.Redis.StaleTTL,
+			SupersededOutputGrace: cfg.Superseded.Grace,
 		},
 	)
 
+	cleanupSvc := usecase.NewCleanupService(
+		objectStorage,
+		replicaStorage,
+		videoCache,
+		cdnPurger,
+		outputBuckets,
+		usecase.CleanupServiceConfig{MaxRetries: cfg.Worker.MaxRetries},
+	)
+
+	// Tasks are held rather than started while a maintenance window is open
+	maintenanceSvc := usecase.NewMaintenanceService(cache.NewRedisMaintenanceStore(redisClient), usecase.MaintenanceConfig{
+		CacheTTL: cfg.Maintenance.CacheTTL,
+	})
+
+	renditionPruner := usecase.NewRenditionPruner(videoRepo, renditionRepo, objectStorage, replicaStorage, cdnPurger, usecase.RenditionPruneConfig{
+		MinAge:    cfg.Prune.MinAge,
+		BatchSize: cfg.Prune.BatchSize,
+	})
+
+	archiveMover := usecase.NewArchiveMover(
+		videoRepo,
+		postgres.NewArchiveRepository(pgClient.Pool()),
+		objectStorage,
+		replicaStorage,
+		videoCache,
+		videoEvents,
+		cdnPurger,
+		statusEvents,
+		usecase.ArchiveMoverConfig{
+			Lease:     cfg.Archive.Lease,
+			BatchSize: cfg.Archive.BatchSize,
+		},
+	)
+
+	supersededSweeper := usecase.NewSupersededOutputSweeper(supersededOutputs, queueClient, usecase.SupersededOutputSweeperConfig{
+		BatchSize: cfg.Superseded.BatchSize,
+		Lease:     cfg.Superseded.Lease,
+	})
+
+	// Republish tasks lost by the broker before consuming, so the pass sees the queue as
+	// it was left
+	if cfg.Reconcile.OnStart {
+		taskReconciler := usecase.NewTaskReconciler(
+			videoRepo,
+			queueClient,
+			queueClient,
+			cache.NewRedisTranscodeProgressStore(redisClient, cfg.Worker.ProgressTTL),
+			cache.NewRedisLockStore(redisClient),
+			usecase.TaskReconcileConfig{
+				MinAge:   cfg.Reconcile.MinAge,
+				MaxTasks: cfg.Reconcile.MaxTasks,
+				TaskTTL:  cfg.RabbitMQ.TaskTTL,
+			},
+		)
+		reconcileTasks(ctx, logger, taskReconciler, cfg.Reconcile.Timeout)
+	}
+
+	// Expose Prometheus metrics (storage throughput, errors) for scraping, next to the probes.
+	// Redis only carries progress, cache invalidation and live status, all best-effort
+	healthHandler := handler.NewHealthHandler(handler.DefaultReadyCheckTimeout,
+		handler.HealthCheck{Name: "postgres", Ping: pgClient.Ping, Critical: true},
+		handler.HealthCheck{Name: "minio", Ping: objectStorage.Ping, Critical: true},
+		handler.HealthCheck{Name: cfg.Queue.Backend, Ping: queueClient.Ping, Critical: true},
+		handler.HealthCheck{Name: cfg.Queue.Backend + "_deletes", Ping: deleteQueueClient.Ping, Critical: true},
+		handler.HealthCheck{Name: "redis", Ping: func(ctx context.Context) error { return redisClient.Ping(ctx).Err() }},
+	).WithMaintenance(maintenanceSvc)
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+	metricsMux.HandleFunc("/healthz", handler.Health)
+	metricsMux.HandleFunc("/readyz", healthHandler.Ready)
+	metricsSrv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Worker.MetricsPort),
+		Handler: metricsMux,
+	}
+
 	// Setup signal handling for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -112,30 +376,43 @@ func run() error {
 	var wg sync.WaitGroup
 
 	// Start consuming messages in a goroutine
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 3)
+	go func() {
+		logger.Info("starting metrics server", slog.Int("port", cfg.Worker.MetricsPort))
+		if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- fmt.Errorf("metrics server error: %w", err)
+		}
+	}()
+
 	go func() {
 		logger.Info("starting worker, consuming transcode tasks")
 		err := queueClient.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error {
+			if err := awaitMaintenance(ctx, logger, maintenanceSvc); err != nil {
+				return err
+			}
 			wg.Add(1)
 			defer wg.Done()
 
-			logger.Info("processing task",
-				slog.String("video_id", task.VideoID.String()),
+			// Every record written while processing the task carries its video ID
+			taskCtx := logging.WithAttrs(ctx, slog.String("video_id", task.VideoID.String()))
+
+			logger.InfoContext(taskCtx, "processing task",
 				slog.Int("retry_count", task.RetryCount),
 			)
 
-			if err := transcodeSvc.ProcessTask(ctx, task); err != nil {
-				logger.Error("task processing failed",
-					slog.String("video_id", task.VideoID.String()),
+			if err := transcodeSvc.ProcessTask(taskCtx, task); err != nil {
+				if errors.Is(err, repository.ErrTaskDeferred) {
+					// Not a failure; the service logged why and the queue puts the task back
+					return err
+				}
+				logger.ErrorContext(taskCtx, "task processing failed",
 					slog.Int("retry_count", task.RetryCount),
 					slog.String("error", err.Error()),
 				)
 				return err
 			}
 
-			logger.Info("task completed successfully",
-				slog.String("video_id", task.VideoID.String()),
-			)
+			logger.InfoContext(taskCtx, "task completed successfully")
 			return nil
 		})
 		if err != nil && ctx.Err() == nil {
@@ -143,6 +420,54 @@ func run() error {
 		}
 	}()
 
+	go func() {
+		logger.Info("consuming delete tasks", slog.String("queue", cfg.RabbitMQ.DeleteQueue))
+		err := deleteQueueClient.ConsumeDeleteTasks(ctx, func(task repository.DeleteTask) error {
+			if err := awaitMaintenance(ctx, logger, maintenanceSvc); err != nil {
+				return err
+			}
+			wg.Add(1)
+			defer wg.Done()
+
+			taskCtx := logging.WithAttrs(ctx, slog.String("video_id", task.VideoID.String()))
+			if err := cleanupSvc.ProcessDeleteTask(taskCtx, task); err != nil {
+				logger.ErrorContext(taskCtx, "delete task failed",
+					slog.Int("retry_count", task.RetryCount),
+					slog.String("error", err.Error()),
+				)
+				return err
+			}
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			errCh <- fmt.Errorf("delete consumer error: %w", err)
+		}
+	}()
+
+	if cfg.Prune.Interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runRenditionPruner(ctx, logger, renditionPruner, maintenanceSvc, cfg.Prune.Interval)
+		}()
+	}
+
+	if cfg.Archive.Interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runArchiveMover(ctx, logger, archiveMover, maintenanceSvc, cfg.Archive.Interval)
+		}()
+	}
+
+	if cfg.Superseded.Interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runSupersededOutputSweeper(ctx, logger, supersededSweeper, maintenanceSvc, cfg.Superseded.Interval)
+		}()
+	}
+
 	// Wait for shutdown signal or error
 	select {
 	case err := <-errCh:
@@ -172,6 +497,236 @@ func run() error {
 		logger.Warn("shutdown timeout exceeded, some tasks may not have completed")
 	}
 
+	if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("metrics server shutdown error", slog.String("error", err.Error()))
+	}
+
 	logger.Info("worker stopped")
 	return nil
 }
+
+// awaitMaintenance holds a received task until the open maintenance window, if any, ends.
+// Held tasks stay unacked, so at most the prefetch count waits in the worker; the rest
+// stay in the queue. Shutdown is not delayed: a held task fails and is retried later.
+func awaitMaintenance(ctx context.Context, logger *slog.Logger, maintenance usecase.MaintenanceService) error {
+	window := maintenance.Active(ctx)
+	if window == nil {
+		return nil
+	}
+
+	logger.InfoContext(ctx, "maintenance window open, holding task",
+		slog.String("reason", window.Reason),
+	)
+	if err := maintenance.Wait(ctx); err != nil {
+		return fmt.Errorf("wait for maintenance to end: %w", err)
+	}
+	logger.InfoContext(ctx, "maintenance window ended, resuming task")
+	return nil
+}
+
+// runRenditionPruner periodically prunes unwatched renditions until ctx is cancelled.
+// Runs are skipped while a maintenance window is open. Pruning the same video twice is
+// harmless, so every worker replica may run it.
+func runRenditionPruner(ctx context.Context, logger *slog.Logger, pruner usecase.RenditionPruner, maintenance usecase.MaintenanceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if maintenance.Active(ctx) != nil {
+				continue
+			}
+			result, err := pruner.Prune(ctx)
+			if err != nil {
+				logger.Error("rendition pruning failed", slog.String("error", err.Error()))
+				continue
+			}
+			if result.Renditions > 0 {
+				logger.Info("pruned unwatched renditions",
+					slog.Int("videos", result.Videos),
+					slog.Int("renditions", result.Renditions),
+					slog.Int64("bytes", result.Bytes),
+				)
+			}
+		}
+	}
+}
+
+// reconcileTasks runs a single task reconciliation pass. Failures are logged and do not
+// stop the worker: the pass is an optimization over waiting for the videos to be noticed.
+func reconcileTasks(ctx context.Context, logger *slog.Logger, reconciler usecase.TaskReconciler, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := reconciler.Reconcile(ctx)
+	if err != nil {
+		logger.Warn("task reconciliation failed", slog.String("error", err.Error()))
+		return
+	}
+	if result.Skipped {
+		logger.Info("task reconciliation skipped, another worker ran it recently")
+		return
+	}
+	logger.Info("task reconciliation finished",
+		slog.Int("queued", result.Queued),
+		slog.Int("stale", result.Stale),
+		slog.Int("in_flight", result.InFlight),
+		slog.Int("republished", result.Republished),
+	)
+}
+
+// runArchiveMover periodically moves output to and from the archive tier until ctx is
+// cancelled. Runs are skipped while a maintenance window is open. Moves are claimed with
+// leases, so every worker replica may run it.
+func runArchiveMover(ctx context.Context, logger *slog.Logger, mover usecase.ArchiveMover, maintenance usecase.MaintenanceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if maintenance.Active(ctx) != nil {
+				continue
+			}
+			result, err := mover.Run(ctx)
+			if err != nil {
+				logger.Error("archive move failed", slog.String("error", err.Error()))
+				continue
+			}
+			if result.Archived > 0 || result.Restored > 0 {
+				logger.Info("moved archived output",
+					slog.Int("archived", result.Archived),
+					slog.Int("restored", result.Restored),
+					slog.Int64("bytes", result.Bytes),
+				)
+			}
+		}
+	}
+}
+
+// runSupersededOutputSweeper periodically hands superseded output versions past their
+// grace period to the cleanup consumer until ctx is cancelled. Runs are skipped while a
+// maintenance window is open. Versions are claimed with leases, so every worker replica
+// may run it.
+func runSupersededOutputSweeper(ctx context.Context, logger *slog.Logger, sweeper usecase.SupersededOutputSweeper, maintenance usecase.MaintenanceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if maintenance.Active(ctx) != nil {
+				continue
+			}
+			result, err := sweeper.Run(ctx)
+			if err != nil {
+				logger.Error("superseded output sweep failed", slog.String("error", err.Error()))
+				continue
+			}
+			if result.Published > 0 || result.Failed > 0 {
+				logger.Info("scheduled deletion of superseded output",
+					slog.Int("published", result.Published),
+					slog.Int("failed", result.Failed),
+				)
+			}
+		}
+	}
+}
+
+// newFallbackStorage wraps the primary storage client so reads fall back to the
+// MINIO_FALLBACK_ENDPOINTS nodes and then, when MINIO_FALLBACK_SECONDARY is set, to the
+// secondary region. Fallback nodes that are unreachable at startup are skipped with a
+// warning rather than failing it. Without fallback origins every call goes to the primary.
+func newFallbackStorage(ctx context.Context, logger *slog.Logger, cfg *config.Config, primary *storage.Client, secondary repository.ObjectStorage) *storage.FallbackStorage {
+	origins := []storage.Origin{{Name: "primary", Storage: primary}}
+	for _, endpoint := range cfg.Fallback.Endpoints {
+		client, err := storage.NewClient(ctx, storage.ClientConfig{
+			Endpoint:  endpoint,
+			AccessKey: cfg.MinIO.AccessKey,
+			SecretKey: cfg.MinIO.SecretKey,
+			Bucket:    cfg.MinIO.Bucket,
+			UseSSL:    cfg.MinIO.UseSSL,
+		})
+		if err != nil {
+			logger.Warn("skipping unreachable fallback MinIO", slog.String("endpoint", endpoint), slog.Any("error", err))
+			continue
+		}
+		origins = append(origins, storage.Origin{Name: endpoint, Storage: client})
+	}
+	if cfg.Fallback.Secondary && secondary != nil {
+		origins = append(origins, storage.Origin{Name: "secondary", Storage: secondary})
+	}
+
+	return storage.NewFallbackStorage(origins, storage.FallbackConfig{
+		FailureThreshold: cfg.Fallback.FailureThreshold,
+		DemoteFor:        cfg.Fallback.DemoteFor,
+	})
+}
+
+// newOutputBucketService returns nil when no secrets key is configured, which disables
+// tenant output buckets.
+func newOutputBucketService(cfg config.OutputBucketConfig, pgClient *postgres.Client) (usecase.OutputBucketService, error) {
+	store, err := secrets.ParseLocalStore(cfg.SecretsKeys)
+	if err != nil || store == nil {
+		return nil, err
+	}
+	return usecase.NewOutputBucketService(
+		postgres.NewOutputBucketRepository(pgClient.Pool()),
+		store,
+		storage.NewOutputBucketConnector(),
+		usecase.OutputBucketServiceConfig{
+			ResolveCacheTTL: cfg.CacheTTL,
+			CheckTimeout:    cfg.CheckTimeout,
+		},
+	), nil
+}
+
+// newCDNPurger selects the CDN purge provider.
+// Returns nil when purging is disabled, leaving superseded output to expire with its cache TTL.
+func newCDNPurger(cfg config.CDNPurgeConfig, baseURL string) (repository.CDNPurger, error) {
+	var (
+		purger repository.CDNPurger
+		err    error
+	)
+
+	switch cfg.Provider {
+	case "", "none":
+		return nil, nil
+	case metrics.CDNProviderCloudFront:
+		purger, err = cdn.NewCloudFrontPurger(cdn.CloudFrontConfig{
+			DistributionID:  cfg.CloudFrontDistributionID,
+			AccessKeyID:     cfg.CloudFrontAccessKeyID,
+			SecretAccessKey: cfg.CloudFrontSecretKey,
+			Timeout:         cfg.Timeout,
+		})
+	case metrics.CDNProviderFastly:
+		purger, err = cdn.NewFastlyPurger(cdn.FastlyConfig{
+			ServiceID: cfg.FastlyServiceID,
+			APIToken:  cfg.FastlyAPIToken,
+			Timeout:   cfg.Timeout,
+		})
+	case metrics.CDNProviderBunny:
+		purger, err = cdn.NewBunnyPurger(cdn.BunnyConfig{
+			APIKey:  cfg.BunnyAPIKey,
+			BaseURL: baseURL,
+			Timeout: cfg.Timeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown CDN purge provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cdn.NewRetryingPurger(cfg.Provider, purger, cdn.RetryConfig{
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     cfg.Backoff,
+	}), nil
+}
@@ -0,0 +1,12 @@
+CREATE TABLE playback_progress (
+    user_id UUID NOT NULL,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    position_ms BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, video_id)
+);
+
+CREATE INDEX idx_playback_progress_user_updated ON playback_progress(user_id, updated_at DESC);
+
+COMMENT ON TABLE playback_progress IS 'Last playback position per user and video, flushed periodically from Redis';
+COMMENT ON COLUMN playback_progress.position_ms IS 'Playback position in milliseconds from the start of the video';
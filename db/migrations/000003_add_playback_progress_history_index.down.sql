@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_playback_progress_user_history;
+
+CREATE INDEX idx_playback_progress_user_updated ON playback_progress(user_id, updated_at DESC);
@@ -0,0 +1,5 @@
+-- Watch history pages in (updated_at DESC, video_id DESC) order; include video_id
+-- so keyset pagination is fully served by the index.
+DROP INDEX IF EXISTS idx_playback_progress_user_updated;
+
+CREATE INDEX idx_playback_progress_user_history ON playback_progress(user_id, updated_at DESC, video_id DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE videos
+    DROP COLUMN IF EXISTS preview_url,
+    DROP COLUMN IF EXISTS preview_seconds;
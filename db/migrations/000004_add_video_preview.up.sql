@@ -0,0 +1,6 @@
+ALTER TABLE videos
+    ADD COLUMN preview_seconds INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN preview_url TEXT;
+
+COMMENT ON COLUMN videos.preview_seconds IS 'Length of the public preview rendition in seconds; 0 disables previews';
+COMMENT ON COLUMN videos.preview_url IS 'Object storage path to the preview manifest (.m3u8) after transcoding';
@@ -0,0 +1,10 @@
+CREATE TABLE entitlements (
+    user_id UUID NOT NULL,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    granted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (user_id, video_id)
+);
+
+COMMENT ON TABLE entitlements IS 'Local grants of full-stream access, used when ENTITLEMENT_PROVIDER=local';
+COMMENT ON COLUMN entitlements.expires_at IS 'End of access (e.g., rental period); NULL grants access indefinitely';
@@ -0,0 +1,3 @@
+ALTER TABLE videos ADD COLUMN output_version BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN videos.output_version IS 'Version of the HLS output that hls_url/preview_url point at; regenerated output is written under a new prefix and swapped in atomically';
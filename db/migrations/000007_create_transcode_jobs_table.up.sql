@@ -0,0 +1,22 @@
+CREATE TABLE transcode_jobs (
+    id UUID PRIMARY KEY,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    output_version BIGINT NOT NULL,
+    attempt INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    download_ms BIGINT NOT NULL DEFAULT 0,
+    probe_ms BIGINT NOT NULL DEFAULT 0,
+    transcode_ms BIGINT NOT NULL DEFAULT 0,
+    variant_ms JSONB NOT NULL DEFAULT '{}',
+    upload_ms BIGINT NOT NULL DEFAULT 0,
+    preview_ms BIGINT NOT NULL DEFAULT 0,
+    finalize_ms BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_transcode_jobs_video_id_started_at ON transcode_jobs(video_id, started_at DESC);
+
+COMMENT ON TABLE transcode_jobs IS 'One row per worker attempt, with a per-stage timing breakdown';
+COMMENT ON COLUMN transcode_jobs.variant_ms IS 'Encode time per ABR variant, e.g. {"1080p": 41200, "720p": 23800}';
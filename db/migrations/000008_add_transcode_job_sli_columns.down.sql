@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transcode_jobs_finished_at;
+
+ALTER TABLE transcode_jobs
+    DROP COLUMN IF EXISTS final,
+    DROP COLUMN IF EXISTS queued_at;
@@ -0,0 +1,8 @@
+ALTER TABLE transcode_jobs
+    ADD COLUMN queued_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN final BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_transcode_jobs_finished_at ON transcode_jobs(finished_at) WHERE final;
+
+COMMENT ON COLUMN transcode_jobs.queued_at IS 'When processing was requested; time-to-READY is finished_at - queued_at';
+COMMENT ON COLUMN transcode_jobs.final IS 'TRUE when this attempt decided the outcome (succeeded, or failed with no retries left)';
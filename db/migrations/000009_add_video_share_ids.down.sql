@@ -0,0 +1 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS share_slug, DROP COLUMN IF EXISTS sortable_id;
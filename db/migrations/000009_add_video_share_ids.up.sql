@@ -0,0 +1,9 @@
+ALTER TABLE videos
+    ADD COLUMN sortable_id VARCHAR(32),
+    ADD COLUMN share_slug VARCHAR(16);
+
+CREATE UNIQUE INDEX idx_videos_sortable_id ON videos(sortable_id);
+CREATE UNIQUE INDEX idx_videos_share_slug ON videos(share_slug);
+
+COMMENT ON COLUMN videos.sortable_id IS 'Time-ordered external ID (ULID or KSUID); NULL for videos created before it existed';
+COMMENT ON COLUMN videos.share_slug IS 'Short random ID used in share links (/v1/v/{slug})';
@@ -0,0 +1,20 @@
+CREATE TABLE custom_domains (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    hostname VARCHAR(253) NOT NULL,
+    certificate_id TEXT,
+    verification_token VARCHAR(64) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, hostname)
+);
+
+-- Any tenant may claim a hostname, but only one may prove ownership of it
+CREATE UNIQUE INDEX idx_custom_domains_verified_hostname ON custom_domains(hostname) WHERE status = 'VERIFIED';
+CREATE INDEX idx_custom_domains_tenant_id ON custom_domains(tenant_id, created_at);
+
+COMMENT ON TABLE custom_domains IS 'Tenant vanity hostnames for playback URLs; a tenant is the account owning videos (videos.user_id)';
+COMMENT ON COLUMN custom_domains.certificate_id IS 'TLS certificate provisioned for the hostname at the CDN (e.g., ACM ARN); the domain is not used until set';
+COMMENT ON COLUMN custom_domains.verification_token IS 'Value the tenant publishes in a TXT record at _gostream-verification.{hostname}';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_videos_title_prefix, idx_videos_user_id_created_at, idx_videos_created_at;
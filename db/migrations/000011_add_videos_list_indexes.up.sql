@@ -0,0 +1,6 @@
+-- Admin and bulk listing filter on created_at ranges, optionally per owner, and on title prefix
+CREATE INDEX idx_videos_created_at ON videos(created_at DESC, id DESC);
+CREATE INDEX idx_videos_user_id_created_at ON videos(user_id, created_at DESC);
+
+-- text_pattern_ops lets LIKE 'prefix%' use the index regardless of the database collation
+CREATE INDEX idx_videos_title_prefix ON videos(lower(title) text_pattern_ops);
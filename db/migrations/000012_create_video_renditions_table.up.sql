@@ -0,0 +1,18 @@
+CREATE TABLE video_renditions (
+    id UUID PRIMARY KEY,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    output_version BIGINT NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    bitrate INTEGER NOT NULL,
+    codec VARCHAR(32) NOT NULL,
+    segment_count INTEGER NOT NULL,
+    bytes BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (video_id, output_version, name)
+);
+
+COMMENT ON TABLE video_renditions IS 'Renditions produced by each transcode, written by the worker before the output is published';
+COMMENT ON COLUMN video_renditions.bitrate IS 'Target video bitrate in bits per second';
+COMMENT ON COLUMN video_renditions.bytes IS 'Stored size of the rendition playlist and segments';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_playback_progress_updated_at, idx_transcode_jobs_finished_at_all; DROP TABLE IF EXISTS analytics_export_bookmarks;
@@ -0,0 +1,12 @@
+CREATE TABLE analytics_export_bookmarks (
+    stream VARCHAR(64) PRIMARY KEY,
+    exported_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Hourly export windows scan these tables by event time
+CREATE INDEX idx_transcode_jobs_finished_at_all ON transcode_jobs(finished_at);
+CREATE INDEX idx_playback_progress_updated_at ON playback_progress(updated_at);
+
+COMMENT ON TABLE analytics_export_bookmarks IS 'Progress of the analytics export to object storage, one row per event stream';
+COMMENT ON COLUMN analytics_export_bookmarks.exported_until IS 'Exclusive end of the last exported hourly partition';
@@ -0,0 +1,2 @@
+UPDATE videos SET status = 'PENDING_UPLOAD' WHERE status = 'UPLOADED';
+ALTER TABLE videos DROP COLUMN IF EXISTS original_etag, DROP COLUMN IF EXISTS original_size;
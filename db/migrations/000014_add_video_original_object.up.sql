@@ -0,0 +1,7 @@
+ALTER TABLE videos
+    ADD COLUMN original_size BIGINT,
+    ADD COLUMN original_etag TEXT;
+
+COMMENT ON COLUMN videos.status IS 'Video processing status: PENDING_UPLOAD, UPLOADED, PROCESSING, READY, FAILED';
+COMMENT ON COLUMN videos.original_size IS 'Size in bytes of the uploaded original, recorded when the upload is confirmed';
+COMMENT ON COLUMN videos.original_etag IS 'Storage ETag of the uploaded original, recorded when the upload is confirmed';
@@ -0,0 +1,6 @@
+ALTER TABLE transcode_jobs
+    DROP COLUMN IF EXISTS estimated_output_bytes,
+    DROP COLUMN IF EXISTS estimated_processing_ms,
+    DROP COLUMN IF EXISTS output_bytes,
+    DROP COLUMN IF EXISTS source_height,
+    DROP COLUMN IF EXISTS source_duration_ms;
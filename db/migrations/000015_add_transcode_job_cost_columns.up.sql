@@ -0,0 +1,12 @@
+ALTER TABLE transcode_jobs
+    ADD COLUMN source_duration_ms BIGINT,
+    ADD COLUMN source_height INTEGER,
+    ADD COLUMN output_bytes BIGINT,
+    ADD COLUMN estimated_processing_ms BIGINT,
+    ADD COLUMN estimated_output_bytes BIGINT;
+
+COMMENT ON COLUMN transcode_jobs.source_duration_ms IS 'Probed duration of the original; NULL if probing did not complete';
+COMMENT ON COLUMN transcode_jobs.source_height IS 'Probed height in pixels of the original video stream';
+COMMENT ON COLUMN transcode_jobs.output_bytes IS 'Size of the uploaded ABR output';
+COMMENT ON COLUMN transcode_jobs.estimated_processing_ms IS 'Processing time predicted for this attempt, for estimator calibration';
+COMMENT ON COLUMN transcode_jobs.estimated_output_bytes IS 'Output size predicted for this attempt, for estimator calibration';
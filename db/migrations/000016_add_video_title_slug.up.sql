@@ -0,0 +1,6 @@
+ALTER TABLE videos ADD COLUMN title_slug VARCHAR(64);
+
+-- NULL slugs (disabled, or titles without letters or digits) never collide
+CREATE UNIQUE INDEX idx_videos_user_id_title_slug ON videos(user_id, title_slug);
+
+COMMENT ON COLUMN videos.title_slug IS 'Slug derived from the title, unique per user (/v1/u/{user}/v/{slug})';
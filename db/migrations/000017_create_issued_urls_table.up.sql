@@ -0,0 +1,21 @@
+CREATE TABLE issued_urls (
+    id UUID PRIMARY KEY,
+    object_key TEXT NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    requester_id UUID,
+    video_id UUID,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Per-user issuance caps count a user's recent URLs
+CREATE INDEX idx_issued_urls_requester_issued_at ON issued_urls(requester_id, purpose, issued_at);
+-- Audit lookups by video
+CREATE INDEX idx_issued_urls_video_id ON issued_urls(video_id);
+-- Retention cleanup
+CREATE INDEX idx_issued_urls_expires_at ON issued_urls(expires_at);
+
+COMMENT ON TABLE issued_urls IS 'Audit log of presigned URLs; the URLs themselves are not stored';
+COMMENT ON COLUMN issued_urls.requester_id IS 'User the URL was issued to; NULL for URLs the service issues to itself';
+COMMENT ON COLUMN issued_urls.video_id IS 'Video the object belongs to; not a foreign key so records outlive deleted videos';
@@ -0,0 +1,7 @@
+ALTER TABLE videos
+    DROP COLUMN IF EXISTS source_frame_rate,
+    DROP COLUMN IF EXISTS source_bitrate,
+    DROP COLUMN IF EXISTS source_codec,
+    DROP COLUMN IF EXISTS source_height,
+    DROP COLUMN IF EXISTS source_width,
+    DROP COLUMN IF EXISTS source_duration_ms;
@@ -0,0 +1,14 @@
+ALTER TABLE videos
+    ADD COLUMN source_duration_ms BIGINT,
+    ADD COLUMN source_width INTEGER,
+    ADD COLUMN source_height INTEGER,
+    ADD COLUMN source_codec VARCHAR(32),
+    ADD COLUMN source_bitrate BIGINT,
+    ADD COLUMN source_frame_rate DOUBLE PRECISION;
+
+COMMENT ON COLUMN videos.source_duration_ms IS 'Probed duration of the original; NULL until the first transcode probes it';
+COMMENT ON COLUMN videos.source_width IS 'Probed width in pixels of the original video stream';
+COMMENT ON COLUMN videos.source_height IS 'Probed height in pixels of the original video stream';
+COMMENT ON COLUMN videos.source_codec IS 'Codec of the original video stream as named by ffprobe (e.g., h264)';
+COMMENT ON COLUMN videos.source_bitrate IS 'Overall bitrate of the original in bits per second';
+COMMENT ON COLUMN videos.source_frame_rate IS 'Average frame rate of the original video stream';
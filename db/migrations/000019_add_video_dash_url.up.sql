@@ -0,0 +1,4 @@
+ALTER TABLE videos
+    ADD COLUMN dash_url TEXT;
+
+COMMENT ON COLUMN videos.dash_url IS 'Storage key of the MPEG-DASH manifest of the published output; NULL when DASH is not produced';
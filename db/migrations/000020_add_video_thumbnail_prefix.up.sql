@@ -0,0 +1,4 @@
+ALTER TABLE videos
+    ADD COLUMN thumbnail_prefix TEXT;
+
+COMMENT ON COLUMN videos.thumbnail_prefix IS 'Storage prefix of the published output''s poster images ({size}.jpg); NULL when none were generated';
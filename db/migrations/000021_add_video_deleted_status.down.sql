@@ -0,0 +1 @@
+COMMENT ON COLUMN videos.status IS 'Video processing status: PENDING_UPLOAD, UPLOADED, PROCESSING, READY, FAILED';
@@ -0,0 +1,4 @@
+ALTER TABLE videos
+    ADD COLUMN storage_key TEXT;
+
+COMMENT ON COLUMN videos.storage_key IS 'Path segment of the video''s objects (originals/{key}/, hls/{key}/, previews/{key}/), derived by HMAC from the ID; NULL when objects are stored under the ID';
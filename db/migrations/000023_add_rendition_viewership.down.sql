@@ -0,0 +1,3 @@
+ALTER TABLE video_renditions
+    DROP COLUMN IF EXISTS pruned_at,
+    DROP COLUMN IF EXISTS last_viewed_at;
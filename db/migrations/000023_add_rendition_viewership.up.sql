@@ -0,0 +1,6 @@
+ALTER TABLE video_renditions
+    ADD COLUMN last_viewed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN pruned_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN video_renditions.last_viewed_at IS 'Last time a viewer fetched the rendition playlist through the API, at most hourly; NULL if never';
+COMMENT ON COLUMN video_renditions.pruned_at IS 'When the rendition was removed from the output for lack of viewers; NULL while it is served';
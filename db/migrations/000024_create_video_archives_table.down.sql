@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS video_archives;
+
+COMMENT ON COLUMN videos.status IS 'Video processing status: PENDING_UPLOAD, UPLOADED, PROCESSING, READY, FAILED, DELETED';
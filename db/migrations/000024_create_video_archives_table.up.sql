@@ -0,0 +1,23 @@
+CREATE TABLE video_archives (
+    video_id UUID PRIMARY KEY REFERENCES videos(id) ON DELETE CASCADE,
+    output_version BIGINT NOT NULL,
+    prefix TEXT NOT NULL,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    archived_at TIMESTAMP WITH TIME ZONE,
+    restore_requested_at TIMESTAMP WITH TIME ZONE,
+    restore_eta TIMESTAMP WITH TIME ZONE,
+    leased_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The worker claims rows with pending moves: not yet archived, or restore requested
+CREATE INDEX idx_video_archives_pending ON video_archives(created_at)
+    WHERE archived_at IS NULL OR restore_requested_at IS NOT NULL;
+
+COMMENT ON TABLE video_archives IS 'Output of ARCHIVED videos, from the archive request until the output is served again';
+COMMENT ON COLUMN video_archives.prefix IS 'Storage prefix of the archived output; the archive tier holds it under archive/{prefix}';
+COMMENT ON COLUMN video_archives.archived_at IS 'When the objects finished moving to the archive tier; NULL while they move';
+COMMENT ON COLUMN video_archives.restore_eta IS 'When the restore requested at restore_requested_at is expected to complete';
+COMMENT ON COLUMN video_archives.leased_until IS 'Until when a worker holds the row while moving its objects';
+
+COMMENT ON COLUMN videos.status IS 'Video processing status: PENDING_UPLOAD, UPLOADED, PROCESSING, READY, FAILED, DELETED, ARCHIVED';
@@ -0,0 +1,17 @@
+CREATE TABLE video_subtitles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    language VARCHAR(35) NOT NULL,
+    label VARCHAR(64) NOT NULL,
+    format VARCHAR(8) NOT NULL,
+    source_key TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (video_id, language)
+);
+
+COMMENT ON TABLE video_subtitles IS 'Subtitle tracks of a video, one per language, listed in every HLS output as EXT-X-MEDIA subtitle renditions';
+COMMENT ON COLUMN video_subtitles.language IS 'BCP 47 language tag of the track';
+COMMENT ON COLUMN video_subtitles.format IS 'Format of the uploaded file: srt or vtt';
+COMMENT ON COLUMN video_subtitles.source_key IS 'Storage key of the uploaded file; the worker converts it to segmented WebVTT per output version';
@@ -0,0 +1,8 @@
+CREATE TABLE video_encryption_keys (
+    video_id UUID PRIMARY KEY REFERENCES videos(id) ON DELETE CASCADE,
+    key BYTEA NOT NULL CHECK (octet_length(key) = 16),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE video_encryption_keys IS 'AES-128 keys of videos with encrypted HLS segments, served to players holding a playback token';
+COMMENT ON COLUMN video_encryption_keys.key IS 'Raw 16-byte content key, shared by every output version of the video';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_videos_expires_at;
+
+ALTER TABLE videos DROP COLUMN IF EXISTS expires_at;
+
+COMMENT ON COLUMN videos.status IS 'Video processing status: PENDING_UPLOAD, UPLOADED, PROCESSING, READY, FAILED, DELETED, ARCHIVED';
@@ -0,0 +1,7 @@
+ALTER TABLE videos ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+
+-- The expiry scheduler scans videos whose expiry has passed
+CREATE INDEX idx_videos_expires_at ON videos(expires_at) WHERE expires_at IS NOT NULL;
+
+COMMENT ON COLUMN videos.status IS 'Video processing status: PENDING_UPLOAD, UPLOADED, PROCESSING, READY, FAILED, DELETED, ARCHIVED, EXPIRED';
+COMMENT ON COLUMN videos.expires_at IS 'When the video is unpublished (moved to EXPIRED); NULL never expires it';
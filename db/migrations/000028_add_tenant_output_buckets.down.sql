@@ -0,0 +1,3 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS external_output;
+
+DROP TABLE IF EXISTS tenant_output_buckets;
@@ -0,0 +1,21 @@
+CREATE TABLE tenant_output_buckets (
+    tenant_id UUID PRIMARY KEY,
+    endpoint VARCHAR(260) NOT NULL,
+    region VARCHAR(64),
+    bucket VARCHAR(63) NOT NULL,
+    use_ssl BOOLEAN NOT NULL,
+    access_key_id TEXT NOT NULL,
+    secret_ref TEXT NOT NULL,
+    public_base_url TEXT NOT NULL,
+    checked_at TIMESTAMP WITH TIME ZONE,
+    check_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE videos ADD COLUMN external_output BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON TABLE tenant_output_buckets IS 'S3-compatible buckets tenants bring for their transcode output; a tenant is the account owning videos (videos.user_id)';
+COMMENT ON COLUMN tenant_output_buckets.secret_ref IS 'Reference to the secret access key in the secrets provider; never the key itself';
+COMMENT ON COLUMN tenant_output_buckets.check_error IS 'Why the last health check failed; NULL when it passed';
+COMMENT ON COLUMN videos.external_output IS 'The published output is stored in the owner''s output bucket rather than platform storage';
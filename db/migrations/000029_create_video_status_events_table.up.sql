@@ -0,0 +1,17 @@
+CREATE TABLE video_status_events (
+    id UUID PRIMARY KEY,
+    video_id UUID NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- History lookups by video, most recent first
+CREATE INDEX idx_video_status_events_video_created_at ON video_status_events(video_id, created_at DESC);
+
+COMMENT ON TABLE video_status_events IS 'Audit log of video status transitions';
+COMMENT ON COLUMN video_status_events.video_id IS 'Not a foreign key so the history outlives deleted videos';
+COMMENT ON COLUMN video_status_events.from_status IS 'NULL for the status a video was created with';
+COMMENT ON COLUMN video_status_events.actor IS 'Component that made the transition: api, worker, expirer or archiver';
@@ -0,0 +1,2 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS failure_reason;
+ALTER TABLE videos DROP COLUMN IF EXISTS failure_code;
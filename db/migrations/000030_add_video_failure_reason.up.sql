@@ -0,0 +1,5 @@
+ALTER TABLE videos ADD COLUMN failure_code VARCHAR(32);
+ALTER TABLE videos ADD COLUMN failure_reason TEXT;
+
+COMMENT ON COLUMN videos.failure_code IS 'Why a FAILED video failed: invalid_input, encode_failed, retries_exhausted, expired or internal; NULL in other statuses';
+COMMENT ON COLUMN videos.failure_reason IS 'Error of the failed transcode, ending with the tail of the FFmpeg output when it failed; NULL in other statuses';
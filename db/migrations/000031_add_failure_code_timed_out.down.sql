@@ -0,0 +1 @@
+COMMENT ON COLUMN videos.failure_code IS 'Why a FAILED video failed: invalid_input, encode_failed, retries_exhausted, expired or internal; NULL in other statuses';
@@ -0,0 +1 @@
+COMMENT ON COLUMN videos.failure_code IS 'Why a FAILED video failed: invalid_input, encode_failed, retries_exhausted, expired, timed_out or internal; NULL in other statuses';
@@ -0,0 +1,2 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS idempotency_hash;
+ALTER TABLE videos DROP COLUMN IF EXISTS idempotency_key;
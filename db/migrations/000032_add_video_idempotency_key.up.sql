@@ -0,0 +1,8 @@
+ALTER TABLE videos ADD COLUMN idempotency_key VARCHAR(255);
+ALTER TABLE videos ADD COLUMN idempotency_hash VARCHAR(64);
+
+-- NULLs are distinct, so videos created without a key never collide
+CREATE UNIQUE INDEX idx_videos_user_id_idempotency_key ON videos(user_id, idempotency_key);
+
+COMMENT ON COLUMN videos.idempotency_key IS 'Idempotency-Key of the create request, unique per user; NULL when none was sent';
+COMMENT ON COLUMN videos.idempotency_hash IS 'SHA-256 of the create request, telling a retry from a reuse of the key for another request';
@@ -0,0 +1,27 @@
+CREATE TABLE users (
+    id UUID PRIMARY KEY,
+    email VARCHAR(254) NOT NULL UNIQUE,
+    name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100),
+    hint VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id, created_at);
+
+COMMENT ON TABLE users IS 'Accounts owning videos (videos.user_id, not a foreign key: older videos reference IDs without a user)';
+COMMENT ON TABLE api_keys IS 'Keys authenticating API requests as their user';
+COMMENT ON COLUMN api_keys.hint IS 'Start of the key, shown in listings so the owner can tell keys apart';
+COMMENT ON COLUMN api_keys.key_hash IS 'Hex SHA-256 of the key; the key itself is only shown when issued';
+COMMENT ON COLUMN api_keys.last_used_at IS 'Last authentication, updated at most once a minute';
+COMMENT ON COLUMN api_keys.revoked_at IS 'Set when the key is revoked; revoked keys are kept for auditing';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS transcode_task_outbox;
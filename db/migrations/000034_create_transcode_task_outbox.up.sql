@@ -0,0 +1,16 @@
+CREATE TABLE transcode_task_outbox (
+    id UUID PRIMARY KEY,
+    video_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transcode_task_outbox_available_at ON transcode_task_outbox(available_at);
+
+COMMENT ON TABLE transcode_task_outbox IS 'Transcode tasks written with the video update that requires them and deleted once published';
+COMMENT ON COLUMN transcode_task_outbox.video_id IS 'Not a foreign key; a task of a deleted video is still published and dropped by the worker';
+COMMENT ON COLUMN transcode_task_outbox.payload IS 'The task as published to the queue';
+COMMENT ON COLUMN transcode_task_outbox.available_at IS 'When the relay may claim the task; a claim or failed publication moves it forward';
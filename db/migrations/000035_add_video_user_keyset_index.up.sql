@@ -0,0 +1,7 @@
+-- Per-user listings page on (created_at, id) in either direction; with id in the index
+-- the keyset condition and ORDER BY are a single range scan.
+-- CONCURRENTLY keeps writes to videos flowing while the index builds. It cannot run in a
+-- transaction, and migrate sends a file as one implicit transaction, so the file holds
+-- this statement alone and the index it replaces is dropped by 000045. A failed build
+-- leaves an INVALID index behind; drop it before running the migration again.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_videos_user_id_created_at_id ON videos(user_id, created_at DESC, id DESC);
@@ -0,0 +1,8 @@
+-- 'simple' neither stems nor drops stop words, so titles in any language are searchable;
+-- a description column can be appended to the expression once videos have one
+ALTER TABLE videos ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(title, ''))) STORED;
+
+CREATE INDEX idx_videos_search_vector ON videos USING GIN (search_vector);
+
+COMMENT ON COLUMN videos.search_vector IS 'Lexemes of the title for GET /v1/videos/search, maintained by PostgreSQL';
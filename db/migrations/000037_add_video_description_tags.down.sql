@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_videos_search_vector;
+ALTER TABLE videos DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE videos ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(title, ''))) STORED;
+
+CREATE INDEX idx_videos_search_vector ON videos USING GIN (search_vector);
+
+COMMENT ON COLUMN videos.search_vector IS 'Lexemes of the title for GET /v1/videos/search, maintained by PostgreSQL';
+
+ALTER TABLE videos DROP COLUMN IF EXISTS tags;
+ALTER TABLE videos DROP COLUMN IF EXISTS description;
@@ -0,0 +1,18 @@
+ALTER TABLE videos ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE videos ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Rebuild the search vector so descriptions are searchable too; title matches are
+-- weighted above description matches so they rank first
+DROP INDEX IF EXISTS idx_videos_search_vector;
+ALTER TABLE videos DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE videos ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_videos_search_vector ON videos USING GIN (search_vector);
+
+COMMENT ON COLUMN videos.description IS 'Free-form description set by the owner; empty when unset';
+COMMENT ON COLUMN videos.tags IS 'Lowercase, deduplicated tags set by the owner';
+COMMENT ON COLUMN videos.search_vector IS 'Lexemes of the title (weight A) and description (weight B) for GET /v1/videos/search, maintained by PostgreSQL';
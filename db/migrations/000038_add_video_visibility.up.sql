@@ -0,0 +1,4 @@
+-- Existing videos stay reachable by ID and share link, as they were before
+ALTER TABLE videos ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'unlisted';
+
+COMMENT ON COLUMN videos.visibility IS 'public, unlisted or private; private videos are only shown and played to their owner';
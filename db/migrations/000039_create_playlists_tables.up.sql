@@ -0,0 +1,25 @@
+CREATE TABLE playlists (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility VARCHAR(16) NOT NULL DEFAULT 'unlisted',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE playlist_items (
+    playlist_id UUID NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (playlist_id, video_id),
+    UNIQUE (playlist_id, position)
+);
+
+CREATE INDEX idx_playlists_user_id ON playlists(user_id, created_at);
+-- Purging a video deletes its items through the foreign key
+CREATE INDEX idx_playlist_items_video_id ON playlist_items(video_id);
+
+COMMENT ON TABLE playlists IS 'Ordered lists of a user''s videos, such as channels or courses';
+COMMENT ON COLUMN playlists.visibility IS 'public, unlisted or private, like videos.visibility';
+COMMENT ON TABLE playlist_items IS 'Videos of a playlist; the list is replaced as a whole, so positions are always 0..n-1';
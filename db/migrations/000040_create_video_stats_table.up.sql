@@ -0,0 +1,9 @@
+CREATE TABLE video_stats (
+    video_id UUID PRIMARY KEY REFERENCES videos(id) ON DELETE CASCADE,
+    views BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE video_stats IS 'View counts, flushed from Redis counters; rows exist once a video has been viewed';
+COMMENT ON COLUMN video_stats.last_viewed_at IS 'Latest view flushed so far';
@@ -0,0 +1,4 @@
+ALTER TABLE videos
+    ADD COLUMN output_size BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN videos.output_size IS 'Size in bytes of the published output in our storage, recorded by the worker; 0 for output in a tenant bucket';
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN plan VARCHAR(32) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN users.plan IS 'Subscription plan selecting the concurrent stream limit (PLAYBACK_PLAN_STREAM_LIMITS); empty uses the default limit';
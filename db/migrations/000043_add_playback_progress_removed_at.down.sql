@@ -0,0 +1,4 @@
+DELETE FROM playback_progress WHERE removed_at IS NOT NULL;
+
+ALTER TABLE playback_progress
+    DROP COLUMN IF EXISTS removed_at;
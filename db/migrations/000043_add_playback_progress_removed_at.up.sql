@@ -0,0 +1,4 @@
+ALTER TABLE playback_progress
+    ADD COLUMN removed_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN playback_progress.removed_at IS 'When the user removed the video from their history; the row stays so a flush of older heartbeats cannot restore it';
@@ -0,0 +1,16 @@
+CREATE TABLE superseded_outputs (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    output_version BIGINT NOT NULL,
+    user_id UUID NOT NULL,
+    prefixes TEXT[] NOT NULL,
+    external_output BOOLEAN NOT NULL DEFAULT FALSE,
+    delete_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (video_id, output_version)
+);
+
+CREATE INDEX idx_superseded_outputs_delete_after ON superseded_outputs(delete_after);
+
+COMMENT ON TABLE superseded_outputs IS 'Output versions replaced by a regenerated one, awaiting deletion once CDN caches have drained';
+COMMENT ON COLUMN superseded_outputs.prefixes IS 'Storage prefixes of the superseded version (HLS/DASH and preview)';
+COMMENT ON COLUMN superseded_outputs.delete_after IS 'When the worker may delete the prefixes; a claim moves it forward by the lease';
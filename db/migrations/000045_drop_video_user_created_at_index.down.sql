@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_videos_user_id_created_at ON videos(user_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- Superseded by idx_videos_user_id_created_at_id (000035); a file of its own, as
+-- CONCURRENTLY cannot run in a transaction
+DROP INDEX CONCURRENTLY IF EXISTS idx_videos_user_id_created_at;
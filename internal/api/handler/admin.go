@@ -0,0 +1,587 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type StageTimingsResponse struct {
+	DownloadMs  int64            `json:"download_ms"`
+	ProbeMs     int64            `json:"probe_ms"`
+	TranscodeMs int64            `json:"transcode_ms"`
+	VariantsMs  map[string]int64 `json:"variants_ms"`
+	UploadMs    int64            `json:"upload_ms"`
+	PreviewMs   int64            `json:"preview_ms"`
+	FinalizeMs  int64            `json:"finalize_ms"`
+	TotalMs     int64            `json:"total_ms"`
+}
+
+type TranscodeJobResponse struct {
+	ID            string               `json:"id"`
+	VideoID       string               `json:"video_id"`
+	OutputVersion int64                `json:"output_version"`
+	Attempt       int                  `json:"attempt"`
+	Status        string               `json:"status"`
+	Error         string               `json:"error,omitempty"`
+	Timings       StageTimingsResponse `json:"timings"`
+	StartedAt     string               `json:"started_at"`
+	FinishedAt    string               `json:"finished_at"`
+}
+
+type TranscodeJobsResponse struct {
+	Items []TranscodeJobResponse `json:"items"`
+}
+
+type AdminVideosResponse struct {
+	Items []VideoResponse `json:"items"`
+}
+
+type ForceStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type RequeueTranscodeResponse struct {
+	VideoID       string `json:"video_id"`
+	OutputVersion int64  `json:"output_version"`
+	EnqueuedAt    string `json:"enqueued_at"`
+}
+
+type RenditionResponse struct {
+	Name          string `json:"name"`
+	OutputVersion int64  `json:"output_version"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	Bitrate       int    `json:"bitrate"`
+	Codec         string `json:"codec"`
+	SegmentCount  int    `json:"segment_count"`
+	Bytes         int64  `json:"bytes"`
+	LastViewedAt  string `json:"last_viewed_at,omitempty"`
+	PrunedAt      string `json:"pruned_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type RenditionsResponse struct {
+	Items []RenditionResponse `json:"items"`
+}
+
+type RatioSLIResponse struct {
+	Good      int64   `json:"good"`
+	Total     int64   `json:"total"`
+	Ratio     float64 `json:"ratio"`
+	Objective float64 `json:"objective"`
+	BurnRate  float64 `json:"burn_rate"`
+}
+
+type LatencySLIResponse struct {
+	P95Seconds       float64 `json:"p95_seconds"`
+	Samples          int64   `json:"samples"`
+	ObjectiveSeconds float64 `json:"objective_seconds"`
+	Met              bool    `json:"met"`
+}
+
+type SLOSnapshotResponse struct {
+	WindowSeconds    float64            `json:"window_seconds"`
+	GeneratedAt      string             `json:"generated_at"`
+	TranscodeSuccess RatioSLIResponse   `json:"transcode_success"`
+	TimeToReady      LatencySLIResponse `json:"time_to_ready"`
+	APIAvailability  RatioSLIResponse   `json:"api_availability"`
+}
+
+type OutputChecksumFailureResponse struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+type OutputVerificationResponse struct {
+	VideoID       string                          `json:"video_id"`
+	OutputVersion int64                           `json:"output_version"`
+	Checked       int                             `json:"checked"`
+	OK            bool                            `json:"ok"`
+	Failures      []OutputChecksumFailureResponse `json:"failures"`
+}
+
+type HotKeyResponse struct {
+	VideoID string `json:"video_id"`
+	Hits    int64  `json:"hits"`
+	Class   string `json:"class"`
+}
+
+type HotKeysResponse struct {
+	WindowSeconds float64          `json:"window_seconds"`
+	GeneratedAt   string           `json:"generated_at"`
+	Items         []HotKeyResponse `json:"items"`
+}
+
+type MessageAgeResponse struct {
+	Sampled    int     `json:"sampled"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+type QueueResponse struct {
+	Name       string             `json:"name"`
+	Depth      int                `json:"depth"`
+	Ready      int                `json:"ready"`
+	Unacked    int                `json:"unacked"`
+	Consumers  int                `json:"consumers"`
+	MessageAge MessageAgeResponse `json:"message_age"`
+}
+
+type QueuesResponse struct {
+	GeneratedAt string          `json:"generated_at"`
+	Items       []QueueResponse `json:"items"`
+	DeadLetter  *QueueResponse  `json:"dead_letter,omitempty"`
+}
+
+// AdminHandler handles operator-facing HTTP requests.
+type AdminHandler struct {
+	svc usecase.AdminService
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(svc usecase.AdminService) *AdminHandler {
+	return &AdminHandler{svc: svc}
+}
+
+// ListTranscodeJobs handles GET /v1/admin/videos/{id}/transcode-jobs
+func (h *AdminHandler) ListTranscodeJobs(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_limit", "Limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	jobs, err := h.svc.ListTranscodeJobs(r.Context(), videoID, limit)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	items := make([]TranscodeJobResponse, len(jobs))
+	for i, job := range jobs {
+		variants := make(map[string]int64, len(job.Timings.Variants))
+		for name, d := range job.Timings.Variants {
+			variants[name] = d.Milliseconds()
+		}
+
+		items[i] = TranscodeJobResponse{
+			ID:            job.ID.String(),
+			VideoID:       job.VideoID.String(),
+			OutputVersion: job.OutputVersion,
+			Attempt:       job.Attempt,
+			Status:        string(job.Status),
+			Error:         job.Error,
+			Timings: StageTimingsResponse{
+				DownloadMs:  job.Timings.Download.Milliseconds(),
+				ProbeMs:     job.Timings.Probe.Milliseconds(),
+				TranscodeMs: job.Timings.Transcode.Milliseconds(),
+				VariantsMs:  variants,
+				UploadMs:    job.Timings.Upload.Milliseconds(),
+				PreviewMs:   job.Timings.Preview.Milliseconds(),
+				FinalizeMs:  job.Timings.Finalize.Milliseconds(),
+				TotalMs:     job.Timings.Total().Milliseconds(),
+			},
+			StartedAt:  job.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+			FinishedAt: job.FinishedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	JSON(w, http.StatusOK, TranscodeJobsResponse{Items: items})
+}
+
+// TranscodeJobLog handles GET /v1/admin/videos/{id}/transcode-jobs/{jobID}/log
+// It serves the FFmpeg output of the job, uploaded when the worker's WORKER_FFMPEG_LOGS mode selects it.
+func (h *AdminHandler) TranscodeJobLog(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_job_id", "Job ID must be a valid UUID")
+		return
+	}
+
+	log, err := h.svc.TranscodeJobLog(r.Context(), videoID, jobID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	defer log.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, log)
+}
+
+// ListRenditions handles GET /v1/admin/videos/{id}/renditions
+func (h *AdminHandler) ListRenditions(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	renditions, err := h.svc.ListRenditions(r.Context(), videoID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	items := make([]RenditionResponse, len(renditions))
+	for i, rd := range renditions {
+		items[i] = RenditionResponse{
+			Name:          rd.Name,
+			OutputVersion: rd.OutputVersion,
+			Width:         rd.Width,
+			Height:        rd.Height,
+			Bitrate:       rd.Bitrate,
+			Codec:         rd.Codec,
+			SegmentCount:  rd.SegmentCount,
+			Bytes:         rd.Bytes,
+			CreatedAt:     rd.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if !rd.LastViewedAt.IsZero() {
+			items[i].LastViewedAt = rd.LastViewedAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+		if rd.IsPruned() {
+			items[i].PrunedAt = rd.PrunedAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+	}
+
+	JSON(w, http.StatusOK, RenditionsResponse{Items: items})
+}
+
+// VerifyOutput handles POST /v1/admin/videos/{id}/verify-output
+// Mismatched files are reported in the body with 200; the request itself only fails when
+// verification cannot run.
+func (h *AdminHandler) VerifyOutput(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	result, err := h.svc.VerifyOutput(r.Context(), videoID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	failures := make([]OutputChecksumFailureResponse, len(result.Failures))
+	for i, f := range result.Failures {
+		failures[i] = OutputChecksumFailureResponse{Key: f.Key, Reason: f.Reason}
+	}
+
+	JSON(w, http.StatusOK, OutputVerificationResponse{
+		VideoID:       result.VideoID.String(),
+		OutputVersion: result.OutputVersion,
+		Checked:       result.Checked,
+		OK:            result.OK(),
+		Failures:      failures,
+	})
+}
+
+// ListVideos handles GET /v1/admin/videos?user_id=...&status=...&created_after=...&created_before=...&title_prefix=...&limit=50
+// Dates are RFC 3339. To page, pass the created_at of the last item as created_before.
+func (h *AdminHandler) ListVideos(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var filter repository.VideoFilter
+
+	if v := q.Get("user_id"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+			return
+		}
+		filter.UserID = userID
+	}
+
+	if v := q.Get("created_after"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_created_after", "created_after must be an RFC 3339 timestamp")
+			return
+		}
+		filter.CreatedAfter = t
+	}
+
+	if v := q.Get("created_before"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_created_before", "created_before must be an RFC 3339 timestamp")
+			return
+		}
+		filter.CreatedBefore = t
+	}
+
+	if v := q.Get("status"); v != "" {
+		status := model.Status(strings.ToUpper(v))
+		if !status.IsValid() {
+			Error(w, http.StatusBadRequest, "invalid_status", "Unknown video status")
+			return
+		}
+		filter.Status = status
+	}
+
+	filter.TitlePrefix = q.Get("title_prefix")
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_limit", "Limit must be a positive integer")
+			return
+		}
+		filter.Limit = n
+	}
+
+	videos, err := h.svc.ListVideos(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	items := make([]VideoResponse, len(videos))
+	for i, video := range videos {
+		items[i] = toVideoResponse(video)
+	}
+
+	JSON(w, http.StatusOK, AdminVideosResponse{Items: items})
+}
+
+// ForceStatus handles POST /v1/admin/videos/{id}/status
+// Moves a video to {"status"} outside the normal transitions; {"reason"} is required.
+func (h *AdminHandler) ForceStatus(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	var req ForceStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	video, err := h.svc.ForceStatus(r.Context(), videoID, usecase.ForceStatusInput{
+		Status: model.Status(strings.ToUpper(req.Status)),
+		Reason: req.Reason,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toVideoResponse(video))
+}
+
+// RequeueTranscode handles POST /v1/admin/videos/{id}/requeue
+// Publishes a new transcode task for a PROCESSING video whose task was lost.
+func (h *AdminHandler) RequeueTranscode(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	task, err := h.svc.RequeueTranscode(r.Context(), videoID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusAccepted, RequeueTranscodeResponse{
+		VideoID:       task.VideoID.String(),
+		OutputVersion: task.OutputVersion,
+		EnqueuedAt:    task.EnqueuedAt.Format(time.RFC3339),
+	})
+}
+
+// FlushVideoCache handles DELETE /v1/admin/videos/{id}/cache
+func (h *AdminHandler) FlushVideoCache(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	if err := h.svc.FlushVideoCache(r.Context(), videoID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SLOSnapshot handles GET /v1/admin/slo?window=1h
+// Intended for dashboards; alerting should use the Prometheus sli_* series, which aggregate across replicas.
+func (h *AdminHandler) SLOSnapshot(w http.ResponseWriter, r *http.Request) {
+	var window time.Duration
+	if v := r.URL.Query().Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_window", "Window must be a positive duration (e.g., 1h, 30m)")
+			return
+		}
+		window = d
+	}
+
+	snapshot, err := h.svc.SLOSnapshot(r.Context(), window)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, SLOSnapshotResponse{
+		WindowSeconds:    snapshot.Window.Seconds(),
+		GeneratedAt:      snapshot.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"),
+		TranscodeSuccess: newRatioSLIResponse(snapshot.TranscodeSuccess),
+		TimeToReady: LatencySLIResponse{
+			P95Seconds:       snapshot.TimeToReady.P95.Seconds(),
+			Samples:          snapshot.TimeToReady.Samples,
+			ObjectiveSeconds: snapshot.TimeToReady.Objective.Seconds(),
+			Met:              snapshot.TimeToReady.Met,
+		},
+		APIAvailability: newRatioSLIResponse(snapshot.APIAvailability),
+	})
+}
+
+// HotKeys handles GET /v1/admin/cache/hot-keys?limit=20
+// Counts cover only the API instance answering; use the key_class metric labels for fleet-wide trends.
+func (h *AdminHandler) HotKeys(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_limit", "Limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	snapshot, err := h.svc.HotKeys(r.Context(), limit)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	items := make([]HotKeyResponse, len(snapshot.Keys))
+	for i, k := range snapshot.Keys {
+		items[i] = HotKeyResponse{VideoID: k.VideoID.String(), Hits: k.Hits, Class: k.Class}
+	}
+
+	JSON(w, http.StatusOK, HotKeysResponse{
+		WindowSeconds: snapshot.Window.Seconds(),
+		GeneratedAt:   snapshot.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Items:         items,
+	})
+}
+
+// QueueStats handles GET /v1/admin/queues
+func (h *AdminHandler) QueueStats(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.svc.QueueStats(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	resp := QueuesResponse{
+		GeneratedAt: snapshot.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Items:       make([]QueueResponse, len(snapshot.Queues)),
+	}
+	for i, q := range snapshot.Queues {
+		resp.Items[i] = newQueueResponse(q)
+	}
+	if snapshot.DeadLetter != nil {
+		dead := newQueueResponse(*snapshot.DeadLetter)
+		resp.DeadLetter = &dead
+	}
+
+	JSON(w, http.StatusOK, resp)
+}
+
+func newQueueResponse(q usecase.QueueSnapshot) QueueResponse {
+	return QueueResponse{
+		Name:      q.Name,
+		Depth:     q.Depth,
+		Ready:     q.Ready,
+		Unacked:   q.Unacked,
+		Consumers: q.Consumers,
+		MessageAge: MessageAgeResponse{
+			Sampled:    q.Age.Sampled,
+			P50Seconds: q.Age.P50.Seconds(),
+			P90Seconds: q.Age.P90.Seconds(),
+			P99Seconds: q.Age.P99.Seconds(),
+			MaxSeconds: q.Age.Max.Seconds(),
+		},
+	}
+}
+
+func newRatioSLIResponse(sli usecase.RatioSLI) RatioSLIResponse {
+	return RatioSLIResponse{
+		Good:      sli.Good,
+		Total:     sli.Total,
+		Ratio:     sli.Ratio,
+		Objective: sli.Objective,
+		BurnRate:  sli.BurnRate,
+	}
+}
+
+func (h *AdminHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrVideoNotFound):
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, usecase.ErrInvalidDateRange):
+		Error(w, http.StatusBadRequest, "invalid_date_range", "created_after must be before created_before")
+	case errors.Is(err, usecase.ErrVideoNotReady):
+		Error(w, http.StatusConflict, "video_not_ready", "Video has no published output")
+	case errors.Is(err, usecase.ErrExternalOutput):
+		Error(w, http.StatusConflict, "external_output", "Output is stored in the tenant's output bucket")
+	case errors.Is(err, usecase.ErrOutputChecksumsNotFound):
+		Error(w, http.StatusNotFound, "checksums_not_found", "Output has no checksum manifest")
+	case errors.Is(err, usecase.ErrTranscodeLogNotFound):
+		Error(w, http.StatusNotFound, "transcode_log_not_found", "Transcode job has no FFmpeg log")
+	case errors.Is(err, usecase.ErrQueueStatsDisabled):
+		Error(w, http.StatusNotFound, "queue_stats_disabled", "Queue statistics are not enabled")
+	case errors.Is(err, repository.ErrQueueNotFound):
+		Error(w, http.StatusNotFound, "queue_not_found", err.Error())
+	case errors.Is(err, usecase.ErrForceReasonRequired):
+		Error(w, http.StatusBadRequest, "reason_required", "A reason is required to force a status")
+	case errors.Is(err, model.ErrStatusNotForceable):
+		Error(w, http.StatusConflict, "status_not_forceable", "Video cannot be forced into this status")
+	case errors.Is(err, usecase.ErrVideoNotProcessing):
+		Error(w, http.StatusConflict, "video_not_processing", "Video is not processing")
+	case errors.Is(err, usecase.ErrVideoCacheDisabled):
+		Error(w, http.StatusNotFound, "video_cache_disabled", "Video cache is not enabled")
+	default:
+		ServiceError(w, err)
+	}
+}
@@ -0,0 +1,894 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Mock AdminService
+
+type mockAdminService struct {
+	listTranscodeJobsFn func(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error)
+	sloSnapshotFn       func(ctx context.Context, window time.Duration) (*usecase.SLOSnapshot, error)
+	listVideosFn        func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error)
+	listRenditionsFn    func(ctx context.Context, videoID uuid.UUID) ([]*model.Rendition, error)
+	verifyOutputFn      func(ctx context.Context, videoID uuid.UUID) (*usecase.OutputVerification, error)
+	hotKeysFn           func(ctx context.Context, limit int) (*usecase.HotKeysSnapshot, error)
+	queueStatsFn        func(ctx context.Context) (*usecase.QueuesSnapshot, error)
+	transcodeJobLogFn   func(ctx context.Context, videoID, jobID uuid.UUID) (io.ReadCloser, error)
+	forceStatusFn       func(ctx context.Context, videoID uuid.UUID, input usecase.ForceStatusInput) (*model.Video, error)
+	requeueTranscodeFn  func(ctx context.Context, videoID uuid.UUID) (*repository.TranscodeTask, error)
+	flushVideoCacheFn   func(ctx context.Context, videoID uuid.UUID) error
+}
+
+func (m *mockAdminService) ForceStatus(ctx context.Context, videoID uuid.UUID, input usecase.ForceStatusInput) (*model.Video, error) {
+	if m.forceStatusFn != nil {
+		return m.forceStatusFn(ctx, videoID, input)
+	}
+	return &model.Video{ID: videoID, Status: input.Status}, nil
+}
+
+func (m *mockAdminService) RequeueTranscode(ctx context.Context, videoID uuid.UUID) (*repository.TranscodeTask, error) {
+	if m.requeueTranscodeFn != nil {
+		return m.requeueTranscodeFn(ctx, videoID)
+	}
+	return &repository.TranscodeTask{VideoID: videoID}, nil
+}
+
+func (m *mockAdminService) FlushVideoCache(ctx context.Context, videoID uuid.UUID) error {
+	if m.flushVideoCacheFn != nil {
+		return m.flushVideoCacheFn(ctx, videoID)
+	}
+	return nil
+}
+
+func (m *mockAdminService) VerifyOutput(ctx context.Context, videoID uuid.UUID) (*usecase.OutputVerification, error) {
+	if m.verifyOutputFn != nil {
+		return m.verifyOutputFn(ctx, videoID)
+	}
+	return &usecase.OutputVerification{VideoID: videoID}, nil
+}
+
+func (m *mockAdminService) HotKeys(ctx context.Context, limit int) (*usecase.HotKeysSnapshot, error) {
+	if m.hotKeysFn != nil {
+		return m.hotKeysFn(ctx, limit)
+	}
+	return &usecase.HotKeysSnapshot{}, nil
+}
+
+func (m *mockAdminService) QueueStats(ctx context.Context) (*usecase.QueuesSnapshot, error) {
+	if m.queueStatsFn != nil {
+		return m.queueStatsFn(ctx)
+	}
+	return &usecase.QueuesSnapshot{}, nil
+}
+
+func (m *mockAdminService) ListRenditions(ctx context.Context, videoID uuid.UUID) ([]*model.Rendition, error) {
+	if m.listRenditionsFn != nil {
+		return m.listRenditionsFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminService) ListTranscodeJobs(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error) {
+	if m.listTranscodeJobsFn != nil {
+		return m.listTranscodeJobsFn(ctx, videoID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminService) TranscodeJobLog(ctx context.Context, videoID, jobID uuid.UUID) (io.ReadCloser, error) {
+	if m.transcodeJobLogFn != nil {
+		return m.transcodeJobLogFn(ctx, videoID, jobID)
+	}
+	return nil, usecase.ErrTranscodeLogNotFound
+}
+
+func (m *mockAdminService) ListVideos(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+	if m.listVideosFn != nil {
+		return m.listVideosFn(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminService) SLOSnapshot(ctx context.Context, window time.Duration) (*usecase.SLOSnapshot, error) {
+	if m.sloSnapshotFn != nil {
+		return m.sloSnapshotFn(ctx, window)
+	}
+	return &usecase.SLOSnapshot{}, nil
+}
+
+func TestAdminHandler_ListTranscodeJobs(t *testing.T) {
+	videoID := uuid.New()
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	job := model.NewTranscodeJob(videoID, 7, 1, start)
+	job.Timings.Download = 2 * time.Second
+	job.Timings.Transcode = 30 * time.Second
+	job.Timings.Variants["720p"] = 18 * time.Second
+	job.Timings.Upload = 4 * time.Second
+	job.Finish(nil, start.Add(36*time.Second))
+
+	tests := []struct {
+		name       string
+		videoID    string
+		query      string
+		serviceErr error
+		wantStatus int
+		wantLimit  int
+	}{
+		{
+			name:       "lists jobs",
+			videoID:    videoID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "passes limit",
+			videoID:    videoID.String(),
+			query:      "?limit=5",
+			wantStatus: http.StatusOK,
+			wantLimit:  5,
+		},
+		{
+			name:       "invalid limit",
+			videoID:    videoID.String(),
+			query:      "?limit=abc",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid video ID",
+			videoID:    "not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "video not found",
+			videoID:    videoID.String(),
+			serviceErr: repository.ErrVideoNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLimit := 0
+			svc := &mockAdminService{
+				listTranscodeJobsFn: func(ctx context.Context, id uuid.UUID, limit int) ([]*model.TranscodeJob, error) {
+					gotLimit = limit
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return []*model.TranscodeJob{job}, nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			r := chi.NewRouter()
+			r.Get("/v1/admin/videos/{id}/transcode-jobs", h.ListTranscodeJobs)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/videos/"+tt.videoID+"/transcode-jobs"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if gotLimit != tt.wantLimit {
+				t.Errorf("limit: got %d, expected %d", gotLimit, tt.wantLimit)
+			}
+
+			var resp TranscodeJobsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Items) != 1 {
+				t.Fatalf("expected 1 item, got %d", len(resp.Items))
+			}
+			got := resp.Items[0]
+			if got.Status != "SUCCEEDED" || got.Attempt != 1 || got.OutputVersion != 7 {
+				t.Errorf("unexpected job: %+v", got)
+			}
+			if got.Timings.DownloadMs != 2000 || got.Timings.VariantsMs["720p"] != 18000 || got.Timings.TotalMs != 36000 {
+				t.Errorf("unexpected timings: %+v", got.Timings)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_TranscodeJobLog(t *testing.T) {
+	videoID := uuid.New()
+	jobID := uuid.New()
+	const log = "==> hls: ffmpeg -i input.mp4\nConversion failed!\n<== hls: exit status 1\n\n"
+
+	tests := []struct {
+		name       string
+		videoID    string
+		jobID      string
+		serviceErr error
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "serves log",
+			videoID:    videoID.String(),
+			jobID:      jobID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid video ID",
+			videoID:    "not-a-uuid",
+			jobID:      jobID.String(),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_video_id",
+		},
+		{
+			name:       "invalid job ID",
+			videoID:    videoID.String(),
+			jobID:      "not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_job_id",
+		},
+		{
+			name:       "video not found",
+			videoID:    videoID.String(),
+			jobID:      jobID.String(),
+			serviceErr: repository.ErrVideoNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "video_not_found",
+		},
+		{
+			name:       "no log uploaded",
+			videoID:    videoID.String(),
+			jobID:      jobID.String(),
+			serviceErr: usecase.ErrTranscodeLogNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "transcode_log_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAdminService{
+				transcodeJobLogFn: func(ctx context.Context, vid, jid uuid.UUID) (io.ReadCloser, error) {
+					if vid != videoID || jid != jobID {
+						t.Errorf("got video %s job %s, expected %s %s", vid, jid, videoID, jobID)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return io.NopCloser(strings.NewReader(log)), nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			r := chi.NewRouter()
+			r.Get("/v1/admin/videos/{id}/transcode-jobs/{jobID}/log", h.TranscodeJobLog)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/videos/"+tt.videoID+"/transcode-jobs/"+tt.jobID+"/log", nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				var resp ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("error code: got %q, expected %q", resp.Error, tt.wantCode)
+				}
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+				t.Errorf("Content-Type: got %q", got)
+			}
+			if rec.Body.String() != log {
+				t.Errorf("body: got %q, expected %q", rec.Body.String(), log)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ListVideos(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		query      string
+		serviceErr error
+		wantStatus int
+		wantFilter repository.VideoFilter
+	}{
+		{
+			name:       "no filters",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "all filters",
+			query:      "?user_id=" + userID.String() + "&status=processing&created_after=2026-01-01T00:00:00Z&created_before=2026-02-01T09:00:00%2B09:00&title_prefix=Demo&limit=25",
+			wantStatus: http.StatusOK,
+			wantFilter: repository.VideoFilter{
+				UserID:        userID,
+				Status:        model.StatusProcessing,
+				CreatedAfter:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+				TitlePrefix:   "Demo",
+				Limit:         25,
+			},
+		},
+		{
+			name:       "invalid created_after",
+			query:      "?created_after=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid created_before",
+			query:      "?created_before=2026-01-01",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid user ID",
+			query:      "?user_id=abc",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			query:      "?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid status",
+			query:      "?status=stuck",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "inverted range",
+			query:      "?created_after=2026-02-01T00:00:00Z&created_before=2026-01-01T00:00:00Z",
+			serviceErr: usecase.ErrInvalidDateRange,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got repository.VideoFilter
+			svc := &mockAdminService{
+				listVideosFn: func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+					got = filter
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					video, err := model.NewVideo(userID, "Demo reel")
+					if err != nil {
+						return nil, err
+					}
+					return []*model.Video{video}, nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/videos"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.ListVideos(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got.UserID != tt.wantFilter.UserID || got.Status != tt.wantFilter.Status || got.TitlePrefix != tt.wantFilter.TitlePrefix || got.Limit != tt.wantFilter.Limit ||
+				!got.CreatedAfter.Equal(tt.wantFilter.CreatedAfter) || !got.CreatedBefore.Equal(tt.wantFilter.CreatedBefore) {
+				t.Errorf("filter: got %+v, expected %+v", got, tt.wantFilter)
+			}
+
+			var resp AdminVideosResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Items) != 1 || resp.Items[0].Title != "Demo reel" {
+				t.Errorf("unexpected items: %+v", resp.Items)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ListRenditions(t *testing.T) {
+	videoID := uuid.New()
+	rendition := model.NewRendition(videoID, 7, "720p")
+	rendition.Width, rendition.Height, rendition.Bitrate = 1280, 720, 2500000
+	rendition.Codec, rendition.SegmentCount, rendition.Bytes = "libx264", 12, 4096
+	rendition.LastViewedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		videoID    string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "lists renditions", videoID: videoID.String(), wantStatus: http.StatusOK},
+		{name: "invalid video ID", videoID: "not-a-uuid", wantStatus: http.StatusBadRequest},
+		{name: "video not found", videoID: videoID.String(), serviceErr: repository.ErrVideoNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAdminService{
+				listRenditionsFn: func(ctx context.Context, id uuid.UUID) ([]*model.Rendition, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return []*model.Rendition{rendition}, nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			r := chi.NewRouter()
+			r.Get("/v1/admin/videos/{id}/renditions", h.ListRenditions)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/videos/"+tt.videoID+"/renditions", nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp RenditionsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Items) != 1 {
+				t.Fatalf("expected 1 item, got %d", len(resp.Items))
+			}
+			got := resp.Items[0]
+			if got.Name != "720p" || got.OutputVersion != 7 || got.Width != 1280 || got.SegmentCount != 12 || got.Bytes != 4096 {
+				t.Errorf("unexpected rendition: %+v", got)
+			}
+			if got.LastViewedAt != "2026-03-01T12:00:00Z" || got.PrunedAt != "" {
+				t.Errorf("unexpected view times: %+v", got)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_VerifyOutput(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name       string
+		videoID    string
+		failures   []usecase.OutputChecksumFailure
+		serviceErr error
+		wantStatus int
+		wantOK     bool
+	}{
+		{name: "output intact", videoID: videoID.String(), wantStatus: http.StatusOK, wantOK: true},
+		{
+			name:       "reports mismatches",
+			videoID:    videoID.String(),
+			failures:   []usecase.OutputChecksumFailure{{Key: "hls/x/v7/720p/segment_001.ts", Reason: usecase.ChecksumFailureChecksum}},
+			wantStatus: http.StatusOK,
+		},
+		{name: "invalid video ID", videoID: "not-a-uuid", wantStatus: http.StatusBadRequest},
+		{name: "video not found", videoID: videoID.String(), serviceErr: repository.ErrVideoNotFound, wantStatus: http.StatusNotFound},
+		{name: "video not ready", videoID: videoID.String(), serviceErr: usecase.ErrVideoNotReady, wantStatus: http.StatusConflict},
+		{name: "no manifest", videoID: videoID.String(), serviceErr: usecase.ErrOutputChecksumsNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAdminService{
+				verifyOutputFn: func(ctx context.Context, id uuid.UUID) (*usecase.OutputVerification, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &usecase.OutputVerification{VideoID: id, OutputVersion: 7, Checked: 5, Failures: tt.failures}, nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			r := chi.NewRouter()
+			r.Post("/v1/admin/videos/{id}/verify-output", h.VerifyOutput)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/videos/"+tt.videoID+"/verify-output", nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp OutputVerificationResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.OK != tt.wantOK || resp.Checked != 5 || resp.OutputVersion != 7 {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+			if len(resp.Failures) != len(tt.failures) {
+				t.Fatalf("failures: got %d, expected %d", len(resp.Failures), len(tt.failures))
+			}
+			for i, f := range resp.Failures {
+				if f.Key != tt.failures[i].Key || f.Reason != tt.failures[i].Reason {
+					t.Errorf("failure %d: got %+v, expected %+v", i, f, tt.failures[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAdminHandler_SLOSnapshot(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		serviceErr error
+		wantStatus int
+		wantWindow time.Duration
+	}{
+		{
+			name:       "default window",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "explicit window",
+			query:      "?window=6h",
+			wantStatus: http.StatusOK,
+			wantWindow: 6 * time.Hour,
+		},
+		{
+			name:       "invalid window",
+			query:      "?window=soon",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "negative window",
+			query:      "?window=-1h",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "service error",
+			serviceErr: errors.New("db down"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotWindow time.Duration
+			svc := &mockAdminService{
+				sloSnapshotFn: func(ctx context.Context, window time.Duration) (*usecase.SLOSnapshot, error) {
+					gotWindow = window
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &usecase.SLOSnapshot{
+						Window:           time.Hour,
+						GeneratedAt:      time.Now(),
+						TranscodeSuccess: usecase.RatioSLI{Good: 99, Total: 100, Ratio: 0.99, Objective: 0.99, BurnRate: 1},
+						TimeToReady:      usecase.LatencySLI{P95: 5 * time.Minute, Samples: 99, Objective: 10 * time.Minute, Met: true},
+						APIAvailability:  usecase.RatioSLI{Ratio: 1, Objective: 0.999},
+					}, nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/slo"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.SLOSnapshot(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if gotWindow != tt.wantWindow {
+				t.Errorf("window: got %v, expected %v", gotWindow, tt.wantWindow)
+			}
+
+			var resp SLOSnapshotResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.WindowSeconds != 3600 || resp.TranscodeSuccess.Good != 99 || resp.TimeToReady.P95Seconds != 300 || !resp.TimeToReady.Met {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_HotKeys(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantLimit  int
+	}{
+		{name: "default limit", wantStatus: http.StatusOK},
+		{name: "explicit limit", query: "?limit=5", wantStatus: http.StatusOK, wantLimit: 5},
+		{name: "invalid limit", query: "?limit=0", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLimit int
+			svc := &mockAdminService{
+				hotKeysFn: func(ctx context.Context, limit int) (*usecase.HotKeysSnapshot, error) {
+					gotLimit = limit
+					return &usecase.HotKeysSnapshot{
+						Window:      5 * time.Minute,
+						GeneratedAt: time.Now(),
+						Keys:        []usecase.HotKey{{VideoID: videoID, Hits: 120, Class: "hot"}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/cache/hot-keys"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			NewAdminHandler(svc).HotKeys(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if gotLimit != tt.wantLimit {
+				t.Errorf("limit: got %d, expected %d", gotLimit, tt.wantLimit)
+			}
+
+			var resp HotKeysResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			want := []HotKeyResponse{{VideoID: videoID.String(), Hits: 120, Class: "hot"}}
+			if resp.WindowSeconds != 300 || !slices.Equal(resp.Items, want) {
+				t.Errorf("response: got %+v, expected window 300 and items %+v", resp, want)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_QueueStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "snapshot", wantStatus: http.StatusOK},
+		{name: "disabled", serviceErr: usecase.ErrQueueStatsDisabled, wantStatus: http.StatusNotFound, wantCode: "queue_stats_disabled"},
+		{name: "queue missing", serviceErr: fmt.Errorf("queue transcode_tasks stats: %w", repository.ErrQueueNotFound), wantStatus: http.StatusNotFound, wantCode: "queue_not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAdminService{
+				queueStatsFn: func(ctx context.Context) (*usecase.QueuesSnapshot, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &usecase.QueuesSnapshot{
+						GeneratedAt: time.Now(),
+						Queues: []usecase.QueueSnapshot{{
+							Name: "transcode_tasks", Depth: 12, Ready: 10, Unacked: 2, Consumers: 3,
+							Age: usecase.MessageAgeStats{Sampled: 10, P50: 30 * time.Second, P90: time.Minute, P99: 2 * time.Minute, Max: 2 * time.Minute},
+						}},
+						DeadLetter: &usecase.QueueSnapshot{Name: "transcode_tasks_dead", Depth: 4, Ready: 4},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/queues", nil)
+			rec := httptest.NewRecorder()
+			NewAdminHandler(svc).QueueStats(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Error != tt.wantCode {
+					t.Errorf("error: got %+v, expected %s", resp, tt.wantCode)
+				}
+				return
+			}
+
+			var resp QueuesResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Items) != 1 || resp.Items[0].Depth != 12 || resp.Items[0].MessageAge.P90Seconds != 60 {
+				t.Errorf("items: got %+v", resp.Items)
+			}
+			if resp.DeadLetter == nil || resp.DeadLetter.Depth != 4 {
+				t.Errorf("dead letter: got %+v", resp.DeadLetter)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ForceStatus(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name       string
+		videoID    string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantInput  usecase.ForceStatusInput
+	}{
+		{
+			name:       "forces status",
+			videoID:    videoID.String(),
+			body:       `{"status": "uploaded", "reason": "task lost"}`,
+			wantStatus: http.StatusOK,
+			wantInput:  usecase.ForceStatusInput{Status: model.StatusUploaded, Reason: "task lost"},
+		},
+		{name: "invalid video ID", videoID: "not-a-uuid", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", videoID: videoID.String(), body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "reason required", videoID: videoID.String(), body: `{"status": "FAILED"}`, serviceErr: usecase.ErrForceReasonRequired, wantStatus: http.StatusBadRequest},
+		{name: "not forceable", videoID: videoID.String(), body: `{"status": "ARCHIVED", "reason": "x"}`, serviceErr: model.ErrStatusNotForceable, wantStatus: http.StatusConflict},
+		{name: "video not found", videoID: videoID.String(), body: `{"status": "FAILED", "reason": "x"}`, serviceErr: repository.ErrVideoNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got usecase.ForceStatusInput
+			svc := &mockAdminService{
+				forceStatusFn: func(ctx context.Context, id uuid.UUID, input usecase.ForceStatusInput) (*model.Video, error) {
+					got = input
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &model.Video{ID: id, Status: input.Status}, nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			r := chi.NewRouter()
+			r.Post("/v1/admin/videos/{id}/status", h.ForceStatus)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/videos/"+tt.videoID+"/status", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got != tt.wantInput {
+				t.Errorf("input: got %+v, expected %+v", got, tt.wantInput)
+			}
+
+			var resp VideoResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Status != string(model.StatusUploaded) {
+				t.Errorf("video status: got %q, expected %q", resp.Status, model.StatusUploaded)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_RequeueTranscode(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name       string
+		videoID    string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "requeued", videoID: videoID.String(), wantStatus: http.StatusAccepted},
+		{name: "invalid video ID", videoID: "not-a-uuid", wantStatus: http.StatusBadRequest},
+		{name: "not processing", videoID: videoID.String(), serviceErr: usecase.ErrVideoNotProcessing, wantStatus: http.StatusConflict},
+		{name: "video not found", videoID: videoID.String(), serviceErr: repository.ErrVideoNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAdminService{
+				requeueTranscodeFn: func(ctx context.Context, id uuid.UUID) (*repository.TranscodeTask, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &repository.TranscodeTask{VideoID: id, OutputVersion: 42, EnqueuedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}, nil
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			r := chi.NewRouter()
+			r.Post("/v1/admin/videos/{id}/requeue", h.RequeueTranscode)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/videos/"+tt.videoID+"/requeue", nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusAccepted {
+				return
+			}
+
+			var resp RequeueTranscodeResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.VideoID != videoID.String() || resp.OutputVersion != 42 || resp.EnqueuedAt != "2026-03-01T12:00:00Z" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_FlushVideoCache(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name       string
+		videoID    string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "flushed", videoID: videoID.String(), wantStatus: http.StatusNoContent},
+		{name: "invalid video ID", videoID: "not-a-uuid", wantStatus: http.StatusBadRequest},
+		{name: "cache disabled", videoID: videoID.String(), serviceErr: usecase.ErrVideoCacheDisabled, wantStatus: http.StatusNotFound},
+		{name: "cache error", videoID: videoID.String(), serviceErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAdminService{
+				flushVideoCacheFn: func(ctx context.Context, id uuid.UUID) error {
+					return tt.serviceErr
+				},
+			}
+			h := NewAdminHandler(svc)
+
+			r := chi.NewRouter()
+			r.Delete("/v1/admin/videos/{id}/cache", h.FlushVideoCache)
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/admin/videos/"+tt.videoID+"/cache", nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status: got %d, expected %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
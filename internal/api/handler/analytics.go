@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+// AnalyticsExportRequest selects a replay; an empty body runs a regular export.
+type AnalyticsExportRequest struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+type AnalyticsPartitionResponse struct {
+	Stream string `json:"stream"`
+	Hour   string `json:"hour"`
+	Key    string `json:"key"`
+	Events int    `json:"events"`
+}
+
+type AnalyticsExportResponse struct {
+	Partitions []AnalyticsPartitionResponse `json:"partitions"`
+}
+
+// AnalyticsHandler handles analytics export HTTP requests.
+type AnalyticsHandler struct {
+	svc usecase.AnalyticsExportService
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler.
+func NewAnalyticsHandler(svc usecase.AnalyticsExportService) *AnalyticsHandler {
+	return &AnalyticsHandler{svc: svc}
+}
+
+// Export handles POST /v1/admin/analytics/exports
+// Without a body it exports every closed hour since the bookmarks; with "from" and "to"
+// (RFC 3339) it rewrites the hours in that range without moving the bookmarks.
+func (h *AnalyticsHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var req AnalyticsExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	if (req.From == nil) != (req.To == nil) {
+		Error(w, http.StatusBadRequest, "invalid_date_range", "from and to must be given together")
+		return
+	}
+
+	var (
+		result *usecase.AnalyticsExportResult
+		err    error
+	)
+	if req.From != nil {
+		result, err = h.svc.Replay(r.Context(), *req.From, *req.To)
+	} else {
+		result, err = h.svc.Export(r.Context())
+	}
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	partitions := make([]AnalyticsPartitionResponse, len(result.Partitions))
+	for i, p := range result.Partitions {
+		partitions[i] = AnalyticsPartitionResponse{
+			Stream: string(p.Stream),
+			Hour:   p.Hour.Format("2006-01-02T15:04:05Z07:00"),
+			Key:    p.Key,
+			Events: p.Events,
+		}
+	}
+
+	JSON(w, http.StatusOK, AnalyticsExportResponse{Partitions: partitions})
+}
+
+func (h *AnalyticsHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidDateRange):
+		Error(w, http.StatusBadRequest, "invalid_date_range", "from must be before to")
+	case errors.Is(err, usecase.ErrExportRangeTooLarge):
+		Error(w, http.StatusBadRequest, "range_too_large", "Replay range exceeds the hours allowed per run")
+	default:
+		ServiceError(w, err)
+	}
+}
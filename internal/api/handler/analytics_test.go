@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockAnalyticsExportService is a mock implementation of usecase.AnalyticsExportService.
+type mockAnalyticsExportService struct {
+	exportFn func(ctx context.Context) (*usecase.AnalyticsExportResult, error)
+	replayFn func(ctx context.Context, from, to time.Time) (*usecase.AnalyticsExportResult, error)
+}
+
+func (m *mockAnalyticsExportService) Export(ctx context.Context) (*usecase.AnalyticsExportResult, error) {
+	if m.exportFn != nil {
+		return m.exportFn(ctx)
+	}
+	return &usecase.AnalyticsExportResult{}, nil
+}
+
+func (m *mockAnalyticsExportService) Replay(ctx context.Context, from, to time.Time) (*usecase.AnalyticsExportResult, error) {
+	if m.replayFn != nil {
+		return m.replayFn(ctx, from, to)
+	}
+	return &usecase.AnalyticsExportResult{}, nil
+}
+
+func TestAnalyticsHandler_Export(t *testing.T) {
+	hour := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	partition := usecase.AnalyticsPartition{
+		Stream: model.AnalyticsStreamPlayback,
+		Hour:   hour,
+		Key:    "analytics/playback/dt=2026-03-01/hour=10/events.jsonl",
+		Events: 7,
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		wantReplay     bool
+		wantStatusCode int
+	}{
+		{
+			name:           "export without body",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "replay range",
+			body:           `{"from":"2026-03-01T10:00:00Z","to":"2026-03-01T11:00:00Z"}`,
+			wantReplay:     true,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "only from given",
+			body:           `{"from":"2026-03-01T10:00:00Z"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"from":`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "range too large",
+			body:           `{"from":"2026-01-01T00:00:00Z","to":"2026-03-01T00:00:00Z"}`,
+			serviceErr:     usecase.ErrExportRangeTooLarge,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid range",
+			body:           `{"from":"2026-03-01T11:00:00Z","to":"2026-03-01T10:00:00Z"}`,
+			serviceErr:     usecase.ErrInvalidDateRange,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "service error",
+			serviceErr:     context.DeadlineExceeded,
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replayed bool
+			mock := &mockAnalyticsExportService{
+				exportFn: func(ctx context.Context) (*usecase.AnalyticsExportResult, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &usecase.AnalyticsExportResult{Partitions: []usecase.AnalyticsPartition{partition}}, nil
+				},
+				replayFn: func(ctx context.Context, from, to time.Time) (*usecase.AnalyticsExportResult, error) {
+					replayed = true
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &usecase.AnalyticsExportResult{Partitions: []usecase.AnalyticsPartition{partition}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/analytics/exports", bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+			NewAnalyticsHandler(mock).Export(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			if replayed != tt.wantReplay {
+				t.Errorf("replayed: got %v, expected %v", replayed, tt.wantReplay)
+			}
+
+			var resp AnalyticsExportResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(resp.Partitions) != 1 {
+				t.Fatalf("partitions: got %d, expected 1", len(resp.Partitions))
+			}
+			got := resp.Partitions[0]
+			if got.Stream != "playback" || got.Hour != "2026-03-01T10:00:00Z" || got.Key != partition.Key || got.Events != 7 {
+				t.Errorf("unexpected partition: %+v", got)
+			}
+		})
+	}
+}
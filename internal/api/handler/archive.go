@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Archive states reported in ArchiveResponse.
+const (
+	archiveStateArchiving = "archiving"
+	archiveStateArchived  = "archived"
+	archiveStateRestoring = "restoring"
+)
+
+// Request/Response types
+
+type ArchiveResponse struct {
+	VideoID       string `json:"video_id"`
+	OutputVersion int64  `json:"output_version"`
+	// State is archiving while the output moves to the archive tier, archived once it is
+	// there, and restoring after a restore was requested.
+	State              string `json:"state"`
+	Bytes              int64  `json:"bytes"`
+	ArchivedAt         string `json:"archived_at,omitempty"`
+	RestoreRequestedAt string `json:"restore_requested_at,omitempty"`
+	RestoreETA         string `json:"restore_eta,omitempty"`
+}
+
+// ArchiveHandler handles archive tier HTTP requests.
+type ArchiveHandler struct {
+	svc usecase.ArchiveService
+}
+
+// NewArchiveHandler creates a new ArchiveHandler.
+func NewArchiveHandler(svc usecase.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{svc: svc}
+}
+
+// Archive handles POST /v1/videos/{id}/archive
+// The output moves asynchronously; the response reports the state of the move.
+func (h *ArchiveHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.svc.Archive, http.StatusAccepted)
+}
+
+// Restore handles POST /v1/videos/{id}/restore
+func (h *ArchiveHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.svc.Restore, http.StatusAccepted)
+}
+
+// Get handles GET /v1/videos/{id}/archive
+func (h *ArchiveHandler) Get(w http.ResponseWriter, r *http.Request) {
+	callerID, _ := middleware.GetUserID(r.Context())
+	getArchive := func(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+		return h.svc.GetArchive(ctx, videoID, callerID)
+	}
+	h.handle(w, r, getArchive, http.StatusOK)
+}
+
+func (h *ArchiveHandler) handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	fn func(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error),
+	status int,
+) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	archive, err := fn(r.Context(), videoID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, status, toArchiveResponse(archive))
+}
+
+func toArchiveResponse(archive *model.VideoArchive) ArchiveResponse {
+	resp := ArchiveResponse{
+		VideoID:       archive.VideoID.String(),
+		OutputVersion: archive.OutputVersion,
+		State:         archiveStateArchiving,
+		Bytes:         archive.Bytes,
+	}
+	if archive.IsArchived() {
+		resp.State = archiveStateArchived
+		resp.ArchivedAt = archive.ArchivedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if archive.IsRestoring() {
+		resp.State = archiveStateRestoring
+		resp.RestoreRequestedAt = archive.RestoreRequestedAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.RestoreETA = archive.RestoreETA.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
+
+func (h *ArchiveHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrVideoNotFound):
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, usecase.ErrVideoNotReady):
+		Error(w, http.StatusConflict, "video_not_ready", "Only READY videos can be archived")
+	case errors.Is(err, usecase.ErrExternalOutput):
+		Error(w, http.StatusConflict, "external_output", "Output stored in the tenant's output bucket cannot be archived")
+	case errors.Is(err, usecase.ErrVideoNotArchived):
+		Error(w, http.StatusConflict, "video_not_archived", "Video is not archived")
+	default:
+		ServiceError(w, err)
+	}
+}
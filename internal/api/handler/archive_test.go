@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockArchiveService is a mock implementation of usecase.ArchiveService.
+type mockArchiveService struct {
+	archiveFn    func(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error)
+	restoreFn    func(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error)
+	getArchiveFn func(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoArchive, error)
+}
+
+func (m *mockArchiveService) Archive(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	if m.archiveFn != nil {
+		return m.archiveFn(ctx, videoID)
+	}
+	return &model.VideoArchive{VideoID: videoID}, nil
+}
+
+func (m *mockArchiveService) Restore(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	if m.restoreFn != nil {
+		return m.restoreFn(ctx, videoID)
+	}
+	return &model.VideoArchive{VideoID: videoID}, nil
+}
+
+func (m *mockArchiveService) GetArchive(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoArchive, error) {
+	if m.getArchiveFn != nil {
+		return m.getArchiveFn(ctx, videoID, callerID)
+	}
+	return &model.VideoArchive{VideoID: videoID}, nil
+}
+
+func TestArchiveHandler(t *testing.T) {
+	videoID := uuid.New()
+	archivedAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	eta := archivedAt.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setupMock      func(m *mockArchiveService)
+		wantStatusCode int
+		wantCode       string
+		wantState      string
+	}{
+		{
+			name:           "archive",
+			method:         http.MethodPost,
+			path:           "/v1/videos/" + videoID.String() + "/archive",
+			setupMock:      func(m *mockArchiveService) {},
+			wantStatusCode: http.StatusAccepted,
+			wantState:      "archiving",
+		},
+		{
+			name:   "archive video not ready",
+			method: http.MethodPost,
+			path:   "/v1/videos/" + videoID.String() + "/archive",
+			setupMock: func(m *mockArchiveService) {
+				m.archiveFn = func(ctx context.Context, id uuid.UUID) (*model.VideoArchive, error) {
+					return nil, usecase.ErrVideoNotReady
+				}
+			},
+			wantStatusCode: http.StatusConflict,
+			wantCode:       "video_not_ready",
+		},
+		{
+			name:   "restore",
+			method: http.MethodPost,
+			path:   "/v1/videos/" + videoID.String() + "/restore",
+			setupMock: func(m *mockArchiveService) {
+				m.restoreFn = func(ctx context.Context, id uuid.UUID) (*model.VideoArchive, error) {
+					return &model.VideoArchive{VideoID: id, ArchivedAt: &archivedAt, RestoreRequestedAt: &archivedAt, RestoreETA: &eta}, nil
+				}
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantState:      "restoring",
+		},
+		{
+			name:   "restore video not archived",
+			method: http.MethodPost,
+			path:   "/v1/videos/" + videoID.String() + "/restore",
+			setupMock: func(m *mockArchiveService) {
+				m.restoreFn = func(ctx context.Context, id uuid.UUID) (*model.VideoArchive, error) {
+					return nil, usecase.ErrVideoNotArchived
+				}
+			},
+			wantStatusCode: http.StatusConflict,
+			wantCode:       "video_not_archived",
+		},
+		{
+			name:   "get archived",
+			method: http.MethodGet,
+			path:   "/v1/videos/" + videoID.String() + "/archive",
+			setupMock: func(m *mockArchiveService) {
+				m.getArchiveFn = func(ctx context.Context, id, callerID uuid.UUID) (*model.VideoArchive, error) {
+					return &model.VideoArchive{VideoID: id, Bytes: 4096, ArchivedAt: &archivedAt}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			wantState:      "archived",
+		},
+		{
+			name:   "get video not found",
+			method: http.MethodGet,
+			path:   "/v1/videos/" + videoID.String() + "/archive",
+			setupMock: func(m *mockArchiveService) {
+				m.getArchiveFn = func(ctx context.Context, id, callerID uuid.UUID) (*model.VideoArchive, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "video_not_found",
+		},
+		{
+			name:   "service error",
+			method: http.MethodGet,
+			path:   "/v1/videos/" + videoID.String() + "/archive",
+			setupMock: func(m *mockArchiveService) {
+				m.getArchiveFn = func(ctx context.Context, id, callerID uuid.UUID) (*model.VideoArchive, error) {
+					return nil, errors.New("connection refused")
+				}
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantCode:       "internal_error",
+		},
+		{
+			name:           "invalid video ID",
+			method:         http.MethodPost,
+			path:           "/v1/videos/not-a-uuid/archive",
+			setupMock:      func(m *mockArchiveService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockArchiveService{}
+			tt.setupMock(mock)
+			h := NewArchiveHandler(mock)
+
+			r := chi.NewRouter()
+			r.Post("/v1/videos/{id}/archive", h.Archive)
+			r.Post("/v1/videos/{id}/restore", h.Restore)
+			r.Get("/v1/videos/{id}/archive", h.Get)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, resp.Error)
+				}
+				return
+			}
+
+			var resp ArchiveResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.VideoID != videoID.String() || resp.State != tt.wantState {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+			if tt.wantState == "restoring" && resp.RestoreETA != "2026-04-01T13:00:00Z" {
+				t.Errorf("expected restore_eta 2026-04-01T13:00:00Z, got %q", resp.RestoreETA)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type RegisterDomainRequest struct {
+	Hostname      string `json:"hostname"`
+	CertificateID string `json:"certificate_id,omitempty"`
+}
+
+type SetCertificateRequest struct {
+	CertificateID string `json:"certificate_id"`
+}
+
+type VerificationRecordResponse struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CustomDomainResponse struct {
+	ID                 string                     `json:"id"`
+	TenantID           string                     `json:"tenant_id"`
+	Hostname           string                     `json:"hostname"`
+	CertificateID      string                     `json:"certificate_id,omitempty"`
+	Status             string                     `json:"status"`
+	Servable           bool                       `json:"servable"`
+	VerificationRecord VerificationRecordResponse `json:"verification_record"`
+	VerifiedAt         string                     `json:"verified_at,omitempty"`
+	CreatedAt          string                     `json:"created_at"`
+}
+
+type CustomDomainsResponse struct {
+	Items []CustomDomainResponse `json:"items"`
+}
+
+// CustomDomainHandler handles tenant custom domain HTTP requests.
+type CustomDomainHandler struct {
+	svc usecase.CustomDomainService
+}
+
+// NewCustomDomainHandler creates a new CustomDomainHandler.
+func NewCustomDomainHandler(svc usecase.CustomDomainService) *CustomDomainHandler {
+	return &CustomDomainHandler{svc: svc}
+}
+
+// Register handles POST /v1/tenants/{id}/domains
+func (h *CustomDomainHandler) Register(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseOwnTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	var req RegisterDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	domain, err := h.svc.RegisterDomain(r.Context(), usecase.RegisterDomainInput{
+		TenantID:      tenantID,
+		Hostname:      req.Hostname,
+		CertificateID: req.CertificateID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, toCustomDomainResponse(domain))
+}
+
+// List handles GET /v1/tenants/{id}/domains
+func (h *CustomDomainHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseOwnTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	domains, err := h.svc.ListDomains(r.Context(), tenantID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	resp := CustomDomainsResponse{Items: make([]CustomDomainResponse, 0, len(domains))}
+	for _, domain := range domains {
+		resp.Items = append(resp.Items, toCustomDomainResponse(domain))
+	}
+
+	JSON(w, http.StatusOK, resp)
+}
+
+// Verify handles POST /v1/tenants/{id}/domains/{domainID}/verify
+func (h *CustomDomainHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	tenantID, domainID, ok := parseDomainPath(w, r)
+	if !ok {
+		return
+	}
+
+	domain, err := h.svc.VerifyDomain(r.Context(), tenantID, domainID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toCustomDomainResponse(domain))
+}
+
+// SetCertificate handles PUT /v1/tenants/{id}/domains/{domainID}/certificate
+func (h *CustomDomainHandler) SetCertificate(w http.ResponseWriter, r *http.Request) {
+	tenantID, domainID, ok := parseDomainPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req SetCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	domain, err := h.svc.SetCertificate(r.Context(), tenantID, domainID, req.CertificateID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toCustomDomainResponse(domain))
+}
+
+// Delete handles DELETE /v1/tenants/{id}/domains/{domainID}
+func (h *CustomDomainHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenantID, domainID, ok := parseDomainPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteDomain(r.Context(), tenantID, domainID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseDomainPath parses the tenant and domain IDs and checks that the caller is the
+// tenant, writing a 400, 401 or 403 response on failure.
+func parseDomainPath(w http.ResponseWriter, r *http.Request) (tenantID, domainID uuid.UUID, ok bool) {
+	tenantID, ok = parseOwnTenantID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	domainID, err := uuid.Parse(chi.URLParam(r, "domainID"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_domain_id", "Domain ID must be a valid UUID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, domainID, true
+}
+
+func (h *CustomDomainHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrCustomDomainNotFound):
+		Error(w, http.StatusNotFound, "domain_not_found", "Custom domain not found")
+	case errors.Is(err, repository.ErrDuplicateCustomDomain):
+		Error(w, http.StatusConflict, "domain_taken", "Hostname is already registered or verified by another tenant")
+	case errors.Is(err, model.ErrInvalidTenantID):
+		Error(w, http.StatusBadRequest, "invalid_tenant_id", "Tenant ID cannot be empty")
+	case errors.Is(err, model.ErrInvalidHostname):
+		Error(w, http.StatusBadRequest, "invalid_hostname", "Hostname must be a fully qualified domain name")
+	case errors.Is(err, usecase.ErrDomainVerificationFailed):
+		Error(w, http.StatusUnprocessableEntity, "verification_failed", "Verification TXT record not found; DNS changes may take time to propagate")
+	default:
+		ServiceError(w, err)
+	}
+}
+
+func toCustomDomainResponse(d *model.CustomDomain) CustomDomainResponse {
+	resp := CustomDomainResponse{
+		ID:            d.ID.String(),
+		TenantID:      d.TenantID.String(),
+		Hostname:      d.Hostname,
+		CertificateID: d.CertificateID,
+		Status:        d.Status.String(),
+		Servable:      d.IsServable(),
+		VerificationRecord: VerificationRecordResponse{
+			Type:  "TXT",
+			Name:  d.VerificationRecordName(),
+			Value: d.VerificationToken,
+		},
+		CreatedAt: d.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if d.VerifiedAt != nil {
+		resp.VerifiedAt = d.VerifiedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
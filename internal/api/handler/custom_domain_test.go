@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockCustomDomainService is a mock implementation of usecase.CustomDomainService.
+type mockCustomDomainService struct {
+	registerDomainFn func(ctx context.Context, input usecase.RegisterDomainInput) (*model.CustomDomain, error)
+	listDomainsFn    func(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error)
+	verifyDomainFn   func(ctx context.Context, tenantID, domainID uuid.UUID) (*model.CustomDomain, error)
+	setCertificateFn func(ctx context.Context, tenantID, domainID uuid.UUID, certificateID string) (*model.CustomDomain, error)
+	deleteDomainFn   func(ctx context.Context, tenantID, domainID uuid.UUID) error
+}
+
+func (m *mockCustomDomainService) RegisterDomain(ctx context.Context, input usecase.RegisterDomainInput) (*model.CustomDomain, error) {
+	if m.registerDomainFn != nil {
+		return m.registerDomainFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockCustomDomainService) ListDomains(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error) {
+	if m.listDomainsFn != nil {
+		return m.listDomainsFn(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *mockCustomDomainService) VerifyDomain(ctx context.Context, tenantID, domainID uuid.UUID) (*model.CustomDomain, error) {
+	if m.verifyDomainFn != nil {
+		return m.verifyDomainFn(ctx, tenantID, domainID)
+	}
+	return nil, nil
+}
+
+func (m *mockCustomDomainService) SetCertificate(ctx context.Context, tenantID, domainID uuid.UUID, certificateID string) (*model.CustomDomain, error) {
+	if m.setCertificateFn != nil {
+		return m.setCertificateFn(ctx, tenantID, domainID, certificateID)
+	}
+	return nil, nil
+}
+
+func (m *mockCustomDomainService) DeleteDomain(ctx context.Context, tenantID, domainID uuid.UUID) error {
+	if m.deleteDomainFn != nil {
+		return m.deleteDomainFn(ctx, tenantID, domainID)
+	}
+	return nil
+}
+
+func (m *mockCustomDomainService) PlaybackBaseURL(ctx context.Context, tenantID uuid.UUID) string {
+	return ""
+}
+
+func newCustomDomainRouter(h *CustomDomainHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Post("/v1/tenants/{id}/domains", h.Register)
+	r.Get("/v1/tenants/{id}/domains", h.List)
+	r.Post("/v1/tenants/{id}/domains/{domainID}/verify", h.Verify)
+	r.Put("/v1/tenants/{id}/domains/{domainID}/certificate", h.SetCertificate)
+	r.Delete("/v1/tenants/{id}/domains/{domainID}", h.Delete)
+	return r
+}
+
+func TestCustomDomainHandler_Register(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "registered",
+			tenantID:       tenantID.String(),
+			body:           `{"hostname":"video.example.com"}`,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "invalid tenant ID",
+			tenantID:       "not-a-uuid",
+			body:           `{"hostname":"video.example.com"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid hostname",
+			tenantID:       tenantID.String(),
+			body:           `{"hostname":"localhost"}`,
+			serviceErr:     model.ErrInvalidHostname,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "hostname taken",
+			tenantID:       tenantID.String(),
+			body:           `{"hostname":"video.example.com"}`,
+			serviceErr:     repository.ErrDuplicateCustomDomain,
+			wantStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockCustomDomainService{
+				registerDomainFn: func(ctx context.Context, input usecase.RegisterDomainInput) (*model.CustomDomain, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return model.NewCustomDomain(input.TenantID, input.Hostname, input.CertificateID)
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/tenants/"+tt.tenantID+"/domains", bytes.NewBufferString(tt.body))
+			req.Header.Set(middleware.UserIDHeader, tenantID.String())
+			rec := httptest.NewRecorder()
+			newCustomDomainRouter(NewCustomDomainHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if rec.Code != http.StatusCreated {
+				return
+			}
+
+			var resp CustomDomainResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Status != "PENDING_VERIFICATION" || resp.Servable {
+				t.Errorf("expected pending, unservable domain, got %+v", resp)
+			}
+			if resp.VerificationRecord.Type != "TXT" || resp.VerificationRecord.Name != "_gostream-verification.video.example.com" || resp.VerificationRecord.Value == "" {
+				t.Errorf("unexpected verification record: %+v", resp.VerificationRecord)
+			}
+		})
+	}
+}
+
+func TestCustomDomainHandler_Verify(t *testing.T) {
+	tests := []struct {
+		name           string
+		domainID       string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "verified",
+			domainID:       uuid.New().String(),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid domain ID",
+			domainID:       "not-a-uuid",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "record not published",
+			domainID:       uuid.New().String(),
+			serviceErr:     usecase.ErrDomainVerificationFailed,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "domain not found",
+			domainID:       uuid.New().String(),
+			serviceErr:     repository.ErrCustomDomainNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockCustomDomainService{
+				verifyDomainFn: func(ctx context.Context, tenantID, domainID uuid.UUID) (*model.CustomDomain, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					domain, err := model.NewCustomDomain(tenantID, "video.example.com", "")
+					if err != nil {
+						return nil, err
+					}
+					domain.MarkVerified(time.Now())
+					return domain, nil
+				},
+			}
+
+			tenantID := uuid.New()
+			req := httptest.NewRequest(http.MethodPost, "/v1/tenants/"+tenantID.String()+"/domains/"+tt.domainID+"/verify", nil)
+			req.Header.Set(middleware.UserIDHeader, tenantID.String())
+			rec := httptest.NewRecorder()
+			newCustomDomainRouter(NewCustomDomainHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestCustomDomainHandler_Delete(t *testing.T) {
+	mock := &mockCustomDomainService{}
+
+	tenantID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/v1/tenants/"+tenantID.String()+"/domains/"+uuid.New().String(), nil)
+	req.Header.Set(middleware.UserIDHeader, tenantID.String())
+	rec := httptest.NewRecorder()
+	newCustomDomainRouter(NewCustomDomainHandler(mock)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestCustomDomainHandler_NotOwner(t *testing.T) {
+	tenantID := uuid.New()
+	domainPath := "/domains/" + uuid.New().String()
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/domains"},
+		{http.MethodGet, "/domains"},
+		{http.MethodPost, domainPath + "/verify"},
+		{http.MethodPut, domainPath + "/certificate"},
+		{http.MethodDelete, domainPath},
+	}
+	callers := []struct {
+		name           string
+		userHeader     string
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "anonymous", wantStatusCode: http.StatusUnauthorized, wantCode: "unauthenticated"},
+		{name: "another tenant", userHeader: uuid.New().String(), wantStatusCode: http.StatusForbidden, wantCode: "not_owner"},
+	}
+
+	for _, route := range routes {
+		for _, caller := range callers {
+			t.Run(route.method+" "+route.path+" by "+caller.name, func(t *testing.T) {
+				fail := func(ctx context.Context, tenantID, domainID uuid.UUID) (*model.CustomDomain, error) {
+					t.Error("service called for a caller that is not the tenant")
+					return nil, nil
+				}
+				mock := &mockCustomDomainService{
+					registerDomainFn: func(ctx context.Context, input usecase.RegisterDomainInput) (*model.CustomDomain, error) {
+						return fail(ctx, input.TenantID, uuid.Nil)
+					},
+					verifyDomainFn: fail,
+				}
+
+				req := httptest.NewRequest(route.method, "/v1/tenants/"+tenantID.String()+route.path, bytes.NewBufferString(`{"hostname":"video.example.com","certificate_id":"cert-1"}`))
+				if caller.userHeader != "" {
+					req.Header.Set(middleware.UserIDHeader, caller.userHeader)
+				}
+				rec := httptest.NewRecorder()
+				newCustomDomainRouter(NewCustomDomainHandler(mock)).ServeHTTP(rec, req)
+
+				if rec.Code != caller.wantStatusCode || !strings.Contains(rec.Body.String(), caller.wantCode) {
+					t.Errorf("expected %d %s, got %d: %s", caller.wantStatusCode, caller.wantCode, rec.Code, rec.Body.String())
+				}
+			})
+		}
+	}
+}
@@ -1,15 +1,154 @@
 package handler
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// DefaultReadyCheckTimeout bounds each dependency ping of a readiness check, so a hung
+// dependency fails the probe instead of stalling it.
+const DefaultReadyCheckTimeout = 2 * time.Second
+
+// Statuses reported by the readiness check, for each dependency and overall.
+const (
+	DependencyOK          = "ok"
+	DependencyUnavailable = "unavailable"
+	// ReadinessDegraded is the overall status when only non-critical dependencies are down.
+	ReadinessDegraded = "degraded"
 )
 
 type HealthResponse struct {
 	Status string `json:"status"`
 }
 
+// ReadinessResponse reports the overall status and the status of each dependency.
+// Maintenance is set while a maintenance window is open.
+type ReadinessResponse struct {
+	Status       string                        `json:"status"`
+	Dependencies map[string]DependencyResponse `json:"dependencies"`
+	Maintenance  *MaintenanceResponse          `json:"maintenance,omitempty"`
+}
+
+type DependencyResponse struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// HealthCheck pings one dependency of the service.
+type HealthCheck struct {
+	Name string
+	Ping func(ctx context.Context) error
+	// Critical dependencies fail readiness when unavailable; others only degrade it.
+	Critical bool
+}
+
+// HealthHandler serves liveness and readiness probes.
+type HealthHandler struct {
+	checks      []HealthCheck
+	timeout     time.Duration
+	maintenance usecase.MaintenanceService
+}
+
+// NewHealthHandler creates a new HealthHandler running checks for readiness.
+// A non-positive timeout falls back to DefaultReadyCheckTimeout.
+func NewHealthHandler(timeout time.Duration, checks ...HealthCheck) *HealthHandler {
+	if timeout <= 0 {
+		timeout = DefaultReadyCheckTimeout
+	}
+	return &HealthHandler{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// WithMaintenance makes readiness report open maintenance windows of svc and returns h.
+// Maintenance does not fail readiness: reads are still served and the window ends on its own.
+func (h *HealthHandler) WithMaintenance(svc usecase.MaintenanceService) *HealthHandler {
+	h.maintenance = svc
+	return h
+}
+
+// Health handles GET /health and GET /healthz.
+// It only reports that the process serves requests; dependencies are not checked, so a
+// dependency outage does not get every replica restarted.
 func Health(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, HealthResponse{
 		Status: "ok",
 	})
 }
+
+// Ready handles GET /readyz.
+// All dependencies are pinged concurrently; the response is 503 if a critical one is unavailable.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	resp := ReadinessResponse{
+		Status:       DependencyOK,
+		Dependencies: make(map[string]DependencyResponse, len(h.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dep := h.run(r.Context(), check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Dependencies[check.Name] = dep
+			if dep.Status == DependencyOK {
+				return
+			}
+			if check.Critical {
+				resp.Status = DependencyUnavailable
+			} else if resp.Status == DependencyOK {
+				resp.Status = ReadinessDegraded
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h.maintenance != nil {
+		if window := h.maintenance.Active(r.Context()); window != nil {
+			maintenance := toMaintenanceResponse(window, time.Now())
+			resp.Maintenance = &maintenance
+		}
+	}
+
+	status := http.StatusOK
+	if resp.Status == DependencyUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	JSON(w, status, resp)
+}
+
+// run pings one dependency. Errors are logged rather than returned to the caller, as
+// they may name internal hosts.
+func (h *HealthHandler) run(ctx context.Context, check HealthCheck) DependencyResponse {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Ping(ctx)
+	dep := DependencyResponse{
+		Status:    DependencyOK,
+		Critical:  check.Critical,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		dep.Status = DependencyUnavailable
+		slog.WarnContext(ctx, "readiness check failed",
+			"dependency", check.Name,
+			"error", err,
+		)
+	}
+	return dep
+}
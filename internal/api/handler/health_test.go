@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestHealth(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Health(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name        string
+		checks      []HealthCheck
+		wantStatus  int
+		wantOverall string
+		wantDeps    map[string]string
+	}{
+		{
+			name: "all up",
+			checks: []HealthCheck{
+				{Name: "postgres", Ping: up, Critical: true},
+				{Name: "redis", Ping: up},
+			},
+			wantStatus:  http.StatusOK,
+			wantOverall: DependencyOK,
+			wantDeps:    map[string]string{"postgres": DependencyOK, "redis": DependencyOK},
+		},
+		{
+			name: "non-critical down",
+			checks: []HealthCheck{
+				{Name: "postgres", Ping: up, Critical: true},
+				{Name: "redis", Ping: down},
+			},
+			wantStatus:  http.StatusOK,
+			wantOverall: ReadinessDegraded,
+			wantDeps:    map[string]string{"postgres": DependencyOK, "redis": DependencyUnavailable},
+		},
+		{
+			name: "critical down",
+			checks: []HealthCheck{
+				{Name: "postgres", Ping: down, Critical: true},
+				{Name: "redis", Ping: down},
+			},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantOverall: DependencyUnavailable,
+			wantDeps:    map[string]string{"postgres": DependencyUnavailable, "redis": DependencyUnavailable},
+		},
+		{
+			name: "critical times out",
+			checks: []HealthCheck{
+				{Name: "rabbitmq", Ping: hang, Critical: true},
+			},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantOverall: DependencyUnavailable,
+			wantDeps:    map[string]string{"rabbitmq": DependencyUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(10*time.Millisecond, tt.checks...)
+
+			rec := httptest.NewRecorder()
+			h.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			var resp ReadinessResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Status != tt.wantOverall {
+				t.Errorf("status: got %q, expected %q", resp.Status, tt.wantOverall)
+			}
+			if len(resp.Dependencies) != len(tt.wantDeps) {
+				t.Errorf("dependencies: got %v, expected %v", resp.Dependencies, tt.wantDeps)
+			}
+			for name, want := range tt.wantDeps {
+				if got := resp.Dependencies[name].Status; got != want {
+					t.Errorf("%s: got %q, expected %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Ready_Maintenance(t *testing.T) {
+	window := &model.MaintenanceWindow{StartsAt: time.Now().Add(-time.Minute), Reason: "database upgrade"}
+	h := NewHealthHandler(time.Second).WithMaintenance(&mockMaintenanceService{
+		activeFn: func(ctx context.Context) *model.MaintenanceWindow { return window },
+	})
+
+	rec := httptest.NewRecorder()
+	h.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	// Maintenance is reported but does not take the service out of rotation
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+	var resp ReadinessResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Maintenance == nil || !resp.Maintenance.Active || resp.Maintenance.Reason != window.Reason {
+		t.Errorf("maintenance: got %+v, want the open window", resp.Maintenance)
+	}
+}
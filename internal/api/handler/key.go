@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// KeyHandler serves the decryption keys of encrypted HLS output.
+type KeyHandler struct {
+	svc usecase.KeyService
+}
+
+// NewKeyHandler creates a new KeyHandler.
+func NewKeyHandler(svc usecase.KeyService) *KeyHandler {
+	return &KeyHandler{svc: svc}
+}
+
+// Get handles GET /v1/videos/{id}/key?token=...
+// Players fetch it from the EXT-X-KEY URI of tokenized playlists, which carries the
+// token. The response is the raw 16-byte AES-128 key.
+func (h *KeyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	key, err := h.svc.DecryptionKey(r.Context(), videoID, r.URL.Query().Get("token"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	// Neither the CDN nor the browser may keep the key past the token's lifetime
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(key)
+}
+
+func (h *KeyHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidPlaybackToken):
+		Error(w, http.StatusUnauthorized, "invalid_playback_token", "Playback token is invalid or expired")
+	case errors.Is(err, repository.ErrEncryptionKeyNotFound):
+		Error(w, http.StatusNotFound, "key_not_found", "Video is not encrypted")
+	default:
+		ServiceError(w, err)
+	}
+}
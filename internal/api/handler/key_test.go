@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockKeyService is a mock implementation of usecase.KeyService.
+type mockKeyService struct {
+	decryptionKeyFn func(ctx context.Context, videoID uuid.UUID, token string) ([]byte, error)
+}
+
+func (m *mockKeyService) DecryptionKey(ctx context.Context, videoID uuid.UUID, token string) ([]byte, error) {
+	if m.decryptionKeyFn != nil {
+		return m.decryptionKeyFn(ctx, videoID, token)
+	}
+	return nil, repository.ErrEncryptionKeyNotFound
+}
+
+func TestKeyHandler_Get(t *testing.T) {
+	videoID := uuid.New()
+	key := bytes.Repeat([]byte{0x5a}, 16)
+
+	tests := []struct {
+		name           string
+		path           string
+		keyErr         error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "authorized",
+			path:           "/v1/videos/" + videoID.String() + "/key?token=tok",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid token",
+			path:           "/v1/videos/" + videoID.String() + "/key?token=bad",
+			keyErr:         usecase.ErrInvalidPlaybackToken,
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "invalid_playback_token",
+		},
+		{
+			name:           "not encrypted",
+			path:           "/v1/videos/" + videoID.String() + "/key?token=tok",
+			keyErr:         repository.ErrEncryptionKeyNotFound,
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "key_not_found",
+		},
+		{
+			name:           "service error",
+			path:           "/v1/videos/" + videoID.String() + "/key?token=tok",
+			keyErr:         errors.New("connection refused"),
+			wantStatusCode: http.StatusInternalServerError,
+			wantCode:       "internal_error",
+		},
+		{
+			name:           "invalid video ID",
+			path:           "/v1/videos/not-a-uuid/key?token=tok",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewKeyHandler(&mockKeyService{
+				decryptionKeyFn: func(ctx context.Context, id uuid.UUID, token string) ([]byte, error) {
+					if tt.keyErr != nil {
+						return nil, tt.keyErr
+					}
+					if id != videoID || token != "tok" {
+						t.Errorf("unexpected request for %s with token %q", id, token)
+					}
+					return key, nil
+				},
+			})
+
+			r := chi.NewRouter()
+			r.Get("/v1/videos/{id}/key", h.Get)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, resp.Error)
+				}
+				return
+			}
+
+			if !bytes.Equal(rec.Body.Bytes(), key) {
+				t.Errorf("expected the key, got %x", rec.Body.Bytes())
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "private, no-store" {
+				t.Errorf("expected Cache-Control private, no-store, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+// ScheduleMaintenanceRequest opens a maintenance window; without starts_at it opens now,
+// without ends_at it stays open until it is ended.
+type ScheduleMaintenanceRequest struct {
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+}
+
+type MaintenanceResponse struct {
+	Active   bool   `json:"active"`
+	StartsAt string `json:"starts_at,omitempty"`
+	EndsAt   string `json:"ends_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// MaintenanceHandler handles maintenance window HTTP requests.
+type MaintenanceHandler struct {
+	svc usecase.MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler.
+func NewMaintenanceHandler(svc usecase.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{svc: svc}
+}
+
+// Get handles GET /v1/admin/maintenance
+// It reads the stored window rather than the cached one, so it reflects changes at once.
+func (h *MaintenanceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	window, err := h.svc.Window(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toMaintenanceResponse(window, time.Now()))
+}
+
+// Schedule handles PUT /v1/admin/maintenance
+func (h *MaintenanceHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	var req ScheduleMaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	window := model.MaintenanceWindow{Reason: req.Reason}
+	if req.StartsAt != nil {
+		window.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		window.EndsAt = *req.EndsAt
+	}
+
+	scheduled, err := h.svc.Schedule(r.Context(), window)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toMaintenanceResponse(scheduled, time.Now()))
+}
+
+// End handles DELETE /v1/admin/maintenance
+func (h *MaintenanceHandler) End(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.End(r.Context()); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MaintenanceHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidMaintenanceWindow):
+		Error(w, http.StatusBadRequest, "invalid_maintenance_window", "ends_at must be after starts_at and in the future")
+	default:
+		ServiceError(w, err)
+	}
+}
+
+// toMaintenanceResponse describes window as of now; a nil window is reported as inactive.
+func toMaintenanceResponse(window *model.MaintenanceWindow, now time.Time) MaintenanceResponse {
+	if window == nil {
+		return MaintenanceResponse{}
+	}
+
+	resp := MaintenanceResponse{
+		Active:   window.IsActive(now),
+		StartsAt: window.StartsAt.Format("2006-01-02T15:04:05Z07:00"),
+		Reason:   window.Reason,
+	}
+	if !window.EndsAt.IsZero() {
+		resp.EndsAt = window.EndsAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockMaintenanceService is a mock implementation of usecase.MaintenanceService.
+type mockMaintenanceService struct {
+	scheduleFn func(ctx context.Context, window model.MaintenanceWindow) (*model.MaintenanceWindow, error)
+	endFn      func(ctx context.Context) error
+	windowFn   func(ctx context.Context) (*model.MaintenanceWindow, error)
+	activeFn   func(ctx context.Context) *model.MaintenanceWindow
+}
+
+func (m *mockMaintenanceService) Schedule(ctx context.Context, window model.MaintenanceWindow) (*model.MaintenanceWindow, error) {
+	if m.scheduleFn != nil {
+		return m.scheduleFn(ctx, window)
+	}
+	return &window, nil
+}
+
+func (m *mockMaintenanceService) End(ctx context.Context) error {
+	if m.endFn != nil {
+		return m.endFn(ctx)
+	}
+	return nil
+}
+
+func (m *mockMaintenanceService) Window(ctx context.Context) (*model.MaintenanceWindow, error) {
+	if m.windowFn != nil {
+		return m.windowFn(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockMaintenanceService) Active(ctx context.Context) *model.MaintenanceWindow {
+	if m.activeFn != nil {
+		return m.activeFn(ctx)
+	}
+	return nil
+}
+
+func (m *mockMaintenanceService) Wait(ctx context.Context) error {
+	return nil
+}
+
+func TestMaintenanceHandler_Get(t *testing.T) {
+	startsAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name           string
+		window         *model.MaintenanceWindow
+		serviceErr     error
+		wantStatusCode int
+		want           MaintenanceResponse
+	}{
+		{
+			name:           "no window",
+			wantStatusCode: http.StatusOK,
+			want:           MaintenanceResponse{},
+		},
+		{
+			name:           "scheduled window",
+			window:         &model.MaintenanceWindow{StartsAt: startsAt, Reason: "database upgrade"},
+			wantStatusCode: http.StatusOK,
+			want:           MaintenanceResponse{StartsAt: startsAt.Format(time.RFC3339), Reason: "database upgrade"},
+		},
+		{
+			name:           "store error",
+			serviceErr:     errors.New("redis down"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewMaintenanceHandler(&mockMaintenanceService{
+				windowFn: func(ctx context.Context) (*model.MaintenanceWindow, error) {
+					return tt.window, tt.serviceErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			h.Get(rec, httptest.NewRequest(http.MethodGet, "/v1/admin/maintenance", nil))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp MaintenanceResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp != tt.want {
+				t.Errorf("got %+v, want %+v", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceHandler_Schedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+		wantActive     bool
+		wantEndsAt     bool
+	}{
+		{
+			name:           "open now without body",
+			wantStatusCode: http.StatusOK,
+			wantActive:     true,
+		},
+		{
+			name:           "open now until end",
+			body:           `{"ends_at":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `","reason":"database upgrade"}`,
+			wantStatusCode: http.StatusOK,
+			wantActive:     true,
+			wantEndsAt:     true,
+		},
+		{
+			name:           "scheduled",
+			body:           `{"starts_at":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"ends_at":`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid window",
+			body:           `{"ends_at":"2020-01-01T00:00:00Z"}`,
+			serviceErr:     usecase.ErrInvalidMaintenanceWindow,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewMaintenanceHandler(&mockMaintenanceService{
+				scheduleFn: func(ctx context.Context, window model.MaintenanceWindow) (*model.MaintenanceWindow, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if window.StartsAt.IsZero() {
+						window.StartsAt = time.Now()
+					}
+					return &window, nil
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			h.Schedule(rec, httptest.NewRequest(http.MethodPut, "/v1/admin/maintenance", bytes.NewBufferString(tt.body)))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp MaintenanceResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Active != tt.wantActive {
+				t.Errorf("active: got %v, want %v", resp.Active, tt.wantActive)
+			}
+			if (resp.EndsAt != "") != tt.wantEndsAt {
+				t.Errorf("ends_at: got %q, want set=%v", resp.EndsAt, tt.wantEndsAt)
+			}
+		})
+	}
+}
+
+func TestMaintenanceHandler_End(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{name: "ended", wantStatusCode: http.StatusNoContent},
+		{name: "store error", serviceErr: errors.New("redis down"), wantStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewMaintenanceHandler(&mockMaintenanceService{
+				endFn: func(ctx context.Context) error { return tt.serviceErr },
+			})
+
+			rec := httptest.NewRecorder()
+			h.End(rec, httptest.NewRequest(http.MethodDelete, "/v1/admin/maintenance", nil))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type ConfigureOutputBucketRequest struct {
+	Endpoint string `json:"endpoint"`
+	Region   string `json:"region,omitempty"`
+	Bucket   string `json:"bucket"`
+	// UseSSL defaults to true; only plain-HTTP test endpoints need to turn it off.
+	UseSSL          *bool  `json:"use_ssl,omitempty"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	PublicBaseURL   string `json:"public_base_url"`
+}
+
+// OutputBucketResponse never includes the secret access key.
+type OutputBucketResponse struct {
+	TenantID      string `json:"tenant_id"`
+	Endpoint      string `json:"endpoint"`
+	Region        string `json:"region,omitempty"`
+	Bucket        string `json:"bucket"`
+	UseSSL        bool   `json:"use_ssl"`
+	AccessKeyID   string `json:"access_key_id"`
+	PublicBaseURL string `json:"public_base_url"`
+	Healthy       bool   `json:"healthy"`
+	CheckedAt     string `json:"checked_at,omitempty"`
+	CheckError    string `json:"check_error,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// OutputBucketHandler handles tenant output bucket HTTP requests.
+type OutputBucketHandler struct {
+	svc usecase.OutputBucketService
+}
+
+// NewOutputBucketHandler creates a new OutputBucketHandler.
+// The svc parameter is optional - pass nil when no secrets key is configured.
+func NewOutputBucketHandler(svc usecase.OutputBucketService) *OutputBucketHandler {
+	return &OutputBucketHandler{svc: svc}
+}
+
+// Configure handles PUT /v1/tenants/{id}/output-bucket
+func (h *OutputBucketHandler) Configure(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseOwnTenantID(w, r)
+	if !ok || !h.enabled(w) {
+		return
+	}
+
+	var req ConfigureOutputBucketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	useSSL := true
+	if req.UseSSL != nil {
+		useSSL = *req.UseSSL
+	}
+	bucket, err := h.svc.Configure(r.Context(), usecase.ConfigureOutputBucketInput{
+		TenantID:        tenantID,
+		Endpoint:        req.Endpoint,
+		Region:          req.Region,
+		Bucket:          req.Bucket,
+		UseSSL:          useSSL,
+		AccessKeyID:     req.AccessKeyID,
+		SecretAccessKey: req.SecretAccessKey,
+		PublicBaseURL:   req.PublicBaseURL,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toOutputBucketResponse(bucket))
+}
+
+// Get handles GET /v1/tenants/{id}/output-bucket
+func (h *OutputBucketHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseOwnTenantID(w, r)
+	if !ok || !h.enabled(w) {
+		return
+	}
+
+	bucket, err := h.svc.Get(r.Context(), tenantID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toOutputBucketResponse(bucket))
+}
+
+// Check handles POST /v1/tenants/{id}/output-bucket/check
+func (h *OutputBucketHandler) Check(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseOwnTenantID(w, r)
+	if !ok || !h.enabled(w) {
+		return
+	}
+
+	bucket, err := h.svc.Check(r.Context(), tenantID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toOutputBucketResponse(bucket))
+}
+
+// Delete handles DELETE /v1/tenants/{id}/output-bucket
+func (h *OutputBucketHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := parseOwnTenantID(w, r)
+	if !ok || !h.enabled(w) {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), tenantID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// enabled writes a 404 response when tenant output buckets are not configured.
+func (h *OutputBucketHandler) enabled(w http.ResponseWriter) bool {
+	if h.svc == nil {
+		Error(w, http.StatusNotFound, "output_buckets_disabled", "Tenant output buckets are not enabled")
+		return false
+	}
+	return true
+}
+
+// parseOwnTenantID parses the tenant ID and checks that the caller is that tenant,
+// writing a 400, 401 or 403 response on failure. A tenant is the user owning the videos,
+// so only the user can manage its own settings.
+func parseOwnTenantID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	tenantID, ok := parseTenantID(w, r)
+	if !ok {
+		return uuid.Nil, false
+	}
+	caller, ok := callerID(w, r)
+	if !ok {
+		return uuid.Nil, false
+	}
+	if caller != tenantID {
+		Error(w, http.StatusForbidden, "not_owner", "Users can only manage their own tenant settings")
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+// parseTenantID parses the tenant ID, writing a 400 response on failure.
+func parseTenantID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_tenant_id", "Tenant ID must be a valid UUID")
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+func (h *OutputBucketHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrOutputBucketNotFound):
+		Error(w, http.StatusNotFound, "output_bucket_not_found", "Tenant has no output bucket")
+	case errors.Is(err, model.ErrInvalidTenantID):
+		Error(w, http.StatusBadRequest, "invalid_tenant_id", "Tenant ID cannot be empty")
+	case errors.Is(err, model.ErrInvalidBucketEndpoint):
+		Error(w, http.StatusBadRequest, "invalid_endpoint", "Endpoint must be a fully qualified host name with an optional port")
+	case errors.Is(err, model.ErrInvalidBucketName):
+		Error(w, http.StatusBadRequest, "invalid_bucket", "Bucket must be a valid S3 bucket name")
+	case errors.Is(err, model.ErrMissingBucketAccessKey):
+		Error(w, http.StatusBadRequest, "missing_access_key_id", "Access key ID is required")
+	case errors.Is(err, usecase.ErrMissingBucketSecret):
+		Error(w, http.StatusBadRequest, "missing_secret_access_key", "Secret access key is required")
+	case errors.Is(err, model.ErrInvalidPublicBaseURL):
+		Error(w, http.StatusBadRequest, "invalid_public_base_url", "Public base URL must be an absolute http or https URL without query or fragment")
+	case errors.Is(err, usecase.ErrOutputBucketCheckFailed):
+		// The detail is the storage error for the tenant's own bucket, e.g. "Access Denied"
+		Error(w, http.StatusUnprocessableEntity, "bucket_check_failed", err.Error())
+	default:
+		ServiceError(w, err)
+	}
+}
+
+func toOutputBucketResponse(b *model.OutputBucket) OutputBucketResponse {
+	resp := OutputBucketResponse{
+		TenantID:      b.TenantID.String(),
+		Endpoint:      b.Endpoint,
+		Region:        b.Region,
+		Bucket:        b.Bucket,
+		UseSSL:        b.UseSSL,
+		AccessKeyID:   b.AccessKeyID,
+		PublicBaseURL: b.PublicBaseURL,
+		Healthy:       b.IsHealthy(),
+		CheckError:    b.CheckError,
+		CreatedAt:     b.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     b.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if !b.CheckedAt.IsZero() {
+		resp.CheckedAt = b.CheckedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
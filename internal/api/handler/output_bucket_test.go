@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockOutputBucketService is a mock implementation of usecase.OutputBucketService.
+type mockOutputBucketService struct {
+	configureFn func(ctx context.Context, input usecase.ConfigureOutputBucketInput) (*model.OutputBucket, error)
+	getFn       func(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error)
+	checkFn     func(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error)
+	deleteFn    func(ctx context.Context, tenantID uuid.UUID) error
+}
+
+func (m *mockOutputBucketService) Configure(ctx context.Context, input usecase.ConfigureOutputBucketInput) (*model.OutputBucket, error) {
+	if m.configureFn != nil {
+		return m.configureFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockOutputBucketService) Get(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *mockOutputBucketService) Check(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+	if m.checkFn != nil {
+		return m.checkFn(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *mockOutputBucketService) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, tenantID)
+	}
+	return nil
+}
+
+func (m *mockOutputBucketService) OutputStorage(ctx context.Context, tenantID uuid.UUID) (repository.ObjectStorage, error) {
+	return nil, nil
+}
+
+func (m *mockOutputBucketService) OutputBaseURL(ctx context.Context, tenantID uuid.UUID) string {
+	return ""
+}
+
+func newOutputBucketRouter(h *OutputBucketHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Put("/v1/tenants/{id}/output-bucket", h.Configure)
+	r.Get("/v1/tenants/{id}/output-bucket", h.Get)
+	r.Post("/v1/tenants/{id}/output-bucket/check", h.Check)
+	r.Delete("/v1/tenants/{id}/output-bucket", h.Delete)
+	return r
+}
+
+func TestOutputBucketHandler_Configure(t *testing.T) {
+	tenantID := uuid.New()
+	validBody := `{"endpoint":"s3.eu-west-1.amazonaws.com","bucket":"acme-videos","access_key_id":"AKIAEXAMPLE","secret_access_key":"top-secret","public_base_url":"https://cdn.acme.example"}`
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+		wantUseSSL     bool
+	}{
+		{
+			name:           "configured",
+			tenantID:       tenantID.String(),
+			body:           validBody,
+			wantStatusCode: http.StatusOK,
+			wantUseSSL:     true,
+		},
+		{
+			name:           "plain HTTP endpoint",
+			tenantID:       tenantID.String(),
+			body:           `{"endpoint":"minio.acme.example:9000","bucket":"acme-videos","use_ssl":false,"access_key_id":"acme","secret_access_key":"top-secret","public_base_url":"https://cdn.acme.example"}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid tenant ID",
+			tenantID:       "not-a-uuid",
+			body:           validBody,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			tenantID:       tenantID.String(),
+			body:           `{`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid endpoint",
+			tenantID:       tenantID.String(),
+			body:           validBody,
+			serviceErr:     model.ErrInvalidBucketEndpoint,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "missing secret",
+			tenantID:       tenantID.String(),
+			body:           validBody,
+			serviceErr:     usecase.ErrMissingBucketSecret,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "health check failed",
+			tenantID:       tenantID.String(),
+			body:           validBody,
+			serviceErr:     fmt.Errorf("%w: write probe object: Access Denied", usecase.ErrOutputBucketCheckFailed),
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockOutputBucketService{
+				configureFn: func(ctx context.Context, input usecase.ConfigureOutputBucketInput) (*model.OutputBucket, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if input.SecretAccessKey != "top-secret" {
+						t.Errorf("secret access key = %q, want top-secret", input.SecretAccessKey)
+					}
+					bucket, err := model.NewOutputBucket(input.TenantID, input.Endpoint, input.Region, input.Bucket, input.AccessKeyID, input.PublicBaseURL, input.UseSSL)
+					if err != nil {
+						return nil, err
+					}
+					bucket.SecretRef = "local:k1:sealed"
+					bucket.RecordCheck(time.Now(), nil)
+					return bucket, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/v1/tenants/"+tt.tenantID+"/output-bucket", bytes.NewBufferString(tt.body))
+			req.Header.Set(middleware.UserIDHeader, tenantID.String())
+			rec := httptest.NewRecorder()
+			newOutputBucketRouter(NewOutputBucketHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if rec.Code == http.StatusUnprocessableEntity && !strings.Contains(rec.Body.String(), "Access Denied") {
+				t.Errorf("check failure response lacks the storage error: %s", rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			if body := rec.Body.String(); strings.Contains(body, "top-secret") || strings.Contains(body, "sealed") {
+				t.Errorf("response exposes the secret: %s", body)
+			}
+			var resp OutputBucketResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if !resp.Healthy || resp.UseSSL != tt.wantUseSSL || resp.CheckedAt == "" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestOutputBucketHandler_Check(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		checkErr       error
+		wantStatusCode int
+		wantHealthy    bool
+	}{
+		{
+			name:           "healthy",
+			wantStatusCode: http.StatusOK,
+			wantHealthy:    true,
+		},
+		{
+			name:           "failing check is reported, not an error",
+			checkErr:       fmt.Errorf("write probe object: Access Denied"),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "no bucket",
+			serviceErr:     repository.ErrOutputBucketNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockOutputBucketService{
+				checkFn: func(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					bucket := &model.OutputBucket{TenantID: tenantID}
+					bucket.RecordCheck(time.Now(), tt.checkErr)
+					return bucket, nil
+				},
+			}
+
+			tenantID := uuid.New()
+			req := httptest.NewRequest(http.MethodPost, "/v1/tenants/"+tenantID.String()+"/output-bucket/check", nil)
+			req.Header.Set(middleware.UserIDHeader, tenantID.String())
+			rec := httptest.NewRecorder()
+			newOutputBucketRouter(NewOutputBucketHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp OutputBucketResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Healthy != tt.wantHealthy || (tt.checkErr != nil && resp.CheckError == "") {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestOutputBucketHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "deleted",
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "no bucket",
+			serviceErr:     repository.ErrOutputBucketNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockOutputBucketService{
+				deleteFn: func(ctx context.Context, tenantID uuid.UUID) error {
+					return tt.serviceErr
+				},
+			}
+
+			tenantID := uuid.New()
+			req := httptest.NewRequest(http.MethodDelete, "/v1/tenants/"+tenantID.String()+"/output-bucket", nil)
+			req.Header.Set(middleware.UserIDHeader, tenantID.String())
+			rec := httptest.NewRecorder()
+			newOutputBucketRouter(NewOutputBucketHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestOutputBucketHandler_Disabled(t *testing.T) {
+	tenantID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/"+tenantID.String()+"/output-bucket", nil)
+	req.Header.Set(middleware.UserIDHeader, tenantID.String())
+	rec := httptest.NewRecorder()
+	newOutputBucketRouter(NewOutputBucketHandler(nil)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "output_buckets_disabled") {
+		t.Errorf("expected 404 output_buckets_disabled, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestOutputBucketHandler_NotOwner(t *testing.T) {
+	tenantID := uuid.New()
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPut, "/output-bucket"},
+		{http.MethodGet, "/output-bucket"},
+		{http.MethodPost, "/output-bucket/check"},
+		{http.MethodDelete, "/output-bucket"},
+	}
+	callers := []struct {
+		name           string
+		userHeader     string
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "anonymous", wantStatusCode: http.StatusUnauthorized, wantCode: "unauthenticated"},
+		{name: "another tenant", userHeader: uuid.New().String(), wantStatusCode: http.StatusForbidden, wantCode: "not_owner"},
+	}
+
+	for _, route := range routes {
+		for _, caller := range callers {
+			t.Run(route.method+" "+route.path+" by "+caller.name, func(t *testing.T) {
+				mock := &mockOutputBucketService{
+					configureFn: func(ctx context.Context, input usecase.ConfigureOutputBucketInput) (*model.OutputBucket, error) {
+						t.Error("Configure called for a caller that is not the tenant")
+						return nil, nil
+					},
+					getFn: func(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+						t.Error("Get called for a caller that is not the tenant")
+						return nil, nil
+					},
+					checkFn: func(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+						t.Error("Check called for a caller that is not the tenant")
+						return nil, nil
+					},
+					deleteFn: func(ctx context.Context, tenantID uuid.UUID) error {
+						t.Error("Delete called for a caller that is not the tenant")
+						return nil
+					},
+				}
+
+				req := httptest.NewRequest(route.method, "/v1/tenants/"+tenantID.String()+route.path, bytes.NewBufferString(`{}`))
+				if caller.userHeader != "" {
+					req.Header.Set(middleware.UserIDHeader, caller.userHeader)
+				}
+				rec := httptest.NewRecorder()
+				newOutputBucketRouter(NewOutputBucketHandler(mock)).ServeHTTP(rec, req)
+
+				if rec.Code != caller.wantStatusCode || !strings.Contains(rec.Body.String(), caller.wantCode) {
+					t.Errorf("expected %d %s, got %d: %s", caller.wantStatusCode, caller.wantCode, rec.Code, rec.Body.String())
+				}
+			})
+		}
+	}
+}
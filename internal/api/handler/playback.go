@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type PlaybackTokenResponse struct {
+	Token     string `json:"token"`
+	VideoID   string `json:"video_id"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// PlaybackHandler handles playback token HTTP requests.
+type PlaybackHandler struct {
+	svc       usecase.PlaybackTokenService
+	manifests usecase.ManifestService
+}
+
+// NewPlaybackHandler creates a new PlaybackHandler.
+// The manifests parameter is optional - pass nil when playlists are not served through the API.
+func NewPlaybackHandler(svc usecase.PlaybackTokenService, manifests usecase.ManifestService) *PlaybackHandler {
+	return &PlaybackHandler{
+		svc:       svc,
+		manifests: manifests,
+	}
+}
+
+// IssueToken handles POST /v1/videos/{id}/playback-token
+func (h *PlaybackHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	// The token is issued to the caller, and held to the plan on the caller's record
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := h.svc.IssueToken(r.Context(), usecase.IssuePlaybackTokenInput{
+		VideoID: videoID,
+		UserID:  userID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, PlaybackTokenResponse{
+		Token:     token.Token,
+		VideoID:   token.VideoID.String(),
+		ExpiresAt: token.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// Authorize handles GET /v1/playback/authorize?token=...&video_id=...
+// Intended as an auth subrequest target for the manifest/segment proxy (e.g., nginx auth_request):
+// 204 allows the request, 401 denies it.
+func (h *PlaybackHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(r.URL.Query().Get("video_id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	if _, err := h.svc.ValidateToken(r.Context(), r.URL.Query().Get("token"), videoID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Playlist handles GET /v1/videos/{id}/hls/*?token=...
+// It serves the video's HLS playlists with the token appended to every URI, so segment
+// requests carry it to the segment route's Authorize check and revoking the token stops
+// playback without storing a playlist per viewer.
+func (h *PlaybackHandler) Playlist(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+	if h.manifests == nil {
+		Error(w, http.StatusNotFound, "playlist_not_found", "Playlist not found")
+		return
+	}
+
+	playlist, err := h.manifests.TokenizedPlaylist(r.Context(), videoID, r.URL.Query().Get("token"), chi.URLParam(r, "*"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	// Each response embeds the viewer's token
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(playlist)
+}
+
+// RevokeToken handles DELETE /v1/playback-tokens/{token}
+// Only the user the token was issued to may revoke it.
+func (h *PlaybackHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RevokeToken(r.Context(), userID, chi.URLParam(r, "token")); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeVideoTokens handles DELETE /v1/videos/{id}/playback-tokens
+func (h *PlaybackHandler) RevokeVideoTokens(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	if err := h.svc.RevokeVideoTokens(r.Context(), videoID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOwnUserTokens handles DELETE /v1/users/{id}/playback-tokens
+// Revoking also frees the user's stream sessions, so users may only revoke their own tokens.
+func (h *PlaybackHandler) RevokeOwnUserTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+	caller, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	if caller != userID {
+		Error(w, http.StatusForbidden, "not_owner", "Users can only revoke their own playback tokens")
+		return
+	}
+
+	h.revokeUserTokens(w, r, userID)
+}
+
+// RevokeUserTokens handles DELETE /v1/admin/users/{id}/playback-tokens
+func (h *PlaybackHandler) RevokeUserTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+
+	h.revokeUserTokens(w, r, userID)
+}
+
+func (h *PlaybackHandler) revokeUserTokens(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	if err := h.svc.RevokeUserTokens(r.Context(), userID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PlaybackHandler) handleServiceError(w http.ResponseWriter, err error) {
+	var archived *usecase.VideoArchivedError
+	switch {
+	case errors.As(err, &archived):
+		// Retry-After is whole seconds; round up so clients never retry early
+		retryAfter := max(1, int(math.Ceil(time.Until(archived.RestoreETA).Seconds())))
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		Error(w, http.StatusConflict, "video_archived", "Video is archived and being restored, retry later")
+	case errors.Is(err, repository.ErrVideoNotFound):
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, model.ErrInvalidUserID):
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID cannot be empty")
+	case errors.Is(err, usecase.ErrVideoNotReady):
+		Error(w, http.StatusConflict, "video_not_ready", "Video is not ready for playback")
+	case errors.Is(err, usecase.ErrNotEntitled):
+		Error(w, http.StatusForbidden, "not_entitled", "User is not entitled to stream this video")
+	case errors.Is(err, usecase.ErrStreamLimitExceeded):
+		Error(w, http.StatusTooManyRequests, "stream_limit_exceeded", "Too many concurrent streams for this user")
+	case errors.Is(err, usecase.ErrPlaylistNotFound):
+		Error(w, http.StatusNotFound, "playlist_not_found", "Playlist not found")
+	case errors.Is(err, usecase.ErrExternalOutput):
+		Error(w, http.StatusConflict, "external_output", "Playlists of output in the tenant's output bucket are served by the tenant's CDN")
+	case errors.Is(err, usecase.ErrPlaybackTokenNotFound):
+		Error(w, http.StatusNotFound, "playback_token_not_found", "Playback token not found")
+	case errors.Is(err, usecase.ErrInvalidPlaybackToken):
+		Error(w, http.StatusUnauthorized, "invalid_playback_token", "Playback token is invalid or expired")
+	default:
+		ServiceError(w, err)
+	}
+}
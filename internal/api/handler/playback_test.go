@@ -0,0 +1,475 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Mock PlaybackTokenService
+
+type mockPlaybackTokenService struct {
+	issueTokenFn        func(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error)
+	validateTokenFn     func(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error)
+	revokeTokenFn       func(ctx context.Context, callerID uuid.UUID, token string) error
+	revokeVideoTokensFn func(ctx context.Context, videoID uuid.UUID) error
+	revokeUserTokensFn  func(ctx context.Context, userID uuid.UUID) error
+}
+
+func (m *mockPlaybackTokenService) IssueToken(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+	if m.issueTokenFn != nil {
+		return m.issueTokenFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockPlaybackTokenService) ValidateToken(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error) {
+	if m.validateTokenFn != nil {
+		return m.validateTokenFn(ctx, token, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockPlaybackTokenService) RevokeToken(ctx context.Context, callerID uuid.UUID, token string) error {
+	if m.revokeTokenFn != nil {
+		return m.revokeTokenFn(ctx, callerID, token)
+	}
+	return nil
+}
+
+func (m *mockPlaybackTokenService) RevokeVideoTokens(ctx context.Context, videoID uuid.UUID) error {
+	if m.revokeVideoTokensFn != nil {
+		return m.revokeVideoTokensFn(ctx, videoID)
+	}
+	return nil
+}
+
+func (m *mockPlaybackTokenService) RevokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	if m.revokeUserTokensFn != nil {
+		return m.revokeUserTokensFn(ctx, userID)
+	}
+	return nil
+}
+
+func TestPlaybackHandler_IssueToken(t *testing.T) {
+	caller := uuid.New()
+
+	tests := []struct {
+		name           string
+		videoID        string
+		anonymous      bool
+		setupMock      func(m *mockPlaybackTokenService)
+		wantStatusCode int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:    "successful issue",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockPlaybackTokenService) {
+				m.issueTokenFn = func(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+					if input.UserID != caller {
+						t.Errorf("expected token for caller %s, got %s", caller, input.UserID)
+					}
+					return &model.PlaybackToken{
+						Token:     "opaque",
+						VideoID:   input.VideoID,
+						UserID:    input.UserID,
+						IssuedAt:  time.Now(),
+						ExpiresAt: time.Now().Add(5 * time.Minute),
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusCreated,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp PlaybackTokenResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Token != "opaque" {
+					t.Errorf("expected token opaque, got %s", resp.Token)
+				}
+				if resp.ExpiresAt == "" {
+					t.Error("expected expires_at to be non-empty")
+				}
+			},
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			setupMock:      func(m *mockPlaybackTokenService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "anonymous",
+			videoID:        uuid.New().String(),
+			anonymous:      true,
+			setupMock:      func(m *mockPlaybackTokenService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "video not found",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockPlaybackTokenService) {
+				m.issueTokenFn = func(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:    "video not ready",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockPlaybackTokenService) {
+				m.issueTokenFn = func(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+					return nil, usecase.ErrVideoNotReady
+				}
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:    "stream limit exceeded",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockPlaybackTokenService) {
+				m.issueTokenFn = func(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+					return nil, usecase.ErrStreamLimitExceeded
+				}
+			},
+			wantStatusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:    "not entitled",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockPlaybackTokenService) {
+				m.issueTokenFn = func(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+					return nil, usecase.ErrNotEntitled
+				}
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPlaybackTokenService{}
+			tt.setupMock(mock)
+			h := NewPlaybackHandler(mock, nil)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Post("/v1/videos/{id}/playback-token", h.IssueToken)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+tt.videoID+"/playback-token", nil)
+			if !tt.anonymous {
+				req.Header.Set(middleware.UserIDHeader, caller.String())
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestPlaybackHandler_IssueToken_Archived(t *testing.T) {
+	mock := &mockPlaybackTokenService{
+		issueTokenFn: func(ctx context.Context, input usecase.IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+			return nil, &usecase.VideoArchivedError{RestoreETA: time.Now().Add(90 * time.Second)}
+		},
+	}
+	h := NewPlaybackHandler(mock, nil)
+
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Post("/v1/videos/{id}/playback-token", h.IssueToken)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+uuid.New().String()+"/playback-token", nil)
+	req.Header.Set(middleware.UserIDHeader, uuid.New().String())
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("expected Retry-After 90, got %q", got)
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error != "video_archived" {
+		t.Errorf("expected code video_archived, got %q", resp.Error)
+	}
+}
+
+// Mock ManifestService
+
+type mockManifestService struct {
+	tokenizedPlaylistFn func(ctx context.Context, videoID uuid.UUID, token, name string) ([]byte, error)
+}
+
+func (m *mockManifestService) TokenizedPlaylist(ctx context.Context, videoID uuid.UUID, token, name string) ([]byte, error) {
+	return m.tokenizedPlaylistFn(ctx, videoID, token, name)
+}
+
+func TestPlaybackHandler_Playlist(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		playlistErr    error
+		wantStatusCode int
+		wantName       string
+	}{
+		{
+			name:           "variant playlist",
+			path:           "/v1/videos/" + videoID.String() + "/hls/720p/playlist.m3u8?token=opaque",
+			wantStatusCode: http.StatusOK,
+			wantName:       "720p/playlist.m3u8",
+		},
+		{
+			name:           "invalid token",
+			path:           "/v1/videos/" + videoID.String() + "/hls/master.m3u8?token=stolen",
+			playlistErr:    usecase.ErrInvalidPlaybackToken,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown playlist",
+			path:           "/v1/videos/" + videoID.String() + "/hls/4k/playlist.m3u8?token=opaque",
+			playlistErr:    usecase.ErrPlaylistNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid video ID",
+			path:           "/v1/videos/not-a-uuid/hls/master.m3u8?token=opaque",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName, gotToken string
+			manifests := &mockManifestService{
+				tokenizedPlaylistFn: func(ctx context.Context, id uuid.UUID, token, name string) ([]byte, error) {
+					gotName, gotToken = name, token
+					if tt.playlistErr != nil {
+						return nil, tt.playlistErr
+					}
+					return []byte("#EXTM3U\n"), nil
+				},
+			}
+			h := NewPlaybackHandler(&mockPlaybackTokenService{}, manifests)
+
+			r := chi.NewRouter()
+			r.Get("/v1/videos/{id}/hls/*", h.Playlist)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+			if gotName != tt.wantName || gotToken != "opaque" {
+				t.Errorf("got playlist %q with token %q", gotName, gotToken)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/vnd.apple.mpegurl" {
+				t.Errorf("unexpected Content-Type %q", ct)
+			}
+			if cc := rec.Header().Get("Cache-Control"); cc != "private, no-store" {
+				t.Errorf("unexpected Cache-Control %q", cc)
+			}
+		})
+	}
+}
+
+func TestPlaybackHandler_Authorize(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(m *mockPlaybackTokenService)
+		wantStatusCode int
+	}{
+		{
+			name:  "valid token",
+			query: "?token=opaque&video_id=" + uuid.New().String(),
+			setupMock: func(m *mockPlaybackTokenService) {
+				m.validateTokenFn = func(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error) {
+					return &model.PlaybackToken{Token: token, VideoID: videoID}, nil
+				}
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:  "invalid token",
+			query: "?token=stolen&video_id=" + uuid.New().String(),
+			setupMock: func(m *mockPlaybackTokenService) {
+				m.validateTokenFn = func(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error) {
+					return nil, usecase.ErrInvalidPlaybackToken
+				}
+			},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing video ID",
+			query:          "?token=opaque",
+			setupMock:      func(m *mockPlaybackTokenService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPlaybackTokenService{}
+			tt.setupMock(mock)
+			h := NewPlaybackHandler(mock, nil)
+
+			r := chi.NewRouter()
+			r.Get("/v1/playback/authorize", h.Authorize)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/playback/authorize"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestPlaybackHandler_Revoke(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	var revokedToken string
+	var revokedVideo, revokedUser, tokenCaller uuid.UUID
+
+	mock := &mockPlaybackTokenService{
+		revokeTokenFn: func(ctx context.Context, callerID uuid.UUID, token string) error {
+			revokedToken, tokenCaller = token, callerID
+			return nil
+		},
+		revokeVideoTokensFn: func(ctx context.Context, id uuid.UUID) error {
+			revokedVideo = id
+			return nil
+		},
+		revokeUserTokensFn: func(ctx context.Context, id uuid.UUID) error {
+			revokedUser = id
+			return nil
+		},
+	}
+	h := NewPlaybackHandler(mock, nil)
+
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Delete("/v1/playback-tokens/{token}", h.RevokeToken)
+	r.Delete("/v1/videos/{id}/playback-tokens", h.RevokeVideoTokens)
+	r.Delete("/v1/users/{id}/playback-tokens", h.RevokeOwnUserTokens)
+
+	paths := []string{
+		"/v1/playback-tokens/stolen",
+		"/v1/videos/" + videoID.String() + "/playback-tokens",
+		"/v1/users/" + userID.String() + "/playback-tokens",
+	}
+	for _, path := range paths {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req.Header.Set(middleware.UserIDHeader, userID.String())
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusNoContent, rec.Code)
+		}
+	}
+
+	if revokedToken != "stolen" || tokenCaller != userID {
+		t.Errorf("revoked token: got %q by %v, expected %q by %v", revokedToken, tokenCaller, "stolen", userID)
+	}
+	if revokedVideo != videoID {
+		t.Errorf("revoked video: got %v, expected %v", revokedVideo, videoID)
+	}
+	if revokedUser != userID {
+		t.Errorf("revoked user: got %v, expected %v", revokedUser, userID)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/users/not-a-uuid/playback-tokens", nil)
+	req.Header.Set(middleware.UserIDHeader, userID.String())
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for invalid user ID, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestPlaybackHandler_Revoke_OtherUser(t *testing.T) {
+	userID := uuid.New()
+
+	mock := &mockPlaybackTokenService{
+		revokeTokenFn: func(ctx context.Context, callerID uuid.UUID, token string) error {
+			return usecase.ErrPlaybackTokenNotFound
+		},
+		revokeUserTokensFn: func(ctx context.Context, id uuid.UUID) error {
+			t.Errorf("RevokeUserTokens(%v) called for another user", id)
+			return nil
+		},
+	}
+	h := NewPlaybackHandler(mock, nil)
+
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Delete("/v1/playback-tokens/{token}", h.RevokeToken)
+	r.Delete("/v1/users/{id}/playback-tokens", h.RevokeOwnUserTokens)
+
+	tests := []struct {
+		name       string
+		path       string
+		caller     string
+		wantStatus int
+	}{
+		{name: "other user's tokens", path: "/v1/users/" + userID.String() + "/playback-tokens", caller: uuid.New().String(), wantStatus: http.StatusForbidden},
+		{name: "anonymous user revocation", path: "/v1/users/" + userID.String() + "/playback-tokens", wantStatus: http.StatusUnauthorized},
+		{name: "other user's token", path: "/v1/playback-tokens/stolen", caller: uuid.New().String(), wantStatus: http.StatusNotFound},
+		{name: "anonymous token revocation", path: "/v1/playback-tokens/stolen", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			if tt.caller != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.caller)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+// CreatePlaylistRequest is the body of POST /v1/playlists.
+type CreatePlaylistRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Visibility is one of public, unlisted or private; unlisted when omitted.
+	Visibility string `json:"visibility,omitempty"`
+	// VideoIDs are the initial videos in playback order.
+	VideoIDs []string `json:"video_ids,omitempty"`
+}
+
+// UpdatePlaylistRequest is the body of PATCH /v1/playlists/{id}; omitted fields are left
+// as they are.
+type UpdatePlaylistRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Visibility  *string `json:"visibility"`
+}
+
+// SetPlaylistVideosRequest is the body of PUT /v1/playlists/{id}/videos.
+type SetPlaylistVideosRequest struct {
+	VideoIDs []string `json:"video_ids"`
+}
+
+type PlaylistResponse struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"user_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Visibility  string   `json:"visibility"`
+	VideoIDs    []string `json:"video_ids"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+type PlaylistsResponse struct {
+	Items []PlaylistResponse `json:"items"`
+}
+
+// PlaylistHandler handles playlist HTTP requests.
+type PlaylistHandler struct {
+	svc usecase.PlaylistService
+	// entitlementsEnforced hides playback URLs from other users; see
+	// model.Video.PlaybackURLsVisibleTo.
+	entitlementsEnforced bool
+}
+
+// NewPlaylistHandler creates a new PlaylistHandler. entitlementsEnforced is set when an
+// entitlement provider is configured.
+func NewPlaylistHandler(svc usecase.PlaylistService, entitlementsEnforced bool) *PlaylistHandler {
+	return &PlaylistHandler{svc: svc, entitlementsEnforced: entitlementsEnforced}
+}
+
+// Create handles POST /v1/playlists
+// The playlist is owned by the caller and may only hold the caller's videos.
+func (h *PlaylistHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreatePlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	videoIDs, ok := parseVideoIDs(w, req.VideoIDs)
+	if !ok {
+		return
+	}
+
+	playlist, err := h.svc.CreatePlaylist(r.Context(), usecase.CreatePlaylistInput{
+		UserID:      userID,
+		Title:       req.Title,
+		Description: req.Description,
+		Visibility:  model.Visibility(req.Visibility),
+		VideoIDs:    videoIDs,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, toPlaylistResponse(playlist))
+}
+
+// Get handles GET /v1/playlists/{id}
+func (h *PlaylistHandler) Get(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := playlistIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	caller, _ := middleware.GetUserID(r.Context())
+	playlist, err := h.svc.GetPlaylist(r.Context(), playlistID, caller)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toPlaylistResponse(playlist))
+}
+
+// ListByUser handles GET /v1/users/{id}/playlists
+func (h *PlaylistHandler) ListByUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+
+	caller, _ := middleware.GetUserID(r.Context())
+	playlists, err := h.svc.ListPlaylists(r.Context(), userID, caller)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	items := make([]PlaylistResponse, 0, len(playlists))
+	for _, p := range playlists {
+		items = append(items, toPlaylistResponse(p))
+	}
+	JSON(w, http.StatusOK, PlaylistsResponse{Items: items})
+}
+
+// Update handles PATCH /v1/playlists/{id}
+func (h *PlaylistHandler) Update(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := playlistIDParam(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdatePlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	if req.Title == nil && req.Description == nil && req.Visibility == nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "At least one of title, description or visibility is required")
+		return
+	}
+
+	input := usecase.UpdatePlaylistInput{
+		Title:       req.Title,
+		Description: req.Description,
+	}
+	if req.Visibility != nil {
+		visibility := model.Visibility(*req.Visibility)
+		input.Visibility = &visibility
+	}
+
+	playlist, err := h.svc.UpdatePlaylist(r.Context(), playlistID, userID, input)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toPlaylistResponse(playlist))
+}
+
+// SetVideos handles PUT /v1/playlists/{id}/videos
+// It replaces the playlist's videos, so adding, removing and reordering are one request.
+func (h *PlaylistHandler) SetVideos(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := playlistIDParam(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req SetPlaylistVideosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	if req.VideoIDs == nil {
+		Error(w, http.StatusBadRequest, "invalid_video_ids", "video_ids is required; send an empty list to clear the playlist")
+		return
+	}
+	videoIDs, ok := parseVideoIDs(w, req.VideoIDs)
+	if !ok {
+		return
+	}
+
+	playlist, err := h.svc.SetPlaylistVideos(r.Context(), playlistID, userID, videoIDs)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toPlaylistResponse(playlist))
+}
+
+// Delete handles DELETE /v1/playlists/{id}
+func (h *PlaylistHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := playlistIDParam(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeletePlaylist(r.Context(), playlistID, userID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Videos handles GET /v1/playlists/{id}/videos
+// It returns the playlist's videos in playback order with their HLS URLs.
+func (h *PlaylistHandler) Videos(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := playlistIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	caller, _ := middleware.GetUserID(r.Context())
+	videos, err := h.svc.ListPlaylistVideos(r.Context(), playlistID, caller)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, VideosResponse{Items: toVideoResponses(videos, caller, h.entitlementsEnforced)})
+}
+
+func playlistIDParam(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	playlistID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_playlist_id", "Playlist ID must be a valid UUID")
+		return uuid.Nil, false
+	}
+	return playlistID, true
+}
+
+func parseVideoIDs(w http.ResponseWriter, ids []string) ([]uuid.UUID, bool) {
+	videoIDs := make([]uuid.UUID, 0, len(ids))
+	for _, s := range ids {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_video_ids", "Video IDs must be valid UUIDs")
+			return nil, false
+		}
+		videoIDs = append(videoIDs, id)
+	}
+	return videoIDs, true
+}
+
+func (h *PlaylistHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrPlaylistNotFound):
+		Error(w, http.StatusNotFound, "playlist_not_found", "Playlist not found")
+	case errors.Is(err, usecase.ErrNotPlaylistOwner):
+		Error(w, http.StatusForbidden, "not_owner", "User does not own this playlist")
+	case errors.Is(err, usecase.ErrInvalidPlaylistVideo):
+		Error(w, http.StatusBadRequest, "invalid_video_ids", "Videos must exist and belong to the playlist's owner")
+	case errors.Is(err, model.ErrTooManyPlaylistVideos):
+		Error(w, http.StatusBadRequest, "invalid_video_ids", "A playlist can have at most 200 videos")
+	case errors.Is(err, model.ErrDuplicatePlaylistVideo):
+		Error(w, http.StatusBadRequest, "invalid_video_ids", "A playlist cannot contain a video more than once")
+	case errors.Is(err, model.ErrEmptyTitle):
+		Error(w, http.StatusBadRequest, "invalid_title", "Title cannot be empty")
+	case errors.Is(err, model.ErrTitleTooLong):
+		Error(w, http.StatusBadRequest, "invalid_title", "Title exceeds maximum length")
+	case errors.Is(err, model.ErrDescriptionTooLong):
+		Error(w, http.StatusBadRequest, "invalid_description", "Description exceeds maximum length of 5000 characters")
+	case errors.Is(err, model.ErrInvalidVisibility):
+		Error(w, http.StatusBadRequest, "invalid_visibility", "Visibility must be public, unlisted or private")
+	default:
+		ServiceError(w, err)
+	}
+}
+
+func toPlaylistResponse(p *model.Playlist) PlaylistResponse {
+	videoIDs := make([]string, len(p.VideoIDs))
+	for i, id := range p.VideoIDs {
+		videoIDs[i] = id.String()
+	}
+	return PlaylistResponse{
+		ID:          p.ID.String(),
+		UserID:      p.UserID.String(),
+		Title:       p.Title,
+		Description: p.Description,
+		Visibility:  p.Visibility.String(),
+		VideoIDs:    videoIDs,
+		CreatedAt:   p.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   p.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
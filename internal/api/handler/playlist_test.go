@@ -0,0 +1,455 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockPlaylistService is a mock implementation of usecase.PlaylistService.
+type mockPlaylistService struct {
+	createPlaylistFn     func(ctx context.Context, input usecase.CreatePlaylistInput) (*model.Playlist, error)
+	getPlaylistFn        func(ctx context.Context, playlistID, callerID uuid.UUID) (*model.Playlist, error)
+	listPlaylistsFn      func(ctx context.Context, userID, callerID uuid.UUID) ([]*model.Playlist, error)
+	updatePlaylistFn     func(ctx context.Context, playlistID, callerID uuid.UUID, input usecase.UpdatePlaylistInput) (*model.Playlist, error)
+	setPlaylistVideosFn  func(ctx context.Context, playlistID, callerID uuid.UUID, videoIDs []uuid.UUID) (*model.Playlist, error)
+	deletePlaylistFn     func(ctx context.Context, playlistID, callerID uuid.UUID) error
+	listPlaylistVideosFn func(ctx context.Context, playlistID, callerID uuid.UUID) ([]*model.Video, error)
+}
+
+func (m *mockPlaylistService) CreatePlaylist(ctx context.Context, input usecase.CreatePlaylistInput) (*model.Playlist, error) {
+	if m.createPlaylistFn != nil {
+		return m.createPlaylistFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockPlaylistService) GetPlaylist(ctx context.Context, playlistID, callerID uuid.UUID) (*model.Playlist, error) {
+	if m.getPlaylistFn != nil {
+		return m.getPlaylistFn(ctx, playlistID, callerID)
+	}
+	return nil, repository.ErrPlaylistNotFound
+}
+
+func (m *mockPlaylistService) ListPlaylists(ctx context.Context, userID, callerID uuid.UUID) ([]*model.Playlist, error) {
+	if m.listPlaylistsFn != nil {
+		return m.listPlaylistsFn(ctx, userID, callerID)
+	}
+	return nil, nil
+}
+
+func (m *mockPlaylistService) UpdatePlaylist(ctx context.Context, playlistID, callerID uuid.UUID, input usecase.UpdatePlaylistInput) (*model.Playlist, error) {
+	if m.updatePlaylistFn != nil {
+		return m.updatePlaylistFn(ctx, playlistID, callerID, input)
+	}
+	return nil, repository.ErrPlaylistNotFound
+}
+
+func (m *mockPlaylistService) SetPlaylistVideos(ctx context.Context, playlistID, callerID uuid.UUID, videoIDs []uuid.UUID) (*model.Playlist, error) {
+	if m.setPlaylistVideosFn != nil {
+		return m.setPlaylistVideosFn(ctx, playlistID, callerID, videoIDs)
+	}
+	return nil, repository.ErrPlaylistNotFound
+}
+
+func (m *mockPlaylistService) DeletePlaylist(ctx context.Context, playlistID, callerID uuid.UUID) error {
+	if m.deletePlaylistFn != nil {
+		return m.deletePlaylistFn(ctx, playlistID, callerID)
+	}
+	return repository.ErrPlaylistNotFound
+}
+
+func (m *mockPlaylistService) ListPlaylistVideos(ctx context.Context, playlistID, callerID uuid.UUID) ([]*model.Video, error) {
+	if m.listPlaylistVideosFn != nil {
+		return m.listPlaylistVideosFn(ctx, playlistID, callerID)
+	}
+	return nil, repository.ErrPlaylistNotFound
+}
+
+func newPlaylistRouter(h *PlaylistHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Post("/v1/playlists", h.Create)
+	r.Get("/v1/playlists/{id}", h.Get)
+	r.Patch("/v1/playlists/{id}", h.Update)
+	r.Delete("/v1/playlists/{id}", h.Delete)
+	r.Put("/v1/playlists/{id}/videos", h.SetVideos)
+	r.Get("/v1/playlists/{id}/videos", h.Videos)
+	r.Get("/v1/users/{id}/playlists", h.ListByUser)
+	return r
+}
+
+func TestPlaylistHandler_Create(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "created",
+			userID:         userID.String(),
+			body:           `{"title": "Go course", "visibility": "public", "video_ids": ["` + videoID.String() + `"]}`,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "unauthenticated",
+			body:           `{"title": "Go course"}`,
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "unauthenticated",
+		},
+		{
+			name:           "invalid JSON",
+			userID:         userID.String(),
+			body:           `{`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "malformed video ID",
+			userID:         userID.String(),
+			body:           `{"title": "Go course", "video_ids": ["nope"]}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_ids",
+		},
+		{
+			name:           "video of another user",
+			userID:         userID.String(),
+			body:           `{"title": "Go course", "video_ids": ["` + videoID.String() + `"]}`,
+			serviceErr:     usecase.ErrInvalidPlaylistVideo,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_ids",
+		},
+		{
+			name:           "empty title",
+			userID:         userID.String(),
+			body:           `{}`,
+			serviceErr:     model.ErrEmptyTitle,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPlaylistService{
+				createPlaylistFn: func(ctx context.Context, input usecase.CreatePlaylistInput) (*model.Playlist, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if input.UserID != userID {
+						t.Errorf("UserID = %s, want %s", input.UserID, userID)
+					}
+					now := time.Now()
+					return &model.Playlist{
+						ID: uuid.New(), UserID: input.UserID, Title: input.Title, Visibility: input.Visibility,
+						VideoIDs: input.VideoIDs, CreatedAt: now, UpdatedAt: now,
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/playlists", strings.NewReader(tt.body))
+			if tt.userID != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			newPlaylistRouter(NewPlaylistHandler(mock, false)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if rec.Code == http.StatusCreated {
+				var resp PlaylistResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Visibility != "public" || len(resp.VideoIDs) != 1 || resp.VideoIDs[0] != videoID.String() {
+					t.Errorf("unexpected response: %+v", resp)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylistHandler_Get(t *testing.T) {
+	playlistID := uuid.New()
+
+	tests := []struct {
+		name           string
+		target         string
+		wantStatusCode int
+		wantCode       string
+	}{
+		{"found", "/v1/playlists/" + playlistID.String(), http.StatusOK, ""},
+		{"not found", "/v1/playlists/" + uuid.New().String(), http.StatusNotFound, "playlist_not_found"},
+		{"invalid ID", "/v1/playlists/nope", http.StatusBadRequest, "invalid_playlist_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPlaylistService{
+				getPlaylistFn: func(ctx context.Context, id, callerID uuid.UUID) (*model.Playlist, error) {
+					if id != playlistID {
+						return nil, repository.ErrPlaylistNotFound
+					}
+					return &model.Playlist{ID: id, UserID: uuid.New(), Title: "Go course", Visibility: model.VisibilityPublic, VideoIDs: []uuid.UUID{}}, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			newPlaylistRouter(NewPlaylistHandler(mock, false)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK && !strings.Contains(rec.Body.String(), `"video_ids":[]`) {
+				t.Errorf("expected an empty video_ids list, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPlaylistHandler_Update(t *testing.T) {
+	userID := uuid.New()
+	playlistID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "updated",
+			body:           `{"title": "Go course", "visibility": "private"}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "no fields",
+			body:           `{}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "not owner",
+			body:           `{"title": "Mine now"}`,
+			serviceErr:     usecase.ErrNotPlaylistOwner,
+			wantStatusCode: http.StatusForbidden,
+			wantCode:       "not_owner",
+		},
+		{
+			name:           "invalid visibility",
+			body:           `{"visibility": "friends"}`,
+			serviceErr:     model.ErrInvalidVisibility,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_visibility",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPlaylistService{
+				updatePlaylistFn: func(ctx context.Context, id, callerID uuid.UUID, input usecase.UpdatePlaylistInput) (*model.Playlist, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if callerID != userID || input.Title == nil || *input.Visibility != model.VisibilityPrivate {
+						t.Errorf("unexpected update by %s: %+v", callerID, input)
+					}
+					return &model.Playlist{ID: id, UserID: callerID, Title: *input.Title, Visibility: *input.Visibility}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/playlists/"+playlistID.String(), strings.NewReader(tt.body))
+			req.Header.Set(middleware.UserIDHeader, userID.String())
+			rec := httptest.NewRecorder()
+			newPlaylistRouter(NewPlaylistHandler(mock, false)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPlaylistHandler_SetVideos(t *testing.T) {
+	userID := uuid.New()
+	playlistID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "replaced",
+			body:           `{"video_ids": ["` + second.String() + `", "` + first.String() + `"]}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing list",
+			body:           `{}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_ids",
+		},
+		{
+			name:           "duplicate video",
+			body:           `{"video_ids": ["` + first.String() + `", "` + first.String() + `"]}`,
+			serviceErr:     model.ErrDuplicatePlaylistVideo,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_ids",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPlaylistService{
+				setPlaylistVideosFn: func(ctx context.Context, id, callerID uuid.UUID, videoIDs []uuid.UUID) (*model.Playlist, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &model.Playlist{ID: id, UserID: callerID, Title: "Go course", VideoIDs: videoIDs}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/v1/playlists/"+playlistID.String()+"/videos", strings.NewReader(tt.body))
+			req.Header.Set(middleware.UserIDHeader, userID.String())
+			rec := httptest.NewRecorder()
+			newPlaylistRouter(NewPlaylistHandler(mock, false)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK {
+				var resp PlaylistResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if len(resp.VideoIDs) != 2 || resp.VideoIDs[0] != second.String() {
+					t.Errorf("expected the requested order, got %v", resp.VideoIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylistHandler_Delete(t *testing.T) {
+	userID := uuid.New()
+	playlistID := uuid.New()
+	mock := &mockPlaylistService{
+		deletePlaylistFn: func(ctx context.Context, id, callerID uuid.UUID) error {
+			if callerID != userID {
+				return usecase.ErrNotPlaylistOwner
+			}
+			return nil
+		},
+	}
+	router := newPlaylistRouter(NewPlaylistHandler(mock, false))
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/playlists/"+playlistID.String(), nil)
+	req.Header.Set(middleware.UserIDHeader, userID.String())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("owner: expected status %d, got %d: %s", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/v1/playlists/"+playlistID.String(), nil)
+	req.Header.Set(middleware.UserIDHeader, uuid.New().String())
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("another user: expected status %d, got %d: %s", http.StatusForbidden, rec.Code, rec.Body.String())
+	}
+}
+
+func TestPlaylistHandler_Videos(t *testing.T) {
+	playlistID := uuid.New()
+	videos := []*model.Video{
+		{ID: uuid.New(), Title: "Part 1", Status: model.StatusReady, HLSURL: "https://cdn.example.com/hls/1/master.m3u8"},
+		{ID: uuid.New(), Title: "Part 2", Status: model.StatusReady, HLSURL: "https://cdn.example.com/hls/2/master.m3u8"},
+	}
+	mock := &mockPlaylistService{
+		listPlaylistVideosFn: func(ctx context.Context, id, callerID uuid.UUID) ([]*model.Video, error) {
+			if id != playlistID {
+				return nil, repository.ErrPlaylistNotFound
+			}
+			return videos, nil
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	newPlaylistRouter(NewPlaylistHandler(mock, false)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/playlists/"+playlistID.String()+"/videos", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp VideosResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[0].Title != "Part 1" || resp.Items[1].HLSURL != videos[1].HLSURL {
+		t.Errorf("unexpected items: %+v", resp.Items)
+	}
+}
+
+func TestPlaylistHandler_ListByUser(t *testing.T) {
+	userID := uuid.New()
+	callerID := uuid.New()
+	mock := &mockPlaylistService{
+		listPlaylistsFn: func(ctx context.Context, id, caller uuid.UUID) ([]*model.Playlist, error) {
+			if id != userID || caller != callerID {
+				t.Errorf("ListPlaylists(%s, %s), want (%s, %s)", id, caller, userID, callerID)
+			}
+			return []*model.Playlist{{ID: uuid.New(), UserID: id, Title: "Go course"}}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/"+userID.String()+"/playlists", nil)
+	req.Header.Set(middleware.UserIDHeader, callerID.String())
+	rec := httptest.NewRecorder()
+	newPlaylistRouter(NewPlaylistHandler(mock, false)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp PlaylistsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].Title != "Go course" {
+		t.Errorf("unexpected items: %+v", resp.Items)
+	}
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type SaveProgressRequest struct {
+	PositionSeconds float64 `json:"position_seconds"`
+}
+
+type ProgressResponse struct {
+	VideoID         string  `json:"video_id"`
+	UserID          string  `json:"user_id"`
+	PositionSeconds float64 `json:"position_seconds"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+type HistoryEntryResponse struct {
+	VideoID         string  `json:"video_id"`
+	Title           string  `json:"title"`
+	PositionSeconds float64 `json:"position_seconds"`
+	WatchedAt       string  `json:"watched_at"`
+}
+
+type HistoryResponse struct {
+	Items      []HistoryEntryResponse `json:"items"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+// ProgressHandler handles playback progress HTTP requests.
+type ProgressHandler struct {
+	svc usecase.ProgressService
+}
+
+// NewProgressHandler creates a new ProgressHandler.
+func NewProgressHandler(svc usecase.ProgressService) *ProgressHandler {
+	return &ProgressHandler{svc: svc}
+}
+
+// SaveProgress handles PUT /v1/videos/{id}/progress
+// Players call this periodically as a heartbeat with the current position.
+func (h *ProgressHandler) SaveProgress(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req SaveProgressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	if math.IsNaN(req.PositionSeconds) || math.IsInf(req.PositionSeconds, 0) {
+		Error(w, http.StatusBadRequest, "invalid_position", "Position must be a finite number of seconds")
+		return
+	}
+
+	progress, err := h.svc.SaveProgress(r.Context(), usecase.SaveProgressInput{
+		UserID:   userID,
+		VideoID:  videoID,
+		Position: time.Duration(req.PositionSeconds * float64(time.Second)),
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toProgressResponse(progress))
+}
+
+// GetProgress handles GET /v1/videos/{id}/progress
+func (h *ProgressHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	progress, err := h.svc.GetProgress(r.Context(), userID, videoID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toProgressResponse(progress))
+}
+
+// ListHistory handles GET /v1/me/history?limit=...&cursor=...
+func (h *ProgressHandler) ListHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		Error(w, http.StatusUnauthorized, "unauthenticated", "A valid "+middleware.UserIDHeader+" header is required")
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_limit", "Limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	output, err := h.svc.ListHistory(r.Context(), usecase.ListHistoryInput{
+		UserID: userID,
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	items := make([]HistoryEntryResponse, len(output.Entries))
+	for i, e := range output.Entries {
+		items[i] = HistoryEntryResponse{
+			VideoID:         e.VideoID.String(),
+			Title:           e.Title,
+			PositionSeconds: e.Position.Seconds(),
+			WatchedAt:       e.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	JSON(w, http.StatusOK, HistoryResponse{
+		Items:      items,
+		NextCursor: output.NextCursor,
+	})
+}
+
+// RemoveFromHistory handles DELETE /v1/me/history/{videoID}
+func (h *ProgressHandler) RemoveFromHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		Error(w, http.StatusUnauthorized, "unauthenticated", "A valid "+middleware.UserIDHeader+" header is required")
+		return
+	}
+
+	videoID, err := uuid.Parse(chi.URLParam(r, "videoID"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	if err := h.svc.RemoveFromHistory(r.Context(), userID, videoID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProgressHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrProgressNotFound):
+		Error(w, http.StatusNotFound, "progress_not_found", "No playback progress recorded for this video")
+	case errors.Is(err, repository.ErrVideoNotFound):
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, model.ErrInvalidUserID):
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID cannot be empty")
+	case errors.Is(err, model.ErrInvalidPosition):
+		Error(w, http.StatusBadRequest, "invalid_position", "Position cannot be negative")
+	case errors.Is(err, usecase.ErrInvalidCursor):
+		Error(w, http.StatusBadRequest, "invalid_cursor", "Cursor is malformed")
+	default:
+		ServiceError(w, err)
+	}
+}
+
+func toProgressResponse(p *model.PlaybackProgress) ProgressResponse {
+	return ProgressResponse{
+		VideoID:         p.VideoID.String(),
+		UserID:          p.UserID.String(),
+		PositionSeconds: p.Position.Seconds(),
+		UpdatedAt:       p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
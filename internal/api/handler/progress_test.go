@@ -0,0 +1,395 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Mock ProgressService
+
+type mockProgressService struct {
+	saveProgressFn  func(ctx context.Context, input usecase.SaveProgressInput) (*model.PlaybackProgress, error)
+	getProgressFn   func(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error)
+	flushProgressFn func(ctx context.Context) (int, error)
+	listHistoryFn   func(ctx context.Context, input usecase.ListHistoryInput) (*usecase.ListHistoryOutput, error)
+	removeFn        func(ctx context.Context, userID, videoID uuid.UUID) error
+}
+
+func (m *mockProgressService) SaveProgress(ctx context.Context, input usecase.SaveProgressInput) (*model.PlaybackProgress, error) {
+	if m.saveProgressFn != nil {
+		return m.saveProgressFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockProgressService) GetProgress(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+	if m.getProgressFn != nil {
+		return m.getProgressFn(ctx, userID, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockProgressService) FlushProgress(ctx context.Context) (int, error) {
+	if m.flushProgressFn != nil {
+		return m.flushProgressFn(ctx)
+	}
+	return 0, nil
+}
+
+func (m *mockProgressService) ListHistory(ctx context.Context, input usecase.ListHistoryInput) (*usecase.ListHistoryOutput, error) {
+	if m.listHistoryFn != nil {
+		return m.listHistoryFn(ctx, input)
+	}
+	return &usecase.ListHistoryOutput{}, nil
+}
+
+func (m *mockProgressService) RemoveFromHistory(ctx context.Context, userID, videoID uuid.UUID) error {
+	if m.removeFn != nil {
+		return m.removeFn(ctx, userID, videoID)
+	}
+	return nil
+}
+
+func TestProgressHandler_SaveProgress(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		videoID        string
+		userHeader     string
+		requestBody    interface{}
+		setupMock      func(m *mockProgressService)
+		wantStatusCode int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:        "successful save",
+			videoID:     uuid.New().String(),
+			userHeader:  userID.String(),
+			requestBody: SaveProgressRequest{PositionSeconds: 12.5},
+			setupMock: func(m *mockProgressService) {
+				m.saveProgressFn = func(ctx context.Context, input usecase.SaveProgressInput) (*model.PlaybackProgress, error) {
+					if input.UserID != userID {
+						t.Errorf("expected user %s, got %s", userID, input.UserID)
+					}
+					if input.Position != 12500*time.Millisecond {
+						t.Errorf("expected position 12.5s, got %v", input.Position)
+					}
+					return &model.PlaybackProgress{
+						UserID:    input.UserID,
+						VideoID:   input.VideoID,
+						Position:  input.Position,
+						UpdatedAt: time.Now(),
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp ProgressResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.PositionSeconds != 12.5 {
+					t.Errorf("expected position_seconds 12.5, got %v", resp.PositionSeconds)
+				}
+			},
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			userHeader:     userID.String(),
+			requestBody:    SaveProgressRequest{},
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "anonymous",
+			videoID:        uuid.New().String(),
+			requestBody:    SaveProgressRequest{},
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:        "negative position",
+			videoID:     uuid.New().String(),
+			userHeader:  userID.String(),
+			requestBody: SaveProgressRequest{PositionSeconds: -1},
+			setupMock: func(m *mockProgressService) {
+				m.saveProgressFn = func(ctx context.Context, input usecase.SaveProgressInput) (*model.PlaybackProgress, error) {
+					return nil, model.ErrInvalidPosition
+				}
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProgressService{}
+			tt.setupMock(mock)
+			h := NewProgressHandler(mock)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Put("/v1/videos/{id}/progress", h.SaveProgress)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPut, "/v1/videos/"+tt.videoID+"/progress", bytes.NewReader(body))
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestProgressHandler_GetProgress(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		userHeader     string
+		setupMock      func(m *mockProgressService)
+		wantStatusCode int
+	}{
+		{
+			name:       "progress found",
+			userHeader: userID.String(),
+			setupMock: func(m *mockProgressService) {
+				m.getProgressFn = func(ctx context.Context, caller, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+					if caller != userID {
+						t.Errorf("expected user %s, got %s", userID, caller)
+					}
+					return &model.PlaybackProgress{UserID: caller, VideoID: videoID, Position: time.Minute, UpdatedAt: time.Now()}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "anonymous",
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "progress not found",
+			userHeader: userID.String(),
+			setupMock: func(m *mockProgressService) {
+				m.getProgressFn = func(ctx context.Context, caller, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+					return nil, repository.ErrProgressNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProgressService{}
+			tt.setupMock(mock)
+			h := NewProgressHandler(mock)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Get("/v1/videos/{id}/progress", h.GetProgress)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+uuid.New().String()+"/progress", nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestProgressHandler_ListHistory(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		userHeader     string
+		query          string
+		setupMock      func(m *mockProgressService)
+		wantStatusCode int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:       "first page",
+			userHeader: userID.String(),
+			query:      "?limit=1",
+			setupMock: func(m *mockProgressService) {
+				m.listHistoryFn = func(ctx context.Context, input usecase.ListHistoryInput) (*usecase.ListHistoryOutput, error) {
+					if input.UserID != userID {
+						t.Errorf("expected user %s, got %s", userID, input.UserID)
+					}
+					if input.Limit != 1 {
+						t.Errorf("expected limit 1, got %d", input.Limit)
+					}
+					return &usecase.ListHistoryOutput{
+						Entries: []*model.WatchHistoryEntry{{
+							PlaybackProgress: model.PlaybackProgress{UserID: userID, VideoID: uuid.New(), Position: time.Minute, UpdatedAt: time.Now()},
+							Title:            "Big Buck Bunny",
+						}},
+						NextCursor: "next",
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp HistoryResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if len(resp.Items) != 1 || resp.Items[0].Title != "Big Buck Bunny" {
+					t.Errorf("unexpected items: %+v", resp.Items)
+				}
+				if resp.NextCursor != "next" {
+					t.Errorf("expected next_cursor next, got %q", resp.NextCursor)
+				}
+			},
+		},
+		{
+			name:           "missing user header",
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid limit",
+			userHeader:     userID.String(),
+			query:          "?limit=abc",
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid cursor",
+			userHeader: userID.String(),
+			query:      "?cursor=garbage",
+			setupMock: func(m *mockProgressService) {
+				m.listHistoryFn = func(ctx context.Context, input usecase.ListHistoryInput) (*usecase.ListHistoryOutput, error) {
+					return nil, usecase.ErrInvalidCursor
+				}
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProgressService{}
+			tt.setupMock(mock)
+			h := NewProgressHandler(mock)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Get("/v1/me/history", h.ListHistory)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/me/history"+tt.query, nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestProgressHandler_RemoveFromHistory(t *testing.T) {
+	tests := []struct {
+		name           string
+		userHeader     string
+		videoID        string
+		setupMock      func(m *mockProgressService)
+		wantStatusCode int
+	}{
+		{
+			name:           "successful remove",
+			userHeader:     uuid.New().String(),
+			videoID:        uuid.New().String(),
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:       "not in history",
+			userHeader: uuid.New().String(),
+			videoID:    uuid.New().String(),
+			setupMock: func(m *mockProgressService) {
+				m.removeFn = func(ctx context.Context, userID, videoID uuid.UUID) error {
+					return repository.ErrProgressNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid user header",
+			userHeader:     "not-a-uuid",
+			videoID:        uuid.New().String(),
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid video ID",
+			userHeader:     uuid.New().String(),
+			videoID:        "bad",
+			setupMock:      func(m *mockProgressService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProgressService{}
+			tt.setupMock(mock)
+			h := NewProgressHandler(mock)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Delete("/v1/me/history/{videoID}", h.RemoveFromHistory)
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/me/history/"+tt.videoID, nil)
+			req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
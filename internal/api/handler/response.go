@@ -3,6 +3,8 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 )
 
 func JSON(w http.ResponseWriter, status int, data any) {
@@ -26,3 +28,20 @@ func Error(w http.ResponseWriter, status int, err string, message string) {
 		Message: message,
 	})
 }
+
+// ServiceError writes the response for an error no handler-specific case matched, with
+// the status chosen by its errs code. Errors without a code are internal errors.
+func ServiceError(w http.ResponseWriter, err error) {
+	switch errs.CodeOf(err) {
+	case errs.NotFound:
+		Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errs.Conflict:
+		Error(w, http.StatusConflict, "conflict", "Request conflicts with the current state")
+	case errs.Invalid:
+		Error(w, http.StatusBadRequest, "invalid_request", "Request is invalid")
+	case errs.Transient:
+		Error(w, http.StatusServiceUnavailable, "unavailable", "Service is temporarily unavailable, retry later")
+	default:
+		Error(w, http.StatusInternalServerError, "internal_error", "An unexpected error occurred")
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestServiceError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "not found", err: fmt.Errorf("get: %w", repository.ErrArchiveNotFound), wantStatus: http.StatusNotFound, wantCode: "not_found"},
+		{name: "conflict", err: repository.ErrDuplicateVideo, wantStatus: http.StatusConflict, wantCode: "conflict"},
+		{name: "invalid", err: errs.New(errs.Invalid, "bad range"), wantStatus: http.StatusBadRequest, wantCode: "invalid_request"},
+		{name: "transient", err: errs.Wrap(errs.Transient, errors.New("connection reset")), wantStatus: http.StatusServiceUnavailable, wantCode: "unavailable"},
+		{name: "permanent", err: repository.ErrPermanentTaskFailure, wantStatus: http.StatusInternalServerError, wantCode: "internal_error"},
+		{name: "uncoded", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCode: "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ServiceError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error != tt.wantCode {
+				t.Errorf("expected error code %q, got %q", tt.wantCode, resp.Error)
+			}
+		})
+	}
+}
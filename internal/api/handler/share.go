@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// DefaultShareRedirectTemplate sends share links to the video metadata endpoint.
+const DefaultShareRedirectTemplate = "/v1/videos/{id}"
+
+// ShareHandler resolves short share links.
+type ShareHandler struct {
+	svc              usecase.VideoService
+	redirectTemplate string
+}
+
+// NewShareHandler creates a new ShareHandler.
+// The redirectTemplate may contain {id}, {sortable_id}, {slug} and {title_slug}
+// placeholders; an empty template falls back to DefaultShareRedirectTemplate.
+func NewShareHandler(svc usecase.VideoService, redirectTemplate string) *ShareHandler {
+	if redirectTemplate == "" {
+		redirectTemplate = DefaultShareRedirectTemplate
+	}
+	return &ShareHandler{svc: svc, redirectTemplate: redirectTemplate}
+}
+
+// Redirect handles GET /v1/v/{slug}
+func (h *ShareHandler) Redirect(w http.ResponseWriter, r *http.Request) {
+	video, err := h.svc.ResolveShareSlug(r.Context(), chi.URLParam(r, "slug"))
+	h.redirect(w, r, video, err)
+}
+
+// RedirectTitleSlug handles GET /v1/u/{userID}/v/{slug}
+func (h *ShareHandler) RedirectTitleSlug(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+
+	video, err := h.svc.ResolveTitleSlug(r.Context(), userID, chi.URLParam(r, "slug"))
+	h.redirect(w, r, video, err)
+}
+
+// redirect sends the client to the resolved video, or reports why it could not be resolved.
+func (h *ShareHandler) redirect(w http.ResponseWriter, r *http.Request, video *model.Video, err error) {
+	if err != nil {
+		if errors.Is(err, repository.ErrVideoNotFound) {
+			Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+			return
+		}
+		ServiceError(w, err)
+		return
+	}
+	if callerID, _ := middleware.GetUserID(r.Context()); !video.VisibleTo(callerID) {
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+		return
+	}
+
+	target := strings.NewReplacer(
+		"{id}", video.ID.String(),
+		"{sortable_id}", video.SortableID,
+		"{slug}", video.ShareSlug,
+		"{title_slug}", url.PathEscape(video.TitleSlug),
+	).Replace(h.redirectTemplate)
+
+	http.Redirect(w, r, target, http.StatusFound)
+}
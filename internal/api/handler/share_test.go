@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestShareHandler_Redirect(t *testing.T) {
+	video := &model.Video{
+		ID:         uuid.New(),
+		SortableID: "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		ShareSlug:  "a1b2c3d4",
+	}
+
+	tests := []struct {
+		name           string
+		template       string
+		visibility     model.Visibility
+		serviceErr     error
+		wantStatusCode int
+		wantLocation   string
+	}{
+		{
+			name:           "default template redirects to video metadata",
+			wantStatusCode: http.StatusFound,
+			wantLocation:   "/v1/videos/" + video.ID.String(),
+		},
+		{
+			name:           "custom template",
+			template:       "https://watch.example.com/{sortable_id}?s={slug}",
+			wantStatusCode: http.StatusFound,
+			wantLocation:   "https://watch.example.com/01ARZ3NDEKTSV4RRFFQ69G5FAV?s=a1b2c3d4",
+		},
+		{
+			name:           "unknown slug",
+			serviceErr:     repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "private video",
+			visibility:     model.VisibilityPrivate,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "service error",
+			serviceErr:     errors.New("database unavailable"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				resolveShareSlugFn: func(ctx context.Context, slug string) (*model.Video, error) {
+					if slug != video.ShareSlug {
+						t.Errorf("expected slug %q, got %q", video.ShareSlug, slug)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					resolved := *video
+					resolved.Visibility = tt.visibility
+					return &resolved, nil
+				},
+			}
+			h := NewShareHandler(mock, tt.template)
+
+			r := chi.NewRouter()
+			r.Get("/v1/v/{slug}", h.Redirect)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/v/"+video.ShareSlug, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("expected Location %q, got %q", tt.wantLocation, got)
+			}
+		})
+	}
+}
+
+func TestShareHandler_RedirectTitleSlug(t *testing.T) {
+	userID := uuid.New()
+	video := &model.Video{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ShareSlug: "a1b2c3d4",
+		TitleSlug: "動画-テスト",
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		template       string
+		serviceErr     error
+		wantStatusCode int
+		wantLocation   string
+	}{
+		{
+			name:           "default template redirects to video metadata",
+			userID:         userID.String(),
+			wantStatusCode: http.StatusFound,
+			wantLocation:   "/v1/videos/" + video.ID.String(),
+		},
+		{
+			name:           "title slug placeholder is escaped",
+			userID:         userID.String(),
+			template:       "https://watch.example.com/{title_slug}",
+			wantStatusCode: http.StatusFound,
+			wantLocation:   "https://watch.example.com/%E5%8B%95%E7%94%BB-%E3%83%86%E3%82%B9%E3%83%88",
+		},
+		{
+			name:           "unknown slug",
+			userID:         userID.String(),
+			serviceErr:     repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid user ID",
+			userID:         "not-a-uuid",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				resolveTitleSlugFn: func(ctx context.Context, id uuid.UUID, slug string) (*model.Video, error) {
+					if id != userID || slug != video.TitleSlug {
+						t.Errorf("expected %s/%q, got %s/%q", userID, video.TitleSlug, id, slug)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return video, nil
+				},
+			}
+			h := NewShareHandler(mock, tt.template)
+
+			r := chi.NewRouter()
+			r.Get("/v1/u/{userID}/v/{slug}", h.RedirectTitleSlug)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/u/"+tt.userID+"/v/"+url.PathEscape(video.TitleSlug), nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("expected Location %q, got %q", tt.wantLocation, got)
+			}
+		})
+	}
+}
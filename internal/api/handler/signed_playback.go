@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+type SignedPlaybackResponse struct {
+	URL       string `json:"url"`
+	VideoID   string `json:"video_id"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// SignedPlaybackHandler handles signed playback URL HTTP requests.
+type SignedPlaybackHandler struct {
+	svc usecase.SignedPlaybackService
+}
+
+// NewSignedPlaybackHandler creates a new SignedPlaybackHandler.
+// The svc parameter is optional - pass nil when no signing key is configured.
+func NewSignedPlaybackHandler(svc usecase.SignedPlaybackService) *SignedPlaybackHandler {
+	return &SignedPlaybackHandler{svc: svc}
+}
+
+// Issue handles GET /v1/videos/{id}/playback
+// It returns an expiring signed URL of the video's master playlist for the caller.
+func (h *SignedPlaybackHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		Error(w, http.StatusUnauthorized, "unauthenticated", "A valid "+middleware.UserIDHeader+" header is required")
+		return
+	}
+	if h.svc == nil {
+		Error(w, http.StatusNotFound, "signed_playback_disabled", "Signed playback URLs are not enabled")
+		return
+	}
+
+	playback, err := h.svc.Issue(r.Context(), usecase.IssueSignedPlaybackInput{
+		VideoID: videoID,
+		UserID:  userID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	// The URL grants access by itself
+	w.Header().Set("Cache-Control", "private, no-store")
+	JSON(w, http.StatusOK, SignedPlaybackResponse{
+		URL:       playback.URL,
+		VideoID:   playback.Claims.VideoID.String(),
+		ExpiresAt: playback.Claims.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// Verify handles GET /v1/playback/verify?token=...&key=...
+// Intended as an auth subrequest target for an origin serving signed URLs (e.g., nginx
+// auth_request): 204 allows the request for the object key, 401 denies it.
+func (h *SignedPlaybackHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	if h.svc == nil {
+		Error(w, http.StatusNotFound, "signed_playback_disabled", "Signed playback URLs are not enabled")
+		return
+	}
+
+	if _, err := h.svc.Verify(r.URL.Query().Get("token"), r.URL.Query().Get("key")); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SignedPlaybackHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrVideoNotFound):
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, model.ErrInvalidUserID):
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID cannot be empty")
+	case errors.Is(err, usecase.ErrVideoNotReady):
+		Error(w, http.StatusConflict, "video_not_ready", "Video is not ready for playback")
+	case errors.Is(err, usecase.ErrNotEntitled):
+		Error(w, http.StatusForbidden, "not_entitled", "User is not entitled to stream this video")
+	case errors.Is(err, usecase.ErrInvalidPlaybackToken):
+		Error(w, http.StatusUnauthorized, "invalid_playback_token", "Playback URL is invalid or expired")
+	default:
+		ServiceError(w, err)
+	}
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockSignedPlaybackService is a mock implementation of usecase.SignedPlaybackService.
+type mockSignedPlaybackService struct {
+	issueFn  func(ctx context.Context, input usecase.IssueSignedPlaybackInput) (*usecase.SignedPlayback, error)
+	verifyFn func(token, key string) (*model.SignedPlaybackClaims, error)
+}
+
+func (m *mockSignedPlaybackService) Issue(ctx context.Context, input usecase.IssueSignedPlaybackInput) (*usecase.SignedPlayback, error) {
+	if m.issueFn != nil {
+		return m.issueFn(ctx, input)
+	}
+	return nil, usecase.ErrVideoNotReady
+}
+
+func (m *mockSignedPlaybackService) Verify(token, key string) (*model.SignedPlaybackClaims, error) {
+	if m.verifyFn != nil {
+		return m.verifyFn(token, key)
+	}
+	return nil, usecase.ErrInvalidPlaybackToken
+}
+
+func TestSignedPlaybackHandler_Issue(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		path           string
+		userHeader     string
+		disabled       bool
+		issueErr       error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "issued",
+			path:           "/v1/videos/" + videoID.String() + "/playback",
+			userHeader:     userID.String(),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "unauthenticated",
+			path:           "/v1/videos/" + videoID.String() + "/playback",
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "unauthenticated",
+		},
+		{
+			name:           "not entitled",
+			path:           "/v1/videos/" + videoID.String() + "/playback",
+			userHeader:     userID.String(),
+			issueErr:       usecase.ErrNotEntitled,
+			wantStatusCode: http.StatusForbidden,
+			wantCode:       "not_entitled",
+		},
+		{
+			name:           "not ready",
+			path:           "/v1/videos/" + videoID.String() + "/playback",
+			userHeader:     userID.String(),
+			issueErr:       usecase.ErrVideoNotReady,
+			wantStatusCode: http.StatusConflict,
+			wantCode:       "video_not_ready",
+		},
+		{
+			name:           "not found",
+			path:           "/v1/videos/" + videoID.String() + "/playback",
+			userHeader:     userID.String(),
+			issueErr:       repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "video_not_found",
+		},
+		{
+			name:           "disabled",
+			path:           "/v1/videos/" + videoID.String() + "/playback",
+			userHeader:     userID.String(),
+			disabled:       true,
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "signed_playback_disabled",
+		},
+		{
+			name:           "invalid video ID",
+			path:           "/v1/videos/not-a-uuid/playback",
+			userHeader:     userID.String(),
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var svc usecase.SignedPlaybackService = &mockSignedPlaybackService{
+				issueFn: func(ctx context.Context, input usecase.IssueSignedPlaybackInput) (*usecase.SignedPlayback, error) {
+					if tt.issueErr != nil {
+						return nil, tt.issueErr
+					}
+					if input.VideoID != videoID || input.UserID != userID {
+						t.Errorf("unexpected input %+v", input)
+					}
+					return &usecase.SignedPlayback{
+						URL:    "https://cdn.example.com/s/tok/hls/abc/v1/master.m3u8",
+						Claims: model.SignedPlaybackClaims{VideoID: videoID, UserID: userID, ExpiresAt: expiresAt},
+					}, nil
+				},
+			}
+			if tt.disabled {
+				svc = nil
+			}
+			h := NewSignedPlaybackHandler(svc)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Get("/v1/videos/{id}/playback", h.Issue)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, resp.Error)
+				}
+				return
+			}
+
+			var resp SignedPlaybackResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			want := SignedPlaybackResponse{
+				URL:       "https://cdn.example.com/s/tok/hls/abc/v1/master.m3u8",
+				VideoID:   videoID.String(),
+				ExpiresAt: "2026-01-02T03:04:05Z",
+			}
+			if resp != want {
+				t.Errorf("expected %+v, got %+v", want, resp)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "private, no-store" {
+				t.Errorf("expected Cache-Control private, no-store, got %q", got)
+			}
+		})
+	}
+}
+
+func TestSignedPlaybackHandler_Verify(t *testing.T) {
+	h := NewSignedPlaybackHandler(&mockSignedPlaybackService{
+		verifyFn: func(token, key string) (*model.SignedPlaybackClaims, error) {
+			if token != "tok" || key != "hls/abc/v1/720p/segment_000.ts" {
+				return nil, usecase.ErrInvalidPlaybackToken
+			}
+			return &model.SignedPlaybackClaims{}, nil
+		},
+	})
+
+	tests := []struct {
+		name           string
+		query          string
+		wantStatusCode int
+	}{
+		{name: "allowed", query: "?token=tok&key=hls/abc/v1/720p/segment_000.ts", wantStatusCode: http.StatusNoContent},
+		{name: "denied", query: "?token=tok&key=hls/abc/v2/720p/segment_000.ts", wantStatusCode: http.StatusUnauthorized},
+		{name: "missing token", query: "?key=hls/abc/v1/720p/segment_000.ts", wantStatusCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.Verify(rec, httptest.NewRequest(http.MethodGet, "/v1/playback/verify"+tt.query, nil))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
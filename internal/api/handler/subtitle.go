@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/subtitle"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type SubtitleResponse struct {
+	ID        string `json:"id"`
+	VideoID   string `json:"video_id"`
+	Language  string `json:"language"`
+	Label     string `json:"label"`
+	Format    string `json:"format"`
+	Default   bool   `json:"default"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ListSubtitlesResponse struct {
+	Subtitles []SubtitleResponse `json:"subtitles"`
+}
+
+// SubtitleHandler handles subtitle track HTTP requests.
+type SubtitleHandler struct {
+	svc usecase.SubtitleService
+}
+
+// NewSubtitleHandler creates a new SubtitleHandler.
+func NewSubtitleHandler(svc usecase.SubtitleService) *SubtitleHandler {
+	return &SubtitleHandler{svc: svc}
+}
+
+// Upload handles POST /v1/videos/{id}/subtitles
+// The body is the raw SRT or WebVTT file. The language query parameter is required;
+// label and default are optional. The format comes from the format query parameter or
+// the Content-Type (text/vtt, application/x-subrip), and is detected from the file otherwise.
+func (h *SubtitleHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	q := r.URL.Query()
+	input := usecase.UploadSubtitleInput{
+		VideoID:  videoID,
+		Language: q.Get("language"),
+		Label:    q.Get("label"),
+		// One extra byte lets the service tell an oversized file from a truncated one
+		Data: http.MaxBytesReader(w, r.Body, usecase.MaxSubtitleBytes+1),
+	}
+	if input.Language == "" {
+		Error(w, http.StatusBadRequest, "invalid_language", "language query parameter is required")
+		return
+	}
+	if raw := q.Get("default"); raw != "" {
+		input.Default, err = strconv.ParseBool(raw)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_default", "default must be true or false")
+			return
+		}
+	}
+
+	format, ok := subtitleFormat(q.Get("format"), r.Header.Get("Content-Type"))
+	if !ok {
+		Error(w, http.StatusBadRequest, "invalid_format", "Subtitle format must be srt or vtt")
+		return
+	}
+	input.Format = format
+
+	sub, err := h.svc.Upload(r.Context(), input)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, toSubtitleResponse(sub))
+}
+
+// List handles GET /v1/videos/{id}/subtitles
+func (h *SubtitleHandler) List(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	callerID, _ := middleware.GetUserID(r.Context())
+	subtitles, err := h.svc.List(r.Context(), videoID, callerID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	resp := ListSubtitlesResponse{Subtitles: make([]SubtitleResponse, len(subtitles))}
+	for i, sub := range subtitles {
+		resp.Subtitles[i] = toSubtitleResponse(sub)
+	}
+	JSON(w, http.StatusOK, resp)
+}
+
+// subtitleFormat resolves the upload format from the format query parameter or else the
+// Content-Type. Returns "" when neither names a format, leaving detection to the service.
+func subtitleFormat(param, contentType string) (string, bool) {
+	if param != "" {
+		format, err := subtitle.ParseFormat(param)
+		return string(format), err == nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/vtt":
+		return model.SubtitleFormatWebVTT, true
+	case "application/x-subrip", "text/srt":
+		return model.SubtitleFormatSRT, true
+	default:
+		return "", true
+	}
+}
+
+func toSubtitleResponse(sub *model.Subtitle) SubtitleResponse {
+	return SubtitleResponse{
+		ID:        sub.ID.String(),
+		VideoID:   sub.VideoID.String(),
+		Language:  sub.Language,
+		Label:     sub.Label,
+		Format:    sub.Format,
+		Default:   sub.Default,
+		CreatedAt: sub.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: sub.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func (h *SubtitleHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrVideoNotFound):
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, model.ErrInvalidSubtitleLanguage):
+		Error(w, http.StatusBadRequest, "invalid_language", "language must be a BCP 47 language tag")
+	case errors.Is(err, model.ErrSubtitleLabelTooLong):
+		Error(w, http.StatusBadRequest, "invalid_label", "label must be at most 64 characters")
+	case errors.Is(err, model.ErrInvalidSubtitleFormat):
+		Error(w, http.StatusBadRequest, "invalid_format", "Subtitle format must be srt or vtt")
+	case errors.Is(err, usecase.ErrInvalidSubtitleFile):
+		Error(w, http.StatusBadRequest, "invalid_subtitle_file", "Subtitle file cannot be parsed")
+	case errors.Is(err, usecase.ErrSubtitleTooLarge):
+		Error(w, http.StatusRequestEntityTooLarge, "subtitle_too_large", "Subtitle file exceeds 2 MiB")
+	default:
+		ServiceError(w, err)
+	}
+}
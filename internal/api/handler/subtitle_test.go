@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockSubtitleService is a mock implementation of usecase.SubtitleService.
+type mockSubtitleService struct {
+	uploadFn func(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error)
+	listFn   func(ctx context.Context, videoID, callerID uuid.UUID) ([]*model.Subtitle, error)
+}
+
+func (m *mockSubtitleService) Upload(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error) {
+	if m.uploadFn != nil {
+		return m.uploadFn(ctx, input)
+	}
+	return &model.Subtitle{VideoID: input.VideoID, Language: input.Language, Format: input.Format}, nil
+}
+
+func (m *mockSubtitleService) List(ctx context.Context, videoID, callerID uuid.UUID) ([]*model.Subtitle, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, videoID, callerID)
+	}
+	return []*model.Subtitle{}, nil
+}
+
+func TestSubtitleHandler_Upload(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		contentType    string
+		setupMock      func(m *mockSubtitleService)
+		wantStatusCode int
+		wantCode       string
+		wantFormat     string
+	}{
+		{
+			name:           "format from content type",
+			query:          "?language=en&label=English&default=true",
+			contentType:    "text/vtt; charset=utf-8",
+			wantStatusCode: http.StatusCreated,
+			wantFormat:     "vtt",
+		},
+		{
+			name:           "format parameter wins",
+			query:          "?language=en&format=srt",
+			contentType:    "text/plain",
+			wantStatusCode: http.StatusCreated,
+			wantFormat:     "srt",
+		},
+		{
+			name:           "format left to detection",
+			query:          "?language=en",
+			contentType:    "application/octet-stream",
+			wantStatusCode: http.StatusCreated,
+			wantFormat:     "",
+		},
+		{
+			name:           "missing language",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_language",
+		},
+		{
+			name:           "unknown format",
+			query:          "?language=en&format=ass",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_format",
+		},
+		{
+			name:           "invalid default",
+			query:          "?language=en&default=maybe",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_default",
+		},
+		{
+			name:  "invalid language tag",
+			query: "?language=english!",
+			setupMock: func(m *mockSubtitleService) {
+				m.uploadFn = func(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error) {
+					return nil, model.ErrInvalidSubtitleLanguage
+				}
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_language",
+		},
+		{
+			name:  "unparsable file",
+			query: "?language=en",
+			setupMock: func(m *mockSubtitleService) {
+				m.uploadFn = func(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error) {
+					return nil, usecase.ErrInvalidSubtitleFile
+				}
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_subtitle_file",
+		},
+		{
+			name:  "file too large",
+			query: "?language=en",
+			setupMock: func(m *mockSubtitleService) {
+				m.uploadFn = func(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error) {
+					return nil, usecase.ErrSubtitleTooLarge
+				}
+			},
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+			wantCode:       "subtitle_too_large",
+		},
+		{
+			name:  "video not found",
+			query: "?language=en",
+			setupMock: func(m *mockSubtitleService) {
+				m.uploadFn = func(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "video_not_found",
+		},
+		{
+			name:  "service error",
+			query: "?language=en",
+			setupMock: func(m *mockSubtitleService) {
+				m.uploadFn = func(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error) {
+					return nil, errors.New("connection refused")
+				}
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantCode:       "internal_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSubtitleService{}
+			var got usecase.UploadSubtitleInput
+			mock.uploadFn = func(ctx context.Context, input usecase.UploadSubtitleInput) (*model.Subtitle, error) {
+				got = input
+				if _, err := io.ReadAll(input.Data); err != nil {
+					return nil, err
+				}
+				return &model.Subtitle{ID: uuid.New(), VideoID: input.VideoID, Language: input.Language, Format: input.Format, CreatedAt: now, UpdatedAt: now}, nil
+			}
+			if tt.setupMock != nil {
+				tt.setupMock(mock)
+			}
+			h := NewSubtitleHandler(mock)
+
+			r := chi.NewRouter()
+			r.Post("/v1/videos/{id}/subtitles", h.Upload)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+videoID.String()+"/subtitles"+tt.query, strings.NewReader("WEBVTT\n"))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, resp.Error)
+				}
+				return
+			}
+
+			if got.Format != tt.wantFormat || got.VideoID != videoID || got.Language != "en" {
+				t.Errorf("unexpected input: %+v", got)
+			}
+			if strings.Contains(tt.query, "default=true") && (!got.Default || got.Label != "English") {
+				t.Errorf("label and default not passed: %+v", got)
+			}
+
+			var resp SubtitleResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.VideoID != videoID.String() || resp.CreatedAt != "2026-04-01T12:00:00Z" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestSubtitleHandler_List(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name           string
+		videoID        string
+		setupMock      func(m *mockSubtitleService)
+		wantStatusCode int
+		wantCount      int
+	}{
+		{
+			name:    "lists tracks",
+			videoID: videoID.String(),
+			setupMock: func(m *mockSubtitleService) {
+				m.listFn = func(ctx context.Context, id, callerID uuid.UUID) ([]*model.Subtitle, error) {
+					return []*model.Subtitle{
+						{VideoID: id, Language: "en", Label: "English", Format: "srt", Default: true},
+						{VideoID: id, Language: "ja", Label: "ja", Format: "vtt"},
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			wantCount:      2,
+		},
+		{
+			name:    "video not found",
+			videoID: videoID.String(),
+			setupMock: func(m *mockSubtitleService) {
+				m.listFn = func(ctx context.Context, id, callerID uuid.UUID) ([]*model.Subtitle, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			setupMock:      func(m *mockSubtitleService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSubtitleService{}
+			tt.setupMock(mock)
+			h := NewSubtitleHandler(mock)
+
+			r := chi.NewRouter()
+			r.Get("/v1/videos/{id}/subtitles", h.List)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+tt.videoID+"/subtitles", nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp ListSubtitlesResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(resp.Subtitles) != tt.wantCount || !resp.Subtitles[0].Default {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
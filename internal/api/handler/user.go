@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type CreateUserRequest struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+	Plan  string `json:"plan,omitempty"`
+}
+
+type IssueAPIKeyRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
+type UserResponse struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Name      string `json:"name,omitempty"`
+	Plan      string `json:"plan,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type APIKeyResponse struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	Name       string `json:"name,omitempty"`
+	Hint       string `json:"hint"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+}
+
+// IssuedAPIKeyResponse carries the key itself, which is never returned again.
+type IssuedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeysResponse struct {
+	Items []APIKeyResponse `json:"items"`
+}
+
+// UserHandler handles user and API key HTTP requests.
+type UserHandler struct {
+	svc usecase.UserService
+}
+
+// NewUserHandler creates a new UserHandler.
+func NewUserHandler(svc usecase.UserService) *UserHandler {
+	return &UserHandler{svc: svc}
+}
+
+// Create handles POST /v1/admin/users
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	user, err := h.svc.CreateUser(r.Context(), usecase.CreateUserInput{
+		Email: req.Email,
+		Name:  req.Name,
+		Plan:  req.Plan,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, toUserResponse(user))
+}
+
+// Get handles GET /v1/admin/users/{id}
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+
+	user, err := h.svc.GetUser(r.Context(), userID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toUserResponse(user))
+}
+
+// IssueKey handles POST /v1/admin/users/{id}/api-keys
+// Operators issue a user's first key here; later keys can be issued with it.
+func (h *UserHandler) IssueKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+	h.issueKey(w, r, userID)
+}
+
+// IssueOwnKey handles POST /v1/me/api-keys
+func (h *UserHandler) IssueOwnKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	h.issueKey(w, r, userID)
+}
+
+func (h *UserHandler) issueKey(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	var req IssueAPIKeyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+			return
+		}
+	}
+
+	issued, err := h.svc.IssueAPIKey(r.Context(), userID, req.Name)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	// The key grants access by itself
+	w.Header().Set("Cache-Control", "no-store")
+	JSON(w, http.StatusCreated, IssuedAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(issued.APIKey),
+		Key:            issued.Key,
+	})
+}
+
+// ListOwnKeys handles GET /v1/me/api-keys
+func (h *UserHandler) ListOwnKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.svc.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	resp := APIKeysResponse{Items: make([]APIKeyResponse, 0, len(keys))}
+	for _, key := range keys {
+		resp.Items = append(resp.Items, toAPIKeyResponse(key))
+	}
+
+	JSON(w, http.StatusOK, resp)
+}
+
+// RevokeOwnKey handles DELETE /v1/me/api-keys/{keyID}
+// Revoking a revoked key succeeds, so retries are safe.
+func (h *UserHandler) RevokeOwnKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyID"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_key_id", "API key ID must be a valid UUID")
+		return
+	}
+
+	key, err := h.svc.RevokeAPIKey(r.Context(), userID, keyID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toAPIKeyResponse(key))
+}
+
+// callerID returns the authenticated caller, writing a 401 response when there is none.
+func callerID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		Error(w, http.StatusUnauthorized, "unauthenticated", "An API key or a valid "+middleware.UserIDHeader+" header is required")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (h *UserHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		Error(w, http.StatusNotFound, "user_not_found", "User not found")
+	case errors.Is(err, repository.ErrAPIKeyNotFound):
+		Error(w, http.StatusNotFound, "api_key_not_found", "API key not found")
+	case errors.Is(err, repository.ErrDuplicateEmail):
+		Error(w, http.StatusConflict, "email_taken", "Email is already registered")
+	case errors.Is(err, model.ErrInvalidEmail):
+		Error(w, http.StatusBadRequest, "invalid_email", "Email must be a valid address")
+	case errors.Is(err, model.ErrUserNameTooLong):
+		Error(w, http.StatusBadRequest, "invalid_name", "Name exceeds maximum length")
+	case errors.Is(err, model.ErrInvalidUserPlan):
+		Error(w, http.StatusBadRequest, "invalid_plan", "Plan must be at most 32 characters without spaces")
+	case errors.Is(err, model.ErrAPIKeyNameTooLong):
+		Error(w, http.StatusBadRequest, "invalid_name", "API key name exceeds maximum length")
+	default:
+		ServiceError(w, err)
+	}
+}
+
+func toUserResponse(u *model.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID.String(),
+		Email:     u.Email,
+		Name:      u.Name,
+		Plan:      u.Plan,
+		CreatedAt: u.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toAPIKeyResponse(k *model.APIKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:        k.ID.String(),
+		UserID:    k.UserID.String(),
+		Name:      k.Name,
+		Hint:      k.Hint,
+		CreatedAt: k.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if k.LastUsedAt != nil {
+		resp.LastUsedAt = k.LastUsedAt.UTC().Format(time.RFC3339)
+	}
+	if k.RevokedAt != nil {
+		resp.RevokedAt = k.RevokedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
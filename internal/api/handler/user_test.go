@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockUserService is a mock implementation of usecase.UserService.
+type mockUserService struct {
+	createUserFn   func(ctx context.Context, input usecase.CreateUserInput) (*model.User, error)
+	getUserFn      func(ctx context.Context, userID uuid.UUID) (*model.User, error)
+	issueAPIKeyFn  func(ctx context.Context, userID uuid.UUID, name string) (*usecase.IssuedAPIKey, error)
+	listAPIKeysFn  func(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error)
+	revokeAPIKeyFn func(ctx context.Context, userID, keyID uuid.UUID) (*model.APIKey, error)
+}
+
+func (m *mockUserService) CreateUser(ctx context.Context, input usecase.CreateUserInput) (*model.User, error) {
+	if m.createUserFn != nil {
+		return m.createUserFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockUserService) GetUser(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	if m.getUserFn != nil {
+		return m.getUserFn(ctx, userID)
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (m *mockUserService) IssueAPIKey(ctx context.Context, userID uuid.UUID, name string) (*usecase.IssuedAPIKey, error) {
+	if m.issueAPIKeyFn != nil {
+		return m.issueAPIKeyFn(ctx, userID, name)
+	}
+	return nil, nil
+}
+
+func (m *mockUserService) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	if m.listAPIKeysFn != nil {
+		return m.listAPIKeysFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserService) RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) (*model.APIKey, error) {
+	if m.revokeAPIKeyFn != nil {
+		return m.revokeAPIKeyFn(ctx, userID, keyID)
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func (m *mockUserService) AuthenticateAPIKey(ctx context.Context, key string) (uuid.UUID, error) {
+	return uuid.Nil, usecase.ErrInvalidAPIKey
+}
+
+func newUserRouter(h *UserHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Post("/v1/admin/users", h.Create)
+	r.Get("/v1/admin/users/{id}", h.Get)
+	r.Post("/v1/admin/users/{id}/api-keys", h.IssueKey)
+	r.Post("/v1/me/api-keys", h.IssueOwnKey)
+	r.Get("/v1/me/api-keys", h.ListOwnKeys)
+	r.Delete("/v1/me/api-keys/{keyID}", h.RevokeOwnKey)
+	return r
+}
+
+func TestUserHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "created",
+			body:           `{"email": "alice@example.com", "name": "Alice", "plan": "premium"}`,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "invalid email",
+			body:           `{"email": "alice"}`,
+			serviceErr:     model.ErrInvalidEmail,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_email",
+		},
+		{
+			name:           "invalid plan",
+			body:           `{"email": "alice@example.com", "plan": "gold plus"}`,
+			serviceErr:     model.ErrInvalidUserPlan,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_plan",
+		},
+		{
+			name:           "email taken",
+			body:           `{"email": "alice@example.com"}`,
+			serviceErr:     repository.ErrDuplicateEmail,
+			wantStatusCode: http.StatusConflict,
+			wantCode:       "email_taken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockUserService{
+				createUserFn: func(ctx context.Context, input usecase.CreateUserInput) (*model.User, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &model.User{ID: uuid.New(), Email: input.Email, Name: input.Name, Plan: input.Plan, CreatedAt: time.Now()}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/users", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newUserRouter(NewUserHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if rec.Code == http.StatusCreated {
+				var resp UserResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Email != "alice@example.com" || resp.Name != "Alice" || resp.Plan != "premium" {
+					t.Errorf("unexpected response: %+v", resp)
+				}
+			}
+		})
+	}
+}
+
+func TestUserHandler_IssueOwnKey(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           []byte
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "issued",
+			userID:         userID.String(),
+			body:           []byte(`{"name": "ci"}`),
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "no body",
+			userID:         userID.String(),
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "unauthenticated",
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "unauthenticated",
+		},
+		{
+			name:           "unknown user",
+			userID:         userID.String(),
+			serviceErr:     repository.ErrUserNotFound,
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "user_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockUserService{
+				issueAPIKeyFn: func(ctx context.Context, id uuid.UUID, name string) (*usecase.IssuedAPIKey, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if id != userID {
+						t.Errorf("expected key for %v, got %v", userID, id)
+					}
+					key, secret, err := model.NewAPIKey(id, name)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return &usecase.IssuedAPIKey{APIKey: key, Key: secret}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/me/api-keys", bytes.NewReader(tt.body))
+			if tt.userID != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			newUserRouter(NewUserHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if rec.Code == http.StatusCreated {
+				var resp IssuedAPIKeyResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if !strings.HasPrefix(resp.Key, model.APIKeyPrefix) || !strings.HasPrefix(resp.Key, resp.Hint) {
+					t.Errorf("unexpected key %q with hint %q", resp.Key, resp.Hint)
+				}
+				if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+					t.Errorf("expected Cache-Control no-store, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestUserHandler_RevokeOwnKey(t *testing.T) {
+	userID := uuid.New()
+	keyID := uuid.New()
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name           string
+		keyID          string
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "revoked",
+			keyID:          keyID.String(),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid key ID",
+			keyID:          "abc",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_key_id",
+		},
+		{
+			name:           "key of another user",
+			keyID:          keyID.String(),
+			serviceErr:     repository.ErrAPIKeyNotFound,
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "api_key_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockUserService{
+				revokeAPIKeyFn: func(ctx context.Context, uid, kid uuid.UUID) (*model.APIKey, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &model.APIKey{ID: kid, UserID: uid, Hint: "gsk_abcdef", RevokedAt: &revokedAt}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/me/api-keys/"+tt.keyID, nil)
+			req.Header.Set(middleware.UserIDHeader, userID.String())
+			rec := httptest.NewRecorder()
+			newUserRouter(NewUserHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK {
+				var resp APIKeyResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.RevokedAt == "" {
+					t.Errorf("expected revoked_at, got %+v", resp)
+				}
+			}
+		})
+	}
+}
@@ -1,100 +1,528 @@
 package handler
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
 	"github.com/hszk-dev/gostream/internal/usecase"
 )
 
+// ViewerRegionHeader carries the viewer's region as resolved by the edge (e.g., CDN geo headers).
+// It selects which storage region's CDN appears in playback URLs.
+const ViewerRegionHeader = "X-Viewer-Region"
+
+// staleWarning is the Warning header of responses served from cache past its TTL
+// (RFC 7234 warn-code 110).
+const staleWarning = `110 - "Response is Stale"`
+
+// maxFormFieldBytes caps each form field of an inline upload.
+const maxFormFieldBytes = 1024
+
+// maxIdempotencyKeyLength caps the Idempotency-Key header, matching its column.
+const maxIdempotencyKeyLength = 255
+
 // Request/Response types
 
 type CreateVideoRequest struct {
-	UserID   string `json:"user_id"`
-	Title    string `json:"title"`
-	FileName string `json:"file_name"`
+	UserID         string `json:"user_id"`
+	Title          string `json:"title"`
+	FileName       string `json:"file_name"`
+	PreviewSeconds int    `json:"preview_seconds,omitempty"`
+	// ExpiresAt unpublishes the video at the given RFC 3339 time; omitted never expires it.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// SetExpirationRequest is the body of PUT /v1/videos/{id}/expiration; a null expires_at
+// clears the expiry.
+type SetExpirationRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UpdateVideoRequest is the body of PATCH /v1/videos/{id}; omitted fields are left as
+// they are, and an empty description or tag list clears it.
+type UpdateVideoRequest struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Tags        *[]string `json:"tags"`
+	// Visibility is one of public, unlisted or private.
+	Visibility *string `json:"visibility"`
+}
+
+// InitiateUploadRequest is the body of POST /v1/videos/{id}/uploads.
+type InitiateUploadRequest struct {
+	// Size of the original in bytes, which determines the number of parts.
+	Size int64 `json:"size"`
+}
+
+// UploadPartResponse is where one part of a multipart upload is PUT.
+type UploadPartResponse struct {
+	PartNumber int    `json:"part_number"`
+	URL        string `json:"url"`
+}
+
+// InitiateUploadResponse is returned by POST /v1/videos/{id}/uploads. Every part but the
+// last is part_size bytes.
+type InitiateUploadResponse struct {
+	UploadID  string               `json:"upload_id"`
+	PartSize  int64                `json:"part_size"`
+	Parts     []UploadPartResponse `json:"parts"`
+	ExpiresAt string               `json:"expires_at"`
+}
+
+// CompleteUploadRequest is the body of POST /v1/videos/{id}/uploads/{uploadID}/complete.
+type CompleteUploadRequest struct {
+	Parts []CompletedPartRequest `json:"parts"`
+}
+
+// CompletedPartRequest is an uploaded part and the ETag header its PUT returned.
+type CompletedPartRequest struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+// ProcessRequest is the optional body of a process request; an empty body uses the default ABR profile.
+type ProcessRequest struct {
+	ABRProfile string `json:"abr_profile,omitempty"`
+}
+
+// RetranscodeRequest is the optional body of a retranscode; an empty body keeps the default ladder.
+type RetranscodeRequest struct {
+	ABRProfile string   `json:"abr_profile,omitempty"`
+	Variants   []string `json:"variants,omitempty"`
 }
 
 type CreateVideoResponse struct {
-	ID        string `json:"id"`
-	UserID    string `json:"user_id"`
-	Title     string `json:"title"`
-	Status    string `json:"status"`
-	UploadURL string `json:"upload_url"`
-	CreatedAt string `json:"created_at"`
+	ID         string `json:"id"`
+	SortableID string `json:"sortable_id,omitempty"`
+	ShareSlug  string `json:"share_slug,omitempty"`
+	TitleSlug  string `json:"title_slug,omitempty"`
+	UserID     string `json:"user_id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	UploadURL  string `json:"upload_url"`
+	CreatedAt  string `json:"created_at"`
 }
 
 type VideoResponse struct {
-	ID          string `json:"id"`
-	UserID      string `json:"user_id"`
-	Title       string `json:"title"`
-	Status      string `json:"status"`
-	OriginalURL string `json:"original_url,omitempty"`
-	HLSURL      string `json:"hls_url,omitempty"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID             string   `json:"id"`
+	SortableID     string   `json:"sortable_id,omitempty"`
+	ShareSlug      string   `json:"share_slug,omitempty"`
+	TitleSlug      string   `json:"title_slug,omitempty"`
+	UserID         string   `json:"user_id"`
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Visibility     string   `json:"visibility,omitempty"`
+	Status         string   `json:"status"`
+	OriginalURL    string   `json:"original_url,omitempty"`
+	OriginalSize   int64    `json:"original_size,omitempty"`
+	OriginalETag   string   `json:"original_etag,omitempty"`
+	HLSURL         string   `json:"hls_url,omitempty"`
+	DashURL        string   `json:"dash_url,omitempty"`
+	PreviewSeconds int      `json:"preview_seconds,omitempty"`
+	PreviewURL     string   `json:"preview_url,omitempty"`
+	CreatedAt      string   `json:"created_at"`
+	UpdatedAt      string   `json:"updated_at"`
+	// ExpiresAt is when the video is unpublished; omitted for videos that never expire.
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// FailureCode and FailureReason tell why a FAILED video failed; omitted otherwise.
+	FailureCode   string `json:"failure_code,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	// Thumbnails are CDN URLs of the poster images, so list views need no extra requests.
+	Thumbnails *ThumbnailsResponse `json:"thumbnails,omitempty"`
+	// Source is omitted until the original has been probed by a transcode.
+	Source *SourceResponse `json:"source,omitempty"`
+	// TranscodeProgress is the percentage encoded, only while PROCESSING and only on
+	// GET /v1/videos/{id}. It stops at 99 while the output is uploaded.
+	TranscodeProgress *int `json:"transcode_progress,omitempty"`
+	// Stale is set when the video was served from cache past its TTL during a database
+	// outage, so it may not reflect recent changes.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// ThumbnailsResponse holds a poster image URL per size.
+type ThumbnailsResponse struct {
+	Small  string `json:"small"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
+}
+
+// SourceResponse describes the uploaded original as probed before transcoding.
+type SourceResponse struct {
+	DurationSeconds float64 `json:"duration_seconds"`
+	Width           int     `json:"width"`
+	Height          int     `json:"height"`
+	Codec           string  `json:"codec,omitempty"`
+	Bitrate         int64   `json:"bitrate,omitempty"`
+	FrameRate       float64 `json:"frame_rate,omitempty"`
+}
+
+// PlannedVariantResponse is a rendition a transcode would produce.
+type PlannedVariantResponse struct {
+	Name           string `json:"name"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	Bitrate        int    `json:"bitrate"`
+	EstimatedBytes int64  `json:"estimated_bytes"`
+}
+
+// TranscodeEstimateResponse predicts the cost of a transcode from historical jobs.
+type TranscodeEstimateResponse struct {
+	ProcessingSeconds float64 `json:"processing_seconds"`
+	OutputBytes       int64   `json:"output_bytes"`
+	Samples           int64   `json:"samples"`
+}
+
+// ProcessPlanResponse is returned by POST /v1/videos/{id}/process?dry_run=true.
+type ProcessPlanResponse struct {
+	SourceWidth              int                      `json:"source_width"`
+	SourceHeight             int                      `json:"source_height"`
+	ABRProfile               string                   `json:"abr_profile"`
+	Variants                 []PlannedVariantResponse `json:"variants"`
+	Preview                  *PlannedVariantResponse  `json:"preview,omitempty"`
+	EstimatedDurationSeconds float64                  `json:"estimated_duration_seconds"`
+	EstimatedOutputBytes     int64                    `json:"estimated_output_bytes"`
+	// Estimate is omitted until enough transcodes have been recorded.
+	Estimate *TranscodeEstimateResponse `json:"estimate,omitempty"`
+}
+
+// OriginalURLResponse is returned by GET /v1/videos/{id}/original-url.
+type OriginalURLResponse struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type VideosResponse struct {
+	Items      []VideoResponse `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// StatusEventResponse is a recorded status transition. From is empty for the transition
+// that created the video.
+type StatusEventResponse struct {
+	From      string `json:"from,omitempty"`
+	To        string `json:"to"`
+	Actor     string `json:"actor"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type StatusEventsResponse struct {
+	Items []StatusEventResponse `json:"items"`
+}
+
+// StorageUsageResponse is returned by GET /v1/users/{id}/usage. quota_bytes is omitted
+// when storage is unlimited.
+type StorageUsageResponse struct {
+	UserID        string `json:"user_id"`
+	Videos        int64  `json:"videos"`
+	OriginalBytes int64  `json:"original_bytes"`
+	OutputBytes   int64  `json:"output_bytes"`
+	TotalBytes    int64  `json:"total_bytes"`
+	QuotaBytes    int64  `json:"quota_bytes,omitempty"`
 }
 
 // VideoHandler handles video-related HTTP requests.
 type VideoHandler struct {
 	svc usecase.VideoService
+	// entitlementsEnforced hides playback URLs from other users; see
+	// model.Video.PlaybackURLsVisibleTo.
+	entitlementsEnforced bool
 }
 
-// NewVideoHandler creates a new VideoHandler.
-func NewVideoHandler(svc usecase.VideoService) *VideoHandler {
-	return &VideoHandler{svc: svc}
+// NewVideoHandler creates a new VideoHandler. entitlementsEnforced is set when an
+// entitlement provider is configured.
+func NewVideoHandler(svc usecase.VideoService, entitlementsEnforced bool) *VideoHandler {
+	return &VideoHandler{svc: svc, entitlementsEnforced: entitlementsEnforced}
 }
 
 // Create handles POST /v1/videos
+// A multipart/form-data body uploads the file with the request instead; see createInline.
+// With an Idempotency-Key header, a retry responds with the video the first request
+// created, marked by an Idempotent-Replayed header, rather than creating another.
 func (h *VideoHandler) Create(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Idempotency-Key")
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		if key != "" {
+			// The file would be streamed to storage before a replay could be detected
+			Error(w, http.StatusBadRequest, "idempotency_key_unsupported", "Idempotency-Key is only supported with a JSON body")
+			return
+		}
+		h.createInline(w, r)
+		return
+	}
+	if key != "" && !validIdempotencyKey(key) {
+		Error(w, http.StatusBadRequest, "invalid_idempotency_key", "Idempotency-Key must be at most 255 visible ASCII characters")
+		return
+	}
+
 	var req CreateVideoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
 		return
 	}
 
+	input, ok := createVideoInput(w, r, req)
+	if !ok {
+		return
+	}
+	input.IdempotencyKey = key
+
+	output, err := h.svc.CreateVideo(r.Context(), input)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	if output.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	JSON(w, http.StatusCreated, CreateVideoResponse{
+		ID:         output.Video.ID.String(),
+		SortableID: output.Video.SortableID,
+		ShareSlug:  output.Video.ShareSlug,
+		TitleSlug:  output.Video.TitleSlug,
+		UserID:     output.Video.UserID.String(),
+		Title:      output.Video.Title,
+		Status:     output.Video.Status.String(),
+		UploadURL:  output.UploadURL,
+		CreatedAt:  output.Video.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// createInline creates a video from a small file sent as the "file" part of a
+// multipart/form-data body. The fields of CreateVideoRequest, plus process and
+// abr_profile, are form fields that must precede the file, which is streamed to storage
+// without buffering; file_name defaults to the part's file name. It responds with the
+// video, UPLOADED or, with process=true, PROCESSING.
+func (h *VideoHandler) createInline(w http.ResponseWriter, r *http.Request) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid multipart body")
+		return
+	}
+
+	fields := make(map[string]string)
+	var file *multipart.Part
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			Error(w, http.StatusBadRequest, "missing_file", "A file part is required")
+			return
+		}
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_request", "Invalid multipart body")
+			return
+		}
+		if part.FormName() == "file" {
+			file = part
+			break
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldBytes+1))
+		if err != nil || len(value) > maxFormFieldBytes {
+			Error(w, http.StatusBadRequest, "invalid_request", "Form field "+part.FormName()+" is too long")
+			return
+		}
+		fields[part.FormName()] = string(value)
+	}
+
+	req := CreateVideoRequest{
+		UserID:   fields["user_id"],
+		Title:    fields["title"],
+		FileName: cmp.Or(fields["file_name"], file.FileName()),
+	}
+	if raw := fields["preview_seconds"]; raw != "" {
+		if req.PreviewSeconds, err = strconv.Atoi(raw); err != nil {
+			Error(w, http.StatusBadRequest, "invalid_preview_seconds", "preview_seconds must be an integer")
+			return
+		}
+	}
+	if raw := fields["expires_at"]; raw != "" {
+		expiresAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_expires_at", "expires_at must be an RFC 3339 time")
+			return
+		}
+		req.ExpiresAt = &expiresAt
+	}
+	input, ok := createVideoInput(w, r, req)
+	if !ok {
+		return
+	}
+
+	upload := usecase.UploadVideoInput{
+		CreateVideoInput: input,
+		Content:          file,
+		ContentType:      cmp.Or(file.Header.Get("Content-Type"), "application/octet-stream"),
+		ABRProfile:       fields["abr_profile"],
+	}
+	if raw := fields["process"]; raw != "" {
+		if upload.Process, err = strconv.ParseBool(raw); err != nil {
+			Error(w, http.StatusBadRequest, "invalid_process", "process must be true or false")
+			return
+		}
+	}
+
+	video, err := h.svc.UploadVideo(r.Context(), upload)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, toVideoResponse(video))
+}
+
+// validIdempotencyKey reports whether key fits its column and holds only visible ASCII.
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < '!' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// createVideoInput validates a create request, writing a 400 response on failure.
+// A request authenticated with an API key creates the video for the key's user, so
+// user_id may be omitted and must not name anyone else (403).
+func createVideoInput(w http.ResponseWriter, r *http.Request, req CreateVideoRequest) (usecase.CreateVideoInput, bool) {
+	caller, _ := middleware.GetUserID(r.Context())
+	byKey := middleware.AuthenticatedByAPIKey(r.Context())
+	if byKey && req.UserID == "" {
+		req.UserID = caller.String()
+	}
+
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
 		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
-		return
+		return usecase.CreateVideoInput{}, false
+	}
+	if byKey && userID != caller {
+		Error(w, http.StatusForbidden, "not_owner", "API key cannot create videos for another user")
+		return usecase.CreateVideoInput{}, false
 	}
 
 	if req.Title == "" {
 		Error(w, http.StatusBadRequest, "invalid_title", "Title is required")
-		return
+		return usecase.CreateVideoInput{}, false
 	}
 
 	if req.FileName == "" {
 		Error(w, http.StatusBadRequest, "invalid_file_name", "File name is required")
+		return usecase.CreateVideoInput{}, false
+	}
+
+	input := usecase.CreateVideoInput{
+		UserID:         userID,
+		Title:          req.Title,
+		FileName:       req.FileName,
+		PreviewSeconds: req.PreviewSeconds,
+	}
+	if req.ExpiresAt != nil {
+		input.ExpiresAt = *req.ExpiresAt
+	}
+	return input, true
+}
+
+// CompleteUpload handles POST /v1/videos/{id}/upload-complete
+func (h *VideoHandler) CompleteUpload(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
 		return
 	}
 
-	output, err := h.svc.CreateVideo(r.Context(), usecase.CreateVideoInput{
-		UserID:   userID,
-		Title:    req.Title,
-		FileName: req.FileName,
-	})
+	video, err := h.svc.CompleteUpload(r.Context(), videoID)
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 
-	JSON(w, http.StatusCreated, CreateVideoResponse{
-		ID:        output.Video.ID.String(),
-		UserID:    output.Video.UserID.String(),
-		Title:     output.Video.Title,
-		Status:    output.Video.Status.String(),
-		UploadURL: output.UploadURL,
-		CreatedAt: output.Video.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	})
+	JSON(w, http.StatusOK, toVideoResponse(video))
+}
+
+// InitiateUpload handles POST /v1/videos/{id}/uploads
+// It starts a multipart upload of the original, for files too large for the single
+// upload URL returned on create.
+func (h *VideoHandler) InitiateUpload(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	var req InitiateUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	upload, err := h.svc.InitiateMultipartUpload(r.Context(), videoID, req.Size)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	resp := InitiateUploadResponse{
+		UploadID:  upload.UploadID,
+		PartSize:  upload.PartSize,
+		Parts:     make([]UploadPartResponse, len(upload.PartURLs)),
+		ExpiresAt: upload.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+	for i, url := range upload.PartURLs {
+		resp.Parts[i] = UploadPartResponse{PartNumber: i + 1, URL: url}
+	}
+	JSON(w, http.StatusCreated, resp)
+}
+
+// CompleteMultipartUpload handles POST /v1/videos/{id}/uploads/{uploadID}/complete
+// It assembles the parts and confirms the upload like /upload-complete.
+func (h *VideoHandler) CompleteMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	var req CompleteUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	parts := make([]repository.CompletedPart, len(req.Parts))
+	for i, p := range req.Parts {
+		parts[i] = repository.CompletedPart{PartNumber: p.PartNumber, ETag: p.ETag}
+	}
+
+	video, err := h.svc.CompleteMultipartUpload(r.Context(), videoID, chi.URLParam(r, "uploadID"), parts)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toVideoResponse(video))
 }
 
 // TriggerProcess handles POST /v1/videos/{id}/process
+// With ?dry_run=true it returns the planned job instead of starting it.
+// An optional body selects the ABR profile.
 func (h *VideoHandler) TriggerProcess(w http.ResponseWriter, r *http.Request) {
 	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -102,7 +530,58 @@ func (h *VideoHandler) TriggerProcess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.svc.TriggerProcess(r.Context(), videoID); err != nil {
+	var req ProcessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	input := usecase.ProcessInput{ABRProfile: req.ABRProfile}
+
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_dry_run", "dry_run must be true or false")
+			return
+		}
+		if dryRun {
+			plan, err := h.svc.PlanProcess(r.Context(), videoID, input)
+			if err != nil {
+				h.handleServiceError(w, err)
+				return
+			}
+			JSON(w, http.StatusOK, toProcessPlanResponse(plan))
+			return
+		}
+	}
+
+	if err := h.svc.TriggerProcess(r.Context(), videoID, input); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Retranscode handles POST /v1/videos/{id}/retranscode
+// READY and FAILED videos can be regenerated, optionally with another ABR profile or
+// restricted to some variants.
+func (h *VideoHandler) Retranscode(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	var req RetranscodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+
+	if err := h.svc.Retranscode(r.Context(), videoID, usecase.RetranscodeInput{
+		ABRProfile: req.ABRProfile,
+		Variants:   req.Variants,
+	}); err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
@@ -110,6 +589,123 @@ func (h *VideoHandler) TriggerProcess(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 }
 
+// Delete handles DELETE /v1/videos/{id}
+// The video disappears immediately; its stored objects are removed asynchronously by the worker.
+func (h *VideoHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	video, err := h.svc.DeleteVideo(r.Context(), videoID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusAccepted, toVideoResponse(video))
+}
+
+// SetExpiration handles PUT /v1/videos/{id}/expiration
+// Once expires_at passes the video is unpublished: it moves to EXPIRED, leaves listings
+// and its playback tokens are revoked.
+func (h *VideoHandler) SetExpiration(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	var req SetExpirationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+
+	video, err := h.svc.SetExpiration(r.Context(), videoID, expiresAt)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toVideoResponse(video))
+}
+
+// Update handles PATCH /v1/videos/{id}
+// It changes the title, description, tags and visibility; the title slug is kept so
+// existing links keep working.
+func (h *VideoHandler) Update(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	var req UpdateVideoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
+		return
+	}
+	if req.Title == nil && req.Description == nil && req.Tags == nil && req.Visibility == nil {
+		Error(w, http.StatusBadRequest, "invalid_request", "At least one of title, description, tags or visibility is required")
+		return
+	}
+
+	input := usecase.UpdateMetadataInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        req.Tags,
+	}
+	if req.Visibility != nil {
+		visibility := model.Visibility(*req.Visibility)
+		input.Visibility = &visibility
+	}
+
+	video, err := h.svc.UpdateMetadata(r.Context(), videoID, input)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toVideoResponse(video))
+}
+
+// GetOriginalURL handles GET /v1/videos/{id}/original-url
+// It returns an expiring download URL of the original upload to the video's owner.
+func (h *VideoHandler) GetOriginalURL(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		Error(w, http.StatusUnauthorized, "unauthenticated", "A valid "+middleware.UserIDHeader+" header is required")
+		return
+	}
+
+	original, err := h.svc.GetOriginalURL(r.Context(), usecase.OriginalURLInput{
+		VideoID: videoID,
+		UserID:  userID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	// The URL grants access by itself
+	w.Header().Set("Cache-Control", "private, no-store")
+	JSON(w, http.StatusOK, OriginalURLResponse{
+		URL:       original.URL,
+		ExpiresAt: original.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
 // Get handles GET /v1/videos/{id}
 func (h *VideoHandler) Get(w http.ResponseWriter, r *http.Request) {
 	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -118,41 +714,376 @@ func (h *VideoHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	video, err := h.svc.GetVideo(r.Context(), videoID)
+	ctx := r.Context()
+	if region := r.Header.Get(ViewerRegionHeader); region != "" {
+		ctx = usecase.WithViewerRegion(ctx, region)
+	}
+
+	video, err := h.svc.GetVideo(ctx, videoID)
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
+	// Private videos are reported as missing, so their IDs cannot be probed
+	callerID, _ := middleware.GetUserID(ctx)
+	if !video.VisibleTo(callerID) {
+		h.handleServiceError(w, repository.ErrVideoNotFound)
+		return
+	}
 
-	JSON(w, http.StatusOK, toVideoResponse(video))
+	resp := toViewerVideoResponse(video, callerID, h.entitlementsEnforced)
+	if video.Stale {
+		w.Header().Set("Warning", staleWarning)
+	}
+	if video.Status == model.StatusProcessing {
+		if percent, ok := h.svc.GetTranscodeProgress(ctx, videoID); ok {
+			resp.TranscodeProgress = &percent
+		}
+	}
+
+	JSON(w, http.StatusOK, resp)
+}
+
+// ListByUser handles GET /v1/users/{id}/videos?limit=...&cursor=...
+func (h *VideoHandler) ListByUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_limit", "Limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	callerID, _ := middleware.GetUserID(r.Context())
+	output, err := h.svc.ListVideos(r.Context(), usecase.ListVideosInput{
+		UserID:   userID,
+		CallerID: callerID,
+		Cursor:   r.URL.Query().Get("cursor"),
+		Limit:    limit,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, VideosResponse{
+		Items:      toVideoResponses(output.Videos, callerID, h.entitlementsEnforced),
+		NextCursor: output.NextCursor,
+	})
+}
+
+// Search handles GET /v1/videos/search?q=...&user_id=...&limit=...&cursor=...
+// It searches the titles and descriptions of user_id's videos, or of the caller's own
+// without it.
+func (h *VideoHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var userID uuid.UUID
+	if v := q.Get("user_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+			return
+		}
+		userID = id
+	} else if id, ok := middleware.GetUserID(r.Context()); ok {
+		userID = id
+	} else {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "user_id or a valid "+middleware.UserIDHeader+" header is required")
+		return
+	}
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_limit", "Limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	callerID, _ := middleware.GetUserID(r.Context())
+	output, err := h.svc.SearchVideos(r.Context(), usecase.SearchVideosInput{
+		UserID:   userID,
+		CallerID: callerID,
+		Query:    q.Get("q"),
+		Cursor:   q.Get("cursor"),
+		Limit:    limit,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, VideosResponse{
+		Items:      toVideoResponses(output.Videos, callerID, h.entitlementsEnforced),
+		NextCursor: output.NextCursor,
+	})
+}
+
+// ListStatusEvents handles GET /v1/videos/{id}/status-events?limit=...
+// It returns the video's status transitions, most recent first.
+func (h *VideoHandler) ListStatusEvents(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			Error(w, http.StatusBadRequest, "invalid_limit", "Limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	callerID, _ := middleware.GetUserID(r.Context())
+	events, err := h.svc.ListStatusEvents(r.Context(), videoID, callerID, limit)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	items := make([]StatusEventResponse, len(events))
+	for i, e := range events {
+		items[i] = StatusEventResponse{
+			From:      string(e.From),
+			To:        string(e.To),
+			Actor:     string(e.Actor),
+			Reason:    e.Reason,
+			CreatedAt: e.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	JSON(w, http.StatusOK, StatusEventsResponse{Items: items})
+}
+
+// Usage handles GET /v1/users/{id}/usage
+// Usage is private, so only the user themselves may read it.
+func (h *VideoHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID must be a valid UUID")
+		return
+	}
+	caller, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	if caller != userID {
+		Error(w, http.StatusForbidden, "not_owner", "Users can only view their own storage usage")
+		return
+	}
+
+	usage, err := h.svc.GetStorageUsage(r.Context(), userID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, StorageUsageResponse{
+		UserID:        usage.UserID.String(),
+		Videos:        usage.Videos,
+		OriginalBytes: usage.OriginalBytes,
+		OutputBytes:   usage.OutputBytes,
+		TotalBytes:    usage.TotalBytes(),
+		QuotaBytes:    usage.QuotaBytes,
+	})
 }
 
 func (h *VideoHandler) handleServiceError(w http.ResponseWriter, err error) {
+	var (
+		overloaded  *usecase.OverloadedError
+		rateLimited *usecase.URLRateLimitedError
+	)
 	switch {
+	case errors.As(err, &overloaded):
+		// Retry-After is whole seconds; round up so clients never retry early
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(overloaded.RetryAfter.Seconds()))))
+		Error(w, http.StatusServiceUnavailable, "overloaded", "Service is shedding background work, retry later")
+	case errors.As(err, &rateLimited):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateLimited.RetryAfter.Seconds()))))
+		Error(w, http.StatusTooManyRequests, "rate_limited", "Too many presigned URLs issued, retry later")
 	case errors.Is(err, repository.ErrVideoNotFound):
 		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, usecase.ErrNotVideoOwner):
+		Error(w, http.StatusForbidden, "not_owner", "User does not own this video")
+	case errors.Is(err, usecase.ErrOriginalNotUploaded):
+		Error(w, http.StatusConflict, "original_not_uploaded", "Original file has not been uploaded")
 	case errors.Is(err, model.ErrInvalidUserID):
 		Error(w, http.StatusBadRequest, "invalid_user_id", "User ID cannot be empty")
 	case errors.Is(err, model.ErrEmptyTitle):
 		Error(w, http.StatusBadRequest, "invalid_title", "Title cannot be empty")
 	case errors.Is(err, model.ErrTitleTooLong):
 		Error(w, http.StatusBadRequest, "invalid_title", "Title exceeds maximum length")
+	case errors.Is(err, model.ErrDescriptionTooLong):
+		Error(w, http.StatusBadRequest, "invalid_description", "Description exceeds maximum length of 5000 characters")
+	case errors.Is(err, model.ErrInvalidTag):
+		Error(w, http.StatusBadRequest, "invalid_tags", "Tags must be 1 to 50 letters, digits, spaces, hyphens or underscores")
+	case errors.Is(err, model.ErrTooManyTags):
+		Error(w, http.StatusBadRequest, "invalid_tags", "A video can have at most 20 tags")
+	case errors.Is(err, model.ErrInvalidVisibility):
+		Error(w, http.StatusBadRequest, "invalid_visibility", "Visibility must be public, unlisted or private")
+	case errors.Is(err, model.ErrInvalidPreviewDuration):
+		Error(w, http.StatusBadRequest, "invalid_preview_seconds", "Preview length must be between 0 and 600 seconds")
+	case errors.Is(err, model.ErrExpiryInPast):
+		Error(w, http.StatusBadRequest, "invalid_expires_at", "Expiry time must be in the future")
+	case errors.Is(err, usecase.ErrVideoExpired):
+		Error(w, http.StatusConflict, "video_expired", "Video has already expired")
 	case errors.Is(err, usecase.ErrVideoAlreadyCompleted):
 		Error(w, http.StatusConflict, "video_already_completed", "Video processing has already completed")
+	case errors.Is(err, usecase.ErrUploadClosed):
+		Error(w, http.StatusConflict, "upload_closed", "Video is not awaiting an upload")
+	case errors.Is(err, usecase.ErrInvalidUploadSize):
+		Error(w, http.StatusBadRequest, "invalid_size", "Size must be a positive number of bytes, at most 5 TiB")
+	case errors.Is(err, usecase.ErrInvalidUploadParts), errors.Is(err, repository.ErrInvalidMultipartParts):
+		Error(w, http.StatusBadRequest, "invalid_parts", "Parts must list every uploaded part once with the ETag its upload returned")
+	case errors.Is(err, repository.ErrMultipartUploadNotFound):
+		Error(w, http.StatusNotFound, "upload_not_found", "Multipart upload not found")
+	case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+		Error(w, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used for a different request")
+	case errors.Is(err, usecase.ErrInlineUploadDisabled):
+		Error(w, http.StatusUnsupportedMediaType, "inline_upload_disabled", "Inline uploads are not enabled; create the video with a JSON body and upload to the returned URL")
+	case errors.Is(err, usecase.ErrInlineUploadTooLarge):
+		Error(w, http.StatusRequestEntityTooLarge, "file_too_large", "File exceeds the inline upload limit; create the video with a JSON body and upload to the returned URL")
+	case errors.Is(err, usecase.ErrQuotaExceeded):
+		Error(w, http.StatusPaymentRequired, "quota_exceeded", "Storage quota is used up; delete videos to free space")
+	case errors.Is(err, usecase.ErrUploadExceedsQuota):
+		Error(w, http.StatusRequestEntityTooLarge, "quota_exceeded", "Original exceeds the remaining storage quota")
+	case errors.Is(err, usecase.ErrUploadMissing):
+		Error(w, http.StatusUnprocessableEntity, "upload_missing", "Original file has not been uploaded")
+	case errors.Is(err, usecase.ErrNoVideoStream):
+		Error(w, http.StatusUnprocessableEntity, "no_video_stream", "Original file has no video stream")
+	case errors.Is(err, usecase.ErrPlanningUnavailable):
+		Error(w, http.StatusNotImplemented, "dry_run_unavailable", "Dry runs are not available")
+	case errors.Is(err, usecase.ErrVideoProcessing):
+		Error(w, http.StatusConflict, "video_processing", "Video is being processed, retry once it completes")
+	case errors.Is(err, usecase.ErrVideoNotReady):
+		Error(w, http.StatusConflict, "video_not_ready", "Video has no output to regenerate")
+	case errors.Is(err, usecase.ErrUnknownVariant):
+		Error(w, http.StatusBadRequest, "invalid_variants", "Variants must be names from the ABR ladder")
+	case errors.Is(err, usecase.ErrUnknownABRProfile):
+		Error(w, http.StatusBadRequest, "invalid_abr_profile", "ABR profile is not configured")
+	case errors.Is(err, usecase.ErrInvalidCursor):
+		Error(w, http.StatusBadRequest, "invalid_cursor", "Cursor is malformed")
+	case errors.Is(err, usecase.ErrInvalidSearchQuery):
+		Error(w, http.StatusBadRequest, "invalid_query", "Query must be 1 to 200 characters")
 	default:
-		Error(w, http.StatusInternalServerError, "internal_error", "An unexpected error occurred")
+		ServiceError(w, err)
 	}
 }
 
+func toProcessPlanResponse(p *usecase.TranscodePlan) ProcessPlanResponse {
+	resp := ProcessPlanResponse{
+		SourceWidth:              p.Source.Width,
+		SourceHeight:             p.Source.Height,
+		ABRProfile:               p.ABRProfile,
+		Variants:                 make([]PlannedVariantResponse, 0, len(p.Variants)),
+		EstimatedDurationSeconds: p.EstimatedDuration.Seconds(),
+		EstimatedOutputBytes:     p.EstimatedOutputBytes,
+	}
+	for _, v := range p.Variants {
+		resp.Variants = append(resp.Variants, toPlannedVariantResponse(v))
+	}
+	if p.Preview != nil {
+		preview := toPlannedVariantResponse(*p.Preview)
+		resp.Preview = &preview
+	}
+	if p.Estimate != nil {
+		resp.Estimate = &TranscodeEstimateResponse{
+			ProcessingSeconds: p.Estimate.ProcessingTime.Seconds(),
+			OutputBytes:       p.Estimate.OutputBytes,
+			Samples:           p.Estimate.Samples,
+		}
+	}
+	return resp
+}
+
+func toPlannedVariantResponse(v usecase.PlannedVariant) PlannedVariantResponse {
+	return PlannedVariantResponse{
+		Name:           v.Variant.Name,
+		Width:          v.Variant.Width(),
+		Height:         v.Variant.Height,
+		Bitrate:        v.Variant.Bitrate,
+		EstimatedBytes: v.EstimatedBytes,
+	}
+}
+
+func toVideoResponses(videos []*model.Video, callerID uuid.UUID, entitlementsEnforced bool) []VideoResponse {
+	items := make([]VideoResponse, 0, len(videos))
+	for _, v := range videos {
+		items = append(items, toViewerVideoResponse(v, callerID, entitlementsEnforced))
+	}
+	return items
+}
+
+// toViewerVideoResponse is toVideoResponse for responses other users than the owner may
+// receive: the playback URLs are left out unless callerID may see them.
+func toViewerVideoResponse(v *model.Video, callerID uuid.UUID, entitlementsEnforced bool) VideoResponse {
+	resp := toVideoResponse(v)
+	if !v.PlaybackURLsVisibleTo(callerID, entitlementsEnforced) {
+		resp.HLSURL = ""
+		resp.DashURL = ""
+	}
+	return resp
+}
+
 func toVideoResponse(v *model.Video) VideoResponse {
-	return VideoResponse{
-		ID:          v.ID.String(),
-		UserID:      v.UserID.String(),
-		Title:       v.Title,
-		Status:      v.Status.String(),
-		OriginalURL: v.OriginalURL,
-		HLSURL:      v.HLSURL,
-		CreatedAt:   v.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   v.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	resp := VideoResponse{
+		ID:             v.ID.String(),
+		SortableID:     v.SortableID,
+		ShareSlug:      v.ShareSlug,
+		TitleSlug:      v.TitleSlug,
+		UserID:         v.UserID.String(),
+		Title:          v.Title,
+		Description:    v.Description,
+		Tags:           v.Tags,
+		Visibility:     v.Visibility.String(),
+		Status:         v.Status.String(),
+		OriginalURL:    v.OriginalURL,
+		OriginalSize:   v.OriginalSize,
+		OriginalETag:   v.OriginalETag,
+		HLSURL:         v.HLSURL,
+		Stale:          v.Stale,
+		DashURL:        v.DashURL,
+		PreviewSeconds: v.PreviewSeconds,
+		PreviewURL:     v.PreviewURL,
+		FailureCode:    string(v.FailureCode),
+		FailureReason:  v.FailureReason,
+		CreatedAt:      v.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      v.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if !v.ExpiresAt.IsZero() {
+		resp.ExpiresAt = v.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if v.ThumbnailPrefix != "" {
+		resp.Thumbnails = &ThumbnailsResponse{
+			Small:  v.ThumbnailKey("small"),
+			Medium: v.ThumbnailKey("medium"),
+			Large:  v.ThumbnailKey("large"),
+		}
+	}
+	if !v.Source.IsZero() {
+		resp.Source = &SourceResponse{
+			DurationSeconds: v.Source.Duration.Seconds(),
+			Width:           v.Source.Width,
+			Height:          v.Source.Height,
+			Codec:           v.Source.Codec,
+			Bitrate:         v.Source.Bitrate,
+			FrameRate:       v.Source.FrameRate,
+		}
 	}
+	return resp
 }
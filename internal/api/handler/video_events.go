@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// eventsHeartbeat is how often an idle event stream gets a comment line, so proxies
+// and load balancers do not close it during a long transcode.
+const eventsHeartbeat = 15 * time.Second
+
+// VideoEventResponse is the data of a status or progress event.
+type VideoEventResponse struct {
+	VideoID string `json:"video_id"`
+	// Status is set on "status" events.
+	Status string `json:"status,omitempty"`
+	// TranscodeProgress is set on "progress" events.
+	TranscodeProgress *int `json:"transcode_progress,omitempty"`
+}
+
+// VideoEventsHandler streams live video status and transcode progress.
+type VideoEventsHandler struct {
+	watcher   usecase.VideoWatcher
+	heartbeat time.Duration
+}
+
+// NewVideoEventsHandler creates a new VideoEventsHandler.
+func NewVideoEventsHandler(watcher usecase.VideoWatcher) *VideoEventsHandler {
+	return &VideoEventsHandler{
+		watcher:   watcher,
+		heartbeat: eventsHeartbeat,
+	}
+}
+
+// Stream handles GET /v1/videos/{id}/events as a Server-Sent Events stream.
+// The first event is the current status; the stream ends after READY, FAILED or DELETED,
+// so clients should close their EventSource then instead of letting it reconnect.
+func (h *VideoEventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	callerID, _ := middleware.GetUserID(r.Context())
+	events, err := h.watcher.Watch(r.Context(), videoID, callerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrVideoNotFound) {
+			Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+			return
+		}
+		ServiceError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the server's write timeout; ignore writers that cannot extend it
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Stop nginx from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			err = writeVideoEvent(w, event)
+		case <-heartbeat.C:
+			_, err = io.WriteString(w, ": keep-alive\n\n")
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		// The client went away; the request context cancels the watch
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeVideoEvent(w io.Writer, event cache.VideoEvent) error {
+	data := VideoEventResponse{VideoID: event.VideoID.String()}
+	switch event.Type {
+	case cache.VideoEventStatus:
+		data.Status = string(event.Status)
+	case cache.VideoEventProgress:
+		progress := event.Progress
+		data.TranscodeProgress = &progress
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+	return err
+}
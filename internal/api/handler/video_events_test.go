@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// Mock VideoWatcher
+
+type mockVideoWatcher struct {
+	watchFn func(ctx context.Context, videoID, callerID uuid.UUID) (<-chan cache.VideoEvent, error)
+}
+
+func (m *mockVideoWatcher) Watch(ctx context.Context, videoID, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+	return m.watchFn(ctx, videoID, callerID)
+}
+
+func serveVideoEvents(h *VideoEventsHandler, ctx context.Context, id string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Get("/v1/videos/{id}/events", h.Stream)
+	req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+id+"/events", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestVideoEventsHandler_Stream(t *testing.T) {
+	videoID := uuid.New()
+	watcher := &mockVideoWatcher{
+		watchFn: func(ctx context.Context, id, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+			events := make(chan cache.VideoEvent, 3)
+			events <- cache.VideoEvent{Type: cache.VideoEventStatus, VideoID: id, Status: model.StatusProcessing}
+			events <- cache.VideoEvent{Type: cache.VideoEventProgress, VideoID: id, Progress: 0}
+			events <- cache.VideoEvent{Type: cache.VideoEventStatus, VideoID: id, Status: model.StatusReady}
+			close(events)
+			return events, nil
+		},
+	}
+
+	rec := serveVideoEvents(NewVideoEventsHandler(watcher), context.Background(), videoID.String())
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type: got %q", got)
+	}
+	if !rec.Flushed {
+		t.Error("expected the stream to be flushed")
+	}
+
+	id := videoID.String()
+	want := "event: status\ndata: {\"video_id\":\"" + id + "\",\"status\":\"PROCESSING\"}\n\n" +
+		"event: progress\ndata: {\"video_id\":\"" + id + "\",\"transcode_progress\":0}\n\n" +
+		"event: status\ndata: {\"video_id\":\"" + id + "\",\"status\":\"READY\"}\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestVideoEventsHandler_Stream_Heartbeat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher := &mockVideoWatcher{
+		watchFn: func(ctx context.Context, id, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+			events := make(chan cache.VideoEvent)
+			go func() {
+				<-ctx.Done()
+				close(events)
+			}()
+			return events, nil
+		},
+	}
+	h := NewVideoEventsHandler(watcher)
+	h.heartbeat = time.Millisecond
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	rec := serveVideoEvents(h, ctx, uuid.New().String())
+
+	if !strings.HasPrefix(rec.Body.String(), ": keep-alive\n\n") {
+		t.Errorf("expected heartbeat comments, got %q", rec.Body.String())
+	}
+}
+
+func TestVideoEventsHandler_Stream_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		watchErr   error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid id", id: "not-a-uuid", wantStatus: http.StatusBadRequest, wantCode: "invalid_video_id"},
+		{name: "not found", id: uuid.NewString(), watchErr: repository.ErrVideoNotFound, wantStatus: http.StatusNotFound, wantCode: "video_not_found"},
+		{name: "watch fails", id: uuid.NewString(), watchErr: errors.New("redis down"), wantStatus: http.StatusInternalServerError, wantCode: "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watcher := &mockVideoWatcher{
+				watchFn: func(ctx context.Context, id, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+					return nil, tt.watchErr
+				},
+			}
+
+			rec := serveVideoEvents(NewVideoEventsHandler(watcher), context.Background(), tt.id)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error %q in body %q", tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
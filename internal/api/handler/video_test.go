@@ -4,24 +4,49 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/transcoder"
 	"github.com/hszk-dev/gostream/internal/usecase"
 )
 
 // Mock VideoService
 
 type mockVideoService struct {
-	createVideoFn    func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error)
-	triggerProcessFn func(ctx context.Context, videoID uuid.UUID) error
-	getVideoFn       func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	createVideoFn       func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error)
+	uploadVideoFn       func(ctx context.Context, input usecase.UploadVideoInput) (*model.Video, error)
+	completeUploadFn    func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	initiateMultipartFn func(ctx context.Context, videoID uuid.UUID, size int64) (*usecase.MultipartUpload, error)
+	completeMultipartFn func(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error)
+	triggerProcessFn    func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error
+	planProcessFn       func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) (*usecase.TranscodePlan, error)
+	retranscodeFn       func(ctx context.Context, videoID uuid.UUID, input usecase.RetranscodeInput) error
+	getVideoFn          func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	resolveShareSlugFn  func(ctx context.Context, slug string) (*model.Video, error)
+	resolveTitleSlugFn  func(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error)
+	transcodeProgressFn func(ctx context.Context, videoID uuid.UUID) (int, bool)
+	listVideosFn        func(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error)
+	searchVideosFn      func(ctx context.Context, input usecase.SearchVideosInput) (*usecase.ListVideosOutput, error)
+	deleteVideoFn       func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	setExpirationFn     func(ctx context.Context, videoID uuid.UUID, expiresAt time.Time) (*model.Video, error)
+	updateMetadataFn    func(ctx context.Context, videoID uuid.UUID, input usecase.UpdateMetadataInput) (*model.Video, error)
+	getOriginalURLFn    func(ctx context.Context, input usecase.OriginalURLInput) (*usecase.OriginalURL, error)
+	listStatusEventsFn  func(ctx context.Context, videoID, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error)
+	getStorageUsageFn   func(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error)
 }
 
 func (m *mockVideoService) CreateVideo(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error) {
@@ -31,13 +56,69 @@ func (m *mockVideoService) CreateVideo(ctx context.Context, input usecase.Create
 	return nil, nil
 }
 
-func (m *mockVideoService) TriggerProcess(ctx context.Context, videoID uuid.UUID) error {
+func (m *mockVideoService) UploadVideo(ctx context.Context, input usecase.UploadVideoInput) (*model.Video, error) {
+	if m.uploadVideoFn != nil {
+		return m.uploadVideoFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) CompleteUpload(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	if m.completeUploadFn != nil {
+		return m.completeUploadFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) InitiateMultipartUpload(ctx context.Context, videoID uuid.UUID, size int64) (*usecase.MultipartUpload, error) {
+	if m.initiateMultipartFn != nil {
+		return m.initiateMultipartFn(ctx, videoID, size)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) CompleteMultipartUpload(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error) {
+	if m.completeMultipartFn != nil {
+		return m.completeMultipartFn(ctx, videoID, uploadID, parts)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) TriggerProcess(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
 	if m.triggerProcessFn != nil {
-		return m.triggerProcessFn(ctx, videoID)
+		return m.triggerProcessFn(ctx, videoID, input)
 	}
 	return nil
 }
 
+func (m *mockVideoService) PlanProcess(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) (*usecase.TranscodePlan, error) {
+	if m.planProcessFn != nil {
+		return m.planProcessFn(ctx, videoID, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) Retranscode(ctx context.Context, videoID uuid.UUID, input usecase.RetranscodeInput) error {
+	if m.retranscodeFn != nil {
+		return m.retranscodeFn(ctx, videoID, input)
+	}
+	return nil
+}
+
+func (m *mockVideoService) ListStatusEvents(ctx context.Context, videoID, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+	if m.listStatusEventsFn != nil {
+		return m.listStatusEventsFn(ctx, videoID, callerID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error) {
+	if m.getStorageUsageFn != nil {
+		return m.getStorageUsageFn(ctx, userID)
+	}
+	return nil, nil
+}
+
 func (m *mockVideoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
 	if m.getVideoFn != nil {
 		return m.getVideoFn(ctx, videoID)
@@ -45,12 +126,76 @@ func (m *mockVideoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*mo
 	return nil, nil
 }
 
+func (m *mockVideoService) GetTranscodeProgress(ctx context.Context, videoID uuid.UUID) (int, bool) {
+	if m.transcodeProgressFn != nil {
+		return m.transcodeProgressFn(ctx, videoID)
+	}
+	return 0, false
+}
+
+func (m *mockVideoService) ResolveShareSlug(ctx context.Context, slug string) (*model.Video, error) {
+	if m.resolveShareSlugFn != nil {
+		return m.resolveShareSlugFn(ctx, slug)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) ResolveTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error) {
+	if m.resolveTitleSlugFn != nil {
+		return m.resolveTitleSlugFn(ctx, userID, slug)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) ListVideos(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error) {
+	if m.listVideosFn != nil {
+		return m.listVideosFn(ctx, input)
+	}
+	return &usecase.ListVideosOutput{}, nil
+}
+
+func (m *mockVideoService) SearchVideos(ctx context.Context, input usecase.SearchVideosInput) (*usecase.ListVideosOutput, error) {
+	if m.searchVideosFn != nil {
+		return m.searchVideosFn(ctx, input)
+	}
+	return &usecase.ListVideosOutput{}, nil
+}
+
+func (m *mockVideoService) DeleteVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	if m.deleteVideoFn != nil {
+		return m.deleteVideoFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) SetExpiration(ctx context.Context, videoID uuid.UUID, expiresAt time.Time) (*model.Video, error) {
+	if m.setExpirationFn != nil {
+		return m.setExpirationFn(ctx, videoID, expiresAt)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) UpdateMetadata(ctx context.Context, videoID uuid.UUID, input usecase.UpdateMetadataInput) (*model.Video, error) {
+	if m.updateMetadataFn != nil {
+		return m.updateMetadataFn(ctx, videoID, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) GetOriginalURL(ctx context.Context, input usecase.OriginalURLInput) (*usecase.OriginalURL, error) {
+	if m.getOriginalURLFn != nil {
+		return m.getOriginalURLFn(ctx, input)
+	}
+	return nil, nil
+}
+
 func TestVideoHandler_Create(t *testing.T) {
 	tests := []struct {
 		name           string
 		requestBody    interface{}
 		setupMock      func(m *mockVideoService)
 		wantStatusCode int
+		wantRetryAfter string
 		checkResponse  func(t *testing.T, body []byte)
 	}{
 		{
@@ -140,13 +285,55 @@ func TestVideoHandler_Create(t *testing.T) {
 			},
 			wantStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: "service error - invalid preview length",
+			requestBody: CreateVideoRequest{
+				UserID:         uuid.New().String(),
+				Title:          "Test Video",
+				FileName:       "video.mp4",
+				PreviewSeconds: 3600,
+			},
+			setupMock: func(m *mockVideoService) {
+				m.createVideoFn = func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error) {
+					if input.PreviewSeconds != 3600 {
+						t.Errorf("expected preview seconds 3600, got %d", input.PreviewSeconds)
+					}
+					return nil, model.ErrInvalidPreviewDuration
+				}
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "service error - upload URL rate limited",
+			requestBody: CreateVideoRequest{
+				UserID:   uuid.New().String(),
+				Title:    "Test Video",
+				FileName: "video.mp4",
+			},
+			setupMock: func(m *mockVideoService) {
+				m.createVideoFn = func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error) {
+					return nil, &usecase.URLRateLimitedError{Limit: 60, Window: time.Hour, RetryAfter: 90 * time.Second}
+				}
+			},
+			wantStatusCode: http.StatusTooManyRequests,
+			wantRetryAfter: "90",
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp ErrorResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != "rate_limited" {
+					t.Errorf("expected error rate_limited, got %q", resp.Error)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockVideoService{}
 			tt.setupMock(mock)
-			h := NewVideoHandler(mock)
+			h := NewVideoHandler(mock, false)
 
 			var body []byte
 			switch v := tt.requestBody.(type) {
@@ -169,6 +356,9 @@ func TestVideoHandler_Create(t *testing.T) {
 			if rec.Code != tt.wantStatusCode {
 				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
 			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("expected Retry-After %q, got %q", tt.wantRetryAfter, got)
+			}
 
 			if tt.checkResponse != nil {
 				tt.checkResponse(t, rec.Body.Bytes())
@@ -177,140 +367,1880 @@ func TestVideoHandler_Create(t *testing.T) {
 	}
 }
 
-func TestVideoHandler_TriggerProcess(t *testing.T) {
+func TestVideoHandler_Create_IdempotencyKey(t *testing.T) {
 	tests := []struct {
 		name           string
-		videoID        string
-		setupMock      func(m *mockVideoService)
+		key            string
+		multipart      bool
+		replayed       bool
+		serviceErr     error
 		wantStatusCode int
+		wantCode       string
+		wantReplayed   string
 	}{
 		{
-			name:    "successful trigger",
-			videoID: uuid.New().String(),
-			setupMock: func(m *mockVideoService) {
-				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID) error {
-					return nil
-				}
-			},
-			wantStatusCode: http.StatusAccepted,
+			name:           "first request",
+			key:            "order-42",
+			wantStatusCode: http.StatusCreated,
 		},
 		{
-			name:           "invalid video ID",
-			videoID:        "not-a-uuid",
-			setupMock:      func(m *mockVideoService) {},
+			name:           "replayed request",
+			key:            "order-42",
+			replayed:       true,
+			wantStatusCode: http.StatusCreated,
+			wantReplayed:   "true",
+		},
+		{
+			name:           "key reused for a different request",
+			key:            "order-42",
+			serviceErr:     usecase.ErrIdempotencyKeyReused,
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantCode:       "idempotency_key_reused",
+		},
+		{
+			name:           "key with spaces",
+			key:            "order 42",
 			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_idempotency_key",
 		},
 		{
-			name:    "video not found",
-			videoID: uuid.New().String(),
-			setupMock: func(m *mockVideoService) {
-				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID) error {
-					return repository.ErrVideoNotFound
-				}
-			},
-			wantStatusCode: http.StatusNotFound,
+			name:           "key too long",
+			key:            strings.Repeat("k", maxIdempotencyKeyLength+1),
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_idempotency_key",
 		},
 		{
-			name:    "video already completed",
-			videoID: uuid.New().String(),
-			setupMock: func(m *mockVideoService) {
-				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID) error {
-					return usecase.ErrVideoAlreadyCompleted
-				}
-			},
-			wantStatusCode: http.StatusConflict,
+			name:           "inline upload",
+			key:            "order-42",
+			multipart:      true,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "idempotency_key_unsupported",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mock := &mockVideoService{}
-			tt.setupMock(mock)
-			h := NewVideoHandler(mock)
-
-			r := chi.NewRouter()
-			r.Post("/v1/videos/{id}/process", h.TriggerProcess)
+			mock := &mockVideoService{
+				createVideoFn: func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error) {
+					if input.IdempotencyKey != tt.key {
+						t.Errorf("expected idempotency key %q, got %q", tt.key, input.IdempotencyKey)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					video := &model.Video{ID: uuid.New(), UserID: input.UserID, Title: input.Title, Status: model.StatusPendingUpload}
+					return &usecase.CreateVideoOutput{Video: video, UploadURL: "http://minio:9000/upload", Replayed: tt.replayed}, nil
+				},
+				uploadVideoFn: func(ctx context.Context, input usecase.UploadVideoInput) (*model.Video, error) {
+					t.Error("inline upload should not run with an idempotency key")
+					return nil, nil
+				},
+			}
 
-			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+tt.videoID+"/process", nil)
+			var req *http.Request
+			if tt.multipart {
+				var body bytes.Buffer
+				mw := multipart.NewWriter(&body)
+				mw.WriteField("user_id", uuid.New().String())
+				mw.WriteField("title", "Clip")
+				mw.Close()
+				req = httptest.NewRequest(http.MethodPost, "/v1/videos", &body)
+				req.Header.Set("Content-Type", mw.FormDataContentType())
+			} else {
+				body, _ := json.Marshal(CreateVideoRequest{UserID: uuid.New().String(), Title: "Clip", FileName: "clip.mp4"})
+				req = httptest.NewRequest(http.MethodPost, "/v1/videos", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req.Header.Set("Idempotency-Key", tt.key)
 			rec := httptest.NewRecorder()
+			NewVideoHandler(mock, false).Create(rec, req)
 
-			r.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if got := rec.Header().Get("Idempotent-Replayed"); got != tt.wantReplayed {
+				t.Errorf("expected Idempotent-Replayed %q, got %q", tt.wantReplayed, got)
+			}
+		})
+	}
+}
+
+// keyUser authenticates every API key as one user.
+type keyUser uuid.UUID
+
+func (u keyUser) AuthenticateAPIKey(ctx context.Context, key string) (uuid.UUID, error) {
+	return uuid.UUID(u), nil
+}
+
+func TestVideoHandler_Create_APIKey(t *testing.T) {
+	caller := uuid.New()
+
+	tests := []struct {
+		name           string
+		userID         string
+		wantStatusCode int
+	}{
+		{name: "user ID defaults to the key's user", wantStatusCode: http.StatusCreated},
+		{name: "key's own user", userID: caller.String(), wantStatusCode: http.StatusCreated},
+		{name: "another user", userID: uuid.New().String(), wantStatusCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				createVideoFn: func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error) {
+					if input.UserID != caller {
+						t.Errorf("expected video for %v, got %v", caller, input.UserID)
+					}
+					video := &model.Video{ID: uuid.New(), UserID: input.UserID, Title: input.Title, Status: model.StatusPendingUpload}
+					return &usecase.CreateVideoOutput{Video: video, UploadURL: "http://minio:9000/upload"}, nil
+				},
+			}
+			h := middleware.APIKey(keyUser(caller))(http.HandlerFunc(NewVideoHandler(mock, false).Create))
+
+			body, _ := json.Marshal(CreateVideoRequest{UserID: tt.userID, Title: "Clip", FileName: "clip.mp4"})
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer gsk_test")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
 
 			if rec.Code != tt.wantStatusCode {
-				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
 			}
 		})
 	}
 }
 
-func TestVideoHandler_Get(t *testing.T) {
+func TestVideoHandler_Create_Inline(t *testing.T) {
+	userID := uuid.New()
+
 	tests := []struct {
 		name           string
-		videoID        string
-		setupMock      func(m *mockVideoService)
+		fields         [][2]string
+		withFile       bool
+		serviceErr     error
 		wantStatusCode int
-		checkResponse  func(t *testing.T, body []byte)
+		wantCode       string
 	}{
 		{
-			name:    "successful get",
-			videoID: uuid.New().String(),
-			setupMock: func(m *mockVideoService) {
-				m.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
-					return &model.Video{
-						ID:        videoID,
-						UserID:    uuid.New(),
-						Title:     "Test Video",
-						Status:    model.StatusReady,
-						HLSURL:    "hls/video-id/master.m3u8",
-						CreatedAt: time.Now(),
-						UpdatedAt: time.Now(),
-					}, nil
+			name:           "uploads and processes",
+			fields:         [][2]string{{"user_id", userID.String()}, {"title", "Clip"}, {"process", "true"}, {"abr_profile", "screen"}},
+			withFile:       true,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "missing file",
+			fields:         [][2]string{{"user_id", userID.String()}, {"title", "Clip"}},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "missing_file",
+		},
+		{
+			name:           "invalid user ID",
+			fields:         [][2]string{{"user_id", "not-a-uuid"}, {"title", "Clip"}},
+			withFile:       true,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_user_id",
+		},
+		{
+			name:           "invalid process flag",
+			fields:         [][2]string{{"user_id", userID.String()}, {"title", "Clip"}, {"process", "sometimes"}},
+			withFile:       true,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_process",
+		},
+		{
+			name:           "file too large",
+			fields:         [][2]string{{"user_id", userID.String()}, {"title", "Clip"}},
+			withFile:       true,
+			serviceErr:     usecase.ErrInlineUploadTooLarge,
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+			wantCode:       "file_too_large",
+		},
+		{
+			name:           "disabled",
+			fields:         [][2]string{{"user_id", userID.String()}, {"title", "Clip"}},
+			withFile:       true,
+			serviceErr:     usecase.ErrInlineUploadDisabled,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+			wantCode:       "inline_upload_disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			for _, f := range tt.fields {
+				if err := mw.WriteField(f[0], f[1]); err != nil {
+					t.Fatal(err)
 				}
-			},
-			wantStatusCode: http.StatusOK,
-			checkResponse: func(t *testing.T, body []byte) {
+			}
+			if tt.withFile {
+				part, err := mw.CreateFormFile("file", "clip.mp4")
+				if err != nil {
+					t.Fatal(err)
+				}
+				part.Write([]byte("tiny clip"))
+			}
+			mw.Close()
+
+			mock := &mockVideoService{
+				uploadVideoFn: func(ctx context.Context, input usecase.UploadVideoInput) (*model.Video, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					content, _ := io.ReadAll(input.Content)
+					if string(content) != "tiny clip" || input.FileName != "clip.mp4" || !input.Process || input.ABRProfile != "screen" {
+						t.Errorf("unexpected input: %+v, content %q", input, content)
+					}
+					return &model.Video{ID: uuid.New(), UserID: input.UserID, Title: input.Title, Status: model.StatusProcessing}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos", &body)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			rec := httptest.NewRecorder()
+			NewVideoHandler(mock, false).Create(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+			if rec.Code == http.StatusCreated {
 				var resp VideoResponse
-				if err := json.Unmarshal(body, &resp); err != nil {
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
 					t.Fatalf("failed to unmarshal response: %v", err)
 				}
-				if resp.Status != "READY" {
-					t.Errorf("expected status READY, got %s", resp.Status)
-				}
-				if resp.HLSURL == "" {
-					t.Error("expected HLS URL to be non-empty")
+				if resp.Status != "PROCESSING" {
+					t.Errorf("expected status PROCESSING, got %s", resp.Status)
 				}
-			},
+			}
+		})
+	}
+}
+
+func TestVideoHandler_CompleteUpload(t *testing.T) {
+	tests := []struct {
+		name           string
+		videoID        string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "upload confirmed",
+			videoID:        uuid.New().String(),
+			wantStatusCode: http.StatusOK,
 		},
 		{
 			name:           "invalid video ID",
 			videoID:        "not-a-uuid",
-			setupMock:      func(m *mockVideoService) {},
 			wantStatusCode: http.StatusBadRequest,
 		},
 		{
-			name:    "video not found",
-			videoID: uuid.New().String(),
-			setupMock: func(m *mockVideoService) {
-				m.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
-					return nil, repository.ErrVideoNotFound
-				}
-			},
+			name:           "video not found",
+			videoID:        uuid.New().String(),
+			serviceErr:     repository.ErrVideoNotFound,
 			wantStatusCode: http.StatusNotFound,
 		},
+		{
+			name:           "original not uploaded",
+			videoID:        uuid.New().String(),
+			serviceErr:     usecase.ErrUploadMissing,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "original exceeds quota",
+			videoID:        uuid.New().String(),
+			serviceErr:     usecase.ErrUploadExceedsQuota,
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "quota used up",
+			videoID:        uuid.New().String(),
+			serviceErr:     usecase.ErrQuotaExceeded,
+			wantStatusCode: http.StatusPaymentRequired,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mock := &mockVideoService{}
-			tt.setupMock(mock)
-			h := NewVideoHandler(mock)
+			mock := &mockVideoService{
+				completeUploadFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &model.Video{
+						ID:           videoID,
+						UserID:       uuid.New(),
+						Title:        "Test Video",
+						Status:       model.StatusUploaded,
+						OriginalSize: 1024,
+						OriginalETag: "abc123",
+					}, nil
+				},
+			}
 
 			r := chi.NewRouter()
-			r.Get("/v1/videos/{id}", h.Get)
+			r.Post("/v1/videos/{id}/upload-complete", NewVideoHandler(mock, false).CompleteUpload)
 
-			req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+tt.videoID, nil)
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+tt.videoID+"/upload-complete", nil)
 			rec := httptest.NewRecorder()
-
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp VideoResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Status != "UPLOADED" || resp.OriginalSize != 1024 || resp.OriginalETag != "abc123" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_InitiateUpload(t *testing.T) {
+	tests := []struct {
+		name           string
+		videoID        string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "upload initiated",
+			videoID:        uuid.New().String(),
+			body:           `{"size":134217729}`,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			body:           `{"size":1}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			videoID:        uuid.New().String(),
+			body:           `{`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid size",
+			videoID:        uuid.New().String(),
+			body:           `{"size":0}`,
+			serviceErr:     usecase.ErrInvalidUploadSize,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "video already uploaded",
+			videoID:        uuid.New().String(),
+			body:           `{"size":1}`,
+			serviceErr:     usecase.ErrUploadClosed,
+			wantStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				initiateMultipartFn: func(ctx context.Context, videoID uuid.UUID, size int64) (*usecase.MultipartUpload, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &usecase.MultipartUpload{
+						UploadID:  "upload-1",
+						PartSize:  64 << 20,
+						PartURLs:  []string{"https://storage/part1", "https://storage/part2", "https://storage/part3"},
+						ExpiresAt: time.Now().Add(6 * time.Hour),
+					}, nil
+				},
+			}
+
+			r := chi.NewRouter()
+			r.Post("/v1/videos/{id}/uploads", NewVideoHandler(mock, false).InitiateUpload)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+tt.videoID+"/uploads", bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if rec.Code != http.StatusCreated {
+				return
+			}
+
+			var resp InitiateUploadResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.UploadID != "upload-1" || len(resp.Parts) != 3 || resp.Parts[2].PartNumber != 3 || resp.Parts[2].URL != "https://storage/part3" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_CompleteMultipartUpload(t *testing.T) {
+	tests := []struct {
+		name           string
+		videoID        string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "upload assembled",
+			videoID:        uuid.New().String(),
+			body:           `{"parts":[{"part_number":1,"etag":"\"a\""},{"part_number":2,"etag":"\"b\""}]}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			body:           `{"parts":[]}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid parts",
+			videoID:        uuid.New().String(),
+			body:           `{"parts":[]}`,
+			serviceErr:     usecase.ErrInvalidUploadParts,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "parts rejected by storage",
+			videoID:        uuid.New().String(),
+			body:           `{"parts":[{"part_number":1,"etag":"x"}]}`,
+			serviceErr:     repository.ErrInvalidMultipartParts,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown upload",
+			videoID:        uuid.New().String(),
+			body:           `{"parts":[{"part_number":1,"etag":"a"}]}`,
+			serviceErr:     repository.ErrMultipartUploadNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				completeMultipartFn: func(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if uploadID != "upload-1" || len(parts) != 2 || parts[1].ETag != `"b"` {
+						t.Errorf("completed upload %q with parts %+v", uploadID, parts)
+					}
+					return &model.Video{
+						ID:           videoID,
+						UserID:       uuid.New(),
+						Title:        "Test Video",
+						Status:       model.StatusUploaded,
+						OriginalSize: 8 << 30,
+					}, nil
+				},
+			}
+
+			r := chi.NewRouter()
+			r.Post("/v1/videos/{id}/uploads/{uploadID}/complete", NewVideoHandler(mock, false).CompleteMultipartUpload)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+tt.videoID+"/uploads/upload-1/complete", bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp VideoResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Status != "UPLOADED" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_TriggerProcess(t *testing.T) {
+	tests := []struct {
+		name           string
+		videoID        string
+		body           string
+		setupMock      func(m *mockVideoService)
+		wantStatusCode int
+	}{
+		{
+			name:    "successful trigger",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
+					return nil
+				}
+			},
+			wantStatusCode: http.StatusAccepted,
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			setupMock:      func(m *mockVideoService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "video not found",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
+					return repository.ErrVideoNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:    "video already completed",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
+					return usecase.ErrVideoAlreadyCompleted
+				}
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:    "ABR profile is passed through",
+			videoID: uuid.New().String(),
+			body:    `{"abr_profile": "screen"}`,
+			setupMock: func(m *mockVideoService) {
+				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
+					if input.ABRProfile != "screen" {
+						return errors.New("unexpected ABR profile")
+					}
+					return nil
+				}
+			},
+			wantStatusCode: http.StatusAccepted,
+		},
+		{
+			name:    "unknown ABR profile",
+			videoID: uuid.New().String(),
+			body:    `{"abr_profile": "film"}`,
+			setupMock: func(m *mockVideoService) {
+				m.triggerProcessFn = func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
+					return usecase.ErrUnknownABRProfile
+				}
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed body",
+			videoID:        uuid.New().String(),
+			body:           `{"abr_profile":`,
+			setupMock:      func(m *mockVideoService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{}
+			tt.setupMock(mock)
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Post("/v1/videos/{id}/process", h.TriggerProcess)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+tt.videoID+"/process", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_TriggerProcess_DryRun(t *testing.T) {
+	plan := &usecase.TranscodePlan{
+		Source: transcoder.ProbeResult{Duration: 10 * time.Second, Width: 1920, Height: 1080},
+		Variants: []usecase.PlannedVariant{
+			{Variant: transcoder.Variant{Name: "720p", Height: 720, Bitrate: 2500000}, EstimatedBytes: 3125000},
+		},
+		Preview:              &usecase.PlannedVariant{Variant: transcoder.Variant{Name: "preview", Height: 480, Bitrate: 1200000}, EstimatedBytes: 750000},
+		EstimatedDuration:    10 * time.Second,
+		EstimatedOutputBytes: 3875000,
+		Estimate:             &model.TranscodeEstimate{ProcessingTime: 90 * time.Second, OutputBytes: 3000000, Samples: 25},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		planErr        error
+		wantStatusCode int
+		wantPlanned    bool
+		wantTriggered  bool
+	}{
+		{name: "dry run returns the plan", query: "?dry_run=true", wantStatusCode: http.StatusOK, wantPlanned: true},
+		{name: "dry_run=false triggers", query: "?dry_run=false", wantStatusCode: http.StatusAccepted, wantTriggered: true},
+		{name: "invalid dry_run", query: "?dry_run=maybe", wantStatusCode: http.StatusBadRequest},
+		{name: "upload missing", query: "?dry_run=1", planErr: usecase.ErrUploadMissing, wantStatusCode: http.StatusUnprocessableEntity, wantPlanned: true},
+		{name: "no video stream", query: "?dry_run=1", planErr: usecase.ErrNoVideoStream, wantStatusCode: http.StatusUnprocessableEntity, wantPlanned: true},
+		{name: "planning unavailable", query: "?dry_run=1", planErr: usecase.ErrPlanningUnavailable, wantStatusCode: http.StatusNotImplemented, wantPlanned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var planned, triggered bool
+			mock := &mockVideoService{
+				planProcessFn: func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) (*usecase.TranscodePlan, error) {
+					planned = true
+					if tt.planErr != nil {
+						return nil, tt.planErr
+					}
+					return plan, nil
+				},
+				triggerProcessFn: func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
+					triggered = true
+					return nil
+				},
+			}
+
+			r := chi.NewRouter()
+			r.Post("/v1/videos/{id}/process", NewVideoHandler(mock, false).TriggerProcess)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+uuid.New().String()+"/process"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if planned != tt.wantPlanned || triggered != tt.wantTriggered {
+				t.Errorf("planned = %v, triggered = %v; want %v, %v", planned, triggered, tt.wantPlanned, tt.wantTriggered)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp ProcessPlanResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(resp.Variants) != 1 || resp.Variants[0].Name != "720p" || resp.Variants[0].Width != 1280 {
+				t.Errorf("unexpected variants: %+v", resp.Variants)
+			}
+			if resp.Preview == nil || resp.Preview.EstimatedBytes != 750000 {
+				t.Errorf("unexpected preview: %+v", resp.Preview)
+			}
+			if resp.EstimatedDurationSeconds != 10 || resp.EstimatedOutputBytes != 3875000 || resp.SourceHeight != 1080 {
+				t.Errorf("unexpected plan: %+v", resp)
+			}
+			if resp.Estimate == nil || resp.Estimate.ProcessingSeconds != 90 || resp.Estimate.OutputBytes != 3000000 || resp.Estimate.Samples != 25 {
+				t.Errorf("unexpected estimate: %+v", resp.Estimate)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_Retranscode(t *testing.T) {
+	tests := []struct {
+		name           string
+		videoID        string
+		body           string
+		serviceErr     error
+		wantStatusCode int
+		wantRetryAfter string
+		wantVariants   []string
+	}{
+		{
+			name:           "accepted",
+			videoID:        uuid.New().String(),
+			wantStatusCode: http.StatusAccepted,
+		},
+		{
+			name:           "variant override",
+			videoID:        uuid.New().String(),
+			body:           `{"variants":["720p","360p"]}`,
+			wantStatusCode: http.StatusAccepted,
+			wantVariants:   []string{"720p", "360p"},
+		},
+		{
+			name:           "invalid JSON body",
+			videoID:        uuid.New().String(),
+			body:           `{"variants":`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown variant",
+			videoID:        uuid.New().String(),
+			body:           `{"variants":["4k"]}`,
+			serviceErr:     usecase.ErrUnknownVariant,
+			wantStatusCode: http.StatusBadRequest,
+			wantVariants:   []string{"4k"},
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "video not found",
+			videoID:        uuid.New().String(),
+			serviceErr:     repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "video not ready",
+			videoID:        uuid.New().String(),
+			serviceErr:     usecase.ErrVideoNotReady,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "shed by admission control",
+			videoID:        uuid.New().String(),
+			serviceErr:     &usecase.OverloadedError{Reason: "transcode backlog 900 exceeds 500", RetryAfter: 1500 * time.Millisecond},
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantRetryAfter: "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotVariants []string
+			mock := &mockVideoService{
+				retranscodeFn: func(ctx context.Context, videoID uuid.UUID, input usecase.RetranscodeInput) error {
+					gotVariants = input.Variants
+					return tt.serviceErr
+				},
+			}
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Post("/v1/videos/{id}/retranscode", h.Retranscode)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/videos/"+tt.videoID+"/retranscode", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("expected Retry-After %q, got %q", tt.wantRetryAfter, got)
+			}
+			if !slices.Equal(gotVariants, tt.wantVariants) {
+				t.Errorf("expected variants %v, got %v", tt.wantVariants, gotVariants)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		videoID        string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "accepted",
+			videoID:        uuid.New().String(),
+			wantStatusCode: http.StatusAccepted,
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "video not found",
+			videoID:        uuid.New().String(),
+			serviceErr:     repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "video processing",
+			videoID:        uuid.New().String(),
+			serviceErr:     usecase.ErrVideoProcessing,
+			wantStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				deleteVideoFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &model.Video{ID: videoID, UserID: uuid.New(), Title: "Test Video", Status: model.StatusDeleted}, nil
+				},
+			}
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Delete("/v1/videos/{id}", h.Delete)
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/videos/"+tt.videoID, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if rec.Code == http.StatusAccepted {
+				var resp VideoResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Status != string(model.StatusDeleted) {
+					t.Errorf("expected status %s, got %s", model.StatusDeleted, resp.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestVideoHandler_Update(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	private := model.VisibilityPrivate
+	hidden := model.Visibility("hidden")
+
+	tests := []struct {
+		name           string
+		videoID        string
+		body           string
+		serviceErr     error
+		wantInput      usecase.UpdateMetadataInput
+		wantStatusCode int
+		wantErrorCode  string
+	}{
+		{
+			name:           "updates every field",
+			videoID:        uuid.New().String(),
+			body:           `{"title":"Renamed","description":"About it","tags":["go"]}`,
+			wantInput:      usecase.UpdateMetadataInput{Title: ptr("Renamed"), Description: ptr("About it"), Tags: &[]string{"go"}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "empty values clear fields",
+			videoID:        uuid.New().String(),
+			body:           `{"description":"","tags":[]}`,
+			wantInput:      usecase.UpdateMetadataInput{Description: ptr(""), Tags: &[]string{}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "makes the video private",
+			videoID:        uuid.New().String(),
+			body:           `{"visibility":"private"}`,
+			wantInput:      usecase.UpdateMetadataInput{Visibility: &private},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid visibility",
+			videoID:        uuid.New().String(),
+			body:           `{"visibility":"hidden"}`,
+			wantInput:      usecase.UpdateMetadataInput{Visibility: &hidden},
+			serviceErr:     model.ErrInvalidVisibility,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_visibility",
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			body:           `{"title":"Renamed"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_video_id",
+		},
+		{
+			name:           "malformed JSON",
+			videoID:        uuid.New().String(),
+			body:           `{"title":`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_request",
+		},
+		{
+			name:           "no fields",
+			videoID:        uuid.New().String(),
+			body:           `{}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_request",
+		},
+		{
+			name:           "empty title",
+			videoID:        uuid.New().String(),
+			body:           `{"title":""}`,
+			serviceErr:     model.ErrEmptyTitle,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_title",
+		},
+		{
+			name:           "description too long",
+			videoID:        uuid.New().String(),
+			body:           `{"description":"long"}`,
+			serviceErr:     model.ErrDescriptionTooLong,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_description",
+		},
+		{
+			name:           "invalid tag",
+			videoID:        uuid.New().String(),
+			body:           `{"tags":["a,b"]}`,
+			serviceErr:     model.ErrInvalidTag,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_tags",
+		},
+		{
+			name:           "too many tags",
+			videoID:        uuid.New().String(),
+			body:           `{"tags":["a"]}`,
+			serviceErr:     model.ErrTooManyTags,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "invalid_tags",
+		},
+		{
+			name:           "video not found",
+			videoID:        uuid.New().String(),
+			body:           `{"title":"Renamed"}`,
+			serviceErr:     repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+			wantErrorCode:  "video_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				updateMetadataFn: func(ctx context.Context, videoID uuid.UUID, input usecase.UpdateMetadataInput) (*model.Video, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if !reflect.DeepEqual(input, tt.wantInput) {
+						t.Errorf("input = %+v, want %+v", input, tt.wantInput)
+					}
+					video := &model.Video{ID: videoID, UserID: uuid.New(), Title: "Promo", Status: model.StatusReady, Tags: []string{"go"}}
+					if input.Description != nil {
+						video.Description = *input.Description
+					}
+					return video, nil
+				},
+			}
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Patch("/v1/videos/{id}", h.Update)
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/videos/"+tt.videoID, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantErrorCode {
+					t.Errorf("error = %q, want %q", resp.Error, tt.wantErrorCode)
+				}
+				return
+			}
+
+			var resp VideoResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if !slices.Equal(resp.Tags, []string{"go"}) {
+				t.Errorf("tags = %q, want [go]", resp.Tags)
+			}
+			if tt.wantInput.Description != nil && resp.Description != *tt.wantInput.Description {
+				t.Errorf("description = %q, want %q", resp.Description, *tt.wantInput.Description)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_SetExpiration(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		videoID        string
+		body           string
+		serviceErr     error
+		wantExpiresAt  time.Time
+		wantStatusCode int
+	}{
+		{
+			name:           "schedules expiry",
+			videoID:        uuid.New().String(),
+			body:           `{"expires_at":"2030-01-02T03:04:05Z"}`,
+			wantExpiresAt:  expiresAt,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "null clears expiry",
+			videoID:        uuid.New().String(),
+			body:           `{"expires_at":null}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			body:           `{}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed time",
+			videoID:        uuid.New().String(),
+			body:           `{"expires_at":"tomorrow"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "time in the past",
+			videoID:        uuid.New().String(),
+			body:           `{"expires_at":"2020-01-01T00:00:00Z"}`,
+			serviceErr:     model.ErrExpiryInPast,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "already expired",
+			videoID:        uuid.New().String(),
+			body:           `{"expires_at":"2030-01-02T03:04:05Z"}`,
+			serviceErr:     usecase.ErrVideoExpired,
+			wantStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				setExpirationFn: func(ctx context.Context, videoID uuid.UUID, got time.Time) (*model.Video, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if !got.Equal(tt.wantExpiresAt) {
+						t.Errorf("expiresAt = %v, want %v", got, tt.wantExpiresAt)
+					}
+					return &model.Video{ID: videoID, UserID: uuid.New(), Title: "Promo", Status: model.StatusReady, ExpiresAt: got}, nil
+				},
+			}
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Put("/v1/videos/{id}/expiration", h.SetExpiration)
+
+			req := httptest.NewRequest(http.MethodPut, "/v1/videos/"+tt.videoID+"/expiration", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK {
+				var resp VideoResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				want := ""
+				if !tt.wantExpiresAt.IsZero() {
+					want = "2030-01-02T03:04:05Z"
+				}
+				if resp.ExpiresAt != want {
+					t.Errorf("expires_at = %q, want %q", resp.ExpiresAt, want)
+				}
+			}
+		})
+	}
+}
+
+func TestVideoHandler_GetOriginalURL(t *testing.T) {
+	userID := uuid.New()
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		videoID        string
+		userHeader     string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{
+			name:           "owner gets URL",
+			videoID:        uuid.New().String(),
+			userHeader:     userID.String(),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			userHeader:     userID.String(),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "missing user",
+			videoID:        uuid.New().String(),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "not the owner",
+			videoID:        uuid.New().String(),
+			userHeader:     userID.String(),
+			serviceErr:     usecase.ErrNotVideoOwner,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "not uploaded yet",
+			videoID:        uuid.New().String(),
+			userHeader:     userID.String(),
+			serviceErr:     usecase.ErrOriginalNotUploaded,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "rate limited",
+			videoID:        uuid.New().String(),
+			userHeader:     userID.String(),
+			serviceErr:     &usecase.URLRateLimitedError{RetryAfter: time.Minute},
+			wantStatusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:           "video not found",
+			videoID:        uuid.New().String(),
+			userHeader:     userID.String(),
+			serviceErr:     repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				getOriginalURLFn: func(ctx context.Context, input usecase.OriginalURLInput) (*usecase.OriginalURL, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if input.UserID != userID || input.VideoID.String() != tt.videoID {
+						t.Errorf("input = %+v, want video %s of user %s", input, tt.videoID, userID)
+					}
+					return &usecase.OriginalURL{URL: "https://storage.example.com/original.mp4?sig=abc", ExpiresAt: expiresAt}, nil
+				},
+			}
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Get("/v1/videos/{id}/original-url", h.GetOriginalURL)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+tt.videoID+"/original-url", nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK {
+				var resp OriginalURLResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.URL != "https://storage.example.com/original.mp4?sig=abc" || resp.ExpiresAt != "2030-01-02T03:04:05Z" {
+					t.Errorf("response = %+v", resp)
+				}
+				if got := rec.Header().Get("Cache-Control"); got != "private, no-store" {
+					t.Errorf("Cache-Control = %q, want private, no-store", got)
+				}
+			}
+		})
+	}
+}
+
+func TestVideoHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		videoID        string
+		setupMock      func(m *mockVideoService)
+		wantStatusCode int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:    "successful get",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					return &model.Video{
+						ID:              videoID,
+						UserID:          uuid.New(),
+						Title:           "Test Video",
+						Status:          model.StatusReady,
+						HLSURL:          "hls/video-id/master.m3u8",
+						ThumbnailPrefix: "https://cdn.example.com/hls/video-id/thumbnails",
+						Source: model.SourceMetadata{
+							Duration:  90500 * time.Millisecond,
+							Width:     1920,
+							Height:    1080,
+							Codec:     "h264",
+							Bitrate:   5000000,
+							FrameRate: 30,
+						},
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp VideoResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Status != "READY" {
+					t.Errorf("expected status READY, got %s", resp.Status)
+				}
+				if resp.HLSURL == "" {
+					t.Error("expected HLS URL to be non-empty")
+				}
+				want := SourceResponse{DurationSeconds: 90.5, Width: 1920, Height: 1080, Codec: "h264", Bitrate: 5000000, FrameRate: 30}
+				if resp.Source == nil || *resp.Source != want {
+					t.Errorf("expected source %+v, got %+v", want, resp.Source)
+				}
+				wantThumbnails := ThumbnailsResponse{
+					Small:  "https://cdn.example.com/hls/video-id/thumbnails/small.jpg",
+					Medium: "https://cdn.example.com/hls/video-id/thumbnails/medium.jpg",
+					Large:  "https://cdn.example.com/hls/video-id/thumbnails/large.jpg",
+				}
+				if resp.Thumbnails == nil || *resp.Thumbnails != wantThumbnails {
+					t.Errorf("expected thumbnails %+v, got %+v", wantThumbnails, resp.Thumbnails)
+				}
+				if resp.TranscodeProgress != nil {
+					t.Errorf("expected no transcode progress for a READY video, got %d", *resp.TranscodeProgress)
+				}
+			},
+		},
+		{
+			name:    "failed video with failure reason",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					return &model.Video{
+						ID:            videoID,
+						UserID:        uuid.New(),
+						Status:        model.StatusFailed,
+						FailureCode:   model.FailureCodeEncodeFailed,
+						FailureReason: "ffmpeg execution failed: exit status 1: Conversion failed!",
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp VideoResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.FailureCode != "encode_failed" || resp.FailureReason != "ffmpeg execution failed: exit status 1: Conversion failed!" {
+					t.Errorf("unexpected failure: %q, %q", resp.FailureCode, resp.FailureReason)
+				}
+			},
+		},
+		{
+			name:    "processing video with progress",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusProcessing}, nil
+				}
+				m.transcodeProgressFn = func(ctx context.Context, videoID uuid.UUID) (int, bool) {
+					return 42, true
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp VideoResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.TranscodeProgress == nil || *resp.TranscodeProgress != 42 {
+					t.Errorf("expected transcode progress 42, got %v", resp.TranscodeProgress)
+				}
+			},
+		},
+		{
+			name:    "processing video without progress",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusProcessing}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp map[string]any
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if _, ok := resp["transcode_progress"]; ok {
+					t.Errorf("expected transcode_progress to be omitted, got %s", body)
+				}
+			},
+		},
+		{
+			name:           "invalid video ID",
+			videoID:        "not-a-uuid",
+			setupMock:      func(m *mockVideoService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "video not found",
+			videoID: uuid.New().String(),
+			setupMock: func(m *mockVideoService) {
+				m.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{}
+			tt.setupMock(mock)
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Get("/v1/videos/{id}", h.Get)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+tt.videoID, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestVideoHandler_Get_Stale(t *testing.T) {
+	tests := []struct {
+		name  string
+		stale bool
+	}{
+		{name: "fresh video", stale: false},
+		{name: "stale video", stale: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				getVideoFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusReady, Stale: tt.stale}, nil
+				},
+			}
+
+			r := chi.NewRouter()
+			r.Get("/v1/videos/{id}", NewVideoHandler(mock, false).Get)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+uuid.New().String(), nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rec.Code)
+			}
+			var resp VideoResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Stale != tt.stale {
+				t.Errorf("expected stale %v, got %v", tt.stale, resp.Stale)
+			}
+			if got := rec.Header().Get("Warning") != ""; got != tt.stale {
+				t.Errorf("expected Warning header %v, got %q", tt.stale, rec.Header().Get("Warning"))
+			}
+		})
+	}
+}
+
+func TestVideoHandler_Get_PlaybackURLs(t *testing.T) {
+	ownerID := uuid.New()
+
+	tests := []struct {
+		name                 string
+		entitlementsEnforced bool
+		previewSeconds       int
+		userHeader           string
+		wantURLs             bool
+	}{
+		{name: "ungated video", userHeader: uuid.New().String(), wantURLs: true},
+		{name: "entitlements, another user", entitlementsEnforced: true, userHeader: uuid.New().String()},
+		{name: "entitlements, anonymous", entitlementsEnforced: true},
+		{name: "entitlements, owner", entitlementsEnforced: true, userHeader: ownerID.String(), wantURLs: true},
+		{name: "preview, another user", previewSeconds: 30, userHeader: uuid.New().String()},
+		{name: "preview, owner", previewSeconds: 30, userHeader: ownerID.String(), wantURLs: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				getVideoFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+					return &model.Video{
+						ID:             videoID,
+						UserID:         ownerID,
+						Status:         model.StatusReady,
+						Visibility:     model.VisibilityUnlisted,
+						HLSURL:         "https://cdn.example.com/hls/video-id/master.m3u8",
+						DashURL:        "https://cdn.example.com/hls/video-id/manifest.mpd",
+						PreviewSeconds: tt.previewSeconds,
+					}, nil
+				},
+			}
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Get("/v1/videos/{id}", NewVideoHandler(mock, tt.entitlementsEnforced).Get)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/videos/"+uuid.New().String(), nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rec.Code)
+			}
+			var resp VideoResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if got := resp.HLSURL != "" && resp.DashURL != ""; got != tt.wantURLs {
+				t.Errorf("expected playback URLs %v, got hls_url %q, dash_url %q", tt.wantURLs, resp.HLSURL, resp.DashURL)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_ListByUser(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(m *mockVideoService)
+		wantStatusCode int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name: "page with next cursor",
+			path: "/v1/users/" + userID.String() + "/videos?limit=1&cursor=abc",
+			setupMock: func(m *mockVideoService) {
+				m.listVideosFn = func(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error) {
+					if input.UserID != userID || input.Limit != 1 || input.Cursor != "abc" {
+						t.Errorf("unexpected input: %+v", input)
+					}
+					return &usecase.ListVideosOutput{
+						Videos:     []*model.Video{{ID: uuid.New(), UserID: userID, Title: "Newest", Status: model.StatusReady}},
+						NextCursor: "next",
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp VideosResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if len(resp.Items) != 1 || resp.Items[0].Title != "Newest" {
+					t.Errorf("unexpected items: %+v", resp.Items)
+				}
+				if resp.NextCursor != "next" {
+					t.Errorf("NextCursor = %q, want %q", resp.NextCursor, "next")
+				}
+			},
+		},
+		{
+			name:           "invalid user ID",
+			path:           "/v1/users/not-a-uuid/videos",
+			setupMock:      func(m *mockVideoService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			path:           "/v1/users/" + userID.String() + "/videos?limit=0",
+			setupMock:      func(m *mockVideoService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "malformed cursor",
+			path: "/v1/users/" + userID.String() + "/videos?cursor=bogus",
+			setupMock: func(m *mockVideoService) {
+				m.listVideosFn = func(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error) {
+					return nil, usecase.ErrInvalidCursor
+				}
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{}
+			tt.setupMock(mock)
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Get("/v1/users/{id}/videos", h.ListByUser)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestVideoHandler_PrivateVideos(t *testing.T) {
+	ownerID := uuid.New()
+	public := &model.Video{ID: uuid.New(), UserID: ownerID, Title: "Public", Status: model.StatusReady, Visibility: model.VisibilityPublic}
+	unlisted := &model.Video{ID: uuid.New(), UserID: ownerID, Title: "Unlisted", Status: model.StatusReady, Visibility: model.VisibilityUnlisted}
+	private := &model.Video{ID: uuid.New(), UserID: ownerID, Title: "Private", Status: model.StatusReady, Visibility: model.VisibilityPrivate}
+	videos := map[uuid.UUID]*model.Video{public.ID: public, unlisted.ID: unlisted, private.ID: private}
+
+	mock := &mockVideoService{
+		getVideoFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+			return videos[videoID], nil
+		},
+		listVideosFn: func(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error) {
+			if input.CallerID != input.UserID {
+				return &usecase.ListVideosOutput{Videos: []*model.Video{public}, NextCursor: "next"}, nil
+			}
+			return &usecase.ListVideosOutput{Videos: []*model.Video{public, unlisted, private}, NextCursor: "next"}, nil
+		},
+	}
+	h := NewVideoHandler(mock, false)
+
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Get("/v1/videos/{id}", h.Get)
+	r.Get("/v1/users/{id}/videos", h.ListByUser)
+
+	tests := []struct {
+		name           string
+		path           string
+		userHeader     string
+		wantStatusCode int
+		wantTitles     []string
+	}{
+		{
+			name:           "anonymous caller gets unlisted video",
+			path:           "/v1/videos/" + unlisted.ID.String(),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "anonymous caller cannot get private video",
+			path:           "/v1/videos/" + private.ID.String(),
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "other user cannot get private video",
+			path:           "/v1/videos/" + private.ID.String(),
+			userHeader:     uuid.New().String(),
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "owner gets private video",
+			path:           "/v1/videos/" + private.ID.String(),
+			userHeader:     ownerID.String(),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "listing shows only public videos to others",
+			path:           "/v1/users/" + ownerID.String() + "/videos",
+			userHeader:     uuid.New().String(),
+			wantStatusCode: http.StatusOK,
+			wantTitles:     []string{"Public"},
+		},
+		{
+			name:           "listing shows only public videos to anonymous callers",
+			path:           "/v1/users/" + ownerID.String() + "/videos",
+			wantStatusCode: http.StatusOK,
+			wantTitles:     []string{"Public"},
+		},
+		{
+			name:           "listing shows private videos to the owner",
+			path:           "/v1/users/" + ownerID.String() + "/videos",
+			userHeader:     ownerID.String(),
+			wantStatusCode: http.StatusOK,
+			wantTitles:     []string{"Public", "Unlisted", "Private"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantTitles == nil {
+				return
+			}
+			var resp VideosResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			var titles []string
+			for _, item := range resp.Items {
+				titles = append(titles, item.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("titles = %q, want %q", titles, tt.wantTitles)
+			}
+			if resp.NextCursor != "next" {
+				t.Errorf("NextCursor = %q, want %q", resp.NextCursor, "next")
+			}
+		})
+	}
+}
+
+func TestVideoHandler_Search(t *testing.T) {
+	userID := uuid.New()
+	callerID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		userHeader     string
+		wantUser       uuid.UUID
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "user's videos",
+			path:           "/v1/videos/search?q=cat+video&user_id=" + userID.String() + "&limit=5&cursor=abc",
+			wantUser:       userID,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "caller's videos",
+			path:           "/v1/videos/search?q=cat+video&limit=5&cursor=abc",
+			userHeader:     callerID.String(),
+			wantUser:       callerID,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "no user",
+			path:           "/v1/videos/search?q=cat",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_user_id",
+		},
+		{
+			name:           "invalid user ID",
+			path:           "/v1/videos/search?q=cat&user_id=nobody",
+			userHeader:     callerID.String(),
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_user_id",
+		},
+		{
+			name:           "invalid limit",
+			path:           "/v1/videos/search?q=cat&limit=0",
+			userHeader:     callerID.String(),
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_limit",
+		},
+		{
+			name:           "invalid query",
+			path:           "/v1/videos/search?q=",
+			userHeader:     callerID.String(),
+			serviceErr:     usecase.ErrInvalidSearchQuery,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				searchVideosFn: func(ctx context.Context, input usecase.SearchVideosInput) (*usecase.ListVideosOutput, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if input.UserID != tt.wantUser || input.Query != "cat video" || input.Limit != 5 || input.Cursor != "abc" {
+						t.Errorf("unexpected input: %+v", input)
+					}
+					return &usecase.ListVideosOutput{
+						Videos:     []*model.Video{{ID: uuid.New(), UserID: input.UserID, Title: "My cat video", Status: model.StatusReady}},
+						NextCursor: "next",
+					}, nil
+				},
+			}
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Get("/v1/videos/search", h.Search)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("error = %q, want %q", resp.Error, tt.wantCode)
+				}
+				return
+			}
+
+			var resp VideosResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(resp.Items) != 1 || resp.Items[0].Title != "My cat video" || resp.NextCursor != "next" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestVideoHandler_ListStatusEvents(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(m *mockVideoService)
+		wantStatusCode int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name: "most recent first",
+			path: "/v1/videos/" + videoID.String() + "/status-events?limit=2",
+			setupMock: func(m *mockVideoService) {
+				m.listStatusEventsFn = func(ctx context.Context, id, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+					if id != videoID || limit != 2 {
+						t.Errorf("unexpected arguments: %s, %d", id, limit)
+					}
+					return []*model.VideoStatusEvent{
+						model.NewVideoStatusEvent(id, model.StatusProcessing, model.StatusFailed, model.StatusActorWorker, "retries exhausted after 3 attempts"),
+						model.NewVideoStatusEvent(id, "", model.StatusPendingUpload, model.StatusActorAPI, "created"),
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp StatusEventsResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if len(resp.Items) != 2 {
+					t.Fatalf("got %d items, want 2", len(resp.Items))
+				}
+				if got := resp.Items[0]; got.From != "PROCESSING" || got.To != "FAILED" || got.Actor != "worker" || got.Reason == "" {
+					t.Errorf("unexpected first item: %+v", got)
+				}
+				if got := resp.Items[1]; got.From != "" || got.Actor != "api" {
+					t.Errorf("unexpected creation item: %+v", got)
+				}
+			},
+		},
+		{
+			name:           "invalid video ID",
+			path:           "/v1/videos/not-a-uuid/status-events",
+			setupMock:      func(m *mockVideoService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			path:           "/v1/videos/" + videoID.String() + "/status-events?limit=-1",
+			setupMock:      func(m *mockVideoService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "video not found",
+			path: "/v1/videos/" + videoID.String() + "/status-events",
+			setupMock: func(m *mockVideoService) {
+				m.listStatusEventsFn = func(ctx context.Context, id, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{}
+			tt.setupMock(mock)
+			h := NewVideoHandler(mock, false)
+
+			r := chi.NewRouter()
+			r.Get("/v1/videos/{id}/status-events", h.ListStatusEvents)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
 			r.ServeHTTP(rec, req)
 
 			if rec.Code != tt.wantStatusCode {
@@ -323,3 +2253,99 @@ func TestVideoHandler_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestVideoHandler_Usage(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		userID         string
+		userHeader     string
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "own usage",
+			userID:         userID.String(),
+			userHeader:     userID.String(),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid user ID",
+			userID:         "not-a-uuid",
+			userHeader:     userID.String(),
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_user_id",
+		},
+		{
+			name:           "anonymous caller",
+			userID:         userID.String(),
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "unauthenticated",
+		},
+		{
+			name:           "another user's usage",
+			userID:         userID.String(),
+			userHeader:     uuid.New().String(),
+			wantStatusCode: http.StatusForbidden,
+			wantCode:       "not_owner",
+		},
+		{
+			name:           "service error",
+			userID:         userID.String(),
+			userHeader:     userID.String(),
+			serviceErr:     errors.New("database error"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockVideoService{
+				getStorageUsageFn: func(ctx context.Context, id uuid.UUID) (*model.StorageUsage, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &model.StorageUsage{UserID: id, Videos: 2, OriginalBytes: 3000, OutputBytes: 1500, QuotaBytes: 10000}, nil
+				},
+			}
+
+			r := chi.NewRouter()
+			r.Use(middleware.UserID)
+			r.Get("/v1/users/{id}/usage", NewVideoHandler(mock, false).Usage)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/users/"+tt.userID+"/usage", nil)
+			if tt.userHeader != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("error = %q, want %q", resp.Error, tt.wantCode)
+				}
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp StorageUsageResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			want := StorageUsageResponse{UserID: userID.String(), Videos: 2, OriginalBytes: 3000, OutputBytes: 1500, TotalBytes: 4500, QuotaBytes: 10000}
+			if resp != want {
+				t.Errorf("response = %+v, want %+v", resp, want)
+			}
+		})
+	}
+}
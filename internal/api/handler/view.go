@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Request/Response types
+
+type VideoStatsResponse struct {
+	VideoID string `json:"video_id"`
+	Views   int64  `json:"views"`
+	// LastViewedAt is omitted until the video is first viewed.
+	LastViewedAt string `json:"last_viewed_at,omitempty"`
+}
+
+// ViewHandler handles view counting HTTP requests.
+type ViewHandler struct {
+	svc usecase.ViewService
+}
+
+// NewViewHandler creates a new ViewHandler.
+func NewViewHandler(svc usecase.ViewService) *ViewHandler {
+	return &ViewHandler{svc: svc}
+}
+
+// RecordView handles POST /v1/videos/{id}/views
+// Players call this once when playback starts; anonymous viewers are counted too.
+func (h *ViewHandler) RecordView(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	caller, _ := middleware.GetUserID(r.Context())
+	if err := h.svc.RecordView(r.Context(), videoID, caller); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetStats handles GET /v1/videos/{id}/stats
+func (h *ViewHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	videoID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusBadRequest, "invalid_video_id", "Video ID must be a valid UUID")
+		return
+	}
+
+	caller, _ := middleware.GetUserID(r.Context())
+	stats, err := h.svc.GetStats(r.Context(), videoID, caller)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, toVideoStatsResponse(stats))
+}
+
+func (h *ViewHandler) handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrVideoNotFound):
+		Error(w, http.StatusNotFound, "video_not_found", "Video not found")
+	case errors.Is(err, usecase.ErrVideoNotReady):
+		Error(w, http.StatusConflict, "video_not_ready", "Video is not ready for playback")
+	default:
+		ServiceError(w, err)
+	}
+}
+
+func toVideoStatsResponse(s *model.VideoStats) VideoStatsResponse {
+	resp := VideoStatsResponse{
+		VideoID: s.VideoID.String(),
+		Views:   s.Views,
+	}
+	if !s.LastViewedAt.IsZero() {
+		resp.LastViewedAt = s.LastViewedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// mockViewService is a mock implementation of usecase.ViewService.
+type mockViewService struct {
+	recordViewFn func(ctx context.Context, videoID, callerID uuid.UUID) error
+	getStatsFn   func(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoStats, error)
+}
+
+func (m *mockViewService) RecordView(ctx context.Context, videoID, callerID uuid.UUID) error {
+	if m.recordViewFn != nil {
+		return m.recordViewFn(ctx, videoID, callerID)
+	}
+	return nil
+}
+
+func (m *mockViewService) GetStats(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoStats, error) {
+	if m.getStatsFn != nil {
+		return m.getStatsFn(ctx, videoID, callerID)
+	}
+	return nil, repository.ErrVideoNotFound
+}
+
+func (m *mockViewService) FlushViews(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func newViewRouter(h *ViewHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.UserID)
+	r.Post("/v1/videos/{id}/views", h.RecordView)
+	r.Get("/v1/videos/{id}/stats", h.GetStats)
+	return r
+}
+
+func TestViewHandler_RecordView(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		target         string
+		userID         string
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+		wantCaller     uuid.UUID
+	}{
+		{
+			name:           "anonymous view",
+			target:         "/v1/videos/" + videoID.String() + "/views",
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "signed-in view",
+			target:         "/v1/videos/" + videoID.String() + "/views",
+			userID:         userID.String(),
+			wantStatusCode: http.StatusNoContent,
+			wantCaller:     userID,
+		},
+		{
+			name:           "invalid video ID",
+			target:         "/v1/videos/nope/views",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_video_id",
+		},
+		{
+			name:           "hidden video",
+			target:         "/v1/videos/" + videoID.String() + "/views",
+			serviceErr:     repository.ErrVideoNotFound,
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "video_not_found",
+		},
+		{
+			name:           "not ready",
+			target:         "/v1/videos/" + videoID.String() + "/views",
+			serviceErr:     usecase.ErrVideoNotReady,
+			wantStatusCode: http.StatusConflict,
+			wantCode:       "video_not_ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockViewService{
+				recordViewFn: func(ctx context.Context, id, callerID uuid.UUID) error {
+					if id != videoID || callerID != tt.wantCaller {
+						t.Errorf("RecordView(%s, %s), want (%s, %s)", id, callerID, videoID, tt.wantCaller)
+					}
+					return tt.serviceErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			if tt.userID != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			newViewRouter(NewViewHandler(mock)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("expected error code %q, got %s", tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestViewHandler_GetStats(t *testing.T) {
+	videoID := uuid.New()
+	lastViewedAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		stats          *model.VideoStats
+		wantStatusCode int
+		want           VideoStatsResponse
+	}{
+		{
+			name:           "viewed",
+			stats:          &model.VideoStats{VideoID: videoID, Views: 42, LastViewedAt: lastViewedAt},
+			wantStatusCode: http.StatusOK,
+			want:           VideoStatsResponse{VideoID: videoID.String(), Views: 42, LastViewedAt: "2026-03-01T12:30:00Z"},
+		},
+		{
+			name:           "never viewed",
+			stats:          &model.VideoStats{VideoID: videoID},
+			wantStatusCode: http.StatusOK,
+			want:           VideoStatsResponse{VideoID: videoID.String()},
+		},
+		{
+			name:           "not found",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockViewService{
+				getStatsFn: func(ctx context.Context, id, callerID uuid.UUID) (*model.VideoStats, error) {
+					if tt.stats == nil {
+						return nil, repository.ErrVideoNotFound
+					}
+					return tt.stats, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			newViewRouter(NewViewHandler(mock)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/videos/"+videoID.String()+"/stats", nil))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var got VideoStatsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
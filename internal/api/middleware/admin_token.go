@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminTokenHeader carries the operator token of /v1/admin requests. It is kept apart
+// from Authorization, which authenticates API keys of users.
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminToken rejects requests without one of tokens in AdminTokenHeader with 401.
+// Listing the old and new token allows rotation without locking operators out.
+// Tokens are compared by SHA-256 in constant time, so neither their contents nor
+// their lengths leak through timing.
+func AdminToken(tokens [][]byte) func(http.Handler) http.Handler {
+	digests := make([][sha256.Size]byte, len(tokens))
+	for i, token := range tokens {
+		digests[i] = sha256.Sum256(token)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := sha256.Sum256([]byte(r.Header.Get(AdminTokenHeader)))
+			match := 0
+			for _, digest := range digests {
+				match |= subtle.ConstantTimeCompare(got[:], digest[:])
+			}
+			if match != 1 || r.Header.Get(AdminTokenHeader) == "" {
+				writeJSONError(w, http.StatusUnauthorized, "invalid_admin_token", "A valid "+AdminTokenHeader+" header is required")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// staticAPIKeys is an APIKeyAuthenticator knowing a single key.
+type staticAPIKeys struct {
+	key    string
+	userID uuid.UUID
+	err    error
+}
+
+func (s staticAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (uuid.UUID, error) {
+	if s.err != nil {
+		return uuid.Nil, s.err
+	}
+	if key != s.key {
+		return uuid.Nil, errs.New(errs.Invalid, "invalid API key")
+	}
+	return s.userID, nil
+}
+
+func TestAPIKey(t *testing.T) {
+	keyUser := uuid.New()
+	headerUser := uuid.New()
+
+	tests := []struct {
+		name          string
+		authorization string
+		authErr       error
+		wantStatus    int
+		wantUser      uuid.UUID
+		wantByKey     bool
+	}{
+		{name: "no key", wantStatus: http.StatusOK, wantUser: headerUser},
+		{name: "valid key", authorization: "Bearer gsk_valid", wantStatus: http.StatusOK, wantUser: keyUser, wantByKey: true},
+		{name: "scheme is case-insensitive", authorization: "bearer gsk_valid", wantStatus: http.StatusOK, wantUser: keyUser, wantByKey: true},
+		{name: "invalid key", authorization: "Bearer gsk_other", wantStatus: http.StatusUnauthorized},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "lookup failure", authorization: "Bearer gsk_valid", authErr: errors.New("connection refused"), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotUser   uuid.UUID
+				gotByKey  bool
+				auth      = staticAPIKeys{key: "gsk_valid", userID: keyUser, err: tt.authErr}
+				handlerFn = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					gotUser, _ = GetUserID(r.Context())
+					gotByKey = AuthenticatedByAPIKey(r.Context())
+					w.WriteHeader(http.StatusOK)
+				})
+			)
+			h := UserID(APIKey(auth)(handlerFn))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/videos", nil)
+			req.Header.Set(UserIDHeader, headerUser.String())
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			if gotUser != tt.wantUser || gotByKey != tt.wantByKey {
+				t.Errorf("user = %v (by key %v), want %v (by key %v)", gotUser, gotByKey, tt.wantUser, tt.wantByKey)
+			}
+		})
+	}
+}
+
+// staticVideos is a VideoLookup knowing a single video.
+type staticVideos struct {
+	video *model.Video
+	err   error
+}
+
+func (s staticVideos) GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	// Stands in for a read replica that has not caught up with the video's creation
+	if allowed, set := repository.StaleReads(ctx); videoID != s.video.ID || allowed || !set {
+		return nil, errs.New(errs.NotFound, "video not found")
+	}
+	return s.video, nil
+}
+
+func TestVideoOwner(t *testing.T) {
+	owner := uuid.New()
+	video := &model.Video{ID: uuid.New(), UserID: owner}
+
+	tests := []struct {
+		name       string
+		videoID    string
+		userID     uuid.UUID
+		byKey      bool
+		lookupErr  error
+		wantStatus int
+	}{
+		{name: "owner", videoID: video.ID.String(), userID: owner, byKey: true, wantStatus: http.StatusOK},
+		{name: "another user", videoID: video.ID.String(), userID: uuid.New(), byKey: true, wantStatus: http.StatusForbidden},
+		{name: "header owner", videoID: video.ID.String(), userID: owner, wantStatus: http.StatusOK},
+		{name: "header another user", videoID: video.ID.String(), userID: uuid.New(), wantStatus: http.StatusForbidden},
+		{name: "anonymous", videoID: video.ID.String(), wantStatus: http.StatusUnauthorized},
+		{name: "unknown video", videoID: uuid.New().String(), userID: owner, byKey: true, wantStatus: http.StatusNotFound},
+		{name: "malformed ID", videoID: "abc", userID: owner, byKey: true, wantStatus: http.StatusOK},
+		{name: "lookup failure", videoID: video.ID.String(), userID: owner, byKey: true, lookupErr: errors.New("connection refused"), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.With(VideoOwner(staticVideos{video: video, err: tt.lookupErr})).Delete("/v1/videos/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/videos/"+tt.videoID, nil)
+			ctx := req.Context()
+			if tt.userID != uuid.Nil {
+				ctx = context.WithValue(ctx, UserIDKey, tt.userID)
+			}
+			if tt.byKey {
+				ctx = context.WithValue(ctx, apiKeyAuthKey, true)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req.WithContext(ctx))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
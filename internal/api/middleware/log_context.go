@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hszk-dev/gostream/internal/infrastructure/logging"
+)
+
+// TraceParentHeader is the W3C Trace Context header carrying the caller's trace ID.
+const TraceParentHeader = "traceparent"
+
+// LogContext is a middleware that attaches request_id, trace_id, user_id, tenant_id and
+// video_id to every log record written with the request context.
+// It must be used AFTER RequestID and UserID, and before Logger so the access log is enriched too.
+//
+// A tenant is the account owning videos, so tenant_id is the {id} of /tenants/{id} routes
+// and otherwise the caller's user ID. Route parameters are resolved after this middleware
+// runs, so they are read when each record is written.
+func LogContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var attrs []slog.Attr
+		if requestID := GetRequestID(ctx); requestID != "" {
+			attrs = append(attrs, slog.String("request_id", requestID))
+		}
+		if traceID := parseTraceID(r.Header.Get(TraceParentHeader)); traceID != "" {
+			attrs = append(attrs, slog.String("trace_id", traceID))
+		}
+
+		var userID string
+		if id, ok := GetUserID(ctx); ok {
+			userID = id.String()
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		ctx = logging.WithAttrs(ctx, attrs...)
+
+		rctx := chi.RouteContext(ctx)
+		ctx = logging.WithLazyAttrs(ctx, func() []slog.Attr {
+			tenantID, videoID := routeIDs(rctx)
+			if tenantID == "" {
+				tenantID = userID
+			}
+
+			var attrs []slog.Attr
+			if tenantID != "" {
+				attrs = append(attrs, slog.String("tenant_id", tenantID))
+			}
+			if videoID != "" {
+				attrs = append(attrs, slog.String("video_id", videoID))
+			}
+			return attrs
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// routeIDs extracts the tenant and video IDs from the matched route.
+// The {id} parameter names a different entity depending on the route prefix.
+func routeIDs(rctx *chi.Context) (tenantID, videoID string) {
+	if rctx == nil {
+		return "", ""
+	}
+
+	pattern := rctx.RoutePattern()
+	id := rctx.URLParam("id")
+	switch {
+	case strings.HasPrefix(pattern, "/v1/tenants/"):
+		tenantID = id
+	case strings.HasPrefix(pattern, "/v1/videos/"), strings.HasPrefix(pattern, "/v1/admin/videos/"):
+		videoID = id
+	}
+	if v := rctx.URLParam("videoID"); v != "" {
+		videoID = v
+	}
+
+	return tenantID, videoID
+}
+
+// parseTraceID returns the trace ID of a version-00 traceparent header, or "" if malformed.
+// Format: 00-{32 hex trace ID}-{16 hex parent ID}-{2 hex flags}
+func parseTraceID(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return ""
+	}
+
+	traceID := strings.ToLower(parts[1])
+	if strings.Trim(traceID, "0123456789abcdef") != "" || traceID == strings.Repeat("0", 32) {
+		return ""
+	}
+
+	return traceID
+}
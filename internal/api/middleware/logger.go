@@ -24,6 +24,14 @@ func (rw *responseWriter) WriteHeader(code int) {
 	}
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to flush
+// streamed responses.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Logger is a middleware that writes an access log record per request.
+// The request ID and caller identity are attached by LogContext.
 func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -31,11 +39,9 @@ func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 			wrapped := wrapResponseWriter(w)
 
 			defer func() {
-				requestID := GetRequestID(r.Context())
 				duration := time.Since(start)
 
-				logger.Info("request completed",
-					slog.String("request_id", requestID),
+				logger.InfoContext(r.Context(), "request completed",
 					slog.String("method", r.Method),
 					slog.String("path", r.URL.Path),
 					slog.Int("status", wrapped.status),
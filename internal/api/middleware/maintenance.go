@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// MaintenanceState reports the open maintenance window, if any.
+type MaintenanceState interface {
+	Active(ctx context.Context) *model.MaintenanceWindow
+}
+
+// Maintenance is a middleware that rejects mutating requests with 503 while a maintenance
+// window is open. Reads keep working, as do admin routes (so the window can be ended) and
+// playback token issuance (so viewers can keep watching).
+// Retry-After is the time left in the window, or retryAfter for windows without an end.
+func Maintenance(state MaintenanceState, retryAfter time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maintenanceExempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			window := state.Active(r.Context())
+			if window == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			wait := retryAfter
+			if remaining, ok := window.Remaining(time.Now()); ok {
+				wait = remaining
+			}
+			message := "The service is under maintenance"
+			if window.Reason != "" {
+				message += ": " + window.Reason
+			}
+
+			// Retry-After is whole seconds; round up so clients never retry early
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if err := json.NewEncoder(w).Encode(map[string]string{"error": "maintenance", "message": message}); err != nil {
+				http.Error(w, "failed to encode response", http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+func maintenanceExempt(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return strings.HasPrefix(r.URL.Path, "/v1/admin/") ||
+		(r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/playback-token"))
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+type staticMaintenance struct {
+	window *model.MaintenanceWindow
+}
+
+func (s staticMaintenance) Active(ctx context.Context) *model.MaintenanceWindow {
+	return s.window
+}
+
+func TestMaintenance(t *testing.T) {
+	open := &model.MaintenanceWindow{
+		StartsAt: time.Now().Add(-time.Minute),
+		EndsAt:   time.Now().Add(90 * time.Second),
+		Reason:   "database upgrade",
+	}
+	noEnd := &model.MaintenanceWindow{StartsAt: time.Now().Add(-time.Minute)}
+
+	tests := []struct {
+		name           string
+		window         *model.MaintenanceWindow
+		method         string
+		path           string
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{name: "no window", method: http.MethodPost, path: "/v1/videos", wantStatus: http.StatusOK},
+		{name: "write rejected", window: open, method: http.MethodPost, path: "/v1/videos", wantStatus: http.StatusServiceUnavailable, wantRetryAfter: "90"},
+		{name: "delete rejected", window: open, method: http.MethodDelete, path: "/v1/videos/abc", wantStatus: http.StatusServiceUnavailable, wantRetryAfter: "90"},
+		{name: "window without end", window: noEnd, method: http.MethodPut, path: "/v1/videos/abc/progress", wantStatus: http.StatusServiceUnavailable, wantRetryAfter: "300"},
+		{name: "read allowed", window: open, method: http.MethodGet, path: "/v1/videos/abc", wantStatus: http.StatusOK},
+		{name: "admin allowed", window: open, method: http.MethodDelete, path: "/v1/admin/maintenance", wantStatus: http.StatusOK},
+		{name: "playback token allowed", window: open, method: http.MethodPost, path: "/v1/videos/abc/playback-token", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Maintenance(staticMaintenance{window: tt.window}, 5*time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable && !strings.Contains(rec.Body.String(), `"error":"maintenance"`) {
+				t.Errorf("unexpected body: %s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// unmatchedRoute labels requests that matched no route, so scanners probing random
+// paths cannot blow up the label cardinality.
+const unmatchedRoute = "unmatched"
+
+// Metrics is a middleware that records request counts, latency and in-flight requests
+// per route. Requests are labeled with the route pattern rather than the path, so
+// /v1/videos/{id} is one series for all videos.
+// Must be installed outside Recoverer so recovered panics are counted as 500s.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		start := time.Now()
+		wrapped := wrapResponseWriter(w)
+		defer func() {
+			// The pattern is complete only once routing has finished
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			metrics.HTTPRequestsTotal.WithLabelValues(r.Method, route, strconv.Itoa(wrapped.status)).Inc()
+			metrics.HTTPRequestDurationSeconds.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+		}()
+
+		next.ServeHTTP(wrapped, r)
+	})
+}
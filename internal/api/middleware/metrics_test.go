@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Metrics)
+	r.Route("/v1/videos", func(r chi.Router) {
+		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if got := testutil.ToFloat64(metrics.HTTPRequestsInFlight); got != 1 {
+				t.Errorf("in-flight requests: got %v, want 1", got)
+			}
+			w.WriteHeader(http.StatusTeapot)
+		})
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		route  string
+		status string
+	}{
+		{name: "matched route", path: "/v1/videos/abc", route: "/v1/videos/{id}", status: "418"},
+		{name: "unmatched path", path: "/wp-login.php", route: unmatchedRoute, status: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, tt.route, tt.status)
+			before := testutil.ToFloat64(counter)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("requests counted for %s %s: got %v, want 1", tt.route, tt.status, got)
+			}
+			if got := testutil.ToFloat64(metrics.HTTPRequestsInFlight); got != 0 {
+				t.Errorf("in-flight requests after completion: got %v, want 0", got)
+			}
+		})
+	}
+}
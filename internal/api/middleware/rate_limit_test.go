@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// countingRateLimitStore is a cache.RateLimitStore allowing limit requests per key.
+type countingRateLimitStore struct {
+	limit int
+	wait  time.Duration
+	err   error
+	taken map[string]int
+}
+
+func (s *countingRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int, now time.Time) (bool, time.Duration, error) {
+	if s.err != nil {
+		return false, 0, s.err
+	}
+	if s.taken[key] >= s.limit {
+		return false, s.wait, nil
+	}
+	s.taken[key]++
+	return true, 0, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		bucket         TokenBucket
+		storeErr       error
+		userID         string
+		byKey          bool
+		path           string
+		remoteAddrs    []string
+		wantStatuses   []int
+		wantRetryAfter string
+		wantKey        string
+	}{
+		{
+			name:         "under the limit",
+			bucket:       TokenBucket{Rate: 1, Burst: 2},
+			remoteAddrs:  []string{"192.0.2.1:1234", "192.0.2.1:1234"},
+			wantStatuses: []int{http.StatusOK, http.StatusOK},
+			wantKey:      "api:ip:192.0.2.1",
+		},
+		{
+			name:           "over the limit",
+			bucket:         TokenBucket{Rate: 1, Burst: 2},
+			remoteAddrs:    []string{"192.0.2.1:1234", "192.0.2.1:5678", "192.0.2.1:1234"},
+			wantStatuses:   []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetryAfter: "2",
+		},
+		{
+			name:         "callers have separate buckets",
+			bucket:       TokenBucket{Rate: 1, Burst: 2},
+			remoteAddrs:  []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.2:1234"},
+			wantStatuses: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:           "API key users are limited regardless of address",
+			bucket:         TokenBucket{Rate: 1, Burst: 2},
+			userID:         userID.String(),
+			byKey:          true,
+			remoteAddrs:    []string{"192.0.2.1:1234", "192.0.2.2:1234", "192.0.2.3:1234"},
+			wantStatuses:   []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetryAfter: "2",
+			wantKey:        "api:user:" + userID.String(),
+		},
+		{
+			name:           "API key users are limited by address too",
+			bucket:         TokenBucket{Rate: 1, Burst: 2},
+			userID:         userID.String(),
+			byKey:          true,
+			remoteAddrs:    []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.1:1234"},
+			wantStatuses:   []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetryAfter: "2",
+			wantKey:        "api:ip:192.0.2.1",
+		},
+		{
+			name:           "header users are limited by address",
+			bucket:         TokenBucket{Rate: 1, Burst: 2},
+			userID:         userID.String(),
+			remoteAddrs:    []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.1:1234"},
+			wantStatuses:   []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetryAfter: "2",
+			wantKey:        "api:ip:192.0.2.1",
+		},
+		{
+			name:         "header users get no user bucket",
+			bucket:       TokenBucket{Rate: 1, Burst: 2},
+			userID:       userID.String(),
+			remoteAddrs:  []string{"192.0.2.1:1234", "192.0.2.2:1234", "192.0.2.3:1234"},
+			wantStatuses: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:         "playback authorization is not limited",
+			bucket:       TokenBucket{Rate: 1, Burst: 2},
+			path:         "/v1/playback/authorize",
+			remoteAddrs:  []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.1:1234"},
+			wantStatuses: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:         "signed URL verification is not limited",
+			bucket:       TokenBucket{Rate: 1, Burst: 2},
+			path:         "/v1/playback/verify",
+			remoteAddrs:  []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.1:1234"},
+			wantStatuses: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:         "disabled",
+			remoteAddrs:  []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.1:1234"},
+			wantStatuses: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:         "store unavailable",
+			bucket:       TokenBucket{Rate: 1, Burst: 2},
+			storeErr:     errors.New("redis down"),
+			remoteAddrs:  []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.1:1234"},
+			wantStatuses: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &countingRateLimitStore{limit: 2, wait: 1500 * time.Millisecond, err: tt.storeErr, taken: map[string]int{}}
+			var h http.Handler = RateLimit(store, "api", tt.bucket)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			if tt.byKey {
+				// Stands in for APIKey, which marks the user as authenticated
+				limited := h
+				h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					limited.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyAuthKey, true)))
+				})
+			}
+			h = UserID(h)
+
+			path := tt.path
+			if path == "" {
+				path = "/v1/videos/abc"
+			}
+
+			var rec *httptest.ResponseRecorder
+			for i, addr := range tt.remoteAddrs {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				req.RemoteAddr = addr
+				if tt.userID != "" {
+					req.Header.Set(UserIDHeader, tt.userID)
+				}
+				rec = httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+
+				if rec.Code != tt.wantStatuses[i] {
+					t.Errorf("request %d: status = %d, want %d", i, rec.Code, tt.wantStatuses[i])
+				}
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if tt.wantKey != "" && store.taken[tt.wantKey] == 0 {
+				t.Errorf("no token taken from %q: %v", tt.wantKey, store.taken)
+			}
+		})
+	}
+}
@@ -11,11 +11,9 @@ func Recoverer(logger *slog.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if rec := recover(); rec != nil {
-					requestID := GetRequestID(r.Context())
 					stack := debug.Stack()
 
-					logger.Error("panic recovered",
-						slog.String("request_id", requestID),
+					logger.ErrorContext(r.Context(), "panic recovered",
 						slog.Any("panic", rec),
 						slog.String("stack", string(stack)),
 					)
@@ -9,7 +9,11 @@ import (
 
 type ctxKey int
 
-const RequestIDKey ctxKey = iota
+const (
+	RequestIDKey ctxKey = iota
+	UserIDKey
+	apiKeyAuthKey
+)
 
 // RequestID is a middleware that propagates chi's request ID to our context key.
 // It must be used AFTER chi's RequestID middleware in the chain.
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSONError writes the API's JSON error body; the handler package cannot be imported here.
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": code, "message": message}); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// SignedPlaybackVerifier checks signed playback tokens.
+type SignedPlaybackVerifier interface {
+	Verify(token, key string) (*model.SignedPlaybackClaims, error)
+}
+
+// SignedPlayback is a middleware for an HLS origin serving signed playback URLs. It
+// expects paths of the form /{token}/{object key} (mount it with http.StripPrefix),
+// answers 401 unless the token grants access to the key, and passes the request on
+// with the path reduced to /{object key}.
+func SignedPlayback(verifier SignedPlaybackVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+			if _, err := verifier.Verify(token, key); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				if err := json.NewEncoder(w).Encode(map[string]string{"error": "invalid_playback_token", "message": "Playback URL is invalid or expired"}); err != nil {
+					http.Error(w, "failed to encode response", http.StatusInternalServerError)
+				}
+				return
+			}
+
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = "/" + key
+			r2.URL.RawPath = ""
+			next.ServeHTTP(w, r2)
+		})
+	}
+}
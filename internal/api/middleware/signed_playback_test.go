@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+type fakeSignedPlaybackVerifier struct{}
+
+func (fakeSignedPlaybackVerifier) Verify(token, key string) (*model.SignedPlaybackClaims, error) {
+	if token != "good" || key != "hls/abc/v1/master.m3u8" {
+		return nil, errors.New("invalid playback token")
+	}
+	return &model.SignedPlaybackClaims{Prefix: "hls/abc/v1/"}, nil
+}
+
+func TestSignedPlayback(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantPath   string
+	}{
+		{name: "valid", path: "/good/hls/abc/v1/master.m3u8", wantStatus: http.StatusOK, wantPath: "/hls/abc/v1/master.m3u8"},
+		{name: "invalid token", path: "/bad/hls/abc/v1/master.m3u8", wantStatus: http.StatusUnauthorized},
+		{name: "no key", path: "/good", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			h := SignedPlayback(fakeSignedPlaybackVerifier{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("forwarded path = %q, want %q", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// SLI is a middleware that classifies each request for the API availability SLI.
+// 5xx responses count against availability; 4xx are client errors and count as good.
+// Health checks and metrics scrapes are excluded so probe traffic does not dilute the ratio.
+// Must be installed outside Recoverer so recovered panics are seen as 500s.
+func SLI(window *metrics.AvailabilityWindow) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/health", "/healthz", "/readyz", "/metrics":
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			wrapped := wrapResponseWriter(w)
+			defer func() {
+				good := wrapped.status < http.StatusInternalServerError
+				result := metrics.SLIGood
+				if !good {
+					result = metrics.SLIBad
+				}
+				metrics.SLIHTTPRequestsTotal.WithLabelValues(result).Inc()
+				if window != nil {
+					window.Record(good, time.Now())
+				}
+			}()
+
+			next.ServeHTTP(wrapped, r)
+		})
+	}
+}
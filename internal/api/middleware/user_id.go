@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// UserIDHeader carries the caller's user ID.
+// The API does not authenticate users itself; the header must be set by a trusted
+// gateway after authentication and stripped from client requests.
+const UserIDHeader = "X-User-ID"
+
+// UserID is a middleware that stores the caller's user ID from UserIDHeader in the context.
+// Missing or malformed values are ignored; handlers that require a user reject the request.
+func UserID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userID, err := uuid.Parse(r.Header.Get(UserIDHeader)); err == nil && userID != uuid.Nil {
+			r = r.WithContext(context.WithValue(r.Context(), UserIDKey, userID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetUserID retrieves the caller's user ID from context.
+func GetUserID(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
+	return userID, ok
+}
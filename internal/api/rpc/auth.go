@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hszk-dev/gostream/internal/api/middleware"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+// userIDMetadata carries the caller's user ID, like middleware.UserIDHeader. It must be
+// set by a trusted gateway or service, never by end users.
+const userIDMetadata = "x-user-id"
+
+type callerKey struct{}
+
+// caller is who made a call, as resolved by the auth interceptors.
+type caller struct {
+	userID uuid.UUID
+	// byAPIKey is set when an API key authenticated the call rather than userIDMetadata.
+	byAPIKey bool
+}
+
+// callerFrom returns the caller stored by the auth interceptors; ok is false for calls
+// without an API key or a valid userIDMetadata.
+func callerFrom(ctx context.Context) (caller, bool) {
+	c, ok := ctx.Value(callerKey{}).(caller)
+	return c, ok
+}
+
+// UnaryAuth authenticates unary calls the way middleware.UserID and middleware.APIKey
+// authenticate REST requests: "authorization: Bearer {key}" metadata authenticates as
+// the key's user, and calls without it fall back to userIDMetadata. An invalid key gets
+// Unauthenticated and a failed check Unavailable.
+func UnaryAuth(auth middleware.APIKeyAuthenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth is UnaryAuth for streaming calls.
+func StreamAuth(auth middleware.APIKeyAuthenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), auth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, auth middleware.APIKeyAuthenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	header := firstValue(md, "authorization")
+	if header == "" {
+		if userID, err := uuid.Parse(firstValue(md, userIDMetadata)); err == nil && userID != uuid.Nil {
+			ctx = context.WithValue(ctx, callerKey{}, caller{userID: userID})
+		}
+		return ctx, nil
+	}
+
+	scheme, key, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a Bearer API key")
+	}
+
+	userID, err := auth.AuthenticateAPIKey(ctx, strings.TrimSpace(key))
+	if err != nil {
+		if errors.Is(err, errs.Invalid) {
+			return nil, status.Error(codes.Unauthenticated, "API key is invalid or revoked")
+		}
+		slog.ErrorContext(ctx, "failed to authenticate API key",
+			slog.String("error", err.Error()),
+		)
+		return nil, status.Error(codes.Unavailable, "authentication is temporarily unavailable")
+	}
+
+	return context.WithValue(ctx, callerKey{}, caller{userID: userID, byAPIKey: true}), nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+type authenticatorFunc func(ctx context.Context, key string) (uuid.UUID, error)
+
+func (f authenticatorFunc) AuthenticateAPIKey(ctx context.Context, key string) (uuid.UUID, error) {
+	return f(ctx, key)
+}
+
+func TestAuthenticate(t *testing.T) {
+	keyUser := uuid.New()
+	gatewayUser := uuid.New()
+
+	auth := authenticatorFunc(func(ctx context.Context, key string) (uuid.UUID, error) {
+		switch key {
+		case "valid":
+			return keyUser, nil
+		case "down":
+			return uuid.Nil, errors.New("connection refused")
+		default:
+			return uuid.Nil, errs.New(errs.Invalid, "invalid API key")
+		}
+	})
+
+	tests := []struct {
+		name       string
+		md         metadata.MD
+		wantCode   codes.Code
+		wantCaller *caller
+	}{
+		{
+			name:     "anonymous",
+			wantCode: codes.OK,
+		},
+		{
+			name:       "gateway user",
+			md:         metadata.Pairs(userIDMetadata, gatewayUser.String()),
+			wantCode:   codes.OK,
+			wantCaller: &caller{userID: gatewayUser},
+		},
+		{
+			name:     "malformed gateway user is ignored",
+			md:       metadata.Pairs(userIDMetadata, "nobody"),
+			wantCode: codes.OK,
+		},
+		{
+			name:       "API key takes precedence over the gateway user",
+			md:         metadata.Pairs("authorization", "bearer valid", userIDMetadata, gatewayUser.String()),
+			wantCode:   codes.OK,
+			wantCaller: &caller{userID: keyUser, byAPIKey: true},
+		},
+		{
+			name:     "basic auth",
+			md:       metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "revoked key",
+			md:       metadata.Pairs("authorization", "Bearer revoked"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "check failed",
+			md:       metadata.Pairs("authorization", "Bearer down"),
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			ctx, err := authenticate(ctx, auth)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("authenticate() code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			got, ok := callerFrom(ctx)
+			if ok != (tt.wantCaller != nil) {
+				t.Fatalf("caller set = %v, want %v", ok, tt.wantCaller != nil)
+			}
+			if ok && got != *tt.wantCaller {
+				t.Errorf("caller = %+v, want %+v", got, *tt.wantCaller)
+			}
+		})
+	}
+}
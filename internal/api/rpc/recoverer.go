@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryRecoverer turns a panicking call into an Internal error, like middleware.Recoverer.
+func UnaryRecoverer(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recoverCall(ctx, logger, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoverer is UnaryRecoverer for streaming calls.
+func StreamRecoverer(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recoverCall(ss.Context(), logger, info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
+
+func recoverCall(ctx context.Context, logger *slog.Logger, method string, err *error) {
+	if rec := recover(); rec != nil {
+		logger.ErrorContext(ctx, "panic recovered",
+			slog.String("method", method),
+			slog.Any("panic", rec),
+			slog.String("stack", string(debug.Stack())),
+		)
+		*err = status.Error(codes.Internal, "an unexpected error occurred")
+	}
+}
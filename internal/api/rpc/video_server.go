@@ -0,0 +1,340 @@
+// Package rpc serves the gostream.v1 gRPC API on top of the same usecase services as the
+// REST handlers.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	gostreamv1 "github.com/hszk-dev/gostream/api/proto/gostream/v1"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+// Videos is the part of usecase.VideoService the gRPC API serves.
+type Videos interface {
+	CreateVideo(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error)
+	GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	GetTranscodeProgress(ctx context.Context, videoID uuid.UUID) (int, bool)
+	ListVideos(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error)
+	TriggerProcess(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error
+}
+
+// VideoServer implements gostreamv1.VideoServiceServer.
+type VideoServer struct {
+	gostreamv1.UnimplementedVideoServiceServer
+
+	videos  Videos
+	watcher usecase.VideoWatcher
+	// entitlementsEnforced hides playback URLs from other users, as in the REST API
+	entitlementsEnforced bool
+}
+
+// NewVideoServer creates a new VideoServer. entitlementsEnforced is set when an
+// entitlement provider is configured.
+func NewVideoServer(videos Videos, watcher usecase.VideoWatcher, entitlementsEnforced bool) *VideoServer {
+	return &VideoServer{
+		videos:               videos,
+		watcher:              watcher,
+		entitlementsEnforced: entitlementsEnforced,
+	}
+}
+
+// CreateVideo validates like POST /v1/videos with a JSON body.
+func (s *VideoServer) CreateVideo(ctx context.Context, req *gostreamv1.CreateVideoRequest) (*gostreamv1.CreateVideoResponse, error) {
+	c, _ := callerFrom(ctx)
+	if c.byAPIKey && req.GetUserId() == "" {
+		req.UserId = c.userID.String()
+	}
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be a valid UUID")
+	}
+	if c.byAPIKey && userID != c.userID {
+		return nil, status.Error(codes.PermissionDenied, "API key cannot create videos for another user")
+	}
+	if req.GetTitle() == "" {
+		return nil, status.Error(codes.InvalidArgument, "title is required")
+	}
+	if req.GetFileName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "file_name is required")
+	}
+
+	input := usecase.CreateVideoInput{
+		UserID:         userID,
+		Title:          req.GetTitle(),
+		FileName:       req.GetFileName(),
+		PreviewSeconds: int(req.GetPreviewSeconds()),
+		ExpiresAt:      timestamp(req.GetExpiresAt()),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	}
+
+	output, err := s.videos.CreateVideo(ctx, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &gostreamv1.CreateVideoResponse{
+		Video:     toVideo(output.Video),
+		UploadUrl: output.UploadURL,
+		Replayed:  output.Replayed,
+	}, nil
+}
+
+func (s *VideoServer) GetVideo(ctx context.Context, req *gostreamv1.GetVideoRequest) (*gostreamv1.GetVideoResponse, error) {
+	videoID, err := parseVideoID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if region := req.GetViewerRegion(); region != "" {
+		ctx = usecase.WithViewerRegion(ctx, region)
+	}
+
+	video, err := s.videos.GetVideo(ctx, videoID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	// Private videos are reported as missing, like GET /v1/videos/{id} does
+	c, _ := callerFrom(ctx)
+	if !video.VisibleTo(c.userID) {
+		return nil, toStatus(repository.ErrVideoNotFound)
+	}
+
+	resp := s.toViewerVideo(video, c.userID)
+	if video.Status == model.StatusProcessing {
+		if percent, ok := s.videos.GetTranscodeProgress(ctx, videoID); ok {
+			progress := int32(percent)
+			resp.TranscodeProgress = &progress
+		}
+	}
+	return &gostreamv1.GetVideoResponse{Video: resp}, nil
+}
+
+func (s *VideoServer) ListVideos(ctx context.Context, req *gostreamv1.ListVideosRequest) (*gostreamv1.ListVideosResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be a valid UUID")
+	}
+	if req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	c, _ := callerFrom(ctx)
+	output, err := s.videos.ListVideos(ctx, usecase.ListVideosInput{
+		UserID:   userID,
+		CallerID: c.userID,
+		Cursor:   req.GetCursor(),
+		Limit:    int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	videos := make([]*gostreamv1.Video, 0, len(output.Videos))
+	for _, v := range output.Videos {
+		videos = append(videos, s.toViewerVideo(v, c.userID))
+	}
+	return &gostreamv1.ListVideosResponse{
+		Videos:     videos,
+		NextCursor: output.NextCursor,
+	}, nil
+}
+
+// TriggerProcess rejects callers other than the video's owner, like
+// middleware.VideoOwner does for POST /v1/videos/{id}/process.
+func (s *VideoServer) TriggerProcess(ctx context.Context, req *gostreamv1.TriggerProcessRequest) (*gostreamv1.TriggerProcessResponse, error) {
+	videoID, err := parseVideoID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := callerFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "an API key or x-user-id metadata is required")
+	}
+	video, err := s.videos.GetVideo(ctx, videoID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if video.UserID != c.userID {
+		return nil, status.Error(codes.PermissionDenied, "user does not own this video")
+	}
+
+	if err := s.videos.TriggerProcess(ctx, videoID, usecase.ProcessInput{ABRProfile: req.GetAbrProfile()}); err != nil {
+		return nil, toStatus(err)
+	}
+	return &gostreamv1.TriggerProcessResponse{}, nil
+}
+
+// WatchVideo sends the same events as GET /v1/videos/{id}/events. gRPC keepalives take
+// the place of its heartbeat comments.
+func (s *VideoServer) WatchVideo(req *gostreamv1.WatchVideoRequest, stream grpc.ServerStreamingServer[gostreamv1.VideoEvent]) error {
+	videoID, err := parseVideoID(req.GetId())
+	if err != nil {
+		return err
+	}
+
+	c, _ := callerFrom(stream.Context())
+	events, err := s.watcher.Watch(stream.Context(), videoID, c.userID)
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for event := range events {
+		// The client went away; its context cancels the watch
+		if err := stream.Send(toVideoEvent(event)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseVideoID(raw string) (uuid.UUID, error) {
+	videoID, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "id must be a valid UUID")
+	}
+	return videoID, nil
+}
+
+// toStatus maps a usecase error to the gRPC status matching the REST handlers' response.
+// Errors no case matches get the status of their errs code.
+func toStatus(err error) error {
+	var (
+		overloaded  *usecase.OverloadedError
+		rateLimited *usecase.URLRateLimitedError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.As(err, &overloaded):
+		return status.Error(codes.Unavailable, "service is shedding background work, retry later")
+	case errors.As(err, &rateLimited):
+		return status.Error(codes.ResourceExhausted, "too many presigned URLs issued, retry later")
+	case errors.Is(err, repository.ErrVideoNotFound):
+		return status.Error(codes.NotFound, "video not found")
+	case errors.Is(err, usecase.ErrNotVideoOwner):
+		return status.Error(codes.PermissionDenied, "user does not own this video")
+	case errors.Is(err, model.ErrInvalidUserID),
+		errors.Is(err, model.ErrEmptyTitle),
+		errors.Is(err, model.ErrTitleTooLong),
+		errors.Is(err, model.ErrDescriptionTooLong),
+		errors.Is(err, model.ErrInvalidTag),
+		errors.Is(err, model.ErrTooManyTags),
+		errors.Is(err, model.ErrInvalidVisibility),
+		errors.Is(err, model.ErrInvalidPreviewDuration),
+		errors.Is(err, model.ErrExpiryInPast),
+		errors.Is(err, usecase.ErrUnknownABRProfile),
+		errors.Is(err, usecase.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, usecase.ErrQuotaExceeded),
+		errors.Is(err, usecase.ErrUploadExceedsQuota):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, usecase.ErrVideoAlreadyCompleted),
+		errors.Is(err, usecase.ErrVideoExpired),
+		errors.Is(err, usecase.ErrUploadMissing),
+		errors.Is(err, usecase.ErrNoVideoStream):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	switch errs.CodeOf(err) {
+	case errs.NotFound:
+		return status.Error(codes.NotFound, "resource not found")
+	case errs.Conflict:
+		return status.Error(codes.FailedPrecondition, "request conflicts with the current state")
+	case errs.Invalid:
+		return status.Error(codes.InvalidArgument, "request is invalid")
+	case errs.Transient:
+		return status.Error(codes.Unavailable, "service is temporarily unavailable, retry later")
+	default:
+		return status.Error(codes.Internal, "an unexpected error occurred")
+	}
+}
+
+var videoStatuses = map[model.Status]gostreamv1.VideoStatus{
+	model.StatusPendingUpload: gostreamv1.VideoStatus_VIDEO_STATUS_PENDING_UPLOAD,
+	model.StatusUploaded:      gostreamv1.VideoStatus_VIDEO_STATUS_UPLOADED,
+	model.StatusProcessing:    gostreamv1.VideoStatus_VIDEO_STATUS_PROCESSING,
+	model.StatusReady:         gostreamv1.VideoStatus_VIDEO_STATUS_READY,
+	model.StatusFailed:        gostreamv1.VideoStatus_VIDEO_STATUS_FAILED,
+	model.StatusDeleted:       gostreamv1.VideoStatus_VIDEO_STATUS_DELETED,
+	model.StatusArchived:      gostreamv1.VideoStatus_VIDEO_STATUS_ARCHIVED,
+	model.StatusExpired:       gostreamv1.VideoStatus_VIDEO_STATUS_EXPIRED,
+}
+
+// toVideoStatus returns VIDEO_STATUS_UNSPECIFIED for statuses the proto does not know yet.
+func toVideoStatus(s model.Status) gostreamv1.VideoStatus {
+	return videoStatuses[s]
+}
+
+// toViewerVideo is toVideo for responses other users than the owner may receive: the
+// playback URLs are left out unless callerID may see them.
+func (s *VideoServer) toViewerVideo(v *model.Video, callerID uuid.UUID) *gostreamv1.Video {
+	resp := toVideo(v)
+	if !v.PlaybackURLsVisibleTo(callerID, s.entitlementsEnforced) {
+		resp.HlsUrl = ""
+		resp.DashUrl = ""
+	}
+	return resp
+}
+
+func toVideo(v *model.Video) *gostreamv1.Video {
+	resp := &gostreamv1.Video{
+		Id:             v.ID.String(),
+		SortableId:     v.SortableID,
+		ShareSlug:      v.ShareSlug,
+		TitleSlug:      v.TitleSlug,
+		UserId:         v.UserID.String(),
+		Title:          v.Title,
+		Status:         toVideoStatus(v.Status),
+		OriginalUrl:    v.OriginalURL,
+		OriginalSize:   v.OriginalSize,
+		HlsUrl:         v.HLSURL,
+		DashUrl:        v.DashURL,
+		PreviewSeconds: int32(v.PreviewSeconds),
+		PreviewUrl:     v.PreviewURL,
+		CreatedAt:      timestamppb.New(v.CreatedAt),
+		UpdatedAt:      timestamppb.New(v.UpdatedAt),
+		FailureCode:    string(v.FailureCode),
+		FailureReason:  v.FailureReason,
+		Stale:          v.Stale,
+	}
+	if !v.ExpiresAt.IsZero() {
+		resp.ExpiresAt = timestamppb.New(v.ExpiresAt)
+	}
+	return resp
+}
+
+func toVideoEvent(event cache.VideoEvent) *gostreamv1.VideoEvent {
+	resp := &gostreamv1.VideoEvent{VideoId: event.VideoID.String()}
+	switch event.Type {
+	case cache.VideoEventStatus:
+		resp.Event = &gostreamv1.VideoEvent_Status{Status: toVideoStatus(event.Status)}
+	case cache.VideoEventProgress:
+		resp.Event = &gostreamv1.VideoEvent_TranscodeProgress{TranscodeProgress: int32(event.Progress)}
+	}
+	return resp
+}
+
+// timestamp converts an optional protobuf timestamp, returning the zero time when unset.
+func timestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
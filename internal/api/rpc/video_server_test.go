@@ -0,0 +1,509 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	gostreamv1 "github.com/hszk-dev/gostream/api/proto/gostream/v1"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/usecase"
+)
+
+type mockVideos struct {
+	createVideoFn       func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error)
+	getVideoFn          func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	transcodeProgressFn func(ctx context.Context, videoID uuid.UUID) (int, bool)
+	listVideosFn        func(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error)
+	triggerProcessFn    func(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error
+}
+
+func (m *mockVideos) CreateVideo(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error) {
+	if m.createVideoFn != nil {
+		return m.createVideoFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideos) GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	if m.getVideoFn != nil {
+		return m.getVideoFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockVideos) GetTranscodeProgress(ctx context.Context, videoID uuid.UUID) (int, bool) {
+	if m.transcodeProgressFn != nil {
+		return m.transcodeProgressFn(ctx, videoID)
+	}
+	return 0, false
+}
+
+func (m *mockVideos) ListVideos(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error) {
+	if m.listVideosFn != nil {
+		return m.listVideosFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideos) TriggerProcess(ctx context.Context, videoID uuid.UUID, input usecase.ProcessInput) error {
+	if m.triggerProcessFn != nil {
+		return m.triggerProcessFn(ctx, videoID, input)
+	}
+	return nil
+}
+
+type mockWatcher struct {
+	watchFn func(ctx context.Context, videoID, callerID uuid.UUID) (<-chan cache.VideoEvent, error)
+}
+
+func (m *mockWatcher) Watch(ctx context.Context, videoID, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+	return m.watchFn(ctx, videoID, callerID)
+}
+
+type mockAuthenticator struct {
+	keys map[string]uuid.UUID
+}
+
+func (m *mockAuthenticator) AuthenticateAPIKey(ctx context.Context, key string) (uuid.UUID, error) {
+	if userID, ok := m.keys[key]; ok {
+		return userID, nil
+	}
+	return uuid.Nil, errs.New(errs.Invalid, "invalid API key")
+}
+
+// newTestClient serves a VideoServer with the auth and recovery interceptors over an
+// in-memory connection.
+func newTestClient(t *testing.T, videos Videos, watcher usecase.VideoWatcher, auth *mockAuthenticator) gostreamv1.VideoServiceClient {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryRecoverer(logger), UnaryAuth(auth)),
+		grpc.ChainStreamInterceptor(StreamRecoverer(logger), StreamAuth(auth)),
+	)
+	gostreamv1.RegisterVideoServiceServer(srv, NewVideoServer(videos, watcher, false))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return gostreamv1.NewVideoServiceClient(conn)
+}
+
+func TestVideoServer_CreateVideo(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name      string
+		md        metadata.MD
+		req       *gostreamv1.CreateVideoRequest
+		createErr error
+		wantCode  codes.Code
+		wantUser  uuid.UUID
+	}{
+		{
+			name:     "gateway user",
+			md:       metadata.Pairs(userIDMetadata, owner.String()),
+			req:      &gostreamv1.CreateVideoRequest{UserId: owner.String(), Title: "Title", FileName: "video.mp4"},
+			wantCode: codes.OK,
+			wantUser: owner,
+		},
+		{
+			name:     "API key fills in its user",
+			md:       metadata.Pairs("authorization", "Bearer key"),
+			req:      &gostreamv1.CreateVideoRequest{Title: "Title", FileName: "video.mp4"},
+			wantCode: codes.OK,
+			wantUser: owner,
+		},
+		{
+			name:     "API key for another user",
+			md:       metadata.Pairs("authorization", "Bearer key"),
+			req:      &gostreamv1.CreateVideoRequest{UserId: other.String(), Title: "Title", FileName: "video.mp4"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "invalid API key",
+			md:       metadata.Pairs("authorization", "Bearer revoked"),
+			req:      &gostreamv1.CreateVideoRequest{Title: "Title", FileName: "video.mp4"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "missing title",
+			req:      &gostreamv1.CreateVideoRequest{UserId: owner.String(), FileName: "video.mp4"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:      "reused idempotency key",
+			req:       &gostreamv1.CreateVideoRequest{UserId: owner.String(), Title: "Title", FileName: "video.mp4", IdempotencyKey: "k"},
+			createErr: usecase.ErrIdempotencyKeyReused,
+			wantCode:  codes.AlreadyExists,
+		},
+		{
+			name:      "quota used up",
+			req:       &gostreamv1.CreateVideoRequest{UserId: owner.String(), Title: "Title", FileName: "video.mp4"},
+			createErr: usecase.ErrQuotaExceeded,
+			wantCode:  codes.ResourceExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideos{
+				createVideoFn: func(ctx context.Context, input usecase.CreateVideoInput) (*usecase.CreateVideoOutput, error) {
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					if input.UserID != tt.wantUser {
+						t.Errorf("user: got %s, expected %s", input.UserID, tt.wantUser)
+					}
+					return &usecase.CreateVideoOutput{
+						Video:     &model.Video{ID: uuid.New(), UserID: input.UserID, Title: input.Title, Status: model.StatusPendingUpload},
+						UploadURL: "http://minio/upload",
+					}, nil
+				},
+			}
+			client := newTestClient(t, videos, nil, &mockAuthenticator{keys: map[string]uuid.UUID{"key": owner}})
+
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			resp, err := client.CreateVideo(ctx, tt.req)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("CreateVideo() code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if err == nil && resp.GetUploadUrl() != "http://minio/upload" {
+				t.Errorf("upload URL: got %q", resp.GetUploadUrl())
+			}
+		})
+	}
+}
+
+func TestVideoServer_GetVideo(t *testing.T) {
+	videoID := uuid.New()
+	ownerID := uuid.New()
+	expiresAt := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		id           string
+		md           metadata.MD
+		video        *model.Video
+		getErr       error
+		wantCode     codes.Code
+		wantProgress bool
+	}{
+		{
+			name:         "processing video with progress",
+			id:           videoID.String(),
+			video:        &model.Video{ID: videoID, Status: model.StatusProcessing, ExpiresAt: expiresAt},
+			wantCode:     codes.OK,
+			wantProgress: true,
+		},
+		{
+			name:     "ready video",
+			id:       videoID.String(),
+			video:    &model.Video{ID: videoID, Status: model.StatusReady, HLSURL: "hls/master.m3u8"},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "private video for its owner",
+			id:       videoID.String(),
+			md:       metadata.Pairs(userIDMetadata, ownerID.String()),
+			video:    &model.Video{ID: videoID, UserID: ownerID, Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "private video for another user",
+			id:       videoID.String(),
+			md:       metadata.Pairs(userIDMetadata, uuid.New().String()),
+			video:    &model.Video{ID: videoID, UserID: ownerID, Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "invalid ID",
+			id:       "not-a-uuid",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "not found",
+			id:       videoID.String(),
+			getErr:   repository.ErrVideoNotFound,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "database down",
+			id:       videoID.String(),
+			getErr:   errs.Wrap(errs.Transient, errors.New("connection refused")),
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "unexpected error",
+			id:       videoID.String(),
+			getErr:   errors.New("boom"),
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideos{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return tt.video, tt.getErr
+				},
+				transcodeProgressFn: func(ctx context.Context, id uuid.UUID) (int, bool) {
+					return 42, true
+				},
+			}
+			client := newTestClient(t, videos, nil, &mockAuthenticator{})
+
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			resp, err := client.GetVideo(ctx, &gostreamv1.GetVideoRequest{Id: tt.id})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("GetVideo() code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			v := resp.GetVideo()
+			if v.GetStatus() != toVideoStatus(tt.video.Status) {
+				t.Errorf("status: got %s", v.GetStatus())
+			}
+			if tt.wantProgress != (v.TranscodeProgress != nil) {
+				t.Errorf("transcode progress: got %v, expected set %v", v.TranscodeProgress, tt.wantProgress)
+			}
+			if !tt.video.ExpiresAt.IsZero() && !v.GetExpiresAt().AsTime().Equal(expiresAt) {
+				t.Errorf("expires at: got %v, expected %v", v.GetExpiresAt().AsTime(), expiresAt)
+			}
+			if tt.video.ExpiresAt.IsZero() && v.ExpiresAt != nil {
+				t.Error("expires at must be unset for videos that never expire")
+			}
+		})
+	}
+}
+
+func TestVideoServer_TriggerProcess(t *testing.T) {
+	owner := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name        string
+		md          metadata.MD
+		processErr  error
+		wantCode    codes.Code
+		wantProcess bool
+	}{
+		{
+			name:        "gateway owner",
+			md:          metadata.Pairs(userIDMetadata, owner.String()),
+			wantCode:    codes.OK,
+			wantProcess: true,
+		},
+		{
+			name:     "gateway another user",
+			md:       metadata.Pairs(userIDMetadata, uuid.New().String()),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "anonymous",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:        "owner's API key",
+			md:          metadata.Pairs("authorization", "Bearer owner-key"),
+			wantCode:    codes.OK,
+			wantProcess: true,
+		},
+		{
+			name:     "another user's API key",
+			md:       metadata.Pairs("authorization", "Bearer other-key"),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:        "already completed",
+			md:          metadata.Pairs(userIDMetadata, owner.String()),
+			processErr:  usecase.ErrVideoAlreadyCompleted,
+			wantCode:    codes.FailedPrecondition,
+			wantProcess: true,
+		},
+		{
+			name:        "unknown ABR profile",
+			md:          metadata.Pairs(userIDMetadata, owner.String()),
+			processErr:  usecase.ErrUnknownABRProfile,
+			wantCode:    codes.InvalidArgument,
+			wantProcess: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processed := false
+			videos := &mockVideos{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, UserID: owner, Status: model.StatusUploaded}, nil
+				},
+				triggerProcessFn: func(ctx context.Context, id uuid.UUID, input usecase.ProcessInput) error {
+					processed = true
+					if input.ABRProfile != "screen" {
+						t.Errorf("ABR profile: got %q, expected screen", input.ABRProfile)
+					}
+					return tt.processErr
+				},
+			}
+			auth := &mockAuthenticator{keys: map[string]uuid.UUID{"owner-key": owner, "other-key": uuid.New()}}
+			client := newTestClient(t, videos, nil, auth)
+
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			_, err := client.TriggerProcess(ctx, &gostreamv1.TriggerProcessRequest{Id: videoID.String(), AbrProfile: "screen"})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("TriggerProcess() code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if processed != tt.wantProcess {
+				t.Errorf("processed: got %v, expected %v", processed, tt.wantProcess)
+			}
+		})
+	}
+}
+
+func TestVideoServer_ListVideos(t *testing.T) {
+	userID := uuid.New()
+	videos := &mockVideos{
+		listVideosFn: func(ctx context.Context, input usecase.ListVideosInput) (*usecase.ListVideosOutput, error) {
+			if input.UserID != userID || input.Limit != 2 || input.Cursor != "c1" {
+				t.Errorf("ListVideos(%+v): unexpected input", input)
+			}
+			page := []*model.Video{
+				{ID: uuid.New(), UserID: userID},
+				{ID: uuid.New(), UserID: userID},
+			}
+			if input.CallerID == userID {
+				page = append(page, &model.Video{ID: uuid.New(), UserID: userID, Visibility: model.VisibilityPrivate})
+			}
+			return &usecase.ListVideosOutput{Videos: page, NextCursor: "c2"}, nil
+		},
+	}
+	client := newTestClient(t, videos, nil, &mockAuthenticator{})
+
+	resp, err := client.ListVideos(context.Background(), &gostreamv1.ListVideosRequest{UserId: userID.String(), Limit: 2, Cursor: "c1"})
+	if err != nil {
+		t.Fatalf("ListVideos() unexpected error: %v", err)
+	}
+	if len(resp.GetVideos()) != 2 || resp.GetNextCursor() != "c2" {
+		t.Errorf("ListVideos() = %d videos, cursor %q", len(resp.GetVideos()), resp.GetNextCursor())
+	}
+
+	ownerCtx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(userIDMetadata, userID.String()))
+	resp, err = client.ListVideos(ownerCtx, &gostreamv1.ListVideosRequest{UserId: userID.String(), Limit: 2, Cursor: "c1"})
+	if err != nil {
+		t.Fatalf("ListVideos() as owner unexpected error: %v", err)
+	}
+	if len(resp.GetVideos()) != 3 {
+		t.Errorf("ListVideos() as owner = %d videos, want the private one too", len(resp.GetVideos()))
+	}
+
+	_, err = client.ListVideos(context.Background(), &gostreamv1.ListVideosRequest{UserId: userID.String(), Limit: -1})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("negative limit: code = %s, want InvalidArgument", code)
+	}
+}
+
+func TestVideoServer_WatchVideo(t *testing.T) {
+	videoID := uuid.New()
+
+	t.Run("streams events until the watch ends", func(t *testing.T) {
+		watcher := &mockWatcher{
+			watchFn: func(ctx context.Context, id, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+				events := make(chan cache.VideoEvent, 3)
+				events <- cache.VideoEvent{Type: cache.VideoEventStatus, VideoID: id, Status: model.StatusProcessing}
+				events <- cache.VideoEvent{Type: cache.VideoEventProgress, VideoID: id, Progress: 50}
+				events <- cache.VideoEvent{Type: cache.VideoEventStatus, VideoID: id, Status: model.StatusReady}
+				close(events)
+				return events, nil
+			},
+		}
+		client := newTestClient(t, &mockVideos{}, watcher, &mockAuthenticator{})
+
+		stream, err := client.WatchVideo(context.Background(), &gostreamv1.WatchVideoRequest{Id: videoID.String()})
+		if err != nil {
+			t.Fatalf("WatchVideo() unexpected error: %v", err)
+		}
+
+		var got []*gostreamv1.VideoEvent
+		for {
+			event, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Recv() unexpected error: %v", err)
+			}
+			got = append(got, event)
+		}
+
+		if len(got) != 3 {
+			t.Fatalf("received %d events, expected 3", len(got))
+		}
+		if got[0].GetStatus() != gostreamv1.VideoStatus_VIDEO_STATUS_PROCESSING {
+			t.Errorf("first event: got %v", got[0])
+		}
+		if got[1].GetTranscodeProgress() != 50 {
+			t.Errorf("progress event: got %v", got[1])
+		}
+		if got[2].GetStatus() != gostreamv1.VideoStatus_VIDEO_STATUS_READY || got[2].GetVideoId() != videoID.String() {
+			t.Errorf("last event: got %v", got[2])
+		}
+	})
+
+	t.Run("unknown video", func(t *testing.T) {
+		watcher := &mockWatcher{
+			watchFn: func(ctx context.Context, id, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+				return nil, repository.ErrVideoNotFound
+			},
+		}
+		client := newTestClient(t, &mockVideos{}, watcher, &mockAuthenticator{})
+
+		stream, err := client.WatchVideo(context.Background(), &gostreamv1.WatchVideoRequest{Id: videoID.String()})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if code := status.Code(err); code != codes.NotFound {
+			t.Errorf("WatchVideo() code = %s, want NotFound", code)
+		}
+	})
+}
+
+func TestUnaryRecoverer(t *testing.T) {
+	videos := &mockVideos{
+		getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			panic("nil map")
+		},
+	}
+	client := newTestClient(t, videos, nil, &mockAuthenticator{})
+
+	_, err := client.GetVideo(context.Background(), &gostreamv1.GetVideoRequest{Id: uuid.New().String()})
+	if code := status.Code(err); code != codes.Internal {
+		t.Errorf("GetVideo() code = %s, want Internal", code)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// WorkerProfile describes a worker node class, so a heterogeneous fleet can run
+// the same binary and differ only in WORKER_PROFILE.
+type WorkerProfile struct {
+	Name        string
+	Concurrency int      // tasks transcoded at once
+	Codecs      []string // FFmpeg video encoders the node may use; the first one available is used
+	Queues      []string // queues consumed as "name[:weight[:prefetch]]", e.g. GPU nodes favour the GPU-only queue
+}
+
+// workerProfiles are the built-in node classes.
+// cpu-small matches the behavior of workers that predate profiles.
+var workerProfiles = map[string]WorkerProfile{
+	"cpu-small": {
+		Concurrency: 1,
+		Codecs:      []string{"libx264"},
+		Queues:      []string{"transcode_tasks"},
+	},
+	"cpu-large": {
+		Concurrency: 4,
+		Codecs:      []string{"libx264", "libx265"},
+		Queues:      []string{"transcode_tasks"},
+	},
+	"gpu": {
+		Concurrency: 2,
+		Codecs:      []string{"h264_nvenc", "hevc_nvenc", "libx264"},
+		Queues:      []string{"transcode_tasks_gpu:3", "transcode_tasks:1"},
+	},
+}
+
+// WorkerProfile resolves the configured node-class profile.
+// WORKER_CONCURRENCY, WORKER_CODECS and WORKER_QUEUES override the profile's values when set.
+func (c WorkerConfig) WorkerProfile() (WorkerProfile, error) {
+	base, ok := workerProfiles[c.Profile]
+	if !ok {
+		names := make([]string, 0, len(workerProfiles))
+		for name := range workerProfiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return WorkerProfile{}, fmt.Errorf("unknown worker profile %q (available: %v)", c.Profile, names)
+	}
+
+	profile := WorkerProfile{
+		Name:        c.Profile,
+		Concurrency: base.Concurrency,
+		Codecs:      slices.Clone(base.Codecs),
+		Queues:      slices.Clone(base.Queues),
+	}
+	if c.Concurrency > 0 {
+		profile.Concurrency = c.Concurrency
+	}
+	if len(c.Codecs) > 0 {
+		profile.Codecs = c.Codecs
+	}
+	if len(c.Queues) > 0 {
+		profile.Queues = c.Queues
+	}
+
+	return profile, nil
+}
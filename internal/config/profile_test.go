@@ -0,0 +1,74 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWorkerConfig_WorkerProfile(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             WorkerConfig
+		wantConcurrency int
+		wantCodecs      []string
+		wantQueues      []string
+		wantErr         bool
+	}{
+		{
+			name:            "cpu-small",
+			cfg:             WorkerConfig{Profile: "cpu-small"},
+			wantConcurrency: 1,
+			wantCodecs:      []string{"libx264"},
+			wantQueues:      []string{"transcode_tasks"},
+		},
+		{
+			name:            "gpu",
+			cfg:             WorkerConfig{Profile: "gpu"},
+			wantConcurrency: 2,
+			wantCodecs:      []string{"h264_nvenc", "hevc_nvenc", "libx264"},
+			wantQueues:      []string{"transcode_tasks_gpu:3", "transcode_tasks:1"},
+		},
+		{
+			name: "overrides",
+			cfg: WorkerConfig{
+				Profile:     "cpu-large",
+				Concurrency: 8,
+				Codecs:      []string{"libx265"},
+				Queues:      []string{"transcode_tasks_bulk"},
+			},
+			wantConcurrency: 8,
+			wantCodecs:      []string{"libx265"},
+			wantQueues:      []string{"transcode_tasks_bulk"},
+		},
+		{
+			name:    "unknown profile",
+			cfg:     WorkerConfig{Profile: "tpu"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.WorkerProfile()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WorkerProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Name != tt.cfg.Profile {
+				t.Errorf("Name = %q, want %q", got.Name, tt.cfg.Profile)
+			}
+			if got.Concurrency != tt.wantConcurrency {
+				t.Errorf("Concurrency = %d, want %d", got.Concurrency, tt.wantConcurrency)
+			}
+			if !slices.Equal(got.Codecs, tt.wantCodecs) {
+				t.Errorf("Codecs = %v, want %v", got.Codecs, tt.wantCodecs)
+			}
+			if !slices.Equal(got.Queues, tt.wantQueues) {
+				t.Errorf("Queues = %v, want %v", got.Queues, tt.wantQueues)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/storage"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+// FFmpegCheck verifies that FFmpeg provides one of the allowed video encoders and every
+// other encoder and muxer the worker uses, and that ffprobe runs.
+func FFmpegCheck(ffmpegPath, ffprobePath string, codecs []string, formats transcoder.OutputFormats) Check {
+	return Check{
+		Name: "ffmpeg",
+		Run: func(ctx context.Context) Result {
+			encoder, err := transcoder.SelectVideoEncoder(ctx, ffmpegPath, codecs)
+			if errors.Is(err, transcoder.ErrNoUsableEncoder) {
+				return fail("Install an FFmpeg build with one of these encoders, or change WORKER_PROFILE/WORKER_CODECS", "%v", err)
+			}
+			if err != nil {
+				return fail("Install FFmpeg (e.g. apt install ffmpeg) and make sure it is on the worker's PATH", "%v", err)
+			}
+
+			var muxers []string
+			if formats.HLS() {
+				muxers = append(muxers, "hls")
+			}
+			if formats.DASH() {
+				muxers = append(muxers, "dash")
+			}
+			// aac encodes the audio renditions, mjpeg the thumbnails
+			missing, err := transcoder.MissingFeatures(ctx, ffmpegPath, []string{"aac", "mjpeg"}, muxers)
+			if err != nil {
+				return fail("Check that FFmpeg runs: "+ffmpegPath+" -version", "%v", err)
+			}
+			if len(missing) > 0 {
+				return fail("Install a full FFmpeg build; minimal builds leave out features the worker needs",
+					"missing %s", strings.Join(missing, ", "))
+			}
+
+			if err := exec.CommandContext(ctx, ffprobePath, "-version").Run(); err != nil {
+				return fail("Install ffprobe (it ships with FFmpeg); the worker probes every upload with it",
+					"ffprobe: %v", err)
+			}
+			return ok("video encoder %s, %s muxer", encoder, strings.Join(muxers, " and "))
+		},
+	}
+}
+
+// CORSSource reads the CORS rules of the bucket.
+type CORSSource interface {
+	BucketCORS(ctx context.Context) ([]storage.CORSRule, error)
+}
+
+// BucketCORSCheck verifies that browsers may PUT to the bucket, which direct uploads to
+// presigned URLs require. Failures are warnings: API-only clients do not need CORS, and
+// MinIO applies its server-wide MINIO_API_CORS_ALLOW_ORIGIN when a bucket has no rules.
+func BucketCORSCheck(bucket string, src CORSSource) Check {
+	remedy := "Allow PUT from your web app's origin for browser uploads, e.g.\n" +
+		"mc cors set ALIAS/" + bucket + " cors.xml (S3: aws s3api put-bucket-cors)"
+	return Check{
+		Name: "bucket CORS",
+		Run: func(ctx context.Context) Result {
+			rules, err := src.BucketCORS(ctx)
+			if err != nil {
+				return warn(remedy, "could not read the CORS configuration of %q: %v", bucket, err)
+			}
+			for _, rule := range rules {
+				if slices.Contains(rule.AllowedMethods, http.MethodPut) {
+					return ok("PUT allowed from %s", strings.Join(rule.AllowedOrigins, ", "))
+				}
+			}
+			if len(rules) == 0 {
+				return warn(remedy, "bucket %q has no CORS rules", bucket)
+			}
+			return warn(remedy, "no CORS rule of bucket %q allows PUT", bucket)
+		},
+	}
+}
+
+// QueueInspector is a connected message queue client.
+type QueueInspector interface {
+	Depth(ctx context.Context) (int, error)
+	Close() error
+}
+
+// QueueCheck connects to the message queue, which declares every queue the services use,
+// and reads the task queue depth. Declaring needs configure permission as well as
+// read and write, so this catches users that can publish but not start a service.
+func QueueCheck(connect func(ctx context.Context) (QueueInspector, error)) Check {
+	return Check{
+		Name: "queue",
+		Run: func(ctx context.Context) Result {
+			client, err := connect(ctx)
+			if err != nil {
+				return fail(queueRemedy(err), "%v", err)
+			}
+			defer func() { _ = client.Close() }() // Best-effort cleanup
+
+			depth, err := client.Depth(ctx)
+			if err != nil {
+				return fail(queueRemedy(err), "%v", err)
+			}
+			return ok("queues declared, %d tasks waiting", depth)
+		},
+	}
+}
+
+// queueRemedy suggests a fix for a RabbitMQ error.
+func queueRemedy(err error) string {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		switch amqpErr.Code {
+		case amqp.AccessRefused:
+			return "Check RABBITMQ_USER/RABBITMQ_PASSWORD and grant configure, write and read permissions:\n" +
+				"rabbitmqctl set_permissions -p VHOST USER '.*' '.*' '.*'"
+		case amqp.PreconditionFailed:
+			return "A queue exists with different arguments, e.g. one declared before dead-lettering was\n" +
+				"added; delete the queue once it is drained, or set x-dead-letter-exchange with a policy"
+		case amqp.NotAllowed:
+			return "Create the virtual host or grant the user access to it"
+		}
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return "Check that RabbitMQ is running and RABBITMQ_HOST/RABBITMQ_PORT point at it"
+	}
+	return "Check RABBITMQ_HOST, RABBITMQ_PORT and the RabbitMQ server log"
+}
+
+// SchemaVersioner reports the applied migration version.
+type SchemaVersioner interface {
+	SchemaVersion(ctx context.Context) (int64, error)
+}
+
+// MigrationsCheck compares the applied migration version with latest, the newest
+// migration shipped with this build. A latest of 0 skips the comparison.
+func MigrationsCheck(db SchemaVersioner, latest int64) Check {
+	return Check{
+		Name: "migrations",
+		Run: func(ctx context.Context) Result {
+			version, err := db.SchemaVersion(ctx)
+			if errors.Is(err, repository.ErrSchemaDirty) {
+				return fail("Repair the schema by hand, then record the last good version:\n"+
+					"migrate -path db/migrations -database DSN force VERSION && make migrate-up", "%v", err)
+			}
+			if err != nil {
+				return fail("Apply the migrations: make migrate-up", "%v", err)
+			}
+
+			switch {
+			case latest == 0:
+				return ok("schema version %d", version)
+			case version < latest:
+				return fail("Apply the pending migrations: make migrate-up",
+					"schema version %d, this build expects %d", version, latest)
+			case version > latest:
+				return warn("Upgrade the services to the release that shipped the newer migrations",
+					"schema version %d is newer than this build's %d", version, latest)
+			}
+			return ok("schema version %d is current", version)
+		},
+	}
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+// LatestMigration returns the highest version among the golang-migrate files in dir.
+func LatestMigration(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		m := migrationFile.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration %s: %w", filepath.Join(dir, entry.Name()), err)
+		}
+		latest = max(latest, version)
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations found in %s", dir)
+	}
+	return latest, nil
+}
+
+// RedisLatencyCheck pings Redis samples times and warns when the median round trip
+// exceeds threshold. Every playback request reads the cache and token state from Redis.
+func RedisLatencyCheck(ping func(ctx context.Context) error, samples int, threshold time.Duration) Check {
+	return Check{
+		Name: "redis latency",
+		Run: func(ctx context.Context) Result {
+			durations := make([]time.Duration, 0, samples)
+			for range samples {
+				start := time.Now()
+				if err := ping(ctx); err != nil {
+					return fail("Check that Redis is running and REDIS_HOST, REDIS_PORT and REDIS_PASSWORD are correct",
+						"ping: %v", err)
+				}
+				durations = append(durations, time.Since(start))
+			}
+			slices.Sort(durations)
+
+			median, slowest := durations[len(durations)/2], durations[len(durations)-1]
+			if median > threshold {
+				return warn("Run Redis close to the API and check the host for swapping or CPU contention",
+					"median round trip %s over %d pings (max %s), above %s", median, samples, slowest, threshold)
+			}
+			return ok("median round trip %s over %d pings (max %s)", median, samples, slowest)
+		},
+	}
+}
+
+// presignSkewLimit is the clock difference S3-compatible servers tolerate when
+// verifying request signatures, presigned URLs included.
+const presignSkewLimit = 15 * time.Minute
+
+// ClockSkewCheck compares the local clock with a remote one. Skew above presignSkewLimit
+// fails because storage rejects the signatures; skew above tolerance only warns, since
+// token expiry and time-to-ready metrics are merely off by that much.
+func ClockSkewCheck(source string, remote func(ctx context.Context) (time.Time, error), tolerance time.Duration) Check {
+	return Check{
+		Name: "clock skew (" + source + ")",
+		Run: func(ctx context.Context) Result {
+			before := time.Now()
+			remoteTime, err := remote(ctx)
+			if err != nil {
+				return fail("Check that "+source+" is reachable", "%v", err)
+			}
+			// Assume the remote clock was read halfway through the round trip
+			local := before.Add(time.Since(before) / 2)
+
+			skew := remoteTime.Sub(local).Round(time.Millisecond)
+			direction := "ahead"
+			if skew < 0 {
+				skew, direction = -skew, "behind"
+			}
+
+			remedy := "Synchronize both hosts with NTP (chrony or systemd-timesyncd)"
+			switch {
+			case skew > presignSkewLimit:
+				return fail(remedy, "%s is %s by %s; presigned URLs will be rejected", source, direction, skew)
+			case skew > tolerance:
+				return warn(remedy, "%s is %s by %s", source, direction, skew)
+			}
+			return ok("%s is %s by %s", source, direction, skew)
+		},
+	}
+}
+
+// HTTPDate returns a remote clock read from the Date header of a HEAD request to url.
+// The header has one second resolution, so tolerances below that are meaningless.
+func HTTPDate(client *http.Client, url string) func(ctx context.Context) (time.Time, error) {
+	return func(ctx context.Context) (time.Time, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			return time.Time{}, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return time.Time{}, err
+		}
+		_ = resp.Body.Close()
+
+		date := resp.Header.Get("Date")
+		if date == "" {
+			return time.Time{}, fmt.Errorf("%s sent no Date header", url)
+		}
+		return http.ParseTime(date)
+	}
+}
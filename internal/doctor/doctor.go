@@ -0,0 +1,96 @@
+// Package doctor diagnoses a gostream installation: it checks that every dependency is
+// reachable and configured the way the services expect, and tells the operator how to
+// fix what is not.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusOK means the check passed.
+	StatusOK Status = "OK"
+	// StatusWarn means the installation works but something is likely to cause trouble.
+	StatusWarn Status = "WARN"
+	// StatusFail means a service will not work until the problem is fixed.
+	StatusFail Status = "FAIL"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Name   string
+	Status Status
+	// Detail describes what was found.
+	Detail string
+	// Remedy tells the operator how to fix a warning or failure; empty for passed checks.
+	Remedy string
+}
+
+// Check is a single diagnostic.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) Result
+}
+
+// ok, warn and fail build results; the name is filled in by Run.
+func ok(format string, args ...any) Result {
+	return Result{Status: StatusOK, Detail: fmt.Sprintf(format, args...)}
+}
+
+func warn(remedy, format string, args ...any) Result {
+	return Result{Status: StatusWarn, Detail: fmt.Sprintf(format, args...), Remedy: remedy}
+}
+
+func fail(remedy, format string, args ...any) Result {
+	return Result{Status: StatusFail, Detail: fmt.Sprintf(format, args...), Remedy: remedy}
+}
+
+// Unavailable reports a check that could not run because its dependency could not be
+// reached, e.g. when connecting failed before the check was built.
+func Unavailable(name string, err error, remedy string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) Result {
+			return fail(remedy, "%v", err)
+		},
+	}
+}
+
+// Run runs the checks in order, each bounded by timeout, so one hung dependency does not
+// hide the results of the others.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		result := check.Run(checkCtx)
+		cancel()
+
+		result.Name = check.Name
+		results = append(results, result)
+	}
+	return results
+}
+
+// Report writes one line per result, followed by the remedy of each warning or failure
+// and a summary. Returns false if any check failed.
+func Report(w io.Writer, results []Result) bool {
+	counts := make(map[Status]int, 3)
+	for _, r := range results {
+		counts[r.Status]++
+		fmt.Fprintf(w, "[%-4s] %s: %s\n", r.Status, r.Name, r.Detail)
+		if r.Remedy != "" {
+			for _, line := range strings.Split(r.Remedy, "\n") {
+				fmt.Fprintf(w, "       %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[StatusOK], counts[StatusWarn], counts[StatusFail])
+	return counts[StatusFail] == 0
+}
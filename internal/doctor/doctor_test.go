@@ -0,0 +1,349 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/storage"
+)
+
+func TestRun(t *testing.T) {
+	var deadlineSet bool
+	checks := []Check{
+		{Name: "first", Run: func(ctx context.Context) Result {
+			_, deadlineSet = ctx.Deadline()
+			return ok("fine")
+		}},
+		Unavailable("second", errors.New("connection refused"), "start it"),
+	}
+
+	results := Run(context.Background(), checks, time.Second)
+
+	if !deadlineSet {
+		t.Error("check ran without a deadline")
+	}
+	want := []Result{
+		{Name: "first", Status: StatusOK, Detail: "fine"},
+		{Name: "second", Status: StatusFail, Detail: "connection refused", Remedy: "start it"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("results[%d] = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []Result
+		wantPassed bool
+		wantOutput string
+	}{
+		{
+			name: "warnings pass",
+			results: []Result{
+				{Name: "ffmpeg", Status: StatusOK, Detail: "video encoder libx264"},
+				{Name: "bucket CORS", Status: StatusWarn, Detail: "no rules", Remedy: "allow PUT\nwith mc"},
+			},
+			wantPassed: true,
+			wantOutput: "[OK  ] ffmpeg: video encoder libx264\n" +
+				"[WARN] bucket CORS: no rules\n" +
+				"       allow PUT\n" +
+				"       with mc\n" +
+				"\n1 passed, 1 warnings, 0 failed\n",
+		},
+		{
+			name: "failure",
+			results: []Result{
+				{Name: "migrations", Status: StatusFail, Detail: "behind", Remedy: "make migrate-up"},
+			},
+			wantPassed: false,
+			wantOutput: "[FAIL] migrations: behind\n" +
+				"       make migrate-up\n" +
+				"\n0 passed, 0 warnings, 1 failed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if passed := Report(&buf, tt.results); passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v", passed, tt.wantPassed)
+			}
+			if buf.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", buf.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+type corsSourceFunc func(ctx context.Context) ([]storage.CORSRule, error)
+
+func (f corsSourceFunc) BucketCORS(ctx context.Context) ([]storage.CORSRule, error) {
+	return f(ctx)
+}
+
+func TestBucketCORSCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      []storage.CORSRule
+		err        error
+		wantStatus Status
+	}{
+		{
+			name:       "PUT allowed",
+			rules:      []storage.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET", "PUT"}}},
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "no rules",
+			rules:      []storage.CORSRule{},
+			wantStatus: StatusWarn,
+		},
+		{
+			name:       "read only",
+			rules:      []storage.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}},
+			wantStatus: StatusWarn,
+		},
+		{
+			name:       "unsupported",
+			err:        errors.New("NotImplemented"),
+			wantStatus: StatusWarn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := BucketCORSCheck("videos", corsSourceFunc(func(ctx context.Context) ([]storage.CORSRule, error) {
+				return tt.rules, tt.err
+			}))
+			result := check.Run(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+			if result.Status != StatusOK && result.Remedy == "" {
+				t.Error("remedy is empty")
+			}
+		})
+	}
+}
+
+type fakeQueue struct {
+	depth  int
+	err    error
+	closed bool
+}
+
+func (q *fakeQueue) Depth(ctx context.Context) (int, error) { return q.depth, q.err }
+
+func (q *fakeQueue) Close() error {
+	q.closed = true
+	return nil
+}
+
+func TestQueueCheck(t *testing.T) {
+	t.Run("connected", func(t *testing.T) {
+		q := &fakeQueue{depth: 3}
+		result := QueueCheck(func(ctx context.Context) (QueueInspector, error) { return q, nil }).Run(context.Background())
+		if result.Status != StatusOK {
+			t.Errorf("status = %s, want OK (%s)", result.Status, result.Detail)
+		}
+		if !q.closed {
+			t.Error("client was not closed")
+		}
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantRemedy string
+	}{
+		{
+			name:       "access refused",
+			err:        fmt.Errorf("failed to declare queue video_tasks: %w", &amqp.Error{Code: amqp.AccessRefused}),
+			wantRemedy: "rabbitmqctl set_permissions",
+		},
+		{
+			name:       "queue arguments differ",
+			err:        fmt.Errorf("failed to declare queue video_tasks: %w", &amqp.Error{Code: amqp.PreconditionFailed}),
+			wantRemedy: "x-dead-letter-exchange",
+		},
+		{
+			name:       "other",
+			err:        errors.New("dial tcp: i/o timeout"),
+			wantRemedy: "RABBITMQ_HOST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := QueueCheck(func(ctx context.Context) (QueueInspector, error) { return nil, tt.err }).Run(context.Background())
+			if result.Status != StatusFail {
+				t.Errorf("status = %s, want FAIL", result.Status)
+			}
+			if !strings.Contains(result.Remedy, tt.wantRemedy) {
+				t.Errorf("remedy = %q, want it to mention %q", result.Remedy, tt.wantRemedy)
+			}
+		})
+	}
+}
+
+type schemaVersionFunc func(ctx context.Context) (int64, error)
+
+func (f schemaVersionFunc) SchemaVersion(ctx context.Context) (int64, error) {
+	return f(ctx)
+}
+
+func TestMigrationsCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    int64
+		err        error
+		latest     int64
+		wantStatus Status
+		wantRemedy string
+	}{
+		{name: "current", version: 21, latest: 21, wantStatus: StatusOK},
+		{name: "latest unknown", version: 21, wantStatus: StatusOK},
+		{name: "behind", version: 19, latest: 21, wantStatus: StatusFail, wantRemedy: "make migrate-up"},
+		{name: "ahead", version: 22, latest: 21, wantStatus: StatusWarn},
+		{name: "dirty", err: fmt.Errorf("schema version 20: %w", repository.ErrSchemaDirty), latest: 21, wantStatus: StatusFail, wantRemedy: "force"},
+		{name: "never migrated", err: errors.New("database has no applied migrations"), latest: 21, wantStatus: StatusFail, wantRemedy: "make migrate-up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := schemaVersionFunc(func(ctx context.Context) (int64, error) { return tt.version, tt.err })
+			result := MigrationsCheck(db, tt.latest).Run(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+			if !strings.Contains(result.Remedy, tt.wantRemedy) {
+				t.Errorf("remedy = %q, want it to mention %q", result.Remedy, tt.wantRemedy)
+			}
+		})
+	}
+}
+
+func TestLatestMigration(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"000001_create_videos_table.up.sql",
+		"000001_create_videos_table.down.sql",
+		"000012_add_index.up.sql",
+		"000013_add_column.down.sql",
+		"README.md",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := LatestMigration(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if latest != 12 {
+		t.Errorf("latest = %d, want 12", latest)
+	}
+
+	if _, err := LatestMigration(t.TempDir()); err == nil {
+		t.Error("expected error for a directory without migrations")
+	}
+}
+
+func TestRedisLatencyCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		delay      time.Duration
+		err        error
+		threshold  time.Duration
+		wantStatus Status
+	}{
+		{name: "fast", threshold: time.Second, wantStatus: StatusOK},
+		{name: "slow", delay: 2 * time.Millisecond, threshold: time.Millisecond, wantStatus: StatusWarn},
+		{name: "unreachable", err: errors.New("connection refused"), threshold: time.Second, wantStatus: StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pings := 0
+			ping := func(ctx context.Context) error {
+				pings++
+				time.Sleep(tt.delay)
+				return tt.err
+			}
+			result := RedisLatencyCheck(ping, 3, tt.threshold).Run(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+			if tt.err == nil && pings != 3 {
+				t.Errorf("pings = %d, want 3", pings)
+			}
+		})
+	}
+}
+
+func TestClockSkewCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		offset     time.Duration
+		wantStatus Status
+		wantDetail string
+	}{
+		{name: "in sync", offset: 0, wantStatus: StatusOK},
+		{name: "ahead", offset: 10 * time.Second, wantStatus: StatusWarn, wantDetail: "ahead"},
+		{name: "behind", offset: -10 * time.Second, wantStatus: StatusWarn, wantDetail: "behind"},
+		{name: "beyond signature limit", offset: 20 * time.Minute, wantStatus: StatusFail, wantDetail: "presigned URLs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := func(ctx context.Context) (time.Time, error) {
+				return time.Now().Add(tt.offset), nil
+			}
+			result := ClockSkewCheck("PostgreSQL", remote, 2*time.Second).Run(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+			if !strings.Contains(result.Detail, tt.wantDetail) {
+				t.Errorf("detail = %q, want it to mention %q", result.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestHTTPDate(t *testing.T) {
+	date := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		w.Header().Set("Date", date.Format(http.TimeFormat))
+	}))
+	defer server.Close()
+
+	got, err := HTTPDate(server.Client(), server.URL+"/minio/health/live")(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(date) {
+		t.Errorf("date = %v, want %v", got, date)
+	}
+}
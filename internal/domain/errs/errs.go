@@ -0,0 +1,82 @@
+// Package errs classifies errors with a small set of codes shared by every layer.
+//
+// Infrastructure adapters attach a code where they translate driver errors, domain
+// sentinels carry one from their declaration, and consumers branch on the code instead of
+// on each sentinel: HTTP handlers pick a status and the worker decides whether a task is
+// worth retrying. Codes are errors themselves, so errors.Is(err, errs.NotFound) reports
+// whether any error in err's tree carries the code.
+package errs
+
+import "errors"
+
+// Code is the class of an error.
+type Code string
+
+const (
+	// NotFound means the requested entity or object does not exist.
+	NotFound Code = "not found"
+	// Conflict means the request clashes with the current state, e.g. a duplicate key
+	// or a stale version.
+	Conflict Code = "conflict"
+	// Invalid means the input can never succeed as given.
+	Invalid Code = "invalid"
+	// Transient means the failure is temporary (timeouts, lost connections, overload)
+	// and the same request may succeed later.
+	Transient Code = "transient"
+	// Permanent means retrying cannot succeed and the work must be given up.
+	Permanent Code = "permanent"
+)
+
+func (c Code) Error() string {
+	return string(c)
+}
+
+// Error is an error with a code.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the error's code, so errors.Is(err, errs.Conflict) matches.
+func (e *Error) Is(target error) bool {
+	code, ok := target.(Code)
+	return ok && code == e.Code
+}
+
+// New returns an error with the given code and text, typically to declare a sentinel.
+func New(code Code, text string) error {
+	return &Error{Code: code, Err: errors.New(text)}
+}
+
+// Wrap attaches code to err, keeping err in the chain. Returns nil if err is nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// CodeOf returns the code of the outermost coded error in err's tree, or "" if no error
+// in it has a code.
+func CodeOf(err error) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
+
+// Retryable reports whether the operation that returned err may succeed if repeated.
+// Errors coded Permanent or Invalid are not retryable; everything else, including errors
+// without a code, is.
+func Retryable(err error) bool {
+	return !errors.Is(err, Permanent) && !errors.Is(err, Invalid)
+}
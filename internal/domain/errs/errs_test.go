@@ -0,0 +1,94 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError_Is(t *testing.T) {
+	sentinel := New(NotFound, "video not found")
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{name: "sentinel matches its code", err: sentinel, target: NotFound, want: true},
+		{name: "sentinel matches itself", err: sentinel, target: sentinel, want: true},
+		{name: "other code", err: sentinel, target: Conflict, want: false},
+		{name: "wrapped sentinel", err: fmt.Errorf("get video: %w", sentinel), target: NotFound, want: true},
+		{name: "code of an inner error", err: fmt.Errorf("%w: %w", New(Permanent, "gave up"), sentinel), target: NotFound, want: true},
+		{name: "uncoded error", err: errors.New("boom"), target: Transient, want: false},
+		{name: "wrapped driver error", err: Wrap(Transient, errors.New("connection reset")), target: Transient, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(Transient, nil) != nil {
+		t.Error("Wrap(nil) should return nil")
+	}
+
+	cause := errors.New("connection reset")
+	err := Wrap(Transient, cause)
+	if err.Error() != cause.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), cause.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("wrapped error should keep its cause in the chain")
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "uncoded", err: errors.New("boom"), want: ""},
+		{name: "coded", err: New(Conflict, "duplicate"), want: Conflict},
+		{name: "wrapped", err: fmt.Errorf("create video: %w", New(Conflict, "duplicate")), want: Conflict},
+		{name: "outermost code wins", err: fmt.Errorf("%w: %w", New(Permanent, "gave up"), New(NotFound, "missing")), want: Permanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "uncoded", err: errors.New("boom"), want: true},
+		{name: "transient", err: Wrap(Transient, errors.New("timeout")), want: true},
+		{name: "not found", err: New(NotFound, "missing"), want: true},
+		{name: "permanent", err: fmt.Errorf("task: %w", New(Permanent, "gave up")), want: false},
+		{name: "invalid", err: New(Invalid, "no video stream"), want: false},
+		{name: "invalid behind a transient wrapper", err: Wrap(Transient, New(Invalid, "bad input")), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Retryable(tt.err); got != tt.want {
+				t.Errorf("Retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
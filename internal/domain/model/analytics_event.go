@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsStream is a family of events exported together under its own prefix.
+type AnalyticsStream string
+
+const (
+	// AnalyticsStreamVideoLifecycle covers uploads and transcode attempts.
+	AnalyticsStreamVideoLifecycle AnalyticsStream = "video_lifecycle"
+	// AnalyticsStreamPlayback covers playback progress beacons.
+	AnalyticsStreamPlayback AnalyticsStream = "playback"
+)
+
+// AnalyticsStreams lists every exported stream.
+var AnalyticsStreams = []AnalyticsStream{AnalyticsStreamVideoLifecycle, AnalyticsStreamPlayback}
+
+// Analytics event types.
+const (
+	EventVideoCreated       = "video.created"
+	EventTranscodeSucceeded = "transcode.succeeded"
+	EventTranscodeFailed    = "transcode.failed"
+	EventPlaybackProgress   = "playback.progress"
+)
+
+// AnalyticsEvent is a single row of the analytics export.
+type AnalyticsEvent struct {
+	Type       string
+	OccurredAt time.Time
+	VideoID    uuid.UUID
+	UserID     uuid.UUID
+	// Attributes holds type-specific fields, e.g. the attempt number of a transcode event.
+	Attributes map[string]any
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are recognizable to secret scanners.
+const APIKeyPrefix = "gsk_"
+
+const (
+	// apiKeySecretBytes is the entropy of an API key (256 bits).
+	apiKeySecretBytes = 32
+
+	// apiKeyHintLength is how much of a key after APIKeyPrefix is kept to tell keys apart.
+	apiKeyHintLength = 6
+
+	maxAPIKeyNameLength = 100
+)
+
+var ErrAPIKeyNameTooLong = errors.New("API key name exceeds maximum length")
+
+// APIKey authenticates requests as its user. Only the SHA-256 of the key is stored; the
+// key itself is shown once, when it is issued. A hash without a salt or stretching is
+// enough, as keys are random rather than chosen by people.
+type APIKey struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Name   string
+	// Hint is the start of the key, shown in listings so the owner can tell keys apart.
+	Hint       string
+	Hash       string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewAPIKey creates an APIKey for a user, returning it with the key to hand to the user.
+// The name is optional.
+func NewAPIKey(userID uuid.UUID, name string) (*APIKey, string, error) {
+	if userID == uuid.Nil {
+		return nil, "", ErrInvalidUserID
+	}
+
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, "", ErrAPIKeyNameTooLong
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	_, _ = rand.Read(secret)
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Hint:      key[:len(APIKeyPrefix)+apiKeyHintLength],
+		Hash:      HashAPIKey(key),
+		CreatedAt: time.Now(),
+	}, key, nil
+}
+
+// HashAPIKey returns the stored form of an API key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsRevoked returns true once the key no longer authenticates.
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	userID := uuid.New()
+
+	key, secret, err := NewAPIKey(userID, " ci ")
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, APIKeyPrefix) {
+		t.Errorf("key %q lacks prefix %q", secret, APIKeyPrefix)
+	}
+	if key.UserID != userID || key.Name != "ci" || key.IsRevoked() {
+		t.Errorf("unexpected key: %+v", key)
+	}
+	if !strings.HasPrefix(secret, key.Hint) || len(key.Hint) != len(APIKeyPrefix)+apiKeyHintLength {
+		t.Errorf("Hint = %q, want the start of %q", key.Hint, secret)
+	}
+	if key.Hash != HashAPIKey(secret) || strings.Contains(key.Hash, secret) {
+		t.Errorf("Hash = %q, want the hash of the key", key.Hash)
+	}
+
+	_, other, _ := NewAPIKey(userID, "")
+	if other == secret {
+		t.Error("expected keys to differ")
+	}
+
+	if _, _, err := NewAPIKey(uuid.Nil, "ci"); !errors.Is(err, ErrInvalidUserID) {
+		t.Errorf("NewAPIKey(nil user) error = %v, want %v", err, ErrInvalidUserID)
+	}
+	if _, _, err := NewAPIKey(userID, strings.Repeat("k", maxAPIKeyNameLength+1)); !errors.Is(err, ErrAPIKeyNameTooLong) {
+		t.Errorf("NewAPIKey(long name) error = %v, want %v", err, ErrAPIKeyNameTooLong)
+	}
+}
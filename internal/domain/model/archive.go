@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoArchive tracks the output of an ARCHIVED video on its way to, in, and back from
+// the archive tier. It exists from the archive request until the output is served again.
+type VideoArchive struct {
+	VideoID uuid.UUID
+	// OutputVersion and Prefix identify the archived output, e.g. "hls/{key}/v{version}/".
+	OutputVersion int64
+	Prefix        string
+	// Bytes is the size of the objects moved to the archive tier; zero until ArchivedAt is set.
+	Bytes int64
+	// ArchivedAt is when the objects finished moving to the archive tier; nil while they move.
+	ArchivedAt *time.Time
+	// RestoreRequestedAt is when a viewer or the owner asked for the output back; nil
+	// until then. RestoreETA is when it is expected to be served again.
+	RestoreRequestedAt *time.Time
+	RestoreETA         *time.Time
+	CreatedAt          time.Time
+}
+
+// NewVideoArchive creates the archive record of a video's current output.
+func NewVideoArchive(video *Video, prefix string) *VideoArchive {
+	return &VideoArchive{
+		VideoID:       video.ID,
+		OutputVersion: video.OutputVersion,
+		Prefix:        prefix,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// IsArchived returns true once the objects are in the archive tier.
+func (a *VideoArchive) IsArchived() bool {
+	return a.ArchivedAt != nil
+}
+
+// IsRestoring returns true if a restore was requested.
+func (a *VideoArchive) IsRestoring() bool {
+	return a.RestoreRequestedAt != nil
+}
+
+// RequestRestore records a restore request at now, expected to complete after d.
+// A pending request is kept as it is, so repeated requests report the same ETA.
+func (a *VideoArchive) RequestRestore(now time.Time, d time.Duration) {
+	if a.IsRestoring() {
+		return
+	}
+	eta := now.Add(d)
+	a.RestoreRequestedAt = &now
+	a.RestoreETA = &eta
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomDomainStatus represents the ownership verification state of a custom domain.
+type CustomDomainStatus string
+
+const (
+	DomainStatusPending  CustomDomainStatus = "PENDING_VERIFICATION"
+	DomainStatusVerified CustomDomainStatus = "VERIFIED"
+)
+
+func (s CustomDomainStatus) String() string {
+	return string(s)
+}
+
+// DomainVerificationPrefix is prepended to the hostname to form the TXT record
+// a tenant publishes to prove ownership.
+const DomainVerificationPrefix = "_gostream-verification."
+
+// domainTokenBytes is the entropy of a verification token (128 bits).
+const domainTokenBytes = 16
+
+const maxHostnameLength = 253
+
+var (
+	ErrInvalidTenantID = errors.New("tenant ID cannot be nil")
+	ErrInvalidHostname = errors.New("hostname must be a fully qualified domain name")
+)
+
+// CustomDomain is a vanity hostname a tenant serves playback from.
+// A tenant is the account that owns videos (videos.user_id). The domain is only used
+// for playback URLs once ownership is verified and a TLS certificate is attached.
+type CustomDomain struct {
+	ID       uuid.UUID
+	TenantID uuid.UUID
+	Hostname string
+	// CertificateID references the TLS certificate provisioned for Hostname at the CDN
+	// (e.g., an ACM certificate ARN). Empty until the certificate is issued.
+	CertificateID     string
+	VerificationToken string
+	Status            CustomDomainStatus
+	VerifiedAt        *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewCustomDomain creates an unverified CustomDomain with a fresh verification token.
+// The hostname is normalized to lowercase without a trailing dot.
+func NewCustomDomain(tenantID uuid.UUID, hostname, certificateID string) (*CustomDomain, error) {
+	if tenantID == uuid.Nil {
+		return nil, ErrInvalidTenantID
+	}
+
+	hostname, err := NormalizeHostname(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, domainTokenBytes)
+	_, _ = rand.Read(token)
+
+	now := time.Now()
+	return &CustomDomain{
+		ID:                uuid.New(),
+		TenantID:          tenantID,
+		Hostname:          hostname,
+		CertificateID:     strings.TrimSpace(certificateID),
+		VerificationToken: hex.EncodeToString(token),
+		Status:            DomainStatusPending,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// NormalizeHostname lowercases hostname and checks that it is a fully qualified DNS name.
+// IP addresses, single-label names and wildcards are rejected.
+func NormalizeHostname(hostname string) (string, error) {
+	h := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+	if h == "" || len(h) > maxHostnameLength || net.ParseIP(h) != nil {
+		return "", ErrInvalidHostname
+	}
+
+	labels := strings.Split(h, ".")
+	if len(labels) < 2 {
+		return "", ErrInvalidHostname
+	}
+	for _, label := range labels {
+		if !isValidDNSLabel(label) {
+			return "", ErrInvalidHostname
+		}
+	}
+	return h, nil
+}
+
+func isValidDNSLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// VerificationRecordName is the DNS name the TXT verification record must be published at.
+func (d *CustomDomain) VerificationRecordName() string {
+	return DomainVerificationPrefix + d.Hostname
+}
+
+// MarkVerified records successful ownership verification.
+func (d *CustomDomain) MarkVerified(at time.Time) {
+	d.Status = DomainStatusVerified
+	d.VerifiedAt = &at
+	d.UpdatedAt = at
+}
+
+// SetCertificateID attaches the TLS certificate provisioned for the hostname.
+func (d *CustomDomain) SetCertificateID(certificateID string) {
+	d.CertificateID = strings.TrimSpace(certificateID)
+	d.UpdatedAt = time.Now()
+}
+
+// IsVerified returns true once DNS ownership has been proven.
+func (d *CustomDomain) IsVerified() bool {
+	return d.Status == DomainStatusVerified
+}
+
+// IsServable returns true if playback URLs may point at the domain:
+// ownership is verified and a certificate is attached so it can serve HTTPS.
+func (d *CustomDomain) IsServable() bool {
+	return d.IsVerified() && d.CertificateID != ""
+}
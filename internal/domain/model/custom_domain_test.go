@@ -0,0 +1,111 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewCustomDomain(t *testing.T) {
+	tests := []struct {
+		name         string
+		tenantID     uuid.UUID
+		hostname     string
+		wantHostname string
+		wantErr      error
+	}{
+		{
+			name:         "normalizes hostname",
+			tenantID:     uuid.New(),
+			hostname:     " Video.Example.COM. ",
+			wantHostname: "video.example.com",
+		},
+		{
+			name:     "nil tenant",
+			tenantID: uuid.Nil,
+			hostname: "video.example.com",
+			wantErr:  ErrInvalidTenantID,
+		},
+		{
+			name:     "single label",
+			tenantID: uuid.New(),
+			hostname: "localhost",
+			wantErr:  ErrInvalidHostname,
+		},
+		{
+			name:     "ip address",
+			tenantID: uuid.New(),
+			hostname: "10.0.0.1",
+			wantErr:  ErrInvalidHostname,
+		},
+		{
+			name:     "wildcard",
+			tenantID: uuid.New(),
+			hostname: "*.example.com",
+			wantErr:  ErrInvalidHostname,
+		},
+		{
+			name:     "label starting with hyphen",
+			tenantID: uuid.New(),
+			hostname: "-video.example.com",
+			wantErr:  ErrInvalidHostname,
+		},
+		{
+			name:     "url instead of hostname",
+			tenantID: uuid.New(),
+			hostname: "https://video.example.com/",
+			wantErr:  ErrInvalidHostname,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewCustomDomain(tt.tenantID, tt.hostname, "")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if d.Hostname != tt.wantHostname {
+				t.Errorf("hostname: got %q, expected %q", d.Hostname, tt.wantHostname)
+			}
+			if d.Status != DomainStatusPending {
+				t.Errorf("status: got %s, expected %s", d.Status, DomainStatusPending)
+			}
+			if len(d.VerificationToken) != 2*domainTokenBytes {
+				t.Errorf("verification token %q: expected %d hex characters", d.VerificationToken, 2*domainTokenBytes)
+			}
+			if got := d.VerificationRecordName(); got != "_gostream-verification."+tt.wantHostname {
+				t.Errorf("verification record: got %q", got)
+			}
+		})
+	}
+}
+
+func TestCustomDomain_IsServable(t *testing.T) {
+	d, err := NewCustomDomain(uuid.New(), "video.example.com", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d.IsServable() {
+		t.Error("unverified domain must not be servable")
+	}
+
+	d.MarkVerified(time.Now())
+	if d.IsServable() {
+		t.Error("domain without a certificate must not be servable")
+	}
+
+	d.SetCertificateID("arn:aws:acm:us-east-1:123456789012:certificate/abc")
+	if !d.IsServable() {
+		t.Error("verified domain with a certificate should be servable")
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EncryptionKeySize is the size of an HLS AES-128 content key in bytes.
+const EncryptionKeySize = 16
+
+// EncryptionKey is the AES-128 key that encrypts a video's HLS segments. A video keeps
+// one key across output versions, so segments still cached from an older output stay
+// playable.
+type EncryptionKey struct {
+	VideoID   uuid.UUID
+	Key       []byte
+	CreatedAt time.Time
+}
+
+// NewEncryptionKey creates a random key for a video.
+func NewEncryptionKey(videoID uuid.UUID) *EncryptionKey {
+	key := make([]byte, EncryptionKeySize)
+	_, _ = rand.Read(key)
+
+	return &EncryptionKey{
+		VideoID:   videoID,
+		Key:       key,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// IDStrategy selects the scheme used for a video's sortable ID.
+// The UUID primary key is unaffected; sortable IDs exist for ordering and external references.
+type IDStrategy string
+
+const (
+	// IDStrategyULID produces 26-character Crockford base32 IDs with millisecond precision.
+	IDStrategyULID IDStrategy = "ulid"
+	// IDStrategyKSUID produces 27-character base62 IDs with second precision.
+	IDStrategyKSUID IDStrategy = "ksuid"
+)
+
+// ShareSlugLength is the number of characters in a share slug.
+const ShareSlugLength = 8
+
+var ErrUnknownIDStrategy = errors.New("unknown ID strategy")
+
+// crockfordAlphabet omits I, L, O and U so IDs survive being read aloud or retyped.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// slugAlphabet is the lowercase Crockford alphabet; 32 symbols keep byte-to-symbol mapping unbiased.
+const slugAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ksuidEpoch is the KSUID timestamp origin (2014-05-13T16:53:20Z).
+const ksuidEpoch = 1400000000
+
+func (s IDStrategy) IsValid() bool {
+	switch s {
+	case IDStrategyULID, IDStrategyKSUID:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewID generates a sortable ID for t. IDs generated later sort lexicographically after
+// earlier ones, down to the strategy's timestamp precision.
+func (s IDStrategy) NewID(t time.Time) (string, error) {
+	switch s {
+	case IDStrategyULID:
+		return NewULID(t), nil
+	case IDStrategyKSUID:
+		return NewKSUID(t), nil
+	default:
+		return "", ErrUnknownIDStrategy
+	}
+}
+
+// NewULID returns a ULID: a 48-bit millisecond timestamp followed by 80 random bits.
+func NewULID(t time.Time) string {
+	var b [16]byte
+	ms := uint64(t.UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	_, _ = rand.Read(b[6:])
+
+	// 128 bits encode to 26 symbols of 5 bits, with the top symbol holding only 3 bits
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	out := make([]byte, 26)
+	for i := range out {
+		shift := uint(5 * (25 - i))
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift+5 > 64:
+			v = lo>>shift | hi<<(64-shift)
+		default:
+			v = lo >> shift
+		}
+		out[i] = crockfordAlphabet[v&31]
+	}
+	return string(out)
+}
+
+// NewKSUID returns a KSUID: a 32-bit second timestamp offset from the KSUID epoch
+// followed by 128 random bits, base62 encoded.
+func NewKSUID(t time.Time) string {
+	var b [20]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(t.Unix()-ksuidEpoch))
+	_, _ = rand.Read(b[4:])
+
+	n := new(big.Int).SetBytes(b[:])
+	base := big.NewInt(62)
+	mod := new(big.Int)
+	out := make([]byte, 27)
+	for i := len(out) - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+	return string(out)
+}
+
+// NewShareSlug returns a random slug for share links. Slugs are not unique by
+// construction; callers must handle collisions when persisting them.
+func NewShareSlug() string {
+	b := make([]byte, ShareSlugLength)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = slugAlphabet[b[i]&31]
+	}
+	return string(b)
+}
+
+// IsValidShareSlug reports whether s is shaped like a share slug.
+func IsValidShareSlug(s string) bool {
+	if len(s) != ShareSlugLength {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(slugAlphabet, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIDStrategy_NewID(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		strategy IDStrategy
+		later    time.Duration
+		wantLen  int
+		wantErr  error
+	}{
+		{
+			name:     "ulid sorts by millisecond",
+			strategy: IDStrategyULID,
+			later:    time.Millisecond,
+			wantLen:  26,
+		},
+		{
+			name:     "ksuid sorts by second",
+			strategy: IDStrategyKSUID,
+			later:    time.Second,
+			wantLen:  27,
+		},
+		{
+			name:     "unknown strategy",
+			strategy: IDStrategy("snowflake"),
+			wantErr:  ErrUnknownIDStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := tt.strategy.NewID(base)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			second, err := tt.strategy.NewID(base.Add(tt.later))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(first) != tt.wantLen || len(second) != tt.wantLen {
+				t.Errorf("expected length %d, got %d and %d", tt.wantLen, len(first), len(second))
+			}
+			if first >= second {
+				t.Errorf("expected %q to sort before %q", first, second)
+			}
+		})
+	}
+}
+
+func TestNewULID_KnownTimestamp(t *testing.T) {
+	// The first 10 symbols encode the timestamp; 1469918176385 ms is "01ARYZ6S41" in the ULID spec.
+	got := NewULID(time.UnixMilli(1469918176385))
+	if got[:10] != "01ARYZ6S41" {
+		t.Errorf("timestamp prefix: got %q, expected %q", got[:10], "01ARYZ6S41")
+	}
+}
+
+func TestNewShareSlug(t *testing.T) {
+	seen := make(map[string]struct{})
+	for range 1000 {
+		slug := NewShareSlug()
+		if !IsValidShareSlug(slug) {
+			t.Fatalf("generated invalid slug %q", slug)
+		}
+		seen[slug] = struct{}{}
+	}
+	if len(seen) < 999 {
+		t.Errorf("expected slugs to be effectively unique, got %d distinct of 1000", len(seen))
+	}
+}
+
+func TestIsValidShareSlug(t *testing.T) {
+	tests := []struct {
+		slug string
+		want bool
+	}{
+		{"a1b2c3d4", true},
+		{"zzzzzzzz", true},
+		{"a1b2c3d", false},
+		{"a1b2c3d4e", false},
+		{"A1B2C3D4", false},
+		{"ilou0000", false},
+		{"a1b2-3d4", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			if got := IsValidShareSlug(tt.slug); got != tt.want {
+				t.Errorf("IsValidShareSlug(%q) = %v, want %v", tt.slug, got, tt.want)
+			}
+		})
+	}
+}
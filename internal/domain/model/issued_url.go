@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// URLPurpose records why a presigned URL was issued.
+type URLPurpose string
+
+const (
+	// URLPurposeUpload is a client upload of a video original.
+	URLPurposeUpload URLPurpose = "upload"
+	// URLPurposeProbe is a server-side read of an original to plan its transcode.
+	URLPurposeProbe URLPurpose = "probe"
+	// URLPurposeDownload is an owner's download of a video original.
+	URLPurposeDownload URLPurpose = "download"
+)
+
+// IssuedURL is the audit record of one presigned URL. The URL itself is not kept:
+// it is a bearer credential, and the key, expiry and requester are enough to
+// answer who had access to what.
+type IssuedURL struct {
+	ID  uuid.UUID
+	Key string
+	// Method is the HTTP method the URL is signed for (PUT for uploads, GET for downloads).
+	Method  string
+	Purpose URLPurpose
+	// RequesterID is the user the URL was issued to; uuid.Nil for URLs the service
+	// issues to itself.
+	RequesterID uuid.UUID
+	// VideoID is the video the object belongs to; uuid.Nil if it belongs to none.
+	VideoID   uuid.UUID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// NewIssuedURL creates the audit record of a URL issued now and valid for expiry.
+func NewIssuedURL(key, method string, purpose URLPurpose, requesterID, videoID uuid.UUID, expiry time.Duration) *IssuedURL {
+	now := time.Now()
+	return &IssuedURL{
+		ID:          uuid.New(),
+		Key:         key,
+		Method:      method,
+		Purpose:     purpose,
+		RequesterID: requesterID,
+		VideoID:     videoID,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(expiry),
+	}
+}
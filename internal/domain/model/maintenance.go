@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// MaintenanceWindow is a period during which the API rejects writes and workers stop
+// taking new tasks, so infrastructure can be serviced without losing work.
+type MaintenanceWindow struct {
+	// StartsAt is when the window opens; a window scheduled for now starts immediately.
+	StartsAt time.Time
+	// EndsAt is when the window closes on its own. Zero keeps it open until it is ended.
+	EndsAt time.Time
+	// Reason is shown to clients rejected during the window.
+	Reason string
+}
+
+// IsActive returns true if the window is open at the given time.
+func (m *MaintenanceWindow) IsActive(now time.Time) bool {
+	if now.Before(m.StartsAt) {
+		return false
+	}
+	return m.EndsAt.IsZero() || now.Before(m.EndsAt)
+}
+
+// Remaining returns how long the window stays open after the given time.
+// Returns false if the window has no end.
+func (m *MaintenanceWindow) Remaining(now time.Time) (time.Duration, bool) {
+	if m.EndsAt.IsZero() {
+		return 0, false
+	}
+	return max(m.EndsAt.Sub(now), 0), true
+}
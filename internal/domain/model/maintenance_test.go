@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindow_IsActive(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		want   bool
+	}{
+		{"scheduled", MaintenanceWindow{StartsAt: now.Add(time.Minute), EndsAt: now.Add(time.Hour)}, false},
+		{"open", MaintenanceWindow{StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)}, true},
+		{"starts now", MaintenanceWindow{StartsAt: now, EndsAt: now.Add(time.Hour)}, true},
+		{"no end", MaintenanceWindow{StartsAt: now.Add(-time.Hour)}, true},
+		{"ended", MaintenanceWindow{StartsAt: now.Add(-time.Hour), EndsAt: now}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.IsActive(now); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_Remaining(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		want   time.Duration
+		wantOK bool
+	}{
+		{"open", MaintenanceWindow{EndsAt: now.Add(10 * time.Minute)}, 10 * time.Minute, true},
+		{"ended", MaintenanceWindow{EndsAt: now.Add(-time.Minute)}, 0, true},
+		{"no end", MaintenanceWindow{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.window.Remaining(now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Remaining() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// bucketNamePattern matches S3 bucket names: 3-63 lowercase letters, digits, dots and
+// hyphens, starting and ending with a letter or digit.
+var bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+var (
+	ErrInvalidBucketEndpoint  = errors.New("bucket endpoint must be a fully qualified host name with an optional port")
+	ErrInvalidBucketName      = errors.New("bucket name must be a valid S3 bucket name")
+	ErrMissingBucketAccessKey = errors.New("bucket access key ID cannot be empty")
+	ErrInvalidPublicBaseURL   = errors.New("public base URL must be an absolute http or https URL without query or fragment")
+)
+
+// OutputBucket is an S3-compatible bucket a tenant brings for its transcode output.
+// The worker writes every new output version of the tenant's videos there instead of to
+// the platform's storage; metadata stays in the platform's database. The secret key is
+// held by the secrets provider and referenced by SecretRef.
+type OutputBucket struct {
+	TenantID uuid.UUID
+	// Endpoint is the S3 API host, with an optional port (e.g., s3.eu-west-1.amazonaws.com).
+	Endpoint    string
+	Region      string
+	Bucket      string
+	UseSSL      bool
+	AccessKeyID string
+	SecretRef   string
+	// PublicBaseURL is where the tenant serves the bucket to viewers (e.g., its CDN);
+	// playback URLs of output stored in the bucket are built on it.
+	PublicBaseURL string
+	// CheckedAt is when the bucket was last written to by a health check, and CheckError
+	// why that check failed; empty when it passed.
+	CheckedAt  time.Time
+	CheckError string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewOutputBucket creates an output bucket configuration with validation.
+// The endpoint is normalized like a custom domain hostname and the base URL loses any
+// trailing slash.
+func NewOutputBucket(tenantID uuid.UUID, endpoint, region, bucket, accessKeyID, publicBaseURL string, useSSL bool) (*OutputBucket, error) {
+	if tenantID == uuid.Nil {
+		return nil, ErrInvalidTenantID
+	}
+
+	endpoint, err := normalizeBucketEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !bucketNamePattern.MatchString(bucket) || strings.Contains(bucket, "..") {
+		return nil, ErrInvalidBucketName
+	}
+	accessKeyID = strings.TrimSpace(accessKeyID)
+	if accessKeyID == "" {
+		return nil, ErrMissingBucketAccessKey
+	}
+	publicBaseURL, err = normalizePublicBaseURL(publicBaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &OutputBucket{
+		TenantID:      tenantID,
+		Endpoint:      endpoint,
+		Region:        strings.TrimSpace(region),
+		Bucket:        bucket,
+		UseSSL:        useSSL,
+		AccessKeyID:   accessKeyID,
+		PublicBaseURL: publicBaseURL,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// normalizeBucketEndpoint accepts host or host:port. IP addresses and single-label names
+// are rejected, so a tenant cannot point the worker at hosts on the platform's network.
+func normalizeBucketEndpoint(endpoint string) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	host, port := endpoint, ""
+	if h, p, err := net.SplitHostPort(endpoint); err == nil {
+		host, port = h, p
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", ErrInvalidBucketEndpoint
+		}
+	}
+
+	host, err := NormalizeHostname(host)
+	if err != nil {
+		return "", ErrInvalidBucketEndpoint
+	}
+	if port != "" {
+		return net.JoinHostPort(host, port), nil
+	}
+	return host, nil
+}
+
+func normalizePublicBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", ErrInvalidPublicBaseURL
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// RecordCheck records the outcome of a health check; a nil err marks the bucket healthy.
+func (b *OutputBucket) RecordCheck(at time.Time, err error) {
+	b.CheckedAt = at
+	b.CheckError = ""
+	if err != nil {
+		b.CheckError = err.Error()
+	}
+	b.UpdatedAt = at
+}
+
+// IsHealthy returns true if the last health check passed.
+func (b *OutputBucket) IsHealthy() bool {
+	return !b.CheckedAt.IsZero() && b.CheckError == ""
+}
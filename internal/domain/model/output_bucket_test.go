@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewOutputBucket(t *testing.T) {
+	tests := []struct {
+		name          string
+		tenantID      uuid.UUID
+		endpoint      string
+		bucket        string
+		accessKeyID   string
+		publicBaseURL string
+		wantEndpoint  string
+		wantBaseURL   string
+		wantErr       error
+	}{
+		{
+			name:          "normalizes endpoint and base URL",
+			tenantID:      uuid.New(),
+			endpoint:      " S3.EU-West-1.amazonaws.com ",
+			bucket:        "acme-videos",
+			accessKeyID:   "AKIAEXAMPLE",
+			publicBaseURL: "https://cdn.acme.example/",
+			wantEndpoint:  "s3.eu-west-1.amazonaws.com",
+			wantBaseURL:   "https://cdn.acme.example",
+		},
+		{
+			name:          "endpoint with port",
+			tenantID:      uuid.New(),
+			endpoint:      "minio.acme.example:9000",
+			bucket:        "videos.acme",
+			accessKeyID:   "acme",
+			publicBaseURL: "https://cdn.acme.example/videos",
+			wantEndpoint:  "minio.acme.example:9000",
+			wantBaseURL:   "https://cdn.acme.example/videos",
+		},
+		{
+			name:          "nil tenant",
+			tenantID:      uuid.Nil,
+			endpoint:      "s3.amazonaws.com",
+			bucket:        "acme-videos",
+			accessKeyID:   "AKIAEXAMPLE",
+			publicBaseURL: "https://cdn.acme.example",
+			wantErr:       ErrInvalidTenantID,
+		},
+		{
+			name:          "ip endpoint",
+			tenantID:      uuid.New(),
+			endpoint:      "10.0.0.5:9000",
+			bucket:        "acme-videos",
+			accessKeyID:   "AKIAEXAMPLE",
+			publicBaseURL: "https://cdn.acme.example",
+			wantErr:       ErrInvalidBucketEndpoint,
+		},
+		{
+			name:          "endpoint with scheme",
+			tenantID:      uuid.New(),
+			endpoint:      "https://s3.amazonaws.com",
+			bucket:        "acme-videos",
+			accessKeyID:   "AKIAEXAMPLE",
+			publicBaseURL: "https://cdn.acme.example",
+			wantErr:       ErrInvalidBucketEndpoint,
+		},
+		{
+			name:          "uppercase bucket",
+			tenantID:      uuid.New(),
+			endpoint:      "s3.amazonaws.com",
+			bucket:        "Acme",
+			accessKeyID:   "AKIAEXAMPLE",
+			publicBaseURL: "https://cdn.acme.example",
+			wantErr:       ErrInvalidBucketName,
+		},
+		{
+			name:          "missing access key",
+			tenantID:      uuid.New(),
+			endpoint:      "s3.amazonaws.com",
+			bucket:        "acme-videos",
+			accessKeyID:   " ",
+			publicBaseURL: "https://cdn.acme.example",
+			wantErr:       ErrMissingBucketAccessKey,
+		},
+		{
+			name:          "base URL with query",
+			tenantID:      uuid.New(),
+			endpoint:      "s3.amazonaws.com",
+			bucket:        "acme-videos",
+			accessKeyID:   "AKIAEXAMPLE",
+			publicBaseURL: "https://cdn.acme.example/?sig=1",
+			wantErr:       ErrInvalidPublicBaseURL,
+		},
+		{
+			name:          "relative base URL",
+			tenantID:      uuid.New(),
+			endpoint:      "s3.amazonaws.com",
+			bucket:        "acme-videos",
+			accessKeyID:   "AKIAEXAMPLE",
+			publicBaseURL: "/videos",
+			wantErr:       ErrInvalidPublicBaseURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOutputBucket(tt.tenantID, tt.endpoint, "", tt.bucket, tt.accessKeyID, tt.publicBaseURL, true)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NewOutputBucket() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewOutputBucket() unexpected error: %v", err)
+			}
+			if got.Endpoint != tt.wantEndpoint {
+				t.Errorf("Endpoint = %q, want %q", got.Endpoint, tt.wantEndpoint)
+			}
+			if got.PublicBaseURL != tt.wantBaseURL {
+				t.Errorf("PublicBaseURL = %q, want %q", got.PublicBaseURL, tt.wantBaseURL)
+			}
+			if got.IsHealthy() {
+				t.Error("new bucket reported healthy before any check")
+			}
+		})
+	}
+}
+
+func TestOutputBucket_RecordCheck(t *testing.T) {
+	bucket := &OutputBucket{TenantID: uuid.New()}
+	now := time.Now()
+
+	bucket.RecordCheck(now, errors.New("access denied"))
+	if bucket.IsHealthy() || bucket.CheckError != "access denied" {
+		t.Errorf("after failed check: healthy = %v, error = %q", bucket.IsHealthy(), bucket.CheckError)
+	}
+
+	bucket.RecordCheck(now.Add(time.Minute), nil)
+	if !bucket.IsHealthy() || !bucket.CheckedAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("after passed check: healthy = %v, checked at %v", bucket.IsHealthy(), bucket.CheckedAt)
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidPosition = errors.New("playback position cannot be negative")
+)
+
+// PlaybackProgress records how far a user has watched a video ("continue watching").
+type PlaybackProgress struct {
+	UserID    uuid.UUID
+	VideoID   uuid.UUID
+	Position  time.Duration
+	UpdatedAt time.Time
+}
+
+// NewPlaybackProgress creates a progress record stamped with the current time.
+func NewPlaybackProgress(userID, videoID uuid.UUID, position time.Duration) (*PlaybackProgress, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if position < 0 {
+		return nil, ErrInvalidPosition
+	}
+
+	return &PlaybackProgress{
+		UserID:    userID,
+		VideoID:   videoID,
+		Position:  position,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// WatchHistoryEntry is a progress record enriched with video metadata for history listings.
+type WatchHistoryEntry struct {
+	PlaybackProgress
+	Title string
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlaybackToken is a short-lived credential that authorizes a user to stream a video.
+// Tokens are opaque to clients; all claims live server-side so they can be revoked.
+type PlaybackToken struct {
+	Token     string
+	VideoID   uuid.UUID
+	UserID    uuid.UUID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// IsExpired returns true if the token is no longer valid at the given time.
+func (t *PlaybackToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IssuedBefore returns true if the token was issued at or before the given cutoff.
+// Used to check tokens against bulk revocation markers.
+func (t *PlaybackToken) IssuedBefore(cutoff time.Time) bool {
+	return !t.IssuedAt.After(cutoff)
+}
+
+// SignedPlaybackClaims are the claims carried by a signed playback URL. Unlike a
+// PlaybackToken they travel inside the URL under an HMAC, so an origin can check them
+// without a lookup; they cannot be revoked before they expire.
+type SignedPlaybackClaims struct {
+	VideoID uuid.UUID
+	UserID  uuid.UUID
+	// Prefix is the storage prefix the URL grants access to: the directory of the
+	// published master playlist, which holds every playlist and segment of the output.
+	Prefix    string
+	ExpiresAt time.Time
+}
+
+// IsExpired returns true if the claims are no longer valid at the given time.
+func (c *SignedPlaybackClaims) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxPlaylistVideos bounds a playlist, so its videos are returned in a single response.
+const maxPlaylistVideos = 200
+
+var (
+	ErrTooManyPlaylistVideos  = errors.New("playlist exceeds maximum of 200 videos")
+	ErrDuplicatePlaylistVideo = errors.New("playlist contains a video more than once")
+)
+
+// Playlist is an ordered list of a user's videos, such as a channel or a course.
+type Playlist struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	// Visibility works like a video's: private playlists are only seen by their owner.
+	Visibility Visibility
+	// VideoIDs are the playlist's videos in playback order.
+	VideoIDs  []uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewPlaylist creates an empty, unlisted Playlist owned by userID.
+func NewPlaylist(userID uuid.UUID, title, description string) (*Playlist, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := validateTitle(title); err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return nil, ErrDescriptionTooLong
+	}
+
+	now := time.Now()
+	return &Playlist{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+		Visibility:  VisibilityUnlisted,
+		VideoIDs:    []uuid.UUID{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// SetTitle renames the playlist.
+func (p *Playlist) SetTitle(title string) error {
+	if err := validateTitle(title); err != nil {
+		return err
+	}
+	p.Title = title
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetDescription replaces the description; an empty description clears it.
+func (p *Playlist) SetDescription(description string) error {
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return ErrDescriptionTooLong
+	}
+	p.Description = description
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetVisibility changes who can see the playlist.
+func (p *Playlist) SetVisibility(visibility Visibility) error {
+	if !visibility.IsValid() {
+		return ErrInvalidVisibility
+	}
+	p.Visibility = visibility
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetVideos replaces the videos with videoIDs in playback order; an empty list clears
+// the playlist. Whether the videos exist is left to the caller.
+func (p *Playlist) SetVideos(videoIDs []uuid.UUID) error {
+	if len(videoIDs) > maxPlaylistVideos {
+		return ErrTooManyPlaylistVideos
+	}
+	seen := make(map[uuid.UUID]bool, len(videoIDs))
+	for _, id := range videoIDs {
+		if seen[id] {
+			return ErrDuplicatePlaylistVideo
+		}
+		seen[id] = true
+	}
+
+	p.VideoIDs = append(make([]uuid.UUID, 0, len(videoIDs)), videoIDs...)
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// VisibleTo reports whether userID may see the playlist; uuid.Nil stands for an
+// anonymous caller.
+func (p *Playlist) VisibleTo(userID uuid.UUID) bool {
+	return p.Visibility.allows(p.UserID, userID)
+}
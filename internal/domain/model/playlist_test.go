@@ -0,0 +1,111 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewPlaylist(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		title       string
+		description string
+		wantErr     error
+	}{
+		{"valid", uuid.New(), "Go course", "Ten lessons", nil},
+		{"nil user", uuid.Nil, "Go course", "", ErrInvalidUserID},
+		{"empty title", uuid.New(), "", "", ErrEmptyTitle},
+		{"title too long", uuid.New(), strings.Repeat("a", 256), "", ErrTitleTooLong},
+		{"description too long", uuid.New(), "Go course", strings.Repeat("a", 5001), ErrDescriptionTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist, err := NewPlaylist(tt.userID, tt.title, tt.description)
+			if err != tt.wantErr {
+				t.Fatalf("NewPlaylist() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if playlist.ID == uuid.Nil || playlist.UserID != tt.userID || playlist.Title != tt.title || playlist.Description != tt.description {
+				t.Errorf("NewPlaylist() = %+v", playlist)
+			}
+			if playlist.Visibility != VisibilityUnlisted {
+				t.Errorf("Visibility = %q, want %q", playlist.Visibility, VisibilityUnlisted)
+			}
+			if playlist.VideoIDs == nil || len(playlist.VideoIDs) != 0 {
+				t.Errorf("VideoIDs = %v, want empty", playlist.VideoIDs)
+			}
+		})
+	}
+}
+
+func TestPlaylist_SetVideos(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	tooMany := make([]uuid.UUID, 201)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name     string
+		videoIDs []uuid.UUID
+		want     []uuid.UUID
+		wantErr  error
+	}{
+		{"keeps order", []uuid.UUID{b, a}, []uuid.UUID{b, a}, nil},
+		{"empty clears", []uuid.UUID{}, []uuid.UUID{}, nil},
+		{"nil clears", nil, []uuid.UUID{}, nil},
+		{"at the limit", tooMany[:200], tooMany[:200], nil},
+		{"too many", tooMany, []uuid.UUID{a}, ErrTooManyPlaylistVideos},
+		{"duplicate", []uuid.UUID{a, b, a}, []uuid.UUID{a}, ErrDuplicatePlaylistVideo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist := &Playlist{VideoIDs: []uuid.UUID{a}}
+
+			err := playlist.SetVideos(tt.videoIDs)
+			if err != tt.wantErr {
+				t.Fatalf("SetVideos() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(playlist.VideoIDs) != len(tt.want) || playlist.VideoIDs == nil {
+				t.Fatalf("VideoIDs = %v, want %v", playlist.VideoIDs, tt.want)
+			}
+			for i := range tt.want {
+				if playlist.VideoIDs[i] != tt.want[i] {
+					t.Fatalf("VideoIDs = %v, want %v", playlist.VideoIDs, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylist_VisibleTo(t *testing.T) {
+	owner := uuid.New()
+
+	tests := []struct {
+		name       string
+		visibility Visibility
+		viewer     uuid.UUID
+		want       bool
+	}{
+		{"unlisted to anonymous", VisibilityUnlisted, uuid.Nil, true},
+		{"private to owner", VisibilityPrivate, owner, true},
+		{"private to another user", VisibilityPrivate, uuid.New(), false},
+		{"private to anonymous", VisibilityPrivate, uuid.Nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist := &Playlist{UserID: owner, Visibility: tt.visibility}
+			if got := playlist.VisibleTo(tt.viewer); got != tt.want {
+				t.Errorf("VisibleTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
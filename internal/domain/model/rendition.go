@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rendition describes one encoded quality level of a video's output version.
+// Each transcode of a video produces a full set of renditions under its output version,
+// so billing and ladder analytics can tell regenerations apart.
+type Rendition struct {
+	ID            uuid.UUID
+	VideoID       uuid.UUID
+	OutputVersion int64
+	// Name is the variant name, which is also its directory under the output prefix (e.g., "720p").
+	Name    string
+	Width   int
+	Height  int
+	Bitrate int
+	// Codec is the video encoder used (e.g., "libx264").
+	Codec        string
+	SegmentCount int
+	// Bytes is the stored size of the rendition's playlist and segments.
+	Bytes     int64
+	CreatedAt time.Time
+	// LastViewedAt is when a viewer last fetched the rendition's playlist; zero if never.
+	LastViewedAt time.Time
+	// PrunedAt is when the rendition was removed from the output; zero while it is served.
+	PrunedAt time.Time
+}
+
+// NewRendition creates a rendition record for the named variant of an output version.
+func NewRendition(videoID uuid.UUID, outputVersion int64, name string) *Rendition {
+	return &Rendition{
+		ID:            uuid.New(),
+		VideoID:       videoID,
+		OutputVersion: outputVersion,
+		Name:          name,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// IsPruned returns true if the rendition was removed from the output.
+func (r *Rendition) IsPruned() bool {
+	return !r.PrunedAt.IsZero()
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxTitleSlugLength is the maximum number of characters in a title slug, including
+// any collision suffix.
+const MaxTitleSlugLength = 64
+
+// TitleSlug derives a human-readable slug from a title: letters and digits are
+// lowercased and every other run of characters becomes a single hyphen, e.g.
+// "My First Video!" becomes "my-first-video". Returns an empty string if the title
+// has no letters or digits.
+func TitleSlug(title string) string {
+	var b strings.Builder
+	n := 0
+	pendingHyphen := false
+	for _, r := range title {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+		if pendingHyphen {
+			if n+2 > MaxTitleSlugLength {
+				break
+			}
+			b.WriteByte('-')
+			n++
+			pendingHyphen = false
+		}
+		if n+1 > MaxTitleSlugLength {
+			break
+		}
+		b.WriteRune(unicode.ToLower(r))
+		n++
+	}
+	return b.String()
+}
+
+// TitleSlugWithSuffix appends "-{suffix}" to slug to resolve a collision (e.g.
+// "my-video-2"), shortening slug so the result stays within MaxTitleSlugLength.
+func TitleSlugWithSuffix(slug, suffix string) string {
+	suffix = "-" + suffix
+	runes := []rune(slug)
+	if keep := MaxTitleSlugLength - len([]rune(suffix)); len(runes) > keep {
+		runes = runes[:max(keep, 0)]
+	}
+	return strings.TrimSuffix(string(runes), "-") + suffix
+}
+
+// IsValidTitleSlug reports whether s is shaped like a title slug.
+func IsValidTitleSlug(s string) bool {
+	return s != "" && TitleSlug(s) == s
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTitleSlug(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"My First Video!", "my-first-video"},
+		{"  --Hello,   World--  ", "hello-world"},
+		{"Go 1.25 release notes", "go-1-25-release-notes"},
+		{"Café au lait", "café-au-lait"},
+		{"動画 テスト", "動画-テスト"},
+		{"!!!", ""},
+		{strings.Repeat("ab ", 40), strings.TrimSuffix(strings.Repeat("ab-", 21), "-") + "-a"},
+	}
+	for _, tt := range tests {
+		got := TitleSlug(tt.title)
+		if got != tt.want {
+			t.Errorf("TitleSlug(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+		if len([]rune(got)) > MaxTitleSlugLength {
+			t.Errorf("TitleSlug(%q) has %d characters, want at most %d", tt.title, len([]rune(got)), MaxTitleSlugLength)
+		}
+	}
+}
+
+func TestTitleSlugWithSuffix(t *testing.T) {
+	if got := TitleSlugWithSuffix("my-video", "2"); got != "my-video-2" {
+		t.Errorf("TitleSlugWithSuffix() = %q, want my-video-2", got)
+	}
+
+	// Shortening must not leave a double hyphen before the suffix
+	long := strings.Repeat("a", 61) + "-bc"
+	got := TitleSlugWithSuffix(long, "10")
+	if want := strings.Repeat("a", 61) + "-10"; got != want {
+		t.Errorf("TitleSlugWithSuffix() = %q, want %q", got, want)
+	}
+	if !IsValidTitleSlug(got) {
+		t.Errorf("IsValidTitleSlug(%q) = false, want true", got)
+	}
+}
+
+func TestIsValidTitleSlug(t *testing.T) {
+	tests := []struct {
+		slug string
+		want bool
+	}{
+		{"my-video", true},
+		{"my-video-2", true},
+		{"動画", true},
+		{"", false},
+		{"My-Video", false},
+		{"my--video", false},
+		{"-my-video", false},
+		{"my_video", false},
+		{strings.Repeat("a", MaxTitleSlugLength+1), false},
+	}
+	for _, tt := range tests {
+		if got := IsValidTitleSlug(tt.slug); got != tt.want {
+			t.Errorf("IsValidTitleSlug(%q) = %v, want %v", tt.slug, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package model
+
+import "github.com/google/uuid"
+
+// StorageUsage is the storage taken up by a user's videos that are not deleted: their
+// uploaded originals and the published output the worker wrote to our storage.
+type StorageUsage struct {
+	UserID        uuid.UUID
+	Videos        int64
+	OriginalBytes int64
+	OutputBytes   int64
+	// QuotaBytes is the most the user may store; 0 means unlimited.
+	QuotaBytes int64
+}
+
+// TotalBytes returns the bytes counted against the quota.
+func (u *StorageUsage) TotalBytes() int64 {
+	return u.OriginalBytes + u.OutputBytes
+}
+
+// Exhausted reports whether the user has no bytes left to store.
+func (u *StorageUsage) Exhausted() bool {
+	return u.QuotaBytes > 0 && u.TotalBytes() >= u.QuotaBytes
+}
+
+// Allows reports whether n more bytes fit within the quota.
+func (u *StorageUsage) Allows(n int64) bool {
+	return u.QuotaBytes <= 0 || u.TotalBytes()+n <= u.QuotaBytes
+}
@@ -0,0 +1,54 @@
+package model
+
+import "testing"
+
+func TestStorageUsage_Quota(t *testing.T) {
+	tests := []struct {
+		name          string
+		usage         StorageUsage
+		add           int64
+		wantExhausted bool
+		wantAllows    bool
+	}{
+		{
+			name:       "unlimited",
+			usage:      StorageUsage{OriginalBytes: 1 << 40, OutputBytes: 1 << 40},
+			add:        1 << 40,
+			wantAllows: true,
+		},
+		{
+			name:       "fits",
+			usage:      StorageUsage{OriginalBytes: 300, OutputBytes: 200, QuotaBytes: 1000},
+			add:        500,
+			wantAllows: true,
+		},
+		{
+			name:  "does not fit",
+			usage: StorageUsage{OriginalBytes: 300, OutputBytes: 200, QuotaBytes: 1000},
+			add:   501,
+		},
+		{
+			name:          "exhausted",
+			usage:         StorageUsage{OriginalBytes: 600, OutputBytes: 400, QuotaBytes: 1000},
+			add:           1,
+			wantExhausted: true,
+		},
+		{
+			name:          "over quota",
+			usage:         StorageUsage{OriginalBytes: 900, OutputBytes: 400, QuotaBytes: 1000},
+			add:           1,
+			wantExhausted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.usage.Exhausted(); got != tt.wantExhausted {
+				t.Errorf("Exhausted() = %v, want %v", got, tt.wantExhausted)
+			}
+			if got := tt.usage.Allows(tt.add); got != tt.wantAllows {
+				t.Errorf("Allows(%d) = %v, want %v", tt.add, got, tt.wantAllows)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subtitle formats accepted for upload.
+const (
+	SubtitleFormatSRT    = "srt"
+	SubtitleFormatWebVTT = "vtt"
+)
+
+const maxSubtitleLabelLength = 64
+
+// languageTagPattern matches BCP 47 language tags such as "en", "pt-BR" or "zh-Hant-TW".
+var languageTagPattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+var (
+	ErrInvalidSubtitleLanguage = errors.New("subtitle language must be a BCP 47 language tag")
+	ErrSubtitleLabelTooLong    = errors.New("subtitle label exceeds maximum length of 64 characters")
+	ErrInvalidSubtitleFormat   = errors.New("subtitle format must be srt or vtt")
+)
+
+// Subtitle is a subtitle track of a video, one per language. The uploaded file is kept as
+// is; the worker converts it to segmented WebVTT next to each HLS output version.
+type Subtitle struct {
+	ID      uuid.UUID
+	VideoID uuid.UUID
+	// Language is the BCP 47 language tag of the track (e.g., "en", "pt-BR").
+	Language string
+	// Label is the track name players show; defaults to Language.
+	Label string
+	// Format is the uploaded file's format, SubtitleFormatSRT or SubtitleFormatWebVTT.
+	Format string
+	// SourceKey is the storage key of the uploaded file.
+	SourceKey string
+	// Default marks the track players select when the viewer has not chosen one.
+	Default   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewSubtitle creates a subtitle track record with validation.
+func NewSubtitle(videoID uuid.UUID, language, label, format string, isDefault bool) (*Subtitle, error) {
+	if !languageTagPattern.MatchString(language) {
+		return nil, ErrInvalidSubtitleLanguage
+	}
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = language
+	}
+	if len(label) > maxSubtitleLabelLength {
+		return nil, ErrSubtitleLabelTooLong
+	}
+	if format != SubtitleFormatSRT && format != SubtitleFormatWebVTT {
+		return nil, ErrInvalidSubtitleFormat
+	}
+
+	now := time.Now()
+	return &Subtitle{
+		ID:        uuid.New(),
+		VideoID:   videoID,
+		Language:  language,
+		Label:     label,
+		Format:    format,
+		Default:   isDefault,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewSubtitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		label     string
+		format    string
+		wantLabel string
+		wantErr   error
+	}{
+		{name: "label defaults to language", language: "en", format: SubtitleFormatSRT, wantLabel: "en"},
+		{name: "region subtag", language: "pt-BR", label: " Português ", format: SubtitleFormatWebVTT, wantLabel: "Português"},
+		{name: "invalid language", language: "english!", format: SubtitleFormatSRT, wantErr: ErrInvalidSubtitleLanguage},
+		{name: "label too long", language: "en", label: strings.Repeat("a", 65), format: SubtitleFormatSRT, wantErr: ErrSubtitleLabelTooLong},
+		{name: "unknown format", language: "en", format: "ass", wantErr: ErrInvalidSubtitleFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := NewSubtitle(uuid.New(), tt.language, tt.label, tt.format, false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewSubtitle() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && sub.Label != tt.wantLabel {
+				t.Errorf("Label = %q, want %q", sub.Label, tt.wantLabel)
+			}
+		})
+	}
+}
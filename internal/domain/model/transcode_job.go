@@ -0,0 +1,119 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TranscodeJobStatus represents the outcome of a single transcode attempt.
+type TranscodeJobStatus string
+
+const (
+	JobStatusSucceeded TranscodeJobStatus = "SUCCEEDED"
+	JobStatusFailed    TranscodeJobStatus = "FAILED"
+)
+
+// Transcode pipeline stages, in execution order.
+const (
+	StageDownload  = "download"
+	StageProbe     = "probe"
+	StageTranscode = "transcode"
+	StageUpload    = "upload"
+	StagePreview   = "preview"
+	StageFinalize  = "finalize"
+)
+
+// StageTimings breaks down where a transcode attempt spent its time.
+// Stages that did not run (e.g., after an earlier failure) are zero.
+type StageTimings struct {
+	Download  time.Duration
+	Probe     time.Duration
+	Transcode time.Duration
+	// Variants holds the encode time of each ABR variant, keyed by variant name.
+	// Their sum is at most Transcode, which also covers master playlist generation.
+	Variants map[string]time.Duration
+	Upload   time.Duration
+	Preview  time.Duration
+	Finalize time.Duration
+}
+
+// Total returns the combined duration of all stages.
+func (t StageTimings) Total() time.Duration {
+	return t.Download + t.Probe + t.Transcode + t.Upload + t.Preview + t.Finalize
+}
+
+// TranscodeEstimate predicts the cost of transcoding a source from historical jobs.
+type TranscodeEstimate struct {
+	// ProcessingTime is the expected wall-clock time of a successful attempt.
+	ProcessingTime time.Duration
+	// OutputBytes is the expected size of the ABR output.
+	OutputBytes int64
+	// Samples is the number of historical jobs the estimate is based on.
+	Samples int64
+}
+
+// TranscodeJob records one worker attempt at processing a video.
+// Retries of the same task produce separate jobs with increasing Attempt numbers.
+type TranscodeJob struct {
+	ID            uuid.UUID
+	VideoID       uuid.UUID
+	OutputVersion int64
+	// Attempt is the zero-based retry count of the task this job processed.
+	Attempt int
+	Status  TranscodeJobStatus
+	Error   string
+	// Final reports whether this attempt decided the task's outcome:
+	// it succeeded, or it failed with no retries left.
+	Final   bool
+	Timings StageTimings
+	// SourceDuration and SourceHeight describe the probed original; zero if probing did not complete.
+	SourceDuration time.Duration
+	SourceHeight   int
+	// OutputBytes is the size of the uploaded ABR output; zero if the upload did not complete.
+	OutputBytes int64
+	// Estimate is the prediction for this attempt, kept to calibrate the estimator against
+	// the actual outcome. Nil when there was not enough history or the source was not probed.
+	Estimate *TranscodeEstimate
+	// QueuedAt is when processing was first requested; zero for tasks enqueued before it was tracked.
+	QueuedAt   time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// NewTranscodeJob creates a job record for an attempt that started at startedAt.
+func NewTranscodeJob(videoID uuid.UUID, outputVersion int64, attempt int, startedAt time.Time) *TranscodeJob {
+	return &TranscodeJob{
+		ID:            uuid.New(),
+		VideoID:       videoID,
+		OutputVersion: outputVersion,
+		Attempt:       attempt,
+		StartedAt:     startedAt,
+		Timings:       StageTimings{Variants: make(map[string]time.Duration)},
+	}
+}
+
+// Finish records the outcome of the attempt. A nil err marks the job as succeeded.
+func (j *TranscodeJob) Finish(err error, finishedAt time.Time) {
+	j.FinishedAt = finishedAt
+	if err != nil {
+		j.Status = JobStatusFailed
+		j.Error = err.Error()
+		return
+	}
+	j.Status = JobStatusSucceeded
+}
+
+// TimeToReady returns the time from the processing request to this attempt's success.
+// Returns false for failed attempts and for jobs without a queue timestamp.
+func (j *TranscodeJob) TimeToReady() (time.Duration, bool) {
+	if j.Status != JobStatusSucceeded || j.QueuedAt.IsZero() {
+		return 0, false
+	}
+	return j.FinishedAt.Sub(j.QueuedAt), true
+}
+
+// Duration returns the wall-clock time of the attempt.
+func (j *TranscodeJob) Duration() time.Duration {
+	return j.FinishedAt.Sub(j.StartedAt)
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTranscodeJob_Finish(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus TranscodeJobStatus
+		wantError  string
+	}{
+		{"success", nil, JobStatusSucceeded, ""},
+		{"failure", errors.New("ffmpeg crashed"), JobStatusFailed, "ffmpeg crashed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := NewTranscodeJob(uuid.New(), 1, 0, start)
+			job.Finish(tt.err, start.Add(3*time.Second))
+
+			if job.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", job.Status, tt.wantStatus)
+			}
+			if job.Error != tt.wantError {
+				t.Errorf("Error = %q, want %q", job.Error, tt.wantError)
+			}
+			if job.Duration() != 3*time.Second {
+				t.Errorf("Duration() = %v, want 3s", job.Duration())
+			}
+		})
+	}
+}
+
+func TestStageTimings_Total(t *testing.T) {
+	timings := StageTimings{
+		Download:  time.Second,
+		Probe:     100 * time.Millisecond,
+		Transcode: 10 * time.Second,
+		Variants:  map[string]time.Duration{"720p": 6 * time.Second, "360p": 4 * time.Second},
+		Upload:    2 * time.Second,
+		Finalize:  50 * time.Millisecond,
+	}
+
+	if got, want := timings.Total(), 13150*time.Millisecond; got != want {
+		t.Errorf("Total() = %v, want %v", got, want)
+	}
+}
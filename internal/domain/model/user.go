@@ -0,0 +1,82 @@
+package model
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxEmailLength    = 254
+	maxUserNameLength = 255
+	maxUserPlanLength = 32
+)
+
+var (
+	ErrInvalidEmail    = errors.New("email must be a valid address")
+	ErrUserNameTooLong = errors.New("user name exceeds maximum length")
+	ErrInvalidUserPlan = errors.New("plan must be at most 32 characters without spaces")
+)
+
+// User is an account owning videos (videos.user_id) and authenticating with API keys.
+// Videos created before users existed may reference IDs without a user.
+type User struct {
+	ID    uuid.UUID
+	Email string
+	Name  string
+	// Plan is the subscription plan selecting the user's concurrent stream limit; empty
+	// uses the default limit.
+	Plan      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewUser creates a User. The email is normalized to lowercase; the name is optional.
+func NewUser(email, name string) (*User, error) {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxUserNameLength {
+		return nil, ErrUserNameTooLong
+	}
+
+	now := time.Now()
+	return &User{
+		ID:        uuid.New(),
+		Email:     email,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// NormalizePlan trims plan and checks that it is a single word of at most 32 characters.
+func NormalizePlan(plan string) (string, error) {
+	plan = strings.TrimSpace(plan)
+	if len(plan) > maxUserPlanLength || strings.ContainsFunc(plan, unicode.IsSpace) {
+		return "", ErrInvalidUserPlan
+	}
+	return plan, nil
+}
+
+// NormalizeEmail lowercases email and checks that it is a bare address, without a
+// display name or angle brackets.
+func NormalizeEmail(email string) (string, error) {
+	e := strings.ToLower(strings.TrimSpace(email))
+	if e == "" || len(e) > maxEmailLength {
+		return "", ErrInvalidEmail
+	}
+	addr, err := mail.ParseAddress(e)
+	if err != nil || addr.Address != e || !strings.Contains(e[strings.LastIndexByte(e, '@'):], ".") {
+		return "", ErrInvalidEmail
+	}
+	return e, nil
+}
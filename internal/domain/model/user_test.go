@@ -0,0 +1,99 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		userName  string
+		wantEmail string
+		wantErr   error
+	}{
+		{
+			name:      "normalizes email",
+			email:     " Alice@Example.COM ",
+			userName:  " Alice ",
+			wantEmail: "alice@example.com",
+		},
+		{
+			name:    "empty email",
+			email:   "",
+			wantErr: ErrInvalidEmail,
+		},
+		{
+			name:    "missing domain",
+			email:   "alice@",
+			wantErr: ErrInvalidEmail,
+		},
+		{
+			name:    "single label domain",
+			email:   "alice@localhost",
+			wantErr: ErrInvalidEmail,
+		},
+		{
+			name:    "display name",
+			email:   "Alice <alice@example.com>",
+			wantErr: ErrInvalidEmail,
+		},
+		{
+			name:     "name too long",
+			email:    "alice@example.com",
+			userName: strings.Repeat("a", maxUserNameLength+1),
+			wantErr:  ErrUserNameTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := NewUser(tt.email, tt.userName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if user.Email != tt.wantEmail {
+				t.Errorf("Email = %q, want %q", user.Email, tt.wantEmail)
+			}
+			if user.Name != strings.TrimSpace(tt.userName) {
+				t.Errorf("Name = %q, want trimmed %q", user.Name, tt.userName)
+			}
+			if user.ID == uuid.Nil {
+				t.Error("expected an ID")
+			}
+		})
+	}
+}
+
+func TestNormalizePlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    string
+		want    string
+		wantErr error
+	}{
+		{name: "empty", plan: "", want: ""},
+		{name: "trimmed", plan: " premium ", want: "premium"},
+		{name: "inner space", plan: "gold plus", wantErr: ErrInvalidUserPlan},
+		{name: "too long", plan: strings.Repeat("p", maxUserPlanLength+1), wantErr: ErrInvalidUserPlan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePlan(tt.plan)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NormalizePlan() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizePlan() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package model
 
 import (
 	"errors"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -12,24 +15,52 @@ type Status string
 
 const (
 	StatusPendingUpload Status = "PENDING_UPLOAD"
+	StatusUploaded      Status = "UPLOADED"
 	StatusProcessing    Status = "PROCESSING"
 	StatusReady         Status = "READY"
 	StatusFailed        Status = "FAILED"
+	// StatusDeleted marks a video removed by its owner; its stored objects are
+	// garbage-collected by the worker.
+	StatusDeleted Status = "DELETED"
+	// StatusArchived marks a READY video whose output is kept in the archive tier
+	// (see VideoArchive); it must be restored before it can be streamed again.
+	StatusArchived Status = "ARCHIVED"
+	// StatusExpired marks a video unpublished because its ExpiresAt has passed; it is no
+	// longer listed or streamed, and may be deleted once the expiry grace period ends.
+	StatusExpired Status = "EXPIRED"
 )
 
 // Valid status transitions:
-// PENDING_UPLOAD -> PROCESSING -> READY
-//                            \-> FAILED
+// PENDING_UPLOAD -> UPLOADED -> PROCESSING -> READY
+//       \_____________________/         \-> FAILED
+//
+// PENDING_UPLOAD -> PROCESSING remains for clients that trigger processing
+// without confirming the upload first.
+//
+// READY <-> ARCHIVED moves the output to the archive tier and back.
+//
+// FAILED -> PROCESSING retries a failed transcode from the original.
+//
+// Every status except PROCESSING, DELETED and EXPIRED may move to EXPIRED once the
+// video's ExpiresAt passes; a video being transcoded expires when the transcode ends.
+//
+// Every status except PROCESSING may move to DELETED, which is terminal. A video
+// being transcoded cannot be deleted, since the worker would upload output after
+// the cleanup had run.
 var validTransitions = map[Status][]Status{
-	StatusPendingUpload: {StatusProcessing},
+	StatusPendingUpload: {StatusUploaded, StatusProcessing, StatusDeleted, StatusExpired},
+	StatusUploaded:      {StatusProcessing, StatusDeleted, StatusExpired},
 	StatusProcessing:    {StatusReady, StatusFailed},
-	StatusReady:         {},
-	StatusFailed:        {},
+	StatusReady:         {StatusArchived, StatusDeleted, StatusExpired},
+	StatusFailed:        {StatusProcessing, StatusDeleted, StatusExpired},
+	StatusDeleted:       {},
+	StatusArchived:      {StatusReady, StatusDeleted, StatusExpired},
+	StatusExpired:       {StatusDeleted},
 }
 
 func (s Status) IsValid() bool {
 	switch s {
-	case StatusPendingUpload, StatusProcessing, StatusReady, StatusFailed:
+	case StatusPendingUpload, StatusUploaded, StatusProcessing, StatusReady, StatusFailed, StatusDeleted, StatusArchived, StatusExpired:
 		return true
 	default:
 		return false
@@ -55,48 +86,152 @@ func (s Status) String() string {
 
 // Video represents a video entity in the domain.
 type Video struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	Title       string
-	Status      Status
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Title  string
+	// Description is free-form text set by the owner; empty when unset.
+	Description string
+	// Tags are set by the owner and normalized by SetTags: trimmed, lowercase and unique.
+	Tags   []string
+	Status Status
+	// Visibility controls who can see and play the video; see VisibleTo.
+	Visibility  Visibility
 	OriginalURL string
 	HLSURL      string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// DashURL is the MPEG-DASH manifest of the same output as HLSURL; either may be
+	// empty, depending on the output formats the worker produces.
+	DashURL string
+	// PreviewSeconds is the length of the public preview rendition; zero disables it.
+	PreviewSeconds int
+	PreviewURL     string
+	// ThumbnailPrefix is the storage prefix of the output's poster images, one
+	// {size}.jpg per ThumbnailSizes entry; empty when none were generated.
+	ThumbnailPrefix string
+	// OutputVersion identifies the output prefix HLSURL, DashURL, PreviewURL and
+	// ThumbnailPrefix point at.
+	// Regenerated output is written under a new version rather than overwriting
+	// objects in place, so CDN caches never need to be purged.
+	OutputVersion int64
+	// ExternalOutput marks output stored in the owner's OutputBucket rather than the
+	// platform's storage.
+	ExternalOutput bool
+	// SortableID is a time-ordered ID (see IDStrategy); empty for videos created before it existed.
+	SortableID string
+	// ShareSlug is the short ID used in share links (/v1/v/{slug}).
+	ShareSlug string
+	// TitleSlug is derived from the title and unique per user (/v1/u/{user}/v/{slug});
+	// empty when title slugs are disabled or the title has no letters or digits.
+	TitleSlug string
+	// StorageKey is the path segment the video's objects are stored under
+	// (originals/{key}/, hls/{key}/, previews/{key}/); empty for videos stored
+	// under their ID. See StoragePrefix.
+	StorageKey string
+	// OriginalSize and OriginalETag describe the uploaded original once the upload
+	// is confirmed; zero values before that.
+	OriginalSize int64
+	OriginalETag string
+	// Source describes the original as probed by the first transcode; zero before that.
+	Source SourceMetadata
+	// ExpiresAt is when the video is unpublished (see StatusExpired); zero never expires it.
+	ExpiresAt time.Time
+	// IdempotencyKey is the Idempotency-Key of the request that created the video, unique
+	// per user, and IdempotencyHash fingerprints that request to detect reuse of the key.
+	// Both are written on creation and only read back by a lookup by key.
+	IdempotencyKey  string
+	IdempotencyHash string
+	// FailureCode and FailureReason tell why a FAILED video failed; both are empty in
+	// every other status. See MarkFailed.
+	FailureCode   FailureCode
+	FailureReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	// Stale marks a cached copy served past its TTL because the database was
+	// unavailable; it is never persisted.
+	Stale bool
+}
+
+// SourceMetadata describes an uploaded original as reported by ffprobe.
+type SourceMetadata struct {
+	Duration time.Duration
+	Width    int
+	Height   int
+	// Codec is the codec of the video stream (e.g., "h264").
+	Codec string
+	// Bitrate is the overall bitrate in bits per second; zero if unknown.
+	Bitrate int64
+	// FrameRate is the average frame rate; zero if unknown.
+	FrameRate float64
+}
+
+// IsZero reports whether the metadata is unset, i.e. the original has not been probed.
+func (m SourceMetadata) IsZero() bool {
+	return m == SourceMetadata{}
 }
 
 var (
-	ErrEmptyTitle         = errors.New("title cannot be empty")
-	ErrInvalidUserID      = errors.New("user ID cannot be nil")
-	ErrInvalidTransition  = errors.New("invalid status transition")
-	ErrTitleTooLong       = errors.New("title exceeds maximum length of 255 characters")
+	ErrEmptyTitle             = errors.New("title cannot be empty")
+	ErrInvalidUserID          = errors.New("user ID cannot be nil")
+	ErrInvalidTransition      = errors.New("invalid status transition")
+	ErrTitleTooLong           = errors.New("title exceeds maximum length of 255 characters")
+	ErrInvalidPreviewDuration = errors.New("preview duration must be between 0 and 600 seconds")
+	ErrExpiryInPast           = errors.New("expiry time must be in the future")
+	ErrDescriptionTooLong     = errors.New("description exceeds maximum length of 5000 characters")
+	ErrTooManyTags            = errors.New("a video can have at most 20 tags")
+	ErrInvalidTag             = errors.New("tags must be 1 to 50 letters, digits, spaces, hyphens or underscores")
 )
 
-const maxTitleLength = 255
+const (
+	maxTitleLength       = 255
+	maxDescriptionLength = 5000
+	maxTags              = 20
+	maxTagLength         = 50
+)
+
+// ThumbnailSizes names the poster images generated for every output, smallest first.
+var ThumbnailSizes = []string{"small", "medium", "large"}
+
+// MaxPreviewSeconds caps preview renditions so they stay a teaser rather than a free copy.
+const MaxPreviewSeconds = 600
 
 // NewVideo creates a new Video with PENDING_UPLOAD status.
 func NewVideo(userID uuid.UUID, title string) (*Video, error) {
 	if userID == uuid.Nil {
 		return nil, ErrInvalidUserID
 	}
-	if title == "" {
-		return nil, ErrEmptyTitle
-	}
-	if len(title) > maxTitleLength {
-		return nil, ErrTitleTooLong
+	if err := validateTitle(title); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
 	return &Video{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Title:     title,
-		Status:    StatusPendingUpload,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:         uuid.New(),
+		UserID:     userID,
+		Title:      title,
+		Status:     StatusPendingUpload,
+		Visibility: VisibilityUnlisted,
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	}, nil
 }
 
+func validateTitle(title string) error {
+	if title == "" {
+		return ErrEmptyTitle
+	}
+	if len(title) > maxTitleLength {
+		return ErrTitleTooLong
+	}
+	return nil
+}
+
+// StoragePrefix returns the path segment the video's objects are stored under.
+func (v *Video) StoragePrefix() string {
+	if v.StorageKey != "" {
+		return v.StorageKey
+	}
+	return v.ID.String()
+}
+
 // TransitionTo attempts to change the video status.
 // Returns error if the transition is not allowed.
 func (v *Video) TransitionTo(next Status) error {
@@ -106,11 +241,25 @@ func (v *Video) TransitionTo(next Status) error {
 	if !v.Status.CanTransitionTo(next) {
 		return ErrInvalidTransition
 	}
+	if v.Status == StatusFailed {
+		v.FailureCode = ""
+		v.FailureReason = ""
+	}
 	v.Status = next
 	v.UpdatedAt = time.Now()
 	return nil
 }
 
+// MarkUploaded records the confirmed original object and transitions to UPLOADED.
+func (v *Video) MarkUploaded(size int64, etag string) error {
+	if err := v.TransitionTo(StatusUploaded); err != nil {
+		return err
+	}
+	v.OriginalSize = size
+	v.OriginalETag = etag
+	return nil
+}
+
 // SetOriginalURL sets the original video URL after upload.
 func (v *Video) SetOriginalURL(url string) {
 	v.OriginalURL = url
@@ -123,11 +272,143 @@ func (v *Video) SetHLSURL(url string) {
 	v.UpdatedAt = time.Now()
 }
 
+// SetDashURL sets the MPEG-DASH manifest URL after transcoding.
+func (v *Video) SetDashURL(url string) {
+	v.DashURL = url
+	v.UpdatedAt = time.Now()
+}
+
+// SetTitle renames the video. The title slug is left as it is, so links to the old
+// slug keep working.
+func (v *Video) SetTitle(title string) error {
+	if err := validateTitle(title); err != nil {
+		return err
+	}
+	v.Title = title
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetDescription sets the description; an empty string clears it.
+func (v *Video) SetDescription(description string) error {
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return ErrDescriptionTooLong
+	}
+	v.Description = description
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetTags replaces the tags. Each tag is trimmed and lowercased, and duplicates are
+// dropped keeping the first occurrence; an empty list clears the tags.
+func (v *Video) SetTags(tags []string) error {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !validTag(tag) {
+			return ErrInvalidTag
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return ErrTooManyTags
+	}
+	v.Tags = normalized
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+func validTag(tag string) bool {
+	if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+		return false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// SetPreviewSeconds configures the preview rendition length. Zero disables previews.
+func (v *Video) SetPreviewSeconds(seconds int) error {
+	if seconds < 0 || seconds > MaxPreviewSeconds {
+		return ErrInvalidPreviewDuration
+	}
+	v.PreviewSeconds = seconds
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetExpiresAt schedules the video to expire at t; the zero time clears the expiry.
+// Returns ErrExpiryInPast unless t is in the future.
+func (v *Video) SetExpiresAt(t time.Time) error {
+	now := time.Now()
+	if !t.IsZero() && !t.After(now) {
+		return ErrExpiryInPast
+	}
+	v.ExpiresAt = t
+	v.UpdatedAt = now
+	return nil
+}
+
+// SetPreviewURL sets the preview manifest URL after transcoding.
+func (v *Video) SetPreviewURL(url string) {
+	v.PreviewURL = url
+	v.UpdatedAt = time.Now()
+}
+
+// SetThumbnailPrefix sets the prefix of the poster images after transcoding.
+func (v *Video) SetThumbnailPrefix(prefix string) {
+	v.ThumbnailPrefix = prefix
+	v.UpdatedAt = time.Now()
+}
+
+// ThumbnailKey returns the key of the poster image of the given size, or an empty string
+// when the output has no thumbnails.
+func (v *Video) ThumbnailKey(size string) string {
+	if v.ThumbnailPrefix == "" {
+		return ""
+	}
+	return strings.TrimSuffix(v.ThumbnailPrefix, "/") + "/" + size + ".jpg"
+}
+
+// SetOutputVersion records which output version the HLS and preview URLs belong to.
+func (v *Video) SetOutputVersion(version int64) {
+	v.OutputVersion = version
+	v.UpdatedAt = time.Now()
+}
+
+// HasPreview returns true if a preview rendition has been generated.
+func (v *Video) HasPreview() bool {
+	return v.PreviewSeconds > 0 && v.PreviewURL != ""
+}
+
 // IsReady returns true if the video is ready for streaming.
 func (v *Video) IsReady() bool {
 	return v.Status == StatusReady
 }
 
+// IsDeleted returns true if the video has been deleted by its owner.
+func (v *Video) IsDeleted() bool {
+	return v.Status == StatusDeleted
+}
+
+// IsArchived returns true if the video's output is in the archive tier.
+func (v *Video) IsArchived() bool {
+	return v.Status == StatusArchived
+}
+
+// IsExpired returns true if the video has been unpublished by its expiry.
+func (v *Video) IsExpired() bool {
+	return v.Status == StatusExpired
+}
+
 // IsFailed returns true if the video processing failed.
 func (v *Video) IsFailed() bool {
 	return v.Status == StatusFailed
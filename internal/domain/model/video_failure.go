@@ -0,0 +1,54 @@
+package model
+
+import "unicode/utf8"
+
+// FailureCode categorizes why a video failed, so clients can tell a bad upload from an
+// outage without parsing FailureReason.
+type FailureCode string
+
+const (
+	// FailureCodeInvalidInput means the original cannot be transcoded as uploaded
+	// (e.g., it has no video stream); uploading another file is the fix.
+	FailureCodeInvalidInput FailureCode = "invalid_input"
+	// FailureCodeEncodeFailed means FFmpeg exited with an error; FailureReason ends
+	// with the tail of its output.
+	FailureCodeEncodeFailed FailureCode = "encode_failed"
+	// FailureCodeRetriesExhausted means attempts kept failing without a recorded cause,
+	// e.g. because the worker crashed mid-transcode.
+	FailureCodeRetriesExhausted FailureCode = "retries_exhausted"
+	// FailureCodeExpired means the transcode task waited in the queue past its expiry.
+	FailureCodeExpired FailureCode = "expired"
+	// FailureCodeTimedOut means the last attempt ran past its deadline, e.g. because
+	// FFmpeg hung on a corrupt input.
+	FailureCodeTimedOut FailureCode = "timed_out"
+	// FailureCodeInternal covers every other failure, such as storage errors.
+	FailureCodeInternal FailureCode = "internal"
+)
+
+// MaxFailureReasonLength caps FailureReason in bytes; longer reasons keep their end,
+// which is where FFmpeg prints the cause.
+const MaxFailureReasonLength = 2048
+
+// MarkFailed transitions the video to FAILED and records why. The reason is truncated
+// to MaxFailureReasonLength.
+func (v *Video) MarkFailed(code FailureCode, reason string) error {
+	if err := v.TransitionTo(StatusFailed); err != nil {
+		return err
+	}
+	v.FailureCode = code
+	v.FailureReason = truncateFailureReason(reason)
+	return nil
+}
+
+// truncateFailureReason keeps the last MaxFailureReasonLength bytes of reason without
+// splitting a UTF-8 sequence.
+func truncateFailureReason(reason string) string {
+	if len(reason) <= MaxFailureReasonLength {
+		return reason
+	}
+	start := len(reason) - MaxFailureReasonLength
+	for start < len(reason) && !utf8.RuneStart(reason[start]) {
+		start++
+	}
+	return reason[start:]
+}
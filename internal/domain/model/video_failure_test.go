@@ -0,0 +1,76 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestVideo_MarkFailed(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     Status
+		reason     string
+		wantErr    error
+		wantReason string
+	}{
+		{
+			name:       "processing video",
+			status:     StatusProcessing,
+			reason:     "ffmpeg exited with status 1: Invalid data found when processing input",
+			wantReason: "ffmpeg exited with status 1: Invalid data found when processing input",
+		},
+		{
+			name:       "long reason keeps its end",
+			status:     StatusProcessing,
+			reason:     strings.Repeat("a", MaxFailureReasonLength) + "cause",
+			wantReason: strings.Repeat("a", MaxFailureReasonLength-len("cause")) + "cause",
+		},
+		{
+			name:    "ready video",
+			status:  StatusReady,
+			reason:  "boom",
+			wantErr: ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Video{Status: tt.status}
+			err := v.MarkFailed(FailureCodeEncodeFailed, tt.reason)
+			if err != tt.wantErr {
+				t.Fatalf("MarkFailed() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if v.FailureCode != "" || v.FailureReason != "" {
+					t.Errorf("failure recorded on rejected transition: %q, %q", v.FailureCode, v.FailureReason)
+				}
+				return
+			}
+			if v.Status != StatusFailed || v.FailureCode != FailureCodeEncodeFailed || v.FailureReason != tt.wantReason {
+				t.Errorf("got status %s, code %q, reason %q", v.Status, v.FailureCode, v.FailureReason)
+			}
+		})
+	}
+}
+
+func TestVideo_MarkFailed_ClearedOnRetry(t *testing.T) {
+	v := &Video{Status: StatusProcessing}
+	if err := v.MarkFailed(FailureCodeInvalidInput, "input has no video stream"); err != nil {
+		t.Fatalf("MarkFailed() error = %v", err)
+	}
+	if err := v.TransitionTo(StatusProcessing); err != nil {
+		t.Fatalf("TransitionTo() error = %v", err)
+	}
+	if v.FailureCode != "" || v.FailureReason != "" {
+		t.Errorf("failure kept after leaving FAILED: %q, %q", v.FailureCode, v.FailureReason)
+	}
+}
+
+func TestTruncateFailureReason_KeepsRunes(t *testing.T) {
+	reason := strings.Repeat("é", MaxFailureReasonLength)
+	got := truncateFailureReason(reason)
+	if len(got) > MaxFailureReasonLength || !utf8.ValidString(got) {
+		t.Errorf("truncated to %d bytes, valid UTF-8 = %v", len(got), utf8.ValidString(got))
+	}
+}
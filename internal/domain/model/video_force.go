@@ -0,0 +1,38 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrStatusNotForceable is returned when a video cannot be forced into a status.
+var ErrStatusNotForceable = errors.New("status cannot be forced")
+
+// ForceStatus moves the video to next regardless of the transition rules, for operators
+// repairing a video left stuck by a lost task or a crashed worker. DELETED and ARCHIVED
+// can be neither left nor entered this way, as their stored objects would not match the
+// status, and READY requires published output. A video forced into FAILED records
+// FailureCodeInternal with reason.
+func (v *Video) ForceStatus(next Status, reason string) error {
+	if !next.IsValid() || next == v.Status {
+		return ErrStatusNotForceable
+	}
+	for _, s := range []Status{v.Status, next} {
+		if s == StatusDeleted || s == StatusArchived {
+			return ErrStatusNotForceable
+		}
+	}
+	if next == StatusReady && v.HLSURL == "" && v.DashURL == "" {
+		return ErrStatusNotForceable
+	}
+
+	v.FailureCode = ""
+	v.FailureReason = ""
+	if next == StatusFailed {
+		v.FailureCode = FailureCodeInternal
+		v.FailureReason = truncateFailureReason(reason)
+	}
+	v.Status = next
+	v.UpdatedAt = time.Now()
+	return nil
+}
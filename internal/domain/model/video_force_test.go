@@ -0,0 +1,95 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVideo_ForceStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   Status
+		hlsURL   string
+		next     Status
+		wantErr  error
+		wantCode FailureCode
+	}{
+		{
+			name:   "stuck processing back to uploaded",
+			status: StatusProcessing,
+			next:   StatusUploaded,
+		},
+		{
+			name:     "stuck processing to failed",
+			status:   StatusProcessing,
+			next:     StatusFailed,
+			wantCode: FailureCodeInternal,
+		},
+		{
+			name:   "failed with output to ready",
+			status: StatusFailed,
+			hlsURL: "hls/v/1/master.m3u8",
+			next:   StatusReady,
+		},
+		{
+			name:    "ready without output",
+			status:  StatusProcessing,
+			next:    StatusReady,
+			wantErr: ErrStatusNotForceable,
+		},
+		{
+			name:    "same status",
+			status:  StatusProcessing,
+			next:    StatusProcessing,
+			wantErr: ErrStatusNotForceable,
+		},
+		{
+			name:    "out of deleted",
+			status:  StatusDeleted,
+			next:    StatusUploaded,
+			wantErr: ErrStatusNotForceable,
+		},
+		{
+			name:    "into archived",
+			status:  StatusReady,
+			hlsURL:  "hls/v/1/master.m3u8",
+			next:    StatusArchived,
+			wantErr: ErrStatusNotForceable,
+		},
+		{
+			name:    "unknown status",
+			status:  StatusReady,
+			next:    Status("PAUSED"),
+			wantErr: ErrStatusNotForceable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Video{Status: tt.status, HLSURL: tt.hlsURL, FailureCode: FailureCodeTimedOut, FailureReason: "timed out"}
+			if tt.status != StatusFailed {
+				v.FailureCode, v.FailureReason = "", ""
+			}
+
+			err := v.ForceStatus(tt.next, "stuck after broker outage")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ForceStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if v.Status != tt.status {
+					t.Errorf("status changed to %s on error", v.Status)
+				}
+				return
+			}
+			if v.Status != tt.next {
+				t.Errorf("status = %s, want %s", v.Status, tt.next)
+			}
+			if v.FailureCode != tt.wantCode {
+				t.Errorf("failure code = %q, want %q", v.FailureCode, tt.wantCode)
+			}
+			if tt.wantCode != "" && v.FailureReason != "stuck after broker outage" {
+				t.Errorf("failure reason = %q", v.FailureReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoStats counts the views of a video. It is also used for the views buffered since
+// the last flush, which are added to the stored counts with Add.
+type VideoStats struct {
+	VideoID uuid.UUID
+	Views   int64
+	// LastViewedAt is zero until the video is first viewed.
+	LastViewedAt time.Time
+}
+
+// Add counts other's views in s, keeping the later of the two last views.
+func (s *VideoStats) Add(other *VideoStats) {
+	s.Views += other.Views
+	if other.LastViewedAt.After(s.LastViewedAt) {
+		s.LastViewedAt = other.LastViewedAt
+	}
+}
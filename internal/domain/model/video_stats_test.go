@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestVideoStats_Add(t *testing.T) {
+	videoID := uuid.New()
+	earlier := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		stats     VideoStats
+		other     VideoStats
+		wantViews int64
+		wantLast  time.Time
+	}{
+		{
+			name:      "later view is kept",
+			stats:     VideoStats{VideoID: videoID, Views: 10, LastViewedAt: earlier},
+			other:     VideoStats{VideoID: videoID, Views: 2, LastViewedAt: later},
+			wantViews: 12,
+			wantLast:  later,
+		},
+		{
+			name:      "earlier view is ignored",
+			stats:     VideoStats{VideoID: videoID, Views: 10, LastViewedAt: later},
+			other:     VideoStats{VideoID: videoID, Views: 2, LastViewedAt: earlier},
+			wantViews: 12,
+			wantLast:  later,
+		},
+		{
+			name:      "first views",
+			stats:     VideoStats{VideoID: videoID},
+			other:     VideoStats{VideoID: videoID, Views: 2, LastViewedAt: earlier},
+			wantViews: 2,
+			wantLast:  earlier,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.stats.Add(&tt.other)
+			if tt.stats.Views != tt.wantViews {
+				t.Errorf("Views = %d, want %d", tt.stats.Views, tt.wantViews)
+			}
+			if !tt.stats.LastViewedAt.Equal(tt.wantLast) {
+				t.Errorf("LastViewedAt = %v, want %v", tt.stats.LastViewedAt, tt.wantLast)
+			}
+		})
+	}
+}
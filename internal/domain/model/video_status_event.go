@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusActor identifies the component that changed a video's status.
+type StatusActor string
+
+const (
+	// StatusActorAPI is a request to the API, by the owner or an operator.
+	StatusActorAPI StatusActor = "api"
+	// StatusActorWorker is the transcode worker, including its retry handling.
+	StatusActorWorker StatusActor = "worker"
+	// StatusActorExpirer is the scheduler that expires and deletes videos past expires_at.
+	StatusActorExpirer StatusActor = "expirer"
+	// StatusActorArchiver is the archive tiering of cold videos.
+	StatusActorArchiver StatusActor = "archiver"
+	// StatusActorAdmin is an operator forcing a status through the admin API.
+	StatusActorAdmin StatusActor = "admin"
+)
+
+// VideoStatusEvent is the audit record of one status transition of a video.
+type VideoStatusEvent struct {
+	ID      uuid.UUID
+	VideoID uuid.UUID
+	// From is empty for the status a video was created with.
+	From  Status
+	To    Status
+	Actor StatusActor
+	// Reason says why the transition happened, e.g. the error a transcode failed with.
+	Reason    string
+	CreatedAt time.Time
+}
+
+// NewVideoStatusEvent creates the record of a transition happening now.
+func NewVideoStatusEvent(videoID uuid.UUID, from, to Status, actor StatusActor, reason string) *VideoStatusEvent {
+	return &VideoStatusEvent{
+		ID:        uuid.New(),
+		VideoID:   videoID,
+		From:      from,
+		To:        to,
+		Actor:     actor,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,9 +15,13 @@ func TestStatus_IsValid(t *testing.T) {
 		want   bool
 	}{
 		{"PENDING_UPLOAD is valid", StatusPendingUpload, true},
+		{"UPLOADED is valid", StatusUploaded, true},
 		{"PROCESSING is valid", StatusProcessing, true},
 		{"READY is valid", StatusReady, true},
 		{"FAILED is valid", StatusFailed, true},
+		{"DELETED is valid", StatusDeleted, true},
+		{"ARCHIVED is valid", StatusArchived, true},
+		{"EXPIRED is valid", StatusExpired, true},
 		{"empty string is invalid", Status(""), false},
 		{"unknown status is invalid", Status("UNKNOWN"), false},
 	}
@@ -39,8 +44,22 @@ func TestStatus_CanTransitionTo(t *testing.T) {
 	}{
 		// Valid transitions
 		{"PENDING_UPLOAD -> PROCESSING", StatusPendingUpload, StatusProcessing, true},
+		{"PENDING_UPLOAD -> UPLOADED", StatusPendingUpload, StatusUploaded, true},
+		{"UPLOADED -> PROCESSING", StatusUploaded, StatusProcessing, true},
 		{"PROCESSING -> READY", StatusProcessing, StatusReady, true},
 		{"PROCESSING -> FAILED", StatusProcessing, StatusFailed, true},
+		{"PENDING_UPLOAD -> DELETED", StatusPendingUpload, StatusDeleted, true},
+		{"UPLOADED -> DELETED", StatusUploaded, StatusDeleted, true},
+		{"READY -> DELETED", StatusReady, StatusDeleted, true},
+		{"FAILED -> DELETED", StatusFailed, StatusDeleted, true},
+		{"FAILED -> PROCESSING (retranscode)", StatusFailed, StatusProcessing, true},
+		{"READY -> ARCHIVED", StatusReady, StatusArchived, true},
+		{"ARCHIVED -> READY", StatusArchived, StatusReady, true},
+		{"ARCHIVED -> DELETED", StatusArchived, StatusDeleted, true},
+		{"READY -> EXPIRED", StatusReady, StatusExpired, true},
+		{"PENDING_UPLOAD -> EXPIRED", StatusPendingUpload, StatusExpired, true},
+		{"ARCHIVED -> EXPIRED", StatusArchived, StatusExpired, true},
+		{"EXPIRED -> DELETED (grace period over)", StatusExpired, StatusDeleted, true},
 
 		// Invalid transitions
 		{"PENDING_UPLOAD -> READY (skip)", StatusPendingUpload, StatusReady, false},
@@ -48,6 +67,16 @@ func TestStatus_CanTransitionTo(t *testing.T) {
 		{"READY -> PROCESSING (reverse)", StatusReady, StatusProcessing, false},
 		{"FAILED -> READY (terminal)", StatusFailed, StatusReady, false},
 		{"READY -> PENDING_UPLOAD (reverse)", StatusReady, StatusPendingUpload, false},
+		{"UPLOADED -> PENDING_UPLOAD (reverse)", StatusUploaded, StatusPendingUpload, false},
+		{"UPLOADED -> READY (skip)", StatusUploaded, StatusReady, false},
+		{"PROCESSING -> UPLOADED (reverse)", StatusProcessing, StatusUploaded, false},
+		{"PROCESSING -> DELETED (in flight)", StatusProcessing, StatusDeleted, false},
+		{"DELETED -> PROCESSING (terminal)", StatusDeleted, StatusProcessing, false},
+		{"FAILED -> ARCHIVED (no output)", StatusFailed, StatusArchived, false},
+		{"ARCHIVED -> PROCESSING", StatusArchived, StatusProcessing, false},
+		{"PROCESSING -> EXPIRED (in flight)", StatusProcessing, StatusExpired, false},
+		{"EXPIRED -> READY (terminal)", StatusExpired, StatusReady, false},
+		{"DELETED -> EXPIRED (terminal)", StatusDeleted, StatusExpired, false},
 
 		// Self transitions
 		{"PENDING_UPLOAD -> PENDING_UPLOAD", StatusPendingUpload, StatusPendingUpload, false},
@@ -227,6 +256,24 @@ func TestVideo_TransitionTo(t *testing.T) {
 	}
 }
 
+func TestVideo_MarkUploaded(t *testing.T) {
+	video, _ := NewVideo(uuid.New(), "test")
+
+	if err := video.MarkUploaded(1024, "abc123"); err != nil {
+		t.Fatalf("Video.MarkUploaded() error = %v", err)
+	}
+	if video.Status != StatusUploaded || video.OriginalSize != 1024 || video.OriginalETag != "abc123" {
+		t.Errorf("unexpected video after MarkUploaded: status=%v size=%d etag=%q", video.Status, video.OriginalSize, video.OriginalETag)
+	}
+
+	if err := video.MarkUploaded(2048, "def456"); err != ErrInvalidTransition {
+		t.Errorf("second MarkUploaded() error = %v, want %v", err, ErrInvalidTransition)
+	}
+	if video.OriginalSize != 1024 {
+		t.Error("a rejected MarkUploaded() must not change the recorded object")
+	}
+}
+
 func TestVideo_SetOriginalURL(t *testing.T) {
 	video, _ := NewVideo(uuid.New(), "test")
 	oldUpdatedAt := video.UpdatedAt
@@ -255,6 +302,27 @@ func TestVideo_SetHLSURL(t *testing.T) {
 	}
 }
 
+func TestVideo_ThumbnailKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{"no thumbnails", "", ""},
+		{"storage prefix", "hls/abc/v2/thumbnails/", "hls/abc/v2/thumbnails/small.jpg"},
+		{"CDN URL without trailing slash", "https://cdn.example.com/hls/abc/v2/thumbnails", "https://cdn.example.com/hls/abc/v2/thumbnails/small.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &Video{ThumbnailPrefix: tt.prefix}
+			if got := video.ThumbnailKey("small"); got != tt.want {
+				t.Errorf("ThumbnailKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestVideo_IsReady(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -302,3 +370,189 @@ func TestVideo_IsFailed(t *testing.T) {
 		})
 	}
 }
+
+func TestVideo_SetPreviewSeconds(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int
+		wantErr error
+	}{
+		{"zero disables preview", 0, nil},
+		{"typical preview", 60, nil},
+		{"maximum preview", MaxPreviewSeconds, nil},
+		{"negative rejected", -1, ErrInvalidPreviewDuration},
+		{"too long rejected", MaxPreviewSeconds + 1, ErrInvalidPreviewDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, _ := NewVideo(uuid.New(), "test")
+
+			err := video.SetPreviewSeconds(tt.seconds)
+			if err != tt.wantErr {
+				t.Fatalf("SetPreviewSeconds() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && video.PreviewSeconds != tt.seconds {
+				t.Errorf("PreviewSeconds = %d, want %d", video.PreviewSeconds, tt.seconds)
+			}
+		})
+	}
+}
+
+func TestVideo_SetTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		wantErr error
+	}{
+		{"new title", "Renamed", nil},
+		{"empty title rejected", "", ErrEmptyTitle},
+		{"too long rejected", strings.Repeat("a", 256), ErrTitleTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, _ := NewVideo(uuid.New(), "test")
+			video.TitleSlug = "test"
+
+			err := video.SetTitle(tt.title)
+			if err != tt.wantErr {
+				t.Fatalf("SetTitle() error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.title
+			if tt.wantErr != nil {
+				want = "test"
+			}
+			if video.Title != want {
+				t.Errorf("Title = %q, want %q", video.Title, want)
+			}
+			if video.TitleSlug != "test" {
+				t.Errorf("TitleSlug = %q, want it unchanged", video.TitleSlug)
+			}
+		})
+	}
+}
+
+func TestVideo_SetDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		wantErr     error
+	}{
+		{"empty clears description", "", nil},
+		{"typical description", "A walk through the park.", nil},
+		{"maximum length counts characters", strings.Repeat("あ", 5000), nil},
+		{"too long rejected", strings.Repeat("a", 5001), ErrDescriptionTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, _ := NewVideo(uuid.New(), "test")
+			video.Description = "previous"
+
+			err := video.SetDescription(tt.description)
+			if err != tt.wantErr {
+				t.Fatalf("SetDescription() error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.description
+			if tt.wantErr != nil {
+				want = "previous"
+			}
+			if video.Description != want {
+				t.Errorf("Description = %q, want %q", video.Description, want)
+			}
+		})
+	}
+}
+
+func TestVideo_SetTags(t *testing.T) {
+	tooMany := make([]string, 21)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		{"empty clears tags", nil, []string{}, nil},
+		{"normalized", []string{" Go ", "Live-Stream", "cooking_101"}, []string{"go", "live-stream", "cooking_101"}, nil},
+		{"duplicates dropped in order", []string{"b", "A", "a", "B"}, []string{"b", "a"}, nil},
+		{"non-ASCII letters allowed", []string{"料理"}, []string{"料理"}, nil},
+		{"duplicates do not count toward the limit", append(tooMany[:20:20], "T"), tooMany[:20], nil},
+		{"blank tag rejected", []string{"go", "  "}, nil, ErrInvalidTag},
+		{"punctuation rejected", []string{"go,rust"}, nil, ErrInvalidTag},
+		{"too long tag rejected", []string{strings.Repeat("a", 51)}, nil, ErrInvalidTag},
+		{"too many tags rejected", tooMany, nil, ErrTooManyTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, _ := NewVideo(uuid.New(), "test")
+			video.Tags = []string{"previous"}
+
+			err := video.SetTags(tt.tags)
+			if err != tt.wantErr {
+				t.Fatalf("SetTags() error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.want
+			if tt.wantErr != nil {
+				want = []string{"previous"}
+			}
+			if strings.Join(video.Tags, ",") != strings.Join(want, ",") || len(video.Tags) != len(want) {
+				t.Errorf("Tags = %q, want %q", video.Tags, want)
+			}
+		})
+	}
+}
+
+func TestVideo_HasPreview(t *testing.T) {
+	video, _ := NewVideo(uuid.New(), "test")
+	if video.HasPreview() {
+		t.Error("new video should not have a preview")
+	}
+
+	_ = video.SetPreviewSeconds(60)
+	if video.HasPreview() {
+		t.Error("video without preview URL should not have a preview")
+	}
+
+	video.SetPreviewURL("previews/abc/playlist.m3u8")
+	if !video.HasPreview() {
+		t.Error("video with preview duration and URL should have a preview")
+	}
+}
+
+func TestVideo_SetExpiresAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{"future time schedules expiry", time.Now().Add(time.Hour), nil},
+		{"zero time clears expiry", time.Time{}, nil},
+		{"past time is rejected", time.Now().Add(-time.Minute), ErrExpiryInPast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, _ := NewVideo(uuid.New(), "test")
+			video.ExpiresAt = time.Now().Add(24 * time.Hour)
+			previous := video.ExpiresAt
+
+			err := video.SetExpiresAt(tt.expiresAt)
+			if err != tt.wantErr {
+				t.Fatalf("SetExpiresAt() error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.expiresAt
+			if tt.wantErr != nil {
+				want = previous
+			}
+			if !video.ExpiresAt.Equal(want) {
+				t.Errorf("ExpiresAt = %v, want %v", video.ExpiresAt, want)
+			}
+		})
+	}
+}
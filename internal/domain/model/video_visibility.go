@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Visibility controls who can see and play a video.
+type Visibility string
+
+const (
+	// VisibilityPublic videos can be seen by anyone and may be listed by discovery
+	// endpoints.
+	VisibilityPublic Visibility = "public"
+	// VisibilityUnlisted videos can be seen by anyone who has their ID or share link, but
+	// are left out of discovery endpoints. New videos are unlisted.
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityPrivate videos can only be seen and played by their owner.
+	VisibilityPrivate Visibility = "private"
+)
+
+var ErrInvalidVisibility = errors.New("visibility must be public, unlisted or private")
+
+func (v Visibility) IsValid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPrivate:
+		return true
+	default:
+		return false
+	}
+}
+
+func (v Visibility) String() string {
+	return string(v)
+}
+
+// SetVisibility changes who can see the video.
+func (v *Video) SetVisibility(visibility Visibility) error {
+	if !visibility.IsValid() {
+		return ErrInvalidVisibility
+	}
+	v.Visibility = visibility
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// VisibleTo reports whether userID may see and play the video; uuid.Nil stands for an
+// anonymous caller. Only private videos are restricted, to their owner.
+func (v *Video) VisibleTo(userID uuid.UUID) bool {
+	return v.Visibility.allows(v.UserID, userID)
+}
+
+// PlaybackURLsVisibleTo reports whether userID may be shown the video's HLS and DASH
+// URLs. With entitlements enforced or a preview set, anyone but the owner has to go
+// through a playback token or signed URL, which check them.
+func (v *Video) PlaybackURLsVisibleTo(userID uuid.UUID, entitlementsEnforced bool) bool {
+	if userID != uuid.Nil && userID == v.UserID {
+		return true
+	}
+	return !entitlementsEnforced && v.PreviewSeconds == 0
+}
+
+// allows reports whether userID may see something of ownerID's with this visibility.
+func (v Visibility) allows(ownerID, userID uuid.UUID) bool {
+	return v != VisibilityPrivate || (userID != uuid.Nil && userID == ownerID)
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestVideo_SetVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility Visibility
+		wantErr    error
+	}{
+		{"public", VisibilityPublic, nil},
+		{"unlisted", VisibilityUnlisted, nil},
+		{"private", VisibilityPrivate, nil},
+		{"empty rejected", "", ErrInvalidVisibility},
+		{"unknown rejected", "friends", ErrInvalidVisibility},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, _ := NewVideo(uuid.New(), "test")
+			if video.Visibility != VisibilityUnlisted {
+				t.Fatalf("new video Visibility = %q, want %q", video.Visibility, VisibilityUnlisted)
+			}
+
+			err := video.SetVisibility(tt.visibility)
+			if err != tt.wantErr {
+				t.Fatalf("SetVisibility() error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.visibility
+			if tt.wantErr != nil {
+				want = VisibilityUnlisted
+			}
+			if video.Visibility != want {
+				t.Errorf("Visibility = %q, want %q", video.Visibility, want)
+			}
+		})
+	}
+}
+
+func TestVideo_VisibleTo(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name       string
+		visibility Visibility
+		viewer     uuid.UUID
+		want       bool
+	}{
+		{"public to anyone", VisibilityPublic, uuid.Nil, true},
+		{"unlisted to another user", VisibilityUnlisted, other, true},
+		{"unlisted to anonymous", VisibilityUnlisted, uuid.Nil, true},
+		{"private to owner", VisibilityPrivate, owner, true},
+		{"private to another user", VisibilityPrivate, other, false},
+		{"private to anonymous", VisibilityPrivate, uuid.Nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &Video{ID: uuid.New(), UserID: owner, Visibility: tt.visibility}
+			if got := video.VisibleTo(tt.viewer); got != tt.want {
+				t.Errorf("VisibleTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideo_PlaybackURLsVisibleTo(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name         string
+		viewer       uuid.UUID
+		entitlements bool
+		preview      int
+		want         bool
+	}{
+		{"ungated to another user", other, false, 0, true},
+		{"ungated to anonymous", uuid.Nil, false, 0, true},
+		{"entitlements, owner", owner, true, 0, true},
+		{"entitlements, another user", other, true, 0, false},
+		{"preview, owner", owner, false, 30, true},
+		{"preview, another user", other, false, 30, false},
+		{"preview, anonymous", uuid.Nil, false, 30, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &Video{ID: uuid.New(), UserID: owner, PreviewSeconds: tt.preview}
+			if got := video.PlaybackURLsVisibleTo(tt.viewer, tt.entitlements); got != tt.want {
+				t.Errorf("PlaybackURLsVisibleTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
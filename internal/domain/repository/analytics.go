@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// AnalyticsEventSource reads analytics events from the system of record.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type AnalyticsEventSource interface {
+	// ListEvents returns the events of stream that occurred in [from, to), oldest first.
+	ListEvents(ctx context.Context, stream model.AnalyticsStream, from, to time.Time) ([]*model.AnalyticsEvent, error)
+}
+
+// ExportBookmarkRepository records how far each analytics stream has been exported.
+type ExportBookmarkRepository interface {
+	// Get returns the end of the last exported window, or the zero time if the stream
+	// has never been exported.
+	Get(ctx context.Context, stream model.AnalyticsStream) (time.Time, error)
+
+	// Advance moves the bookmark to until. It never moves a bookmark backwards, so
+	// concurrent exporters cannot undo each other's progress.
+	Advance(ctx context.Context, stream model.AnalyticsStream, until time.Time) error
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// ArchiveRepository defines the interface for persisting the archive records of videos.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type ArchiveRepository interface {
+	// Save creates the archive record of a video, replacing a leftover one.
+	Save(ctx context.Context, archive *model.VideoArchive) error
+
+	// GetByVideoID retrieves the archive record of a video.
+	// Returns ErrArchiveNotFound if the video has none.
+	GetByVideoID(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error)
+
+	// RequestRestore records a restore request with its ETA and returns the updated record.
+	// A pending request is kept as it is, so repeated calls report the first ETA.
+	// Returns ErrArchiveNotFound if the video has no record.
+	RequestRestore(ctx context.Context, videoID uuid.UUID, requestedAt, eta time.Time) (*model.VideoArchive, error)
+
+	// ClaimNext leases the next record with objects to move until leaseUntil, so no other
+	// worker claims it meanwhile: requested restores first, then pending archives, oldest
+	// first. Records whose lease expired before now are claimed again.
+	// Returns ErrArchiveNotFound if nothing is pending.
+	ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*model.VideoArchive, error)
+
+	// MarkArchived records that the objects are in the archive tier and releases the lease.
+	MarkArchived(ctx context.Context, videoID uuid.UUID, bytes int64, at time.Time) error
+
+	// Delete removes the archive record unless it was replaced after createdAt, which
+	// happens when the video is archived again while the record is being retired.
+	Delete(ctx context.Context, videoID uuid.UUID, createdAt time.Time) error
+}
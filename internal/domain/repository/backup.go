@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"io"
+)
+
+// MetadataSnapshot describes a metadata dump.
+type MetadataSnapshot struct {
+	// SchemaVersion is the migration version the dump was taken at.
+	SchemaVersion int64
+	// Rows is the number of rows dumped per table.
+	Rows map[string]int64
+	// StorageKeys lists the objects the dumped rows reference, sorted.
+	StorageKeys []string
+}
+
+// MetadataBackupRepository dumps and restores the service's metadata tables.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type MetadataBackupRepository interface {
+	// Dump writes each table as JSON Lines (one row object per line) to the writer
+	// returned by create. All tables and storage keys are read from one snapshot.
+	Dump(ctx context.Context, create func(table string) (io.WriteCloser, error)) (*MetadataSnapshot, error)
+
+	// Restore loads each table from the reader returned by open in a single
+	// transaction and returns the rows loaded per table. Returns ErrRestoreTargetNotEmpty
+	// if any table already has rows.
+	Restore(ctx context.Context, open func(table string) (io.ReadCloser, error)) (map[string]int64, error)
+
+	// SchemaVersion returns the migration version of the database.
+	SchemaVersion(ctx context.Context) (int64, error)
+}
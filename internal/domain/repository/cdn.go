@@ -0,0 +1,11 @@
+package repository
+
+import "context"
+
+// CDNPurger defines the interface for evicting cached content from a CDN.
+// Implementations should be provided by the infrastructure layer (e.g., CloudFront, Fastly, bunny.net).
+type CDNPurger interface {
+	// Purge evicts every cached object under each path prefix (e.g., "hls/{video_id}/").
+	// Prefixes are relative to the CDN base URL.
+	Purge(ctx context.Context, prefixes []string) error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// CustomDomainRepository defines the interface for tenant custom domain persistence.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type CustomDomainRepository interface {
+	// Create persists a new custom domain.
+	// Returns ErrDuplicateCustomDomain if the tenant already registered the hostname.
+	Create(ctx context.Context, domain *model.CustomDomain) error
+
+	// GetByID retrieves a custom domain by its unique identifier.
+	// Returns nil and ErrCustomDomainNotFound if the domain does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.CustomDomain, error)
+
+	// ListByTenant retrieves all domains registered by a tenant, oldest first.
+	// Returns empty slice if the tenant has none.
+	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error)
+
+	// GetPlaybackDomain retrieves the domain a tenant's playback URLs should use:
+	// the earliest verified domain with a certificate attached.
+	// Returns nil and ErrCustomDomainNotFound if the tenant has no servable domain.
+	GetPlaybackDomain(ctx context.Context, tenantID uuid.UUID) (*model.CustomDomain, error)
+
+	// Update persists changes to an existing custom domain.
+	// Returns ErrCustomDomainNotFound if the domain does not exist, and
+	// ErrDuplicateCustomDomain if another tenant already verified the hostname.
+	Update(ctx context.Context, domain *model.CustomDomain) error
+
+	// Delete removes a custom domain.
+	// Returns ErrCustomDomainNotFound if the domain does not exist.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// TXTResolver looks up DNS TXT records. *net.Resolver satisfies this interface.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// EncryptionKeyRepository defines the interface for persisting the content keys of
+// encrypted HLS output.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type EncryptionKeyRepository interface {
+	// GetOrCreate stores key unless the video already has one, and returns the video's key.
+	// Concurrent calls for a video all return the same key.
+	GetOrCreate(ctx context.Context, key *model.EncryptionKey) (*model.EncryptionKey, error)
+
+	// GetByVideoID retrieves the key of a video.
+	// Returns ErrEncryptionKeyNotFound if the video has none.
+	GetByVideoID(ctx context.Context, videoID uuid.UUID) (*model.EncryptionKey, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// EntitlementChecker decides whether a user may stream a video.
+// Purchases and subscriptions are managed outside this service; implementations
+// adapt whichever system of record a deployment uses (local table, remote HTTP API).
+type EntitlementChecker interface {
+	// IsEntitled reports whether userID currently has access to the full stream of videoID.
+	// An error means the decision could not be made and access should be denied.
+	IsEntitled(ctx context.Context, userID, videoID uuid.UUID) (bool, error)
+}
@@ -1,17 +1,90 @@
 package repository
 
-import "errors"
+import "github.com/hszk-dev/gostream/internal/domain/errs"
 
+// Every error below carries an errs code, so callers that only need the class of a
+// failure can test errors.Is(err, errs.NotFound) instead of each sentinel.
 var (
 	// ErrVideoNotFound is returned when a video cannot be found.
-	ErrVideoNotFound = errors.New("video not found")
+	ErrVideoNotFound = errs.New(errs.NotFound, "video not found")
 
 	// ErrDuplicateVideo is returned when attempting to create a video that already exists.
-	ErrDuplicateVideo = errors.New("video already exists")
+	ErrDuplicateVideo = errs.New(errs.Conflict, "video already exists")
+
+	// ErrDuplicateShareSlug is returned when a video's share slug is already taken.
+	// Slugs are random, so callers should retry with a fresh one.
+	ErrDuplicateShareSlug = errs.New(errs.Conflict, "share slug already exists")
+
+	// ErrDuplicateTitleSlug is returned when the user already has a video with the title slug.
+	ErrDuplicateTitleSlug = errs.New(errs.Conflict, "title slug already exists")
+
+	// ErrDuplicateIdempotencyKey is returned when the user already created a video with the
+	// idempotency key.
+	ErrDuplicateIdempotencyKey = errs.New(errs.Conflict, "idempotency key already exists")
+
+	// ErrStaleOutputVersion is returned when publishing an output version that is not newer
+	// than the one a video already points at.
+	ErrStaleOutputVersion = errs.New(errs.Conflict, "newer output version already published")
 
 	// ErrObjectNotFound is returned when an object cannot be found in storage.
-	ErrObjectNotFound = errors.New("object not found")
+	ErrObjectNotFound = errs.New(errs.NotFound, "object not found")
+
+	// ErrObjectChanged is returned when a read pinned to an ETag finds the object replaced.
+	ErrObjectChanged = errs.New(errs.Conflict, "object changed since it was first read")
+
+	// ErrProgressNotFound is returned when no playback progress exists for a user and video.
+	ErrProgressNotFound = errs.New(errs.NotFound, "playback progress not found")
+
+	// ErrCustomDomainNotFound is returned when a custom domain cannot be found.
+	ErrCustomDomainNotFound = errs.New(errs.NotFound, "custom domain not found")
+
+	// ErrDuplicateCustomDomain is returned when a hostname is already registered by the
+	// same tenant or verified by another tenant.
+	ErrDuplicateCustomDomain = errs.New(errs.Conflict, "custom domain already exists")
+
+	// ErrUserNotFound is returned when a user cannot be found.
+	ErrUserNotFound = errs.New(errs.NotFound, "user not found")
+
+	// ErrDuplicateEmail is returned when another user already has the email.
+	ErrDuplicateEmail = errs.New(errs.Conflict, "email already exists")
+
+	// ErrAPIKeyNotFound is returned when an API key cannot be found, or belongs to
+	// another user.
+	ErrAPIKeyNotFound = errs.New(errs.NotFound, "API key not found")
+
+	// ErrPlaylistNotFound is returned when a playlist cannot be found.
+	ErrPlaylistNotFound = errs.New(errs.NotFound, "playlist not found")
+
+	// ErrArchiveNotFound is returned when a video has no archive record.
+	ErrArchiveNotFound = errs.New(errs.NotFound, "archive not found")
+
+	// ErrEncryptionKeyNotFound is returned when a video has no content key.
+	ErrEncryptionKeyNotFound = errs.New(errs.NotFound, "encryption key not found")
+
+	// ErrOutputBucketNotFound is returned when a tenant has no output bucket.
+	ErrOutputBucketNotFound = errs.New(errs.NotFound, "output bucket not found")
+
+	// ErrSecretNotFound is returned when a secret reference cannot be resolved.
+	ErrSecretNotFound = errs.New(errs.NotFound, "secret not found")
+
+	// ErrMultipartUploadNotFound is returned when a multipart upload does not exist for the
+	// key, e.g. because it was already completed or aborted.
+	ErrMultipartUploadNotFound = errs.New(errs.NotFound, "multipart upload not found")
+
+	// ErrInvalidMultipartParts is returned when completing a multipart upload with parts
+	// that were not uploaded, carry the wrong ETag, or are too small.
+	ErrInvalidMultipartParts = errs.New(errs.Invalid, "invalid multipart upload parts")
 
 	// ErrBucketNotFound is returned when the specified bucket does not exist.
-	ErrBucketNotFound = errors.New("bucket not found")
+	ErrBucketNotFound = errs.New(errs.NotFound, "bucket not found")
+
+	// ErrRestoreTargetNotEmpty is returned when a metadata restore would overwrite existing rows.
+	ErrRestoreTargetNotEmpty = errs.New(errs.Conflict, "restore target database is not empty")
+
+	// ErrSchemaDirty is returned when the last migration failed partway and the schema
+	// version recorded by golang-migrate cannot be trusted.
+	ErrSchemaDirty = errs.New(errs.Conflict, "schema is dirty (failed migration)")
+
+	// ErrQueueNotFound is returned when the broker has no queue with the given name.
+	ErrQueueNotFound = errs.New(errs.NotFound, "queue not found")
 )
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// URLUsage summarises the URLs issued to a requester within a window.
+type URLUsage struct {
+	Count int
+	// Oldest is when the earliest URL in the window was issued; zero if Count is 0.
+	Oldest time.Time
+}
+
+// IssuedURLRepository defines the interface for the presigned URL audit log.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type IssuedURLRepository interface {
+	// Create records an issued URL.
+	Create(ctx context.Context, issued *model.IssuedURL) error
+
+	// UsageSince summarises the URLs issued to requesterID for purpose after since.
+	UsageSince(ctx context.Context, requesterID uuid.UUID, purpose model.URLPurpose, since time.Time) (URLUsage, error)
+
+	// DeleteExpiredBefore removes records of URLs that expired before the cutoff and
+	// returns the number removed.
+	DeleteExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// OutboxMessage is a transcode task awaiting publication to the queue.
+type OutboxMessage struct {
+	ID   uuid.UUID
+	Task TranscodeTask
+	// Attempts is the number of failed publications so far.
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// OutboxRepository defines the interface for the transactional outbox of transcode tasks.
+// A task is written in the same transaction as the video update that requires it, so a
+// committed update always has its task published eventually, at least once.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type OutboxRepository interface {
+	// UpdateVideoWithTask persists changes to video, as VideoRepository.Update does, and
+	// adds msg to the outbox in the same transaction. msg cannot be claimed before
+	// availableAt, leaving the caller time to publish it after the commit.
+	// Returns ErrVideoNotFound if the video doesn't exist; nothing is written then.
+	UpdateVideoWithTask(ctx context.Context, video *model.Video, msg *OutboxMessage, availableAt time.Time) error
+
+	// Claim returns up to limit messages available at now, oldest first, and makes them
+	// unavailable to other claims until until.
+	Claim(ctx context.Context, now, until time.Time, limit int) ([]*OutboxMessage, error)
+
+	// Delete removes a published message. Deleting a missing message is not an error.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Release records a failed publication of a message, which becomes available again
+	// at retryAt.
+	Release(ctx context.Context, id uuid.UUID, retryAt time.Time, lastErr string) error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// OutputBucketRepository defines the interface for persisting the buckets tenants bring
+// for their transcode output. A tenant has at most one.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type OutputBucketRepository interface {
+	// Save creates the tenant's output bucket or replaces the existing one.
+	Save(ctx context.Context, bucket *model.OutputBucket) error
+
+	// GetByTenant retrieves a tenant's output bucket.
+	// Returns nil and ErrOutputBucketNotFound if the tenant has none.
+	GetByTenant(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error)
+
+	// Delete removes a tenant's output bucket.
+	// Returns ErrOutputBucketNotFound if the tenant has none.
+	Delete(ctx context.Context, tenantID uuid.UUID) error
+}
+
+// SecretStore keeps credentials out of the rows that use them: callers persist the
+// returned reference instead of the value. Implementations may seal the value into the
+// reference itself or keep it in an external secrets manager.
+type SecretStore interface {
+	// Put stores value under name and returns the reference to persist.
+	Put(ctx context.Context, name string, value []byte) (string, error)
+
+	// Get returns the value a reference points at.
+	// Returns ErrSecretNotFound if the reference is unknown.
+	Get(ctx context.Context, ref string) ([]byte, error)
+
+	// Delete removes the value a reference points at. Deleting a missing secret succeeds.
+	Delete(ctx context.Context, ref string) error
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// PlaylistRepository defines the interface for playlist persistence.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type PlaylistRepository interface {
+	// Create persists a new playlist with its videos.
+	Create(ctx context.Context, playlist *model.Playlist) error
+
+	// GetByID retrieves a playlist with its video IDs in order.
+	// Returns nil and ErrPlaylistNotFound if the playlist does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Playlist, error)
+
+	// ListByUser retrieves every playlist of a user with its video IDs, newest first.
+	// Returns empty slice if the user has none.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Playlist, error)
+
+	// Update persists changes to an existing playlist, replacing its videos atomically.
+	// Returns ErrPlaylistNotFound if the playlist does not exist.
+	Update(ctx context.Context, playlist *model.Playlist) error
+
+	// Delete removes a playlist and its list of videos; the videos themselves are kept.
+	// Returns ErrPlaylistNotFound if the playlist does not exist.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// HistoryCursor marks the last entry of a watch history page.
+// The next page starts strictly after it in (UpdatedAt DESC, VideoID DESC) order.
+type HistoryCursor struct {
+	UpdatedAt time.Time
+	VideoID   uuid.UUID
+}
+
+// ProgressRepository defines the interface for durable playback progress persistence.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type ProgressRepository interface {
+	// UpsertBatch persists a batch of progress records.
+	// Records older than the stored value or than a removal are ignored, so out-of-order
+	// flushes cannot rewind progress or restore a removed entry.
+	UpsertBatch(ctx context.Context, progress []*model.PlaybackProgress) error
+
+	// Get retrieves the progress of a user on a video.
+	// Returns nil and ErrProgressNotFound if no progress has been recorded.
+	Get(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error)
+
+	// ListByUser returns up to limit watch history entries, most recently watched first.
+	// A nil cursor starts from the newest entry.
+	ListByUser(ctx context.Context, userID uuid.UUID, cursor *HistoryCursor, limit int) ([]*model.WatchHistoryEntry, error)
+
+	// Delete removes the progress of a user on a video.
+	// Returns ErrProgressNotFound if no progress has been recorded.
+	Delete(ctx context.Context, userID, videoID uuid.UUID) error
+}
@@ -2,16 +2,63 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 )
 
+// ErrPermanentTaskFailure is returned (wrapped) by a task handler when the task must not
+// be retried. The queue moves such tasks, like any error errs.Retryable rejects, to its
+// dead-letter queue instead of republishing.
+var ErrPermanentTaskFailure = errs.New(errs.Permanent, "task failed permanently")
+
+// ErrTaskDeferred is returned (wrapped) by a task handler that cannot start the task yet,
+// e.g. because its tenant has used up its concurrent encodes. The queue puts such tasks
+// back for later without counting a retry.
+var ErrTaskDeferred = errs.New(errs.Transient, "task deferred")
+
 // TranscodeTask represents a video transcoding job message.
 type TranscodeTask struct {
 	VideoID     uuid.UUID `json:"video_id"`
 	OriginalKey string    `json:"original_key"`
 	OutputKey   string    `json:"output_key"`
 	RetryCount  int       `json:"retry_count"`
+	// PreviewKey is the storage prefix for the preview rendition; empty when previews are disabled.
+	PreviewKey     string `json:"preview_key,omitempty"`
+	PreviewSeconds int    `json:"preview_seconds,omitempty"`
+	// OutputVersion is the version OutputKey and PreviewKey were allocated for.
+	OutputVersion int64 `json:"output_version,omitempty"`
+	// EnqueuedAt is when processing was requested; retries keep the original value
+	// so time-to-READY covers queueing and every attempt.
+	EnqueuedAt time.Time `json:"enqueued_at,omitzero"`
+	// ExpiresAt is when the task goes stale; a worker dequeuing it later fails the video
+	// instead of transcoding. Retries keep the original value, and zero never expires.
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+	// ABRProfile names the ABR ladder to encode, as configured on the worker; empty uses
+	// the worker's default profile.
+	ABRProfile string `json:"abr_profile,omitempty"`
+	// Variants names the variants of the ladder to encode; empty encodes every variant
+	// the source is tall enough for.
+	Variants []string `json:"variants,omitempty"`
+	// SubtitlesOnly rebuilds the subtitle tracks of the published HLS output at OutputKey
+	// instead of transcoding, after a subtitle upload to a READY video.
+	SubtitlesOnly bool `json:"subtitles_only,omitempty"`
+}
+
+// DeleteTask is a storage cleanup job for a video deleted by its owner, or for an output
+// version superseded by a regenerated one.
+type DeleteTask struct {
+	VideoID uuid.UUID `json:"video_id"`
+	UserID  uuid.UUID `json:"user_id"`
+	// OriginalKey is the uploaded original; empty if the video never had one.
+	OriginalKey string `json:"original_key,omitempty"`
+	// OutputPrefixes hold every output version of the video (HLS, DASH, thumbnails, previews).
+	OutputPrefixes []string `json:"output_prefixes"`
+	// ExternalOutput marks a video whose output is stored in its owner's output bucket;
+	// the prefixes are then deleted from that bucket as well.
+	ExternalOutput bool `json:"external_output,omitempty"`
+	RetryCount     int  `json:"retry_count"`
 }
 
 // MessageQueue defines the interface for message queue operations.
@@ -27,6 +74,40 @@ type MessageQueue interface {
 	// Used by the worker service.
 	ConsumeTranscodeTasks(ctx context.Context, handler func(task TranscodeTask) error) error
 
+	// PublishDeleteTask sends a storage cleanup task to the delete queue.
+	// Used by the API server when a video is deleted.
+	PublishDeleteTask(ctx context.Context, task DeleteTask) error
+
+	// ConsumeDeleteTasks starts consuming storage cleanup tasks from the delete queue.
+	// Used by the worker service.
+	ConsumeDeleteTasks(ctx context.Context, handler func(task DeleteTask) error) error
+
 	// Close gracefully closes the connection to the message queue.
 	Close() error
 }
+
+// QueueInspector reports the state of the transcode queue.
+type QueueInspector interface {
+	// Depth returns the number of transcode tasks waiting to be consumed.
+	Depth(ctx context.Context) (int, error)
+}
+
+// QueueStats is a point-in-time view of one broker queue.
+type QueueStats struct {
+	Name string
+	// Ready messages wait for a consumer; Unacked ones are delivered but not yet acknowledged.
+	Ready     int
+	Unacked   int
+	Consumers int
+	// MessageAges are the ages of the messages sampled from the head of the queue, oldest
+	// first; the head holds the oldest messages, so a sample smaller than the queue is
+	// biased towards them. Messages published without a timestamp are not included.
+	MessageAges []time.Duration
+}
+
+// QueueMonitor reads queue statistics from the broker's management interface.
+type QueueMonitor interface {
+	// QueueStats returns the statistics of the named queue.
+	// Returns ErrQueueNotFound if the broker has no such queue.
+	QueueStats(ctx context.Context, name string) (*QueueStats, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "context"
+
+// staleReadsKey is the context key for the stale-read flag.
+type staleReadsKey struct{}
+
+// WithStaleReads returns a context that marks reads as tolerant of a read replica's lag,
+// or not. Repositories with a replica serve tolerant reads from it; unmarked reads, such
+// as those that precede a write, go to the primary. Passing false forces the primary for
+// a read that must see a write just made, even through a call that tolerates lag by default.
+func WithStaleReads(ctx context.Context, allowed bool) context.Context {
+	return context.WithValue(ctx, staleReadsKey{}, allowed)
+}
+
+// StaleReads reports whether ctx marks reads as tolerant of replica lag, and whether it
+// marks them at all.
+func StaleReads(ctx context.Context) (allowed, set bool) {
+	allowed, set = ctx.Value(staleReadsKey{}).(bool)
+	return allowed, set
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// RenditionRepository defines the interface for persisting the renditions of each output version.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type RenditionRepository interface {
+	// SaveAll persists renditions atomically. A rendition with the same video, output
+	// version and name replaces the existing one, so retried transcodes do not duplicate rows.
+	SaveAll(ctx context.Context, renditions []*model.Rendition) error
+
+	// ListByVideoID returns the renditions of one output version of a video, highest quality first.
+	// Returns empty slice if none were recorded.
+	// Pruned renditions are included.
+	ListByVideoID(ctx context.Context, videoID uuid.UUID, outputVersion int64) ([]*model.Rendition, error)
+
+	// MarkViewed records that a viewer fetched the named rendition's playlist at the given time.
+	// The write may be skipped while the recorded time is recent, so popular videos do not
+	// contend on one row. Unknown renditions are ignored.
+	MarkViewed(ctx context.Context, videoID uuid.UUID, outputVersion int64, name string, at time.Time) error
+
+	// ListPruneCandidates returns the IDs of READY videos without DASH output whose published
+	// output was created before cutoff and still serves a rendition above the highest one
+	// viewed since cutoff (or above its lowest one, if none was). Videos with no recorded
+	// view at all are skipped, as nothing is known about them. Oldest output first.
+	ListPruneCandidates(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error)
+
+	// MarkPruned records that the named renditions were removed from the output at the given time.
+	MarkPruned(ctx context.Context, videoID uuid.UUID, outputVersion int64, names []string, at time.Time) error
+}
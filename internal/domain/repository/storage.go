@@ -26,11 +26,48 @@ type ObjectStorage interface {
 	// Caller is responsible for closing the returned ReadCloser.
 	Download(ctx context.Context, key string) (io.ReadCloser, error)
 
+	// DownloadRange retrieves length bytes of an object starting at offset.
+	// Used for parallel ranged downloads of large originals. A non-empty etag pins the
+	// read to that version of the object; ErrObjectChanged is returned if it was replaced.
+	// Caller is responsible for closing the returned ReadCloser.
+	DownloadRange(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error)
+
+	// Stat returns metadata about a stored object.
+	// Returns ErrObjectNotFound if the object does not exist.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+
 	// Delete removes an object from the storage.
 	Delete(ctx context.Context, key string) error
 
 	// Exists checks if an object exists in the storage.
 	Exists(ctx context.Context, key string) (bool, error)
+
+	// ListObjects returns every object whose key starts with prefix, in key order.
+	// Returns an empty slice if nothing matches.
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+
+	// DeleteByPrefix removes every object whose key starts with prefix and returns how many
+	// were removed. Deleting an empty prefix succeeds with a count of zero.
+	DeleteByPrefix(ctx context.Context, prefix string) (int, error)
+
+	// InitiateMultipartUpload starts a multipart upload to key and returns its upload ID.
+	// Used for originals too large for a single presigned PUT.
+	InitiateMultipartUpload(ctx context.Context, key, contentType string) (string, error)
+
+	// PresignPart creates a presigned URL for uploading part partNumber (1-10000) of a
+	// multipart upload. The ETag of the part's response is needed to complete the upload.
+	PresignPart(ctx context.Context, key, uploadID string, partNumber int, expiry time.Duration) (string, error)
+
+	// CompleteMultipartUpload assembles the parts, given in ascending part number order,
+	// into the object. Returns ErrMultipartUploadNotFound if the upload does not exist and
+	// ErrInvalidMultipartParts if the parts do not match what was uploaded.
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error
+}
+
+// CompletedPart identifies an uploaded part of a multipart upload.
+type CompletedPart struct {
+	PartNumber int
+	ETag       string
 }
 
 // ObjectInfo contains metadata about a stored object.
@@ -39,4 +76,7 @@ type ObjectInfo struct {
 	Size         int64
 	ContentType  string
 	LastModified time.Time
+	// ETag is the entity tag reported by the storage backend.
+	// For single-part uploads on S3-compatible stores this is the hex MD5 of the content.
+	ETag string
 }
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// SubtitleRepository defines the interface for persisting the subtitle tracks of videos.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type SubtitleRepository interface {
+	// Save persists a subtitle track. A track with the same video and language replaces
+	// the existing one, keeping its ID and creation time. Saving a default track clears
+	// the default flag of the video's other tracks.
+	Save(ctx context.Context, subtitle *model.Subtitle) error
+
+	// ListByVideoID returns the subtitle tracks of a video ordered by language.
+	// Returns empty slice if the video has none.
+	ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]*model.Subtitle, error)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupersededOutput is an output version replaced by a regenerated one. Its objects are
+// kept until DeleteAfter, so CDN caches and players still holding its URLs can drain.
+type SupersededOutput struct {
+	VideoID       uuid.UUID
+	OutputVersion int64
+	UserID        uuid.UUID
+	// Prefixes are the storage prefixes of the version (HLS/DASH and preview).
+	Prefixes []string
+	// ExternalOutput marks a version stored in its owner's output bucket.
+	ExternalOutput bool
+	DeleteAfter    time.Time
+	CreatedAt      time.Time
+}
+
+// SupersededOutputRepository defines the interface for scheduling the deletion of
+// superseded output versions.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type SupersededOutputRepository interface {
+	// Schedule records a superseded version. Scheduling a version twice keeps the first record.
+	Schedule(ctx context.Context, output *SupersededOutput) error
+
+	// Claim returns up to limit versions due for deletion at now, oldest first, and makes
+	// them unavailable to other claims until until.
+	Claim(ctx context.Context, now, until time.Time, limit int) ([]*SupersededOutput, error)
+
+	// Delete removes the record of a version whose deletion was handed off. Deleting a
+	// missing record is not an error.
+	Delete(ctx context.Context, videoID uuid.UUID, outputVersion int64) error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// TranscodeJobStats aggregates final transcode outcomes over a time window.
+type TranscodeJobStats struct {
+	Succeeded int64
+	Failed    int64
+	// TimeToReadyP95 is the 95th percentile time from processing request to READY.
+	// Zero when TimeToReadySamples is zero.
+	TimeToReadyP95     time.Duration
+	TimeToReadySamples int64
+}
+
+// TranscodeCostStats aggregates succeeded jobs whose source had the same height.
+type TranscodeCostStats struct {
+	SourceHeight int
+	Jobs         int64
+	// SourceDuration, ProcessingTime and OutputBytes are totals over the jobs.
+	SourceDuration time.Duration
+	ProcessingTime time.Duration
+	OutputBytes    int64
+}
+
+// TranscodeJobRepository defines the interface for persisting transcode attempt records.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type TranscodeJobRepository interface {
+	// Create persists a finished transcode job.
+	Create(ctx context.Context, job *model.TranscodeJob) error
+
+	// ListByVideoID returns up to limit jobs for a video, most recent first.
+	ListByVideoID(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error)
+
+	// Stats aggregates final jobs that finished at or after since.
+	Stats(ctx context.Context, since time.Time) (*TranscodeJobStats, error)
+
+	// CostStats aggregates final, succeeded jobs with a probed source that finished at or
+	// after since, grouped by source height in ascending order.
+	CostStats(ctx context.Context, since time.Time) ([]TranscodeCostStats, error)
+}
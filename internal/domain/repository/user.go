@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// UserRepository defines the interface for user persistence.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type UserRepository interface {
+	// Create persists a new user.
+	// Returns ErrDuplicateEmail if another user already has the email.
+	Create(ctx context.Context, user *model.User) error
+
+	// GetByID retrieves a user by its unique identifier.
+	// Returns nil and ErrUserNotFound if the user does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.User, error)
+}
+
+// APIKeyRepository defines the interface for API key persistence.
+type APIKeyRepository interface {
+	// Create persists a new API key.
+	Create(ctx context.Context, key *model.APIKey) error
+
+	// GetByHash retrieves the key with the given hash, revoked or not.
+	// Returns nil and ErrAPIKeyNotFound if no key has the hash.
+	GetByHash(ctx context.Context, hash string) (*model.APIKey, error)
+
+	// ListByUser retrieves every key of a user, revoked ones included, oldest first.
+	// Returns empty slice if the user has none.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error)
+
+	// Revoke marks a key of the user revoked at the given time and returns it.
+	// Returns nil and ErrAPIKeyNotFound if the user has no such key. Revoking a
+	// revoked key keeps the first revocation time.
+	Revoke(ctx context.Context, userID, keyID uuid.UUID, at time.Time) (*model.APIKey, error)
+
+	// TouchLastUsed records that the key authenticated a request at the given time.
+	// Unknown keys are ignored.
+	TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time) error
+}
@@ -2,25 +2,115 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 )
 
+// VideoCursor marks the last video of a listing page.
+// The next page starts strictly after it in the listing's (CreatedAt, ID) order.
+type VideoCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// VideoFilter narrows the videos returned by List. Zero-valued fields are ignored.
+type VideoFilter struct {
+	UserID uuid.UUID
+	// CreatedAfter and CreatedBefore are exclusive bounds on created_at.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// TitlePrefix matches the start of the title, case-insensitively.
+	TitlePrefix string
+	// Status matches videos in exactly this status.
+	Status model.Status
+	// Statuses matches videos in any of these statuses.
+	Statuses []model.Status
+	// Visibility matches videos with exactly this visibility.
+	Visibility model.Visibility
+	// UpdatedBefore is an exclusive bound on updated_at.
+	UpdatedBefore time.Time
+	// ExpiresBefore is an exclusive bound on expires_at; videos without an expiry never match.
+	ExpiresBefore time.Time
+	// After continues a previous listing; nil starts from the newest video, or the
+	// oldest when Ascending.
+	After *VideoCursor
+	// Ascending lists oldest first instead of newest first.
+	Ascending bool
+	// ExcludeDeleted leaves out videos in the DELETED status.
+	ExcludeDeleted bool
+	// ExcludeExpired leaves out videos in the EXPIRED status.
+	ExcludeExpired bool
+	Limit          int
+}
+
+// VideoSearchCursor marks the last result of a search page. The next page starts
+// strictly after it in (Rank DESC, CreatedAt DESC, ID DESC) order.
+type VideoSearchCursor struct {
+	Rank      float32
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// VideoSearch is a full-text search over video titles and descriptions. DELETED and
+// EXPIRED videos are never returned.
+type VideoSearch struct {
+	// Query uses web search syntax: words, "quoted phrases", OR and -excluded words.
+	Query string
+	// UserID restricts the search to one user's videos.
+	UserID uuid.UUID
+	// Visibility restricts the search to videos with exactly this visibility.
+	Visibility model.Visibility
+	// After continues a previous search; nil starts from the best match.
+	After *VideoSearchCursor
+	Limit int
+}
+
+// VideoSearchResult is a video matching a search with its relevance; higher ranks
+// match better.
+type VideoSearchResult struct {
+	Video *model.Video
+	Rank  float32
+}
+
 // VideoRepository defines the interface for video persistence operations.
 // Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
 type VideoRepository interface {
 	// Create persists a new video entity.
-	// Returns error if the video already exists or persistence fails.
+	// Returns ErrDuplicateShareSlug if the share slug is taken, ErrDuplicateTitleSlug if
+	// the user already has the title slug, ErrDuplicateIdempotencyKey if the user already
+	// created a video with the idempotency key, or error if the video already exists or
+	// persistence fails.
 	Create(ctx context.Context, video *model.Video) error
 
 	// GetByID retrieves a video by its unique identifier.
 	// Returns nil and ErrVideoNotFound if the video does not exist.
+	// A context marked with WithStaleReads may be served by a lagging read replica.
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Video, error)
 
-	// GetByUserID retrieves all videos belonging to a user.
-	// Returns empty slice if no videos exist for the user.
-	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Video, error)
+	// GetByShareSlug retrieves a video by its share slug.
+	// Returns nil and ErrVideoNotFound if no video has the slug.
+	GetByShareSlug(ctx context.Context, slug string) (*model.Video, error)
+
+	// GetByTitleSlug retrieves a user's video by its title slug.
+	// Returns nil and ErrVideoNotFound if the user has no video with the slug.
+	GetByTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error)
+
+	// GetByIdempotencyKey retrieves the video a user created with the idempotency key,
+	// including its IdempotencyKey and IdempotencyHash.
+	// Returns nil and ErrVideoNotFound if the user has no video with the key.
+	GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*model.Video, error)
+
+	// List retrieves up to filter.Limit videos matching filter, newest first unless
+	// filter.Ascending. Returns empty slice if no videos match.
+	// A context marked with WithStaleReads may be served by a lagging read replica.
+	List(ctx context.Context, filter VideoFilter) ([]*model.Video, error)
+
+	// Search retrieves up to search.Limit videos whose titles or descriptions match
+	// search.Query, best match first and newest first among equal ranks.
+	// Returns empty slice if no videos match.
+	Search(ctx context.Context, search VideoSearch) ([]VideoSearchResult, error)
 
 	// Update persists changes to an existing video entity.
 	// Returns ErrVideoNotFound if the video does not exist.
@@ -30,4 +120,22 @@ type VideoRepository interface {
 	// This is optimized for status transitions without full entity update.
 	// Returns ErrVideoNotFound if the video does not exist.
 	UpdateStatus(ctx context.Context, id uuid.UUID, status model.Status) error
+
+	// UpdateSource records the probed metadata of a video's original.
+	// Returns ErrVideoNotFound if the video does not exist.
+	UpdateSource(ctx context.Context, id uuid.UUID, source model.SourceMetadata) error
+
+	// PublishOutput atomically repoints a video at a regenerated output version.
+	// The pointer only moves forward: returns ErrStaleOutputVersion if the video already
+	// references version or a newer one, and ErrVideoNotFound if the video does not exist.
+	// externalOutput records whether the version was written to the owner's output bucket.
+	PublishOutput(ctx context.Context, id uuid.UUID, version int64, hlsURL, dashURL, previewURL, thumbnailPrefix string, externalOutput bool) error
+
+	// SetOutputSize records the size in bytes of the video's published output.
+	// Returns ErrVideoNotFound if the video does not exist.
+	SetOutputSize(ctx context.Context, id uuid.UUID, size int64) error
+
+	// GetStorageUsage sums the original and output sizes of the user's videos that are
+	// not DELETED. QuotaBytes is left zero. A user without videos has zero usage.
+	GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error)
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// VideoStatsRepository defines the interface for durable view counts.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type VideoStatsRepository interface {
+	// AddBatch adds buffered views to the stored counts.
+	// Views of videos that no longer exist are dropped.
+	AddBatch(ctx context.Context, deltas []*model.VideoStats) error
+
+	// Get retrieves the stored counts of a video.
+	// Returns zero stats if none of its views have been flushed yet.
+	Get(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error)
+}
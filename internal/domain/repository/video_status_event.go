@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// VideoStatusEventRepository defines the interface for the video status audit log.
+// Implementations should be provided by the infrastructure layer (e.g., PostgreSQL).
+type VideoStatusEventRepository interface {
+	// Create records a status transition.
+	Create(ctx context.Context, event *model.VideoStatusEvent) error
+
+	// ListByVideoID returns up to limit transitions of a video, most recent first.
+	ListByVideoID(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error)
+}
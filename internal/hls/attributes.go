@@ -0,0 +1,160 @@
+package hls
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Attribute is one NAME=VALUE pair of an attribute list (RFC 8216 section 4.2).
+type Attribute struct {
+	Name  string
+	Value string
+	// Quoted is set for quoted-string values, which are written between double quotes.
+	Quoted bool
+}
+
+// AttributeList is the value of tags such as EXT-X-STREAM-INF, EXT-X-MEDIA and
+// EXT-X-KEY. Attributes keep their order, so an unmodified list encodes as it was read.
+type AttributeList []Attribute
+
+// ParseAttributeList parses an attribute list. Quoted strings may contain commas and
+// equals signs but, per the specification, no double quotes or line breaks.
+func ParseAttributeList(s string) (AttributeList, error) {
+	var attrs AttributeList
+	for len(s) > 0 {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("attribute without name or value in %q", s)
+		}
+		attr := Attribute{Name: s[:eq]}
+		s = s[eq+1:]
+
+		if strings.HasPrefix(s, `"`) {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted string in attribute %s", attr.Name)
+			}
+			attr.Value, attr.Quoted = s[1:end+1], true
+			s = s[end+2:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			attr.Value = s[:end]
+			s = s[end:]
+		}
+		attrs = append(attrs, attr)
+
+		if len(s) > 0 {
+			if s[0] != ',' {
+				return nil, fmt.Errorf("expected comma after attribute %s", attr.Name)
+			}
+			s = s[1:]
+		}
+	}
+	return attrs, nil
+}
+
+// Get returns the value of the named attribute.
+func (l AttributeList) Get(name string) (string, bool) {
+	for _, attr := range l {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// Int returns the named attribute as a decimal integer; zero if it is missing.
+func (l AttributeList) Int(name string) (int64, error) {
+	value, ok := l.Get(name)
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("attribute %s: %w", name, err)
+	}
+	return n, nil
+}
+
+// Set sets the named attribute to an unquoted value (a number, resolution or
+// enumerated string), replacing it in place or appending it.
+func (l *AttributeList) Set(name, value string) {
+	l.set(Attribute{Name: name, Value: value})
+}
+
+// SetQuoted sets the named attribute to a quoted-string value.
+func (l *AttributeList) SetQuoted(name, value string) {
+	l.set(Attribute{Name: name, Value: value, Quoted: true})
+}
+
+// Delete removes the named attribute if present.
+func (l *AttributeList) Delete(name string) {
+	*l = slices.DeleteFunc(*l, func(attr Attribute) bool { return attr.Name == name })
+}
+
+func (l *AttributeList) set(attr Attribute) {
+	for i := range *l {
+		if (*l)[i].Name == attr.Name {
+			(*l)[i] = attr
+			return
+		}
+	}
+	*l = append(*l, attr)
+}
+
+// String encodes the list as it appears after the tag's colon.
+func (l AttributeList) String() string {
+	var sb strings.Builder
+	for i, attr := range l {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(attr.Name)
+		sb.WriteByte('=')
+		if attr.Quoted {
+			sb.WriteString(`"` + attr.Value + `"`)
+		} else {
+			sb.WriteString(attr.Value)
+		}
+	}
+	return sb.String()
+}
+
+// ByteRange is a sub-range of a resource, as in EXT-X-BYTERANGE and the BYTERANGE
+// attribute of EXT-X-MAP.
+type ByteRange struct {
+	Length int64
+	// Offset is where the range starts. Without HasOffset the range starts right
+	// after the previous sub-range of the same resource.
+	Offset    int64
+	HasOffset bool
+}
+
+// ParseByteRange parses "<length>[@<offset>]".
+func ParseByteRange(s string) (ByteRange, error) {
+	lengthText, offsetText, hasOffset := strings.Cut(s, "@")
+	length, err := strconv.ParseInt(lengthText, 10, 64)
+	if err != nil || length < 0 {
+		return ByteRange{}, fmt.Errorf("invalid byte range %q", s)
+	}
+	br := ByteRange{Length: length, HasOffset: hasOffset}
+	if hasOffset {
+		br.Offset, err = strconv.ParseInt(offsetText, 10, 64)
+		if err != nil || br.Offset < 0 {
+			return ByteRange{}, fmt.Errorf("invalid byte range %q", s)
+		}
+	}
+	return br, nil
+}
+
+func (b ByteRange) String() string {
+	if !b.HasOffset {
+		return strconv.FormatInt(b.Length, 10)
+	}
+	return strconv.FormatInt(b.Length, 10) + "@" + strconv.FormatInt(b.Offset, 10)
+}
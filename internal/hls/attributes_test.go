@@ -0,0 +1,154 @@
+package hls
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAttributeList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    AttributeList
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "unquoted values",
+			input: "BANDWIDTH=2800000,RESOLUTION=1280x720",
+			want: AttributeList{
+				{Name: "BANDWIDTH", Value: "2800000"},
+				{Name: "RESOLUTION", Value: "1280x720"},
+			},
+		},
+		{
+			name:  "quoted value with comma and equals sign",
+			input: `BANDWIDTH=1,CODECS="avc1.4d401f,mp4a.40.2",URI="key?a=b"`,
+			want: AttributeList{
+				{Name: "BANDWIDTH", Value: "1"},
+				{Name: "CODECS", Value: "avc1.4d401f,mp4a.40.2", Quoted: true},
+				{Name: "URI", Value: "key?a=b", Quoted: true},
+			},
+		},
+		{
+			name:  "empty quoted value",
+			input: `NAME=""`,
+			want:  AttributeList{{Name: "NAME", Value: "", Quoted: true}},
+		},
+		{
+			name:    "missing name",
+			input:   "=1",
+			wantErr: true,
+		},
+		{
+			name:    "missing equals sign",
+			input:   "BANDWIDTH",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quoted string",
+			input:   `URI="key`,
+			wantErr: true,
+		},
+		{
+			name:    "text after quoted string",
+			input:   `URI="key"x,METHOD=AES-128`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAttributeList(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttributeList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAttributeList() = %#v, want %#v", got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %q, want %q", got.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestAttributeList_Set(t *testing.T) {
+	attrs := AttributeList{{Name: "BANDWIDTH", Value: "1"}, {Name: "URI", Value: "a", Quoted: true}}
+
+	attrs.Set("BANDWIDTH", "2")
+	attrs.SetQuoted("URI", "b")
+	attrs.Set("RESOLUTION", "640x360")
+
+	want := `BANDWIDTH=2,URI="b",RESOLUTION=640x360`
+	if attrs.String() != want {
+		t.Errorf("String() = %q, want %q", attrs.String(), want)
+	}
+}
+
+func TestAttributeList_Delete(t *testing.T) {
+	attrs := AttributeList{{Name: "BANDWIDTH", Value: "1"}, {Name: "SUBTITLES", Value: "subs", Quoted: true}}
+
+	attrs.Delete("SUBTITLES")
+	attrs.Delete("AUDIO")
+
+	if want := "BANDWIDTH=1"; attrs.String() != want {
+		t.Errorf("String() = %q, want %q", attrs.String(), want)
+	}
+}
+
+func TestAttributeList_Int(t *testing.T) {
+	attrs := AttributeList{{Name: "BANDWIDTH", Value: "2800000"}, {Name: "BAD", Value: "x"}}
+
+	if n, err := attrs.Int("BANDWIDTH"); err != nil || n != 2800000 {
+		t.Errorf("Int(BANDWIDTH) = %d, %v, want 2800000", n, err)
+	}
+	if n, err := attrs.Int("MISSING"); err != nil || n != 0 {
+		t.Errorf("Int(MISSING) = %d, %v, want 0", n, err)
+	}
+	if _, err := attrs.Int("BAD"); err == nil {
+		t.Error("Int(BAD) expected error")
+	}
+}
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ByteRange
+		wantErr bool
+	}{
+		{input: "1024", want: ByteRange{Length: 1024}},
+		{input: "1024@2048", want: ByteRange{Length: 1024, Offset: 2048, HasOffset: true}},
+		{input: "0@0", want: ByteRange{HasOffset: true}},
+		{input: "", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "1024@", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "10@-5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteRange() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %q, want %q", got.String(), tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package hls
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Variant is a variant stream of a master playlist: an EXT-X-STREAM-INF tag and the
+// URI line after it.
+type Variant struct {
+	Bandwidth        int64
+	AverageBandwidth int64
+	Codecs           string
+	// Width and Height are the RESOLUTION attribute; zero when it is missing.
+	Width  int
+	Height int
+	// FrameRate is zero when the attribute is missing.
+	FrameRate float64
+	// Audio, Video, Subtitles and ClosedCaptions name EXT-X-MEDIA groups.
+	Audio          string
+	Video          string
+	Subtitles      string
+	ClosedCaptions string
+	URI            string
+}
+
+// Tag returns the EXT-X-STREAM-INF tag of the variant.
+func (v Variant) Tag() Tag {
+	attrs := AttributeList{}
+	attrs.Set("BANDWIDTH", strconv.FormatInt(v.Bandwidth, 10))
+	if v.AverageBandwidth > 0 {
+		attrs.Set("AVERAGE-BANDWIDTH", strconv.FormatInt(v.AverageBandwidth, 10))
+	}
+	if v.Codecs != "" {
+		attrs.SetQuoted("CODECS", v.Codecs)
+	}
+	if v.Width > 0 && v.Height > 0 {
+		attrs.Set("RESOLUTION", fmt.Sprintf("%dx%d", v.Width, v.Height))
+	}
+	if v.FrameRate > 0 {
+		attrs.Set("FRAME-RATE", strconv.FormatFloat(v.FrameRate, 'f', 3, 64))
+	}
+	for _, group := range []struct{ name, value string }{
+		{"AUDIO", v.Audio},
+		{"VIDEO", v.Video},
+		{"SUBTITLES", v.Subtitles},
+		{"CLOSED-CAPTIONS", v.ClosedCaptions},
+	} {
+		if group.value != "" {
+			attrs.SetQuoted(group.name, group.value)
+		}
+	}
+	return Tag{Name: TagStreamInf, Attrs: attrs}
+}
+
+func parseVariant(attrs AttributeList, uri string) (Variant, error) {
+	v := Variant{URI: uri}
+	v.Codecs, _ = attrs.Get("CODECS")
+	v.Audio, _ = attrs.Get("AUDIO")
+	v.Video, _ = attrs.Get("VIDEO")
+	v.Subtitles, _ = attrs.Get("SUBTITLES")
+	v.ClosedCaptions, _ = attrs.Get("CLOSED-CAPTIONS")
+
+	var err error
+	if v.Bandwidth, err = attrs.Int("BANDWIDTH"); err != nil {
+		return Variant{}, err
+	}
+	if v.AverageBandwidth, err = attrs.Int("AVERAGE-BANDWIDTH"); err != nil {
+		return Variant{}, err
+	}
+	if resolution, ok := attrs.Get("RESOLUTION"); ok {
+		if _, err := fmt.Sscanf(resolution, "%dx%d", &v.Width, &v.Height); err != nil {
+			return Variant{}, fmt.Errorf("attribute RESOLUTION: invalid value %q", resolution)
+		}
+	}
+	if frameRate, ok := attrs.Get("FRAME-RATE"); ok {
+		if v.FrameRate, err = strconv.ParseFloat(frameRate, 64); err != nil {
+			return Variant{}, fmt.Errorf("attribute FRAME-RATE: %w", err)
+		}
+	}
+	return v, nil
+}
+
+// Media is an EXT-X-MEDIA rendition: an alternative audio, video, subtitle or
+// closed-caption track of a master playlist.
+type Media struct {
+	// Type is AUDIO, VIDEO, SUBTITLES or CLOSED-CAPTIONS.
+	Type     string
+	GroupID  string
+	Name     string
+	Language string
+	// URI is empty for renditions carried in the variant stream itself.
+	URI        string
+	Default    bool
+	Autoselect bool
+	Channels   string
+}
+
+// Tag returns the EXT-X-MEDIA tag of the rendition.
+func (m Media) Tag() Tag {
+	attrs := AttributeList{}
+	attrs.Set("TYPE", m.Type)
+	attrs.SetQuoted("GROUP-ID", m.GroupID)
+	attrs.SetQuoted("NAME", m.Name)
+	if m.Language != "" {
+		attrs.SetQuoted("LANGUAGE", m.Language)
+	}
+	if m.Default {
+		attrs.Set("DEFAULT", "YES")
+	}
+	if m.Autoselect {
+		attrs.Set("AUTOSELECT", "YES")
+	}
+	if m.Channels != "" {
+		attrs.SetQuoted("CHANNELS", m.Channels)
+	}
+	if m.URI != "" {
+		attrs.SetQuoted("URI", m.URI)
+	}
+	return Tag{Name: TagMedia, Attrs: attrs}
+}
+
+func parseMedia(attrs AttributeList) Media {
+	var m Media
+	m.Type, _ = attrs.Get("TYPE")
+	m.GroupID, _ = attrs.Get("GROUP-ID")
+	m.Name, _ = attrs.Get("NAME")
+	m.Language, _ = attrs.Get("LANGUAGE")
+	m.URI, _ = attrs.Get("URI")
+	m.Channels, _ = attrs.Get("CHANNELS")
+	isDefault, _ := attrs.Get("DEFAULT")
+	autoselect, _ := attrs.Get("AUTOSELECT")
+	m.Default = isDefault == "YES"
+	m.Autoselect = autoselect == "YES"
+	return m
+}
+
+// Key is an EXT-X-KEY tag; it applies to every segment after it until the next one.
+type Key struct {
+	// Method is NONE, AES-128 or SAMPLE-AES.
+	Method string
+	URI    string
+	// IV is the hexadecimal initialization vector, "0x" prefix included; empty to use
+	// the media sequence number.
+	IV string
+}
+
+// Tag returns the EXT-X-KEY tag.
+func (k Key) Tag() Tag {
+	attrs := AttributeList{}
+	attrs.Set("METHOD", k.Method)
+	if k.URI != "" {
+		attrs.SetQuoted("URI", k.URI)
+	}
+	if k.IV != "" {
+		attrs.Set("IV", k.IV)
+	}
+	return Tag{Name: TagKey, Attrs: attrs}
+}
+
+// Map is an EXT-X-MAP tag: the initialization section of the segments after it.
+type Map struct {
+	URI       string
+	ByteRange *ByteRange
+}
+
+// Tag returns the EXT-X-MAP tag.
+func (m Map) Tag() Tag {
+	attrs := AttributeList{}
+	attrs.SetQuoted("URI", m.URI)
+	if m.ByteRange != nil {
+		attrs.SetQuoted("BYTERANGE", m.ByteRange.String())
+	}
+	return Tag{Name: TagMap, Attrs: attrs}
+}
+
+// Segment is a media segment of a media playlist.
+type Segment struct {
+	// Duration is the EXTINF duration in seconds.
+	Duration float64
+	Title    string
+	URI      string
+	// ByteRange is set when the segment is a sub-range of URI.
+	ByteRange *ByteRange
+	// Discontinuity is set when EXT-X-DISCONTINUITY precedes the segment.
+	Discontinuity bool
+	// Key and Map are the EXT-X-KEY and EXT-X-MAP in effect for the segment, if any.
+	// AddSegment does not write them; add their tags before the first segment they apply to.
+	Key *Key
+	Map *Map
+}
+
+// Variants returns the variant streams of a master playlist in order.
+func (p *Playlist) Variants() ([]Variant, error) {
+	var variants []Variant
+	for i := 0; i < len(p.Lines); i++ {
+		line := p.Lines[i]
+		if line.Kind != LineTag || line.Tag.Name != TagStreamInf {
+			continue
+		}
+		end, err := p.variantEnd(i)
+		if err != nil {
+			return nil, err
+		}
+		v, err := parseVariant(line.Tag.Attrs, p.Lines[end].Text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", TagStreamInf, err)
+		}
+		variants = append(variants, v)
+		i = end
+	}
+	return variants, nil
+}
+
+// variantEnd returns the index of the URI line of the EXT-X-STREAM-INF at i.
+func (p *Playlist) variantEnd(i int) (int, error) {
+	for j := i + 1; j < len(p.Lines); j++ {
+		switch line := p.Lines[j]; {
+		case line.Kind == LineURI:
+			return j, nil
+		case line.Kind == LineTag && line.Tag.Name == TagStreamInf:
+			return 0, fmt.Errorf("%s without URI", TagStreamInf)
+		}
+	}
+	return 0, fmt.Errorf("%s without URI", TagStreamInf)
+}
+
+// RemoveVariants removes the variant streams for which drop returns true, along with
+// the blank line separating each from the next entry.
+func (p *Playlist) RemoveVariants(drop func(v Variant) bool) error {
+	kept := make([]Line, 0, len(p.Lines))
+	for i := 0; i < len(p.Lines); i++ {
+		line := p.Lines[i]
+		if line.Kind != LineTag || line.Tag.Name != TagStreamInf {
+			kept = append(kept, line)
+			continue
+		}
+
+		end, err := p.variantEnd(i)
+		if err != nil {
+			return err
+		}
+		v, err := parseVariant(line.Tag.Attrs, p.Lines[end].Text)
+		if err != nil {
+			return fmt.Errorf("%s: %w", TagStreamInf, err)
+		}
+		if !drop(v) {
+			kept = append(kept, p.Lines[i:end+1]...)
+		} else if end+1 < len(p.Lines) && p.Lines[end+1].Kind == LineBlank {
+			end++
+		}
+		i = end
+	}
+	p.Lines = kept
+	return nil
+}
+
+// Media returns the EXT-X-MEDIA renditions of a master playlist in order.
+func (p *Playlist) Media() []Media {
+	var media []Media
+	for _, line := range p.Lines {
+		if line.Kind == LineTag && line.Tag.Name == TagMedia {
+			media = append(media, parseMedia(line.Tag.Attrs))
+		}
+	}
+	return media
+}
+
+// RemoveMedia removes the EXT-X-MEDIA renditions for which drop returns true.
+func (p *Playlist) RemoveMedia(drop func(m Media) bool) {
+	p.Lines = slices.DeleteFunc(p.Lines, func(line Line) bool {
+		return line.Kind == LineTag && line.Tag.Name == TagMedia && drop(parseMedia(line.Tag.Attrs))
+	})
+}
+
+// InsertMedia adds EXT-X-MEDIA renditions after the existing ones or, if there are none,
+// before the first variant stream, so they are declared ahead of the variants that
+// reference their group. Without either they are appended.
+func (p *Playlist) InsertMedia(media ...Media) {
+	at, found := len(p.Lines), false
+	for i, line := range p.Lines {
+		if line.Kind != LineTag {
+			continue
+		}
+		if line.Tag.Name == TagMedia {
+			at, found = i+1, true
+		} else if line.Tag.Name == TagStreamInf {
+			if !found {
+				at = i
+			}
+			break
+		}
+	}
+
+	lines := make([]Line, len(media))
+	for i, m := range media {
+		lines[i] = Line{Kind: LineTag, Tag: m.Tag()}
+	}
+	p.Lines = slices.Insert(p.Lines, at, lines...)
+}
+
+// SetSubtitles sets the SUBTITLES group of every variant stream; an empty group removes
+// the attribute.
+func (p *Playlist) SetSubtitles(group string) {
+	for i := range p.Lines {
+		line := &p.Lines[i]
+		if line.Kind != LineTag || line.Tag.Name != TagStreamInf {
+			continue
+		}
+		if group == "" {
+			line.Tag.Attrs.Delete("SUBTITLES")
+		} else {
+			line.Tag.Attrs.SetQuoted("SUBTITLES", group)
+		}
+	}
+}
+
+// Segments returns the media segments of a media playlist in order.
+func (p *Playlist) Segments() ([]Segment, error) {
+	var (
+		segments []Segment
+		pending  *Segment // EXTINF seen, URI line not yet
+		next     Segment  // tags seen before the next EXTINF
+		key      *Key
+		initMap  *Map
+	)
+	for _, line := range p.Lines {
+		switch line.Kind {
+		case LineURI:
+			if pending == nil {
+				return nil, fmt.Errorf("segment %s without %s", line.Text, TagInf)
+			}
+			pending.URI = line.Text
+			segments = append(segments, *pending)
+			pending, next = nil, Segment{}
+			continue
+		case LineTag:
+		default:
+			continue
+		}
+
+		tag := line.Tag
+		switch tag.Name {
+		case TagInf:
+			duration, title, _ := strings.Cut(tag.Value, ",")
+			d, err := strconv.ParseFloat(duration, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid duration %q", TagInf, duration)
+			}
+			seg := next
+			seg.Duration, seg.Title, seg.Key, seg.Map = d, title, key, initMap
+			pending = &seg
+		case TagByteRange:
+			br, err := ParseByteRange(tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", TagByteRange, err)
+			}
+			if pending != nil {
+				pending.ByteRange = &br
+			} else {
+				next.ByteRange = &br
+			}
+		case TagDiscontinuity:
+			next.Discontinuity = true
+		case TagKey:
+			k := Key{}
+			k.Method, _ = tag.Attrs.Get("METHOD")
+			k.URI, _ = tag.Attrs.Get("URI")
+			k.IV, _ = tag.Attrs.Get("IV")
+			key = &k
+			if k.Method == "NONE" {
+				key = nil
+			}
+		case TagMap:
+			m := Map{}
+			m.URI, _ = tag.Attrs.Get("URI")
+			if value, ok := tag.Attrs.Get("BYTERANGE"); ok {
+				br, err := ParseByteRange(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", TagMap, err)
+				}
+				m.ByteRange = &br
+			}
+			initMap = &m
+		}
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("%s without URI", TagInf)
+	}
+	return segments, nil
+}
+
+// AddVariant appends a variant stream.
+func (p *Playlist) AddVariant(v Variant) {
+	p.AddTag(v.Tag())
+	p.AddURI(v.URI)
+}
+
+// AddMedia appends an EXT-X-MEDIA rendition.
+func (p *Playlist) AddMedia(m Media) {
+	p.AddTag(m.Tag())
+}
+
+// AddSegment appends a media segment with its EXT-X-DISCONTINUITY and EXT-X-BYTERANGE.
+// Durations are written with millisecond precision.
+func (p *Playlist) AddSegment(s Segment) {
+	if s.Discontinuity {
+		p.AddTag(Tag{Name: TagDiscontinuity})
+	}
+	p.AddTag(Tag{Name: TagInf, Value: strconv.FormatFloat(s.Duration, 'f', 3, 64) + "," + s.Title})
+	if s.ByteRange != nil {
+		p.AddTag(Tag{Name: TagByteRange, Value: s.ByteRange.String()})
+	}
+	p.AddURI(s.URI)
+}
@@ -0,0 +1,237 @@
+// Package hls parses and generates HLS playlists (RFC 8216).
+//
+// A Playlist keeps every line of its input in order, including comments, blank lines
+// and tags this package does not know, so a playlist that is parsed, edited and encoded
+// again differs from its input only where it was edited. Typed views such as Variants
+// and Segments are read from the lines on demand.
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tag names used by this package.
+const (
+	TagHeader          = "EXTM3U"
+	TagVersion         = "EXT-X-VERSION"
+	TagStreamInf       = "EXT-X-STREAM-INF"
+	TagIFrameStreamInf = "EXT-X-I-FRAME-STREAM-INF"
+	TagMedia           = "EXT-X-MEDIA"
+	TagInf             = "EXTINF"
+	TagByteRange       = "EXT-X-BYTERANGE"
+	TagDiscontinuity   = "EXT-X-DISCONTINUITY"
+	TagKey             = "EXT-X-KEY"
+	TagMap             = "EXT-X-MAP"
+	TagTargetDuration  = "EXT-X-TARGETDURATION"
+	TagEndList         = "EXT-X-ENDLIST"
+)
+
+// attributeTags are the tags whose value is an attribute list.
+var attributeTags = map[string]bool{
+	TagStreamInf:             true,
+	TagIFrameStreamInf:       true,
+	TagMedia:                 true,
+	TagKey:                   true,
+	TagMap:                   true,
+	"EXT-X-SESSION-DATA":     true,
+	"EXT-X-SESSION-KEY":      true,
+	"EXT-X-CONTENT-STEERING": true,
+	"EXT-X-DATERANGE":        true,
+	"EXT-X-DEFINE":           true,
+	"EXT-X-START":            true,
+	"EXT-X-SERVER-CONTROL":   true,
+	"EXT-X-PART-INF":         true,
+	"EXT-X-PART":             true,
+	"EXT-X-PRELOAD-HINT":     true,
+	"EXT-X-RENDITION-REPORT": true,
+	"EXT-X-SKIP":             true,
+}
+
+// ErrNotPlaylist is returned by Parse when the input does not start with #EXTM3U.
+var ErrNotPlaylist = errors.New("not an m3u8 playlist")
+
+// LineKind classifies the lines of a playlist.
+type LineKind int
+
+const (
+	LineBlank LineKind = iota
+	LineComment
+	LineTag
+	LineURI
+)
+
+// Tag is a line starting with "#EXT".
+type Tag struct {
+	// Name is the tag name without the leading '#', e.g. "EXT-X-STREAM-INF".
+	Name string
+	// Value is the text after the colon of tags without an attribute list,
+	// e.g. "6.000," for EXTINF.
+	Value string
+	// Attrs is the attribute list of tags that have one; nil otherwise.
+	Attrs AttributeList
+}
+
+func (t Tag) String() string {
+	switch {
+	case t.Attrs != nil:
+		return "#" + t.Name + ":" + t.Attrs.String()
+	case t.Value != "":
+		return "#" + t.Name + ":" + t.Value
+	default:
+		return "#" + t.Name
+	}
+}
+
+// Line is one line of a playlist.
+type Line struct {
+	Kind LineKind
+	// Tag is set for LineTag.
+	Tag Tag
+	// Text is the URI of a LineURI and the whole line, '#' included, of a LineComment.
+	Text string
+}
+
+func (l Line) String() string {
+	switch l.Kind {
+	case LineTag:
+		return l.Tag.String()
+	case LineURI, LineComment:
+		return l.Text
+	default:
+		return ""
+	}
+}
+
+// Playlist is a master or media playlist.
+type Playlist struct {
+	Lines []Line
+}
+
+// New returns a playlist holding only the #EXTM3U header and, if version is positive,
+// EXT-X-VERSION.
+func New(version int) *Playlist {
+	p := &Playlist{Lines: []Line{{Kind: LineTag, Tag: Tag{Name: TagHeader}}}}
+	if version > 0 {
+		p.AddTag(Tag{Name: TagVersion, Value: strconv.Itoa(version)})
+	}
+	return p
+}
+
+// Parse parses a playlist. Line endings may be LF or CRLF; leading and trailing
+// whitespace of each line is dropped.
+// Returns ErrNotPlaylist if the first line is not #EXTM3U.
+func Parse(data []byte) (*Playlist, error) {
+	p := &Playlist{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for n := 1; scanner.Scan(); n++ {
+		line, err := parseLine(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if n == 1 && (line.Kind != LineTag || line.Tag.Name != TagHeader) {
+			return nil, ErrNotPlaylist
+		}
+		p.Lines = append(p.Lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(p.Lines) == 0 {
+		return nil, ErrNotPlaylist
+	}
+	return p, nil
+}
+
+func parseLine(text string) (Line, error) {
+	switch {
+	case text == "":
+		return Line{Kind: LineBlank}, nil
+	case strings.HasPrefix(text, "#EXT"):
+		name, value, _ := strings.Cut(text[1:], ":")
+		tag := Tag{Name: name}
+		if attributeTags[name] {
+			attrs, err := ParseAttributeList(value)
+			if err != nil {
+				return Line{}, fmt.Errorf("%s: %w", name, err)
+			}
+			tag.Attrs = attrs
+			if tag.Attrs == nil {
+				tag.Attrs = AttributeList{}
+			}
+		} else {
+			tag.Value = value
+		}
+		return Line{Kind: LineTag, Tag: tag}, nil
+	case text[0] == '#':
+		return Line{Kind: LineComment, Text: text}, nil
+	default:
+		return Line{Kind: LineURI, Text: text}, nil
+	}
+}
+
+// Encode returns the playlist with every line terminated by LF.
+func (p *Playlist) Encode() []byte {
+	var buf bytes.Buffer
+	for _, line := range p.Lines {
+		buf.WriteString(line.String())
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// AddTag appends a tag line.
+func (p *Playlist) AddTag(tag Tag) {
+	p.Lines = append(p.Lines, Line{Kind: LineTag, Tag: tag})
+}
+
+// AddURI appends a URI line.
+func (p *Playlist) AddURI(uri string) {
+	p.Lines = append(p.Lines, Line{Kind: LineURI, Text: uri})
+}
+
+// AddBlank appends a blank line, which players ignore but keeps entries apart for readers.
+func (p *Playlist) AddBlank() {
+	p.Lines = append(p.Lines, Line{Kind: LineBlank})
+}
+
+// IsMaster reports whether the playlist lists variant streams or renditions rather
+// than segments.
+func (p *Playlist) IsMaster() bool {
+	for _, line := range p.Lines {
+		if line.Kind != LineTag {
+			continue
+		}
+		switch line.Tag.Name {
+		case TagStreamInf, TagIFrameStreamInf, TagMedia:
+			return true
+		case TagInf, TagTargetDuration:
+			return false
+		}
+	}
+	return false
+}
+
+// RewriteURIs replaces every URI of the playlist - URI lines and URI attributes of
+// tags such as EXT-X-MEDIA, EXT-X-MAP, EXT-X-KEY and EXT-X-I-FRAME-STREAM-INF - with
+// rewrite(uri).
+func (p *Playlist) RewriteURIs(rewrite func(uri string) string) {
+	for i := range p.Lines {
+		line := &p.Lines[i]
+		switch line.Kind {
+		case LineURI:
+			line.Text = rewrite(line.Text)
+		case LineTag:
+			for j := range line.Tag.Attrs {
+				if attr := &line.Tag.Attrs[j]; attr.Name == "URI" {
+					attr.Value = rewrite(attr.Value)
+				}
+			}
+		}
+	}
+}
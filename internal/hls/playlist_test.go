@@ -0,0 +1,345 @@
+package hls
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testMasterPlaylist = `#EXTM3U
+#EXT-X-VERSION:6
+# generated by packager
+#EXT-X-INDEPENDENT-SEGMENTS
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="English",LANGUAGE="en",DEFAULT=YES,AUTOSELECT=YES,CHANNELS="2",URI="audio/en.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="Japanese",LANGUAGE="ja",URI="subs/ja.m3u8"
+
+#EXT-X-STREAM-INF:BANDWIDTH=800000,AVERAGE-BANDWIDTH=700000,CODECS="avc1.4d401e,mp4a.40.2",RESOLUTION=640x360,FRAME-RATE=29.970,AUDIO="aud",SUBTITLES="subs"
+360p/playlist.m3u8
+
+#EXT-X-STREAM-INF:BANDWIDTH=2800000,RESOLUTION=1280x720,AUDIO="aud"
+720p/playlist.m3u8
+
+#EXT-X-I-FRAME-STREAM-INF:BANDWIDTH=100000,URI="360p/iframes.m3u8"
+`
+
+const testMediaPlaylist = `#EXTM3U
+#EXT-X-VERSION:7
+#EXT-X-TARGETDURATION:6
+#EXT-X-MAP:URI="init.mp4",BYTERANGE="720@0"
+#EXTINF:6.000,first
+#EXT-X-BYTERANGE:1000@720
+main.mp4
+#EXTINF:6.000,
+#EXT-X-BYTERANGE:1200
+main.mp4
+#EXT-X-DISCONTINUITY
+#EXT-X-KEY:METHOD=AES-128,URI="https://keys.example.com/k1",IV=0x0000000000000000000000000000000A
+#EXTINF:4.5,
+segment_002.ts
+#EXT-X-KEY:METHOD=NONE
+#EXTINF:2.000,
+segment_003.ts
+#EXT-X-ENDLIST
+`
+
+func TestParse_RoundTrip(t *testing.T) {
+	for name, input := range map[string]string{
+		"master": testMasterPlaylist,
+		"media":  testMediaPlaylist,
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, err := Parse([]byte(input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := string(p.Encode()); got != input {
+				t.Errorf("Encode() = %q, want %q", got, input)
+			}
+		})
+	}
+}
+
+func TestParse_CRLF(t *testing.T) {
+	p, err := Parse([]byte("#EXTM3U\r\n#EXT-X-VERSION:3\r\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := string(p.Encode()); got != "#EXTM3U\n#EXT-X-VERSION:3\n" {
+		t.Errorf("Encode() = %q", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "empty", input: "", wantErr: ErrNotPlaylist},
+		{name: "missing header", input: "#EXT-X-VERSION:3\n", wantErr: ErrNotPlaylist},
+		{name: "leading blank line", input: "\n#EXTM3U\n", wantErr: ErrNotPlaylist},
+		{name: "malformed attribute list", input: "#EXTM3U\n#EXT-X-KEY:METHOD=AES-128,URI=\"k\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			if err == nil {
+				t.Fatal("Parse() expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlaylist_IsMaster(t *testing.T) {
+	master, _ := Parse([]byte(testMasterPlaylist))
+	media, _ := Parse([]byte(testMediaPlaylist))
+
+	if !master.IsMaster() {
+		t.Error("master.IsMaster() = false, want true")
+	}
+	if media.IsMaster() {
+		t.Error("media.IsMaster() = true, want false")
+	}
+}
+
+func TestPlaylist_Variants(t *testing.T) {
+	p, err := Parse([]byte(testMasterPlaylist))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got, err := p.Variants()
+	if err != nil {
+		t.Fatalf("Variants() error = %v", err)
+	}
+
+	want := []Variant{
+		{
+			Bandwidth:        800000,
+			AverageBandwidth: 700000,
+			Codecs:           "avc1.4d401e,mp4a.40.2",
+			Width:            640,
+			Height:           360,
+			FrameRate:        29.97,
+			Audio:            "aud",
+			Subtitles:        "subs",
+			URI:              "360p/playlist.m3u8",
+		},
+		{Bandwidth: 2800000, Width: 1280, Height: 720, Audio: "aud", URI: "720p/playlist.m3u8"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Variants() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlaylist_Variants_MissingURI(t *testing.T) {
+	p, err := Parse([]byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1\n#EXT-X-STREAM-INF:BANDWIDTH=2\nb.m3u8\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := p.Variants(); err == nil {
+		t.Error("Variants() expected error")
+	}
+}
+
+func TestPlaylist_Media(t *testing.T) {
+	p, err := Parse([]byte(testMasterPlaylist))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Media{
+		{
+			Type:       "AUDIO",
+			GroupID:    "aud",
+			Name:       "English",
+			Language:   "en",
+			Default:    true,
+			Autoselect: true,
+			Channels:   "2",
+			URI:        "audio/en.m3u8",
+		},
+		{Type: "SUBTITLES", GroupID: "subs", Name: "Japanese", Language: "ja", URI: "subs/ja.m3u8"},
+	}
+	if got := p.Media(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Media() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlaylist_Segments(t *testing.T) {
+	p, err := Parse([]byte(testMediaPlaylist))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got, err := p.Segments()
+	if err != nil {
+		t.Fatalf("Segments() error = %v", err)
+	}
+
+	initMap := &Map{URI: "init.mp4", ByteRange: &ByteRange{Length: 720, HasOffset: true}}
+	key := &Key{Method: "AES-128", URI: "https://keys.example.com/k1", IV: "0x0000000000000000000000000000000A"}
+	want := []Segment{
+		{Duration: 6, Title: "first", URI: "main.mp4", ByteRange: &ByteRange{Length: 1000, Offset: 720, HasOffset: true}, Map: initMap},
+		{Duration: 6, URI: "main.mp4", ByteRange: &ByteRange{Length: 1200}, Map: initMap},
+		{Duration: 4.5, URI: "segment_002.ts", Discontinuity: true, Key: key, Map: initMap},
+		{Duration: 2, URI: "segment_003.ts", Map: initMap},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Segments() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlaylist_Segments_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "URI without EXTINF", input: "#EXTM3U\nsegment_000.ts\n"},
+		{name: "EXTINF without URI", input: "#EXTM3U\n#EXTINF:6.000,\n"},
+		{name: "invalid duration", input: "#EXTM3U\n#EXTINF:abc,\nsegment_000.ts\n"},
+		{name: "invalid byte range", input: "#EXTM3U\n#EXTINF:6.000,\n#EXT-X-BYTERANGE:x\nsegment_000.ts\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if _, err := p.Segments(); err == nil {
+				t.Error("Segments() expected error")
+			}
+		})
+	}
+}
+
+func TestPlaylist_RewriteURIs(t *testing.T) {
+	p, err := Parse([]byte(testMasterPlaylist))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	p.RewriteURIs(func(uri string) string { return "/cdn/" + uri })
+
+	got := string(p.Encode())
+	for _, want := range []string{
+		`URI="/cdn/audio/en.m3u8"`,
+		`URI="/cdn/subs/ja.m3u8"`,
+		"\n/cdn/360p/playlist.m3u8\n",
+		"\n/cdn/720p/playlist.m3u8\n",
+		`URI="/cdn/360p/iframes.m3u8"`,
+		"# generated by packager\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rewritten playlist missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestPlaylist_RemoveVariants(t *testing.T) {
+	p, err := Parse([]byte(testMasterPlaylist))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	err = p.RemoveVariants(func(v Variant) bool { return v.Height == 360 })
+	if err != nil {
+		t.Fatalf("RemoveVariants() error = %v", err)
+	}
+
+	want := strings.Replace(testMasterPlaylist,
+		"#EXT-X-STREAM-INF:BANDWIDTH=800000,AVERAGE-BANDWIDTH=700000,CODECS=\"avc1.4d401e,mp4a.40.2\",RESOLUTION=640x360,FRAME-RATE=29.970,AUDIO=\"aud\",SUBTITLES=\"subs\"\n360p/playlist.m3u8\n\n",
+		"", 1)
+	if got := string(p.Encode()); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+}
+
+func TestPlaylist_ReplaceSubtitles(t *testing.T) {
+	p, err := Parse([]byte(testMasterPlaylist))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	p.RemoveMedia(func(m Media) bool { return m.Type == "SUBTITLES" })
+	p.InsertMedia(Media{Type: "SUBTITLES", GroupID: "text", Name: "English", Language: "en", URI: "subtitles/en/playlist.m3u8"})
+	p.SetSubtitles("text")
+
+	want := strings.NewReplacer(
+		`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="Japanese",LANGUAGE="ja",URI="subs/ja.m3u8"`,
+		`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="text",NAME="English",LANGUAGE="en",URI="subtitles/en/playlist.m3u8"`,
+		`SUBTITLES="subs"`, `SUBTITLES="text"`,
+		`RESOLUTION=1280x720,AUDIO="aud"`, `RESOLUTION=1280x720,AUDIO="aud",SUBTITLES="text"`,
+	).Replace(testMasterPlaylist)
+	if got := string(p.Encode()); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+
+	p.SetSubtitles("")
+	for _, v := range mustVariants(t, p) {
+		if v.Subtitles != "" {
+			t.Errorf("variant %s keeps SUBTITLES=%q", v.URI, v.Subtitles)
+		}
+	}
+}
+
+func TestPlaylist_InsertMedia_NoMedia(t *testing.T) {
+	p := New(3)
+	p.AddBlank()
+	p.AddVariant(Variant{Bandwidth: 800000, URI: "360p/playlist.m3u8"})
+	p.InsertMedia(Media{Type: "SUBTITLES", GroupID: "text", Name: "English", URI: "subtitles/en/playlist.m3u8"})
+
+	want := "#EXTM3U\n#EXT-X-VERSION:3\n\n" +
+		"#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID=\"text\",NAME=\"English\",URI=\"subtitles/en/playlist.m3u8\"\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=800000\n360p/playlist.m3u8\n"
+	if got := string(p.Encode()); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+}
+
+func mustVariants(t *testing.T, p *Playlist) []Variant {
+	t.Helper()
+	variants, err := p.Variants()
+	if err != nil {
+		t.Fatalf("Variants() error = %v", err)
+	}
+	return variants
+}
+
+func TestNew(t *testing.T) {
+	p := New(7)
+	p.AddMedia(Media{Type: "AUDIO", GroupID: "aud", Name: "English", Default: true, URI: "audio/en.m3u8"})
+	p.AddVariant(Variant{Bandwidth: 800000, Codecs: "avc1.4d401e", Width: 640, Height: 360, Audio: "aud", URI: "360p/playlist.m3u8"})
+	p.AddTag(Map{URI: "init.mp4"}.Tag())
+	p.AddTag(Key{Method: "AES-128", URI: "key.bin"}.Tag())
+	p.AddSegment(Segment{Duration: 6, URI: "seg0.m4s"})
+	p.AddSegment(Segment{Duration: 2.5, URI: "main.mp4", ByteRange: &ByteRange{Length: 100, Offset: 0, HasOffset: true}, Discontinuity: true})
+
+	want := `#EXTM3U
+#EXT-X-VERSION:7
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="English",DEFAULT=YES,URI="audio/en.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=800000,CODECS="avc1.4d401e",RESOLUTION=640x360,AUDIO="aud"
+360p/playlist.m3u8
+#EXT-X-MAP:URI="init.mp4"
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin"
+#EXTINF:6.000,
+seg0.m4s
+#EXT-X-DISCONTINUITY
+#EXTINF:2.500,
+#EXT-X-BYTERANGE:100@0
+main.mp4
+`
+	if got := string(p.Encode()); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+
+	if got := string(New(0).Encode()); got != "#EXTM3U\n" {
+		t.Errorf("New(0).Encode() = %q", got)
+	}
+}
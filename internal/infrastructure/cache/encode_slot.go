@@ -0,0 +1,24 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EncodeSlotStore counts the encodes each tenant is running across the worker fleet,
+// so a tenant can be held to a number of simultaneous encodes. A slot is held while it
+// has been touched within its timeout, so slots of crashed workers free themselves.
+type EncodeSlotStore interface {
+	// Acquire claims slotID for tenantID if the tenant holds fewer than limit slots.
+	// Re-acquiring a held slot always succeeds and refreshes it.
+	// Returns false without error when the limit is reached.
+	Acquire(ctx context.Context, tenantID uuid.UUID, slotID string, limit int, now time.Time, timeout time.Duration) (bool, error)
+
+	// Touch refreshes a held slot (heartbeat). Unknown slots are ignored.
+	Touch(ctx context.Context, tenantID uuid.UUID, slotID string, now time.Time) error
+
+	// Release frees a slot so it no longer counts towards the limit.
+	Release(ctx context.Context, tenantID uuid.UUID, slotID string) error
+}
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// LockStore provides named locks shared by every process, so a job that must not run
+// concurrently (e.g., a startup pass) runs on only one worker at a time.
+type LockStore interface {
+	// Acquire takes the named lock for ttl and returns a token identifying the holder.
+	// Returns false without error if another holder has the lock.
+	Acquire(ctx context.Context, name string, ttl time.Duration) (string, bool, error)
+
+	// Release frees the named lock if it is still held with token.
+	// Releasing a lock that expired or was taken over is a no-op.
+	Release(ctx context.Context, name, token string) error
+}
@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// MaintenanceStore holds the maintenance window shared by every API server and worker.
+// At most one window is stored; scheduling another replaces it.
+type MaintenanceStore interface {
+	// Get returns the stored window, scheduled or open.
+	// Returns nil, nil if no window is stored or the stored one has ended.
+	Get(ctx context.Context) (*model.MaintenanceWindow, error)
+
+	// Set stores the window, replacing any other. A window with an end is removed once it ends.
+	Set(ctx context.Context, window *model.MaintenanceWindow) error
+
+	// Clear removes the stored window, ending maintenance early.
+	Clear(ctx context.Context) error
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// PlaybackTokenStore defines the interface for persisting playback tokens and revocations.
+// Bulk revocation is modelled as a "revoked before" timestamp per video or user rather than
+// tracking every issued token, so revoking is O(1) regardless of how many tokens exist.
+type PlaybackTokenStore interface {
+	// Save stores a token until its expiry.
+	Save(ctx context.Context, token *model.PlaybackToken) error
+
+	// Get retrieves a token by its opaque value.
+	// Returns nil, nil if the token does not exist or has expired.
+	Get(ctx context.Context, token string) (*model.PlaybackToken, error)
+
+	// Delete removes a single token.
+	// Returns nil if the token did not exist.
+	Delete(ctx context.Context, token string) error
+
+	// RevokeVideo invalidates all tokens for a video issued at or before the given time.
+	// The marker is kept for ttl, which should be at least the maximum token lifetime.
+	RevokeVideo(ctx context.Context, videoID uuid.UUID, at time.Time, ttl time.Duration) error
+
+	// RevokeUser invalidates all tokens for a user issued at or before the given time.
+	// The marker is kept for ttl, which should be at least the maximum token lifetime.
+	RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time, ttl time.Duration) error
+
+	// RevokedAt returns the latest revocation markers for a video and a user.
+	// Zero times are returned when no marker exists.
+	RevokedAt(ctx context.Context, videoID, userID uuid.UUID) (videoRevokedAt, userRevokedAt time.Time, err error)
+}
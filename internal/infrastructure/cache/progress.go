@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// ProgressStore buffers playback progress heartbeats in front of the database.
+// Heartbeats are written here and flushed to PostgreSQL in batches, so frequent
+// position updates do not translate into one database write each.
+type ProgressStore interface {
+	// Save stores the latest progress and marks it for the next flush.
+	Save(ctx context.Context, progress *model.PlaybackProgress) error
+
+	// Prime stores progress loaded from the database without marking it for flush.
+	Prime(ctx context.Context, progress *model.PlaybackProgress) error
+
+	// Get retrieves the buffered progress of a user on a video.
+	// Returns nil, nil if nothing is buffered.
+	Get(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error)
+
+	// PopDirty removes and returns up to n records awaiting flush. Records that expired
+	// are dropped, so fewer than n may be returned while more remain; drained reports
+	// that the records awaiting flush ran out.
+	PopDirty(ctx context.Context, n int) (progress []*model.PlaybackProgress, drained bool, err error)
+
+	// MarkDirty re-queues records for the next flush (e.g., after a failed flush).
+	MarkDirty(ctx context.Context, progress []*model.PlaybackProgress) error
+
+	// Delete removes buffered progress and drops it from the pending flush.
+	// Reports whether any progress was buffered.
+	Delete(ctx context.Context, userID, videoID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore keeps token buckets shared by every API instance, so a client is held
+// to one limit whichever instance serves it.
+type RateLimitStore interface {
+	// Take removes a token from the bucket key, which holds up to burst tokens and
+	// refills at rate tokens per second; a new bucket starts full.
+	// Returns false without error, and the wait until a token is available, when the
+	// bucket is empty.
+	Take(ctx context.Context, key string, rate float64, burst int, now time.Time) (bool, time.Duration, error)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,36 +18,114 @@ import (
 const (
 	// videoCacheKeyPrefix is the prefix for video cache keys in Redis.
 	videoCacheKeyPrefix = "video:"
+	// videoListCacheKeyPrefix is the prefix for a user's video list hash in Redis.
+	// Each field is a page size, so one DEL drops every cached variant of the first page.
+	videoListCacheKeyPrefix = "video_list:"
 )
 
 // videoJSON is the JSON representation of a Video for caching.
 // Using explicit struct avoids coupling to domain model's JSON tags.
 type videoJSON struct {
-	ID          string `json:"id"`
-	UserID      string `json:"user_id"`
-	Title       string `json:"title"`
-	Status      string `json:"status"`
-	OriginalURL string `json:"original_url"`
-	HLSURL      string `json:"hls_url"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID             string   `json:"id"`
+	UserID         string   `json:"user_id"`
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Status         string   `json:"status"`
+	Visibility     string   `json:"visibility,omitempty"`
+	OriginalURL    string   `json:"original_url"`
+	HLSURL         string   `json:"hls_url"`
+	DashURL        string   `json:"dash_url,omitempty"`
+	PreviewSeconds int      `json:"preview_seconds,omitempty"`
+	PreviewURL     string   `json:"preview_url,omitempty"`
+	OutputVersion  int64    `json:"output_version,omitempty"`
+	ExternalOutput bool     `json:"external_output,omitempty"`
+	SortableID     string   `json:"sortable_id,omitempty"`
+	ShareSlug      string   `json:"share_slug,omitempty"`
+	TitleSlug      string   `json:"title_slug,omitempty"`
+	StorageKey     string   `json:"storage_key,omitempty"`
+	CreatedAt      string   `json:"created_at"`
+	UpdatedAt      string   `json:"updated_at"`
+	// Source is omitted until the original has been probed.
+	Source *sourceJSON `json:"source,omitempty"`
+	// ThumbnailPrefix is cached as a storage key; CDN URLs are built per request.
+	ThumbnailPrefix string `json:"thumbnail_prefix,omitempty"`
+	ExpiresAt       string `json:"expires_at,omitempty"`
+	FailureCode     string `json:"failure_code,omitempty"`
+	FailureReason   string `json:"failure_reason,omitempty"`
+	// FreshUntil is when a single-video entry expires logically; the key outlives it by
+	// the stale TTL. Empty in list pages, which expire with their key.
+	FreshUntil string `json:"fresh_until,omitempty"`
+	// LoadTimeUs is how long the video took to load, in microseconds; 0 if unknown.
+	LoadTimeUs int64 `json:"load_time_us,omitempty"`
+}
+
+// videoPageJSON is the cached form of VideoPage.
+type videoPageJSON struct {
+	Videos     []json.RawMessage `json:"videos"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// sourceJSON is the cached form of model.SourceMetadata.
+type sourceJSON struct {
+	DurationMs int64   `json:"duration_ms"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	Codec      string  `json:"codec,omitempty"`
+	Bitrate    int64   `json:"bitrate,omitempty"`
+	FrameRate  float64 `json:"frame_rate,omitempty"`
 }
 
 // RedisVideoCache implements VideoCache using Redis as the backing store.
 type RedisVideoCache struct {
-	client *redis.Client
+	client    *redis.Client
+	ttlJitter float64
+	now       func() time.Time
+	random    func() float64
 }
 
 // NewRedisVideoCache creates a new Redis-backed video cache.
-func NewRedisVideoCache(client *redis.Client) *RedisVideoCache {
+// ttlJitter is the largest fraction, between 0 and 1, by which each TTL is randomly
+// shortened, so entries cached together by several API replicas do not all expire at
+// once; 0 stores TTLs as given.
+func NewRedisVideoCache(client *redis.Client, ttlJitter float64) *RedisVideoCache {
 	return &RedisVideoCache{
-		client: client,
+		client:    client,
+		ttlJitter: min(max(ttlJitter, 0), 1),
+		now:       time.Now,
+		random:    rand.Float64,
 	}
 }
 
 // Get retrieves a video from Redis cache.
-// Returns nil, nil on cache miss.
+// Returns nil, nil on cache miss, including entries only retained for stale reads.
 func (c *RedisVideoCache) Get(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	entry, err := c.get(ctx, videoID, false)
+	if entry == nil {
+		return nil, err
+	}
+	return entry.Video, nil
+}
+
+// GetStale retrieves a video from Redis cache, ignoring its logical expiry.
+// Returns nil, nil on cache miss.
+func (c *RedisVideoCache) GetStale(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	entry, err := c.get(ctx, videoID, true)
+	if entry == nil {
+		return nil, err
+	}
+	return entry.Video, nil
+}
+
+// GetEntry retrieves a video from Redis cache with its logical expiry and load time.
+// Returns nil, nil on cache miss, like Get.
+func (c *RedisVideoCache) GetEntry(ctx context.Context, videoID uuid.UUID) (*VideoEntry, error) {
+	return c.get(ctx, videoID, false)
+}
+
+// get reads a video entry; entries past their logical expiry count as misses unless
+// stale is set. Stale reads of expired entries are counted with the stale status.
+func (c *RedisVideoCache) get(ctx context.Context, videoID uuid.UUID, stale bool) (*VideoEntry, error) {
 	key := c.buildKey(videoID)
 
 	data, err := c.client.Get(ctx, key).Bytes()
@@ -62,7 +142,7 @@ func (c *RedisVideoCache) Get(ctx context.Context, videoID uuid.UUID) (*model.Vi
 		return nil, fmt.Errorf("redis get: %w", err)
 	}
 
-	video, err := c.deserialize(data)
+	entry, err := c.deserialize(data)
 	if err != nil {
 		metrics.CacheOperationsTotal.WithLabelValues(
 			metrics.CacheOpGet, metrics.CacheStatusError, metrics.CacheTypeRedis,
@@ -70,17 +150,31 @@ func (c *RedisVideoCache) Get(ctx context.Context, videoID uuid.UUID) (*model.Vi
 		return nil, fmt.Errorf("deserialize video: %w", err)
 	}
 
+	status := metrics.CacheStatusHit
+	if !entry.FreshUntil.IsZero() && c.now().After(entry.FreshUntil) {
+		if !stale {
+			metrics.CacheOperationsTotal.WithLabelValues(
+				metrics.CacheOpGet, metrics.CacheStatusMiss, metrics.CacheTypeRedis,
+			).Inc()
+			return nil, nil // Retained only for stale reads
+		}
+		status = metrics.CacheStatusStale
+	}
+
 	metrics.CacheOperationsTotal.WithLabelValues(
-		metrics.CacheOpGet, metrics.CacheStatusHit, metrics.CacheTypeRedis,
+		metrics.CacheOpGet, status, metrics.CacheTypeRedis,
 	).Inc()
-	return video, nil
+	return entry, nil
 }
 
-// Set stores a video in Redis cache with the specified TTL.
-func (c *RedisVideoCache) Set(ctx context.Context, video *model.Video, ttl time.Duration) error {
+// Set stores a video in Redis cache with the specified TTL, shortened by the jitter.
+// The key expires staleTTL after the TTL; the TTL itself and the load time are recorded
+// in the entry.
+func (c *RedisVideoCache) Set(ctx context.Context, video *model.Video, ttl, staleTTL, loadTime time.Duration) error {
 	key := c.buildKey(video.ID)
+	ttl = c.jitter(ttl)
 
-	data, err := c.serialize(video)
+	data, err := c.serialize(video, &VideoEntry{FreshUntil: c.now().Add(ttl), LoadTime: loadTime})
 	if err != nil {
 		metrics.CacheOperationsTotal.WithLabelValues(
 			metrics.CacheOpSet, metrics.CacheStatusError, metrics.CacheTypeRedis,
@@ -88,7 +182,7 @@ func (c *RedisVideoCache) Set(ctx context.Context, video *model.Video, ttl time.
 		return fmt.Errorf("serialize video: %w", err)
 	}
 
-	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+	if err := c.client.Set(ctx, key, data, ttl+staleTTL).Err(); err != nil {
 		metrics.CacheOperationsTotal.WithLabelValues(
 			metrics.CacheOpSet, metrics.CacheStatusError, metrics.CacheTypeRedis,
 		).Inc()
@@ -118,28 +212,152 @@ func (c *RedisVideoCache) Delete(ctx context.Context, videoID uuid.UUID) error {
 	return nil
 }
 
+// GetFirstPage retrieves the first page of a user's video list from Redis.
+// Returns nil, nil on cache miss.
+func (c *RedisVideoCache) GetFirstPage(ctx context.Context, userID uuid.UUID, limit int) (*VideoPage, error) {
+	data, err := c.client.HGet(ctx, c.buildListKey(userID), strconv.Itoa(limit)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			metrics.CacheOperationsTotal.WithLabelValues(
+				metrics.CacheOpGet, metrics.CacheStatusMiss, metrics.CacheTypeRedis,
+			).Inc()
+			return nil, nil // Cache miss
+		}
+		metrics.CacheOperationsTotal.WithLabelValues(
+			metrics.CacheOpGet, metrics.CacheStatusError, metrics.CacheTypeRedis,
+		).Inc()
+		return nil, fmt.Errorf("redis hget: %w", err)
+	}
+
+	page, err := c.deserializePage(data)
+	if err != nil {
+		metrics.CacheOperationsTotal.WithLabelValues(
+			metrics.CacheOpGet, metrics.CacheStatusError, metrics.CacheTypeRedis,
+		).Inc()
+		return nil, fmt.Errorf("deserialize video page: %w", err)
+	}
+
+	metrics.CacheOperationsTotal.WithLabelValues(
+		metrics.CacheOpGet, metrics.CacheStatusHit, metrics.CacheTypeRedis,
+	).Inc()
+	return page, nil
+}
+
+// SetFirstPage stores the first page of a user's video list in Redis.
+// The TTL, shortened by the jitter, applies to the user's whole list hash and is
+// refreshed on every write.
+func (c *RedisVideoCache) SetFirstPage(ctx context.Context, userID uuid.UUID, limit int, page *VideoPage, ttl time.Duration) error {
+	data, err := c.serializePage(page)
+	if err != nil {
+		metrics.CacheOperationsTotal.WithLabelValues(
+			metrics.CacheOpSet, metrics.CacheStatusError, metrics.CacheTypeRedis,
+		).Inc()
+		return fmt.Errorf("serialize video page: %w", err)
+	}
+
+	key := c.buildListKey(userID)
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, strconv.Itoa(limit), data)
+		pipe.Expire(ctx, key, c.jitter(ttl))
+		return nil
+	})
+	if err != nil {
+		metrics.CacheOperationsTotal.WithLabelValues(
+			metrics.CacheOpSet, metrics.CacheStatusError, metrics.CacheTypeRedis,
+		).Inc()
+		return fmt.Errorf("redis hset: %w", err)
+	}
+
+	metrics.CacheOperationsTotal.WithLabelValues(
+		metrics.CacheOpSet, metrics.CacheStatusSuccess, metrics.CacheTypeRedis,
+	).Inc()
+	return nil
+}
+
+// DeleteFirstPages removes every cached first page of a user's video list from Redis.
+func (c *RedisVideoCache) DeleteFirstPages(ctx context.Context, userID uuid.UUID) error {
+	if err := c.client.Del(ctx, c.buildListKey(userID)).Err(); err != nil {
+		metrics.CacheOperationsTotal.WithLabelValues(
+			metrics.CacheOpDelete, metrics.CacheStatusError, metrics.CacheTypeRedis,
+		).Inc()
+		return fmt.Errorf("redis del: %w", err)
+	}
+
+	metrics.CacheOperationsTotal.WithLabelValues(
+		metrics.CacheOpDelete, metrics.CacheStatusSuccess, metrics.CacheTypeRedis,
+	).Inc()
+	return nil
+}
+
+// jitter shortens ttl by a random fraction of up to ttlJitter.
+func (c *RedisVideoCache) jitter(ttl time.Duration) time.Duration {
+	if c.ttlJitter <= 0 {
+		return ttl
+	}
+	return ttl - time.Duration(float64(ttl)*c.ttlJitter*c.random())
+}
+
+// buildListKey constructs the Redis key for a user's video list hash.
+func (c *RedisVideoCache) buildListKey(userID uuid.UUID) string {
+	return videoListCacheKeyPrefix + userID.String()
+}
+
 // buildKey constructs the Redis key for a video.
 func (c *RedisVideoCache) buildKey(videoID uuid.UUID) string {
 	return videoCacheKeyPrefix + videoID.String()
 }
 
-// serialize converts a Video to JSON bytes.
-func (c *RedisVideoCache) serialize(video *model.Video) ([]byte, error) {
+// serialize converts a Video to JSON bytes; a nil meta, as for list pages, leaves the
+// expiry to the key.
+func (c *RedisVideoCache) serialize(video *model.Video, meta *VideoEntry) ([]byte, error) {
 	v := videoJSON{
-		ID:          video.ID.String(),
-		UserID:      video.UserID.String(),
-		Title:       video.Title,
-		Status:      string(video.Status),
-		OriginalURL: video.OriginalURL,
-		HLSURL:      video.HLSURL,
-		CreatedAt:   video.CreatedAt.Format(time.RFC3339Nano),
-		UpdatedAt:   video.UpdatedAt.Format(time.RFC3339Nano),
+		ID:              video.ID.String(),
+		UserID:          video.UserID.String(),
+		Title:           video.Title,
+		Description:     video.Description,
+		Tags:            video.Tags,
+		Status:          string(video.Status),
+		Visibility:      string(video.Visibility),
+		OriginalURL:     video.OriginalURL,
+		HLSURL:          video.HLSURL,
+		DashURL:         video.DashURL,
+		PreviewSeconds:  video.PreviewSeconds,
+		PreviewURL:      video.PreviewURL,
+		ThumbnailPrefix: video.ThumbnailPrefix,
+		OutputVersion:   video.OutputVersion,
+		ExternalOutput:  video.ExternalOutput,
+		SortableID:      video.SortableID,
+		ShareSlug:       video.ShareSlug,
+		TitleSlug:       video.TitleSlug,
+		StorageKey:      video.StorageKey,
+		FailureCode:     string(video.FailureCode),
+		FailureReason:   video.FailureReason,
+		CreatedAt:       video.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:       video.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	if meta != nil {
+		v.FreshUntil = meta.FreshUntil.Format(time.RFC3339Nano)
+		v.LoadTimeUs = meta.LoadTime.Microseconds()
+	}
+	if !video.ExpiresAt.IsZero() {
+		v.ExpiresAt = video.ExpiresAt.Format(time.RFC3339Nano)
+	}
+	if !video.Source.IsZero() {
+		v.Source = &sourceJSON{
+			DurationMs: video.Source.Duration.Milliseconds(),
+			Width:      video.Source.Width,
+			Height:     video.Source.Height,
+			Codec:      video.Source.Codec,
+			Bitrate:    video.Source.Bitrate,
+			FrameRate:  video.Source.FrameRate,
+		}
 	}
 	return json.Marshal(v)
 }
 
-// deserialize converts JSON bytes to a Video.
-func (c *RedisVideoCache) deserialize(data []byte) (*model.Video, error) {
+// deserialize converts JSON bytes to a cache entry, whose logical expiry is zero for
+// entries that expire with their key.
+func (c *RedisVideoCache) deserialize(data []byte) (*VideoEntry, error) {
 	var v videoJSON
 	if err := json.Unmarshal(data, &v); err != nil {
 		return nil, err
@@ -165,14 +383,99 @@ func (c *RedisVideoCache) deserialize(data []byte) (*model.Video, error) {
 		return nil, fmt.Errorf("parse updated_at: %w", err)
 	}
 
-	return &model.Video{
-		ID:          id,
-		UserID:      userID,
-		Title:       v.Title,
-		Status:      model.Status(v.Status),
-		OriginalURL: v.OriginalURL,
-		HLSURL:      v.HLSURL,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
+	var freshUntil time.Time
+	if v.FreshUntil != "" {
+		freshUntil, err = time.Parse(time.RFC3339Nano, v.FreshUntil)
+		if err != nil {
+			return nil, fmt.Errorf("parse fresh_until: %w", err)
+		}
+	}
+
+	video := &model.Video{
+		ID:              id,
+		UserID:          userID,
+		Title:           v.Title,
+		Description:     v.Description,
+		Tags:            v.Tags,
+		Status:          model.Status(v.Status),
+		Visibility:      model.VisibilityUnlisted,
+		OriginalURL:     v.OriginalURL,
+		HLSURL:          v.HLSURL,
+		DashURL:         v.DashURL,
+		PreviewSeconds:  v.PreviewSeconds,
+		PreviewURL:      v.PreviewURL,
+		ThumbnailPrefix: v.ThumbnailPrefix,
+		OutputVersion:   v.OutputVersion,
+		ExternalOutput:  v.ExternalOutput,
+		SortableID:      v.SortableID,
+		ShareSlug:       v.ShareSlug,
+		TitleSlug:       v.TitleSlug,
+		StorageKey:      v.StorageKey,
+		FailureCode:     model.FailureCode(v.FailureCode),
+		FailureReason:   v.FailureReason,
+		CreatedAt:       createdAt,
+		UpdatedAt:       updatedAt,
+	}
+	// Entries cached before videos had a visibility were unlisted
+	if v.Visibility != "" {
+		video.Visibility = model.Visibility(v.Visibility)
+	}
+	if v.ExpiresAt != "" {
+		video.ExpiresAt, err = time.Parse(time.RFC3339Nano, v.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("parse expires_at: %w", err)
+		}
+	}
+	if v.Source != nil {
+		video.Source = model.SourceMetadata{
+			Duration:  time.Duration(v.Source.DurationMs) * time.Millisecond,
+			Width:     v.Source.Width,
+			Height:    v.Source.Height,
+			Codec:     v.Source.Codec,
+			Bitrate:   v.Source.Bitrate,
+			FrameRate: v.Source.FrameRate,
+		}
+	}
+	return &VideoEntry{
+		Video:      video,
+		FreshUntil: freshUntil,
+		LoadTime:   time.Duration(v.LoadTimeUs) * time.Microsecond,
 	}, nil
 }
+
+// serializePage converts a VideoPage to JSON bytes, reusing the per-video encoding.
+func (c *RedisVideoCache) serializePage(page *VideoPage) ([]byte, error) {
+	p := videoPageJSON{
+		Videos:     make([]json.RawMessage, len(page.Videos)),
+		NextCursor: page.NextCursor,
+	}
+	for i, video := range page.Videos {
+		data, err := c.serialize(video, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.Videos[i] = data
+	}
+	return json.Marshal(p)
+}
+
+// deserializePage converts JSON bytes to a VideoPage.
+func (c *RedisVideoCache) deserializePage(data []byte) (*VideoPage, error) {
+	var p videoPageJSON
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	page := &VideoPage{
+		Videos:     make([]*model.Video, len(p.Videos)),
+		NextCursor: p.NextCursor,
+	}
+	for i, raw := range p.Videos {
+		entry, err := c.deserialize(raw)
+		if err != nil {
+			return nil, err
+		}
+		page.Videos[i] = entry.Video
+	}
+	return page, nil
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// encodeSlotKeyPrefix is the prefix for per-tenant encode slot sorted sets in Redis.
+	// Members are slot IDs; scores are last-touched Unix milliseconds.
+	encodeSlotKeyPrefix = "encode_slots:"
+)
+
+// RedisEncodeSlotStore implements EncodeSlotStore as a semaphore on Redis sorted sets.
+// Slots are claimed with the same atomic prune-check-add script as stream sessions.
+type RedisEncodeSlotStore struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisEncodeSlotStore implements EncodeSlotStore.
+var _ EncodeSlotStore = (*RedisEncodeSlotStore)(nil)
+
+// NewRedisEncodeSlotStore creates a new Redis-backed encode slot store.
+func NewRedisEncodeSlotStore(client *redis.Client) *RedisEncodeSlotStore {
+	return &RedisEncodeSlotStore{
+		client: client,
+	}
+}
+
+// Acquire atomically claims a slot if the tenant is under the limit.
+func (s *RedisEncodeSlotStore) Acquire(ctx context.Context, tenantID uuid.UUID, slotID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+	acquired, err := acquireSessionScript.Run(ctx, s.client,
+		[]string{s.buildKey(tenantID)},
+		now.UnixMilli(),
+		now.Add(-timeout).UnixMilli(),
+		limit,
+		slotID,
+		timeout.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("redis acquire encode slot: %w", err)
+	}
+	return acquired == 1, nil
+}
+
+// Touch refreshes the last-touched score of a held slot.
+func (s *RedisEncodeSlotStore) Touch(ctx context.Context, tenantID uuid.UUID, slotID string, now time.Time) error {
+	err := s.client.ZAddXX(ctx, s.buildKey(tenantID), redis.Z{
+		Score:  float64(now.UnixMilli()),
+		Member: slotID,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("redis touch encode slot: %w", err)
+	}
+	return nil
+}
+
+// Release removes a slot from the tenant's set.
+func (s *RedisEncodeSlotStore) Release(ctx context.Context, tenantID uuid.UUID, slotID string) error {
+	if err := s.client.ZRem(ctx, s.buildKey(tenantID), slotID).Err(); err != nil {
+		return fmt.Errorf("redis release encode slot: %w", err)
+	}
+	return nil
+}
+
+// buildKey constructs the Redis key for a tenant's slot set.
+func (s *RedisEncodeSlotStore) buildKey(tenantID uuid.UUID) string {
+	return encodeSlotKeyPrefix + tenantID.String()
+}
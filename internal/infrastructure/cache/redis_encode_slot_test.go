@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRedisEncodeSlotStore(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisEncodeSlotStore(client)
+	ctx := context.Background()
+	tenantID := uuid.New()
+	start := time.Now()
+
+	for _, slot := range []string{"a", "b"} {
+		if ok, err := store.Acquire(ctx, tenantID, slot, 2, start, time.Minute); err != nil || !ok {
+			t.Fatalf("Acquire(%s) = %v, %v; expected a slot", slot, ok, err)
+		}
+	}
+	if ok, err := store.Acquire(ctx, tenantID, "c", 2, start, time.Minute); err != nil || ok {
+		t.Fatalf("Acquire(c) = %v, %v; expected the limit to be reached", ok, err)
+	}
+
+	// Another tenant has its own slots
+	if ok, err := store.Acquire(ctx, uuid.New(), "c", 2, start, time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(c) for other tenant = %v, %v; expected a slot", ok, err)
+	}
+
+	// A touched slot outlives the timeout; an untouched one is reclaimed
+	later := start.Add(90 * time.Second)
+	if err := store.Touch(ctx, tenantID, "a", start.Add(time.Minute)); err != nil {
+		t.Fatalf("Touch() failed: %v", err)
+	}
+	if ok, err := store.Acquire(ctx, tenantID, "c", 2, later, time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(c) after b timed out = %v, %v; expected a slot", ok, err)
+	}
+	if ok, err := store.Acquire(ctx, tenantID, "d", 2, later, time.Minute); err != nil || ok {
+		t.Fatalf("Acquire(d) = %v, %v; expected touched slot a to still count", ok, err)
+	}
+
+	if err := store.Release(ctx, tenantID, "a"); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if ok, err := store.Acquire(ctx, tenantID, "d", 2, later, time.Minute); err != nil || !ok {
+		t.Errorf("Acquire(d) after release = %v, %v; expected a slot", ok, err)
+	}
+
+	// Touching a released slot does not bring it back
+	if err := store.Touch(ctx, tenantID, "a", later); err != nil {
+		t.Fatalf("Touch() failed: %v", err)
+	}
+	if n := client.ZCard(ctx, encodeSlotKeyPrefix+tenantID.String()).Val(); n != 2 {
+		t.Errorf("expected 2 held slots, got %d", n)
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// lockKeyPrefix is the prefix for lock keys in Redis.
+	lockKeyPrefix = "lock:"
+)
+
+// releaseLockScript deletes a lock only if it still holds the caller's token, so a holder
+// whose lock expired cannot release the lock of the next holder.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLockStore implements LockStore using Redis SET NX with a random token.
+type RedisLockStore struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisLockStore implements LockStore.
+var _ LockStore = (*RedisLockStore)(nil)
+
+// NewRedisLockStore creates a new Redis-backed lock store.
+func NewRedisLockStore(client *redis.Client) *RedisLockStore {
+	return &RedisLockStore{
+		client: client,
+	}
+}
+
+// Acquire atomically takes the lock unless it is already held.
+func (s *RedisLockStore) Acquire(ctx context.Context, name string, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+	acquired, err := s.client.SetNX(ctx, lockKeyPrefix+name, token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("redis acquire lock: %w", err)
+	}
+	if !acquired {
+		return "", false, nil
+	}
+	return token, true, nil
+}
+
+// Release deletes the lock if token still holds it.
+func (s *RedisLockStore) Release(ctx context.Context, name, token string) error {
+	if err := releaseLockScript.Run(ctx, s.client, []string{lockKeyPrefix + name}, token).Err(); err != nil {
+		return fmt.Errorf("redis release lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedisLockStore(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisLockStore(client)
+	ctx := context.Background()
+
+	token, ok, err := store.Acquire(ctx, "reconcile", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	if !ok || token == "" {
+		t.Fatalf("expected first acquire to succeed, got ok=%v token=%q", ok, token)
+	}
+
+	if _, ok, err := store.Acquire(ctx, "reconcile", time.Minute); err != nil || ok {
+		t.Fatalf("expected held lock to be refused, got ok=%v err=%v", ok, err)
+	}
+
+	// A stale token must not free the current holder's lock
+	if err := store.Release(ctx, "reconcile", "stale"); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, ok, _ := store.Acquire(ctx, "reconcile", time.Minute); ok {
+		t.Fatal("expected lock to survive release with a stale token")
+	}
+
+	if err := store.Release(ctx, "reconcile", token); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, ok, err := store.Acquire(ctx, "reconcile", time.Minute); err != nil || !ok {
+		t.Errorf("expected released lock to be acquirable, got ok=%v err=%v", ok, err)
+	}
+
+	ttl := client.TTL(ctx, lockKeyPrefix+"reconcile").Val()
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected TTL within 1m, got %v", ttl)
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// maintenanceKey holds the current maintenance window.
+const maintenanceKey = "maintenance"
+
+// maintenanceJSON is the JSON representation of a MaintenanceWindow in Redis.
+type maintenanceJSON struct {
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// RedisMaintenanceStore implements MaintenanceStore using a single Redis key.
+type RedisMaintenanceStore struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisMaintenanceStore implements MaintenanceStore.
+var _ MaintenanceStore = (*RedisMaintenanceStore)(nil)
+
+// NewRedisMaintenanceStore creates a new Redis-backed maintenance store.
+func NewRedisMaintenanceStore(client *redis.Client) *RedisMaintenanceStore {
+	return &RedisMaintenanceStore{client: client}
+}
+
+// Get retrieves the maintenance window.
+// Returns nil, nil if no window is stored.
+func (s *RedisMaintenanceStore) Get(ctx context.Context) (*model.MaintenanceWindow, error) {
+	data, err := s.client.Get(ctx, maintenanceKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	var v maintenanceJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("deserialize maintenance window: %w", err)
+	}
+
+	window := &model.MaintenanceWindow{Reason: v.Reason}
+	if window.StartsAt, err = time.Parse(time.RFC3339Nano, v.StartsAt); err != nil {
+		return nil, fmt.Errorf("parse starts_at: %w", err)
+	}
+	if v.EndsAt != "" {
+		if window.EndsAt, err = time.Parse(time.RFC3339Nano, v.EndsAt); err != nil {
+			return nil, fmt.Errorf("parse ends_at: %w", err)
+		}
+	}
+	return window, nil
+}
+
+// Set stores the window with a TTL matching its remaining lifetime, so an ended window
+// does not outlive its end. A window without an end is kept until Clear.
+func (s *RedisMaintenanceStore) Set(ctx context.Context, window *model.MaintenanceWindow) error {
+	var ttl time.Duration
+	v := maintenanceJSON{
+		StartsAt: window.StartsAt.Format(time.RFC3339Nano),
+		Reason:   window.Reason,
+	}
+	if !window.EndsAt.IsZero() {
+		ttl = time.Until(window.EndsAt)
+		if ttl <= 0 {
+			return fmt.Errorf("maintenance window already ended")
+		}
+		v.EndsAt = window.EndsAt.Format(time.RFC3339Nano)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("serialize maintenance window: %w", err)
+	}
+
+	if err := s.client.Set(ctx, maintenanceKey, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the maintenance window.
+func (s *RedisMaintenanceStore) Clear(ctx context.Context) error {
+	if err := s.client.Del(ctx, maintenanceKey).Err(); err != nil {
+		return fmt.Errorf("redis del: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestRedisMaintenanceStore_SetGetClear(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisMaintenanceStore(client)
+	ctx := context.Background()
+
+	got, err := store.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected no window, got %+v", got)
+	}
+
+	now := time.Now().Truncate(time.Microsecond)
+	window := &model.MaintenanceWindow{
+		StartsAt: now.Add(time.Minute),
+		EndsAt:   now.Add(time.Hour),
+		Reason:   "database upgrade",
+	}
+	if err := store.Set(ctx, window); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	got, err = store.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected window, got nil")
+	}
+	if !got.StartsAt.Equal(window.StartsAt) || !got.EndsAt.Equal(window.EndsAt) || got.Reason != window.Reason {
+		t.Errorf("got %+v, want %+v", got, window)
+	}
+
+	ttl := client.TTL(ctx, maintenanceKey).Val()
+	if ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected TTL until the window ends, got %v", ttl)
+	}
+
+	if err := store.Clear(ctx); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if got, err := store.Get(ctx); err != nil || got != nil {
+		t.Errorf("after Clear: got (%+v, %v), want (nil, nil)", got, err)
+	}
+}
+
+func TestRedisMaintenanceStore_Set_NoEnd(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisMaintenanceStore(client)
+	ctx := context.Background()
+
+	if err := store.Set(ctx, &model.MaintenanceWindow{StartsAt: time.Now()}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	got, err := store.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil || !got.EndsAt.IsZero() {
+		t.Errorf("expected a window without end, got %+v", got)
+	}
+	if ttl := client.TTL(ctx, maintenanceKey).Val(); ttl != -1 {
+		t.Errorf("expected no TTL, got %v", ttl)
+	}
+}
+
+func TestRedisMaintenanceStore_Set_Ended(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisMaintenanceStore(client)
+
+	window := &model.MaintenanceWindow{
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(-time.Minute),
+	}
+	if err := store.Set(context.Background(), window); err == nil {
+		t.Error("expected error when storing an ended window")
+	}
+}
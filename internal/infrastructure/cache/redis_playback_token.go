@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// playbackTokenKeyPrefix is the prefix for playback token keys in Redis.
+	playbackTokenKeyPrefix = "playback_token:"
+	// playbackRevokedVideoKeyPrefix is the prefix for per-video revocation markers.
+	playbackRevokedVideoKeyPrefix = "playback_revoked:video:"
+	// playbackRevokedUserKeyPrefix is the prefix for per-user revocation markers.
+	playbackRevokedUserKeyPrefix = "playback_revoked:user:"
+)
+
+// playbackTokenJSON is the JSON representation of a PlaybackToken in Redis.
+type playbackTokenJSON struct {
+	VideoID   string `json:"video_id"`
+	UserID    string `json:"user_id"`
+	IssuedAt  string `json:"issued_at"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// RedisPlaybackTokenStore implements PlaybackTokenStore using Redis.
+type RedisPlaybackTokenStore struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisPlaybackTokenStore implements PlaybackTokenStore.
+var _ PlaybackTokenStore = (*RedisPlaybackTokenStore)(nil)
+
+// NewRedisPlaybackTokenStore creates a new Redis-backed playback token store.
+func NewRedisPlaybackTokenStore(client *redis.Client) *RedisPlaybackTokenStore {
+	return &RedisPlaybackTokenStore{
+		client: client,
+	}
+}
+
+// Save stores a token with a TTL matching its remaining lifetime.
+func (s *RedisPlaybackTokenStore) Save(ctx context.Context, token *model.PlaybackToken) error {
+	ttl := time.Until(token.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("token already expired")
+	}
+
+	data, err := json.Marshal(playbackTokenJSON{
+		VideoID:   token.VideoID.String(),
+		UserID:    token.UserID.String(),
+		IssuedAt:  token.IssuedAt.Format(time.RFC3339Nano),
+		ExpiresAt: token.ExpiresAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("serialize token: %w", err)
+	}
+
+	if err := s.client.Set(ctx, playbackTokenKeyPrefix+token.Token, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a token from Redis.
+// Returns nil, nil if the token does not exist.
+func (s *RedisPlaybackTokenStore) Get(ctx context.Context, token string) (*model.PlaybackToken, error) {
+	data, err := s.client.Get(ctx, playbackTokenKeyPrefix+token).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	var v playbackTokenJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("deserialize token: %w", err)
+	}
+
+	videoID, err := uuid.Parse(v.VideoID)
+	if err != nil {
+		return nil, fmt.Errorf("parse video ID: %w", err)
+	}
+
+	userID, err := uuid.Parse(v.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parse user ID: %w", err)
+	}
+
+	issuedAt, err := time.Parse(time.RFC3339Nano, v.IssuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse issued_at: %w", err)
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339Nano, v.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse expires_at: %w", err)
+	}
+
+	return &model.PlaybackToken{
+		Token:     token,
+		VideoID:   videoID,
+		UserID:    userID,
+		IssuedAt:  issuedAt,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Delete removes a single token from Redis.
+func (s *RedisPlaybackTokenStore) Delete(ctx context.Context, token string) error {
+	if err := s.client.Del(ctx, playbackTokenKeyPrefix+token).Err(); err != nil {
+		return fmt.Errorf("redis del: %w", err)
+	}
+	return nil
+}
+
+// RevokeVideo records a revocation marker for all tokens of a video.
+func (s *RedisPlaybackTokenStore) RevokeVideo(ctx context.Context, videoID uuid.UUID, at time.Time, ttl time.Duration) error {
+	return s.setRevokedAt(ctx, playbackRevokedVideoKeyPrefix+videoID.String(), at, ttl)
+}
+
+// RevokeUser records a revocation marker for all tokens of a user.
+func (s *RedisPlaybackTokenStore) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time, ttl time.Duration) error {
+	return s.setRevokedAt(ctx, playbackRevokedUserKeyPrefix+userID.String(), at, ttl)
+}
+
+// RevokedAt fetches both revocation markers in a single round trip.
+func (s *RedisPlaybackTokenStore) RevokedAt(ctx context.Context, videoID, userID uuid.UUID) (time.Time, time.Time, error) {
+	values, err := s.client.MGet(ctx,
+		playbackRevokedVideoKeyPrefix+videoID.String(),
+		playbackRevokedUserKeyPrefix+userID.String(),
+	).Result()
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("redis mget: %w", err)
+	}
+
+	videoRevokedAt, err := parseRevokedAt(values[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("parse video revocation: %w", err)
+	}
+
+	userRevokedAt, err := parseRevokedAt(values[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("parse user revocation: %w", err)
+	}
+
+	return videoRevokedAt, userRevokedAt, nil
+}
+
+// setRevokedAt stores a revocation timestamp in Unix nanoseconds.
+func (s *RedisPlaybackTokenStore) setRevokedAt(ctx context.Context, key string, at time.Time, ttl time.Duration) error {
+	if err := s.client.Set(ctx, key, at.UnixNano(), ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// parseRevokedAt converts an MGET value into a timestamp.
+// A nil value (missing key) yields the zero time.
+func parseRevokedAt(v any) (time.Time, error) {
+	if v == nil {
+		return time.Time{}, nil
+	}
+
+	str, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected value type %T", v)
+	}
+
+	nanos, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, nanos), nil
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestRedisPlaybackTokenStore_SaveAndGet(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisPlaybackTokenStore(client)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Microsecond)
+	token := &model.PlaybackToken{
+		Token:     "opaque-token",
+		VideoID:   uuid.New(),
+		UserID:    uuid.New(),
+		IssuedAt:  now,
+		ExpiresAt: now.Add(5 * time.Minute),
+	}
+
+	if err := store.Save(ctx, token); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, token.Token)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected token, got nil")
+	}
+
+	if got.VideoID != token.VideoID {
+		t.Errorf("VideoID: got %v, want %v", got.VideoID, token.VideoID)
+	}
+	if got.UserID != token.UserID {
+		t.Errorf("UserID: got %v, want %v", got.UserID, token.UserID)
+	}
+	if !got.IssuedAt.Equal(token.IssuedAt) {
+		t.Errorf("IssuedAt: got %v, want %v", got.IssuedAt, token.IssuedAt)
+	}
+	if !got.ExpiresAt.Equal(token.ExpiresAt) {
+		t.Errorf("ExpiresAt: got %v, want %v", got.ExpiresAt, token.ExpiresAt)
+	}
+
+	ttl := client.TTL(ctx, playbackTokenKeyPrefix+token.Token).Val()
+	if ttl <= 0 || ttl > 5*time.Minute {
+		t.Errorf("expected TTL within token lifetime, got %v", ttl)
+	}
+}
+
+func TestRedisPlaybackTokenStore_Save_Expired(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisPlaybackTokenStore(client)
+
+	token := &model.PlaybackToken{
+		Token:     "expired-token",
+		VideoID:   uuid.New(),
+		UserID:    uuid.New(),
+		IssuedAt:  time.Now().Add(-time.Hour),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	if err := store.Save(context.Background(), token); err == nil {
+		t.Error("expected error when saving an expired token")
+	}
+}
+
+func TestRedisPlaybackTokenStore_Get_Missing(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisPlaybackTokenStore(client)
+
+	got, err := store.Get(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("expected nil error for missing token, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected nil token, got %+v", got)
+	}
+}
+
+func TestRedisPlaybackTokenStore_Delete(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisPlaybackTokenStore(client)
+	ctx := context.Background()
+
+	token := &model.PlaybackToken{
+		Token:     "to-delete",
+		VideoID:   uuid.New(),
+		UserID:    uuid.New(),
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := store.Save(ctx, token); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if err := store.Delete(ctx, token.Token); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, token.Token)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got != nil {
+		t.Error("expected token to be deleted")
+	}
+}
+
+func TestRedisPlaybackTokenStore_RevokedAt(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisPlaybackTokenStore(client)
+	ctx := context.Background()
+
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	videoRevokedAt, userRevokedAt, err := store.RevokedAt(ctx, videoID, userID)
+	if err != nil {
+		t.Fatalf("RevokedAt failed: %v", err)
+	}
+	if !videoRevokedAt.IsZero() || !userRevokedAt.IsZero() {
+		t.Errorf("expected zero times without markers, got %v / %v", videoRevokedAt, userRevokedAt)
+	}
+
+	at := time.Now()
+	if err := store.RevokeVideo(ctx, videoID, at, time.Minute); err != nil {
+		t.Fatalf("RevokeVideo failed: %v", err)
+	}
+	if err := store.RevokeUser(ctx, userID, at.Add(time.Second), time.Minute); err != nil {
+		t.Fatalf("RevokeUser failed: %v", err)
+	}
+
+	videoRevokedAt, userRevokedAt, err = store.RevokedAt(ctx, videoID, userID)
+	if err != nil {
+		t.Fatalf("RevokedAt failed: %v", err)
+	}
+	if !videoRevokedAt.Equal(at) {
+		t.Errorf("video revoked at: got %v, want %v", videoRevokedAt, at)
+	}
+	if !userRevokedAt.Equal(at.Add(time.Second)) {
+		t.Errorf("user revoked at: got %v, want %v", userRevokedAt, at.Add(time.Second))
+	}
+}
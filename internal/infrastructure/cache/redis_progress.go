@@ -0,0 +1,238 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// progressKeyPrefix is the prefix for playback progress keys in Redis.
+	// Keys are "playback_progress:{user_id}:{video_id}".
+	progressKeyPrefix = "playback_progress:"
+	// progressDirtyKey is the set of "{user_id}:{video_id}" members awaiting flush.
+	progressDirtyKey = "playback_progress:dirty"
+)
+
+// progressJSON is the JSON representation of a PlaybackProgress in Redis.
+type progressJSON struct {
+	PositionMs int64  `json:"position_ms"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// RedisProgressStore implements ProgressStore using Redis.
+type RedisProgressStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// Compile-time verification that RedisProgressStore implements ProgressStore.
+var _ ProgressStore = (*RedisProgressStore)(nil)
+
+// NewRedisProgressStore creates a new Redis-backed progress store.
+// The TTL must comfortably exceed the flush interval, otherwise buffered
+// heartbeats can expire before they are persisted.
+func NewRedisProgressStore(client *redis.Client, ttl time.Duration) *RedisProgressStore {
+	return &RedisProgressStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Save stores progress and adds it to the dirty set in one round trip.
+func (s *RedisProgressStore) Save(ctx context.Context, progress *model.PlaybackProgress) error {
+	data, err := marshalProgress(progress)
+	if err != nil {
+		return err
+	}
+
+	member := progressMember(progress.UserID, progress.VideoID)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, progressKeyPrefix+member, data, s.ttl)
+		pipe.SAdd(ctx, progressDirtyKey, member)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis save progress: %w", err)
+	}
+
+	return nil
+}
+
+// Prime stores progress without marking it dirty.
+// Uses SET NX so a concurrent heartbeat is never overwritten by an older database value.
+func (s *RedisProgressStore) Prime(ctx context.Context, progress *model.PlaybackProgress) error {
+	data, err := marshalProgress(progress)
+	if err != nil {
+		return err
+	}
+
+	key := progressKeyPrefix + progressMember(progress.UserID, progress.VideoID)
+	if err := s.client.SetNX(ctx, key, data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("redis prime progress: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves buffered progress from Redis.
+// Returns nil, nil if the key does not exist.
+func (s *RedisProgressStore) Get(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+	data, err := s.client.Get(ctx, progressKeyPrefix+progressMember(userID, videoID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("redis get progress: %w", err)
+	}
+
+	return unmarshalProgress(userID, videoID, data)
+}
+
+// PopDirty pops up to n members from the dirty set and loads their current values.
+// Members whose keys have already expired are dropped; the set is drained once a pop
+// comes back short.
+func (s *RedisProgressStore) PopDirty(ctx context.Context, n int) ([]*model.PlaybackProgress, bool, error) {
+	members, err := s.client.SPopN(ctx, progressDirtyKey, int64(n)).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis pop dirty progress: %w", err)
+	}
+	drained := len(members) < n
+	if len(members) == 0 {
+		return nil, drained, nil
+	}
+
+	keys := make([]string, len(members))
+	for i, member := range members {
+		keys[i] = progressKeyPrefix + member
+	}
+
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		// Put the members back so the next flush retries them
+		_ = s.client.SAdd(ctx, progressDirtyKey, toAny(members)...).Err()
+		return nil, false, fmt.Errorf("redis mget progress: %w", err)
+	}
+
+	result := make([]*model.PlaybackProgress, 0, len(members))
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		userID, videoID, err := parseProgressMember(members[i])
+		if err != nil {
+			continue
+		}
+
+		progress, err := unmarshalProgress(userID, videoID, []byte(raw))
+		if err != nil {
+			continue
+		}
+		result = append(result, progress)
+	}
+
+	return result, drained, nil
+}
+
+// MarkDirty adds records back to the dirty set.
+func (s *RedisProgressStore) MarkDirty(ctx context.Context, progress []*model.PlaybackProgress) error {
+	if len(progress) == 0 {
+		return nil
+	}
+
+	members := make([]any, len(progress))
+	for i, p := range progress {
+		members[i] = progressMember(p.UserID, p.VideoID)
+	}
+
+	if err := s.client.SAdd(ctx, progressDirtyKey, members...).Err(); err != nil {
+		return fmt.Errorf("redis mark dirty progress: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the progress key and its dirty-set member in one round trip.
+func (s *RedisProgressStore) Delete(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+	member := progressMember(userID, videoID)
+	var deleted *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		deleted = pipe.Del(ctx, progressKeyPrefix+member)
+		pipe.SRem(ctx, progressDirtyKey, member)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("redis delete progress: %w", err)
+	}
+
+	return deleted.Val() > 0, nil
+}
+
+func progressMember(userID, videoID uuid.UUID) string {
+	return userID.String() + ":" + videoID.String()
+}
+
+func parseProgressMember(member string) (uuid.UUID, uuid.UUID, error) {
+	userPart, videoPart, ok := strings.Cut(member, ":")
+	if !ok {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("malformed progress member %q", member)
+	}
+
+	userID, err := uuid.Parse(userPart)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("parse user ID: %w", err)
+	}
+	videoID, err := uuid.Parse(videoPart)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("parse video ID: %w", err)
+	}
+
+	return userID, videoID, nil
+}
+
+func marshalProgress(progress *model.PlaybackProgress) ([]byte, error) {
+	data, err := json.Marshal(progressJSON{
+		PositionMs: progress.Position.Milliseconds(),
+		UpdatedAt:  progress.UpdatedAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("serialize progress: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalProgress(userID, videoID uuid.UUID, data []byte) (*model.PlaybackProgress, error) {
+	var v progressJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("deserialize progress: %w", err)
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339Nano, v.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse updated_at: %w", err)
+	}
+
+	return &model.PlaybackProgress{
+		UserID:    userID,
+		VideoID:   videoID,
+		Position:  time.Duration(v.PositionMs) * time.Millisecond,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func toAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
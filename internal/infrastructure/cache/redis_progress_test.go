@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestRedisProgressStore_SaveAndGet(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisProgressStore(client, time.Hour)
+	ctx := context.Background()
+
+	progress := &model.PlaybackProgress{
+		UserID:    uuid.New(),
+		VideoID:   uuid.New(),
+		Position:  95 * time.Second,
+		UpdatedAt: time.Now().Truncate(time.Millisecond),
+	}
+
+	if err := store.Save(ctx, progress); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, progress.UserID, progress.VideoID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected progress, got nil")
+	}
+	if got.Position != progress.Position {
+		t.Errorf("Position: got %v, want %v", got.Position, progress.Position)
+	}
+	if !got.UpdatedAt.Equal(progress.UpdatedAt) {
+		t.Errorf("UpdatedAt: got %v, want %v", got.UpdatedAt, progress.UpdatedAt)
+	}
+}
+
+func TestRedisProgressStore_Get_NotFound(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisProgressStore(client, time.Hour)
+
+	got, err := store.Get(context.Background(), uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected nil, got %+v", got)
+	}
+}
+
+func TestRedisProgressStore_PopDirty(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisProgressStore(client, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		progress := &model.PlaybackProgress{
+			UserID:    uuid.New(),
+			VideoID:   uuid.New(),
+			Position:  time.Duration(i) * time.Second,
+			UpdatedAt: time.Now(),
+		}
+		if err := store.Save(ctx, progress); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	first, drained, err := store.PopDirty(ctx, 2)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(first) != 2 || drained {
+		t.Fatalf("expected 2 records and more to come, got %d, drained %v", len(first), drained)
+	}
+
+	second, drained, err := store.PopDirty(ctx, 2)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(second) != 1 || !drained {
+		t.Fatalf("expected the last record, got %d, drained %v", len(second), drained)
+	}
+
+	empty, drained, err := store.PopDirty(ctx, 2)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(empty) != 0 || !drained {
+		t.Errorf("expected no records, got %d, drained %v", len(empty), drained)
+	}
+
+	// Re-queued records are returned by the next pop
+	if err := store.MarkDirty(ctx, second); err != nil {
+		t.Fatalf("MarkDirty failed: %v", err)
+	}
+	again, _, err := store.PopDirty(ctx, 10)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(again) != 1 || again[0].UserID != second[0].UserID {
+		t.Errorf("expected re-queued record, got %+v", again)
+	}
+}
+
+func TestRedisProgressStore_PopDirtyDropsExpired(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisProgressStore(client, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		progress := &model.PlaybackProgress{UserID: uuid.New(), VideoID: uuid.New(), UpdatedAt: time.Now()}
+		if err := store.Save(ctx, progress); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		// Expire the values but keep them dirty
+		client.Del(ctx, progressKeyPrefix+progressMember(progress.UserID, progress.VideoID))
+	}
+
+	// A batch emptied by expiry is not the end of the dirty set
+	batch, drained, err := store.PopDirty(ctx, 2)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(batch) != 0 || drained {
+		t.Errorf("expected an empty batch with more to come, got %d, drained %v", len(batch), drained)
+	}
+
+	if _, drained, err := store.PopDirty(ctx, 2); err != nil || !drained {
+		t.Errorf("PopDirty = %v, %v; expected the set drained", drained, err)
+	}
+}
+
+func TestRedisProgressStore_Prime(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisProgressStore(client, time.Hour)
+	ctx := context.Background()
+	userID, videoID := uuid.New(), uuid.New()
+
+	fresh := &model.PlaybackProgress{UserID: userID, VideoID: videoID, Position: 60 * time.Second, UpdatedAt: time.Now()}
+	if err := store.Save(ctx, fresh); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, _, err := store.PopDirty(ctx, 10); err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+
+	// Priming must not overwrite a newer buffered heartbeat
+	stale := &model.PlaybackProgress{UserID: userID, VideoID: videoID, Position: 10 * time.Second, UpdatedAt: time.Now()}
+	if err := store.Prime(ctx, stale); err != nil {
+		t.Fatalf("Prime failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, userID, videoID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Position != fresh.Position {
+		t.Errorf("Position: got %v, want %v", got.Position, fresh.Position)
+	}
+
+	// Primed values are not flushed
+	dirty, _, err := store.PopDirty(ctx, 10)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(dirty) != 0 {
+		t.Errorf("expected primed record to stay clean, got %d dirty", len(dirty))
+	}
+}
+
+func TestRedisProgressStore_Delete(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisProgressStore(client, time.Hour)
+	ctx := context.Background()
+
+	progress := &model.PlaybackProgress{UserID: uuid.New(), VideoID: uuid.New(), Position: time.Minute, UpdatedAt: time.Now()}
+	if err := store.Save(ctx, progress); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if deleted, err := store.Delete(ctx, progress.UserID, progress.VideoID); err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v; expected the buffered progress deleted", deleted, err)
+	}
+
+	got, err := store.Get(ctx, progress.UserID, progress.VideoID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected progress to be deleted, got %+v", got)
+	}
+
+	// A deleted record must not be resurrected by the next flush
+	dirty, _, err := store.PopDirty(ctx, 10)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(dirty) != 0 {
+		t.Errorf("expected no dirty records, got %d", len(dirty))
+	}
+
+	if deleted, err := store.Delete(ctx, progress.UserID, progress.VideoID); err != nil || deleted {
+		t.Errorf("second Delete = %v, %v; expected nothing buffered", deleted, err)
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// rateLimitKeyPrefix is the prefix for token bucket hashes in Redis.
+	// Fields are tokens (fractional) and ts, the Unix milliseconds they were counted at.
+	rateLimitKeyPrefix = "rate_limit:"
+)
+
+// takeTokenScript refills a bucket for the time since it was last counted, then takes a
+// token if one is left. Running it as a script makes the read-refill-take atomic, so
+// concurrent requests cannot spend the same token. An idle bucket expires once it would
+// have refilled completely, as it is then the same as a new one.
+//
+// KEYS[1] = bucket hash, ARGV = now_ms, rate (tokens per second), burst
+// Returns {taken, wait_ms}
+var takeTokenScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+	ts = now
+end
+local taken = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	taken = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(ts))
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate))
+return {taken, wait}
+`)
+
+// RedisRateLimitStore implements RateLimitStore as token buckets in Redis hashes.
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisRateLimitStore implements RateLimitStore.
+var _ RateLimitStore = (*RedisRateLimitStore)(nil)
+
+// NewRedisRateLimitStore creates a new Redis-backed rate limit store.
+func NewRedisRateLimitStore(client *redis.Client) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		client: client,
+	}
+}
+
+// Take atomically refills the bucket and takes a token from it if one is left.
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int, now time.Time) (bool, time.Duration, error) {
+	if rate <= 0 || burst < 1 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return false, 0, fmt.Errorf("invalid token bucket: rate %v, burst %d", rate, burst)
+	}
+
+	result, err := takeTokenScript.Run(ctx, s.client,
+		[]string{rateLimitKeyPrefix + key},
+		now.UnixMilli(),
+		strconv.FormatFloat(rate, 'f', -1, 64),
+		burst,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("redis take token: %w", err)
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("redis take token: unexpected reply %v", result)
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
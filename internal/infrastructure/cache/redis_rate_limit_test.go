@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedisRateLimitStore(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisRateLimitStore(client)
+	ctx := context.Background()
+	start := time.Now()
+
+	// A new bucket starts full
+	for i := 0; i < 3; i++ {
+		if ok, _, err := store.Take(ctx, "user:a", 2, 3, start); err != nil || !ok {
+			t.Fatalf("Take() #%d = %v, %v; expected a token", i, ok, err)
+		}
+	}
+	ok, wait, err := store.Take(ctx, "user:a", 2, 3, start)
+	if err != nil || ok {
+		t.Fatalf("Take() on empty bucket = %v, %v; expected no token", ok, err)
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("wait = %v, want 500ms at 2 tokens per second", wait)
+	}
+
+	// Another key has its own bucket
+	if ok, _, err := store.Take(ctx, "user:b", 2, 3, start); err != nil || !ok {
+		t.Fatalf("Take() for other key = %v, %v; expected a token", ok, err)
+	}
+
+	// Tokens refill at the rate, up to the burst
+	if ok, _, err := store.Take(ctx, "user:a", 2, 3, start.Add(500*time.Millisecond)); err != nil || !ok {
+		t.Fatalf("Take() after refill = %v, %v; expected a token", ok, err)
+	}
+	if ok, wait, err := store.Take(ctx, "user:a", 2, 3, start.Add(750*time.Millisecond)); err != nil || ok || wait != 250*time.Millisecond {
+		t.Fatalf("Take() on half a token = %v, %v, %v; expected a 250ms wait", ok, wait, err)
+	}
+	later := start.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if ok, _, err := store.Take(ctx, "user:a", 2, 3, later); err != nil || !ok {
+			t.Fatalf("Take() #%d after idle = %v, %v; expected a token", i, ok, err)
+		}
+	}
+	if ok, _, err := store.Take(ctx, "user:a", 2, 3, later); err != nil || ok {
+		t.Errorf("Take() = %v, %v; expected the refill to stop at the burst", ok, err)
+	}
+
+	// Idle buckets expire once they would be full again
+	if ttl := client.PTTL(ctx, rateLimitKeyPrefix+"user:a").Val(); ttl <= 0 || ttl > 1500*time.Millisecond {
+		t.Errorf("bucket TTL = %v, want at most 1.5s", ttl)
+	}
+
+	if _, _, err := store.Take(ctx, "user:a", 0, 3, later); err == nil {
+		t.Error("Take() with zero rate: expected an error")
+	}
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// streamSessionKeyPrefix is the prefix for per-user session sorted sets in Redis.
+	// Members are session IDs; scores are last-seen Unix milliseconds.
+	streamSessionKeyPrefix = "playback_sessions:"
+)
+
+// acquireSessionScript prunes stale sessions, then admits the session if it already
+// exists or the user is under the limit. Running it as a script makes the
+// check-then-add atomic, so concurrent starts cannot overshoot the limit.
+//
+// KEYS[1] = session set, ARGV = now_ms, stale_before_ms, limit, session_id, ttl_ms
+var acquireSessionScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[2])
+local exists = redis.call("ZSCORE", KEYS[1], ARGV[4])
+if not exists and redis.call("ZCARD", KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call("ZADD", KEYS[1], ARGV[1], ARGV[4])
+redis.call("PEXPIRE", KEYS[1], ARGV[5])
+return 1
+`)
+
+// RedisStreamSessionStore implements StreamSessionStore using Redis sorted sets.
+type RedisStreamSessionStore struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisStreamSessionStore implements StreamSessionStore.
+var _ StreamSessionStore = (*RedisStreamSessionStore)(nil)
+
+// NewRedisStreamSessionStore creates a new Redis-backed stream session store.
+func NewRedisStreamSessionStore(client *redis.Client) *RedisStreamSessionStore {
+	return &RedisStreamSessionStore{
+		client: client,
+	}
+}
+
+// Acquire atomically admits a session if the user is under the limit.
+func (s *RedisStreamSessionStore) Acquire(ctx context.Context, userID uuid.UUID, sessionID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+	admitted, err := acquireSessionScript.Run(ctx, s.client,
+		[]string{s.buildKey(userID)},
+		now.UnixMilli(),
+		now.Add(-timeout).UnixMilli(),
+		limit,
+		sessionID,
+		timeout.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("redis acquire session: %w", err)
+	}
+	return admitted == 1, nil
+}
+
+// Touch refreshes the last-seen score of an existing session and extends the
+// set's TTL, so a long stream kept alive by heartbeats outlives the initial expiry.
+func (s *RedisStreamSessionStore) Touch(ctx context.Context, userID uuid.UUID, sessionID string, now time.Time, timeout time.Duration) error {
+	key := s.buildKey(userID)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAddXX(ctx, key, redis.Z{
+			Score:  float64(now.UnixMilli()),
+			Member: sessionID,
+		})
+		pipe.PExpire(ctx, key, timeout)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis touch session: %w", err)
+	}
+	return nil
+}
+
+// Release removes a session from the user's active set.
+func (s *RedisStreamSessionStore) Release(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	if err := s.client.ZRem(ctx, s.buildKey(userID), sessionID).Err(); err != nil {
+		return fmt.Errorf("redis release session: %w", err)
+	}
+	return nil
+}
+
+// ReleaseAll drops the user's entire session set.
+func (s *RedisStreamSessionStore) ReleaseAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.client.Del(ctx, s.buildKey(userID)).Err(); err != nil {
+		return fmt.Errorf("redis release sessions: %w", err)
+	}
+	return nil
+}
+
+// buildKey constructs the Redis key for a user's session set.
+func (s *RedisStreamSessionStore) buildKey(userID uuid.UUID) string {
+	return streamSessionKeyPrefix + userID.String()
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRedisStreamSessionStore_Acquire_EnforcesLimit(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisStreamSessionStore(client)
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	for _, session := range []string{"s1", "s2"} {
+		ok, err := store.Acquire(ctx, userID, session, 2, now, time.Minute)
+		if err != nil {
+			t.Fatalf("Acquire(%s) failed: %v", session, err)
+		}
+		if !ok {
+			t.Fatalf("Acquire(%s) expected to be admitted", session)
+		}
+	}
+
+	ok, err := store.Acquire(ctx, userID, "s3", 2, now, time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire(s3) failed: %v", err)
+	}
+	if ok {
+		t.Error("expected third session to be rejected")
+	}
+
+	// Re-acquiring an existing session is always allowed
+	ok, err = store.Acquire(ctx, userID, "s1", 2, now, time.Minute)
+	if err != nil {
+		t.Fatalf("re-Acquire(s1) failed: %v", err)
+	}
+	if !ok {
+		t.Error("expected existing session to be re-admitted")
+	}
+
+	// Another user is unaffected
+	ok, err = store.Acquire(ctx, uuid.New(), "s3", 2, now, time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire for other user failed: %v", err)
+	}
+	if !ok {
+		t.Error("expected other user's session to be admitted")
+	}
+}
+
+func TestRedisStreamSessionStore_Acquire_PrunesStaleSessions(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisStreamSessionStore(client)
+	ctx := context.Background()
+	userID := uuid.New()
+	start := time.Now()
+
+	if ok, err := store.Acquire(ctx, userID, "old", 1, start, time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(old) = %v, %v", ok, err)
+	}
+
+	// Within the timeout the slot is still taken
+	if ok, err := store.Acquire(ctx, userID, "new", 1, start.Add(30*time.Second), time.Minute); err != nil || ok {
+		t.Fatalf("Acquire(new) before timeout = %v, %v; expected rejection", ok, err)
+	}
+
+	// After the timeout the stale session no longer counts
+	if ok, err := store.Acquire(ctx, userID, "new", 1, start.Add(2*time.Minute), time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(new) after timeout = %v, %v; expected admission", ok, err)
+	}
+}
+
+func TestRedisStreamSessionStore_TouchKeepsSessionAlive(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisStreamSessionStore(client)
+	ctx := context.Background()
+	userID := uuid.New()
+	start := time.Now()
+
+	if ok, err := store.Acquire(ctx, userID, "s1", 1, start, time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(s1) = %v, %v", ok, err)
+	}
+
+	key := streamSessionKeyPrefix + userID.String()
+	client.PExpire(ctx, key, time.Second)
+
+	if err := store.Touch(ctx, userID, "s1", start.Add(50*time.Second), time.Minute); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+
+	// The heartbeat extends the set's TTL back to the session timeout
+	if ttl := client.PTTL(ctx, key).Val(); ttl <= time.Second {
+		t.Errorf("expected TTL to be refreshed, got %v", ttl)
+	}
+
+	// s1 was refreshed, so it still holds the only slot
+	if ok, err := store.Acquire(ctx, userID, "s2", 1, start.Add(90*time.Second), time.Minute); err != nil || ok {
+		t.Fatalf("Acquire(s2) = %v, %v; expected rejection", ok, err)
+	}
+
+	// Touching an unknown session must not create it
+	if err := store.Touch(ctx, userID, "ghost", start, time.Minute); err != nil {
+		t.Fatalf("Touch(ghost) failed: %v", err)
+	}
+	if n := client.ZCard(ctx, key).Val(); n != 1 {
+		t.Errorf("expected 1 session, got %d", n)
+	}
+}
+
+func TestRedisStreamSessionStore_Release(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisStreamSessionStore(client)
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	if ok, err := store.Acquire(ctx, userID, "s1", 1, now, time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(s1) = %v, %v", ok, err)
+	}
+
+	if err := store.Release(ctx, userID, "s1"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	if ok, err := store.Acquire(ctx, userID, "s2", 1, now, time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(s2) after release = %v, %v; expected admission", ok, err)
+	}
+}
+
+func TestRedisStreamSessionStore_ReleaseAll(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisStreamSessionStore(client)
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	for _, session := range []string{"s1", "s2"} {
+		if ok, err := store.Acquire(ctx, userID, session, 2, now, time.Minute); err != nil || !ok {
+			t.Fatalf("Acquire(%s) = %v, %v", session, ok, err)
+		}
+	}
+
+	if err := store.ReleaseAll(ctx, userID); err != nil {
+		t.Fatalf("ReleaseAll failed: %v", err)
+	}
+
+	if n := client.ZCard(ctx, streamSessionKeyPrefix+userID.String()).Val(); n != 0 {
+		t.Errorf("expected no sessions, got %d", n)
+	}
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
@@ -35,22 +36,44 @@ func TestRedisVideoCache_Get_CacheHit(t *testing.T) {
 	client, cleanup := setupTestRedis(t)
 	defer cleanup()
 
-	cache := NewRedisVideoCache(client)
+	cache := NewRedisVideoCache(client, 0)
 	ctx := context.Background()
 
 	video := &model.Video{
-		ID:          uuid.New(),
-		UserID:      uuid.New(),
-		Title:       "Test Video",
-		Status:      model.StatusReady,
-		OriginalURL: "originals/test.mp4",
-		HLSURL:      "hls/test/master.m3u8",
-		CreatedAt:   time.Now().Truncate(time.Microsecond),
-		UpdatedAt:   time.Now().Truncate(time.Microsecond),
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		Title:           "Test Video",
+		Description:     "A walk through the park.",
+		Tags:            []string{"outdoors", "walk"},
+		Status:          model.StatusReady,
+		Visibility:      model.VisibilityPrivate,
+		OriginalURL:     "originals/test.mp4",
+		HLSURL:          "hls/test/master.m3u8",
+		PreviewSeconds:  60,
+		PreviewURL:      "previews/test/playlist.m3u8",
+		ThumbnailPrefix: "hls/test/v2/thumbnails/",
+		OutputVersion:   2,
+		SortableID:      "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		ShareSlug:       "a1b2c3d4",
+		TitleSlug:       "test-video",
+		StorageKey:      "9f86d081884c7d659a2feaa0c55ad015",
+		Source: model.SourceMetadata{
+			Duration:  90500 * time.Millisecond,
+			Width:     1920,
+			Height:    1080,
+			Codec:     "h264",
+			Bitrate:   5000000,
+			FrameRate: 29.97,
+		},
+		ExpiresAt:     time.Now().Add(24 * time.Hour).Truncate(time.Microsecond),
+		FailureCode:   model.FailureCodeEncodeFailed,
+		FailureReason: "ffmpeg execution failed: exit status 1",
+		CreatedAt:     time.Now().Truncate(time.Microsecond),
+		UpdatedAt:     time.Now().Truncate(time.Microsecond),
 	}
 
 	// Set the video in cache
-	err := cache.Set(ctx, video, 5*time.Minute)
+	err := cache.Set(ctx, video, 5*time.Minute, 0, 0)
 	if err != nil {
 		t.Fatalf("Set failed: %v", err)
 	}
@@ -84,13 +107,55 @@ func TestRedisVideoCache_Get_CacheHit(t *testing.T) {
 	if got.HLSURL != video.HLSURL {
 		t.Errorf("HLSURL = %v, want %v", got.HLSURL, video.HLSURL)
 	}
+	if got.ThumbnailPrefix != video.ThumbnailPrefix {
+		t.Errorf("ThumbnailPrefix = %v, want %v", got.ThumbnailPrefix, video.ThumbnailPrefix)
+	}
+	if got.PreviewSeconds != video.PreviewSeconds {
+		t.Errorf("PreviewSeconds = %v, want %v", got.PreviewSeconds, video.PreviewSeconds)
+	}
+	if got.PreviewURL != video.PreviewURL {
+		t.Errorf("PreviewURL = %v, want %v", got.PreviewURL, video.PreviewURL)
+	}
+	if got.OutputVersion != video.OutputVersion {
+		t.Errorf("OutputVersion = %v, want %v", got.OutputVersion, video.OutputVersion)
+	}
+	if got.SortableID != video.SortableID {
+		t.Errorf("SortableID = %v, want %v", got.SortableID, video.SortableID)
+	}
+	if got.ShareSlug != video.ShareSlug {
+		t.Errorf("ShareSlug = %v, want %v", got.ShareSlug, video.ShareSlug)
+	}
+	if got.TitleSlug != video.TitleSlug {
+		t.Errorf("TitleSlug = %v, want %v", got.TitleSlug, video.TitleSlug)
+	}
+	if got.Visibility != video.Visibility {
+		t.Errorf("Visibility = %v, want %v", got.Visibility, video.Visibility)
+	}
+	if got.Description != video.Description {
+		t.Errorf("Description = %v, want %v", got.Description, video.Description)
+	}
+	if !reflect.DeepEqual(got.Tags, video.Tags) {
+		t.Errorf("Tags = %v, want %v", got.Tags, video.Tags)
+	}
+	if got.StorageKey != video.StorageKey {
+		t.Errorf("StorageKey = %v, want %v", got.StorageKey, video.StorageKey)
+	}
+	if got.Source != video.Source {
+		t.Errorf("Source = %+v, want %+v", got.Source, video.Source)
+	}
+	if !got.ExpiresAt.Equal(video.ExpiresAt) {
+		t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, video.ExpiresAt)
+	}
+	if got.FailureCode != video.FailureCode || got.FailureReason != video.FailureReason {
+		t.Errorf("failure = %q, %q; want %q, %q", got.FailureCode, got.FailureReason, video.FailureCode, video.FailureReason)
+	}
 }
 
 func TestRedisVideoCache_Get_CacheMiss(t *testing.T) {
 	client, cleanup := setupTestRedis(t)
 	defer cleanup()
 
-	cache := NewRedisVideoCache(client)
+	cache := NewRedisVideoCache(client, 0)
 	ctx := context.Background()
 
 	// Try to get a non-existent video
@@ -104,11 +169,155 @@ func TestRedisVideoCache_Get_CacheMiss(t *testing.T) {
 	}
 }
 
+func TestRedisVideoCache_GetStale(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	cache := NewRedisVideoCache(client, 0)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	video := &model.Video{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Title:     "Test Video",
+		Status:    model.StatusReady,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := cache.Set(ctx, video, time.Minute, time.Hour, 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// The key outlives the TTL by the stale TTL
+	if ttl := client.TTL(ctx, cache.buildKey(video.ID)).Val(); ttl != time.Minute+time.Hour {
+		t.Errorf("key TTL = %v, want %v", ttl, time.Minute+time.Hour)
+	}
+
+	if got, err := cache.Get(ctx, video.ID); err != nil || got == nil {
+		t.Fatalf("Get before the TTL = %v, %v, want a hit", got, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got, err := cache.Get(ctx, video.ID); err != nil || got != nil {
+		t.Errorf("Get past the TTL = %v, %v, want a miss", got, err)
+	}
+	got, err := cache.GetStale(ctx, video.ID)
+	if err != nil || got == nil || got.ID != video.ID {
+		t.Errorf("GetStale past the TTL = %v, %v, want the video", got, err)
+	}
+
+	if got, err := cache.GetStale(ctx, uuid.New()); err != nil || got != nil {
+		t.Errorf("GetStale of an unknown video = %v, %v, want a miss", got, err)
+	}
+}
+
+func TestRedisVideoCache_GetEntry(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	cache := NewRedisVideoCache(client, 0)
+	now := time.Now().Truncate(time.Microsecond)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	video := &model.Video{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Title:     "Test Video",
+		Status:    model.StatusReady,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := cache.Set(ctx, video, time.Minute, 0, 1500*time.Microsecond); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	entry, err := cache.GetEntry(ctx, video.ID)
+	if err != nil || entry == nil {
+		t.Fatalf("GetEntry = %v, %v, want a hit", entry, err)
+	}
+	if entry.Video.ID != video.ID {
+		t.Errorf("Video.ID = %v, want %v", entry.Video.ID, video.ID)
+	}
+	if want := now.Add(time.Minute); !entry.FreshUntil.Equal(want) {
+		t.Errorf("FreshUntil = %v, want %v", entry.FreshUntil, want)
+	}
+	if entry.LoadTime != 1500*time.Microsecond {
+		t.Errorf("LoadTime = %v, want %v", entry.LoadTime, 1500*time.Microsecond)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if entry, err := cache.GetEntry(ctx, video.ID); err != nil || entry != nil {
+		t.Errorf("GetEntry past the TTL = %v, %v, want a miss", entry, err)
+	}
+}
+
+func TestRedisVideoCache_TTLJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		jitter  float64
+		random  float64
+		wantTTL time.Duration
+	}{
+		{name: "no jitter", jitter: 0, random: 0.5, wantTTL: 10 * time.Minute},
+		{name: "shortened by a fraction", jitter: 0.2, random: 0.5, wantTTL: 9 * time.Minute},
+		{name: "never below the jitter", jitter: 0.2, random: 1, wantTTL: 8 * time.Minute},
+		{name: "jitter capped at the whole TTL", jitter: 2, random: 0.25, wantTTL: 7*time.Minute + 30*time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, cleanup := setupTestRedis(t)
+			defer cleanup()
+
+			cache := NewRedisVideoCache(client, tt.jitter)
+			now := time.Now().Truncate(time.Microsecond)
+			cache.now = func() time.Time { return now }
+			cache.random = func() float64 { return tt.random }
+			ctx := context.Background()
+
+			video := &model.Video{
+				ID:        uuid.New(),
+				UserID:    uuid.New(),
+				Title:     "Test Video",
+				Status:    model.StatusReady,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := cache.Set(ctx, video, 10*time.Minute, time.Hour, 0); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+
+			entry, err := cache.GetEntry(ctx, video.ID)
+			if err != nil || entry == nil {
+				t.Fatalf("GetEntry = %v, %v, want a hit", entry, err)
+			}
+			if want := now.Add(tt.wantTTL); !entry.FreshUntil.Equal(want) {
+				t.Errorf("FreshUntil = %v, want %v", entry.FreshUntil, want)
+			}
+			// Stale retention is kept in full past the jittered TTL
+			if ttl := client.TTL(ctx, cache.buildKey(video.ID)).Val(); ttl != tt.wantTTL+time.Hour {
+				t.Errorf("key TTL = %v, want %v", ttl, tt.wantTTL+time.Hour)
+			}
+
+			userID := uuid.New()
+			if err := cache.SetFirstPage(ctx, userID, 20, &VideoPage{}, 10*time.Minute); err != nil {
+				t.Fatalf("SetFirstPage failed: %v", err)
+			}
+			if ttl := client.TTL(ctx, cache.buildListKey(userID)).Val(); ttl != tt.wantTTL {
+				t.Errorf("list key TTL = %v, want %v", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
+
 func TestRedisVideoCache_Delete(t *testing.T) {
 	client, cleanup := setupTestRedis(t)
 	defer cleanup()
 
-	cache := NewRedisVideoCache(client)
+	cache := NewRedisVideoCache(client, 0)
 	ctx := context.Background()
 
 	video := &model.Video{
@@ -121,7 +330,7 @@ func TestRedisVideoCache_Delete(t *testing.T) {
 	}
 
 	// Set the video in cache
-	err := cache.Set(ctx, video, 5*time.Minute)
+	err := cache.Set(ctx, video, 5*time.Minute, 0, 0)
 	if err != nil {
 		t.Fatalf("Set failed: %v", err)
 	}
@@ -147,7 +356,7 @@ func TestRedisVideoCache_Delete_NonExistent(t *testing.T) {
 	client, cleanup := setupTestRedis(t)
 	defer cleanup()
 
-	cache := NewRedisVideoCache(client)
+	cache := NewRedisVideoCache(client, 0)
 	ctx := context.Background()
 
 	// Delete non-existent video should not error
@@ -161,7 +370,7 @@ func TestRedisVideoCache_Set_AllStatuses(t *testing.T) {
 	client, cleanup := setupTestRedis(t)
 	defer cleanup()
 
-	cache := NewRedisVideoCache(client)
+	cache := NewRedisVideoCache(client, 0)
 	ctx := context.Background()
 
 	statuses := []model.Status{
@@ -182,7 +391,7 @@ func TestRedisVideoCache_Set_AllStatuses(t *testing.T) {
 				UpdatedAt: time.Now(),
 			}
 
-			err := cache.Set(ctx, video, 5*time.Minute)
+			err := cache.Set(ctx, video, 5*time.Minute, 0, 0)
 			if err != nil {
 				t.Fatalf("Set failed: %v", err)
 			}
@@ -203,7 +412,7 @@ func TestRedisVideoCache_buildKey(t *testing.T) {
 	client, cleanup := setupTestRedis(t)
 	defer cleanup()
 
-	cache := NewRedisVideoCache(client)
+	cache := NewRedisVideoCache(client, 0)
 	videoID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
 
 	key := cache.buildKey(videoID)
@@ -213,3 +422,90 @@ func TestRedisVideoCache_buildKey(t *testing.T) {
 		t.Errorf("buildKey() = %v, want %v", key, expected)
 	}
 }
+
+func TestRedisVideoCache_FirstPage(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	cache := NewRedisVideoCache(client, 0)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	got, err := cache.GetFirstPage(ctx, userID, 20)
+	if err != nil {
+		t.Fatalf("GetFirstPage failed: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected nil for cache miss, got %+v", got)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	page := &VideoPage{
+		Videos: []*model.Video{
+			{ID: uuid.New(), UserID: userID, Title: "Newest", Status: model.StatusReady, HLSURL: "hls/a/master.m3u8", CreatedAt: now, UpdatedAt: now},
+			{ID: uuid.New(), UserID: userID, Title: "Older", Status: model.StatusPendingUpload, CreatedAt: now, UpdatedAt: now},
+		},
+		NextCursor: "next",
+	}
+	if err := cache.SetFirstPage(ctx, userID, 20, page, 5*time.Minute); err != nil {
+		t.Fatalf("SetFirstPage failed: %v", err)
+	}
+
+	got, err = cache.GetFirstPage(ctx, userID, 20)
+	if err != nil {
+		t.Fatalf("GetFirstPage failed: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected cached page, got nil")
+	}
+	if got.NextCursor != "next" {
+		t.Errorf("NextCursor = %q, want %q", got.NextCursor, "next")
+	}
+	if len(got.Videos) != 2 {
+		t.Fatalf("len(Videos) = %d, want 2", len(got.Videos))
+	}
+	if got.Videos[0].ID != page.Videos[0].ID || got.Videos[0].HLSURL != "hls/a/master.m3u8" {
+		t.Errorf("Videos[0] = %+v, want %+v", got.Videos[0], page.Videos[0])
+	}
+
+	// Other page sizes are cached separately
+	got, err = cache.GetFirstPage(ctx, userID, 50)
+	if err != nil {
+		t.Fatalf("GetFirstPage failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected miss for another page size, got %+v", got)
+	}
+
+	if err := cache.DeleteFirstPages(ctx, userID); err != nil {
+		t.Fatalf("DeleteFirstPages failed: %v", err)
+	}
+	got, err = cache.GetFirstPage(ctx, userID, 20)
+	if err != nil {
+		t.Fatalf("GetFirstPage failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected nil after delete, got %+v", got)
+	}
+}
+
+func TestRedisVideoCache_SetFirstPage_TTL(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	cache := NewRedisVideoCache(client, 0)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	if err := cache.SetFirstPage(ctx, userID, 20, &VideoPage{}, time.Minute); err != nil {
+		t.Fatalf("SetFirstPage failed: %v", err)
+	}
+
+	ttl, err := client.TTL(ctx, cache.buildListKey(userID)).Result()
+	if err != nil {
+		t.Fatalf("TTL failed: %v", err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL = %v, want within (0, 1m]", ttl)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// transcodeProgressKeyPrefix is the prefix for transcode progress keys in Redis.
+	// Keys are "transcode_progress:{video_id}".
+	transcodeProgressKeyPrefix = "transcode_progress:"
+)
+
+// RedisTranscodeProgressStore implements TranscodeProgressStore using Redis.
+type RedisTranscodeProgressStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// Compile-time verification that RedisTranscodeProgressStore implements TranscodeProgressStore.
+var _ TranscodeProgressStore = (*RedisTranscodeProgressStore)(nil)
+
+// NewRedisTranscodeProgressStore creates a new Redis-backed transcode progress store.
+// Every Set refreshes the TTL, so progress left behind by a crashed worker expires
+// ttl after its last update.
+func NewRedisTranscodeProgressStore(client *redis.Client, ttl time.Duration) *RedisTranscodeProgressStore {
+	return &RedisTranscodeProgressStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Set stores the percentage with the configured TTL.
+func (s *RedisTranscodeProgressStore) Set(ctx context.Context, videoID uuid.UUID, percent int) error {
+	if err := s.client.Set(ctx, transcodeProgressKey(videoID), percent, s.ttl).Err(); err != nil {
+		return fmt.Errorf("redis set transcode progress: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves the stored percentage.
+func (s *RedisTranscodeProgressStore) Get(ctx context.Context, videoID uuid.UUID) (int, bool, error) {
+	percent, err := s.client.Get(ctx, transcodeProgressKey(videoID)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("redis get transcode progress: %w", err)
+	}
+	return percent, true, nil
+}
+
+// Delete removes the stored percentage.
+func (s *RedisTranscodeProgressStore) Delete(ctx context.Context, videoID uuid.UUID) error {
+	if err := s.client.Del(ctx, transcodeProgressKey(videoID)).Err(); err != nil {
+		return fmt.Errorf("redis delete transcode progress: %w", err)
+	}
+	return nil
+}
+
+func transcodeProgressKey(videoID uuid.UUID) string {
+	return transcodeProgressKeyPrefix + videoID.String()
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRedisTranscodeProgressStore(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRedisTranscodeProgressStore(client, time.Minute)
+	ctx := context.Background()
+	videoID := uuid.New()
+
+	if _, ok, err := store.Get(ctx, videoID); err != nil || ok {
+		t.Fatalf("Get() before Set: ok=%v err=%v, expected no progress", ok, err)
+	}
+
+	if err := store.Set(ctx, videoID, 42); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	percent, ok, err := store.Get(ctx, videoID)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if !ok || percent != 42 {
+		t.Errorf("Get() = %d, %v; expected 42, true", percent, ok)
+	}
+
+	ttl := client.TTL(ctx, transcodeProgressKeyPrefix+videoID.String()).Val()
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected TTL within 1m, got %v", ttl)
+	}
+
+	if err := store.Delete(ctx, videoID); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, ok, err := store.Get(ctx, videoID); err != nil || ok {
+		t.Errorf("Get() after Delete: ok=%v err=%v, expected no progress", ok, err)
+	}
+}
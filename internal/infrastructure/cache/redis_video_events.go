@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+const (
+	// videoEventsChannelPrefix is the prefix for video event pub/sub channels.
+	// Channels are "video_events:{video_id}".
+	videoEventsChannelPrefix = "video_events:"
+
+	// videoEventBuffer is the number of events a subscriber may fall behind by before
+	// receiving blocks on the subscription.
+	videoEventBuffer = 16
+)
+
+// RedisVideoEventBus implements VideoEventBus using Redis pub/sub, one channel per video.
+type RedisVideoEventBus struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisVideoEventBus implements VideoEventBus.
+var _ VideoEventBus = (*RedisVideoEventBus)(nil)
+
+// NewRedisVideoEventBus creates a new Redis-backed video event bus.
+func NewRedisVideoEventBus(client *redis.Client) *RedisVideoEventBus {
+	return &RedisVideoEventBus{client: client}
+}
+
+// videoEventJSON is the published form of VideoEvent; the video ID is in the channel name.
+type videoEventJSON struct {
+	Type     VideoEventType `json:"type"`
+	Status   string         `json:"status,omitempty"`
+	Progress int            `json:"progress,omitempty"`
+}
+
+// Publish sends the event to the video's channel.
+func (b *RedisVideoEventBus) Publish(ctx context.Context, event VideoEvent) error {
+	data, err := json.Marshal(videoEventJSON{
+		Type:     event.Type,
+		Status:   string(event.Status),
+		Progress: event.Progress,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal video event: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, videoEventsChannel(event.VideoID), data).Err(); err != nil {
+		return fmt.Errorf("redis publish video event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe subscribes to the video's channel and forwards its events until ctx is done.
+// Malformed messages are logged and skipped.
+func (b *RedisVideoEventBus) Subscribe(ctx context.Context, videoID uuid.UUID) (<-chan VideoEvent, error) {
+	sub := b.client.Subscribe(ctx, videoEventsChannel(videoID))
+	// Wait for the confirmation so no event published after we return is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close() // Best-effort cleanup
+		return nil, fmt.Errorf("redis subscribe video events: %w", err)
+	}
+
+	events := make(chan VideoEvent, videoEventBuffer)
+	go func() {
+		defer close(events)
+		defer func() { _ = sub.Close() }() // Best-effort cleanup
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var v videoEventJSON
+				if err := json.Unmarshal([]byte(msg.Payload), &v); err != nil {
+					slog.WarnContext(ctx, "skipping malformed video event",
+						"video_id", videoID,
+						"error", err,
+					)
+					continue
+				}
+				event := VideoEvent{
+					Type:     v.Type,
+					VideoID:  videoID,
+					Status:   model.Status(v.Status),
+					Progress: v.Progress,
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func videoEventsChannel(videoID uuid.UUID) string {
+	return videoEventsChannelPrefix + videoID.String()
+}
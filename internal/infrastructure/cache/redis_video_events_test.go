@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestRedisVideoEventBus(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	bus := NewRedisVideoEventBus(client)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	videoID := uuid.New()
+
+	events, err := bus.Subscribe(ctx, videoID)
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	published := []VideoEvent{
+		{Type: VideoEventProgress, VideoID: videoID, Progress: 42},
+		// Events of other videos are not delivered
+		{Type: VideoEventStatus, VideoID: uuid.New(), Status: model.StatusFailed},
+		{Type: VideoEventStatus, VideoID: videoID, Status: model.StatusReady},
+	}
+	for _, event := range published {
+		if err := bus.Publish(ctx, event); err != nil {
+			t.Fatalf("Publish() failed: %v", err)
+		}
+	}
+
+	for _, want := range []VideoEvent{published[0], published[2]} {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Errorf("got event %+v, want %+v", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %+v", want)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("unexpected event after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// viewCountKeyPrefix is the prefix for pending view hashes in Redis.
+	// Keys are "video_views:{video_id}" with fields views and last_viewed_at (Unix milliseconds).
+	viewCountKeyPrefix = "video_views:"
+	// viewCountDirtyKey is the set of video IDs with pending views.
+	viewCountDirtyKey = "video_views:dirty"
+)
+
+// addViewsScript counts views and marks the video dirty. Running it as a script keeps
+// the latest view time when replicas, or a re-queued failed flush, report views out of
+// order.
+//
+// KEYS[1] = pending view hash, KEYS[2] = dirty set, ARGV = views, last_viewed_at_ms, video_id
+var addViewsScript = redis.NewScript(`
+redis.call("HINCRBY", KEYS[1], "views", ARGV[1])
+local last = tonumber(redis.call("HGET", KEYS[1], "last_viewed_at"))
+if last == nil or tonumber(ARGV[2]) > last then
+	redis.call("HSET", KEYS[1], "last_viewed_at", ARGV[2])
+end
+redis.call("SADD", KEYS[2], ARGV[3])
+return 1
+`)
+
+// RedisViewCounter implements ViewCounter using Redis hashes.
+// Pending views have no TTL: every key is in the dirty set and removed by its flush.
+type RedisViewCounter struct {
+	client *redis.Client
+}
+
+// Compile-time verification that RedisViewCounter implements ViewCounter.
+var _ ViewCounter = (*RedisViewCounter)(nil)
+
+// NewRedisViewCounter creates a new Redis-backed view counter.
+func NewRedisViewCounter(client *redis.Client) *RedisViewCounter {
+	return &RedisViewCounter{
+		client: client,
+	}
+}
+
+// Add counts views atomically with marking the video dirty.
+func (c *RedisViewCounter) Add(ctx context.Context, videoID uuid.UUID, views int64, lastViewedAt time.Time) error {
+	err := addViewsScript.Run(ctx, c.client,
+		[]string{viewCountKeyPrefix + videoID.String(), viewCountDirtyKey},
+		views,
+		lastViewedAt.UnixMilli(),
+		videoID.String(),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("redis add views: %w", err)
+	}
+
+	return nil
+}
+
+// Pending reads the pending views of a video.
+func (c *RedisViewCounter) Pending(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error) {
+	fields, err := c.client.HGetAll(ctx, viewCountKeyPrefix+videoID.String()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis get pending views: %w", err)
+	}
+
+	return parseViewCount(videoID, fields), nil
+}
+
+// PopDirty pops up to n videos from the dirty set, then reads and deletes their hashes in
+// one transaction, so a view counted meanwhile starts a new hash for the next flush.
+// The set is drained once a pop comes back short.
+func (c *RedisViewCounter) PopDirty(ctx context.Context, n int) ([]*model.VideoStats, bool, error) {
+	members, err := c.client.SPopN(ctx, viewCountDirtyKey, int64(n)).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis pop dirty views: %w", err)
+	}
+	drained := len(members) < n
+	if len(members) == 0 {
+		return nil, drained, nil
+	}
+
+	reads := make([]*redis.MapStringStringCmd, len(members))
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, member := range members {
+			reads[i] = pipe.HGetAll(ctx, viewCountKeyPrefix+member)
+			pipe.Del(ctx, viewCountKeyPrefix+member)
+		}
+		return nil
+	})
+	if err != nil {
+		// Put the members back so the next flush retries them
+		_ = c.client.SAdd(ctx, viewCountDirtyKey, toAny(members)...).Err()
+		return nil, false, fmt.Errorf("redis read pending views: %w", err)
+	}
+
+	result := make([]*model.VideoStats, 0, len(members))
+	for i, member := range members {
+		videoID, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+
+		stats := parseViewCount(videoID, reads[i].Val())
+		if stats.Views > 0 {
+			result = append(result, stats)
+		}
+	}
+
+	return result, drained, nil
+}
+
+func parseViewCount(videoID uuid.UUID, fields map[string]string) *model.VideoStats {
+	stats := &model.VideoStats{VideoID: videoID}
+	if views, err := strconv.ParseInt(fields["views"], 10, 64); err == nil {
+		stats.Views = views
+	}
+	if ms, err := strconv.ParseInt(fields["last_viewed_at"], 10, 64); err == nil {
+		stats.LastViewedAt = time.UnixMilli(ms)
+	}
+	return stats
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRedisViewCounter_AddAndPending(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	counter := NewRedisViewCounter(client)
+	ctx := context.Background()
+	videoID := uuid.New()
+	now := time.Now().Truncate(time.Millisecond)
+
+	got, err := counter.Pending(ctx, videoID)
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if got.Views != 0 || !got.LastViewedAt.IsZero() {
+		t.Errorf("expected no pending views, got %+v", got)
+	}
+
+	// The second view arrives out of order and must not rewind the last view
+	if err := counter.Add(ctx, videoID, 1, now); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := counter.Add(ctx, videoID, 2, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	got, err = counter.Pending(ctx, videoID)
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if got.VideoID != videoID || got.Views != 3 {
+		t.Errorf("Views: got %d, want 3", got.Views)
+	}
+	if !got.LastViewedAt.Equal(now) {
+		t.Errorf("LastViewedAt: got %v, want %v", got.LastViewedAt, now)
+	}
+}
+
+func TestRedisViewCounter_PopDirty(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	counter := NewRedisViewCounter(client)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+
+	want := map[uuid.UUID]int64{uuid.New(): 1, uuid.New(): 5, uuid.New(): 2}
+	for videoID, views := range want {
+		if err := counter.Add(ctx, videoID, views, now); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	first, drained, err := counter.PopDirty(ctx, 2)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if drained {
+		t.Error("expected more to come after a full batch")
+	}
+	rest, drained, err := counter.PopDirty(ctx, 2)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(first) != 2 || len(rest) != 1 || !drained {
+		t.Fatalf("expected batches of 2 and 1, got %d and %d, drained %v", len(first), len(rest), drained)
+	}
+	for _, stats := range append(first, rest...) {
+		if stats.Views != want[stats.VideoID] || !stats.LastViewedAt.Equal(now) {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+
+		// Popped views are no longer pending
+		pending, err := counter.Pending(ctx, stats.VideoID)
+		if err != nil {
+			t.Fatalf("Pending failed: %v", err)
+		}
+		if pending.Views != 0 {
+			t.Errorf("expected no pending views after pop, got %d", pending.Views)
+		}
+	}
+
+	empty, drained, err := counter.PopDirty(ctx, 2)
+	if err != nil {
+		t.Fatalf("PopDirty failed: %v", err)
+	}
+	if len(empty) != 0 || !drained {
+		t.Errorf("expected empty dirty set, got %d, drained %v", len(empty), drained)
+	}
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StreamSessionStore tracks active playback sessions per user.
+// A session is considered active while it has been seen within the session timeout.
+type StreamSessionStore interface {
+	// Acquire registers sessionID for userID if the user has fewer than limit active sessions.
+	// Re-acquiring an existing session always succeeds and refreshes it.
+	// Returns false without error when the limit is reached.
+	Acquire(ctx context.Context, userID uuid.UUID, sessionID string, limit int, now time.Time, timeout time.Duration) (bool, error)
+
+	// Touch refreshes the last-seen time of an existing session (heartbeat)
+	// and keeps the user's session set alive for another timeout.
+	// Unknown sessions are ignored.
+	Touch(ctx context.Context, userID uuid.UUID, sessionID string, now time.Time, timeout time.Duration) error
+
+	// Release removes a session so it no longer counts towards the limit.
+	Release(ctx context.Context, userID uuid.UUID, sessionID string) error
+
+	// ReleaseAll removes every session of a user.
+	ReleaseAll(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TranscodeProgressStore holds the encode progress of videos being transcoded.
+// Progress changes too often to be worth a database write, and is meaningless once
+// the transcode finishes, so it lives only here.
+type TranscodeProgressStore interface {
+	// Set records the percentage (0-100) of the video's current transcode that is complete.
+	Set(ctx context.Context, videoID uuid.UUID, percent int) error
+
+	// Get retrieves the recorded percentage.
+	// Returns false if no transcode of the video has reported progress recently.
+	Get(ctx context.Context, videoID uuid.UUID) (int, bool, error)
+
+	// Delete removes the recorded progress.
+	// Returns nil if nothing was recorded.
+	Delete(ctx context.Context, videoID uuid.UUID) error
+}
@@ -12,13 +12,50 @@ import (
 // Implementations should handle serialization/deserialization transparently.
 type VideoCache interface {
 	// Get retrieves a video from cache by ID.
-	// Returns nil, nil if the video is not found in cache (cache miss).
+	// Returns nil, nil if the video is not found in cache or is past its TTL (cache miss).
 	Get(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
 
-	// Set stores a video in cache with the specified TTL.
-	Set(ctx context.Context, video *model.Video, ttl time.Duration) error
+	// GetStale retrieves a video from cache by ID even past its TTL, as long as it is
+	// still retained for stale reads. Returns nil, nil if nothing is retained.
+	GetStale(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+
+	// GetEntry is Get with the entry's expiry and load time, for callers that refresh
+	// entries before they expire. Returns nil, nil on cache miss.
+	GetEntry(ctx context.Context, videoID uuid.UUID) (*VideoEntry, error)
+
+	// Set stores a video in cache with the specified TTL. The entry is retained for
+	// GetStale for staleTTL after it expires; zero drops it at the TTL. loadTime is how
+	// long the video took to load and is returned by GetEntry; zero if unknown.
+	// Implementations may shorten the TTL by a random jitter.
+	Set(ctx context.Context, video *model.Video, ttl, staleTTL, loadTime time.Duration) error
 
 	// Delete removes a video from cache by ID.
 	// Returns nil if the video was not in cache.
 	Delete(ctx context.Context, videoID uuid.UUID) error
+
+	// GetFirstPage retrieves the first page of a user's video list for the given page size.
+	// Returns nil, nil on cache miss.
+	GetFirstPage(ctx context.Context, userID uuid.UUID, limit int) (*VideoPage, error)
+
+	// SetFirstPage stores the first page of a user's video list with the specified TTL.
+	SetFirstPage(ctx context.Context, userID uuid.UUID, limit int, page *VideoPage, ttl time.Duration) error
+
+	// DeleteFirstPages removes every cached first page of a user's video list.
+	// Returns nil if nothing was cached.
+	DeleteFirstPages(ctx context.Context, userID uuid.UUID) error
+}
+
+// VideoEntry is a cached video with the metadata needed to refresh it early.
+type VideoEntry struct {
+	Video *model.Video
+	// FreshUntil is when the entry expires; zero if it expires with its key.
+	FreshUntil time.Time
+	// LoadTime is how long the video took to load when it was cached; zero if unknown.
+	LoadTime time.Duration
+}
+
+// VideoPage is a cached page of a user's video list.
+type VideoPage struct {
+	Videos     []*model.Video
+	NextCursor string
 }
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// VideoEventType distinguishes status changes from progress updates.
+type VideoEventType string
+
+const (
+	// VideoEventStatus reports that the video moved to Status.
+	VideoEventStatus VideoEventType = "status"
+	// VideoEventProgress reports the encode Progress of a PROCESSING video.
+	VideoEventProgress VideoEventType = "progress"
+)
+
+// VideoEvent is a change of a video's status or transcode progress.
+type VideoEvent struct {
+	Type    VideoEventType
+	VideoID uuid.UUID
+	// Status is set for status events.
+	Status model.Status
+	// Progress is the encoded percentage (0-99), set for progress events.
+	Progress int
+}
+
+// VideoEventBus carries video events from the services that cause them (mostly the
+// worker) to the API servers streaming them to clients.
+type VideoEventBus interface {
+	// Publish delivers the event to the current subscribers of its video.
+	// Events are not stored: a subscriber that connects later never sees it.
+	Publish(ctx context.Context, event VideoEvent) error
+
+	// Subscribe delivers the video's events until ctx is done, then closes the channel.
+	// The subscription is active once Subscribe returns, so state read afterwards is
+	// never older than the first event delivered.
+	Subscribe(ctx context.Context, videoID uuid.UUID) (<-chan VideoEvent, error)
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+// ViewCounter buffers video views in front of the database.
+// Views are counted here and flushed to PostgreSQL in batches, so a popular video does
+// not turn every view into a write to the same row.
+type ViewCounter interface {
+	// Add counts views of a video, the latest at lastViewedAt, and marks it for the next
+	// flush. It also re-queues the views of a failed flush.
+	Add(ctx context.Context, videoID uuid.UUID, views int64, lastViewedAt time.Time) error
+
+	// Pending returns the views counted since the last flush.
+	// Returns zero stats if there are none.
+	Pending(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error)
+
+	// PopDirty removes and returns the pending views of up to n videos. Videos without
+	// views are dropped, so fewer than n may be returned while more remain; drained
+	// reports that the videos awaiting flush ran out.
+	PopDirty(ctx context.Context, n int) (stats []*model.VideoStats, drained bool, err error)
+}
@@ -0,0 +1,82 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const defaultBunnyEndpoint = "https://api.bunny.net"
+
+// BunnyConfig holds configuration for BunnyPurger.
+type BunnyConfig struct {
+	APIKey string
+	// BaseURL is the public pull zone URL that prefixes are resolved against.
+	BaseURL string
+	// Endpoint overrides the bunny.net API endpoint (for testing).
+	Endpoint string
+	Timeout  time.Duration
+}
+
+// BunnyPurger implements repository.CDNPurger using bunny.net wildcard URL purges.
+// bunny.net purges one URL per request, so prefixes are purged sequentially.
+type BunnyPurger struct {
+	apiKey   string
+	baseURL  string
+	endpoint string
+	client   *http.Client
+}
+
+var _ repository.CDNPurger = (*BunnyPurger)(nil)
+
+// NewBunnyPurger creates a new BunnyPurger instance.
+func NewBunnyPurger(cfg BunnyConfig) (*BunnyPurger, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("bunny API key is required")
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("bunny purge requires a CDN base URL")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultBunnyEndpoint
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &BunnyPurger{
+		apiKey:   cfg.APIKey,
+		baseURL:  strings.TrimRight(cfg.BaseURL, "/"),
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Purge evicts each prefix with a wildcard URL purge.
+// Stops at the first failure; retrying the whole batch is safe because purges are idempotent.
+func (p *BunnyPurger) Purge(ctx context.Context, prefixes []string) error {
+	for _, prefix := range prefixes {
+		target := p.baseURL + "/" + strings.TrimPrefix(prefix, "/") + "*"
+		query := url.Values{"url": {target}, "async": {"false"}}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/purge?"+query.Encode(), nil)
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("AccessKey", p.apiKey)
+
+		if err := do(p.client, req, http.StatusOK); err != nil {
+			return fmt.Errorf("purge %s: %w", prefix, err)
+		}
+	}
+
+	return nil
+}
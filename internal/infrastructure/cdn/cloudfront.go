@@ -0,0 +1,143 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const (
+	defaultCloudFrontEndpoint = "https://cloudfront.amazonaws.com"
+	// cloudFrontRegion is fixed: CloudFront is a global service signed in us-east-1.
+	cloudFrontRegion = "us-east-1"
+)
+
+// CloudFrontConfig holds configuration for CloudFrontPurger.
+type CloudFrontConfig struct {
+	DistributionID  string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Endpoint overrides the CloudFront API endpoint (for testing).
+	Endpoint string
+	Timeout  time.Duration
+}
+
+// CloudFrontPurger implements repository.CDNPurger using CloudFront invalidations.
+// All prefixes are submitted as wildcard paths in a single invalidation batch.
+type CloudFrontPurger struct {
+	distributionID string
+	creds          aws.Credentials
+	signer         *v4.Signer
+	endpoint       string
+	client         *http.Client
+	now            func() time.Time
+}
+
+var _ repository.CDNPurger = (*CloudFrontPurger)(nil)
+
+// NewCloudFrontPurger creates a new CloudFrontPurger instance.
+func NewCloudFrontPurger(cfg CloudFrontConfig) (*CloudFrontPurger, error) {
+	if cfg.DistributionID == "" {
+		return nil, fmt.Errorf("cloudfront distribution ID is required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("cloudfront credentials are required")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultCloudFrontEndpoint
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &CloudFrontPurger{
+		distributionID: cfg.DistributionID,
+		creds: aws.Credentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			SessionToken:    cfg.SessionToken,
+		},
+		signer:   v4.NewSigner(),
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   &http.Client{Timeout: timeout},
+		now:      time.Now,
+	}, nil
+}
+
+type invalidationBatch struct {
+	XMLName         xml.Name `xml:"InvalidationBatch"`
+	Xmlns           string   `xml:"xmlns,attr"`
+	Paths           paths    `xml:"Paths"`
+	CallerReference string   `xml:"CallerReference"`
+}
+
+type paths struct {
+	Quantity int      `xml:"Quantity"`
+	Items    []string `xml:"Items>Path"`
+}
+
+// Purge creates an invalidation for every prefix.
+// CloudFront completes invalidations asynchronously; Purge returns once the request is accepted.
+func (p *CloudFrontPurger) Purge(ctx context.Context, prefixes []string) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	items := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		items[i] = "/" + strings.TrimPrefix(prefix, "/") + "*"
+	}
+
+	ref, err := callerReference()
+	if err != nil {
+		return fmt.Errorf("generate caller reference: %w", err)
+	}
+
+	body, err := xml.Marshal(invalidationBatch{
+		Xmlns:           "http://cloudfront.amazonaws.com/doc/2020-05-31/",
+		Paths:           paths{Quantity: len(items), Items: items},
+		CallerReference: ref,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal invalidation batch: %w", err)
+	}
+	body = append([]byte(xml.Header), body...)
+
+	url := fmt.Sprintf("%s/2020-05-31/distribution/%s/invalidation", p.endpoint, p.distributionID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	payloadHash := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, p.creds, req, hex.EncodeToString(payloadHash[:]), "cloudfront", cloudFrontRegion, p.now()); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	return do(p.client, req, http.StatusCreated)
+}
+
+// callerReference returns a unique token so CloudFront does not dedupe distinct invalidations.
+func callerReference() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
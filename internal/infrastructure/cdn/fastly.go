@@ -0,0 +1,93 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const defaultFastlyEndpoint = "https://api.fastly.com"
+
+// FastlyConfig holds configuration for FastlyPurger.
+type FastlyConfig struct {
+	ServiceID string
+	APIToken  string
+	// Endpoint overrides the Fastly API endpoint (for testing).
+	Endpoint string
+	Timeout  time.Duration
+}
+
+// FastlyPurger implements repository.CDNPurger using Fastly surrogate key purges.
+//
+// Fastly cannot purge by URL prefix, so the service VCL must tag every response with
+// its object prefix as a surrogate key (e.g., "/hls/{video_id}/v{n}/" for segments and
+// manifests under that directory). Each purged prefix maps to exactly one surrogate key.
+type FastlyPurger struct {
+	serviceID string
+	token     string
+	endpoint  string
+	client    *http.Client
+}
+
+var _ repository.CDNPurger = (*FastlyPurger)(nil)
+
+// NewFastlyPurger creates a new FastlyPurger instance.
+func NewFastlyPurger(cfg FastlyConfig) (*FastlyPurger, error) {
+	if cfg.ServiceID == "" || cfg.APIToken == "" {
+		return nil, fmt.Errorf("fastly service ID and API token are required")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultFastlyEndpoint
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &FastlyPurger{
+		serviceID: cfg.ServiceID,
+		token:     cfg.APIToken,
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+type surrogateKeyPurge struct {
+	SurrogateKeys []string `json:"surrogate_keys"`
+}
+
+// Purge evicts every surrogate key in a single batch request.
+func (p *FastlyPurger) Purge(ctx context.Context, prefixes []string) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		keys[i] = "/" + strings.TrimPrefix(prefix, "/")
+	}
+
+	body, err := json.Marshal(surrogateKeyPurge{SurrogateKeys: keys})
+	if err != nil {
+		return fmt.Errorf("marshal purge request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/service/%s/purge", p.endpoint, p.serviceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Fastly-Key", p.token)
+
+	return do(p.client, req, http.StatusOK)
+}
@@ -0,0 +1,52 @@
+// Package cdn provides CDNPurger implementations for the supported CDN providers.
+package cdn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout bounds each purge API call.
+const defaultTimeout = 10 * time.Second
+
+// statusError is returned when a provider API responds with an unexpected status code.
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// isRetryable reports whether a failed purge may succeed if attempted again.
+// Throttling, server errors, and transport failures are retried; other client errors are not.
+func isRetryable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+	}
+	var ne net.Error
+	return errors.As(err, &ne) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// do sends req and checks the response status against want.
+func do(client *http.Client, req *http.Request, want int) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send purge request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != want {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,135 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCloudFrontPurger_Purge(t *testing.T) {
+	var gotPath, gotAuth, gotToken string
+	var gotBatch invalidationBatch
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotToken = r.Header.Get("X-Amz-Security-Token")
+		body, _ := io.ReadAll(r.Body)
+		if err := xml.Unmarshal(body, &gotBatch); err != nil {
+			t.Errorf("failed to decode invalidation batch: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	p, err := NewCloudFrontPurger(CloudFrontConfig{
+		DistributionID:  "E123",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Endpoint:        srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("failed to create purger: %v", err)
+	}
+
+	if err := p.Purge(context.Background(), []string{"hls/abc/v1/", "previews/abc/v1/"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/2020-05-31/distribution/E123/invalidation" {
+		t.Errorf("path: got %q", gotPath)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(gotAuth, "/us-east-1/cloudfront/") {
+		t.Errorf("authorization: got %q", gotAuth)
+	}
+	if gotToken != "token" || !strings.Contains(gotAuth, "x-amz-security-token") {
+		t.Errorf("session token %q is not sent and signed: %q", gotToken, gotAuth)
+	}
+	wantPaths := []string{"/hls/abc/v1/*", "/previews/abc/v1/*"}
+	if gotBatch.Paths.Quantity != 2 || strings.Join(gotBatch.Paths.Items, ",") != strings.Join(wantPaths, ",") {
+		t.Errorf("paths: got %+v, expected %v", gotBatch.Paths, wantPaths)
+	}
+	if gotBatch.CallerReference == "" {
+		t.Error("expected caller reference")
+	}
+}
+
+func TestFastlyPurger_Purge(t *testing.T) {
+	var gotPath, gotKey string
+	var gotBody surrogateKeyPurge
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("Fastly-Key")
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p, err := NewFastlyPurger(FastlyConfig{ServiceID: "svc", APIToken: "token", Endpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("failed to create purger: %v", err)
+	}
+
+	if err := p.Purge(context.Background(), []string{"hls/abc/v1/"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/service/svc/purge" {
+		t.Errorf("path: got %q", gotPath)
+	}
+	if gotKey != "token" {
+		t.Errorf("Fastly-Key: got %q", gotKey)
+	}
+	if len(gotBody.SurrogateKeys) != 1 || gotBody.SurrogateKeys[0] != "/hls/abc/v1/" {
+		t.Errorf("surrogate keys: got %v", gotBody.SurrogateKeys)
+	}
+}
+
+func TestBunnyPurger_Purge(t *testing.T) {
+	var gotURLs []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("AccessKey") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		gotURLs = append(gotURLs, r.URL.Query().Get("url"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p, err := NewBunnyPurger(BunnyConfig{APIKey: "key", BaseURL: "https://cdn.example.com/", Endpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("failed to create purger: %v", err)
+	}
+
+	if err := p.Purge(context.Background(), []string{"hls/abc/v1/", "previews/abc/v1/"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"https://cdn.example.com/hls/abc/v1/*", "https://cdn.example.com/previews/abc/v1/*"}
+	if strings.Join(gotURLs, ",") != strings.Join(want, ",") {
+		t.Errorf("purged URLs: got %v, expected %v", gotURLs, want)
+	}
+}
+
+func TestNewPurgers_Validation(t *testing.T) {
+	if _, err := NewCloudFrontPurger(CloudFrontConfig{AccessKeyID: "a", SecretAccessKey: "b"}); err == nil {
+		t.Error("cloudfront: expected error for missing distribution ID")
+	}
+	if _, err := NewFastlyPurger(FastlyConfig{ServiceID: "svc"}); err == nil {
+		t.Error("fastly: expected error for missing token")
+	}
+	if _, err := NewBunnyPurger(BunnyConfig{APIKey: "key"}); err == nil {
+		t.Error("bunny: expected error for missing base URL")
+	}
+}
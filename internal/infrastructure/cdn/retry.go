@@ -0,0 +1,79 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// RetryConfig holds configuration for RetryingPurger.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first. Values below 1 mean 1.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles after each attempt.
+	Backoff time.Duration
+}
+
+// DefaultRetryConfig returns the default retry configuration.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		Backoff:     time.Second,
+	}
+}
+
+// RetryingPurger wraps a provider purger with exponential backoff and metrics.
+// Only transient failures (throttling, 5xx, transport errors) are retried.
+type RetryingPurger struct {
+	provider string
+	next     repository.CDNPurger
+	cfg      RetryConfig
+}
+
+var _ repository.CDNPurger = (*RetryingPurger)(nil)
+
+// NewRetryingPurger creates a new RetryingPurger instance.
+// The provider parameter labels metrics (see metrics.CDNProvider* constants).
+func NewRetryingPurger(provider string, next repository.CDNPurger, cfg RetryConfig) *RetryingPurger {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &RetryingPurger{provider: provider, next: next, cfg: cfg}
+}
+
+// Purge calls the wrapped purger until it succeeds, fails permanently, or attempts run out.
+func (p *RetryingPurger) Purge(ctx context.Context, prefixes []string) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.CDNPurgeDurationSeconds.WithLabelValues(p.provider).Observe(time.Since(start).Seconds())
+	}()
+
+	backoff := p.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		err := p.next.Purge(ctx, prefixes)
+		if err == nil {
+			metrics.CDNPurgeAttemptsTotal.WithLabelValues(p.provider, metrics.CDNPurgeSuccess).Inc()
+			return nil
+		}
+
+		if attempt >= p.cfg.MaxAttempts || !isRetryable(err) {
+			metrics.CDNPurgeAttemptsTotal.WithLabelValues(p.provider, metrics.CDNPurgeFailure).Inc()
+			return fmt.Errorf("%s purge failed after %d attempt(s): %w", p.provider, attempt, err)
+		}
+		metrics.CDNPurgeAttemptsTotal.WithLabelValues(p.provider, metrics.CDNPurgeRetry).Inc()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s purge: %w", p.provider, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
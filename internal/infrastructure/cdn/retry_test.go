@@ -0,0 +1,95 @@
+package cdn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+type purgeFunc func(ctx context.Context, prefixes []string) error
+
+func (f purgeFunc) Purge(ctx context.Context, prefixes []string) error { return f(ctx, prefixes) }
+
+func TestRetryingPurger_Purge(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error
+		maxAttempts  int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{
+			name:         "success on first attempt",
+			errs:         []error{nil},
+			maxAttempts:  3,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries server errors",
+			errs:         []error{&statusError{StatusCode: 503}, &statusError{StatusCode: 429}, nil},
+			maxAttempts:  3,
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			errs:         []error{&statusError{StatusCode: 500}, &statusError{StatusCode: 500}},
+			maxAttempts:  2,
+			wantErr:      true,
+			wantAttempts: 2,
+		},
+		{
+			name:         "does not retry client errors",
+			errs:         []error{&statusError{StatusCode: http.StatusForbidden}},
+			maxAttempts:  3,
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+		{
+			name:         "does not retry unknown errors",
+			errs:         []error{errors.New("boom")},
+			maxAttempts:  3,
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			next := purgeFunc(func(ctx context.Context, prefixes []string) error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			})
+
+			p := NewRetryingPurger(metrics.CDNProviderFastly, next, RetryConfig{MaxAttempts: tt.maxAttempts, Backoff: time.Millisecond})
+			err := p.Purge(context.Background(), []string{"hls/abc/v1/"})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error: got %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts: got %d, expected %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryingPurger_Purge_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	next := purgeFunc(func(ctx context.Context, prefixes []string) error {
+		cancel()
+		return &statusError{StatusCode: 503}
+	})
+
+	p := NewRetryingPurger(metrics.CDNProviderBunny, next, RetryConfig{MaxAttempts: 3, Backoff: time.Hour})
+	err := p.Purge(ctx, []string{"hls/abc/v1/"})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package entitlement provides EntitlementChecker implementations backed by external services.
+package entitlement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// HTTPCheckerConfig holds configuration for HTTPChecker.
+type HTTPCheckerConfig struct {
+	// URL is the provider endpoint. user_id and video_id are appended as query parameters.
+	URL string
+	// Token is sent as a bearer token when non-empty.
+	Token string
+	// Timeout bounds each check so a slow provider cannot stall playback requests.
+	Timeout time.Duration
+}
+
+// entitlementResponse is the provider's response body.
+type entitlementResponse struct {
+	Entitled bool `json:"entitled"`
+}
+
+// HTTPChecker implements repository.EntitlementChecker by querying an external billing service.
+//
+// The provider contract is:
+//
+//	GET {URL}?user_id={uuid}&video_id={uuid}
+//	200 {"entitled": true|false}
+//
+// Any other status is treated as an error, so an unavailable provider fails closed.
+type HTTPChecker struct {
+	endpoint *url.URL
+	token    string
+	client   *http.Client
+}
+
+// NewHTTPChecker creates a new HTTPChecker instance.
+func NewHTTPChecker(cfg HTTPCheckerConfig) (*HTTPChecker, error) {
+	endpoint, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse entitlement URL: %w", err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("entitlement URL must be absolute: %q", cfg.URL)
+	}
+
+	return &HTTPChecker{
+		endpoint: endpoint,
+		token:    cfg.Token,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// IsEntitled asks the provider whether the user may stream the video.
+func (c *HTTPChecker) IsEntitled(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+	entitled, err := c.check(ctx, userID, videoID)
+	if err != nil {
+		metrics.EntitlementChecksTotal.WithLabelValues(metrics.EntitlementProviderHTTP, metrics.EntitlementError).Inc()
+		return false, err
+	}
+
+	result := metrics.EntitlementDenied
+	if entitled {
+		result = metrics.EntitlementAllowed
+	}
+	metrics.EntitlementChecksTotal.WithLabelValues(metrics.EntitlementProviderHTTP, result).Inc()
+
+	return entitled, nil
+}
+
+func (c *HTTPChecker) check(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+	u := *c.endpoint
+	q := u.Query()
+	q.Set("user_id", userID.String())
+	q.Set("video_id", videoID.String())
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("create entitlement request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("entitlement request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("entitlement provider returned status %d", resp.StatusCode)
+	}
+
+	var body entitlementResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("decode entitlement response: %w", err)
+	}
+
+	return body.Entitled, nil
+}
+
+// Compile-time verification that HTTPChecker implements repository.EntitlementChecker.
+var _ repository.EntitlementChecker = (*HTTPChecker)(nil)
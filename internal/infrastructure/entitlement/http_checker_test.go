@@ -0,0 +1,118 @@
+package entitlement
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHTTPChecker_IsEntitled(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		token   string
+		status  int
+		body    string
+		delay   time.Duration
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "entitled",
+			status: http.StatusOK,
+			body:   `{"entitled": true}`,
+			want:   true,
+		},
+		{
+			name:   "not entitled",
+			status: http.StatusOK,
+			body:   `{"entitled": false}`,
+			want:   false,
+		},
+		{
+			name:   "sends bearer token",
+			token:  "secret",
+			status: http.StatusOK,
+			body:   `{"entitled": true}`,
+			want:   true,
+		},
+		{
+			name:    "provider error fails closed",
+			status:  http.StatusInternalServerError,
+			body:    `{"entitled": true}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed body",
+			status:  http.StatusOK,
+			body:    `not json`,
+			wantErr: true,
+		},
+		{
+			name:    "timeout",
+			status:  http.StatusOK,
+			body:    `{"entitled": true}`,
+			delay:   200 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("user_id"); got != userID.String() {
+					t.Errorf("user_id: got %q, expected %q", got, userID)
+				}
+				if got := r.URL.Query().Get("video_id"); got != videoID.String() {
+					t.Errorf("video_id: got %q, expected %q", got, videoID)
+				}
+				if got := r.URL.Query().Get("tenant"); got != "acme" {
+					t.Errorf("existing query parameter lost: got %q", got)
+				}
+				if tt.token != "" {
+					if got := r.Header.Get("Authorization"); got != "Bearer "+tt.token {
+						t.Errorf("Authorization: got %q", got)
+					}
+				}
+				if tt.delay > 0 {
+					time.Sleep(tt.delay)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			checker, err := NewHTTPChecker(HTTPCheckerConfig{
+				URL:     srv.URL + "/entitlements?tenant=acme",
+				Token:   tt.token,
+				Timeout: 50 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("NewHTTPChecker() error = %v", err)
+			}
+
+			got, err := checker.IsEntitled(context.Background(), userID, videoID)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsEntitled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsEntitled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHTTPChecker_InvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "/relative/path", "://bad"} {
+		if _, err := NewHTTPChecker(HTTPCheckerConfig{URL: raw}); err == nil {
+			t.Errorf("NewHTTPChecker(%q): expected error", raw)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Supported output formats.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Config holds configuration for the slog handler.
+type Config struct {
+	// Level is one of debug, info, warn, error.
+	Level string
+	// Format is FormatJSON or FormatText.
+	Format string
+	// AddSource includes the caller's file and line in every record.
+	AddSource bool
+}
+
+// NewHandler creates a slog handler writing to w that enriches records with attributes
+// attached to the context via WithAttrs and WithLazyAttrs.
+func NewHandler(w io.Writer, cfg Config) (slog.Handler, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+	}
+
+	opts := &slog.HandlerOptions{Level: level, AddSource: cfg.AddSource}
+
+	var h slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be %q or %q", cfg.Format, FormatJSON, FormatText)
+	}
+
+	return NewContextHandler(h), nil
+}
+
+type ctxKey struct{}
+
+// contextAttrs is the enrichment carried by a context. Lazy sources are evaluated when a
+// record is handled, so values that become known later in the request (e.g., route
+// parameters resolved after middleware runs) are still attached.
+type contextAttrs struct {
+	attrs []slog.Attr
+	lazy  []func() []slog.Attr
+}
+
+// WithAttrs returns a copy of ctx whose log records carry attrs.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	parent := fromContext(ctx)
+	return context.WithValue(ctx, ctxKey{}, &contextAttrs{
+		attrs: append(parent.attrs[:len(parent.attrs):len(parent.attrs)], attrs...),
+		lazy:  parent.lazy,
+	})
+}
+
+// WithLazyAttrs returns a copy of ctx whose log records carry the attributes returned by fn
+// at the time each record is handled.
+func WithLazyAttrs(ctx context.Context, fn func() []slog.Attr) context.Context {
+	parent := fromContext(ctx)
+	return context.WithValue(ctx, ctxKey{}, &contextAttrs{
+		attrs: parent.attrs,
+		lazy:  append(parent.lazy[:len(parent.lazy):len(parent.lazy)], fn),
+	})
+}
+
+func fromContext(ctx context.Context) *contextAttrs {
+	if ca, ok := ctx.Value(ctxKey{}).(*contextAttrs); ok {
+		return ca
+	}
+	return &contextAttrs{}
+}
+
+// ContextHandler is a slog.Handler that adds context attributes to every record.
+// Only the *Context logging methods (e.g., slog.InfoContext) pass a context to the handler.
+type ContextHandler struct {
+	next slog.Handler
+}
+
+// NewContextHandler wraps next with context enrichment.
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the context attributes to r before passing it on.
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ca, ok := ctx.Value(ctxKey{}).(*contextAttrs); ok {
+		r = r.Clone()
+		r.AddAttrs(ca.attrs...)
+		for _, fn := range ca.lazy {
+			r.AddAttrs(fn()...)
+		}
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler whose records carry attrs in addition to the context attributes.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler that qualifies subsequent attributes with name.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{next: h.next.WithGroup(name)}
+}
+
+// Compile-time verification that ContextHandler implements slog.Handler.
+var _ slog.Handler = (*ContextHandler)(nil)
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "json", cfg: Config{Level: "info", Format: "json"}},
+		{name: "text", cfg: Config{Level: "DEBUG", Format: "text"}},
+		{name: "invalid level", cfg: Config{Level: "verbose", Format: "json"}, wantErr: true},
+		{name: "invalid format", cfg: Config{Level: "info", Format: "xml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHandler(&bytes.Buffer{}, tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewHandler(&buf, Config{Level: "info", Format: "json"})
+	if err != nil {
+		t.Fatalf("NewHandler() unexpected error: %v", err)
+	}
+	logger := slog.New(h)
+
+	videoID := ""
+	parent := WithAttrs(context.Background(), slog.String("request_id", "req-1"))
+	ctx := WithAttrs(parent, slog.String("user_id", "user-1"))
+	ctx = WithLazyAttrs(ctx, func() []slog.Attr {
+		if videoID == "" {
+			return nil
+		}
+		return []slog.Attr{slog.String("video_id", videoID)}
+	})
+
+	// Lazy attributes are read when the record is written, not when they are attached
+	videoID = "video-1"
+	logger.InfoContext(ctx, "enriched", slog.String("extra", "x"))
+	logger.InfoContext(parent, "parent only")
+	logger.Info("no context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 records, got %d: %s", len(lines), buf.String())
+	}
+
+	tests := []struct {
+		line int
+		want map[string]string
+		omit []string
+	}{
+		{line: 0, want: map[string]string{"request_id": "req-1", "user_id": "user-1", "video_id": "video-1", "extra": "x"}},
+		{line: 1, want: map[string]string{"request_id": "req-1"}, omit: []string{"user_id", "video_id"}},
+		{line: 2, omit: []string{"request_id", "user_id", "video_id"}},
+	}
+
+	for _, tt := range tests {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(lines[tt.line]), &record); err != nil {
+			t.Fatalf("failed to unmarshal record %d: %v", tt.line, err)
+		}
+		for key, want := range tt.want {
+			if got := record[key]; got != want {
+				t.Errorf("record %d: %s = %v, want %q", tt.line, key, got, want)
+			}
+		}
+		for _, key := range tt.omit {
+			if _, ok := record[key]; ok {
+				t.Errorf("record %d: unexpected attribute %s", tt.line, key)
+			}
+		}
+	}
+}
+
+func TestNewHandler_Level(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewHandler(&buf, Config{Level: "warn", Format: "text"})
+	if err != nil {
+		t.Fatalf("NewHandler() unexpected error: %v", err)
+	}
+	logger := slog.New(h)
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	if out := buf.String(); strings.Contains(out, "dropped") || !strings.Contains(out, "kept") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// availabilityBucket counts requests within one bucket interval.
+type availabilityBucket struct {
+	start time.Time
+	good  int64
+	total int64
+}
+
+// AvailabilityWindow keeps per-minute request counts for a bounded retention period,
+// so the API can report availability over a recent window without querying Prometheus.
+// Counts are per process; aggregate across replicas with the sli_http_requests_total series.
+type AvailabilityWindow struct {
+	mu        sync.Mutex
+	buckets   []availabilityBucket
+	retention time.Duration
+}
+
+// availabilityBucketSize is the resolution of AvailabilityWindow.
+const availabilityBucketSize = time.Minute
+
+// NewAvailabilityWindow creates a window retaining at least retention of history.
+func NewAvailabilityWindow(retention time.Duration) *AvailabilityWindow {
+	n := int(retention/availabilityBucketSize) + 1
+	return &AvailabilityWindow{buckets: make([]availabilityBucket, n), retention: retention}
+}
+
+// Record counts a request outcome at now.
+func (w *AvailabilityWindow) Record(good bool, now time.Time) {
+	start := now.Truncate(availabilityBucketSize)
+	idx := int(start.Unix()/int64(availabilityBucketSize/time.Second)) % len(w.buckets)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	b := &w.buckets[idx]
+	if !b.start.Equal(start) {
+		*b = availabilityBucket{start: start}
+	}
+	b.total++
+	if good {
+		b.good++
+	}
+}
+
+// Counts returns the good and total request counts within window before now.
+// The window is rounded up to whole buckets and capped at the retention period.
+func (w *AvailabilityWindow) Counts(window time.Duration, now time.Time) (good, total int64) {
+	cutoff := now.Add(-min(window, w.retention)).Truncate(availabilityBucketSize)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, b := range w.buckets {
+		if b.start.IsZero() || b.start.Before(cutoff) || b.start.After(now) {
+			continue
+		}
+		good += b.good
+		total += b.total
+	}
+	return good, total
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAvailabilityWindow_Counts(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	w := NewAvailabilityWindow(10 * time.Minute)
+
+	w.Record(true, now.Add(-30*time.Minute)) // outside retention: overwritten or filtered
+	w.Record(true, now.Add(-8*time.Minute))
+	w.Record(false, now.Add(-8*time.Minute))
+	w.Record(true, now.Add(-2*time.Minute))
+	w.Record(true, now)
+
+	tests := []struct {
+		name      string
+		window    time.Duration
+		wantGood  int64
+		wantTotal int64
+	}{
+		{"last minute", time.Minute, 1, 1},
+		{"last five minutes", 5 * time.Minute, 2, 2},
+		{"full retention", 10 * time.Minute, 3, 4},
+		{"window beyond retention is capped", time.Hour, 3, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			good, total := w.Counts(tt.window, now)
+			if good != tt.wantGood || total != tt.wantTotal {
+				t.Errorf("Counts(%v) = (%d, %d), want (%d, %d)", tt.window, good, total, tt.wantGood, tt.wantTotal)
+			}
+		})
+	}
+}
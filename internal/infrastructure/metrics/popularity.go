@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Key class constants, derived from a key's recent request count.
+const (
+	KeyClassHot  = "hot"
+	KeyClassWarm = "warm"
+	KeyClassCold = "cold"
+)
+
+// KeyPopularityConfig holds configuration for KeyPopularity.
+type KeyPopularityConfig struct {
+	// Window is the counting period; a key's hits cover the current and previous window.
+	Window time.Duration
+	// HotHits and WarmHits are the minimum hits of a hot and a warm key.
+	HotHits  int64
+	WarmHits int64
+	// MaxKeys caps the keys counted per window, bounding memory under scans of many
+	// distinct keys. Keys beyond the cap are not counted until the next window.
+	MaxKeys int
+}
+
+// DefaultKeyPopularityConfig returns the default configuration.
+func DefaultKeyPopularityConfig() KeyPopularityConfig {
+	return KeyPopularityConfig{
+		Window:   5 * time.Minute,
+		HotHits:  100,
+		WarmHits: 10,
+		MaxKeys:  10000,
+	}
+}
+
+// KeyHits is a key's request count over the current and previous window.
+type KeyHits struct {
+	Key   string
+	Hits  int64
+	Class string
+}
+
+// KeyPopularity counts requests per cache key in two rolling windows, so metrics can be
+// labelled by how hot a key is and operators can list the hottest keys.
+// Counts are per process, like AvailabilityWindow.
+type KeyPopularity struct {
+	mu          sync.Mutex
+	cfg         KeyPopularityConfig
+	windowStart time.Time
+	current     map[string]int64
+	previous    map[string]int64
+}
+
+// NewKeyPopularity creates a tracker with no recorded requests.
+// A non-positive Window or MaxKeys falls back to the default.
+func NewKeyPopularity(cfg KeyPopularityConfig) *KeyPopularity {
+	defaults := DefaultKeyPopularityConfig()
+	if cfg.Window <= 0 {
+		cfg.Window = defaults.Window
+	}
+	if cfg.MaxKeys <= 0 {
+		cfg.MaxKeys = defaults.MaxKeys
+	}
+	return &KeyPopularity{
+		cfg:      cfg,
+		current:  make(map[string]int64),
+		previous: make(map[string]int64),
+	}
+}
+
+// Record counts a request for key at now.
+func (p *KeyPopularity) Record(key string, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rotate(now)
+	if _, ok := p.current[key]; !ok && len(p.current) >= p.cfg.MaxKeys {
+		return
+	}
+	p.current[key]++
+}
+
+// Class returns the class of key at now without counting a request.
+func (p *KeyPopularity) Class(key string, now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rotate(now)
+	return p.classify(p.current[key] + p.previous[key])
+}
+
+// Top returns up to n keys with the most hits at now, most requested first.
+func (p *KeyPopularity) Top(n int, now time.Time) []KeyHits {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rotate(now)
+	hits := make(map[string]int64, len(p.current)+len(p.previous))
+	for key, count := range p.previous {
+		hits[key] += count
+	}
+	for key, count := range p.current {
+		hits[key] += count
+	}
+
+	top := make([]KeyHits, 0, len(hits))
+	for key, count := range hits {
+		top = append(top, KeyHits{Key: key, Hits: count, Class: p.classify(count)})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Hits != top[j].Hits {
+			return top[i].Hits > top[j].Hits
+		}
+		return top[i].Key < top[j].Key
+	})
+	return top[:min(n, len(top))]
+}
+
+// Window returns the counting period.
+func (p *KeyPopularity) Window() time.Duration {
+	return p.cfg.Window
+}
+
+// rotate starts a new window once now has left the current one. After an idle period
+// longer than a window, the previous counts are dropped too. Callers must hold mu.
+func (p *KeyPopularity) rotate(now time.Time) {
+	start := now.Truncate(p.cfg.Window)
+	if !start.After(p.windowStart) {
+		return
+	}
+	if start.Sub(p.windowStart) == p.cfg.Window {
+		p.previous = p.current
+	} else {
+		p.previous = make(map[string]int64)
+	}
+	p.current = make(map[string]int64)
+	p.windowStart = start
+}
+
+func (p *KeyPopularity) classify(hits int64) string {
+	switch {
+	case hits >= p.cfg.HotHits:
+		return KeyClassHot
+	case hits >= p.cfg.WarmHits:
+		return KeyClassWarm
+	default:
+		return KeyClassCold
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestKeyPopularity_Class(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewKeyPopularity(KeyPopularityConfig{Window: time.Minute, HotHits: 3, WarmHits: 2, MaxKeys: 10})
+
+	for range 3 {
+		p.Record("a", start)
+	}
+	p.Record("b", start)
+	p.Record("b", start.Add(30*time.Second))
+
+	tests := []struct {
+		name string
+		key  string
+		now  time.Time
+		want string
+	}{
+		{"hot in the current window", "a", start.Add(30 * time.Second), KeyClassHot},
+		{"warm in the current window", "b", start.Add(30 * time.Second), KeyClassWarm},
+		{"unknown key", "c", start, KeyClassCold},
+		{"previous window still counts", "a", start.Add(90 * time.Second), KeyClassHot},
+		{"idle for a whole window", "a", start.Add(3 * time.Minute), KeyClassCold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Class(tt.key, tt.now); got != tt.want {
+				t.Errorf("Class(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyPopularity_Top(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewKeyPopularity(KeyPopularityConfig{Window: time.Minute, HotHits: 3, WarmHits: 2, MaxKeys: 2})
+
+	p.Record("a", now.Add(-time.Minute)) // previous window
+	p.Record("a", now)
+	p.Record("a", now)
+	p.Record("b", now)
+	p.Record("c", now) // over MaxKeys: not counted
+
+	want := []KeyHits{
+		{Key: "a", Hits: 3, Class: KeyClassHot},
+		{Key: "b", Hits: 1, Class: KeyClassCold},
+	}
+	if got := p.Top(10, now); !slices.Equal(got, want) {
+		t.Errorf("Top(10) = %+v, want %+v", got, want)
+	}
+	if got := p.Top(1, now); !slices.Equal(got, want[:1]) {
+		t.Errorf("Top(1) = %+v, want %+v", got, want[:1])
+	}
+}
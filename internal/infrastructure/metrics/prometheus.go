@@ -39,14 +39,506 @@ var (
 	// SingleflightRequestsTotal tracks singleflight behavior.
 	// Labels:
 	//   - result: initiated (new execution), shared (reused result)
+	//   - key_class: hot, warm, cold (see KeyPopularity)
 	SingleflightRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "singleflight_requests_total",
 			Help:      "Total number of singleflight requests",
 		},
+		[]string{"result", "key_class"},
+	)
+
+	// VideoCacheLookupsTotal tracks how video lookups were answered, by key popularity.
+	// Unlike cache_operations_total it counts lookups rather than Redis calls, and only
+	// the leader of a singleflight group looks up.
+	// Labels:
+	//   - result: hit, miss, stale, refresh
+	//   - key_class: hot, warm, cold (see KeyPopularity)
+	VideoCacheLookupsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "video_cache_lookups_total",
+			Help:      "Total number of video lookups by cache result and key popularity",
+		},
+		[]string{"result", "key_class"},
+	)
+
+	// StorageBytesTransferredTotal tracks bytes moved between the worker and object storage.
+	// Labels:
+	//   - operation: upload, download
+	StorageBytesTransferredTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_bytes_transferred_total",
+			Help:      "Total number of bytes transferred to or from object storage",
+		},
+		[]string{"operation"},
+	)
+
+	// StorageThroughputBytesPerSecond observes the effective throughput of a transfer.
+	// Buckets range from 256 KiB/s to 1 GiB/s to cover both WAN and in-cluster links.
+	// Labels:
+	//   - operation: upload, download
+	StorageThroughputBytesPerSecond = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_throughput_bytes_per_second",
+			Help:      "Throughput of object storage transfers in bytes per second",
+			Buckets:   prometheus.ExponentialBuckets(256*1024, 2, 13),
+		},
+		[]string{"operation"},
+	)
+
+	// StorageErrorsTotal tracks failed object storage operations.
+	// Labels:
+	//   - operation: upload, download, replicate
+	StorageErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_errors_total",
+			Help:      "Total number of failed object storage operations",
+		},
+		[]string{"operation"},
+	)
+
+	// StorageFallbackReadsTotal tracks reads served by an origin other than the primary.
+	// Labels:
+	//   - operation: download, download_range, stat, exists, list
+	//   - origin: configured origin name
+	StorageFallbackReadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_fallback_reads_total",
+			Help:      "Total number of object storage reads served by a fallback origin",
+		},
+		[]string{"operation", "origin"},
+	)
+
+	// StorageOriginDemoted is 1 while a storage origin is demoted after repeated read failures.
+	// Labels:
+	//   - origin: configured origin name
+	StorageOriginDemoted = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "storage_origin_demoted",
+			Help:      "Whether a storage origin is demoted behind the others (1) or not (0)",
+		},
+		[]string{"origin"},
+	)
+
+	// EntitlementChecksTotal tracks entitlement decisions for playback.
+	// Labels:
+	//   - provider: local, http
+	//   - result: allowed, denied, error
+	EntitlementChecksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "entitlement_checks_total",
+			Help:      "Total number of entitlement checks",
+		},
+		[]string{"provider", "result"},
+	)
+
+	// TranscodeStageDurationSeconds tracks time spent in each transcode pipeline stage.
+	// Labels:
+	//   - stage: download, probe, transcode, upload, preview, finalize
+	//   - status: succeeded, failed (outcome of the whole attempt)
+	TranscodeStageDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "transcode_stage_duration_seconds",
+			Help:      "Time spent in each transcode pipeline stage",
+			// 100ms to ~30min: stages range from manifest writes to full-length encodes
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 15),
+		},
+		[]string{"stage", "status"},
+	)
+
+	// TranscodeVariantDurationSeconds tracks encode time per ABR variant.
+	// Labels:
+	//   - variant: 1080p, 720p, 360p, ...
+	TranscodeVariantDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "transcode_variant_duration_seconds",
+			Help:      "Time taken to encode a single ABR variant",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 15),
+		},
+		[]string{"variant"},
+	)
+
+	// TranscodeAttemptsTotal tracks every transcode attempt by outcome, retries included,
+	// so the failure rate shows problems before retries run out.
+	// Labels:
+	//   - status: succeeded, failed
+	TranscodeAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transcode_attempts_total",
+			Help:      "Total number of transcode attempts",
+		},
+		[]string{"status"},
+	)
+
+	// TranscodeDurationSeconds tracks the wall-clock time of whole transcode attempts.
+	// Labels:
+	//   - status: succeeded, failed
+	TranscodeDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "transcode_duration_seconds",
+			Help:      "Time taken by a transcode attempt from dequeue to outcome",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 16),
+		},
+		[]string{"status"},
+	)
+
+	// TranscodeOutputBytes tracks the size of the ABR output uploaded by succeeded attempts.
+	// Buckets range from 1 MiB to 32 GiB.
+	TranscodeOutputBytes = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "transcode_output_bytes",
+			Help:      "Size of the uploaded ABR output of a transcode",
+			Buckets:   prometheus.ExponentialBuckets(1<<20, 2, 16),
+		},
+	)
+
+	// TranscodeTasksReconciledTotal tracks transcode tasks republished for PROCESSING videos
+	// whose task was lost by the broker.
+	TranscodeTasksReconciledTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transcode_tasks_reconciled_total",
+			Help:      "Total number of lost transcode tasks republished by startup reconciliation",
+		},
+	)
+
+	// OutboxPublishTotal tracks publications of transcode tasks from the outbox.
+	// Labels:
+	//   - result: published, failed (the task stays in the outbox for a retry)
+	OutboxPublishTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "outbox_publish_total",
+			Help:      "Total number of outbox transcode task publications by result",
+		},
+		[]string{"result"},
+	)
+
+	// RenditionsPrunedTotal tracks renditions removed from published output for lack of viewers.
+	RenditionsPrunedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "renditions_pruned_total",
+			Help:      "Total number of unwatched renditions removed from published output",
+		},
+	)
+
+	// RenditionPrunedBytesTotal tracks the storage reclaimed by pruning renditions.
+	RenditionPrunedBytesTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rendition_pruned_bytes_total",
+			Help:      "Total bytes of rendition output removed by pruning",
+		},
+	)
+
+	// ArchiveMovesTotal tracks videos whose output the worker moved between tiers.
+	// Labels:
+	//   - direction: archive, restore
+	ArchiveMovesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "archive_moves_total",
+			Help:      "Total number of video outputs moved to or from the archive tier",
+		},
+		[]string{"direction"},
+	)
+
+	// ArchiveMovedBytesTotal tracks the bytes moved between tiers.
+	// Labels:
+	//   - direction: archive, restore
+	ArchiveMovedBytesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "archive_moved_bytes_total",
+			Help:      "Total bytes of video output moved to or from the archive tier",
+		},
+		[]string{"direction"},
+	)
+
+	// TranscodeTasksExpiredTotal tracks transcode tasks dropped because they were
+	// dequeued after their expiry, e.g. once a backlog drains after an outage.
+	TranscodeTasksExpiredTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transcode_tasks_expired_total",
+			Help:      "Total number of transcode tasks dropped past their expiry",
+		},
+	)
+
+	// TranscodeTasksTimedOutTotal tracks transcode attempts cancelled by their deadline,
+	// typically FFmpeg hanging on a corrupt input.
+	TranscodeTasksTimedOutTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transcode_tasks_timed_out_total",
+			Help:      "Total number of transcode attempts cancelled by their deadline",
+		},
+	)
+
+	// TranscodeEstimateRatio tracks actual/estimated cost of succeeded transcodes,
+	// so the estimator can be calibrated; 1 is a perfect prediction.
+	// Labels:
+	//   - quantity: processing_time, output_bytes
+	TranscodeEstimateRatio = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "transcode_estimate_ratio",
+			Help:      "Ratio of actual to estimated transcode cost",
+			Buckets:   []float64{0.25, 0.5, 0.75, 0.9, 1, 1.1, 1.25, 1.5, 2, 4},
+		},
+		[]string{"quantity"},
+	)
+
+	// SLITranscodesTotal tracks final transcode outcomes for the success-ratio SLI.
+	// Retried attempts are not counted; only the attempt that decides the outcome is.
+	// Labels:
+	//   - result: good (READY), bad (failed with no retries left)
+	SLITranscodesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sli_transcodes_total",
+			Help:      "Total number of final transcode outcomes",
+		},
+		[]string{"result"},
+	)
+
+	// SLITimeToReadySeconds tracks time from processing request to READY, including queueing and retries.
+	// Buckets are aligned with plausible objectives so histogram_quantile stays accurate near the threshold.
+	SLITimeToReadySeconds = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "sli_time_to_ready_seconds",
+			Help:      "Time from processing request until the video is READY",
+			Buckets:   []float64{30, 60, 120, 300, 600, 900, 1200, 1800, 3600, 7200},
+		},
+	)
+
+	// SLIHTTPRequestsTotal tracks API requests for the availability SLI.
+	// Labels:
+	//   - result: good (non-5xx), bad (5xx)
+	SLIHTTPRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sli_http_requests_total",
+			Help:      "Total number of API requests classified for the availability SLI",
+		},
+		[]string{"result"},
+	)
+
+	// HTTPRequestsTotal tracks API requests by outcome.
+	// Labels:
+	//   - method: HTTP method
+	//   - route: chi route pattern (e.g., /v1/videos/{id}), "unmatched" for 404s outside any route
+	//   - status: response status code
+	HTTPRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests handled",
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	// HTTPRequestDurationSeconds tracks API request latency until the handler returns.
+	// Streaming routes (/v1/videos/{id}/events) observe the stream's lifetime.
+	// Labels:
+	//   - method: HTTP method
+	//   - route: chi route pattern
+	HTTPRequestDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to handle HTTP requests",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "route"},
+	)
+
+	// RateLimitChecksTotal tracks API rate limit checks.
+	// Labels:
+	//   - scope: api, create, process
+	//   - result: allowed, limited, error (Redis unavailable; the request is allowed)
+	RateLimitChecksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_checks_total",
+			Help:      "Total number of API rate limit checks by result",
+		},
+		[]string{"scope", "result"},
+	)
+
+	// HTTPRequestsInFlight tracks API requests currently being handled.
+	HTTPRequestsInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests currently being handled",
+		},
+	)
+
+	// SLOObjective exposes configured objectives so burn-rate rules need no hardcoded thresholds.
+	// Labels:
+	//   - sli: transcode_success, time_to_ready, api_availability
+	SLOObjective = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "slo_objective",
+			Help:      "Configured SLO objective (ratio, or seconds for time_to_ready)",
+		},
+		[]string{"sli"},
+	)
+
+	// CDNPurgeAttemptsTotal tracks individual CDN purge API calls.
+	// Labels:
+	//   - provider: cloudfront, fastly, bunny
+	//   - result: success, retry, failure
+	CDNPurgeAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cdn_purge_attempts_total",
+			Help:      "Total number of CDN purge attempts",
+		},
+		[]string{"provider", "result"},
+	)
+
+	// CDNPurgeDurationSeconds tracks end-to-end purge latency, including retries.
+	// Labels:
+	//   - provider: cloudfront, fastly, bunny
+	CDNPurgeDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cdn_purge_duration_seconds",
+			Help:      "Time taken to purge content from the CDN",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"provider"},
+	)
+
+	// AdmissionDecisionsTotal tracks admission control decisions for queued work.
+	// Labels:
+	//   - class: interactive, background
+	//   - result: admitted, shed_failure_rate, shed_backlog
+	AdmissionDecisionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "admission_decisions_total",
+			Help:      "Total number of admission control decisions",
+		},
+		[]string{"class", "result"},
+	)
+
+	// AnalyticsEventsExportedTotal tracks events written to the analytics export.
+	// Replayed partitions are counted again.
+	// Labels:
+	//   - stream: video_lifecycle, playback
+	AnalyticsEventsExportedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "analytics_events_exported_total",
+			Help:      "Total number of analytics events exported to object storage",
+		},
+		[]string{"stream"},
+	)
+
+	// QueuePublishedTotal tracks task messages published, retries included.
+	// Labels:
+	//   - routing_key: queue name, or the transcode routing key for the task exchange
+	//   - status: success, error
+	QueuePublishedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_published_total",
+			Help:      "Total number of task messages published",
+		},
+		[]string{"routing_key", "status"},
+	)
+
+	// QueueConsumedTotal tracks consumed task messages by how they were settled.
+	// A backlog grows while the publish rate exceeds the acked + dead_lettered rate.
+	// Labels:
+	//   - queue: consumed queue the message came from
+	//   - outcome: acked, retried, deferred, dead_lettered
+	QueueConsumedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_consumed_total",
+			Help:      "Total number of task messages consumed",
+		},
+		[]string{"queue", "outcome"},
+	)
+
+	// QueueConnectionsLostTotal tracks RabbitMQ or NATS connections closed by the broker or network.
+	QueueConnectionsLostTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_connections_lost_total",
+			Help:      "Total number of message broker connections lost unexpectedly",
+		},
+	)
+
+	// QueueReconnectsTotal tracks attempts to replace a lost RabbitMQ or NATS connection.
+	// Labels:
+	//   - result: success, failure
+	QueueReconnectsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_reconnects_total",
+			Help:      "Total number of message broker reconnect attempts",
+		},
 		[]string{"result"},
 	)
+
+	// QueueDeadLetteredTotal tracks transcode task messages rejected to the dead-letter queue.
+	// Labels:
+	//   - queue: consumed queue the message came from
+	//   - reason: malformed, republish_failed, permanent_failure
+	QueueDeadLetteredTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_dead_lettered_total",
+			Help:      "Total number of task messages rejected to the dead-letter queue",
+		},
+		[]string{"queue", "reason"},
+	)
+
+	// QueueHandlerPanicsTotal tracks transcode task handlers that panicked and were recovered.
+	// Labels:
+	//   - queue: consumed queue the message came from
+	QueueHandlerPanicsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_handler_panics_total",
+			Help:      "Total number of recovered panics in transcode task handlers",
+		},
+		[]string{"queue"},
+	)
+
+	// PresignedURLsTotal tracks presigned URL requests.
+	// Labels:
+	//   - purpose: upload, probe
+	//   - outcome: issued, rate_limited
+	PresignedURLsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "presigned_urls_total",
+			Help:      "Total number of presigned URL requests by purpose and outcome",
+		},
+		[]string{"purpose", "outcome"},
+	)
 )
 
 // Cache operation status constants.
@@ -55,6 +547,10 @@ const (
 	CacheStatusMiss    = "miss"
 	CacheStatusSuccess = "success"
 	CacheStatusError   = "error"
+	// CacheStatusStale counts entries read past their TTL while the database is unavailable.
+	CacheStatusStale = "stale"
+	// CacheStatusRefresh counts cache hits reloaded before they expired.
+	CacheStatusRefresh = "refresh"
 )
 
 // Cache operation type constants.
@@ -74,11 +570,103 @@ const (
 	DBQuerySelect = "select"
 	DBQueryInsert = "insert"
 	DBQueryUpdate = "update"
+	DBQueryDelete = "delete"
 )
 
 // Table name constants.
 const (
-	TableVideos = "videos"
+	TableVideos            = "videos"
+	TablePlaybackProgress  = "playback_progress"
+	TableEntitlements      = "entitlements"
+	TableTranscodeJobs     = "transcode_jobs"
+	TableCustomDomains     = "custom_domains"
+	TableVideoRenditions   = "video_renditions"
+	TableExportBookmarks   = "analytics_export_bookmarks"
+	TableIssuedURLs        = "issued_urls"
+	TableVideoArchives     = "video_archives"
+	TableVideoSubtitles    = "video_subtitles"
+	TableEncryptionKeys    = "video_encryption_keys"
+	TableOutputBuckets     = "tenant_output_buckets"
+	TableStatusEvents      = "video_status_events"
+	TableUsers             = "users"
+	TableAPIKeys           = "api_keys"
+	TableTranscodeOutbox   = "transcode_task_outbox"
+	TablePlaylists         = "playlists"
+	TablePlaylistItems     = "playlist_items"
+	TableVideoStats        = "video_stats"
+	TableSupersededOutputs = "superseded_outputs"
+)
+
+// Storage operation constants.
+const (
+	StorageOpUpload    = "upload"
+	StorageOpDownload  = "download"
+	StorageOpReplicate = "replicate"
+)
+
+// Entitlement provider constants.
+const (
+	EntitlementProviderLocal = "local"
+	EntitlementProviderHTTP  = "http"
+)
+
+// Entitlement result constants.
+const (
+	EntitlementAllowed = "allowed"
+	EntitlementDenied  = "denied"
+	EntitlementError   = "error"
+)
+
+// Transcode job status constants.
+const (
+	TranscodeJobSucceeded = "succeeded"
+	TranscodeJobFailed    = "failed"
+)
+
+// Archive move direction constants.
+const (
+	ArchiveDirectionArchive = "archive"
+	ArchiveDirectionRestore = "restore"
+)
+
+// SLI result constants.
+const (
+	SLIGood = "good"
+	SLIBad  = "bad"
+)
+
+// SLI name constants.
+const (
+	SLITranscodeSuccess = "transcode_success"
+	SLITimeToReady      = "time_to_ready"
+	SLIAPIAvailability  = "api_availability"
+)
+
+// CDN provider constants.
+const (
+	CDNProviderCloudFront = "cloudfront"
+	CDNProviderFastly     = "fastly"
+	CDNProviderBunny      = "bunny"
+)
+
+// CDN purge result constants.
+const (
+	CDNPurgeSuccess = "success"
+	CDNPurgeRetry   = "retry"
+	CDNPurgeFailure = "failure"
+)
+
+// Admission work class constants.
+const (
+	AdmissionClassInteractive = "interactive"
+	AdmissionClassBackground  = "background"
+)
+
+// Admission result constants.
+const (
+	AdmissionAdmitted        = "admitted"
+	AdmissionShedFailureRate = "shed_failure_rate"
+	AdmissionShedBacklog     = "shed_backlog"
 )
 
 // Singleflight result constants.
@@ -86,3 +674,49 @@ const (
 	SingleflightInitiated = "initiated"
 	SingleflightShared    = "shared"
 )
+
+// Transcode estimate quantity constants.
+const (
+	EstimateProcessingTime = "processing_time"
+	EstimateOutputBytes    = "output_bytes"
+)
+
+// Queue publish status constants.
+const (
+	QueuePublishSuccess = "success"
+	QueuePublishError   = "error"
+)
+
+// Queue consume outcome constants.
+const (
+	QueueOutcomeAcked        = "acked"
+	QueueOutcomeRetried      = "retried"
+	QueueOutcomeDeferred     = "deferred"
+	QueueOutcomeDeadLettered = "dead_lettered"
+)
+
+// Queue reconnect result constants.
+const (
+	QueueReconnectSuccess = "success"
+	QueueReconnectFailure = "failure"
+)
+
+// Dead-letter reason constants.
+const (
+	DeadLetterMalformed        = "malformed"
+	DeadLetterRepublishFailed  = "republish_failed"
+	DeadLetterPermanentFailure = "permanent_failure"
+)
+
+// Rate limit check result constants.
+const (
+	RateLimitAllowed = "allowed"
+	RateLimitLimited = "limited"
+	RateLimitError   = "error"
+)
+
+// Presigned URL outcome constants.
+const (
+	PresignedURLIssued      = "issued"
+	PresignedURLRateLimited = "rate_limited"
+)
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// AnalyticsRepository implements repository.AnalyticsEventSource using PostgreSQL.
+// Events are derived from the tables of record rather than a separate event log, so a
+// window can be re-read at any time. Playback progress keeps only the latest beacon per
+// user and video, so an older window loses beacons that have since been superseded.
+type AnalyticsRepository struct {
+	db DBTX
+}
+
+// NewAnalyticsRepository creates a new AnalyticsRepository instance.
+func NewAnalyticsRepository(db DBTX) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// ListEvents returns the events of stream that occurred in [from, to), oldest first.
+func (r *AnalyticsRepository) ListEvents(ctx context.Context, stream model.AnalyticsStream, from, to time.Time) ([]*model.AnalyticsEvent, error) {
+	switch stream {
+	case model.AnalyticsStreamVideoLifecycle:
+		return r.listLifecycleEvents(ctx, from, to)
+	case model.AnalyticsStreamPlayback:
+		return r.listPlaybackEvents(ctx, from, to)
+	default:
+		return nil, fmt.Errorf("unknown analytics stream %q", stream)
+	}
+}
+
+// listLifecycleEvents reads video creations and transcode attempts.
+func (r *AnalyticsRepository) listLifecycleEvents(ctx context.Context, from, to time.Time) ([]*model.AnalyticsEvent, error) {
+	const query = `
+		SELECT occurred_at, video_id, user_id, job_status, attempt, output_version, final, error, duration_ms
+		FROM (
+			SELECT created_at AS occurred_at, id AS video_id, user_id,
+			       NULL::text AS job_status, NULL::int AS attempt, NULL::bigint AS output_version,
+			       NULL::boolean AS final, NULL::text AS error, NULL::bigint AS duration_ms
+			FROM videos
+			WHERE created_at >= $1 AND created_at < $2
+			UNION ALL
+			SELECT j.finished_at, j.video_id, v.user_id,
+			       j.status, j.attempt, j.output_version,
+			       j.final, j.error, (EXTRACT(EPOCH FROM j.finished_at - j.started_at) * 1000)::bigint
+			FROM transcode_jobs j
+			JOIN videos v ON v.id = j.video_id
+			WHERE j.finished_at >= $1 AND j.finished_at < $2
+		) e
+		ORDER BY occurred_at, video_id
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableTranscodeJobs).Inc()
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lifecycle events: %w", classify(err))
+	}
+	defer rows.Close()
+
+	events := []*model.AnalyticsEvent{}
+	for rows.Next() {
+		var (
+			event         model.AnalyticsEvent
+			jobStatus     *string
+			attempt       *int
+			outputVersion *int64
+			final         *bool
+			jobErr        *string
+			durationMs    *int64
+		)
+		if err := rows.Scan(
+			&event.OccurredAt,
+			&event.VideoID,
+			&event.UserID,
+			&jobStatus,
+			&attempt,
+			&outputVersion,
+			&final,
+			&jobErr,
+			&durationMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan lifecycle event: %w", classify(err))
+		}
+
+		if jobStatus == nil {
+			event.Type = model.EventVideoCreated
+		} else {
+			event.Type = model.EventTranscodeFailed
+			if model.TranscodeJobStatus(*jobStatus) == model.JobStatusSucceeded {
+				event.Type = model.EventTranscodeSucceeded
+			}
+			event.Attributes = map[string]any{
+				"attempt":        derefOr(attempt, 0),
+				"output_version": derefOr(outputVersion, 0),
+				"final":          derefOr(final, false),
+				"duration_ms":    derefOr(durationMs, 0),
+			}
+			if jobErr != nil {
+				event.Attributes["error"] = *jobErr
+			}
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating lifecycle events: %w", classify(err))
+	}
+
+	return events, nil
+}
+
+// listPlaybackEvents reads the playback progress beacons last recorded in the window.
+func (r *AnalyticsRepository) listPlaybackEvents(ctx context.Context, from, to time.Time) ([]*model.AnalyticsEvent, error) {
+	const query = `
+		SELECT updated_at, video_id, user_id, position_ms
+		FROM playback_progress
+		WHERE updated_at >= $1 AND updated_at < $2 AND removed_at IS NULL
+		ORDER BY updated_at, user_id, video_id
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TablePlaybackProgress).Inc()
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query playback events: %w", classify(err))
+	}
+	defer rows.Close()
+
+	events := []*model.AnalyticsEvent{}
+	for rows.Next() {
+		var (
+			occurredAt time.Time
+			videoID    uuid.UUID
+			userID     uuid.UUID
+			positionMs int64
+		)
+		if err := rows.Scan(&occurredAt, &videoID, &userID, &positionMs); err != nil {
+			return nil, fmt.Errorf("failed to scan playback event: %w", classify(err))
+		}
+		events = append(events, &model.AnalyticsEvent{
+			Type:       model.EventPlaybackProgress,
+			OccurredAt: occurredAt,
+			VideoID:    videoID,
+			UserID:     userID,
+			Attributes: map[string]any{"position_ms": positionMs},
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating playback events: %w", classify(err))
+	}
+
+	return events, nil
+}
+
+// derefOr returns *p, or fallback when p is nil.
+func derefOr[T any](p *T, fallback T) T {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
+
+// Compile-time verification that AnalyticsRepository implements repository.AnalyticsEventSource.
+var _ repository.AnalyticsEventSource = (*AnalyticsRepository)(nil)
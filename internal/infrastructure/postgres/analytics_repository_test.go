@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestAnalyticsRepository_ListEvents_VideoLifecycle(t *testing.T) {
+	from := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	var (
+		failed, succeeded   = "FAILED", "SUCCEEDED"
+		first, second       = 1, 2
+		outputVersion       = int64(1)
+		notFinal, isFinal   = false, true
+		failedMs, succeedMs = int64(5000), int64(8000)
+		jobErr              = "ffmpeg exited with status 1"
+	)
+	rows := pgxmock.NewRows([]string{"occurred_at", "video_id", "user_id", "job_status", "attempt", "output_version", "final", "error", "duration_ms"}).
+		AddRow(from.Add(time.Minute), videoID, userID, nil, nil, nil, nil, nil, nil).
+		AddRow(from.Add(2*time.Minute), videoID, userID, &failed, &first, &outputVersion, &notFinal, &jobErr, &failedMs).
+		AddRow(from.Add(3*time.Minute), videoID, userID, &succeeded, &second, &outputVersion, &isFinal, nil, &succeedMs)
+	mock.ExpectQuery(`SELECT occurred_at, video_id, user_id, .* FROM videos .* UNION ALL .* FROM transcode_jobs`).
+		WithArgs(from, to).
+		WillReturnRows(rows)
+
+	repo := NewAnalyticsRepository(mock)
+	events, err := repo.ListEvents(context.Background(), model.AnalyticsStreamVideoLifecycle, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantTypes := []string{model.EventVideoCreated, model.EventTranscodeFailed, model.EventTranscodeSucceeded}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("events: got %d, expected %d", len(events), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if events[i].Type != want {
+			t.Errorf("event %d type: got %q, expected %q", i, events[i].Type, want)
+		}
+	}
+	if events[0].Attributes != nil {
+		t.Errorf("created event should have no attributes, got %v", events[0].Attributes)
+	}
+	if events[1].Attributes["error"] != jobErr || events[1].Attributes["attempt"] != 1 {
+		t.Errorf("unexpected failed attributes: %v", events[1].Attributes)
+	}
+	if _, ok := events[2].Attributes["error"]; ok {
+		t.Error("succeeded event should not carry an error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAnalyticsRepository_ListEvents_Playback(t *testing.T) {
+	from := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		queryErr error
+		wantErr  bool
+	}{
+		{name: "returns progress beacons"},
+		{name: "database error", queryErr: errors.New("connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			query := mock.ExpectQuery(`SELECT updated_at, video_id, user_id, position_ms FROM playback_progress`).WithArgs(from, to)
+			if tt.queryErr != nil {
+				query.WillReturnError(tt.queryErr)
+			} else {
+				query.WillReturnRows(pgxmock.NewRows([]string{"updated_at", "video_id", "user_id", "position_ms"}).
+					AddRow(from.Add(time.Minute), uuid.New(), uuid.New(), int64(42000)))
+			}
+
+			repo := NewAnalyticsRepository(mock)
+			events, err := repo.ListEvents(context.Background(), model.AnalyticsStreamPlayback, from, to)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				if len(events) != 1 || events[0].Type != model.EventPlaybackProgress || events[0].Attributes["position_ms"] != int64(42000) {
+					t.Errorf("unexpected events: %+v", events)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAnalyticsRepository_ListEvents_UnknownStream(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	repo := NewAnalyticsRepository(mock)
+	if _, err := repo.ListEvents(context.Background(), "unknown", time.Now(), time.Now()); err == nil {
+		t.Error("expected error for unknown stream")
+	}
+}
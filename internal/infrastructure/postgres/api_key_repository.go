@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const apiKeyColumns = `id, user_id, name, hint, key_hash, created_at, last_used_at, revoked_at`
+
+// APIKeyRepository implements repository.APIKeyRepository using PostgreSQL.
+type APIKeyRepository struct {
+	db DBTX
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository instance.
+func NewAPIKeyRepository(db DBTX) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create persists a new API key.
+func (r *APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	const query = `
+		INSERT INTO api_keys (` + apiKeyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableAPIKeys).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		key.ID,
+		key.UserID,
+		nullString(key.Name),
+		key.Hint,
+		key.Hash,
+		key.CreatedAt,
+		key.LastUsedAt,
+		key.RevokedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", classify(err))
+	}
+
+	return nil
+}
+
+// GetByHash retrieves the key with the given hash, revoked or not.
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	const query = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableAPIKeys).Inc()
+
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, hash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key by hash: %w", classify(err))
+	}
+
+	return key, nil
+}
+
+// ListByUser retrieves every key of a user, oldest first.
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	const query = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableAPIKeys).Inc()
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", classify(err))
+	}
+	defer rows.Close()
+
+	keys := []*model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", classify(err))
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating API keys: %w", classify(err))
+	}
+
+	return keys, nil
+}
+
+// Revoke marks a key of the user revoked, keeping an earlier revocation time.
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, keyID uuid.UUID, at time.Time) (*model.APIKey, error) {
+	const query = `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, $3)
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + apiKeyColumns
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableAPIKeys).Inc()
+
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, keyID, userID, at))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to revoke API key: %w", classify(err))
+	}
+
+	return key, nil
+}
+
+// TouchLastUsed records that the key authenticated a request.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	const query = `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableAPIKeys).Inc()
+
+	if _, err := r.db.Exec(ctx, query, keyID, at); err != nil {
+		return fmt.Errorf("failed to touch API key: %w", classify(err))
+	}
+
+	return nil
+}
+
+// scanAPIKey scans a single row into an APIKey model.
+// pgx.Rows satisfies pgx.Row, so this serves both QueryRow and Query results.
+func scanAPIKey(row pgx.Row) (*model.APIKey, error) {
+	var (
+		key  model.APIKey
+		name *string
+	)
+
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&name,
+		&key.Hint,
+		&key.Hash,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		key.Name = *name
+	}
+
+	return &key, nil
+}
+
+// Compile-time verification that APIKeyRepository implements repository.APIKeyRepository.
+var _ repository.APIKeyRepository = (*APIKeyRepository)(nil)
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var apiKeyColumnNames = []string{
+	"id", "user_id", "name", "hint", "key_hash", "created_at", "last_used_at", "revoked_at",
+}
+
+func TestAPIKeyRepository_Create(t *testing.T) {
+	key, _, err := model.NewAPIKey(uuid.New(), "ci")
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec("INSERT INTO api_keys").
+		WithArgs(key.ID, key.UserID, pgxmock.AnyArg(), key.Hint, key.Hash, key.CreatedAt, key.LastUsedAt, key.RevokedAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	if err := NewAPIKeyRepository(mock).Create(context.Background(), key); err != nil {
+		t.Fatalf("Create() unexpected error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAPIKeyRepository_GetByHash(t *testing.T) {
+	keyID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM api_keys WHERE key_hash").
+					WithArgs("hash").
+					WillReturnRows(pgxmock.NewRows(apiKeyColumnNames).
+						AddRow(keyID, userID, (*string)(nil), "gsk_abcdef", "hash", now, (*time.Time)(nil), (*time.Time)(nil)))
+			},
+		},
+		{
+			name: "not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM api_keys WHERE key_hash").
+					WithArgs("hash").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			got, err := NewAPIKeyRepository(mock).GetByHash(context.Background(), "hash")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetByHash() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByHash() unexpected error = %v", err)
+			}
+			if got.ID != keyID || got.UserID != userID || got.Name != "" || got.IsRevoked() {
+				t.Errorf("GetByHash() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	keyID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+	name := "ci"
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "revoked",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE api_keys SET revoked_at = COALESCE").
+					WithArgs(keyID, userID, now).
+					WillReturnRows(pgxmock.NewRows(apiKeyColumnNames).
+						AddRow(keyID, userID, &name, "gsk_abcdef", "hash", now, (*time.Time)(nil), &now))
+			},
+		},
+		{
+			name: "key of another user",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE api_keys SET revoked_at = COALESCE").
+					WithArgs(keyID, userID, now).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			got, err := NewAPIKeyRepository(mock).Revoke(context.Background(), userID, keyID, now)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Revoke() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Revoke() unexpected error = %v", err)
+			}
+			if !got.IsRevoked() || got.Name != "ci" {
+				t.Errorf("Revoke() = %+v", got)
+			}
+		})
+	}
+}
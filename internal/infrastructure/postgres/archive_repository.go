@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const archiveColumns = `video_id, output_version, prefix, bytes, archived_at, restore_requested_at, restore_eta, created_at`
+
+// ArchiveRepository implements repository.ArchiveRepository using PostgreSQL.
+type ArchiveRepository struct {
+	db DBTX
+}
+
+// NewArchiveRepository creates a new ArchiveRepository instance.
+func NewArchiveRepository(db DBTX) *ArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// Save upserts the archive record; a replaced record loses its lease and restore request.
+func (r *ArchiveRepository) Save(ctx context.Context, archive *model.VideoArchive) error {
+	const query = `
+		INSERT INTO video_archives (` + archiveColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (video_id) DO UPDATE SET
+			output_version = EXCLUDED.output_version,
+			prefix = EXCLUDED.prefix,
+			bytes = EXCLUDED.bytes,
+			archived_at = EXCLUDED.archived_at,
+			restore_requested_at = EXCLUDED.restore_requested_at,
+			restore_eta = EXCLUDED.restore_eta,
+			leased_until = NULL,
+			created_at = EXCLUDED.created_at
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableVideoArchives).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		archive.VideoID,
+		archive.OutputVersion,
+		archive.Prefix,
+		archive.Bytes,
+		archive.ArchivedAt,
+		archive.RestoreRequestedAt,
+		archive.RestoreETA,
+		archive.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save archive: %w", classify(err))
+	}
+
+	return nil
+}
+
+// GetByVideoID retrieves the archive record of a video.
+func (r *ArchiveRepository) GetByVideoID(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	const query = `
+		SELECT ` + archiveColumns + `
+		FROM video_archives
+		WHERE video_id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideoArchives).Inc()
+
+	archive, err := scanArchive(r.db.QueryRow(ctx, query, videoID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrArchiveNotFound
+		}
+		return nil, fmt.Errorf("failed to get archive: %w", classify(err))
+	}
+
+	return archive, nil
+}
+
+// RequestRestore sets the restore columns only where they are empty, so concurrent
+// requests agree on the first ETA without a read-modify-write.
+func (r *ArchiveRepository) RequestRestore(ctx context.Context, videoID uuid.UUID, requestedAt, eta time.Time) (*model.VideoArchive, error) {
+	const query = `
+		UPDATE video_archives
+		SET restore_requested_at = COALESCE(restore_requested_at, $2),
+			restore_eta = COALESCE(restore_eta, $3)
+		WHERE video_id = $1
+		RETURNING ` + archiveColumns
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideoArchives).Inc()
+
+	archive, err := scanArchive(r.db.QueryRow(ctx, query, videoID, requestedAt, eta))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrArchiveNotFound
+		}
+		return nil, fmt.Errorf("failed to request restore: %w", classify(err))
+	}
+
+	return archive, nil
+}
+
+// ClaimNext leases one pending record in a single statement. SKIP LOCKED lets workers
+// claiming at the same time pick different records instead of waiting on each other.
+func (r *ArchiveRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*model.VideoArchive, error) {
+	const query = `
+		UPDATE video_archives
+		SET leased_until = $2
+		WHERE video_id = (
+			SELECT video_id
+			FROM video_archives
+			WHERE (archived_at IS NULL OR restore_requested_at IS NOT NULL)
+				AND (leased_until IS NULL OR leased_until <= $1)
+			ORDER BY restore_requested_at IS NULL, COALESCE(restore_requested_at, created_at)
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + archiveColumns
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideoArchives).Inc()
+
+	archive, err := scanArchive(r.db.QueryRow(ctx, query, now, leaseUntil))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrArchiveNotFound
+		}
+		return nil, fmt.Errorf("failed to claim archive: %w", classify(err))
+	}
+
+	return archive, nil
+}
+
+// MarkArchived records the moved objects and releases the lease.
+func (r *ArchiveRepository) MarkArchived(ctx context.Context, videoID uuid.UUID, bytes int64, at time.Time) error {
+	const query = `
+		UPDATE video_archives
+		SET bytes = $2, archived_at = $3, leased_until = NULL
+		WHERE video_id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideoArchives).Inc()
+
+	tag, err := r.db.Exec(ctx, query, videoID, bytes, at)
+	if err != nil {
+		return fmt.Errorf("failed to mark archived: %w", classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrArchiveNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the archive record if it is still the one created at createdAt.
+// A record already gone or replaced is not an error.
+func (r *ArchiveRepository) Delete(ctx context.Context, videoID uuid.UUID, createdAt time.Time) error {
+	const query = `DELETE FROM video_archives WHERE video_id = $1 AND created_at = $2`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TableVideoArchives).Inc()
+
+	if _, err := r.db.Exec(ctx, query, videoID, createdAt); err != nil {
+		return fmt.Errorf("failed to delete archive: %w", classify(err))
+	}
+
+	return nil
+}
+
+// scanArchive scans a single row into a VideoArchive model.
+func scanArchive(row pgx.Row) (*model.VideoArchive, error) {
+	var archive model.VideoArchive
+
+	err := row.Scan(
+		&archive.VideoID,
+		&archive.OutputVersion,
+		&archive.Prefix,
+		&archive.Bytes,
+		&archive.ArchivedAt,
+		&archive.RestoreRequestedAt,
+		&archive.RestoreETA,
+		&archive.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archive, nil
+}
+
+// Compile-time verification that ArchiveRepository implements repository.ArchiveRepository.
+var _ repository.ArchiveRepository = (*ArchiveRepository)(nil)
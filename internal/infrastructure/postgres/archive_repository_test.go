@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var archiveColumnNames = []string{
+	"video_id", "output_version", "prefix", "bytes", "archived_at", "restore_requested_at", "restore_eta", "created_at",
+}
+
+func TestArchiveRepository_RequestRestore(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Now()
+	eta := now.Add(10 * time.Minute)
+	firstRequest := now.Add(-time.Hour)
+	firstETA := firstRequest.Add(10 * time.Minute)
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantETA time.Time
+		wantErr error
+	}{
+		{
+			name: "first request",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE video_archives SET restore_requested_at = COALESCE").
+					WithArgs(videoID, now, eta).
+					WillReturnRows(pgxmock.NewRows(archiveColumnNames).AddRow(
+						videoID, int64(7), "hls/abc/v7/", int64(4096), &firstRequest, &now, &eta, firstRequest,
+					))
+			},
+			wantETA: eta,
+		},
+		{
+			name: "pending request keeps its ETA",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE video_archives SET restore_requested_at = COALESCE").
+					WithArgs(videoID, now, eta).
+					WillReturnRows(pgxmock.NewRows(archiveColumnNames).AddRow(
+						videoID, int64(7), "hls/abc/v7/", int64(4096), &firstRequest, &firstRequest, &firstETA, firstRequest,
+					))
+			},
+			wantETA: firstETA,
+		},
+		{
+			name: "not archived",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE video_archives SET restore_requested_at = COALESCE").
+					WithArgs(videoID, now, eta).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrArchiveNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewArchiveRepository(mock)
+			got, err := repo.RequestRestore(context.Background(), videoID, now, eta)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("RequestRestore() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RequestRestore() unexpected error = %v", err)
+			}
+
+			if got.RestoreETA == nil || !got.RestoreETA.Equal(tt.wantETA) || !got.IsArchived() || !got.IsRestoring() {
+				t.Errorf("RequestRestore() = %+v", got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestArchiveRepository_ClaimNext(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Now()
+	leaseUntil := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "pending archive",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE video_archives SET leased_until = .* FOR UPDATE SKIP LOCKED").
+					WithArgs(now, leaseUntil).
+					WillReturnRows(pgxmock.NewRows(archiveColumnNames).AddRow(
+						videoID, int64(7), "hls/abc/v7/", int64(0), nil, nil, nil, now,
+					))
+			},
+		},
+		{
+			name: "nothing pending",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE video_archives SET leased_until").
+					WithArgs(now, leaseUntil).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrArchiveNotFound,
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE video_archives SET leased_until").
+					WithArgs(now, leaseUntil).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: errors.New("failed to claim archive"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewArchiveRepository(mock)
+			got, err := repo.ClaimNext(context.Background(), now, leaseUntil)
+
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && !containsError(err, tt.wantErr)) {
+					t.Errorf("ClaimNext() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ClaimNext() unexpected error = %v", err)
+			}
+
+			if got.VideoID != videoID || got.Prefix != "hls/abc/v7/" || got.IsArchived() || got.IsRestoring() {
+				t.Errorf("ClaimNext() = %+v", got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestArchiveRepository_MarkArchived(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "marked", affected: 1},
+		{name: "record gone", affected: 0, wantErr: repository.ErrArchiveNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			mock.ExpectExec("UPDATE video_archives SET bytes = \\$2, archived_at = \\$3, leased_until = NULL").
+				WithArgs(videoID, int64(4096), now).
+				WillReturnResult(pgxmock.NewResult("UPDATE", tt.affected))
+
+			repo := NewArchiveRepository(mock)
+			err = repo.MarkArchived(context.Background(), videoID, 4096, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("MarkArchived() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
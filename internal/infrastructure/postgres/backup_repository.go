@@ -0,0 +1,298 @@
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// backupTables lists the tables covered by a metadata backup, parents before
+// children so a restore satisfies foreign keys.
+var backupTables = []string{
+	"users",
+	"api_keys",
+	"videos",
+	"video_encryption_keys",
+	"transcode_jobs",
+	"video_renditions",
+	"playback_progress",
+	"entitlements",
+	"custom_domains",
+	"analytics_export_bookmarks",
+	"issued_urls",
+	"playlists",
+	"playlist_items",
+	"video_stats",
+}
+
+// restoreBatchSize is the number of rows inserted per statement during a restore.
+const restoreBatchSize = 500
+
+// maxBackupLineSize bounds a single dumped row.
+const maxBackupLineSize = 16 << 20
+
+// TxDBTX is a DBTX that can also start transactions, such as pgxpool.Pool.
+type TxDBTX interface {
+	DBTX
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// BackupRepository implements repository.MetadataBackupRepository using PostgreSQL.
+// Rows are dumped with row_to_json and restored with json_populate_recordset, so a
+// backup only restores into a database at the same migration version.
+type BackupRepository struct {
+	db TxDBTX
+}
+
+// NewBackupRepository creates a new BackupRepository instance.
+func NewBackupRepository(db TxDBTX) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// Dump reads every table inside one read-only REPEATABLE READ transaction, so the
+// tables and storage keys are consistent with each other without blocking writers.
+func (r *BackupRepository) Dump(ctx context.Context, create func(table string) (io.WriteCloser, error)) (*repository.MetadataSnapshot, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &repository.MetadataSnapshot{
+		SchemaVersion: version,
+		Rows:          make(map[string]int64, len(backupTables)),
+	}
+	for _, table := range backupTables {
+		n, err := dumpTable(ctx, tx, table, create)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Rows[table] = n
+	}
+
+	snapshot.StorageKeys, err = storageKeys(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// dumpTable writes one table as JSON Lines.
+func dumpTable(ctx context.Context, db DBTX, table string, create func(table string) (io.WriteCloser, error)) (n int64, err error) {
+	w, err := create(table)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s dump: %w", table, err)
+	}
+	defer func() {
+		if cerr := w.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s dump: %w", table, cerr)
+		}
+	}()
+
+	rows, err := db.Query(ctx, fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t", pgx.Identifier{table}.Sanitize()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	bw := bufio.NewWriter(w)
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return n, fmt.Errorf("failed to scan %s row: %w", table, err)
+		}
+		if _, err := bw.WriteString(row + "\n"); err != nil {
+			return n, fmt.Errorf("failed to write %s row: %w", table, err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, fmt.Errorf("error iterating %s: %w", table, err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return n, fmt.Errorf("failed to write %s dump: %w", table, err)
+	}
+	return n, nil
+}
+
+// storageKeys returns the objects referenced by the metadata: uploaded originals,
+// published master, DASH and preview manifests, thumbnails, and the variant playlists of
+// the published output version. Originals of videos still awaiting upload are skipped because they
+// may never have been written.
+func storageKeys(ctx context.Context, db DBTX) ([]string, error) {
+	const query = `
+		SELECT original_url FROM videos WHERE original_url IS NOT NULL AND status <> 'PENDING_UPLOAD'
+		UNION
+		SELECT hls_url FROM videos WHERE hls_url IS NOT NULL
+		UNION
+		SELECT dash_url FROM videos WHERE dash_url IS NOT NULL
+		UNION
+		SELECT preview_url FROM videos WHERE preview_url IS NOT NULL
+		UNION
+		SELECT rtrim(v.thumbnail_prefix, '/') || '/' || s.size || '.jpg'
+		FROM videos v, unnest($1::text[]) AS s(size)
+		WHERE v.thumbnail_prefix IS NOT NULL
+		UNION
+		SELECT regexp_replace(v.hls_url, '[^/]*$', '') || r.name || '/playlist.m3u8'
+		FROM video_renditions r
+		JOIN videos v ON v.id = r.video_id AND v.output_version = r.output_version
+		WHERE v.hls_url IS NOT NULL
+		ORDER BY 1
+	`
+
+	rows, err := db.Query(ctx, query, model.ThumbnailSizes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query storage keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan storage key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating storage keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Restore refuses to load into tables that already have rows rather than merging,
+// so a restore can never silently mix two states of the service.
+func (r *BackupRepository) Restore(ctx context.Context, open func(table string) (io.ReadCloser, error)) (map[string]int64, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, table := range backupTables {
+		var exists bool
+		query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s)", pgx.Identifier{table}.Sanitize())
+		if err := tx.QueryRow(ctx, query).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", table, err)
+		}
+		if exists {
+			return nil, fmt.Errorf("%s: %w", table, repository.ErrRestoreTargetNotEmpty)
+		}
+	}
+
+	loaded := make(map[string]int64, len(backupTables))
+	for _, table := range backupTables {
+		n, err := restoreTable(ctx, tx, table, open)
+		if err != nil {
+			return nil, err
+		}
+		loaded[table] = n
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return loaded, nil
+}
+
+// restoreTable inserts one table's JSON Lines dump in batches.
+func restoreTable(ctx context.Context, db DBTX, table string, open func(table string) (io.ReadCloser, error)) (int64, error) {
+	rc, err := open(table)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s dump: %w", table, err)
+	}
+	defer rc.Close()
+
+	ident := pgx.Identifier{table}.Sanitize()
+	query := fmt.Sprintf("INSERT INTO %s SELECT * FROM json_populate_recordset(NULL::%s, $1::json)", ident, ident)
+
+	var (
+		n     int64
+		batch []json.RawMessage
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		rows, err := json.Marshal(batch)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s batch: %w", table, err)
+		}
+		if _, err := db.Exec(ctx, query, string(rows)); err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", table, err)
+		}
+		n += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 64*1024), maxBackupLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return n, fmt.Errorf("invalid row in %s dump at row %d", table, n+int64(len(batch))+1)
+		}
+		batch = append(batch, json.RawMessage(append([]byte(nil), line...)))
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return n, fmt.Errorf("failed to read %s dump: %w", table, err)
+	}
+	if err := flush(); err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
+
+// SchemaVersion returns the version recorded by golang-migrate.
+func (r *BackupRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	return schemaVersion(ctx, r.db)
+}
+
+func schemaVersion(ctx context.Context, db DBTX) (int64, error) {
+	const query = `SELECT version, dirty FROM schema_migrations`
+
+	var (
+		version int64
+		dirty   bool
+	)
+	if err := db.QueryRow(ctx, query).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errors.New("database has no applied migrations")
+		}
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d: %w", version, repository.ErrSchemaDirty)
+	}
+	return version, nil
+}
+
+// Compile-time verification that BackupRepository implements repository.MetadataBackupRepository.
+var _ repository.MetadataBackupRepository = (*BackupRepository)(nil)
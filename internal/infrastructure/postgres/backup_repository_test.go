@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error { return nil }
+
+func TestBackupRepository_Dump(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(13), false))
+	for _, table := range backupTables {
+		rows := pgxmock.NewRows([]string{"row_to_json"})
+		if table == "videos" {
+			rows.AddRow(`{"id":"a"}`).AddRow(`{"id":"b"}`)
+		}
+		mock.ExpectQuery(`SELECT row_to_json\(t\)::text FROM "` + table + `" t`).WillReturnRows(rows)
+	}
+	mock.ExpectQuery(`SELECT original_url FROM videos .* UNION .* FROM video_renditions`).
+		WithArgs(model.ThumbnailSizes).
+		WillReturnRows(pgxmock.NewRows([]string{"key"}).AddRow("hls/a/v1/720p/playlist.m3u8").AddRow("hls/a/v1/master.m3u8"))
+	mock.ExpectRollback()
+
+	dumps := make(map[string]*bufferCloser)
+	repo := NewBackupRepository(mock)
+	snapshot, err := repo.Dump(context.Background(), func(table string) (io.WriteCloser, error) {
+		dumps[table] = &bufferCloser{}
+		return dumps[table], nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if snapshot.SchemaVersion != 13 {
+		t.Errorf("schema version: got %d, expected 13", snapshot.SchemaVersion)
+	}
+	if snapshot.Rows["videos"] != 2 || snapshot.Rows["transcode_jobs"] != 0 {
+		t.Errorf("unexpected row counts: %v", snapshot.Rows)
+	}
+	if len(snapshot.StorageKeys) != 2 {
+		t.Errorf("storage keys: got %v", snapshot.StorageKeys)
+	}
+	if got := dumps["videos"].String(); got != "{\"id\":\"a\"}\n{\"id\":\"b\"}\n" {
+		t.Errorf("videos dump: got %q", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestBackupRepository_Dump_DirtySchema(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(13), true))
+	mock.ExpectRollback()
+
+	repo := NewBackupRepository(mock)
+	_, err = repo.Dump(context.Background(), func(table string) (io.WriteCloser, error) {
+		t.Errorf("no table should be dumped, got %s", table)
+		return &bufferCloser{}, nil
+	})
+	if !errors.Is(err, repository.ErrSchemaDirty) {
+		t.Errorf("expected ErrSchemaDirty, got %v", err)
+	}
+}
+
+func TestBackupRepository_Restore(t *testing.T) {
+	tests := []struct {
+		name      string
+		nonEmpty  string
+		insertErr error
+		wantErr   error
+	}{
+		{name: "loads every table"},
+		{name: "target not empty", nonEmpty: "custom_domains", wantErr: repository.ErrRestoreTargetNotEmpty},
+		{name: "insert fails", insertErr: errors.New("foreign key violation")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			mock.ExpectBeginTx(pgx.TxOptions{})
+			for _, table := range backupTables {
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM "` + table + `"\)`).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(table == tt.nonEmpty))
+				if table == tt.nonEmpty {
+					break
+				}
+			}
+			if tt.nonEmpty == "" {
+				insert := mock.ExpectExec(`INSERT INTO "videos" SELECT \* FROM json_populate_recordset\(NULL::"videos", \$1::json\)`).
+					WithArgs(`[{"id":"a"},{"id":"b"}]`)
+				if tt.insertErr != nil {
+					insert.WillReturnError(tt.insertErr)
+				} else {
+					insert.WillReturnResult(pgxmock.NewResult("INSERT", 2))
+					mock.ExpectCommit()
+				}
+			}
+			if tt.nonEmpty != "" || tt.insertErr != nil {
+				mock.ExpectRollback()
+			}
+
+			repo := NewBackupRepository(mock)
+			loaded, err := repo.Restore(context.Background(), func(table string) (io.ReadCloser, error) {
+				if table == "videos" {
+					return io.NopCloser(strings.NewReader("{\"id\":\"a\"}\n\n{\"id\":\"b\"}\n")), nil
+				}
+				return io.NopCloser(strings.NewReader("")), nil
+			})
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+			case tt.insertErr != nil:
+				if !errors.Is(err, tt.insertErr) {
+					t.Fatalf("expected error %v, got %v", tt.insertErr, err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if loaded["videos"] != 2 || len(loaded) != len(backupTables) {
+					t.Errorf("unexpected loaded rows: %v", loaded)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -69,6 +69,15 @@ func (c *Client) Ping(ctx context.Context) error {
 	return c.pool.Ping(ctx)
 }
 
+// Now returns the database server's current time, for measuring clock skew.
+func (c *Client) Now(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	if err := c.pool.QueryRow(ctx, "SELECT now()").Scan(&now); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read database time: %w", classify(err))
+	}
+	return now, nil
+}
+
 // Close closes all connections in the pool.
 func (c *Client) Close() {
 	c.pool.Close()
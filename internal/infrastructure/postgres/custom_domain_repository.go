@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const customDomainColumns = `id, tenant_id, hostname, certificate_id, verification_token, status, verified_at, created_at, updated_at`
+
+// CustomDomainRepository implements repository.CustomDomainRepository using PostgreSQL.
+type CustomDomainRepository struct {
+	db DBTX
+}
+
+// NewCustomDomainRepository creates a new CustomDomainRepository instance.
+func NewCustomDomainRepository(db DBTX) *CustomDomainRepository {
+	return &CustomDomainRepository{db: db}
+}
+
+// Create persists a new custom domain.
+func (r *CustomDomainRepository) Create(ctx context.Context, domain *model.CustomDomain) error {
+	const query = `
+		INSERT INTO custom_domains (` + customDomainColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableCustomDomains).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		domain.ID,
+		domain.TenantID,
+		domain.Hostname,
+		nullString(domain.CertificateID),
+		domain.VerificationToken,
+		domain.Status.String(),
+		domain.VerifiedAt,
+		domain.CreatedAt,
+		domain.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrDuplicateCustomDomain
+		}
+		return fmt.Errorf("failed to create custom domain: %w", classify(err))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a custom domain by its unique identifier.
+func (r *CustomDomainRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.CustomDomain, error) {
+	const query = `
+		SELECT ` + customDomainColumns + `
+		FROM custom_domains
+		WHERE id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableCustomDomains).Inc()
+
+	domain, err := scanCustomDomain(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrCustomDomainNotFound
+		}
+		return nil, fmt.Errorf("failed to get custom domain by ID: %w", classify(err))
+	}
+
+	return domain, nil
+}
+
+// ListByTenant retrieves all domains registered by a tenant, oldest first.
+func (r *CustomDomainRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error) {
+	const query = `
+		SELECT ` + customDomainColumns + `
+		FROM custom_domains
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableCustomDomains).Inc()
+
+	rows, err := r.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom domains: %w", classify(err))
+	}
+	defer rows.Close()
+
+	domains := []*model.CustomDomain{}
+	for rows.Next() {
+		domain, err := scanCustomDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom domain: %w", classify(err))
+		}
+		domains = append(domains, domain)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custom domains: %w", classify(err))
+	}
+
+	return domains, nil
+}
+
+// GetPlaybackDomain retrieves the earliest verified domain with a certificate attached.
+func (r *CustomDomainRepository) GetPlaybackDomain(ctx context.Context, tenantID uuid.UUID) (*model.CustomDomain, error) {
+	const query = `
+		SELECT ` + customDomainColumns + `
+		FROM custom_domains
+		WHERE tenant_id = $1 AND status = $2 AND certificate_id IS NOT NULL
+		ORDER BY verified_at
+		LIMIT 1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableCustomDomains).Inc()
+
+	domain, err := scanCustomDomain(r.db.QueryRow(ctx, query, tenantID, model.DomainStatusVerified.String()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrCustomDomainNotFound
+		}
+		return nil, fmt.Errorf("failed to get playback domain: %w", classify(err))
+	}
+
+	return domain, nil
+}
+
+// Update persists changes to an existing custom domain.
+func (r *CustomDomainRepository) Update(ctx context.Context, domain *model.CustomDomain) error {
+	const query = `
+		UPDATE custom_domains
+		SET certificate_id = $2, status = $3, verified_at = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableCustomDomains).Inc()
+
+	domain.UpdatedAt = time.Now()
+
+	tag, err := r.db.Exec(ctx, query,
+		domain.ID,
+		nullString(domain.CertificateID),
+		domain.Status.String(),
+		domain.VerifiedAt,
+		domain.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrDuplicateCustomDomain
+		}
+		return fmt.Errorf("failed to update custom domain: %w", classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrCustomDomainNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a custom domain.
+func (r *CustomDomainRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM custom_domains WHERE id = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TableCustomDomains).Inc()
+
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete custom domain: %w", classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrCustomDomainNotFound
+	}
+
+	return nil
+}
+
+// scanCustomDomain scans a single row into a CustomDomain model.
+// pgx.Rows satisfies pgx.Row, so this serves both QueryRow and Query results.
+func scanCustomDomain(row pgx.Row) (*model.CustomDomain, error) {
+	var (
+		domain        model.CustomDomain
+		certificateID *string
+		status        string
+	)
+
+	err := row.Scan(
+		&domain.ID,
+		&domain.TenantID,
+		&domain.Hostname,
+		&certificateID,
+		&domain.VerificationToken,
+		&status,
+		&domain.VerifiedAt,
+		&domain.CreatedAt,
+		&domain.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	domain.Status = model.CustomDomainStatus(status)
+	if certificateID != nil {
+		domain.CertificateID = *certificateID
+	}
+
+	return &domain, nil
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// Compile-time verification that CustomDomainRepository implements repository.CustomDomainRepository.
+var _ repository.CustomDomainRepository = (*CustomDomainRepository)(nil)
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var customDomainColumnNames = []string{
+	"id", "tenant_id", "hostname", "certificate_id", "verification_token", "status", "verified_at", "created_at", "updated_at",
+}
+
+func TestCustomDomainRepository_Create(t *testing.T) {
+	domain, err := model.NewCustomDomain(uuid.New(), "video.example.com", "")
+	if err != nil {
+		t.Fatalf("failed to create domain: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		execErr error
+		wantErr error
+	}{
+		{
+			name: "successful creation",
+		},
+		{
+			name:    "hostname already registered",
+			execErr: &pgconn.PgError{Code: "23505"},
+			wantErr: repository.ErrDuplicateCustomDomain,
+		},
+		{
+			name:    "database error",
+			execErr: errors.New("connection refused"),
+			wantErr: errors.New("failed to create custom domain"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			exec := mock.ExpectExec("INSERT INTO custom_domains").
+				WithArgs(
+					domain.ID,
+					domain.TenantID,
+					domain.Hostname,
+					pgxmock.AnyArg(),
+					domain.VerificationToken,
+					"PENDING_VERIFICATION",
+					pgxmock.AnyArg(),
+					pgxmock.AnyArg(),
+					pgxmock.AnyArg(),
+				)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			repo := NewCustomDomainRepository(mock)
+			err = repo.Create(context.Background(), domain)
+
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && !containsError(err, tt.wantErr)) {
+					t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Create() unexpected error = %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestCustomDomainRepository_GetPlaybackDomain(t *testing.T) {
+	tenantID := uuid.New()
+	domainID := uuid.New()
+	now := time.Now()
+	certificateID := "arn:aws:acm:us-east-1:123456789012:certificate/abc"
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "servable domain",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM custom_domains WHERE tenant_id").
+					WithArgs(tenantID, "VERIFIED").
+					WillReturnRows(pgxmock.NewRows(customDomainColumnNames).AddRow(
+						domainID, tenantID, "video.example.com", &certificateID, "token", "VERIFIED", &now, now, now,
+					))
+			},
+		},
+		{
+			name: "no servable domain",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM custom_domains WHERE tenant_id").
+					WithArgs(tenantID, "VERIFIED").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrCustomDomainNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewCustomDomainRepository(mock)
+			got, err := repo.GetPlaybackDomain(context.Background(), tenantID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetPlaybackDomain() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPlaybackDomain() unexpected error = %v", err)
+			}
+
+			if got.ID != domainID || got.CertificateID != certificateID || !got.IsServable() {
+				t.Errorf("GetPlaybackDomain() = %+v", got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestCustomDomainRepository_Update(t *testing.T) {
+	domain, err := model.NewCustomDomain(uuid.New(), "video.example.com", "")
+	if err != nil {
+		t.Fatalf("failed to create domain: %v", err)
+	}
+	domain.MarkVerified(time.Now())
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "successful update",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE custom_domains").
+					WithArgs(domain.ID, pgxmock.AnyArg(), "VERIFIED", pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name: "hostname verified by another tenant",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE custom_domains").
+					WithArgs(domain.ID, pgxmock.AnyArg(), "VERIFIED", pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			wantErr: repository.ErrDuplicateCustomDomain,
+		},
+		{
+			name: "domain not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE custom_domains").
+					WithArgs(domain.ID, pgxmock.AnyArg(), "VERIFIED", pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+			},
+			wantErr: repository.ErrCustomDomainNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewCustomDomainRepository(mock)
+			err = repo.Update(context.Background(), domain)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Update() unexpected error = %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestCustomDomainRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	id := uuid.New()
+	mock.ExpectExec("DELETE FROM custom_domains").
+		WithArgs(id).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	repo := NewCustomDomainRepository(mock)
+	if err := repo.Delete(context.Background(), id); !errors.Is(err, repository.ErrCustomDomainNotFound) {
+		t.Errorf("Delete() error = %v, wantErr %v", err, repository.ErrCustomDomainNotFound)
+	}
+}
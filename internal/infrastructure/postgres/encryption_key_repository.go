@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// EncryptionKeyRepository implements repository.EncryptionKeyRepository using PostgreSQL.
+type EncryptionKeyRepository struct {
+	db DBTX
+}
+
+// Compile-time verification that EncryptionKeyRepository implements repository.EncryptionKeyRepository.
+var _ repository.EncryptionKeyRepository = (*EncryptionKeyRepository)(nil)
+
+// NewEncryptionKeyRepository creates a new EncryptionKeyRepository instance.
+func NewEncryptionKeyRepository(db DBTX) *EncryptionKeyRepository {
+	return &EncryptionKeyRepository{db: db}
+}
+
+// GetOrCreate uses a no-op update on conflict rather than DO NOTHING, so RETURNING yields
+// the stored key even when a concurrent insert committed it after the statement started.
+func (r *EncryptionKeyRepository) GetOrCreate(ctx context.Context, key *model.EncryptionKey) (*model.EncryptionKey, error) {
+	const query = `
+		INSERT INTO video_encryption_keys (video_id, key, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (video_id) DO UPDATE SET video_id = EXCLUDED.video_id
+		RETURNING video_id, key, created_at
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableEncryptionKeys).Inc()
+
+	var stored model.EncryptionKey
+	err := r.db.QueryRow(ctx, query, key.VideoID, key.Key, key.CreatedAt).Scan(&stored.VideoID, &stored.Key, &stored.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save encryption key: %w", classify(err))
+	}
+
+	return &stored, nil
+}
+
+// GetByVideoID retrieves the key of a video.
+func (r *EncryptionKeyRepository) GetByVideoID(ctx context.Context, videoID uuid.UUID) (*model.EncryptionKey, error) {
+	const query = `
+		SELECT video_id, key, created_at
+		FROM video_encryption_keys
+		WHERE video_id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableEncryptionKeys).Inc()
+
+	var key model.EncryptionKey
+	err := r.db.QueryRow(ctx, query, videoID).Scan(&key.VideoID, &key.Key, &key.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrEncryptionKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get encryption key: %w", classify(err))
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestEncryptionKeyRepository_GetOrCreate(t *testing.T) {
+	videoID := uuid.New()
+	existing := bytes.Repeat([]byte{7}, model.EncryptionKeySize)
+
+	tests := []struct {
+		name    string
+		queryFn func(q *pgxmock.ExpectedQuery, key *model.EncryptionKey)
+		wantKey func(key *model.EncryptionKey) []byte
+		wantErr bool
+	}{
+		{
+			name: "new key",
+			queryFn: func(q *pgxmock.ExpectedQuery, key *model.EncryptionKey) {
+				q.WillReturnRows(pgxmock.NewRows([]string{"video_id", "key", "created_at"}).AddRow(videoID, key.Key, key.CreatedAt))
+			},
+			wantKey: func(key *model.EncryptionKey) []byte { return key.Key },
+		},
+		{
+			name: "existing key wins",
+			queryFn: func(q *pgxmock.ExpectedQuery, key *model.EncryptionKey) {
+				q.WillReturnRows(pgxmock.NewRows([]string{"video_id", "key", "created_at"}).AddRow(videoID, existing, key.CreatedAt.Add(-time.Hour)))
+			},
+			wantKey: func(*model.EncryptionKey) []byte { return existing },
+		},
+		{
+			name: "database error",
+			queryFn: func(q *pgxmock.ExpectedQuery, key *model.EncryptionKey) {
+				q.WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			key := model.NewEncryptionKey(videoID)
+			q := mock.ExpectQuery(`INSERT INTO video_encryption_keys .* ON CONFLICT \(video_id\) DO UPDATE .* RETURNING video_id, key, created_at`).
+				WithArgs(videoID, key.Key, key.CreatedAt)
+			tt.queryFn(q, key)
+
+			repo := NewEncryptionKeyRepository(mock)
+			got, err := repo.GetOrCreate(context.Background(), key)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOrCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !bytes.Equal(got.Key, tt.wantKey(key)) {
+				t.Errorf("Key = %x, want %x", got.Key, tt.wantKey(key))
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncryptionKeyRepository_GetByVideoID(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		queryFn func(q *pgxmock.ExpectedQuery)
+		wantErr error
+	}{
+		{
+			name: "found",
+			queryFn: func(q *pgxmock.ExpectedQuery) {
+				q.WillReturnRows(pgxmock.NewRows([]string{"video_id", "key", "created_at"}).AddRow(videoID, make([]byte, 16), time.Now()))
+			},
+		},
+		{
+			name: "not found",
+			queryFn: func(q *pgxmock.ExpectedQuery) {
+				q.WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrEncryptionKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.queryFn(mock.ExpectQuery(`FROM video_encryption_keys\s+WHERE video_id = \$1`).WithArgs(videoID))
+
+			repo := NewEncryptionKeyRepository(mock)
+			got, err := repo.GetByVideoID(context.Background(), videoID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByVideoID() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.VideoID != videoID || len(got.Key) != model.EncryptionKeySize) {
+				t.Errorf("GetByVideoID() = %+v", got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// EntitlementRepository implements repository.EntitlementChecker using the local entitlements table.
+type EntitlementRepository struct {
+	db DBTX
+}
+
+// NewEntitlementRepository creates a new EntitlementRepository instance.
+func NewEntitlementRepository(db DBTX) *EntitlementRepository {
+	return &EntitlementRepository{db: db}
+}
+
+// IsEntitled reports whether an unexpired grant exists for the user and video.
+func (r *EntitlementRepository) IsEntitled(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM entitlements
+			WHERE user_id = $1 AND video_id = $2
+			  AND (expires_at IS NULL OR expires_at > NOW())
+		)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableEntitlements).Inc()
+
+	var entitled bool
+	if err := r.db.QueryRow(ctx, query, userID, videoID).Scan(&entitled); err != nil {
+		metrics.EntitlementChecksTotal.WithLabelValues(metrics.EntitlementProviderLocal, metrics.EntitlementError).Inc()
+		return false, fmt.Errorf("failed to check entitlement: %w", classify(err))
+	}
+
+	result := metrics.EntitlementDenied
+	if entitled {
+		result = metrics.EntitlementAllowed
+	}
+	metrics.EntitlementChecksTotal.WithLabelValues(metrics.EntitlementProviderLocal, result).Inc()
+
+	return entitled, nil
+}
+
+// Compile-time verification that EntitlementRepository implements repository.EntitlementChecker.
+var _ repository.EntitlementChecker = (*EntitlementRepository)(nil)
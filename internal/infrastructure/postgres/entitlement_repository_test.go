@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestEntitlementRepository_IsEntitled(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "active grant",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(userID, videoID).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			want: true,
+		},
+		{
+			name: "no grant",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(userID, videoID).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			want: false,
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(userID, videoID).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewEntitlementRepository(mock)
+			got, err := repo.IsEntitled(context.Background(), userID, videoID)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsEntitled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsEntitled() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+// classify attaches an errs code to a database error by its SQLSTATE: constraint
+// violations are conflicts, data exceptions invalid input, and lost connections,
+// serialization failures, deadlocks, exhausted resources and shutdowns transient, as
+// are timeouts and network failures. Other errors are returned unchanged.
+// Callers translate the violations they expect into repository sentinels first.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "23"): // integrity constraint violation
+			return errs.Wrap(errs.Conflict, err)
+		case strings.HasPrefix(pgErr.Code, "22"): // data exception
+			return errs.Wrap(errs.Invalid, err)
+		case strings.HasPrefix(pgErr.Code, "08"), // connection exception
+			strings.HasPrefix(pgErr.Code, "53"), // insufficient resources
+			pgErr.Code == "40001",               // serialization_failure
+			pgErr.Code == "40P01",               // deadlock_detected
+			pgErr.Code == "57P01",               // admin_shutdown
+			pgErr.Code == "57P03":               // cannot_connect_now
+			return errs.Wrap(errs.Transient, err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if pgconn.Timeout(err) || pgconn.SafeToRetry(err) || errors.As(err, &netErr) {
+		return errs.Wrap(errs.Transient, err)
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode errs.Code
+	}{
+		{name: "nil", err: nil},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, wantCode: errs.Conflict},
+		{name: "foreign key violation", err: &pgconn.PgError{Code: "23503"}, wantCode: errs.Conflict},
+		{name: "invalid text representation", err: &pgconn.PgError{Code: "22P02"}, wantCode: errs.Invalid},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, wantCode: errs.Transient},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, wantCode: errs.Transient},
+		{name: "too many connections", err: &pgconn.PgError{Code: "53300"}, wantCode: errs.Transient},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, wantCode: errs.Transient},
+		{name: "syntax error", err: &pgconn.PgError{Code: "42601"}},
+		{name: "timeout", err: fmt.Errorf("query: %w", context.DeadlineExceeded), wantCode: errs.Transient},
+		{name: "plain error", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classify(tt.err)
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Errorf("classify() dropped the cause: %v", got)
+			}
+			if code := errs.CodeOf(got); code != tt.wantCode {
+				t.Errorf("code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
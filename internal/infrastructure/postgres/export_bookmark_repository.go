@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// ExportBookmarkRepository implements repository.ExportBookmarkRepository using PostgreSQL.
+type ExportBookmarkRepository struct {
+	db DBTX
+}
+
+// NewExportBookmarkRepository creates a new ExportBookmarkRepository instance.
+func NewExportBookmarkRepository(db DBTX) *ExportBookmarkRepository {
+	return &ExportBookmarkRepository{db: db}
+}
+
+// Get returns the end of the last exported window, or the zero time if none was exported.
+func (r *ExportBookmarkRepository) Get(ctx context.Context, stream model.AnalyticsStream) (time.Time, error) {
+	const query = `SELECT exported_until FROM analytics_export_bookmarks WHERE stream = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableExportBookmarks).Inc()
+
+	var until time.Time
+	if err := r.db.QueryRow(ctx, query, string(stream)).Scan(&until); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get export bookmark: %w", classify(err))
+	}
+	return until, nil
+}
+
+// Advance moves the bookmark forward; GREATEST keeps it from moving backwards.
+func (r *ExportBookmarkRepository) Advance(ctx context.Context, stream model.AnalyticsStream, until time.Time) error {
+	const query = `
+		INSERT INTO analytics_export_bookmarks (stream, exported_until, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (stream) DO UPDATE SET
+			exported_until = GREATEST(analytics_export_bookmarks.exported_until, EXCLUDED.exported_until),
+			updated_at = NOW()
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableExportBookmarks).Inc()
+
+	if _, err := r.db.Exec(ctx, query, string(stream), until); err != nil {
+		return fmt.Errorf("failed to advance export bookmark: %w", classify(err))
+	}
+	return nil
+}
+
+// Compile-time verification that ExportBookmarkRepository implements repository.ExportBookmarkRepository.
+var _ repository.ExportBookmarkRepository = (*ExportBookmarkRepository)(nil)
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestExportBookmarkRepository_Get(t *testing.T) {
+	until := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		queryErr error
+		want     time.Time
+		wantErr  bool
+	}{
+		{name: "returns bookmark", want: until},
+		{name: "never exported", queryErr: pgx.ErrNoRows},
+		{name: "database error", queryErr: errors.New("connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			query := mock.ExpectQuery(`SELECT exported_until FROM analytics_export_bookmarks WHERE stream = \$1`).
+				WithArgs("playback")
+			if tt.queryErr != nil {
+				query.WillReturnError(tt.queryErr)
+			} else {
+				query.WillReturnRows(pgxmock.NewRows([]string{"exported_until"}).AddRow(until))
+			}
+
+			repo := NewExportBookmarkRepository(mock)
+			got, err := repo.Get(context.Background(), model.AnalyticsStreamPlayback)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Get() = %v, expected %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestExportBookmarkRepository_Advance(t *testing.T) {
+	until := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec(`INSERT INTO analytics_export_bookmarks .* ON CONFLICT \(stream\) DO UPDATE SET exported_until = GREATEST`).
+		WithArgs("video_lifecycle", until).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	repo := NewExportBookmarkRepository(mock)
+	if err := repo.Advance(context.Background(), model.AnalyticsStreamVideoLifecycle, until); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// IssuedURLRepository implements repository.IssuedURLRepository using PostgreSQL.
+type IssuedURLRepository struct {
+	db DBTX
+}
+
+// NewIssuedURLRepository creates a new IssuedURLRepository instance.
+func NewIssuedURLRepository(db DBTX) *IssuedURLRepository {
+	return &IssuedURLRepository{db: db}
+}
+
+// Create records an issued URL.
+func (r *IssuedURLRepository) Create(ctx context.Context, issued *model.IssuedURL) error {
+	const query = `
+		INSERT INTO issued_urls (id, object_key, method, purpose, requester_id, video_id, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableIssuedURLs).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		issued.ID,
+		issued.Key,
+		issued.Method,
+		string(issued.Purpose),
+		nullUUID(issued.RequesterID),
+		nullUUID(issued.VideoID),
+		issued.IssuedAt,
+		issued.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record issued URL: %w", classify(err))
+	}
+
+	return nil
+}
+
+// UsageSince counts the requester's URLs with idx_issued_urls_requester_issued_at.
+func (r *IssuedURLRepository) UsageSince(ctx context.Context, requesterID uuid.UUID, purpose model.URLPurpose, since time.Time) (repository.URLUsage, error) {
+	const query = `
+		SELECT count(*), min(issued_at)
+		FROM issued_urls
+		WHERE requester_id = $1 AND purpose = $2 AND issued_at > $3
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableIssuedURLs).Inc()
+
+	var (
+		usage  repository.URLUsage
+		oldest *time.Time
+	)
+	if err := r.db.QueryRow(ctx, query, requesterID, string(purpose), since).Scan(&usage.Count, &oldest); err != nil {
+		return repository.URLUsage{}, fmt.Errorf("failed to count issued URLs: %w", classify(err))
+	}
+	if oldest != nil {
+		usage.Oldest = *oldest
+	}
+
+	return usage, nil
+}
+
+// DeleteExpiredBefore removes records of URLs that expired before the cutoff.
+func (r *IssuedURLRepository) DeleteExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	const query = `DELETE FROM issued_urls WHERE expires_at < $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TableIssuedURLs).Inc()
+
+	tag, err := r.db.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired issued URLs: %w", classify(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// nullUUID converts uuid.Nil to SQL NULL.
+func nullUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
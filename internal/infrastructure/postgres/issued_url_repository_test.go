@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestIssuedURLRepository_Create(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name          string
+		issued        *model.IssuedURL
+		wantRequester any
+		execErr       error
+		wantErr       bool
+	}{
+		{
+			name:          "user upload",
+			issued:        model.NewIssuedURL("originals/v/video.mp4", "PUT", model.URLPurposeUpload, userID, videoID, 15*time.Minute),
+			wantRequester: &userID,
+		},
+		{
+			name:          "service URL has no requester",
+			issued:        model.NewIssuedURL("originals/v/video.mp4", "GET", model.URLPurposeProbe, uuid.Nil, videoID, 5*time.Minute),
+			wantRequester: (*uuid.UUID)(nil),
+		},
+		{
+			name:          "database error",
+			issued:        model.NewIssuedURL("originals/v/video.mp4", "PUT", model.URLPurposeUpload, userID, videoID, 15*time.Minute),
+			wantRequester: &userID,
+			execErr:       errors.New("connection refused"),
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			issued := tt.issued
+			exec := mock.ExpectExec("INSERT INTO issued_urls").
+				WithArgs(issued.ID, issued.Key, issued.Method, string(issued.Purpose), tt.wantRequester, &videoID, issued.IssuedAt, issued.ExpiresAt)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			repo := NewIssuedURLRepository(mock)
+			err = repo.Create(context.Background(), issued)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestIssuedURLRepository_UsageSince(t *testing.T) {
+	userID := uuid.New()
+	since := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+	oldest := since.Add(10 * time.Minute)
+
+	tests := []struct {
+		name       string
+		count      int
+		oldest     *time.Time
+		wantOldest time.Time
+	}{
+		{name: "recent URLs", count: 3, oldest: &oldest, wantOldest: oldest},
+		{name: "no URLs", count: 0, oldest: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			mock.ExpectQuery(`SELECT count\(\*\), min\(issued_at\) FROM issued_urls WHERE requester_id = \$1 AND purpose = \$2 AND issued_at > \$3`).
+				WithArgs(userID, "upload", since).
+				WillReturnRows(pgxmock.NewRows([]string{"count", "min"}).AddRow(tt.count, tt.oldest))
+
+			repo := NewIssuedURLRepository(mock)
+			got, err := repo.UsageSince(context.Background(), userID, model.URLPurposeUpload, since)
+			if err != nil {
+				t.Fatalf("UsageSince() error = %v", err)
+			}
+			if got.Count != tt.count || !got.Oldest.Equal(tt.wantOldest) {
+				t.Errorf("UsageSince() = %+v, want %d since %v", got, tt.count, tt.wantOldest)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestIssuedURLRepository_DeleteExpiredBefore(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	cutoff := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`DELETE FROM issued_urls WHERE expires_at < \$1`).
+		WithArgs(cutoff).
+		WillReturnResult(pgxmock.NewResult("DELETE", 42))
+
+	repo := NewIssuedURLRepository(mock)
+	n, err := repo.DeleteExpiredBefore(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("DeleteExpiredBefore() error = %v", err)
+	}
+	if n != 42 {
+		t.Errorf("DeleteExpiredBefore() = %d, want 42", n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// OutboxRepository implements repository.OutboxRepository using PostgreSQL.
+type OutboxRepository struct {
+	db TxDBTX
+}
+
+// NewOutboxRepository creates a new OutboxRepository instance.
+func NewOutboxRepository(db TxDBTX) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// UpdateVideoWithTask runs VideoRepository.Update and the outbox insert in one transaction.
+func (r *OutboxRepository) UpdateVideoWithTask(ctx context.Context, video *model.Video, msg *repository.OutboxMessage, availableAt time.Time) error {
+	const query = `
+		INSERT INTO transcode_task_outbox (id, video_id, payload, attempts, available_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	payload, err := json.Marshal(msg.Task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transcode task: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", classify(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := NewVideoRepository(tx).Update(ctx, video); err != nil {
+		return err
+	}
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableTranscodeOutbox).Inc()
+
+	if _, err := tx.Exec(ctx, query, msg.ID, msg.Task.VideoID, payload, msg.Attempts, availableAt, msg.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert outbox message: %w", classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", classify(err))
+	}
+	return nil
+}
+
+// Claim moves available_at of the claimed rows to until. SKIP LOCKED keeps concurrent
+// relays from claiming the same rows.
+func (r *OutboxRepository) Claim(ctx context.Context, now, until time.Time, limit int) ([]*repository.OutboxMessage, error) {
+	const query = `
+		UPDATE transcode_task_outbox
+		SET available_at = $2
+		WHERE id IN (
+			SELECT id FROM transcode_task_outbox
+			WHERE available_at <= $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, payload, attempts, created_at
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableTranscodeOutbox).Inc()
+
+	rows, err := r.db.Query(ctx, query, now, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", classify(err))
+	}
+	defer rows.Close()
+
+	var msgs []*repository.OutboxMessage
+	for rows.Next() {
+		var (
+			msg     repository.OutboxMessage
+			payload []byte
+		)
+		if err := rows.Scan(&msg.ID, &payload, &msg.Attempts, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		if err := json.Unmarshal(payload, &msg.Task); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outbox message %s: %w", msg.ID, err)
+		}
+		msgs = append(msgs, &msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox messages: %w", err)
+	}
+
+	return msgs, nil
+}
+
+// Delete removes a published message.
+func (r *OutboxRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM transcode_task_outbox WHERE id = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TableTranscodeOutbox).Inc()
+
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete outbox message: %w", classify(err))
+	}
+	return nil
+}
+
+// Release records a failed publication.
+func (r *OutboxRepository) Release(ctx context.Context, id uuid.UUID, retryAt time.Time, lastErr string) error {
+	const query = `
+		UPDATE transcode_task_outbox
+		SET attempts = attempts + 1, last_error = $3, available_at = $2
+		WHERE id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableTranscodeOutbox).Inc()
+
+	if _, err := r.db.Exec(ctx, query, id, retryAt, lastErr); err != nil {
+		return fmt.Errorf("failed to release outbox message: %w", classify(err))
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestOutboxRepository_UpdateVideoWithTask(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface, msg *repository.OutboxMessage)
+		wantErr error
+	}{
+		{
+			name: "update and insert commit together",
+			mockFn: func(mock pgxmock.PgxPoolIface, msg *repository.OutboxMessage) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("UPDATE videos").
+					WithArgs(videoUpdateArgs(videoID)...).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec("INSERT INTO transcode_task_outbox").
+					WithArgs(msg.ID, videoID, pgxmock.AnyArg(), 0, now.Add(time.Minute), now).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "missing video writes no task",
+			mockFn: func(mock pgxmock.PgxPoolIface, msg *repository.OutboxMessage) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("UPDATE videos").
+					WithArgs(videoUpdateArgs(videoID)...).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mock.ExpectRollback()
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name: "failed insert rolls back the update",
+			mockFn: func(mock pgxmock.PgxPoolIface, msg *repository.OutboxMessage) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("UPDATE videos").
+					WithArgs(videoUpdateArgs(videoID)...).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec("INSERT INTO transcode_task_outbox").
+					WithArgs(msg.ID, videoID, pgxmock.AnyArg(), 0, now.Add(time.Minute), now).
+					WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("failed to insert outbox message"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			video := &model.Video{ID: videoID, UserID: uuid.New(), Title: "Title", Status: model.StatusProcessing}
+			msg := &repository.OutboxMessage{
+				ID:        uuid.New(),
+				Task:      repository.TranscodeTask{VideoID: videoID, OutputKey: "hls/v/1"},
+				CreatedAt: now,
+			}
+			tt.mockFn(mock, msg)
+
+			repo := NewOutboxRepository(mock)
+			err = repo.UpdateVideoWithTask(context.Background(), video, msg, now.Add(time.Minute))
+
+			if tt.wantErr != nil {
+				if err == nil || !containsError(err, tt.wantErr) {
+					t.Errorf("UpdateVideoWithTask() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("UpdateVideoWithTask() unexpected error: %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+// videoUpdateArgs matches the arguments of VideoRepository.Update by video ID only.
+func videoUpdateArgs(videoID uuid.UUID) []any {
+	args := []any{videoID}
+	for range 19 {
+		args = append(args, pgxmock.AnyArg())
+	}
+	return args
+}
+
+func TestOutboxRepository_Claim(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	now := time.Now()
+	task := repository.TranscodeTask{VideoID: uuid.New(), OutputKey: "hls/v/1", ABRProfile: "mobile"}
+	payload, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("failed to marshal task: %v", err)
+	}
+	msgID := uuid.New()
+
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED").
+		WithArgs(now, now.Add(time.Minute), 10).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "payload", "attempts", "created_at"}).
+			AddRow(msgID, payload, 2, now.Add(-time.Hour)))
+
+	msgs, err := NewOutboxRepository(mock).Claim(context.Background(), now, now.Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("Claim() unexpected error: %v", err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("Claim() returned %d messages, want 1", len(msgs))
+	}
+	if msgs[0].ID != msgID || msgs[0].Attempts != 2 {
+		t.Errorf("Claim() = %+v", msgs[0])
+	}
+	if msgs[0].Task.VideoID != task.VideoID || msgs[0].Task.ABRProfile != "mobile" {
+		t.Errorf("Claim() task = %+v, want %+v", msgs[0].Task, task)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestOutboxRepository_Release(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	id := uuid.New()
+	retryAt := time.Now().Add(time.Minute)
+	mock.ExpectExec("attempts = attempts \\+ 1").
+		WithArgs(id, retryAt, "connection closed").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	if err := NewOutboxRepository(mock).Release(context.Background(), id, retryAt, "connection closed"); err != nil {
+		t.Errorf("Release() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const outputBucketColumns = `tenant_id, endpoint, region, bucket, use_ssl, access_key_id, secret_ref, public_base_url, checked_at, check_error, created_at, updated_at`
+
+// OutputBucketRepository implements repository.OutputBucketRepository using PostgreSQL.
+type OutputBucketRepository struct {
+	db DBTX
+}
+
+// NewOutputBucketRepository creates a new OutputBucketRepository instance.
+func NewOutputBucketRepository(db DBTX) *OutputBucketRepository {
+	return &OutputBucketRepository{db: db}
+}
+
+// Save upserts the tenant's output bucket. Replacing a bucket keeps its original
+// created_at.
+func (r *OutputBucketRepository) Save(ctx context.Context, bucket *model.OutputBucket) error {
+	const query = `
+		INSERT INTO tenant_output_buckets (` + outputBucketColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			endpoint = EXCLUDED.endpoint,
+			region = EXCLUDED.region,
+			bucket = EXCLUDED.bucket,
+			use_ssl = EXCLUDED.use_ssl,
+			access_key_id = EXCLUDED.access_key_id,
+			secret_ref = EXCLUDED.secret_ref,
+			public_base_url = EXCLUDED.public_base_url,
+			checked_at = EXCLUDED.checked_at,
+			check_error = EXCLUDED.check_error,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableOutputBuckets).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		bucket.TenantID,
+		bucket.Endpoint,
+		nullString(bucket.Region),
+		bucket.Bucket,
+		bucket.UseSSL,
+		bucket.AccessKeyID,
+		bucket.SecretRef,
+		bucket.PublicBaseURL,
+		nullTime(bucket.CheckedAt),
+		nullString(bucket.CheckError),
+		bucket.CreatedAt,
+		bucket.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save output bucket: %w", classify(err))
+	}
+
+	return nil
+}
+
+// GetByTenant retrieves a tenant's output bucket.
+func (r *OutputBucketRepository) GetByTenant(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+	const query = `
+		SELECT ` + outputBucketColumns + `
+		FROM tenant_output_buckets
+		WHERE tenant_id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableOutputBuckets).Inc()
+
+	var (
+		bucket     model.OutputBucket
+		region     *string
+		checkedAt  *time.Time
+		checkError *string
+	)
+
+	err := r.db.QueryRow(ctx, query, tenantID).Scan(
+		&bucket.TenantID,
+		&bucket.Endpoint,
+		&region,
+		&bucket.Bucket,
+		&bucket.UseSSL,
+		&bucket.AccessKeyID,
+		&bucket.SecretRef,
+		&bucket.PublicBaseURL,
+		&checkedAt,
+		&checkError,
+		&bucket.CreatedAt,
+		&bucket.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrOutputBucketNotFound
+		}
+		return nil, fmt.Errorf("failed to get output bucket: %w", classify(err))
+	}
+
+	if region != nil {
+		bucket.Region = *region
+	}
+	if checkedAt != nil {
+		bucket.CheckedAt = *checkedAt
+	}
+	if checkError != nil {
+		bucket.CheckError = *checkError
+	}
+
+	return &bucket, nil
+}
+
+// Delete removes a tenant's output bucket.
+func (r *OutputBucketRepository) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	const query = `DELETE FROM tenant_output_buckets WHERE tenant_id = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TableOutputBuckets).Inc()
+
+	tag, err := r.db.Exec(ctx, query, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete output bucket: %w", classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrOutputBucketNotFound
+	}
+
+	return nil
+}
+
+// Compile-time verification that OutputBucketRepository implements repository.OutputBucketRepository.
+var _ repository.OutputBucketRepository = (*OutputBucketRepository)(nil)
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var outputBucketColumnNames = []string{
+	"tenant_id", "endpoint", "region", "bucket", "use_ssl", "access_key_id", "secret_ref", "public_base_url", "checked_at", "check_error", "created_at", "updated_at",
+}
+
+func TestOutputBucketRepository_Save(t *testing.T) {
+	bucket, err := model.NewOutputBucket(uuid.New(), "s3.eu-west-1.amazonaws.com", "eu-west-1", "acme-videos", "AKIAEXAMPLE", "https://cdn.acme.example", true)
+	if err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+	bucket.SecretRef = "local:k1:sealed"
+	bucket.RecordCheck(time.Now(), nil)
+
+	tests := []struct {
+		name    string
+		execErr error
+		wantErr bool
+	}{
+		{
+			name: "successful upsert",
+		},
+		{
+			name:    "database error",
+			execErr: errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			exec := mock.ExpectExec("INSERT INTO tenant_output_buckets .* ON CONFLICT \\(tenant_id\\) DO UPDATE").
+				WithArgs(
+					bucket.TenantID,
+					bucket.Endpoint,
+					pgxmock.AnyArg(),
+					bucket.Bucket,
+					true,
+					bucket.AccessKeyID,
+					bucket.SecretRef,
+					bucket.PublicBaseURL,
+					pgxmock.AnyArg(),
+					pgxmock.AnyArg(),
+					pgxmock.AnyArg(),
+					pgxmock.AnyArg(),
+				)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			repo := NewOutputBucketRepository(mock)
+			err = repo.Save(context.Background(), bucket)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Save() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestOutputBucketRepository_GetByTenant(t *testing.T) {
+	tenantID := uuid.New()
+	now := time.Now()
+	checkError := "access denied"
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "bucket found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM tenant_output_buckets WHERE tenant_id").
+					WithArgs(tenantID).
+					WillReturnRows(pgxmock.NewRows(outputBucketColumnNames).AddRow(
+						tenantID, "minio.acme.example:9000", (*string)(nil), "acme-videos", false, "acme", "local:k1:sealed",
+						"https://cdn.acme.example", &now, &checkError, now, now,
+					))
+			},
+		},
+		{
+			name: "no bucket",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM tenant_output_buckets WHERE tenant_id").
+					WithArgs(tenantID).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrOutputBucketNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewOutputBucketRepository(mock)
+			got, err := repo.GetByTenant(context.Background(), tenantID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetByTenant() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByTenant() unexpected error = %v", err)
+			}
+
+			if got.TenantID != tenantID || got.Region != "" || got.CheckError != checkError || got.IsHealthy() {
+				t.Errorf("GetByTenant() = %+v", got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestOutputBucketRepository_Delete(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{
+			name:         "successful delete",
+			rowsAffected: 1,
+		},
+		{
+			name:         "no bucket",
+			rowsAffected: 0,
+			wantErr:      repository.ErrOutputBucketNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			mock.ExpectExec("DELETE FROM tenant_output_buckets").
+				WithArgs(tenantID).
+				WillReturnResult(pgxmock.NewResult("DELETE", tt.rowsAffected))
+
+			repo := NewOutputBucketRepository(mock)
+			err = repo.Delete(context.Background(), tenantID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const playlistColumns = `id, user_id, title, description, visibility, created_at, updated_at`
+
+// playlistSelect reads playlists with their video IDs in order, so a playlist is loaded
+// in one query.
+const playlistSelect = `
+	SELECT ` + playlistColumns + `,
+		ARRAY(SELECT video_id FROM playlist_items WHERE playlist_id = playlists.id ORDER BY position) AS video_ids
+	FROM playlists
+`
+
+// PlaylistRepository implements repository.PlaylistRepository using PostgreSQL.
+type PlaylistRepository struct {
+	db TxDBTX
+}
+
+// NewPlaylistRepository creates a new PlaylistRepository instance.
+func NewPlaylistRepository(db TxDBTX) *PlaylistRepository {
+	return &PlaylistRepository{db: db}
+}
+
+// Create inserts the playlist and its videos in one transaction.
+func (r *PlaylistRepository) Create(ctx context.Context, playlist *model.Playlist) error {
+	const query = `
+		INSERT INTO playlists (` + playlistColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", classify(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TablePlaylists).Inc()
+
+	_, err = tx.Exec(ctx, query,
+		playlist.ID,
+		playlist.UserID,
+		playlist.Title,
+		playlist.Description,
+		visibilityOrDefault(playlist.Visibility),
+		playlist.CreatedAt,
+		playlist.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create playlist: %w", classify(err))
+	}
+
+	if err := insertPlaylistItems(ctx, tx, playlist); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", classify(err))
+	}
+	return nil
+}
+
+// GetByID retrieves a playlist with its video IDs in order.
+func (r *PlaylistRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Playlist, error) {
+	const query = playlistSelect + `WHERE id = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TablePlaylists).Inc()
+
+	playlist, err := scanPlaylist(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrPlaylistNotFound
+		}
+		return nil, fmt.Errorf("failed to get playlist by ID: %w", classify(err))
+	}
+
+	return playlist, nil
+}
+
+// ListByUser retrieves every playlist of a user, newest first.
+func (r *PlaylistRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Playlist, error) {
+	const query = playlistSelect + `WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TablePlaylists).Inc()
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query playlists: %w", classify(err))
+	}
+	defer rows.Close()
+
+	playlists := []*model.Playlist{}
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan playlist: %w", classify(err))
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating playlists: %w", classify(err))
+	}
+
+	return playlists, nil
+}
+
+// Update writes the metadata and replaces the videos in one transaction, so readers
+// see either the old list or the new one.
+func (r *PlaylistRepository) Update(ctx context.Context, playlist *model.Playlist) error {
+	const (
+		updateQuery = `
+			UPDATE playlists
+			SET title = $2, description = $3, visibility = $4, updated_at = $5
+			WHERE id = $1
+		`
+		deleteItemsQuery = `DELETE FROM playlist_items WHERE playlist_id = $1`
+	)
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", classify(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TablePlaylists).Inc()
+
+	result, err := tx.Exec(ctx, updateQuery,
+		playlist.ID,
+		playlist.Title,
+		playlist.Description,
+		visibilityOrDefault(playlist.Visibility),
+		playlist.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update playlist: %w", classify(err))
+	}
+	if result.RowsAffected() == 0 {
+		return repository.ErrPlaylistNotFound
+	}
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TablePlaylistItems).Inc()
+
+	if _, err := tx.Exec(ctx, deleteItemsQuery, playlist.ID); err != nil {
+		return fmt.Errorf("failed to delete playlist items: %w", classify(err))
+	}
+
+	if err := insertPlaylistItems(ctx, tx, playlist); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", classify(err))
+	}
+	return nil
+}
+
+// Delete removes a playlist; its items go with it through the foreign key.
+func (r *PlaylistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM playlists WHERE id = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TablePlaylists).Inc()
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete playlist: %w", classify(err))
+	}
+	if result.RowsAffected() == 0 {
+		return repository.ErrPlaylistNotFound
+	}
+
+	return nil
+}
+
+// insertPlaylistItems writes the playlist's videos with their positions in one statement.
+func insertPlaylistItems(ctx context.Context, db DBTX, playlist *model.Playlist) error {
+	const query = `
+		INSERT INTO playlist_items (playlist_id, video_id, position)
+		SELECT $1, video_id, position - 1
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS items(video_id, position)
+	`
+
+	if len(playlist.VideoIDs) == 0 {
+		return nil
+	}
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TablePlaylistItems).Inc()
+
+	if _, err := db.Exec(ctx, query, playlist.ID, playlist.VideoIDs); err != nil {
+		return fmt.Errorf("failed to insert playlist items: %w", classify(err))
+	}
+	return nil
+}
+
+// scanPlaylist scans a single row of playlistSelect into a Playlist model.
+// pgx.Rows satisfies pgx.Row, so this serves both QueryRow and Query results.
+func scanPlaylist(row pgx.Row) (*model.Playlist, error) {
+	var (
+		playlist   model.Playlist
+		visibility string
+	)
+
+	err := row.Scan(
+		&playlist.ID,
+		&playlist.UserID,
+		&playlist.Title,
+		&playlist.Description,
+		&visibility,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt,
+		&playlist.VideoIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.Visibility = model.Visibility(visibility)
+	if playlist.VideoIDs == nil {
+		playlist.VideoIDs = []uuid.UUID{}
+	}
+
+	return &playlist, nil
+}
+
+// Compile-time verification that PlaylistRepository implements repository.PlaylistRepository.
+var _ repository.PlaylistRepository = (*PlaylistRepository)(nil)
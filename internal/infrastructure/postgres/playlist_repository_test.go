@@ -0,0 +1,306 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var playlistColumnNames = []string{
+	"id", "user_id", "title", "description", "visibility", "created_at", "updated_at", "video_ids",
+}
+
+func TestPlaylistRepository_Create(t *testing.T) {
+	videoIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name     string
+		videoIDs []uuid.UUID
+		mockFn   func(mock pgxmock.PgxPoolIface, p *model.Playlist)
+		wantErr  error
+	}{
+		{
+			name:     "playlist and items commit together",
+			videoIDs: videoIDs,
+			mockFn: func(mock pgxmock.PgxPoolIface, p *model.Playlist) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("INSERT INTO playlists").
+					WithArgs(p.ID, p.UserID, "Go course", "", "unlisted", p.CreatedAt, p.UpdatedAt).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec("INSERT INTO playlist_items").
+					WithArgs(p.ID, videoIDs).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+				mock.ExpectCommit()
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "empty playlist writes no items",
+			mockFn: func(mock pgxmock.PgxPoolIface, p *model.Playlist) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("INSERT INTO playlists").
+					WithArgs(p.ID, p.UserID, "Go course", "", "unlisted", p.CreatedAt, p.UpdatedAt).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:     "failed item insert rolls back the playlist",
+			videoIDs: videoIDs,
+			mockFn: func(mock pgxmock.PgxPoolIface, p *model.Playlist) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("INSERT INTO playlists").
+					WithArgs(p.ID, p.UserID, "Go course", "", "unlisted", p.CreatedAt, p.UpdatedAt).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec("INSERT INTO playlist_items").
+					WithArgs(p.ID, videoIDs).
+					WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("failed to insert playlist items"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			playlist, err := model.NewPlaylist(uuid.New(), "Go course", "")
+			if err != nil {
+				t.Fatalf("failed to create playlist: %v", err)
+			}
+			if err := playlist.SetVideos(tt.videoIDs); err != nil {
+				t.Fatalf("failed to set videos: %v", err)
+			}
+			tt.mockFn(mock, playlist)
+
+			err = NewPlaylistRepository(mock).Create(context.Background(), playlist)
+
+			if tt.wantErr != nil {
+				if err == nil || !containsError(err, tt.wantErr) {
+					t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Create() unexpected error = %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlaylistRepository_GetByID(t *testing.T) {
+	playlistID := uuid.New()
+	userID := uuid.New()
+	videoIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		want    *model.Playlist
+		wantErr error
+	}{
+		{
+			name: "found with videos in order",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM playlist_items .* FROM playlists WHERE id").
+					WithArgs(playlistID).
+					WillReturnRows(pgxmock.NewRows(playlistColumnNames).
+						AddRow(playlistID, userID, "Go course", "Ten lessons", "private", now, now, videoIDs))
+			},
+			want: &model.Playlist{
+				ID: playlistID, UserID: userID, Title: "Go course", Description: "Ten lessons",
+				Visibility: model.VisibilityPrivate, VideoIDs: videoIDs, CreatedAt: now, UpdatedAt: now,
+			},
+		},
+		{
+			name: "empty playlist",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM playlists WHERE id").
+					WithArgs(playlistID).
+					WillReturnRows(pgxmock.NewRows(playlistColumnNames).
+						AddRow(playlistID, userID, "Go course", "", "unlisted", now, now, []uuid.UUID(nil)))
+			},
+			want: &model.Playlist{
+				ID: playlistID, UserID: userID, Title: "Go course",
+				Visibility: model.VisibilityUnlisted, VideoIDs: []uuid.UUID{}, CreatedAt: now, UpdatedAt: now,
+			},
+		},
+		{
+			name: "not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM playlists WHERE id").
+					WithArgs(playlistID).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrPlaylistNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			got, err := NewPlaylistRepository(mock).GetByID(context.Background(), playlistID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetByID() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByID() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetByID() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaylistRepository_ListByUser(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT .* FROM playlists WHERE user_id = \\$1 ORDER BY created_at DESC").
+		WithArgs(userID).
+		WillReturnRows(pgxmock.NewRows(playlistColumnNames).
+			AddRow(uuid.New(), userID, "Newest", "", "unlisted", now, now, []uuid.UUID{uuid.New()}).
+			AddRow(uuid.New(), userID, "Oldest", "", "private", now, now, []uuid.UUID{}))
+
+	got, err := NewPlaylistRepository(mock).ListByUser(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListByUser() unexpected error = %v", err)
+	}
+	if len(got) != 2 || got[0].Title != "Newest" || len(got[0].VideoIDs) != 1 || got[1].Visibility != model.VisibilityPrivate {
+		t.Errorf("ListByUser() = %+v", got)
+	}
+}
+
+func TestPlaylistRepository_Update(t *testing.T) {
+	now := time.Now()
+	playlist := &model.Playlist{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Title:      "Go course",
+		Visibility: model.VisibilityPublic,
+		VideoIDs:   []uuid.UUID{uuid.New()},
+		UpdatedAt:  now,
+	}
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "metadata and items are replaced together",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("UPDATE playlists").
+					WithArgs(playlist.ID, "Go course", "", "public", now).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec("DELETE FROM playlist_items").
+					WithArgs(playlist.ID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 3))
+				mock.ExpectExec("INSERT INTO playlist_items").
+					WithArgs(playlist.ID, playlist.VideoIDs).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBeginTx(pgx.TxOptions{})
+				mock.ExpectExec("UPDATE playlists").
+					WithArgs(playlist.ID, "Go course", "", "public", now).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mock.ExpectRollback()
+			},
+			wantErr: repository.ErrPlaylistNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			err = NewPlaylistRepository(mock).Update(context.Background(), playlist)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlaylistRepository_Delete(t *testing.T) {
+	playlistID := uuid.New()
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found", affected: 0, wantErr: repository.ErrPlaylistNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			mock.ExpectExec("DELETE FROM playlists").
+				WithArgs(playlistID).
+				WillReturnResult(pgxmock.NewResult("DELETE", tt.affected))
+
+			err = NewPlaylistRepository(mock).Delete(context.Background(), playlistID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
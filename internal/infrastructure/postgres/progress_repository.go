@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// ProgressRepository implements repository.ProgressRepository using PostgreSQL.
+type ProgressRepository struct {
+	db DBTX
+}
+
+// NewProgressRepository creates a new ProgressRepository instance.
+func NewProgressRepository(db DBTX) *ProgressRepository {
+	return &ProgressRepository{db: db}
+}
+
+// UpsertBatch persists a batch of progress records in a single statement.
+// Rows for videos that no longer exist are dropped by the join instead of failing the whole batch,
+// and records older than a removal are skipped, so a flush racing RemoveFromHistory cannot restore the entry.
+func (r *ProgressRepository) UpsertBatch(ctx context.Context, progress []*model.PlaybackProgress) error {
+	if len(progress) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO playback_progress (user_id, video_id, position_ms, updated_at)
+		SELECT p.user_id, p.video_id, p.position_ms, p.updated_at
+		FROM unnest($1::uuid[], $2::uuid[], $3::bigint[], $4::timestamptz[])
+			AS p(user_id, video_id, position_ms, updated_at)
+		JOIN videos v ON v.id = p.video_id
+		ON CONFLICT (user_id, video_id) DO UPDATE
+		SET position_ms = EXCLUDED.position_ms, updated_at = EXCLUDED.updated_at, removed_at = NULL
+		WHERE playback_progress.updated_at < EXCLUDED.updated_at
+		  AND (playback_progress.removed_at IS NULL OR playback_progress.removed_at < EXCLUDED.updated_at)
+	`
+
+	userIDs := make([]uuid.UUID, len(progress))
+	videoIDs := make([]uuid.UUID, len(progress))
+	positions := make([]int64, len(progress))
+	updatedAts := make([]time.Time, len(progress))
+	for i, p := range progress {
+		userIDs[i] = p.UserID
+		videoIDs[i] = p.VideoID
+		positions[i] = p.Position.Milliseconds()
+		updatedAts[i] = p.UpdatedAt
+	}
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TablePlaybackProgress).Inc()
+
+	if _, err := r.db.Exec(ctx, query, userIDs, videoIDs, positions, updatedAts); err != nil {
+		return fmt.Errorf("failed to upsert playback progress: %w", classify(err))
+	}
+
+	return nil
+}
+
+// Get retrieves the progress of a user on a video.
+func (r *ProgressRepository) Get(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+	const query = `
+		SELECT user_id, video_id, position_ms, updated_at
+		FROM playback_progress
+		WHERE user_id = $1 AND video_id = $2 AND removed_at IS NULL
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TablePlaybackProgress).Inc()
+
+	var (
+		progress   model.PlaybackProgress
+		positionMs int64
+	)
+	err := r.db.QueryRow(ctx, query, userID, videoID).Scan(
+		&progress.UserID,
+		&progress.VideoID,
+		&positionMs,
+		&progress.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrProgressNotFound
+		}
+		return nil, fmt.Errorf("failed to get playback progress: %w", classify(err))
+	}
+
+	progress.Position = time.Duration(positionMs) * time.Millisecond
+	return &progress, nil
+}
+
+// ListByUser returns a page of the user's watch history using keyset pagination.
+// Deleted videos, and private videos the user no longer owns, are left out by the query,
+// so neither their titles leak nor pages come back short.
+func (r *ProgressRepository) ListByUser(ctx context.Context, userID uuid.UUID, cursor *repository.HistoryCursor, limit int) ([]*model.WatchHistoryEntry, error) {
+	const query = `
+		SELECT p.user_id, p.video_id, p.position_ms, p.updated_at, v.title
+		FROM playback_progress p
+		JOIN videos v ON v.id = p.video_id
+		WHERE p.user_id = $1
+		  AND p.removed_at IS NULL
+		  AND v.status <> $5
+		  AND (v.visibility <> $6 OR v.user_id = p.user_id)
+		  AND ($2::timestamptz IS NULL OR (p.updated_at, p.video_id) < ($2, $3::uuid))
+		ORDER BY p.updated_at DESC, p.video_id DESC
+		LIMIT $4
+	`
+
+	var (
+		before   *time.Time
+		beforeID *uuid.UUID
+	)
+	if cursor != nil {
+		before = &cursor.UpdatedAt
+		beforeID = &cursor.VideoID
+	}
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TablePlaybackProgress).Inc()
+
+	rows, err := r.db.Query(ctx, query, userID, before, beforeID, limit, string(model.StatusDeleted), string(model.VisibilityPrivate))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watch history: %w", classify(err))
+	}
+	defer rows.Close()
+
+	var entries []*model.WatchHistoryEntry
+	for rows.Next() {
+		var (
+			entry      model.WatchHistoryEntry
+			positionMs int64
+		)
+		if err := rows.Scan(
+			&entry.UserID,
+			&entry.VideoID,
+			&positionMs,
+			&entry.UpdatedAt,
+			&entry.Title,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan watch history entry: %w", classify(err))
+		}
+		entry.Position = time.Duration(positionMs) * time.Millisecond
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watch history: %w", classify(err))
+	}
+
+	return entries, nil
+}
+
+// Delete marks a progress record removed rather than deleting the row. The marker is
+// written even when nothing was recorded yet, since a heartbeat may be mid-flush.
+func (r *ProgressRepository) Delete(ctx context.Context, userID, videoID uuid.UUID) error {
+	const query = `
+		WITH previous AS (
+			SELECT removed_at FROM playback_progress
+			WHERE user_id = $1 AND video_id = $2
+			FOR UPDATE
+		)
+		INSERT INTO playback_progress (user_id, video_id, position_ms, updated_at, removed_at)
+		SELECT $1, v.id, 0, now(), now()
+		FROM videos v
+		WHERE v.id = $2
+		ON CONFLICT (user_id, video_id) DO UPDATE
+		SET removed_at = EXCLUDED.removed_at
+		RETURNING COALESCE((SELECT removed_at IS NULL FROM previous), false)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TablePlaybackProgress).Inc()
+
+	var existed bool
+	if err := r.db.QueryRow(ctx, query, userID, videoID).Scan(&existed); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrProgressNotFound // the video no longer exists
+		}
+		return fmt.Errorf("failed to delete playback progress: %w", classify(err))
+	}
+
+	if !existed {
+		return repository.ErrProgressNotFound
+	}
+
+	return nil
+}
+
+// Compile-time verification that ProgressRepository implements repository.ProgressRepository.
+var _ repository.ProgressRepository = (*ProgressRepository)(nil)
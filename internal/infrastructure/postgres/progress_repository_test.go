@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestProgressRepository_UpsertBatch(t *testing.T) {
+	now := time.Now()
+	progress := []*model.PlaybackProgress{
+		{UserID: uuid.New(), VideoID: uuid.New(), Position: 90 * time.Second, UpdatedAt: now},
+		{UserID: uuid.New(), VideoID: uuid.New(), Position: 1500 * time.Millisecond, UpdatedAt: now},
+	}
+
+	tests := []struct {
+		name     string
+		progress []*model.PlaybackProgress
+		mockFn   func(mock pgxmock.PgxPoolIface)
+		wantErr  bool
+	}{
+		{
+			name:     "successful upsert",
+			progress: progress,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO playback_progress").
+					WithArgs(
+						[]uuid.UUID{progress[0].UserID, progress[1].UserID},
+						[]uuid.UUID{progress[0].VideoID, progress[1].VideoID},
+						[]int64{90000, 1500},
+						[]time.Time{now, now},
+					).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+			},
+			wantErr: false,
+		},
+		{
+			name:     "removed entries are not restored",
+			progress: progress[:1],
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`removed_at = NULL .* playback_progress.removed_at < EXCLUDED.updated_at`).
+					WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+			},
+			wantErr: false,
+		},
+		{
+			name:     "empty batch is a no-op",
+			progress: nil,
+			mockFn:   func(mock pgxmock.PgxPoolIface) {},
+			wantErr:  false,
+		},
+		{
+			name:     "database error",
+			progress: progress,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO playback_progress").
+					WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewProgressRepository(mock)
+			err = repo.UpsertBatch(context.Background(), tt.progress)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpsertBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestProgressRepository_Get(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		want    time.Duration
+		wantErr error
+	}{
+		{
+			name: "progress found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows([]string{"user_id", "video_id", "position_ms", "updated_at"}).
+					AddRow(userID, videoID, int64(42500), now)
+				mock.ExpectQuery("SELECT .* FROM playback_progress WHERE user_id").
+					WithArgs(userID, videoID).
+					WillReturnRows(rows)
+			},
+			want: 42500 * time.Millisecond,
+		},
+		{
+			name: "progress not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM playback_progress WHERE user_id").
+					WithArgs(userID, videoID).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrProgressNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewProgressRepository(mock)
+			got, err := repo.Get(context.Background(), userID, videoID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Get() unexpected error = %v", err)
+			}
+			if got.Position != tt.want {
+				t.Errorf("Get() position = %v, want %v", got.Position, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestProgressRepository_ListByUser(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name      string
+		cursor    *repository.HistoryCursor
+		mockFn    func(mock pgxmock.PgxPoolIface)
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name: "first page",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows([]string{"user_id", "video_id", "position_ms", "updated_at", "title"}).
+					AddRow(userID, videoID, int64(1000), now, "First").
+					AddRow(userID, uuid.New(), int64(2000), now.Add(-time.Hour), "Second")
+				mock.ExpectQuery(`SELECT .* FROM playback_progress p JOIN videos v .* v.status <> \$5 AND \(v.visibility <> \$6 OR v.user_id = p.user_id\)`).
+					WithArgs(userID, (*time.Time)(nil), (*uuid.UUID)(nil), 20, "DELETED", "private").
+					WillReturnRows(rows)
+			},
+			wantCount: 2,
+		},
+		{
+			name:   "page after cursor",
+			cursor: &repository.HistoryCursor{UpdatedAt: now, VideoID: videoID},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows([]string{"user_id", "video_id", "position_ms", "updated_at", "title"})
+				mock.ExpectQuery("SELECT .* FROM playback_progress p JOIN videos v").
+					WithArgs(userID, &now, &videoID, 20, "DELETED", "private").
+					WillReturnRows(rows)
+			},
+			wantCount: 0,
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM playback_progress p JOIN videos v").
+					WithArgs(userID, pgxmock.AnyArg(), pgxmock.AnyArg(), 20, pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewProgressRepository(mock)
+			got, err := repo.ListByUser(context.Background(), userID, tt.cursor, 20)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListByUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("ListByUser() returned %d entries, want %d", len(got), tt.wantCount)
+			}
+			if tt.wantCount > 0 && got[0].Title != "First" {
+				t.Errorf("ListByUser() first title = %q, want %q", got[0].Title, "First")
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestProgressRepository_Delete(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "successful delete",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("INSERT INTO playback_progress .* SET removed_at").
+					WithArgs(userID, videoID).
+					WillReturnRows(pgxmock.NewRows([]string{"existed"}).AddRow(true))
+			},
+		},
+		{
+			name: "progress not found leaves a removal marker",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("INSERT INTO playback_progress .* SET removed_at").
+					WithArgs(userID, videoID).
+					WillReturnRows(pgxmock.NewRows([]string{"existed"}).AddRow(false))
+			},
+			wantErr: repository.ErrProgressNotFound,
+		},
+		{
+			name: "video no longer exists",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("INSERT INTO playback_progress").
+					WithArgs(userID, videoID).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrProgressNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewProgressRepository(mock)
+			err = repo.Delete(context.Background(), userID, videoID)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// renditionColumns is the number of columns written per rendition.
+const renditionColumns = 11
+
+// renditionViewResolution is how stale last_viewed_at may get before a view updates it.
+// Pruning looks back days, so an hour of slack costs nothing and saves most writes.
+const renditionViewResolution = time.Hour
+
+// RenditionRepository implements repository.RenditionRepository using PostgreSQL.
+type RenditionRepository struct {
+	db DBTX
+}
+
+// NewRenditionRepository creates a new RenditionRepository instance.
+func NewRenditionRepository(db DBTX) *RenditionRepository {
+	return &RenditionRepository{db: db}
+}
+
+// SaveAll upserts every rendition in a single statement, so a set is never partially written.
+func (r *RenditionRepository) SaveAll(ctx context.Context, renditions []*model.Rendition) error {
+	if len(renditions) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(renditions))
+	args := make([]any, 0, len(renditions)*renditionColumns)
+	for i, rd := range renditions {
+		placeholders := make([]string, renditionColumns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*renditionColumns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args,
+			rd.ID,
+			rd.VideoID,
+			rd.OutputVersion,
+			rd.Name,
+			rd.Width,
+			rd.Height,
+			rd.Bitrate,
+			rd.Codec,
+			rd.SegmentCount,
+			rd.Bytes,
+			rd.CreatedAt,
+		)
+	}
+
+	query := `
+		INSERT INTO video_renditions (
+			id, video_id, output_version, name, width, height, bitrate, codec, segment_count, bytes, created_at
+		)
+		VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (video_id, output_version, name) DO UPDATE SET
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			bitrate = EXCLUDED.bitrate,
+			codec = EXCLUDED.codec,
+			segment_count = EXCLUDED.segment_count,
+			bytes = EXCLUDED.bytes
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableVideoRenditions).Inc()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to save renditions: %w", classify(err))
+	}
+
+	return nil
+}
+
+// ListByVideoID retrieves the renditions of one output version, highest quality first.
+func (r *RenditionRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID, outputVersion int64) ([]*model.Rendition, error) {
+	const query = `
+		SELECT id, video_id, output_version, name, width, height, bitrate, codec, segment_count, bytes, created_at,
+			last_viewed_at, pruned_at
+		FROM video_renditions
+		WHERE video_id = $1 AND output_version = $2
+		ORDER BY height DESC, bitrate DESC
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideoRenditions).Inc()
+
+	rows, err := r.db.Query(ctx, query, videoID, outputVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query renditions: %w", classify(err))
+	}
+	defer rows.Close()
+
+	renditions := []*model.Rendition{}
+	for rows.Next() {
+		var (
+			rd           model.Rendition
+			lastViewedAt *time.Time
+			prunedAt     *time.Time
+		)
+		if err := rows.Scan(
+			&rd.ID,
+			&rd.VideoID,
+			&rd.OutputVersion,
+			&rd.Name,
+			&rd.Width,
+			&rd.Height,
+			&rd.Bitrate,
+			&rd.Codec,
+			&rd.SegmentCount,
+			&rd.Bytes,
+			&rd.CreatedAt,
+			&lastViewedAt,
+			&prunedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rendition: %w", classify(err))
+		}
+		if lastViewedAt != nil {
+			rd.LastViewedAt = *lastViewedAt
+		}
+		if prunedAt != nil {
+			rd.PrunedAt = *prunedAt
+		}
+		renditions = append(renditions, &rd)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating renditions: %w", classify(err))
+	}
+
+	return renditions, nil
+}
+
+// MarkViewed only writes when the recorded view is older than renditionViewResolution.
+func (r *RenditionRepository) MarkViewed(ctx context.Context, videoID uuid.UUID, outputVersion int64, name string, at time.Time) error {
+	const query = `
+		UPDATE video_renditions
+		SET last_viewed_at = $4
+		WHERE video_id = $1 AND output_version = $2 AND name = $3
+			AND (last_viewed_at IS NULL OR last_viewed_at < $5)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideoRenditions).Inc()
+
+	if _, err := r.db.Exec(ctx, query, videoID, outputVersion, name, at, at.Add(-renditionViewResolution)); err != nil {
+		return fmt.Errorf("failed to mark rendition viewed: %w", classify(err))
+	}
+
+	return nil
+}
+
+// ListPruneCandidates evaluates the pruning rule over the served renditions of each
+// published output. It only narrows the search: the caller applies the rule again to
+// the renditions it loads.
+func (r *RenditionRepository) ListPruneCandidates(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	const query = `
+		SELECT video_id
+		FROM (
+			SELECT r.video_id, r.height, r.created_at,
+				MAX(r.height) FILTER (WHERE r.last_viewed_at >= $1) OVER w AS watched_height,
+				MIN(r.height) OVER w AS lowest_height,
+				BOOL_OR(r.last_viewed_at IS NOT NULL) OVER w AS tracked
+			FROM video_renditions r
+			JOIN videos v ON v.id = r.video_id AND v.output_version = r.output_version
+			WHERE v.status = 'READY' AND v.dash_url IS NULL AND r.pruned_at IS NULL
+			WINDOW w AS (PARTITION BY r.video_id)
+		) served
+		WHERE tracked AND created_at < $1 AND height > COALESCE(watched_height, lowest_height)
+		GROUP BY video_id
+		ORDER BY MIN(created_at)
+		LIMIT $2
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideoRenditions).Inc()
+
+	rows, err := r.db.Query(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prune candidates: %w", classify(err))
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan prune candidate: %w", classify(err))
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating prune candidates: %w", classify(err))
+	}
+
+	return ids, nil
+}
+
+// MarkPruned keeps the first pruned_at of a rendition, so a retried prune does not move it.
+func (r *RenditionRepository) MarkPruned(ctx context.Context, videoID uuid.UUID, outputVersion int64, names []string, at time.Time) error {
+	const query = `
+		UPDATE video_renditions
+		SET pruned_at = $4
+		WHERE video_id = $1 AND output_version = $2 AND name = ANY($3) AND pruned_at IS NULL
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideoRenditions).Inc()
+
+	if _, err := r.db.Exec(ctx, query, videoID, outputVersion, names, at); err != nil {
+		return fmt.Errorf("failed to mark renditions pruned: %w", classify(err))
+	}
+
+	return nil
+}
+
+// Compile-time verification that RenditionRepository implements repository.RenditionRepository.
+var _ repository.RenditionRepository = (*RenditionRepository)(nil)
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestRenditionRepository_SaveAll(t *testing.T) {
+	videoID := uuid.New()
+	r720 := model.NewRendition(videoID, 2, "720p")
+	r720.Width, r720.Height, r720.Bitrate, r720.Codec, r720.SegmentCount, r720.Bytes = 1280, 720, 2500000, "libx264", 10, 1000
+	r360 := model.NewRendition(videoID, 2, "360p")
+	r360.Width, r360.Height, r360.Bitrate, r360.Codec, r360.SegmentCount, r360.Bytes = 640, 360, 800000, "libx264", 10, 300
+
+	tests := []struct {
+		name    string
+		execErr error
+		wantErr bool
+	}{
+		{name: "saves every rendition in one statement"},
+		{name: "database error", execErr: errors.New("connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			exec := mock.ExpectExec(`INSERT INTO video_renditions .* VALUES \(\$1, .*\$11\), \(\$12, .*\$22\) ON CONFLICT \(video_id, output_version, name\) DO UPDATE`).
+				WithArgs(
+					r720.ID, videoID, int64(2), "720p", 1280, 720, 2500000, "libx264", 10, int64(1000), r720.CreatedAt,
+					r360.ID, videoID, int64(2), "360p", 640, 360, 800000, "libx264", 10, int64(300), r360.CreatedAt,
+				)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 2))
+			}
+
+			repo := NewRenditionRepository(mock)
+			err = repo.SaveAll(context.Background(), []*model.Rendition{r720, r360})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SaveAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestRenditionRepository_SaveAll_Empty(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	repo := NewRenditionRepository(mock)
+	if err := repo.SaveAll(context.Background(), nil); err != nil {
+		t.Errorf("SaveAll() unexpected error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestRenditionRepository_ListByVideoID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	videoID := uuid.New()
+	now := time.Now()
+	rows := pgxmock.NewRows([]string{
+		"id", "video_id", "output_version", "name", "width", "height", "bitrate", "codec", "segment_count", "bytes", "created_at",
+		"last_viewed_at", "pruned_at",
+	}).
+		AddRow(uuid.New(), videoID, int64(2), "1080p", 1920, 1080, 5000000, "libx264", 10, int64(2000), now, nil, &now).
+		AddRow(uuid.New(), videoID, int64(2), "720p", 1280, 720, 2500000, "libx264", 10, int64(1000), now, &now, nil)
+	mock.ExpectQuery("SELECT .* FROM video_renditions WHERE video_id = \\$1 AND output_version = \\$2").
+		WithArgs(videoID, int64(2)).
+		WillReturnRows(rows)
+
+	repo := NewRenditionRepository(mock)
+	got, err := repo.ListByVideoID(context.Background(), videoID, 2)
+	if err != nil {
+		t.Fatalf("ListByVideoID() unexpected error = %v", err)
+	}
+
+	if len(got) != 2 || got[0].Name != "1080p" || got[1].Bytes != 1000 {
+		t.Errorf("ListByVideoID() = %+v", got)
+	}
+	if len(got) == 2 && (!got[0].IsPruned() || !got[0].LastViewedAt.IsZero() || got[1].IsPruned() || !got[1].LastViewedAt.Equal(now)) {
+		t.Errorf("viewership columns not scanned: %+v, %+v", got[0], got[1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRenditionRepository_MarkViewed(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	videoID := uuid.New()
+	at := time.Now()
+	mock.ExpectExec(`UPDATE video_renditions SET last_viewed_at = \$4 WHERE .* last_viewed_at < \$5`).
+		WithArgs(videoID, int64(2), "720p", at, at.Add(-renditionViewResolution)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	repo := NewRenditionRepository(mock)
+	if err := repo.MarkViewed(context.Background(), videoID, 2, "720p", at); err != nil {
+		t.Errorf("MarkViewed() unexpected error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRenditionRepository_ListPruneCandidates(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	cutoff := time.Now().Add(-30 * 24 * time.Hour)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	mock.ExpectQuery(`SELECT video_id FROM .* WHERE tracked AND created_at < \$1 .* LIMIT \$2`).
+		WithArgs(cutoff, 50).
+		WillReturnRows(pgxmock.NewRows([]string{"video_id"}).AddRow(ids[0]).AddRow(ids[1]))
+
+	repo := NewRenditionRepository(mock)
+	got, err := repo.ListPruneCandidates(context.Background(), cutoff, 50)
+	if err != nil {
+		t.Fatalf("ListPruneCandidates() unexpected error = %v", err)
+	}
+	if len(got) != 2 || got[0] != ids[0] || got[1] != ids[1] {
+		t.Errorf("ListPruneCandidates() = %v, want %v", got, ids)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRenditionRepository_MarkPruned(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	videoID := uuid.New()
+	at := time.Now()
+	names := []string{"1080p", "720p"}
+	mock.ExpectExec(`UPDATE video_renditions SET pruned_at = \$4 WHERE .* name = ANY\(\$3\) AND pruned_at IS NULL`).
+		WithArgs(videoID, int64(2), names, at).
+		WillReturnError(errors.New("connection refused"))
+
+	repo := NewRenditionRepository(mock)
+	if err := repo.MarkPruned(context.Background(), videoID, 2, names, at); err == nil {
+		t.Error("MarkPruned() expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// SubtitleRepository implements repository.SubtitleRepository using PostgreSQL.
+type SubtitleRepository struct {
+	db DBTX
+}
+
+// Compile-time verification that SubtitleRepository implements repository.SubtitleRepository.
+var _ repository.SubtitleRepository = (*SubtitleRepository)(nil)
+
+// NewSubtitleRepository creates a new SubtitleRepository instance.
+func NewSubtitleRepository(db DBTX) *SubtitleRepository {
+	return &SubtitleRepository{db: db}
+}
+
+// Save upserts the track and clears the other defaults in a single statement. The ID and
+// creation time of a replaced track are read back into subtitle.
+func (r *SubtitleRepository) Save(ctx context.Context, subtitle *model.Subtitle) error {
+	const query = `
+		WITH cleared AS (
+			UPDATE video_subtitles
+			SET is_default = FALSE, updated_at = $9
+			WHERE $7 AND video_id = $2 AND language <> $3 AND is_default
+		)
+		INSERT INTO video_subtitles (id, video_id, language, label, format, source_key, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (video_id, language) DO UPDATE SET
+			label = EXCLUDED.label,
+			format = EXCLUDED.format,
+			source_key = EXCLUDED.source_key,
+			is_default = EXCLUDED.is_default,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableVideoSubtitles).Inc()
+
+	err := r.db.QueryRow(ctx, query,
+		subtitle.ID,
+		subtitle.VideoID,
+		subtitle.Language,
+		subtitle.Label,
+		subtitle.Format,
+		subtitle.SourceKey,
+		subtitle.Default,
+		subtitle.CreatedAt,
+		subtitle.UpdatedAt,
+	).Scan(&subtitle.ID, &subtitle.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save subtitle: %w", classify(err))
+	}
+
+	return nil
+}
+
+// ListByVideoID retrieves the subtitle tracks of a video ordered by language.
+func (r *SubtitleRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]*model.Subtitle, error) {
+	const query = `
+		SELECT id, video_id, language, label, format, source_key, is_default, created_at, updated_at
+		FROM video_subtitles
+		WHERE video_id = $1
+		ORDER BY language
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideoSubtitles).Inc()
+
+	rows, err := r.db.Query(ctx, query, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subtitles: %w", classify(err))
+	}
+	defer rows.Close()
+
+	subtitles := []*model.Subtitle{}
+	for rows.Next() {
+		var s model.Subtitle
+		if err := rows.Scan(
+			&s.ID,
+			&s.VideoID,
+			&s.Language,
+			&s.Label,
+			&s.Format,
+			&s.SourceKey,
+			&s.Default,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan subtitle: %w", classify(err))
+		}
+		subtitles = append(subtitles, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subtitles: %w", classify(err))
+	}
+
+	return subtitles, nil
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestSubtitleRepository_Save(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		queryFn func(q *pgxmock.ExpectedQuery, sub *model.Subtitle)
+		wantID  func(sub *model.Subtitle) uuid.UUID
+		wantErr bool
+	}{
+		{
+			name: "new track",
+			queryFn: func(q *pgxmock.ExpectedQuery, sub *model.Subtitle) {
+				q.WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(sub.ID, sub.CreatedAt))
+			},
+		},
+		{
+			name: "replaced track keeps its ID",
+			queryFn: func(q *pgxmock.ExpectedQuery, sub *model.Subtitle) {
+				q.WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(videoID, sub.CreatedAt.Add(-time.Hour)))
+			},
+			wantID: func(*model.Subtitle) uuid.UUID { return videoID },
+		},
+		{
+			name: "database error",
+			queryFn: func(q *pgxmock.ExpectedQuery, sub *model.Subtitle) {
+				q.WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			sub, err := model.NewSubtitle(videoID, "en", "English", model.SubtitleFormatSRT, true)
+			if err != nil {
+				t.Fatalf("NewSubtitle() error = %v", err)
+			}
+			sub.SourceKey = "subtitles/key/en.srt"
+			want := sub.ID
+			if tt.wantID != nil {
+				want = tt.wantID(sub)
+			}
+
+			q := mock.ExpectQuery(`WITH cleared AS \(\s+UPDATE video_subtitles .* INSERT INTO video_subtitles .* ON CONFLICT \(video_id, language\) DO UPDATE .* RETURNING id, created_at`).
+				WithArgs(sub.ID, videoID, "en", "English", "srt", "subtitles/key/en.srt", true, sub.CreatedAt, sub.UpdatedAt)
+			tt.queryFn(q, sub)
+
+			repo := NewSubtitleRepository(mock)
+			err = repo.Save(context.Background(), sub)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Save() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && sub.ID != want {
+				t.Errorf("ID = %s, want %s", sub.ID, want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSubtitleRepository_ListByVideoID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	videoID := uuid.New()
+	now := time.Now()
+	rows := pgxmock.NewRows([]string{"id", "video_id", "language", "label", "format", "source_key", "is_default", "created_at", "updated_at"}).
+		AddRow(uuid.New(), videoID, "en", "English", "vtt", "subtitles/key/en.vtt", true, now, now).
+		AddRow(uuid.New(), videoID, "ja", "日本語", "srt", "subtitles/key/ja.srt", false, now, now)
+	mock.ExpectQuery(`FROM video_subtitles\s+WHERE video_id = \$1\s+ORDER BY language`).
+		WithArgs(videoID).
+		WillReturnRows(rows)
+
+	repo := NewSubtitleRepository(mock)
+	got, err := repo.ListByVideoID(context.Background(), videoID)
+	if err != nil {
+		t.Fatalf("ListByVideoID() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Language != "en" || !got[0].Default || got[1].Label != "日本語" {
+		t.Errorf("ListByVideoID() = %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
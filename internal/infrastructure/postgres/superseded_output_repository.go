@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const supersededOutputColumns = `video_id, output_version, user_id, prefixes, external_output, delete_after, created_at`
+
+// SupersededOutputRepository implements repository.SupersededOutputRepository using PostgreSQL.
+type SupersededOutputRepository struct {
+	db DBTX
+}
+
+// NewSupersededOutputRepository creates a new SupersededOutputRepository instance.
+func NewSupersededOutputRepository(db DBTX) *SupersededOutputRepository {
+	return &SupersededOutputRepository{db: db}
+}
+
+// Schedule inserts the record; a redelivered task publishing the same version again
+// leaves the existing record as it is.
+func (r *SupersededOutputRepository) Schedule(ctx context.Context, output *repository.SupersededOutput) error {
+	const query = `
+		INSERT INTO superseded_outputs (` + supersededOutputColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (video_id, output_version) DO NOTHING
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableSupersededOutputs).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		output.VideoID,
+		output.OutputVersion,
+		output.UserID,
+		output.Prefixes,
+		output.ExternalOutput,
+		output.DeleteAfter,
+		output.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to schedule superseded output: %w", classify(err))
+	}
+	return nil
+}
+
+// Claim moves delete_after of the claimed rows to until. SKIP LOCKED keeps concurrent
+// workers from claiming the same rows.
+func (r *SupersededOutputRepository) Claim(ctx context.Context, now, until time.Time, limit int) ([]*repository.SupersededOutput, error) {
+	const query = `
+		UPDATE superseded_outputs
+		SET delete_after = $2
+		WHERE (video_id, output_version) IN (
+			SELECT video_id, output_version FROM superseded_outputs
+			WHERE delete_after <= $1
+			ORDER BY delete_after
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + supersededOutputColumns
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableSupersededOutputs).Inc()
+
+	rows, err := r.db.Query(ctx, query, now, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim superseded outputs: %w", classify(err))
+	}
+	defer rows.Close()
+
+	var outputs []*repository.SupersededOutput
+	for rows.Next() {
+		var output repository.SupersededOutput
+		if err := rows.Scan(
+			&output.VideoID,
+			&output.OutputVersion,
+			&output.UserID,
+			&output.Prefixes,
+			&output.ExternalOutput,
+			&output.DeleteAfter,
+			&output.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan superseded output: %w", err)
+		}
+		outputs = append(outputs, &output)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating superseded outputs: %w", err)
+	}
+
+	return outputs, nil
+}
+
+// Delete removes the record of a version.
+func (r *SupersededOutputRepository) Delete(ctx context.Context, videoID uuid.UUID, outputVersion int64) error {
+	const query = `DELETE FROM superseded_outputs WHERE video_id = $1 AND output_version = $2`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryDelete, metrics.TableSupersededOutputs).Inc()
+
+	if _, err := r.db.Exec(ctx, query, videoID, outputVersion); err != nil {
+		return fmt.Errorf("failed to delete superseded output: %w", classify(err))
+	}
+	return nil
+}
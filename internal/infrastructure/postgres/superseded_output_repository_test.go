@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestSupersededOutputRepository_Schedule(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	now := time.Now()
+	output := &repository.SupersededOutput{
+		VideoID:       uuid.New(),
+		OutputVersion: 3,
+		UserID:        uuid.New(),
+		Prefixes:      []string{"hls/v/v3/", "previews/v/v3/"},
+		DeleteAfter:   now.Add(24 * time.Hour),
+		CreatedAt:     now,
+	}
+	mock.ExpectExec("ON CONFLICT \\(video_id, output_version\\) DO NOTHING").
+		WithArgs(output.VideoID, int64(3), output.UserID, output.Prefixes, false, output.DeleteAfter, now).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	if err := NewSupersededOutputRepository(mock).Schedule(context.Background(), output); err != nil {
+		t.Errorf("Schedule() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSupersededOutputRepository_Claim(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	now := time.Now()
+	videoID := uuid.New()
+	userID := uuid.New()
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED").
+		WithArgs(now, now.Add(time.Hour), 10).
+		WillReturnRows(pgxmock.NewRows([]string{"video_id", "output_version", "user_id", "prefixes", "external_output", "delete_after", "created_at"}).
+			AddRow(videoID, int64(3), userID, []string{"hls/v/v3/"}, true, now.Add(time.Hour), now.Add(-24*time.Hour)))
+
+	outputs, err := NewSupersededOutputRepository(mock).Claim(context.Background(), now, now.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Claim() unexpected error: %v", err)
+	}
+	if len(outputs) != 1 {
+		t.Fatalf("Claim() returned %d outputs, want 1", len(outputs))
+	}
+	got := outputs[0]
+	if got.VideoID != videoID || got.OutputVersion != 3 || got.UserID != userID || !got.ExternalOutput {
+		t.Errorf("Claim() = %+v", got)
+	}
+	if len(got.Prefixes) != 1 || got.Prefixes[0] != "hls/v/v3/" {
+		t.Errorf("Claim() prefixes = %q, want [hls/v/v3/]", got.Prefixes)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSupersededOutputRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	videoID := uuid.New()
+	mock.ExpectExec("DELETE FROM superseded_outputs").
+		WithArgs(videoID, int64(3)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	if err := NewSupersededOutputRepository(mock).Delete(context.Background(), videoID, 3); err != nil {
+		t.Errorf("Delete() unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
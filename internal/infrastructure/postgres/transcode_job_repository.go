@@ -0,0 +1,301 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// TranscodeJobRepository implements repository.TranscodeJobRepository using PostgreSQL.
+type TranscodeJobRepository struct {
+	db DBTX
+}
+
+// NewTranscodeJobRepository creates a new TranscodeJobRepository instance.
+func NewTranscodeJobRepository(db DBTX) *TranscodeJobRepository {
+	return &TranscodeJobRepository{db: db}
+}
+
+// Create inserts a finished job. Durations are stored in milliseconds; source, output
+// and estimate columns are NULL when unknown.
+func (r *TranscodeJobRepository) Create(ctx context.Context, job *model.TranscodeJob) error {
+	const query = `
+		INSERT INTO transcode_jobs (
+			id, video_id, output_version, attempt, status, error, final,
+			download_ms, probe_ms, transcode_ms, variant_ms, upload_ms, preview_ms, finalize_ms,
+			queued_at, started_at, finished_at,
+			source_duration_ms, source_height, output_bytes, estimated_processing_ms, estimated_output_bytes
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+	`
+
+	variants := make(map[string]int64, len(job.Timings.Variants))
+	for name, d := range job.Timings.Variants {
+		variants[name] = d.Milliseconds()
+	}
+	variantJSON, err := json.Marshal(variants)
+	if err != nil {
+		return fmt.Errorf("failed to marshal variant timings: %w", classify(err))
+	}
+
+	var jobErr *string
+	if job.Error != "" {
+		jobErr = &job.Error
+	}
+
+	var queuedAt *time.Time
+	if !job.QueuedAt.IsZero() {
+		queuedAt = &job.QueuedAt
+	}
+
+	var estimatedMs, estimatedBytes *int64
+	if job.Estimate != nil {
+		estimatedMs = nullInt64(job.Estimate.ProcessingTime.Milliseconds())
+		estimatedBytes = nullInt64(job.Estimate.OutputBytes)
+	}
+
+	var sourceHeight *int
+	if job.SourceHeight > 0 {
+		sourceHeight = &job.SourceHeight
+	}
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableTranscodeJobs).Inc()
+
+	_, err = r.db.Exec(ctx, query,
+		job.ID,
+		job.VideoID,
+		job.OutputVersion,
+		job.Attempt,
+		string(job.Status),
+		jobErr,
+		job.Final,
+		job.Timings.Download.Milliseconds(),
+		job.Timings.Probe.Milliseconds(),
+		job.Timings.Transcode.Milliseconds(),
+		variantJSON,
+		job.Timings.Upload.Milliseconds(),
+		job.Timings.Preview.Milliseconds(),
+		job.Timings.Finalize.Milliseconds(),
+		queuedAt,
+		job.StartedAt,
+		job.FinishedAt,
+		nullInt64(job.SourceDuration.Milliseconds()),
+		sourceHeight,
+		nullInt64(job.OutputBytes),
+		estimatedMs,
+		estimatedBytes,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert transcode job: %w", classify(err))
+	}
+
+	return nil
+}
+
+// ListByVideoID retrieves the most recent jobs for a video.
+func (r *TranscodeJobRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error) {
+	const query = `
+		SELECT id, video_id, output_version, attempt, status, error, final,
+		       download_ms, probe_ms, transcode_ms, variant_ms, upload_ms, preview_ms, finalize_ms,
+		       queued_at, started_at, finished_at,
+		       source_duration_ms, source_height, output_bytes, estimated_processing_ms, estimated_output_bytes
+		FROM transcode_jobs
+		WHERE video_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableTranscodeJobs).Inc()
+
+	rows, err := r.db.Query(ctx, query, videoID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transcode jobs: %w", classify(err))
+	}
+	defer rows.Close()
+
+	var jobs []*model.TranscodeJob
+	for rows.Next() {
+		var (
+			job                              model.TranscodeJob
+			status                           string
+			jobErr                           *string
+			queuedAt                         *time.Time
+			downloadMs, probeMs, transcodeMs int64
+			uploadMs, previewMs, finalizeMs  int64
+			variantJSON                      []byte
+			sourceMs, outputBytes            *int64
+			sourceHeight                     *int
+			estimatedMs, estimatedBytes      *int64
+		)
+		if err := rows.Scan(
+			&job.ID,
+			&job.VideoID,
+			&job.OutputVersion,
+			&job.Attempt,
+			&status,
+			&jobErr,
+			&job.Final,
+			&downloadMs,
+			&probeMs,
+			&transcodeMs,
+			&variantJSON,
+			&uploadMs,
+			&previewMs,
+			&finalizeMs,
+			&queuedAt,
+			&job.StartedAt,
+			&job.FinishedAt,
+			&sourceMs,
+			&sourceHeight,
+			&outputBytes,
+			&estimatedMs,
+			&estimatedBytes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan transcode job: %w", classify(err))
+		}
+
+		var variants map[string]int64
+		if err := json.Unmarshal(variantJSON, &variants); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal variant timings: %w", classify(err))
+		}
+
+		job.Status = model.TranscodeJobStatus(status)
+		if jobErr != nil {
+			job.Error = *jobErr
+		}
+		if queuedAt != nil {
+			job.QueuedAt = *queuedAt
+		}
+		job.Timings = model.StageTimings{
+			Download:  millis(downloadMs),
+			Probe:     millis(probeMs),
+			Transcode: millis(transcodeMs),
+			Variants:  make(map[string]time.Duration, len(variants)),
+			Upload:    millis(uploadMs),
+			Preview:   millis(previewMs),
+			Finalize:  millis(finalizeMs),
+		}
+		for name, ms := range variants {
+			job.Timings.Variants[name] = millis(ms)
+		}
+		if sourceMs != nil {
+			job.SourceDuration = millis(*sourceMs)
+		}
+		if sourceHeight != nil {
+			job.SourceHeight = *sourceHeight
+		}
+		if outputBytes != nil {
+			job.OutputBytes = *outputBytes
+		}
+		if estimatedMs != nil && estimatedBytes != nil {
+			job.Estimate = &model.TranscodeEstimate{
+				ProcessingTime: millis(*estimatedMs),
+				OutputBytes:    *estimatedBytes,
+			}
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transcode jobs: %w", classify(err))
+	}
+
+	return jobs, nil
+}
+
+// Stats aggregates final outcomes and the time-to-READY percentile in a single scan.
+func (r *TranscodeJobRepository) Stats(ctx context.Context, since time.Time) (*repository.TranscodeJobStats, error) {
+	const query = `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'SUCCEEDED'),
+			COUNT(*) FILTER (WHERE status = 'FAILED'),
+			COALESCE(
+				PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM finished_at - queued_at))
+					FILTER (WHERE status = 'SUCCEEDED' AND queued_at IS NOT NULL),
+				0
+			),
+			COUNT(*) FILTER (WHERE status = 'SUCCEEDED' AND queued_at IS NOT NULL)
+		FROM transcode_jobs
+		WHERE final AND finished_at >= $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableTranscodeJobs).Inc()
+
+	var (
+		stats     repository.TranscodeJobStats
+		p95Second float64
+	)
+	if err := r.db.QueryRow(ctx, query, since).Scan(
+		&stats.Succeeded,
+		&stats.Failed,
+		&p95Second,
+		&stats.TimeToReadySamples,
+	); err != nil {
+		return nil, fmt.Errorf("failed to aggregate transcode jobs: %w", classify(err))
+	}
+	stats.TimeToReadyP95 = time.Duration(p95Second * float64(time.Second))
+
+	return &stats, nil
+}
+
+// CostStats aggregates processing time and output size of final, succeeded jobs with a
+// probed source, per source height.
+func (r *TranscodeJobRepository) CostStats(ctx context.Context, since time.Time) ([]repository.TranscodeCostStats, error) {
+	const query = `
+		SELECT
+			source_height,
+			COUNT(*),
+			SUM(source_duration_ms),
+			SUM((EXTRACT(EPOCH FROM finished_at - started_at) * 1000)::BIGINT),
+			SUM(output_bytes)
+		FROM transcode_jobs
+		WHERE final AND status = 'SUCCEEDED' AND finished_at >= $1
+		  AND source_duration_ms > 0 AND source_height IS NOT NULL AND output_bytes IS NOT NULL
+		GROUP BY source_height
+		ORDER BY source_height
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableTranscodeJobs).Inc()
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transcode cost stats: %w", classify(err))
+	}
+	defer rows.Close()
+
+	var stats []repository.TranscodeCostStats
+	for rows.Next() {
+		var (
+			s                      repository.TranscodeCostStats
+			sourceMs, processingMs int64
+		)
+		if err := rows.Scan(&s.SourceHeight, &s.Jobs, &sourceMs, &processingMs, &s.OutputBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan transcode cost stats: %w", classify(err))
+		}
+		s.SourceDuration = millis(sourceMs)
+		s.ProcessingTime = millis(processingMs)
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transcode cost stats: %w", classify(err))
+	}
+
+	return stats, nil
+}
+
+// millis converts a stored millisecond count to a duration.
+func millis(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Compile-time verification that TranscodeJobRepository implements repository.TranscodeJobRepository.
+var _ repository.TranscodeJobRepository = (*TranscodeJobRepository)(nil)
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestTranscodeJobRepository_Create(t *testing.T) {
+	start := time.Now()
+	job := model.NewTranscodeJob(uuid.New(), 42, 1, start)
+	job.Timings.Download = 1500 * time.Millisecond
+	job.Timings.Transcode = 10 * time.Second
+	job.Timings.Variants["720p"] = 6 * time.Second
+	job.SourceDuration = 90 * time.Second
+	job.SourceHeight = 1080
+	job.Estimate = &model.TranscodeEstimate{ProcessingTime: 20 * time.Second, OutputBytes: 5 << 20}
+	job.Finish(errors.New("upload failed"), start.Add(12*time.Second))
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr bool
+	}{
+		{
+			name: "successful insert",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO transcode_jobs").
+					WithArgs(
+						job.ID, job.VideoID, int64(42), 1, "FAILED", pgxmock.AnyArg(), false,
+						int64(1500), int64(0), int64(10000), []byte(`{"720p":6000}`), int64(0), int64(0), int64(0),
+						pgxmock.AnyArg(), job.StartedAt, job.FinishedAt,
+						pgxmock.AnyArg(), pgxmock.AnyArg(), (*int64)(nil), pgxmock.AnyArg(), pgxmock.AnyArg(),
+					).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			},
+			wantErr: false,
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO transcode_jobs").
+					WithArgs(
+						pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+						pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+						pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+						pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+						pgxmock.AnyArg(), pgxmock.AnyArg(),
+					).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewTranscodeJobRepository(mock)
+			err = repo.Create(context.Background(), job)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestTranscodeJobRepository_ListByVideoID(t *testing.T) {
+	videoID := uuid.New()
+	jobID := uuid.New()
+	start := time.Now()
+	errMsg := "transcode: ffmpeg execution failed"
+	queuedAt := start.Add(-time.Minute)
+	sourceMs, outputBytes, estimatedMs, estimatedBytes := int64(120000), int64(2048), int64(40000), int64(1024)
+	sourceHeight := 720
+
+	columns := []string{
+		"id", "video_id", "output_version", "attempt", "status", "error", "final",
+		"download_ms", "probe_ms", "transcode_ms", "variant_ms", "upload_ms", "preview_ms", "finalize_ms",
+		"queued_at", "started_at", "finished_at",
+		"source_duration_ms", "source_height", "output_bytes", "estimated_processing_ms", "estimated_output_bytes",
+	}
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM transcode_jobs").
+		WithArgs(videoID, 10).
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow(jobID, videoID, int64(7), 0, "FAILED", &errMsg, true,
+				int64(1200), int64(30), int64(45000), []byte(`{"1080p":30000,"360p":15000}`), int64(0), int64(0), int64(0),
+				&queuedAt, start, start.Add(46*time.Second),
+				&sourceMs, &sourceHeight, &outputBytes, &estimatedMs, &estimatedBytes))
+
+	repo := NewTranscodeJobRepository(mock)
+	jobs, err := repo.ListByVideoID(context.Background(), videoID, 10)
+	if err != nil {
+		t.Fatalf("ListByVideoID() error = %v", err)
+	}
+
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs))
+	}
+	job := jobs[0]
+	if job.ID != jobID || job.Status != model.JobStatusFailed || job.Error != errMsg || !job.Final {
+		t.Errorf("unexpected job: %+v", job)
+	}
+	if !job.QueuedAt.Equal(queuedAt) {
+		t.Errorf("QueuedAt: got %v, expected %v", job.QueuedAt, queuedAt)
+	}
+	if job.Timings.Download != 1200*time.Millisecond || job.Timings.Transcode != 45*time.Second {
+		t.Errorf("unexpected timings: %+v", job.Timings)
+	}
+	if job.Timings.Variants["1080p"] != 30*time.Second || job.Timings.Variants["360p"] != 15*time.Second {
+		t.Errorf("unexpected variant timings: %v", job.Timings.Variants)
+	}
+	if job.SourceDuration != 2*time.Minute || job.SourceHeight != 720 || job.OutputBytes != 2048 {
+		t.Errorf("unexpected source/output: %v %d %d", job.SourceDuration, job.SourceHeight, job.OutputBytes)
+	}
+	if job.Estimate == nil || job.Estimate.ProcessingTime != 40*time.Second || job.Estimate.OutputBytes != 1024 {
+		t.Errorf("unexpected estimate: %+v", job.Estimate)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestTranscodeJobRepository_CostStats(t *testing.T) {
+	since := time.Now().Add(-7 * 24 * time.Hour)
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM transcode_jobs (.+) GROUP BY source_height").
+		WithArgs(since).
+		WillReturnRows(pgxmock.NewRows([]string{"source_height", "jobs", "source_ms", "processing_ms", "output_bytes"}).
+			AddRow(720, int64(4), int64(240000), int64(120000), int64(80000000)).
+			AddRow(1080, int64(2), int64(60000), int64(90000), int64(30000000)))
+
+	repo := NewTranscodeJobRepository(mock)
+	stats, err := repo.CostStats(context.Background(), since)
+	if err != nil {
+		t.Fatalf("CostStats() error = %v", err)
+	}
+
+	want := []repository.TranscodeCostStats{
+		{SourceHeight: 720, Jobs: 4, SourceDuration: 4 * time.Minute, ProcessingTime: 2 * time.Minute, OutputBytes: 80000000},
+		{SourceHeight: 1080, Jobs: 2, SourceDuration: time.Minute, ProcessingTime: 90 * time.Second, OutputBytes: 30000000},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("CostStats() = %+v, want %+v", stats, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestTranscodeJobRepository_Stats(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		mockFn    func(mock pgxmock.PgxPoolIface)
+		wantStats *repository.TranscodeJobStats
+		wantErr   bool
+	}{
+		{
+			name: "aggregates final jobs",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM transcode_jobs").
+					WithArgs(since).
+					WillReturnRows(pgxmock.NewRows([]string{"succeeded", "failed", "p95", "samples"}).
+						AddRow(int64(98), int64(2), 412.5, int64(90)))
+			},
+			wantStats: &repository.TranscodeJobStats{
+				Succeeded:          98,
+				Failed:             2,
+				TimeToReadyP95:     412500 * time.Millisecond,
+				TimeToReadySamples: 90,
+			},
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM transcode_jobs").
+					WithArgs(since).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewTranscodeJobRepository(mock)
+			stats, err := repo.Stats(context.Background(), since)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Stats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantStats != nil && *stats != *tt.wantStats {
+				t.Errorf("Stats() = %+v, want %+v", *stats, *tt.wantStats)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
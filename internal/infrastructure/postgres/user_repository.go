@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const userColumns = `id, email, name, plan, created_at, updated_at`
+
+// UserRepository implements repository.UserRepository using PostgreSQL.
+type UserRepository struct {
+	db DBTX
+}
+
+// NewUserRepository creates a new UserRepository instance.
+func NewUserRepository(db DBTX) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create persists a new user.
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	const query = `
+		INSERT INTO users (` + userColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableUsers).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		user.ID,
+		user.Email,
+		nullString(user.Name),
+		user.Plan,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrDuplicateEmail
+		}
+		return fmt.Errorf("failed to create user: %w", classify(err))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a user by its unique identifier.
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	const query = `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableUsers).Inc()
+
+	var (
+		user model.User
+		name *string
+	)
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&user.ID,
+		&user.Email,
+		&name,
+		&user.Plan,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by ID: %w", classify(err))
+	}
+
+	if name != nil {
+		user.Name = *name
+	}
+
+	return &user, nil
+}
+
+// Compile-time verification that UserRepository implements repository.UserRepository.
+var _ repository.UserRepository = (*UserRepository)(nil)
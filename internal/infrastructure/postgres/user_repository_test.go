@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestUserRepository_Create(t *testing.T) {
+	user, err := model.NewUser("alice@example.com", "")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	user.Plan = "premium"
+
+	tests := []struct {
+		name    string
+		execErr error
+		wantErr error
+	}{
+		{
+			name: "successful creation",
+		},
+		{
+			name:    "email taken",
+			execErr: &pgconn.PgError{Code: "23505"},
+			wantErr: repository.ErrDuplicateEmail,
+		},
+		{
+			name:    "database error",
+			execErr: errors.New("connection refused"),
+			wantErr: errors.New("failed to create user"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			// An empty name is stored as NULL
+			exec := mock.ExpectExec("INSERT INTO users").
+				WithArgs(user.ID, user.Email, (*string)(nil), user.Plan, user.CreatedAt, user.UpdatedAt)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			err = NewUserRepository(mock).Create(context.Background(), user)
+
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && !containsError(err, tt.wantErr)) {
+					t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Create() unexpected error = %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetByID(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	name := "Alice"
+
+	tests := []struct {
+		name     string
+		mockFn   func(mock pgxmock.PgxPoolIface)
+		wantName string
+		wantPlan string
+		wantErr  error
+	}{
+		{
+			name: "found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM users WHERE id").
+					WithArgs(userID).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "email", "name", "plan", "created_at", "updated_at"}).
+						AddRow(userID, "alice@example.com", &name, "premium", now, now))
+			},
+			wantName: "Alice",
+			wantPlan: "premium",
+		},
+		{
+			name: "not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM users WHERE id").
+					WithArgs(userID).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			got, err := NewUserRepository(mock).GetByID(context.Background(), userID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetByID() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByID() unexpected error = %v", err)
+			}
+			if got.ID != userID || got.Name != tt.wantName || got.Plan != tt.wantPlan {
+				t.Errorf("GetByID() = %+v", got)
+			}
+		})
+	}
+}
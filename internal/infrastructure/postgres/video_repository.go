@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,9 +23,19 @@ type DBTX interface {
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+// Unique indexes whose violations map to their own errors.
+const (
+	shareSlugConstraint      = "idx_videos_share_slug"
+	titleSlugConstraint      = "idx_videos_user_id_title_slug"
+	idempotencyKeyConstraint = "idx_videos_user_id_idempotency_key"
+)
+
 // VideoRepository implements repository.VideoRepository using PostgreSQL.
 type VideoRepository struct {
 	db DBTX
+	// replica serves GetByID and List for contexts marked with repository.WithStaleReads;
+	// nil sends every query to db.
+	replica DBTX
 }
 
 // NewVideoRepository creates a new VideoRepository instance.
@@ -32,11 +43,29 @@ func NewVideoRepository(db DBTX) *VideoRepository {
 	return &VideoRepository{db: db}
 }
 
+// NewVideoRepositoryWithReplica creates a VideoRepository that serves GetByID and List
+// from a read replica when the context tolerates stale reads, and everything else,
+// including every write, from the primary.
+func NewVideoRepositoryWithReplica(primary, replica DBTX) *VideoRepository {
+	return &VideoRepository{db: primary, replica: replica}
+}
+
+// reader returns the database for a read the replica may serve.
+func (r *VideoRepository) reader(ctx context.Context) DBTX {
+	if r.replica == nil {
+		return r.db
+	}
+	if allowed, _ := repository.StaleReads(ctx); allowed {
+		return r.replica
+	}
+	return r.db
+}
+
 // Create persists a new video entity.
 func (r *VideoRepository) Create(ctx context.Context, video *model.Video) error {
 	const query = `
-		INSERT INTO videos (id, user_id, title, status, original_url, hls_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO videos (id, user_id, title, status, original_url, hls_url, dash_url, preview_seconds, preview_url, thumbnail_prefix, output_version, sortable_id, share_slug, title_slug, storage_key, created_at, updated_at, expires_at, external_output, idempotency_key, idempotency_hash, description, tags, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 	`
 
 	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableVideos).Inc()
@@ -48,15 +77,39 @@ func (r *VideoRepository) Create(ctx context.Context, video *model.Video) error
 		video.Status.String(),
 		nullString(video.OriginalURL),
 		nullString(video.HLSURL),
+		nullString(video.DashURL),
+		video.PreviewSeconds,
+		nullString(video.PreviewURL),
+		nullString(video.ThumbnailPrefix),
+		video.OutputVersion,
+		nullString(video.SortableID),
+		nullString(video.ShareSlug),
+		nullString(video.TitleSlug),
+		nullString(video.StorageKey),
 		video.CreatedAt,
 		video.UpdatedAt,
+		nullTime(video.ExpiresAt),
+		video.ExternalOutput,
+		nullString(video.IdempotencyKey),
+		nullString(video.IdempotencyHash),
+		video.Description,
+		textArray(video.Tags),
+		visibilityOrDefault(video.Visibility),
 	)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			switch pgErr.ConstraintName {
+			case shareSlugConstraint:
+				return repository.ErrDuplicateShareSlug
+			case titleSlugConstraint:
+				return repository.ErrDuplicateTitleSlug
+			case idempotencyKeyConstraint:
+				return repository.ErrDuplicateIdempotencyKey
+			}
 			return repository.ErrDuplicateVideo
 		}
-		return fmt.Errorf("failed to create video: %w", err)
+		return fmt.Errorf("failed to create video: %w", classify(err))
 	}
 
 	return nil
@@ -65,62 +118,275 @@ func (r *VideoRepository) Create(ctx context.Context, video *model.Video) error
 // GetByID retrieves a video by its unique identifier.
 func (r *VideoRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Video, error) {
 	const query = `
-		SELECT id, user_id, title, status, original_url, hls_url, created_at, updated_at
+		SELECT id, user_id, title, status, original_url, hls_url, dash_url, preview_seconds, preview_url, thumbnail_prefix, output_version, external_output, sortable_id, share_slug, title_slug, storage_key, original_size, original_etag,
+		       source_duration_ms, source_width, source_height, source_codec, source_bitrate, source_frame_rate,
+		       expires_at, failure_code, failure_reason, created_at, updated_at, description, tags, visibility
 		FROM videos
 		WHERE id = $1
 	`
 
 	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideos).Inc()
 
-	video, err := r.scanVideo(r.db.QueryRow(ctx, query, id))
+	video, err := r.scanVideo(r.reader(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrVideoNotFound
+		}
+		return nil, fmt.Errorf("failed to get video by ID: %w", classify(err))
+	}
+
+	return video, nil
+}
+
+// GetByShareSlug retrieves a video by its share slug.
+func (r *VideoRepository) GetByShareSlug(ctx context.Context, slug string) (*model.Video, error) {
+	const query = `
+		SELECT id, user_id, title, status, original_url, hls_url, dash_url, preview_seconds, preview_url, thumbnail_prefix, output_version, external_output, sortable_id, share_slug, title_slug, storage_key, original_size, original_etag,
+		       source_duration_ms, source_width, source_height, source_codec, source_bitrate, source_frame_rate,
+		       expires_at, failure_code, failure_reason, created_at, updated_at, description, tags, visibility
+		FROM videos
+		WHERE share_slug = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideos).Inc()
+
+	video, err := r.scanVideo(r.db.QueryRow(ctx, query, slug))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrVideoNotFound
+		}
+		return nil, fmt.Errorf("failed to get video by share slug: %w", classify(err))
+	}
+
+	return video, nil
+}
+
+// GetByTitleSlug retrieves a user's video by its title slug.
+func (r *VideoRepository) GetByTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error) {
+	const query = `
+		SELECT id, user_id, title, status, original_url, hls_url, dash_url, preview_seconds, preview_url, thumbnail_prefix, output_version, external_output, sortable_id, share_slug, title_slug, storage_key, original_size, original_etag,
+		       source_duration_ms, source_width, source_height, source_codec, source_bitrate, source_frame_rate,
+		       expires_at, failure_code, failure_reason, created_at, updated_at, description, tags, visibility
+		FROM videos
+		WHERE user_id = $1 AND title_slug = $2
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideos).Inc()
+
+	video, err := r.scanVideo(r.db.QueryRow(ctx, query, userID, slug))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrVideoNotFound
 		}
-		return nil, fmt.Errorf("failed to get video by ID: %w", err)
+		return nil, fmt.Errorf("failed to get video by title slug: %w", classify(err))
 	}
 
 	return video, nil
 }
 
-// GetByUserID retrieves all videos belonging to a user.
-func (r *VideoRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Video, error) {
+// GetByIdempotencyKey retrieves the video a user created with the idempotency key.
+// The hash is selected after the usual columns and scanned alongside them.
+func (r *VideoRepository) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*model.Video, error) {
 	const query = `
-		SELECT id, user_id, title, status, original_url, hls_url, created_at, updated_at
+		SELECT id, user_id, title, status, original_url, hls_url, dash_url, preview_seconds, preview_url, thumbnail_prefix, output_version, external_output, sortable_id, share_slug, title_slug, storage_key, original_size, original_etag,
+		       source_duration_ms, source_width, source_height, source_codec, source_bitrate, source_frame_rate,
+		       expires_at, failure_code, failure_reason, created_at, updated_at, description, tags, visibility, idempotency_hash
 		FROM videos
-		WHERE user_id = $1
-		ORDER BY created_at DESC
+		WHERE user_id = $1 AND idempotency_key = $2
 	`
 
 	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideos).Inc()
 
-	rows, err := r.db.Query(ctx, query, userID)
+	var hash *string
+	video, err := r.scanVideo(extraColumnsRow{Row: r.db.QueryRow(ctx, query, userID, key), dest: []any{&hash}})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrVideoNotFound
+		}
+		return nil, fmt.Errorf("failed to get video by idempotency key: %w", classify(err))
+	}
+	video.IdempotencyKey = key
+	if hash != nil {
+		video.IdempotencyHash = *hash
+	}
+
+	return video, nil
+}
+
+// extraColumnsRow scans the columns following those of scanVideo into dest.
+type extraColumnsRow struct {
+	pgx.Row
+	dest []any
+}
+
+func (r extraColumnsRow) Scan(dest ...any) error {
+	return r.Row.Scan(append(dest, r.dest...)...)
+}
+
+// List retrieves videos matching filter, newest first unless filter.Ascending.
+// Date bounds use idx_videos_created_at, or idx_videos_user_id_created_at_id for one user,
+// both scanned backwards for ascending listings; the title prefix uses
+// idx_videos_title_prefix, which indexes lower(title) with text_pattern_ops so
+// LIKE 'prefix%' can range-scan.
+func (r *VideoRepository) List(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.UserID != uuid.Nil {
+		addCond("user_id = $%d", filter.UserID)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		addCond("created_at > $%d", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		addCond("created_at < $%d", filter.CreatedBefore)
+	}
+	if filter.TitlePrefix != "" {
+		addCond(`lower(title) LIKE $%d ESCAPE '\'`, escapeLike(strings.ToLower(filter.TitlePrefix))+"%")
+	}
+	if filter.Status != "" {
+		addCond("status = $%d", string(filter.Status))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		addCond("status = ANY($%d)", statuses)
+	}
+	if filter.Visibility != "" {
+		addCond("visibility = $%d", string(filter.Visibility))
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		addCond("updated_at < $%d", filter.UpdatedBefore)
+	}
+	if !filter.ExpiresBefore.IsZero() {
+		addCond("expires_at < $%d", filter.ExpiresBefore)
+	}
+	if filter.ExcludeDeleted {
+		addCond("status <> $%d", string(model.StatusDeleted))
+	}
+	if filter.ExcludeExpired {
+		addCond("status <> $%d", string(model.StatusExpired))
+	}
+	order, past := "DESC", "<"
+	if filter.Ascending {
+		order, past = "ASC", ">"
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) %s ($%d, $%d)", past, len(args)-1, len(args)))
+	}
+
+	query := `
+		SELECT id, user_id, title, status, original_url, hls_url, dash_url, preview_seconds, preview_url, thumbnail_prefix, output_version, external_output, sortable_id, share_slug, title_slug, storage_key, original_size, original_etag,
+		       source_duration_ms, source_width, source_height, source_codec, source_bitrate, source_frame_rate,
+		       expires_at, failure_code, failure_reason, created_at, updated_at, description, tags, visibility
+		FROM videos`
+	if len(conds) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf("\n\t\tORDER BY created_at %s, id %s\n\t\tLIMIT $%d", order, order, len(args))
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideos).Inc()
+
+	rows, err := r.reader(ctx).Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query videos by user ID: %w", err)
+		return nil, fmt.Errorf("failed to list videos: %w", classify(err))
 	}
 	defer rows.Close()
 
-	var videos []*model.Video
+	videos := []*model.Video{}
 	for rows.Next() {
 		video, err := r.scanVideoFromRows(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan video: %w", err)
+			return nil, fmt.Errorf("failed to scan video: %w", classify(err))
 		}
 		videos = append(videos, video)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating videos: %w", err)
+		return nil, fmt.Errorf("error iterating videos: %w", classify(err))
 	}
 
 	return videos, nil
 }
 
+// Search retrieves videos whose titles or descriptions match search.Query, best match
+// first; title matches are weighted above description matches.
+// The match uses idx_videos_search_vector; ranks are real, so the cursor's rank compares
+// exactly with the one computed for the same row again.
+func (r *VideoRepository) Search(ctx context.Context, search repository.VideoSearch) ([]repository.VideoSearchResult, error) {
+	args := []any{search.Query, string(model.StatusDeleted), string(model.StatusExpired)}
+	conds := []string{"search_vector @@ websearch_to_tsquery('simple', $1)", "status <> $2", "status <> $3"}
+
+	if search.UserID != uuid.Nil {
+		args = append(args, search.UserID)
+		conds = append(conds, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if search.Visibility != "" {
+		args = append(args, string(search.Visibility))
+		conds = append(conds, fmt.Sprintf("visibility = $%d", len(args)))
+	}
+	if search.After != nil {
+		args = append(args, search.After.Rank, search.After.CreatedAt, search.After.ID)
+		conds = append(conds, fmt.Sprintf("(rank, created_at, id) < ($%d::real, $%d, $%d)", len(args)-2, len(args)-1, len(args)))
+	}
+	args = append(args, search.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, title, status, original_url, hls_url, dash_url, preview_seconds, preview_url, thumbnail_prefix, output_version, external_output, sortable_id, share_slug, title_slug, storage_key, original_size, original_etag,
+		       source_duration_ms, source_width, source_height, source_codec, source_bitrate, source_frame_rate,
+		       expires_at, failure_code, failure_reason, created_at, updated_at, description, tags, visibility, rank
+		FROM (
+			SELECT *, ts_rank(search_vector, websearch_to_tsquery('simple', $1)) AS rank
+			FROM videos
+		) ranked
+		WHERE %s
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT $%d`, strings.Join(conds, " AND "), len(args))
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideos).Inc()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search videos: %w", classify(err))
+	}
+	defer rows.Close()
+
+	results := []repository.VideoSearchResult{}
+	for rows.Next() {
+		var rank float32
+		video, err := r.scanVideo(extraColumnsRow{Row: rows, dest: []any{&rank}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan video: %w", classify(err))
+		}
+		results = append(results, repository.VideoSearchResult{Video: video, Rank: rank})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating videos: %w", classify(err))
+	}
+
+	return results, nil
+}
+
 // Update persists changes to an existing video entity.
 func (r *VideoRepository) Update(ctx context.Context, video *model.Video) error {
 	const query = `
 		UPDATE videos
-		SET title = $2, status = $3, original_url = $4, hls_url = $5, updated_at = $6
+		SET title = $2, status = $3, original_url = $4, hls_url = $5, dash_url = $6,
+		    preview_seconds = $7, preview_url = $8, thumbnail_prefix = $9, output_version = $10,
+		    updated_at = $11, original_size = $12, original_etag = $13, expires_at = $14,
+		    external_output = $15, failure_code = $16, failure_reason = $17, description = $18,
+		    tags = $19, visibility = $20
 		WHERE id = $1
 	`
 
@@ -134,10 +400,24 @@ func (r *VideoRepository) Update(ctx context.Context, video *model.Video) error
 		video.Status.String(),
 		nullString(video.OriginalURL),
 		nullString(video.HLSURL),
+		nullString(video.DashURL),
+		video.PreviewSeconds,
+		nullString(video.PreviewURL),
+		nullString(video.ThumbnailPrefix),
+		video.OutputVersion,
 		video.UpdatedAt,
+		nullInt64(video.OriginalSize),
+		nullString(video.OriginalETag),
+		nullTime(video.ExpiresAt),
+		video.ExternalOutput,
+		nullString(string(video.FailureCode)),
+		nullString(video.FailureReason),
+		video.Description,
+		textArray(video.Tags),
+		visibilityOrDefault(video.Visibility),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update video: %w", err)
+		return fmt.Errorf("failed to update video: %w", classify(err))
 	}
 
 	if tag.RowsAffected() == 0 {
@@ -159,7 +439,7 @@ func (r *VideoRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 
 	tag, err := r.db.Exec(ctx, query, id, status.String(), time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to update video status: %w", err)
+		return fmt.Errorf("failed to update video status: %w", classify(err))
 	}
 
 	if tag.RowsAffected() == 0 {
@@ -169,13 +449,130 @@ func (r *VideoRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
+// UpdateSource records the probed metadata of a video's original.
+func (r *VideoRepository) UpdateSource(ctx context.Context, id uuid.UUID, source model.SourceMetadata) error {
+	const query = `
+		UPDATE videos
+		SET source_duration_ms = $2, source_width = $3, source_height = $4, source_codec = $5,
+		    source_bitrate = $6, source_frame_rate = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideos).Inc()
+
+	tag, err := r.db.Exec(ctx, query,
+		id,
+		nullInt64(source.Duration.Milliseconds()),
+		nullInt64(int64(source.Width)),
+		nullInt64(int64(source.Height)),
+		nullString(source.Codec),
+		nullInt64(source.Bitrate),
+		nullFloat64(source.FrameRate),
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update video source: %w", classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrVideoNotFound
+	}
+
+	return nil
+}
+
+// PublishOutput swaps the video's output pointer to version if it is newer than the current one.
+// The version check and the swap happen in a single statement, so a slow worker finishing an
+// older regeneration can never roll the pointer back.
+func (r *VideoRepository) PublishOutput(ctx context.Context, id uuid.UUID, version int64, hlsURL, dashURL, previewURL, thumbnailPrefix string, externalOutput bool) error {
+	const query = `
+		WITH updated AS (
+			UPDATE videos
+			SET hls_url = $3, dash_url = $4, preview_url = $5, thumbnail_prefix = $6, output_version = $2, updated_at = $7,
+			    external_output = $8
+			WHERE id = $1 AND output_version < $2
+			RETURNING id
+		)
+		SELECT EXISTS (SELECT 1 FROM updated), EXISTS (SELECT 1 FROM videos WHERE id = $1)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideos).Inc()
+
+	var published, exists bool
+	err := r.db.QueryRow(ctx, query, id, version, nullString(hlsURL), nullString(dashURL), nullString(previewURL), nullString(thumbnailPrefix), time.Now(), externalOutput).Scan(&published, &exists)
+	if err != nil {
+		return fmt.Errorf("failed to publish video output: %w", classify(err))
+	}
+
+	if !exists {
+		return repository.ErrVideoNotFound
+	}
+	if !published {
+		return repository.ErrStaleOutputVersion
+	}
+
+	return nil
+}
+
+// SetOutputSize records the output size without touching updated_at, since the video
+// itself has not changed.
+func (r *VideoRepository) SetOutputSize(ctx context.Context, id uuid.UUID, size int64) error {
+	const query = `UPDATE videos SET output_size = $2 WHERE id = $1`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryUpdate, metrics.TableVideos).Inc()
+
+	tag, err := r.db.Exec(ctx, query, id, size)
+	if err != nil {
+		return fmt.Errorf("failed to set video output size: %w", classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrVideoNotFound
+	}
+
+	return nil
+}
+
+// GetStorageUsage sums the user's videos with the user_id index.
+func (r *VideoRepository) GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error) {
+	const query = `
+		SELECT COUNT(*), COALESCE(SUM(original_size), 0), COALESCE(SUM(output_size), 0)
+		FROM videos
+		WHERE user_id = $1 AND status <> $2
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideos).Inc()
+
+	usage := &model.StorageUsage{UserID: userID}
+	err := r.db.QueryRow(ctx, query, userID, string(model.StatusDeleted)).Scan(&usage.Videos, &usage.OriginalBytes, &usage.OutputBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage usage: %w", classify(err))
+	}
+
+	return usage, nil
+}
+
 // scanVideo scans a single row into a Video model.
 func (r *VideoRepository) scanVideo(row pgx.Row) (*model.Video, error) {
 	var (
-		video       model.Video
-		status      string
-		originalURL *string
-		hlsURL      *string
+		video         model.Video
+		status        string
+		originalURL   *string
+		hlsURL        *string
+		dashURL       *string
+		previewURL    *string
+		thumbnail     *string
+		sortableID    *string
+		shareSlug     *string
+		titleSlug     *string
+		storageKey    *string
+		originalSize  *int64
+		originalETag  *string
+		source        nullSource
+		expiresAt     *time.Time
+		failureCode   *string
+		failureReason *string
+		visibility    string
 	)
 
 	err := row.Scan(
@@ -185,20 +582,82 @@ func (r *VideoRepository) scanVideo(row pgx.Row) (*model.Video, error) {
 		&status,
 		&originalURL,
 		&hlsURL,
+		&dashURL,
+		&video.PreviewSeconds,
+		&previewURL,
+		&thumbnail,
+		&video.OutputVersion,
+		&video.ExternalOutput,
+		&sortableID,
+		&shareSlug,
+		&titleSlug,
+		&storageKey,
+		&originalSize,
+		&originalETag,
+		&source.durationMs,
+		&source.width,
+		&source.height,
+		&source.codec,
+		&source.bitrate,
+		&source.frameRate,
+		&expiresAt,
+		&failureCode,
+		&failureReason,
 		&video.CreatedAt,
 		&video.UpdatedAt,
+		&video.Description,
+		&video.Tags,
+		&visibility,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	video.Status = model.Status(status)
+	video.Visibility = model.Visibility(visibility)
 	if originalURL != nil {
 		video.OriginalURL = *originalURL
 	}
 	if hlsURL != nil {
 		video.HLSURL = *hlsURL
 	}
+	if dashURL != nil {
+		video.DashURL = *dashURL
+	}
+	if previewURL != nil {
+		video.PreviewURL = *previewURL
+	}
+	if thumbnail != nil {
+		video.ThumbnailPrefix = *thumbnail
+	}
+	if sortableID != nil {
+		video.SortableID = *sortableID
+	}
+	if shareSlug != nil {
+		video.ShareSlug = *shareSlug
+	}
+	if titleSlug != nil {
+		video.TitleSlug = *titleSlug
+	}
+	if storageKey != nil {
+		video.StorageKey = *storageKey
+	}
+	if originalSize != nil {
+		video.OriginalSize = *originalSize
+	}
+	if originalETag != nil {
+		video.OriginalETag = *originalETag
+	}
+	video.Source = source.metadata()
+	if expiresAt != nil {
+		video.ExpiresAt = *expiresAt
+	}
+	if failureCode != nil {
+		video.FailureCode = model.FailureCode(*failureCode)
+	}
+	if failureReason != nil {
+		video.FailureReason = *failureReason
+	}
 
 	return &video, nil
 }
@@ -206,10 +665,24 @@ func (r *VideoRepository) scanVideo(row pgx.Row) (*model.Video, error) {
 // scanVideoFromRows scans from pgx.Rows into a Video model.
 func (r *VideoRepository) scanVideoFromRows(rows pgx.Rows) (*model.Video, error) {
 	var (
-		video       model.Video
-		status      string
-		originalURL *string
-		hlsURL      *string
+		video         model.Video
+		status        string
+		originalURL   *string
+		hlsURL        *string
+		dashURL       *string
+		previewURL    *string
+		thumbnail     *string
+		sortableID    *string
+		shareSlug     *string
+		titleSlug     *string
+		storageKey    *string
+		originalSize  *int64
+		originalETag  *string
+		source        nullSource
+		expiresAt     *time.Time
+		failureCode   *string
+		failureReason *string
+		visibility    string
 	)
 
 	err := rows.Scan(
@@ -219,24 +692,91 @@ func (r *VideoRepository) scanVideoFromRows(rows pgx.Rows) (*model.Video, error)
 		&status,
 		&originalURL,
 		&hlsURL,
+		&dashURL,
+		&video.PreviewSeconds,
+		&previewURL,
+		&thumbnail,
+		&video.OutputVersion,
+		&video.ExternalOutput,
+		&sortableID,
+		&shareSlug,
+		&titleSlug,
+		&storageKey,
+		&originalSize,
+		&originalETag,
+		&source.durationMs,
+		&source.width,
+		&source.height,
+		&source.codec,
+		&source.bitrate,
+		&source.frameRate,
+		&expiresAt,
+		&failureCode,
+		&failureReason,
 		&video.CreatedAt,
 		&video.UpdatedAt,
+		&video.Description,
+		&video.Tags,
+		&visibility,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	video.Status = model.Status(status)
+	video.Visibility = model.Visibility(visibility)
 	if originalURL != nil {
 		video.OriginalURL = *originalURL
 	}
 	if hlsURL != nil {
 		video.HLSURL = *hlsURL
 	}
+	if dashURL != nil {
+		video.DashURL = *dashURL
+	}
+	if previewURL != nil {
+		video.PreviewURL = *previewURL
+	}
+	if thumbnail != nil {
+		video.ThumbnailPrefix = *thumbnail
+	}
+	if sortableID != nil {
+		video.SortableID = *sortableID
+	}
+	if shareSlug != nil {
+		video.ShareSlug = *shareSlug
+	}
+	if titleSlug != nil {
+		video.TitleSlug = *titleSlug
+	}
+	if storageKey != nil {
+		video.StorageKey = *storageKey
+	}
+	if originalSize != nil {
+		video.OriginalSize = *originalSize
+	}
+	if originalETag != nil {
+		video.OriginalETag = *originalETag
+	}
+	video.Source = source.metadata()
+	if expiresAt != nil {
+		video.ExpiresAt = *expiresAt
+	}
+	if failureCode != nil {
+		video.FailureCode = model.FailureCode(*failureCode)
+	}
+	if failureReason != nil {
+		video.FailureReason = *failureReason
+	}
 
 	return &video, nil
 }
 
+// escapeLike escapes LIKE wildcards so user input matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 // nullString returns nil for empty strings, otherwise returns a pointer to the string.
 func nullString(s string) *string {
 	if s == "" {
@@ -245,5 +785,79 @@ func nullString(s string) *string {
 	return &s
 }
 
+// textArray returns s, or an empty slice for nil, which pgx would encode as NULL.
+func textArray(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// visibilityOrDefault returns v, or the column default for videos built without one.
+func visibilityOrDefault(v model.Visibility) string {
+	if v == "" {
+		return model.VisibilityUnlisted.String()
+	}
+	return v.String()
+}
+
+// nullInt64 converts zero to nil for nullable integer columns.
+func nullInt64(n int64) *int64 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
+// nullFloat64 converts zero to nil for nullable floating-point columns.
+func nullFloat64(f float64) *float64 {
+	if f == 0 {
+		return nil
+	}
+	return &f
+}
+
+// nullTime converts the zero time to nil for nullable timestamp columns.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nullSource holds the nullable source metadata columns of a video row.
+type nullSource struct {
+	durationMs *int64
+	width      *int
+	height     *int
+	codec      *string
+	bitrate    *int64
+	frameRate  *float64
+}
+
+// metadata converts the scanned columns, treating NULL as the zero value.
+func (n nullSource) metadata() model.SourceMetadata {
+	var m model.SourceMetadata
+	if n.durationMs != nil {
+		m.Duration = time.Duration(*n.durationMs) * time.Millisecond
+	}
+	if n.width != nil {
+		m.Width = *n.width
+	}
+	if n.height != nil {
+		m.Height = *n.height
+	}
+	if n.codec != nil {
+		m.Codec = *n.codec
+	}
+	if n.bitrate != nil {
+		m.Bitrate = *n.bitrate
+	}
+	if n.frameRate != nil {
+		m.FrameRate = *n.frameRate
+	}
+	return m
+}
+
 // Compile-time verification that VideoRepository implements repository.VideoRepository.
 var _ repository.VideoRepository = (*VideoRepository)(nil)
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -43,6 +45,22 @@ func TestVideoRepository_Create(t *testing.T) {
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						"",
+						[]string{},
+						"unlisted",
 					).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 			},
@@ -69,11 +87,160 @@ func TestVideoRepository_Create(t *testing.T) {
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						"",
+						[]string{},
+						"unlisted",
 					).
 					WillReturnError(&pgconn.PgError{Code: "23505"})
 			},
 			wantErr: repository.ErrDuplicateVideo,
 		},
+		{
+			name: "duplicate share slug error",
+			video: &model.Video{
+				ID:         uuid.New(),
+				UserID:     uuid.New(),
+				Title:      "Test Video",
+				Status:     model.StatusPendingUpload,
+				SortableID: "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+				ShareSlug:  "a1b2c3d4",
+				CreatedAt:  time.Now(),
+				UpdatedAt:  time.Now(),
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface, video *model.Video) {
+				mock.ExpectExec("INSERT INTO videos").
+					WithArgs(
+						video.ID,
+						video.UserID,
+						video.Title,
+						video.Status.String(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						&video.SortableID,
+						&video.ShareSlug,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						"",
+						[]string{},
+						"unlisted",
+					).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_videos_share_slug"})
+			},
+			wantErr: repository.ErrDuplicateShareSlug,
+		},
+		{
+			name: "duplicate title slug error",
+			video: &model.Video{
+				ID:        uuid.New(),
+				UserID:    uuid.New(),
+				Title:     "Test Video",
+				Status:    model.StatusPendingUpload,
+				ShareSlug: "a1b2c3d4",
+				TitleSlug: "test-video",
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface, video *model.Video) {
+				mock.ExpectExec("INSERT INTO videos").
+					WithArgs(
+						video.ID,
+						video.UserID,
+						video.Title,
+						video.Status.String(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						&video.ShareSlug,
+						&video.TitleSlug,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						"",
+						[]string{},
+						"unlisted",
+					).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_videos_user_id_title_slug"})
+			},
+			wantErr: repository.ErrDuplicateTitleSlug,
+		},
+		{
+			name: "duplicate idempotency key error",
+			video: &model.Video{
+				ID:              uuid.New(),
+				UserID:          uuid.New(),
+				Title:           "Test Video",
+				Status:          model.StatusPendingUpload,
+				ShareSlug:       "a1b2c3d4",
+				IdempotencyKey:  "retry-1",
+				IdempotencyHash: "3f8a",
+				CreatedAt:       time.Now(),
+				UpdatedAt:       time.Now(),
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface, video *model.Video) {
+				mock.ExpectExec("INSERT INTO videos").
+					WithArgs(
+						video.ID,
+						video.UserID,
+						video.Title,
+						video.Status.String(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						&video.ShareSlug,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						&video.IdempotencyKey,
+						&video.IdempotencyHash,
+						"",
+						[]string{},
+						"unlisted",
+					).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_videos_user_id_idempotency_key"})
+			},
+			wantErr: repository.ErrDuplicateIdempotencyKey,
+		},
 		{
 			name: "database error",
 			video: &model.Video{
@@ -95,6 +262,22 @@ func TestVideoRepository_Create(t *testing.T) {
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						"",
+						[]string{},
+						"unlisted",
 					).
 					WillReturnError(errors.New("connection refused"))
 			},
@@ -154,21 +337,23 @@ func TestVideoRepository_GetByID(t *testing.T) {
 			id:   videoID,
 			mockFn: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{
-					"id", "user_id", "title", "status", "original_url", "hls_url", "created_at", "updated_at",
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
 				}).AddRow(
-					videoID, userID, "Test Video", "PENDING_UPLOAD", nil, nil, now, now,
+					videoID, userID, "Test Video", "PENDING_UPLOAD", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted",
 				)
 				mock.ExpectQuery("SELECT .* FROM videos WHERE id").
 					WithArgs(videoID).
 					WillReturnRows(rows)
 			},
 			want: &model.Video{
-				ID:        videoID,
-				UserID:    userID,
-				Title:     "Test Video",
-				Status:    model.StatusPendingUpload,
-				CreatedAt: now,
-				UpdatedAt: now,
+				ID:         videoID,
+				UserID:     userID,
+				Title:      "Test Video",
+				Status:     model.StatusPendingUpload,
+				Visibility: model.VisibilityUnlisted,
+				CreatedAt:  now,
+				UpdatedAt:  now,
 			},
 			wantErr: nil,
 		},
@@ -189,10 +374,12 @@ func TestVideoRepository_GetByID(t *testing.T) {
 			mockFn: func(mock pgxmock.PgxPoolIface) {
 				originalURL := "s3://bucket/original.mp4"
 				hlsURL := "s3://bucket/hls/master.m3u8"
+				dashURL := "s3://bucket/hls/manifest.mpd"
 				rows := pgxmock.NewRows([]string{
-					"id", "user_id", "title", "status", "original_url", "hls_url", "created_at", "updated_at",
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
 				}).AddRow(
-					videoID, userID, "Test Video", "READY", &originalURL, &hlsURL, now, now,
+					videoID, userID, "Test Video", "READY", &originalURL, &hlsURL, &dashURL, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted",
 				)
 				mock.ExpectQuery("SELECT .* FROM videos WHERE id").
 					WithArgs(videoID).
@@ -203,8 +390,169 @@ func TestVideoRepository_GetByID(t *testing.T) {
 				UserID:      userID,
 				Title:       "Test Video",
 				Status:      model.StatusReady,
+				Visibility:  model.VisibilityUnlisted,
 				OriginalURL: "s3://bucket/original.mp4",
 				HLSURL:      "s3://bucket/hls/master.m3u8",
+				DashURL:     "s3://bucket/hls/manifest.mpd",
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "with preview and thumbnails",
+			id:   videoID,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				previewURL := "previews/" + videoID.String() + "/playlist.m3u8"
+				thumbnailPrefix := "hls/" + videoID.String() + "/v3/thumbnails/"
+				storageKey := "9f86d081884c7d659a2feaa0c55ad015"
+				expiresAt := now.Add(24 * time.Hour)
+				rows := pgxmock.NewRows([]string{
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+				}).AddRow(
+					videoID, userID, "Test Video", "READY", nil, nil, nil, 60, &previewURL, &thumbnailPrefix, int64(3), false, nil, nil, nil, &storageKey, nil, nil, nil, nil, nil, nil, nil, nil, &expiresAt, nil, nil, now, now, "", []string{}, "unlisted",
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE id").
+					WithArgs(videoID).
+					WillReturnRows(rows)
+			},
+			want: &model.Video{
+				ID:              videoID,
+				UserID:          userID,
+				Title:           "Test Video",
+				Status:          model.StatusReady,
+				Visibility:      model.VisibilityUnlisted,
+				PreviewSeconds:  60,
+				PreviewURL:      "previews/" + videoID.String() + "/playlist.m3u8",
+				ThumbnailPrefix: "hls/" + videoID.String() + "/v3/thumbnails/",
+				StorageKey:      "9f86d081884c7d659a2feaa0c55ad015",
+				OutputVersion:   3,
+				ExpiresAt:       now.Add(24 * time.Hour),
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "with confirmed upload",
+			id:   videoID,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				originalURL := "originals/" + videoID.String() + "/video.mp4"
+				size := int64(1024)
+				etag := "abc123"
+				rows := pgxmock.NewRows([]string{
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+				}).AddRow(
+					videoID, userID, "Test Video", "UPLOADED", &originalURL, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, &size, &etag, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted",
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE id").
+					WithArgs(videoID).
+					WillReturnRows(rows)
+			},
+			want: &model.Video{
+				ID:           videoID,
+				UserID:       userID,
+				Title:        "Test Video",
+				Status:       model.StatusUploaded,
+				Visibility:   model.VisibilityUnlisted,
+				OriginalURL:  "originals/" + videoID.String() + "/video.mp4",
+				OriginalSize: 1024,
+				OriginalETag: "abc123",
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "with probed source",
+			id:   videoID,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				durationMs := int64(90500)
+				width, height := 1920, 1080
+				codec := "h264"
+				bitrate := int64(5000000)
+				frameRate := 29.97
+				rows := pgxmock.NewRows([]string{
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+				}).AddRow(
+					videoID, userID, "Test Video", "PROCESSING", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, &durationMs, &width, &height, &codec, &bitrate, &frameRate, nil, nil, nil, now, now, "", []string{}, "unlisted",
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE id").
+					WithArgs(videoID).
+					WillReturnRows(rows)
+			},
+			want: &model.Video{
+				ID:         videoID,
+				UserID:     userID,
+				Title:      "Test Video",
+				Status:     model.StatusProcessing,
+				Visibility: model.VisibilityUnlisted,
+				Source: model.SourceMetadata{
+					Duration:  90500 * time.Millisecond,
+					Width:     1920,
+					Height:    1080,
+					Codec:     "h264",
+					Bitrate:   5000000,
+					FrameRate: 29.97,
+				},
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "failed video with failure reason",
+			id:   videoID,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				code, reason := "invalid_input", "input has no video stream"
+				rows := pgxmock.NewRows([]string{
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+				}).AddRow(
+					videoID, userID, "Test Video", "FAILED", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &code, &reason, now, now, "", []string{}, "unlisted",
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE id").
+					WithArgs(videoID).
+					WillReturnRows(rows)
+			},
+			want: &model.Video{
+				ID:            videoID,
+				UserID:        userID,
+				Title:         "Test Video",
+				Status:        model.StatusFailed,
+				Visibility:    model.VisibilityUnlisted,
+				FailureCode:   model.FailureCodeInvalidInput,
+				FailureReason: "input has no video stream",
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "with metadata",
+			id:   videoID,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows([]string{
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+				}).AddRow(
+					videoID, userID, "Test Video", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "A walk through the park.", []string{"outdoors", "walk"}, "private",
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE id").
+					WithArgs(videoID).
+					WillReturnRows(rows)
+			},
+			want: &model.Video{
+				ID:          videoID,
+				UserID:      userID,
+				Title:       "Test Video",
+				Description: "A walk through the park.",
+				Tags:        []string{"outdoors", "walk"},
+				Status:      model.StatusReady,
+				Visibility:  model.VisibilityPrivate,
 				CreatedAt:   now,
 				UpdatedAt:   now,
 			},
@@ -242,7 +590,17 @@ func TestVideoRepository_GetByID(t *testing.T) {
 				got.Title != tt.want.Title ||
 				got.Status != tt.want.Status ||
 				got.OriginalURL != tt.want.OriginalURL ||
-				got.HLSURL != tt.want.HLSURL {
+				got.HLSURL != tt.want.HLSURL ||
+				got.PreviewSeconds != tt.want.PreviewSeconds ||
+				got.PreviewURL != tt.want.PreviewURL ||
+				got.OriginalSize != tt.want.OriginalSize ||
+				got.OriginalETag != tt.want.OriginalETag ||
+				got.Source != tt.want.Source ||
+				got.FailureCode != tt.want.FailureCode ||
+				got.FailureReason != tt.want.FailureReason ||
+				got.Description != tt.want.Description ||
+				got.Visibility != tt.want.Visibility ||
+				strings.Join(got.Tags, ",") != strings.Join(tt.want.Tags, ",") {
 				t.Errorf("GetByID() = %+v, want %+v", got, tt.want)
 			}
 
@@ -253,48 +611,40 @@ func TestVideoRepository_GetByID(t *testing.T) {
 	}
 }
 
-func TestVideoRepository_GetByUserID(t *testing.T) {
-	now := time.Now()
+func TestVideoRepository_GetByShareSlug(t *testing.T) {
+	videoID := uuid.New()
 	userID := uuid.New()
-	videoID1 := uuid.New()
-	videoID2 := uuid.New()
+	now := time.Now()
+	sortableID := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	slug := "a1b2c3d4"
 
 	tests := []struct {
 		name    string
-		userID  uuid.UUID
 		mockFn  func(mock pgxmock.PgxPoolIface)
-		want    int
-		wantErr bool
+		wantErr error
 	}{
 		{
-			name:   "returns multiple videos",
-			userID: userID,
+			name: "successful retrieval",
 			mockFn: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{
-					"id", "user_id", "title", "status", "original_url", "hls_url", "created_at", "updated_at",
-				}).
-					AddRow(videoID1, userID, "Video 1", "READY", nil, nil, now, now).
-					AddRow(videoID2, userID, "Video 2", "PENDING_UPLOAD", nil, nil, now, now)
-				mock.ExpectQuery("SELECT .* FROM videos WHERE user_id").
-					WithArgs(userID).
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+				}).AddRow(
+					videoID, userID, "Test Video", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, &sortableID, &slug, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted",
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE share_slug").
+					WithArgs(slug).
 					WillReturnRows(rows)
 			},
-			want:    2,
-			wantErr: false,
 		},
 		{
-			name:   "returns empty slice when no videos",
-			userID: userID,
+			name: "slug not found",
 			mockFn: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{
-					"id", "user_id", "title", "status", "original_url", "hls_url", "created_at", "updated_at",
-				})
-				mock.ExpectQuery("SELECT .* FROM videos WHERE user_id").
-					WithArgs(userID).
-					WillReturnRows(rows)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE share_slug").
+					WithArgs(slug).
+					WillReturnError(pgx.ErrNoRows)
 			},
-			want:    0,
-			wantErr: false,
+			wantErr: repository.ErrVideoNotFound,
 		},
 	}
 
@@ -309,15 +659,20 @@ func TestVideoRepository_GetByUserID(t *testing.T) {
 			tt.mockFn(mock)
 
 			repo := NewVideoRepository(mock)
-			got, err := repo.GetByUserID(context.Background(), tt.userID)
+			got, err := repo.GetByShareSlug(context.Background(), slug)
 
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetByUserID() error = %v, wantErr %v", err, tt.wantErr)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetByShareSlug() error = %v, wantErr %v", err, tt.wantErr)
+				}
 				return
 			}
 
-			if len(got) != tt.want {
-				t.Errorf("GetByUserID() returned %d videos, want %d", len(got), tt.want)
+			if err != nil {
+				t.Fatalf("GetByShareSlug() unexpected error = %v", err)
+			}
+			if got.ID != videoID || got.SortableID != sortableID || got.ShareSlug != slug {
+				t.Errorf("GetByShareSlug() = %+v, want ID %s, sortable ID %s, slug %s", got, videoID, sortableID, slug)
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -327,42 +682,567 @@ func TestVideoRepository_GetByUserID(t *testing.T) {
 	}
 }
 
-func TestVideoRepository_Update(t *testing.T) {
+func TestVideoRepository_GetByTitleSlug(t *testing.T) {
 	videoID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+	slug := "my-first-video"
 
 	tests := []struct {
 		name    string
-		video   *model.Video
 		mockFn  func(mock pgxmock.PgxPoolIface)
 		wantErr error
 	}{
 		{
-			name: "successful update",
-			video: &model.Video{
-				ID:          videoID,
-				UserID:      uuid.New(),
-				Title:       "Updated Title",
-				Status:      model.StatusProcessing,
-				OriginalURL: "s3://bucket/original.mp4",
-			},
+			name: "successful retrieval",
 			mockFn: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("UPDATE videos").
-					WithArgs(
-						videoID,
-						"Updated Title",
-						"PROCESSING",
-						pgxmock.AnyArg(),
-						pgxmock.AnyArg(),
-						pgxmock.AnyArg(),
-					).
-					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				rows := pgxmock.NewRows([]string{
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+				}).AddRow(
+					videoID, userID, "My First Video", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, &slug, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted",
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE user_id = \\$1 AND title_slug = \\$2").
+					WithArgs(userID, slug).
+					WillReturnRows(rows)
 			},
-			wantErr: nil,
 		},
 		{
-			name: "video not found",
-			video: &model.Video{
-				ID:     videoID,
+			name: "slug not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM videos WHERE user_id = \\$1 AND title_slug = \\$2").
+					WithArgs(userID, slug).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			got, err := repo.GetByTitleSlug(context.Background(), userID, slug)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetByTitleSlug() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetByTitleSlug() unexpected error = %v", err)
+			}
+			if got.ID != videoID || got.TitleSlug != slug {
+				t.Errorf("GetByTitleSlug() = %+v, want ID %s, title slug %s", got, videoID, slug)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoRepository_GetByIdempotencyKey(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+	key := "create-7f3c"
+	hash := "9b1e4c"
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "successful retrieval",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows([]string{
+					"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+					"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility", "idempotency_hash",
+				}).AddRow(
+					videoID, userID, "My First Video", "PENDING_UPLOAD", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted", &hash,
+				)
+				mock.ExpectQuery("SELECT .* FROM videos WHERE user_id = \\$1 AND idempotency_key = \\$2").
+					WithArgs(userID, key).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "key not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM videos WHERE user_id = \\$1 AND idempotency_key = \\$2").
+					WithArgs(userID, key).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			got, err := repo.GetByIdempotencyKey(context.Background(), userID, key)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetByIdempotencyKey() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetByIdempotencyKey() unexpected error = %v", err)
+			}
+			if got.ID != videoID || got.IdempotencyKey != key || got.IdempotencyHash != hash {
+				t.Errorf("GetByIdempotencyKey() = %+v, want ID %s, key %s, hash %s", got, videoID, key, hash)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoRepository_List(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	cursorID := uuid.New()
+	columns := []string{
+		"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+		"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility",
+	}
+
+	tests := []struct {
+		name    string
+		filter  repository.VideoFilter
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		want    int
+		wantErr bool
+	}{
+		{
+			name:   "no filters",
+			filter: repository.VideoFilter{Limit: 50},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Video 1", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted")
+				mock.ExpectQuery(`FROM videos\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$1`).
+					WithArgs(50).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "all filters",
+			filter: repository.VideoFilter{
+				UserID:        userID,
+				CreatedAfter:  after,
+				CreatedBefore: before,
+				TitlePrefix:   "50%_Off",
+				Limit:         10,
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "50%_off sale", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted").
+					AddRow(uuid.New(), userID, "50%_OFF sale", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted")
+				mock.ExpectQuery(`WHERE user_id = \$1 AND created_at > \$2 AND created_at < \$3 AND lower\(title\) LIKE \$4 .* LIMIT \$5`).
+					WithArgs(userID, after, before, `50\%\_off%`, 10).
+					WillReturnRows(rows)
+			},
+			want: 2,
+		},
+		{
+			name:   "exclude deleted",
+			filter: repository.VideoFilter{UserID: userID, ExcludeDeleted: true, Limit: 20},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Kept", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted")
+				mock.ExpectQuery(`WHERE user_id = \$1 AND status <> \$2\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$3`).
+					WithArgs(userID, "DELETED", 20).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name:   "public videos of a user",
+			filter: repository.VideoFilter{UserID: userID, Visibility: model.VisibilityPublic, ExcludeDeleted: true, Limit: 20},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Shared", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "public")
+				mock.ExpectQuery(`WHERE user_id = \$1 AND visibility = \$2 AND status <> \$3\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$4`).
+					WithArgs(userID, "public", "DELETED", 20).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name:   "status updated before",
+			filter: repository.VideoFilter{Status: model.StatusProcessing, UpdatedBefore: before, Limit: 100},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Stuck", "PROCESSING", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, after, after, "", []string{}, "unlisted")
+				mock.ExpectQuery(`WHERE status = \$1 AND updated_at < \$2\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$3`).
+					WithArgs("PROCESSING", before, 100).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "expired in statuses",
+			filter: repository.VideoFilter{
+				Statuses:      []model.Status{model.StatusReady, model.StatusArchived},
+				ExpiresBefore: before,
+				Limit:         10,
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Promo", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &after, nil, nil, after, after, "", []string{}, "unlisted")
+				mock.ExpectQuery(`WHERE status = ANY\(\$1\) AND expires_at < \$2\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$3`).
+					WithArgs([]string{"READY", "ARCHIVED"}, before, 10).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name:   "exclude deleted and expired",
+			filter: repository.VideoFilter{UserID: userID, ExcludeDeleted: true, ExcludeExpired: true, Limit: 20},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Kept", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted")
+				mock.ExpectQuery(`WHERE user_id = \$1 AND status <> \$2 AND status <> \$3\s+ORDER BY`).
+					WithArgs(userID, "DELETED", "EXPIRED", 20).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "after cursor",
+			filter: repository.VideoFilter{
+				UserID: userID,
+				After:  &repository.VideoCursor{CreatedAt: before, ID: cursorID},
+				Limit:  21,
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Older", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted")
+				mock.ExpectQuery(`WHERE user_id = \$1 AND \(created_at, id\) < \(\$2, \$3\)\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$4`).
+					WithArgs(userID, before, cursorID, 21).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "ascending after cursor in statuses",
+			filter: repository.VideoFilter{
+				UserID:    userID,
+				Statuses:  []model.Status{model.StatusReady},
+				After:     &repository.VideoCursor{CreatedAt: after, ID: cursorID},
+				Ascending: true,
+				Limit:     11,
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Newer", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted")
+				mock.ExpectQuery(`WHERE user_id = \$1 AND status = ANY\(\$2\) AND \(created_at, id\) > \(\$3, \$4\)\s+ORDER BY created_at ASC, id ASC\s+LIMIT \$5`).
+					WithArgs(userID, []string{"READY"}, after, cursorID, 11).
+					WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name:   "database error",
+			filter: repository.VideoFilter{TitlePrefix: "demo", Limit: 50},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM videos").
+					WithArgs("demo%", 50).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			got, err := repo.List(context.Background(), tt.filter)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != tt.want {
+				t.Errorf("List() returned %d videos, want %d", len(got), tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoRepository_Search(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	cursorID := uuid.New()
+	columns := []string{
+		"id", "user_id", "title", "status", "original_url", "hls_url", "dash_url", "preview_seconds", "preview_url", "thumbnail_prefix", "output_version", "external_output", "sortable_id", "share_slug", "title_slug", "storage_key", "original_size", "original_etag",
+		"source_duration_ms", "source_width", "source_height", "source_codec", "source_bitrate", "source_frame_rate", "expires_at", "failure_code", "failure_reason", "created_at", "updated_at", "description", "tags", "visibility", "rank",
+	}
+
+	tests := []struct {
+		name      string
+		search    repository.VideoSearch
+		mockFn    func(mock pgxmock.PgxPoolIface)
+		wantRanks []float32
+		wantErr   bool
+	}{
+		{
+			name:   "ranked matches of a user",
+			search: repository.VideoSearch{Query: `"cat video" -dog`, UserID: userID, Limit: 21},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "My cat video", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted", float32(0.09)).
+					AddRow(uuid.New(), userID, "Cat video 2", "PROCESSING", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "unlisted", float32(0.06))
+				mock.ExpectQuery(`websearch_to_tsquery\('simple', \$1\).*WHERE search_vector @@ websearch_to_tsquery\('simple', \$1\) AND status <> \$2 AND status <> \$3 AND user_id = \$4\s+ORDER BY rank DESC, created_at DESC, id DESC\s+LIMIT \$5`).
+					WithArgs(`"cat video" -dog`, "DELETED", "EXPIRED", userID, 21).
+					WillReturnRows(rows)
+			},
+			wantRanks: []float32{0.09, 0.06},
+		},
+		{
+			name:   "public matches of a user",
+			search: repository.VideoSearch{Query: "cat", UserID: userID, Visibility: model.VisibilityPublic, Limit: 21},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns).
+					AddRow(uuid.New(), userID, "Cat", "READY", nil, nil, nil, 0, nil, nil, int64(0), false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, now, now, "", []string{}, "public", float32(0.09))
+				mock.ExpectQuery(`AND user_id = \$4 AND visibility = \$5\s+ORDER BY rank DESC, created_at DESC, id DESC\s+LIMIT \$6`).
+					WithArgs("cat", "DELETED", "EXPIRED", userID, "public", 21).
+					WillReturnRows(rows)
+			},
+			wantRanks: []float32{0.09},
+		},
+		{
+			name: "after cursor",
+			search: repository.VideoSearch{
+				Query:  "cat",
+				UserID: userID,
+				After:  &repository.VideoSearchCursor{Rank: 0.06, CreatedAt: now, ID: cursorID},
+				Limit:  3,
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(columns)
+				mock.ExpectQuery(`AND \(rank, created_at, id\) < \(\$5::real, \$6, \$7\)\s+ORDER BY rank DESC, created_at DESC, id DESC\s+LIMIT \$8`).
+					WithArgs("cat", "DELETED", "EXPIRED", userID, float32(0.06), now, cursorID, 3).
+					WillReturnRows(rows)
+			},
+			wantRanks: []float32{},
+		},
+		{
+			name:   "database error",
+			search: repository.VideoSearch{Query: "cat", Limit: 21},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT .* FROM").
+					WithArgs("cat", "DELETED", "EXPIRED", 21).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			got, err := repo.Search(context.Background(), tt.search)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				ranks := make([]float32, len(got))
+				for i, result := range got {
+					ranks[i] = result.Rank
+				}
+				if !reflect.DeepEqual(ranks, tt.wantRanks) {
+					t.Errorf("Search() ranks = %v, want %v", ranks, tt.wantRanks)
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoRepository_Update(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		video   *model.Video
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "successful update",
+			video: &model.Video{
+				ID:          videoID,
+				UserID:      uuid.New(),
+				Title:       "Updated Title",
+				Status:      model.StatusProcessing,
+				OriginalURL: "s3://bucket/original.mp4",
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE videos").
+					WithArgs(
+						videoID,
+						"Updated Title",
+						"PROCESSING",
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+					).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "metadata and visibility are written",
+			video: &model.Video{
+				ID:          videoID,
+				UserID:      uuid.New(),
+				Title:       "Updated Title",
+				Description: "A walk through the park.",
+				Tags:        []string{"outdoors", "walk"},
+				Status:      model.StatusReady,
+				Visibility:  model.VisibilityPrivate,
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`description = \$18,\s+tags = \$19`).
+					WithArgs(
+						videoID,
+						"Updated Title",
+						"READY",
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						"A walk through the park.",
+						[]string{"outdoors", "walk"},
+						"private",
+					).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "failed video keeps its failure",
+			video: &model.Video{
+				ID:            videoID,
+				UserID:        uuid.New(),
+				Title:         "Updated Title",
+				Status:        model.StatusFailed,
+				FailureCode:   model.FailureCodeEncodeFailed,
+				FailureReason: "ffmpeg execution failed: exit status 1: Conversion failed!",
+			},
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				code, reason := "encode_failed", "ffmpeg execution failed: exit status 1: Conversion failed!"
+				mock.ExpectExec(`failure_code = \$16, failure_reason = \$17`).
+					WithArgs(
+						videoID,
+						"Updated Title",
+						"FAILED",
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						&code,
+						&reason,
+						"",
+						[]string{},
+						pgxmock.AnyArg(),
+					).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "video not found",
+			video: &model.Video{
+				ID:     videoID,
 				UserID: uuid.New(),
 				Title:  "Updated Title",
 				Status: model.StatusProcessing,
@@ -376,6 +1256,20 @@ func TestVideoRepository_Update(t *testing.T) {
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
 					).
 					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
 			},
@@ -479,6 +1373,263 @@ func TestVideoRepository_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestVideoRepository_UpdateSource(t *testing.T) {
+	videoID := uuid.New()
+	source := model.SourceMetadata{
+		Duration:  90500 * time.Millisecond,
+		Width:     1920,
+		Height:    1080,
+		Codec:     "h264",
+		Bitrate:   5000000,
+		FrameRate: 29.97,
+	}
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "successful update",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				durationMs := int64(90500)
+				width, height := int64(1920), int64(1080)
+				codec := "h264"
+				bitrate := int64(5000000)
+				frameRate := 29.97
+				mock.ExpectExec("UPDATE videos SET source_duration_ms").
+					WithArgs(videoID, &durationMs, &width, &height, &codec, &bitrate, &frameRate, pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name: "video not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE videos SET source_duration_ms").
+					WithArgs(videoID, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			err = repo.UpdateSource(context.Background(), videoID, source)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoRepository_PublishOutput(t *testing.T) {
+	videoID := uuid.New()
+	hlsURL := "hls/" + videoID.String() + "/v2/master.m3u8"
+	dashURL := "hls/" + videoID.String() + "/v2/manifest.mpd"
+	thumbnailPrefix := "hls/" + videoID.String() + "/v2/thumbnails/"
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "newer version published",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("WITH updated AS").
+					WithArgs(videoID, int64(2), &hlsURL, &dashURL, (*string)(nil), &thumbnailPrefix, pgxmock.AnyArg(), false).
+					WillReturnRows(pgxmock.NewRows([]string{"published", "exists"}).AddRow(true, true))
+			},
+		},
+		{
+			name: "stale version rejected",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("WITH updated AS").
+					WithArgs(videoID, int64(2), &hlsURL, &dashURL, (*string)(nil), &thumbnailPrefix, pgxmock.AnyArg(), false).
+					WillReturnRows(pgxmock.NewRows([]string{"published", "exists"}).AddRow(false, true))
+			},
+			wantErr: repository.ErrStaleOutputVersion,
+		},
+		{
+			name: "video not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("WITH updated AS").
+					WithArgs(videoID, int64(2), &hlsURL, &dashURL, (*string)(nil), &thumbnailPrefix, pgxmock.AnyArg(), false).
+					WillReturnRows(pgxmock.NewRows([]string{"published", "exists"}).AddRow(false, false))
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("WITH updated AS").
+					WithArgs(videoID, int64(2), &hlsURL, &dashURL, (*string)(nil), &thumbnailPrefix, pgxmock.AnyArg(), false).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: errors.New("failed to publish video output"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			err = repo.PublishOutput(context.Background(), videoID, 2, hlsURL, dashURL, "", thumbnailPrefix, false)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) && !containsError(err, tt.wantErr) {
+					t.Errorf("PublishOutput() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("PublishOutput() unexpected error = %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoRepository_SetOutputSize(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "successful update",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE videos SET output_size").
+					WithArgs(videoID, int64(4096)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name: "video not found",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE videos SET output_size").
+					WithArgs(videoID, int64(4096)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			err = repo.SetOutputSize(context.Background(), videoID, 4096)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SetOutputSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoRepository_GetStorageUsage(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name      string
+		mockFn    func(mock pgxmock.PgxPoolIface)
+		wantUsage *model.StorageUsage
+		wantErr   error
+	}{
+		{
+			name: "sums videos",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\)").
+					WithArgs(userID, string(model.StatusDeleted)).
+					WillReturnRows(pgxmock.NewRows([]string{"count", "original", "output"}).AddRow(int64(3), int64(3000), int64(1500)))
+			},
+			wantUsage: &model.StorageUsage{UserID: userID, Videos: 3, OriginalBytes: 3000, OutputBytes: 1500},
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\)").
+					WithArgs(userID, string(model.StatusDeleted)).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: errors.New("failed to get storage usage"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoRepository(mock)
+			usage, err := repo.GetStorageUsage(context.Background(), userID)
+
+			if tt.wantErr != nil {
+				if !containsError(err, tt.wantErr) {
+					t.Errorf("GetStorageUsage() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetStorageUsage() unexpected error = %v", err)
+			}
+			if *usage != *tt.wantUsage {
+				t.Errorf("GetStorageUsage() = %+v, want %+v", usage, tt.wantUsage)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
 // containsError checks if err's message contains the expected error's message.
 func containsError(err, expected error) bool {
 	if err == nil || expected == nil {
@@ -488,3 +1639,86 @@ func containsError(err, expected error) bool {
 		len(err.Error()) >= len(expected.Error()) &&
 		err.Error()[:len(expected.Error())] == expected.Error()[:len(expected.Error())]
 }
+
+func TestVideoRepository_ReadReplica(t *testing.T) {
+	videoID := uuid.New()
+	getByID := func(ctx context.Context, repo *VideoRepository) error {
+		_, err := repo.GetByID(ctx, videoID)
+		return err
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		call        func(ctx context.Context, repo *VideoRepository) error
+		write       bool
+		args        int
+		wantReplica bool
+	}{
+		{name: "lookup reads the primary by default", ctx: context.Background(), call: getByID, args: 1},
+		{name: "lookup tolerating stale reads reads the replica", ctx: repository.WithStaleReads(context.Background(), true), call: getByID, args: 1, wantReplica: true},
+		{name: "lookup forcing the primary", ctx: repository.WithStaleReads(context.Background(), false), call: getByID, args: 1},
+		{
+			name: "listing tolerating stale reads reads the replica",
+			ctx:  repository.WithStaleReads(context.Background(), true),
+			call: func(ctx context.Context, repo *VideoRepository) error {
+				_, err := repo.List(ctx, repository.VideoFilter{UserID: videoID, Limit: 10})
+				return err
+			},
+			args:        2,
+			wantReplica: true,
+		},
+		{
+			name: "writes always go to the primary",
+			ctx:  repository.WithStaleReads(context.Background(), true),
+			call: func(ctx context.Context, repo *VideoRepository) error {
+				return repo.UpdateStatus(ctx, videoID, model.StatusProcessing)
+			},
+			write: true,
+			args:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer primary.Close()
+			replica, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer replica.Close()
+
+			// The other database has no expectations, so a query sent there fails the call
+			target := primary
+			if tt.wantReplica {
+				target = replica
+			}
+			args := make([]any, tt.args)
+			for i := range args {
+				args[i] = pgxmock.AnyArg()
+			}
+			if tt.write {
+				target.ExpectExec("UPDATE videos").WithArgs(args...).WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			} else {
+				target.ExpectQuery("SELECT .* FROM videos").WithArgs(args...).WillReturnError(pgx.ErrNoRows)
+			}
+
+			repo := NewVideoRepositoryWithReplica(primary, replica)
+			// Queries answer no rows: a lookup reports not found, a listing fails
+			if err := tt.call(tt.ctx, repo); err != nil && !errors.Is(err, repository.ErrVideoNotFound) && !errors.Is(err, pgx.ErrNoRows) {
+				t.Fatalf("call error = %v", err)
+			}
+
+			if err := primary.ExpectationsWereMet(); err != nil {
+				t.Errorf("primary: %v", err)
+			}
+			if err := replica.ExpectationsWereMet(); err != nil {
+				t.Errorf("replica: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// VideoStatsRepository implements repository.VideoStatsRepository using PostgreSQL.
+type VideoStatsRepository struct {
+	db DBTX
+}
+
+// NewVideoStatsRepository creates a new VideoStatsRepository instance.
+func NewVideoStatsRepository(db DBTX) *VideoStatsRepository {
+	return &VideoStatsRepository{db: db}
+}
+
+// AddBatch adds a batch of buffered views in a single statement.
+// Rows for videos that no longer exist are dropped by the join instead of failing the whole batch.
+func (r *VideoStatsRepository) AddBatch(ctx context.Context, deltas []*model.VideoStats) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO video_stats (video_id, views, last_viewed_at, updated_at)
+		SELECT d.video_id, d.views, d.last_viewed_at, NOW()
+		FROM unnest($1::uuid[], $2::bigint[], $3::timestamptz[])
+			AS d(video_id, views, last_viewed_at)
+		JOIN videos v ON v.id = d.video_id
+		ON CONFLICT (video_id) DO UPDATE
+		SET views = video_stats.views + EXCLUDED.views,
+			last_viewed_at = GREATEST(video_stats.last_viewed_at, EXCLUDED.last_viewed_at),
+			updated_at = NOW()
+	`
+
+	videoIDs := make([]uuid.UUID, len(deltas))
+	views := make([]int64, len(deltas))
+	lastViewedAts := make([]time.Time, len(deltas))
+	for i, d := range deltas {
+		videoIDs[i] = d.VideoID
+		views[i] = d.Views
+		lastViewedAts[i] = d.LastViewedAt
+	}
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableVideoStats).Inc()
+
+	if _, err := r.db.Exec(ctx, query, videoIDs, views, lastViewedAts); err != nil {
+		return fmt.Errorf("failed to add video views: %w", classify(err))
+	}
+
+	return nil
+}
+
+// Get retrieves the stored counts of a video, or zero stats if it has none.
+func (r *VideoStatsRepository) Get(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error) {
+	const query = `
+		SELECT views, last_viewed_at
+		FROM video_stats
+		WHERE video_id = $1
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableVideoStats).Inc()
+
+	stats := &model.VideoStats{VideoID: videoID}
+	err := r.db.QueryRow(ctx, query, videoID).Scan(&stats.Views, &stats.LastViewedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &model.VideoStats{VideoID: videoID}, nil
+		}
+		return nil, fmt.Errorf("failed to get video stats: %w", classify(err))
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestVideoStatsRepository_AddBatch(t *testing.T) {
+	now := time.Now()
+	deltas := []*model.VideoStats{
+		{VideoID: uuid.New(), Views: 3, LastViewedAt: now},
+		{VideoID: uuid.New(), Views: 1, LastViewedAt: now.Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		name    string
+		deltas  []*model.VideoStats
+		mockFn  func(mock pgxmock.PgxPoolIface)
+		wantErr bool
+	}{
+		{
+			name:   "successful add",
+			deltas: deltas,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO video_stats").
+					WithArgs(
+						[]uuid.UUID{deltas[0].VideoID, deltas[1].VideoID},
+						[]int64{3, 1},
+						[]time.Time{now, now.Add(-time.Minute)},
+					).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+			},
+		},
+		{
+			name:   "empty batch is a no-op",
+			mockFn: func(mock pgxmock.PgxPoolIface) {},
+		},
+		{
+			name:   "database error",
+			deltas: deltas,
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO video_stats").
+					WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			repo := NewVideoStatsRepository(mock)
+			err = repo.AddBatch(context.Background(), tt.deltas)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoStatsRepository_Get(t *testing.T) {
+	videoID := uuid.New()
+	lastViewedAt := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		mockFn    func(mock pgxmock.PgxPoolIface)
+		wantViews int64
+		wantLast  time.Time
+		wantErr   bool
+	}{
+		{
+			name: "viewed video",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT views, last_viewed_at").
+					WithArgs(videoID).
+					WillReturnRows(pgxmock.NewRows([]string{"views", "last_viewed_at"}).AddRow(int64(42), lastViewedAt))
+			},
+			wantViews: 42,
+			wantLast:  lastViewedAt,
+		},
+		{
+			name: "never viewed",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT views, last_viewed_at").
+					WithArgs(videoID).
+					WillReturnError(pgx.ErrNoRows)
+			},
+		},
+		{
+			name: "database error",
+			mockFn: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT views, last_viewed_at").
+					WithArgs(videoID).
+					WillReturnError(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			tt.mockFn(mock)
+
+			got, err := NewVideoStatsRepository(mock).Get(context.Background(), videoID)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got.VideoID != videoID || got.Views != tt.wantViews || !got.LastViewedAt.Equal(tt.wantLast) {
+					t.Errorf("Get() = %+v", got)
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
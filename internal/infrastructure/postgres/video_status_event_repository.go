@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// VideoStatusEventRepository implements repository.VideoStatusEventRepository using PostgreSQL.
+type VideoStatusEventRepository struct {
+	db DBTX
+}
+
+// NewVideoStatusEventRepository creates a new VideoStatusEventRepository instance.
+func NewVideoStatusEventRepository(db DBTX) *VideoStatusEventRepository {
+	return &VideoStatusEventRepository{db: db}
+}
+
+// Create records a status transition.
+func (r *VideoStatusEventRepository) Create(ctx context.Context, event *model.VideoStatusEvent) error {
+	const query = `
+		INSERT INTO video_status_events (id, video_id, from_status, to_status, actor, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQueryInsert, metrics.TableStatusEvents).Inc()
+
+	_, err := r.db.Exec(ctx, query,
+		event.ID,
+		event.VideoID,
+		nullString(string(event.From)),
+		string(event.To),
+		string(event.Actor),
+		nullString(event.Reason),
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record status event: %w", classify(err))
+	}
+
+	return nil
+}
+
+// ListByVideoID reads the history with idx_video_status_events_video_created_at.
+func (r *VideoStatusEventRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+	const query = `
+		SELECT id, video_id, from_status, to_status, actor, reason, created_at
+		FROM video_status_events
+		WHERE video_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	metrics.DBQueriesTotal.WithLabelValues(metrics.DBQuerySelect, metrics.TableStatusEvents).Inc()
+
+	rows, err := r.db.Query(ctx, query, videoID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status events: %w", classify(err))
+	}
+	defer rows.Close()
+
+	var events []*model.VideoStatusEvent
+	for rows.Next() {
+		var (
+			event        model.VideoStatusEvent
+			from, reason *string
+			to, actor    string
+		)
+		if err := rows.Scan(&event.ID, &event.VideoID, &from, &to, &actor, &reason, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status event: %w", classify(err))
+		}
+
+		event.To = model.Status(to)
+		event.Actor = model.StatusActor(actor)
+		if from != nil {
+			event.From = model.Status(*from)
+		}
+		if reason != nil {
+			event.Reason = *reason
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status events: %w", classify(err))
+	}
+
+	return events, nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestVideoStatusEventRepository_Create(t *testing.T) {
+	videoID := uuid.New()
+	failed := "transcode failed: ffmpeg exited with status 1"
+
+	tests := []struct {
+		name       string
+		event      *model.VideoStatusEvent
+		wantFrom   any
+		wantReason any
+		execErr    error
+		wantErr    bool
+	}{
+		{
+			name:       "transition",
+			event:      model.NewVideoStatusEvent(videoID, model.StatusProcessing, model.StatusFailed, model.StatusActorWorker, failed),
+			wantFrom:   func() *string { s := "PROCESSING"; return &s }(),
+			wantReason: &failed,
+		},
+		{
+			name:       "initial status has no from or reason",
+			event:      model.NewVideoStatusEvent(videoID, "", model.StatusPendingUpload, model.StatusActorAPI, ""),
+			wantFrom:   (*string)(nil),
+			wantReason: (*string)(nil),
+		},
+		{
+			name:       "database error",
+			event:      model.NewVideoStatusEvent(videoID, "", model.StatusPendingUpload, model.StatusActorAPI, ""),
+			wantFrom:   (*string)(nil),
+			wantReason: (*string)(nil),
+			execErr:    errors.New("connection refused"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer mock.Close()
+
+			event := tt.event
+			exec := mock.ExpectExec("INSERT INTO video_status_events").
+				WithArgs(event.ID, videoID, tt.wantFrom, string(event.To), string(event.Actor), tt.wantReason, event.CreatedAt)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			repo := NewVideoStatusEventRepository(mock)
+			err = repo.Create(context.Background(), event)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoStatusEventRepository_ListByVideoID(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Now()
+	from, reason := "PROCESSING", "retries exhausted"
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM video_status_events").
+		WithArgs(videoID, 50).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "video_id", "from_status", "to_status", "actor", "reason", "created_at"}).
+			AddRow(uuid.New(), videoID, &from, "FAILED", "worker", &reason, now).
+			AddRow(uuid.New(), videoID, (*string)(nil), "PENDING_UPLOAD", "api", (*string)(nil), now.Add(-time.Hour)))
+
+	repo := NewVideoStatusEventRepository(mock)
+	events, err := repo.ListByVideoID(context.Background(), videoID, 50)
+	if err != nil {
+		t.Fatalf("ListByVideoID() error = %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if e := events[0]; e.From != model.StatusProcessing || e.To != model.StatusFailed || e.Actor != model.StatusActorWorker || e.Reason != reason {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e := events[1]; e.From != "" || e.To != model.StatusPendingUpload || e.Reason != "" {
+		t.Errorf("unexpected initial event: %+v", e)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
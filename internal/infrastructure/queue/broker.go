@@ -0,0 +1,45 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// Message brokers a Broker can be opened on.
+const (
+	BackendRabbitMQ = "rabbitmq"
+	BackendNATS     = "nats"
+)
+
+// Broker is a connected message queue client of either backend.
+type Broker interface {
+	repository.MessageQueue
+	repository.QueueInspector
+	// Ping reports whether the connection to the broker is open.
+	Ping(ctx context.Context) error
+}
+
+// Open connects to the named backend: RabbitMQ at cfg.URL, or NATS JetStream as
+// configured by jsCfg. Both use cfg for queue names, retries, dead-lettering,
+// encryption and reconnects.
+func Open(ctx context.Context, backend string, cfg ClientConfig, jsCfg JetStreamConfig) (Broker, error) {
+	// Nil clients are returned as a nil Broker, not an interface holding a nil pointer
+	switch backend {
+	case BackendRabbitMQ, "":
+		client, err := NewClient(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case BackendNATS:
+		client, err := NewJetStreamClient(ctx, cfg, jsCfg)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unknown queue backend %q", backend)
+	}
+}
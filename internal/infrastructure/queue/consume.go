@@ -0,0 +1,179 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ConsumeQueue is a queue consumed by ConsumeTranscodeTasks.
+type ConsumeQueue struct {
+	Name string
+	// Weight is the queue's relative share of handler slots while several queues
+	// have tasks waiting; values <= 0 count as 1. An idle queue never blocks others.
+	Weight int
+	// Prefetch is the number of unacked tasks the broker delivers from this queue.
+	// Optional - 0 uses ClientConfig.Prefetch.
+	Prefetch int
+}
+
+// ParseConsumeQueues parses "name[:weight[:prefetch]]" entries as used in configuration.
+func ParseConsumeQueues(specs []string) ([]ConsumeQueue, error) {
+	queues := make([]ConsumeQueue, 0, len(specs))
+	for _, spec := range specs {
+		parts := strings.Split(strings.TrimSpace(spec), ":")
+		if parts[0] == "" || len(parts) > 3 {
+			return nil, fmt.Errorf("queue entry %q must be name[:weight[:prefetch]]", spec)
+		}
+
+		q := ConsumeQueue{Name: parts[0], Weight: 1}
+		if len(parts) > 1 {
+			weight, err := strconv.Atoi(parts[1])
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("queue %q weight must be a positive integer", q.Name)
+			}
+			q.Weight = weight
+		}
+		if len(parts) > 2 {
+			prefetch, err := strconv.Atoi(parts[2])
+			if err != nil || prefetch <= 0 {
+				return nil, fmt.Errorf("queue %q prefetch must be a positive integer", q.Name)
+			}
+			q.Prefetch = prefetch
+		}
+		queues = append(queues, q)
+	}
+	return queues, nil
+}
+
+// queuedDelivery is a delivery tagged with the queue it was consumed from.
+type queuedDelivery struct {
+	queue string
+	msg   amqp.Delivery
+}
+
+// weightedMerge hands deliveries from several queues to handlers using smooth weighted
+// round-robin among the queues that have a task waiting.
+//
+// At most one delivery per queue is held back, and a queue is only chosen when a handler
+// is idle, so a task that arrives on a heavier queue while handlers are busy still goes
+// ahead of tasks already waiting on lighter queues.
+type weightedMerge struct {
+	queues  []ConsumeQueue
+	sources []<-chan amqp.Delivery
+	pending []*amqp.Delivery
+	current []int // smooth weighted round-robin state
+}
+
+func newWeightedMerge(queues []ConsumeQueue, sources []<-chan amqp.Delivery) *weightedMerge {
+	return &weightedMerge{
+		queues:  queues,
+		sources: sources,
+		pending: make([]*amqp.Delivery, len(queues)),
+		current: make([]int, len(queues)),
+	}
+}
+
+// run sends one delivery to out for every signal on idle until ctx is cancelled or a source closes.
+func (m *weightedMerge) run(ctx context.Context, idle <-chan struct{}, out chan<- queuedDelivery) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-idle:
+		}
+
+		if err := m.fill(); err != nil {
+			return err
+		}
+		if !m.hasPending() {
+			if err := m.wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		i := m.pick()
+		d := queuedDelivery{queue: m.queues[i].Name, msg: *m.pending[i]}
+		m.pending[i] = nil
+
+		select {
+		case out <- d:
+		case <-ctx.Done():
+			// Left unacked; the broker redelivers it once the channel closes
+			return ctx.Err()
+		}
+	}
+}
+
+// fill takes a delivery from every queue that has one ready without blocking.
+func (m *weightedMerge) fill() error {
+	for i, src := range m.sources {
+		if m.pending[i] != nil {
+			continue
+		}
+		select {
+		case msg, ok := <-src:
+			if !ok {
+				return errSourceClosed
+			}
+			m.pending[i] = &msg
+		default:
+		}
+	}
+	return nil
+}
+
+// wait blocks until any queue delivers.
+func (m *weightedMerge) wait(ctx context.Context) error {
+	cases := make([]reflect.SelectCase, 0, len(m.sources)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	for _, src := range m.sources {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(src)})
+	}
+
+	chosen, value, ok := reflect.Select(cases)
+	if chosen == 0 {
+		return ctx.Err()
+	}
+	if !ok {
+		return errSourceClosed
+	}
+	msg := value.Interface().(amqp.Delivery)
+	m.pending[chosen-1] = &msg
+	return nil
+}
+
+func (m *weightedMerge) hasPending() bool {
+	for _, d := range m.pending {
+		if d != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// pick chooses among queues with a pending delivery. Each candidate gains its weight,
+// the largest total wins and pays back the sum of the candidates' weights.
+func (m *weightedMerge) pick() int {
+	best, total := -1, 0
+	for i, d := range m.pending {
+		if d == nil {
+			continue
+		}
+		w := max(m.queues[i].Weight, 1)
+		m.current[i] += w
+		total += w
+		if best == -1 || m.current[i] > m.current[best] {
+			best = i
+		}
+	}
+	m.current[best] -= total
+	return best
+}
+
+var errSourceClosed = errors.New("message channel closed unexpectedly")
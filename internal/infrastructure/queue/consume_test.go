@@ -0,0 +1,164 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestParseConsumeQueues(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []ConsumeQueue
+		wantErr bool
+	}{
+		{
+			name:  "name only",
+			specs: []string{"transcode_tasks"},
+			want:  []ConsumeQueue{{Name: "transcode_tasks", Weight: 1}},
+		},
+		{
+			name:  "weight and prefetch",
+			specs: []string{"transcode_tasks_high:4:8", " transcode_tasks_low:1 "},
+			want: []ConsumeQueue{
+				{Name: "transcode_tasks_high", Weight: 4, Prefetch: 8},
+				{Name: "transcode_tasks_low", Weight: 1},
+			},
+		},
+		{name: "empty name", specs: []string{":2"}, wantErr: true},
+		{name: "zero weight", specs: []string{"q:0"}, wantErr: true},
+		{name: "invalid prefetch", specs: []string{"q:1:x"}, wantErr: true},
+		{name: "too many parts", specs: []string{"q:1:1:1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConsumeQueues(tt.specs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConsumeQueues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConsumeQueues() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightedMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []int
+		backlog []int // tasks waiting per queue
+		take    int
+		want    map[string]int
+	}{
+		{
+			name:    "shares by weight while all queues are busy",
+			weights: []int{3, 1},
+			backlog: []int{20, 20},
+			take:    8,
+			want:    map[string]int{"q0": 6, "q1": 2},
+		},
+		{
+			name:    "idle queue does not hold back others",
+			weights: []int{3, 1},
+			backlog: []int{0, 5},
+			take:    5,
+			want:    map[string]int{"q1": 5},
+		},
+		{
+			name:    "lighter queue runs once heavier queue drains",
+			weights: []int{5, 1},
+			backlog: []int{2, 3},
+			take:    5,
+			want:    map[string]int{"q0": 2, "q1": 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queues := make([]ConsumeQueue, len(tt.weights))
+			sources := make([]<-chan amqp.Delivery, len(tt.weights))
+			for i, w := range tt.weights {
+				queues[i] = ConsumeQueue{Name: fmt.Sprintf("q%d", i), Weight: w}
+				src := make(chan amqp.Delivery, tt.backlog[i])
+				for range tt.backlog[i] {
+					src <- amqp.Delivery{}
+				}
+				sources[i] = src
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			idle := make(chan struct{})
+			out := make(chan queuedDelivery)
+			go func() { _ = newWeightedMerge(queues, sources).run(ctx, idle, out) }()
+
+			got := map[string]int{}
+			for range tt.take {
+				idle <- struct{}{}
+				got[(<-out).queue]++
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("deliveries per queue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ConsumeTranscodeTasks_PerQueuePrefetch(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	mockCh := &mockChannel{
+		qosFunc: func(prefetchCount, prefetchSize int, global bool) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, fmt.Sprintf("qos %d global=%v", prefetchCount, global))
+			return nil
+		},
+		consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, "consume "+queue)
+			return make(chan amqp.Delivery), nil
+		},
+	}
+
+	client := &Client{
+		channel: mockCh,
+		config: ClientConfig{
+			QueueName: "transcode_tasks",
+			Prefetch:  2,
+			ConsumeQueues: []ConsumeQueue{
+				{Name: "transcode_tasks_high", Weight: 4, Prefetch: 8},
+				{Name: "transcode_tasks", Weight: 1},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_ = client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error { return nil })
+
+	want := []string{
+		"qos 8 global=false", "consume transcode_tasks_high",
+		"qos 2 global=false", "consume transcode_tasks",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
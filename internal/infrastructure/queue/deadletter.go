@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// ErrDeadLetterDisabled is returned by ConsumeDeadLetters when no dead-letter queue is configured.
+var ErrDeadLetterDisabled = errors.New("dead-letter queue is not configured")
+
+// DeadLetter is a task message that was rejected by a consumer.
+type DeadLetter struct {
+	// Task is the decoded task; nil if the message could not be decoded.
+	Task *repository.TranscodeTask
+	// DecodeErr explains why Task is nil.
+	DecodeErr error
+	// Body is the raw (possibly encrypted) message body.
+	Body []byte
+	// Queue is the queue the message was rejected from, taken from the x-death header.
+	Queue string
+	// Count is the number of times the message has been dead-lettered from Queue.
+	Count int64
+}
+
+// ConsumeDeadLetters reads the dead-letter queue so operators can inspect or requeue
+// rejected tasks. The handler is called for one message at a time; the message is
+// acked when it returns nil. A handler error leaves the message on the queue and
+// stops consumption with that error. Returns when context is cancelled or the channel
+// is closed.
+func (c *Client) ConsumeDeadLetters(ctx context.Context, handler func(DeadLetter) error) error {
+	if c.config.DeadLetterExchange == "" || c.config.DeadLetterQueue == "" {
+		return ErrDeadLetterDisabled
+	}
+
+	ch, _ := c.current()
+	if err := ch.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS for %s: %w", c.config.DeadLetterQueue, err)
+	}
+	msgs, err := ch.Consume(
+		c.config.DeadLetterQueue,
+		"",    // consumer tag (auto-generated)
+		false, // autoAck - a letter is only removed once handled
+		false, // exclusive
+		false, // noLocal
+		false, // noWait
+		nil,   // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer for %s: %w", c.config.DeadLetterQueue, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-msgs:
+			if !ok {
+				return errSourceClosed
+			}
+
+			if err := handler(c.decodeDeadLetter(msg)); err != nil {
+				_ = msg.Nack(false, true)
+				return err
+			}
+			_ = msg.Ack(false)
+		}
+	}
+}
+
+// decodeDeadLetter decodes the task and the most recent x-death entry of msg.
+func (c *Client) decodeDeadLetter(msg amqp.Delivery) DeadLetter {
+	letter := DeadLetter{Body: msg.Body}
+
+	task, err := c.decodeTask(msg)
+	if err != nil {
+		letter.DecodeErr = err
+	} else {
+		letter.Task = &task
+	}
+
+	// The broker prepends an entry per dead-lettering queue, most recent first
+	if deaths, ok := msg.Headers["x-death"].([]interface{}); ok && len(deaths) > 0 {
+		if death, ok := deaths[0].(amqp.Table); ok {
+			letter.Queue, _ = death["queue"].(string)
+			letter.Count, _ = death["count"].(int64)
+		}
+	}
+
+	return letter
+}
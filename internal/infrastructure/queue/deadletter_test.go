@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestClient_ConsumeDeadLetters(t *testing.T) {
+	task := repository.TranscodeTask{VideoID: uuid.New(), OutputKey: "hls/video-123/", RetryCount: 3}
+	taskBody, _ := json.Marshal(task)
+
+	newClient := func(deliveries <-chan amqp.Delivery, consumed *string) *Client {
+		return &Client{
+			channel: &mockChannel{
+				consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+					*consumed = queue
+					return deliveries, nil
+				},
+			},
+			config: DefaultClientConfig("amqp://localhost"),
+		}
+	}
+
+	t.Run("decodes letters and acks handled ones", func(t *testing.T) {
+		deliveries := make(chan amqp.Delivery, 2)
+		var acked int
+		ack := &mockAcknowledger{
+			ackFunc: func(tag uint64, multiple bool) error {
+				acked++
+				return nil
+			},
+		}
+		deliveries <- amqp.Delivery{
+			Body: taskBody,
+			Headers: amqp.Table{"x-death": []interface{}{
+				amqp.Table{"queue": "transcode_tasks_gpu", "reason": "rejected", "count": int64(2)},
+				amqp.Table{"queue": "transcode_tasks", "reason": "rejected", "count": int64(1)},
+			}},
+			Acknowledger: ack,
+		}
+		deliveries <- amqp.Delivery{Body: []byte("invalid json"), Acknowledger: ack}
+		close(deliveries)
+
+		var (
+			consumed string
+			letters  []DeadLetter
+		)
+		err := newClient(deliveries, &consumed).ConsumeDeadLetters(context.Background(), func(letter DeadLetter) error {
+			letters = append(letters, letter)
+			return nil
+		})
+		if !errors.Is(err, errSourceClosed) {
+			t.Fatalf("ConsumeDeadLetters() error = %v, want %v", err, errSourceClosed)
+		}
+
+		if consumed != "transcode_tasks_dead" {
+			t.Errorf("consumed queue = %s, want transcode_tasks_dead", consumed)
+		}
+		if acked != 2 || len(letters) != 2 {
+			t.Fatalf("acked %d of %d letters, want 2 of 2", acked, len(letters))
+		}
+		if letters[0].Task == nil || letters[0].Task.VideoID != task.VideoID {
+			t.Errorf("letter task = %+v, want video %s", letters[0].Task, task.VideoID)
+		}
+		if letters[0].Queue != "transcode_tasks_gpu" || letters[0].Count != 2 {
+			t.Errorf("letter death = %s x%d, want transcode_tasks_gpu x2", letters[0].Queue, letters[0].Count)
+		}
+		if letters[1].Task != nil || letters[1].DecodeErr == nil || string(letters[1].Body) != "invalid json" {
+			t.Errorf("malformed letter = %+v, want decode error and raw body", letters[1])
+		}
+	})
+
+	t.Run("handler error requeues and stops", func(t *testing.T) {
+		deliveries := make(chan amqp.Delivery, 2)
+		var requeued, acked bool
+		ack := &mockAcknowledger{
+			ackFunc: func(tag uint64, multiple bool) error {
+				acked = true
+				return nil
+			},
+			nackFunc: func(tag uint64, multiple bool, requeue bool) error {
+				requeued = requeue
+				return nil
+			},
+		}
+		deliveries <- amqp.Delivery{Body: taskBody, Acknowledger: ack}
+		deliveries <- amqp.Delivery{Body: taskBody, Acknowledger: ack}
+
+		handlerErr := errors.New("publish failed")
+		calls := 0
+		var consumed string
+		err := newClient(deliveries, &consumed).ConsumeDeadLetters(context.Background(), func(letter DeadLetter) error {
+			calls++
+			return handlerErr
+		})
+		if !errors.Is(err, handlerErr) {
+			t.Fatalf("ConsumeDeadLetters() error = %v, want %v", err, handlerErr)
+		}
+		if calls != 1 {
+			t.Errorf("handler calls = %d, want 1", calls)
+		}
+		if !requeued || acked {
+			t.Errorf("requeued = %v, acked = %v; want requeued only", requeued, acked)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		client := &Client{channel: &mockChannel{}, config: ClientConfig{QueueName: "transcode_tasks"}}
+		err := client.ConsumeDeadLetters(context.Background(), func(DeadLetter) error { return nil })
+		if !errors.Is(err, ErrDeadLetterDisabled) {
+			t.Errorf("ConsumeDeadLetters() error = %v, want %v", err, ErrDeadLetterDisabled)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// ErrDeleteQueueDisabled is returned by PublishDeleteTask and ConsumeDeleteTasks when no
+// delete queue is configured.
+var ErrDeleteQueueDisabled = errors.New("delete queue is not configured")
+
+// PublishDeleteTask sends a storage cleanup task to the delete queue.
+// Messages are persistent and encrypted like transcode tasks.
+func (c *Client) PublishDeleteTask(ctx context.Context, task repository.DeleteTask) error {
+	if c.config.DeleteQueueName == "" {
+		return ErrDeleteQueueDisabled
+	}
+	return c.publish(ctx, "", c.config.DeleteQueueName, task)
+}
+
+// ConsumeDeleteTasks consumes storage cleanup tasks one at a time. Messages are settled
+// like transcode tasks: acked on success, dead-lettered when malformed or when the
+// handler fails with an error errs.Retryable rejects, and otherwise republished with
+// an incremented RetryCount. A panicking handler counts as a failure. Returns when
+// context is cancelled, or when the channel is closed and the client does not reconnect.
+//
+// QoS is per channel, so run it on a Client of its own rather than next to
+// ConsumeTranscodeTasks.
+func (c *Client) ConsumeDeleteTasks(ctx context.Context, handler func(task repository.DeleteTask) error) error {
+	queue := c.config.DeleteQueueName
+	if queue == "" {
+		return ErrDeleteQueueDisabled
+	}
+
+	return c.consumeWithReconnect(ctx, func(ch amqpChannel) error {
+		return c.consumeDeleteTasks(ctx, ch, queue, handler)
+	})
+}
+
+// consumeDeleteTasks consumes on ch until ctx is cancelled or the channel closes.
+func (c *Client) consumeDeleteTasks(ctx context.Context, ch amqpChannel, queue string, handler func(task repository.DeleteTask) error) error {
+	if err := ch.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS for %s: %w", queue, err)
+	}
+	msgs, err := ch.Consume(
+		queue,
+		"",    // consumer tag (auto-generated)
+		false, // autoAck - manual ack for reliability
+		false, // exclusive
+		false, // noLocal
+		false, // noWait
+		nil,   // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer for %s: %w", queue, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-msgs:
+			if !ok {
+				return errSourceClosed
+			}
+			c.handleDeleteDelivery(ctx, queue, msg, handler)
+		}
+	}
+}
+
+// handleDeleteDelivery processes one cleanup message and settles it.
+func (c *Client) handleDeleteDelivery(ctx context.Context, queue string, msg amqp.Delivery, handler func(task repository.DeleteTask) error) {
+	var task repository.DeleteTask
+	if err := c.decodeMessage(msg, &task); err != nil {
+		slog.ErrorContext(ctx, "discarding undecodable delete task message",
+			"queue", queue,
+			"error", err,
+		)
+		c.deadLetter(queue, msg, metrics.DeadLetterMalformed)
+		return
+	}
+
+	if err := invokeHandler(ctx, queue, task.VideoID, task, handler); err != nil {
+		if !errs.Retryable(err) {
+			c.deadLetter(queue, msg, metrics.DeadLetterPermanentFailure)
+			return
+		}
+
+		task.RetryCount++
+		if pubErr := c.republish(ctx, queue, task.RetryCount, task); pubErr != nil {
+			slog.ErrorContext(ctx, "failed to republish delete task for retry",
+				"video_id", task.VideoID,
+				"retry_count", task.RetryCount,
+				"error", pubErr,
+			)
+			c.deadLetter(queue, msg, metrics.DeadLetterRepublishFailed)
+		} else {
+			c.ack(queue, msg, metrics.QueueOutcomeRetried)
+		}
+		return
+	}
+
+	c.ack(queue, msg, metrics.QueueOutcomeAcked)
+}
@@ -0,0 +1,173 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestClient_PublishDeleteTask(t *testing.T) {
+	task := repository.DeleteTask{
+		VideoID:        uuid.New(),
+		UserID:         uuid.New(),
+		OriginalKey:    "originals/video-123/movie.mp4",
+		OutputPrefixes: []string{"hls/video-123/", "previews/video-123/"},
+	}
+
+	t.Run("publishes to the delete queue", func(t *testing.T) {
+		var (
+			exchange, key string
+			got           repository.DeleteTask
+		)
+		client := &Client{
+			channel: &mockChannel{
+				publishWithContextFunc: func(ctx context.Context, ex, k string, mandatory, immediate bool, msg amqp.Publishing) error {
+					exchange, key = ex, k
+					if msg.DeliveryMode != amqp.Persistent {
+						t.Errorf("DeliveryMode = %d, want persistent", msg.DeliveryMode)
+					}
+					return json.Unmarshal(msg.Body, &got)
+				},
+			},
+			config: DefaultClientConfig("amqp://localhost"),
+		}
+
+		if err := client.PublishDeleteTask(context.Background(), task); err != nil {
+			t.Fatalf("PublishDeleteTask() error = %v", err)
+		}
+		if exchange != "" || key != "video_deletes" {
+			t.Errorf("published to %q/%q, want default exchange/video_deletes", exchange, key)
+		}
+		if got.VideoID != task.VideoID || len(got.OutputPrefixes) != 2 {
+			t.Errorf("published task = %+v, want %+v", got, task)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		client := &Client{channel: &mockChannel{}, config: ClientConfig{QueueName: "transcode_tasks"}}
+
+		if err := client.PublishDeleteTask(context.Background(), task); !errors.Is(err, ErrDeleteQueueDisabled) {
+			t.Errorf("PublishDeleteTask() error = %v, want %v", err, ErrDeleteQueueDisabled)
+		}
+		if err := client.ConsumeDeleteTasks(context.Background(), nil); !errors.Is(err, ErrDeleteQueueDisabled) {
+			t.Errorf("ConsumeDeleteTasks() error = %v, want %v", err, ErrDeleteQueueDisabled)
+		}
+	})
+}
+
+func TestClient_ConsumeDeleteTasks(t *testing.T) {
+	task := repository.DeleteTask{VideoID: uuid.New(), OutputPrefixes: []string{"hls/video-123/"}}
+	taskBody, _ := json.Marshal(task)
+
+	tests := []struct {
+		name          string
+		body          []byte
+		handlerErr    error
+		wantAck       bool
+		wantNack      bool
+		wantRepublish string
+	}{
+		{
+			name:    "success acks",
+			body:    taskBody,
+			wantAck: true,
+		},
+		{
+			name:     "malformed message is dead-lettered",
+			body:     []byte("invalid json"),
+			wantNack: true,
+		},
+		{
+			name:          "handler failure is retried through the retry queue",
+			body:          taskBody,
+			handlerErr:    errors.New("storage unavailable"),
+			wantAck:       true,
+			wantRepublish: "video_deletes.retry.5s",
+		},
+		{
+			name:       "permanent failure is dead-lettered",
+			body:       taskBody,
+			handlerErr: fmt.Errorf("giving up: %w", repository.ErrPermanentTaskFailure),
+			wantNack:   true,
+		},
+		{
+			name:       "invalid input is dead-lettered",
+			body:       taskBody,
+			handlerErr: fmt.Errorf("delete: %w", errs.New(errs.Invalid, "prefix is empty")),
+			wantNack:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries := make(chan amqp.Delivery, 1)
+			var acked, nacked bool
+			deliveries <- amqp.Delivery{
+				Body: tt.body,
+				Acknowledger: &mockAcknowledger{
+					ackFunc: func(tag uint64, multiple bool) error {
+						acked = true
+						return nil
+					},
+					nackFunc: func(tag uint64, multiple bool, requeue bool) error {
+						nacked = !requeue
+						return nil
+					},
+				},
+			}
+			close(deliveries)
+
+			var (
+				consumed    string
+				republished string
+				retried     repository.DeleteTask
+			)
+			client := &Client{
+				channel: &mockChannel{
+					consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+						consumed = queue
+						return deliveries, nil
+					},
+					publishWithContextFunc: func(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+						republished = key
+						return json.Unmarshal(msg.Body, &retried)
+					},
+				},
+				config: DefaultClientConfig("amqp://localhost"),
+			}
+
+			var handled repository.DeleteTask
+			err := client.ConsumeDeleteTasks(context.Background(), func(task repository.DeleteTask) error {
+				handled = task
+				return tt.handlerErr
+			})
+			if !errors.Is(err, errSourceClosed) {
+				t.Fatalf("ConsumeDeleteTasks() error = %v, want %v", err, errSourceClosed)
+			}
+
+			if consumed != "video_deletes" {
+				t.Errorf("consumed queue = %s, want video_deletes", consumed)
+			}
+			if acked != tt.wantAck || nacked != tt.wantNack {
+				t.Errorf("acked = %v, nacked = %v, want %v and %v", acked, nacked, tt.wantAck, tt.wantNack)
+			}
+			if republished != tt.wantRepublish {
+				t.Errorf("republished to %q, want %q", republished, tt.wantRepublish)
+			}
+			if tt.wantRepublish != "" && retried.RetryCount != 1 {
+				t.Errorf("retried RetryCount = %d, want 1", retried.RetryCount)
+			}
+			if !tt.wantNack && handled.VideoID != task.VideoID {
+				t.Errorf("handled task = %+v, want video %s", handled, task.VideoID)
+			}
+		})
+	}
+}
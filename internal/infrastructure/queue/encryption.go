@@ -0,0 +1,202 @@
+package queue
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Message headers describing an encrypted body.
+const (
+	encryptionHeader = "x-encryption"
+	keyIDHeader      = "x-encryption-key-id"
+
+	encryptionAESGCM = "AES-256-GCM"
+
+	encryptedContentType = "application/octet-stream"
+)
+
+var (
+	// ErrUnknownEncryptionKey is returned when a message was sealed with a key that is not configured.
+	ErrUnknownEncryptionKey = errors.New("unknown encryption key")
+	// ErrNoEncryptionKeys is returned when a PayloadCipher is created without keys.
+	ErrNoEncryptionKeys = errors.New("at least one encryption key is required")
+	// ErrPlaintextMessage is returned when a cipher requiring encryption receives a plaintext message.
+	ErrPlaintextMessage = errors.New("received plaintext message but encryption is required")
+)
+
+// EncryptionKey is a 256-bit AES key and the ID recorded on messages it seals.
+// The key may be a shared secret or a KMS data key decrypted at startup.
+type EncryptionKey struct {
+	ID  string
+	Key []byte
+}
+
+// PayloadCipher seals and opens queue message bodies with AES-256-GCM.
+// The first key seals new messages; every key opens, so keys can be rotated by
+// prepending the new key and removing the old one once the queue has drained.
+type PayloadCipher struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+	// requireEncryption rejects plaintext messages instead of accepting them.
+	requireEncryption bool
+}
+
+// NewPayloadCipher creates a cipher from keys; keys[0] is used to seal.
+func NewPayloadCipher(keys ...EncryptionKey) (*PayloadCipher, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoEncryptionKeys
+	}
+
+	c := &PayloadCipher{
+		activeKeyID: keys[0].ID,
+		aeads:       make(map[string]cipher.AEAD, len(keys)),
+	}
+	for _, k := range keys {
+		if k.ID == "" {
+			return nil, errors.New("encryption key ID cannot be empty")
+		}
+		if _, dup := c.aeads[k.ID]; dup {
+			return nil, fmt.Errorf("duplicate encryption key ID %q", k.ID)
+		}
+		if len(k.Key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes, got %d", k.ID, len(k.Key))
+		}
+
+		block, err := aes.NewCipher(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher for key %q: %w", k.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCM for key %q: %w", k.ID, err)
+		}
+		c.aeads[k.ID] = aead
+	}
+
+	return c, nil
+}
+
+// ParsePayloadCipher creates a cipher from "id:base64key[,id:base64key...]" as used in
+// configuration; the first key seals. Returns nil without error for an empty spec.
+func ParsePayloadCipher(spec string) (*PayloadCipher, error) {
+	keys, err := parseEncryptionKeys(spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return NewPayloadCipher(keys...)
+}
+
+func parseEncryptionKeys(spec string) ([]EncryptionKey, error) {
+	var keys []EncryptionKey
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("encryption key entry must be id:base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		keys = append(keys, EncryptionKey{ID: id, Key: key})
+	}
+	return keys, nil
+}
+
+// RequireEncryption makes consumers using the cipher reject plaintext messages, so a
+// task published by anyone without a key is never run. Enable it once every producer
+// encrypts; until then plaintext is accepted so encryption can be rolled out.
+func (c *PayloadCipher) RequireEncryption() {
+	c.requireEncryption = true
+}
+
+// Seal encrypts plaintext with the active key.
+// The returned body is the random nonce followed by the ciphertext. The key ID is
+// authenticated as additional data, so a body cannot be relabelled with another key.
+func (c *PayloadCipher) Seal(plaintext []byte) (keyID string, body []byte, err error) {
+	aead := c.aeads[c.activeKeyID]
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return c.activeKeyID, aead.Seal(nonce, nonce, plaintext, []byte(c.activeKeyID)), nil
+}
+
+// Open decrypts a body produced by Seal.
+func (c *PayloadCipher) Open(keyID string, body []byte) ([]byte, error) {
+	aead, ok := c.aeads[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncryptionKey, keyID)
+	}
+	if len(body) < aead.NonceSize() {
+		return nil, errors.New("encrypted body is too short")
+	}
+
+	nonce, ciphertext := body[:aead.NonceSize()], body[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %w", err)
+	}
+	return plaintext, nil
+}
+
+// sealTask marshals task to JSON, encrypted with cipher if it is set. keyID is the key
+// that sealed the body; empty for plaintext.
+func sealTask(cipher *PayloadCipher, task any) (body []byte, keyID string, err error) {
+	body, err = json.Marshal(task)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal task: %w", err)
+	}
+	if cipher == nil {
+		return body, "", nil
+	}
+
+	keyID, sealed, err := cipher.Seal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encrypt task: %w", err)
+	}
+	return sealed, keyID, nil
+}
+
+// openTask unmarshals a message body into task. scheme is the message's encryption
+// header; the body is decrypted with cipher when it is set, and must be when cipher
+// requires encryption.
+func openTask(cipher *PayloadCipher, scheme, keyID string, body []byte, task any) error {
+	if scheme == "" && cipher != nil && cipher.requireEncryption {
+		return ErrPlaintextMessage
+	}
+	if scheme != "" {
+		if scheme != encryptionAESGCM {
+			return fmt.Errorf("unsupported encryption %q", scheme)
+		}
+		if cipher == nil {
+			return errors.New("received encrypted message but no cipher is configured")
+		}
+
+		plaintext, err := cipher.Open(keyID, body)
+		if err != nil {
+			return err
+		}
+		body = plaintext
+	}
+
+	if err := json.Unmarshal(body, task); err != nil {
+		return fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package queue
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestPayloadCipher_RoundTrip(t *testing.T) {
+	c, err := NewPayloadCipher(EncryptionKey{ID: "k1", Key: testKey(1)})
+	if err != nil {
+		t.Fatalf("NewPayloadCipher() unexpected error: %v", err)
+	}
+
+	plaintext := []byte(`{"video_id":"550e8400-e29b-41d4-a716-446655440000"}`)
+	keyID, body, err := c.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal() unexpected error: %v", err)
+	}
+	if keyID != "k1" {
+		t.Errorf("keyID = %q, want %q", keyID, "k1")
+	}
+	if bytes.Contains(body, []byte("video_id")) {
+		t.Error("sealed body must not contain plaintext")
+	}
+
+	// Nonces are random, so sealing twice yields different bodies
+	_, body2, err := c.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal() unexpected error: %v", err)
+	}
+	if bytes.Equal(body, body2) {
+		t.Error("expected distinct ciphertexts for repeated Seal")
+	}
+
+	got, err := c.Open(keyID, body)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Open() = %q, want %q", got, plaintext)
+	}
+}
+
+func TestPayloadCipher_Open(t *testing.T) {
+	old, err := NewPayloadCipher(EncryptionKey{ID: "old", Key: testKey(1)})
+	if err != nil {
+		t.Fatalf("NewPayloadCipher() unexpected error: %v", err)
+	}
+	_, sealedOld, err := old.Seal([]byte("payload"))
+	if err != nil {
+		t.Fatalf("Seal() unexpected error: %v", err)
+	}
+
+	// Rotated: new key seals, old key still opens
+	rotated, err := NewPayloadCipher(
+		EncryptionKey{ID: "new", Key: testKey(2)},
+		EncryptionKey{ID: "old", Key: testKey(1)},
+	)
+	if err != nil {
+		t.Fatalf("NewPayloadCipher() unexpected error: %v", err)
+	}
+
+	tampered := bytes.Clone(sealedOld)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		cipher  *PayloadCipher
+		keyID   string
+		body    []byte
+		wantErr error
+		fails   bool
+	}{
+		{name: "previous key after rotation", cipher: rotated, keyID: "old", body: sealedOld},
+		{name: "unknown key", cipher: old, keyID: "new", body: sealedOld, wantErr: ErrUnknownEncryptionKey},
+		{name: "relabelled key ID", cipher: rotated, keyID: "new", body: sealedOld, fails: true},
+		{name: "tampered body", cipher: rotated, keyID: "old", body: tampered, fails: true},
+		{name: "truncated body", cipher: rotated, keyID: "old", body: sealedOld[:4], fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cipher.Open(tt.keyID, tt.body)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Open() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.fails:
+				if err == nil {
+					t.Error("Open() expected error")
+				}
+			default:
+				if err != nil {
+					t.Errorf("Open() unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePayloadCipher(t *testing.T) {
+	k1 := base64.StdEncoding.EncodeToString(testKey(1))
+	k2 := base64.StdEncoding.EncodeToString(testKey(2))
+
+	tests := []struct {
+		name       string
+		spec       string
+		wantNil    bool
+		wantActive string
+		wantErr    bool
+	}{
+		{name: "empty disables encryption", spec: "", wantNil: true},
+		{name: "single key", spec: "k1:" + k1, wantActive: "k1"},
+		{name: "first key is active", spec: "k2:" + k2 + ", k1:" + k1, wantActive: "k2"},
+		{name: "missing ID", spec: k1, wantErr: true},
+		{name: "invalid base64", spec: "k1:not base64!", wantErr: true},
+		{name: "short key", spec: "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{name: "duplicate ID", spec: "k1:" + k1 + ",k1:" + k2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParsePayloadCipher(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePayloadCipher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (c == nil) != tt.wantNil {
+				t.Fatalf("ParsePayloadCipher() = %v, wantNil %v", c, tt.wantNil)
+			}
+			if c != nil && c.activeKeyID != tt.wantActive {
+				t.Errorf("active key = %q, want %q", c.activeKeyID, tt.wantActive)
+			}
+		})
+	}
+}
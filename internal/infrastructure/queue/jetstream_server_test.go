@@ -0,0 +1,393 @@
+package queue
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// fakeJetStream is an in-process NATS server with just enough JetStream for
+// JetStreamClient: stream and consumer creation, publishing with acks, consumer info,
+// no-wait pull requests and message acknowledgements.
+type fakeJetStream struct {
+	t        *testing.T
+	listener net.Listener
+	// tls, when set, makes the server require TLS as nats-server does with a tls block
+	tls *tls.Config
+
+	mu sync.Mutex
+	// noJetStream makes the server answer $JS.API requests with no responders
+	noJetStream bool
+	// streamExists makes STREAM.UPDATE succeed; until then it reports the stream missing
+	streamExists bool
+	stream       jetstream.StreamConfig
+	consumers    map[string]jetstream.ConsumerConfig
+	messages     []*fakeStoredMsg
+	nextSeq      int
+	// acks records every acknowledgement body ("+ACK", "-NAK", "+WPI", "+TERM", ...)
+	acks  []string
+	conns []net.Conn
+}
+
+type fakeStoredMsg struct {
+	seq       int
+	subject   string
+	header    map[string]string
+	data      []byte
+	delivered int
+	inFlight  bool
+}
+
+func newFakeJetStream(t *testing.T) *fakeJetStream {
+	return newFakeJetStreamTLS(t, nil)
+}
+
+// newFakeJetStreamTLS starts a fake server that requires TLS with config when it is not nil.
+func newFakeJetStreamTLS(t *testing.T, config *tls.Config) *fakeJetStream {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeJetStream{t: t, listener: listener, tls: config, consumers: make(map[string]jetstream.ConsumerConfig)}
+	go s.serve()
+	t.Cleanup(s.close)
+	return s
+}
+
+func (s *fakeJetStream) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeJetStream) close() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+// dropConnections closes every client connection, as a server restart would.
+func (s *fakeJetStream) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *fakeJetStream) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+// fakeSub is a client subscription; subject may contain the '*' and '>' wildcards.
+type fakeSub struct {
+	subject string
+	sid     string
+}
+
+// fakeNATSClient is the server side of one connection.
+type fakeNATSClient struct {
+	wmu sync.Mutex
+	w   *bufio.Writer
+
+	mu   sync.Mutex
+	subs []fakeSub
+}
+
+func (c *fakeNATSClient) send(s string) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_, _ = c.w.WriteString(s)
+	_ = c.w.Flush()
+}
+
+// deliver sends a message published on msgSubject to every subscription matching
+// subject; they differ when a pull consumer delivers to a fetch's reply inbox.
+func (c *fakeNATSClient) deliver(subject, msgSubject, reply string, header string, data []byte) {
+	c.mu.Lock()
+	var sids []string
+	for _, sub := range c.subs {
+		if subjectMatches(sub.subject, subject) {
+			sids = append(sids, sub.sid)
+		}
+	}
+	c.mu.Unlock()
+
+	if reply != "" {
+		reply += " "
+	}
+	for _, sid := range sids {
+		if header == "" {
+			c.send(fmt.Sprintf("MSG %s %s %s%d\r\n%s\r\n", msgSubject, sid, reply, len(data), data))
+			continue
+		}
+		c.send(fmt.Sprintf("HMSG %s %s %s%d %d\r\n%s%s\r\n", msgSubject, sid, reply, len(header), len(header)+len(data), header, data))
+	}
+}
+
+// subjectMatches reports whether subject matches pattern, which may use '*' for one
+// token and a trailing '>' for the rest.
+func subjectMatches(pattern, subject string) bool {
+	want, got := strings.Split(pattern, "."), strings.Split(subject, ".")
+	for i, token := range want {
+		if token == ">" {
+			return len(got) > i
+		}
+		if i >= len(got) || (token != "*" && token != got[i]) {
+			return false
+		}
+	}
+	return len(want) == len(got)
+}
+
+func (s *fakeJetStream) handle(conn net.Conn) {
+	info := map[string]any{"server_id": "fake", "version": "2.10.0", "proto": 1, "headers": true, "jetstream": true, "max_payload": 1 << 20}
+	if s.tls != nil {
+		info["tls_required"] = true
+	}
+	infoJSON, _ := json.Marshal(info)
+	if _, err := fmt.Fprintf(conn, "INFO %s\r\n", infoJSON); err != nil {
+		return
+	}
+	if s.tls != nil {
+		tlsConn := tls.Server(conn, s.tls)
+		if err := tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			return
+		}
+		conn = tlsConn
+	}
+
+	client := &fakeNATSClient{w: bufio.NewWriter(conn)}
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		op, args, _ := strings.Cut(line, " ")
+		fields := strings.Fields(args)
+		switch op {
+		case "CONNECT", "PONG":
+		case "PING":
+			client.send("PONG\r\n")
+		case "SUB":
+			client.mu.Lock()
+			client.subs = append(client.subs, fakeSub{subject: fields[0], sid: fields[len(fields)-1]})
+			client.mu.Unlock()
+		case "UNSUB":
+			if len(fields) > 1 {
+				continue // auto-unsubscribe after a number of messages; kept until closed
+			}
+			client.mu.Lock()
+			for i, sub := range client.subs {
+				if sub.sid == fields[0] {
+					client.subs = append(client.subs[:i], client.subs[i+1:]...)
+					break
+				}
+			}
+			client.mu.Unlock()
+		case "PUB", "HPUB":
+			headerLen := 0
+			total, _ := strconv.Atoi(fields[len(fields)-1])
+			if op == "HPUB" {
+				headerLen, _ = strconv.Atoi(fields[len(fields)-2])
+			}
+			buf := make([]byte, total+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			var header map[string]string
+			if op == "HPUB" {
+				header = parseFakeHeader(buf[:headerLen])
+			}
+			reply := ""
+			if (op == "PUB" && len(fields) == 3) || (op == "HPUB" && len(fields) == 4) {
+				reply = fields[1]
+			}
+			s.publish(client, fields[0], reply, header, buf[headerLen:total])
+		default:
+			s.t.Errorf("fake server: unexpected line %q", line)
+			return
+		}
+	}
+}
+
+// parseFakeHeader parses "NATS/1.0\r\nKey: Value\r\n...\r\n\r\n".
+func parseFakeHeader(data []byte) map[string]string {
+	lines := strings.Split(strings.TrimSuffix(string(data), "\r\n\r\n"), "\r\n")
+	header := make(map[string]string, len(lines)-1)
+	for _, line := range lines[1:] {
+		key, value, _ := strings.Cut(line, ":")
+		header[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return header
+}
+
+func encodeFakeHeader(header map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("NATS/1.0\r\n")
+	for key, value := range header {
+		sb.WriteString(key + ": " + value + "\r\n")
+	}
+	sb.WriteString("\r\n")
+	return sb.String()
+}
+
+func (s *fakeJetStream) publish(client *fakeNATSClient, subject, reply string, header map[string]string, data []byte) {
+	switch {
+	case strings.HasPrefix(subject, "$JS.API."):
+		s.mu.Lock()
+		noJetStream := s.noJetStream
+		s.mu.Unlock()
+		if noJetStream {
+			client.deliver(reply, reply, "", "NATS/1.0 503\r\n\r\n", nil)
+			return
+		}
+		s.api(client, strings.TrimPrefix(subject, "$JS.API."), reply, data)
+	case strings.HasPrefix(subject, "$JS.ACK."):
+		s.ack(subject, string(data))
+	default:
+		s.mu.Lock()
+		s.nextSeq++
+		s.messages = append(s.messages, &fakeStoredMsg{seq: s.nextSeq, subject: subject, header: header, data: data})
+		seq := s.nextSeq
+		s.mu.Unlock()
+		client.deliver(reply, reply, "", "", fmt.Appendf(nil, `{"stream":"GOSTREAM","seq":%d}`, seq))
+	}
+}
+
+func (s *fakeJetStream) api(client *fakeNATSClient, endpoint, reply string, data []byte) {
+	respond := func(v any) {
+		body, _ := json.Marshal(v)
+		client.deliver(reply, reply, "", "", body)
+	}
+	apiError := func(code, errCode int, description string) map[string]any {
+		return map[string]any{"error": map[string]any{"code": code, "err_code": errCode, "description": description}}
+	}
+	tokens := strings.Split(endpoint, ".")
+
+	s.mu.Lock()
+	switch {
+	case strings.HasPrefix(endpoint, "STREAM.CREATE."):
+		_ = json.Unmarshal(data, &s.stream)
+		s.streamExists = true
+		stream := s.stream
+		s.mu.Unlock()
+		respond(map[string]any{"config": stream})
+	case strings.HasPrefix(endpoint, "STREAM.UPDATE."):
+		if !s.streamExists {
+			s.mu.Unlock()
+			respond(apiError(404, int(jetstream.JSErrCodeStreamNotFound), "stream not found"))
+			return
+		}
+		_ = json.Unmarshal(data, &s.stream)
+		stream := s.stream
+		s.mu.Unlock()
+		respond(map[string]any{"config": stream})
+	case strings.HasPrefix(endpoint, "CONSUMER.CREATE."):
+		var req struct {
+			Config jetstream.ConsumerConfig `json:"config"`
+		}
+		_ = json.Unmarshal(data, &req)
+		s.consumers[tokens[3]] = req.Config
+		s.mu.Unlock()
+		respond(map[string]any{"stream_name": tokens[2], "name": tokens[3], "config": req.Config})
+	case strings.HasPrefix(endpoint, "CONSUMER.INFO."):
+		consumer := s.consumers[tokens[3]]
+		pending := 0
+		for _, msg := range s.messages {
+			if msg.subject == consumer.FilterSubject && !msg.inFlight {
+				pending++
+			}
+		}
+		s.mu.Unlock()
+		respond(map[string]any{"stream_name": tokens[2], "name": tokens[3], "config": consumer, "num_pending": pending})
+	case strings.HasPrefix(endpoint, "CONSUMER.MSG.NEXT."):
+		durable := tokens[4]
+		filter := s.consumers[durable].FilterSubject
+		for _, msg := range s.messages {
+			if msg.subject != filter || msg.inFlight {
+				continue
+			}
+			msg.inFlight = true
+			msg.delivered++
+			header := ""
+			if len(msg.header) > 0 {
+				header = encodeFakeHeader(msg.header)
+			}
+			ackSubject := fmt.Sprintf("$JS.ACK.GOSTREAM.%s.%d.%d.%d.%d.0", durable, msg.delivered, msg.seq, msg.seq, time.Now().UnixNano())
+			subject, body := msg.subject, msg.data
+			s.mu.Unlock()
+			client.deliver(reply, subject, ackSubject, header, body)
+			return
+		}
+		s.mu.Unlock()
+		client.deliver(reply, reply, "", "NATS/1.0 404 No Messages\r\n\r\n", nil)
+	default:
+		s.mu.Unlock()
+		respond(apiError(400, 10003, "unknown endpoint "+endpoint))
+	}
+}
+
+func (s *fakeJetStream) ack(subject, body string) {
+	seq, _ := strconv.Atoi(strings.Split(subject, ".")[5])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acks = append(s.acks, body)
+	for i, msg := range s.messages {
+		if msg.seq != seq {
+			continue
+		}
+		switch {
+		case body == "+ACK", strings.HasPrefix(body, "+TERM"):
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+		case strings.HasPrefix(body, "-NAK"):
+			msg.inFlight = false // redelivered at once; the requested delay is only recorded
+		}
+		return
+	}
+}
+
+// stored returns the messages held for subject.
+func (s *fakeJetStream) stored(subject string) []fakeStoredMsg {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var msgs []fakeStoredMsg
+	for _, msg := range s.messages {
+		if msg.subject == subject {
+			msgs = append(msgs, *msg)
+		}
+	}
+	return msgs
+}
+
+func (s *fakeJetStream) recordedAcks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.acks...)
+}
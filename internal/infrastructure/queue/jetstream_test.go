@@ -0,0 +1,496 @@
+package queue
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// newTestJetStreamClient connects a JetStreamClient to server with a short poll interval.
+func newTestJetStreamClient(t *testing.T, server *fakeJetStream, cfg ClientConfig) *JetStreamClient {
+	t.Helper()
+	jsCfg := DefaultJetStreamConfig(server.url())
+	jsCfg.PollInterval = 10 * time.Millisecond
+
+	client, err := NewJetStreamClient(context.Background(), cfg, jsCfg)
+	if err != nil {
+		t.Fatalf("NewJetStreamClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// consumeUntil runs ConsumeTranscodeTasks until handler has been called n times.
+func consumeUntil(t *testing.T, client *JetStreamClient, n int, handler func(task repository.TranscodeTask) error) []repository.TranscodeTask {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		tasks []repository.TranscodeTask
+	)
+	err := client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error {
+		mu.Lock()
+		tasks = append(tasks, task)
+		if len(tasks) == n {
+			defer cancel()
+		}
+		mu.Unlock()
+		return handler(task)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ConsumeTranscodeTasks() error = %v, want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(tasks) != n {
+		t.Fatalf("handler called %d times, want %d", len(tasks), n)
+	}
+	return tasks
+}
+
+// waitForAcks waits until the server has recorded n acknowledgements, which are sent
+// asynchronously after the handler returns.
+func waitForAcks(t *testing.T, server *fakeJetStream, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		acks := server.recordedAcks()
+		if len(acks) >= n || time.Now().After(deadline) {
+			return acks
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNewJetStreamClient_DeclaresTopology(t *testing.T) {
+	server := newFakeJetStream(t)
+	cfg := DefaultClientConfig("")
+	cfg.ConsumeQueues = []ConsumeQueue{{Name: "transcode_tasks_gpu", Weight: 3}, {Name: "transcode_tasks", Weight: 1}}
+	newTestJetStreamClient(t, server, cfg)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	wantSubjects := []string{"transcode_tasks", "transcode_tasks_gpu", "video_deletes", "transcode_tasks_dead"}
+	if !reflect.DeepEqual(server.stream.Subjects, wantSubjects) {
+		t.Errorf("stream subjects = %v, want %v", server.stream.Subjects, wantSubjects)
+	}
+	if server.stream.Retention != jetstream.WorkQueuePolicy {
+		t.Errorf("stream retention = %q, want workqueue", server.stream.Retention)
+	}
+	for _, queue := range []string{"transcode_tasks", "transcode_tasks_gpu", "video_deletes"} {
+		consumer, ok := server.consumers[queue]
+		if !ok {
+			t.Errorf("no consumer declared for %s", queue)
+			continue
+		}
+		if consumer.FilterSubject != queue || consumer.AckPolicy != jetstream.AckExplicitPolicy || consumer.AckWait != 30*time.Second || consumer.MaxDeliver != -1 {
+			t.Errorf("unexpected consumer config for %s: %+v", queue, consumer)
+		}
+	}
+	if _, ok := server.consumers["transcode_tasks_dead"]; ok {
+		t.Error("dead-letter queue should not be consumed")
+	}
+}
+
+func TestNewJetStreamClient_UpdatesExistingStream(t *testing.T) {
+	server := newFakeJetStream(t)
+	server.mu.Lock()
+	server.streamExists = true
+	server.mu.Unlock()
+
+	newTestJetStreamClient(t, server, DefaultClientConfig(""))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.stream.Name != "GOSTREAM" {
+		t.Errorf("stream was not updated: %+v", server.stream)
+	}
+}
+
+func TestNewJetStreamClient_NoJetStream(t *testing.T) {
+	server := newFakeJetStream(t)
+	server.mu.Lock()
+	server.noJetStream = true
+	server.mu.Unlock()
+
+	_, err := NewJetStreamClient(context.Background(), DefaultClientConfig(""), DefaultJetStreamConfig(server.url()))
+	if !errors.Is(err, nats.ErrNoResponders) {
+		t.Errorf("NewJetStreamClient() error = %v, want no responders", err)
+	}
+}
+
+func TestJetStreamClient_PublishAndConsume(t *testing.T) {
+	server := newFakeJetStream(t)
+	client := newTestJetStreamClient(t, server, DefaultClientConfig(""))
+	ctx := context.Background()
+
+	task := repository.TranscodeTask{VideoID: uuid.New(), OriginalKey: "originals/a.mp4", OutputKey: "hls/a"}
+	if err := client.PublishTranscodeTask(ctx, task); err != nil {
+		t.Fatalf("PublishTranscodeTask() error = %v", err)
+	}
+
+	depth, err := client.Depth(ctx)
+	if err != nil || depth != 1 {
+		t.Fatalf("Depth() = %d, %v, want 1", depth, err)
+	}
+
+	got := consumeUntil(t, client, 1, func(task repository.TranscodeTask) error { return nil })
+	if !reflect.DeepEqual(got[0], task) {
+		t.Errorf("consumed %+v, want %+v", got[0], task)
+	}
+
+	if acks := waitForAcks(t, server, 1); !reflect.DeepEqual(acks, []string{"+ACK"}) {
+		t.Errorf("acks = %v, want [+ACK]", acks)
+	}
+	if depth, _ := client.Depth(ctx); depth != 0 {
+		t.Errorf("Depth() after ack = %d, want 0", depth)
+	}
+}
+
+func TestJetStreamClient_RetryBackoff(t *testing.T) {
+	server := newFakeJetStream(t)
+	cfg := DefaultClientConfig("")
+	cfg.RetryBaseDelay = 5 * time.Second
+	cfg.RetryMultiplier = 2
+	cfg.RetryMaxDelay = time.Minute
+	client := newTestJetStreamClient(t, server, cfg)
+
+	if err := client.PublishTranscodeTask(context.Background(), repository.TranscodeTask{VideoID: uuid.New(), RetryCount: 1}); err != nil {
+		t.Fatalf("PublishTranscodeTask() error = %v", err)
+	}
+
+	calls := 0
+	got := consumeUntil(t, client, 3, func(task repository.TranscodeTask) error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+
+	var retryCounts []int
+	for _, task := range got {
+		retryCounts = append(retryCounts, task.RetryCount)
+	}
+	// The published count is kept and every redelivery adds one
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(retryCounts, want) {
+		t.Errorf("retry counts = %v, want %v", retryCounts, want)
+	}
+
+	// The backoff for the redelivery with RetryCount n is retryDelay(cfg, n)
+	want := []string{
+		fmt.Sprintf(`-NAK {"delay": %d}`, 10*time.Second),
+		fmt.Sprintf(`-NAK {"delay": %d}`, 20*time.Second),
+		"+ACK",
+	}
+	if acks := waitForAcks(t, server, 3); !reflect.DeepEqual(acks, want) {
+		t.Errorf("acks = %v, want %v", acks, want)
+	}
+}
+
+func TestJetStreamClient_Defer(t *testing.T) {
+	server := newFakeJetStream(t)
+	cfg := DefaultClientConfig("")
+	cfg.RetryBaseDelay = 0
+	client := newTestJetStreamClient(t, server, cfg)
+
+	if err := client.PublishTranscodeTask(context.Background(), repository.TranscodeTask{VideoID: uuid.New()}); err != nil {
+		t.Fatalf("PublishTranscodeTask() error = %v", err)
+	}
+
+	calls := 0
+	got := consumeUntil(t, client, 3, func(task repository.TranscodeTask) error {
+		calls++
+		switch calls {
+		case 1:
+			return errors.New("transient")
+		case 2:
+			return fmt.Errorf("%w: tenant at its limit", repository.ErrTaskDeferred)
+		}
+		return nil
+	})
+
+	var retryCounts []int
+	for _, task := range got {
+		retryCounts = append(retryCounts, task.RetryCount)
+	}
+	// The deferred copy keeps the count of the delivery it replaces
+	if want := []int{0, 1, 1}; !reflect.DeepEqual(retryCounts, want) {
+		t.Errorf("retry counts = %v, want %v", retryCounts, want)
+	}
+	if acks, want := waitForAcks(t, server, 3), []string{"-NAK", "+ACK", "+ACK"}; !reflect.DeepEqual(acks, want) {
+		t.Errorf("acks = %v, want %v", acks, want)
+	}
+}
+
+func TestJetStreamClient_DeadLetter(t *testing.T) {
+	tests := []struct {
+		name       string
+		publish    func(client *JetStreamClient) error
+		handlerErr error
+		wantReason string
+	}{
+		{
+			name: "permanent failure",
+			publish: func(client *JetStreamClient) error {
+				return client.PublishTranscodeTask(context.Background(), repository.TranscodeTask{VideoID: uuid.New()})
+			},
+			handlerErr: fmt.Errorf("%w: corrupt input", repository.ErrPermanentTaskFailure),
+			wantReason: metrics.DeadLetterPermanentFailure,
+		},
+		{
+			name: "invalid input",
+			publish: func(client *JetStreamClient) error {
+				return client.PublishTranscodeTask(context.Background(), repository.TranscodeTask{VideoID: uuid.New()})
+			},
+			handlerErr: fmt.Errorf("probe: %w", errs.New(errs.Invalid, "input has no video stream")),
+			wantReason: metrics.DeadLetterPermanentFailure,
+		},
+		{
+			name: "malformed message",
+			publish: func(client *JetStreamClient) error {
+				return client.store(context.Background(), "transcode_tasks", nil, []byte("not json"))
+			},
+			wantReason: metrics.DeadLetterMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeJetStream(t)
+			client := newTestJetStreamClient(t, server, DefaultClientConfig(""))
+			if err := tt.publish(client); err != nil {
+				t.Fatalf("publish error = %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				done <- client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error { return tt.handlerErr })
+			}()
+
+			acks := waitForAcks(t, server, 1)
+			cancel()
+			<-done
+
+			if !reflect.DeepEqual(acks, []string{"+TERM"}) {
+				t.Errorf("acks = %v, want [+TERM]", acks)
+			}
+			dead := server.stored("transcode_tasks_dead")
+			if len(dead) != 1 {
+				t.Fatalf("dead-lettered %d messages, want 1", len(dead))
+			}
+			if dead[0].header[deadLetterQueueHeader] != "transcode_tasks" || dead[0].header[deadLetterReasonHeader] != tt.wantReason {
+				t.Errorf("unexpected dead-letter headers %v", dead[0].header)
+			}
+			if len(server.stored("transcode_tasks")) != 0 {
+				t.Error("dead-lettered task is still queued")
+			}
+		})
+	}
+}
+
+func TestJetStreamClient_Encryption(t *testing.T) {
+	server := newFakeJetStream(t)
+	cipher, err := NewPayloadCipher(EncryptionKey{ID: "k1", Key: make([]byte, 32)})
+	if err != nil {
+		t.Fatalf("NewPayloadCipher() error = %v", err)
+	}
+	cfg := DefaultClientConfig("")
+	cfg.Cipher = cipher
+	client := newTestJetStreamClient(t, server, cfg)
+
+	task := repository.TranscodeTask{VideoID: uuid.New(), OriginalKey: "originals/secret.mp4"}
+	if err := client.PublishTranscodeTask(context.Background(), task); err != nil {
+		t.Fatalf("PublishTranscodeTask() error = %v", err)
+	}
+
+	stored := server.stored("transcode_tasks")
+	if len(stored) != 1 {
+		t.Fatalf("stored %d messages, want 1", len(stored))
+	}
+	if stored[0].header[encryptionHeader] != encryptionAESGCM || stored[0].header[keyIDHeader] != "k1" {
+		t.Errorf("unexpected headers %v", stored[0].header)
+	}
+	if json.Valid(stored[0].data) {
+		t.Error("stored body is plaintext JSON")
+	}
+
+	got := consumeUntil(t, client, 1, func(task repository.TranscodeTask) error { return nil })
+	if !reflect.DeepEqual(got[0], task) {
+		t.Errorf("consumed %+v, want %+v", got[0], task)
+	}
+}
+
+func TestJetStreamClient_DeleteTasks(t *testing.T) {
+	t.Run("consume", func(t *testing.T) {
+		server := newFakeJetStream(t)
+		client := newTestJetStreamClient(t, server, DefaultClientConfig(""))
+
+		task := repository.DeleteTask{VideoID: uuid.New(), OutputPrefixes: []string{"hls/a/"}}
+		if err := client.PublishDeleteTask(context.Background(), task); err != nil {
+			t.Fatalf("PublishDeleteTask() error = %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var got repository.DeleteTask
+		err := client.ConsumeDeleteTasks(ctx, func(task repository.DeleteTask) error {
+			got = task
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("ConsumeDeleteTasks() error = %v", err)
+		}
+		if got.VideoID != task.VideoID || !slices.Equal(got.OutputPrefixes, task.OutputPrefixes) {
+			t.Errorf("consumed %+v, want %+v", got, task)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server := newFakeJetStream(t)
+		cfg := DefaultClientConfig("")
+		cfg.DeleteQueueName = ""
+		client := newTestJetStreamClient(t, server, cfg)
+
+		if err := client.PublishDeleteTask(context.Background(), repository.DeleteTask{}); !errors.Is(err, ErrDeleteQueueDisabled) {
+			t.Errorf("PublishDeleteTask() error = %v, want ErrDeleteQueueDisabled", err)
+		}
+		err := client.ConsumeDeleteTasks(context.Background(), func(repository.DeleteTask) error { return nil })
+		if !errors.Is(err, ErrDeleteQueueDisabled) {
+			t.Errorf("ConsumeDeleteTasks() error = %v, want ErrDeleteQueueDisabled", err)
+		}
+	})
+}
+
+func TestJetStreamClient_Reconnect(t *testing.T) {
+	t.Run("redials lost connection", func(t *testing.T) {
+		server := newFakeJetStream(t)
+		client := newTestJetStreamClient(t, server, DefaultClientConfig(""))
+
+		server.dropConnections()
+		deadline := time.Now().Add(5 * time.Second)
+		for client.Ping(context.Background()) == nil && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if err := client.PublishTranscodeTask(context.Background(), repository.TranscodeTask{VideoID: uuid.New()}); err != nil {
+			t.Fatalf("PublishTranscodeTask() after reconnect error = %v", err)
+		}
+		if err := client.Ping(context.Background()); err != nil {
+			t.Errorf("Ping() after reconnect error = %v", err)
+		}
+	})
+
+	t.Run("reconnect disabled", func(t *testing.T) {
+		server := newFakeJetStream(t)
+		cfg := DefaultClientConfig("")
+		cfg.ReconnectBaseDelay = 0
+		client := newTestJetStreamClient(t, server, cfg)
+
+		server.dropConnections()
+		done := make(chan error, 1)
+		go func() {
+			done <- client.ConsumeTranscodeTasks(context.Background(), func(repository.TranscodeTask) error { return nil })
+		}()
+
+		select {
+		case err := <-done:
+			if !errors.Is(err, nats.ErrConnectionClosed) {
+				t.Errorf("ConsumeTranscodeTasks() error = %v, want closed connection", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("ConsumeTranscodeTasks() did not return after the connection was lost")
+		}
+	})
+}
+
+func TestNewJetStreamClient_TLS(t *testing.T) {
+	certServer := httptest.NewTLSServer(http.NotFoundHandler())
+	certServer.Close()
+	server := newFakeJetStreamTLS(t, &tls.Config{Certificates: certServer.TLS.Certificates})
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certServer.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+
+	t.Run("trusted CA", func(t *testing.T) {
+		jsCfg := DefaultJetStreamConfig(server.url())
+		jsCfg.TLSCAFile = caFile
+		client, err := NewJetStreamClient(context.Background(), DefaultClientConfig(""), jsCfg)
+		if err != nil {
+			t.Fatalf("NewJetStreamClient() error = %v", err)
+		}
+		defer client.Close()
+
+		if err := client.PublishTranscodeTask(context.Background(), repository.TranscodeTask{VideoID: uuid.New()}); err != nil {
+			t.Errorf("PublishTranscodeTask() error = %v", err)
+		}
+	})
+
+	t.Run("unknown CA", func(t *testing.T) {
+		_, err := NewJetStreamClient(context.Background(), DefaultClientConfig(""), DefaultJetStreamConfig(server.url()))
+		var unknownAuthority x509.UnknownAuthorityError
+		if !errors.As(err, &unknownAuthority) {
+			t.Errorf("NewJetStreamClient() error = %v, want an unknown authority error", err)
+		}
+	})
+}
+
+func TestWeightedPicker(t *testing.T) {
+	queues := []ConsumeQueue{{Name: "gpu", Weight: 3}, {Name: "cpu", Weight: 1}}
+
+	t.Run("all queues busy", func(t *testing.T) {
+		p := newWeightedPicker(queues)
+		var picks []string
+		for range 8 {
+			order := p.order()
+			p.chose(order)
+			picks = append(picks, queues[order[0]].Name)
+		}
+		want := []string{"gpu", "gpu", "cpu", "gpu", "gpu", "gpu", "cpu", "gpu"}
+		if !reflect.DeepEqual(picks, want) {
+			t.Errorf("picks = %v, want %v", picks, want)
+		}
+	})
+
+	t.Run("empty queue does not build up credit", func(t *testing.T) {
+		p := newWeightedPicker(queues)
+		// gpu is empty, so cpu is taken alone every time
+		for range 5 {
+			order := p.order()
+			p.chose(order[slices.Index(order, 1):])
+		}
+		if order := p.order(); order[0] != 0 {
+			t.Errorf("order() = %v, want gpu first once it has tasks again", order)
+		}
+	})
+}
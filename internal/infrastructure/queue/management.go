@@ -0,0 +1,167 @@
+package queue
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// ManagementConfig holds configuration for ManagementClient.
+type ManagementConfig struct {
+	// URL is the base URL of the RabbitMQ management API (e.g., http://localhost:15672).
+	URL      string
+	User     string
+	Password string
+	VHost    string
+	// AgeSample is how many messages are read from the head of a queue to compute their
+	// ages. Sampled messages are requeued at their position but marked redelivered.
+	// Optional - zero reports no message ages and leaves the queues untouched.
+	AgeSample int
+	// Timeout bounds each management API request.
+	Timeout time.Duration
+}
+
+// managementQueue is the subset of GET /api/queues/{vhost}/{name} the client reads.
+type managementQueue struct {
+	Name                   string `json:"name"`
+	MessagesReady          int    `json:"messages_ready"`
+	MessagesUnacknowledged int    `json:"messages_unacknowledged"`
+	Consumers              int    `json:"consumers"`
+}
+
+// managementMessage is one message of POST /api/queues/{vhost}/{name}/get.
+type managementMessage struct {
+	Properties struct {
+		// Timestamp is the AMQP timestamp property in Unix seconds; absent when not set.
+		Timestamp int64 `json:"timestamp"`
+	} `json:"properties"`
+}
+
+// ManagementClient implements repository.QueueMonitor with the RabbitMQ management HTTP API,
+// so operators can see the backlog without access to the broker UI.
+type ManagementClient struct {
+	base   *url.URL
+	cfg    ManagementConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewManagementClient creates a new ManagementClient instance.
+func NewManagementClient(cfg ManagementConfig) (*ManagementClient, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.URL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse management URL: %w", err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("management URL must be absolute: %q", cfg.URL)
+	}
+
+	return &ManagementClient{
+		base:   base,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		now:    time.Now,
+	}, nil
+}
+
+// QueueStats returns the depth and consumers of the queue and, if AgeSample is set, the
+// ages of the messages at its head.
+func (c *ManagementClient) QueueStats(ctx context.Context, name string) (*repository.QueueStats, error) {
+	var q managementQueue
+	if err := c.do(ctx, http.MethodGet, c.queuePath(name), nil, &q); err != nil {
+		return nil, err
+	}
+
+	stats := &repository.QueueStats{
+		Name:      name,
+		Ready:     q.MessagesReady,
+		Unacked:   q.MessagesUnacknowledged,
+		Consumers: q.Consumers,
+	}
+	if c.cfg.AgeSample <= 0 || q.MessagesReady == 0 {
+		return stats, nil
+	}
+
+	// ack_requeue_true puts the messages back; the payload is not needed, only the timestamp
+	body := map[string]any{
+		"count":    c.cfg.AgeSample,
+		"ackmode":  "ack_requeue_true",
+		"encoding": "auto",
+		"truncate": 1,
+	}
+	var msgs []managementMessage
+	if err := c.do(ctx, http.MethodPost, c.queuePath(name)+"/get", body, &msgs); err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	for _, m := range msgs {
+		if m.Properties.Timestamp == 0 {
+			continue
+		}
+		stats.MessageAges = append(stats.MessageAges, max(now.Sub(time.Unix(m.Properties.Timestamp, 0)), 0))
+	}
+	slices.SortFunc(stats.MessageAges, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+
+	return stats, nil
+}
+
+// queuePath returns the API path of a queue; the vhost is escaped, so "/" becomes "%2F".
+func (c *ManagementClient) queuePath(name string) string {
+	return "/api/queues/" + url.PathEscape(c.cfg.VHost) + "/" + url.PathEscape(name)
+}
+
+func (c *ManagementClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader = http.NoBody
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode management request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.base.String()+path, body)
+	if err != nil {
+		return fmt.Errorf("create management request: %w", err)
+	}
+	req.SetBasicAuth(c.cfg.User, c.cfg.Password)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("management request: %w", errs.Wrap(errs.Transient, err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return repository.ErrQueueNotFound
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("management API returned status %d: %w", resp.StatusCode, errs.New(errs.Transient, "management API unavailable"))
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("management API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode management response: %w", err)
+	}
+	return nil
+}
+
+// Compile-time verification that ManagementClient implements repository.QueueMonitor.
+var _ repository.QueueMonitor = (*ManagementClient)(nil)
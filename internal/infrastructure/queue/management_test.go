@@ -0,0 +1,133 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestManagementClient_QueueStats(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name        string
+		ageSample   int
+		status      int
+		queue       string
+		messages    string
+		wantErr     error
+		wantAges    []time.Duration
+		wantSampled bool
+	}{
+		{
+			name:        "depth, consumers and ages",
+			ageSample:   10,
+			status:      http.StatusOK,
+			queue:       `{"name":"transcode_tasks","messages_ready":3,"messages_unacknowledged":2,"consumers":4}`,
+			messages:    `[{"properties":{"timestamp":1699999990}},{"properties":{}},{"properties":{"timestamp":1699999400}}]`,
+			wantAges:    []time.Duration{10 * time.Minute, 10 * time.Second},
+			wantSampled: true,
+		},
+		{
+			name:      "sampling disabled",
+			status:    http.StatusOK,
+			queue:     `{"name":"transcode_tasks","messages_ready":3,"messages_unacknowledged":2,"consumers":4}`,
+			messages:  `[]`,
+			wantAges:  nil,
+			ageSample: 0,
+		},
+		{
+			name:      "unknown queue",
+			ageSample: 10,
+			status:    http.StatusNotFound,
+			wantErr:   repository.ErrQueueNotFound,
+		},
+		{
+			name:      "broker unavailable",
+			ageSample: 10,
+			status:    http.StatusServiceUnavailable,
+			wantErr:   errs.New(errs.Transient, ""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampled := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if user, pass, ok := r.BasicAuth(); !ok || user != "gostream" || pass != "secret" {
+					t.Errorf("basic auth: got %q/%q", user, pass)
+				}
+				if r.URL.EscapedPath() != "/api/queues/%2F/transcode_tasks" && r.URL.EscapedPath() != "/api/queues/%2F/transcode_tasks/get" {
+					t.Errorf("unexpected path %q", r.URL.EscapedPath())
+				}
+				w.WriteHeader(tt.status)
+				if r.Method == http.MethodPost {
+					sampled = true
+					var body map[string]any
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("decode get request: %v", err)
+					}
+					if body["ackmode"] != "ack_requeue_true" || body["count"] != float64(tt.ageSample) {
+						t.Errorf("get request: %v", body)
+					}
+					_, _ = w.Write([]byte(tt.messages))
+					return
+				}
+				_, _ = w.Write([]byte(tt.queue))
+			}))
+			defer srv.Close()
+
+			client, err := NewManagementClient(ManagementConfig{
+				URL:       srv.URL + "/",
+				User:      "gostream",
+				Password:  "secret",
+				VHost:     "/",
+				AgeSample: tt.ageSample,
+				Timeout:   time.Second,
+			})
+			if err != nil {
+				t.Fatalf("NewManagementClient() unexpected error: %v", err)
+			}
+			client.now = func() time.Time { return now }
+
+			got, err := client.QueueStats(context.Background(), "transcode_tasks")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) && errs.CodeOf(err) != errs.CodeOf(tt.wantErr) {
+					t.Fatalf("QueueStats() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("QueueStats() unexpected error: %v", err)
+			}
+
+			if got.Ready != 3 || got.Unacked != 2 || got.Consumers != 4 {
+				t.Errorf("QueueStats() = %+v", got)
+			}
+			if sampled != tt.wantSampled {
+				t.Errorf("sampled = %v, want %v", sampled, tt.wantSampled)
+			}
+			if len(got.MessageAges) != len(tt.wantAges) {
+				t.Fatalf("MessageAges = %v, want %v", got.MessageAges, tt.wantAges)
+			}
+			for i := range tt.wantAges {
+				if got.MessageAges[i] != tt.wantAges[i] {
+					t.Errorf("MessageAges = %v, want %v", got.MessageAges, tt.wantAges)
+				}
+			}
+		})
+	}
+}
+
+func TestNewManagementClient_RelativeURL(t *testing.T) {
+	if _, err := NewManagementClient(ManagementConfig{URL: "localhost:15672"}); err == nil {
+		t.Error("NewManagementClient() expected an error for a URL without scheme")
+	}
+}
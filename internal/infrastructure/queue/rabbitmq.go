@@ -2,14 +2,29 @@ package queue
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+var (
+	// ErrHandlerPanic is the handler failure recorded when a task handler panics.
+	ErrHandlerPanic = errors.New("task handler panicked")
+
+	// ErrClientClosed is returned by consumers waiting for a reconnect when the client is closed.
+	ErrClientClosed = errors.New("queue client closed")
 )
 
 // ClientConfig holds configuration for the RabbitMQ client.
@@ -18,7 +33,43 @@ type ClientConfig struct {
 	QueueName  string // Queue name for transcode tasks
 	Exchange   string // Exchange name (empty = default exchange)
 	RoutingKey string // Routing key (typically same as queue name for default exchange)
-	Prefetch   int    // Consumer prefetch count (QoS)
+	Prefetch   int    // Consumer prefetch count (QoS), per consumed queue unless overridden
+	// ConsumeQueues are the queues ConsumeTranscodeTasks reads from, so node classes and
+	// priorities can use separate queues. Optional - empty consumes QueueName only. Queues
+	// other than QueueName are declared on connect and addressed through the default exchange.
+	ConsumeQueues []ConsumeQueue
+	// Concurrency is the number of tasks handled at once; values <= 1 handle one at a time.
+	// A queue's prefetch should be at least Concurrency or handlers sit idle.
+	Concurrency int
+	// Cipher encrypts message bodies so broker operators cannot read task payloads.
+	// Optional - nil publishes plaintext JSON. Encrypted messages are always decrypted
+	// when received, and plaintext messages are still accepted so producers and
+	// consumers can enable encryption in any order, unless the cipher requires encryption.
+	Cipher *PayloadCipher
+	// DeadLetterExchange is the fanout exchange that rejected task messages are routed to,
+	// declared on connect together with DeadLetterQueue bound to it. Optional - empty
+	// disables dead-lettering and rejected messages are dropped by the broker.
+	DeadLetterExchange string
+	DeadLetterQueue    string
+	// RetryBaseDelay is how long the first retry of a failed task waits; each further
+	// retry waits RetryMultiplier times longer, up to RetryMaxDelay (a RetryMaxDelay
+	// below RetryBaseDelay keeps every retry at RetryBaseDelay). Delays are held in one
+	// TTL queue per distinct delay and task queue, declared on connect, which
+	// dead-letters expired messages back into the task queue. Optional - zero
+	// republishes failed tasks immediately.
+	RetryBaseDelay  time.Duration
+	RetryMultiplier float64
+	RetryMaxDelay   time.Duration
+	// DeleteQueueName is the queue storage cleanup tasks of deleted videos are published
+	// to, declared on connect like the task queues and addressed through the default
+	// exchange. Optional - empty disables PublishDeleteTask and ConsumeDeleteTasks.
+	DeleteQueueName string
+	// ReconnectBaseDelay is how long the client waits before redialing a lost connection;
+	// each failed attempt doubles the wait, up to ReconnectMaxDelay. The topology is
+	// declared again on every new connection and consumers resume on it. Optional - zero
+	// disables reconnection, so a lost connection stays lost and consumers return.
+	ReconnectBaseDelay time.Duration
+	ReconnectMaxDelay  time.Duration
 }
 
 // DefaultClientConfig returns a ClientConfig with sensible defaults.
@@ -30,6 +81,18 @@ func DefaultClientConfig(url string) ClientConfig {
 		Exchange:   "", // Default exchange
 		RoutingKey: "transcode_tasks",
 		Prefetch:   1,
+
+		DeadLetterExchange: "transcode_tasks.dlx",
+		DeadLetterQueue:    "transcode_tasks_dead",
+
+		RetryBaseDelay:  5 * time.Second,
+		RetryMultiplier: 2,
+		RetryMaxDelay:   5 * time.Minute,
+
+		DeleteQueueName: "video_deletes",
+
+		ReconnectBaseDelay: time.Second,
+		ReconnectMaxDelay:  30 * time.Second,
 	}
 }
 
@@ -38,170 +101,705 @@ type amqpConnection interface {
 	Channel() (*amqp.Channel, error)
 	Close() error
 	IsClosed() bool
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
 }
 
 // amqpChannel abstracts amqp.Channel for testability.
 type amqpChannel interface {
 	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
 	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
 	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
 	Qos(prefetchCount, prefetchSize int, global bool) error
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
 	Close() error
 }
 
 // Client implements repository.MessageQueue using RabbitMQ.
 type Client struct {
+	// mu guards the connection and channel, which are replaced on reconnect
+	mu      sync.RWMutex
 	conn    amqpConnection
 	channel amqpChannel
 	config  ClientConfig
+
+	// dial opens a connection with a channel ready for use; nil disables reconnection
+	dial func() (amqpConnection, amqpChannel, error)
+	// generation counts reconnects; reconnected is closed and replaced after each one
+	generation  uint64
+	reconnected chan struct{}
+	onReconnect []func()
+	// done is closed by Close and stops reconnecting
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
-// Compile-time verification that Client implements repository.MessageQueue.
-var _ repository.MessageQueue = (*Client)(nil)
+// Compile-time verification that Client implements repository.MessageQueue and repository.QueueInspector.
+var (
+	_ repository.MessageQueue   = (*Client)(nil)
+	_ repository.QueueInspector = (*Client)(nil)
+)
 
 // NewClient creates a new RabbitMQ client.
 // It establishes connection and declares the queue during initialization to fail fast.
+// With ReconnectBaseDelay set, a connection lost later is redialed in the background.
 func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
-	conn, err := amqp.Dial(cfg.URL)
+	dial := func() (amqpConnection, amqpChannel, error) {
+		conn, err := amqp.Dial(cfg.URL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		}
+		ch, err := openChannel(conn, cfg)
+		if err != nil {
+			_ = conn.Close() // Best-effort cleanup; original error takes precedence
+			return nil, nil, err
+		}
+		return conn, ch, nil
+	}
+
+	conn, ch, err := dial()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return nil, err
 	}
 
-	return newClientWithConnection(ctx, conn, cfg)
+	c := newClient(conn, ch, cfg)
+	if cfg.ReconnectBaseDelay > 0 {
+		c.dial = dial
+		go c.watch(conn)
+	}
+	return c, nil
 }
 
 // newClientWithConnection creates a Client with a given amqpConnection.
 // This is used for dependency injection in tests.
 func newClientWithConnection(ctx context.Context, conn amqpConnection, cfg ClientConfig) (*Client, error) {
-	ch, err := conn.Channel()
+	ch, err := openChannel(conn, cfg)
 	if err != nil {
 		_ = conn.Close() // Best-effort cleanup; original error takes precedence
+		return nil, err
+	}
+	return newClient(conn, ch, cfg), nil
+}
+
+func newClient(conn amqpConnection, ch amqpChannel, cfg ClientConfig) *Client {
+	return &Client{
+		conn:        conn,
+		channel:     ch,
+		config:      cfg,
+		reconnected: make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// openChannel opens a channel on conn, sets its QoS and declares the topology.
+func openChannel(conn amqpConnection, cfg ClientConfig) (amqpChannel, error) {
+	ch, err := conn.Channel()
+	if err != nil {
 		return nil, fmt.Errorf("failed to open channel: %w", err)
 	}
 
 	if err := ch.Qos(cfg.Prefetch, 0, false); err != nil {
-		_ = ch.Close()   // Best-effort cleanup
-		_ = conn.Close() // Best-effort cleanup
+		_ = ch.Close() // Best-effort cleanup
 		return nil, fmt.Errorf("failed to set QoS: %w", err)
 	}
 
-	// Declare queue (idempotent operation)
-	// durable=true ensures queue survives broker restart
-	_, err = ch.QueueDeclare(
-		cfg.QueueName,
+	if err := declareTopology(ch, cfg); err != nil {
+		_ = ch.Close() // Best-effort cleanup
+		return nil, err
+	}
+
+	return ch, nil
+}
+
+// NotifyReconnect registers fn to be called after every successful reconnect, once the
+// topology has been declared again. Callbacks run on the reconnecting goroutine and
+// must not block.
+func (c *Client) NotifyReconnect(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onReconnect = append(c.onReconnect, fn)
+}
+
+// current returns the channel in use and the reconnect generation it belongs to.
+func (c *Client) current() (amqpChannel, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.channel, c.generation
+}
+
+// watch waits for conn to close and reconnects unless the client closed it.
+func (c *Client) watch(conn amqpConnection) {
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	select {
+	case <-c.done:
+		return
+	case amqpErr, ok := <-closed:
+		// A graceful close by Close delivers no error
+		if !ok || amqpErr == nil {
+			return
+		}
+		metrics.QueueConnectionsLostTotal.Inc()
+		slog.Warn("RabbitMQ connection lost, reconnecting", "error", amqpErr)
+	}
+
+	c.reconnect()
+}
+
+// reconnect dials with exponential backoff until it succeeds or the client is closed,
+// then swaps in the new connection and wakes consumers waiting for it.
+func (c *Client) reconnect() {
+	delay := c.config.ReconnectBaseDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-c.done:
+			return
+		case <-time.After(delay):
+		}
+
+		conn, ch, err := c.dial()
+		if err != nil {
+			metrics.QueueReconnectsTotal.WithLabelValues(metrics.QueueReconnectFailure).Inc()
+			slog.Warn("RabbitMQ reconnect failed",
+				"attempt", attempt,
+				"error", err,
+			)
+			delay = min(delay*2, max(c.config.ReconnectMaxDelay, c.config.ReconnectBaseDelay))
+			continue
+		}
+
+		c.mu.Lock()
+		select {
+		case <-c.done:
+			// Closed while dialing; do not leak the new connection
+			c.mu.Unlock()
+			_ = conn.Close() // Best-effort cleanup
+			return
+		default:
+		}
+		c.conn, c.channel = conn, ch
+		c.generation++
+		close(c.reconnected)
+		c.reconnected = make(chan struct{})
+		callbacks := slices.Clone(c.onReconnect)
+		c.mu.Unlock()
+
+		metrics.QueueReconnectsTotal.WithLabelValues(metrics.QueueReconnectSuccess).Inc()
+		slog.Info("RabbitMQ reconnected", "attempt", attempt)
+		go c.watch(conn)
+		for _, fn := range callbacks {
+			fn()
+		}
+		return
+	}
+}
+
+// awaitReconnect blocks until the client has reconnected since generation.
+func (c *Client) awaitReconnect(ctx context.Context, generation uint64) error {
+	for {
+		c.mu.RLock()
+		current, reconnected := c.generation, c.reconnected
+		c.mu.RUnlock()
+		if current > generation {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.done:
+			return ErrClientClosed
+		case <-reconnected:
+		}
+	}
+}
+
+// consumeWithReconnect runs consume on the current channel. When the channel closes
+// under it and the client reconnects, consume runs again on the new channel.
+// Tasks being handled when the connection dropped cannot be acked anymore; the broker
+// redelivers them once it notices the old channel is gone.
+func (c *Client) consumeWithReconnect(ctx context.Context, consume func(ch amqpChannel) error) error {
+	for {
+		ch, generation := c.current()
+		err := consume(ch)
+		if c.dial == nil || !(errors.Is(err, errSourceClosed) || errors.Is(err, amqp.ErrClosed)) {
+			return err
+		}
+
+		slog.WarnContext(ctx, "RabbitMQ channel closed, resuming consumption after reconnect", "error", err)
+		if err := c.awaitReconnect(ctx, generation); err != nil {
+			return err
+		}
+	}
+}
+
+// declareTopology declares the dead-letter exchange and queue, then the task queues and
+// their retry queues (idempotent operations). durable=true ensures everything survives
+// broker restart.
+//
+// Task queues carry x-dead-letter-exchange, so a queue that already exists without it
+// fails with PRECONDITION_FAILED and must be recreated or given a policy instead.
+func declareTopology(ch amqpChannel, cfg ClientConfig) error {
+	if err := declareDeadLetter(ch, cfg); err != nil {
+		return err
+	}
+
+	// Rejected messages are routed to the dead-letter exchange by the broker
+	var args amqp.Table
+	if cfg.DeadLetterExchange != "" {
+		args = amqp.Table{"x-dead-letter-exchange": cfg.DeadLetterExchange}
+	}
+
+	for _, name := range declaredQueues(cfg) {
+		_, err := ch.QueueDeclare(
+			name,
+			true,  // durable
+			false, // autoDelete
+			false, // exclusive
+			false, // noWait
+			args,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", name, err)
+		}
+
+		if err := declareRetryQueues(ch, cfg, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declareRetryQueues declares the delay queues for retries of tasks from queue. Nothing
+// consumes them; messages expire after the queue's TTL and the broker dead-letters them
+// through the default exchange back into queue.
+func declareRetryQueues(ch amqpChannel, cfg ClientConfig, queue string) error {
+	for _, delay := range retryDelays(cfg) {
+		name := retryQueueName(queue, delay)
+		_, err := ch.QueueDeclare(
+			name,
+			true,  // durable
+			false, // autoDelete
+			false, // exclusive
+			false, // noWait
+			amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// retryDelay returns how long the retry with the given RetryCount (1 for the first
+// retry) waits before it is redelivered.
+func retryDelay(cfg ClientConfig, retryCount int) time.Duration {
+	delay := cfg.RetryBaseDelay
+	limit := max(cfg.RetryMaxDelay, cfg.RetryBaseDelay)
+	for range retryCount - 1 {
+		if cfg.RetryMultiplier <= 1 || delay >= limit {
+			break
+		}
+		delay = time.Duration(float64(delay) * cfg.RetryMultiplier)
+	}
+	return min(delay, limit)
+}
+
+// retryDelays returns every distinct delay retryDelay can return, in increasing order,
+// so a retry queue can be declared for each. Returns nil when retries are not delayed.
+func retryDelays(cfg ClientConfig) []time.Duration {
+	if cfg.RetryBaseDelay <= 0 {
+		return nil
+	}
+
+	var delays []time.Duration
+	for n := 1; ; n++ {
+		delay := retryDelay(cfg, n)
+		if len(delays) > 0 && delay == delays[len(delays)-1] {
+			return delays
+		}
+		delays = append(delays, delay)
+	}
+}
+
+// retryQueueName returns the name of the queue holding retries of tasks from queue
+// for delay, e.g. transcode_tasks.retry.40s.
+func retryQueueName(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%s", queue, delay)
+}
+
+// declareDeadLetter declares the dead-letter exchange and the queue bound to it.
+func declareDeadLetter(ch amqpChannel, cfg ClientConfig) error {
+	if cfg.DeadLetterExchange == "" {
+		return nil
+	}
+
+	err := ch.ExchangeDeclare(
+		cfg.DeadLetterExchange,
+		amqp.ExchangeFanout,
 		true,  // durable
 		false, // autoDelete
-		false, // exclusive
+		false, // internal
 		false, // noWait
 		nil,   // arguments
 	)
 	if err != nil {
-		_ = ch.Close()   // Best-effort cleanup
-		_ = conn.Close() // Best-effort cleanup
-		return nil, fmt.Errorf("failed to declare queue: %w", err)
+		return fmt.Errorf("failed to declare dead-letter exchange %s: %w", cfg.DeadLetterExchange, err)
 	}
 
-	return &Client{
-		conn:    conn,
-		channel: ch,
-		config:  cfg,
-	}, nil
+	if cfg.DeadLetterQueue == "" {
+		return nil
+	}
+	if _, err := ch.QueueDeclare(cfg.DeadLetterQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", cfg.DeadLetterQueue, err)
+	}
+	if err := ch.QueueBind(cfg.DeadLetterQueue, "", cfg.DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s: %w", cfg.DeadLetterQueue, err)
+	}
+	return nil
+}
+
+// declaredQueues returns QueueName followed by every other consumed queue and, if set,
+// the delete queue.
+func declaredQueues(cfg ClientConfig) []string {
+	queues := []string{cfg.QueueName}
+	for _, q := range cfg.ConsumeQueues {
+		if !slices.Contains(queues, q.Name) {
+			queues = append(queues, q.Name)
+		}
+	}
+	if cfg.DeleteQueueName != "" && !slices.Contains(queues, cfg.DeleteQueueName) {
+		queues = append(queues, cfg.DeleteQueueName)
+	}
+	return queues
+}
+
+// consumeQueues returns the queues to consume, defaulting to QueueName.
+func consumeQueues(cfg ClientConfig) []ConsumeQueue {
+	if len(cfg.ConsumeQueues) == 0 {
+		return []ConsumeQueue{{Name: cfg.QueueName, Weight: 1}}
+	}
+	return cfg.ConsumeQueues
 }
 
 // PublishTranscodeTask sends a transcoding task to the queue.
 // Messages are persistent to survive broker restarts.
 func (c *Client) PublishTranscodeTask(ctx context.Context, task repository.TranscodeTask) error {
-	body, err := json.Marshal(task)
+	return c.publish(ctx, c.config.Exchange, c.config.RoutingKey, task)
+}
+
+// republish sends a retried task back to the queue it was consumed from, through the
+// retry queue for its delay when retries are delayed. retryCount is the task's
+// incremented RetryCount.
+func (c *Client) republish(ctx context.Context, queue string, retryCount int, task any) error {
+	if c.config.RetryBaseDelay > 0 {
+		return c.publish(ctx, "", retryQueueName(queue, retryDelay(c.config, retryCount)), task)
+	}
+	if queue == c.config.QueueName {
+		return c.publish(ctx, c.config.Exchange, c.config.RoutingKey, task)
+	}
+	return c.publish(ctx, "", queue, task)
+}
+
+func (c *Client) publish(ctx context.Context, exchange, routingKey string, task any) error {
+	msg, err := c.encodeTask(task)
 	if err != nil {
-		return fmt.Errorf("failed to marshal task: %w", err)
+		return err
 	}
 
-	err = c.channel.PublishWithContext(
+	ch, _ := c.current()
+	err = ch.PublishWithContext(
 		ctx,
-		c.config.Exchange,
-		c.config.RoutingKey,
+		exchange,
+		routingKey,
 		false, // mandatory
 		false, // immediate
-		amqp.Publishing{
+		msg,
+	)
+	if err != nil {
+		metrics.QueuePublishedTotal.WithLabelValues(routingKey, metrics.QueuePublishError).Inc()
+		// The broker refused or could not be reached; the same publish may succeed later
+		return fmt.Errorf("failed to publish task: %w", errs.Wrap(errs.Transient, err))
+	}
+
+	metrics.QueuePublishedTotal.WithLabelValues(routingKey, metrics.QueuePublishSuccess).Inc()
+	return nil
+}
+
+// encodeTask builds a persistent message for task, encrypting the body if a cipher is configured.
+// The timestamp lets the management API report how long messages have been queued.
+func (c *Client) encodeTask(task any) (amqp.Publishing, error) {
+	body, keyID, err := sealTask(c.config.Cipher, task)
+	if err != nil {
+		return amqp.Publishing{}, err
+	}
+
+	if keyID == "" {
+		return amqp.Publishing{
 			DeliveryMode: amqp.Persistent,
 			ContentType:  "application/json",
+			Timestamp:    time.Now(),
 			Body:         body,
+		}, nil
+	}
+
+	return amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  encryptedContentType,
+		Timestamp:    time.Now(),
+		Headers: amqp.Table{
+			encryptionHeader: encryptionAESGCM,
+			keyIDHeader:      keyID,
 		},
+		Body: body,
+	}, nil
+}
+
+// decodeTask parses a delivered transcode task.
+func (c *Client) decodeTask(msg amqp.Delivery) (repository.TranscodeTask, error) {
+	var task repository.TranscodeTask
+	err := c.decodeMessage(msg, &task)
+	return task, err
+}
+
+// decodeMessage unmarshals a delivered message into task, decrypting it if it carries
+// the encryption header.
+func (c *Client) decodeMessage(msg amqp.Delivery, task any) error {
+	scheme, _ := msg.Headers[encryptionHeader].(string)
+	keyID, _ := msg.Headers[keyIDHeader].(string)
+	return openTask(c.config.Cipher, scheme, keyID, msg.Body, task)
+}
+
+// Depth returns the number of ready messages in the transcode queue.
+// Unacknowledged (in-flight) messages are not included.
+func (c *Client) Depth(ctx context.Context) (int, error) {
+	// A passive declare only inspects the queue; it fails instead of creating it
+	ch, _ := c.current()
+	q, err := ch.QueueDeclarePassive(
+		c.config.QueueName,
+		true,  // durable
+		false, // autoDelete
+		false, // exclusive
+		false, // noWait
+		nil,   // arguments
 	)
 	if err != nil {
-		return fmt.Errorf("failed to publish task: %w", err)
+		return 0, fmt.Errorf("failed to inspect queue: %w", err)
 	}
 
-	return nil
+	return q.Messages, nil
 }
 
-// ConsumeTranscodeTasks starts consuming transcoding tasks from the queue.
-// The handler function is called for each received task.
-// Returns when context is cancelled or channel is closed.
+// ConsumeTranscodeTasks starts consuming transcoding tasks from the consumed queues.
+// The handler function is called for each received task, by up to Concurrency
+// goroutines at once; when several queues have tasks waiting, free handlers are
+// shared between them by weight. Returns when context is cancelled, or when a channel
+// is closed and the client does not reconnect (see ClientConfig.ReconnectBaseDelay).
 //
 // Ack/Nack strategy:
 //   - Successful processing: Ack
-//   - JSON unmarshal failure: Nack without requeue (malformed message)
-//   - Handler failure: Increment RetryCount, republish as new message, Ack original
+//   - JSON unmarshal or decryption failure: Nack without requeue (malformed message)
+//   - Handler failure errs.Retryable rejects (e.g., repository.ErrPermanentTaskFailure or
+//     an input coded errs.Invalid): Nack without requeue
+//   - Handler failure wrapping repository.ErrTaskDeferred: republish unchanged, after the
+//     first retry delay when RetryBaseDelay is set, and Ack original
+//   - Other handler failure: Increment RetryCount, republish as new message to the same queue
+//     (after a backoff delay when RetryBaseDelay is set), Ack original
+//   - Republish failure: Nack without requeue
+//
+// A panicking handler is recovered and counted as a handler failure, so the delivery
+// is retried like any other failed task and the remaining handlers keep running.
+// Goroutines the handler starts itself are not covered and must recover on their own.
+//
+// A message Nacked without requeue is dead-lettered when DeadLetterExchange is set.
 //
 // Note: We don't use Nack(requeue=true) for retries because it would put the
 // same message back without incrementing RetryCount, causing an infinite loop.
 func (c *Client) ConsumeTranscodeTasks(ctx context.Context, handler func(task repository.TranscodeTask) error) error {
-	msgs, err := c.channel.Consume(
-		c.config.QueueName,
-		"",    // consumer tag (auto-generated)
-		false, // autoAck - manual ack for reliability
-		false, // exclusive
-		false, // noLocal
-		false, // noWait
-		nil,   // arguments
-	)
-	if err != nil {
-		return fmt.Errorf("failed to register consumer: %w", err)
+	return c.consumeWithReconnect(ctx, func(ch amqpChannel) error {
+		return c.consumeTranscodeTasks(ctx, ch, handler)
+	})
+}
+
+// consumeTranscodeTasks consumes on ch until ctx is cancelled or a queue's channel closes.
+func (c *Client) consumeTranscodeTasks(ctx context.Context, ch amqpChannel, handler func(task repository.TranscodeTask) error) error {
+	queues := consumeQueues(c.config)
+	sources := make([]<-chan amqp.Delivery, 0, len(queues))
+	for _, q := range queues {
+		// A non-global QoS applies to the consumers registered after it
+		prefetch := c.config.Prefetch
+		if q.Prefetch > 0 {
+			prefetch = q.Prefetch
+		}
+		if err := ch.Qos(prefetch, 0, false); err != nil {
+			return fmt.Errorf("failed to set QoS for %s: %w", q.Name, err)
+		}
+
+		msgs, err := ch.Consume(
+			q.Name,
+			"",    // consumer tag (auto-generated)
+			false, // autoAck - manual ack for reliability
+			false, // exclusive
+			false, // noLocal
+			false, // noWait
+			nil,   // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register consumer for %s: %w", q.Name, err)
+		}
+		sources = append(sources, msgs)
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case msg, ok := <-msgs:
-			if !ok {
-				return fmt.Errorf("message channel closed unexpectedly")
-			}
+	// Handlers announce they are idle; the merge answers each with the next task by weight
+	idle := make(chan struct{})
+	merged := make(chan queuedDelivery)
 
-			var task repository.TranscodeTask
-			if err := json.Unmarshal(msg.Body, &task); err != nil {
-				// Malformed message - don't requeue
-				_ = msg.Nack(false, false)
-				continue
-			}
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return newWeightedMerge(queues, sources).run(gctx, idle, merged)
+	})
 
-			if err := handler(task); err != nil {
-				// Processing failed - increment retry count and republish
-				task.RetryCount++
-				if pubErr := c.PublishTranscodeTask(ctx, task); pubErr != nil {
-					// Republish failed - discard message to prevent infinite loop
-					// The video will remain in PROCESSING state for manual investigation
-					slog.Error("failed to republish task for retry",
-						"video_id", task.VideoID,
-						"retry_count", task.RetryCount,
-						"error", pubErr,
-					)
-					_ = msg.Nack(false, false)
-				} else {
-					// Republish succeeded - ack original message
-					_ = msg.Ack(false)
+	for range max(c.config.Concurrency, 1) {
+		g.Go(func() error {
+			for {
+				select {
+				case <-gctx.Done():
+					return gctx.Err()
+				case idle <- struct{}{}:
+				}
+
+				select {
+				case <-gctx.Done():
+					return gctx.Err()
+				case d := <-merged:
+					// The parent context is used so a sibling failure does not abort in-flight retries
+					c.handleDelivery(ctx, d.queue, d.msg, handler)
 				}
-				continue
 			}
+		})
+	}
+
+	return g.Wait()
+}
+
+// handleDelivery processes one message and settles it according to the Ack/Nack strategy.
+func (c *Client) handleDelivery(ctx context.Context, queue string, msg amqp.Delivery, handler func(task repository.TranscodeTask) error) {
+	task, err := c.decodeTask(msg)
+	if err != nil {
+		// Malformed or undecryptable message - don't requeue
+		slog.ErrorContext(ctx, "discarding undecodable task message",
+			"queue", queue,
+			"error", err,
+		)
+		c.deadLetter(queue, msg, metrics.DeadLetterMalformed)
+		return
+	}
+
+	if err := invokeHandler(ctx, queue, task.VideoID, task, handler); err != nil {
+		if !errs.Retryable(err) {
+			c.deadLetter(queue, msg, metrics.DeadLetterPermanentFailure)
+			return
+		}
+		if errors.Is(err, repository.ErrTaskDeferred) {
+			c.deferTask(ctx, queue, msg, task)
+			return
+		}
+
+		// Processing failed - increment retry count and republish
+		task.RetryCount++
+		if pubErr := c.republish(ctx, queue, task.RetryCount, task); pubErr != nil {
+			// Republish failed - dead-letter the original to prevent an infinite loop
+			// The video will remain in PROCESSING state until the task is requeued
+			slog.ErrorContext(ctx, "failed to republish task for retry",
+				"video_id", task.VideoID,
+				"retry_count", task.RetryCount,
+				"error", pubErr,
+			)
+			c.deadLetter(queue, msg, metrics.DeadLetterRepublishFailed)
+		} else {
+			// Republish succeeded - ack original message
+			c.ack(queue, msg, metrics.QueueOutcomeRetried)
+		}
+		return
+	}
+
+	c.ack(queue, msg, metrics.QueueOutcomeAcked)
+}
+
+// deferTask puts a task the handler could not start yet back on its queue, behind the
+// shortest retry delay, without counting a retry. The original is only acked once the
+// copy is published; otherwise it is requeued as is.
+func (c *Client) deferTask(ctx context.Context, queue string, msg amqp.Delivery, task repository.TranscodeTask) {
+	if err := c.republish(ctx, queue, 1, task); err != nil {
+		slog.WarnContext(ctx, "failed to republish deferred task, requeueing it",
+			"video_id", task.VideoID,
+			"error", err,
+		)
+		metrics.QueueConsumedTotal.WithLabelValues(queue, metrics.QueueOutcomeDeferred).Inc()
+		_ = msg.Nack(false, true)
+		return
+	}
+	c.ack(queue, msg, metrics.QueueOutcomeDeferred)
+}
 
-			_ = msg.Ack(false)
+// invokeHandler calls handler, converting a panic into an error so the delivery is
+// still settled and the consuming goroutine survives.
+func invokeHandler[T any](ctx context.Context, queue string, videoID uuid.UUID, task T, handler func(task T) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.QueueHandlerPanicsTotal.WithLabelValues(queue).Inc()
+			slog.ErrorContext(ctx, "recovered panic in task handler",
+				"queue", queue,
+				"video_id", videoID,
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+			err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
 		}
+	}()
+
+	return handler(task)
+}
+
+// ack acknowledges msg, counting it under outcome.
+func (c *Client) ack(queue string, msg amqp.Delivery, outcome string) {
+	metrics.QueueConsumedTotal.WithLabelValues(queue, outcome).Inc()
+	_ = msg.Ack(false)
+}
+
+// deadLetter rejects msg without requeue, which routes it to the dead-letter exchange.
+func (c *Client) deadLetter(queue string, msg amqp.Delivery, reason string) {
+	metrics.QueueConsumedTotal.WithLabelValues(queue, metrics.QueueOutcomeDeadLettered).Inc()
+	metrics.QueueDeadLetteredTotal.WithLabelValues(queue, reason).Inc()
+	_ = msg.Nack(false, false)
+}
+
+// Ping reports whether the connection to RabbitMQ is currently open.
+// While the client is reconnecting it reports the lost connection.
+func (c *Client) Ping(ctx context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.conn.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
 	}
+	return nil
 }
 
-// Close gracefully closes the RabbitMQ connection and channel.
+// Close gracefully closes the RabbitMQ connection and channel and stops reconnecting.
 func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		if c.done != nil {
+			close(c.done)
+		}
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	var errs []error
 
 	if c.channel != nil {
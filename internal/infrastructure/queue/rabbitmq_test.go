@@ -1,17 +1,24 @@
 package queue
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	amqp "github.com/rabbitmq/amqp091-go"
 
 	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
 )
 
 // mockConnection implements amqpConnection interface for testing.
@@ -19,6 +26,8 @@ type mockConnection struct {
 	channelFunc  func() (*amqp.Channel, error)
 	closeFunc    func() error
 	isClosedFunc func() bool
+	// notifyCloseFunc defaults to returning the receiver, which then never fires
+	notifyCloseFunc func(receiver chan *amqp.Error) chan *amqp.Error
 }
 
 func (m *mockConnection) Channel() (*amqp.Channel, error) {
@@ -42,12 +51,22 @@ func (m *mockConnection) IsClosed() bool {
 	return false
 }
 
+func (m *mockConnection) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	if m.notifyCloseFunc != nil {
+		return m.notifyCloseFunc(receiver)
+	}
+	return receiver
+}
+
 // mockChannel implements amqpChannel interface for testing.
 type mockChannel struct {
 	queueDeclareFunc       func(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	queueDeclarePassiveFn  func(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
 	publishWithContextFunc func(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
 	consumeFunc            func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
 	qosFunc                func(prefetchCount, prefetchSize int, global bool) error
+	exchangeDeclareFunc    func(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	queueBindFunc          func(name, key, exchange string, noWait bool, args amqp.Table) error
 	closeFunc              func() error
 }
 
@@ -58,6 +77,13 @@ func (m *mockChannel) QueueDeclare(name string, durable, autoDelete, exclusive,
 	return amqp.Queue{Name: name}, nil
 }
 
+func (m *mockChannel) QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	if m.queueDeclarePassiveFn != nil {
+		return m.queueDeclarePassiveFn(name, durable, autoDelete, exclusive, noWait, args)
+	}
+	return amqp.Queue{Name: name}, nil
+}
+
 func (m *mockChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
 	if m.publishWithContextFunc != nil {
 		return m.publishWithContextFunc(ctx, exchange, key, mandatory, immediate, msg)
@@ -79,6 +105,20 @@ func (m *mockChannel) Qos(prefetchCount, prefetchSize int, global bool) error {
 	return nil
 }
 
+func (m *mockChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	if m.exchangeDeclareFunc != nil {
+		return m.exchangeDeclareFunc(name, kind, durable, autoDelete, internal, noWait, args)
+	}
+	return nil
+}
+
+func (m *mockChannel) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	if m.queueBindFunc != nil {
+		return m.queueBindFunc(name, key, exchange, noWait, args)
+	}
+	return nil
+}
+
 func (m *mockChannel) Close() error {
 	if m.closeFunc != nil {
 		return m.closeFunc()
@@ -105,6 +145,15 @@ func TestDefaultClientConfig(t *testing.T) {
 	if cfg.Prefetch != 1 {
 		t.Errorf("Prefetch = %v, want %v", cfg.Prefetch, 1)
 	}
+	if cfg.DeadLetterExchange != "transcode_tasks.dlx" {
+		t.Errorf("DeadLetterExchange = %v, want %v", cfg.DeadLetterExchange, "transcode_tasks.dlx")
+	}
+	if cfg.DeadLetterQueue != "transcode_tasks_dead" {
+		t.Errorf("DeadLetterQueue = %v, want %v", cfg.DeadLetterQueue, "transcode_tasks_dead")
+	}
+	if cfg.RetryBaseDelay != 5*time.Second || cfg.RetryMultiplier != 2 || cfg.RetryMaxDelay != 5*time.Minute {
+		t.Errorf("retry backoff = %v x%v up to %v, want 5s x2 up to 5m", cfg.RetryBaseDelay, cfg.RetryMultiplier, cfg.RetryMaxDelay)
+	}
 }
 
 func TestClient_PublishTranscodeTask(t *testing.T) {
@@ -223,6 +272,198 @@ func TestClient_PublishTranscodeTask_MessageContent(t *testing.T) {
 	}
 }
 
+func TestClient_EncryptedPayload(t *testing.T) {
+	task := repository.TranscodeTask{
+		VideoID:     uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"),
+		OriginalKey: "uploads/video-123/original.mp4",
+		OutputKey:   "hls/video-123/",
+	}
+
+	payloadCipher, err := NewPayloadCipher(EncryptionKey{ID: "k1", Key: bytes.Repeat([]byte{7}, 32)})
+	if err != nil {
+		t.Fatalf("NewPayloadCipher() unexpected error: %v", err)
+	}
+	strictCipher, err := NewPayloadCipher(EncryptionKey{ID: "k1", Key: bytes.Repeat([]byte{7}, 32)})
+	if err != nil {
+		t.Fatalf("NewPayloadCipher() unexpected error: %v", err)
+	}
+	strictCipher.RequireEncryption()
+	plainBody, _ := json.Marshal(task)
+
+	var published amqp.Publishing
+	producer := &Client{
+		channel: &mockChannel{
+			publishWithContextFunc: func(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+				published = msg
+				return nil
+			},
+		},
+		config: ClientConfig{RoutingKey: "transcode_tasks", Cipher: payloadCipher},
+	}
+	if err := producer.PublishTranscodeTask(context.Background(), task); err != nil {
+		t.Fatalf("PublishTranscodeTask() unexpected error = %v", err)
+	}
+
+	if bytes.Contains(published.Body, []byte(task.OriginalKey)) {
+		t.Error("published body must not contain plaintext task fields")
+	}
+	if published.Headers[encryptionHeader] != encryptionAESGCM || published.Headers[keyIDHeader] != "k1" {
+		t.Errorf("unexpected headers: %v", published.Headers)
+	}
+	if published.DeliveryMode != amqp.Persistent {
+		t.Error("expected persistent delivery mode")
+	}
+	if published.Timestamp.IsZero() {
+		t.Error("expected a publish timestamp")
+	}
+
+	tests := []struct {
+		name     string
+		cipher   *PayloadCipher
+		delivery amqp.Delivery
+		wantTask bool
+	}{
+		{
+			name:     "encrypted message",
+			cipher:   payloadCipher,
+			delivery: amqp.Delivery{Headers: published.Headers, Body: published.Body},
+			wantTask: true,
+		},
+		{
+			name:     "plaintext message during rollout",
+			cipher:   payloadCipher,
+			delivery: amqp.Delivery{Body: plainBody},
+			wantTask: true,
+		},
+		{
+			name:     "encrypted message with encryption required",
+			cipher:   strictCipher,
+			delivery: amqp.Delivery{Headers: published.Headers, Body: published.Body},
+			wantTask: true,
+		},
+		{
+			name:     "plaintext message with encryption required",
+			cipher:   strictCipher,
+			delivery: amqp.Delivery{Body: plainBody},
+		},
+		{
+			name:     "encrypted message without cipher",
+			delivery: amqp.Delivery{Headers: published.Headers, Body: published.Body},
+		},
+		{
+			name:     "tampered message",
+			cipher:   payloadCipher,
+			delivery: amqp.Delivery{Headers: published.Headers, Body: append(bytes.Clone(published.Body[:len(published.Body)-1]), 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries := make(chan amqp.Delivery, 1)
+			var acked, nacked bool
+			tt.delivery.Acknowledger = &mockAcknowledger{
+				ackFunc: func(tag uint64, multiple bool) error {
+					acked = true
+					return nil
+				},
+				nackFunc: func(tag uint64, multiple bool, requeue bool) error {
+					nacked = true
+					return nil
+				},
+			}
+			deliveries <- tt.delivery
+
+			consumer := &Client{
+				channel: &mockChannel{
+					consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+						return deliveries, nil
+					},
+				},
+				config: ClientConfig{QueueName: "transcode_tasks", Cipher: tt.cipher},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			var received *repository.TranscodeTask
+			_ = consumer.ConsumeTranscodeTasks(ctx, func(got repository.TranscodeTask) error {
+				received = &got
+				return nil
+			})
+
+			if tt.wantTask {
+				if received == nil || !reflect.DeepEqual(*received, task) {
+					t.Errorf("received task = %+v, want %+v", received, task)
+				}
+				if !acked {
+					t.Error("expected Ack")
+				}
+				return
+			}
+			if received != nil {
+				t.Error("handler must not be called for undecodable messages")
+			}
+			if !nacked {
+				t.Error("expected Nack")
+			}
+		})
+	}
+}
+
+func TestClient_Depth(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockChannel *mockChannel
+		want        int
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "returns ready message count",
+			mockChannel: &mockChannel{
+				queueDeclarePassiveFn: func(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+					if name != "transcode_tasks" {
+						t.Errorf("queue name = %v, want %v", name, "transcode_tasks")
+					}
+					return amqp.Queue{Name: name, Messages: 42}, nil
+				},
+			},
+			want: 42,
+		},
+		{
+			name: "inspect error",
+			mockChannel: &mockChannel{
+				queueDeclarePassiveFn: func(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+					return amqp.Queue{}, errors.New("channel closed")
+				},
+			},
+			wantErr:     true,
+			errContains: "failed to inspect queue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				channel: tt.mockChannel,
+				config:  ClientConfig{QueueName: "transcode_tasks"},
+			}
+
+			got, err := client.Depth(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Depth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("error = %v, should contain %v", err.Error(), tt.errContains)
+			}
+			if got != tt.want {
+				t.Errorf("Depth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestClient_ConsumeTranscodeTasks(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -451,6 +692,173 @@ func TestClient_ConsumeTranscodeTasks_MessageHandling(t *testing.T) {
 		}
 	})
 
+	t.Run("handler error with backoff - republish to the retry queue for its delay", func(t *testing.T) {
+		delayed := task
+		delayed.RetryCount = 2
+		body, _ := json.Marshal(delayed)
+
+		deliveries := make(chan amqp.Delivery, 1)
+		deliveries <- amqp.Delivery{Body: body, Acknowledger: &mockAcknowledger{}}
+
+		var exchange, key string
+		mockCh := &mockChannel{
+			consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+				return deliveries, nil
+			},
+			publishWithContextFunc: func(ctx context.Context, ex, k string, mandatory, immediate bool, msg amqp.Publishing) error {
+				exchange, key = ex, k
+				return nil
+			},
+		}
+
+		client := &Client{
+			channel: mockCh,
+			config: ClientConfig{
+				QueueName:       "transcode_tasks",
+				Exchange:        "tasks",
+				RoutingKey:      "transcode",
+				RetryBaseDelay:  time.Second,
+				RetryMultiplier: 2,
+				RetryMaxDelay:   time.Minute,
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error {
+			return errors.New("processing failed")
+		})
+
+		// The third attempt waits 1s * 2^2 in the default exchange's retry queue
+		if exchange != "" || key != "transcode_tasks.retry.4s" {
+			t.Errorf("republished to %q/%q, want default exchange/transcode_tasks.retry.4s", exchange, key)
+		}
+	})
+
+	t.Run("deferred task - republish unchanged behind the first retry delay and ack", func(t *testing.T) {
+		delayed := task
+		delayed.RetryCount = 2
+		body, _ := json.Marshal(delayed)
+
+		deliveries := make(chan amqp.Delivery, 1)
+		acked := false
+		deliveries <- amqp.Delivery{Body: body, Acknowledger: &mockAcknowledger{
+			ackFunc: func(tag uint64, multiple bool) error {
+				acked = true
+				return nil
+			},
+		}}
+
+		var key string
+		var republished repository.TranscodeTask
+		mockCh := &mockChannel{
+			consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+				return deliveries, nil
+			},
+			publishWithContextFunc: func(ctx context.Context, ex, k string, mandatory, immediate bool, msg amqp.Publishing) error {
+				key = k
+				_ = json.Unmarshal(msg.Body, &republished)
+				return nil
+			},
+		}
+
+		client := &Client{
+			channel: mockCh,
+			config: ClientConfig{
+				QueueName:       "transcode_tasks",
+				RoutingKey:      "transcode_tasks",
+				RetryBaseDelay:  time.Second,
+				RetryMultiplier: 2,
+				RetryMaxDelay:   time.Minute,
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		deferredBefore := testutil.ToFloat64(metrics.QueueConsumedTotal.WithLabelValues("transcode_tasks", metrics.QueueOutcomeDeferred))
+		_ = client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error {
+			return fmt.Errorf("%w: tenant at its limit", repository.ErrTaskDeferred)
+		})
+
+		if key != "transcode_tasks.retry.1s" {
+			t.Errorf("republished to %q, want transcode_tasks.retry.1s", key)
+		}
+		if republished.RetryCount != 2 {
+			t.Errorf("republished RetryCount = %d, want 2", republished.RetryCount)
+		}
+		if !acked {
+			t.Error("expected original to be acked")
+		}
+		if got := testutil.ToFloat64(metrics.QueueConsumedTotal.WithLabelValues("transcode_tasks", metrics.QueueOutcomeDeferred)) - deferredBefore; got != 1 {
+			t.Errorf("deferred messages = %v, want 1", got)
+		}
+	})
+
+	t.Run("handler panic - recovered and republished for retry", func(t *testing.T) {
+		deliveries := make(chan amqp.Delivery, 2)
+		acks := 0
+		var republished []repository.TranscodeTask
+
+		for range 2 {
+			deliveries <- amqp.Delivery{
+				Body: taskBody,
+				Acknowledger: &mockAcknowledger{
+					ackFunc: func(tag uint64, multiple bool) error {
+						acks++
+						return nil
+					},
+				},
+			}
+		}
+
+		mockCh := &mockChannel{
+			consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+				return deliveries, nil
+			},
+			publishWithContextFunc: func(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+				var task repository.TranscodeTask
+				_ = json.Unmarshal(msg.Body, &task)
+				republished = append(republished, task)
+				return nil
+			},
+		}
+
+		client := &Client{
+			channel: mockCh,
+			config:  ClientConfig{QueueName: "transcode_tasks", RoutingKey: "transcode_tasks"},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		before := testutil.ToFloat64(metrics.QueueHandlerPanicsTotal.WithLabelValues("transcode_tasks"))
+		retriedBefore := testutil.ToFloat64(metrics.QueueConsumedTotal.WithLabelValues("transcode_tasks", metrics.QueueOutcomeRetried))
+		publishedBefore := testutil.ToFloat64(metrics.QueuePublishedTotal.WithLabelValues("transcode_tasks", metrics.QueuePublishSuccess))
+
+		// The same consumer goroutine must survive the first panic to handle the second delivery
+		_ = client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error {
+			panic("nil map write")
+		})
+
+		if acks != 2 {
+			t.Errorf("acks = %d, want 2", acks)
+		}
+		if len(republished) != 2 || republished[0].RetryCount != 1 {
+			t.Errorf("republished = %+v, want 2 tasks with RetryCount 1", republished)
+		}
+		if got := testutil.ToFloat64(metrics.QueueHandlerPanicsTotal.WithLabelValues("transcode_tasks")) - before; got != 2 {
+			t.Errorf("recorded panics = %v, want 2", got)
+		}
+		if got := testutil.ToFloat64(metrics.QueueConsumedTotal.WithLabelValues("transcode_tasks", metrics.QueueOutcomeRetried)) - retriedBefore; got != 2 {
+			t.Errorf("retried messages = %v, want 2", got)
+		}
+		if got := testutil.ToFloat64(metrics.QueuePublishedTotal.WithLabelValues("transcode_tasks", metrics.QueuePublishSuccess)) - publishedBefore; got != 2 {
+			t.Errorf("published messages = %v, want 2", got)
+		}
+	})
+
 	t.Run("handler error with republish failure - nack without requeue", func(t *testing.T) {
 		deliveries := make(chan amqp.Delivery, 1)
 		nackCalled := false
@@ -496,6 +904,296 @@ func TestClient_ConsumeTranscodeTasks_MessageHandling(t *testing.T) {
 			t.Error("expected Nack requeue=false when republish fails")
 		}
 	})
+
+	t.Run("permanent handler failure - nack without requeue or republish", func(t *testing.T) {
+		deliveries := make(chan amqp.Delivery, 1)
+		nackCalled := false
+		nackRequeue := true
+		published := false
+
+		delivery := amqp.Delivery{
+			Body: taskBody,
+			Acknowledger: &mockAcknowledger{
+				nackFunc: func(tag uint64, multiple bool, requeue bool) error {
+					nackCalled = true
+					nackRequeue = requeue
+					return nil
+				},
+			},
+		}
+		deliveries <- delivery
+
+		mockCh := &mockChannel{
+			consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+				return deliveries, nil
+			},
+			publishWithContextFunc: func(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+				published = true
+				return nil
+			},
+		}
+
+		client := &Client{
+			channel: mockCh,
+			config:  ClientConfig{QueueName: "transcode_tasks", RoutingKey: "transcode_tasks"},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error {
+			return fmt.Errorf("giving up: %w", repository.ErrPermanentTaskFailure)
+		})
+
+		if !nackCalled {
+			t.Error("expected Nack to be called for a permanent failure")
+		}
+		if nackRequeue {
+			t.Error("expected Nack requeue=false for a permanent failure")
+		}
+		if published {
+			t.Error("expected no republish for a permanent failure")
+		}
+	})
+}
+
+func TestClient_ConsumeTranscodeTasks_MultipleQueues(t *testing.T) {
+	cpuTask := repository.TranscodeTask{VideoID: uuid.New(), OutputKey: "hls/cpu/"}
+	gpuTask := repository.TranscodeTask{VideoID: uuid.New(), OutputKey: "hls/gpu/"}
+
+	sources := map[string]chan amqp.Delivery{
+		"transcode_tasks_gpu": make(chan amqp.Delivery, 1),
+		"transcode_tasks":     make(chan amqp.Delivery, 1),
+	}
+	for queue, task := range map[string]repository.TranscodeTask{"transcode_tasks_gpu": gpuTask, "transcode_tasks": cpuTask} {
+		body, _ := json.Marshal(task)
+		sources[queue] <- amqp.Delivery{Body: body, Acknowledger: &mockAcknowledger{}}
+	}
+
+	var (
+		mu        sync.Mutex
+		consumed  []string
+		republish = map[string]string{} // video ID -> exchange/routing key
+	)
+	mockCh := &mockChannel{
+		consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			consumed = append(consumed, queue)
+			return sources[queue], nil
+		},
+		publishWithContextFunc: func(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+			var task repository.TranscodeTask
+			_ = json.Unmarshal(msg.Body, &task)
+			mu.Lock()
+			defer mu.Unlock()
+			republish[task.VideoID.String()] = exchange + "/" + key
+			return nil
+		},
+	}
+
+	client := &Client{
+		channel: mockCh,
+		config: ClientConfig{
+			QueueName:     "transcode_tasks",
+			Exchange:      "tasks",
+			RoutingKey:    "transcode",
+			ConsumeQueues: []ConsumeQueue{{Name: "transcode_tasks_gpu"}, {Name: "transcode_tasks"}},
+			Concurrency:   2,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Both handlers must be running at once for either to return, proving Concurrency is honoured
+	var started sync.WaitGroup
+	started.Add(2)
+	err := client.ConsumeTranscodeTasks(ctx, func(task repository.TranscodeTask) error {
+		started.Done()
+		started.Wait()
+		if task.VideoID == cpuTask.VideoID {
+			defer cancel()
+		}
+		return errors.New("processing failed")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ConsumeTranscodeTasks() error = %v, want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(consumed) != 2 {
+		t.Errorf("consumed queues = %v, want both", consumed)
+	}
+	// Retries go back to the queue the task came from
+	if got := republish[gpuTask.VideoID.String()]; got != "/transcode_tasks_gpu" {
+		t.Errorf("GPU task republished to %q, want default exchange/transcode_tasks_gpu", got)
+	}
+	if got := republish[cpuTask.VideoID.String()]; got != "tasks/transcode" {
+		t.Errorf("CPU task republished to %q, want tasks/transcode", got)
+	}
+}
+
+func TestDeclaredQueues(t *testing.T) {
+	got := declaredQueues(ClientConfig{
+		QueueName:     "transcode_tasks",
+		ConsumeQueues: []ConsumeQueue{{Name: "transcode_tasks_gpu"}, {Name: "transcode_tasks"}},
+	})
+	want := []string{"transcode_tasks", "transcode_tasks_gpu"}
+	if !slices.Equal(got, want) {
+		t.Errorf("declaredQueues() = %v, want %v", got, want)
+	}
+
+	got = declaredQueues(ClientConfig{QueueName: "transcode_tasks", DeleteQueueName: "video_deletes"})
+	want = []string{"transcode_tasks", "video_deletes"}
+	if !slices.Equal(got, want) {
+		t.Errorf("declaredQueues() with delete queue = %v, want %v", got, want)
+	}
+}
+
+func TestDeclareTopology(t *testing.T) {
+	t.Run("dead-letter exchange configured", func(t *testing.T) {
+		var (
+			exchange, kind string
+			bound          [2]string
+			queueArgs      = map[string]amqp.Table{}
+		)
+		mockCh := &mockChannel{
+			exchangeDeclareFunc: func(name, k string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+				exchange, kind = name, k
+				return nil
+			},
+			queueBindFunc: func(name, key, ex string, noWait bool, args amqp.Table) error {
+				bound = [2]string{name, ex}
+				return nil
+			},
+			queueDeclareFunc: func(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+				queueArgs[name] = args
+				return amqp.Queue{Name: name}, nil
+			},
+		}
+
+		cfg := DefaultClientConfig("amqp://localhost")
+		cfg.ConsumeQueues = []ConsumeQueue{{Name: "transcode_tasks_gpu"}}
+		if err := declareTopology(mockCh, cfg); err != nil {
+			t.Fatalf("declareTopology() error = %v", err)
+		}
+
+		if exchange != "transcode_tasks.dlx" || kind != amqp.ExchangeFanout {
+			t.Errorf("exchange = %s (%s), want transcode_tasks.dlx (fanout)", exchange, kind)
+		}
+		if bound != [2]string{"transcode_tasks_dead", "transcode_tasks.dlx"} {
+			t.Errorf("bound = %v, want transcode_tasks_dead to transcode_tasks.dlx", bound)
+		}
+		if args, ok := queueArgs["transcode_tasks_dead"]; !ok || args != nil {
+			t.Errorf("dead-letter queue args = %v (declared %v), want nil", args, ok)
+		}
+		for _, name := range []string{"transcode_tasks", "transcode_tasks_gpu"} {
+			if got := queueArgs[name]["x-dead-letter-exchange"]; got != "transcode_tasks.dlx" {
+				t.Errorf("%s x-dead-letter-exchange = %v, want transcode_tasks.dlx", name, got)
+			}
+		}
+	})
+
+	t.Run("retry queues", func(t *testing.T) {
+		queueArgs := map[string]amqp.Table{}
+		mockCh := &mockChannel{
+			queueDeclareFunc: func(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+				queueArgs[name] = args
+				return amqp.Queue{Name: name}, nil
+			},
+		}
+
+		cfg := ClientConfig{
+			QueueName:       "transcode_tasks",
+			ConsumeQueues:   []ConsumeQueue{{Name: "transcode_tasks_gpu"}},
+			RetryBaseDelay:  10 * time.Second,
+			RetryMultiplier: 3,
+			RetryMaxDelay:   time.Minute,
+		}
+		if err := declareTopology(mockCh, cfg); err != nil {
+			t.Fatalf("declareTopology() error = %v", err)
+		}
+
+		// 2 task queues, each with 10s, 30s and 1m0s retry queues
+		if len(queueArgs) != 8 {
+			t.Errorf("declared %d queues, want 8: %v", len(queueArgs), queueArgs)
+		}
+		want := amqp.Table{
+			"x-message-ttl":             int64(30000),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": "transcode_tasks_gpu",
+		}
+		if got := queueArgs["transcode_tasks_gpu.retry.30s"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("retry queue args = %v, want %v", got, want)
+		}
+		if _, ok := queueArgs["transcode_tasks.retry.1m0s"]; !ok {
+			t.Error("expected a retry queue capped at RetryMaxDelay")
+		}
+	})
+
+	t.Run("dead-lettering disabled", func(t *testing.T) {
+		mockCh := &mockChannel{
+			exchangeDeclareFunc: func(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+				t.Error("unexpected exchange declaration")
+				return nil
+			},
+			queueDeclareFunc: func(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+				if args != nil {
+					t.Errorf("%s args = %v, want nil", name, args)
+				}
+				return amqp.Queue{Name: name}, nil
+			},
+		}
+
+		if err := declareTopology(mockCh, ClientConfig{QueueName: "transcode_tasks"}); err != nil {
+			t.Fatalf("declareTopology() error = %v", err)
+		}
+	})
+
+	t.Run("exchange declaration error", func(t *testing.T) {
+		mockCh := &mockChannel{
+			exchangeDeclareFunc: func(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+				return errors.New("access refused")
+			},
+		}
+
+		err := declareTopology(mockCh, DefaultClientConfig("amqp://localhost"))
+		if err == nil || !strings.Contains(err.Error(), "dead-letter exchange") {
+			t.Errorf("declareTopology() error = %v, want dead-letter exchange error", err)
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        ClientConfig
+		retryCount int
+		want       time.Duration
+	}{
+		{"first retry", DefaultClientConfig(""), 1, 5 * time.Second},
+		{"grows by multiplier", DefaultClientConfig(""), 4, 40 * time.Second},
+		{"capped at max delay", DefaultClientConfig(""), 20, 5 * time.Minute},
+		{"constant without multiplier", ClientConfig{RetryBaseDelay: time.Second, RetryMaxDelay: time.Minute}, 3, time.Second},
+		{"max below base", ClientConfig{RetryBaseDelay: time.Minute, RetryMultiplier: 2}, 3, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.cfg, tt.retryCount); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.retryCount, got, tt.want)
+			}
+		})
+	}
+
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 160 * time.Second, 5 * time.Minute}
+	if got := retryDelays(DefaultClientConfig("")); !slices.Equal(got, want) {
+		t.Errorf("retryDelays() = %v, want %v", got, want)
+	}
+	if got := retryDelays(ClientConfig{}); got != nil {
+		t.Errorf("retryDelays() without backoff = %v, want nil", got)
+	}
 }
 
 // mockAcknowledger implements amqp.Acknowledger for testing.
@@ -526,6 +1224,157 @@ func (m *mockAcknowledger) Reject(tag uint64, requeue bool) error {
 	return nil
 }
 
+func TestClient_Ping(t *testing.T) {
+	tests := []struct {
+		name    string
+		closed  bool
+		wantErr bool
+	}{
+		{name: "open connection", closed: false, wantErr: false},
+		{name: "closed connection", closed: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				conn: &mockConnection{
+					isClosedFunc: func() bool { return tt.closed },
+				},
+			}
+
+			err := client.Ping(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	task := repository.TranscodeTask{
+		VideoID:     uuid.New(),
+		OriginalKey: "uploads/video/original.mp4",
+	}
+	body, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("failed to marshal task: %v", err)
+	}
+
+	// The first connection drops while consuming; its deliveries channel closes with it
+	lost := make(chan *amqp.Error, 1)
+	oldDeliveries := make(chan amqp.Delivery)
+	oldConn := &mockConnection{
+		notifyCloseFunc: func(receiver chan *amqp.Error) chan *amqp.Error { return lost },
+	}
+	oldCh := &mockChannel{
+		consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+			return oldDeliveries, nil
+		},
+	}
+
+	newDeliveries := make(chan amqp.Delivery, 1)
+	newDeliveries <- amqp.Delivery{Body: body, Acknowledger: &mockAcknowledger{}}
+	newCh := &mockChannel{
+		consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+			return newDeliveries, nil
+		},
+	}
+
+	dials := 0
+	client := newClient(oldConn, oldCh, ClientConfig{
+		QueueName:          "transcode_tasks",
+		ReconnectBaseDelay: time.Millisecond,
+		ReconnectMaxDelay:  4 * time.Millisecond,
+	})
+	client.dial = func() (amqpConnection, amqpChannel, error) {
+		dials++
+		if dials == 1 {
+			return nil, nil, errors.New("connection refused")
+		}
+		return &mockConnection{}, newCh, nil
+	}
+	reconnected := make(chan struct{}, 1)
+	client.NotifyReconnect(func() { reconnected <- struct{}{} })
+	defer client.Close()
+
+	failuresBefore := testutil.ToFloat64(metrics.QueueReconnectsTotal.WithLabelValues(metrics.QueueReconnectFailure))
+	successesBefore := testutil.ToFloat64(metrics.QueueReconnectsTotal.WithLabelValues(metrics.QueueReconnectSuccess))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan repository.TranscodeTask, 1)
+	consumeErr := make(chan error, 1)
+	go func() {
+		consumeErr <- client.ConsumeTranscodeTasks(ctx, func(got repository.TranscodeTask) error {
+			received <- got
+			return nil
+		})
+	}()
+
+	go client.watch(oldConn)
+	lost <- &amqp.Error{Code: amqp.ConnectionForced, Reason: "broker restart"}
+	close(oldDeliveries)
+
+	select {
+	case <-reconnected:
+	case <-time.After(time.Second):
+		t.Fatal("reconnect callback was not called")
+	}
+
+	select {
+	case got := <-received:
+		if got.VideoID != task.VideoID {
+			t.Errorf("VideoID = %v, want %v", got.VideoID, task.VideoID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("consumer did not resume on the new channel")
+	}
+
+	if ch, generation := client.current(); ch != newCh || generation != 1 {
+		t.Errorf("current() = (%v, %d), want the new channel at generation 1", ch, generation)
+	}
+	if got := testutil.ToFloat64(metrics.QueueReconnectsTotal.WithLabelValues(metrics.QueueReconnectFailure)) - failuresBefore; got != 1 {
+		t.Errorf("failed reconnects = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.QueueReconnectsTotal.WithLabelValues(metrics.QueueReconnectSuccess)) - successesBefore; got != 1 {
+		t.Errorf("successful reconnects = %v, want 1", got)
+	}
+
+	cancel()
+	select {
+	case err := <-consumeErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ConsumeTranscodeTasks() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("consumer did not stop after cancel")
+	}
+}
+
+func TestClient_Reconnect_Closed(t *testing.T) {
+	// A closed client does not resume consumption
+	deliveries := make(chan amqp.Delivery)
+	close(deliveries)
+	client := newClient(&mockConnection{}, &mockChannel{
+		consumeFunc: func(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+			return deliveries, nil
+		},
+	}, ClientConfig{QueueName: "transcode_tasks", ReconnectBaseDelay: time.Millisecond})
+	client.dial = func() (amqpConnection, amqpChannel, error) {
+		return nil, nil, errors.New("unexpected dial")
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	err := client.ConsumeTranscodeTasks(context.Background(), func(task repository.TranscodeTask) error { return nil })
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("ConsumeTranscodeTasks() error = %v, want ErrClientClosed", err)
+	}
+}
+
 func TestClient_Close(t *testing.T) {
 	tests := []struct {
 		name        string
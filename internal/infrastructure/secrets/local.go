@@ -0,0 +1,144 @@
+// Package secrets implements repository.SecretStore.
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// localRefPrefix marks references sealed by a LocalStore.
+const localRefPrefix = "local:"
+
+// ErrNoKeys is returned when a LocalStore is created without keys.
+var ErrNoKeys = errors.New("at least one secrets key is required")
+
+// Key is a 256-bit AES key and the ID recorded in the references it seals.
+type Key struct {
+	ID  string
+	Key []byte
+}
+
+// LocalStore seals secrets with AES-256-GCM into the reference itself, so the database
+// only ever holds ciphertext and nothing but the keys has to be provisioned. The first
+// key seals and every key opens, so keys can be rotated by prepending a new one and
+// re-saving the secrets sealed with the old one. A secrets manager (e.g., Vault or AWS
+// Secrets Manager) can take its place behind repository.SecretStore.
+type LocalStore struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+}
+
+var _ repository.SecretStore = (*LocalStore)(nil)
+
+// NewLocalStore creates a store from keys; keys[0] is used to seal.
+func NewLocalStore(keys ...Key) (*LocalStore, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	s := &LocalStore{
+		activeKeyID: keys[0].ID,
+		aeads:       make(map[string]cipher.AEAD, len(keys)),
+	}
+	for _, k := range keys {
+		if k.ID == "" || strings.Contains(k.ID, ":") {
+			return nil, fmt.Errorf("secrets key ID %q must be non-empty and without colons", k.ID)
+		}
+		if _, dup := s.aeads[k.ID]; dup {
+			return nil, fmt.Errorf("duplicate secrets key ID %q", k.ID)
+		}
+		if len(k.Key) != 32 {
+			return nil, fmt.Errorf("secrets key %q must be 32 bytes, got %d", k.ID, len(k.Key))
+		}
+
+		block, err := aes.NewCipher(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher for key %q: %w", k.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCM for key %q: %w", k.ID, err)
+		}
+		s.aeads[k.ID] = aead
+	}
+
+	return s, nil
+}
+
+// ParseLocalStore creates a store from "id:base64key[,id:base64key...]" as used in
+// configuration; the first key seals. Returns nil without error for an empty spec.
+func ParseLocalStore(spec string) (*LocalStore, error) {
+	var keys []Key
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("secrets key entry must be id:base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("secrets key %q is not valid base64: %w", id, err)
+		}
+		keys = append(keys, Key{ID: id, Key: key})
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return NewLocalStore(keys...)
+}
+
+// Put seals value with the active key. The reference is
+// local:{key ID}:{base64url(nonce || ciphertext)}; the key ID is authenticated as
+// additional data, so a reference cannot be relabelled with another key. The name is
+// not needed to open the secret and is not recorded.
+func (s *LocalStore) Put(ctx context.Context, name string, value []byte) (string, error) {
+	aead := s.aeads[s.activeKeyID]
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, value, []byte(s.activeKeyID))
+
+	return localRefPrefix + s.activeKeyID + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Get opens a reference produced by Put.
+func (s *LocalStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(ref, localRefPrefix), ":")
+	if !ok || !strings.HasPrefix(ref, localRefPrefix) {
+		return nil, fmt.Errorf("%w: not a local secret reference", repository.ErrSecretNotFound)
+	}
+	aead, ok := s.aeads[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: sealed with unknown key %q", repository.ErrSecretNotFound, keyID)
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed secret reference")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	value, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open secret: %w", err)
+	}
+	return value, nil
+}
+
+// Delete does nothing: the value lives in the reference, which goes with its row.
+func (s *LocalStore) Delete(ctx context.Context, ref string) error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestLocalStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(Key{ID: "k1", Key: testKey(1)})
+	if err != nil {
+		t.Fatalf("NewLocalStore() unexpected error: %v", err)
+	}
+
+	ref, err := store.Put(ctx, "output-bucket/acme", []byte("wJalrXUtnFEMI"))
+	if err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(ref, "local:k1:") || strings.Contains(ref, "wJalrXUtnFEMI") {
+		t.Errorf("Put() = %q, want a sealed local:k1: reference", ref)
+	}
+
+	got, err := store.Get(ctx, ref)
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if string(got) != "wJalrXUtnFEMI" {
+		t.Errorf("Get() = %q, want %q", got, "wJalrXUtnFEMI")
+	}
+	if err := store.Delete(ctx, ref); err != nil {
+		t.Errorf("Delete() unexpected error: %v", err)
+	}
+}
+
+func TestLocalStore_Get(t *testing.T) {
+	ctx := context.Background()
+	old, err := NewLocalStore(Key{ID: "old", Key: testKey(1)})
+	if err != nil {
+		t.Fatalf("NewLocalStore() unexpected error: %v", err)
+	}
+	sealedOld, err := old.Put(ctx, "secret", []byte("value"))
+	if err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	// Rotated: the new key seals, the old key still opens
+	rotated, err := NewLocalStore(Key{ID: "new", Key: testKey(2)}, Key{ID: "old", Key: testKey(1)})
+	if err != nil {
+		t.Fatalf("NewLocalStore() unexpected error: %v", err)
+	}
+
+	tampered := []byte(sealedOld)
+	tampered[len(tampered)-2] ^= 0x01
+
+	tests := []struct {
+		name     string
+		ref      string
+		want     string
+		wantErr  bool
+		notFound bool
+	}{
+		{name: "sealed with old key", ref: sealedOld, want: "value"},
+		{name: "relabelled key", ref: strings.Replace(sealedOld, "local:old:", "local:new:", 1), wantErr: true},
+		{name: "tampered", ref: string(tampered), wantErr: true},
+		{name: "unknown key", ref: "local:gone:" + base64.RawURLEncoding.EncodeToString(make([]byte, 40)), wantErr: true, notFound: true},
+		{name: "foreign reference", ref: "vault:secret/data/acme", wantErr: true, notFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rotated.Get(ctx, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, repository.ErrSecretNotFound) != tt.notFound {
+				t.Errorf("Get() error = %v, want ErrSecretNotFound: %v", err, tt.notFound)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLocalStore(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(testKey(1))
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "empty", spec: "", wantNil: true},
+		{name: "two keys", spec: "k2:" + key + ", k1:" + key},
+		{name: "missing separator", spec: key, wantErr: true},
+		{name: "invalid base64", spec: "k1:not-base64!", wantErr: true},
+		{name: "short key", spec: "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{name: "duplicate ID", spec: "k1:" + key + ",k1:" + key, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := ParseLocalStore(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLocalStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (store == nil) != tt.wantNil {
+				t.Errorf("ParseLocalStore() = %v, wantNil %v", store, tt.wantNil)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// transientCodes are S3 error codes that ask the client to try again.
+var transientCodes = map[string]bool{
+	"SlowDown":                   true,
+	"RequestTimeout":             true,
+	"ServiceUnavailable":         true,
+	"InternalError":              true,
+	"XMinioServerNotInitialized": true,
+}
+
+// invalidPartCodes are S3 error codes for rejected parts of a multipart upload.
+var invalidPartCodes = map[string]bool{
+	"InvalidPart":      true,
+	"InvalidPartOrder": true,
+	"EntityTooSmall":   true,
+}
+
+// classify attaches an errs code to a MinIO error. Missing keys and buckets become
+// repository.ErrObjectNotFound and repository.ErrBucketNotFound, failed If-Match
+// preconditions repository.ErrObjectChanged, missing and rejected
+// multipart uploads repository.ErrMultipartUploadNotFound and
+// repository.ErrInvalidMultipartParts; throttling, server
+// errors, timeouts and network failures are coded errs.Transient. Other errors, such
+// as denied access, are returned unchanged.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	resp := minio.ToErrorResponse(err)
+	switch {
+	case resp.Code == "NoSuchKey":
+		return fmt.Errorf("%w: %w", repository.ErrObjectNotFound, err)
+	case resp.Code == "NoSuchBucket":
+		return fmt.Errorf("%w: %w", repository.ErrBucketNotFound, err)
+	case resp.Code == "PreconditionFailed", resp.StatusCode == http.StatusPreconditionFailed:
+		return fmt.Errorf("%w: %w", repository.ErrObjectChanged, err)
+	case resp.Code == "NoSuchUpload":
+		return fmt.Errorf("%w: %w", repository.ErrMultipartUploadNotFound, err)
+	case invalidPartCodes[resp.Code]:
+		return fmt.Errorf("%w: %w", repository.ErrInvalidMultipartParts, err)
+	case transientCodes[resp.Code], resp.StatusCode >= http.StatusInternalServerError, resp.StatusCode == http.StatusTooManyRequests:
+		return errs.Wrap(errs.Transient, err)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return errs.Wrap(errs.Transient, err)
+	}
+	return err
+}
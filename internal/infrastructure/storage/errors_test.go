@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantIs   error
+		wantCode errs.Code
+	}{
+		{name: "nil", err: nil},
+		{name: "missing key", err: minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404}, wantIs: repository.ErrObjectNotFound, wantCode: errs.NotFound},
+		{name: "missing bucket", err: minio.ErrorResponse{Code: "NoSuchBucket", StatusCode: 404}, wantIs: repository.ErrBucketNotFound, wantCode: errs.NotFound},
+		{name: "replaced object", err: minio.ErrorResponse{Code: "PreconditionFailed", StatusCode: 412}, wantIs: repository.ErrObjectChanged, wantCode: errs.Conflict},
+		{name: "missing multipart upload", err: minio.ErrorResponse{Code: "NoSuchUpload", StatusCode: 404}, wantIs: repository.ErrMultipartUploadNotFound, wantCode: errs.NotFound},
+		{name: "part too small", err: minio.ErrorResponse{Code: "EntityTooSmall", StatusCode: 400}, wantIs: repository.ErrInvalidMultipartParts, wantCode: errs.Invalid},
+		{name: "throttled", err: minio.ErrorResponse{Code: "SlowDown", StatusCode: 503}, wantCode: errs.Transient},
+		{name: "server error", err: minio.ErrorResponse{Code: "InternalError", StatusCode: 500}, wantCode: errs.Transient},
+		{name: "network failure", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, wantCode: errs.Transient},
+		{name: "deadline", err: fmt.Errorf("put: %w", context.DeadlineExceeded), wantCode: errs.Transient},
+		{name: "access denied", err: minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classify(tt.err)
+			if (got == nil) != (tt.err == nil) {
+				t.Fatalf("classify() = %v, want nil only for nil", got)
+			}
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Errorf("classify() dropped the cause: %v", got)
+			}
+			if tt.wantIs != nil && !errors.Is(got, tt.wantIs) {
+				t.Errorf("classify() = %v, want it to wrap %v", got, tt.wantIs)
+			}
+			if code := errs.CodeOf(got); code != tt.wantCode {
+				t.Errorf("code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// Origin is a named object storage that can serve reads.
+type Origin struct {
+	// Name identifies the origin in logs and metrics (e.g., "primary", "minio-2").
+	Name    string
+	Storage repository.ObjectStorage
+}
+
+// FallbackConfig holds configuration for FallbackStorage.
+type FallbackConfig struct {
+	// FailureThreshold is the number of consecutive transient read failures after which an
+	// origin is demoted behind the healthy ones.
+	FailureThreshold int
+	// DemoteFor is how long a demoted origin is tried last. After it, the origin is tried in
+	// its configured place again, and a single further failure demotes it again.
+	DemoteFor time.Duration
+}
+
+// DefaultFallbackConfig returns the default configuration.
+func DefaultFallbackConfig() FallbackConfig {
+	return FallbackConfig{
+		FailureThreshold: 3,
+		DemoteFor:        30 * time.Second,
+	}
+}
+
+// originState is an origin with its read health.
+type originState struct {
+	Origin
+	failures     int
+	demotedUntil time.Time
+}
+
+// FallbackStorage implements repository.ObjectStorage over an ordered list of origins.
+// Reads try the origins in order and move on to the next one when an origin fails with an
+// errs.Transient error, so an outage of one MinIO node or region does not fail downloads.
+// Other errors, including repository.ErrObjectNotFound, are answers and are returned as is.
+// Origins that keep failing are demoted behind the healthy ones for a while, so reads do
+// not wait for a dead origin to time out every time.
+//
+// Writes, deletes and presigned URLs always use the first origin, the primary: fallback
+// origins may hold a subset of the objects (a secondary region only receives transcoded output)
+// and may not be reachable by clients. A read that fails after its object was opened is
+// not retried elsewhere.
+type FallbackStorage struct {
+	mu      sync.Mutex
+	origins []*originState
+	cfg     FallbackConfig
+	now     func() time.Time
+}
+
+// Compile-time verification that FallbackStorage implements ObjectStorage.
+var _ repository.ObjectStorage = (*FallbackStorage)(nil)
+
+// NewFallbackStorage creates a FallbackStorage. The first origin is the primary; origins
+// must not be empty. Non-positive config values fall back to the defaults.
+func NewFallbackStorage(origins []Origin, cfg FallbackConfig) *FallbackStorage {
+	defaults := DefaultFallbackConfig()
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaults.FailureThreshold
+	}
+	if cfg.DemoteFor <= 0 {
+		cfg.DemoteFor = defaults.DemoteFor
+	}
+
+	states := make([]*originState, len(origins))
+	for i, o := range origins {
+		states[i] = &originState{Origin: o}
+		metrics.StorageOriginDemoted.WithLabelValues(o.Name).Set(0)
+	}
+	return &FallbackStorage{
+		origins: states,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// primary returns the storage that receives writes.
+func (s *FallbackStorage) primary() repository.ObjectStorage {
+	return s.origins[0].Storage
+}
+
+// GeneratePresignedUploadURL signs an upload to the primary.
+func (s *FallbackStorage) GeneratePresignedUploadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.primary().GeneratePresignedUploadURL(ctx, key, expiry)
+}
+
+// GeneratePresignedDownloadURL signs a download from the primary. Signing is local, so it
+// does not fail during an outage; only fetching the URL does.
+func (s *FallbackStorage) GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.primary().GeneratePresignedDownloadURL(ctx, key, expiry)
+}
+
+// Upload stores an object on the primary.
+func (s *FallbackStorage) Upload(ctx context.Context, key string, reader io.Reader, contentType string) error {
+	return s.primary().Upload(ctx, key, reader, contentType)
+}
+
+// Download retrieves an object from the first origin that serves it.
+func (s *FallbackStorage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	return read(ctx, s, "download", func(o repository.ObjectStorage) (io.ReadCloser, error) {
+		return o.Download(ctx, key)
+	})
+}
+
+// DownloadRange retrieves part of an object from the first origin that serves it.
+func (s *FallbackStorage) DownloadRange(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error) {
+	return read(ctx, s, "download_range", func(o repository.ObjectStorage) (io.ReadCloser, error) {
+		return o.DownloadRange(ctx, key, etag, offset, length)
+	})
+}
+
+// Stat returns metadata about an object from the first origin that answers.
+func (s *FallbackStorage) Stat(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+	return read(ctx, s, "stat", func(o repository.ObjectStorage) (*repository.ObjectInfo, error) {
+		return o.Stat(ctx, key)
+	})
+}
+
+// Delete removes an object from the primary.
+func (s *FallbackStorage) Delete(ctx context.Context, key string) error {
+	return s.primary().Delete(ctx, key)
+}
+
+// Exists checks for an object on the first origin that answers.
+func (s *FallbackStorage) Exists(ctx context.Context, key string) (bool, error) {
+	return read(ctx, s, "exists", func(o repository.ObjectStorage) (bool, error) {
+		return o.Exists(ctx, key)
+	})
+}
+
+// ListObjects lists objects on the first origin that answers.
+func (s *FallbackStorage) ListObjects(ctx context.Context, prefix string) ([]repository.ObjectInfo, error) {
+	return read(ctx, s, "list", func(o repository.ObjectStorage) ([]repository.ObjectInfo, error) {
+		return o.ListObjects(ctx, prefix)
+	})
+}
+
+// DeleteByPrefix removes objects from the primary.
+func (s *FallbackStorage) DeleteByPrefix(ctx context.Context, prefix string) (int, error) {
+	return s.primary().DeleteByPrefix(ctx, prefix)
+}
+
+// InitiateMultipartUpload starts a multipart upload on the primary, where uploads go.
+func (s *FallbackStorage) InitiateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	return s.primary().InitiateMultipartUpload(ctx, key, contentType)
+}
+
+// PresignPart signs a part upload to the primary.
+func (s *FallbackStorage) PresignPart(ctx context.Context, key, uploadID string, partNumber int, expiry time.Duration) (string, error) {
+	return s.primary().PresignPart(ctx, key, uploadID, partNumber, expiry)
+}
+
+// CompleteMultipartUpload assembles a multipart upload on the primary.
+func (s *FallbackStorage) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []repository.CompletedPart) error {
+	return s.primary().CompleteMultipartUpload(ctx, key, uploadID, parts)
+}
+
+// pinger is implemented by origins that can check their connection, such as *Client.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping succeeds if any origin is reachable, since reads can then still be served.
+// Origins that cannot be pinged count as reachable.
+func (s *FallbackStorage) Ping(ctx context.Context) error {
+	var pingErrs []error
+	for _, o := range s.origins {
+		p, ok := o.Storage.(pinger)
+		if !ok {
+			return nil
+		}
+		err := p.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		pingErrs = append(pingErrs, fmt.Errorf("%s: %w", o.Name, err))
+	}
+	return errors.Join(pingErrs...)
+}
+
+// read runs fn against the origins in order of health until one answers with anything
+// but a transient error. Returns the last error if every origin failed.
+func read[T any](ctx context.Context, s *FallbackStorage, op string, fn func(repository.ObjectStorage) (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+	for _, o := range s.order() {
+		v, err := fn(o.Storage)
+		if err == nil || !errors.Is(err, errs.Transient) {
+			s.report(o, nil)
+			if o != s.origins[0] {
+				metrics.StorageFallbackReadsTotal.WithLabelValues(op, o.Name).Inc()
+			}
+			return v, err
+		}
+		lastErr = err
+
+		// Every origin would fail the same way once the caller has given up, and the
+		// failure says nothing about this origin's health
+		if ctx.Err() != nil {
+			break
+		}
+		s.report(o, err)
+	}
+	return zero, lastErr
+}
+
+// order returns the origins to try: healthy ones in their configured order, then the
+// demoted ones in the same order as a last resort.
+func (s *FallbackStorage) order() []*originState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	ordered := slices.Clone(s.origins)
+	slices.SortStableFunc(ordered, func(a, b *originState) int {
+		aDemoted, bDemoted := now.Before(a.demotedUntil), now.Before(b.demotedUntil)
+		switch {
+		case aDemoted == bDemoted:
+			return 0
+		case aDemoted:
+			return 1
+		default:
+			return -1
+		}
+	})
+	return ordered
+}
+
+// report records the outcome of a read from o; err is nil when o answered.
+func (s *FallbackStorage) report(o *originState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		if !o.demotedUntil.IsZero() {
+			slog.Info("storage origin recovered", "origin", o.Name)
+			metrics.StorageOriginDemoted.WithLabelValues(o.Name).Set(0)
+		}
+		o.failures = 0
+		o.demotedUntil = time.Time{}
+		return
+	}
+
+	o.failures++
+	if o.failures >= s.cfg.FailureThreshold && !s.now().Before(o.demotedUntil) {
+		o.demotedUntil = s.now().Add(s.cfg.DemoteFor)
+		// One more failure after the demotion ends demotes the origin again
+		o.failures = s.cfg.FailureThreshold - 1
+		slog.Warn("storage origin demoted",
+			"origin", o.Name,
+			"demote_for", s.cfg.DemoteFor,
+			"error", err,
+		)
+		metrics.StorageOriginDemoted.WithLabelValues(o.Name).Set(1)
+	}
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// fakeOrigin is an ObjectStorage whose reads return err and whose writes are counted.
+// Methods the tests do not use panic through the nil embedded interface.
+type fakeOrigin struct {
+	repository.ObjectStorage
+	err     error
+	reads   int
+	uploads int
+	pingErr error
+}
+
+func (f *fakeOrigin) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	f.reads++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return io.NopCloser(strings.NewReader(key)), nil
+}
+
+func (f *fakeOrigin) Upload(ctx context.Context, key string, reader io.Reader, contentType string) error {
+	f.uploads++
+	return nil
+}
+
+func (f *fakeOrigin) Ping(ctx context.Context) error {
+	return f.pingErr
+}
+
+func TestFallbackStorage_Download(t *testing.T) {
+	outage := errs.Wrap(errs.Transient, errors.New("connection refused"))
+
+	tests := []struct {
+		name       string
+		primaryErr error
+		replicaErr error
+		wantErr    error
+		wantBody   bool
+		wantReads  [2]int
+	}{
+		{name: "primary serves", wantBody: true, wantReads: [2]int{1, 0}},
+		{name: "falls back on outage", primaryErr: outage, wantBody: true, wantReads: [2]int{1, 1}},
+		{name: "not found is an answer", primaryErr: repository.ErrObjectNotFound, wantErr: repository.ErrObjectNotFound, wantReads: [2]int{1, 0}},
+		{name: "every origin down", primaryErr: outage, replicaErr: outage, wantErr: errs.Transient, wantReads: [2]int{1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeOrigin{err: tt.primaryErr}
+			replica := &fakeOrigin{err: tt.replicaErr}
+			s := NewFallbackStorage([]Origin{{Name: "primary", Storage: primary}, {Name: "replica", Storage: replica}}, FallbackConfig{})
+
+			body, err := s.Download(context.Background(), "hls/video/master.m3u8")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Download() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			if (body != nil) != tt.wantBody {
+				t.Errorf("Download() body = %v, want body %v", body, tt.wantBody)
+			}
+			if got := [2]int{primary.reads, replica.reads}; got != tt.wantReads {
+				t.Errorf("reads = %v, want %v", got, tt.wantReads)
+			}
+		})
+	}
+}
+
+func TestFallbackStorage_Demotion(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	primary := &fakeOrigin{err: errs.Wrap(errs.Transient, errors.New("connection refused"))}
+	replica := &fakeOrigin{}
+	s := NewFallbackStorage([]Origin{{Name: "primary", Storage: primary}, {Name: "replica", Storage: replica}}, FallbackConfig{
+		FailureThreshold: 2,
+		DemoteFor:        time.Minute,
+	})
+	s.now = func() time.Time { return now }
+
+	download := func() {
+		t.Helper()
+		if _, err := s.Download(context.Background(), "key"); err != nil {
+			t.Fatalf("Download() error = %v", err)
+		}
+	}
+
+	// Two failures demote the primary, so the third read goes to the replica directly
+	download()
+	download()
+	download()
+	if primary.reads != 2 || replica.reads != 3 {
+		t.Fatalf("reads = %d/%d, want 2/3", primary.reads, replica.reads)
+	}
+
+	// Once the demotion ends the primary is tried first again, and one failure demotes it
+	now = now.Add(time.Minute)
+	download()
+	download()
+	if primary.reads != 3 {
+		t.Fatalf("primary reads = %d, want 3", primary.reads)
+	}
+
+	// A recovered primary is promoted by its first successful read
+	now = now.Add(time.Minute)
+	primary.err = nil
+	download()
+	download()
+	if primary.reads != 5 || replica.reads != 5 {
+		t.Errorf("reads = %d/%d, want 5/5", primary.reads, replica.reads)
+	}
+}
+
+func TestFallbackStorage_CancelledReadKeepsHealth(t *testing.T) {
+	primary := &fakeOrigin{err: errs.Wrap(errs.Transient, context.Canceled)}
+	replica := &fakeOrigin{}
+	s := NewFallbackStorage([]Origin{{Name: "primary", Storage: primary}, {Name: "replica", Storage: replica}}, FallbackConfig{FailureThreshold: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Download(ctx, "key"); err == nil {
+		t.Fatal("Download() expected error for cancelled context")
+	}
+	if replica.reads != 0 {
+		t.Errorf("replica reads = %d, want 0", replica.reads)
+	}
+	if order := s.order(); order[0].Name != "primary" {
+		t.Errorf("first origin = %s, want primary not demoted", order[0].Name)
+	}
+}
+
+func TestFallbackStorage_WritesUsePrimary(t *testing.T) {
+	primary := &fakeOrigin{err: errs.Wrap(errs.Transient, errors.New("connection refused"))}
+	replica := &fakeOrigin{}
+	s := NewFallbackStorage([]Origin{{Name: "primary", Storage: primary}, {Name: "replica", Storage: replica}}, FallbackConfig{FailureThreshold: 1})
+
+	// Demote the primary; writes must still go to it
+	_, _ = s.Download(context.Background(), "key")
+	if err := s.Upload(context.Background(), "key", strings.NewReader("data"), "video/mp2t"); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if primary.uploads != 1 || replica.uploads != 0 {
+		t.Errorf("uploads = %d/%d, want 1/0", primary.uploads, replica.uploads)
+	}
+}
+
+func TestFallbackStorage_Ping(t *testing.T) {
+	down := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		primaryErr error
+		replicaErr error
+		wantErr    bool
+	}{
+		{name: "all up"},
+		{name: "primary down", primaryErr: down},
+		{name: "all down", primaryErr: down, replicaErr: down, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewFallbackStorage([]Origin{
+				{Name: "primary", Storage: &fakeOrigin{pingErr: tt.primaryErr}},
+				{Name: "replica", Storage: &fakeOrigin{pingErr: tt.replicaErr}},
+			}, FallbackConfig{})
+
+			if err := s.Ping(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/cors"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
 )
 
@@ -30,6 +35,12 @@ type minioClient interface {
 	GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error)
 	RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error
 	StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error)
+	ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo
+	RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError
+	GetBucketCors(ctx context.Context, bucketName string) (*cors.Config, error)
+	NewMultipartUpload(ctx context.Context, bucketName, objectName string, opts minio.PutObjectOptions) (string, error)
+	Presign(ctx context.Context, method, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error)
+	CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []minio.CompletePart, opts minio.PutObjectOptions) (minio.UploadInfo, error)
 }
 
 // minioClientAdapter wraps *minio.Client to implement minioClient interface.
@@ -67,6 +78,32 @@ func (a *minioClientAdapter) StatObject(ctx context.Context, bucketName, objectN
 	return a.client.StatObject(ctx, bucketName, objectName, opts)
 }
 
+func (a *minioClientAdapter) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	return a.client.ListObjects(ctx, bucketName, opts)
+}
+
+func (a *minioClientAdapter) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	return a.client.RemoveObjects(ctx, bucketName, objectsCh, opts)
+}
+
+func (a *minioClientAdapter) GetBucketCors(ctx context.Context, bucketName string) (*cors.Config, error) {
+	return a.client.GetBucketCors(ctx, bucketName)
+}
+
+// The multipart calls are only exposed by minio.Core, which wraps the same client.
+
+func (a *minioClientAdapter) NewMultipartUpload(ctx context.Context, bucketName, objectName string, opts minio.PutObjectOptions) (string, error) {
+	return minio.Core{Client: a.client}.NewMultipartUpload(ctx, bucketName, objectName, opts)
+}
+
+func (a *minioClientAdapter) Presign(ctx context.Context, method, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error) {
+	return a.client.Presign(ctx, method, bucketName, objectName, expiry, reqParams)
+}
+
+func (a *minioClientAdapter) CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []minio.CompletePart, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	return minio.Core{Client: a.client}.CompleteMultipartUpload(ctx, bucketName, objectName, uploadID, parts, opts)
+}
+
 // ClientConfig holds configuration for the MinIO client.
 type ClientConfig struct {
 	Endpoint       string
@@ -75,6 +112,7 @@ type ClientConfig struct {
 	SecretKey      string
 	Bucket         string
 	UseSSL         bool
+	Region         string // Optional: skips region discovery, required by some S3-compatible providers
 }
 
 // Client wraps a MinIO client and implements repository.ObjectStorage.
@@ -91,6 +129,7 @@ func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 	client, err := minio.New(cfg.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
 		Secure: cfg.UseSSL,
+		Region: cfg.Region,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create minio client: %w", err)
@@ -104,6 +143,7 @@ func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 		presignedClient, err := minio.New(cfg.PublicEndpoint, &minio.Options{
 			Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
 			Secure: cfg.UseSSL,
+			Region: cfg.Region,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create presigned minio client: %w", err)
@@ -119,7 +159,7 @@ func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 func newClientWithMinioClient(ctx context.Context, client, presignedClient minioClient, bucket string) (*Client, error) {
 	exists, err := client.BucketExists(ctx, bucket)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check bucket existence: %w", err)
+		return nil, fmt.Errorf("failed to check bucket existence: %w", classify(err))
 	}
 	if !exists {
 		return nil, fmt.Errorf("%w: %s", repository.ErrBucketNotFound, bucket)
@@ -137,7 +177,7 @@ func newClientWithMinioClient(ctx context.Context, client, presignedClient minio
 func (c *Client) GeneratePresignedUploadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
 	presignedURL, err := c.presignedClient.PresignedPutObject(ctx, c.bucket, key, expiry)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate presigned upload URL: %w", err)
+		return "", fmt.Errorf("failed to generate presigned upload URL: %w", classify(err))
 	}
 	return presignedURL.String(), nil
 }
@@ -148,7 +188,7 @@ func (c *Client) GeneratePresignedDownloadURL(ctx context.Context, key string, e
 	reqParams := make(url.Values)
 	presignedURL, err := c.presignedClient.PresignedGetObject(ctx, c.bucket, key, expiry, reqParams)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate presigned download URL: %w", err)
+		return "", fmt.Errorf("failed to generate presigned download URL: %w", classify(err))
 	}
 	return presignedURL.String(), nil
 }
@@ -159,7 +199,7 @@ func (c *Client) Upload(ctx context.Context, key string, reader io.Reader, conte
 		ContentType: contentType,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to upload object: %w", err)
+		return fmt.Errorf("failed to upload object: %w", classify(err))
 	}
 	return nil
 }
@@ -169,7 +209,7 @@ func (c *Client) Upload(ctx context.Context, key string, reader io.Reader, conte
 func (c *Client) Download(ctx context.Context, key string) (io.ReadCloser, error) {
 	obj, err := c.client.GetObject(ctx, c.bucket, key, minio.GetObjectOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get object: %w", err)
+		return nil, fmt.Errorf("failed to get object: %w", classify(err))
 	}
 
 	// Verify the object exists by checking its stat.
@@ -180,17 +220,73 @@ func (c *Client) Download(ctx context.Context, key string) (io.ReadCloser, error
 		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
 			return nil, repository.ErrObjectNotFound
 		}
-		return nil, fmt.Errorf("failed to stat object: %w", err)
+		return nil, fmt.Errorf("failed to stat object: %w", classify(err))
 	}
 
 	return obj, nil
 }
 
+// DownloadRange retrieves length bytes of an object starting at offset.
+// A non-empty etag is sent as If-Match so a replaced object fails with
+// repository.ErrObjectChanged instead of mixing bytes from two versions.
+// Caller is responsible for closing the returned ReadCloser.
+func (c *Client) DownloadRange(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 || length <= 0 {
+		return nil, errs.Wrap(errs.Invalid, fmt.Errorf("invalid range: offset=%d length=%d", offset, length))
+	}
+
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, fmt.Errorf("failed to set range: %w", err)
+	}
+	if etag != "" {
+		if err := opts.SetMatchETag(strings.Trim(etag, `"`)); err != nil {
+			return nil, fmt.Errorf("failed to set etag precondition: %w", err)
+		}
+	}
+
+	obj, err := c.client.GetObject(ctx, c.bucket, key, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object range: %w", classify(err))
+	}
+
+	// Same lazy-reader caveat as Download: surface missing objects eagerly.
+	_, err = obj.Stat()
+	if err != nil {
+		_ = obj.Close() // Best effort close on error path
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, repository.ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object: %w", classify(err))
+	}
+
+	return obj, nil
+}
+
+// Stat returns metadata about a stored object.
+func (c *Client) Stat(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+	info, err := c.client.StatObject(ctx, c.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, repository.ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object: %w", classify(err))
+	}
+
+	return &repository.ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+		ETag:         info.ETag,
+	}, nil
+}
+
 // Delete removes an object from the storage.
 func (c *Client) Delete(ctx context.Context, key string) error {
 	err := c.client.RemoveObject(ctx, c.bucket, key, minio.RemoveObjectOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to delete object: %w", err)
+		return fmt.Errorf("failed to delete object: %w", classify(err))
 	}
 	return nil
 }
@@ -202,16 +298,167 @@ func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
 		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
 			return false, nil
 		}
-		return false, fmt.Errorf("failed to check object existence: %w", err)
+		return false, fmt.Errorf("failed to check object existence: %w", classify(err))
 	}
 	return true, nil
 }
 
+// InitiateMultipartUpload starts a multipart upload of key.
+func (c *Client) InitiateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	uploadID, err := c.client.NewMultipartUpload(ctx, c.bucket, key, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to initiate multipart upload: %w", classify(err))
+	}
+	return uploadID, nil
+}
+
+// PresignPart creates a presigned URL for one part of a multipart upload.
+// Uses presignedClient which may be configured with a public endpoint.
+func (c *Client) PresignPart(ctx context.Context, key, uploadID string, partNumber int, expiry time.Duration) (string, error) {
+	params := url.Values{
+		"partNumber": {strconv.Itoa(partNumber)},
+		"uploadId":   {uploadID},
+	}
+	presignedURL, err := c.presignedClient.Presign(ctx, http.MethodPut, c.bucket, key, expiry, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned part URL: %w", classify(err))
+	}
+	return presignedURL.String(), nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the object.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []repository.CompletedPart) error {
+	completeParts := make([]minio.CompletePart, len(parts))
+	for i, p := range parts {
+		completeParts[i] = minio.CompletePart{PartNumber: p.PartNumber, ETag: p.ETag}
+	}
+	if _, err := c.client.CompleteMultipartUpload(ctx, c.bucket, key, uploadID, completeParts, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", classify(err))
+	}
+	return nil
+}
+
+// listPageSize is the number of keys requested per listing page, the S3 maximum.
+const listPageSize = 1000
+
+// ListObjects returns every object under prefix, listed recursively.
+// The MinIO client fetches listPageSize keys per request and follows the continuation
+// token itself; an error on any page fails the call.
+func (c *Client) ListObjects(ctx context.Context, prefix string) ([]repository.ObjectInfo, error) {
+	// Cancelling stops the listing goroutine if the loop returns early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objects := []repository.ObjectInfo{}
+	for info := range c.client.ListObjects(ctx, c.bucket, listOptions(prefix)) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", classify(info.Err))
+		}
+		objects = append(objects, repository.ObjectInfo{
+			Key:          info.Key,
+			Size:         info.Size,
+			ContentType:  info.ContentType,
+			LastModified: info.LastModified,
+			ETag:         info.ETag,
+		})
+	}
+	return objects, nil
+}
+
+// DeleteByPrefix removes every object under prefix without holding the listing in memory:
+// listed pages are streamed into multi-object deletes, which MinIO sends in batches of up
+// to 1000 keys. A listing error stops the deletion; objects already removed stay removed,
+// so the call can simply be repeated.
+func (c *Client) DeleteByPrefix(ctx context.Context, prefix string) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		listErr error
+		listed  int
+	)
+	toRemove := make(chan minio.ObjectInfo)
+	listDone := make(chan struct{})
+	go func() {
+		defer close(listDone)
+		defer close(toRemove)
+		for info := range c.client.ListObjects(ctx, c.bucket, listOptions(prefix)) {
+			if info.Err != nil {
+				listErr = info.Err
+				return
+			}
+			select {
+			case toRemove <- info:
+				listed++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		removeErr error
+		failed    int
+	)
+	for rErr := range c.client.RemoveObjects(ctx, c.bucket, toRemove, minio.RemoveObjectsOptions{}) {
+		failed++
+		if removeErr == nil {
+			removeErr = fmt.Errorf("failed to delete object %s: %w", rErr.ObjectName, classify(rErr.Err))
+		}
+	}
+	// RemoveObjects may give up before draining toRemove; stop the listing before reading its results
+	cancel()
+	<-listDone
+
+	deleted := listed - failed
+	if listErr != nil {
+		return deleted, fmt.Errorf("failed to list objects: %w", classify(listErr))
+	}
+	if removeErr != nil {
+		return deleted, removeErr
+	}
+	return deleted, nil
+}
+
+// listOptions lists every object under prefix in pages of listPageSize.
+func listOptions(prefix string) minio.ListObjectsOptions {
+	return minio.ListObjectsOptions{Prefix: prefix, Recursive: true, MaxKeys: listPageSize}
+}
+
+// CORSRule is one cross-origin rule of the bucket.
+type CORSRule struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+}
+
+// BucketCORS returns the bucket's CORS rules, which browsers need to upload to presigned URLs.
+// Returns an empty slice if the bucket has no CORS configuration.
+func (c *Client) BucketCORS(ctx context.Context) ([]CORSRule, error) {
+	config, err := c.client.GetBucketCors(ctx, c.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket CORS: %w", classify(err))
+	}
+
+	rules := []CORSRule{}
+	if config == nil {
+		return rules, nil
+	}
+	for _, rule := range config.CORSRules {
+		rules = append(rules, CORSRule{
+			AllowedOrigins: rule.AllowedOrigin,
+			AllowedMethods: rule.AllowedMethod,
+		})
+	}
+	return rules, nil
+}
+
 // Ping verifies the MinIO connection is alive by checking bucket access.
 func (c *Client) Ping(ctx context.Context) error {
 	_, err := c.client.BucketExists(ctx, c.bucket)
 	if err != nil {
-		return fmt.Errorf("failed to ping minio: %w", err)
+		return fmt.Errorf("failed to ping minio: %w", classify(err))
 	}
 	return nil
 }
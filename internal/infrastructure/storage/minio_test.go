@@ -5,12 +5,16 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net/http"
 	"net/url"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/cors"
 
 	"github.com/hszk-dev/gostream/internal/domain/repository"
 )
@@ -59,6 +63,12 @@ type mockMinioClient struct {
 	getObjectFunc          func(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error)
 	removeObjectFunc       func(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error
 	statObjectFunc         func(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error)
+	listObjectsFunc        func(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo
+	removeObjectsFunc      func(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError
+	getBucketCorsFunc      func(ctx context.Context, bucketName string) (*cors.Config, error)
+	newMultipartUploadFunc func(ctx context.Context, bucketName, objectName string, opts minio.PutObjectOptions) (string, error)
+	presignFunc            func(ctx context.Context, method, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error)
+	completeMultipartFunc  func(ctx context.Context, bucketName, objectName, uploadID string, parts []minio.CompletePart, opts minio.PutObjectOptions) (minio.UploadInfo, error)
 }
 
 func (m *mockMinioClient) BucketExists(ctx context.Context, bucketName string) (bool, error) {
@@ -110,6 +120,56 @@ func (m *mockMinioClient) StatObject(ctx context.Context, bucketName, objectName
 	return minio.ObjectInfo{}, nil
 }
 
+func (m *mockMinioClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	if m.listObjectsFunc != nil {
+		return m.listObjectsFunc(ctx, bucketName, opts)
+	}
+	ch := make(chan minio.ObjectInfo)
+	close(ch)
+	return ch
+}
+
+func (m *mockMinioClient) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	if m.removeObjectsFunc != nil {
+		return m.removeObjectsFunc(ctx, bucketName, objectsCh, opts)
+	}
+	errCh := make(chan minio.RemoveObjectError)
+	go func() {
+		defer close(errCh)
+		for range objectsCh {
+		}
+	}()
+	return errCh
+}
+
+func (m *mockMinioClient) GetBucketCors(ctx context.Context, bucketName string) (*cors.Config, error) {
+	if m.getBucketCorsFunc != nil {
+		return m.getBucketCorsFunc(ctx, bucketName)
+	}
+	return nil, nil
+}
+
+func (m *mockMinioClient) NewMultipartUpload(ctx context.Context, bucketName, objectName string, opts minio.PutObjectOptions) (string, error) {
+	if m.newMultipartUploadFunc != nil {
+		return m.newMultipartUploadFunc(ctx, bucketName, objectName, opts)
+	}
+	return "", nil
+}
+
+func (m *mockMinioClient) Presign(ctx context.Context, method, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error) {
+	if m.presignFunc != nil {
+		return m.presignFunc(ctx, method, bucketName, objectName, expiry, reqParams)
+	}
+	return &url.URL{}, nil
+}
+
+func (m *mockMinioClient) CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []minio.CompletePart, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	if m.completeMultipartFunc != nil {
+		return m.completeMultipartFunc(ctx, bucketName, objectName, uploadID, parts, opts)
+	}
+	return minio.UploadInfo{}, nil
+}
+
 func TestNewClientWithMinioClient(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -484,6 +544,221 @@ func TestClient_Download(t *testing.T) {
 	}
 }
 
+func TestClient_DownloadRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		etag        string
+		offset      int64
+		length      int64
+		mockClient  *mockMinioClient
+		wantRange   string
+		wantContent string
+		wantErr     error
+	}{
+		{
+			name:   "successful range download",
+			offset: 10,
+			length: 5,
+			mockClient: &mockMinioClient{
+				getObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+					if got := opts.Header().Get("Range"); got != "bytes=10-14" {
+						return nil, errors.New("unexpected range header: " + got)
+					}
+					if got := opts.Header().Get("If-Match"); got != "" {
+						return nil, errors.New("unexpected If-Match header: " + got)
+					}
+					return &mockObjectReader{data: []byte("chunk")}, nil
+				},
+			},
+			wantContent: "chunk",
+		},
+		{
+			name:   "pinned to etag",
+			etag:   `"abc123"`,
+			offset: 0,
+			length: 5,
+			mockClient: &mockMinioClient{
+				getObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+					if got := opts.Header().Get("If-Match"); got != `"abc123"` {
+						return nil, errors.New("unexpected If-Match header: " + got)
+					}
+					return &mockObjectReader{data: []byte("chunk")}, nil
+				},
+			},
+			wantContent: "chunk",
+		},
+		{
+			name:   "object replaced",
+			etag:   "abc123",
+			offset: 0,
+			length: 5,
+			mockClient: &mockMinioClient{
+				getObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+					return &mockObjectReader{
+						statFunc: func() (minio.ObjectInfo, error) {
+							return minio.ObjectInfo{}, minio.ErrorResponse{Code: "PreconditionFailed", StatusCode: 412}
+						},
+					}, nil
+				},
+			},
+			wantErr: repository.ErrObjectChanged,
+		},
+		{
+			name:    "invalid range",
+			offset:  0,
+			length:  0,
+			wantErr: errors.New("invalid range"),
+		},
+		{
+			name:   "object not found",
+			offset: 0,
+			length: 5,
+			mockClient: &mockMinioClient{
+				getObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+					return &mockObjectReader{
+						statFunc: func() (minio.ObjectInfo, error) {
+							return minio.ObjectInfo{}, minio.ErrorResponse{Code: "NoSuchKey"}
+						},
+					}, nil
+				},
+			},
+			wantErr: repository.ErrObjectNotFound,
+		},
+		{
+			name:   "get object error",
+			offset: 0,
+			length: 5,
+			mockClient: &mockMinioClient{
+				getObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (objectReader, error) {
+					return nil, errors.New("connection refused")
+				},
+			},
+			wantErr: errors.New("failed to get object range"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				client: tt.mockClient,
+				bucket: "videos",
+			}
+
+			reader, err := client.DownloadRange(context.Background(), "originals/video-123/original.mp4", tt.etag, tt.offset, tt.length)
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Errorf("DownloadRange() expected error, got nil")
+					return
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("DownloadRange() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("DownloadRange() unexpected error = %v", err)
+				return
+			}
+
+			defer reader.Close()
+
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				t.Errorf("failed to read content: %v", err)
+				return
+			}
+
+			if string(content) != tt.wantContent {
+				t.Errorf("DownloadRange() content = %v, want %v", string(content), tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestClient_Stat(t *testing.T) {
+	modified := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		mockClient *mockMinioClient
+		want       *repository.ObjectInfo
+		wantErr    error
+	}{
+		{
+			name: "object exists",
+			mockClient: &mockMinioClient{
+				statObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+					return minio.ObjectInfo{
+						Key:          objectName,
+						Size:         1024,
+						ContentType:  "video/mp4",
+						LastModified: modified,
+						ETag:         "d41d8cd98f00b204e9800998ecf8427e",
+					}, nil
+				},
+			},
+			want: &repository.ObjectInfo{
+				Key:          "originals/video-123/original.mp4",
+				Size:         1024,
+				ContentType:  "video/mp4",
+				LastModified: modified,
+				ETag:         "d41d8cd98f00b204e9800998ecf8427e",
+			},
+		},
+		{
+			name: "object not found",
+			mockClient: &mockMinioClient{
+				statObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+					return minio.ObjectInfo{}, minio.ErrorResponse{Code: "NoSuchKey"}
+				},
+			},
+			wantErr: repository.ErrObjectNotFound,
+		},
+		{
+			name: "stat error",
+			mockClient: &mockMinioClient{
+				statObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+					return minio.ObjectInfo{}, errors.New("connection error")
+				},
+			},
+			wantErr: errors.New("failed to stat object"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				client: tt.mockClient,
+				bucket: "videos",
+			}
+
+			got, err := client.Stat(context.Background(), "originals/video-123/original.mp4")
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Errorf("Stat() expected error, got nil")
+					return
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("Stat() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Stat() unexpected error = %v", err)
+				return
+			}
+
+			if *got != *tt.want {
+				t.Errorf("Stat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestClient_Delete(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -529,6 +804,227 @@ func TestClient_Delete(t *testing.T) {
 	}
 }
 
+func TestClient_MultipartUpload(t *testing.T) {
+	internalMock := &mockMinioClient{
+		newMultipartUploadFunc: func(ctx context.Context, bucketName, objectName string, opts minio.PutObjectOptions) (string, error) {
+			if opts.ContentType != "video/mp4" {
+				t.Errorf("content type = %q, want video/mp4", opts.ContentType)
+			}
+			return "upload-1", nil
+		},
+		completeMultipartFunc: func(ctx context.Context, bucketName, objectName, uploadID string, parts []minio.CompletePart, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+			if uploadID != "upload-1" || len(parts) != 2 || parts[1].PartNumber != 2 || parts[1].ETag != "etag-2" {
+				t.Errorf("completed upload %q with parts %+v", uploadID, parts)
+			}
+			return minio.UploadInfo{}, nil
+		},
+	}
+	// Part URLs are signed for the public endpoint like single uploads
+	publicMock := &mockMinioClient{
+		presignFunc: func(ctx context.Context, method, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error) {
+			if method != http.MethodPut || reqParams.Get("partNumber") != "2" || reqParams.Get("uploadId") != "upload-1" {
+				t.Errorf("presigned %s with %v", method, reqParams)
+			}
+			return url.Parse("http://localhost:9000/videos/" + objectName + "?" + reqParams.Encode())
+		},
+	}
+	client := &Client{client: internalMock, presignedClient: publicMock, bucket: "videos"}
+	ctx := context.Background()
+	key := "uploads/video-123/original.mp4"
+
+	uploadID, err := client.InitiateMultipartUpload(ctx, key, "video/mp4")
+	if err != nil || uploadID != "upload-1" {
+		t.Fatalf("InitiateMultipartUpload() = %q, %v", uploadID, err)
+	}
+	partURL, err := client.PresignPart(ctx, key, uploadID, 2, time.Hour)
+	if err != nil || !strings.HasPrefix(partURL, "http://localhost:9000/videos/") {
+		t.Fatalf("PresignPart() = %q, %v", partURL, err)
+	}
+	parts := []repository.CompletedPart{{PartNumber: 1, ETag: "etag-1"}, {PartNumber: 2, ETag: "etag-2"}}
+	if err := client.CompleteMultipartUpload(ctx, key, uploadID, parts); err != nil {
+		t.Fatalf("CompleteMultipartUpload() error = %v", err)
+	}
+
+	internalMock.completeMultipartFunc = func(ctx context.Context, bucketName, objectName, uploadID string, parts []minio.CompletePart, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+		return minio.UploadInfo{}, minio.ErrorResponse{Code: "NoSuchUpload", StatusCode: http.StatusNotFound}
+	}
+	if err := client.CompleteMultipartUpload(ctx, key, "gone", parts); !errors.Is(err, repository.ErrMultipartUploadNotFound) {
+		t.Errorf("CompleteMultipartUpload() error = %v, want %v", err, repository.ErrMultipartUploadNotFound)
+	}
+}
+
+func TestClient_ListObjects(t *testing.T) {
+	listed := func(infos ...minio.ObjectInfo) func(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+		return func(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+			if opts.Prefix != "hls/video-123/" || !opts.Recursive || opts.MaxKeys != listPageSize {
+				t.Errorf("ListObjects options = %+v, want recursive paged listing of the prefix", opts)
+			}
+			ch := make(chan minio.ObjectInfo, len(infos))
+			for _, info := range infos {
+				ch <- info
+			}
+			close(ch)
+			return ch
+		}
+	}
+
+	tests := []struct {
+		name       string
+		mockClient *mockMinioClient
+		wantKeys   []string
+		wantErr    bool
+	}{
+		{
+			name: "objects under prefix",
+			mockClient: &mockMinioClient{listObjectsFunc: listed(
+				minio.ObjectInfo{Key: "hls/video-123/v1/master.m3u8", Size: 512},
+				minio.ObjectInfo{Key: "hls/video-123/v1/720p/segment_000.ts", Size: 4096},
+			)},
+			wantKeys: []string{"hls/video-123/v1/master.m3u8", "hls/video-123/v1/720p/segment_000.ts"},
+		},
+		{
+			name:       "empty prefix listing",
+			mockClient: &mockMinioClient{listObjectsFunc: listed()},
+			wantKeys:   []string{},
+		},
+		{
+			name: "listing error",
+			mockClient: &mockMinioClient{listObjectsFunc: listed(
+				minio.ObjectInfo{Key: "hls/video-123/v1/master.m3u8"},
+				minio.ObjectInfo{Err: errors.New("access denied")},
+			)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				client: tt.mockClient,
+				bucket: "videos",
+			}
+
+			objects, err := client.ListObjects(context.Background(), "hls/video-123/")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListObjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			keys := make([]string, len(objects))
+			for i, o := range objects {
+				keys[i] = o.Key
+			}
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("ListObjects() keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteByPrefix(t *testing.T) {
+	listed := func(infos ...minio.ObjectInfo) func(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+		return func(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+			if opts.Prefix != "hls/video-123/" || !opts.Recursive || opts.MaxKeys != listPageSize {
+				t.Errorf("ListObjects options = %+v, want recursive paged listing of the prefix", opts)
+			}
+			ch := make(chan minio.ObjectInfo, len(infos))
+			for _, info := range infos {
+				ch <- info
+			}
+			close(ch)
+			return ch
+		}
+	}
+
+	var removed []string
+	removeAll := func(failKey string) func(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+		return func(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+			errCh := make(chan minio.RemoveObjectError, 1)
+			go func() {
+				defer close(errCh)
+				for info := range objectsCh {
+					if info.Key == failKey {
+						errCh <- minio.RemoveObjectError{ObjectName: info.Key, Err: errors.New("access denied")}
+						continue
+					}
+					removed = append(removed, info.Key)
+				}
+			}()
+			return errCh
+		}
+	}
+
+	tests := []struct {
+		name        string
+		list        []minio.ObjectInfo
+		failKey     string
+		wantRemoved []string
+		wantDeleted int
+		wantErr     bool
+	}{
+		{
+			name: "removes every object",
+			list: []minio.ObjectInfo{
+				{Key: "hls/video-123/v1/master.m3u8"},
+				{Key: "hls/video-123/v1/720p/segment_000.ts"},
+			},
+			wantRemoved: []string{"hls/video-123/v1/master.m3u8", "hls/video-123/v1/720p/segment_000.ts"},
+			wantDeleted: 2,
+		},
+		{
+			name: "empty prefix",
+		},
+		{
+			name: "listing error stops deletion",
+			list: []minio.ObjectInfo{
+				{Key: "hls/video-123/v1/master.m3u8"},
+				{Err: errors.New("access denied")},
+				{Key: "hls/video-123/v1/720p/segment_000.ts"},
+			},
+			wantRemoved: []string{"hls/video-123/v1/master.m3u8"},
+			wantDeleted: 1,
+			wantErr:     true,
+		},
+		{
+			name: "remove failure",
+			list: []minio.ObjectInfo{
+				{Key: "hls/video-123/v1/master.m3u8"},
+				{Key: "hls/video-123/v1/720p/segment_000.ts"},
+			},
+			failKey:     "hls/video-123/v1/master.m3u8",
+			wantRemoved: []string{"hls/video-123/v1/720p/segment_000.ts"},
+			wantDeleted: 1,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed = nil
+			client := &Client{
+				client: &mockMinioClient{
+					listObjectsFunc:   listed(tt.list...),
+					removeObjectsFunc: removeAll(tt.failKey),
+				},
+				bucket: "videos",
+			}
+
+			deleted, err := client.DeleteByPrefix(context.Background(), "hls/video-123/")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteByPrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("DeleteByPrefix() = %d, want %d", deleted, tt.wantDeleted)
+			}
+			if !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
 func TestClient_Exists(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -593,6 +1089,59 @@ func TestClient_Exists(t *testing.T) {
 	}
 }
 
+func TestClient_BucketCORS(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *cors.Config
+		err       error
+		wantRules []CORSRule
+		wantErr   bool
+	}{
+		{
+			name: "rules",
+			config: &cors.Config{CORSRules: []cors.Rule{
+				{AllowedOrigin: []string{"https://app.example.com"}, AllowedMethod: []string{"GET", "PUT"}},
+			}},
+			wantRules: []CORSRule{
+				{AllowedOrigins: []string{"https://app.example.com"}, AllowedMethods: []string{"GET", "PUT"}},
+			},
+		},
+		{
+			name:      "no configuration",
+			wantRules: []CORSRule{},
+		},
+		{
+			name:    "request error",
+			err:     errors.New("access denied"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				client: &mockMinioClient{
+					getBucketCorsFunc: func(ctx context.Context, bucketName string) (*cors.Config, error) {
+						if bucketName != "videos" {
+							t.Errorf("bucket = %q, want videos", bucketName)
+						}
+						return tt.config, tt.err
+					},
+				},
+				bucket: "videos",
+			}
+
+			rules, err := client.BucketCORS(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BucketCORS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("BucketCORS() = %+v, want %+v", rules, tt.wantRules)
+			}
+		})
+	}
+}
+
 func TestClient_Ping(t *testing.T) {
 	tests := []struct {
 		name       string
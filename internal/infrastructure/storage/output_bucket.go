@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// OutputBucketConnector opens clients for the buckets tenants bring for their
+// transcode output. It implements usecase.OutputBucketConnector.
+type OutputBucketConnector struct{}
+
+// NewOutputBucketConnector creates a new OutputBucketConnector.
+func NewOutputBucketConnector() *OutputBucketConnector {
+	return &OutputBucketConnector{}
+}
+
+// Connect creates a client for bucket authenticated with secretKey.
+// Like NewClient, it fails if the bucket does not exist or the credentials cannot see it.
+func (c *OutputBucketConnector) Connect(ctx context.Context, bucket *model.OutputBucket, secretKey []byte) (repository.ObjectStorage, error) {
+	return NewClient(ctx, ClientConfig{
+		Endpoint:  bucket.Endpoint,
+		AccessKey: bucket.AccessKeyID,
+		SecretKey: string(secretKey),
+		Bucket:    bucket.Bucket,
+		UseSSL:    bucket.UseSSL,
+		Region:    bucket.Region,
+	})
+}
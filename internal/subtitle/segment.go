@@ -0,0 +1,88 @@
+package subtitle
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/hls"
+)
+
+// DefaultSegmentDuration matches the transcoder's default HLS segment duration, so
+// subtitle segments line up with the media segments.
+const DefaultSegmentDuration = 6 * time.Second
+
+// timestampMap maps WebVTT time zero to the first MPEG-TS timestamp of the media
+// segments. FFmpeg's MPEG-TS muxer starts at 1.4s (126000 in the 90kHz clock); without
+// the mapping players would show every cue 1.4s early.
+const timestampMap = "X-TIMESTAMP-MAP=MPEGTS:126000,LOCAL:00:00:00.000"
+
+// Segment is one WebVTT file of a segmented subtitle track.
+type Segment struct {
+	// Name is the file name, e.g. "segment_000.vtt".
+	Name     string
+	Duration time.Duration
+	Data     []byte
+}
+
+// Split cuts cues into WebVTT segments of segmentDuration covering [0, total). A cue
+// that spans a segment boundary is repeated in every segment it overlaps, as RFC 8216
+// requires. Cues past total are dropped; a non-positive total covers every cue.
+func Split(cues []Cue, segmentDuration, total time.Duration) []Segment {
+	if segmentDuration <= 0 {
+		segmentDuration = DefaultSegmentDuration
+	}
+	if total <= 0 {
+		for _, cue := range cues {
+			total = max(total, cue.End)
+		}
+	}
+
+	n := int(math.Ceil(float64(total) / float64(segmentDuration)))
+	segments := make([]Segment, 0, n)
+	for i := range n {
+		start := time.Duration(i) * segmentDuration
+		end := min(start+segmentDuration, total)
+
+		var sb strings.Builder
+		sb.WriteString("WEBVTT\n")
+		sb.WriteString(timestampMap + "\n")
+		for _, cue := range cues {
+			if cue.End <= start || cue.Start >= end {
+				continue
+			}
+			sb.WriteString("\n" + FormatTimestamp(cue.Start) + " --> " + FormatTimestamp(cue.End))
+			if cue.Settings != "" {
+				sb.WriteString(" " + cue.Settings)
+			}
+			sb.WriteString("\n" + cue.Text + "\n")
+		}
+
+		segments = append(segments, Segment{
+			Name:     fmt.Sprintf("segment_%03d.vtt", i),
+			Duration: end - start,
+			Data:     []byte(sb.String()),
+		})
+	}
+	return segments
+}
+
+// MediaPlaylist returns the VOD media playlist of a subtitle track, listing segments by
+// name relative to the playlist.
+func MediaPlaylist(segments []Segment) []byte {
+	var target time.Duration
+	for _, s := range segments {
+		target = max(target, s.Duration)
+	}
+
+	p := hls.New(3)
+	p.AddTag(hls.Tag{Name: hls.TagTargetDuration, Value: fmt.Sprintf("%d", int(math.Ceil(target.Seconds())))})
+	p.AddTag(hls.Tag{Name: "EXT-X-MEDIA-SEQUENCE", Value: "0"})
+	p.AddTag(hls.Tag{Name: "EXT-X-PLAYLIST-TYPE", Value: "VOD"})
+	for _, s := range segments {
+		p.AddSegment(hls.Segment{Duration: s.Duration.Seconds(), URI: s.Name})
+	}
+	p.AddTag(hls.Tag{Name: hls.TagEndList})
+	return p.Encode()
+}
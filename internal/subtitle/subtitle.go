@@ -0,0 +1,159 @@
+// Package subtitle parses SRT and WebVTT subtitle files and splits them into the
+// segmented WebVTT that HLS subtitle renditions are made of (RFC 8216 section 3.5).
+package subtitle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is the file format of an uploaded subtitle.
+type Format string
+
+const (
+	FormatSRT    Format = "srt"
+	FormatWebVTT Format = "vtt"
+)
+
+// ParseFormat parses a format name; "webvtt" is accepted for FormatWebVTT.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "srt":
+		return FormatSRT, nil
+	case "vtt", "webvtt":
+		return FormatWebVTT, nil
+	default:
+		return "", fmt.Errorf("unknown subtitle format %q", s)
+	}
+}
+
+// ErrNoCues is returned by Parse when a file holds no cue.
+var ErrNoCues = errors.New("subtitle file has no cues")
+
+// Cue is one timed piece of subtitle text.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	// Settings are the WebVTT cue settings after the timing (e.g., "line:0 align:start");
+	// SRT cues have none.
+	Settings string
+	// Text is the cue payload, lines separated by LF.
+	Text string
+}
+
+// Parse parses a subtitle file. Line endings may be LF or CRLF and a UTF-8 byte order
+// mark is ignored. WebVTT NOTE, STYLE and REGION blocks are dropped.
+// Returns ErrNoCues if the file holds no cue.
+func Parse(data []byte, format Format) ([]Cue, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	blocks := strings.Split(strings.TrimSpace(text), "\n\n")
+
+	if format == FormatWebVTT {
+		if !strings.HasPrefix(blocks[0], "WEBVTT") {
+			return nil, errors.New("missing WEBVTT header")
+		}
+		blocks = blocks[1:]
+	}
+
+	var cues []Cue
+	for _, block := range blocks {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		if format == FormatWebVTT && isMetadataBlock(lines[0]) {
+			continue
+		}
+
+		// The timing line follows an optional identifier (a counter in SRT)
+		timing := 0
+		if !strings.Contains(lines[0], "-->") {
+			timing = 1
+		}
+		if timing >= len(lines) || !strings.Contains(lines[timing], "-->") {
+			if strings.TrimSpace(block) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("cue %d: missing timing line", len(cues)+1)
+		}
+
+		cue, err := parseTiming(lines[timing])
+		if err != nil {
+			return nil, fmt.Errorf("cue %d: %w", len(cues)+1, err)
+		}
+		cue.Text = strings.Join(lines[timing+1:], "\n")
+		cues = append(cues, cue)
+	}
+
+	if len(cues) == 0 {
+		return nil, ErrNoCues
+	}
+	return cues, nil
+}
+
+func isMetadataBlock(first string) bool {
+	for _, kind := range []string{"NOTE", "STYLE", "REGION"} {
+		if first == kind || strings.HasPrefix(first, kind+" ") || strings.HasPrefix(first, kind+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTiming parses "start --> end [settings]".
+func parseTiming(line string) (Cue, error) {
+	startText, rest, _ := strings.Cut(line, "-->")
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return Cue{}, fmt.Errorf("invalid timing %q", line)
+	}
+
+	start, err := parseTimestamp(strings.TrimSpace(startText))
+	if err != nil {
+		return Cue{}, err
+	}
+	end, err := parseTimestamp(fields[0])
+	if err != nil {
+		return Cue{}, err
+	}
+	if end < start {
+		return Cue{}, fmt.Errorf("cue ends before it starts: %q", line)
+	}
+	return Cue{Start: start, End: end, Settings: strings.Join(fields[1:], " ")}, nil
+}
+
+// parseTimestamp parses "[hh:]mm:ss.ttt", also accepting the comma SRT uses before the
+// milliseconds.
+func parseTimestamp(s string) (time.Duration, error) {
+	clock, millis, ok := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	if !ok || len(millis) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	var d time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}[3-len(parts):]
+	for i, part := range append(parts, millis) {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		if i == len(parts) {
+			d += time.Duration(n) * time.Millisecond
+		} else {
+			d += time.Duration(n) * units[i]
+		}
+	}
+	return d, nil
+}
+
+// FormatTimestamp formats d as a WebVTT timestamp, "hh:mm:ss.ttt".
+func FormatTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,122 @@
+package subtitle
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  Format
+		want    []Cue
+		wantErr error
+	}{
+		{
+			name:   "srt",
+			format: FormatSRT,
+			data:   "\ufeff1\r\n00:00:01,000 --> 00:00:04,500\r\nHello\r\nworld\r\n\r\n2\r\n00:01:02,250 --> 00:01:03,000\r\n<i>Bye</i>\r\n",
+			want: []Cue{
+				{Start: time.Second, End: 4500 * time.Millisecond, Text: "Hello\nworld"},
+				{Start: 62250 * time.Millisecond, End: 63 * time.Second, Text: "<i>Bye</i>"},
+			},
+		},
+		{
+			name:   "webvtt",
+			format: FormatWebVTT,
+			data: "WEBVTT - English\n\nNOTE made by hand\n\nSTYLE\n::cue { color: yellow }\n\n" +
+				"intro\n00:01.000 --> 00:02.000 line:0 align:start\nHi\n\n01:00:00.000 --> 01:00:01.000\nLate\n",
+			want: []Cue{
+				{Start: time.Second, End: 2 * time.Second, Settings: "line:0 align:start", Text: "Hi"},
+				{Start: time.Hour, End: time.Hour + time.Second, Text: "Late"},
+			},
+		},
+		{
+			name:    "webvtt without header",
+			format:  FormatWebVTT,
+			data:    "00:01.000 --> 00:02.000\nHi\n",
+			wantErr: errors.New("missing WEBVTT header"),
+		},
+		{
+			name:    "invalid timestamp",
+			format:  FormatSRT,
+			data:    "1\n00:00:01 --> 00:00:02,000\nHi\n",
+			wantErr: errors.New(`cue 1: invalid timestamp "00:00:01"`),
+		},
+		{
+			name:    "ends before start",
+			format:  FormatSRT,
+			data:    "1\n00:00:03,000 --> 00:00:02,000\nHi\n",
+			wantErr: errors.New(`cue 1: cue ends before it starts: "00:00:03,000 --> 00:00:02,000"`),
+		},
+		{
+			name:    "no cues",
+			format:  FormatWebVTT,
+			data:    "WEBVTT\n\nNOTE nothing yet\n",
+			wantErr: ErrNoCues,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data), tt.format)
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	cues := []Cue{
+		{Start: time.Second, End: 2 * time.Second, Text: "first"},
+		{Start: 5 * time.Second, End: 7 * time.Second, Settings: "align:start", Text: "spans"},
+		{Start: 20 * time.Second, End: 21 * time.Second, Text: "past the end"},
+	}
+
+	segments := Split(cues, 6*time.Second, 10*time.Second)
+	if len(segments) != 2 {
+		t.Fatalf("Split() returned %d segments, want 2", len(segments))
+	}
+
+	want := "WEBVTT\nX-TIMESTAMP-MAP=MPEGTS:126000,LOCAL:00:00:00.000\n\n" +
+		"00:00:01.000 --> 00:00:02.000\nfirst\n\n" +
+		"00:00:05.000 --> 00:00:07.000 align:start\nspans\n"
+	if got := string(segments[0].Data); got != want {
+		t.Errorf("segment 0 = %q, want %q", got, want)
+	}
+	if got := string(segments[1].Data); !strings.Contains(got, "spans") || strings.Contains(got, "first") || strings.Contains(got, "past the end") {
+		t.Errorf("segment 1 = %q, want only the spanning cue", got)
+	}
+	if segments[1].Name != "segment_001.vtt" || segments[1].Duration != 4*time.Second {
+		t.Errorf("segment 1 = %s (%v), want segment_001.vtt (4s)", segments[1].Name, segments[1].Duration)
+	}
+
+	// Without a known duration the track ends with its last cue
+	if got := len(Split(cues, 6*time.Second, 0)); got != 4 {
+		t.Errorf("Split() without total returned %d segments, want 4", got)
+	}
+}
+
+func TestMediaPlaylist(t *testing.T) {
+	segments := Split([]Cue{{Start: 0, End: time.Second, Text: "hi"}}, 6*time.Second, 8500*time.Millisecond)
+
+	want := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXT-X-MEDIA-SEQUENCE:0\n#EXT-X-PLAYLIST-TYPE:VOD\n" +
+		"#EXTINF:6.000,\nsegment_000.vtt\n#EXTINF:2.500,\nsegment_001.vtt\n#EXT-X-ENDLIST\n"
+	if got := string(MediaPlaylist(segments)); got != want {
+		t.Errorf("MediaPlaylist() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package transcoder
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DASHManifestName is the file name of the MPD written by TranscodeToDASH.
+const DASHManifestName = "manifest.mpd"
+
+// DASH segment names use the muxer's template identifiers. They are distinct from the HLS
+// file names, so both formats can share one output directory.
+const (
+	dashInitSegmentName  = "init-$RepresentationID$.m4s"
+	dashMediaSegmentName = "chunk-$RepresentationID$-$Number%05d$.m4s"
+)
+
+// DASHOutput contains the result of an MPEG-DASH transcoding operation.
+type DASHOutput struct {
+	// ManifestPath is the path to the generated .mpd manifest file.
+	ManifestPath string
+	// SegmentPaths contains paths to all fMP4 initialization and media segments.
+	SegmentPaths []string
+	// Variants groups the video segments by representation. ManifestPath is empty, since
+	// every representation is described by the single MPD. Audio segments appear only in
+	// SegmentPaths.
+	Variants []VariantOutput
+}
+
+// TranscodeToDASH encodes every variant in a single FFmpeg process: the input is decoded
+// once, split and scaled per representation, and muxed into one MPD with fMP4 segments.
+// Progress is reported under the variant name "dash".
+func (t *FFmpegTranscoder) TranscodeToDASH(ctx context.Context, inputPath, outputDir string, variants []Variant, progress ProgressFunc) (*DASHOutput, error) {
+	if err := t.validateInput(inputPath); err != nil {
+		return nil, err
+	}
+
+	if err := t.validateOutputDir(outputDir); err != nil {
+		return nil, err
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("at least one variant is required")
+	}
+
+	manifestPath := filepath.Join(outputDir, DASHManifestName)
+	args := t.buildDASHFFmpegArgs(inputPath, manifestPath, variants)
+	if progress != nil {
+		args = append(slices.Clone(progressArgs), args...)
+	}
+
+	cmd := command(ctx, t.config.FFmpegPath, args...)
+	cmd.Stdout = nil
+	stderr, logged := captureStderr(ctx, cmd, "dash")
+
+	var err error
+	if progress != nil {
+		err = runWithProgress(cmd, func(encoded time.Duration) { progress("dash", encoded) })
+	} else {
+		err = cmd.Run()
+	}
+	logged(err)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("transcoding cancelled: %w", ctx.Err())
+		}
+		return nil, execError(err, stderr)
+	}
+
+	segments, err := collectDASHSegments(outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("collect segments: %w", err)
+	}
+
+	return &DASHOutput{
+		ManifestPath: manifestPath,
+		SegmentPaths: segments,
+		Variants:     t.groupDASHSegments(segments, variants),
+	}, nil
+}
+
+// buildDASHFFmpegArgs constructs FFmpeg arguments for a multi-representation DASH encode.
+// Audio is mapped optionally, so silent inputs produce a video-only MPD. Segments use the
+// HLS segment duration, so both formats switch bitrates at the same points.
+func (t *FFmpegTranscoder) buildDASHFFmpegArgs(inputPath, manifestPath string, variants []Variant) []string {
+	var filter strings.Builder
+	fmt.Fprintf(&filter, "[0:v]split=%d", len(variants))
+	for i := range variants {
+		fmt.Fprintf(&filter, "[s%d]", i)
+	}
+	for i, v := range variants {
+		fmt.Fprintf(&filter, ";[s%d]scale=-2:%d[v%d]", i, v.Height, i)
+	}
+
+	args := []string{
+		"-i", inputPath,
+		"-filter_complex", filter.String(),
+	}
+	for i := range variants {
+		args = append(args, "-map", fmt.Sprintf("[v%d]", i))
+	}
+	args = append(args, "-map", "0:a?")
+
+	args = append(args,
+		"-c:v", t.config.VideoCodec,
+		"-preset", t.config.VideoPreset,
+	)
+	for i, v := range variants {
+		args = append(args, fmt.Sprintf("-b:v:%d", i), strconv.Itoa(v.Bitrate))
+	}
+
+	// The audio is encoded once and shared by every representation
+	args = append(args, "-c:a", t.config.AudioCodec)
+	if audioBitrate := maxAudioBitrate(variants); audioBitrate > 0 {
+		args = append(args, "-b:a", strconv.Itoa(audioBitrate))
+	}
+
+	return append(args,
+		"-f", "dash",
+		"-seg_duration", strconv.Itoa(t.config.HLSSegmentDuration),
+		"-use_template", "1",
+		"-use_timeline", "1",
+		"-init_seg_name", dashInitSegmentName,
+		"-media_seg_name", dashMediaSegmentName,
+		"-y",
+		manifestPath,
+	)
+}
+
+// maxAudioBitrate returns the highest audio bitrate of variants; zero keeps the encoder's default.
+func maxAudioBitrate(variants []Variant) int {
+	bitrate := 0
+	for _, v := range variants {
+		bitrate = max(bitrate, v.AudioBitrate)
+	}
+	return bitrate
+}
+
+// groupDASHSegments assigns segments to variants by the representation ID in their file
+// name. Video representations are numbered in variant order; the audio representation
+// follows them and is not assigned.
+func (t *FFmpegTranscoder) groupDASHSegments(segments []string, variants []Variant) []VariantOutput {
+	outputs := make([]VariantOutput, len(variants))
+	for i, v := range variants {
+		outputs[i] = VariantOutput{Variant: v, Codec: t.config.VideoCodec}
+	}
+
+	for _, segment := range segments {
+		name := strings.TrimSuffix(filepath.Base(segment), ".m4s")
+		if rest, ok := strings.CutPrefix(name, "init-"); ok {
+			name = rest
+		} else if rest, ok := strings.CutPrefix(name, "chunk-"); ok {
+			name, _, _ = strings.Cut(rest, "-")
+		}
+		id, err := strconv.Atoi(name)
+		if err != nil || id < 0 || id >= len(outputs) {
+			continue
+		}
+		outputs[id].SegmentPaths = append(outputs[id].SegmentPaths, segment)
+	}
+
+	return outputs
+}
+
+// collectDASHSegments finds all generated .m4s segment files in the output directory.
+func collectDASHSegments(outputDir string) ([]string, error) {
+	segments, err := filepath.Glob(filepath.Join(outputDir, "*.m4s"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list output directory: %w", err)
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no segments generated in output directory")
+	}
+
+	return segments, nil
+}
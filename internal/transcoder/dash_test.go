@@ -0,0 +1,161 @@
+package transcoder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFFmpegTranscoder_BuildDASHFFmpegArgs(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+
+	args := transcoder.buildDASHFFmpegArgs("/input/video.mp4", "/output/manifest.mpd", []Variant{
+		{Name: "720p", Height: 720, Bitrate: 2500000},
+		{Name: "360p", Height: 360, Bitrate: 800000},
+	})
+
+	expectedArgs := []string{
+		"-i", "/input/video.mp4",
+		"-filter_complex", "[0:v]split=2[s0][s1];[s0]scale=-2:720[v0];[s1]scale=-2:360[v1]",
+		"-map", "[v0]",
+		"-map", "[v1]",
+		"-map", "0:a?",
+		"-c:v", "libx264",
+		"-preset", "fast",
+		"-b:v:0", "2500000",
+		"-b:v:1", "800000",
+		"-c:a", "aac",
+		"-f", "dash",
+		"-seg_duration", "6",
+		"-use_template", "1",
+		"-use_timeline", "1",
+		"-init_seg_name", "init-$RepresentationID$.m4s",
+		"-media_seg_name", "chunk-$RepresentationID$-$Number%05d$.m4s",
+		"-y",
+		"/output/manifest.mpd",
+	}
+
+	if !slices.Equal(args, expectedArgs) {
+		t.Errorf("args mismatch:\ngot:      %q\nexpected: %q", args, expectedArgs)
+	}
+}
+
+func TestFFmpegTranscoder_GroupDASHSegments(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+
+	variants := []Variant{
+		{Name: "720p", Height: 720, Bitrate: 2500000},
+		{Name: "360p", Height: 360, Bitrate: 800000},
+	}
+	outputs := transcoder.groupDASHSegments([]string{
+		"/out/chunk-0-00001.m4s",
+		"/out/chunk-1-00001.m4s",
+		"/out/chunk-2-00001.m4s", // audio
+		"/out/init-0.m4s",
+		"/out/init-1.m4s",
+		"/out/init-2.m4s", // audio
+	}, variants)
+
+	if len(outputs) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(outputs))
+	}
+	for i, want := range []string{
+		"/out/chunk-0-00001.m4s,/out/init-0.m4s",
+		"/out/chunk-1-00001.m4s,/out/init-1.m4s",
+	} {
+		if got := strings.Join(outputs[i].SegmentPaths, ","); got != want {
+			t.Errorf("%s segments: got %s, expected %s", outputs[i].Variant.Name, got, want)
+		}
+		if outputs[i].Codec != "libx264" {
+			t.Errorf("%s codec: got %s", outputs[i].Variant.Name, outputs[i].Codec)
+		}
+	}
+}
+
+func TestFFmpegTranscoder_TranscodeToDASH_ValidationErrors(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+	ctx := context.Background()
+
+	inputFile := filepath.Join(t.TempDir(), "input.mp4")
+	if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		inputPath string
+		outputDir string
+		variants  []Variant
+	}{
+		{name: "non-existent input", inputPath: "/non/existent/input.mp4", outputDir: t.TempDir(), variants: DefaultABRVariants()},
+		{name: "non-existent output directory", inputPath: inputFile, outputDir: "/non/existent/output", variants: DefaultABRVariants()},
+		{name: "empty variants", inputPath: inputFile, outputDir: t.TempDir()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := transcoder.TranscodeToDASH(ctx, tt.inputPath, tt.outputDir, tt.variants, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestFFmpegTranscoder_TranscodeToDASH(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	// Stands in for ffmpeg: writes the manifest (the last argument) and one
+	// initialization and media segment, and prints one block of -progress output
+	script := `#!/bin/sh
+for last; do :; done
+dir=$(dirname "$last")
+printf 'out_time_us=4000000\nprogress=end\n'
+touch "$dir/init-0.m4s" "$dir/chunk-0-00001.m4s" "$last"
+`
+	ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(ffmpeg, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+
+	cfg := DefaultFFmpegConfig()
+	cfg.FFmpegPath = ffmpeg
+	transcoder := NewFFmpegTranscoder(cfg)
+
+	inputFile := filepath.Join(t.TempDir(), "input.mp4")
+	if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	outputDir := t.TempDir()
+
+	var reported []string
+	output, err := transcoder.TranscodeToDASH(context.Background(), inputFile, outputDir, DefaultABRVariants(), func(variant string, encoded time.Duration) {
+		reported = append(reported, variant+"@"+encoded.String())
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.ManifestPath != filepath.Join(outputDir, DASHManifestName) {
+		t.Errorf("manifest path: got %s", output.ManifestPath)
+	}
+	var names []string
+	for _, p := range output.SegmentPaths {
+		names = append(names, filepath.Base(p))
+	}
+	if got := strings.Join(names, ","); got != "chunk-0-00001.m4s,init-0.m4s" {
+		t.Errorf("segments: got %s", got)
+	}
+	if len(output.Variants) != 3 || output.Variants[0].Variant.Name != "1080p" || len(output.Variants[0].SegmentPaths) != 2 {
+		t.Errorf("variants: got %+v, expected both segments on 1080p", output.Variants)
+	}
+	if got := strings.Join(reported, ","); got != "dash@4s" {
+		t.Errorf("progress: got %s, expected dash@4s", got)
+	}
+}
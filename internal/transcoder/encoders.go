@@ -0,0 +1,96 @@
+package transcoder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+// ErrNoUsableEncoder is returned when none of the allowed encoders is built into FFmpeg.
+// It is coded errs.Permanent, since retrying on the same host cannot help.
+var ErrNoUsableEncoder = errs.New(errs.Permanent, "no allowed video encoder is available")
+
+// SelectVideoEncoder returns the first of allowed that the FFmpeg binary provides.
+// GPU encoders such as h264_nvenc are only present in builds with hardware support,
+// so listing a CPU encoder last lets a node degrade instead of failing every task.
+func SelectVideoEncoder(ctx context.Context, ffmpegPath string, allowed []string) (string, error) {
+	out, err := command(ctx, ffmpegPath, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list ffmpeg encoders: %w", err)
+	}
+
+	available := parseVideoEncoders(out)
+	for _, codec := range allowed {
+		if slices.Contains(available, codec) {
+			return codec, nil
+		}
+	}
+	return "", fmt.Errorf("%w: allowed %v", ErrNoUsableEncoder, allowed)
+}
+
+// MissingFeatures returns the encoders and muxers the FFmpeg binary lacks, in the order
+// given, as "encoder NAME" and "muxer NAME". Used to diagnose an installation before the
+// first task fails on it.
+func MissingFeatures(ctx context.Context, ffmpegPath string, encoders, muxers []string) ([]string, error) {
+	encoderOut, err := command(ctx, ffmpegPath, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ffmpeg encoders: %w", err)
+	}
+	muxerOut, err := command(ctx, ffmpegPath, "-hide_banner", "-muxers").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ffmpeg muxers: %w", err)
+	}
+
+	availableEncoders := parseListing(encoderOut, "")
+	availableMuxers := parseListing(muxerOut, "")
+
+	var missing []string
+	for _, name := range encoders {
+		if !slices.Contains(availableEncoders, name) {
+			missing = append(missing, "encoder "+name)
+		}
+	}
+	for _, name := range muxers {
+		if !slices.Contains(availableMuxers, name) {
+			missing = append(missing, "muxer "+name)
+		}
+	}
+	return missing, nil
+}
+
+// parseVideoEncoders extracts video encoder names from `ffmpeg -encoders` output.
+// Encoder lines look like " V....D libx264   libx264 H.264 / AVC ...", where the
+// first flag character is V for video.
+func parseVideoEncoders(out []byte) []string {
+	return parseListing(out, "V")
+}
+
+// parseListing extracts the names from `ffmpeg -encoders` or `ffmpeg -muxers` output,
+// keeping only entries whose flags start with flagPrefix. Muxer lines look like
+// "  E hls   Apple HTTP Live Streaming".
+func parseListing(out []byte, flagPrefix string) []string {
+	var names []string
+	listing := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		// The legend ends with a dashed separator ("------" for encoders, "--" for muxers)
+		if strings.HasPrefix(fields[0], "--") {
+			listing = true
+			continue
+		}
+		if listing && len(fields) >= 2 && strings.HasPrefix(fields[0], flagPrefix) {
+			names = append(names, fields[1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,43 @@
+package transcoder
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVideoEncoders(t *testing.T) {
+	out := []byte(`Encoders:
+ V..... = Video
+ A..... = Audio
+ S..... = Subtitle
+ .F.... = Frame-level multithreading
+ ------
+ V....D libx264              libx264 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10 (codec h264)
+ V....D h264_nvenc           NVIDIA NVENC H.264 encoder (codec h264)
+ A....D aac                  AAC (Advanced Audio Coding)
+ V....D libx265              libx265 H.265 / HEVC (codec hevc)
+`)
+
+	got := parseVideoEncoders(out)
+	want := []string{"libx264", "h264_nvenc", "libx265"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseVideoEncoders() = %v, want %v", got, want)
+	}
+}
+
+func TestParseListing_Muxers(t *testing.T) {
+	out := []byte(`File formats:
+ D. = Demuxing supported
+ .E = Muxing supported
+ --
+  E dash            DASH Muxer
+  E hls             Apple HTTP Live Streaming
+ DE mp4             MP4 (MPEG-4 Part 14)
+`)
+
+	got := parseListing(out, "")
+	want := []string{"dash", "hls", "mp4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseListing() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+package transcoder
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// killWaitDelay bounds how long a killed run waits for its output pipes to close, so a
+// process outliving the kill cannot block the worker.
+const killWaitDelay = 5 * time.Second
+
+// command returns a command running name that, once ctx is done, is killed together with
+// any process it started. A hung FFmpeg run thus frees its worker when the task's
+// deadline passes.
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = killWaitDelay
+	return cmd
+}
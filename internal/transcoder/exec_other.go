@@ -0,0 +1,9 @@
+//go:build !unix
+
+package transcoder
+
+import "os/exec"
+
+// killProcessGroupOnCancel keeps the default of killing the process alone, as process
+// groups are Unix-only.
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
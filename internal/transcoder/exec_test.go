@@ -0,0 +1,48 @@
+package transcoder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+func TestCommand_KillsProcessGroupOnCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	inputFile := filepath.Join(t.TempDir(), "input.mp4")
+	if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	// The background child inherits stderr, so killing the shell alone would leave Wait
+	// blocked on the pipe until killWaitDelay
+	ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(ffmpeg, []byte("#!/bin/sh\nsleep 30 &\nsleep 30\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+	cfg := DefaultFFmpegConfig()
+	cfg.FFmpegPath = ffmpeg
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewFFmpegTranscoder(cfg).GenerateThumbnails(ctx, inputFile, t.TempDir(), time.Second, DefaultThumbnailSizes())
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GenerateThumbnails() error = %v, want context.DeadlineExceeded", err)
+	}
+	if !errs.Retryable(err) {
+		t.Errorf("error %v is not retryable", err)
+	}
+	if elapsed >= killWaitDelay {
+		t.Errorf("returned after %s, want the process group killed before killWaitDelay", elapsed)
+	}
+}
@@ -4,9 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/hls"
+	"golang.org/x/sync/errgroup"
 )
 
 // FFmpegConfig holds configuration for the FFmpeg transcoder.
@@ -15,6 +21,10 @@ type FFmpegConfig struct {
 	// If empty, "ffmpeg" will be used (assumes it's in PATH).
 	FFmpegPath string
 
+	// FFprobePath is the path to the ffprobe binary used to inspect inputs before
+	// an ABR transcode. If empty, inputs are not probed and ABROutput.Source is nil.
+	FFprobePath string
+
 	// VideoHeight is the target video height in pixels.
 	// Width is calculated automatically to maintain aspect ratio.
 	// Default: 720
@@ -41,18 +51,36 @@ type FFmpegConfig struct {
 	// Use "vod" for Video on Demand (adds EXT-X-ENDLIST tag).
 	// Default: vod
 	HLSPlaylistType string
+
+	// PlaylistOrder is the order of variants in the master playlist.
+	// Default: highest-first
+	PlaylistOrder PlaylistOrder
+
+	// MaxParallelVariants is the number of ABR variants encoded at once. Each variant is
+	// a separate FFmpeg process, so this multiplies the CPU used per task.
+	// Values <= 1 encode variants sequentially.
+	// Default: 1
+	MaxParallelVariants int
+
+	// DefaultVariant names the variant players start on (e.g., "720p"); it is listed
+	// first in the master playlist regardless of PlaylistOrder.
+	// If empty or not produced, the first variant in PlaylistOrder is the default.
+	DefaultVariant string
 }
 
 // DefaultFFmpegConfig returns an FFmpegConfig with production-ready defaults.
 func DefaultFFmpegConfig() FFmpegConfig {
 	return FFmpegConfig{
-		FFmpegPath:         "ffmpeg",
-		VideoHeight:        720,
-		VideoCodec:         "libx264",
-		VideoPreset:        "fast",
-		AudioCodec:         "aac",
-		HLSSegmentDuration: 6,
-		HLSPlaylistType:    "vod",
+		FFmpegPath:          "ffmpeg",
+		FFprobePath:         "ffprobe",
+		VideoHeight:         720,
+		VideoCodec:          "libx264",
+		VideoPreset:         "fast",
+		AudioCodec:          "aac",
+		HLSSegmentDuration:  6,
+		HLSPlaylistType:     "vod",
+		PlaylistOrder:       PlaylistOrderHighestFirst,
+		MaxParallelVariants: 1,
 	}
 }
 
@@ -87,15 +115,17 @@ func (t *FFmpegTranscoder) TranscodeToHLS(ctx context.Context, inputPath, output
 
 	args := t.buildFFmpegArgs(inputPath, manifestPath, segmentPattern)
 
-	cmd := exec.CommandContext(ctx, t.config.FFmpegPath, args...)
+	cmd := command(ctx, t.config.FFmpegPath, args...)
 	cmd.Stdout = nil // Discard stdout
-	cmd.Stderr = nil // Discard stderr (FFmpeg outputs progress to stderr)
+	stderr, logged := captureStderr(ctx, cmd, "hls") // Only the tail is kept; FFmpeg writes stats there while it runs
 
-	if err := cmd.Run(); err != nil {
+	err := cmd.Run()
+	logged(err)
+	if err != nil {
 		if ctx.Err() != nil {
-			return nil, fmt.Errorf("transcoding cancelled: %w", ctx.Err())
+			return nil, fmt.Errorf("transcoding cancelled: %w", errs.Wrap(errs.Transient, ctx.Err()))
 		}
-		return nil, fmt.Errorf("ffmpeg execution failed: %w", err)
+		return nil, execError(err, stderr)
 	}
 
 	segments, err := t.collectSegments(outputDir)
@@ -198,38 +228,88 @@ func DefaultABRVariants() []Variant {
 	}
 }
 
+// VariantsForSource drops the variants taller than a source of the given height, since
+// upscaling only adds bytes. The smallest variant is always kept so the output is never
+// empty. A non-positive height means the source is unknown and all variants are kept.
+func VariantsForSource(variants []Variant, sourceHeight int) []Variant {
+	if sourceHeight <= 0 || len(variants) == 0 {
+		return variants
+	}
+
+	var kept []Variant
+	smallest := variants[0]
+	for _, v := range variants {
+		if v.Height <= sourceHeight {
+			kept = append(kept, v)
+		}
+		if v.Height < smallest.Height {
+			smallest = v
+		}
+	}
+	if len(kept) == 0 {
+		kept = append(kept, smallest)
+	}
+	return kept
+}
+
 // TranscodeToABR converts the input video to multiple quality variants for ABR streaming.
-// It processes each variant sequentially and generates a master playlist.
-func (t *FFmpegTranscoder) TranscodeToABR(ctx context.Context, inputPath, outputDir string, variants []Variant) (*ABROutput, error) {
+// It encodes up to MaxParallelVariants variants at once and generates a master playlist.
+func (t *FFmpegTranscoder) TranscodeToABR(ctx context.Context, inputPath, outputDir string, variants []Variant, progress ProgressFunc) (*ABROutput, error) {
+	probeStart := time.Now()
 	if err := t.validateInput(inputPath); err != nil {
 		return nil, err
 	}
+	var source *ProbeResult
+	if t.config.FFprobePath != "" {
+		var err error
+		source, err = NewFFprobe(t.config.FFprobePath).Probe(ctx, inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("probe input: %w", err)
+		}
+	}
+	probeDuration := time.Since(probeStart)
 
 	if err := t.validateOutputDir(outputDir); err != nil {
 		return nil, err
 	}
 
 	if len(variants) == 0 {
-		return nil, fmt.Errorf("at least one variant is required")
+		return nil, errs.New(errs.Invalid, "at least one variant is required")
 	}
 
-	var variantOutputs []VariantOutput
-
-	// Process each variant sequentially
-	// Trade-off: Sequential is simpler and more debuggable than parallel.
-	// Can be optimized later if transcoding time becomes a bottleneck.
-	for _, variant := range variants {
-		variantDir := filepath.Join(outputDir, variant.Name)
-		if err := os.MkdirAll(variantDir, 0755); err != nil {
-			return nil, fmt.Errorf("create variant directory %s: %w", variant.Name, err)
-		}
-
-		output, err := t.transcodeVariant(ctx, inputPath, variantDir, variant)
-		if err != nil {
-			return nil, fmt.Errorf("transcode variant %s: %w", variant.Name, err)
-		}
+	// Variants are encoded up to MaxParallelVariants at a time; the first failure cancels
+	// the others. Outputs are stored by index, so their order (and the master playlist)
+	// does not depend on which variant finishes first.
+	variantOutputs := make([]VariantOutput, len(variants))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(t.config.MaxParallelVariants, 1))
+
+	for i, variant := range variants {
+		g.Go(func() (err error) {
+			// The queue consumer only recovers panics on the handler's own goroutine
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("transcode variant %s panicked: %v", variant.Name, r)
+				}
+			}()
+
+			variantDir := filepath.Join(outputDir, variant.Name)
+			if err := os.MkdirAll(variantDir, 0755); err != nil {
+				return fmt.Errorf("create variant directory %s: %w", variant.Name, err)
+			}
+
+			output, err := t.transcodeVariant(gctx, inputPath, variantDir, variant, progress)
+			if err != nil {
+				return fmt.Errorf("transcode variant %s: %w", variant.Name, err)
+			}
+
+			variantOutputs[i] = *output
+			return nil
+		})
+	}
 
-		variantOutputs = append(variantOutputs, *output)
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
 	// Generate master playlist after all variants are complete
@@ -241,25 +321,39 @@ func (t *FFmpegTranscoder) TranscodeToABR(ctx context.Context, inputPath, output
 	return &ABROutput{
 		MasterManifestPath: masterPath,
 		Variants:           variantOutputs,
+		ProbeDuration:      probeDuration,
+		Source:             source,
 	}, nil
 }
 
 // transcodeVariant transcodes the input to a single quality variant.
-func (t *FFmpegTranscoder) transcodeVariant(ctx context.Context, inputPath, variantDir string, variant Variant) (*VariantOutput, error) {
+// When progress is non-nil, FFmpeg's progress output is parsed and reported as it encodes.
+func (t *FFmpegTranscoder) transcodeVariant(ctx context.Context, inputPath, variantDir string, variant Variant, progress ProgressFunc) (*VariantOutput, error) {
 	manifestPath := filepath.Join(variantDir, "playlist.m3u8")
 	segmentPattern := filepath.Join(variantDir, "segment_%03d.ts")
 
 	args := t.buildVariantFFmpegArgs(inputPath, manifestPath, segmentPattern, variant)
+	if progress != nil {
+		args = append(slices.Clone(progressArgs), args...)
+	}
 
-	cmd := exec.CommandContext(ctx, t.config.FFmpegPath, args...)
+	start := time.Now()
+	cmd := command(ctx, t.config.FFmpegPath, args...)
 	cmd.Stdout = nil
-	cmd.Stderr = nil
+	stderr, logged := captureStderr(ctx, cmd, variant.Name)
 
-	if err := cmd.Run(); err != nil {
+	var err error
+	if progress != nil {
+		err = runWithProgress(cmd, func(encoded time.Duration) { progress(variant.Name, encoded) })
+	} else {
+		err = cmd.Run()
+	}
+	logged(err)
+	if err != nil {
 		if ctx.Err() != nil {
-			return nil, fmt.Errorf("transcoding cancelled: %w", ctx.Err())
+			return nil, fmt.Errorf("transcoding cancelled: %w", errs.Wrap(errs.Transient, ctx.Err()))
 		}
-		return nil, fmt.Errorf("ffmpeg execution failed: %w", err)
+		return nil, execError(err, stderr)
 	}
 
 	segments, err := t.collectSegments(variantDir)
@@ -267,24 +361,50 @@ func (t *FFmpegTranscoder) transcodeVariant(ctx context.Context, inputPath, vari
 		return nil, fmt.Errorf("collect segments: %w", err)
 	}
 
+	codec := t.config.VideoCodec
+	if variant.AudioOnly {
+		codec = t.config.AudioCodec
+	}
+
 	return &VariantOutput{
 		Variant:      variant,
 		ManifestPath: manifestPath,
 		SegmentPaths: segments,
+		Codec:        codec,
+		Duration:     time.Since(start),
 	}, nil
 }
 
 // buildVariantFFmpegArgs constructs FFmpeg arguments for a specific variant.
+// An audio-only variant maps the first audio stream and drops video entirely.
+// With a key info file, FFmpeg encrypts every segment and adds EXT-X-KEY to the playlist.
 func (t *FFmpegTranscoder) buildVariantFFmpegArgs(inputPath, manifestPath, segmentPattern string, variant Variant) []string {
-	scaleFilter := fmt.Sprintf("scale=-2:%d", variant.Height)
+	var args []string
+	if variant.AudioOnly {
+		args = []string{
+			"-i", inputPath,
+			"-map", "0:a:0",
+			"-vn",
+			"-c:a", t.config.AudioCodec,
+		}
+	} else {
+		args = []string{
+			"-i", inputPath,
+			"-vf", fmt.Sprintf("scale=-2:%d", variant.Height),
+			"-c:v", t.config.VideoCodec,
+			"-preset", t.config.VideoPreset,
+			"-b:v", fmt.Sprintf("%d", variant.Bitrate), // Target video bitrate
+			"-c:a", t.config.AudioCodec,
+		}
+	}
+	if variant.AudioBitrate > 0 {
+		args = append(args, "-b:a", strconv.Itoa(variant.AudioBitrate))
+	}
+	if variant.KeyInfoPath != "" {
+		args = append(args, "-hls_key_info_file", variant.KeyInfoPath)
+	}
 
-	return []string{
-		"-i", inputPath,
-		"-vf", scaleFilter,
-		"-c:v", t.config.VideoCodec,
-		"-preset", t.config.VideoPreset,
-		"-b:v", fmt.Sprintf("%d", variant.Bitrate), // Target video bitrate
-		"-c:a", t.config.AudioCodec,
+	return append(args,
 		"-f", "hls",
 		"-hls_time", fmt.Sprintf("%d", t.config.HLSSegmentDuration),
 		"-hls_list_size", "0",
@@ -292,34 +412,114 @@ func (t *FFmpegTranscoder) buildVariantFFmpegArgs(inputPath, manifestPath, segme
 		"-hls_segment_filename", segmentPattern,
 		"-y",
 		manifestPath,
-	}
+	)
 }
 
-// generateMasterPlaylist creates the master.m3u8 file that references all variant playlists.
+// generateMasterPlaylist creates the master.m3u8 file that references all variant playlists,
+// listed in the configured PlaylistOrder with the DefaultVariant first.
+// Audio-only variants carry a CODECS attribute, so players can tell them apart from video
+// variants without fetching the playlist.
 func (t *FFmpegTranscoder) generateMasterPlaylist(path string, variants []VariantOutput) error {
-	var sb strings.Builder
-	sb.WriteString("#EXTM3U\n")
-	sb.WriteString("#EXT-X-VERSION:3\n\n")
-
-	for _, v := range variants {
-		// Calculate width assuming 16:9 aspect ratio
-		// This is an approximation; actual width depends on source video
-		width := v.Variant.Height * 16 / 9
-		// Ensure width is even (codec requirement)
-		if width%2 != 0 {
-			width++
+	playlist := hls.New(3)
+	playlist.AddBlank()
+
+	for _, v := range orderVariants(variants, t.config.PlaylistOrder, t.config.DefaultVariant) {
+		variant := hls.Variant{
+			Bandwidth: int64(v.Variant.Bitrate + v.Variant.AudioBitrate),
+			Width:     v.Variant.Width(),
+			Height:    v.Variant.Height,
+			URI:       v.Variant.Name + "/playlist.m3u8",
 		}
-
-		sb.WriteString(fmt.Sprintf(
-			"#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n",
-			v.Variant.Bitrate, width, v.Variant.Height,
-		))
-		sb.WriteString(fmt.Sprintf("%s/playlist.m3u8\n\n", v.Variant.Name))
+		if v.Variant.AudioOnly {
+			variant.Codecs = audioCodecs(t.config.AudioCodec)
+		}
+		playlist.AddVariant(variant)
+		playlist.AddBlank()
 	}
 
-	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+	if err := os.WriteFile(path, playlist.Encode(), 0644); err != nil {
 		return fmt.Errorf("write master playlist: %w", err)
 	}
 
 	return nil
 }
+
+// audioCodecs returns the RFC 6381 CODECS value of an FFmpeg audio encoder, or "" when
+// it is not known; the attribute is then omitted.
+func audioCodecs(encoder string) string {
+	switch encoder {
+	case "aac", "libfdk_aac":
+		return "mp4a.40.2" // AAC-LC
+	case "libmp3lame":
+		return "mp4a.40.34"
+	case "ac3":
+		return "ac-3"
+	case "eac3":
+		return "ec-3"
+	default:
+		return ""
+	}
+}
+
+// DefaultPreviewVariant returns the quality used for preview renditions.
+// A single mid-quality rendition keeps preview encoding cheap relative to the full ABR ladder.
+func DefaultPreviewVariant() Variant {
+	return Variant{Name: "preview", Height: 480, Bitrate: 1200000}
+}
+
+// TranscodePreview encodes the first duration of the input as a single HLS rendition.
+func (t *FFmpegTranscoder) TranscodePreview(ctx context.Context, inputPath, outputDir string, variant Variant, duration time.Duration) (*HLSOutput, error) {
+	if err := t.validateInput(inputPath); err != nil {
+		return nil, err
+	}
+
+	if err := t.validateOutputDir(outputDir); err != nil {
+		return nil, err
+	}
+
+	if duration <= 0 {
+		return nil, errs.New(errs.Invalid, "preview duration must be positive")
+	}
+
+	manifestPath := filepath.Join(outputDir, "playlist.m3u8")
+	segmentPattern := filepath.Join(outputDir, "segment_%03d.ts")
+
+	args := t.buildPreviewFFmpegArgs(inputPath, manifestPath, segmentPattern, variant, duration)
+
+	cmd := command(ctx, t.config.FFmpegPath, args...)
+	cmd.Stdout = nil
+	stderr, logged := captureStderr(ctx, cmd, "preview")
+
+	err := cmd.Run()
+	logged(err)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("transcoding cancelled: %w", errs.Wrap(errs.Transient, ctx.Err()))
+		}
+		return nil, execError(err, stderr)
+	}
+
+	segments, err := t.collectSegments(outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("collect segments: %w", err)
+	}
+
+	return &HLSOutput{
+		ManifestPath: manifestPath,
+		SegmentPaths: segments,
+	}, nil
+}
+
+// buildPreviewFFmpegArgs constructs FFmpeg arguments for a trimmed preview rendition.
+// -t is placed as an output option so only the first duration is encoded; inputs shorter
+// than the preview simply produce a full-length rendition.
+func (t *FFmpegTranscoder) buildPreviewFFmpegArgs(inputPath, manifestPath, segmentPattern string, variant Variant, duration time.Duration) []string {
+	args := t.buildVariantFFmpegArgs(inputPath, manifestPath, segmentPattern, variant)
+
+	// Insert the duration limit right after the input
+	trimmed := make([]string, 0, len(args)+2)
+	trimmed = append(trimmed, args[:2]...)
+	trimmed = append(trimmed, "-t", strconv.FormatFloat(duration.Seconds(), 'f', -1, 64))
+	trimmed = append(trimmed, args[2:]...)
+	return trimmed
+}
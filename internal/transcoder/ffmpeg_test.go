@@ -4,8 +4,14 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/hls"
 )
 
 func TestDefaultFFmpegConfig(t *testing.T) {
@@ -17,12 +23,16 @@ func TestDefaultFFmpegConfig(t *testing.T) {
 		expected any
 	}{
 		{"FFmpegPath", cfg.FFmpegPath, "ffmpeg"},
+		{"FFprobePath", cfg.FFprobePath, "ffprobe"},
 		{"VideoHeight", cfg.VideoHeight, 720},
 		{"VideoCodec", cfg.VideoCodec, "libx264"},
 		{"VideoPreset", cfg.VideoPreset, "fast"},
 		{"AudioCodec", cfg.AudioCodec, "aac"},
 		{"HLSSegmentDuration", cfg.HLSSegmentDuration, 6},
 		{"HLSPlaylistType", cfg.HLSPlaylistType, "vod"},
+		{"PlaylistOrder", cfg.PlaylistOrder, PlaylistOrderHighestFirst},
+		{"DefaultVariant", cfg.DefaultVariant, ""},
+		{"MaxParallelVariants", cfg.MaxParallelVariants, 1},
 	}
 
 	for _, tt := range tests {
@@ -307,6 +317,34 @@ func TestDefaultABRVariants(t *testing.T) {
 	}
 }
 
+func TestVariantsForSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		sourceHeight int
+		want         []string
+	}{
+		{"full HD source", 1080, []string{"1080p", "720p", "360p"}},
+		{"4K source", 2160, []string{"1080p", "720p", "360p"}},
+		{"between rungs", 900, []string{"720p", "360p"}},
+		{"exactly 720p", 720, []string{"720p", "360p"}},
+		{"below smallest rung", 240, []string{"360p"}},
+		{"unknown height", 0, []string{"1080p", "720p", "360p"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VariantsForSource(DefaultABRVariants(), tt.sourceHeight)
+			var names []string
+			for _, v := range got {
+				names = append(names, v.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("VariantsForSource(%d) = %v, want %v", tt.sourceHeight, names, tt.want)
+			}
+		})
+	}
+}
+
 func TestFFmpegTranscoder_BuildVariantFFmpegArgs(t *testing.T) {
 	cfg := DefaultFFmpegConfig()
 	transcoder := NewFFmpegTranscoder(cfg)
@@ -346,6 +384,107 @@ func TestFFmpegTranscoder_BuildVariantFFmpegArgs(t *testing.T) {
 	}
 }
 
+func TestFFmpegTranscoder_BuildVariantFFmpegArgs_AudioBitrate(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+
+	args := transcoder.buildVariantFFmpegArgs("in.mp4", "out/playlist.m3u8", "out/segment_%03d.ts",
+		Variant{Name: "720p", Height: 720, Bitrate: 1500000, AudioBitrate: 64000})
+
+	i := slices.Index(args, "-b:a")
+	if i < 0 || i+1 >= len(args) || args[i+1] != "64000" {
+		t.Errorf("expected -b:a 64000 in %v", args)
+	}
+}
+
+func TestFFmpegTranscoder_BuildVariantFFmpegArgs_KeyInfo(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+
+	args := transcoder.buildVariantFFmpegArgs("in.mp4", "out/playlist.m3u8", "out/segment_%03d.ts",
+		Variant{Name: "720p", Height: 720, Bitrate: 1500000, KeyInfoPath: "work/hls.keyinfo"})
+
+	i := slices.Index(args, "-hls_key_info_file")
+	if i < 0 || args[i+1] != "work/hls.keyinfo" {
+		t.Errorf("expected -hls_key_info_file work/hls.keyinfo in %v", args)
+	}
+	if args[len(args)-1] != "out/playlist.m3u8" {
+		t.Errorf("expected the playlist last in %v", args)
+	}
+
+	args = transcoder.buildVariantFFmpegArgs("in.mp4", "out/playlist.m3u8", "out/segment_%03d.ts",
+		Variant{Name: "720p", Height: 720, Bitrate: 1500000})
+	if slices.Contains(args, "-hls_key_info_file") {
+		t.Errorf("unexpected -hls_key_info_file in %v", args)
+	}
+}
+
+func TestFFmpegTranscoder_BuildVariantFFmpegArgs_AudioOnly(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+
+	args := transcoder.buildVariantFFmpegArgs("in.mp4", "out/playlist.m3u8", "out/segment_%03d.ts",
+		AudioOnlyVariant(96000))
+
+	for _, flag := range []string{"-vf", "-c:v", "-b:v", "-preset"} {
+		if slices.Contains(args, flag) {
+			t.Errorf("unexpected video flag %s in %v", flag, args)
+		}
+	}
+	if i := slices.Index(args, "-map"); i < 0 || args[i+1] != "0:a:0" {
+		t.Errorf("expected -map 0:a:0 in %v", args)
+	}
+	if !slices.Contains(args, "-vn") {
+		t.Errorf("expected -vn in %v", args)
+	}
+	if i := slices.Index(args, "-b:a"); i < 0 || args[i+1] != "96000" {
+		t.Errorf("expected -b:a 96000 in %v", args)
+	}
+}
+
+func TestFFmpegTranscoder_GenerateMasterPlaylist_AudioOnly(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+
+	masterPath := filepath.Join(t.TempDir(), "master.m3u8")
+	err := transcoder.generateMasterPlaylist(masterPath, []VariantOutput{
+		{Variant: AudioOnlyVariant(64000)},
+		{Variant: Variant{Name: "720p", Height: 720, Bitrate: 2500000}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(masterPath)
+	if err != nil {
+		t.Fatalf("failed to read master playlist: %v", err)
+	}
+	playlist, err := hls.Parse(content)
+	if err != nil {
+		t.Fatalf("failed to parse master playlist: %v", err)
+	}
+	variants, err := playlist.Variants()
+	if err != nil {
+		t.Fatalf("failed to list variants: %v", err)
+	}
+
+	if len(variants) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(variants))
+	}
+	if variants[0].URI != "720p/playlist.m3u8" || variants[0].Codecs != "" {
+		t.Errorf("first variant = %+v, want 720p without CODECS", variants[0])
+	}
+	audio := variants[1]
+	if audio.URI != "audio/playlist.m3u8" {
+		t.Errorf("audio URI = %s, want audio/playlist.m3u8", audio.URI)
+	}
+	if audio.Codecs != "mp4a.40.2" {
+		t.Errorf("audio CODECS = %q, want mp4a.40.2", audio.Codecs)
+	}
+	if audio.Bandwidth != 64000 {
+		t.Errorf("audio BANDWIDTH = %d, want 64000", audio.Bandwidth)
+	}
+	if audio.Width != 0 || audio.Height != 0 {
+		t.Errorf("audio RESOLUTION = %dx%d, want none", audio.Width, audio.Height)
+	}
+}
+
 func TestFFmpegTranscoder_GenerateMasterPlaylist(t *testing.T) {
 	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
 
@@ -421,7 +560,7 @@ func TestFFmpegTranscoder_TranscodeToABR_ValidationErrors(t *testing.T) {
 
 	t.Run("returns error for non-existent input", func(t *testing.T) {
 		outputDir := t.TempDir()
-		_, err := transcoder.TranscodeToABR(ctx, "/non/existent/input.mp4", outputDir, variants)
+		_, err := transcoder.TranscodeToABR(ctx, "/non/existent/input.mp4", outputDir, variants, nil)
 		if err == nil {
 			t.Error("expected error for non-existent input")
 		}
@@ -431,7 +570,7 @@ func TestFFmpegTranscoder_TranscodeToABR_ValidationErrors(t *testing.T) {
 		inputFile := filepath.Join(t.TempDir(), "input.mp4")
 		os.WriteFile(inputFile, []byte("dummy"), 0644)
 
-		_, err := transcoder.TranscodeToABR(ctx, inputFile, "/non/existent/output", variants)
+		_, err := transcoder.TranscodeToABR(ctx, inputFile, "/non/existent/output", variants, nil)
 		if err == nil {
 			t.Error("expected error for non-existent output directory")
 		}
@@ -442,9 +581,168 @@ func TestFFmpegTranscoder_TranscodeToABR_ValidationErrors(t *testing.T) {
 		os.WriteFile(inputFile, []byte("dummy"), 0644)
 		outputDir := t.TempDir()
 
-		_, err := transcoder.TranscodeToABR(ctx, inputFile, outputDir, []Variant{})
+		_, err := transcoder.TranscodeToABR(ctx, inputFile, outputDir, []Variant{}, nil)
 		if err == nil {
 			t.Error("expected error for empty variants")
 		}
 	})
 }
+
+// fakeFFmpeg writes a script standing in for ffmpeg: it writes the playlist (the last
+// argument) and one segment, sleeping first for the 1080p variant and failing for
+// variants named "broken". It always prints two blocks of -progress output.
+func fakeFFmpeg(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	script := `#!/bin/sh
+for last; do :; done
+dir=$(dirname "$last")
+case "$dir" in
+*/broken) exit 1 ;;
+*/1080p) sleep 0.2 ;;
+esac
+printf 'out_time_us=1000000\nprogress=continue\nout_time_us=2000000\nprogress=end\n'
+touch "$dir/segment_000.ts" "$last"
+`
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+	return path
+}
+
+func TestFFmpegTranscoder_TranscodeToABR_Parallel(t *testing.T) {
+	cfg := DefaultFFmpegConfig()
+	cfg.FFmpegPath = fakeFFmpeg(t)
+	cfg.FFprobePath = ""
+	cfg.MaxParallelVariants = 3
+	transcoder := NewFFmpegTranscoder(cfg)
+
+	inputFile := filepath.Join(t.TempDir(), "input.mp4")
+	if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	t.Run("outputs keep the requested order", func(t *testing.T) {
+		outputDir := t.TempDir()
+		// 1080p finishes last, but stays first in the output
+		output, err := transcoder.TranscodeToABR(context.Background(), inputFile, outputDir, DefaultABRVariants(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var names []string
+		for _, v := range output.Variants {
+			names = append(names, v.Variant.Name)
+			if len(v.SegmentPaths) != 1 {
+				t.Errorf("%s segments: got %v", v.Variant.Name, v.SegmentPaths)
+			}
+		}
+		if got := strings.Join(names, ","); got != "1080p,720p,360p" {
+			t.Errorf("variants: got %s, expected 1080p,720p,360p", got)
+		}
+
+		content, err := os.ReadFile(output.MasterManifestPath)
+		if err != nil {
+			t.Fatalf("failed to read master playlist: %v", err)
+		}
+		if i, j := strings.Index(string(content), "1080p/"), strings.Index(string(content), "360p/"); i < 0 || j < i {
+			t.Errorf("master playlist order changed:\n%s", content)
+		}
+	})
+
+	t.Run("reports progress per variant", func(t *testing.T) {
+		var mu sync.Mutex
+		got := make(map[string][]time.Duration)
+		progress := func(variant string, encoded time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			got[variant] = append(got[variant], encoded)
+		}
+
+		if _, err := transcoder.TranscodeToABR(context.Background(), inputFile, t.TempDir(), DefaultABRVariants(), progress); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, v := range DefaultABRVariants() {
+			if want := []time.Duration{time.Second, 2 * time.Second}; !slices.Equal(got[v.Name], want) {
+				t.Errorf("%s progress: got %v, expected %v", v.Name, got[v.Name], want)
+			}
+		}
+	})
+
+	t.Run("first failure fails the transcode", func(t *testing.T) {
+		variants := append(DefaultABRVariants(), Variant{Name: "broken", Height: 240, Bitrate: 400000})
+		_, err := transcoder.TranscodeToABR(context.Background(), inputFile, t.TempDir(), variants, nil)
+		if err == nil || !strings.Contains(err.Error(), "transcode variant broken") {
+			t.Errorf("expected broken variant error, got %v", err)
+		}
+	})
+}
+
+func TestFFmpegTranscoder_BuildPreviewFFmpegArgs(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+
+	args := transcoder.buildPreviewFFmpegArgs(
+		"/input/video.mp4",
+		"/output/preview/playlist.m3u8",
+		"/output/preview/segment_%03d.ts",
+		DefaultPreviewVariant(),
+		90*time.Second,
+	)
+
+	expectedArgs := []string{
+		"-i", "/input/video.mp4",
+		"-t", "90",
+		"-vf", "scale=-2:480",
+		"-c:v", "libx264",
+		"-preset", "fast",
+		"-b:v", "1200000",
+		"-c:a", "aac",
+		"-f", "hls",
+		"-hls_time", "6",
+		"-hls_list_size", "0",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", "/output/preview/segment_%03d.ts",
+		"-y",
+		"/output/preview/playlist.m3u8",
+	}
+
+	if len(args) != len(expectedArgs) {
+		t.Fatalf("arg count mismatch: got %d, expected %d", len(args), len(expectedArgs))
+	}
+
+	for i, expected := range expectedArgs {
+		if args[i] != expected {
+			t.Errorf("arg[%d]: got %q, expected %q", i, args[i], expected)
+		}
+	}
+}
+
+func TestFFmpegTranscoder_TranscodePreview_ValidationErrors(t *testing.T) {
+	transcoder := NewFFmpegTranscoder(DefaultFFmpegConfig())
+	ctx := context.Background()
+	variant := DefaultPreviewVariant()
+
+	t.Run("returns error for non-existent input", func(t *testing.T) {
+		_, err := transcoder.TranscodePreview(ctx, "/non/existent/input.mp4", t.TempDir(), variant, time.Minute)
+		if err == nil {
+			t.Error("expected error for non-existent input")
+		}
+	})
+
+	t.Run("returns error for non-positive duration", func(t *testing.T) {
+		inputFile := filepath.Join(t.TempDir(), "input.mp4")
+		if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+
+		_, err := transcoder.TranscodePreview(ctx, inputFile, t.TempDir(), variant, 0)
+		if err == nil {
+			t.Error("expected error for zero duration")
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package transcoder
+
+import "fmt"
+
+// OutputFormats selects the streaming formats produced for each video.
+type OutputFormats string
+
+const (
+	// OutputFormatsHLS produces HLS only.
+	OutputFormatsHLS OutputFormats = "hls"
+	// OutputFormatsDASH produces MPEG-DASH only.
+	OutputFormatsDASH OutputFormats = "dash"
+	// OutputFormatsBoth produces HLS and MPEG-DASH from separate encodes of the same ladder.
+	OutputFormatsBoth OutputFormats = "both"
+)
+
+// ParseOutputFormats validates an output format name. An empty name selects OutputFormatsHLS.
+func ParseOutputFormats(s string) (OutputFormats, error) {
+	switch formats := OutputFormats(s); formats {
+	case "":
+		return OutputFormatsHLS, nil
+	case OutputFormatsHLS, OutputFormatsDASH, OutputFormatsBoth:
+		return formats, nil
+	default:
+		return "", fmt.Errorf("unknown output formats %q (want %s, %s or %s)", s, OutputFormatsHLS, OutputFormatsDASH, OutputFormatsBoth)
+	}
+}
+
+// HLS reports whether HLS output is produced. The zero value produces HLS.
+func (f OutputFormats) HLS() bool {
+	return f != OutputFormatsDASH
+}
+
+// DASH reports whether MPEG-DASH output is produced.
+func (f OutputFormats) DASH() bool {
+	return f == OutputFormatsDASH || f == OutputFormatsBoth
+}
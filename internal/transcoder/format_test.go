@@ -0,0 +1,32 @@
+package transcoder
+
+import "testing"
+
+func TestParseOutputFormats(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     OutputFormats
+		wantHLS  bool
+		wantDASH bool
+		wantErr  bool
+	}{
+		{"", OutputFormatsHLS, true, false, false},
+		{"hls", OutputFormatsHLS, true, false, false},
+		{"dash", OutputFormatsDASH, false, true, false},
+		{"both", OutputFormatsBoth, true, true, false},
+		{"mpd", "", true, false, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseOutputFormats(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOutputFormats(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseOutputFormats(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got.HLS() != tt.wantHLS || got.DASH() != tt.wantDASH {
+			t.Errorf("ParseOutputFormats(%q): HLS() = %v, DASH() = %v", tt.in, got.HLS(), got.DASH())
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package transcoder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// jobLogOutputBytes caps the stderr one FFmpeg run contributes to a JobLog; the start
+// of longer output is dropped, as FFmpeg prints the cause of a failure last.
+const jobLogOutputBytes = 1 << 20
+
+// JobLog collects the stderr of every FFmpeg run of one transcode job. Each run is
+// written as a section headed by its command line and ending with its exit status.
+// Sections are written whole once a run exits, so the output of variants encoded in
+// parallel does not interleave. It is safe for concurrent use.
+type JobLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewJobLog creates a JobLog writing to w.
+func NewJobLog(w io.Writer) *JobLog {
+	return &JobLog{w: w}
+}
+
+// Err returns the first error writing to the log; later sections are dropped after it.
+func (l *JobLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// writeSection appends the output of the run named name, e.g. a variant name.
+func (l *JobLog) writeSection(name string, args []string, output []byte, runErr error) {
+	result := "ok"
+	if runErr != nil {
+		result = runErr.Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "==> %s: %s\n", name, strings.Join(args, " "))
+	b.Write(output)
+	if len(output) > 0 && output[len(output)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "<== %s: %s\n\n", name, result)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return
+	}
+	_, l.err = io.WriteString(l.w, b.String())
+}
+
+type jobLogKey struct{}
+
+// WithJobLog returns a context whose FFmpeg runs also write their stderr to log.
+func WithJobLog(ctx context.Context, log *JobLog) context.Context {
+	return context.WithValue(ctx, jobLogKey{}, log)
+}
+
+// jobLogFrom returns the JobLog of ctx, or nil.
+func jobLogFrom(ctx context.Context) *JobLog {
+	log, _ := ctx.Value(jobLogKey{}).(*JobLog)
+	return log
+}
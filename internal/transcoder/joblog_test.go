@@ -0,0 +1,92 @@
+package transcoder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobLog_WritesSectionPerRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	inputFile := filepath.Join(t.TempDir(), "input.mp4")
+	if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		script     string
+		wantErr    bool
+		wantOutput string
+		wantResult string
+	}{
+		{
+			name:       "successful run",
+			script:     "#!/bin/sh\necho 'ffmpeg version 7.0' >&2\nfor a; do case \"$a\" in *.jpg) touch \"$a\";; esac; done\n",
+			wantOutput: "ffmpeg version 7.0\n",
+			wantResult: "<== thumbnails: ok\n",
+		},
+		{
+			name:       "failed run",
+			script:     "#!/bin/sh\nprintf 'Conversion failed!' >&2\nexit 1\n",
+			wantErr:    true,
+			wantOutput: "Conversion failed!\n",
+			wantResult: "<== thumbnails: exit status 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+			if err := os.WriteFile(ffmpeg, []byte(tt.script), 0755); err != nil {
+				t.Fatalf("failed to write fake ffmpeg: %v", err)
+			}
+			cfg := DefaultFFmpegConfig()
+			cfg.FFmpegPath = ffmpeg
+
+			var buf bytes.Buffer
+			log := NewJobLog(&buf)
+			ctx := WithJobLog(context.Background(), log)
+			_, err := NewFFmpegTranscoder(cfg).GenerateThumbnails(ctx, inputFile, t.TempDir(), time.Second, DefaultThumbnailSizes())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateThumbnails() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if log.Err() != nil {
+				t.Fatalf("Err() = %v", log.Err())
+			}
+
+			got := buf.String()
+			if !strings.HasPrefix(got, "==> thumbnails: "+ffmpeg+" ") {
+				t.Errorf("section does not start with the command line: %q", got)
+			}
+			if !strings.Contains(got, "\n"+tt.wantOutput+tt.wantResult) {
+				t.Errorf("section lacks output %q and result %q: %q", tt.wantOutput, tt.wantResult, got)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestJobLog_KeepsFirstWriteError(t *testing.T) {
+	log := NewJobLog(failingWriter{})
+	log.writeSection("720p", []string{"ffmpeg"}, []byte("output"), nil)
+	log.writeSection("480p", []string{"ffmpeg"}, []byte("output"), nil)
+
+	if err := log.Err(); err == nil || err.Error() != "disk full" {
+		t.Errorf("Err() = %v, want disk full", err)
+	}
+}
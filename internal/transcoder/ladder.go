@@ -0,0 +1,138 @@
+package transcoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+// DefaultABRProfile is the name of the built-in ladder returned by DefaultABRVariants.
+const DefaultABRProfile = "default"
+
+// ErrUnknownABRProfile is returned when a ladder is requested by a name that is not configured.
+var ErrUnknownABRProfile = errs.New(errs.Invalid, "unknown ABR profile")
+
+// ladderName restricts profile and variant names to characters that are safe in storage
+// keys, since variant names become directory names of the output.
+var ladderName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,31}$`)
+
+// ABRProfiles holds named ABR ladders, so videos with different content (e.g., screen
+// recordings and film) can be encoded with different variants. Ladders are ordered
+// tallest first, like DefaultABRVariants.
+type ABRProfiles struct {
+	ladders     map[string][]Variant
+	defaultName string
+}
+
+// DefaultABRProfiles returns the built-in ladder alone, under DefaultABRProfile.
+func DefaultABRProfiles() *ABRProfiles {
+	return &ABRProfiles{
+		ladders:     map[string][]Variant{DefaultABRProfile: DefaultABRVariants()},
+		defaultName: DefaultABRProfile,
+	}
+}
+
+// variantSpec is the JSON form of a Variant.
+type variantSpec struct {
+	Name         string `json:"name"`
+	Height       int    `json:"height"`
+	Bitrate      int    `json:"bitrate"`
+	AudioBitrate int    `json:"audio_bitrate"`
+}
+
+// ParseABRProfiles parses ladders given as a JSON object mapping profile names to lists of
+// variants, e.g. {"screen": [{"name": "1080p", "height": 1080, "bitrate": 2000000,
+// "audio_bitrate": 64000}]}. Bitrates are in bits per second; a zero audio bitrate keeps
+// the encoder's default. The built-in ladder stays available as DefaultABRProfile unless
+// data redefines it. An empty data yields DefaultABRProfiles. defaultName selects the
+// ladder used when a task names none; empty means DefaultABRProfile.
+func ParseABRProfiles(data string, defaultName string) (*ABRProfiles, error) {
+	profiles := DefaultABRProfiles()
+	if defaultName != "" {
+		profiles.defaultName = defaultName
+	}
+
+	if data != "" {
+		var specs map[string][]variantSpec
+		dec := json.NewDecoder(strings.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&specs); err != nil {
+			return nil, fmt.Errorf("parse ABR profiles: %w", err)
+		}
+		for name, spec := range specs {
+			ladder, err := parseLadder(name, spec)
+			if err != nil {
+				return nil, err
+			}
+			profiles.ladders[name] = ladder
+		}
+	}
+
+	if _, ok := profiles.ladders[profiles.defaultName]; !ok {
+		return nil, fmt.Errorf("default ABR profile %q is not defined", profiles.defaultName)
+	}
+	return profiles, nil
+}
+
+// parseLadder validates one profile and orders it tallest first.
+func parseLadder(profile string, specs []variantSpec) ([]Variant, error) {
+	if !ladderName.MatchString(profile) {
+		return nil, fmt.Errorf("ABR profile name %q must be 1-32 letters, digits, '-' or '_'", profile)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("ABR profile %q has no variants", profile)
+	}
+
+	ladder := make([]Variant, 0, len(specs))
+	for _, s := range specs {
+		switch {
+		case !ladderName.MatchString(s.Name):
+			return nil, fmt.Errorf("ABR profile %q: variant name %q must be 1-32 letters, digits, '-' or '_'", profile, s.Name)
+		case slices.ContainsFunc(ladder, func(v Variant) bool { return v.Name == s.Name }):
+			return nil, fmt.Errorf("ABR profile %q: duplicate variant %q", profile, s.Name)
+		case s.Height <= 0 || s.Height%2 != 0:
+			return nil, fmt.Errorf("ABR profile %q: variant %q height must be a positive even number", profile, s.Name)
+		case s.Bitrate <= 0:
+			return nil, fmt.Errorf("ABR profile %q: variant %q bitrate must be positive", profile, s.Name)
+		case s.AudioBitrate < 0:
+			return nil, fmt.Errorf("ABR profile %q: variant %q audio bitrate cannot be negative", profile, s.Name)
+		}
+		ladder = append(ladder, Variant{Name: s.Name, Height: s.Height, Bitrate: s.Bitrate, AudioBitrate: s.AudioBitrate})
+	}
+
+	sort.SliceStable(ladder, func(i, j int) bool { return ladder[i].Height > ladder[j].Height })
+	return ladder, nil
+}
+
+// Ladder returns a copy of the named ladder; an empty name selects the default profile.
+// Returns ErrUnknownABRProfile if no ladder has the name.
+func (p *ABRProfiles) Ladder(name string) ([]Variant, error) {
+	if name == "" {
+		name = p.defaultName
+	}
+	ladder, ok := p.ladders[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownABRProfile, name)
+	}
+	return slices.Clone(ladder), nil
+}
+
+// DefaultName returns the profile used when a task names none.
+func (p *ABRProfiles) DefaultName() string {
+	return p.defaultName
+}
+
+// Names returns the configured profile names in sorted order.
+func (p *ABRProfiles) Names() []string {
+	names := make([]string, 0, len(p.ladders))
+	for name := range p.ladders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,145 @@
+package transcoder
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseABRProfiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		defaultName string
+		wantNames   []string
+		wantDefault string
+		wantErr     string
+	}{
+		{
+			name:        "empty keeps the built-in ladder",
+			wantNames:   []string{DefaultABRProfile},
+			wantDefault: DefaultABRProfile,
+		},
+		{
+			name:        "profiles are added next to the built-in ladder",
+			data:        `{"screen": [{"name": "1080p", "height": 1080, "bitrate": 2000000, "audio_bitrate": 64000}]}`,
+			wantNames:   []string{DefaultABRProfile, "screen"},
+			wantDefault: DefaultABRProfile,
+		},
+		{
+			name:        "configured default",
+			data:        `{"film": [{"name": "2160p", "height": 2160, "bitrate": 16000000}]}`,
+			defaultName: "film",
+			wantNames:   []string{DefaultABRProfile, "film"},
+			wantDefault: "film",
+		},
+		{
+			name:        "undefined default",
+			defaultName: "film",
+			wantErr:     `default ABR profile "film" is not defined`,
+		},
+		{
+			name:    "malformed JSON",
+			data:    `{"screen": [`,
+			wantErr: "parse ABR profiles",
+		},
+		{
+			name:    "unknown field",
+			data:    `{"screen": [{"name": "720p", "height": 720, "bitrate": 1000000, "fps": 30}]}`,
+			wantErr: "unknown field",
+		},
+		{
+			name:    "empty profile",
+			data:    `{"screen": []}`,
+			wantErr: "has no variants",
+		},
+		{
+			name:    "unsafe profile name",
+			data:    `{"../screen": [{"name": "720p", "height": 720, "bitrate": 1000000}]}`,
+			wantErr: "profile name",
+		},
+		{
+			name:    "unsafe variant name",
+			data:    `{"screen": [{"name": "720p/..", "height": 720, "bitrate": 1000000}]}`,
+			wantErr: "variant name",
+		},
+		{
+			name:    "duplicate variant",
+			data:    `{"screen": [{"name": "720p", "height": 720, "bitrate": 1000000}, {"name": "720p", "height": 720, "bitrate": 800000}]}`,
+			wantErr: "duplicate variant",
+		},
+		{
+			name:    "odd height",
+			data:    `{"screen": [{"name": "719p", "height": 719, "bitrate": 1000000}]}`,
+			wantErr: "positive even number",
+		},
+		{
+			name:    "missing bitrate",
+			data:    `{"screen": [{"name": "720p", "height": 720}]}`,
+			wantErr: "bitrate must be positive",
+		},
+		{
+			name:    "negative audio bitrate",
+			data:    `{"screen": [{"name": "720p", "height": 720, "bitrate": 1000000, "audio_bitrate": -1}]}`,
+			wantErr: "audio bitrate cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles, err := ParseABRProfiles(tt.data, tt.defaultName)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseABRProfiles() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseABRProfiles() error = %v", err)
+			}
+			if got := profiles.Names(); !slices.Equal(got, tt.wantNames) {
+				t.Errorf("Names() = %v, want %v", got, tt.wantNames)
+			}
+			if got := profiles.DefaultName(); got != tt.wantDefault {
+				t.Errorf("DefaultName() = %q, want %q", got, tt.wantDefault)
+			}
+		})
+	}
+}
+
+func TestABRProfiles_Ladder(t *testing.T) {
+	profiles, err := ParseABRProfiles(`{"screen": [
+		{"name": "480p", "height": 480, "bitrate": 600000},
+		{"name": "1080p", "height": 1080, "bitrate": 2000000, "audio_bitrate": 64000}
+	]}`, "")
+	if err != nil {
+		t.Fatalf("ParseABRProfiles() error = %v", err)
+	}
+
+	ladder, err := profiles.Ladder("screen")
+	if err != nil {
+		t.Fatalf("Ladder() error = %v", err)
+	}
+	want := []Variant{
+		{Name: "1080p", Height: 1080, Bitrate: 2000000, AudioBitrate: 64000},
+		{Name: "480p", Height: 480, Bitrate: 600000},
+	}
+	if !slices.Equal(ladder, want) {
+		t.Errorf("Ladder() = %v, want %v (tallest first)", ladder, want)
+	}
+
+	// The caller owns the returned slice
+	ladder[0].Bitrate = 1
+	if again, _ := profiles.Ladder("screen"); again[0].Bitrate != 2000000 {
+		t.Error("Ladder() must return a copy")
+	}
+
+	if ladder, err := profiles.Ladder(""); err != nil || !slices.Equal(ladder, DefaultABRVariants()) {
+		t.Errorf("Ladder(\"\") = %v, %v, want the default ladder", ladder, err)
+	}
+
+	if _, err := profiles.Ladder("film"); !errors.Is(err, ErrUnknownABRProfile) {
+		t.Errorf("Ladder(\"film\") error = %v, want ErrUnknownABRProfile", err)
+	}
+}
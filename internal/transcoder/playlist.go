@@ -0,0 +1,60 @@
+package transcoder
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// PlaylistOrder controls the order of variants in the master playlist.
+type PlaylistOrder string
+
+const (
+	// PlaylistOrderHighestFirst lists variants from highest to lowest bandwidth.
+	PlaylistOrderHighestFirst PlaylistOrder = "highest-first"
+	// PlaylistOrderBandwidthAscending lists variants from lowest to highest bandwidth,
+	// so players that start on the first entry begin at the cheapest rung.
+	PlaylistOrderBandwidthAscending PlaylistOrder = "bandwidth-ascending"
+)
+
+// ParsePlaylistOrder validates a playlist order name. An empty name selects
+// PlaylistOrderHighestFirst.
+func ParsePlaylistOrder(s string) (PlaylistOrder, error) {
+	switch order := PlaylistOrder(s); order {
+	case "":
+		return PlaylistOrderHighestFirst, nil
+	case PlaylistOrderHighestFirst, PlaylistOrderBandwidthAscending:
+		return order, nil
+	default:
+		return "", fmt.Errorf("unknown playlist order %q (want %s or %s)", s, PlaylistOrderHighestFirst, PlaylistOrderBandwidthAscending)
+	}
+}
+
+// orderVariants returns the variants in the order they are listed in the master
+// playlist. HLS has no DEFAULT attribute for variant streams - clients start on the
+// first one listed - so the variant named defaultVariant, if present, is moved to the
+// top. Audio-only variants otherwise go last in either order, so players never start on
+// a stream without picture. The input slice is not modified.
+func orderVariants(variants []VariantOutput, order PlaylistOrder, defaultVariant string) []VariantOutput {
+	ordered := slices.Clone(variants)
+	slices.SortStableFunc(ordered, func(a, b VariantOutput) int {
+		if a.Variant.AudioOnly != b.Variant.AudioOnly {
+			if a.Variant.AudioOnly {
+				return 1
+			}
+			return -1
+		}
+		if order == PlaylistOrderBandwidthAscending {
+			return cmp.Compare(a.Variant.Bitrate, b.Variant.Bitrate)
+		}
+		return cmp.Compare(b.Variant.Bitrate, a.Variant.Bitrate)
+	})
+
+	if i := slices.IndexFunc(ordered, func(v VariantOutput) bool { return v.Variant.Name == defaultVariant }); i > 0 {
+		first := ordered[i]
+		copy(ordered[1:i+1], ordered[:i])
+		ordered[0] = first
+	}
+
+	return ordered
+}
@@ -0,0 +1,134 @@
+package transcoder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePlaylistOrder(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    PlaylistOrder
+		wantErr bool
+	}{
+		{"", PlaylistOrderHighestFirst, false},
+		{"highest-first", PlaylistOrderHighestFirst, false},
+		{"bandwidth-ascending", PlaylistOrderBandwidthAscending, false},
+		{"lowest-first", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePlaylistOrder(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePlaylistOrder(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParsePlaylistOrder(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOrderVariants(t *testing.T) {
+	// Deliberately out of order to show the order does not depend on the input
+	variants := []VariantOutput{
+		{Variant: Variant{Name: "720p", Height: 720, Bitrate: 2500000}},
+		{Variant: Variant{Name: "360p", Height: 360, Bitrate: 800000}},
+		{Variant: Variant{Name: "1080p", Height: 1080, Bitrate: 5000000}},
+	}
+
+	tests := []struct {
+		name           string
+		order          PlaylistOrder
+		defaultVariant string
+		want           string
+	}{
+		{"highest first", PlaylistOrderHighestFirst, "", "1080p,720p,360p"},
+		{"unset order is highest first", "", "", "1080p,720p,360p"},
+		{"bandwidth ascending", PlaylistOrderBandwidthAscending, "", "360p,720p,1080p"},
+		{"default moved to top", PlaylistOrderHighestFirst, "720p", "720p,1080p,360p"},
+		{"default already first", PlaylistOrderBandwidthAscending, "360p", "360p,720p,1080p"},
+		{"default not produced", PlaylistOrderBandwidthAscending, "2160p", "360p,720p,1080p"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, v := range orderVariants(variants, tt.order, tt.defaultVariant) {
+				names = append(names, v.Variant.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("orderVariants() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if variants[0].Variant.Name != "720p" || variants[2].Variant.Name != "1080p" {
+		t.Error("orderVariants() modified its input")
+	}
+}
+
+func TestOrderVariants_AudioOnly(t *testing.T) {
+	variants := []VariantOutput{
+		{Variant: AudioOnlyVariant(64000)},
+		{Variant: Variant{Name: "720p", Height: 720, Bitrate: 2500000}},
+		{Variant: Variant{Name: "360p", Height: 360, Bitrate: 800000}},
+	}
+
+	tests := []struct {
+		name           string
+		order          PlaylistOrder
+		defaultVariant string
+		want           string
+	}{
+		{"last when highest first", PlaylistOrderHighestFirst, "", "720p,360p,audio"},
+		{"last when bandwidth ascending", PlaylistOrderBandwidthAscending, "", "360p,720p,audio"},
+		{"first when default", PlaylistOrderBandwidthAscending, "audio", "audio,360p,720p"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, v := range orderVariants(variants, tt.order, tt.defaultVariant) {
+				names = append(names, v.Variant.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("orderVariants() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFFmpegTranscoder_GenerateMasterPlaylist_Order(t *testing.T) {
+	cfg := DefaultFFmpegConfig()
+	cfg.PlaylistOrder = PlaylistOrderBandwidthAscending
+	cfg.DefaultVariant = "720p"
+	transcoder := NewFFmpegTranscoder(cfg)
+
+	masterPath := filepath.Join(t.TempDir(), "master.m3u8")
+	err := transcoder.generateMasterPlaylist(masterPath, []VariantOutput{
+		{Variant: Variant{Name: "1080p", Height: 1080, Bitrate: 5000000}},
+		{Variant: Variant{Name: "720p", Height: 720, Bitrate: 2500000}},
+		{Variant: Variant{Name: "360p", Height: 360, Bitrate: 800000}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(masterPath)
+	if err != nil {
+		t.Fatalf("failed to read master playlist: %v", err)
+	}
+
+	var uris []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasSuffix(line, "/playlist.m3u8") {
+			uris = append(uris, line)
+		}
+	}
+	want := "720p/playlist.m3u8,360p/playlist.m3u8,1080p/playlist.m3u8"
+	if got := strings.Join(uris, ","); got != want {
+		t.Errorf("variant order = %s, want %s", got, want)
+	}
+}
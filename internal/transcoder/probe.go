@@ -0,0 +1,153 @@
+package transcoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+)
+
+// ErrNoVideoStream is returned when a probed input has no video stream.
+// It is coded errs.Invalid: the same input will never have one. Probe returns it with the
+// result for the rest of the input, so callers that accept audio-only sources can use it.
+var ErrNoVideoStream = errs.New(errs.Invalid, "input has no video stream")
+
+// ProbeResult describes a media input.
+type ProbeResult struct {
+	// Duration is the container duration.
+	Duration time.Duration
+	// Width and Height are the dimensions of the first video stream in pixels.
+	Width  int
+	Height int
+	// Codec is the codec of the first video stream as named by ffprobe (e.g., "h264").
+	Codec string
+	// Bitrate is the overall bitrate in bits per second; zero if unknown.
+	Bitrate int64
+	// FrameRate is the average frame rate of the first video stream; zero if unknown.
+	FrameRate float64
+	// HasAudio reports whether the input has at least one audio stream.
+	HasAudio bool
+}
+
+// Prober inspects media inputs without decoding them.
+type Prober interface {
+	// Probe reads the container and stream headers of input, which may be a local path
+	// or a URL (e.g., a presigned download URL). Returns ErrNoVideoStream, along with the
+	// partial result, if the input has no video.
+	Probe(ctx context.Context, input string) (*ProbeResult, error)
+}
+
+// FFprobe implements Prober using the ffprobe CLI.
+type FFprobe struct {
+	path string
+}
+
+// Compile-time verification that FFprobe implements Prober.
+var _ Prober = (*FFprobe)(nil)
+
+// NewFFprobe creates a Prober that runs the ffprobe binary at path.
+// If path is empty, "ffprobe" will be used (assumes it's in PATH).
+func NewFFprobe(path string) *FFprobe {
+	if path == "" {
+		path = "ffprobe"
+	}
+	return &FFprobe{path: path}
+}
+
+// Probe runs ffprobe with JSON output. Only headers are read, so probing a URL
+// fetches a small part of the object rather than the whole file.
+func (p *FFprobe) Probe(ctx context.Context, input string) (*ProbeResult, error) {
+	out, err := command(ctx, p.path,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		input,
+	).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("probe cancelled: %w", errs.Wrap(errs.Transient, ctx.Err()))
+		}
+		return nil, fmt.Errorf("ffprobe execution failed: %w", err)
+	}
+
+	return parseProbeOutput(out)
+}
+
+// parseProbeOutput extracts a ProbeResult from `ffprobe -print_format json` output.
+// ffprobe reports durations as decimal seconds and bitrates as integers, both in
+// strings, and frame rates as fractions such as "30000/1001".
+func parseProbeOutput(out []byte) (*ProbeResult, error) {
+	var probe struct {
+		Format struct {
+			Duration string `json:"duration"`
+			BitRate  string `json:"bit_rate"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType    string `json:"codec_type"`
+			CodecName    string `json:"codec_name"`
+			Width        int    `json:"width"`
+			Height       int    `json:"height"`
+			AvgFrameRate string `json:"avg_frame_rate"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	result := &ProbeResult{}
+	if probe.Format.Duration != "" {
+		seconds, err := strconv.ParseFloat(probe.Format.Duration, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", probe.Format.Duration, err)
+		}
+		result.Duration = time.Duration(seconds * float64(time.Second))
+	}
+	// Some containers (e.g., raw streams) have no overall bitrate; it is informational
+	// only, so an unparseable value is treated as unknown
+	if bitrate, err := strconv.ParseInt(probe.Format.BitRate, 10, 64); err == nil {
+		result.Bitrate = bitrate
+	}
+
+	hasVideo := false
+	for _, s := range probe.Streams {
+		switch s.CodecType {
+		case "video":
+			if !hasVideo {
+				result.Width, result.Height = s.Width, s.Height
+				result.Codec = s.CodecName
+				result.FrameRate = parseFrameRate(s.AvgFrameRate)
+				hasVideo = true
+			}
+		case "audio":
+			result.HasAudio = true
+		}
+	}
+	if !hasVideo {
+		return result, ErrNoVideoStream
+	}
+
+	return result, nil
+}
+
+// parseFrameRate converts an ffprobe rate such as "30000/1001" to frames per second.
+// Returns zero for "0/0", which ffprobe reports when the rate is unknown.
+func parseFrameRate(rate string) float64 {
+	num, den, ok := strings.Cut(rate, "/")
+	if !ok {
+		den = "1"
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
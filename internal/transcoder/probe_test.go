@@ -0,0 +1,92 @@
+package transcoder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseProbeOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    ProbeResult
+		wantErr error
+	}{
+		{
+			name: "video with audio",
+			out: `{
+				"streams": [
+					{"index": 0, "codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080, "avg_frame_rate": "30000/1001"},
+					{"index": 1, "codec_type": "audio", "codec_name": "aac"},
+					{"index": 2, "codec_type": "video", "codec_name": "mjpeg", "width": 320, "height": 180, "avg_frame_rate": "0/0"}
+				],
+				"format": {"duration": "12.500000", "bit_rate": "5243210"}
+			}`,
+			want: ProbeResult{
+				Duration:  12500 * time.Millisecond,
+				Width:     1920,
+				Height:    1080,
+				Codec:     "h264",
+				Bitrate:   5243210,
+				FrameRate: 30000.0 / 1001,
+				HasAudio:  true,
+			},
+		},
+		{
+			name: "video without duration",
+			out:  `{"streams": [{"codec_type": "video", "width": 640, "height": 360}], "format": {}}`,
+			want: ProbeResult{Width: 640, Height: 360},
+		},
+		{
+			name: "unknown frame rate and bitrate",
+			out:  `{"streams": [{"codec_type": "video", "codec_name": "vp9", "width": 640, "height": 360, "avg_frame_rate": "0/0"}], "format": {"bit_rate": "N/A"}}`,
+			want: ProbeResult{Width: 640, Height: 360, Codec: "vp9"},
+		},
+		{
+			name:    "audio only",
+			out:     `{"streams": [{"codec_type": "audio"}], "format": {"duration": "3.0"}}`,
+			want:    ProbeResult{Duration: 3 * time.Second, HasAudio: true},
+			wantErr: ErrNoVideoStream,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProbeOutput([]byte(tt.out))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("parseProbeOutput() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("parseProbeOutput() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("parseProbeOutput() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseProbeOutput([]byte(`{"format": {"duration": "N/A"}, "streams": []}`)); err == nil {
+		t.Error("parseProbeOutput() expected error for invalid duration")
+	}
+}
+
+func TestParseFrameRate(t *testing.T) {
+	tests := []struct {
+		rate string
+		want float64
+	}{
+		{"25/1", 25},
+		{"30000/1001", 30000.0 / 1001},
+		{"24", 24},
+		{"0/0", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseFrameRate(tt.rate); got != tt.want {
+			t.Errorf("parseFrameRate(%q) = %v, want %v", tt.rate, got, tt.want)
+		}
+	}
+}
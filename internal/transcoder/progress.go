@@ -0,0 +1,65 @@
+package transcoder
+
+import (
+	"bufio"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressFunc receives encode progress of one ABR variant: encoded is the position in
+// the source up to which the variant has been written. Variants may be encoded in
+// parallel, so implementations must be safe for concurrent use.
+type ProgressFunc func(variant string, encoded time.Duration)
+
+// progressArgs makes FFmpeg write machine-readable progress to stdout instead of
+// the human-readable stats line on stderr.
+var progressArgs = []string{"-progress", "pipe:1", "-nostats"}
+
+// readProgress parses FFmpeg -progress output from r, calling fn with the encoded
+// position of each progress block, until r is exhausted.
+//
+// Each block is a series of key=value lines ending in progress=continue or
+// progress=end. The position is read from out_time_us, falling back to out_time_ms,
+// which despite its name is also in microseconds; N/A values are skipped.
+func readProgress(r io.Reader, fn func(encoded time.Duration)) {
+	scanner := bufio.NewScanner(r)
+	position := time.Duration(-1)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "out_time_us", "out_time_ms":
+			us, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || us < 0 {
+				continue
+			}
+			if key == "out_time_us" || position < 0 {
+				position = time.Duration(us) * time.Microsecond
+			}
+		case "progress":
+			if position >= 0 {
+				fn(position)
+			}
+			position = -1
+		}
+	}
+}
+
+// runWithProgress runs cmd, passing its stdout to readProgress. Stdout is read to EOF
+// before waiting, as exec requires of StdoutPipe.
+func runWithProgress(cmd *exec.Cmd, fn func(encoded time.Duration)) error {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	readProgress(stdout, fn)
+	return cmd.Wait()
+}
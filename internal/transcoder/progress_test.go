@@ -0,0 +1,54 @@
+package transcoder
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadProgress(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []time.Duration
+	}{
+		{
+			name: "reports each block",
+			output: "frame=24\nout_time_us=1000000\nout_time_ms=1000000\nprogress=continue\n" +
+				"frame=48\nout_time_us=2500000\nout_time_ms=2500000\nprogress=end\n",
+			want: []time.Duration{time.Second, 2500 * time.Millisecond},
+		},
+		{
+			name:   "falls back to out_time_ms",
+			output: "out_time_ms=1500000\nprogress=continue\n",
+			want:   []time.Duration{1500 * time.Millisecond},
+		},
+		{
+			name:   "skips blocks without a position",
+			output: "out_time_us=N/A\nprogress=continue\nout_time_us=3000000\nprogress=end\n",
+			want:   []time.Duration{3 * time.Second},
+		},
+		{
+			name:   "ignores unrelated output",
+			output: "garbage\n\nout_time_us=-5\nprogress=continue\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []time.Duration
+			readProgress(strings.NewReader(tt.output), func(encoded time.Duration) {
+				got = append(got, encoded)
+			})
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, expected %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("block %d: got %v, expected %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
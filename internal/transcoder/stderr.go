@@ -0,0 +1,85 @@
+package transcoder
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+const (
+	// stderrTailBytes is how much of FFmpeg's stderr is kept while it runs. FFmpeg prints
+	// the cause of a failure last, after a banner and stats that may be much longer.
+	stderrTailBytes = 8 << 10
+
+	// stderrTailLines is how many of the kept lines an ExecError reports.
+	stderrTailLines = 5
+)
+
+// ExecError reports an FFmpeg run that exited with an error, with the last lines of its
+// stderr. It is not coded, so failed encodes are retried.
+type ExecError struct {
+	Err    error
+	Stderr string
+}
+
+func (e *ExecError) Error() string {
+	if e.Stderr == "" {
+		return "ffmpeg execution failed: " + e.Err.Error()
+	}
+	return fmt.Sprintf("ffmpeg execution failed: %v: %s", e.Err, e.Stderr)
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// tailBuffer is an io.Writer keeping the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if excess := len(b.buf) - b.max; excess > 0 {
+		b.buf = append(b.buf[:0], b.buf[excess:]...)
+	}
+	return len(p), nil
+}
+
+// Lines returns the last stderrTailLines non-empty lines, joined by " | ". Stats lines
+// end in a carriage return, so both line endings split.
+func (b *tailBuffer) Lines() string {
+	fields := strings.FieldsFunc(string(b.buf), func(r rune) bool { return r == '\n' || r == '\r' })
+	lines := make([]string, 0, stderrTailLines)
+	for i := len(fields) - 1; i >= 0 && len(lines) < stderrTailLines; i-- {
+		if line := strings.TrimSpace(fields[i]); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	slices.Reverse(lines)
+	return strings.Join(lines, " | ")
+}
+
+// captureStderr sets the stderr of cmd to a tail buffer for execError. When ctx carries
+// a JobLog, more of the output is kept and the returned func, called with the result
+// once cmd exits, writes it to the log as the section name.
+func captureStderr(ctx context.Context, cmd *exec.Cmd, name string) (*tailBuffer, func(error)) {
+	log := jobLogFrom(ctx)
+	if log == nil {
+		stderr := &tailBuffer{max: stderrTailBytes}
+		cmd.Stderr = stderr
+		return stderr, func(error) {}
+	}
+
+	stderr := &tailBuffer{max: jobLogOutputBytes}
+	cmd.Stderr = stderr
+	return stderr, func(err error) { log.writeSection(name, cmd.Args, stderr.buf, err) }
+}
+
+// execError wraps the error of a failed FFmpeg run with its stderr tail.
+func execError(err error, stderr *tailBuffer) error {
+	return &ExecError{Err: err, Stderr: stderr.Lines()}
+}
@@ -0,0 +1,85 @@
+package transcoder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailBuffer_Lines(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name:   "last lines only",
+			writes: []string{"ffmpeg version 7.0\n", "line 1\nline 2\n", "line 3\nline 4\nline 5\nline 6\n"},
+			want:   "line 2 | line 3 | line 4 | line 5 | line 6",
+		},
+		{
+			name:   "stats lines end in carriage returns",
+			writes: []string{"frame=  10 fps=0.0\rframe=  20 fps=0.0\r\n", "\n", "Conversion failed!\n"},
+			want:   "frame=  10 fps=0.0 | frame=  20 fps=0.0 | Conversion failed!",
+		},
+		{
+			name:   "only the tail is kept",
+			writes: []string{strings.Repeat("x", stderrTailBytes), "\ncause\n"},
+			want:   strings.Repeat("x", stderrTailBytes-len("\ncause\n")) + " | cause",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tailBuffer{max: stderrTailBytes}
+			for _, w := range tt.writes {
+				if n, err := b.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write() = %d, %v", n, err)
+				}
+			}
+			if len(b.buf) > stderrTailBytes {
+				t.Errorf("kept %d bytes, want at most %d", len(b.buf), stderrTailBytes)
+			}
+			if got := b.Lines(); got != tt.want {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFFmpegTranscoder_GenerateThumbnails_ExecError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	inputFile := filepath.Join(t.TempDir(), "input.mp4")
+	if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+	script := "#!/bin/sh\necho 'ffmpeg version 7.0' >&2\necho 'input.mp4: Invalid data found when processing input' >&2\nexit 1\n"
+	if err := os.WriteFile(ffmpeg, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+	cfg := DefaultFFmpegConfig()
+	cfg.FFmpegPath = ffmpeg
+
+	_, err := NewFFmpegTranscoder(cfg).GenerateThumbnails(context.Background(), inputFile, t.TempDir(), time.Second, DefaultThumbnailSizes())
+
+	var execErr *ExecError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("GenerateThumbnails() error = %v, want an ExecError", err)
+	}
+	if execErr.Stderr != "ffmpeg version 7.0 | input.mp4: Invalid data found when processing input" {
+		t.Errorf("Stderr = %q", execErr.Stderr)
+	}
+}
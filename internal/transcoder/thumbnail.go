@@ -0,0 +1,109 @@
+package transcoder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ThumbnailSize is one width a poster frame is scaled to; the height follows the source
+// aspect ratio.
+type ThumbnailSize struct {
+	// Name identifies the size (e.g., "small") and names the output file.
+	Name string
+	// Width is the image width in pixels.
+	Width int
+}
+
+// ThumbnailOutput is a generated poster image.
+type ThumbnailOutput struct {
+	Size ThumbnailSize
+	// Path is the path to the JPEG file.
+	Path string
+}
+
+// DefaultThumbnailSizes returns the poster sizes generated for every output, smallest first.
+// Small suits list views, medium cards, and large the player poster.
+func DefaultThumbnailSizes() []ThumbnailSize {
+	return []ThumbnailSize{
+		{Name: "small", Width: 320},
+		{Name: "medium", Width: 640},
+		{Name: "large", Width: 1280},
+	}
+}
+
+// GenerateThumbnails extracts the frame at the given offset and writes one JPEG per size
+// in a single FFmpeg process. Files are named {size}.jpg.
+func (t *FFmpegTranscoder) GenerateThumbnails(ctx context.Context, inputPath, outputDir string, at time.Duration, sizes []ThumbnailSize) ([]ThumbnailOutput, error) {
+	if err := t.validateInput(inputPath); err != nil {
+		return nil, err
+	}
+
+	if err := t.validateOutputDir(outputDir); err != nil {
+		return nil, err
+	}
+
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("at least one thumbnail size is required")
+	}
+
+	outputs := make([]ThumbnailOutput, len(sizes))
+	for i, size := range sizes {
+		outputs[i] = ThumbnailOutput{Size: size, Path: filepath.Join(outputDir, size.Name+".jpg")}
+	}
+
+	cmd := command(ctx, t.config.FFmpegPath, buildThumbnailFFmpegArgs(inputPath, at, outputs)...)
+	cmd.Stdout = nil
+	stderr, logged := captureStderr(ctx, cmd, "thumbnails")
+
+	err := cmd.Run()
+	logged(err)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("thumbnail generation cancelled: %w", ctx.Err())
+		}
+		return nil, execError(err, stderr)
+	}
+
+	// FFmpeg exits successfully without writing a frame when the offset is past the end
+	for _, output := range outputs {
+		if _, err := os.Stat(output.Path); err != nil {
+			return nil, fmt.Errorf("thumbnail %s not generated: %w", output.Size.Name, err)
+		}
+	}
+
+	return outputs, nil
+}
+
+// buildThumbnailFFmpegArgs constructs FFmpeg arguments that decode a single frame and
+// scale it once per output. -ss before -i seeks the input rather than decoding up to it.
+func buildThumbnailFFmpegArgs(inputPath string, at time.Duration, outputs []ThumbnailOutput) []string {
+	var filter strings.Builder
+	fmt.Fprintf(&filter, "[0:v]split=%d", len(outputs))
+	for i := range outputs {
+		fmt.Fprintf(&filter, "[s%d]", i)
+	}
+	for i, output := range outputs {
+		fmt.Fprintf(&filter, ";[s%d]scale=%d:-2[t%d]", i, output.Size.Width, i)
+	}
+
+	args := []string{
+		"-y",
+		"-ss", strconv.FormatFloat(at.Seconds(), 'f', -1, 64),
+		"-i", inputPath,
+		"-filter_complex", filter.String(),
+	}
+	for i, output := range outputs {
+		args = append(args,
+			"-map", fmt.Sprintf("[t%d]", i),
+			"-frames:v", "1",
+			"-q:v", "3",
+			output.Path,
+		)
+	}
+	return args
+}
@@ -0,0 +1,97 @@
+package transcoder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBuildThumbnailFFmpegArgs(t *testing.T) {
+	args := buildThumbnailFFmpegArgs("/input/video.mp4", 2500*time.Millisecond, []ThumbnailOutput{
+		{Size: ThumbnailSize{Name: "small", Width: 320}, Path: "/out/small.jpg"},
+		{Size: ThumbnailSize{Name: "large", Width: 1280}, Path: "/out/large.jpg"},
+	})
+
+	expectedArgs := []string{
+		"-y",
+		"-ss", "2.5",
+		"-i", "/input/video.mp4",
+		"-filter_complex", "[0:v]split=2[s0][s1];[s0]scale=320:-2[t0];[s1]scale=1280:-2[t1]",
+		"-map", "[t0]", "-frames:v", "1", "-q:v", "3", "/out/small.jpg",
+		"-map", "[t1]", "-frames:v", "1", "-q:v", "3", "/out/large.jpg",
+	}
+
+	if !slices.Equal(args, expectedArgs) {
+		t.Errorf("args mismatch:\ngot:      %q\nexpected: %q", args, expectedArgs)
+	}
+}
+
+func TestFFmpegTranscoder_GenerateThumbnails(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	inputFile := filepath.Join(t.TempDir(), "input.mp4")
+	if err := os.WriteFile(inputFile, []byte("dummy"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		script  string
+		sizes   []ThumbnailSize
+		wantErr bool
+	}{
+		{
+			name: "one image per size",
+			// Creates every .jpg argument
+			script: "#!/bin/sh\nfor a; do case \"$a\" in *.jpg) touch \"$a\";; esac; done\n",
+			sizes:  DefaultThumbnailSizes(),
+		},
+		{
+			name:    "offset past the end writes nothing",
+			script:  "#!/bin/sh\nexit 0\n",
+			sizes:   DefaultThumbnailSizes(),
+			wantErr: true,
+		},
+		{
+			name:    "no sizes",
+			script:  "#!/bin/sh\nexit 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+			if err := os.WriteFile(ffmpeg, []byte(tt.script), 0755); err != nil {
+				t.Fatalf("failed to write fake ffmpeg: %v", err)
+			}
+			cfg := DefaultFFmpegConfig()
+			cfg.FFmpegPath = ffmpeg
+			transcoder := NewFFmpegTranscoder(cfg)
+
+			outputDir := t.TempDir()
+			outputs, err := transcoder.GenerateThumbnails(context.Background(), inputFile, outputDir, time.Second, tt.sizes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateThumbnails() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(outputs) != len(tt.sizes) {
+				t.Fatalf("expected %d outputs, got %d", len(tt.sizes), len(outputs))
+			}
+			for i, output := range outputs {
+				if want := filepath.Join(outputDir, tt.sizes[i].Name+".jpg"); output.Path != want || output.Size != tt.sizes[i] {
+					t.Errorf("output %d: got %+v, expected %s", i, output, want)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package transcoder
 
 import (
 	"context"
+	"time"
 )
 
 // HLSOutput contains the result of an HLS transcoding operation.
@@ -20,6 +21,36 @@ type Variant struct {
 	Height int
 	// Bitrate is the target bitrate in bits per second, used in master playlist.
 	Bitrate int
+	// AudioBitrate is the audio bitrate in bits per second; zero keeps the encoder's default.
+	AudioBitrate int
+	// AudioOnly marks an AAC-only rendition without video; Height and Bitrate are unused.
+	AudioOnly bool
+	// KeyInfoPath is an FFmpeg key info file (key URI, key file path, optional IV) that
+	// encrypts the segments with AES-128; empty leaves them in the clear.
+	KeyInfoPath string
+}
+
+// AudioOnlyVariantName is the name, and output directory, of the audio-only rendition.
+const AudioOnlyVariantName = "audio"
+
+// DefaultAudioOnlyBitrate is the audio-only rendition bitrate used when none is configured.
+const DefaultAudioOnlyBitrate = 64000
+
+// AudioOnlyVariant returns the audio-only rendition at the given bitrate in bits per second.
+// It is listed in the master playlist like a video variant, so players on very poor
+// connections can keep the audio going, and it is the whole output of audio-only sources.
+func AudioOnlyVariant(bitrate int) Variant {
+	return Variant{Name: AudioOnlyVariantName, AudioBitrate: bitrate, AudioOnly: true}
+}
+
+// Width returns the variant's width assuming a 16:9 aspect ratio, rounded up to an even value
+// (codec requirement). This is an approximation; the encoded width depends on the source.
+func (v Variant) Width() int {
+	width := v.Height * 16 / 9
+	if width%2 != 0 {
+		width++
+	}
+	return width
 }
 
 // VariantOutput contains the result for a single quality variant.
@@ -30,6 +61,11 @@ type VariantOutput struct {
 	ManifestPath string
 	// SegmentPaths contains paths to all .ts segment files for this variant.
 	SegmentPaths []string
+	// Codec is the video encoder used for this variant (e.g., "libx264"), or the audio
+	// encoder for an audio-only variant.
+	Codec string
+	// Duration is the wall-clock time spent encoding this variant.
+	Duration time.Duration
 }
 
 // ABROutput contains the result of a multi-bitrate transcoding operation.
@@ -38,6 +74,10 @@ type ABROutput struct {
 	MasterManifestPath string
 	// Variants contains output information for each quality level.
 	Variants []VariantOutput
+	// ProbeDuration is the time spent inspecting the input before encoding started.
+	ProbeDuration time.Duration
+	// Source describes the probed input; nil when probing is disabled.
+	Source *ProbeResult
 }
 
 // Transcoder defines the interface for video transcoding operations.
@@ -66,6 +106,7 @@ type Transcoder interface {
 	//   - inputPath: Absolute path to the source video file
 	//   - outputDir: Directory where HLS files will be generated
 	//   - variants: Quality variants to generate (e.g., 1080p, 720p, 360p)
+	//   - progress: Called as each variant's encode advances; may be nil
 	//
 	// Returns:
 	//   - ABROutput containing paths to master manifest and all variant outputs
@@ -73,5 +114,56 @@ type Transcoder interface {
 	//
 	// The output directory must exist before calling this method.
 	// Each variant will be placed in a subdirectory named after the variant (e.g., outputDir/720p/).
-	TranscodeToABR(ctx context.Context, inputPath, outputDir string, variants []Variant) (*ABROutput, error)
+	TranscodeToABR(ctx context.Context, inputPath, outputDir string, variants []Variant, progress ProgressFunc) (*ABROutput, error)
+
+	// TranscodePreview converts the first duration of an input video to a single-variant HLS rendition.
+	// Previews are served without entitlement checks, so they are kept short and at a modest quality.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//   - inputPath: Absolute path to the source video file
+	//   - outputDir: Directory where HLS files will be generated
+	//   - variant: Quality to encode the preview at
+	//   - duration: Length of the preview from the start of the video
+	//
+	// Returns:
+	//   - HLSOutput containing paths to the preview playlist and segments
+	//   - error if transcoding fails
+	//
+	// The output directory must exist before calling this method.
+	TranscodePreview(ctx context.Context, inputPath, outputDir string, variant Variant, duration time.Duration) (*HLSOutput, error)
+
+	// TranscodeToDASH converts an input video file to MPEG-DASH with one representation per variant.
+	// It generates a manifest.mpd and fMP4 initialization and media segments in outputDir.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//   - inputPath: Absolute path to the source video file
+	//   - outputDir: Directory where DASH files will be generated
+	//   - variants: Quality variants to generate (e.g., 1080p, 720p, 360p)
+	//   - progress: Called as the encode advances; may be nil
+	//
+	// Returns:
+	//   - DASHOutput containing paths to the manifest and all segments
+	//   - error if transcoding fails
+	//
+	// The output directory must exist before calling this method. File names do not collide
+	// with TranscodeToABR output, so both may share a directory.
+	TranscodeToDASH(ctx context.Context, inputPath, outputDir string, variants []Variant, progress ProgressFunc) (*DASHOutput, error)
+
+	// GenerateThumbnails writes a JPEG poster of the frame at the given offset for each size.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//   - inputPath: Absolute path to the source video file
+	//   - outputDir: Directory where the images will be written
+	//   - at: Offset of the frame into the video
+	//   - sizes: Image sizes to generate
+	//
+	// Returns:
+	//   - One ThumbnailOutput per size, in the order given
+	//   - error if no frame could be extracted
+	//
+	// The output directory must exist before calling this method.
+	GenerateThumbnails(ctx context.Context, inputPath, outputDir string, at time.Duration, sizes []ThumbnailSize) ([]ThumbnailOutput, error)
 }
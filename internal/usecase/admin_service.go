@@ -0,0 +1,602 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const (
+	// DefaultTranscodeJobsLimit is the number of jobs returned when no limit is given.
+	DefaultTranscodeJobsLimit = 20
+	// MaxTranscodeJobsLimit caps the number of jobs returned in one request.
+	MaxTranscodeJobsLimit = 100
+
+	// DefaultVideosLimit is the number of videos returned when no limit is given.
+	DefaultVideosLimit = 50
+	// MaxVideosLimit caps the number of videos returned in one request.
+	MaxVideosLimit = 500
+
+	// DefaultHotKeysLimit is the number of hot keys returned when no limit is given.
+	DefaultHotKeysLimit = 20
+	// MaxHotKeysLimit caps the number of hot keys returned in one request.
+	MaxHotKeysLimit = 1000
+)
+
+var (
+	// ErrInvalidDateRange is returned when created_after is not before created_before.
+	ErrInvalidDateRange = errors.New("created_after must be before created_before")
+	// ErrQueueStatsDisabled is returned when no queue monitor is configured.
+	ErrQueueStatsDisabled = errors.New("queue statistics are not enabled")
+	// ErrTranscodeLogNotFound is returned when a transcode job has no FFmpeg log, as the
+	// worker's FFmpeg log mode did not upload it.
+	ErrTranscodeLogNotFound = errors.New("transcode job has no FFmpeg log")
+	// ErrForceReasonRequired is returned when a status is forced without a reason.
+	ErrForceReasonRequired = errors.New("a reason is required to force a status")
+	// ErrVideoNotProcessing is returned when requeueing the task of a video that is not PROCESSING.
+	ErrVideoNotProcessing = errors.New("video is not processing")
+	// ErrVideoCacheDisabled is returned when flushing the cache of a service without one.
+	ErrVideoCacheDisabled = errors.New("video cache is not enabled")
+)
+
+// AvailabilitySource reports request outcomes over a recent window.
+type AvailabilitySource interface {
+	// Counts returns the good and total request counts within window before now.
+	Counts(window time.Duration, now time.Time) (good, total int64)
+}
+
+// PopularitySource ranks cache keys by recent requests.
+// Implemented by metrics.KeyPopularity.
+type PopularitySource interface {
+	// Top returns up to n keys with the most hits at now, most requested first.
+	Top(n int, now time.Time) []metrics.KeyHits
+	// Window returns the counting period.
+	Window() time.Duration
+}
+
+// AdminServiceConfig holds configuration for AdminService.
+type AdminServiceConfig struct {
+	// TranscodeSuccessObjective is the target ratio of transcodes that reach READY.
+	TranscodeSuccessObjective float64
+	// TimeToReadyObjective is the target p95 time from processing request to READY.
+	TimeToReadyObjective time.Duration
+	// APIAvailabilityObjective is the target ratio of non-5xx API responses.
+	APIAvailabilityObjective float64
+	// SLOWindow is the snapshot window used when none is requested.
+	SLOWindow time.Duration
+	// MaxSLOWindow caps the requested window; it should not exceed the availability retention.
+	MaxSLOWindow time.Duration
+	// Queues are the task queues QueueStats reports, and DeadLetterQueue the queue rejected
+	// tasks end up in; an empty DeadLetterQueue is not reported.
+	Queues          []string
+	DeadLetterQueue string
+	// TaskTTL is how long requeued transcode tasks stay valid; zero never expires.
+	TaskTTL time.Duration
+}
+
+// DefaultAdminServiceConfig returns the default configuration.
+func DefaultAdminServiceConfig() AdminServiceConfig {
+	return AdminServiceConfig{
+		TranscodeSuccessObjective: 0.99,
+		TimeToReadyObjective:      10 * time.Minute,
+		APIAvailabilityObjective:  0.999,
+		SLOWindow:                 time.Hour,
+		MaxSLOWindow:              24 * time.Hour,
+	}
+}
+
+// RatioSLI is a good/total SLI evaluated against its objective.
+type RatioSLI struct {
+	Good  int64
+	Total int64
+	// Ratio is Good/Total, or 1 when Total is zero: no traffic consumes no error budget.
+	Ratio     float64
+	Objective float64
+	// BurnRate is how fast the error budget is being spent: 1 exhausts it exactly at the end
+	// of the SLO period; multi-window alerts typically page at 14.4 (1h) and ticket at 6 (6h).
+	BurnRate float64
+}
+
+// LatencySLI is a percentile SLI evaluated against its objective.
+type LatencySLI struct {
+	P95       time.Duration
+	Samples   int64
+	Objective time.Duration
+	// Met is true when P95 is within Objective, or when there are no samples.
+	Met bool
+}
+
+// SLOSnapshot is a point-in-time evaluation of every SLI over a window.
+type SLOSnapshot struct {
+	Window           time.Duration
+	GeneratedAt      time.Time
+	TranscodeSuccess RatioSLI
+	TimeToReady      LatencySLI
+	// APIAvailability covers only the API instance serving the snapshot.
+	APIAvailability RatioSLI
+}
+
+// HotKey is a video metadata cache key and its recent request count.
+type HotKey struct {
+	VideoID uuid.UUID
+	Hits    int64
+	// Class is metrics.KeyClassHot, KeyClassWarm or KeyClassCold.
+	Class string
+}
+
+// HotKeysSnapshot lists the most requested keys of the API instance serving it.
+type HotKeysSnapshot struct {
+	// Window is the counting period; hits cover up to two windows.
+	Window      time.Duration
+	GeneratedAt time.Time
+	Keys        []HotKey
+}
+
+// MessageAgeStats summarizes how long the sampled messages of a queue have waited.
+type MessageAgeStats struct {
+	Sampled int
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+// QueueSnapshot is the state of one task queue.
+type QueueSnapshot struct {
+	Name string
+	// Depth counts ready and unacknowledged messages.
+	Depth     int
+	Ready     int
+	Unacked   int
+	Consumers int
+	Age       MessageAgeStats
+}
+
+// QueuesSnapshot is the backlog of every task queue and the dead-letter queue.
+type QueuesSnapshot struct {
+	GeneratedAt time.Time
+	Queues      []QueueSnapshot
+	// DeadLetter is nil when no dead-letter queue is configured.
+	DeadLetter *QueueSnapshot
+}
+
+// ForceStatusInput holds the parameters for forcing a video's status.
+type ForceStatusInput struct {
+	Status model.Status
+	// Reason is recorded in the video's status history, and as the failure reason of a
+	// video forced into FAILED.
+	Reason string
+}
+
+// AdminService defines the interface for operator-facing diagnostics and repairs.
+type AdminService interface {
+	// ListTranscodeJobs returns the most recent transcode attempts for a video, newest first.
+	// A limit of zero uses DefaultTranscodeJobsLimit.
+	// Returns repository.ErrVideoNotFound if the video does not exist.
+	ListTranscodeJobs(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error)
+
+	// TranscodeJobLog opens the FFmpeg log of a transcode job; the caller must close it.
+	// Returns ErrTranscodeLogNotFound if the job has no log,
+	// and repository.ErrVideoNotFound if the video does not exist.
+	TranscodeJobLog(ctx context.Context, videoID, jobID uuid.UUID) (io.ReadCloser, error)
+
+	// ListVideos returns videos matching filter, newest first.
+	// A zero limit uses DefaultVideosLimit; larger limits are capped at MaxVideosLimit.
+	// Returns ErrInvalidDateRange if both bounds are set and do not form a range.
+	ListVideos(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error)
+
+	// ListRenditions returns the renditions of the video's published output, highest quality first.
+	// Returns an empty slice if the video has no published output yet.
+	// Returns repository.ErrVideoNotFound if the video does not exist.
+	ListRenditions(ctx context.Context, videoID uuid.UUID) ([]*model.Rendition, error)
+
+	// SLOSnapshot evaluates the SLIs over window.
+	// A zero window uses the configured default; larger windows are capped at MaxSLOWindow.
+	SLOSnapshot(ctx context.Context, window time.Duration) (*SLOSnapshot, error)
+
+	// VerifyOutput re-validates the video's published output against its checksum manifest.
+	// Returns ErrVideoNotReady if the video has no published output,
+	// ErrOutputChecksumsNotFound if the output has no manifest,
+	// and repository.ErrVideoNotFound if the video does not exist.
+	VerifyOutput(ctx context.Context, videoID uuid.UUID) (*OutputVerification, error)
+
+	// HotKeys returns the video cache keys requested most recently, most requested first.
+	// A zero limit uses DefaultHotKeysLimit; larger limits are capped at MaxHotKeysLimit.
+	// Returns no keys when the service has no popularity source.
+	HotKeys(ctx context.Context, limit int) (*HotKeysSnapshot, error)
+
+	// QueueStats reports the depth, consumers and message ages of the configured queues.
+	// Returns ErrQueueStatsDisabled when the service has no queue monitor.
+	QueueStats(ctx context.Context) (*QueuesSnapshot, error)
+
+	// ForceStatus moves a video to a status regardless of the transition rules, within
+	// the limits of model.Video.ForceStatus, and records the operator's reason.
+	// Returns ErrForceReasonRequired without a reason, model.ErrStatusNotForceable if
+	// the video cannot be forced into the status, and repository.ErrVideoNotFound if the
+	// video does not exist.
+	ForceStatus(ctx context.Context, videoID uuid.UUID, input ForceStatusInput) (*model.Video, error)
+
+	// RequeueTranscode publishes a new transcode task for a PROCESSING video whose task
+	// was lost, and returns it.
+	// Returns ErrVideoNotProcessing if the video is not PROCESSING,
+	// and repository.ErrVideoNotFound if the video does not exist.
+	RequeueTranscode(ctx context.Context, videoID uuid.UUID) (*repository.TranscodeTask, error)
+
+	// FlushVideoCache removes a video and its owner's first pages from the video cache.
+	// Returns ErrVideoCacheDisabled when the service has no cache,
+	// and repository.ErrVideoNotFound if the video does not exist.
+	FlushVideoCache(ctx context.Context, videoID uuid.UUID) error
+}
+
+type adminService struct {
+	videos       repository.VideoRepository
+	jobs         repository.TranscodeJobRepository
+	renditions   repository.RenditionRepository
+	storage      repository.ObjectStorage
+	availability AvailabilitySource
+	popularity   PopularitySource
+	queues       repository.QueueMonitor
+	tasks        repository.MessageQueue
+	cache        cache.VideoCache
+	events       cache.VideoEventBus
+	statusEvents repository.VideoStatusEventRepository
+	cfg          AdminServiceConfig
+}
+
+// NewAdminService creates a new AdminService instance.
+// The availability parameter is optional - pass nil to report API availability with no traffic.
+// The popularity parameter is optional - pass nil to report no hot keys.
+// The queues parameter is optional - pass nil to disable queue statistics.
+// The videoCache parameter is optional - pass nil when videos are not cached.
+// The events parameter is optional - pass nil to not publish status changes.
+// The statusEvents parameter is optional - pass nil to not record status transitions.
+func NewAdminService(
+	videos repository.VideoRepository,
+	jobs repository.TranscodeJobRepository,
+	renditions repository.RenditionRepository,
+	storage repository.ObjectStorage,
+	availability AvailabilitySource,
+	popularity PopularitySource,
+	queues repository.QueueMonitor,
+	tasks repository.MessageQueue,
+	videoCache cache.VideoCache,
+	events cache.VideoEventBus,
+	statusEvents repository.VideoStatusEventRepository,
+	cfg AdminServiceConfig,
+) AdminService {
+	return &adminService{
+		videos:       videos,
+		jobs:         jobs,
+		renditions:   renditions,
+		storage:      storage,
+		availability: availability,
+		popularity:   popularity,
+		queues:       queues,
+		tasks:        tasks,
+		cache:        videoCache,
+		events:       events,
+		statusEvents: statusEvents,
+		cfg:          cfg,
+	}
+}
+
+// ListTranscodeJobs checks the video exists so an unknown ID is distinguishable from one with no jobs yet.
+func (s *adminService) ListTranscodeJobs(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error) {
+	if _, err := s.videos.GetByID(ctx, videoID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultTranscodeJobsLimit
+	}
+	limit = min(limit, MaxTranscodeJobsLimit)
+
+	jobs, err := s.jobs.ListByVideoID(ctx, videoID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list transcode jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// TranscodeJobLog reads the log from primary storage, where workers upload it even for
+// tenants with an output bucket.
+func (s *adminService) TranscodeJobLog(ctx context.Context, videoID, jobID uuid.UUID) (io.ReadCloser, error) {
+	if _, err := s.videos.GetByID(ctx, videoID); err != nil {
+		return nil, err
+	}
+
+	log, err := s.storage.Download(ctx, FFmpegLogKey(videoID, jobID))
+	if errors.Is(err, repository.ErrObjectNotFound) {
+		return nil, ErrTranscodeLogNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("download transcode log: %w", err)
+	}
+
+	return log, nil
+}
+
+// ListVideos validates the filter and applies the default and maximum page size.
+func (s *adminService) ListVideos(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultVideosLimit
+	}
+	filter.Limit = min(filter.Limit, MaxVideosLimit)
+
+	videos, err := s.videos.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list videos: %w", err)
+	}
+
+	return videos, nil
+}
+
+// ListRenditions lists the renditions of the output version the video currently serves.
+// A regeneration in progress is not visible until its output is published.
+func (s *adminService) ListRenditions(ctx context.Context, videoID uuid.UUID) ([]*model.Rendition, error) {
+	video, err := s.videos.GetByID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !video.IsReady() {
+		return []*model.Rendition{}, nil
+	}
+
+	renditions, err := s.renditions.ListByVideoID(ctx, videoID, video.OutputVersion)
+	if err != nil {
+		return nil, fmt.Errorf("list renditions: %w", err)
+	}
+
+	return renditions, nil
+}
+
+// VerifyOutput reads the output back from primary storage; a replica is expected to be
+// verified by the replication path, not here.
+func (s *adminService) VerifyOutput(ctx context.Context, videoID uuid.UUID) (*OutputVerification, error) {
+	video, err := s.videos.GetByID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !video.IsReady() || (video.HLSURL == "" && video.DashURL == "") {
+		return nil, ErrVideoNotReady
+	}
+	if video.ExternalOutput {
+		return nil, ErrExternalOutput
+	}
+
+	return verifyOutput(ctx, s.storage, video)
+}
+
+// SLOSnapshot combines transcode outcomes from the job table with this instance's request counts.
+func (s *adminService) SLOSnapshot(ctx context.Context, window time.Duration) (*SLOSnapshot, error) {
+	if window <= 0 {
+		window = s.cfg.SLOWindow
+	}
+	if s.cfg.MaxSLOWindow > 0 {
+		window = min(window, s.cfg.MaxSLOWindow)
+	}
+
+	now := time.Now()
+	stats, err := s.jobs.Stats(ctx, now.Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("transcode job stats: %w", err)
+	}
+
+	var good, total int64
+	if s.availability != nil {
+		good, total = s.availability.Counts(window, now)
+	}
+
+	return &SLOSnapshot{
+		Window:           window,
+		GeneratedAt:      now,
+		TranscodeSuccess: newRatioSLI(stats.Succeeded, stats.Succeeded+stats.Failed, s.cfg.TranscodeSuccessObjective),
+		TimeToReady: LatencySLI{
+			P95:       stats.TimeToReadyP95,
+			Samples:   stats.TimeToReadySamples,
+			Objective: s.cfg.TimeToReadyObjective,
+			Met:       stats.TimeToReadySamples == 0 || stats.TimeToReadyP95 <= s.cfg.TimeToReadyObjective,
+		},
+		APIAvailability: newRatioSLI(good, total, s.cfg.APIAvailabilityObjective),
+	}, nil
+}
+
+// HotKeys reads the popularity counts of this process; keys that are not video IDs are skipped.
+func (s *adminService) HotKeys(ctx context.Context, limit int) (*HotKeysSnapshot, error) {
+	if limit <= 0 {
+		limit = DefaultHotKeysLimit
+	}
+	limit = min(limit, MaxHotKeysLimit)
+
+	now := time.Now()
+	snapshot := &HotKeysSnapshot{GeneratedAt: now, Keys: []HotKey{}}
+	if s.popularity == nil {
+		return snapshot, nil
+	}
+
+	snapshot.Window = s.popularity.Window()
+	for _, k := range s.popularity.Top(limit, now) {
+		videoID, err := uuid.Parse(k.Key)
+		if err != nil {
+			continue
+		}
+		snapshot.Keys = append(snapshot.Keys, HotKey{VideoID: videoID, Hits: k.Hits, Class: k.Class})
+	}
+	return snapshot, nil
+}
+
+// QueueStats asks the broker for every queue in turn; one failing queue fails the snapshot.
+func (s *adminService) QueueStats(ctx context.Context) (*QueuesSnapshot, error) {
+	if s.queues == nil {
+		return nil, ErrQueueStatsDisabled
+	}
+
+	snapshot := &QueuesSnapshot{GeneratedAt: time.Now(), Queues: make([]QueueSnapshot, 0, len(s.cfg.Queues))}
+	for _, name := range s.cfg.Queues {
+		q, err := s.queueSnapshot(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Queues = append(snapshot.Queues, *q)
+	}
+
+	if s.cfg.DeadLetterQueue != "" {
+		q, err := s.queueSnapshot(ctx, s.cfg.DeadLetterQueue)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.DeadLetter = q
+	}
+
+	return snapshot, nil
+}
+
+func (s *adminService) queueSnapshot(ctx context.Context, name string) (*QueueSnapshot, error) {
+	stats, err := s.queues.QueueStats(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("queue %s stats: %w", name, err)
+	}
+
+	return &QueueSnapshot{
+		Name:      name,
+		Depth:     stats.Ready + stats.Unacked,
+		Ready:     stats.Ready,
+		Unacked:   stats.Unacked,
+		Consumers: stats.Consumers,
+		Age:       newMessageAgeStats(stats.MessageAges),
+	}, nil
+}
+
+// newMessageAgeStats computes nearest-rank percentiles of ages.
+func newMessageAgeStats(ages []time.Duration) MessageAgeStats {
+	if len(ages) == 0 {
+		return MessageAgeStats{}
+	}
+
+	sorted := slices.Clone(ages)
+	slices.Sort(sorted)
+	percentile := func(p float64) time.Duration {
+		rank := int(math.Ceil(p * float64(len(sorted))))
+		return sorted[max(rank, 1)-1]
+	}
+
+	return MessageAgeStats{
+		Sampled: len(sorted),
+		P50:     percentile(0.50),
+		P90:     percentile(0.90),
+		P99:     percentile(0.99),
+		Max:     sorted[len(sorted)-1],
+	}
+}
+
+// newRatioSLI computes the ratio and burn rate for a good/total SLI.
+func newRatioSLI(good, total int64, objective float64) RatioSLI {
+	sli := RatioSLI{Good: good, Total: total, Ratio: 1, Objective: objective}
+	if total > 0 {
+		sli.Ratio = float64(good) / float64(total)
+	}
+	if budget := 1 - objective; budget > 0 {
+		sli.BurnRate = (1 - sli.Ratio) / budget
+	}
+	return sli
+}
+
+// ForceStatus is meant for videos no component will move on its own. Forcing a video
+// out of PROCESSING while a worker still transcodes it makes the worker's final
+// transition fail, so its output is never published.
+func (s *adminService) ForceStatus(ctx context.Context, videoID uuid.UUID, input ForceStatusInput) (*model.Video, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, ErrForceReasonRequired
+	}
+
+	video, err := s.videos.GetByID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := video.Status
+	if err := video.ForceStatus(input.Status, reason); err != nil {
+		return nil, err
+	}
+	if err := s.videos.Update(ctx, video); err != nil {
+		return nil, fmt.Errorf("update video status: %w", err)
+	}
+
+	slog.InfoContext(ctx, "forced video status",
+		"video_id", video.ID,
+		"from", from,
+		"to", video.Status,
+		"reason", reason,
+	)
+	recordStatusEvent(ctx, s.statusEvents, video, from, model.StatusActorAdmin, reason)
+	invalidateVideo(ctx, s.cache, video)
+	publishStatus(ctx, s.events, video)
+
+	return video, nil
+}
+
+// RequeueTranscode builds the task like TaskReconciler does, so the original request's
+// ladder overrides fall back to the worker's defaults. A task that was not lost after
+// all is transcoded twice, which the worker handles like a redelivery.
+func (s *adminService) RequeueTranscode(ctx context.Context, videoID uuid.UUID) (*repository.TranscodeTask, error) {
+	video, err := s.videos.GetByID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.Status != model.StatusProcessing {
+		return nil, ErrVideoNotProcessing
+	}
+
+	task := buildTranscodeTask(video, time.Now(), s.cfg.TaskTTL)
+	task.EnqueuedAt = video.UpdatedAt
+	if err := s.tasks.PublishTranscodeTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("publish transcode task: %w", err)
+	}
+
+	slog.InfoContext(ctx, "requeued transcode task",
+		"video_id", video.ID,
+		"output_version", task.OutputVersion,
+	)
+	return &task, nil
+}
+
+// FlushVideoCache returns cache errors, unlike the invalidations that follow updates,
+// since the operator asked for the flush.
+func (s *adminService) FlushVideoCache(ctx context.Context, videoID uuid.UUID) error {
+	if s.cache == nil {
+		return ErrVideoCacheDisabled
+	}
+
+	video, err := s.videos.GetByID(ctx, videoID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cache.Delete(ctx, video.ID); err != nil {
+		return fmt.Errorf("delete cached video: %w", err)
+	}
+	if err := s.cache.DeleteFirstPages(ctx, video.UserID); err != nil {
+		return fmt.Errorf("delete cached video lists: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,680 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+func TestAdminService_ListTranscodeJobs(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name      string
+		limit     int
+		getErr    error
+		listErr   error
+		wantLimit int
+		wantErr   error
+	}{
+		{name: "default limit", limit: 0, wantLimit: DefaultTranscodeJobsLimit},
+		{name: "explicit limit", limit: 5, wantLimit: 5},
+		{name: "limit is capped", limit: 1000, wantLimit: MaxTranscodeJobsLimit},
+		{name: "unknown video", getErr: repository.ErrVideoNotFound, wantErr: repository.ErrVideoNotFound},
+		{name: "repository error", listErr: errors.New("db down"), wantLimit: DefaultTranscodeJobsLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return &model.Video{ID: id}, nil
+				},
+			}
+
+			gotLimit := 0
+			jobs := &mockTranscodeJobRepository{
+				listByVideoIDFn: func(ctx context.Context, id uuid.UUID, limit int) ([]*model.TranscodeJob, error) {
+					gotLimit = limit
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					return []*model.TranscodeJob{{VideoID: id}}, nil
+				},
+			}
+
+			svc := NewAdminService(videos, jobs, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+			result, err := svc.ListTranscodeJobs(context.Background(), videoID, tt.limit)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+			case tt.listErr != nil:
+				if err == nil {
+					t.Error("expected error")
+				}
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(result) != 1 {
+					t.Errorf("expected 1 job, got %d", len(result))
+				}
+			}
+			if gotLimit != tt.wantLimit {
+				t.Errorf("limit: got %d, expected %d", gotLimit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestAdminService_TranscodeJobLog(t *testing.T) {
+	videoID := uuid.New()
+	jobID := uuid.New()
+
+	tests := []struct {
+		name        string
+		getErr      error
+		downloadErr error
+		wantErr     error
+	}{
+		{name: "log uploaded"},
+		{name: "unknown video", getErr: repository.ErrVideoNotFound, wantErr: repository.ErrVideoNotFound},
+		{name: "no log", downloadErr: repository.ErrObjectNotFound, wantErr: ErrTranscodeLogNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return &model.Video{ID: id}, nil
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					if want := "logs/" + videoID.String() + "/" + jobID.String() + ".log"; key != want {
+						t.Errorf("key: got %q, expected %q", key, want)
+					}
+					if tt.downloadErr != nil {
+						return nil, tt.downloadErr
+					}
+					return io.NopCloser(strings.NewReader("ffmpeg output")), nil
+				},
+			}
+
+			svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, storage, nil, nil, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+			log, err := svc.TranscodeJobLog(context.Background(), videoID, jobID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer log.Close()
+			if data, _ := io.ReadAll(log); string(data) != "ffmpeg output" {
+				t.Errorf("log: got %q", data)
+			}
+		})
+	}
+}
+
+func TestAdminService_ListVideos(t *testing.T) {
+	jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    repository.VideoFilter
+		wantLimit int
+		wantErr   error
+	}{
+		{name: "default limit", wantLimit: DefaultVideosLimit},
+		{name: "limit is capped", filter: repository.VideoFilter{Limit: 10000}, wantLimit: MaxVideosLimit},
+		{name: "date range", filter: repository.VideoFilter{CreatedAfter: jan, CreatedBefore: feb, Limit: 5}, wantLimit: 5},
+		{name: "open-ended range", filter: repository.VideoFilter{CreatedAfter: feb}, wantLimit: DefaultVideosLimit},
+		{name: "inverted range", filter: repository.VideoFilter{CreatedAfter: feb, CreatedBefore: jan}, wantErr: ErrInvalidDateRange},
+		{name: "empty range", filter: repository.VideoFilter{CreatedAfter: jan, CreatedBefore: jan}, wantErr: ErrInvalidDateRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got repository.VideoFilter
+			videos := &mockVideoRepository{
+				listFn: func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+					got = filter
+					return []*model.Video{{ID: uuid.New()}}, nil
+				},
+			}
+
+			svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+			_, err := svc.ListVideos(context.Background(), tt.filter)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Limit != tt.wantLimit {
+				t.Errorf("limit: got %d, expected %d", got.Limit, tt.wantLimit)
+			}
+			if !got.CreatedAfter.Equal(tt.filter.CreatedAfter) || !got.CreatedBefore.Equal(tt.filter.CreatedBefore) {
+				t.Errorf("date range not passed through: %+v", got)
+			}
+		})
+	}
+}
+
+func TestAdminService_ListRenditions(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name        string
+		status      model.Status
+		getErr      error
+		wantVersion int64
+		wantCount   int
+		wantErr     error
+	}{
+		{name: "published output", status: model.StatusReady, wantVersion: 3, wantCount: 1},
+		{name: "not yet published", status: model.StatusProcessing, wantCount: 0},
+		{name: "unknown video", getErr: repository.ErrVideoNotFound, wantErr: repository.ErrVideoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return &model.Video{ID: id, Status: tt.status, OutputVersion: 3}, nil
+				},
+			}
+
+			var gotVersion int64
+			renditions := &mockRenditionRepository{
+				listByVideoIDFn: func(ctx context.Context, id uuid.UUID, outputVersion int64) ([]*model.Rendition, error) {
+					gotVersion = outputVersion
+					return []*model.Rendition{model.NewRendition(id, outputVersion, "720p")}, nil
+				},
+			}
+
+			svc := NewAdminService(videos, &mockTranscodeJobRepository{}, renditions, &mockObjectStorage{}, nil, nil, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+			result, err := svc.ListRenditions(context.Background(), videoID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result) != tt.wantCount {
+				t.Errorf("expected %d renditions, got %d", tt.wantCount, len(result))
+			}
+			if gotVersion != tt.wantVersion {
+				t.Errorf("output version: got %d, expected %d", gotVersion, tt.wantVersion)
+			}
+		})
+	}
+}
+
+// mockAvailabilitySource returns fixed request counts.
+type mockAvailabilitySource struct {
+	good, total int64
+	gotWindow   time.Duration
+}
+
+func (m *mockAvailabilitySource) Counts(window time.Duration, now time.Time) (int64, int64) {
+	m.gotWindow = window
+	return m.good, m.total
+}
+
+func TestAdminService_SLOSnapshot(t *testing.T) {
+	cfg := AdminServiceConfig{
+		TranscodeSuccessObjective: 0.99,
+		TimeToReadyObjective:      10 * time.Minute,
+		APIAvailabilityObjective:  0.999,
+		SLOWindow:                 time.Hour,
+		MaxSLOWindow:              24 * time.Hour,
+	}
+
+	tests := []struct {
+		name               string
+		window             time.Duration
+		stats              *repository.TranscodeJobStats
+		statsErr           error
+		good, total        int64
+		wantWindow         time.Duration
+		wantTranscodeRatio float64
+		wantTranscodeBurn  float64
+		wantAvailability   float64
+		wantAvailBurn      float64
+		wantTimeToReadyMet bool
+		wantErr            bool
+	}{
+		{
+			name:               "healthy",
+			stats:              &repository.TranscodeJobStats{Succeeded: 99, Failed: 1, TimeToReadyP95: 5 * time.Minute, TimeToReadySamples: 99},
+			good:               9990,
+			total:              10000,
+			wantWindow:         time.Hour,
+			wantTranscodeRatio: 0.99,
+			wantTranscodeBurn:  1,
+			wantAvailability:   0.999,
+			wantAvailBurn:      1,
+			wantTimeToReadyMet: true,
+		},
+		{
+			name:               "burning budget",
+			window:             6 * time.Hour,
+			stats:              &repository.TranscodeJobStats{Succeeded: 90, Failed: 10, TimeToReadyP95: 20 * time.Minute, TimeToReadySamples: 90},
+			good:               900,
+			total:              1000,
+			wantWindow:         6 * time.Hour,
+			wantTranscodeRatio: 0.9,
+			wantTranscodeBurn:  10,
+			wantAvailability:   0.9,
+			wantAvailBurn:      100,
+		},
+		{
+			name:               "no traffic burns no budget",
+			window:             7 * 24 * time.Hour,
+			stats:              &repository.TranscodeJobStats{},
+			wantWindow:         24 * time.Hour,
+			wantTranscodeRatio: 1,
+			wantAvailability:   1,
+			wantTimeToReadyMet: true,
+		},
+		{
+			name:     "stats error",
+			statsErr: errors.New("db down"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &mockTranscodeJobRepository{
+				statsFn: func(ctx context.Context, since time.Time) (*repository.TranscodeJobStats, error) {
+					return tt.stats, tt.statsErr
+				},
+			}
+			availability := &mockAvailabilitySource{good: tt.good, total: tt.total}
+
+			svc := NewAdminService(&mockVideoRepository{}, jobs, &mockRenditionRepository{}, &mockObjectStorage{}, availability, nil, nil, nil, nil, nil, nil, cfg)
+			snapshot, err := svc.SLOSnapshot(context.Background(), tt.window)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SLOSnapshot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if snapshot.Window != tt.wantWindow || availability.gotWindow != tt.wantWindow {
+				t.Errorf("window: got %v (availability %v), expected %v", snapshot.Window, availability.gotWindow, tt.wantWindow)
+			}
+			assertFloat(t, "transcode ratio", snapshot.TranscodeSuccess.Ratio, tt.wantTranscodeRatio)
+			assertFloat(t, "transcode burn rate", snapshot.TranscodeSuccess.BurnRate, tt.wantTranscodeBurn)
+			assertFloat(t, "availability ratio", snapshot.APIAvailability.Ratio, tt.wantAvailability)
+			assertFloat(t, "availability burn rate", snapshot.APIAvailability.BurnRate, tt.wantAvailBurn)
+			if snapshot.TimeToReady.Met != tt.wantTimeToReadyMet {
+				t.Errorf("time to ready met: got %v, expected %v", snapshot.TimeToReady.Met, tt.wantTimeToReadyMet)
+			}
+		})
+	}
+}
+
+func assertFloat(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s: got %v, expected %v", name, got, want)
+	}
+}
+
+func TestAdminService_HotKeys(t *testing.T) {
+	popularity := metrics.NewKeyPopularity(metrics.KeyPopularityConfig{Window: time.Hour, HotHits: 3, WarmHits: 2, MaxKeys: 100})
+	hot, warm := uuid.New(), uuid.New()
+	now := time.Now()
+	for range 3 {
+		popularity.Record(hot.String(), now)
+	}
+	for range 2 {
+		popularity.Record(warm.String(), now)
+	}
+	popularity.Record("not-a-video-id", now)
+
+	tests := []struct {
+		name       string
+		popularity PopularitySource
+		limit      int
+		want       []HotKey
+	}{
+		{
+			name:       "most requested first",
+			popularity: popularity,
+			want: []HotKey{
+				{VideoID: hot, Hits: 3, Class: metrics.KeyClassHot},
+				{VideoID: warm, Hits: 2, Class: metrics.KeyClassWarm},
+			},
+		},
+		{
+			name:       "limit",
+			popularity: popularity,
+			limit:      1,
+			want:       []HotKey{{VideoID: hot, Hits: 3, Class: metrics.KeyClassHot}},
+		},
+		{name: "no popularity source", want: []HotKey{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewAdminService(&mockVideoRepository{}, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, tt.popularity, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+
+			snapshot, err := svc.HotKeys(context.Background(), tt.limit)
+			if err != nil {
+				t.Fatalf("HotKeys() error = %v", err)
+			}
+			if !slices.Equal(snapshot.Keys, tt.want) {
+				t.Errorf("keys: got %+v, expected %+v", snapshot.Keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminService_QueueStats(t *testing.T) {
+	ages := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		ages = append(ages, time.Duration(i)*time.Second)
+	}
+	stats := map[string]*repository.QueueStats{
+		"transcode_tasks":      {Ready: 100, Unacked: 3, Consumers: 2, MessageAges: ages},
+		"video_deletes":        {Consumers: 1},
+		"transcode_tasks_dead": {Ready: 7},
+	}
+	cfg := DefaultAdminServiceConfig()
+	cfg.Queues = []string{"transcode_tasks", "video_deletes"}
+	cfg.DeadLetterQueue = "transcode_tasks_dead"
+
+	tests := []struct {
+		name     string
+		monitor  repository.QueueMonitor
+		cfg      AdminServiceConfig
+		wantErr  error
+		wantDead bool
+	}{
+		{
+			name: "every queue and the dead-letter queue",
+			monitor: &mockQueueMonitor{
+				queueStatsFn: func(ctx context.Context, name string) (*repository.QueueStats, error) {
+					return stats[name], nil
+				},
+			},
+			cfg:      cfg,
+			wantDead: true,
+		},
+		{
+			name: "missing queue fails the snapshot",
+			monitor: &mockQueueMonitor{
+				queueStatsFn: func(ctx context.Context, name string) (*repository.QueueStats, error) {
+					return nil, repository.ErrQueueNotFound
+				},
+			},
+			cfg:     cfg,
+			wantErr: repository.ErrQueueNotFound,
+		},
+		{
+			name:    "no monitor",
+			cfg:     cfg,
+			wantErr: ErrQueueStatsDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewAdminService(&mockVideoRepository{}, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, tt.monitor, nil, nil, nil, nil, tt.cfg)
+
+			snapshot, err := svc.QueueStats(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("QueueStats() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("QueueStats() unexpected error: %v", err)
+			}
+
+			if len(snapshot.Queues) != 2 {
+				t.Fatalf("queues: got %d, expected 2", len(snapshot.Queues))
+			}
+			tasks := snapshot.Queues[0]
+			if tasks.Name != "transcode_tasks" || tasks.Depth != 103 || tasks.Consumers != 2 {
+				t.Errorf("transcode queue: %+v", tasks)
+			}
+			wantAge := MessageAgeStats{Sampled: 100, P50: 50 * time.Second, P90: 90 * time.Second, P99: 99 * time.Second, Max: 100 * time.Second}
+			if tasks.Age != wantAge {
+				t.Errorf("ages: got %+v, expected %+v", tasks.Age, wantAge)
+			}
+			if snapshot.Queues[1].Age != (MessageAgeStats{}) {
+				t.Errorf("empty queue ages: %+v", snapshot.Queues[1].Age)
+			}
+			if (snapshot.DeadLetter != nil) != tt.wantDead || snapshot.DeadLetter.Depth != 7 {
+				t.Errorf("dead letter: %+v", snapshot.DeadLetter)
+			}
+		})
+	}
+}
+
+func TestAdminService_ForceStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    model.Status
+		input     ForceStatusInput
+		getErr    error
+		wantErr   error
+		wantEvent bool
+	}{
+		{
+			name:      "stuck processing video",
+			status:    model.StatusProcessing,
+			input:     ForceStatusInput{Status: model.StatusUploaded, Reason: "task lost in broker outage"},
+			wantEvent: true,
+		},
+		{
+			name:    "reason required",
+			status:  model.StatusProcessing,
+			input:   ForceStatusInput{Status: model.StatusUploaded, Reason: "  "},
+			wantErr: ErrForceReasonRequired,
+		},
+		{
+			name:    "status not forceable",
+			status:  model.StatusDeleted,
+			input:   ForceStatusInput{Status: model.StatusUploaded, Reason: "undelete"},
+			wantErr: model.ErrStatusNotForceable,
+		},
+		{
+			name:    "video not found",
+			input:   ForceStatusInput{Status: model.StatusUploaded, Reason: "task lost"},
+			getErr:  repository.ErrVideoNotFound,
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: uuid.New(), UserID: uuid.New(), Status: tt.status}
+			var updated bool
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					updated = true
+					return nil
+				},
+			}
+			var event *model.VideoStatusEvent
+			statusEvents := &mockVideoStatusEventRepository{
+				createFn: func(ctx context.Context, e *model.VideoStatusEvent) error {
+					event = e
+					return nil
+				},
+			}
+			videoCache := newMockVideoCache()
+			videoCache.data[video.ID] = video
+
+			svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, nil, nil, videoCache, nil, statusEvents, DefaultAdminServiceConfig())
+			got, err := svc.ForceStatus(context.Background(), video.ID, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ForceStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if updated || event != nil {
+					t.Error("a rejected force must not change the video")
+				}
+				return
+			}
+
+			if got.Status != tt.input.Status || !updated {
+				t.Errorf("status = %s (updated %v), want %s persisted", got.Status, updated, tt.input.Status)
+			}
+			if event == nil || event.Actor != model.StatusActorAdmin || event.From != tt.status || event.Reason != tt.input.Reason {
+				t.Errorf("status event = %+v, want an admin event from %s", event, tt.status)
+			}
+			if _, ok := videoCache.data[video.ID]; ok {
+				t.Error("forced video must be removed from cache")
+			}
+		})
+	}
+}
+
+func TestAdminService_RequeueTranscode(t *testing.T) {
+	processingSince := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name       string
+		status     model.Status
+		publishErr error
+		wantErr    error
+	}{
+		{name: "processing video", status: model.StatusProcessing},
+		{name: "ready video", status: model.StatusReady, wantErr: ErrVideoNotProcessing},
+		{name: "publish error", status: model.StatusProcessing, publishErr: errors.New("channel closed"), wantErr: errors.New("publish transcode task")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: uuid.New(), Status: tt.status, OriginalURL: "originals/v/video.mp4", UpdatedAt: processingSince}
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			var published []repository.TranscodeTask
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					published = append(published, task)
+					return tt.publishErr
+				},
+			}
+
+			cfg := DefaultAdminServiceConfig()
+			cfg.TaskTTL = time.Hour
+			svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, nil, queue, nil, nil, nil, cfg)
+			task, err := svc.RequeueTranscode(context.Background(), video.ID)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("RequeueTranscode() unexpected error: %v", err)
+			case tt.wantErr != nil && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())):
+				t.Fatalf("RequeueTranscode() error = %v, want %v", err, tt.wantErr)
+			case tt.wantErr != nil:
+				return
+			}
+
+			if len(published) != 1 || published[0].VideoID != video.ID {
+				t.Fatalf("published %+v, want one task for the video", published)
+			}
+			if !task.EnqueuedAt.Equal(processingSince) {
+				t.Errorf("enqueued at %v, want the start of processing %v", task.EnqueuedAt, processingSince)
+			}
+			if task.ExpiresAt.IsZero() || task.OutputVersion <= 0 {
+				t.Errorf("task = %+v, want an expiry and a new output version", task)
+			}
+		})
+	}
+}
+
+func TestAdminService_FlushVideoCache(t *testing.T) {
+	video := &model.Video{ID: uuid.New(), UserID: uuid.New(), Status: model.StatusReady}
+	videos := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return video, nil
+		},
+	}
+
+	t.Run("flushes video and first pages", func(t *testing.T) {
+		videoCache := newMockVideoCache()
+		videoCache.data[video.ID] = video
+		videoCache.pages[video.UserID] = map[int]*cache.VideoPage{20: {}}
+
+		svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, nil, nil, videoCache, nil, nil, DefaultAdminServiceConfig())
+		if err := svc.FlushVideoCache(context.Background(), video.ID); err != nil {
+			t.Fatalf("FlushVideoCache() unexpected error: %v", err)
+		}
+		if len(videoCache.data) != 0 || len(videoCache.pages) != 0 {
+			t.Errorf("cache still holds %d videos and %d page sets", len(videoCache.data), len(videoCache.pages))
+		}
+	})
+
+	t.Run("cache error", func(t *testing.T) {
+		videoCache := newMockVideoCache()
+		videoCache.deleteFn = func(ctx context.Context, videoID uuid.UUID) error {
+			return errors.New("connection refused")
+		}
+
+		svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, nil, nil, videoCache, nil, nil, DefaultAdminServiceConfig())
+		if err := svc.FlushVideoCache(context.Background(), video.ID); err == nil {
+			t.Error("FlushVideoCache() expected an error")
+		}
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, &mockObjectStorage{}, nil, nil, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+		if err := svc.FlushVideoCache(context.Background(), video.ID); !errors.Is(err, ErrVideoCacheDisabled) {
+			t.Errorf("FlushVideoCache() error = %v, want %v", err, ErrVideoCacheDisabled)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// ErrOverloaded is returned when non-essential work is shed to protect interactive uploads.
+// The concrete error is an *OverloadedError carrying the suggested retry delay.
+var ErrOverloaded = errors.New("transcode pipeline is overloaded")
+
+// OverloadedError describes why work was rejected and when the caller should retry.
+type OverloadedError struct {
+	// Reason is the signal that tripped, e.g. "transcode failure rate 35.0% exceeds 20.0%".
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *OverloadedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrOverloaded, e.Reason)
+}
+
+func (e *OverloadedError) Unwrap() error {
+	return ErrOverloaded
+}
+
+// WorkClass classifies queued work for admission control.
+type WorkClass string
+
+const (
+	// WorkInteractive is user-driven work such as processing a fresh upload. It is never shed.
+	WorkInteractive WorkClass = "interactive"
+	// WorkBackground is deferrable work such as reprocesses and bulk backfills.
+	WorkBackground WorkClass = "background"
+)
+
+// AdmissionController decides whether new transcode work may be enqueued.
+type AdmissionController interface {
+	// Admit returns nil if work of the given class may proceed, or an *OverloadedError.
+	Admit(ctx context.Context, class WorkClass) error
+}
+
+// AdmissionConfig holds configuration for the admission controller.
+type AdmissionConfig struct {
+	// MaxFailureRatio sheds background work when the final transcode failure ratio exceeds it.
+	// Zero disables the failure-rate signal.
+	MaxFailureRatio float64
+	// FailureWindow is how far back final transcode outcomes are considered.
+	FailureWindow time.Duration
+	// MinSamples is the number of outcomes required before the failure ratio is trusted.
+	MinSamples int64
+	// MaxBacklog sheds background work when more tasks than this are waiting in the queue.
+	// Zero disables the backlog signal.
+	MaxBacklog int
+	// RetryAfter is the delay suggested to rejected callers.
+	RetryAfter time.Duration
+	// CacheTTL is how long signals are reused before they are evaluated again.
+	CacheTTL time.Duration
+}
+
+// DefaultAdmissionConfig returns the default configuration.
+func DefaultAdmissionConfig() AdmissionConfig {
+	return AdmissionConfig{
+		MaxFailureRatio: 0.2,
+		FailureWindow:   15 * time.Minute,
+		MinSamples:      10,
+		MaxBacklog:      500,
+		RetryAfter:      time.Minute,
+		CacheTTL:        10 * time.Second,
+	}
+}
+
+// admissionVerdict is the cached outcome of one signal evaluation.
+type admissionVerdict struct {
+	result string // metrics.Admission* label
+	reason string // empty when admitted
+}
+
+type admissionController struct {
+	jobs  repository.TranscodeJobRepository
+	queue repository.QueueInspector
+	cfg   AdmissionConfig
+	now   func() time.Time
+
+	mu        sync.Mutex
+	verdict   admissionVerdict
+	checkedAt time.Time
+}
+
+// NewAdmissionController creates an AdmissionController that sheds background work
+// while the transcode failure ratio or the queue backlog is above its threshold.
+// The jobs and queue parameters are optional - pass nil to disable that signal.
+func NewAdmissionController(
+	jobs repository.TranscodeJobRepository,
+	queue repository.QueueInspector,
+	cfg AdmissionConfig,
+) AdmissionController {
+	return &admissionController{
+		jobs:  jobs,
+		queue: queue,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Admit always admits interactive work; background work is shed while any signal is tripped.
+func (c *admissionController) Admit(ctx context.Context, class WorkClass) error {
+	if class != WorkBackground {
+		metrics.AdmissionDecisionsTotal.WithLabelValues(string(class), metrics.AdmissionAdmitted).Inc()
+		return nil
+	}
+
+	verdict := c.evaluate(ctx)
+	metrics.AdmissionDecisionsTotal.WithLabelValues(string(class), verdict.result).Inc()
+	if verdict.reason == "" {
+		return nil
+	}
+
+	return &OverloadedError{Reason: verdict.reason, RetryAfter: c.cfg.RetryAfter}
+}
+
+// evaluate returns the cached verdict, refreshing it once CacheTTL has passed.
+// Holding the lock across the refresh collapses concurrent refreshes into one.
+func (c *admissionController) evaluate(ctx context.Context) admissionVerdict {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !c.checkedAt.IsZero() && now.Sub(c.checkedAt) < c.cfg.CacheTTL {
+		return c.verdict
+	}
+
+	c.verdict = c.check(ctx, now)
+	c.checkedAt = now
+	return c.verdict
+}
+
+// check evaluates every signal. Signals that cannot be read fail open so that an
+// unavailable database or broker never blocks work on its own.
+func (c *admissionController) check(ctx context.Context, now time.Time) admissionVerdict {
+	if c.jobs != nil && c.cfg.MaxFailureRatio > 0 {
+		stats, err := c.jobs.Stats(ctx, now.Add(-c.cfg.FailureWindow))
+		if err != nil {
+			slog.WarnContext(ctx, "admission: failed to read transcode stats",
+				slog.String("error", err.Error()),
+			)
+		} else if total := stats.Succeeded + stats.Failed; total > 0 && total >= c.cfg.MinSamples {
+			ratio := float64(stats.Failed) / float64(total)
+			if ratio > c.cfg.MaxFailureRatio {
+				return admissionVerdict{
+					result: metrics.AdmissionShedFailureRate,
+					reason: fmt.Sprintf("transcode failure rate %.1f%% exceeds %.1f%%", ratio*100, c.cfg.MaxFailureRatio*100),
+				}
+			}
+		}
+	}
+
+	if c.queue != nil && c.cfg.MaxBacklog > 0 {
+		depth, err := c.queue.Depth(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "admission: failed to read queue depth",
+				slog.String("error", err.Error()),
+			)
+		} else if depth > c.cfg.MaxBacklog {
+			return admissionVerdict{
+				result: metrics.AdmissionShedBacklog,
+				reason: fmt.Sprintf("transcode backlog %d exceeds %d", depth, c.cfg.MaxBacklog),
+			}
+		}
+	}
+
+	return admissionVerdict{result: metrics.AdmissionAdmitted}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestAdmissionController_Admit(t *testing.T) {
+	tests := []struct {
+		name       string
+		class      WorkClass
+		stats      *repository.TranscodeJobStats
+		statsErr   error
+		depth      int
+		depthErr   error
+		wantShed   bool
+		wantReason string
+	}{
+		{
+			name:  "healthy pipeline admits background work",
+			class: WorkBackground,
+			stats: &repository.TranscodeJobStats{Succeeded: 95, Failed: 5},
+			depth: 10,
+		},
+		{
+			name:       "failure rate above threshold sheds background work",
+			class:      WorkBackground,
+			stats:      &repository.TranscodeJobStats{Succeeded: 60, Failed: 40},
+			wantShed:   true,
+			wantReason: "transcode failure rate 40.0% exceeds 20.0%",
+		},
+		{
+			name:  "failure rate ignored below minimum samples",
+			class: WorkBackground,
+			stats: &repository.TranscodeJobStats{Succeeded: 1, Failed: 3},
+		},
+		{
+			name:       "backlog above threshold sheds background work",
+			class:      WorkBackground,
+			stats:      &repository.TranscodeJobStats{},
+			depth:      501,
+			wantShed:   true,
+			wantReason: "transcode backlog 501 exceeds 500",
+		},
+		{
+			name:  "interactive work is never shed",
+			class: WorkInteractive,
+			stats: &repository.TranscodeJobStats{Succeeded: 0, Failed: 100},
+			depth: 10000,
+		},
+		{
+			name:     "signal errors fail open",
+			class:    WorkBackground,
+			statsErr: errors.New("connection refused"),
+			depthErr: errors.New("channel closed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &mockTranscodeJobRepository{
+				statsFn: func(ctx context.Context, since time.Time) (*repository.TranscodeJobStats, error) {
+					return tt.stats, tt.statsErr
+				},
+			}
+			queue := &mockQueueInspector{
+				depthFn: func(ctx context.Context) (int, error) {
+					return tt.depth, tt.depthErr
+				},
+			}
+
+			ac := NewAdmissionController(jobs, queue, DefaultAdmissionConfig())
+			err := ac.Admit(context.Background(), tt.class)
+
+			if !tt.wantShed {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var overloaded *OverloadedError
+			if !errors.As(err, &overloaded) {
+				t.Fatalf("expected *OverloadedError, got %v", err)
+			}
+			if !errors.Is(err, ErrOverloaded) {
+				t.Errorf("expected error to wrap ErrOverloaded")
+			}
+			if overloaded.Reason != tt.wantReason {
+				t.Errorf("reason: got %q, expected %q", overloaded.Reason, tt.wantReason)
+			}
+			if overloaded.RetryAfter != DefaultAdmissionConfig().RetryAfter {
+				t.Errorf("retry after: got %v, expected %v", overloaded.RetryAfter, DefaultAdmissionConfig().RetryAfter)
+			}
+		})
+	}
+}
+
+func TestAdmissionController_CachesSignals(t *testing.T) {
+	var calls int
+	depth := 1000
+	queue := &mockQueueInspector{
+		depthFn: func(ctx context.Context) (int, error) {
+			calls++
+			return depth, nil
+		},
+	}
+
+	cfg := DefaultAdmissionConfig()
+	ac := NewAdmissionController(nil, queue, cfg).(*admissionController)
+	now := time.Now()
+	ac.now = func() time.Time { return now }
+
+	if err := ac.Admit(context.Background(), WorkBackground); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("expected ErrOverloaded, got %v", err)
+	}
+
+	// The backlog drains, but the cached verdict holds until CacheTTL passes
+	depth = 0
+	if err := ac.Admit(context.Background(), WorkBackground); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("expected cached ErrOverloaded, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("depth calls: got %d, expected 1", calls)
+	}
+
+	now = now.Add(cfg.CacheTTL)
+	if err := ac.Admit(context.Background(), WorkBackground); err != nil {
+		t.Fatalf("expected admission after cache expiry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("depth calls: got %d, expected 2", calls)
+	}
+}
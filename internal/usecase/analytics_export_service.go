@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// analyticsContentType is the media type of exported partitions (JSON Lines).
+const analyticsContentType = "application/x-ndjson"
+
+var (
+	// ErrExportRangeTooLarge is returned when a replay covers more hours than one run may write.
+	ErrExportRangeTooLarge = errors.New("export range exceeds the hours allowed per run")
+)
+
+// AnalyticsExportConfig holds configuration for AnalyticsExportService.
+type AnalyticsExportConfig struct {
+	// Prefix is the storage prefix partitions are written under.
+	Prefix string
+	// Lag delays exporting an hour so late writes (e.g., buffered progress flushes) land first.
+	Lag time.Duration
+	// Backfill is how far back a stream that has never been exported starts.
+	Backfill time.Duration
+	// MaxHoursPerRun bounds the partitions written per stream by one Export or Replay.
+	MaxHoursPerRun int
+}
+
+// DefaultAnalyticsExportConfig returns the default configuration.
+func DefaultAnalyticsExportConfig() AnalyticsExportConfig {
+	return AnalyticsExportConfig{
+		Prefix:         "analytics/",
+		Lag:            10 * time.Minute,
+		Backfill:       24 * time.Hour,
+		MaxHoursPerRun: 48,
+	}
+}
+
+// AnalyticsPartition describes one exported hourly file.
+type AnalyticsPartition struct {
+	Stream model.AnalyticsStream
+	Hour   time.Time
+	Key    string
+	Events int
+}
+
+// AnalyticsExportResult lists the partitions written by a run.
+type AnalyticsExportResult struct {
+	Partitions []AnalyticsPartition
+}
+
+// AnalyticsExportService exports analytics events to object storage as hourly
+// JSON Lines partitions ({prefix}{stream}/dt=YYYY-MM-DD/hour=HH/events.jsonl) for
+// the data team's warehouse.
+type AnalyticsExportService interface {
+	// Export writes every closed hour after each stream's bookmark and advances the
+	// bookmark past it, resuming where the previous run stopped.
+	Export(ctx context.Context) (*AnalyticsExportResult, error)
+
+	// Replay rewrites the closed hours overlapping [from, to) without moving bookmarks,
+	// e.g. after correcting source data. Partition keys are deterministic, so replayed
+	// files replace the originals. Returns ErrInvalidDateRange if from is not before to,
+	// or ErrExportRangeTooLarge if the range spans more than MaxHoursPerRun hours.
+	Replay(ctx context.Context, from, to time.Time) (*AnalyticsExportResult, error)
+}
+
+type analyticsExportService struct {
+	source    repository.AnalyticsEventSource
+	bookmarks repository.ExportBookmarkRepository
+	storage   repository.ObjectStorage
+	cfg       AnalyticsExportConfig
+	now       func() time.Time
+}
+
+// NewAnalyticsExportService creates a new AnalyticsExportService instance.
+func NewAnalyticsExportService(
+	source repository.AnalyticsEventSource,
+	bookmarks repository.ExportBookmarkRepository,
+	storage repository.ObjectStorage,
+	cfg AnalyticsExportConfig,
+) AnalyticsExportService {
+	return &analyticsExportService{
+		source:    source,
+		bookmarks: bookmarks,
+		storage:   storage,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// Export advances the bookmark after each hour, so a failed run resumes at the failed hour.
+func (s *analyticsExportService) Export(ctx context.Context) (*AnalyticsExportResult, error) {
+	closed := s.closedUntil()
+	result := &AnalyticsExportResult{}
+
+	for _, stream := range model.AnalyticsStreams {
+		start, err := s.bookmarks.Get(ctx, stream)
+		if err != nil {
+			return result, fmt.Errorf("get %s bookmark: %w", stream, err)
+		}
+		if start.IsZero() {
+			start = closed.Add(-s.cfg.Backfill).Truncate(time.Hour)
+		}
+
+		for hour, n := start, 0; hour.Before(closed) && n < s.cfg.MaxHoursPerRun; hour, n = hour.Add(time.Hour), n+1 {
+			partition, err := s.exportHour(ctx, stream, hour)
+			if err != nil {
+				return result, err
+			}
+			result.Partitions = append(result.Partitions, *partition)
+
+			if err := s.bookmarks.Advance(ctx, stream, hour.Add(time.Hour)); err != nil {
+				return result, fmt.Errorf("advance %s bookmark: %w", stream, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Replay covers whole hours: from is rounded down and to is rounded up, then limited to closed hours.
+func (s *analyticsExportService) Replay(ctx context.Context, from, to time.Time) (*AnalyticsExportResult, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidDateRange
+	}
+
+	start := from.UTC().Truncate(time.Hour)
+	end := to.UTC().Truncate(time.Hour)
+	if end.Before(to) {
+		end = end.Add(time.Hour)
+	}
+	if hours := int(end.Sub(start) / time.Hour); hours > s.cfg.MaxHoursPerRun {
+		return nil, ErrExportRangeTooLarge
+	}
+	end = minTime(end, s.closedUntil())
+
+	result := &AnalyticsExportResult{}
+	for _, stream := range model.AnalyticsStreams {
+		for hour := start; hour.Before(end); hour = hour.Add(time.Hour) {
+			partition, err := s.exportHour(ctx, stream, hour)
+			if err != nil {
+				return result, err
+			}
+			result.Partitions = append(result.Partitions, *partition)
+		}
+	}
+
+	slog.InfoContext(ctx, "replayed analytics export",
+		"from", start,
+		"to", end,
+		"partitions", len(result.Partitions),
+	)
+
+	return result, nil
+}
+
+// closedUntil returns the end of the most recent hour that is at least Lag old.
+func (s *analyticsExportService) closedUntil() time.Time {
+	return s.now().UTC().Add(-s.cfg.Lag).Truncate(time.Hour)
+}
+
+// analyticsRecord is the JSON Lines representation of an event.
+type analyticsRecord struct {
+	Type       string         `json:"type"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	VideoID    uuid.UUID      `json:"video_id"`
+	UserID     uuid.UUID      `json:"user_id"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// exportHour writes one hourly partition. Empty hours are written too, so the warehouse
+// can tell an hour without events from one that has not been exported yet.
+func (s *analyticsExportService) exportHour(ctx context.Context, stream model.AnalyticsStream, hour time.Time) (*AnalyticsPartition, error) {
+	events, err := s.source.ListEvents(ctx, stream, hour, hour.Add(time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("list %s events: %w", stream, err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(analyticsRecord{
+			Type:       e.Type,
+			OccurredAt: e.OccurredAt.UTC(),
+			VideoID:    e.VideoID,
+			UserID:     e.UserID,
+			Attributes: e.Attributes,
+		}); err != nil {
+			return nil, fmt.Errorf("encode %s event: %w", stream, err)
+		}
+	}
+
+	key := s.partitionKey(stream, hour)
+	if err := s.storage.Upload(ctx, key, &buf, analyticsContentType); err != nil {
+		return nil, fmt.Errorf("upload %s: %w", key, err)
+	}
+	metrics.AnalyticsEventsExportedTotal.WithLabelValues(string(stream)).Add(float64(len(events)))
+
+	return &AnalyticsPartition{Stream: stream, Hour: hour, Key: key, Events: len(events)}, nil
+}
+
+// partitionKey returns the Hive-style key of an hourly partition.
+func (s *analyticsExportService) partitionKey(stream model.AnalyticsStream, hour time.Time) string {
+	hour = hour.UTC()
+	return fmt.Sprintf("%s%s/dt=%s/hour=%02d/events.jsonl", s.cfg.Prefix, stream, hour.Format("2006-01-02"), hour.Hour())
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
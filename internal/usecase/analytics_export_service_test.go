@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func newTestAnalyticsExportService(
+	source *mockAnalyticsEventSource,
+	bookmarks *mockExportBookmarkRepository,
+	storage *mockObjectStorage,
+	now time.Time,
+) *analyticsExportService {
+	svc := NewAnalyticsExportService(source, bookmarks, storage, DefaultAnalyticsExportConfig()).(*analyticsExportService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestAnalyticsExportService_Export(t *testing.T) {
+	// 12:05 with a 10 minute lag: the last closed hour is 10:00-11:00
+	now := time.Date(2026, 3, 1, 12, 5, 0, 0, time.UTC)
+	closed := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		bookmark  time.Time
+		wantHours int
+	}{
+		{
+			name:      "resumes from bookmark",
+			bookmark:  closed.Add(-3 * time.Hour),
+			wantHours: 3,
+		},
+		{
+			name:      "up to date",
+			bookmark:  closed,
+			wantHours: 0,
+		},
+		{
+			name:      "backfills when never exported",
+			wantHours: 24,
+		},
+		{
+			name:      "caps hours per run",
+			bookmark:  closed.Add(-100 * time.Hour),
+			wantHours: 48,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			advanced := make(map[model.AnalyticsStream]time.Time)
+			bookmarks := &mockExportBookmarkRepository{
+				getFn: func(ctx context.Context, stream model.AnalyticsStream) (time.Time, error) {
+					return tt.bookmark, nil
+				},
+				advanceFn: func(ctx context.Context, stream model.AnalyticsStream, until time.Time) error {
+					advanced[stream] = until
+					return nil
+				},
+			}
+
+			svc := newTestAnalyticsExportService(&mockAnalyticsEventSource{}, bookmarks, &mockObjectStorage{}, now)
+			result, err := svc.Export(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := len(result.Partitions), tt.wantHours*len(model.AnalyticsStreams); got != want {
+				t.Fatalf("partitions: got %d, expected %d", got, want)
+			}
+			for _, p := range result.Partitions {
+				if !p.Hour.Before(closed) {
+					t.Errorf("exported unclosed hour %v", p.Hour)
+				}
+			}
+			if tt.wantHours == 0 {
+				if len(advanced) != 0 {
+					t.Errorf("bookmarks should not move, got %v", advanced)
+				}
+				return
+			}
+			for _, stream := range model.AnalyticsStreams {
+				last := result.Partitions[len(result.Partitions)-1].Hour.Add(time.Hour)
+				if !advanced[stream].Equal(last) {
+					t.Errorf("%s bookmark: got %v, expected %v", stream, advanced[stream], last)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyticsExportService_Export_WritesJSONLines(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 5, 0, 0, time.UTC)
+	hour := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	source := &mockAnalyticsEventSource{
+		listEventsFn: func(ctx context.Context, stream model.AnalyticsStream, from, to time.Time) ([]*model.AnalyticsEvent, error) {
+			if !from.Equal(hour) || !to.Equal(hour.Add(time.Hour)) {
+				t.Errorf("unexpected window [%v, %v)", from, to)
+			}
+			if stream != model.AnalyticsStreamVideoLifecycle {
+				return nil, nil
+			}
+			return []*model.AnalyticsEvent{
+				{Type: model.EventVideoCreated, OccurredAt: hour.Add(time.Minute), VideoID: videoID, UserID: userID},
+				{Type: model.EventTranscodeSucceeded, OccurredAt: hour.Add(2 * time.Minute), VideoID: videoID, UserID: userID, Attributes: map[string]any{"attempt": 1}},
+			}, nil
+		},
+	}
+	bookmarks := &mockExportBookmarkRepository{
+		getFn: func(ctx context.Context, stream model.AnalyticsStream) (time.Time, error) {
+			return hour, nil
+		},
+	}
+	uploads := make(map[string]string)
+	storage := &mockObjectStorage{
+		uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+			if contentType != "application/x-ndjson" {
+				t.Errorf("content type: got %q", contentType)
+			}
+			data, _ := io.ReadAll(reader)
+			uploads[key] = string(data)
+			return nil
+		},
+	}
+
+	svc := newTestAnalyticsExportService(source, bookmarks, storage, now)
+	if _, err := svc.Export(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lifecycle, ok := uploads["analytics/video_lifecycle/dt=2026-03-01/hour=10/events.jsonl"]
+	if !ok {
+		t.Fatalf("lifecycle partition not uploaded, got keys %v", uploads)
+	}
+	lines := strings.Split(strings.TrimSpace(lifecycle), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines: got %d, expected 2", len(lines))
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if record["type"] != model.EventTranscodeSucceeded || record["video_id"] != videoID.String() {
+		t.Errorf("unexpected record: %v", record)
+	}
+
+	playback, ok := uploads["analytics/playback/dt=2026-03-01/hour=10/events.jsonl"]
+	if !ok {
+		t.Fatal("empty playback partition should still be uploaded")
+	}
+	if playback != "" {
+		t.Errorf("playback partition: got %q, expected empty", playback)
+	}
+}
+
+func TestAnalyticsExportService_Export_StopsAtFailedHour(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 5, 0, 0, time.UTC)
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	uploadErr := errors.New("storage unavailable")
+
+	var advanced []time.Time
+	bookmarks := &mockExportBookmarkRepository{
+		getFn: func(ctx context.Context, stream model.AnalyticsStream) (time.Time, error) {
+			return start, nil
+		},
+		advanceFn: func(ctx context.Context, stream model.AnalyticsStream, until time.Time) error {
+			advanced = append(advanced, until)
+			return nil
+		},
+	}
+	calls := 0
+	storage := &mockObjectStorage{
+		uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+			calls++
+			if calls == 2 {
+				return uploadErr
+			}
+			return nil
+		},
+	}
+
+	svc := newTestAnalyticsExportService(&mockAnalyticsEventSource{}, bookmarks, storage, now)
+	result, err := svc.Export(context.Background())
+	if !errors.Is(err, uploadErr) {
+		t.Fatalf("expected error %v, got %v", uploadErr, err)
+	}
+	if len(result.Partitions) != 1 {
+		t.Errorf("partitions: got %d, expected 1", len(result.Partitions))
+	}
+	if len(advanced) != 1 || !advanced[0].Equal(start.Add(time.Hour)) {
+		t.Errorf("bookmark should stop after the first hour, got %v", advanced)
+	}
+}
+
+func TestAnalyticsExportService_Replay(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		from      time.Time
+		to        time.Time
+		wantHours int
+		wantErr   error
+	}{
+		{
+			name:      "rounds to whole hours",
+			from:      time.Date(2026, 3, 1, 7, 30, 0, 0, time.UTC),
+			to:        time.Date(2026, 3, 1, 9, 15, 0, 0, time.UTC),
+			wantHours: 3,
+		},
+		{
+			name:      "clamps to closed hours",
+			from:      time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+			to:        time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC),
+			wantHours: 2,
+		},
+		{
+			name:    "invalid range",
+			from:    time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+			to:      time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+			wantErr: ErrInvalidDateRange,
+		},
+		{
+			name:    "range too large",
+			from:    time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantErr: ErrExportRangeTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookmarks := &mockExportBookmarkRepository{
+				advanceFn: func(ctx context.Context, stream model.AnalyticsStream, until time.Time) error {
+					t.Error("replay must not move bookmarks")
+					return nil
+				},
+			}
+
+			svc := newTestAnalyticsExportService(&mockAnalyticsEventSource{}, bookmarks, &mockObjectStorage{}, now)
+			result, err := svc.Replay(context.Background(), tt.from, tt.to)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := len(result.Partitions), tt.wantHours*len(model.AnalyticsStreams); got != want {
+				t.Errorf("partitions: got %d, expected %d", got, want)
+			}
+		})
+	}
+}
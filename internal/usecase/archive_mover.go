@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// ArchiveMoverConfig holds configuration for ArchiveMover.
+type ArchiveMoverConfig struct {
+	// Lease is how long a worker owns a claimed record. A worker that dies mid-move leaves
+	// the record to be claimed again once its lease expires.
+	Lease time.Duration
+	// BatchSize caps the records handled by one run.
+	BatchSize int
+}
+
+// DefaultArchiveMoverConfig returns the default configuration.
+func DefaultArchiveMoverConfig() ArchiveMoverConfig {
+	return ArchiveMoverConfig{
+		Lease:     time.Hour,
+		BatchSize: 10,
+	}
+}
+
+// ArchiveMoveResult summarizes a run of the ArchiveMover.
+type ArchiveMoveResult struct {
+	Archived int
+	Restored int
+	// Bytes is the size of the objects moved in either direction.
+	Bytes int64
+}
+
+// ArchiveMover is the worker side of ArchiveService: it moves output between the hot
+// prefix and the archive tier.
+type ArchiveMover interface {
+	// Run claims up to BatchSize pending records, restores first, and moves their output.
+	// A restored video becomes READY again. Failures of single videos are logged and
+	// retried once their lease expires.
+	Run(ctx context.Context) (*ArchiveMoveResult, error)
+}
+
+type archiveMover struct {
+	videos   repository.VideoRepository
+	archives repository.ArchiveRepository
+	storage  repository.ObjectStorage
+	replica  repository.ObjectStorage
+	cache    cache.VideoCache
+	events   cache.VideoEventBus
+	purger   repository.CDNPurger
+	// statusEvents is optional; nil records no status transitions.
+	statusEvents repository.VideoStatusEventRepository
+	cfg          ArchiveMoverConfig
+	now          func() time.Time
+}
+
+// NewArchiveMover creates a new ArchiveMover instance.
+// The replica parameter is optional - pass nil when output is not replicated.
+// The videoCache parameter is optional - pass nil when videos are not cached.
+// The events parameter is optional - pass nil to not publish status changes.
+// The purger parameter is optional - pass nil to leave archived output to expire from the CDN.
+// The statusEvents parameter is optional - pass nil to not record status transitions.
+func NewArchiveMover(
+	videos repository.VideoRepository,
+	archives repository.ArchiveRepository,
+	storage repository.ObjectStorage,
+	replica repository.ObjectStorage,
+	videoCache cache.VideoCache,
+	events cache.VideoEventBus,
+	purger repository.CDNPurger,
+	statusEvents repository.VideoStatusEventRepository,
+	cfg ArchiveMoverConfig,
+) ArchiveMover {
+	return &archiveMover{
+		videos:       videos,
+		archives:     archives,
+		storage:      storage,
+		replica:      replica,
+		cache:        videoCache,
+		events:       events,
+		purger:       purger,
+		statusEvents: statusEvents,
+		cfg:          cfg,
+		now:          time.Now,
+	}
+}
+
+func (m *archiveMover) Run(ctx context.Context) (*ArchiveMoveResult, error) {
+	result := &ArchiveMoveResult{}
+	for range m.cfg.BatchSize {
+		now := m.now()
+		archive, err := m.archives.ClaimNext(ctx, now, now.Add(m.cfg.Lease))
+		if errors.Is(err, repository.ErrArchiveNotFound) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("claim archive: %w", err)
+		}
+
+		if err := m.handle(ctx, archive, result); err != nil {
+			slog.WarnContext(ctx, "failed to move archived output",
+				"video_id", archive.VideoID,
+				"restore", archive.IsRestoring(),
+				"error", err,
+			)
+		}
+	}
+	return result, nil
+}
+
+// handle moves the output of one claimed record. A record whose video is no longer
+// ARCHIVED is left over from a finished restore or a deletion and is discarded.
+func (m *archiveMover) handle(ctx context.Context, archive *model.VideoArchive, result *ArchiveMoveResult) error {
+	video, err := m.videos.GetByID(ctx, archive.VideoID)
+	if err != nil && !errors.Is(err, repository.ErrVideoNotFound) {
+		return err
+	}
+	if err != nil || !video.IsArchived() {
+		return m.archives.Delete(ctx, archive.VideoID, archive.CreatedAt)
+	}
+
+	if archive.IsRestoring() {
+		return m.restore(ctx, video, archive, result)
+	}
+	return m.archive(ctx, video, archive, result)
+}
+
+// archive moves the output to the archive tier. Thumbnails stay where they are, since
+// listings keep showing them.
+func (m *archiveMover) archive(ctx context.Context, video *model.Video, archive *model.VideoArchive, result *ArchiveMoveResult) error {
+	moved, err := m.move(ctx, archive.Prefix, archiveKey(archive.Prefix), video.ThumbnailPrefix)
+	if err != nil {
+		return err
+	}
+
+	stored, err := m.storage.ListObjects(ctx, archiveKey(archive.Prefix))
+	if err != nil {
+		return fmt.Errorf("list archived output: %w", err)
+	}
+	var bytes int64
+	for _, obj := range stored {
+		bytes += obj.Size
+	}
+	if err := m.archives.MarkArchived(ctx, video.ID, bytes, m.now()); err != nil {
+		return fmt.Errorf("mark archived: %w", err)
+	}
+
+	if m.purger != nil {
+		if err := m.purger.Purge(ctx, []string{archive.Prefix}); err != nil {
+			slog.WarnContext(ctx, "failed to purge CDN",
+				"video_id", video.ID,
+				"prefix", archive.Prefix,
+				"error", err,
+			)
+		}
+	}
+
+	result.Archived++
+	result.Bytes += moved
+	metrics.ArchiveMovesTotal.WithLabelValues(metrics.ArchiveDirectionArchive).Inc()
+	metrics.ArchiveMovedBytesTotal.WithLabelValues(metrics.ArchiveDirectionArchive).Add(float64(moved))
+	slog.InfoContext(ctx, "archived video output",
+		"video_id", video.ID,
+		"output_version", archive.OutputVersion,
+		"bytes", bytes,
+	)
+	return nil
+}
+
+// restore moves the output back and makes the video READY. The record is deleted last:
+// a restore that fails in between leaves a record the next run discards.
+func (m *archiveMover) restore(ctx context.Context, video *model.Video, archive *model.VideoArchive, result *ArchiveMoveResult) error {
+	moved, err := m.move(ctx, archiveKey(archive.Prefix), archive.Prefix, "")
+	if err != nil {
+		return err
+	}
+
+	if err := video.TransitionTo(model.StatusReady); err != nil {
+		return err
+	}
+	if err := m.videos.UpdateStatus(ctx, video.ID, video.Status); err != nil {
+		return fmt.Errorf("update video status: %w", err)
+	}
+	recordStatusEvent(ctx, m.statusEvents, video, model.StatusArchived, model.StatusActorArchiver, "output restored from archive")
+	invalidateVideo(ctx, m.cache, video)
+	publishStatus(ctx, m.events, video)
+
+	if err := m.archives.Delete(ctx, video.ID, archive.CreatedAt); err != nil {
+		return fmt.Errorf("delete archive: %w", err)
+	}
+
+	result.Restored++
+	result.Bytes += moved
+	metrics.ArchiveMovesTotal.WithLabelValues(metrics.ArchiveDirectionRestore).Inc()
+	metrics.ArchiveMovedBytesTotal.WithLabelValues(metrics.ArchiveDirectionRestore).Add(float64(moved))
+	slog.InfoContext(ctx, "restored video output",
+		"video_id", video.ID,
+		"output_version", archive.OutputVersion,
+		"requested_at", archive.RestoreRequestedAt,
+	)
+	return nil
+}
+
+// move moves the objects under from to the same keys under to on the primary and the
+// replica, and returns the bytes moved on the primary.
+func (m *archiveMover) move(ctx context.Context, from, to, skip string) (int64, error) {
+	moved, err := moveObjects(ctx, m.storage, from, to, skip)
+	if err != nil {
+		return 0, err
+	}
+	if m.replica != nil {
+		if _, err := moveObjects(ctx, m.replica, from, to, skip); err != nil {
+			return 0, fmt.Errorf("replica: %w", err)
+		}
+	}
+	return moved, nil
+}
+
+// moveObjects copies every object under from, except those under skip, to the same key
+// under to, and deletes each original once its copy is stored. A move interrupted
+// anywhere is finished by running it again.
+func moveObjects(ctx context.Context, storage repository.ObjectStorage, from, to, skip string) (int64, error) {
+	objects, err := storage.ListObjects(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("list %s: %w", from, err)
+	}
+
+	var moved int64
+	for _, obj := range objects {
+		if skip != "" && strings.HasPrefix(obj.Key, skip) {
+			continue
+		}
+		if err := copyObject(ctx, storage, obj, to+strings.TrimPrefix(obj.Key, from)); err != nil {
+			return moved, err
+		}
+		if err := storage.Delete(ctx, obj.Key); err != nil {
+			return moved, fmt.Errorf("delete %s: %w", obj.Key, err)
+		}
+		moved += obj.Size
+	}
+	return moved, nil
+}
+
+// copyObject stores a copy of obj under key with the same content type. Listings of
+// S3-compatible stores may omit the content type; it is then read with Stat.
+func copyObject(ctx context.Context, storage repository.ObjectStorage, obj repository.ObjectInfo, key string) error {
+	contentType := obj.ContentType
+	if contentType == "" {
+		info, err := storage.Stat(ctx, obj.Key)
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", obj.Key, err)
+		}
+		contentType = info.ContentType
+	}
+
+	reader, err := storage.Download(ctx, obj.Key)
+	if err != nil {
+		metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpDownload).Inc()
+		return fmt.Errorf("download %s: %w", obj.Key, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if err := storage.Upload(ctx, key, reader, contentType); err != nil {
+		metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpUpload).Inc()
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+	return nil
+}
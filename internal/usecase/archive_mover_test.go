@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// newMemoryStorage returns a mockObjectStorage backed by objects, keyed by object key.
+func newMemoryStorage(objects map[string]string) *mockObjectStorage {
+	return &mockObjectStorage{
+		listObjectsFn: func(ctx context.Context, prefix string) ([]repository.ObjectInfo, error) {
+			var infos []repository.ObjectInfo
+			for _, key := range slices.Sorted(maps.Keys(objects)) {
+				if strings.HasPrefix(key, prefix) {
+					infos = append(infos, repository.ObjectInfo{Key: key, Size: int64(len(objects[key]))})
+				}
+			}
+			return infos, nil
+		},
+		statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+			return &repository.ObjectInfo{Key: key, ContentType: "video/mp2t"}, nil
+		},
+		downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			data, ok := objects[key]
+			if !ok {
+				return nil, repository.ErrObjectNotFound
+			}
+			return io.NopCloser(strings.NewReader(data)), nil
+		},
+		uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+			var buf bytes.Buffer
+			if _, err := io.Copy(&buf, reader); err != nil {
+				return err
+			}
+			objects[key] = buf.String()
+			return nil
+		},
+		deleteFn: func(ctx context.Context, key string) error {
+			delete(objects, key)
+			return nil
+		},
+	}
+}
+
+func TestArchiveMover_Run(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := now.Add(-time.Hour)
+
+	hot := map[string]string{
+		"hls/abc/v7/master.m3u8":               "master",
+		"hls/abc/v7/720p/segment_000.ts":       "segment",
+		"hls/abc/v7/thumbnails/small.jpg":      "thumb",
+		"hls/abc/v70/unrelated/segment_000.ts": "other",
+	}
+	archived := map[string]string{
+		"archive/hls/abc/v7/master.m3u8":         "master",
+		"archive/hls/abc/v7/720p/segment_000.ts": "segment",
+		"hls/abc/v7/thumbnails/small.jpg":        "thumb",
+		"hls/abc/v70/unrelated/segment_000.ts":   "other",
+	}
+
+	tests := []struct {
+		name         string
+		status       model.Status
+		restoring    bool
+		objects      map[string]string
+		wantObjects  map[string]string
+		wantStatus   model.Status
+		wantMarked   int64
+		wantDeleted  bool
+		wantPurged   bool
+		wantArchived int
+		wantRestored int
+	}{
+		{
+			name:         "archive",
+			status:       model.StatusArchived,
+			objects:      maps.Clone(hot),
+			wantObjects:  archived,
+			wantMarked:   int64(len("master") + len("segment")),
+			wantPurged:   true,
+			wantArchived: 1,
+		},
+		{
+			name:         "restore",
+			status:       model.StatusArchived,
+			restoring:    true,
+			objects:      maps.Clone(archived),
+			wantObjects:  hot,
+			wantStatus:   model.StatusReady,
+			wantDeleted:  true,
+			wantRestored: 1,
+		},
+		{
+			name:        "stale record",
+			status:      model.StatusReady,
+			restoring:   true,
+			objects:     maps.Clone(hot),
+			wantObjects: hot,
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claimed := false
+			archive := &model.VideoArchive{VideoID: videoID, OutputVersion: 7, Prefix: "hls/abc/v7/", CreatedAt: createdAt}
+			if tt.restoring {
+				archive.ArchivedAt = &createdAt
+				archive.RequestRestore(now, time.Minute)
+			}
+
+			var (
+				marked  int64
+				deleted bool
+				purged  bool
+				status  model.Status
+			)
+			archives := &mockArchiveRepository{
+				claimNextFn: func(ctx context.Context, at, leaseUntil time.Time) (*model.VideoArchive, error) {
+					if claimed {
+						return nil, repository.ErrArchiveNotFound
+					}
+					if !leaseUntil.Equal(now.Add(time.Hour)) {
+						t.Errorf("leaseUntil = %v", leaseUntil)
+					}
+					claimed = true
+					return archive, nil
+				},
+				markArchivedFn: func(ctx context.Context, id uuid.UUID, bytes int64, at time.Time) error {
+					marked = bytes
+					return nil
+				},
+				deleteFn: func(ctx context.Context, id uuid.UUID, at time.Time) error {
+					if !at.Equal(createdAt) {
+						t.Errorf("Delete() createdAt = %v", at)
+					}
+					deleted = true
+					return nil
+				},
+			}
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{
+						ID:              id,
+						Status:          tt.status,
+						HLSURL:          "hls/abc/v7/master.m3u8",
+						ThumbnailPrefix: "hls/abc/v7/thumbnails/",
+						OutputVersion:   7,
+					}, nil
+				},
+				updateStatusFn: func(ctx context.Context, id uuid.UUID, s model.Status) error {
+					status = s
+					return nil
+				},
+			}
+			purger := &mockCDNPurger{
+				purgeFn: func(ctx context.Context, prefixes []string) error {
+					purged = slices.Equal(prefixes, []string{"hls/abc/v7/"})
+					return nil
+				},
+			}
+			replicaObjects := maps.Clone(tt.objects)
+
+			mover := NewArchiveMover(videos, archives, newMemoryStorage(tt.objects), newMemoryStorage(replicaObjects),
+				nil, nil, purger, nil, DefaultArchiveMoverConfig()).(*archiveMover)
+			mover.now = func() time.Time { return now }
+
+			result, err := mover.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if !maps.Equal(tt.objects, tt.wantObjects) {
+				t.Errorf("objects = %v, want %v", tt.objects, tt.wantObjects)
+			}
+			if !maps.Equal(replicaObjects, tt.wantObjects) {
+				t.Errorf("replica objects = %v, want %v", replicaObjects, tt.wantObjects)
+			}
+			if status != tt.wantStatus || marked != tt.wantMarked || deleted != tt.wantDeleted || purged != tt.wantPurged {
+				t.Errorf("status = %q, marked = %d, deleted = %v, purged = %v", status, marked, deleted, purged)
+			}
+			if result.Archived != tt.wantArchived || result.Restored != tt.wantRestored {
+				t.Errorf("result = %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// archiveKeyPrefix is where the archive tier keeps archived output, mirroring the
+// original keys. A bucket lifecycle rule on this prefix moves the objects to a cold
+// storage class (e.g., S3 Glacier or a MinIO remote tier).
+const archiveKeyPrefix = "archive/"
+
+var (
+	// ErrVideoArchived is returned when playback is requested for an archived video.
+	// The concrete error is a *VideoArchivedError carrying the restore ETA.
+	ErrVideoArchived = errors.New("video is archived")
+
+	// ErrVideoNotArchived is returned when restoring or inspecting the archive of a video
+	// that is not ARCHIVED.
+	ErrVideoNotArchived = errors.New("video is not archived")
+)
+
+// VideoArchivedError reports that playback has to wait for the video's restore.
+type VideoArchivedError struct {
+	RestoreETA time.Time
+}
+
+func (e *VideoArchivedError) Error() string {
+	return fmt.Sprintf("%s: restore expected at %s", ErrVideoArchived, e.RestoreETA.Format(time.RFC3339))
+}
+
+func (e *VideoArchivedError) Unwrap() error {
+	return ErrVideoArchived
+}
+
+// ArchiveServiceConfig holds configuration for ArchiveService.
+type ArchiveServiceConfig struct {
+	// RestoreTime is how long a restore is expected to take; it sets the reported ETA.
+	// It should cover the cold tier's retrieval time and the worker's poll interval.
+	RestoreTime time.Duration
+}
+
+// DefaultArchiveServiceConfig returns the default configuration.
+func DefaultArchiveServiceConfig() ArchiveServiceConfig {
+	return ArchiveServiceConfig{
+		RestoreTime: 15 * time.Minute,
+	}
+}
+
+// ArchiveService moves the output of videos nobody watches to the archive tier and back.
+// Like S3 Glacier, archiving and restoring are asynchronous: the worker moves the objects
+// (see ArchiveMover) and the video's status changes when the move is requested or done.
+type ArchiveService interface {
+	// Archive marks a READY video ARCHIVED, which stops its playback right away, and
+	// queues its output for the archive tier. Archiving an ARCHIVED video returns its
+	// record unchanged. Returns ErrVideoNotReady if the video has no output to archive.
+	Archive(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error)
+
+	// Restore requests the output of an ARCHIVED video back and returns the record with
+	// the restore ETA. Repeated requests report the first ETA.
+	// Returns ErrVideoNotArchived if the video is not ARCHIVED.
+	Restore(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error)
+
+	// GetArchive returns the archive record of an ARCHIVED video.
+	// Returns ErrVideoNotArchived if the video is not ARCHIVED, and
+	// repository.ErrVideoNotFound if it is not visible to callerID.
+	GetArchive(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoArchive, error)
+}
+
+type archiveService struct {
+	videos   repository.VideoRepository
+	archives repository.ArchiveRepository
+	cache    cache.VideoCache
+	events   cache.VideoEventBus
+	// statusEvents is optional; nil records no status transitions.
+	statusEvents repository.VideoStatusEventRepository
+
+	restoreTime time.Duration
+	now         func() time.Time
+}
+
+// NewArchiveService creates a new ArchiveService instance.
+// The videoCache parameter is optional - pass nil when videos are not cached.
+// The events parameter is optional - pass nil to not publish status changes.
+// The statusEvents parameter is optional - pass nil to not record status transitions.
+func NewArchiveService(
+	videos repository.VideoRepository,
+	archives repository.ArchiveRepository,
+	videoCache cache.VideoCache,
+	events cache.VideoEventBus,
+	statusEvents repository.VideoStatusEventRepository,
+	cfg ArchiveServiceConfig,
+) ArchiveService {
+	return &archiveService{
+		videos:       videos,
+		archives:     archives,
+		cache:        videoCache,
+		events:       events,
+		statusEvents: statusEvents,
+		restoreTime:  cfg.RestoreTime,
+		now:          time.Now,
+	}
+}
+
+// Archive changes the status before saving the record, so the worker never finds a record
+// of a video still being served. Archiving an ARCHIVED video without a record, left by a
+// failed save, saves it again.
+func (s *archiveService) Archive(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	video, err := hideDeleted(s.videos.GetByID(ctx, videoID))
+	if err != nil {
+		return nil, err
+	}
+
+	if !video.IsArchived() {
+		if !video.IsReady() || video.HLSURL == "" {
+			return nil, ErrVideoNotReady
+		}
+		if video.ExternalOutput {
+			// Storage tiers of the tenant's bucket are the tenant's to manage
+			return nil, ErrExternalOutput
+		}
+		if err := video.TransitionTo(model.StatusArchived); err != nil {
+			return nil, err
+		}
+		if err := s.videos.UpdateStatus(ctx, video.ID, video.Status); err != nil {
+			return nil, fmt.Errorf("update video status: %w", err)
+		}
+		recordStatusEvent(ctx, s.statusEvents, video, model.StatusReady, model.StatusActorAPI, "archived by owner")
+		invalidateVideo(ctx, s.cache, video)
+		publishStatus(ctx, s.events, video)
+	} else if archive, err := s.archives.GetByVideoID(ctx, videoID); !errors.Is(err, repository.ErrArchiveNotFound) {
+		return archive, err
+	}
+
+	archive := model.NewVideoArchive(video, outputPrefix(video))
+	if err := s.archives.Save(ctx, archive); err != nil {
+		return nil, fmt.Errorf("save archive: %w", err)
+	}
+	return archive, nil
+}
+
+// Restore of an ARCHIVED video without a record saves one with the restore requested;
+// the worker then moves back whatever is in the archive tier.
+func (s *archiveService) Restore(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	video, err := hideDeleted(s.videos.GetByID(ctx, videoID))
+	if err != nil {
+		return nil, err
+	}
+	if !video.IsArchived() {
+		return nil, ErrVideoNotArchived
+	}
+
+	now := s.now()
+	archive, err := s.archives.RequestRestore(ctx, videoID, now, now.Add(s.restoreTime))
+	if errors.Is(err, repository.ErrArchiveNotFound) {
+		archive = model.NewVideoArchive(video, outputPrefix(video))
+		archive.RequestRestore(now, s.restoreTime)
+		err = s.archives.Save(ctx, archive)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("request restore: %w", err)
+	}
+
+	return archive, nil
+}
+
+func (s *archiveService) GetArchive(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoArchive, error) {
+	video, err := hideDeleted(s.videos.GetByID(ctx, videoID))
+	if err != nil {
+		return nil, err
+	}
+	if !video.VisibleTo(callerID) {
+		return nil, repository.ErrVideoNotFound
+	}
+	if !video.IsArchived() {
+		return nil, ErrVideoNotArchived
+	}
+
+	archive, err := s.archives.GetByVideoID(ctx, videoID)
+	if errors.Is(err, repository.ErrArchiveNotFound) {
+		return nil, ErrVideoNotArchived
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get archive: %w", err)
+	}
+	return archive, nil
+}
+
+// outputPrefix returns the storage prefix of the video's published output, which holds
+// its HLS and DASH manifests, segments and thumbnails.
+func outputPrefix(video *model.Video) string {
+	return path.Dir(video.HLSURL) + "/"
+}
+
+// archiveKey returns the key an object is kept under in the archive tier.
+func archiveKey(key string) string {
+	return archiveKeyPrefix + key
+}
+
+// invalidateVideo removes a video and its owner's cached first pages from cache.
+// Errors are logged but not propagated - entries also expire with their TTL.
+func invalidateVideo(ctx context.Context, videoCache cache.VideoCache, video *model.Video) {
+	if videoCache == nil {
+		return
+	}
+
+	if err := videoCache.Delete(ctx, video.ID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate video cache",
+			"video_id", video.ID,
+			"error", err,
+		)
+	}
+	if err := videoCache.DeleteFirstPages(ctx, video.UserID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate video list cache",
+			"video_id", video.ID,
+			"user_id", video.UserID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+func TestArchiveService_Archive(t *testing.T) {
+	videoID := uuid.New()
+	existing := &model.VideoArchive{VideoID: videoID, Prefix: "hls/abc/v7/"}
+
+	tests := []struct {
+		name       string
+		status     model.Status
+		hlsURL     string
+		record     *model.VideoArchive
+		wantStatus model.Status
+		wantSaved  bool
+		wantErr    error
+	}{
+		{
+			name:       "ready video",
+			status:     model.StatusReady,
+			hlsURL:     "hls/abc/v7/master.m3u8",
+			wantStatus: model.StatusArchived,
+			wantSaved:  true,
+		},
+		{
+			name:   "already archived",
+			status: model.StatusArchived,
+			hlsURL: "hls/abc/v7/master.m3u8",
+			record: existing,
+		},
+		{
+			name:      "archived without record",
+			status:    model.StatusArchived,
+			hlsURL:    "hls/abc/v7/master.m3u8",
+			wantSaved: true,
+		},
+		{
+			name:    "processing video",
+			status:  model.StatusProcessing,
+			wantErr: ErrVideoNotReady,
+		},
+		{
+			name:    "deleted video",
+			status:  model.StatusDeleted,
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated model.Status
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, Status: tt.status, HLSURL: tt.hlsURL, OutputVersion: 7}, nil
+				},
+				updateStatusFn: func(ctx context.Context, id uuid.UUID, status model.Status) error {
+					updated = status
+					return nil
+				},
+			}
+
+			var saved *model.VideoArchive
+			archives := &mockArchiveRepository{
+				saveFn: func(ctx context.Context, archive *model.VideoArchive) error {
+					if updated == "" && tt.status == model.StatusReady {
+						t.Error("record saved before the status changed")
+					}
+					saved = archive
+					return nil
+				},
+				getByVideoIDFn: func(ctx context.Context, id uuid.UUID) (*model.VideoArchive, error) {
+					if tt.record == nil {
+						return nil, repository.ErrArchiveNotFound
+					}
+					return tt.record, nil
+				},
+			}
+
+			published := false
+			events := &mockVideoEventBus{
+				publishFn: func(ctx context.Context, event cache.VideoEvent) error {
+					published = true
+					return nil
+				},
+			}
+
+			svc := NewArchiveService(videos, archives, nil, events, nil, DefaultArchiveServiceConfig())
+			got, err := svc.Archive(context.Background(), videoID)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Archive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if updated != tt.wantStatus || published != (tt.wantStatus != "") {
+				t.Errorf("status update = %q, published = %v, want %q", updated, published, tt.wantStatus)
+			}
+			if (saved != nil) != tt.wantSaved {
+				t.Fatalf("saved = %+v, want saved %v", saved, tt.wantSaved)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if saved != nil && (saved != got || saved.Prefix != "hls/abc/v7/" || saved.OutputVersion != 7) {
+				t.Errorf("saved = %+v, returned %+v", saved, got)
+			}
+			if tt.record != nil && got != tt.record {
+				t.Errorf("Archive() = %+v, want existing record", got)
+			}
+		})
+	}
+}
+
+func TestArchiveService_Restore(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	firstETA := now.Add(-5 * time.Minute)
+
+	tests := []struct {
+		name      string
+		status    model.Status
+		pending   *time.Time
+		missing   bool
+		wantETA   time.Time
+		wantSaved bool
+		wantErr   error
+	}{
+		{
+			name:    "first request",
+			status:  model.StatusArchived,
+			wantETA: now.Add(15 * time.Minute),
+		},
+		{
+			name:    "pending request keeps its ETA",
+			status:  model.StatusArchived,
+			pending: &firstETA,
+			wantETA: firstETA,
+		},
+		{
+			name:      "record missing",
+			status:    model.StatusArchived,
+			missing:   true,
+			wantETA:   now.Add(15 * time.Minute),
+			wantSaved: true,
+		},
+		{
+			name:    "video not archived",
+			status:  model.StatusReady,
+			wantErr: ErrVideoNotArchived,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, Status: tt.status, HLSURL: "hls/abc/v7/master.m3u8"}, nil
+				},
+			}
+
+			var saved *model.VideoArchive
+			archives := &mockArchiveRepository{
+				requestRestoreFn: func(ctx context.Context, id uuid.UUID, requestedAt, eta time.Time) (*model.VideoArchive, error) {
+					if tt.missing {
+						return nil, repository.ErrArchiveNotFound
+					}
+					archive := &model.VideoArchive{VideoID: id, RestoreRequestedAt: &requestedAt, RestoreETA: &eta}
+					if tt.pending != nil {
+						archive.RestoreETA = tt.pending
+					}
+					return archive, nil
+				},
+				saveFn: func(ctx context.Context, archive *model.VideoArchive) error {
+					saved = archive
+					return nil
+				},
+			}
+
+			svc := NewArchiveService(videos, archives, nil, nil, nil, DefaultArchiveServiceConfig()).(*archiveService)
+			svc.now = func() time.Time { return now }
+
+			got, err := svc.Restore(context.Background(), videoID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.RestoreETA == nil || !got.RestoreETA.Equal(tt.wantETA) {
+				t.Errorf("RestoreETA = %v, want %v", got.RestoreETA, tt.wantETA)
+			}
+			if (saved != nil) != tt.wantSaved {
+				t.Errorf("saved = %+v, want saved %v", saved, tt.wantSaved)
+			}
+			if saved != nil && (!saved.IsRestoring() || saved.Prefix != "hls/abc/v7/") {
+				t.Errorf("saved = %+v", saved)
+			}
+		})
+	}
+}
+
+func TestArchiveService_GetArchive(t *testing.T) {
+	ownerID := uuid.New()
+
+	tests := []struct {
+		name       string
+		status     model.Status
+		visibility model.Visibility
+		callerID   uuid.UUID
+		wantErr    error
+	}{
+		{name: "archived video", status: model.StatusArchived, visibility: model.VisibilityUnlisted},
+		{name: "private video of the caller", status: model.StatusArchived, visibility: model.VisibilityPrivate, callerID: ownerID},
+		{name: "private video of another user", status: model.StatusArchived, visibility: model.VisibilityPrivate, callerID: uuid.New(), wantErr: repository.ErrVideoNotFound},
+		{name: "video not archived", status: model.StatusReady, visibility: model.VisibilityUnlisted, wantErr: ErrVideoNotArchived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, UserID: ownerID, Status: tt.status, Visibility: tt.visibility}, nil
+				},
+			}
+			archives := &mockArchiveRepository{
+				getByVideoIDFn: func(ctx context.Context, id uuid.UUID) (*model.VideoArchive, error) {
+					return &model.VideoArchive{VideoID: id}, nil
+				},
+			}
+
+			svc := NewArchiveService(videos, archives, nil, nil, nil, DefaultArchiveServiceConfig())
+			got, err := svc.GetArchive(context.Background(), uuid.New(), tt.callerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got == nil {
+				t.Error("GetArchive() returned no record")
+			}
+		})
+	}
+}
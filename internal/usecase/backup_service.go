@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const (
+	// backupManifestFile is the name of the manifest within a backup directory.
+	backupManifestFile = "manifest.json"
+
+	// backupObjectCheckConcurrency bounds concurrent existence checks during a restore.
+	backupObjectCheckConcurrency = 16
+)
+
+var (
+	// ErrBackupSchemaMismatch is returned when a backup was taken at a different migration version.
+	ErrBackupSchemaMismatch = errors.New("backup schema version does not match the database")
+
+	// ErrBackupObjectsMissing is returned when objects referenced by a backup are missing from storage.
+	ErrBackupObjectsMissing = errors.New("backup references objects missing from storage")
+)
+
+// BackupManifest is written next to the table dumps of a backup.
+type BackupManifest struct {
+	CreatedAt     time.Time        `json:"created_at"`
+	SchemaVersion int64            `json:"schema_version"`
+	Tables        map[string]int64 `json:"tables"`
+	StorageKeys   []string         `json:"storage_keys"`
+}
+
+// RestoreOptions controls a restore.
+type RestoreOptions struct {
+	// DryRun validates the backup and the referenced objects without loading any rows.
+	DryRun bool
+	// AllowMissingObjects restores even if referenced objects are missing from storage.
+	AllowMissingObjects bool
+}
+
+// RestoreResult reports the outcome of a restore.
+type RestoreResult struct {
+	// Tables is the number of rows loaded per table; empty for a dry run.
+	Tables map[string]int64
+	// MissingKeys lists referenced objects that do not exist in storage, sorted.
+	MissingKeys []string
+}
+
+// BackupService takes and restores logical backups of the service metadata.
+// A backup is a directory holding one JSON Lines file per table ({table}.jsonl)
+// and a manifest of the schema version, row counts and referenced storage keys.
+type BackupService interface {
+	// Backup writes a consistent dump of the metadata to dir, creating it if needed.
+	Backup(ctx context.Context, dir string) (*BackupManifest, error)
+
+	// Restore checks that every object referenced by the backup in dir exists, then
+	// loads the tables into an empty database. Returns ErrBackupSchemaMismatch if the
+	// database is at a different migration version, ErrBackupObjectsMissing (with the
+	// missing keys in the result) unless AllowMissingObjects is set, or
+	// repository.ErrRestoreTargetNotEmpty if the database already has rows.
+	Restore(ctx context.Context, dir string, opts RestoreOptions) (*RestoreResult, error)
+}
+
+type backupService struct {
+	repo    repository.MetadataBackupRepository
+	storage repository.ObjectStorage
+	now     func() time.Time
+}
+
+// NewBackupService creates a new BackupService instance.
+func NewBackupService(repo repository.MetadataBackupRepository, storage repository.ObjectStorage) BackupService {
+	return &backupService{
+		repo:    repo,
+		storage: storage,
+		now:     time.Now,
+	}
+}
+
+// Backup writes the manifest last, so a directory without one is an incomplete backup.
+func (s *backupService) Backup(ctx context.Context, dir string) (*BackupManifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create backup directory: %w", err)
+	}
+
+	createdAt := s.now().UTC()
+	snapshot, err := s.repo.Dump(ctx, func(table string) (io.WriteCloser, error) {
+		return os.Create(backupTablePath(dir, table))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dump metadata: %w", err)
+	}
+
+	manifest := &BackupManifest{
+		CreatedAt:     createdAt,
+		SchemaVersion: snapshot.SchemaVersion,
+		Tables:        snapshot.Rows,
+		StorageKeys:   snapshot.StorageKeys,
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, backupManifestFile), data, 0o644); err != nil {
+		return nil, fmt.Errorf("write manifest: %w", err)
+	}
+
+	slog.InfoContext(ctx, "metadata backup written",
+		"dir", dir,
+		"schema_version", manifest.SchemaVersion,
+		"storage_keys", len(manifest.StorageKeys),
+	)
+
+	return manifest, nil
+}
+
+func (s *backupService) Restore(ctx context.Context, dir string, opts RestoreOptions) (*RestoreResult, error) {
+	manifest, err := readBackupManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.repo.SchemaVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get schema version: %w", err)
+	}
+	if version != manifest.SchemaVersion {
+		return nil, fmt.Errorf("%w: backup is at %d, database is at %d", ErrBackupSchemaMismatch, manifest.SchemaVersion, version)
+	}
+
+	missing, err := s.missingObjects(ctx, manifest.StorageKeys)
+	if err != nil {
+		return nil, err
+	}
+	result := &RestoreResult{MissingKeys: missing}
+	if len(missing) > 0 && !opts.AllowMissingObjects {
+		return result, fmt.Errorf("%w: %d of %d", ErrBackupObjectsMissing, len(missing), len(manifest.StorageKeys))
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	result.Tables, err = s.repo.Restore(ctx, func(table string) (io.ReadCloser, error) {
+		return os.Open(backupTablePath(dir, table))
+	})
+	if err != nil {
+		return result, fmt.Errorf("restore metadata: %w", err)
+	}
+
+	for table, want := range manifest.Tables {
+		if got := result.Tables[table]; got != want {
+			slog.WarnContext(ctx, "restored row count differs from manifest",
+				"table", table,
+				"manifest", want,
+				"restored", got,
+			)
+		}
+	}
+
+	return result, nil
+}
+
+// missingObjects returns the keys that do not exist in storage, sorted.
+func (s *backupService) missingObjects(ctx context.Context, keys []string) ([]string, error) {
+	var (
+		mu      sync.Mutex
+		missing []string
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(backupObjectCheckConcurrency)
+	for _, key := range keys {
+		g.Go(func() error {
+			exists, err := s.storage.Exists(gctx, key)
+			if err != nil {
+				return fmt.Errorf("check %s: %w", key, err)
+			}
+			if !exists {
+				mu.Lock()
+				missing = append(missing, key)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(missing)
+	return missing, nil
+}
+
+func readBackupManifest(dir string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, backupManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+func backupTablePath(dir, table string) string {
+	return filepath.Join(dir, table+".jsonl")
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// backupTestRepo dumps a single videos table and restores by reading it back.
+func backupTestRepo(version int64) *mockMetadataBackupRepository {
+	return &mockMetadataBackupRepository{
+		dumpFn: func(ctx context.Context, create func(table string) (io.WriteCloser, error)) (*repository.MetadataSnapshot, error) {
+			w, err := create("videos")
+			if err != nil {
+				return nil, err
+			}
+			if _, err := io.WriteString(w, "{\"id\":\"a\"}\n{\"id\":\"b\"}\n"); err != nil {
+				return nil, err
+			}
+			if err := w.Close(); err != nil {
+				return nil, err
+			}
+			return &repository.MetadataSnapshot{
+				SchemaVersion: version,
+				Rows:          map[string]int64{"videos": 2},
+				StorageKeys:   []string{"hls/a/v1/master.m3u8", "originals/a/in.mp4"},
+			}, nil
+		},
+		restoreFn: func(ctx context.Context, open func(table string) (io.ReadCloser, error)) (map[string]int64, error) {
+			rc, err := open("videos")
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			if err != nil {
+				return nil, err
+			}
+			if string(data) != "{\"id\":\"a\"}\n{\"id\":\"b\"}\n" {
+				return nil, errors.New("unexpected dump content")
+			}
+			return map[string]int64{"videos": 2}, nil
+		},
+		schemaVersionFn: func(ctx context.Context) (int64, error) {
+			return version, nil
+		},
+	}
+}
+
+func TestBackupService_Backup(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backup")
+	svc := NewBackupService(backupTestRepo(13), &mockObjectStorage{})
+
+	manifest, err := svc.Backup(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.SchemaVersion != 13 || manifest.Tables["videos"] != 2 || len(manifest.StorageKeys) != 2 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+
+	for _, name := range []string{"manifest.json", "videos.jsonl"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s in backup: %v", name, err)
+		}
+	}
+}
+
+func TestBackupService_Backup_DumpFailure(t *testing.T) {
+	dir := t.TempDir()
+	dumpErr := errors.New("connection refused")
+	repo := &mockMetadataBackupRepository{
+		dumpFn: func(ctx context.Context, create func(table string) (io.WriteCloser, error)) (*repository.MetadataSnapshot, error) {
+			return nil, dumpErr
+		},
+	}
+
+	svc := NewBackupService(repo, &mockObjectStorage{})
+	if _, err := svc.Backup(context.Background(), dir); !errors.Is(err, dumpErr) {
+		t.Fatalf("expected error %v, got %v", dumpErr, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manifest.json")); !os.IsNotExist(err) {
+		t.Error("manifest must not be written for a failed dump")
+	}
+}
+
+func TestBackupService_Restore(t *testing.T) {
+	storageErr := errors.New("timeout")
+
+	tests := []struct {
+		name         string
+		dbVersion    int64
+		missing      []string
+		existsErr    error
+		opts         RestoreOptions
+		wantErr      error
+		wantRestored bool
+		wantMissing  []string
+	}{
+		{
+			name:         "restores when all objects exist",
+			dbVersion:    13,
+			wantRestored: true,
+		},
+		{
+			name:      "schema mismatch",
+			dbVersion: 14,
+			wantErr:   ErrBackupSchemaMismatch,
+		},
+		{
+			name:        "missing objects",
+			dbVersion:   13,
+			missing:     []string{"originals/a/in.mp4"},
+			wantErr:     ErrBackupObjectsMissing,
+			wantMissing: []string{"originals/a/in.mp4"},
+		},
+		{
+			name:         "missing objects allowed",
+			dbVersion:    13,
+			missing:      []string{"originals/a/in.mp4"},
+			opts:         RestoreOptions{AllowMissingObjects: true},
+			wantRestored: true,
+			wantMissing:  []string{"originals/a/in.mp4"},
+		},
+		{
+			name:      "dry run",
+			dbVersion: 13,
+			opts:      RestoreOptions{DryRun: true},
+		},
+		{
+			name:      "storage error",
+			dbVersion: 13,
+			existsErr: storageErr,
+			wantErr:   storageErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if _, err := NewBackupService(backupTestRepo(13), &mockObjectStorage{}).Backup(context.Background(), dir); err != nil {
+				t.Fatalf("failed to write backup: %v", err)
+			}
+
+			repo := backupTestRepo(tt.dbVersion)
+			restored := false
+			restoreFn := repo.restoreFn
+			repo.restoreFn = func(ctx context.Context, open func(table string) (io.ReadCloser, error)) (map[string]int64, error) {
+				restored = true
+				return restoreFn(ctx, open)
+			}
+			storage := &mockObjectStorage{
+				existsFn: func(ctx context.Context, key string) (bool, error) {
+					if tt.existsErr != nil {
+						return false, tt.existsErr
+					}
+					return !slices.Contains(tt.missing, key), nil
+				},
+			}
+
+			result, err := NewBackupService(repo, storage).Restore(context.Background(), dir, tt.opts)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if restored != tt.wantRestored {
+				t.Errorf("restored: got %v, expected %v", restored, tt.wantRestored)
+			}
+			if result != nil && !slices.Equal(result.MissingKeys, tt.wantMissing) {
+				t.Errorf("missing keys: got %v, expected %v", result.MissingKeys, tt.wantMissing)
+			}
+			if tt.wantRestored && result.Tables["videos"] != 2 {
+				t.Errorf("restored rows: got %v", result.Tables)
+			}
+		})
+	}
+}
@@ -2,13 +2,19 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"math/rand/v2"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
 	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
 	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
 	"golang.org/x/sync/singleflight"
@@ -20,6 +26,17 @@ type CachedVideoServiceConfig struct {
 	CacheTTL time.Duration
 	// CDNBaseURL is the base URL for CDN-served HLS content.
 	CDNBaseURL string
+	// SecondaryCDNBaseURL is the base URL fronting the secondary storage region.
+	// Empty disables region-aware URL selection.
+	SecondaryCDNBaseURL string
+	// SecondaryRegions lists viewer regions served from the secondary CDN (case-insensitive).
+	SecondaryRegions []string
+	// StaleTTL is how long past CacheTTL a cached video is kept to be served, marked
+	// stale, while the database is unavailable; 0 disables serving stale videos.
+	StaleTTL time.Duration
+	// EarlyRefreshBeta scales how early a cached video is reloaded before it expires;
+	// 1 is the usual choice, larger values refresh earlier, and 0 disables early refresh.
+	EarlyRefreshBeta float64
 }
 
 // DefaultCachedVideoServiceConfig returns the default configuration.
@@ -30,68 +47,219 @@ func DefaultCachedVideoServiceConfig() CachedVideoServiceConfig {
 	}
 }
 
+// PopularityTracker counts requests per cache key and classifies keys by popularity.
+// Implemented by metrics.KeyPopularity.
+type PopularityTracker interface {
+	// Record counts a request for key at now.
+	Record(key string, now time.Time)
+	// Class returns metrics.KeyClassHot, KeyClassWarm or KeyClassCold for key at now.
+	Class(key string, now time.Time) string
+}
+
 // cachedVideoService wraps VideoService with caching capabilities.
 // It implements the decorator pattern to add caching without modifying the original service.
 type cachedVideoService struct {
 	delegate VideoService
 	cache    cache.VideoCache
-	sfGroup  singleflight.Group
+	domains  PlaybackDomainResolver
+	// popularity is optional; nil labels every key cold.
+	popularity PopularityTracker
+	outputs    OutputBucketResolver
+	sfGroup    singleflight.Group
+
+	cacheTTL            time.Duration
+	staleTTL            time.Duration
+	earlyRefreshBeta    float64
+	random              func() float64
+	cdnBaseURL          string
+	secondaryCDNBaseURL string
+	secondaryRegions    map[string]struct{}
+}
+
+// viewerRegionKey is the context key for the viewer's region.
+type viewerRegionKey struct{}
 
-	cacheTTL   time.Duration
-	cdnBaseURL string
+// WithViewerRegion returns a context carrying the viewer's region.
+// GetVideo uses it to point playback URLs at the closest storage region.
+func WithViewerRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, viewerRegionKey{}, region)
 }
 
 // NewCachedVideoService creates a new CachedVideoService wrapping the provided VideoService.
+// The domains parameter is optional - pass nil to always use the configured CDN base URLs.
+// The popularity parameter is optional - pass nil to label every key cold in metrics.
+// The outputs parameter is optional - pass nil when no tenant can have an output bucket.
 func NewCachedVideoService(
 	delegate VideoService,
 	videoCache cache.VideoCache,
+	domains PlaybackDomainResolver,
+	popularity PopularityTracker,
+	outputs OutputBucketResolver,
 	cfg CachedVideoServiceConfig,
 ) VideoService {
+	secondaryRegions := make(map[string]struct{}, len(cfg.SecondaryRegions))
+	for _, region := range cfg.SecondaryRegions {
+		secondaryRegions[strings.ToLower(strings.TrimSpace(region))] = struct{}{}
+	}
+
 	return &cachedVideoService{
-		delegate:   delegate,
-		cache:      videoCache,
-		cacheTTL:   cfg.CacheTTL,
-		cdnBaseURL: cfg.CDNBaseURL,
+		delegate:            delegate,
+		cache:               videoCache,
+		domains:             domains,
+		popularity:          popularity,
+		outputs:             outputs,
+		cacheTTL:            cfg.CacheTTL,
+		staleTTL:            cfg.StaleTTL,
+		earlyRefreshBeta:    cfg.EarlyRefreshBeta,
+		random:              rand.Float64,
+		cdnBaseURL:          cfg.CDNBaseURL,
+		secondaryCDNBaseURL: cfg.SecondaryCDNBaseURL,
+		secondaryRegions:    secondaryRegions,
 	}
 }
 
-// CreateVideo delegates to the underlying service.
-// No caching for create operations - the video is immediately returned.
+// CreateVideo delegates to the underlying service and invalidates the user's cached
+// first page, which the new video now heads.
 func (s *cachedVideoService) CreateVideo(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error) {
-	return s.delegate.CreateVideo(ctx, input)
+	output, err := s.delegate.CreateVideo(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateFirstPages(ctx, input.UserID)
+	return output, nil
+}
+
+// UploadVideo delegates to the underlying service. The video is new, so only the owner's
+// first pages are invalidated.
+func (s *cachedVideoService) UploadVideo(ctx context.Context, input UploadVideoInput) (*model.Video, error) {
+	video, err := s.delegate.UploadVideo(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateFirstPages(ctx, input.UserID)
+	return s.enrichWithCDNURL(ctx, video), nil
+}
+
+// CompleteUpload delegates to the underlying service and invalidates the cache
+// so the next GetVideo reflects the UPLOADED status. The video is enriched because
+// a video already past PENDING_UPLOAD is returned as is.
+func (s *cachedVideoService) CompleteUpload(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	video, err := s.delegate.CompleteUpload(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, videoID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cache on upload complete",
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+	s.invalidateFirstPages(ctx, video.UserID)
+
+	return s.enrichWithCDNURL(ctx, video), nil
+}
+
+// InitiateMultipartUpload delegates to the underlying service; the video is unchanged.
+func (s *cachedVideoService) InitiateMultipartUpload(ctx context.Context, videoID uuid.UUID, size int64) (*MultipartUpload, error) {
+	return s.delegate.InitiateMultipartUpload(ctx, videoID, size)
+}
+
+// CompleteMultipartUpload delegates to the underlying service and invalidates the cache
+// like CompleteUpload.
+func (s *cachedVideoService) CompleteMultipartUpload(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error) {
+	video, err := s.delegate.CompleteMultipartUpload(ctx, videoID, uploadID, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, videoID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cache on multipart upload complete",
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+	s.invalidateFirstPages(ctx, video.UserID)
+
+	return s.enrichWithCDNURL(ctx, video), nil
 }
 
 // TriggerProcess invalidates the cache and delegates to the underlying service.
 // Cache invalidation happens before processing to ensure stale data is not served
-// during the transition to PROCESSING status.
-func (s *cachedVideoService) TriggerProcess(ctx context.Context, videoID uuid.UUID) error {
+// during the transition to PROCESSING status. The owner's first page is found
+// through the video lookup, which is usually a cache hit.
+func (s *cachedVideoService) TriggerProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) error {
+	if video, err := s.getVideoWithCache(ctx, videoID); err == nil {
+		s.invalidateFirstPages(ctx, video.UserID)
+	}
+
 	// Invalidate cache before triggering process
 	// This ensures the next GetVideo call fetches fresh data
 	if err := s.cache.Delete(ctx, videoID); err != nil {
 		// Log but don't fail - cache invalidation failure is non-critical
-		slog.Warn("failed to invalidate cache on trigger process",
+		slog.WarnContext(ctx, "failed to invalidate cache on trigger process",
 			"video_id", videoID,
 			"error", err,
 		)
 	}
 
-	return s.delegate.TriggerProcess(ctx, videoID)
+	return s.delegate.TriggerProcess(ctx, videoID, input)
+}
+
+// PlanProcess delegates to the underlying service; plans change nothing, so the cache is left alone.
+func (s *cachedVideoService) PlanProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) (*TranscodePlan, error) {
+	return s.delegate.PlanProcess(ctx, videoID, input)
+}
+
+// Retranscode delegates to the underlying service.
+// A READY video keeps serving its current output until the worker publishes the new
+// version and invalidates the entry, so only a FAILED video, which moves back to
+// PROCESSING, is evicted here.
+func (s *cachedVideoService) Retranscode(ctx context.Context, videoID uuid.UUID, input RetranscodeInput) error {
+	video, lookupErr := s.getVideoWithCache(ctx, videoID)
+
+	if err := s.delegate.Retranscode(ctx, videoID, input); err != nil {
+		return err
+	}
+
+	if lookupErr != nil || video.Status != model.StatusFailed {
+		return nil
+	}
+	s.invalidateFirstPages(ctx, video.UserID)
+	if err := s.cache.Delete(ctx, videoID); err != nil {
+		// Log but don't fail - cache invalidation failure is non-critical
+		slog.WarnContext(ctx, "failed to invalidate cache on retranscode",
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+	return nil
 }
 
 // GetVideo retrieves video information with caching and CDN URL enrichment.
-// Uses singleflight to prevent cache stampede on concurrent requests for the same video.
+// Uses singleflight to prevent cache stampede on concurrent requests for the same video,
+// and with an early refresh beta configured, reloads popular videos shortly before their
+// entry expires so replicas do not all miss at once.
+// With a stale TTL configured, a database outage is answered from an expired cache entry,
+// marked Stale, instead of failing.
 func (s *cachedVideoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
 	// Use singleflight to coalesce concurrent requests
 	key := videoID.String()
+	if s.popularity != nil {
+		s.popularity.Record(key, time.Now())
+	}
 	result, err, shared := s.sfGroup.Do(key, func() (any, error) {
 		return s.getVideoWithCache(ctx, videoID)
 	})
 
 	// Record singleflight metrics
+	class := s.keyClass(videoID)
 	if shared {
-		metrics.SingleflightRequestsTotal.WithLabelValues(metrics.SingleflightShared).Inc()
+		metrics.SingleflightRequestsTotal.WithLabelValues(metrics.SingleflightShared, class).Inc()
 	} else {
-		metrics.SingleflightRequestsTotal.WithLabelValues(metrics.SingleflightInitiated).Inc()
+		metrics.SingleflightRequestsTotal.WithLabelValues(metrics.SingleflightInitiated, class).Inc()
 	}
 
 	if err != nil {
@@ -99,34 +267,234 @@ func (s *cachedVideoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*
 	}
 
 	video := result.(*model.Video)
-	return s.enrichWithCDNURL(video), nil
+	return s.enrichWithCDNURL(ctx, video), nil
+}
+
+// ListVideos enriches each video of a listing with its CDN URL.
+// Only the first page is cached, since the dashboard requests it far more than any other;
+// later pages are addressed by cursor and go straight to the underlying service.
+// The cached page is invalidated whenever one of the user's videos changes.
+func (s *cachedVideoService) ListVideos(ctx context.Context, input ListVideosInput) (*ListVideosOutput, error) {
+	output, err := s.listVideosWithCache(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range output.Videos {
+		output.Videos[i] = s.enrichWithCDNURL(ctx, v)
+	}
+	return output, nil
+}
+
+// SearchVideos enriches each video of the results with its CDN URL. Results are not
+// cached: queries rarely repeat, and a cached page would miss videos created since.
+func (s *cachedVideoService) SearchVideos(ctx context.Context, input SearchVideosInput) (*ListVideosOutput, error) {
+	output, err := s.delegate.SearchVideos(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range output.Videos {
+		output.Videos[i] = s.enrichWithCDNURL(ctx, v)
+	}
+	return output, nil
+}
+
+// DeleteVideo delegates to the underlying service and evicts the video and the owner's
+// first pages right away; the worker evicts them again once the storage is cleaned up.
+func (s *cachedVideoService) DeleteVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	video, err := s.delegate.DeleteVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, videoID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cache on delete",
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+	s.invalidateFirstPages(ctx, video.UserID)
+
+	return video, nil
+}
+
+// SetExpiration delegates to the underlying service and evicts the video and the
+// owner's first pages, which carry the expiry.
+func (s *cachedVideoService) SetExpiration(ctx context.Context, videoID uuid.UUID, expiresAt time.Time) (*model.Video, error) {
+	video, err := s.delegate.SetExpiration(ctx, videoID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, videoID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cache on expiry change",
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+	s.invalidateFirstPages(ctx, video.UserID)
+
+	return s.enrichWithCDNURL(ctx, video), nil
+}
+
+// UpdateMetadata delegates to the underlying service and evicts the video and the
+// owner's cached first pages, which carry the old metadata.
+func (s *cachedVideoService) UpdateMetadata(ctx context.Context, videoID uuid.UUID, input UpdateMetadataInput) (*model.Video, error) {
+	video, err := s.delegate.UpdateMetadata(ctx, videoID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, videoID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cache on metadata update",
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+	s.invalidateFirstPages(ctx, video.UserID)
+
+	return s.enrichWithCDNURL(ctx, video), nil
+}
+
+// GetOriginalURL delegates to the underlying service.
+// Presigned URLs are per request and must not be shared through the cache.
+func (s *cachedVideoService) GetOriginalURL(ctx context.Context, input OriginalURLInput) (*OriginalURL, error) {
+	return s.delegate.GetOriginalURL(ctx, input)
+}
+
+// ListStatusEvents delegates to the underlying service; the history is read for debugging
+// and not worth caching.
+func (s *cachedVideoService) ListStatusEvents(ctx context.Context, videoID, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+	return s.delegate.ListStatusEvents(ctx, videoID, callerID, limit)
+}
+
+// GetStorageUsage delegates to the underlying service; usage must be current for the
+// quota it is compared with.
+func (s *cachedVideoService) GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error) {
+	return s.delegate.GetStorageUsage(ctx, userID)
+}
+
+// GetTranscodeProgress delegates to the underlying service.
+// Progress changes every few seconds, so it is never cached with the video.
+func (s *cachedVideoService) GetTranscodeProgress(ctx context.Context, videoID uuid.UUID) (int, bool) {
+	return s.delegate.GetTranscodeProgress(ctx, videoID)
+}
+
+// ResolveShareSlug delegates to the underlying service.
+// Share links only need the video ID, so the lookup is neither cached nor enriched.
+func (s *cachedVideoService) ResolveShareSlug(ctx context.Context, slug string) (*model.Video, error) {
+	return s.delegate.ResolveShareSlug(ctx, slug)
+}
+
+// ResolveTitleSlug delegates to the underlying service, uncached like ResolveShareSlug.
+func (s *cachedVideoService) ResolveTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error) {
+	return s.delegate.ResolveTitleSlug(ctx, userID, slug)
+}
+
+// listVideosWithCache implements the cache-aside pattern for the first page of the owner's
+// own listing; other callers only see public videos, so their listings bypass the cache.
+// The videos slice is never shared with the cache, so callers may replace its elements.
+func (s *cachedVideoService) listVideosWithCache(ctx context.Context, input ListVideosInput) (*ListVideosOutput, error) {
+	if input.Cursor != "" || input.CallerID != input.UserID {
+		return s.delegate.ListVideos(ctx, input)
+	}
+
+	page, err := s.cache.GetFirstPage(ctx, input.UserID, input.Limit)
+	if err != nil {
+		slog.WarnContext(ctx, "cache get failed, falling back to database",
+			"user_id", input.UserID,
+			"error", err,
+		)
+	}
+
+	if page != nil {
+		videos := append([]*model.Video(nil), page.Videos...)
+		return &ListVideosOutput{Videos: videos, NextCursor: page.NextCursor}, nil // Cache hit
+	}
+
+	// Like loadVideo, the page to cache is read from the primary, so an upload does not
+	// stay missing from the owner's first page for the TTL; the uncached listings above
+	// are the ones served by the replica
+	output, err := s.delegate.ListVideos(repository.WithStaleReads(ctx, false), input)
+	if err != nil {
+		return nil, err
+	}
+
+	page = &cache.VideoPage{
+		Videos:     append([]*model.Video(nil), output.Videos...),
+		NextCursor: output.NextCursor,
+	}
+	if err := s.cache.SetFirstPage(ctx, input.UserID, input.Limit, page, s.cacheTTL); err != nil {
+		slog.WarnContext(ctx, "failed to cache video list",
+			"user_id", input.UserID,
+			"error", err,
+		)
+	}
+
+	return output, nil
 }
 
 // getVideoWithCache implements the cache-aside pattern.
 func (s *cachedVideoService) getVideoWithCache(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
 	// Try cache first
-	video, err := s.cache.Get(ctx, videoID)
+	entry, err := s.cache.GetEntry(ctx, videoID)
 	if err != nil {
 		// Log cache error but continue to database
-		slog.Warn("cache get failed, falling back to database",
+		slog.WarnContext(ctx, "cache get failed, falling back to database",
 			"video_id", videoID,
 			"error", err,
 		)
 	}
 
-	if video != nil {
-		return video, nil // Cache hit
+	if entry != nil {
+		if !s.shouldRefreshEarly(entry) {
+			metrics.VideoCacheLookupsTotal.WithLabelValues(metrics.CacheStatusHit, s.keyClass(videoID)).Inc()
+			return entry.Video, nil // Cache hit
+		}
+
+		// The entry is still valid, so a failed refresh serves it as a hit
+		video, err := s.loadVideo(ctx, videoID)
+		if err != nil {
+			slog.WarnContext(ctx, "early cache refresh failed, serving cached video",
+				"video_id", videoID,
+				"error", err,
+			)
+			metrics.VideoCacheLookupsTotal.WithLabelValues(metrics.CacheStatusHit, s.keyClass(videoID)).Inc()
+			return entry.Video, nil
+		}
+		metrics.VideoCacheLookupsTotal.WithLabelValues(metrics.CacheStatusRefresh, s.keyClass(videoID)).Inc()
+		return video, nil
 	}
 
 	// Cache miss - fetch from database
-	video, err = s.delegate.GetVideo(ctx, videoID)
+	video, err := s.loadVideo(ctx, videoID)
+	if err != nil {
+		if stale := s.getStaleVideo(ctx, videoID, err); stale != nil {
+			metrics.VideoCacheLookupsTotal.WithLabelValues(metrics.CacheStatusStale, s.keyClass(videoID)).Inc()
+			return stale, nil
+		}
+		return nil, err
+	}
+	metrics.VideoCacheLookupsTotal.WithLabelValues(metrics.CacheStatusMiss, s.keyClass(videoID)).Inc()
+	return video, nil
+}
+
+// loadVideo fetches a video from the underlying service and caches it along with how
+// long the fetch took, which paces early refreshes of the entry. The read goes to the
+// primary: a lagging replica's copy would otherwise be served for the whole TTL. Misses
+// are a small share of single-video reads, so the replica is left the reads that are
+// never cached: cursor pages, other callers' listings and search.
+func (s *cachedVideoService) loadVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	start := time.Now()
+	video, err := s.delegate.GetVideo(repository.WithStaleReads(ctx, false), videoID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Store in cache (async-safe: errors logged but not propagated)
-	if err := s.cache.Set(ctx, video, s.cacheTTL); err != nil {
-		slog.Warn("failed to cache video",
+	if err := s.cache.Set(ctx, video, s.cacheTTL, s.staleTTL, time.Since(start)); err != nil {
+		slog.WarnContext(ctx, "failed to cache video",
 			"video_id", videoID,
 			"error", err,
 		)
@@ -135,23 +503,122 @@ func (s *cachedVideoService) getVideoWithCache(ctx context.Context, videoID uuid
 	return video, nil
 }
 
-// enrichWithCDNURL transforms the HLS URL to CDN URL for READY videos.
+// shouldRefreshEarly decides whether a cache hit is reloaded before it expires, using
+// probabilistic early expiration (XFetch): each request refreshes with a probability that
+// grows as the expiry nears, faster for entries that took longer to load. A popular video
+// is therefore refreshed by one request shortly before it expires, instead of by every
+// replica at once when it does. Entries without a load time are never refreshed early.
+func (s *cachedVideoService) shouldRefreshEarly(entry *cache.VideoEntry) bool {
+	if s.earlyRefreshBeta <= 0 || entry.FreshUntil.IsZero() || entry.LoadTime <= 0 {
+		return false
+	}
+
+	r := s.random()
+	if r <= 0 {
+		return true // -log(0) is infinite
+	}
+	gap := float64(entry.LoadTime) * s.earlyRefreshBeta * -math.Log(r)
+	return float64(time.Until(entry.FreshUntil)) <= gap
+}
+
+// keyClass returns the popularity class of a video's cache key for metric labels.
+func (s *cachedVideoService) keyClass(videoID uuid.UUID) string {
+	if s.popularity == nil {
+		return metrics.KeyClassCold
+	}
+	return s.popularity.Class(videoID.String(), time.Now())
+}
+
+// getStaleVideo returns the cached copy of a video past its TTL, marked stale, when the
+// lookup failed with a transient error such as a database outage. Returns nil when stale
+// reads are disabled, the error is not transient, or nothing is retained, in which case
+// the caller reports the original error.
+func (s *cachedVideoService) getStaleVideo(ctx context.Context, videoID uuid.UUID, lookupErr error) *model.Video {
+	if s.staleTTL <= 0 || !errors.Is(lookupErr, errs.Transient) {
+		return nil
+	}
+
+	video, err := s.cache.GetStale(ctx, videoID)
+	if err != nil {
+		slog.WarnContext(ctx, "stale cache get failed",
+			"video_id", videoID,
+			"error", err,
+		)
+		return nil
+	}
+	if video == nil {
+		return nil
+	}
+
+	slog.WarnContext(ctx, "video lookup failed, serving stale cache entry",
+		"video_id", videoID,
+		"error", lookupErr,
+	)
+	video.Stale = true
+	return video
+}
+
+// enrichWithCDNURL transforms the HLS, DASH, preview and thumbnail URLs to CDN URLs for READY videos.
+// The CDN is chosen per request from the owner's custom domain or the viewer's region;
+// the cached copy stays neutral.
 // Returns a copy to avoid mutating cached data.
-func (s *cachedVideoService) enrichWithCDNURL(video *model.Video) *model.Video {
-	if video.Status != model.StatusReady || video.HLSURL == "" {
+func (s *cachedVideoService) enrichWithCDNURL(ctx context.Context, video *model.Video) *model.Video {
+	if video.Status != model.StatusReady || (video.HLSURL == "" && video.DashURL == "") {
 		return video
 	}
 
 	// Create a copy to avoid mutating cached data
+	baseURL := s.cdnBaseURLFor(ctx, video)
 	enriched := *video
-	enriched.HLSURL = s.buildCDNURL(video.ID)
+	if video.HLSURL != "" {
+		enriched.HLSURL = buildCDNURL(baseURL, video.HLSURL)
+	}
+	if video.DashURL != "" {
+		enriched.DashURL = buildCDNURL(baseURL, video.DashURL)
+	}
+	if video.ThumbnailPrefix != "" {
+		enriched.ThumbnailPrefix = buildCDNURL(baseURL, video.ThumbnailPrefix) + "/"
+	}
+	if video.HasPreview() {
+		enriched.PreviewURL = buildCDNURL(baseURL, video.PreviewURL)
+	}
 	return &enriched
 }
 
-// buildCDNURL constructs the CDN URL for a video's HLS manifest.
-// Format: {CDN_BASE_URL}/hls/{videoID}/master.m3u8
-func (s *cachedVideoService) buildCDNURL(videoID uuid.UUID) string {
-	return fmt.Sprintf("%s/%s", s.cdnBaseURL, path.Join("hls", videoID.String(), "master.m3u8"))
+// cdnBaseURLFor returns the CDN base URL for a video.
+// Output in the tenant's output bucket is served from the bucket's public base URL.
+// Otherwise a tenant's verified custom domain wins, since its CDN is expected to front every
+// region, and viewers with an unknown or unmapped region are served from the primary CDN.
+func (s *cachedVideoService) cdnBaseURLFor(ctx context.Context, video *model.Video) string {
+	if video.ExternalOutput && s.outputs != nil {
+		if baseURL := s.outputs.OutputBaseURL(ctx, video.UserID); baseURL != "" {
+			return baseURL
+		}
+	}
+
+	if s.domains != nil {
+		if baseURL := s.domains.PlaybackBaseURL(ctx, video.UserID); baseURL != "" {
+			return baseURL
+		}
+	}
+
+	if s.secondaryCDNBaseURL == "" {
+		return s.cdnBaseURL
+	}
+
+	region, _ := ctx.Value(viewerRegionKey{}).(string)
+	if _, ok := s.secondaryRegions[strings.ToLower(region)]; ok && region != "" {
+		return s.secondaryCDNBaseURL
+	}
+	return s.cdnBaseURL
+}
+
+// buildCDNURL constructs the CDN URL for a stored manifest key.
+// The key is the video's output pointer, so a published version switch is
+// picked up on the next read without purging anything at the CDN.
+// Format: {CDN_BASE_URL}/{key} (e.g., hls/{videoID}/v{version}/master.m3u8)
+func buildCDNURL(baseURL, key string) string {
+	return fmt.Sprintf("%s/%s", baseURL, path.Clean(strings.TrimPrefix(key, "/")))
 }
 
 // InvalidateCache removes a video from the cache.
@@ -159,3 +626,14 @@ func (s *cachedVideoService) buildCDNURL(videoID uuid.UUID) string {
 func (s *cachedVideoService) InvalidateCache(ctx context.Context, videoID uuid.UUID) error {
 	return s.cache.Delete(ctx, videoID)
 }
+
+// invalidateFirstPages removes the user's cached first pages.
+// Errors are logged but not propagated - cache invalidation is non-critical.
+func (s *cachedVideoService) invalidateFirstPages(ctx context.Context, userID uuid.UUID) {
+	if err := s.cache.DeleteFirstPages(ctx, userID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate video list cache",
+			"user_id", userID,
+			"error", err,
+		)
+	}
+}
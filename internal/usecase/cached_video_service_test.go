@@ -9,15 +9,35 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
 )
 
 // mockVideoService is a mock implementation of VideoService for testing.
 type mockVideoService struct {
-	createVideoFn    func(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error)
-	triggerProcessFn func(ctx context.Context, videoID uuid.UUID) error
-	getVideoFn       func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
-	getVideoCount    atomic.Int32
+	createVideoFn       func(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error)
+	uploadVideoFn       func(ctx context.Context, input UploadVideoInput) (*model.Video, error)
+	completeUploadFn    func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	initiateMultipartFn func(ctx context.Context, videoID uuid.UUID, size int64) (*MultipartUpload, error)
+	completeMultipartFn func(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error)
+	triggerProcessFn    func(ctx context.Context, videoID uuid.UUID, input ProcessInput) error
+	planProcessFn       func(ctx context.Context, videoID uuid.UUID, input ProcessInput) (*TranscodePlan, error)
+	retranscodeFn       func(ctx context.Context, videoID uuid.UUID, input RetranscodeInput) error
+	getVideoFn          func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	resolveShareSlugFn  func(ctx context.Context, slug string) (*model.Video, error)
+	resolveTitleSlugFn  func(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error)
+	transcodeProgressFn func(ctx context.Context, videoID uuid.UUID) (int, bool)
+	listVideosFn        func(ctx context.Context, input ListVideosInput) (*ListVideosOutput, error)
+	searchVideosFn      func(ctx context.Context, input SearchVideosInput) (*ListVideosOutput, error)
+	deleteVideoFn       func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	setExpirationFn     func(ctx context.Context, videoID uuid.UUID, expiresAt time.Time) (*model.Video, error)
+	updateMetadataFn    func(ctx context.Context, videoID uuid.UUID, input UpdateMetadataInput) (*model.Video, error)
+	getOriginalURLFn    func(ctx context.Context, input OriginalURLInput) (*OriginalURL, error)
+	getVideoCount       atomic.Int32
+	listStatusEventsFn  func(ctx context.Context, videoID, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error)
+	getStorageUsageFn   func(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error)
 }
 
 func (m *mockVideoService) CreateVideo(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error) {
@@ -27,13 +47,69 @@ func (m *mockVideoService) CreateVideo(ctx context.Context, input CreateVideoInp
 	return nil, nil
 }
 
-func (m *mockVideoService) TriggerProcess(ctx context.Context, videoID uuid.UUID) error {
+func (m *mockVideoService) UploadVideo(ctx context.Context, input UploadVideoInput) (*model.Video, error) {
+	if m.uploadVideoFn != nil {
+		return m.uploadVideoFn(ctx, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) CompleteUpload(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	if m.completeUploadFn != nil {
+		return m.completeUploadFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) InitiateMultipartUpload(ctx context.Context, videoID uuid.UUID, size int64) (*MultipartUpload, error) {
+	if m.initiateMultipartFn != nil {
+		return m.initiateMultipartFn(ctx, videoID, size)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) CompleteMultipartUpload(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error) {
+	if m.completeMultipartFn != nil {
+		return m.completeMultipartFn(ctx, videoID, uploadID, parts)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) TriggerProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) error {
 	if m.triggerProcessFn != nil {
-		return m.triggerProcessFn(ctx, videoID)
+		return m.triggerProcessFn(ctx, videoID, input)
+	}
+	return nil
+}
+
+func (m *mockVideoService) PlanProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) (*TranscodePlan, error) {
+	if m.planProcessFn != nil {
+		return m.planProcessFn(ctx, videoID, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) Retranscode(ctx context.Context, videoID uuid.UUID, input RetranscodeInput) error {
+	if m.retranscodeFn != nil {
+		return m.retranscodeFn(ctx, videoID, input)
 	}
 	return nil
 }
 
+func (m *mockVideoService) ListStatusEvents(ctx context.Context, videoID, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+	if m.listStatusEventsFn != nil {
+		return m.listStatusEventsFn(ctx, videoID, callerID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error) {
+	if m.getStorageUsageFn != nil {
+		return m.getStorageUsageFn(ctx, userID)
+	}
+	return nil, nil
+}
+
 func (m *mockVideoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
 	m.getVideoCount.Add(1)
 	if m.getVideoFn != nil {
@@ -42,18 +118,87 @@ func (m *mockVideoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*mo
 	return nil, nil
 }
 
+func (m *mockVideoService) GetTranscodeProgress(ctx context.Context, videoID uuid.UUID) (int, bool) {
+	if m.transcodeProgressFn != nil {
+		return m.transcodeProgressFn(ctx, videoID)
+	}
+	return 0, false
+}
+
+func (m *mockVideoService) ResolveShareSlug(ctx context.Context, slug string) (*model.Video, error) {
+	if m.resolveShareSlugFn != nil {
+		return m.resolveShareSlugFn(ctx, slug)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) ResolveTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error) {
+	if m.resolveTitleSlugFn != nil {
+		return m.resolveTitleSlugFn(ctx, userID, slug)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) ListVideos(ctx context.Context, input ListVideosInput) (*ListVideosOutput, error) {
+	if m.listVideosFn != nil {
+		return m.listVideosFn(ctx, input)
+	}
+	return &ListVideosOutput{}, nil
+}
+
+func (m *mockVideoService) SearchVideos(ctx context.Context, input SearchVideosInput) (*ListVideosOutput, error) {
+	if m.searchVideosFn != nil {
+		return m.searchVideosFn(ctx, input)
+	}
+	return &ListVideosOutput{}, nil
+}
+
+func (m *mockVideoService) DeleteVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	if m.deleteVideoFn != nil {
+		return m.deleteVideoFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) SetExpiration(ctx context.Context, videoID uuid.UUID, expiresAt time.Time) (*model.Video, error) {
+	if m.setExpirationFn != nil {
+		return m.setExpirationFn(ctx, videoID, expiresAt)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) UpdateMetadata(ctx context.Context, videoID uuid.UUID, input UpdateMetadataInput) (*model.Video, error) {
+	if m.updateMetadataFn != nil {
+		return m.updateMetadataFn(ctx, videoID, input)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoService) GetOriginalURL(ctx context.Context, input OriginalURLInput) (*OriginalURL, error) {
+	if m.getOriginalURLFn != nil {
+		return m.getOriginalURLFn(ctx, input)
+	}
+	return nil, nil
+}
+
 // mockVideoCache is a mock implementation of VideoCache for testing.
 type mockVideoCache struct {
-	mu      sync.RWMutex
-	data    map[uuid.UUID]*model.Video
-	getFn   func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
-	setFn   func(ctx context.Context, video *model.Video, ttl time.Duration) error
-	deleteFn func(ctx context.Context, videoID uuid.UUID) error
+	mu         sync.RWMutex
+	data       map[uuid.UUID]*model.Video
+	getFn      func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	getStaleFn func(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+	getEntryFn func(ctx context.Context, videoID uuid.UUID) (*cache.VideoEntry, error)
+	setFn      func(ctx context.Context, video *model.Video, ttl, staleTTL, loadTime time.Duration) error
+	deleteFn   func(ctx context.Context, videoID uuid.UUID) error
+
+	pages         map[uuid.UUID]map[int]*cache.VideoPage
+	getFirstPages int
 }
 
 func newMockVideoCache() *mockVideoCache {
 	return &mockVideoCache{
-		data: make(map[uuid.UUID]*model.Video),
+		data:  make(map[uuid.UUID]*model.Video),
+		pages: make(map[uuid.UUID]map[int]*cache.VideoPage),
 	}
 }
 
@@ -66,9 +211,29 @@ func (m *mockVideoCache) Get(ctx context.Context, videoID uuid.UUID) (*model.Vid
 	return m.data[videoID], nil
 }
 
-func (m *mockVideoCache) Set(ctx context.Context, video *model.Video, ttl time.Duration) error {
+func (m *mockVideoCache) GetStale(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	if m.getStaleFn != nil {
+		return m.getStaleFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+// GetEntry returns the video from Get without expiry or load time, so it is never
+// refreshed early, unless getEntryFn is set.
+func (m *mockVideoCache) GetEntry(ctx context.Context, videoID uuid.UUID) (*cache.VideoEntry, error) {
+	if m.getEntryFn != nil {
+		return m.getEntryFn(ctx, videoID)
+	}
+	video, err := m.Get(ctx, videoID)
+	if video == nil {
+		return nil, err
+	}
+	return &cache.VideoEntry{Video: video}, nil
+}
+
+func (m *mockVideoCache) Set(ctx context.Context, video *model.Video, ttl, staleTTL, loadTime time.Duration) error {
 	if m.setFn != nil {
-		return m.setFn(ctx, video, ttl)
+		return m.setFn(ctx, video, ttl, staleTTL, loadTime)
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -86,6 +251,30 @@ func (m *mockVideoCache) Delete(ctx context.Context, videoID uuid.UUID) error {
 	return nil
 }
 
+func (m *mockVideoCache) GetFirstPage(ctx context.Context, userID uuid.UUID, limit int) (*cache.VideoPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.getFirstPages++
+	return m.pages[userID][limit], nil
+}
+
+func (m *mockVideoCache) SetFirstPage(ctx context.Context, userID uuid.UUID, limit int, page *cache.VideoPage, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pages[userID] == nil {
+		m.pages[userID] = make(map[int]*cache.VideoPage)
+	}
+	m.pages[userID][limit] = page
+	return nil
+}
+
+func (m *mockVideoCache) DeleteFirstPages(ctx context.Context, userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pages, userID)
+	return nil
+}
+
 func TestCachedVideoService_GetVideo_CacheHit(t *testing.T) {
 	videoID := uuid.New()
 	cachedVideo := &model.Video{
@@ -103,7 +292,7 @@ func TestCachedVideoService_GetVideo_CacheHit(t *testing.T) {
 	// Pre-populate cache
 	mockCache.data[videoID] = cachedVideo
 
-	svc := NewCachedVideoService(mockSvc, mockCache, DefaultCachedVideoServiceConfig())
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
 
 	got, err := svc.GetVideo(context.Background(), videoID)
 	if err != nil {
@@ -133,12 +322,16 @@ func TestCachedVideoService_GetVideo_CacheMiss(t *testing.T) {
 
 	mockSvc := &mockVideoService{
 		getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			// A replica copy must not be cached
+			if allowed, set := repository.StaleReads(ctx); !set || allowed {
+				t.Errorf("StaleReads = %v, %v; want the primary forced", allowed, set)
+			}
 			return dbVideo, nil
 		},
 	}
 	mockCache := newMockVideoCache()
 
-	svc := NewCachedVideoService(mockSvc, mockCache, DefaultCachedVideoServiceConfig())
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
 
 	got, err := svc.GetVideo(context.Background(), videoID)
 	if err != nil {
@@ -183,7 +376,7 @@ func TestCachedVideoService_GetVideo_CDNURLEnrichment(t *testing.T) {
 		CacheTTL:   5 * time.Minute,
 		CDNBaseURL: "http://cdn.example.com",
 	}
-	svc := NewCachedVideoService(mockSvc, mockCache, cfg)
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, cfg)
 
 	got, err := svc.GetVideo(context.Background(), videoID)
 	if err != nil {
@@ -196,6 +389,269 @@ func TestCachedVideoService_GetVideo_CDNURLEnrichment(t *testing.T) {
 	}
 }
 
+func TestCachedVideoService_GetVideo_PreviewCDNURL(t *testing.T) {
+	videoID := uuid.New()
+	readyVideo := &model.Video{
+		ID:             videoID,
+		UserID:         uuid.New(),
+		Title:          "Ready Video",
+		Status:         model.StatusReady,
+		HLSURL:         "hls/" + videoID.String() + "/master.m3u8",
+		PreviewSeconds: 60,
+		PreviewURL:     "previews/" + videoID.String() + "/playlist.m3u8",
+	}
+
+	mockSvc := &mockVideoService{
+		getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return readyVideo, nil
+		},
+	}
+
+	svc := NewCachedVideoService(mockSvc, newMockVideoCache(), nil, nil, nil, CachedVideoServiceConfig{
+		CacheTTL:   5 * time.Minute,
+		CDNBaseURL: "http://cdn.example.com",
+	})
+
+	got, err := svc.GetVideo(context.Background(), videoID)
+	if err != nil {
+		t.Fatalf("GetVideo failed: %v", err)
+	}
+
+	expectedURL := "http://cdn.example.com/previews/" + videoID.String() + "/playlist.m3u8"
+	if got.PreviewURL != expectedURL {
+		t.Errorf("PreviewURL = %v, want %v", got.PreviewURL, expectedURL)
+	}
+}
+
+func TestCachedVideoService_GetVideo_VersionedCDNURL(t *testing.T) {
+	videoID := uuid.New()
+	readyVideo := &model.Video{
+		ID:              videoID,
+		Status:          model.StatusReady,
+		HLSURL:          "hls/" + videoID.String() + "/v1700000000000/master.m3u8",
+		PreviewSeconds:  30,
+		PreviewURL:      "previews/" + videoID.String() + "/v1700000000000/playlist.m3u8",
+		ThumbnailPrefix: "hls/" + videoID.String() + "/v1700000000000/thumbnails/",
+		OutputVersion:   1700000000000,
+	}
+
+	mockSvc := &mockVideoService{
+		getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return readyVideo, nil
+		},
+	}
+	svc := NewCachedVideoService(mockSvc, newMockVideoCache(), nil, nil, nil, CachedVideoServiceConfig{
+		CacheTTL:   5 * time.Minute,
+		CDNBaseURL: "http://cdn.example.com",
+	})
+
+	got, err := svc.GetVideo(context.Background(), videoID)
+	if err != nil {
+		t.Fatalf("GetVideo failed: %v", err)
+	}
+
+	if want := "http://cdn.example.com/" + readyVideo.HLSURL; got.HLSURL != want {
+		t.Errorf("HLSURL = %v, want %v", got.HLSURL, want)
+	}
+	if want := "http://cdn.example.com/" + readyVideo.PreviewURL; got.PreviewURL != want {
+		t.Errorf("PreviewURL = %v, want %v", got.PreviewURL, want)
+	}
+	if want := "http://cdn.example.com/" + readyVideo.ThumbnailKey("small"); got.ThumbnailKey("small") != want {
+		t.Errorf("small thumbnail = %v, want %v", got.ThumbnailKey("small"), want)
+	}
+}
+
+func TestCachedVideoService_GetVideo_RegionalCDNURL(t *testing.T) {
+	videoID := uuid.New()
+	readyVideo := &model.Video{
+		ID:             videoID,
+		Status:         model.StatusReady,
+		HLSURL:         "hls/" + videoID.String() + "/master.m3u8",
+		PreviewSeconds: 30,
+		PreviewURL:     "previews/" + videoID.String() + "/playlist.m3u8",
+	}
+
+	testCases := []struct {
+		name         string
+		secondaryURL string
+		region       string
+		wantBaseURL  string
+	}{
+		{
+			name:         "mapped region uses secondary",
+			secondaryURL: "http://cdn-ap.example.com",
+			region:       "ap-northeast-1",
+			wantBaseURL:  "http://cdn-ap.example.com",
+		},
+		{
+			name:         "region match is case-insensitive",
+			secondaryURL: "http://cdn-ap.example.com",
+			region:       "AP-Northeast-1",
+			wantBaseURL:  "http://cdn-ap.example.com",
+		},
+		{
+			name:         "unmapped region uses primary",
+			secondaryURL: "http://cdn-ap.example.com",
+			region:       "us-east-1",
+			wantBaseURL:  "http://cdn.example.com",
+		},
+		{
+			name:         "unknown region uses primary",
+			secondaryURL: "http://cdn-ap.example.com",
+			wantBaseURL:  "http://cdn.example.com",
+		},
+		{
+			name:        "no secondary configured",
+			region:      "ap-northeast-1",
+			wantBaseURL: "http://cdn.example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSvc := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return readyVideo, nil
+				},
+			}
+			svc := NewCachedVideoService(mockSvc, newMockVideoCache(), nil, nil, nil, CachedVideoServiceConfig{
+				CacheTTL:            5 * time.Minute,
+				CDNBaseURL:          "http://cdn.example.com",
+				SecondaryCDNBaseURL: tc.secondaryURL,
+				SecondaryRegions:    []string{"ap-northeast-1", " ap-southeast-1"},
+			})
+
+			ctx := context.Background()
+			if tc.region != "" {
+				ctx = WithViewerRegion(ctx, tc.region)
+			}
+
+			got, err := svc.GetVideo(ctx, videoID)
+			if err != nil {
+				t.Fatalf("GetVideo failed: %v", err)
+			}
+
+			if want := tc.wantBaseURL + "/hls/" + videoID.String() + "/master.m3u8"; got.HLSURL != want {
+				t.Errorf("HLSURL = %v, want %v", got.HLSURL, want)
+			}
+			if want := tc.wantBaseURL + "/previews/" + videoID.String() + "/playlist.m3u8"; got.PreviewURL != want {
+				t.Errorf("PreviewURL = %v, want %v", got.PreviewURL, want)
+			}
+		})
+	}
+}
+
+func TestCachedVideoService_GetVideo_CustomDomain(t *testing.T) {
+	videoID := uuid.New()
+	tenantID := uuid.New()
+	readyVideo := &model.Video{
+		ID:        videoID,
+		UserID:    tenantID,
+		Title:     "Tenant Video",
+		Status:    model.StatusReady,
+		HLSURL:    "hls/" + videoID.String() + "/master.m3u8",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	testCases := []struct {
+		name        string
+		domainURL   string
+		region      string
+		wantBaseURL string
+	}{
+		{
+			name:        "custom domain wins over regional CDN",
+			domainURL:   "https://video.example.com",
+			region:      "ap-northeast-1",
+			wantBaseURL: "https://video.example.com",
+		},
+		{
+			name:        "no custom domain falls back to regional CDN",
+			region:      "ap-northeast-1",
+			wantBaseURL: "http://cdn-ap.example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSvc := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return readyVideo, nil
+				},
+			}
+			domains := &mockPlaybackDomainResolver{
+				playbackBaseURLFn: func(ctx context.Context, id uuid.UUID) string {
+					if id != tenantID {
+						t.Errorf("tenant ID = %v, want %v", id, tenantID)
+					}
+					return tc.domainURL
+				},
+			}
+			svc := NewCachedVideoService(mockSvc, newMockVideoCache(), domains, nil, nil, CachedVideoServiceConfig{
+				CacheTTL:            5 * time.Minute,
+				CDNBaseURL:          "http://cdn.example.com",
+				SecondaryCDNBaseURL: "http://cdn-ap.example.com",
+				SecondaryRegions:    []string{"ap-northeast-1"},
+			})
+
+			got, err := svc.GetVideo(WithViewerRegion(context.Background(), tc.region), videoID)
+			if err != nil {
+				t.Fatalf("GetVideo failed: %v", err)
+			}
+
+			if want := tc.wantBaseURL + "/hls/" + videoID.String() + "/master.m3u8"; got.HLSURL != want {
+				t.Errorf("HLSURL = %v, want %v", got.HLSURL, want)
+			}
+		})
+	}
+}
+
+func TestCachedVideoService_GetVideo_ExternalOutput(t *testing.T) {
+	videoID := uuid.New()
+	tenantID := uuid.New()
+	video := &model.Video{
+		ID:             videoID,
+		UserID:         tenantID,
+		Title:          "External Video",
+		Status:         model.StatusReady,
+		HLSURL:         "hls/" + videoID.String() + "/master.m3u8",
+		ExternalOutput: true,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	mockSvc := &mockVideoService{
+		getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return video, nil
+		},
+	}
+	domains := &mockPlaybackDomainResolver{
+		playbackBaseURLFn: func(ctx context.Context, id uuid.UUID) string {
+			return "https://video.example.com"
+		},
+	}
+	outputs := &mockOutputBucketResolver{
+		outputBaseURLFn: func(ctx context.Context, id uuid.UUID) string {
+			if id != tenantID {
+				t.Errorf("tenant ID = %v, want %v", id, tenantID)
+			}
+			return "https://cdn.acme.example"
+		},
+	}
+	svc := NewCachedVideoService(mockSvc, newMockVideoCache(), domains, nil, outputs, CachedVideoServiceConfig{
+		CacheTTL:   5 * time.Minute,
+		CDNBaseURL: "http://cdn.example.com",
+	})
+
+	got, err := svc.GetVideo(context.Background(), videoID)
+	if err != nil {
+		t.Fatalf("GetVideo failed: %v", err)
+	}
+	if want := "https://cdn.acme.example/hls/" + videoID.String() + "/master.m3u8"; got.HLSURL != want {
+		t.Errorf("HLSURL = %v, want %v", got.HLSURL, want)
+	}
+}
+
 func TestCachedVideoService_GetVideo_NoCDNURLForNonReady(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -230,7 +686,7 @@ func TestCachedVideoService_GetVideo_NoCDNURLForNonReady(t *testing.T) {
 				CacheTTL:   5 * time.Minute,
 				CDNBaseURL: "http://cdn.example.com",
 			}
-			svc := NewCachedVideoService(mockSvc, mockCache, cfg)
+			svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, cfg)
 
 			got, err := svc.GetVideo(context.Background(), videoID)
 			if err != nil {
@@ -257,16 +713,16 @@ func TestCachedVideoService_TriggerProcess_InvalidatesCache(t *testing.T) {
 	}
 
 	mockSvc := &mockVideoService{
-		triggerProcessFn: func(ctx context.Context, id uuid.UUID) error {
+		triggerProcessFn: func(ctx context.Context, id uuid.UUID, input ProcessInput) error {
 			return nil
 		},
 	}
 	mockCache := newMockVideoCache()
 	mockCache.data[videoID] = cachedVideo
 
-	svc := NewCachedVideoService(mockSvc, mockCache, DefaultCachedVideoServiceConfig())
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
 
-	err := svc.TriggerProcess(context.Background(), videoID)
+	err := svc.TriggerProcess(context.Background(), videoID, ProcessInput{})
 	if err != nil {
 		t.Fatalf("TriggerProcess failed: %v", err)
 	}
@@ -277,6 +733,244 @@ func TestCachedVideoService_TriggerProcess_InvalidatesCache(t *testing.T) {
 	}
 }
 
+func TestCachedVideoService_CompleteUpload_InvalidatesCache(t *testing.T) {
+	videoID := uuid.New()
+	cachedVideo := &model.Video{
+		ID:        videoID,
+		UserID:    uuid.New(),
+		Title:     "Cached Video",
+		Status:    model.StatusPendingUpload,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	mockSvc := &mockVideoService{
+		completeUploadFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			uploaded := *cachedVideo
+			uploaded.Status = model.StatusUploaded
+			return &uploaded, nil
+		},
+	}
+	mockCache := newMockVideoCache()
+	mockCache.data[videoID] = cachedVideo
+
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
+
+	video, err := svc.CompleteUpload(context.Background(), videoID)
+	if err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+	if video.Status != model.StatusUploaded {
+		t.Errorf("status: got %s, expected %s", video.Status, model.StatusUploaded)
+	}
+
+	// Verify cache was invalidated
+	if mockCache.data[videoID] != nil {
+		t.Error("cache was not invalidated after CompleteUpload")
+	}
+}
+
+func TestCachedVideoService_UpdateMetadata_InvalidatesCache(t *testing.T) {
+	videoID := uuid.New()
+	cachedVideo := &model.Video{
+		ID:        videoID,
+		UserID:    uuid.New(),
+		Title:     "Cached Video",
+		Status:    model.StatusReady,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	updated := *cachedVideo
+	updated.Title = "Renamed"
+
+	mockSvc := &mockVideoService{
+		updateMetadataFn: func(ctx context.Context, id uuid.UUID, input UpdateMetadataInput) (*model.Video, error) {
+			return &updated, nil
+		},
+	}
+	mockCache := newMockVideoCache()
+	mockCache.data[videoID] = cachedVideo
+
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
+
+	title := "Renamed"
+	got, err := svc.UpdateMetadata(context.Background(), videoID, UpdateMetadataInput{Title: &title})
+	if err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+	if got.Title != "Renamed" {
+		t.Errorf("Title = %q, want %q", got.Title, "Renamed")
+	}
+	if mockCache.data[videoID] != nil {
+		t.Error("cache was not invalidated after UpdateMetadata")
+	}
+}
+
+func TestCachedVideoService_ListVideos_CachesFirstPage(t *testing.T) {
+	userID := uuid.New()
+	video := &model.Video{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Title:     "Ready Video",
+		Status:    model.StatusReady,
+		HLSURL:    "hls/abc/master.m3u8",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	var calls []ListVideosInput
+	var primary []bool
+	mockSvc := &mockVideoService{
+		listVideosFn: func(ctx context.Context, input ListVideosInput) (*ListVideosOutput, error) {
+			calls = append(calls, input)
+			allowed, set := repository.StaleReads(ctx)
+			primary = append(primary, set && !allowed)
+			return &ListVideosOutput{Videos: []*model.Video{video}, NextCursor: "next"}, nil
+		},
+	}
+	mockCache := newMockVideoCache()
+
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, CachedVideoServiceConfig{
+		CacheTTL:   5 * time.Minute,
+		CDNBaseURL: "http://cdn.example.com",
+	})
+	ctx := context.Background()
+
+	for range 2 {
+		output, err := svc.ListVideos(ctx, ListVideosInput{UserID: userID, CallerID: userID, Limit: 10})
+		if err != nil {
+			t.Fatalf("ListVideos failed: %v", err)
+		}
+		if output.NextCursor != "next" {
+			t.Errorf("NextCursor = %q, want %q", output.NextCursor, "next")
+		}
+		if want := "http://cdn.example.com/hls/abc/master.m3u8"; output.Videos[0].HLSURL != want {
+			t.Errorf("HLSURL = %q, want %q", output.Videos[0].HLSURL, want)
+		}
+	}
+	if len(calls) != 1 {
+		t.Errorf("delegate called %d times, want 1", len(calls))
+	}
+	if !primary[0] {
+		t.Error("the page to cache was not read from the primary")
+	}
+
+	// The cached copy stays neutral
+	cached := mockCache.pages[userID][10]
+	if cached == nil || cached.Videos[0].HLSURL != "hls/abc/master.m3u8" {
+		t.Errorf("cached page = %+v, want neutral HLS key", cached)
+	}
+
+	// Later pages bypass the cache
+	if _, err := svc.ListVideos(ctx, ListVideosInput{UserID: userID, CallerID: userID, Limit: 10, Cursor: "next"}); err != nil {
+		t.Fatalf("ListVideos failed: %v", err)
+	}
+	if len(calls) != 2 || calls[1].Cursor != "next" {
+		t.Errorf("delegate calls = %+v, want cursor request delegated", calls)
+	}
+	if primary[1] {
+		t.Error("an uncached page should not force the primary")
+	}
+
+	// Other callers only see public videos, so they never get the owner's cached page
+	if _, err := svc.ListVideos(ctx, ListVideosInput{UserID: userID, CallerID: uuid.New(), Limit: 10}); err != nil {
+		t.Fatalf("ListVideos failed: %v", err)
+	}
+	if len(calls) != 3 {
+		t.Errorf("delegate called %d times, want the other caller's listing delegated", len(calls))
+	}
+	if mockCache.getFirstPages != 2 {
+		t.Errorf("GetFirstPage called %d times, want 2", mockCache.getFirstPages)
+	}
+}
+
+func TestCachedVideoService_InvalidatesFirstPages(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+	video := &model.Video{
+		ID:        videoID,
+		UserID:    userID,
+		Title:     "Video",
+		Status:    model.StatusUploaded,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(svc VideoService) error
+	}{
+		{
+			name: "create video",
+			mutate: func(svc VideoService) error {
+				_, err := svc.CreateVideo(context.Background(), CreateVideoInput{UserID: userID, Title: "New", FileName: "new.mp4"})
+				return err
+			},
+		},
+		{
+			name: "complete upload",
+			mutate: func(svc VideoService) error {
+				_, err := svc.CompleteUpload(context.Background(), videoID)
+				return err
+			},
+		},
+		{
+			name: "trigger process",
+			mutate: func(svc VideoService) error {
+				return svc.TriggerProcess(context.Background(), videoID, ProcessInput{})
+			},
+		},
+		{
+			name: "delete video",
+			mutate: func(svc VideoService) error {
+				_, err := svc.DeleteVideo(context.Background(), videoID)
+				return err
+			},
+		},
+		{
+			name: "update metadata",
+			mutate: func(svc VideoService) error {
+				title := "Renamed"
+				_, err := svc.UpdateMetadata(context.Background(), videoID, UpdateMetadataInput{Title: &title})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := &mockVideoService{
+				createVideoFn: func(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error) {
+					return &CreateVideoOutput{Video: video}, nil
+				},
+				completeUploadFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				deleteVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateMetadataFn: func(ctx context.Context, id uuid.UUID, input UpdateMetadataInput) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			mockCache := newMockVideoCache()
+			mockCache.pages[userID] = map[int]*cache.VideoPage{0: {}}
+
+			svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
+
+			if err := tt.mutate(svc); err != nil {
+				t.Fatalf("mutation failed: %v", err)
+			}
+			if _, ok := mockCache.pages[userID]; ok {
+				t.Error("first page was not invalidated")
+			}
+		})
+	}
+}
+
 func TestCachedVideoService_GetVideo_Singleflight(t *testing.T) {
 	videoID := uuid.New()
 	video := &model.Video{
@@ -297,7 +991,7 @@ func TestCachedVideoService_GetVideo_Singleflight(t *testing.T) {
 	}
 	mockCache := newMockVideoCache()
 
-	svc := NewCachedVideoService(mockSvc, mockCache, DefaultCachedVideoServiceConfig())
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
 
 	// Launch multiple concurrent requests
 	var wg sync.WaitGroup
@@ -341,12 +1035,12 @@ func TestCachedVideoService_GetVideo_CacheErrorFallsBackToDB(t *testing.T) {
 		getFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
 			return nil, errors.New("redis connection error")
 		},
-		setFn: func(ctx context.Context, video *model.Video, ttl time.Duration) error {
+		setFn: func(ctx context.Context, video *model.Video, ttl, staleTTL, loadTime time.Duration) error {
 			return errors.New("redis connection error")
 		},
 	}
 
-	svc := NewCachedVideoService(mockSvc, mockCache, DefaultCachedVideoServiceConfig())
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
 
 	got, err := svc.GetVideo(context.Background(), videoID)
 	if err != nil {
@@ -358,6 +1052,128 @@ func TestCachedVideoService_GetVideo_CacheErrorFallsBackToDB(t *testing.T) {
 	}
 }
 
+func TestCachedVideoService_GetVideo_ServeStaleOnError(t *testing.T) {
+	outage := errs.Wrap(errs.Transient, errors.New("connection refused"))
+
+	tests := []struct {
+		name      string
+		staleTTL  time.Duration
+		lookupErr error
+		retained  bool
+		wantStale bool
+		wantErr   error
+	}{
+		{name: "transient error serves the retained copy", staleTTL: time.Hour, lookupErr: outage, retained: true, wantStale: true},
+		{name: "nothing retained", staleTTL: time.Hour, lookupErr: outage, wantErr: errs.Transient},
+		{name: "stale reads disabled", lookupErr: outage, retained: true, wantErr: errs.Transient},
+		{name: "not found is not masked", staleTTL: time.Hour, lookupErr: repository.ErrVideoNotFound, retained: true, wantErr: repository.ErrVideoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			mockSvc := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return nil, tt.lookupErr
+				},
+			}
+			mockCache := newMockVideoCache()
+			mockCache.getStaleFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+				if !tt.retained {
+					return nil, nil
+				}
+				return &model.Video{ID: id, Status: model.StatusReady, HLSURL: "hls/" + id.String() + "/master.m3u8"}, nil
+			}
+
+			cfg := DefaultCachedVideoServiceConfig()
+			cfg.StaleTTL = tt.staleTTL
+			svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, cfg)
+
+			got, err := svc.GetVideo(context.Background(), videoID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetVideo() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetVideo() error = %v", err)
+			}
+			if got.Stale != tt.wantStale {
+				t.Errorf("Stale = %v, want %v", got.Stale, tt.wantStale)
+			}
+			if got.HLSURL != "http://localhost:8081/hls/"+videoID.String()+"/master.m3u8" {
+				t.Errorf("HLSURL = %q, want the CDN URL", got.HLSURL)
+			}
+		})
+	}
+}
+
+func TestCachedVideoService_GetVideo_EarlyRefresh(t *testing.T) {
+	tests := []struct {
+		name        string
+		beta        float64
+		freshFor    time.Duration
+		loadTime    time.Duration
+		lookupErr   error
+		wantLookups int
+		wantTitle   string
+	}{
+		{name: "far from expiry is served from cache", beta: 1, freshFor: time.Hour, loadTime: 10 * time.Millisecond, wantTitle: "cached"},
+		{name: "near expiry is refreshed", beta: 1, freshFor: time.Millisecond, loadTime: 10 * time.Millisecond, wantLookups: 1, wantTitle: "current"},
+		{name: "larger beta refreshes earlier", beta: 1000, freshFor: time.Second, loadTime: 10 * time.Millisecond, wantLookups: 1, wantTitle: "current"},
+		{name: "disabled", freshFor: time.Millisecond, loadTime: 10 * time.Millisecond, wantTitle: "cached"},
+		{name: "unknown load time", beta: 1, freshFor: time.Millisecond, wantTitle: "cached"},
+		{name: "failed refresh serves the cached video", beta: 1, freshFor: time.Millisecond, loadTime: 10 * time.Millisecond, lookupErr: errs.Wrap(errs.Transient, errors.New("connection refused")), wantLookups: 1, wantTitle: "cached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			lookups := 0
+			mockSvc := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					lookups++
+					if tt.lookupErr != nil {
+						return nil, tt.lookupErr
+					}
+					return &model.Video{ID: id, Title: "current", Status: model.StatusProcessing}, nil
+				},
+			}
+			mockCache := newMockVideoCache()
+			mockCache.getEntryFn = func(ctx context.Context, id uuid.UUID) (*cache.VideoEntry, error) {
+				return &cache.VideoEntry{
+					Video:      &model.Video{ID: id, Title: "cached", Status: model.StatusProcessing},
+					FreshUntil: time.Now().Add(tt.freshFor),
+					LoadTime:   tt.loadTime,
+				}, nil
+			}
+
+			cfg := DefaultCachedVideoServiceConfig()
+			cfg.EarlyRefreshBeta = tt.beta
+			svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, cfg)
+			// -log(0.5) is about 0.69, so a refresh is due within 0.69 * beta * load time
+			svc.(*cachedVideoService).random = func() float64 { return 0.5 }
+
+			got, err := svc.GetVideo(context.Background(), videoID)
+			if err != nil {
+				t.Fatalf("GetVideo() error = %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if lookups != tt.wantLookups {
+				t.Errorf("delegate lookups = %d, want %d", lookups, tt.wantLookups)
+			}
+
+			_, cached := mockCache.data[videoID]
+			if wantCached := tt.wantTitle == "current"; cached != wantCached {
+				t.Errorf("refreshed video cached = %v, want %v", cached, wantCached)
+			}
+		})
+	}
+}
+
 func TestCachedVideoService_CreateVideo_Delegates(t *testing.T) {
 	videoID := uuid.New()
 	userID := uuid.New()
@@ -380,7 +1196,7 @@ func TestCachedVideoService_CreateVideo_Delegates(t *testing.T) {
 	}
 	mockCache := newMockVideoCache()
 
-	svc := NewCachedVideoService(mockSvc, mockCache, DefaultCachedVideoServiceConfig())
+	svc := NewCachedVideoService(mockSvc, mockCache, nil, nil, nil, DefaultCachedVideoServiceConfig())
 
 	got, err := svc.CreateVideo(context.Background(), CreateVideoInput{
 		UserID:   userID,
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// CleanupServiceConfig holds configuration for CleanupService.
+type CleanupServiceConfig struct {
+	// MaxRetries is the maximum number of retry attempts before the task is dead-lettered.
+	MaxRetries int
+}
+
+// DefaultCleanupServiceConfig returns the default configuration.
+func DefaultCleanupServiceConfig() CleanupServiceConfig {
+	return CleanupServiceConfig{
+		MaxRetries: DefaultMaxRetries,
+	}
+}
+
+// CleanupService removes the stored objects of deleted videos and superseded output versions.
+type CleanupService interface {
+	// ProcessDeleteTask handles a delete task from the message queue.
+	// Returns nil on success, an error wrapping repository.ErrPermanentTaskFailure once
+	// max retries are exceeded, and any other error for failures that should be retried.
+	ProcessDeleteTask(ctx context.Context, task repository.DeleteTask) error
+}
+
+type cleanupService struct {
+	storage repository.ObjectStorage
+	replica repository.ObjectStorage
+	cache   cache.VideoCache
+	purger  repository.CDNPurger
+	outputs OutputBucketResolver
+
+	maxRetries int
+}
+
+// NewCleanupService creates a new CleanupService instance.
+// The replica parameter is optional - pass nil when output is not replicated.
+// The cache parameter is optional - pass nil to disable cache invalidation.
+// The purger parameter is optional - pass nil to leave deleted output to expire from the CDN.
+// The outputs parameter is optional - pass nil to leave output written to tenants' output
+// buckets in place.
+func NewCleanupService(
+	storage repository.ObjectStorage,
+	replica repository.ObjectStorage,
+	videoCache cache.VideoCache,
+	purger repository.CDNPurger,
+	outputs OutputBucketResolver,
+	cfg CleanupServiceConfig,
+) CleanupService {
+	return &cleanupService{
+		storage:    storage,
+		replica:    replica,
+		cache:      videoCache,
+		purger:     purger,
+		outputs:    outputs,
+		maxRetries: cfg.MaxRetries,
+	}
+}
+
+// ProcessDeleteTask removes the original upload and every object under the output prefixes.
+// Deleting is idempotent, so a retried task simply finishes what an earlier attempt began.
+// The replica only ever holds output, so the original is removed from primary storage alone.
+// Platform storage is always cleaned, since earlier output versions may have been written
+// there before the tenant configured its output bucket.
+func (s *cleanupService) ProcessDeleteTask(ctx context.Context, task repository.DeleteTask) error {
+	if task.RetryCount >= s.maxRetries {
+		return fmt.Errorf("%w: retry count %d reached the limit of %d", repository.ErrPermanentTaskFailure, task.RetryCount, s.maxRetries)
+	}
+
+	deleted := 0
+	if task.OriginalKey != "" {
+		if err := s.storage.Delete(ctx, task.OriginalKey); err != nil {
+			return fmt.Errorf("delete original: %w", err)
+		}
+		deleted++
+	}
+
+	n, err := deletePrefixes(ctx, s.storage, task.OutputPrefixes)
+	if err != nil {
+		return err
+	}
+	deleted += n
+
+	if s.replica != nil {
+		if _, err := deletePrefixes(ctx, s.replica, task.OutputPrefixes); err != nil {
+			return fmt.Errorf("replica: %w", err)
+		}
+	}
+
+	if task.ExternalOutput {
+		n, err := s.deleteExternalOutput(ctx, task)
+		if err != nil {
+			return fmt.Errorf("output bucket: %w", err)
+		}
+		deleted += n
+	}
+
+	s.purgeCDN(ctx, task)
+	s.invalidateCache(ctx, task)
+
+	slog.InfoContext(ctx, "deleted video objects",
+		"video_id", task.VideoID,
+		"objects", deleted,
+	)
+	return nil
+}
+
+// deleteExternalOutput deletes the output prefixes from the owner's output bucket. Output
+// in a bucket the tenant has since removed is left to the tenant.
+func (s *cleanupService) deleteExternalOutput(ctx context.Context, task repository.DeleteTask) (int, error) {
+	if s.outputs == nil {
+		return 0, nil
+	}
+
+	output, err := s.outputs.OutputStorage(ctx, task.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if output == nil {
+		slog.WarnContext(ctx, "skipping output in a removed output bucket",
+			"video_id", task.VideoID,
+			"user_id", task.UserID,
+		)
+		return 0, nil
+	}
+	return deletePrefixes(ctx, output, task.OutputPrefixes)
+}
+
+// deletePrefixes deletes every object under the given prefixes and returns how many were removed.
+func deletePrefixes(ctx context.Context, storage repository.ObjectStorage, prefixes []string) (int, error) {
+	deleted := 0
+	for _, prefix := range prefixes {
+		n, err := storage.DeleteByPrefix(ctx, prefix)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("delete %s: %w", prefix, err)
+		}
+	}
+	return deleted, nil
+}
+
+// purgeCDN evicts the deleted output from the CDN.
+// Errors are logged but not propagated - the objects still expire with their cache TTL.
+func (s *cleanupService) purgeCDN(ctx context.Context, task repository.DeleteTask) {
+	if s.purger == nil || len(task.OutputPrefixes) == 0 {
+		return
+	}
+
+	if err := s.purger.Purge(ctx, task.OutputPrefixes); err != nil {
+		slog.WarnContext(ctx, "failed to purge CDN",
+			"video_id", task.VideoID,
+			"prefixes", task.OutputPrefixes,
+			"error", err,
+		)
+	}
+}
+
+// invalidateCache evicts the video and its owner's first pages once more, in case a
+// lookup repopulated them between the delete request and the cleanup.
+func (s *cleanupService) invalidateCache(ctx context.Context, task repository.DeleteTask) {
+	if s.cache == nil {
+		return
+	}
+
+	if err := s.cache.Delete(ctx, task.VideoID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate video cache",
+			"video_id", task.VideoID,
+			"error", err,
+		)
+	}
+	if err := s.cache.DeleteFirstPages(ctx, task.UserID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate video list cache",
+			"video_id", task.VideoID,
+			"user_id", task.UserID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// newPrefixStorage returns a mock storage holding keys, which records deletions.
+func newPrefixStorage(keys []string) (*mockObjectStorage, *[]string) {
+	var mu sync.Mutex
+	var deleted []string
+	storage := &mockObjectStorage{
+		deleteByPrefixFn: func(ctx context.Context, prefix string) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			n := 0
+			for _, key := range keys {
+				if strings.HasPrefix(key, prefix) {
+					deleted = append(deleted, key)
+					n++
+				}
+			}
+			return n, nil
+		},
+		deleteFn: func(ctx context.Context, key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			deleted = append(deleted, key)
+			return nil
+		},
+	}
+	return storage, &deleted
+}
+
+func TestCleanupService_ProcessDeleteTask(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+	task := repository.DeleteTask{
+		VideoID:        videoID,
+		UserID:         userID,
+		OriginalKey:    "originals/" + videoID.String() + "/video.mp4",
+		OutputPrefixes: []string{"hls/" + videoID.String() + "/", "previews/" + videoID.String() + "/"},
+	}
+	outputs := []string{
+		"hls/" + videoID.String() + "/v1/master.m3u8",
+		"hls/" + videoID.String() + "/v1/720p/segment_000.ts",
+		"previews/" + videoID.String() + "/master.m3u8",
+	}
+	keys := append([]string{task.OriginalKey, "hls/" + uuid.NewString() + "/v1/master.m3u8"}, outputs...)
+
+	t.Run("deletes original and output", func(t *testing.T) {
+		storage, deleted := newPrefixStorage(keys)
+		replica, replicaDeleted := newPrefixStorage(outputs)
+		var purged []string
+		purger := &mockCDNPurger{
+			purgeFn: func(ctx context.Context, prefixes []string) error {
+				purged = prefixes
+				return nil
+			},
+		}
+		videoCache := newMockVideoCache()
+		videoCache.data[videoID] = &model.Video{ID: videoID}
+		videoCache.pages[userID] = map[int]*cache.VideoPage{0: {}}
+
+		svc := NewCleanupService(storage, replica, videoCache, purger, nil, DefaultCleanupServiceConfig())
+		if err := svc.ProcessDeleteTask(context.Background(), task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := append([]string{task.OriginalKey}, outputs...)
+		if !slices.Equal(*deleted, want) {
+			t.Errorf("deleted = %v, want %v", *deleted, want)
+		}
+		if !slices.Equal(*replicaDeleted, outputs) {
+			t.Errorf("replica deleted = %v, want %v", *replicaDeleted, outputs)
+		}
+		if !slices.Equal(purged, task.OutputPrefixes) {
+			t.Errorf("purged = %v, want %v", purged, task.OutputPrefixes)
+		}
+		if _, ok := videoCache.data[videoID]; ok {
+			t.Error("video was not evicted from cache")
+		}
+		if _, ok := videoCache.pages[userID]; ok {
+			t.Error("first pages were not evicted from cache")
+		}
+	})
+
+	t.Run("deletes output from the tenant's bucket", func(t *testing.T) {
+		storage, deleted := newPrefixStorage(keys)
+		bucket, bucketDeleted := newPrefixStorage(outputs)
+		var tenant uuid.UUID
+		resolver := &mockOutputBucketResolver{
+			outputStorageFn: func(ctx context.Context, tenantID uuid.UUID) (repository.ObjectStorage, error) {
+				tenant = tenantID
+				return bucket, nil
+			},
+		}
+		external := task
+		external.ExternalOutput = true
+
+		svc := NewCleanupService(storage, nil, nil, nil, resolver, DefaultCleanupServiceConfig())
+		if err := svc.ProcessDeleteTask(context.Background(), external); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tenant != userID {
+			t.Errorf("resolved bucket of %s, want owner %s", tenant, userID)
+		}
+		if !slices.Equal(*bucketDeleted, outputs) {
+			t.Errorf("bucket deleted = %v, want %v", *bucketDeleted, outputs)
+		}
+		// Earlier versions may still be in platform storage
+		if want := append([]string{task.OriginalKey}, outputs...); !slices.Equal(*deleted, want) {
+			t.Errorf("deleted = %v, want %v", *deleted, want)
+		}
+	})
+
+	t.Run("removed output bucket is skipped", func(t *testing.T) {
+		storage, _ := newPrefixStorage(keys)
+		external := task
+		external.ExternalOutput = true
+
+		svc := NewCleanupService(storage, nil, nil, nil, &mockOutputBucketResolver{}, DefaultCleanupServiceConfig())
+		if err := svc.ProcessDeleteTask(context.Background(), external); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("nothing left to delete", func(t *testing.T) {
+		storage, deleted := newPrefixStorage(nil)
+		svc := NewCleanupService(storage, nil, nil, nil, nil, DefaultCleanupServiceConfig())
+		if err := svc.ProcessDeleteTask(context.Background(), task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(*deleted, []string{task.OriginalKey}) {
+			t.Errorf("deleted = %v, want only the original", *deleted)
+		}
+	})
+
+	t.Run("storage failure is retried", func(t *testing.T) {
+		storageErr := errors.New("storage unavailable")
+		storage, _ := newPrefixStorage(keys)
+		storage.deleteByPrefixFn = func(ctx context.Context, prefix string) (int, error) {
+			return 0, storageErr
+		}
+		svc := NewCleanupService(storage, nil, nil, nil, nil, DefaultCleanupServiceConfig())
+		err := svc.ProcessDeleteTask(context.Background(), task)
+		if !errors.Is(err, storageErr) || errors.Is(err, repository.ErrPermanentTaskFailure) {
+			t.Errorf("expected retryable storage error, got %v", err)
+		}
+	})
+
+	t.Run("purge failure is not fatal", func(t *testing.T) {
+		storage, _ := newPrefixStorage(keys)
+		purger := &mockCDNPurger{
+			purgeFn: func(ctx context.Context, prefixes []string) error {
+				return errors.New("cdn unavailable")
+			},
+		}
+		svc := NewCleanupService(storage, nil, nil, purger, nil, DefaultCleanupServiceConfig())
+		if err := svc.ProcessDeleteTask(context.Background(), task); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("max retries exceeded", func(t *testing.T) {
+		storage, deleted := newPrefixStorage(keys)
+		svc := NewCleanupService(storage, nil, nil, nil, nil, CleanupServiceConfig{MaxRetries: 2})
+		exhausted := task
+		exhausted.RetryCount = 2
+		err := svc.ProcessDeleteTask(context.Background(), exhausted)
+		if !errors.Is(err, repository.ErrPermanentTaskFailure) {
+			t.Errorf("expected ErrPermanentTaskFailure, got %v", err)
+		}
+		if len(*deleted) != 0 {
+			t.Errorf("deleted = %v, want nothing", *deleted)
+		}
+	})
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var (
+	// ErrDomainVerificationFailed is returned when the expected TXT record is not published.
+	ErrDomainVerificationFailed = errors.New("domain verification record not found")
+)
+
+// RegisterDomainInput contains the input parameters for registering a custom domain.
+type RegisterDomainInput struct {
+	TenantID uuid.UUID
+	Hostname string
+	// CertificateID is optional at registration; certificates are often issued after verification.
+	CertificateID string
+}
+
+// PlaybackDomainResolver picks the base URL for a tenant's playback URLs.
+type PlaybackDomainResolver interface {
+	// PlaybackBaseURL returns the tenant's custom playback base URL (e.g., https://video.example.com),
+	// or an empty string if the tenant has no servable custom domain.
+	PlaybackBaseURL(ctx context.Context, tenantID uuid.UUID) string
+}
+
+// CustomDomainService defines the interface for managing tenant custom domains.
+type CustomDomainService interface {
+	PlaybackDomainResolver
+
+	// RegisterDomain claims a hostname for a tenant, pending DNS ownership verification.
+	RegisterDomain(ctx context.Context, input RegisterDomainInput) (*model.CustomDomain, error)
+
+	// ListDomains returns every domain registered by a tenant.
+	ListDomains(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error)
+
+	// VerifyDomain checks the domain's TXT verification record and marks it verified.
+	// Returns ErrDomainVerificationFailed if the record is missing or does not match, and
+	// repository.ErrDuplicateCustomDomain if another tenant already verified the hostname.
+	VerifyDomain(ctx context.Context, tenantID, domainID uuid.UUID) (*model.CustomDomain, error)
+
+	// SetCertificate attaches the TLS certificate provisioned for the domain at the CDN.
+	SetCertificate(ctx context.Context, tenantID, domainID uuid.UUID, certificateID string) (*model.CustomDomain, error)
+
+	// DeleteDomain removes a domain; playback falls back to the default CDN.
+	DeleteDomain(ctx context.Context, tenantID, domainID uuid.UUID) error
+}
+
+// CustomDomainServiceConfig holds configuration for CustomDomainService.
+type CustomDomainServiceConfig struct {
+	// ResolveCacheTTL is how long a tenant's playback domain is reused before it is looked up again.
+	// Domain changes reach playback URLs within this interval.
+	ResolveCacheTTL time.Duration
+	// DNSTimeout bounds each TXT lookup during verification.
+	DNSTimeout time.Duration
+}
+
+// DefaultCustomDomainServiceConfig returns the default configuration.
+func DefaultCustomDomainServiceConfig() CustomDomainServiceConfig {
+	return CustomDomainServiceConfig{
+		ResolveCacheTTL: time.Minute,
+		DNSTimeout:      5 * time.Second,
+	}
+}
+
+// resolvedDomain is a cached playback base URL; an empty baseURL caches the absence of one.
+type resolvedDomain struct {
+	baseURL   string
+	expiresAt time.Time
+}
+
+type customDomainService struct {
+	repo     repository.CustomDomainRepository
+	resolver repository.TXTResolver
+
+	resolveCacheTTL time.Duration
+	dnsTimeout      time.Duration
+
+	mu       sync.Mutex
+	resolved map[uuid.UUID]resolvedDomain
+}
+
+// NewCustomDomainService creates a new CustomDomainService instance.
+func NewCustomDomainService(
+	repo repository.CustomDomainRepository,
+	resolver repository.TXTResolver,
+	cfg CustomDomainServiceConfig,
+) CustomDomainService {
+	return &customDomainService{
+		repo:            repo,
+		resolver:        resolver,
+		resolveCacheTTL: cfg.ResolveCacheTTL,
+		dnsTimeout:      cfg.DNSTimeout,
+		resolved:        make(map[uuid.UUID]resolvedDomain),
+	}
+}
+
+// RegisterDomain validates and persists a new unverified domain.
+func (s *customDomainService) RegisterDomain(ctx context.Context, input RegisterDomainInput) (*model.CustomDomain, error) {
+	domain, err := model.NewCustomDomain(input.TenantID, input.Hostname, input.CertificateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, domain); err != nil {
+		if errors.Is(err, repository.ErrDuplicateCustomDomain) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create custom domain: %w", err)
+	}
+
+	return domain, nil
+}
+
+// ListDomains returns every domain registered by a tenant.
+func (s *customDomainService) ListDomains(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error) {
+	return s.repo.ListByTenant(ctx, tenantID)
+}
+
+// VerifyDomain looks up the TXT record at _gostream-verification.{hostname}.
+// Verification is idempotent: an already verified domain is returned unchanged.
+func (s *customDomainService) VerifyDomain(ctx context.Context, tenantID, domainID uuid.UUID) (*model.CustomDomain, error) {
+	domain, err := s.getOwned(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.IsVerified() {
+		return domain, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, s.dnsTimeout)
+	defer cancel()
+
+	records, err := s.resolver.LookupTXT(lookupCtx, domain.VerificationRecordName())
+	if err != nil {
+		// NXDOMAIN and timeouts look the same to the tenant: the record is not visible yet
+		slog.InfoContext(ctx, "custom domain verification lookup failed",
+			slog.String("hostname", domain.Hostname),
+			slog.String("error", err.Error()),
+		)
+		return nil, ErrDomainVerificationFailed
+	}
+
+	if !containsRecord(records, domain.VerificationToken) {
+		return nil, ErrDomainVerificationFailed
+	}
+
+	domain.MarkVerified(time.Now())
+	if err := s.update(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+// SetCertificate attaches the TLS certificate provisioned for the domain.
+func (s *customDomainService) SetCertificate(ctx context.Context, tenantID, domainID uuid.UUID, certificateID string) (*model.CustomDomain, error) {
+	domain, err := s.getOwned(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	domain.SetCertificateID(certificateID)
+	if err := s.update(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+// DeleteDomain removes a domain owned by the tenant.
+func (s *customDomainService) DeleteDomain(ctx context.Context, tenantID, domainID uuid.UUID) error {
+	if _, err := s.getOwned(ctx, tenantID, domainID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, domainID); err != nil {
+		return err
+	}
+
+	s.forget(tenantID)
+	return nil
+}
+
+// PlaybackBaseURL returns https://{hostname} for the tenant's servable domain.
+// Lookup failures fall back to the default CDN rather than failing playback.
+func (s *customDomainService) PlaybackBaseURL(ctx context.Context, tenantID uuid.UUID) string {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.resolved[tenantID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.baseURL
+	}
+
+	var baseURL string
+	domain, err := s.repo.GetPlaybackDomain(ctx, tenantID)
+	switch {
+	case err == nil:
+		baseURL = "https://" + domain.Hostname
+	case errors.Is(err, repository.ErrCustomDomainNotFound):
+	default:
+		slog.WarnContext(ctx, "failed to resolve tenant playback domain",
+			slog.String("tenant_id", tenantID.String()),
+			slog.String("error", err.Error()),
+		)
+		return ""
+	}
+
+	s.mu.Lock()
+	s.resolved[tenantID] = resolvedDomain{baseURL: baseURL, expiresAt: now.Add(s.resolveCacheTTL)}
+	s.mu.Unlock()
+
+	return baseURL
+}
+
+// getOwned retrieves a domain, hiding domains of other tenants behind ErrCustomDomainNotFound.
+func (s *customDomainService) getOwned(ctx context.Context, tenantID, domainID uuid.UUID) (*model.CustomDomain, error) {
+	domain, err := s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.TenantID != tenantID {
+		return nil, repository.ErrCustomDomainNotFound
+	}
+	return domain, nil
+}
+
+// update persists domain and drops the tenant's cached playback domain on this instance.
+func (s *customDomainService) update(ctx context.Context, domain *model.CustomDomain) error {
+	if err := s.repo.Update(ctx, domain); err != nil {
+		if errors.Is(err, repository.ErrDuplicateCustomDomain) || errors.Is(err, repository.ErrCustomDomainNotFound) {
+			return err
+		}
+		return fmt.Errorf("update custom domain: %w", err)
+	}
+
+	s.forget(domain.TenantID)
+	return nil
+}
+
+func (s *customDomainService) forget(tenantID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.resolved, tenantID)
+	s.mu.Unlock()
+}
+
+// containsRecord reports whether any TXT record equals token, ignoring surrounding whitespace.
+func containsRecord(records []string, token string) bool {
+	for _, record := range records {
+		if strings.TrimSpace(record) == token {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestCustomDomainService_RegisterDomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     RegisterDomainInput
+		createErr error
+		wantErr   error
+	}{
+		{
+			name:  "registers pending domain",
+			input: RegisterDomainInput{TenantID: uuid.New(), Hostname: "Video.Example.com"},
+		},
+		{
+			name:    "invalid hostname",
+			input:   RegisterDomainInput{TenantID: uuid.New(), Hostname: "not a host"},
+			wantErr: model.ErrInvalidHostname,
+		},
+		{
+			name:      "already registered",
+			input:     RegisterDomainInput{TenantID: uuid.New(), Hostname: "video.example.com"},
+			createErr: repository.ErrDuplicateCustomDomain,
+			wantErr:   repository.ErrDuplicateCustomDomain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockCustomDomainRepository{
+				createFn: func(ctx context.Context, domain *model.CustomDomain) error {
+					return tt.createErr
+				},
+			}
+
+			svc := NewCustomDomainService(repo, &mockTXTResolver{}, DefaultCustomDomainServiceConfig())
+			domain, err := svc.RegisterDomain(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if domain.Hostname != "video.example.com" {
+				t.Errorf("hostname: got %q, expected %q", domain.Hostname, "video.example.com")
+			}
+			if domain.IsVerified() {
+				t.Error("new domain must not be verified")
+			}
+		})
+	}
+}
+
+func TestCustomDomainService_VerifyDomain(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name      string
+		tenantID  uuid.UUID
+		records   []string
+		lookupErr error
+		updateErr error
+		wantErr   error
+	}{
+		{
+			name:     "matching TXT record",
+			tenantID: tenantID,
+			records:  []string{"unrelated", "{token}"},
+		},
+		{
+			name:     "record missing",
+			tenantID: tenantID,
+			records:  []string{"v=spf1 -all"},
+			wantErr:  ErrDomainVerificationFailed,
+		},
+		{
+			name:      "lookup fails",
+			tenantID:  tenantID,
+			lookupErr: errors.New("no such host"),
+			wantErr:   ErrDomainVerificationFailed,
+		},
+		{
+			name:      "hostname verified by another tenant",
+			tenantID:  tenantID,
+			records:   []string{"{token}"},
+			updateErr: repository.ErrDuplicateCustomDomain,
+			wantErr:   repository.ErrDuplicateCustomDomain,
+		},
+		{
+			name:     "domain owned by another tenant",
+			tenantID: uuid.New(),
+			wantErr:  repository.ErrCustomDomainNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain, err := model.NewCustomDomain(tenantID, "video.example.com", "")
+			if err != nil {
+				t.Fatalf("failed to create domain: %v", err)
+			}
+
+			var updated bool
+			repo := &mockCustomDomainRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.CustomDomain, error) {
+					return domain, nil
+				},
+				updateFn: func(ctx context.Context, d *model.CustomDomain) error {
+					updated = true
+					return tt.updateErr
+				},
+			}
+			resolver := &mockTXTResolver{
+				lookupTXTFn: func(ctx context.Context, name string) ([]string, error) {
+					if name != "_gostream-verification.video.example.com" {
+						t.Errorf("lookup name: got %q", name)
+					}
+					records := make([]string, len(tt.records))
+					for i, r := range tt.records {
+						if r == "{token}" {
+							r = domain.VerificationToken
+						}
+						records[i] = r
+					}
+					return records, tt.lookupErr
+				},
+			}
+
+			svc := NewCustomDomainService(repo, resolver, DefaultCustomDomainServiceConfig())
+			got, err := svc.VerifyDomain(context.Background(), tt.tenantID, domain.ID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !got.IsVerified() || got.VerifiedAt == nil {
+				t.Error("expected domain to be verified")
+			}
+			if !updated {
+				t.Error("expected verification to be persisted")
+			}
+		})
+	}
+}
+
+func TestCustomDomainService_PlaybackBaseURL(t *testing.T) {
+	tenantID := uuid.New()
+	domain, err := model.NewCustomDomain(tenantID, "video.example.com", "arn:aws:acm:us-east-1:123456789012:certificate/abc")
+	if err != nil {
+		t.Fatalf("failed to create domain: %v", err)
+	}
+
+	var lookups int
+	var lookupErr error
+	repo := &mockCustomDomainRepository{
+		getPlaybackDomainFn: func(ctx context.Context, id uuid.UUID) (*model.CustomDomain, error) {
+			lookups++
+			if lookupErr != nil {
+				return nil, lookupErr
+			}
+			return domain, nil
+		},
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.CustomDomain, error) {
+			return domain, nil
+		},
+	}
+
+	svc := NewCustomDomainService(repo, &mockTXTResolver{}, DefaultCustomDomainServiceConfig())
+	ctx := context.Background()
+
+	if got := svc.PlaybackBaseURL(ctx, tenantID); got != "https://video.example.com" {
+		t.Errorf("PlaybackBaseURL() = %q, want %q", got, "https://video.example.com")
+	}
+	if got := svc.PlaybackBaseURL(ctx, tenantID); got != "https://video.example.com" {
+		t.Errorf("cached PlaybackBaseURL() = %q, want %q", got, "https://video.example.com")
+	}
+	if lookups != 1 {
+		t.Errorf("lookups: got %d, expected 1", lookups)
+	}
+
+	// Deleting the domain drops the cached entry; the next lookup falls back to the default CDN
+	if err := svc.DeleteDomain(ctx, tenantID, domain.ID); err != nil {
+		t.Fatalf("DeleteDomain() unexpected error: %v", err)
+	}
+	lookupErr = repository.ErrCustomDomainNotFound
+	if got := svc.PlaybackBaseURL(ctx, tenantID); got != "" {
+		t.Errorf("PlaybackBaseURL() after delete = %q, want empty", got)
+	}
+	if lookups != 2 {
+		t.Errorf("lookups: got %d, expected 2", lookups)
+	}
+}
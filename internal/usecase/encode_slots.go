@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// DefaultEncodeSlotTimeout is how long the encode slot of a worker that stopped
+// heartbeating keeps counting against its tenant.
+const DefaultEncodeSlotTimeout = 2 * time.Minute
+
+// encodeLimit returns the number of encodes tenantID may run at once; zero is unlimited.
+func (s *transcodeService) encodeLimit(tenantID uuid.UUID) int {
+	if limit, ok := s.tenantEncodeLimits[tenantID.String()]; ok {
+		return limit
+	}
+	return s.maxTenantEncodes
+}
+
+// acquireEncodeSlot claims one of the encode slots of the video's tenant for the task and
+// heartbeats it until the returned release func is called. Returns an error wrapping
+// repository.ErrTaskDeferred when the tenant has no free slot, so the queue puts the task
+// back without holding a worker. Failures of the slot store are logged and let the task
+// run, so a Redis outage degrades to uncapped encoding rather than stopping it.
+func (s *transcodeService) acquireEncodeSlot(ctx context.Context, task repository.TranscodeTask) (func(), error) {
+	noop := func() {}
+	if s.slots == nil || (s.maxTenantEncodes <= 0 && len(s.tenantEncodeLimits) == 0) {
+		return noop, nil
+	}
+
+	video, err := s.repo.GetByID(ctx, task.VideoID)
+	if err != nil {
+		if errors.Is(err, repository.ErrVideoNotFound) {
+			// Nothing to count against; the pipeline handles the missing video
+			return noop, nil
+		}
+		return nil, fmt.Errorf("get video: %w", err)
+	}
+	tenantID := video.UserID
+	limit := s.encodeLimit(tenantID)
+	if limit <= 0 {
+		return noop, nil
+	}
+
+	slotID := uuid.NewString()
+	acquired, err := s.slots.Acquire(ctx, tenantID, slotID, limit, time.Now(), s.encodeSlotTimeout)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to acquire encode slot, encoding without it",
+			"video_id", task.VideoID,
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return noop, nil
+	}
+	if !acquired {
+		slog.InfoContext(ctx, "deferring transcode task of tenant at its encode limit",
+			"video_id", task.VideoID,
+			"tenant_id", tenantID,
+			"limit", limit,
+		)
+		return nil, fmt.Errorf("%w: tenant %s is running %d encodes", repository.ErrTaskDeferred, tenantID, limit)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(s.encodeSlotTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if err := s.slots.Touch(ctx, tenantID, slotID, now); err != nil {
+					slog.WarnContext(ctx, "failed to refresh encode slot",
+						"video_id", task.VideoID,
+						"tenant_id", tenantID,
+						"error", err,
+					)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobRecordTimeout)
+		defer cancel()
+		if err := s.slots.Release(releaseCtx, tenantID, slotID); err != nil {
+			// The slot frees itself once its heartbeat times out
+			slog.WarnContext(releaseCtx, "failed to release encode slot",
+				"video_id", task.VideoID,
+				"tenant_id", tenantID,
+				"error", err,
+			)
+		}
+	}, nil
+}
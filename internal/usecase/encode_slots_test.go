@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestTranscodeService_AcquireEncodeSlot(t *testing.T) {
+	videoID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	tenantID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+
+	tests := []struct {
+		name        string
+		max         int
+		overrides   map[string]int
+		acquired    bool
+		acquireErr  error
+		wantLimit   int // 0 means Acquire must not be called
+		wantErr     error
+		wantRelease bool
+	}{
+		{
+			name: "unlimited",
+		},
+		{
+			name:        "default limit",
+			max:         2,
+			acquired:    true,
+			wantLimit:   2,
+			wantRelease: true,
+		},
+		{
+			name:        "tenant override",
+			max:         2,
+			overrides:   map[string]int{tenantID.String(): 5},
+			acquired:    true,
+			wantLimit:   5,
+			wantRelease: true,
+		},
+		{
+			name:      "override lifts the limit",
+			max:       2,
+			overrides: map[string]int{tenantID.String(): 0},
+		},
+		{
+			name:      "tenant at its limit",
+			max:       2,
+			wantLimit: 2,
+			wantErr:   repository.ErrTaskDeferred,
+		},
+		{
+			name:       "store failure runs the task",
+			max:        2,
+			acquireErr: errors.New("connection refused"),
+			wantLimit:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, UserID: tenantID}, nil
+				},
+			}
+			var gotLimit int
+			var acquiredSlot, releasedSlot string
+			slots := &mockEncodeSlotStore{
+				acquireFn: func(ctx context.Context, id uuid.UUID, slotID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+					if id != tenantID {
+						t.Errorf("Acquire() tenant = %s, want %s", id, tenantID)
+					}
+					if timeout != DefaultEncodeSlotTimeout {
+						t.Errorf("Acquire() timeout = %v, want %v", timeout, DefaultEncodeSlotTimeout)
+					}
+					gotLimit, acquiredSlot = limit, slotID
+					return tt.acquired, tt.acquireErr
+				},
+				releaseFn: func(ctx context.Context, id uuid.UUID, slotID string) error {
+					releasedSlot = slotID
+					return nil
+				},
+			}
+			svc := NewTranscodeService(repo, nil, nil, nil, nil, nil, nil, nil, slots, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{
+				MaxTenantEncodes:   tt.max,
+				TenantEncodeLimits: tt.overrides,
+			}).(*transcodeService)
+
+			release, err := svc.acquireEncodeSlot(context.Background(), repository.TranscodeTask{VideoID: videoID})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("acquireEncodeSlot() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("acquireEncodeSlot() error = %v", err)
+			}
+			if gotLimit != tt.wantLimit {
+				t.Errorf("Acquire() limit = %d, want %d", gotLimit, tt.wantLimit)
+			}
+
+			release()
+			if tt.wantRelease && (releasedSlot == "" || releasedSlot != acquiredSlot) {
+				t.Errorf("released slot %q, want %q", releasedSlot, acquiredSlot)
+			}
+			if !tt.wantRelease && releasedSlot != "" {
+				t.Errorf("released slot %q, want none", releasedSlot)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_DefersOverTenantLimit(t *testing.T) {
+	repo := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return &model.Video{ID: id, UserID: uuid.New()}, nil
+		},
+	}
+	slots := &mockEncodeSlotStore{
+		acquireFn: func(ctx context.Context, tenantID uuid.UUID, slotID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+			return false, nil
+		},
+	}
+	// Storage and the transcoder are nil, so the task must not get past the slot check
+	svc := NewTranscodeService(repo, nil, nil, nil, nil, nil, nil, nil, slots, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{
+		TempDir:          t.TempDir(),
+		MaxRetries:       3,
+		MaxTenantEncodes: 1,
+	})
+
+	err := svc.ProcessTask(context.Background(), repository.TranscodeTask{VideoID: uuid.New(), RetryCount: 1})
+	if !errors.Is(err, repository.ErrTaskDeferred) {
+		t.Fatalf("ProcessTask() error = %v, want ErrTaskDeferred", err)
+	}
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+// ffmpegLogUploadTimeout bounds uploading a job's FFmpeg log after the task's context may
+// have been cancelled.
+const ffmpegLogUploadTimeout = 30 * time.Second
+
+// FFmpegLogMode selects the transcode jobs whose FFmpeg output is uploaded to storage.
+type FFmpegLogMode string
+
+const (
+	// FFmpegLogsOff discards FFmpeg output beyond the tail kept in the failure reason.
+	FFmpegLogsOff FFmpegLogMode = "off"
+	// FFmpegLogsFailed uploads the log of failed jobs only.
+	FFmpegLogsFailed FFmpegLogMode = "failed"
+	// FFmpegLogsAll uploads the log of every job.
+	FFmpegLogsAll FFmpegLogMode = "all"
+)
+
+// ParseFFmpegLogMode validates an FFmpeg log mode. An empty mode selects FFmpegLogsOff.
+func ParseFFmpegLogMode(s string) (FFmpegLogMode, error) {
+	switch mode := FFmpegLogMode(s); mode {
+	case "":
+		return FFmpegLogsOff, nil
+	case FFmpegLogsOff, FFmpegLogsFailed, FFmpegLogsAll:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown FFmpeg log mode %q (want %s, %s or %s)", s, FFmpegLogsOff, FFmpegLogsFailed, FFmpegLogsAll)
+	}
+}
+
+// FFmpegLogKey returns the storage key of the FFmpeg log of a transcode job.
+func FFmpegLogKey(videoID, jobID uuid.UUID) string {
+	return path.Join(ffmpegLogPrefix(videoID), jobID.String()+".log")
+}
+
+// ffmpegLogPrefix returns the prefix holding the FFmpeg logs of every job of a video.
+// Logs are keyed by video ID rather than storage prefix, as they are never served.
+func ffmpegLogPrefix(videoID uuid.UUID) string {
+	return path.Join("logs", videoID.String()) + "/"
+}
+
+// startFFmpegLog returns a context whose FFmpeg runs write their output to a temporary
+// log file, and a func that uploads the log, as selected by the log mode, once the job
+// has finished with err. Without a log mode, or when the file cannot be created, the
+// job runs without a log.
+func (s *transcodeService) startFFmpegLog(ctx context.Context, job *model.TranscodeJob) (context.Context, func(err error)) {
+	if s.ffmpegLogs == "" || s.ffmpegLogs == FFmpegLogsOff {
+		return ctx, func(error) {}
+	}
+
+	f, err := os.CreateTemp(s.tempDir, "ffmpeg-*.log")
+	if err != nil {
+		slog.WarnContext(ctx, "failed to create FFmpeg log",
+			"video_id", job.VideoID,
+			"job_id", job.ID,
+			"error", err,
+		)
+		return ctx, func(error) {}
+	}
+
+	log := transcoder.NewJobLog(f)
+	return transcoder.WithJobLog(ctx, log), func(jobErr error) {
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		if jobErr == nil && s.ffmpegLogs == FFmpegLogsFailed {
+			return
+		}
+		if err := s.uploadFFmpegLog(ctx, job, log, f); err != nil {
+			slog.WarnContext(ctx, "failed to upload FFmpeg log",
+				"video_id", job.VideoID,
+				"job_id", job.ID,
+				"error", err,
+			)
+		}
+	}
+}
+
+// uploadFFmpegLog uploads the log written to f to FFmpegLogKey in platform storage, even
+// for tenants with an output bucket, since only operators read it.
+func (s *transcodeService) uploadFFmpegLog(ctx context.Context, job *model.TranscodeJob, log *transcoder.JobLog, f *os.File) error {
+	if err := log.Err(); err != nil {
+		return fmt.Errorf("write log: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind log: %w", err)
+	}
+
+	// The task context may already be cancelled, and the log of an interrupted job is
+	// the one most worth keeping
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ffmpegLogUploadTimeout)
+	defer cancel()
+	return s.storage.Upload(ctx, FFmpegLogKey(job.VideoID, job.ID), f, "text/plain; charset=utf-8")
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// KeyService serves the AES-128 keys of encrypted HLS output to authorized players.
+type KeyService interface {
+	// DecryptionKey returns the key of the video's HLS segments.
+	// Returns ErrInvalidPlaybackToken if the token does not grant access to the video, and
+	// repository.ErrEncryptionKeyNotFound if the video's segments are not encrypted.
+	DecryptionKey(ctx context.Context, videoID uuid.UUID, token string) ([]byte, error)
+}
+
+type keyService struct {
+	tokens PlaybackTokenService
+	keys   repository.EncryptionKeyRepository
+}
+
+// NewKeyService creates a new KeyService instance.
+func NewKeyService(tokens PlaybackTokenService, keys repository.EncryptionKeyRepository) KeyService {
+	return &keyService{
+		tokens: tokens,
+		keys:   keys,
+	}
+}
+
+// DecryptionKey validates the token first, so unauthorized requests never reach the key
+// store. Like playlist requests, the validation serves as the session heartbeat.
+func (s *keyService) DecryptionKey(ctx context.Context, videoID uuid.UUID, token string) ([]byte, error) {
+	if _, err := s.tokens.ValidateToken(ctx, token, videoID); err != nil {
+		return nil, err
+	}
+
+	key, err := s.keys.GetByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return key.Key, nil
+}
+
+// encryptionKeyURI is the URI of a video's key in its encrypted HLS playlists: the key
+// route of the API, root-relative so that players resolve it against the host serving
+// the tokenized playlists (see tokenizeURI).
+func encryptionKeyURI(videoID uuid.UUID) string {
+	return "/v1/videos/" + videoID.String() + "/key"
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestKeyService_DecryptionKey(t *testing.T) {
+	videoID := uuid.New()
+	key := bytes.Repeat([]byte{0xab}, model.EncryptionKeySize)
+
+	tests := []struct {
+		name     string
+		tokenErr error
+		hasKey   bool
+		wantErr  error
+	}{
+		{name: "authorized", hasKey: true},
+		{name: "invalid token", tokenErr: ErrInvalidPlaybackToken, hasKey: true, wantErr: ErrInvalidPlaybackToken},
+		{name: "not encrypted", wantErr: repository.ErrEncryptionKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &mockPlaybackTokenService{
+				validateTokenFn: func(ctx context.Context, token string, id uuid.UUID) (*model.PlaybackToken, error) {
+					if tt.tokenErr != nil {
+						return nil, tt.tokenErr
+					}
+					return &model.PlaybackToken{Token: token, VideoID: id}, nil
+				},
+			}
+			lookups := 0
+			keys := &mockEncryptionKeyRepository{
+				getByVideoIDFn: func(ctx context.Context, id uuid.UUID) (*model.EncryptionKey, error) {
+					lookups++
+					if !tt.hasKey {
+						return nil, repository.ErrEncryptionKeyNotFound
+					}
+					return &model.EncryptionKey{VideoID: id, Key: key}, nil
+				},
+			}
+
+			got, err := NewKeyService(tokens, keys).DecryptionKey(context.Background(), videoID, "tok")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecryptionKey() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.tokenErr != nil && lookups != 0 {
+				t.Error("key looked up for an invalid token")
+			}
+			if err == nil && !bytes.Equal(got, key) {
+				t.Errorf("DecryptionKey() = %x, want %x", got, key)
+			}
+		})
+	}
+}
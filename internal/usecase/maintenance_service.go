@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// ErrInvalidMaintenanceWindow is returned when a window ends before it starts or has already ended.
+var ErrInvalidMaintenanceWindow = errors.New("maintenance window must end after it starts and in the future")
+
+// MaintenanceService schedules maintenance windows and tells API servers and workers
+// whether one is open.
+type MaintenanceService interface {
+	// Schedule stores the window, replacing any other. A zero StartsAt opens it now.
+	// Returns ErrInvalidMaintenanceWindow if the window ends before it starts or in the past.
+	Schedule(ctx context.Context, window model.MaintenanceWindow) (*model.MaintenanceWindow, error)
+
+	// End removes the window, whether it is scheduled or open.
+	End(ctx context.Context) error
+
+	// Window returns the scheduled or open window, or nil if there is none.
+	Window(ctx context.Context) (*model.MaintenanceWindow, error)
+
+	// Active returns the open window, or nil if there is none. It is called on every
+	// request, so the stored window is reused for CacheTTL.
+	Active(ctx context.Context) *model.MaintenanceWindow
+
+	// Wait blocks while a window is open. Returns ctx.Err() if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
+// MaintenanceConfig holds configuration for MaintenanceService.
+type MaintenanceConfig struct {
+	// CacheTTL is how long the stored window is reused before it is read again, and how
+	// often Wait checks whether the window has ended.
+	CacheTTL time.Duration
+}
+
+// DefaultMaintenanceConfig returns the default configuration.
+func DefaultMaintenanceConfig() MaintenanceConfig {
+	return MaintenanceConfig{
+		CacheTTL: 5 * time.Second,
+	}
+}
+
+type maintenanceService struct {
+	store cache.MaintenanceStore
+	cfg   MaintenanceConfig
+	now   func() time.Time
+
+	mu        sync.Mutex
+	window    *model.MaintenanceWindow
+	checkedAt time.Time
+}
+
+// NewMaintenanceService creates a new MaintenanceService instance.
+func NewMaintenanceService(store cache.MaintenanceStore, cfg MaintenanceConfig) MaintenanceService {
+	return &maintenanceService{
+		store: store,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Schedule also replaces the cached window, so the replica handling the request
+// follows it right away; the others pick it up within CacheTTL.
+func (s *maintenanceService) Schedule(ctx context.Context, window model.MaintenanceWindow) (*model.MaintenanceWindow, error) {
+	now := s.now()
+	if window.StartsAt.IsZero() {
+		window.StartsAt = now
+	}
+	if !window.EndsAt.IsZero() && (!window.EndsAt.After(window.StartsAt) || !window.EndsAt.After(now)) {
+		return nil, ErrInvalidMaintenanceWindow
+	}
+
+	if err := s.store.Set(ctx, &window); err != nil {
+		return nil, fmt.Errorf("store maintenance window: %w", err)
+	}
+
+	s.mu.Lock()
+	s.window, s.checkedAt = &window, now
+	s.mu.Unlock()
+	return &window, nil
+}
+
+func (s *maintenanceService) End(ctx context.Context) error {
+	if err := s.store.Clear(ctx); err != nil {
+		return fmt.Errorf("clear maintenance window: %w", err)
+	}
+
+	s.mu.Lock()
+	s.window, s.checkedAt = nil, s.now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *maintenanceService) Window(ctx context.Context) (*model.MaintenanceWindow, error) {
+	window, err := s.store.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get maintenance window: %w", err)
+	}
+	return window, nil
+}
+
+// Active compares the cached window with the current time on every call, so a scheduled
+// window opens and closes on time even while it is cached. If the store cannot be read,
+// the last known window is kept, so a Redis outage neither starts nor ends maintenance.
+func (s *maintenanceService) Active(ctx context.Context) *model.MaintenanceWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.checkedAt.IsZero() || now.Sub(s.checkedAt) >= s.cfg.CacheTTL {
+		window, err := s.store.Get(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "maintenance: failed to read window",
+				slog.String("error", err.Error()),
+			)
+		} else {
+			s.window = window
+		}
+		s.checkedAt = now
+	}
+
+	if s.window == nil || !s.window.IsActive(now) {
+		return nil
+	}
+	return s.window
+}
+
+func (s *maintenanceService) Wait(ctx context.Context) error {
+	interval := s.cfg.CacheTTL
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	for s.Active(ctx) != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	return nil
+}
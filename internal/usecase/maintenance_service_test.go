@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+)
+
+func TestMaintenanceService_Schedule(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		window   model.MaintenanceWindow
+		setErr   error
+		wantErr  error
+		wantFrom time.Time
+	}{
+		{
+			name:     "starts now without start",
+			window:   model.MaintenanceWindow{EndsAt: now.Add(time.Hour), Reason: "db upgrade"},
+			wantFrom: now,
+		},
+		{
+			name:     "scheduled",
+			window:   model.MaintenanceWindow{StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+			wantFrom: now.Add(time.Hour),
+		},
+		{
+			name:     "no end",
+			window:   model.MaintenanceWindow{},
+			wantFrom: now,
+		},
+		{
+			name:    "ends before start",
+			window:  model.MaintenanceWindow{StartsAt: now.Add(2 * time.Hour), EndsAt: now.Add(time.Hour)},
+			wantErr: ErrInvalidMaintenanceWindow,
+		},
+		{
+			name:    "already ended",
+			window:  model.MaintenanceWindow{StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+			wantErr: ErrInvalidMaintenanceWindow,
+		},
+		{
+			name:    "store error",
+			window:  model.MaintenanceWindow{EndsAt: now.Add(time.Hour)},
+			setErr:  errors.New("redis down"),
+			wantErr: errors.New("store maintenance window"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored *model.MaintenanceWindow
+			store := &mockMaintenanceStore{
+				setFn: func(ctx context.Context, window *model.MaintenanceWindow) error {
+					stored = window
+					return tt.setErr
+				},
+			}
+			svc := NewMaintenanceService(store, DefaultMaintenanceConfig()).(*maintenanceService)
+			svc.now = func() time.Time { return now }
+
+			got, err := svc.Schedule(context.Background(), tt.window)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error %v, got nil", tt.wantErr)
+				}
+				if errors.Is(tt.wantErr, ErrInvalidMaintenanceWindow) && !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.StartsAt.Equal(tt.wantFrom) {
+				t.Errorf("StartsAt = %v, want %v", got.StartsAt, tt.wantFrom)
+			}
+			if stored == nil || *stored != *got {
+				t.Errorf("stored %+v, want %+v", stored, got)
+			}
+		})
+	}
+}
+
+func TestMaintenanceService_Active(t *testing.T) {
+	now := time.Now()
+	window := &model.MaintenanceWindow{StartsAt: now.Add(time.Minute), EndsAt: now.Add(time.Hour)}
+
+	var calls int
+	var getErr error
+	store := &mockMaintenanceStore{
+		getFn: func(ctx context.Context) (*model.MaintenanceWindow, error) {
+			calls++
+			return window, getErr
+		},
+	}
+	cfg := MaintenanceConfig{CacheTTL: 10 * time.Minute}
+	svc := NewMaintenanceService(store, cfg).(*maintenanceService)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if got := svc.Active(ctx); got != nil {
+		t.Errorf("scheduled window reported active: %+v", got)
+	}
+
+	// The cached window opens on time without another read
+	now = now.Add(time.Minute)
+	if got := svc.Active(ctx); got != window {
+		t.Errorf("Active() = %+v, want %+v", got, window)
+	}
+	if calls != 1 {
+		t.Errorf("store reads: got %d, expected 1", calls)
+	}
+
+	// A failed read keeps the last known window
+	now = now.Add(cfg.CacheTTL)
+	getErr = errors.New("redis down")
+	if got := svc.Active(ctx); got != window {
+		t.Errorf("Active() after read error = %+v, want %+v", got, window)
+	}
+	if calls != 2 {
+		t.Errorf("store reads: got %d, expected 2", calls)
+	}
+
+	if err := svc.End(ctx); err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+	if got := svc.Active(ctx); got != nil {
+		t.Errorf("Active() after End = %+v, want nil", got)
+	}
+}
+
+func TestMaintenanceService_Wait(t *testing.T) {
+	open := true
+	store := &mockMaintenanceStore{
+		getFn: func(ctx context.Context) (*model.MaintenanceWindow, error) {
+			if !open {
+				return nil, nil
+			}
+			open = false
+			return &model.MaintenanceWindow{StartsAt: time.Now().Add(-time.Minute)}, nil
+		},
+	}
+	svc := NewMaintenanceService(store, MaintenanceConfig{CacheTTL: time.Millisecond})
+
+	done := make(chan error, 1)
+	go func() { done <- svc.Wait(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Wait() = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after the window ended")
+	}
+}
+
+func TestMaintenanceService_Wait_Canceled(t *testing.T) {
+	store := &mockMaintenanceStore{
+		getFn: func(ctx context.Context) (*model.MaintenanceWindow, error) {
+			return &model.MaintenanceWindow{StartsAt: time.Now().Add(-time.Minute)}, nil
+		},
+	}
+	svc := NewMaintenanceService(store, MaintenanceConfig{CacheTTL: time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := svc.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/hls"
+)
+
+// maxPlaylistBytes caps how much of a stored playlist is read. Playlists of the
+// longest videos are a few hundred KiB; anything larger is not ours.
+const maxPlaylistBytes = 4 << 20
+
+// Query parameters appended to every URI of a tokenized playlist. They match the
+// parameters of GET /v1/playback/authorize, so the segment route can forward them as is.
+const (
+	playlistTokenParam   = "token"
+	playlistVideoIDParam = "video_id"
+)
+
+// ErrPlaylistNotFound is returned when the requested playlist is not part of the video's output.
+var ErrPlaylistNotFound = errors.New("playlist not found")
+
+// ManifestService serves a video's HLS playlists rewritten for one viewer.
+type ManifestService interface {
+	// TokenizedPlaylist returns the playlist at name, relative to the video's HLS output
+	// (e.g. "master.m3u8" or "720p/playlist.m3u8"), with the viewer's token appended to
+	// every URI. Playlist URIs stay relative so players fetch them through this service
+	// again, as do the key URIs of encrypted output, which name the API's key route;
+	// every other URI (segments, init sections) points at the CDN.
+	// Returns ErrInvalidPlaybackToken if the token does not grant access to the video,
+	// ErrVideoNotReady if the video has no HLS output, and ErrPlaylistNotFound if the
+	// output has no such playlist.
+	TokenizedPlaylist(ctx context.Context, videoID uuid.UUID, token, name string) ([]byte, error)
+}
+
+type manifestService struct {
+	videos     VideoService
+	tokens     PlaybackTokenService
+	storage    repository.ObjectStorage
+	renditions repository.RenditionRepository
+}
+
+// NewManifestService creates a new ManifestService instance.
+// The videos service should return CDN URLs (see NewCachedVideoService), which are the
+// base for segment URIs; the playlists themselves are read from storage.
+// The renditions parameter is optional - pass nil to not record which renditions are
+// viewed, which leaves every video out of rendition pruning.
+func NewManifestService(videos VideoService, tokens PlaybackTokenService, storage repository.ObjectStorage, renditions repository.RenditionRepository) ManifestService {
+	return &manifestService{
+		videos:     videos,
+		tokens:     tokens,
+		storage:    storage,
+		renditions: renditions,
+	}
+}
+
+// TokenizedPlaylist validates the token first, so unauthorized requests never reach storage.
+// Validation also serves as the session heartbeat, like the segment route's.
+func (s *manifestService) TokenizedPlaylist(ctx context.Context, videoID uuid.UUID, token, name string) ([]byte, error) {
+	if _, err := s.tokens.ValidateToken(ctx, token, videoID); err != nil {
+		return nil, err
+	}
+
+	name = path.Clean(name)
+	if path.Ext(name) != ".m3u8" || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return nil, ErrPlaylistNotFound
+	}
+
+	video, err := s.videos.GetVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if !video.IsReady() || video.HLSURL == "" {
+		return nil, ErrVideoNotReady
+	}
+	if video.ExternalOutput {
+		// The playlists are in the tenant's bucket, served by the tenant's CDN
+		return nil, ErrExternalOutput
+	}
+
+	// The master playlist sits at the root of the output, so its CDN URL resolves
+	// names relative to the output like the storage key does
+	masterURL, err := url.Parse(video.HLSURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse HLS URL: %w", err)
+	}
+	playlistURL := masterURL.ResolveReference(&url.URL{Path: name})
+
+	key := generateHLSOutputKey(video.StoragePrefix(), video.OutputVersion) + name
+	playlist, err := s.download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	// Variant playlists sit in the variant's directory; a player fetching one picked it.
+	// Subtitle tracks are not renditions
+	if dir := path.Dir(name); dir != "." && path.Dir(dir) != "subtitles" {
+		s.markViewed(ctx, video, dir)
+	}
+
+	parsed, err := hls.Parse(playlist)
+	if err != nil {
+		return nil, fmt.Errorf("parse playlist %s: %w", key, err)
+	}
+
+	query := url.Values{
+		playlistTokenParam:   {token},
+		playlistVideoIDParam: {videoID.String()},
+	}
+	parsed.RewriteURIs(func(uri string) string {
+		return tokenizeURI(uri, playlistURL, query)
+	})
+	return parsed.Encode(), nil
+}
+
+func (s *manifestService) download(ctx context.Context, key string) ([]byte, error) {
+	reader, err := s.storage.Download(ctx, key)
+	if errors.Is(err, repository.ErrObjectNotFound) {
+		return nil, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("download playlist: %w", err)
+	}
+	defer reader.Close()
+
+	playlist, err := io.ReadAll(io.LimitReader(reader, maxPlaylistBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read playlist: %w", err)
+	}
+	if len(playlist) > maxPlaylistBytes {
+		return nil, fmt.Errorf("playlist %s exceeds %d bytes", key, maxPlaylistBytes)
+	}
+	return playlist, nil
+}
+
+// markViewed records a view of the video's rendition for pruning.
+// Errors are logged but not propagated - a missed view only makes pruning less informed.
+func (s *manifestService) markViewed(ctx context.Context, video *model.Video, rendition string) {
+	if s.renditions == nil {
+		return
+	}
+
+	if err := s.renditions.MarkViewed(ctx, video.ID, video.OutputVersion, rendition, time.Now()); err != nil {
+		slog.WarnContext(ctx, "failed to record rendition view",
+			"video_id", video.ID,
+			"rendition", rendition,
+			"error", err,
+		)
+	}
+}
+
+// tokenizeURI appends query to a URI found in the playlist at playlistURL.
+// Nested playlists and root-relative URIs (API routes, such as the decryption key)
+// stay relative; other URIs are resolved to absolute CDN URLs.
+// Absolute URIs point at someone else's host and are returned unchanged, so the
+// token never leaves our infrastructure.
+func tokenizeURI(uri string, playlistURL *url.URL, query url.Values) string {
+	ref, err := url.Parse(uri)
+	if err != nil || ref.IsAbs() || ref.Host != "" {
+		return uri
+	}
+
+	if path.Ext(ref.Path) != ".m3u8" && !strings.HasPrefix(ref.Path, "/") {
+		ref = playlistURL.ResolveReference(ref)
+	}
+
+	values := ref.Query()
+	for name, v := range query {
+		values[name] = v
+	}
+	ref.RawQuery = values.Encode()
+	return ref.String()
+}
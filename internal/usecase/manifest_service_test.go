@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestManifestService_TokenizedPlaylist(t *testing.T) {
+	videoID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	query := "?token=tok&video_id=" + videoID.String()
+
+	playlists := map[string]string{
+		"hls/abc123/v2/master.m3u8": "#EXTM3U\n" +
+			"#EXT-X-STREAM-INF:BANDWIDTH=2800000,RESOLUTION=1280x720\n" +
+			"720p/playlist.m3u8\n" +
+			"#EXT-X-I-FRAME-STREAM-INF:BANDWIDTH=200000,URI=\"720p/iframes.m3u8\"\n",
+		"hls/abc123/v2/720p/playlist.m3u8": "#EXTM3U\n" +
+			"#EXT-X-KEY:METHOD=AES-128,URI=\"/v1/videos/11111111-1111-1111-1111-111111111111/key\"\n" +
+			"#EXT-X-MAP:URI=\"init.mp4\"\n" +
+			"# encoded by ffmpeg\n" +
+			"#EXTINF:6.000,\n" +
+			"segment_000.ts\n" +
+			"#EXTINF:6.000,\n" +
+			"https://ads.example.com/slate.ts\n" +
+			"\n",
+	}
+
+	tests := []struct {
+		name       string
+		want       string
+		wantViewed string
+	}{
+		{
+			name: "master.m3u8",
+			want: "#EXTM3U\n" +
+				"#EXT-X-STREAM-INF:BANDWIDTH=2800000,RESOLUTION=1280x720\n" +
+				"720p/playlist.m3u8" + query + "\n" +
+				"#EXT-X-I-FRAME-STREAM-INF:BANDWIDTH=200000,URI=\"720p/iframes.m3u8" + query + "\"\n",
+		},
+		{
+			name: "720p/playlist.m3u8",
+			want: "#EXTM3U\n" +
+				"#EXT-X-KEY:METHOD=AES-128,URI=\"/v1/videos/11111111-1111-1111-1111-111111111111/key" + query + "\"\n" +
+				"#EXT-X-MAP:URI=\"https://cdn.example.com/hls/abc123/v2/720p/init.mp4" + query + "\"\n" +
+				"# encoded by ffmpeg\n" +
+				"#EXTINF:6.000,\n" +
+				"https://cdn.example.com/hls/abc123/v2/720p/segment_000.ts" + query + "\n" +
+				"#EXTINF:6.000,\n" +
+				"https://ads.example.com/slate.ts\n" +
+				"\n",
+			wantViewed: "720p",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{
+						ID:            id,
+						Status:        model.StatusReady,
+						HLSURL:        "https://cdn.example.com/hls/abc123/v2/master.m3u8",
+						StorageKey:    "abc123",
+						OutputVersion: 2,
+					}, nil
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					playlist, ok := playlists[key]
+					if !ok {
+						return nil, repository.ErrObjectNotFound
+					}
+					return io.NopCloser(strings.NewReader(playlist)), nil
+				},
+			}
+
+			var viewed string
+			renditions := &mockRenditionRepository{
+				markViewedFn: func(ctx context.Context, id uuid.UUID, outputVersion int64, name string, at time.Time) error {
+					if outputVersion != 2 {
+						t.Errorf("MarkViewed output version: got %d, want 2", outputVersion)
+					}
+					viewed = name
+					return errors.New("db down")
+				},
+			}
+
+			svc := NewManifestService(videos, &mockPlaybackTokenService{}, storage, renditions)
+			got, err := svc.TokenizedPlaylist(context.Background(), videoID, "tok", tt.name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("playlist:\ngot  %q\nwant %q", got, tt.want)
+			}
+			if viewed != tt.wantViewed {
+				t.Errorf("viewed rendition: got %q, want %q", viewed, tt.wantViewed)
+			}
+		})
+	}
+}
+
+func TestManifestService_TokenizedPlaylist_Errors(t *testing.T) {
+	ready := &model.Video{Status: model.StatusReady, HLSURL: "https://cdn.example.com/hls/x/v1/master.m3u8", OutputVersion: 1}
+
+	tests := []struct {
+		name     string
+		playlist string
+		tokenErr error
+		video    *model.Video
+		wantErr  error
+	}{
+		{name: "invalid token", playlist: "master.m3u8", tokenErr: ErrInvalidPlaybackToken, video: ready, wantErr: ErrInvalidPlaybackToken},
+		{name: "not ready", playlist: "master.m3u8", video: &model.Video{Status: model.StatusProcessing}, wantErr: ErrVideoNotReady},
+		{name: "missing playlist", playlist: "1080p/playlist.m3u8", video: ready, wantErr: ErrPlaylistNotFound},
+		{name: "segment", playlist: "720p/segment_000.ts", video: ready, wantErr: ErrPlaylistNotFound},
+		{name: "escapes output", playlist: "../../other/v1/master.m3u8", video: ready, wantErr: ErrPlaylistNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					video := *tt.video
+					video.ID = id
+					return &video, nil
+				},
+			}
+			tokens := &mockPlaybackTokenService{
+				validateTokenFn: func(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error) {
+					return nil, tt.tokenErr
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return nil, repository.ErrObjectNotFound
+				},
+			}
+
+			_, err := NewManifestService(videos, tokens, storage, nil).TokenizedPlaylist(context.Background(), uuid.New(), "tok", tt.playlist)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -8,16 +8,25 @@ import (
 	"github.com/google/uuid"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
 	"github.com/hszk-dev/gostream/internal/transcoder"
 )
 
 // mockVideoRepository provides a configurable mock for VideoRepository.
 type mockVideoRepository struct {
-	createFn       func(ctx context.Context, video *model.Video) error
-	getByIDFn      func(ctx context.Context, id uuid.UUID) (*model.Video, error)
-	getByUserIDFn  func(ctx context.Context, userID uuid.UUID) ([]*model.Video, error)
-	updateFn       func(ctx context.Context, video *model.Video) error
-	updateStatusFn func(ctx context.Context, id uuid.UUID, status model.Status) error
+	createFn              func(ctx context.Context, video *model.Video) error
+	getByIDFn             func(ctx context.Context, id uuid.UUID) (*model.Video, error)
+	getByShareSlugFn      func(ctx context.Context, slug string) (*model.Video, error)
+	getByTitleSlugFn      func(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error)
+	getByIdempotencyKeyFn func(ctx context.Context, userID uuid.UUID, key string) (*model.Video, error)
+	listFn                func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error)
+	searchFn              func(ctx context.Context, search repository.VideoSearch) ([]repository.VideoSearchResult, error)
+	updateFn              func(ctx context.Context, video *model.Video) error
+	updateStatusFn        func(ctx context.Context, id uuid.UUID, status model.Status) error
+	updateSourceFn        func(ctx context.Context, id uuid.UUID, source model.SourceMetadata) error
+	publishOutputFn       func(ctx context.Context, id uuid.UUID, version int64, hlsURL, dashURL, previewURL, thumbnailPrefix string, externalOutput bool) error
+	setOutputSizeFn       func(ctx context.Context, id uuid.UUID, size int64) error
+	getStorageUsageFn     func(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error)
 }
 
 func (m *mockVideoRepository) Create(ctx context.Context, video *model.Video) error {
@@ -34,9 +43,37 @@ func (m *mockVideoRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 	return nil, nil
 }
 
-func (m *mockVideoRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Video, error) {
-	if m.getByUserIDFn != nil {
-		return m.getByUserIDFn(ctx, userID)
+func (m *mockVideoRepository) GetByShareSlug(ctx context.Context, slug string) (*model.Video, error) {
+	if m.getByShareSlugFn != nil {
+		return m.getByShareSlugFn(ctx, slug)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoRepository) GetByTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error) {
+	if m.getByTitleSlugFn != nil {
+		return m.getByTitleSlugFn(ctx, userID, slug)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoRepository) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*model.Video, error) {
+	if m.getByIdempotencyKeyFn != nil {
+		return m.getByIdempotencyKeyFn(ctx, userID, key)
+	}
+	return nil, repository.ErrVideoNotFound
+}
+
+func (m *mockVideoRepository) List(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *mockVideoRepository) Search(ctx context.Context, search repository.VideoSearch) ([]repository.VideoSearchResult, error) {
+	if m.searchFn != nil {
+		return m.searchFn(ctx, search)
 	}
 	return nil, nil
 }
@@ -55,14 +92,49 @@ func (m *mockVideoRepository) UpdateStatus(ctx context.Context, id uuid.UUID, st
 	return nil
 }
 
+func (m *mockVideoRepository) UpdateSource(ctx context.Context, id uuid.UUID, source model.SourceMetadata) error {
+	if m.updateSourceFn != nil {
+		return m.updateSourceFn(ctx, id, source)
+	}
+	return nil
+}
+
+func (m *mockVideoRepository) PublishOutput(ctx context.Context, id uuid.UUID, version int64, hlsURL, dashURL, previewURL, thumbnailPrefix string, externalOutput bool) error {
+	if m.publishOutputFn != nil {
+		return m.publishOutputFn(ctx, id, version, hlsURL, dashURL, previewURL, thumbnailPrefix, externalOutput)
+	}
+	return nil
+}
+
+func (m *mockVideoRepository) SetOutputSize(ctx context.Context, id uuid.UUID, size int64) error {
+	if m.setOutputSizeFn != nil {
+		return m.setOutputSizeFn(ctx, id, size)
+	}
+	return nil
+}
+
+func (m *mockVideoRepository) GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error) {
+	if m.getStorageUsageFn != nil {
+		return m.getStorageUsageFn(ctx, userID)
+	}
+	return &model.StorageUsage{UserID: userID}, nil
+}
+
 // mockObjectStorage provides a configurable mock for ObjectStorage.
 type mockObjectStorage struct {
 	generatePresignedUploadURLFn   func(ctx context.Context, key string, expiry time.Duration) (string, error)
 	generatePresignedDownloadURLFn func(ctx context.Context, key string, expiry time.Duration) (string, error)
 	uploadFn                       func(ctx context.Context, key string, reader io.Reader, contentType string) error
 	downloadFn                     func(ctx context.Context, key string) (io.ReadCloser, error)
+	downloadRangeFn                func(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error)
+	statFn                         func(ctx context.Context, key string) (*repository.ObjectInfo, error)
 	deleteFn                       func(ctx context.Context, key string) error
 	existsFn                       func(ctx context.Context, key string) (bool, error)
+	listObjectsFn                  func(ctx context.Context, prefix string) ([]repository.ObjectInfo, error)
+	deleteByPrefixFn               func(ctx context.Context, prefix string) (int, error)
+	initiateMultipartUploadFn      func(ctx context.Context, key, contentType string) (string, error)
+	presignPartFn                  func(ctx context.Context, key, uploadID string, partNumber int, expiry time.Duration) (string, error)
+	completeMultipartUploadFn      func(ctx context.Context, key, uploadID string, parts []repository.CompletedPart) error
 }
 
 func (m *mockObjectStorage) GeneratePresignedUploadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
@@ -93,6 +165,20 @@ func (m *mockObjectStorage) Download(ctx context.Context, key string) (io.ReadCl
 	return nil, nil
 }
 
+func (m *mockObjectStorage) DownloadRange(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error) {
+	if m.downloadRangeFn != nil {
+		return m.downloadRangeFn(ctx, key, etag, offset, length)
+	}
+	return nil, nil
+}
+
+func (m *mockObjectStorage) Stat(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+	if m.statFn != nil {
+		return m.statFn(ctx, key)
+	}
+	return &repository.ObjectInfo{Key: key}, nil
+}
+
 func (m *mockObjectStorage) Delete(ctx context.Context, key string) error {
 	if m.deleteFn != nil {
 		return m.deleteFn(ctx, key)
@@ -107,10 +193,47 @@ func (m *mockObjectStorage) Exists(ctx context.Context, key string) (bool, error
 	return false, nil
 }
 
+func (m *mockObjectStorage) ListObjects(ctx context.Context, prefix string) ([]repository.ObjectInfo, error) {
+	if m.listObjectsFn != nil {
+		return m.listObjectsFn(ctx, prefix)
+	}
+	return []repository.ObjectInfo{}, nil
+}
+
+func (m *mockObjectStorage) DeleteByPrefix(ctx context.Context, prefix string) (int, error) {
+	if m.deleteByPrefixFn != nil {
+		return m.deleteByPrefixFn(ctx, prefix)
+	}
+	return 0, nil
+}
+
+func (m *mockObjectStorage) InitiateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	if m.initiateMultipartUploadFn != nil {
+		return m.initiateMultipartUploadFn(ctx, key, contentType)
+	}
+	return "", nil
+}
+
+func (m *mockObjectStorage) PresignPart(ctx context.Context, key, uploadID string, partNumber int, expiry time.Duration) (string, error) {
+	if m.presignPartFn != nil {
+		return m.presignPartFn(ctx, key, uploadID, partNumber, expiry)
+	}
+	return "", nil
+}
+
+func (m *mockObjectStorage) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []repository.CompletedPart) error {
+	if m.completeMultipartUploadFn != nil {
+		return m.completeMultipartUploadFn(ctx, key, uploadID, parts)
+	}
+	return nil
+}
+
 // mockMessageQueue provides a configurable mock for MessageQueue.
 type mockMessageQueue struct {
 	publishTranscodeTaskFn  func(ctx context.Context, task repository.TranscodeTask) error
 	consumeTranscodeTasksFn func(ctx context.Context, handler func(task repository.TranscodeTask) error) error
+	publishDeleteTaskFn     func(ctx context.Context, task repository.DeleteTask) error
+	consumeDeleteTasksFn    func(ctx context.Context, handler func(task repository.DeleteTask) error) error
 }
 
 func (m *mockMessageQueue) PublishTranscodeTask(ctx context.Context, task repository.TranscodeTask) error {
@@ -127,14 +250,119 @@ func (m *mockMessageQueue) ConsumeTranscodeTasks(ctx context.Context, handler fu
 	return nil
 }
 
+func (m *mockMessageQueue) PublishDeleteTask(ctx context.Context, task repository.DeleteTask) error {
+	if m.publishDeleteTaskFn != nil {
+		return m.publishDeleteTaskFn(ctx, task)
+	}
+	return nil
+}
+
+func (m *mockMessageQueue) ConsumeDeleteTasks(ctx context.Context, handler func(task repository.DeleteTask) error) error {
+	if m.consumeDeleteTasksFn != nil {
+		return m.consumeDeleteTasksFn(ctx, handler)
+	}
+	return nil
+}
+
 func (m *mockMessageQueue) Close() error {
 	return nil
 }
 
+// mockOutboxRepository provides a configurable mock for OutboxRepository.
+type mockOutboxRepository struct {
+	updateVideoWithTaskFn func(ctx context.Context, video *model.Video, msg *repository.OutboxMessage, availableAt time.Time) error
+	claimFn               func(ctx context.Context, now, until time.Time, limit int) ([]*repository.OutboxMessage, error)
+	deleteFn              func(ctx context.Context, id uuid.UUID) error
+	releaseFn             func(ctx context.Context, id uuid.UUID, retryAt time.Time, lastErr string) error
+}
+
+func (m *mockOutboxRepository) UpdateVideoWithTask(ctx context.Context, video *model.Video, msg *repository.OutboxMessage, availableAt time.Time) error {
+	if m.updateVideoWithTaskFn != nil {
+		return m.updateVideoWithTaskFn(ctx, video, msg, availableAt)
+	}
+	return nil
+}
+
+func (m *mockOutboxRepository) Claim(ctx context.Context, now, until time.Time, limit int) ([]*repository.OutboxMessage, error) {
+	if m.claimFn != nil {
+		return m.claimFn(ctx, now, until, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockOutboxRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockOutboxRepository) Release(ctx context.Context, id uuid.UUID, retryAt time.Time, lastErr string) error {
+	if m.releaseFn != nil {
+		return m.releaseFn(ctx, id, retryAt, lastErr)
+	}
+	return nil
+}
+
+// mockSupersededOutputRepository provides a configurable mock for SupersededOutputRepository.
+type mockSupersededOutputRepository struct {
+	scheduleFn func(ctx context.Context, output *repository.SupersededOutput) error
+	claimFn    func(ctx context.Context, now, until time.Time, limit int) ([]*repository.SupersededOutput, error)
+	deleteFn   func(ctx context.Context, videoID uuid.UUID, outputVersion int64) error
+}
+
+func (m *mockSupersededOutputRepository) Schedule(ctx context.Context, output *repository.SupersededOutput) error {
+	if m.scheduleFn != nil {
+		return m.scheduleFn(ctx, output)
+	}
+	return nil
+}
+
+func (m *mockSupersededOutputRepository) Claim(ctx context.Context, now, until time.Time, limit int) ([]*repository.SupersededOutput, error) {
+	if m.claimFn != nil {
+		return m.claimFn(ctx, now, until, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockSupersededOutputRepository) Delete(ctx context.Context, videoID uuid.UUID, outputVersion int64) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, videoID, outputVersion)
+	}
+	return nil
+}
+
+// mockQueueInspector provides a configurable mock for QueueInspector.
+type mockQueueInspector struct {
+	depthFn func(ctx context.Context) (int, error)
+}
+
+func (m *mockQueueInspector) Depth(ctx context.Context) (int, error) {
+	if m.depthFn != nil {
+		return m.depthFn(ctx)
+	}
+	return 0, nil
+}
+
+// mockAdmissionController provides a configurable mock for AdmissionController.
+type mockAdmissionController struct {
+	admitFn func(ctx context.Context, class WorkClass) error
+}
+
+func (m *mockAdmissionController) Admit(ctx context.Context, class WorkClass) error {
+	if m.admitFn != nil {
+		return m.admitFn(ctx, class)
+	}
+	return nil
+}
+
 // mockTranscoder provides a configurable mock for Transcoder.
 type mockTranscoder struct {
-	transcodeToHLSFn func(ctx context.Context, inputPath, outputDir string) (*transcoder.HLSOutput, error)
-	transcodeToABRFn func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant) (*transcoder.ABROutput, error)
+	transcodeToHLSFn   func(ctx context.Context, inputPath, outputDir string) (*transcoder.HLSOutput, error)
+	transcodeToABRFn   func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error)
+	transcodePreviewFn func(ctx context.Context, inputPath, outputDir string, variant transcoder.Variant, duration time.Duration) (*transcoder.HLSOutput, error)
+	transcodeToDASHFn  func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.DASHOutput, error)
+	thumbnailsFn       func(ctx context.Context, inputPath, outputDir string, at time.Duration, sizes []transcoder.ThumbnailSize) ([]transcoder.ThumbnailOutput, error)
 }
 
 func (m *mockTranscoder) TranscodeToHLS(ctx context.Context, inputPath, outputDir string) (*transcoder.HLSOutput, error) {
@@ -144,9 +372,1088 @@ func (m *mockTranscoder) TranscodeToHLS(ctx context.Context, inputPath, outputDi
 	return nil, nil
 }
 
-func (m *mockTranscoder) TranscodeToABR(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant) (*transcoder.ABROutput, error) {
+func (m *mockTranscoder) TranscodeToABR(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
 	if m.transcodeToABRFn != nil {
-		return m.transcodeToABRFn(ctx, inputPath, outputDir, variants)
+		return m.transcodeToABRFn(ctx, inputPath, outputDir, variants, progress)
+	}
+	return nil, nil
+}
+
+func (m *mockTranscoder) TranscodePreview(ctx context.Context, inputPath, outputDir string, variant transcoder.Variant, duration time.Duration) (*transcoder.HLSOutput, error) {
+	if m.transcodePreviewFn != nil {
+		return m.transcodePreviewFn(ctx, inputPath, outputDir, variant, duration)
+	}
+	return nil, nil
+}
+
+func (m *mockTranscoder) TranscodeToDASH(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.DASHOutput, error) {
+	if m.transcodeToDASHFn != nil {
+		return m.transcodeToDASHFn(ctx, inputPath, outputDir, variants, progress)
+	}
+	return nil, nil
+}
+
+func (m *mockTranscoder) GenerateThumbnails(ctx context.Context, inputPath, outputDir string, at time.Duration, sizes []transcoder.ThumbnailSize) ([]transcoder.ThumbnailOutput, error) {
+	if m.thumbnailsFn != nil {
+		return m.thumbnailsFn(ctx, inputPath, outputDir, at, sizes)
+	}
+	return nil, nil
+}
+
+// mockProber provides a configurable mock for Prober.
+type mockProber struct {
+	probeFn func(ctx context.Context, input string) (*transcoder.ProbeResult, error)
+}
+
+func (m *mockProber) Probe(ctx context.Context, input string) (*transcoder.ProbeResult, error) {
+	if m.probeFn != nil {
+		return m.probeFn(ctx, input)
+	}
+	return &transcoder.ProbeResult{}, nil
+}
+
+// mockTranscodeEstimator provides a configurable mock for TranscodeEstimator.
+type mockTranscodeEstimator struct {
+	estimateFn func(ctx context.Context, source transcoder.ProbeResult) (*model.TranscodeEstimate, error)
+}
+
+func (m *mockTranscodeEstimator) Estimate(ctx context.Context, source transcoder.ProbeResult) (*model.TranscodeEstimate, error) {
+	if m.estimateFn != nil {
+		return m.estimateFn(ctx, source)
+	}
+	return nil, nil
+}
+
+// mockPlaybackTokenStore provides a configurable mock for PlaybackTokenStore.
+type mockPlaybackTokenStore struct {
+	saveFn        func(ctx context.Context, token *model.PlaybackToken) error
+	getFn         func(ctx context.Context, token string) (*model.PlaybackToken, error)
+	deleteFn      func(ctx context.Context, token string) error
+	revokeVideoFn func(ctx context.Context, videoID uuid.UUID, at time.Time, ttl time.Duration) error
+	revokeUserFn  func(ctx context.Context, userID uuid.UUID, at time.Time, ttl time.Duration) error
+	revokedAtFn   func(ctx context.Context, videoID, userID uuid.UUID) (time.Time, time.Time, error)
+}
+
+func (m *mockPlaybackTokenStore) Save(ctx context.Context, token *model.PlaybackToken) error {
+	if m.saveFn != nil {
+		return m.saveFn(ctx, token)
+	}
+	return nil
+}
+
+func (m *mockPlaybackTokenStore) Get(ctx context.Context, token string) (*model.PlaybackToken, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, token)
+	}
+	return nil, nil
+}
+
+func (m *mockPlaybackTokenStore) Delete(ctx context.Context, token string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, token)
+	}
+	return nil
+}
+
+func (m *mockPlaybackTokenStore) RevokeVideo(ctx context.Context, videoID uuid.UUID, at time.Time, ttl time.Duration) error {
+	if m.revokeVideoFn != nil {
+		return m.revokeVideoFn(ctx, videoID, at, ttl)
+	}
+	return nil
+}
+
+func (m *mockPlaybackTokenStore) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time, ttl time.Duration) error {
+	if m.revokeUserFn != nil {
+		return m.revokeUserFn(ctx, userID, at, ttl)
+	}
+	return nil
+}
+
+func (m *mockPlaybackTokenStore) RevokedAt(ctx context.Context, videoID, userID uuid.UUID) (time.Time, time.Time, error) {
+	if m.revokedAtFn != nil {
+		return m.revokedAtFn(ctx, videoID, userID)
+	}
+	return time.Time{}, time.Time{}, nil
+}
+
+// mockStreamSessionStore provides a configurable mock for StreamSessionStore.
+type mockStreamSessionStore struct {
+	acquireFn    func(ctx context.Context, userID uuid.UUID, sessionID string, limit int, now time.Time, timeout time.Duration) (bool, error)
+	touchFn      func(ctx context.Context, userID uuid.UUID, sessionID string, now time.Time, timeout time.Duration) error
+	releaseFn    func(ctx context.Context, userID uuid.UUID, sessionID string) error
+	releaseAllFn func(ctx context.Context, userID uuid.UUID) error
+}
+
+func (m *mockStreamSessionStore) Acquire(ctx context.Context, userID uuid.UUID, sessionID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+	if m.acquireFn != nil {
+		return m.acquireFn(ctx, userID, sessionID, limit, now, timeout)
+	}
+	return true, nil
+}
+
+func (m *mockStreamSessionStore) Touch(ctx context.Context, userID uuid.UUID, sessionID string, now time.Time, timeout time.Duration) error {
+	if m.touchFn != nil {
+		return m.touchFn(ctx, userID, sessionID, now, timeout)
+	}
+	return nil
+}
+
+func (m *mockStreamSessionStore) Release(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	if m.releaseFn != nil {
+		return m.releaseFn(ctx, userID, sessionID)
+	}
+	return nil
+}
+
+func (m *mockStreamSessionStore) ReleaseAll(ctx context.Context, userID uuid.UUID) error {
+	if m.releaseAllFn != nil {
+		return m.releaseAllFn(ctx, userID)
+	}
+	return nil
+}
+
+// mockEncodeSlotStore provides a configurable mock for EncodeSlotStore.
+type mockEncodeSlotStore struct {
+	acquireFn func(ctx context.Context, tenantID uuid.UUID, slotID string, limit int, now time.Time, timeout time.Duration) (bool, error)
+	touchFn   func(ctx context.Context, tenantID uuid.UUID, slotID string, now time.Time) error
+	releaseFn func(ctx context.Context, tenantID uuid.UUID, slotID string) error
+}
+
+func (m *mockEncodeSlotStore) Acquire(ctx context.Context, tenantID uuid.UUID, slotID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+	if m.acquireFn != nil {
+		return m.acquireFn(ctx, tenantID, slotID, limit, now, timeout)
+	}
+	return true, nil
+}
+
+func (m *mockEncodeSlotStore) Touch(ctx context.Context, tenantID uuid.UUID, slotID string, now time.Time) error {
+	if m.touchFn != nil {
+		return m.touchFn(ctx, tenantID, slotID, now)
+	}
+	return nil
+}
+
+func (m *mockEncodeSlotStore) Release(ctx context.Context, tenantID uuid.UUID, slotID string) error {
+	if m.releaseFn != nil {
+		return m.releaseFn(ctx, tenantID, slotID)
+	}
+	return nil
+}
+
+// mockProgressRepository provides a configurable mock for ProgressRepository.
+type mockProgressRepository struct {
+	upsertBatchFn func(ctx context.Context, progress []*model.PlaybackProgress) error
+	getFn         func(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error)
+	listByUserFn  func(ctx context.Context, userID uuid.UUID, cursor *repository.HistoryCursor, limit int) ([]*model.WatchHistoryEntry, error)
+	deleteFn      func(ctx context.Context, userID, videoID uuid.UUID) error
+}
+
+func (m *mockProgressRepository) UpsertBatch(ctx context.Context, progress []*model.PlaybackProgress) error {
+	if m.upsertBatchFn != nil {
+		return m.upsertBatchFn(ctx, progress)
+	}
+	return nil
+}
+
+func (m *mockProgressRepository) Get(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, userID, videoID)
+	}
+	return nil, repository.ErrProgressNotFound
+}
+
+func (m *mockProgressRepository) ListByUser(ctx context.Context, userID uuid.UUID, cursor *repository.HistoryCursor, limit int) ([]*model.WatchHistoryEntry, error) {
+	if m.listByUserFn != nil {
+		return m.listByUserFn(ctx, userID, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockProgressRepository) Delete(ctx context.Context, userID, videoID uuid.UUID) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, userID, videoID)
+	}
+	return nil
+}
+
+// mockProgressStore provides a configurable mock for ProgressStore.
+type mockProgressStore struct {
+	saveFn      func(ctx context.Context, progress *model.PlaybackProgress) error
+	primeFn     func(ctx context.Context, progress *model.PlaybackProgress) error
+	getFn       func(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error)
+	popDirtyFn  func(ctx context.Context, n int) ([]*model.PlaybackProgress, bool, error)
+	markDirtyFn func(ctx context.Context, progress []*model.PlaybackProgress) error
+	deleteFn    func(ctx context.Context, userID, videoID uuid.UUID) (bool, error)
+}
+
+func (m *mockProgressStore) Save(ctx context.Context, progress *model.PlaybackProgress) error {
+	if m.saveFn != nil {
+		return m.saveFn(ctx, progress)
+	}
+	return nil
+}
+
+func (m *mockProgressStore) Prime(ctx context.Context, progress *model.PlaybackProgress) error {
+	if m.primeFn != nil {
+		return m.primeFn(ctx, progress)
+	}
+	return nil
+}
+
+func (m *mockProgressStore) Get(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, userID, videoID)
 	}
 	return nil, nil
 }
+
+func (m *mockProgressStore) PopDirty(ctx context.Context, n int) ([]*model.PlaybackProgress, bool, error) {
+	if m.popDirtyFn != nil {
+		return m.popDirtyFn(ctx, n)
+	}
+	return nil, true, nil
+}
+
+func (m *mockProgressStore) MarkDirty(ctx context.Context, progress []*model.PlaybackProgress) error {
+	if m.markDirtyFn != nil {
+		return m.markDirtyFn(ctx, progress)
+	}
+	return nil
+}
+
+func (m *mockProgressStore) Delete(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, userID, videoID)
+	}
+	return false, nil
+}
+
+// mockTranscodeProgressStore provides a configurable mock for TranscodeProgressStore.
+type mockTranscodeProgressStore struct {
+	setFn    func(ctx context.Context, videoID uuid.UUID, percent int) error
+	getFn    func(ctx context.Context, videoID uuid.UUID) (int, bool, error)
+	deleteFn func(ctx context.Context, videoID uuid.UUID) error
+}
+
+func (m *mockTranscodeProgressStore) Set(ctx context.Context, videoID uuid.UUID, percent int) error {
+	if m.setFn != nil {
+		return m.setFn(ctx, videoID, percent)
+	}
+	return nil
+}
+
+func (m *mockTranscodeProgressStore) Get(ctx context.Context, videoID uuid.UUID) (int, bool, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, videoID)
+	}
+	return 0, false, nil
+}
+
+func (m *mockTranscodeProgressStore) Delete(ctx context.Context, videoID uuid.UUID) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, videoID)
+	}
+	return nil
+}
+
+// mockVideoEventBus provides a configurable mock for VideoEventBus.
+// Without subscribeFn, subscriptions receive nothing and close when ctx is done.
+type mockVideoEventBus struct {
+	publishFn   func(ctx context.Context, event cache.VideoEvent) error
+	subscribeFn func(ctx context.Context, videoID uuid.UUID) (<-chan cache.VideoEvent, error)
+}
+
+func (m *mockVideoEventBus) Publish(ctx context.Context, event cache.VideoEvent) error {
+	if m.publishFn != nil {
+		return m.publishFn(ctx, event)
+	}
+	return nil
+}
+
+func (m *mockVideoEventBus) Subscribe(ctx context.Context, videoID uuid.UUID) (<-chan cache.VideoEvent, error) {
+	if m.subscribeFn != nil {
+		return m.subscribeFn(ctx, videoID)
+	}
+	events := make(chan cache.VideoEvent)
+	go func() {
+		<-ctx.Done()
+		close(events)
+	}()
+	return events, nil
+}
+
+// mockEntitlementChecker provides a configurable mock for EntitlementChecker.
+type mockEntitlementChecker struct {
+	isEntitledFn func(ctx context.Context, userID, videoID uuid.UUID) (bool, error)
+}
+
+func (m *mockEntitlementChecker) IsEntitled(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+	if m.isEntitledFn != nil {
+		return m.isEntitledFn(ctx, userID, videoID)
+	}
+	return true, nil
+}
+
+// mockVideoTokenRevoker provides a configurable mock for VideoTokenRevoker.
+type mockVideoTokenRevoker struct {
+	revokeVideoTokensFn func(ctx context.Context, videoID uuid.UUID) error
+}
+
+func (m *mockVideoTokenRevoker) RevokeVideoTokens(ctx context.Context, videoID uuid.UUID) error {
+	if m.revokeVideoTokensFn != nil {
+		return m.revokeVideoTokensFn(ctx, videoID)
+	}
+	return nil
+}
+
+// mockCDNPurger provides a configurable mock for CDNPurger.
+type mockCDNPurger struct {
+	purgeFn func(ctx context.Context, prefixes []string) error
+}
+
+func (m *mockCDNPurger) Purge(ctx context.Context, prefixes []string) error {
+	if m.purgeFn != nil {
+		return m.purgeFn(ctx, prefixes)
+	}
+	return nil
+}
+
+// mockTranscodeJobRepository provides a configurable mock for TranscodeJobRepository.
+type mockTranscodeJobRepository struct {
+	createFn        func(ctx context.Context, job *model.TranscodeJob) error
+	listByVideoIDFn func(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error)
+	statsFn         func(ctx context.Context, since time.Time) (*repository.TranscodeJobStats, error)
+	costStatsFn     func(ctx context.Context, since time.Time) ([]repository.TranscodeCostStats, error)
+}
+
+func (m *mockTranscodeJobRepository) Create(ctx context.Context, job *model.TranscodeJob) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, job)
+	}
+	return nil
+}
+
+func (m *mockTranscodeJobRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.TranscodeJob, error) {
+	if m.listByVideoIDFn != nil {
+		return m.listByVideoIDFn(ctx, videoID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTranscodeJobRepository) Stats(ctx context.Context, since time.Time) (*repository.TranscodeJobStats, error) {
+	if m.statsFn != nil {
+		return m.statsFn(ctx, since)
+	}
+	return &repository.TranscodeJobStats{}, nil
+}
+
+func (m *mockTranscodeJobRepository) CostStats(ctx context.Context, since time.Time) ([]repository.TranscodeCostStats, error) {
+	if m.costStatsFn != nil {
+		return m.costStatsFn(ctx, since)
+	}
+	return nil, nil
+}
+
+// mockRenditionRepository provides a configurable mock for RenditionRepository.
+type mockRenditionRepository struct {
+	saveAllFn             func(ctx context.Context, renditions []*model.Rendition) error
+	listByVideoIDFn       func(ctx context.Context, videoID uuid.UUID, outputVersion int64) ([]*model.Rendition, error)
+	markViewedFn          func(ctx context.Context, videoID uuid.UUID, outputVersion int64, name string, at time.Time) error
+	listPruneCandidatesFn func(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error)
+	markPrunedFn          func(ctx context.Context, videoID uuid.UUID, outputVersion int64, names []string, at time.Time) error
+}
+
+func (m *mockRenditionRepository) SaveAll(ctx context.Context, renditions []*model.Rendition) error {
+	if m.saveAllFn != nil {
+		return m.saveAllFn(ctx, renditions)
+	}
+	return nil
+}
+
+func (m *mockRenditionRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID, outputVersion int64) ([]*model.Rendition, error) {
+	if m.listByVideoIDFn != nil {
+		return m.listByVideoIDFn(ctx, videoID, outputVersion)
+	}
+	return nil, nil
+}
+
+func (m *mockRenditionRepository) MarkViewed(ctx context.Context, videoID uuid.UUID, outputVersion int64, name string, at time.Time) error {
+	if m.markViewedFn != nil {
+		return m.markViewedFn(ctx, videoID, outputVersion, name, at)
+	}
+	return nil
+}
+
+func (m *mockRenditionRepository) ListPruneCandidates(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	if m.listPruneCandidatesFn != nil {
+		return m.listPruneCandidatesFn(ctx, cutoff, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockRenditionRepository) MarkPruned(ctx context.Context, videoID uuid.UUID, outputVersion int64, names []string, at time.Time) error {
+	if m.markPrunedFn != nil {
+		return m.markPrunedFn(ctx, videoID, outputVersion, names, at)
+	}
+	return nil
+}
+
+// mockCustomDomainRepository provides a configurable mock for CustomDomainRepository.
+type mockCustomDomainRepository struct {
+	createFn            func(ctx context.Context, domain *model.CustomDomain) error
+	getByIDFn           func(ctx context.Context, id uuid.UUID) (*model.CustomDomain, error)
+	listByTenantFn      func(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error)
+	getPlaybackDomainFn func(ctx context.Context, tenantID uuid.UUID) (*model.CustomDomain, error)
+	updateFn            func(ctx context.Context, domain *model.CustomDomain) error
+	deleteFn            func(ctx context.Context, id uuid.UUID) error
+}
+
+func (m *mockCustomDomainRepository) Create(ctx context.Context, domain *model.CustomDomain) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, domain)
+	}
+	return nil
+}
+
+func (m *mockCustomDomainRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.CustomDomain, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
+	return nil, repository.ErrCustomDomainNotFound
+}
+
+func (m *mockCustomDomainRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*model.CustomDomain, error) {
+	if m.listByTenantFn != nil {
+		return m.listByTenantFn(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *mockCustomDomainRepository) GetPlaybackDomain(ctx context.Context, tenantID uuid.UUID) (*model.CustomDomain, error) {
+	if m.getPlaybackDomainFn != nil {
+		return m.getPlaybackDomainFn(ctx, tenantID)
+	}
+	return nil, repository.ErrCustomDomainNotFound
+}
+
+func (m *mockCustomDomainRepository) Update(ctx context.Context, domain *model.CustomDomain) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, domain)
+	}
+	return nil
+}
+
+func (m *mockCustomDomainRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, id)
+	}
+	return nil
+}
+
+// mockPlaylistRepository provides a configurable mock for PlaylistRepository.
+type mockPlaylistRepository struct {
+	createFn     func(ctx context.Context, playlist *model.Playlist) error
+	getByIDFn    func(ctx context.Context, id uuid.UUID) (*model.Playlist, error)
+	listByUserFn func(ctx context.Context, userID uuid.UUID) ([]*model.Playlist, error)
+	updateFn     func(ctx context.Context, playlist *model.Playlist) error
+	deleteFn     func(ctx context.Context, id uuid.UUID) error
+}
+
+func (m *mockPlaylistRepository) Create(ctx context.Context, playlist *model.Playlist) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, playlist)
+	}
+	return nil
+}
+
+func (m *mockPlaylistRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Playlist, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
+	return nil, repository.ErrPlaylistNotFound
+}
+
+func (m *mockPlaylistRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Playlist, error) {
+	if m.listByUserFn != nil {
+		return m.listByUserFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockPlaylistRepository) Update(ctx context.Context, playlist *model.Playlist) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, playlist)
+	}
+	return nil
+}
+
+func (m *mockPlaylistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, id)
+	}
+	return nil
+}
+
+// mockTXTResolver provides a configurable mock for TXTResolver.
+type mockTXTResolver struct {
+	lookupTXTFn func(ctx context.Context, name string) ([]string, error)
+}
+
+func (m *mockTXTResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if m.lookupTXTFn != nil {
+		return m.lookupTXTFn(ctx, name)
+	}
+	return nil, nil
+}
+
+// mockPlaybackDomainResolver provides a configurable mock for PlaybackDomainResolver.
+type mockPlaybackDomainResolver struct {
+	playbackBaseURLFn func(ctx context.Context, tenantID uuid.UUID) string
+}
+
+func (m *mockPlaybackDomainResolver) PlaybackBaseURL(ctx context.Context, tenantID uuid.UUID) string {
+	if m.playbackBaseURLFn != nil {
+		return m.playbackBaseURLFn(ctx, tenantID)
+	}
+	return ""
+}
+
+// mockAnalyticsEventSource provides a configurable mock for AnalyticsEventSource.
+type mockAnalyticsEventSource struct {
+	listEventsFn func(ctx context.Context, stream model.AnalyticsStream, from, to time.Time) ([]*model.AnalyticsEvent, error)
+}
+
+func (m *mockAnalyticsEventSource) ListEvents(ctx context.Context, stream model.AnalyticsStream, from, to time.Time) ([]*model.AnalyticsEvent, error) {
+	if m.listEventsFn != nil {
+		return m.listEventsFn(ctx, stream, from, to)
+	}
+	return nil, nil
+}
+
+// mockExportBookmarkRepository provides a configurable mock for ExportBookmarkRepository.
+type mockExportBookmarkRepository struct {
+	getFn     func(ctx context.Context, stream model.AnalyticsStream) (time.Time, error)
+	advanceFn func(ctx context.Context, stream model.AnalyticsStream, until time.Time) error
+}
+
+func (m *mockExportBookmarkRepository) Get(ctx context.Context, stream model.AnalyticsStream) (time.Time, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, stream)
+	}
+	return time.Time{}, nil
+}
+
+func (m *mockExportBookmarkRepository) Advance(ctx context.Context, stream model.AnalyticsStream, until time.Time) error {
+	if m.advanceFn != nil {
+		return m.advanceFn(ctx, stream, until)
+	}
+	return nil
+}
+
+// mockMetadataBackupRepository provides a configurable mock for MetadataBackupRepository.
+type mockMetadataBackupRepository struct {
+	dumpFn          func(ctx context.Context, create func(table string) (io.WriteCloser, error)) (*repository.MetadataSnapshot, error)
+	restoreFn       func(ctx context.Context, open func(table string) (io.ReadCloser, error)) (map[string]int64, error)
+	schemaVersionFn func(ctx context.Context) (int64, error)
+}
+
+func (m *mockMetadataBackupRepository) Dump(ctx context.Context, create func(table string) (io.WriteCloser, error)) (*repository.MetadataSnapshot, error) {
+	if m.dumpFn != nil {
+		return m.dumpFn(ctx, create)
+	}
+	return &repository.MetadataSnapshot{}, nil
+}
+
+func (m *mockMetadataBackupRepository) Restore(ctx context.Context, open func(table string) (io.ReadCloser, error)) (map[string]int64, error) {
+	if m.restoreFn != nil {
+		return m.restoreFn(ctx, open)
+	}
+	return map[string]int64{}, nil
+}
+
+func (m *mockMetadataBackupRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	if m.schemaVersionFn != nil {
+		return m.schemaVersionFn(ctx)
+	}
+	return 0, nil
+}
+
+// mockIssuedURLRepository provides a configurable mock for IssuedURLRepository.
+type mockIssuedURLRepository struct {
+	createFn              func(ctx context.Context, issued *model.IssuedURL) error
+	usageSinceFn          func(ctx context.Context, requesterID uuid.UUID, purpose model.URLPurpose, since time.Time) (repository.URLUsage, error)
+	deleteExpiredBeforeFn func(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+func (m *mockIssuedURLRepository) Create(ctx context.Context, issued *model.IssuedURL) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, issued)
+	}
+	return nil
+}
+
+func (m *mockIssuedURLRepository) UsageSince(ctx context.Context, requesterID uuid.UUID, purpose model.URLPurpose, since time.Time) (repository.URLUsage, error) {
+	if m.usageSinceFn != nil {
+		return m.usageSinceFn(ctx, requesterID, purpose, since)
+	}
+	return repository.URLUsage{}, nil
+}
+
+func (m *mockIssuedURLRepository) DeleteExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	if m.deleteExpiredBeforeFn != nil {
+		return m.deleteExpiredBeforeFn(ctx, cutoff)
+	}
+	return 0, nil
+}
+
+// mockURLIssuer provides a configurable mock for URLIssuer.
+type mockURLIssuer struct {
+	issueUploadURLFn      func(ctx context.Context, req URLRequest) (string, error)
+	issuePartUploadURLsFn func(ctx context.Context, req URLRequest, uploadID string, parts int) ([]string, error)
+	issueDownloadURLFn    func(ctx context.Context, req URLRequest) (string, error)
+}
+
+func (m *mockURLIssuer) IssueUploadURL(ctx context.Context, req URLRequest) (string, error) {
+	if m.issueUploadURLFn != nil {
+		return m.issueUploadURLFn(ctx, req)
+	}
+	return "", nil
+}
+
+func (m *mockURLIssuer) IssuePartUploadURLs(ctx context.Context, req URLRequest, uploadID string, parts int) ([]string, error) {
+	if m.issuePartUploadURLsFn != nil {
+		return m.issuePartUploadURLsFn(ctx, req, uploadID, parts)
+	}
+	return make([]string, parts), nil
+}
+
+func (m *mockURLIssuer) IssueDownloadURL(ctx context.Context, req URLRequest) (string, error) {
+	if m.issueDownloadURLFn != nil {
+		return m.issueDownloadURLFn(ctx, req)
+	}
+	return "", nil
+}
+
+func (m *mockURLIssuer) CleanupExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// mockPlaybackTokenService provides a configurable mock for PlaybackTokenService.
+type mockPlaybackTokenService struct {
+	validateTokenFn func(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error)
+}
+
+func (m *mockPlaybackTokenService) IssueToken(ctx context.Context, input IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+	return nil, nil
+}
+
+func (m *mockPlaybackTokenService) ValidateToken(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error) {
+	if m.validateTokenFn != nil {
+		return m.validateTokenFn(ctx, token, videoID)
+	}
+	return &model.PlaybackToken{Token: token, VideoID: videoID}, nil
+}
+
+func (m *mockPlaybackTokenService) RevokeToken(ctx context.Context, callerID uuid.UUID, token string) error {
+	return nil
+}
+
+func (m *mockPlaybackTokenService) RevokeVideoTokens(ctx context.Context, videoID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockPlaybackTokenService) RevokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+// mockMaintenanceStore provides a configurable mock for MaintenanceStore.
+type mockMaintenanceStore struct {
+	getFn   func(ctx context.Context) (*model.MaintenanceWindow, error)
+	setFn   func(ctx context.Context, window *model.MaintenanceWindow) error
+	clearFn func(ctx context.Context) error
+}
+
+func (m *mockMaintenanceStore) Get(ctx context.Context) (*model.MaintenanceWindow, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockMaintenanceStore) Set(ctx context.Context, window *model.MaintenanceWindow) error {
+	if m.setFn != nil {
+		return m.setFn(ctx, window)
+	}
+	return nil
+}
+
+func (m *mockMaintenanceStore) Clear(ctx context.Context) error {
+	if m.clearFn != nil {
+		return m.clearFn(ctx)
+	}
+	return nil
+}
+
+// mockArchiveRepository provides a configurable mock for ArchiveRepository.
+type mockArchiveRepository struct {
+	saveFn           func(ctx context.Context, archive *model.VideoArchive) error
+	getByVideoIDFn   func(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error)
+	requestRestoreFn func(ctx context.Context, videoID uuid.UUID, requestedAt, eta time.Time) (*model.VideoArchive, error)
+	claimNextFn      func(ctx context.Context, now, leaseUntil time.Time) (*model.VideoArchive, error)
+	markArchivedFn   func(ctx context.Context, videoID uuid.UUID, bytes int64, at time.Time) error
+	deleteFn         func(ctx context.Context, videoID uuid.UUID, createdAt time.Time) error
+}
+
+func (m *mockArchiveRepository) Save(ctx context.Context, archive *model.VideoArchive) error {
+	if m.saveFn != nil {
+		return m.saveFn(ctx, archive)
+	}
+	return nil
+}
+
+func (m *mockArchiveRepository) GetByVideoID(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	if m.getByVideoIDFn != nil {
+		return m.getByVideoIDFn(ctx, videoID)
+	}
+	return nil, repository.ErrArchiveNotFound
+}
+
+func (m *mockArchiveRepository) RequestRestore(ctx context.Context, videoID uuid.UUID, requestedAt, eta time.Time) (*model.VideoArchive, error) {
+	if m.requestRestoreFn != nil {
+		return m.requestRestoreFn(ctx, videoID, requestedAt, eta)
+	}
+	return nil, repository.ErrArchiveNotFound
+}
+
+func (m *mockArchiveRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*model.VideoArchive, error) {
+	if m.claimNextFn != nil {
+		return m.claimNextFn(ctx, now, leaseUntil)
+	}
+	return nil, repository.ErrArchiveNotFound
+}
+
+func (m *mockArchiveRepository) MarkArchived(ctx context.Context, videoID uuid.UUID, bytes int64, at time.Time) error {
+	if m.markArchivedFn != nil {
+		return m.markArchivedFn(ctx, videoID, bytes, at)
+	}
+	return nil
+}
+
+func (m *mockArchiveRepository) Delete(ctx context.Context, videoID uuid.UUID, createdAt time.Time) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, videoID, createdAt)
+	}
+	return nil
+}
+
+// mockArchiveService provides a configurable mock for ArchiveService.
+type mockArchiveService struct {
+	restoreFn func(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error)
+}
+
+func (m *mockArchiveService) Archive(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	return nil, nil
+}
+
+func (m *mockArchiveService) Restore(ctx context.Context, videoID uuid.UUID) (*model.VideoArchive, error) {
+	if m.restoreFn != nil {
+		return m.restoreFn(ctx, videoID)
+	}
+	return nil, nil
+}
+
+func (m *mockArchiveService) GetArchive(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoArchive, error) {
+	return nil, nil
+}
+
+// mockLockStore provides a configurable mock for cache.LockStore.
+type mockLockStore struct {
+	acquireFn func(ctx context.Context, name string, ttl time.Duration) (string, bool, error)
+	releaseFn func(ctx context.Context, name, token string) error
+}
+
+func (m *mockLockStore) Acquire(ctx context.Context, name string, ttl time.Duration) (string, bool, error) {
+	if m.acquireFn != nil {
+		return m.acquireFn(ctx, name, ttl)
+	}
+	return "token", true, nil
+}
+
+func (m *mockLockStore) Release(ctx context.Context, name, token string) error {
+	if m.releaseFn != nil {
+		return m.releaseFn(ctx, name, token)
+	}
+	return nil
+}
+
+// mockSubtitleRepository provides a configurable mock for SubtitleRepository.
+type mockSubtitleRepository struct {
+	saveFn          func(ctx context.Context, subtitle *model.Subtitle) error
+	listByVideoIDFn func(ctx context.Context, videoID uuid.UUID) ([]*model.Subtitle, error)
+}
+
+func (m *mockSubtitleRepository) Save(ctx context.Context, subtitle *model.Subtitle) error {
+	if m.saveFn != nil {
+		return m.saveFn(ctx, subtitle)
+	}
+	return nil
+}
+
+func (m *mockSubtitleRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]*model.Subtitle, error) {
+	if m.listByVideoIDFn != nil {
+		return m.listByVideoIDFn(ctx, videoID)
+	}
+	return []*model.Subtitle{}, nil
+}
+
+// mockEncryptionKeyRepository provides a configurable mock for EncryptionKeyRepository.
+type mockEncryptionKeyRepository struct {
+	getOrCreateFn  func(ctx context.Context, key *model.EncryptionKey) (*model.EncryptionKey, error)
+	getByVideoIDFn func(ctx context.Context, videoID uuid.UUID) (*model.EncryptionKey, error)
+}
+
+func (m *mockEncryptionKeyRepository) GetOrCreate(ctx context.Context, key *model.EncryptionKey) (*model.EncryptionKey, error) {
+	if m.getOrCreateFn != nil {
+		return m.getOrCreateFn(ctx, key)
+	}
+	return key, nil
+}
+
+func (m *mockEncryptionKeyRepository) GetByVideoID(ctx context.Context, videoID uuid.UUID) (*model.EncryptionKey, error) {
+	if m.getByVideoIDFn != nil {
+		return m.getByVideoIDFn(ctx, videoID)
+	}
+	return nil, repository.ErrEncryptionKeyNotFound
+}
+
+// mockOutputBucketRepository provides a configurable mock for OutputBucketRepository.
+type mockOutputBucketRepository struct {
+	saveFn        func(ctx context.Context, bucket *model.OutputBucket) error
+	getByTenantFn func(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error)
+	deleteFn      func(ctx context.Context, tenantID uuid.UUID) error
+}
+
+func (m *mockOutputBucketRepository) Save(ctx context.Context, bucket *model.OutputBucket) error {
+	if m.saveFn != nil {
+		return m.saveFn(ctx, bucket)
+	}
+	return nil
+}
+
+func (m *mockOutputBucketRepository) GetByTenant(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+	if m.getByTenantFn != nil {
+		return m.getByTenantFn(ctx, tenantID)
+	}
+	return nil, repository.ErrOutputBucketNotFound
+}
+
+func (m *mockOutputBucketRepository) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, tenantID)
+	}
+	return nil
+}
+
+// mockVideoStatusEventRepository provides a configurable mock for VideoStatusEventRepository.
+type mockVideoStatusEventRepository struct {
+	createFn        func(ctx context.Context, event *model.VideoStatusEvent) error
+	listByVideoIDFn func(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error)
+}
+
+func (m *mockVideoStatusEventRepository) Create(ctx context.Context, event *model.VideoStatusEvent) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, event)
+	}
+	return nil
+}
+
+func (m *mockVideoStatusEventRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+	if m.listByVideoIDFn != nil {
+		return m.listByVideoIDFn(ctx, videoID, limit)
+	}
+	return []*model.VideoStatusEvent{}, nil
+}
+
+// mockSecretStore provides a configurable mock for SecretStore.
+type mockSecretStore struct {
+	putFn    func(ctx context.Context, name string, value []byte) (string, error)
+	getFn    func(ctx context.Context, ref string) ([]byte, error)
+	deleteFn func(ctx context.Context, ref string) error
+}
+
+func (m *mockSecretStore) Put(ctx context.Context, name string, value []byte) (string, error) {
+	if m.putFn != nil {
+		return m.putFn(ctx, name, value)
+	}
+	return "ref:" + name, nil
+}
+
+func (m *mockSecretStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, ref)
+	}
+	return nil, repository.ErrSecretNotFound
+}
+
+func (m *mockSecretStore) Delete(ctx context.Context, ref string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, ref)
+	}
+	return nil
+}
+
+// mockOutputBucketConnector provides a configurable mock for OutputBucketConnector.
+type mockOutputBucketConnector struct {
+	connectFn func(ctx context.Context, bucket *model.OutputBucket, secretKey []byte) (repository.ObjectStorage, error)
+}
+
+func (m *mockOutputBucketConnector) Connect(ctx context.Context, bucket *model.OutputBucket, secretKey []byte) (repository.ObjectStorage, error) {
+	if m.connectFn != nil {
+		return m.connectFn(ctx, bucket, secretKey)
+	}
+	return &mockObjectStorage{}, nil
+}
+
+// mockOutputBucketResolver provides a configurable mock for OutputBucketResolver.
+type mockOutputBucketResolver struct {
+	outputStorageFn func(ctx context.Context, tenantID uuid.UUID) (repository.ObjectStorage, error)
+	outputBaseURLFn func(ctx context.Context, tenantID uuid.UUID) string
+}
+
+func (m *mockOutputBucketResolver) OutputStorage(ctx context.Context, tenantID uuid.UUID) (repository.ObjectStorage, error) {
+	if m.outputStorageFn != nil {
+		return m.outputStorageFn(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *mockOutputBucketResolver) OutputBaseURL(ctx context.Context, tenantID uuid.UUID) string {
+	if m.outputBaseURLFn != nil {
+		return m.outputBaseURLFn(ctx, tenantID)
+	}
+	return ""
+}
+
+// mockQueueMonitor provides a configurable mock for QueueMonitor.
+type mockQueueMonitor struct {
+	queueStatsFn func(ctx context.Context, name string) (*repository.QueueStats, error)
+}
+
+func (m *mockQueueMonitor) QueueStats(ctx context.Context, name string) (*repository.QueueStats, error) {
+	if m.queueStatsFn != nil {
+		return m.queueStatsFn(ctx, name)
+	}
+	return &repository.QueueStats{Name: name}, nil
+}
+
+// mockUserRepository is a mock implementation of repository.UserRepository.
+type mockUserRepository struct {
+	createFn  func(ctx context.Context, user *model.User) error
+	getByIDFn func(ctx context.Context, id uuid.UUID) (*model.User, error)
+}
+
+func (m *mockUserRepository) Create(ctx context.Context, user *model.User) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, user)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+// mockAPIKeyRepository is a mock implementation of repository.APIKeyRepository.
+type mockAPIKeyRepository struct {
+	createFn        func(ctx context.Context, key *model.APIKey) error
+	getByHashFn     func(ctx context.Context, hash string) (*model.APIKey, error)
+	listByUserFn    func(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error)
+	revokeFn        func(ctx context.Context, userID, keyID uuid.UUID, at time.Time) (*model.APIKey, error)
+	touchLastUsedFn func(ctx context.Context, keyID uuid.UUID, at time.Time) error
+}
+
+func (m *mockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, key)
+	}
+	return nil
+}
+
+func (m *mockAPIKeyRepository) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	if m.getByHashFn != nil {
+		return m.getByHashFn(ctx, hash)
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func (m *mockAPIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	if m.listByUserFn != nil {
+		return m.listByUserFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockAPIKeyRepository) Revoke(ctx context.Context, userID, keyID uuid.UUID, at time.Time) (*model.APIKey, error) {
+	if m.revokeFn != nil {
+		return m.revokeFn(ctx, userID, keyID, at)
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func (m *mockAPIKeyRepository) TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	if m.touchLastUsedFn != nil {
+		return m.touchLastUsedFn(ctx, keyID, at)
+	}
+	return nil
+}
+
+// mockVideoStatsRepository provides a configurable mock for VideoStatsRepository.
+type mockVideoStatsRepository struct {
+	addBatchFn func(ctx context.Context, deltas []*model.VideoStats) error
+	getFn      func(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error)
+}
+
+func (m *mockVideoStatsRepository) AddBatch(ctx context.Context, deltas []*model.VideoStats) error {
+	if m.addBatchFn != nil {
+		return m.addBatchFn(ctx, deltas)
+	}
+	return nil
+}
+
+func (m *mockVideoStatsRepository) Get(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, videoID)
+	}
+	return &model.VideoStats{VideoID: videoID}, nil
+}
+
+// mockViewCounter provides a configurable mock for ViewCounter.
+type mockViewCounter struct {
+	addFn      func(ctx context.Context, videoID uuid.UUID, views int64, lastViewedAt time.Time) error
+	pendingFn  func(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error)
+	popDirtyFn func(ctx context.Context, n int) ([]*model.VideoStats, bool, error)
+}
+
+func (m *mockViewCounter) Add(ctx context.Context, videoID uuid.UUID, views int64, lastViewedAt time.Time) error {
+	if m.addFn != nil {
+		return m.addFn(ctx, videoID, views, lastViewedAt)
+	}
+	return nil
+}
+
+func (m *mockViewCounter) Pending(ctx context.Context, videoID uuid.UUID) (*model.VideoStats, error) {
+	if m.pendingFn != nil {
+		return m.pendingFn(ctx, videoID)
+	}
+	return &model.VideoStats{VideoID: videoID}, nil
+}
+
+func (m *mockViewCounter) PopDirty(ctx context.Context, n int) ([]*model.VideoStats, bool, error) {
+	if m.popDirtyFn != nil {
+		return m.popDirtyFn(ctx, n)
+	}
+	return nil, true, nil
+}
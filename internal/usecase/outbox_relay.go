@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+const (
+	// outboxPublishGrace is how long a task written to the outbox is left to the request
+	// that wrote it before the relay may claim it. It covers the publish that follows the
+	// commit, so the relay only picks up tasks whose request failed or crashed.
+	outboxPublishGrace = 30 * time.Second
+
+	// outboxMaxRetryDelay caps the delay between publications of a task that keeps failing.
+	outboxMaxRetryDelay = 5 * time.Minute
+)
+
+// OutboxRelayConfig holds configuration for OutboxRelay.
+type OutboxRelayConfig struct {
+	// BatchSize caps the tasks published by one run.
+	BatchSize int
+	// Lease is how long claimed tasks are hidden from other relays while they are
+	// published; a relay that crashes mid-run leaves them to the next claim after it.
+	Lease time.Duration
+}
+
+// DefaultOutboxRelayConfig returns the default configuration.
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		BatchSize: 100,
+		Lease:     time.Minute,
+	}
+}
+
+// OutboxRelayResult summarizes a run of the OutboxRelay.
+type OutboxRelayResult struct {
+	Published int
+	Failed    int
+}
+
+// OutboxRelay publishes the transcode tasks left in the outbox, so a video moved to
+// PROCESSING always gets its task even if the request that moved it failed to publish.
+type OutboxRelay interface {
+	// Run claims up to BatchSize available tasks and publishes them. A task that fails to
+	// publish is retried by a later run after a backoff. Delivery is at least once: a
+	// task published just before its deletion fails is published again, which the worker
+	// handles like a redelivery.
+	Run(ctx context.Context) (*OutboxRelayResult, error)
+}
+
+type outboxRelay struct {
+	outbox repository.OutboxRepository
+	queue  repository.MessageQueue
+	cfg    OutboxRelayConfig
+	now    func() time.Time
+}
+
+// NewOutboxRelay creates a new OutboxRelay instance.
+func NewOutboxRelay(outbox repository.OutboxRepository, queue repository.MessageQueue, cfg OutboxRelayConfig) OutboxRelay {
+	return &outboxRelay{
+		outbox: outbox,
+		queue:  queue,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+func (r *outboxRelay) Run(ctx context.Context) (*OutboxRelayResult, error) {
+	now := r.now()
+	msgs, err := r.outbox.Claim(ctx, now, now.Add(r.cfg.Lease), r.cfg.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox tasks: %w", err)
+	}
+
+	result := &OutboxRelayResult{}
+	for _, msg := range msgs {
+		if publishOutboxMessage(ctx, r.outbox, r.queue, msg, r.now) {
+			result.Published++
+		} else {
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// publishOutboxMessage publishes the task of msg and deletes msg from the outbox, and
+// reports whether the task was published. A failed publication is released for a retry
+// after a delay that doubles with each attempt.
+func publishOutboxMessage(ctx context.Context, outbox repository.OutboxRepository, queue repository.MessageQueue, msg *repository.OutboxMessage, now func() time.Time) bool {
+	if err := queue.PublishTranscodeTask(ctx, msg.Task); err != nil {
+		metrics.OutboxPublishTotal.WithLabelValues("failed").Inc()
+		slog.WarnContext(ctx, "failed to publish outbox transcode task",
+			"video_id", msg.Task.VideoID,
+			"outbox_id", msg.ID,
+			"attempts", msg.Attempts+1,
+			"error", err,
+		)
+
+		retryAt := now().Add(outboxRetryDelay(msg.Attempts))
+		if err := outbox.Release(context.WithoutCancel(ctx), msg.ID, retryAt, err.Error()); err != nil {
+			// The claim lapses on its own, so the task is still retried
+			slog.WarnContext(ctx, "failed to release outbox transcode task",
+				"outbox_id", msg.ID,
+				"error", err,
+			)
+		}
+		return false
+	}
+	metrics.OutboxPublishTotal.WithLabelValues("published").Inc()
+
+	// A task that stays in the outbox is published again once it becomes available
+	if err := outbox.Delete(context.WithoutCancel(ctx), msg.ID); err != nil {
+		slog.WarnContext(ctx, "failed to delete published outbox transcode task",
+			"video_id", msg.Task.VideoID,
+			"outbox_id", msg.ID,
+			"error", err,
+		)
+	}
+	return true
+}
+
+// outboxRetryDelay returns the delay before retrying a task that failed to publish after
+// attempts earlier failures.
+func outboxRetryDelay(attempts int) time.Duration {
+	if attempts >= 9 {
+		return outboxMaxRetryDelay
+	}
+	return min(time.Second<<attempts, outboxMaxRetryDelay)
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestOutboxRelay_Run(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	failing := uuid.New()
+
+	tests := []struct {
+		name        string
+		claimErr    error
+		want        OutboxRelayResult
+		wantErr     bool
+		wantDeleted int
+		wantRetryAt time.Time
+	}{
+		{
+			name:        "publishes and deletes claimed tasks",
+			want:        OutboxRelayResult{Published: 2, Failed: 1},
+			wantDeleted: 2,
+			wantRetryAt: now.Add(4 * time.Second),
+		},
+		{
+			name:     "claim error",
+			claimErr: errors.New("connection refused"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := []*repository.OutboxMessage{
+				{ID: uuid.New(), Task: repository.TranscodeTask{VideoID: uuid.New()}},
+				{ID: uuid.New(), Task: repository.TranscodeTask{VideoID: failing}, Attempts: 2},
+				{ID: uuid.New(), Task: repository.TranscodeTask{VideoID: uuid.New()}},
+			}
+
+			var (
+				deleted int
+				retryAt time.Time
+			)
+			outbox := &mockOutboxRepository{
+				claimFn: func(ctx context.Context, claimNow, until time.Time, limit int) ([]*repository.OutboxMessage, error) {
+					if !claimNow.Equal(now) || !until.Equal(now.Add(time.Minute)) || limit != 100 {
+						t.Errorf("Claim(%v, %v, %d): unexpected arguments", claimNow, until, limit)
+					}
+					return msgs, tt.claimErr
+				},
+				deleteFn: func(ctx context.Context, id uuid.UUID) error {
+					deleted++
+					return nil
+				},
+				releaseFn: func(ctx context.Context, id uuid.UUID, at time.Time, lastErr string) error {
+					if id != msgs[1].ID || lastErr != "channel closed" {
+						t.Errorf("Release(%s, %q): unexpected message", id, lastErr)
+					}
+					retryAt = at
+					return nil
+				},
+			}
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					if task.VideoID == failing {
+						return errors.New("channel closed")
+					}
+					return nil
+				},
+			}
+
+			relay := NewOutboxRelay(outbox, queue, DefaultOutboxRelayConfig()).(*outboxRelay)
+			relay.now = func() time.Time { return now }
+
+			result, err := relay.Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *result != tt.want {
+				t.Errorf("Run() = %+v, want %+v", *result, tt.want)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted %d tasks, want %d", deleted, tt.wantDeleted)
+			}
+			if !retryAt.Equal(tt.wantRetryAt) {
+				t.Errorf("retry at %v, want %v", retryAt, tt.wantRetryAt)
+			}
+		})
+	}
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: time.Second},
+		{attempts: 3, want: 8 * time.Second},
+		{attempts: 8, want: 256 * time.Second},
+		{attempts: 9, want: outboxMaxRetryDelay},
+		{attempts: 100, want: outboxMaxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := outboxRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("outboxRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestVideoService_TriggerProcess_Outbox(t *testing.T) {
+	tests := []struct {
+		name        string
+		updateErr   error
+		publishErr  error
+		wantErr     bool
+		wantDeleted bool
+		wantRelease bool
+	}{
+		{
+			name:        "published task leaves the outbox",
+			wantDeleted: true,
+		},
+		{
+			name:        "failed publish is left to the relay",
+			publishErr:  errors.New("channel closed"),
+			wantRelease: true,
+		},
+		{
+			name:      "failed update publishes nothing",
+			updateErr: errors.New("connection refused"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Status:      model.StatusUploaded,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					t.Error("the video must be updated with its task")
+					return nil
+				},
+			}
+
+			var (
+				written           *repository.OutboxMessage
+				deleted, released bool
+				published         int
+			)
+			outbox := &mockOutboxRepository{
+				updateVideoWithTaskFn: func(ctx context.Context, v *model.Video, msg *repository.OutboxMessage, availableAt time.Time) error {
+					if v.Status != model.StatusProcessing {
+						t.Errorf("status: got %s, expected PROCESSING", v.Status)
+					}
+					if !availableAt.Equal(msg.CreatedAt.Add(outboxPublishGrace)) {
+						t.Errorf("available at %v, expected the publish grace after %v", availableAt, msg.CreatedAt)
+					}
+					written = msg
+					return tt.updateErr
+				},
+				deleteFn: func(ctx context.Context, id uuid.UUID) error {
+					deleted = id == written.ID
+					return nil
+				},
+				releaseFn: func(ctx context.Context, id uuid.UUID, retryAt time.Time, lastErr string) error {
+					released = id == written.ID
+					return nil
+				},
+			}
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					published++
+					if task.VideoID != video.ID {
+						t.Errorf("task video: got %s, expected %s", task.VideoID, video.ID)
+					}
+					return tt.publishErr
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, queue, nil, nil, nil, nil, nil, nil, nil, outbox, nil, DefaultVideoServiceConfig())
+			err := svc.TriggerProcess(context.Background(), video.ID, ProcessInput{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TriggerProcess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if published != 0 {
+					t.Error("a task must not be published without its update")
+				}
+				return
+			}
+			if published != 1 {
+				t.Errorf("published %d tasks, expected 1", published)
+			}
+			if deleted != tt.wantDeleted || released != tt.wantRelease {
+				t.Errorf("deleted %v, released %v; expected %v, %v", deleted, released, tt.wantDeleted, tt.wantRelease)
+			}
+		})
+	}
+}
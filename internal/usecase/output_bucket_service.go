@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// outputBucketProbePrefix is where health checks write their probe object; the probe is
+// deleted again, so a passing check leaves the bucket as it found it.
+const outputBucketProbePrefix = ".gostream-check/"
+
+var (
+	// ErrMissingBucketSecret is returned when an output bucket is saved without its secret key.
+	ErrMissingBucketSecret = errs.New(errs.Invalid, "bucket secret access key cannot be empty")
+
+	// ErrOutputBucketCheckFailed is returned when a bucket being saved cannot be written to.
+	// The returned error carries the storage error as detail.
+	ErrOutputBucketCheckFailed = errs.New(errs.Invalid, "output bucket health check failed")
+
+	// ErrExternalOutput is returned for operations on a video's output that only apply to
+	// output in platform storage, such as archiving.
+	ErrExternalOutput = errs.New(errs.Conflict, "video output is stored in the tenant's output bucket")
+)
+
+// ConfigureOutputBucketInput contains the input parameters for saving a tenant's output bucket.
+type ConfigureOutputBucketInput struct {
+	TenantID        uuid.UUID
+	Endpoint        string
+	Region          string
+	Bucket          string
+	UseSSL          bool
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicBaseURL   string
+}
+
+// OutputBucketConnector opens object storage for a tenant's output bucket.
+type OutputBucketConnector interface {
+	// Connect returns storage backed by bucket, authenticated with secretKey.
+	Connect(ctx context.Context, bucket *model.OutputBucket, secretKey []byte) (repository.ObjectStorage, error)
+}
+
+// OutputBucketResolver picks where a tenant's transcode output is stored and served from.
+type OutputBucketResolver interface {
+	// OutputStorage returns storage for the tenant's output bucket, or nil if the tenant
+	// has none. An error means the tenant has a bucket that cannot be opened right now.
+	OutputStorage(ctx context.Context, tenantID uuid.UUID) (repository.ObjectStorage, error)
+
+	// OutputBaseURL returns the public base URL of the tenant's output bucket, or an empty
+	// string if the tenant has none.
+	OutputBaseURL(ctx context.Context, tenantID uuid.UUID) string
+}
+
+// OutputBucketService defines the interface for managing the buckets tenants bring for
+// their transcode output.
+type OutputBucketService interface {
+	OutputBucketResolver
+
+	// Configure validates the bucket, checks that it can be written to with the given
+	// credentials, and saves it, replacing the tenant's previous bucket. Returns
+	// ErrOutputBucketCheckFailed if the check fails; nothing is saved then.
+	Configure(ctx context.Context, input ConfigureOutputBucketInput) (*model.OutputBucket, error)
+
+	// Get returns the tenant's output bucket.
+	// Returns repository.ErrOutputBucketNotFound if the tenant has none.
+	Get(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error)
+
+	// Check re-runs the health check of the tenant's saved bucket and records its outcome.
+	// A failing bucket is returned with CheckError set, not as an error.
+	Check(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error)
+
+	// Delete removes the tenant's output bucket and its secret; new output is written to
+	// platform storage again.
+	Delete(ctx context.Context, tenantID uuid.UUID) error
+}
+
+// OutputBucketServiceConfig holds configuration for OutputBucketService.
+type OutputBucketServiceConfig struct {
+	// ResolveCacheTTL is how long a tenant's bucket is reused before it is looked up again.
+	// Configuration changes reach workers and playback URLs on other instances within it.
+	ResolveCacheTTL time.Duration
+	// CheckTimeout bounds each health check.
+	CheckTimeout time.Duration
+}
+
+// DefaultOutputBucketServiceConfig returns the default configuration.
+func DefaultOutputBucketServiceConfig() OutputBucketServiceConfig {
+	return OutputBucketServiceConfig{
+		ResolveCacheTTL: time.Minute,
+		CheckTimeout:    10 * time.Second,
+	}
+}
+
+// resolvedBucket is a cached tenant bucket; a nil bucket caches the absence of one. The
+// storage is opened on first use.
+type resolvedBucket struct {
+	bucket    *model.OutputBucket
+	storage   repository.ObjectStorage
+	expiresAt time.Time
+}
+
+type outputBucketService struct {
+	repo      repository.OutputBucketRepository
+	secrets   repository.SecretStore
+	connector OutputBucketConnector
+
+	resolveCacheTTL time.Duration
+	checkTimeout    time.Duration
+
+	mu       sync.Mutex
+	resolved map[uuid.UUID]resolvedBucket
+}
+
+// NewOutputBucketService creates a new OutputBucketService instance.
+func NewOutputBucketService(
+	repo repository.OutputBucketRepository,
+	secrets repository.SecretStore,
+	connector OutputBucketConnector,
+	cfg OutputBucketServiceConfig,
+) OutputBucketService {
+	return &outputBucketService{
+		repo:            repo,
+		secrets:         secrets,
+		connector:       connector,
+		resolveCacheTTL: cfg.ResolveCacheTTL,
+		checkTimeout:    cfg.CheckTimeout,
+		resolved:        make(map[uuid.UUID]resolvedBucket),
+	}
+}
+
+// Configure checks the bucket before its secret is stored, so a rejected configuration
+// leaves nothing behind. The secret of the replaced bucket is deleted once the new one is saved.
+func (s *outputBucketService) Configure(ctx context.Context, input ConfigureOutputBucketInput) (*model.OutputBucket, error) {
+	bucket, err := model.NewOutputBucket(input.TenantID, input.Endpoint, input.Region, input.Bucket, input.AccessKeyID, input.PublicBaseURL, input.UseSSL)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(input.SecretAccessKey) == "" {
+		return nil, ErrMissingBucketSecret
+	}
+	secret := []byte(input.SecretAccessKey)
+
+	if err := s.check(ctx, bucket, secret); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOutputBucketCheckFailed, err)
+	}
+	bucket.RecordCheck(time.Now(), nil)
+
+	previous, err := s.repo.GetByTenant(ctx, input.TenantID)
+	switch {
+	case err == nil:
+		bucket.CreatedAt = previous.CreatedAt
+	case errors.Is(err, repository.ErrOutputBucketNotFound):
+	default:
+		return nil, fmt.Errorf("get output bucket: %w", err)
+	}
+
+	bucket.SecretRef, err = s.secrets.Put(ctx, "output-bucket/"+input.TenantID.String(), secret)
+	if err != nil {
+		return nil, fmt.Errorf("store bucket secret: %w", err)
+	}
+	if err := s.repo.Save(ctx, bucket); err != nil {
+		s.deleteSecret(ctx, input.TenantID, bucket.SecretRef)
+		return nil, fmt.Errorf("save output bucket: %w", err)
+	}
+	if previous != nil && previous.SecretRef != bucket.SecretRef {
+		s.deleteSecret(ctx, input.TenantID, previous.SecretRef)
+	}
+
+	s.forget(input.TenantID)
+	return bucket, nil
+}
+
+// Get returns the tenant's output bucket.
+func (s *outputBucketService) Get(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+	return s.repo.GetByTenant(ctx, tenantID)
+}
+
+// Check runs the health check with the stored secret and saves its outcome.
+func (s *outputBucketService) Check(ctx context.Context, tenantID uuid.UUID) (*model.OutputBucket, error) {
+	bucket, err := s.repo.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := s.secrets.Get(ctx, bucket.SecretRef)
+	if err != nil {
+		return nil, fmt.Errorf("get bucket secret: %w", err)
+	}
+
+	bucket.RecordCheck(time.Now(), s.check(ctx, bucket, secret))
+	if err := s.repo.Save(ctx, bucket); err != nil {
+		return nil, fmt.Errorf("save output bucket: %w", err)
+	}
+
+	s.forget(tenantID)
+	return bucket, nil
+}
+
+// Delete removes the tenant's bucket, then its secret.
+func (s *outputBucketService) Delete(ctx context.Context, tenantID uuid.UUID) error {
+	bucket, err := s.repo.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, tenantID); err != nil {
+		return err
+	}
+	s.deleteSecret(ctx, tenantID, bucket.SecretRef)
+
+	s.forget(tenantID)
+	return nil
+}
+
+// OutputStorage opens the tenant's bucket with its stored secret. Unlike playback URLs,
+// failures are returned rather than falling back: output must never land in platform
+// storage while the tenant expects it in its own bucket.
+func (s *outputBucketService) OutputStorage(ctx context.Context, tenantID uuid.UUID) (repository.ObjectStorage, error) {
+	resolved, err := s.resolve(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.bucket == nil {
+		return nil, nil
+	}
+	if resolved.storage != nil {
+		return resolved.storage, nil
+	}
+
+	secret, err := s.secrets.Get(ctx, resolved.bucket.SecretRef)
+	if err != nil {
+		return nil, fmt.Errorf("get bucket secret: %w", err)
+	}
+	storage, err := s.connector.Connect(ctx, resolved.bucket, secret)
+	if err != nil {
+		return nil, fmt.Errorf("connect to output bucket: %w", err)
+	}
+
+	s.mu.Lock()
+	if cached, ok := s.resolved[tenantID]; ok && cached.bucket == resolved.bucket {
+		cached.storage = storage
+		s.resolved[tenantID] = cached
+	}
+	s.mu.Unlock()
+
+	return storage, nil
+}
+
+// OutputBaseURL returns the bucket's public base URL.
+// Lookup failures are logged and return an empty string, like PlaybackBaseURL.
+func (s *outputBucketService) OutputBaseURL(ctx context.Context, tenantID uuid.UUID) string {
+	resolved, err := s.resolve(ctx, tenantID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to resolve tenant output bucket",
+			slog.String("tenant_id", tenantID.String()),
+			slog.String("error", err.Error()),
+		)
+		return ""
+	}
+	if resolved.bucket == nil {
+		return ""
+	}
+	return resolved.bucket.PublicBaseURL
+}
+
+// resolve returns the tenant's cached bucket, looking it up once the entry expires.
+func (s *outputBucketService) resolve(ctx context.Context, tenantID uuid.UUID) (resolvedBucket, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.resolved[tenantID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	bucket, err := s.repo.GetByTenant(ctx, tenantID)
+	if err != nil && !errors.Is(err, repository.ErrOutputBucketNotFound) {
+		return resolvedBucket{}, fmt.Errorf("get output bucket: %w", err)
+	}
+
+	resolved := resolvedBucket{bucket: bucket, expiresAt: now.Add(s.resolveCacheTTL)}
+	s.mu.Lock()
+	s.resolved[tenantID] = resolved
+	s.mu.Unlock()
+
+	return resolved, nil
+}
+
+// check connects to the bucket and writes, reads back and deletes a probe object.
+func (s *outputBucketService) check(ctx context.Context, bucket *model.OutputBucket, secret []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+	defer cancel()
+
+	storage, err := s.connector.Connect(ctx, bucket, secret)
+	if err != nil {
+		return err
+	}
+
+	key := outputBucketProbePrefix + uuid.NewString()
+	if err := storage.Upload(ctx, key, strings.NewReader("ok"), "text/plain"); err != nil {
+		return fmt.Errorf("write probe object: %w", err)
+	}
+	if _, err := storage.Stat(ctx, key); err != nil {
+		return fmt.Errorf("read probe object: %w", err)
+	}
+	if err := storage.Delete(ctx, key); err != nil {
+		return fmt.Errorf("delete probe object: %w", err)
+	}
+	return nil
+}
+
+// deleteSecret removes a secret that is no longer referenced. A failure only leaves an
+// orphaned secret behind, so it is logged rather than returned.
+func (s *outputBucketService) deleteSecret(ctx context.Context, tenantID uuid.UUID, ref string) {
+	if err := s.secrets.Delete(ctx, ref); err != nil {
+		slog.WarnContext(ctx, "failed to delete output bucket secret",
+			slog.String("tenant_id", tenantID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (s *outputBucketService) forget(tenantID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.resolved, tenantID)
+	s.mu.Unlock()
+}
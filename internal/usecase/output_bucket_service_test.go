@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestOutputBucketService_Configure(t *testing.T) {
+	tenantID := uuid.New()
+	input := ConfigureOutputBucketInput{
+		TenantID:        tenantID,
+		Endpoint:        "s3.eu-west-1.amazonaws.com",
+		Region:          "eu-west-1",
+		Bucket:          "acme-videos",
+		UseSSL:          true,
+		AccessKeyID:     "AKIAEXAMPLE",
+		SecretAccessKey: "secret",
+		PublicBaseURL:   "https://cdn.acme.example",
+	}
+	createdAt := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name            string
+		input           func() ConfigureOutputBucketInput
+		previous        *model.OutputBucket
+		uploadErr       error
+		saveErr         error
+		wantErr         error
+		wantPut         bool
+		wantDeletedRefs []string
+	}{
+		{
+			name:    "new bucket",
+			wantPut: true,
+		},
+		{
+			name:            "replaces previous bucket and its secret",
+			previous:        &model.OutputBucket{TenantID: tenantID, SecretRef: "ref:old", CreatedAt: createdAt},
+			wantPut:         true,
+			wantDeletedRefs: []string{"ref:old"},
+		},
+		{
+			name:      "failing check saves nothing",
+			uploadErr: errors.New("Access Denied"),
+			wantErr:   ErrOutputBucketCheckFailed,
+		},
+		{
+			name:            "save failure deletes the new secret",
+			saveErr:         errors.New("connection refused"),
+			wantErr:         errors.New("save output bucket"),
+			wantPut:         true,
+			wantDeletedRefs: []string{"ref:new"},
+		},
+		{
+			name: "invalid bucket name",
+			input: func() ConfigureOutputBucketInput {
+				in := input
+				in.Bucket = "Acme"
+				return in
+			},
+			wantErr: model.ErrInvalidBucketName,
+		},
+		{
+			name: "missing secret",
+			input: func() ConfigureOutputBucketInput {
+				in := input
+				in.SecretAccessKey = ""
+				return in
+			},
+			wantErr: ErrMissingBucketSecret,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *model.OutputBucket
+			repo := &mockOutputBucketRepository{
+				getByTenantFn: func(ctx context.Context, id uuid.UUID) (*model.OutputBucket, error) {
+					if tt.previous == nil {
+						return nil, repository.ErrOutputBucketNotFound
+					}
+					return tt.previous, nil
+				},
+				saveFn: func(ctx context.Context, bucket *model.OutputBucket) error {
+					saved = bucket
+					return tt.saveErr
+				},
+			}
+			var put bool
+			var deletedRefs []string
+			secrets := &mockSecretStore{
+				putFn: func(ctx context.Context, name string, value []byte) (string, error) {
+					put = true
+					if string(value) != input.SecretAccessKey {
+						t.Errorf("stored secret %q, want %q", value, input.SecretAccessKey)
+					}
+					return "ref:new", nil
+				},
+				deleteFn: func(ctx context.Context, ref string) error {
+					deletedRefs = append(deletedRefs, ref)
+					return nil
+				},
+			}
+			var probed []string
+			connector := &mockOutputBucketConnector{
+				connectFn: func(ctx context.Context, bucket *model.OutputBucket, secretKey []byte) (repository.ObjectStorage, error) {
+					return &mockObjectStorage{
+						uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+							probed = append(probed, key)
+							return tt.uploadErr
+						},
+					}, nil
+				},
+			}
+
+			in := input
+			if tt.input != nil {
+				in = tt.input()
+			}
+			svc := NewOutputBucketService(repo, secrets, connector, DefaultOutputBucketServiceConfig())
+			got, err := svc.Configure(context.Background(), in)
+
+			if put != tt.wantPut {
+				t.Errorf("secret stored = %v, want %v", put, tt.wantPut)
+			}
+			if len(deletedRefs) != len(tt.wantDeletedRefs) || (len(deletedRefs) > 0 && deletedRefs[0] != tt.wantDeletedRefs[0]) {
+				t.Errorf("deleted secrets = %v, want %v", deletedRefs, tt.wantDeletedRefs)
+			}
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error())) {
+					t.Fatalf("Configure() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Configure() unexpected error: %v", err)
+			}
+
+			if len(probed) != 1 {
+				t.Errorf("probe objects written = %v, want one", probed)
+			}
+			if saved != got || got.SecretRef != "ref:new" || !got.IsHealthy() {
+				t.Errorf("saved %+v, returned %+v", saved, got)
+			}
+			if tt.previous != nil && !got.CreatedAt.Equal(createdAt) {
+				t.Errorf("CreatedAt = %v, want previous %v", got.CreatedAt, createdAt)
+			}
+		})
+	}
+}
+
+func TestOutputBucketService_Check(t *testing.T) {
+	tenantID := uuid.New()
+	bucket := &model.OutputBucket{TenantID: tenantID, SecretRef: "ref:current"}
+	bucket.RecordCheck(time.Now().Add(-time.Hour), nil)
+
+	repo := &mockOutputBucketRepository{
+		getByTenantFn: func(ctx context.Context, id uuid.UUID) (*model.OutputBucket, error) {
+			return bucket, nil
+		},
+	}
+	secrets := &mockSecretStore{
+		getFn: func(ctx context.Context, ref string) ([]byte, error) {
+			return []byte("secret"), nil
+		},
+	}
+	connector := &mockOutputBucketConnector{
+		connectFn: func(ctx context.Context, b *model.OutputBucket, secretKey []byte) (repository.ObjectStorage, error) {
+			return nil, errors.New("The AWS Access Key Id you provided does not exist")
+		},
+	}
+
+	svc := NewOutputBucketService(repo, secrets, connector, DefaultOutputBucketServiceConfig())
+	got, err := svc.Check(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+	if got.IsHealthy() || got.CheckError == "" {
+		t.Errorf("Check() = healthy %v, error %q; want the failure recorded", got.IsHealthy(), got.CheckError)
+	}
+}
+
+func TestOutputBucketService_OutputStorage(t *testing.T) {
+	tenantID := uuid.New()
+
+	t.Run("no bucket", func(t *testing.T) {
+		svc := NewOutputBucketService(&mockOutputBucketRepository{}, &mockSecretStore{}, &mockOutputBucketConnector{}, DefaultOutputBucketServiceConfig())
+		storage, err := svc.OutputStorage(context.Background(), tenantID)
+		if err != nil || storage != nil {
+			t.Errorf("OutputStorage() = %v, %v; want nil, nil", storage, err)
+		}
+		if base := svc.OutputBaseURL(context.Background(), tenantID); base != "" {
+			t.Errorf("OutputBaseURL() = %q, want empty", base)
+		}
+	})
+
+	t.Run("bucket is looked up and connected once", func(t *testing.T) {
+		lookups, connects := 0, 0
+		repo := &mockOutputBucketRepository{
+			getByTenantFn: func(ctx context.Context, id uuid.UUID) (*model.OutputBucket, error) {
+				lookups++
+				return &model.OutputBucket{TenantID: id, SecretRef: "ref:current", PublicBaseURL: "https://cdn.acme.example"}, nil
+			},
+		}
+		secrets := &mockSecretStore{
+			getFn: func(ctx context.Context, ref string) ([]byte, error) {
+				return []byte("secret"), nil
+			},
+		}
+		connector := &mockOutputBucketConnector{
+			connectFn: func(ctx context.Context, bucket *model.OutputBucket, secretKey []byte) (repository.ObjectStorage, error) {
+				connects++
+				if string(secretKey) != "secret" {
+					t.Errorf("connected with secret %q", secretKey)
+				}
+				return &mockObjectStorage{}, nil
+			},
+		}
+
+		svc := NewOutputBucketService(repo, secrets, connector, DefaultOutputBucketServiceConfig())
+		for range 2 {
+			storage, err := svc.OutputStorage(context.Background(), tenantID)
+			if err != nil || storage == nil {
+				t.Fatalf("OutputStorage() = %v, %v", storage, err)
+			}
+		}
+		if base := svc.OutputBaseURL(context.Background(), tenantID); base != "https://cdn.acme.example" {
+			t.Errorf("OutputBaseURL() = %q", base)
+		}
+		if lookups != 1 || connects != 1 {
+			t.Errorf("lookups = %d, connects = %d; want 1 each", lookups, connects)
+		}
+	})
+
+	t.Run("unreadable secret is an error", func(t *testing.T) {
+		repo := &mockOutputBucketRepository{
+			getByTenantFn: func(ctx context.Context, id uuid.UUID) (*model.OutputBucket, error) {
+				return &model.OutputBucket{TenantID: id, SecretRef: "ref:lost"}, nil
+			},
+		}
+		svc := NewOutputBucketService(repo, &mockSecretStore{}, &mockOutputBucketConnector{}, DefaultOutputBucketServiceConfig())
+		if _, err := svc.OutputStorage(context.Background(), tenantID); !errors.Is(err, repository.ErrSecretNotFound) {
+			t.Errorf("OutputStorage() error = %v, want %v", err, repository.ErrSecretNotFound)
+		}
+	})
+}
+
+func TestOutputBucketService_Delete(t *testing.T) {
+	tenantID := uuid.New()
+	repo := &mockOutputBucketRepository{
+		getByTenantFn: func(ctx context.Context, id uuid.UUID) (*model.OutputBucket, error) {
+			return &model.OutputBucket{TenantID: id, SecretRef: "ref:current"}, nil
+		},
+	}
+	var deletedRef string
+	secrets := &mockSecretStore{
+		deleteFn: func(ctx context.Context, ref string) error {
+			deletedRef = ref
+			return nil
+		},
+	}
+
+	svc := NewOutputBucketService(repo, secrets, &mockOutputBucketConnector{}, DefaultOutputBucketServiceConfig())
+	if err := svc.Delete(context.Background(), tenantID); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if deletedRef != "ref:current" {
+		t.Errorf("deleted secret %q, want ref:current", deletedRef)
+	}
+}
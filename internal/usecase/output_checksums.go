@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// OutputChecksumsFile is the name of the checksum manifest stored next to each output
+// version's master playlist or DASH manifest.
+const OutputChecksumsFile = "checksums.json"
+
+// ErrOutputChecksumsNotFound is returned when a video's output has no checksum manifest,
+// e.g. because it was transcoded before manifests were written.
+var ErrOutputChecksumsNotFound = errors.New("output has no checksum manifest")
+
+// Reasons an output file fails verification.
+const (
+	ChecksumFailureMissing  = "missing"
+	ChecksumFailureSize     = "size_mismatch"
+	ChecksumFailureChecksum = "checksum_mismatch"
+)
+
+// OutputChecksums is the checksum manifest of one output version. Files are keyed by
+// full storage key, so the manifest also covers the preview, which has its own prefix.
+type OutputChecksums struct {
+	Algorithm string                    `json:"algorithm"`
+	CreatedAt time.Time                 `json:"created_at"`
+	Files     map[string]OutputChecksum `json:"files"`
+}
+
+// OutputChecksum describes one uploaded file.
+type OutputChecksum struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// OutputVerification is the result of checking a video's stored output against its manifest.
+type OutputVerification struct {
+	VideoID       uuid.UUID
+	OutputVersion int64
+	// Checked is the number of files listed in the manifest.
+	Checked  int
+	Failures []OutputChecksumFailure
+}
+
+// OK reports whether every file matched the manifest.
+func (v *OutputVerification) OK() bool {
+	return len(v.Failures) == 0
+}
+
+// OutputChecksumFailure is a file that does not match the manifest.
+type OutputChecksumFailure struct {
+	Key string
+	// Reason is one of the ChecksumFailure* constants.
+	Reason string
+}
+
+func newOutputChecksums() *OutputChecksums {
+	return &OutputChecksums{
+		Algorithm: "sha256",
+		CreatedAt: time.Now().UTC(),
+		Files:     make(map[string]OutputChecksum),
+	}
+}
+
+func (c *OutputChecksums) add(key string, size int64, sum []byte) {
+	c.Files[key] = OutputChecksum{Size: size, SHA256: hex.EncodeToString(sum)}
+}
+
+// outputChecksumsKey returns the checksum manifest key for the output whose master
+// playlist or DASH manifest is manifestKey.
+func outputChecksumsKey(manifestKey string) string {
+	return path.Dir(manifestKey) + "/" + OutputChecksumsFile
+}
+
+// verifyOutput re-reads every file listed in the manifest of the video's published
+// output and compares its size and SHA-256. Mismatches are reported as failures;
+// only errors that prevent verification are returned.
+func verifyOutput(ctx context.Context, storage repository.ObjectStorage, video *model.Video) (*OutputVerification, error) {
+	manifestKey := video.HLSURL
+	if manifestKey == "" {
+		manifestKey = video.DashURL
+	}
+	sums, err := readOutputChecksums(ctx, storage, outputChecksumsKey(manifestKey))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(sums.Files))
+	for key := range sums.Files {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	result := &OutputVerification{
+		VideoID:       video.ID,
+		OutputVersion: video.OutputVersion,
+		Checked:       len(keys),
+	}
+	for _, key := range keys {
+		reason, err := verifyOutputFile(ctx, storage, key, sums.Files[key])
+		if err != nil {
+			return nil, fmt.Errorf("verify %s: %w", key, err)
+		}
+		if reason != "" {
+			result.Failures = append(result.Failures, OutputChecksumFailure{Key: key, Reason: reason})
+		}
+	}
+
+	return result, nil
+}
+
+// readOutputChecksums downloads and decodes a checksum manifest.
+func readOutputChecksums(ctx context.Context, storage repository.ObjectStorage, key string) (*OutputChecksums, error) {
+	if _, err := storage.Stat(ctx, key); err != nil {
+		if errors.Is(err, repository.ErrObjectNotFound) {
+			return nil, ErrOutputChecksumsNotFound
+		}
+		return nil, fmt.Errorf("stat checksum manifest: %w", err)
+	}
+
+	reader, err := storage.Download(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("download checksum manifest: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	var sums OutputChecksums
+	if err := json.NewDecoder(reader).Decode(&sums); err != nil {
+		return nil, fmt.Errorf("decode checksum manifest: %w", err)
+	}
+	return &sums, nil
+}
+
+// verifyOutputFile returns the failure reason for one file, or "" if it matches.
+// The size is checked first so a truncated object is reported without reading it.
+func verifyOutputFile(ctx context.Context, storage repository.ObjectStorage, key string, want OutputChecksum) (string, error) {
+	info, err := storage.Stat(ctx, key)
+	if errors.Is(err, repository.ErrObjectNotFound) {
+		return ChecksumFailureMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.Size != want.Size {
+		return ChecksumFailureSize, nil
+	}
+
+	reader, err := storage.Download(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = reader.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != want.SHA256 {
+		return ChecksumFailureChecksum, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+// memoryStorage returns a mockObjectStorage backed by objects.
+func memoryStorage(objects map[string][]byte) *mockObjectStorage {
+	return &mockObjectStorage{
+		uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			objects[key] = data
+			return nil
+		},
+		downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			data, ok := objects[key]
+			if !ok {
+				return nil, repository.ErrObjectNotFound
+			}
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+		statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+			data, ok := objects[key]
+			if !ok {
+				return nil, repository.ErrObjectNotFound
+			}
+			return &repository.ObjectInfo{Key: key, Size: int64(len(data))}, nil
+		},
+	}
+}
+
+func TestOutputChecksums_WrittenAndVerified(t *testing.T) {
+	ctx := context.Background()
+	videoID := uuid.New()
+	prefix := "hls/" + videoID.String() + "/v1/"
+
+	video := &model.Video{
+		ID:          videoID,
+		UserID:      uuid.New(),
+		Status:      model.StatusProcessing,
+		OriginalURL: "originals/" + videoID.String() + "/video.mp4",
+	}
+	repo := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return video, nil
+		},
+		updateFn: func(ctx context.Context, v *model.Video) error {
+			video = v
+			return nil
+		},
+	}
+
+	objects := map[string][]byte{video.OriginalURL: []byte("fake video data")}
+	storage := memoryStorage(objects)
+
+	tc := &mockTranscoder{
+		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+			masterPath := filepath.Join(outputDir, "master.m3u8")
+			mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
+
+			variantDir := filepath.Join(outputDir, "720p")
+			if err := os.MkdirAll(variantDir, 0755); err != nil {
+				return nil, err
+			}
+			manifestPath := filepath.Join(variantDir, "playlist.m3u8")
+			segmentPath := filepath.Join(variantDir, "segment_000.ts")
+			mustWriteFile(t, manifestPath, []byte("#EXTM3U\n"))
+			mustWriteFile(t, segmentPath, []byte("mock segment data"))
+
+			return &transcoder.ABROutput{
+				MasterManifestPath: masterPath,
+				Variants: []transcoder.VariantOutput{{
+					Variant:      transcoder.Variant{Name: "720p"},
+					ManifestPath: manifestPath,
+					SegmentPaths: []string{segmentPath},
+				}},
+			}, nil
+		},
+	}
+
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{
+		TempDir:    t.TempDir(),
+		MaxRetries: 3,
+	})
+	task := repository.TranscodeTask{
+		VideoID:       videoID,
+		OriginalKey:   video.OriginalURL,
+		OutputKey:     prefix,
+		OutputVersion: 1,
+	}
+	if err := svc.ProcessTask(ctx, task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var sums OutputChecksums
+	if err := json.Unmarshal(objects[prefix+OutputChecksumsFile], &sums); err != nil {
+		t.Fatalf("decode checksum manifest: %v", err)
+	}
+	wantKeys := []string{prefix + "master.m3u8", prefix + "720p/playlist.m3u8", prefix + "720p/segment_000.ts"}
+	if len(sums.Files) != len(wantKeys) {
+		t.Errorf("manifest files: got %d, expected %d", len(sums.Files), len(wantKeys))
+	}
+	for _, key := range wantKeys {
+		if _, ok := sums.Files[key]; !ok {
+			t.Errorf("manifest is missing %s", key)
+		}
+	}
+	if sums.Algorithm != "sha256" {
+		t.Errorf("algorithm: got %q, expected sha256", sums.Algorithm)
+	}
+
+	admin := NewAdminService(repo, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, storage, nil, nil, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+
+	result, err := admin.VerifyOutput(ctx, videoID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.OK() || result.Checked != len(wantKeys) || result.OutputVersion != 1 {
+		t.Fatalf("unexpected verification of intact output: %+v", result)
+	}
+
+	// Tamper with the stored output
+	objects[prefix+"master.m3u8"] = []byte("#EXTM3X\n")
+	objects[prefix+"720p/playlist.m3u8"] = []byte("#EXT")
+	delete(objects, prefix+"720p/segment_000.ts")
+
+	result, err = admin.VerifyOutput(ctx, videoID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []OutputChecksumFailure{
+		{Key: prefix + "720p/playlist.m3u8", Reason: ChecksumFailureSize},
+		{Key: prefix + "720p/segment_000.ts", Reason: ChecksumFailureMissing},
+		{Key: prefix + "master.m3u8", Reason: ChecksumFailureChecksum},
+	}
+	if len(result.Failures) != len(want) {
+		t.Fatalf("failures: got %+v, expected %+v", result.Failures, want)
+	}
+	for i := range want {
+		if result.Failures[i] != want[i] {
+			t.Errorf("failure %d: got %+v, expected %+v", i, result.Failures[i], want[i])
+		}
+	}
+}
+
+func TestAdminService_VerifyOutput(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name    string
+		video   *model.Video
+		getErr  error
+		wantErr error
+	}{
+		{
+			name:    "video not found",
+			getErr:  repository.ErrVideoNotFound,
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "video not ready",
+			video:   &model.Video{ID: videoID, Status: model.StatusProcessing},
+			wantErr: ErrVideoNotReady,
+		},
+		{
+			name:    "no checksum manifest",
+			video:   &model.Video{ID: videoID, Status: model.StatusReady, HLSURL: "hls/x/master.m3u8", UpdatedAt: time.Now()},
+			wantErr: ErrOutputChecksumsNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return tt.video, nil
+				},
+			}
+
+			svc := NewAdminService(videos, &mockTranscodeJobRepository{}, &mockRenditionRepository{}, memoryStorage(map[string][]byte{}), nil, nil, nil, nil, nil, nil, nil, DefaultAdminServiceConfig())
+			_, err := svc.VerifyOutput(context.Background(), videoID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
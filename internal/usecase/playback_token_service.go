@@ -0,0 +1,393 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+const (
+	// playbackTokenBytes is the entropy of an opaque playback token (256 bits).
+	playbackTokenBytes = 32
+)
+
+var (
+	// ErrInvalidPlaybackToken is returned when a token is unknown, expired, revoked,
+	// or does not grant access to the requested video.
+	// A single error is used for all cases to avoid leaking why validation failed.
+	ErrInvalidPlaybackToken = errors.New("invalid playback token")
+
+	// ErrPlaybackTokenNotFound is returned when revoking a token that does not exist or
+	// was issued to another user.
+	ErrPlaybackTokenNotFound = errors.New("playback token not found")
+
+	// ErrVideoNotReady is returned when playback is requested for a video that is not READY.
+	ErrVideoNotReady = errors.New("video is not ready for playback")
+
+	// ErrStreamLimitExceeded is returned when a user already has the maximum number of active streams.
+	ErrStreamLimitExceeded = errors.New("concurrent stream limit exceeded")
+
+	// ErrNotEntitled is returned when the user has not purchased or subscribed to the video.
+	ErrNotEntitled = errors.New("user is not entitled to this video")
+)
+
+// PlaybackTokenServiceConfig holds configuration for PlaybackTokenService.
+type PlaybackTokenServiceConfig struct {
+	// TokenTTL is the lifetime of issued playback tokens.
+	// Short TTLs limit the usefulness of leaked links.
+	TokenTTL time.Duration
+	// MaxConcurrentStreams is the default number of simultaneous streams per user.
+	// Zero disables stream limiting.
+	MaxConcurrentStreams int
+	// PlanStreamLimits overrides MaxConcurrentStreams per subscription plan, which is
+	// read from the user's record.
+	PlanStreamLimits map[string]int
+	// SessionTimeout is how long a stream stays active without a heartbeat
+	// (token validation from the manifest/segment proxy).
+	SessionTimeout time.Duration
+}
+
+// DefaultPlaybackTokenServiceConfig returns the default configuration.
+func DefaultPlaybackTokenServiceConfig() PlaybackTokenServiceConfig {
+	return PlaybackTokenServiceConfig{
+		TokenTTL:             5 * time.Minute,
+		MaxConcurrentStreams: 3,
+		SessionTimeout:       time.Minute,
+	}
+}
+
+// IssuePlaybackTokenInput contains the input parameters for issuing a playback token.
+type IssuePlaybackTokenInput struct {
+	VideoID uuid.UUID
+	// UserID is the authenticated caller, never a client-supplied ID: the token is
+	// issued to it, and the video's visibility, entitlements and plan are checked
+	// against it.
+	UserID uuid.UUID
+}
+
+// PlaybackTokenService defines the interface for issuing and validating playback tokens.
+type PlaybackTokenService interface {
+	// IssueToken creates a short-lived token authorizing a user to stream a video.
+	// Each token is a playback session counted against the user's concurrent stream limit.
+	// Returns ErrVideoNotReady if the video cannot be streamed yet, ErrNotEntitled if the
+	// user lacks access, and ErrStreamLimitExceeded if the user has too many active streams.
+	// A private video is reported as repository.ErrVideoNotFound to anyone but its owner.
+	// For an ARCHIVED video it requests a restore and returns a *VideoArchivedError with
+	// the restore ETA.
+	IssueToken(ctx context.Context, input IssuePlaybackTokenInput) (*model.PlaybackToken, error)
+
+	// ValidateToken checks that token is live, unrevoked, and grants access to videoID.
+	// A successful validation also acts as a session heartbeat.
+	// Returns ErrInvalidPlaybackToken on any validation failure.
+	ValidateToken(ctx context.Context, token string, videoID uuid.UUID) (*model.PlaybackToken, error)
+
+	// RevokeToken invalidates a single token issued to callerID.
+	// Returns ErrPlaybackTokenNotFound if the token does not exist or belongs to another user.
+	RevokeToken(ctx context.Context, callerID uuid.UUID, token string) error
+
+	// RevokeVideoTokens invalidates every token issued so far for a video.
+	RevokeVideoTokens(ctx context.Context, videoID uuid.UUID) error
+
+	// RevokeUserTokens invalidates every token issued so far for a user.
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+type playbackTokenService struct {
+	repo         repository.VideoRepository
+	store        cache.PlaybackTokenStore
+	sessions     cache.StreamSessionStore
+	entitlements repository.EntitlementChecker
+	archives     ArchiveService
+	users        repository.UserRepository
+
+	tokenTTL             time.Duration
+	maxConcurrentStreams int
+	planStreamLimits     map[string]int
+	sessionTimeout       time.Duration
+}
+
+// NewPlaybackTokenService creates a new PlaybackTokenService instance.
+// The sessions parameter is optional - pass nil to disable concurrent stream limiting.
+// The entitlements parameter is optional - pass nil to allow any user to stream any READY video.
+// The archives parameter is optional - pass nil to treat ARCHIVED videos as not ready.
+// The users parameter is optional - pass nil to hold every user to the default stream limit.
+func NewPlaybackTokenService(
+	repo repository.VideoRepository,
+	store cache.PlaybackTokenStore,
+	sessions cache.StreamSessionStore,
+	entitlements repository.EntitlementChecker,
+	archives ArchiveService,
+	users repository.UserRepository,
+	cfg PlaybackTokenServiceConfig,
+) PlaybackTokenService {
+	return &playbackTokenService{
+		repo:                 repo,
+		store:                store,
+		sessions:             sessions,
+		entitlements:         entitlements,
+		archives:             archives,
+		users:                users,
+		tokenTTL:             cfg.TokenTTL,
+		maxConcurrentStreams: cfg.MaxConcurrentStreams,
+		planStreamLimits:     cfg.PlanStreamLimits,
+		sessionTimeout:       cfg.SessionTimeout,
+	}
+}
+
+// IssueToken creates and persists a new opaque playback token.
+func (s *playbackTokenService) IssueToken(ctx context.Context, input IssuePlaybackTokenInput) (*model.PlaybackToken, error) {
+	if input.UserID == uuid.Nil {
+		return nil, model.ErrInvalidUserID
+	}
+
+	video, err := s.repo.GetByID(ctx, input.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	if !video.VisibleTo(input.UserID) {
+		// Private videos are reported as missing, so their IDs cannot be probed
+		return nil, repository.ErrVideoNotFound
+	}
+
+	if video.IsArchived() && s.archives != nil {
+		return nil, s.requestRestore(ctx, video, input.UserID)
+	}
+
+	if !video.IsReady() {
+		return nil, ErrVideoNotReady
+	}
+
+	if err := checkEntitlement(ctx, s.entitlements, video, input.UserID); err != nil {
+		return nil, err
+	}
+
+	value, err := generateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+
+	now := time.Now()
+	token := &model.PlaybackToken{
+		Token:     value,
+		VideoID:   input.VideoID,
+		UserID:    input.UserID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.tokenTTL),
+	}
+
+	if err := s.acquireSession(ctx, token); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Save(ctx, token); err != nil {
+		s.releaseSession(ctx, token)
+		return nil, fmt.Errorf("save token: %w", err)
+	}
+
+	return token, nil
+}
+
+// requestRestore restores an archived video for a viewer entitled to it, so restores
+// cannot be triggered for videos the caller could not watch anyway.
+func (s *playbackTokenService) requestRestore(ctx context.Context, video *model.Video, userID uuid.UUID) error {
+	if err := checkEntitlement(ctx, s.entitlements, video, userID); err != nil {
+		return err
+	}
+
+	archive, err := s.archives.Restore(ctx, video.ID)
+	if err != nil {
+		return fmt.Errorf("request restore: %w", err)
+	}
+	return &VideoArchivedError{RestoreETA: *archive.RestoreETA}
+}
+
+// ValidateToken looks up the token and checks expiry, video binding, and revocation markers.
+func (s *playbackTokenService) ValidateToken(ctx context.Context, value string, videoID uuid.UUID) (*model.PlaybackToken, error) {
+	if value == "" {
+		return nil, ErrInvalidPlaybackToken
+	}
+
+	token, err := s.store.Get(ctx, value)
+	if err != nil {
+		return nil, fmt.Errorf("get token: %w", err)
+	}
+
+	if token == nil || token.IsExpired(time.Now()) || token.VideoID != videoID {
+		return nil, ErrInvalidPlaybackToken
+	}
+
+	videoRevokedAt, userRevokedAt, err := s.store.RevokedAt(ctx, token.VideoID, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get revocations: %w", err)
+	}
+
+	if token.IssuedBefore(videoRevokedAt) || token.IssuedBefore(userRevokedAt) {
+		return nil, ErrInvalidPlaybackToken
+	}
+
+	s.touchSession(ctx, token)
+
+	return token, nil
+}
+
+// RevokeToken deletes a single token and ends its playback session. Tokens of other
+// users are reported as missing, so callers cannot probe for them.
+func (s *playbackTokenService) RevokeToken(ctx context.Context, callerID uuid.UUID, value string) error {
+	token, err := s.store.Get(ctx, value)
+	if err != nil {
+		return fmt.Errorf("get token: %w", err)
+	}
+	if token == nil || token.UserID != callerID {
+		return ErrPlaybackTokenNotFound
+	}
+	s.releaseSession(ctx, token)
+
+	if err := s.store.Delete(ctx, value); err != nil {
+		return fmt.Errorf("delete token: %w", err)
+	}
+	return nil
+}
+
+// RevokeVideoTokens records a revocation marker for the video.
+// The marker only needs to outlive the longest token that could predate it.
+func (s *playbackTokenService) RevokeVideoTokens(ctx context.Context, videoID uuid.UUID) error {
+	if err := s.store.RevokeVideo(ctx, videoID, time.Now(), s.tokenTTL); err != nil {
+		return fmt.Errorf("revoke video tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserTokens records a revocation marker for the user and frees all of their stream slots.
+// Video-level revocation does not free slots; those sessions lapse after SessionTimeout
+// because their heartbeats start failing validation.
+func (s *playbackTokenService) RevokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	if err := s.store.RevokeUser(ctx, userID, time.Now(), s.tokenTTL); err != nil {
+		return fmt.Errorf("revoke user tokens: %w", err)
+	}
+
+	if s.sessions != nil {
+		if err := s.sessions.ReleaseAll(ctx, userID); err != nil {
+			return fmt.Errorf("release user sessions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkEntitlement verifies the user may stream the video; nil entitlements allow anyone.
+// Entitlement is only checked at issuance: tokens are short-lived, so a lapsed
+// subscription stops playback within TokenTTL without a provider call per segment.
+// Uploaders always have access to their own videos.
+func checkEntitlement(ctx context.Context, entitlements repository.EntitlementChecker, video *model.Video, userID uuid.UUID) error {
+	if entitlements == nil || video.UserID == userID {
+		return nil
+	}
+
+	entitled, err := entitlements.IsEntitled(ctx, userID, video.ID)
+	if err != nil {
+		return fmt.Errorf("check entitlement: %w", err)
+	}
+	if !entitled {
+		return ErrNotEntitled
+	}
+
+	return nil
+}
+
+// streamLimit returns the concurrent stream limit for a plan.
+func (s *playbackTokenService) streamLimit(plan string) int {
+	if limit, ok := s.planStreamLimits[plan]; ok {
+		return limit
+	}
+	return s.maxConcurrentStreams
+}
+
+// userPlan returns the plan on the user's record. Users without a record, such as the
+// owners of videos created before users existed, have no plan.
+func (s *playbackTokenService) userPlan(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.users == nil || len(s.planStreamLimits) == 0 {
+		return "", nil
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get user plan: %w", err)
+	}
+	return user.Plan, nil
+}
+
+// acquireSession claims a stream slot for the token, using the token itself as the session ID.
+// The limit comes from the plan on the user's record, never from the client.
+func (s *playbackTokenService) acquireSession(ctx context.Context, token *model.PlaybackToken) error {
+	if s.sessions == nil {
+		return nil
+	}
+	plan, err := s.userPlan(ctx, token.UserID)
+	if err != nil {
+		return err
+	}
+	limit := s.streamLimit(plan)
+	if limit <= 0 {
+		return nil
+	}
+
+	ok, err := s.sessions.Acquire(ctx, token.UserID, token.Token, limit, token.IssuedAt, s.sessionTimeout)
+	if err != nil {
+		return fmt.Errorf("acquire stream session: %w", err)
+	}
+	if !ok {
+		return ErrStreamLimitExceeded
+	}
+
+	return nil
+}
+
+// touchSession records a heartbeat for the token's session.
+// Errors are logged but not propagated - a missed heartbeat only risks the slot lapsing early.
+func (s *playbackTokenService) touchSession(ctx context.Context, token *model.PlaybackToken) {
+	if s.sessions == nil {
+		return
+	}
+
+	if err := s.sessions.Touch(ctx, token.UserID, token.Token, time.Now(), s.sessionTimeout); err != nil {
+		slog.WarnContext(ctx, "failed to refresh stream session",
+			"user_id", token.UserID,
+			"error", err,
+		)
+	}
+}
+
+// releaseSession frees the token's stream slot.
+// Errors are logged but not propagated - the slot lapses after SessionTimeout anyway.
+func (s *playbackTokenService) releaseSession(ctx context.Context, token *model.PlaybackToken) {
+	if s.sessions == nil {
+		return
+	}
+
+	if err := s.sessions.Release(ctx, token.UserID, token.Token); err != nil {
+		slog.WarnContext(ctx, "failed to release stream session",
+			"user_id", token.UserID,
+			"error", err,
+		)
+	}
+}
+
+// generateOpaqueToken returns a URL-safe random token suitable for query strings.
+func generateOpaqueToken() (string, error) {
+	buf := make([]byte, playbackTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
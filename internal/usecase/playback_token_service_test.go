@@ -0,0 +1,729 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestPlaybackTokenService_IssueToken(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		video   *model.Video
+		repoErr error
+		saveErr error
+		wantErr error
+	}{
+		{
+			name:   "successful issue",
+			userID: userID,
+			video:  &model.Video{ID: videoID, Status: model.StatusReady},
+		},
+		{
+			name:    "nil user ID",
+			userID:  uuid.Nil,
+			wantErr: model.ErrInvalidUserID,
+		},
+		{
+			name:    "video not found",
+			userID:  userID,
+			repoErr: repository.ErrVideoNotFound,
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "video not ready",
+			userID:  userID,
+			video:   &model.Video{ID: videoID, Status: model.StatusProcessing},
+			wantErr: ErrVideoNotReady,
+		},
+		{
+			name:   "private video for owner",
+			userID: userID,
+			video:  &model.Video{ID: videoID, UserID: userID, Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+		},
+		{
+			name:    "private video for another user",
+			userID:  userID,
+			video:   &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "archived video without archive tier",
+			userID:  userID,
+			video:   &model.Video{ID: videoID, Status: model.StatusArchived},
+			wantErr: ErrVideoNotReady,
+		},
+		{
+			name:    "store error",
+			userID:  userID,
+			video:   &model.Video{ID: videoID, Status: model.StatusReady},
+			saveErr: errors.New("redis down"),
+			wantErr: errors.New("save token"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return tt.video, tt.repoErr
+				},
+			}
+
+			var saved *model.PlaybackToken
+			store := &mockPlaybackTokenStore{
+				saveFn: func(ctx context.Context, token *model.PlaybackToken) error {
+					saved = token
+					return tt.saveErr
+				},
+			}
+
+			svc := NewPlaybackTokenService(repo, store, nil, nil, nil, nil, PlaybackTokenServiceConfig{TokenTTL: time.Minute})
+			token, err := svc.IssueToken(context.Background(), IssuePlaybackTokenInput{VideoID: videoID, UserID: tt.userID})
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error %v, got nil", tt.wantErr)
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if token.Token == "" {
+				t.Error("expected non-empty token")
+			}
+			if saved == nil || saved.Token != token.Token {
+				t.Error("expected token to be persisted")
+			}
+			if got := token.ExpiresAt.Sub(token.IssuedAt); got != time.Minute {
+				t.Errorf("token lifetime: got %v, expected %v", got, time.Minute)
+			}
+		})
+	}
+}
+
+func TestPlaybackTokenService_IssueToken_Unique(t *testing.T) {
+	repo := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return &model.Video{ID: id, Status: model.StatusReady}, nil
+		},
+	}
+	svc := NewPlaybackTokenService(repo, &mockPlaybackTokenStore{}, nil, nil, nil, nil, DefaultPlaybackTokenServiceConfig())
+
+	first, err := svc.IssueToken(context.Background(), IssuePlaybackTokenInput{VideoID: uuid.New(), UserID: uuid.New()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := svc.IssueToken(context.Background(), IssuePlaybackTokenInput{VideoID: uuid.New(), UserID: uuid.New()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first.Token == second.Token {
+		t.Error("expected distinct tokens")
+	}
+}
+
+func TestPlaybackTokenService_IssueToken_Entitlement(t *testing.T) {
+	videoID := uuid.New()
+	ownerID := uuid.New()
+	viewerID := uuid.New()
+
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		entitled    bool
+		checkErr    error
+		wantErr     error
+		wantChecked bool
+	}{
+		{
+			name:        "entitled viewer",
+			userID:      viewerID,
+			entitled:    true,
+			wantChecked: true,
+		},
+		{
+			name:        "viewer without entitlement",
+			userID:      viewerID,
+			entitled:    false,
+			wantErr:     ErrNotEntitled,
+			wantChecked: true,
+		},
+		{
+			name:        "provider error denies access",
+			userID:      viewerID,
+			checkErr:    errors.New("billing unavailable"),
+			wantErr:     errors.New("check entitlement"),
+			wantChecked: true,
+		},
+		{
+			name:   "owner bypasses check",
+			userID: ownerID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, UserID: ownerID, Status: model.StatusReady}, nil
+				},
+			}
+
+			checked := false
+			entitlements := &mockEntitlementChecker{
+				isEntitledFn: func(ctx context.Context, userID, vid uuid.UUID) (bool, error) {
+					checked = true
+					if userID != tt.userID || vid != videoID {
+						t.Errorf("unexpected check for user %s video %s", userID, vid)
+					}
+					return tt.entitled, tt.checkErr
+				},
+			}
+
+			saved := false
+			store := &mockPlaybackTokenStore{
+				saveFn: func(ctx context.Context, token *model.PlaybackToken) error {
+					saved = true
+					return nil
+				},
+			}
+
+			svc := NewPlaybackTokenService(repo, store, nil, entitlements, nil, nil, DefaultPlaybackTokenServiceConfig())
+			_, err := svc.IssueToken(context.Background(), IssuePlaybackTokenInput{VideoID: videoID, UserID: tt.userID})
+
+			if checked != tt.wantChecked {
+				t.Errorf("entitlement checked: got %v, expected %v", checked, tt.wantChecked)
+			}
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error %v, got nil", tt.wantErr)
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if saved {
+					t.Error("expected no token to be persisted")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !saved {
+				t.Error("expected token to be persisted")
+			}
+		})
+	}
+}
+
+func TestPlaybackTokenService_IssueToken_Archived(t *testing.T) {
+	videoID := uuid.New()
+	ownerID := uuid.New()
+	eta := time.Now().Add(15 * time.Minute)
+
+	tests := []struct {
+		name        string
+		entitled    bool
+		restoreErr  error
+		wantRestore bool
+		wantErr     error
+	}{
+		{
+			name:        "entitled viewer triggers restore",
+			entitled:    true,
+			wantRestore: true,
+			wantErr:     ErrVideoArchived,
+		},
+		{
+			name:     "unentitled viewer does not",
+			entitled: false,
+			wantErr:  ErrNotEntitled,
+		},
+		{
+			name:        "restore error",
+			entitled:    true,
+			restoreErr:  errors.New("connection refused"),
+			wantRestore: true,
+			wantErr:     errors.New("request restore"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, UserID: ownerID, Status: model.StatusArchived}, nil
+				},
+			}
+			entitlements := &mockEntitlementChecker{
+				isEntitledFn: func(ctx context.Context, userID, vid uuid.UUID) (bool, error) {
+					return tt.entitled, nil
+				},
+			}
+
+			restored := false
+			archives := &mockArchiveService{
+				restoreFn: func(ctx context.Context, id uuid.UUID) (*model.VideoArchive, error) {
+					restored = true
+					if tt.restoreErr != nil {
+						return nil, tt.restoreErr
+					}
+					return &model.VideoArchive{VideoID: id, RestoreETA: &eta}, nil
+				},
+			}
+
+			saved := false
+			store := &mockPlaybackTokenStore{
+				saveFn: func(ctx context.Context, token *model.PlaybackToken) error {
+					saved = true
+					return nil
+				},
+			}
+
+			svc := NewPlaybackTokenService(repo, store, nil, entitlements, archives, nil, DefaultPlaybackTokenServiceConfig())
+			_, err := svc.IssueToken(context.Background(), IssuePlaybackTokenInput{VideoID: videoID, UserID: uuid.New()})
+
+			if restored != tt.wantRestore {
+				t.Errorf("restore requested: got %v, expected %v", restored, tt.wantRestore)
+			}
+			if saved {
+				t.Error("expected no token to be persisted")
+			}
+			if !errors.Is(err, tt.wantErr) && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			var archivedErr *VideoArchivedError
+			if errors.As(err, &archivedErr) && !archivedErr.RestoreETA.Equal(eta) {
+				t.Errorf("restore ETA: got %v, expected %v", archivedErr.RestoreETA, eta)
+			}
+		})
+	}
+}
+
+func TestPlaybackTokenService_ValidateToken(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+	issuedAt := time.Now().Add(-time.Minute)
+
+	validToken := func() *model.PlaybackToken {
+		return &model.PlaybackToken{
+			Token:     "valid",
+			VideoID:   videoID,
+			UserID:    userID,
+			IssuedAt:  issuedAt,
+			ExpiresAt: issuedAt.Add(5 * time.Minute),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		videoID        uuid.UUID
+		stored         *model.PlaybackToken
+		videoRevokedAt time.Time
+		userRevokedAt  time.Time
+		wantErr        error
+	}{
+		{
+			name:    "valid token",
+			token:   "valid",
+			videoID: videoID,
+			stored:  validToken(),
+		},
+		{
+			name:    "empty token",
+			token:   "",
+			videoID: videoID,
+			wantErr: ErrInvalidPlaybackToken,
+		},
+		{
+			name:    "unknown token",
+			token:   "unknown",
+			videoID: videoID,
+			wantErr: ErrInvalidPlaybackToken,
+		},
+		{
+			name:    "expired token",
+			token:   "valid",
+			videoID: videoID,
+			stored: func() *model.PlaybackToken {
+				tok := validToken()
+				tok.ExpiresAt = time.Now().Add(-time.Second)
+				return tok
+			}(),
+			wantErr: ErrInvalidPlaybackToken,
+		},
+		{
+			name:    "token for another video",
+			token:   "valid",
+			videoID: uuid.New(),
+			stored:  validToken(),
+			wantErr: ErrInvalidPlaybackToken,
+		},
+		{
+			name:           "video tokens revoked",
+			token:          "valid",
+			videoID:        videoID,
+			stored:         validToken(),
+			videoRevokedAt: issuedAt.Add(time.Second),
+			wantErr:        ErrInvalidPlaybackToken,
+		},
+		{
+			name:          "user tokens revoked",
+			token:         "valid",
+			videoID:       videoID,
+			stored:        validToken(),
+			userRevokedAt: issuedAt,
+			wantErr:       ErrInvalidPlaybackToken,
+		},
+		{
+			name:           "revocation predates token",
+			token:          "valid",
+			videoID:        videoID,
+			stored:         validToken(),
+			videoRevokedAt: issuedAt.Add(-time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockPlaybackTokenStore{
+				getFn: func(ctx context.Context, token string) (*model.PlaybackToken, error) {
+					if tt.stored != nil && token == tt.stored.Token {
+						return tt.stored, nil
+					}
+					return nil, nil
+				},
+				revokedAtFn: func(ctx context.Context, videoID, userID uuid.UUID) (time.Time, time.Time, error) {
+					return tt.videoRevokedAt, tt.userRevokedAt, nil
+				},
+			}
+
+			svc := NewPlaybackTokenService(&mockVideoRepository{}, store, nil, nil, nil, nil, DefaultPlaybackTokenServiceConfig())
+			got, err := svc.ValidateToken(context.Background(), tt.token, tt.videoID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.UserID != userID {
+				t.Errorf("UserID: got %v, expected %v", got.UserID, userID)
+			}
+		})
+	}
+}
+
+func TestPlaybackTokenService_Revoke(t *testing.T) {
+	ttl := 2 * time.Minute
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	var revokedVideo, revokedUser uuid.UUID
+	var videoTTL, userTTL time.Duration
+	var deleted string
+
+	store := &mockPlaybackTokenStore{
+		getFn: func(ctx context.Context, value string) (*model.PlaybackToken, error) {
+			if value != "stolen" {
+				return nil, nil
+			}
+			return &model.PlaybackToken{Token: value, VideoID: videoID, UserID: userID}, nil
+		},
+		deleteFn: func(ctx context.Context, token string) error {
+			deleted = token
+			return nil
+		},
+		revokeVideoFn: func(ctx context.Context, id uuid.UUID, at time.Time, ttl time.Duration) error {
+			revokedVideo, videoTTL = id, ttl
+			return nil
+		},
+		revokeUserFn: func(ctx context.Context, id uuid.UUID, at time.Time, ttl time.Duration) error {
+			revokedUser, userTTL = id, ttl
+			return nil
+		},
+	}
+
+	svc := NewPlaybackTokenService(&mockVideoRepository{}, store, nil, nil, nil, nil, PlaybackTokenServiceConfig{TokenTTL: ttl})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		caller uuid.UUID
+		token  string
+	}{
+		{caller: uuid.New(), token: "stolen"},
+		{caller: userID, token: "unknown"},
+	} {
+		if err := svc.RevokeToken(ctx, tc.caller, tc.token); !errors.Is(err, ErrPlaybackTokenNotFound) {
+			t.Errorf("RevokeToken(%v, %q): expected ErrPlaybackTokenNotFound, got %v", tc.caller, tc.token, err)
+		}
+	}
+	if deleted != "" {
+		t.Fatalf("deleted token %q of another user", deleted)
+	}
+
+	if err := svc.RevokeToken(ctx, userID, "stolen"); err != nil {
+		t.Fatalf("RevokeToken failed: %v", err)
+	}
+	if err := svc.RevokeVideoTokens(ctx, videoID); err != nil {
+		t.Fatalf("RevokeVideoTokens failed: %v", err)
+	}
+	if err := svc.RevokeUserTokens(ctx, userID); err != nil {
+		t.Fatalf("RevokeUserTokens failed: %v", err)
+	}
+
+	if deleted != "stolen" {
+		t.Errorf("deleted token: got %q, expected %q", deleted, "stolen")
+	}
+	if revokedVideo != videoID || videoTTL != ttl {
+		t.Errorf("video revocation: got %v/%v, expected %v/%v", revokedVideo, videoTTL, videoID, ttl)
+	}
+	if revokedUser != userID || userTTL != ttl {
+		t.Errorf("user revocation: got %v/%v, expected %v/%v", revokedUser, userTTL, userID, ttl)
+	}
+}
+
+func TestPlaybackTokenService_IssueToken_StreamLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		user       *model.User
+		userErr    error
+		admit      bool
+		acquireErr error
+		wantLimit  int
+		wantErr    error
+	}{
+		{
+			name:      "admitted under default limit",
+			user:      &model.User{},
+			admit:     true,
+			wantLimit: 2,
+		},
+		{
+			name:      "plan override from the user record",
+			user:      &model.User{Plan: "premium"},
+			admit:     true,
+			wantLimit: 4,
+		},
+		{
+			name:      "user without a record",
+			userErr:   repository.ErrUserNotFound,
+			admit:     true,
+			wantLimit: 2,
+		},
+		{
+			name:    "user lookup error",
+			userErr: errors.New("connection refused"),
+			wantErr: errors.New("get user plan"),
+		},
+		{
+			name:      "limit reached",
+			user:      &model.User{},
+			admit:     false,
+			wantLimit: 2,
+			wantErr:   ErrStreamLimitExceeded,
+		},
+		{
+			name:       "session store error",
+			user:       &model.User{},
+			acquireErr: errors.New("redis down"),
+			wantLimit:  2,
+			wantErr:    errors.New("acquire stream session"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, Status: model.StatusReady}, nil
+				},
+			}
+
+			var gotLimit int
+			sessions := &mockStreamSessionStore{
+				acquireFn: func(ctx context.Context, userID uuid.UUID, sessionID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+					gotLimit = limit
+					return tt.admit, tt.acquireErr
+				},
+			}
+
+			saved := false
+			store := &mockPlaybackTokenStore{
+				saveFn: func(ctx context.Context, token *model.PlaybackToken) error {
+					saved = true
+					return nil
+				},
+			}
+
+			userID := uuid.New()
+			users := &mockUserRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.User, error) {
+					if id != userID {
+						t.Errorf("plan of %s, expected the caller %s", id, userID)
+					}
+					return tt.user, tt.userErr
+				},
+			}
+
+			cfg := DefaultPlaybackTokenServiceConfig()
+			cfg.MaxConcurrentStreams = 2
+			cfg.PlanStreamLimits = map[string]int{"premium": 4}
+			svc := NewPlaybackTokenService(repo, store, sessions, nil, nil, users, cfg)
+
+			_, err := svc.IssueToken(context.Background(), IssuePlaybackTokenInput{
+				VideoID: uuid.New(),
+				UserID:  userID,
+			})
+
+			if gotLimit != tt.wantLimit {
+				t.Errorf("limit: got %d, expected %d", gotLimit, tt.wantLimit)
+			}
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error %v, got nil", tt.wantErr)
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if saved {
+					t.Error("token should not be saved when the session is rejected")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !saved {
+				t.Error("expected token to be saved")
+			}
+		})
+	}
+}
+
+func TestPlaybackTokenService_IssueToken_ReleasesSessionOnSaveFailure(t *testing.T) {
+	repo := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return &model.Video{ID: id, Status: model.StatusReady}, nil
+		},
+	}
+
+	var acquired, released string
+	sessions := &mockStreamSessionStore{
+		acquireFn: func(ctx context.Context, userID uuid.UUID, sessionID string, limit int, now time.Time, timeout time.Duration) (bool, error) {
+			acquired = sessionID
+			return true, nil
+		},
+		releaseFn: func(ctx context.Context, userID uuid.UUID, sessionID string) error {
+			released = sessionID
+			return nil
+		},
+	}
+	store := &mockPlaybackTokenStore{
+		saveFn: func(ctx context.Context, token *model.PlaybackToken) error {
+			return errors.New("redis down")
+		},
+	}
+
+	svc := NewPlaybackTokenService(repo, store, sessions, nil, nil, nil, DefaultPlaybackTokenServiceConfig())
+	if _, err := svc.IssueToken(context.Background(), IssuePlaybackTokenInput{VideoID: uuid.New(), UserID: uuid.New()}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if acquired == "" || released != acquired {
+		t.Errorf("expected acquired session %q to be released, released %q", acquired, released)
+	}
+}
+
+func TestPlaybackTokenService_SessionLifecycle(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+	token := &model.PlaybackToken{
+		Token:     "session-token",
+		VideoID:   videoID,
+		UserID:    userID,
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	var touched, released string
+	var touchTimeout time.Duration
+	var releasedAll uuid.UUID
+	sessions := &mockStreamSessionStore{
+		touchFn: func(ctx context.Context, userID uuid.UUID, sessionID string, now time.Time, timeout time.Duration) error {
+			touched = sessionID
+			touchTimeout = timeout
+			return nil
+		},
+		releaseFn: func(ctx context.Context, userID uuid.UUID, sessionID string) error {
+			released = sessionID
+			return nil
+		},
+		releaseAllFn: func(ctx context.Context, id uuid.UUID) error {
+			releasedAll = id
+			return nil
+		},
+	}
+	store := &mockPlaybackTokenStore{
+		getFn: func(ctx context.Context, value string) (*model.PlaybackToken, error) {
+			if value == token.Token {
+				return token, nil
+			}
+			return nil, nil
+		},
+	}
+
+	svc := NewPlaybackTokenService(&mockVideoRepository{}, store, sessions, nil, nil, nil, DefaultPlaybackTokenServiceConfig())
+	ctx := context.Background()
+
+	if _, err := svc.ValidateToken(ctx, token.Token, videoID); err != nil {
+		t.Fatalf("ValidateToken failed: %v", err)
+	}
+	if touched != token.Token {
+		t.Errorf("expected heartbeat for %q, got %q", token.Token, touched)
+	}
+	if want := DefaultPlaybackTokenServiceConfig().SessionTimeout; touchTimeout != want {
+		t.Errorf("heartbeat timeout: got %v, expected %v", touchTimeout, want)
+	}
+
+	if err := svc.RevokeToken(ctx, userID, token.Token); err != nil {
+		t.Fatalf("RevokeToken failed: %v", err)
+	}
+	if released != token.Token {
+		t.Errorf("expected session %q released, got %q", token.Token, released)
+	}
+
+	if err := svc.RevokeUserTokens(ctx, userID); err != nil {
+		t.Fatalf("RevokeUserTokens failed: %v", err)
+	}
+	if releasedAll != userID {
+		t.Errorf("expected all sessions released for %v, got %v", userID, releasedAll)
+	}
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var (
+	// ErrNotPlaylistOwner is returned when a user changes a playlist they do not own.
+	ErrNotPlaylistOwner = errors.New("user does not own the playlist")
+
+	// ErrInvalidPlaylistVideo is returned when a playlist is given a video that does not
+	// exist, was deleted or belongs to another user.
+	ErrInvalidPlaylistVideo = errors.New("playlist videos must be videos of the playlist's owner")
+)
+
+// CreatePlaylistInput contains the input parameters for creating a playlist.
+type CreatePlaylistInput struct {
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	// Visibility defaults to unlisted when empty.
+	Visibility model.Visibility
+	// VideoIDs are the initial videos in playback order; optional.
+	VideoIDs []uuid.UUID
+}
+
+// UpdatePlaylistInput contains the playlist fields to change; nil fields are left as they are.
+type UpdatePlaylistInput struct {
+	Title       *string
+	Description *string
+	Visibility  *model.Visibility
+}
+
+// PlaylistService defines the interface for playlists, ordered lists of a user's videos.
+// A private playlist is reported as repository.ErrPlaylistNotFound to anyone but its owner.
+type PlaylistService interface {
+	// CreatePlaylist creates a playlist owned by input.UserID.
+	// Returns ErrInvalidPlaylistVideo if a video does not exist or belongs to another user.
+	CreatePlaylist(ctx context.Context, input CreatePlaylistInput) (*model.Playlist, error)
+
+	// GetPlaylist returns a playlist as seen by callerID, which is uuid.Nil for anonymous
+	// callers.
+	GetPlaylist(ctx context.Context, playlistID, callerID uuid.UUID) (*model.Playlist, error)
+
+	// ListPlaylists returns a user's playlists, newest first. Private playlists are left
+	// out unless callerID is the user.
+	ListPlaylists(ctx context.Context, userID, callerID uuid.UUID) ([]*model.Playlist, error)
+
+	// UpdatePlaylist changes the title, description and visibility of the playlist.
+	// Returns ErrNotPlaylistOwner if callerID does not own it.
+	UpdatePlaylist(ctx context.Context, playlistID, callerID uuid.UUID, input UpdatePlaylistInput) (*model.Playlist, error)
+
+	// SetPlaylistVideos replaces the playlist's videos with videoIDs in playback order.
+	// Returns ErrNotPlaylistOwner if callerID does not own it, and ErrInvalidPlaylistVideo
+	// if a video does not exist or belongs to another user.
+	SetPlaylistVideos(ctx context.Context, playlistID, callerID uuid.UUID, videoIDs []uuid.UUID) (*model.Playlist, error)
+
+	// DeletePlaylist removes the playlist; its videos are kept.
+	// Returns ErrNotPlaylistOwner if callerID does not own it.
+	DeletePlaylist(ctx context.Context, playlistID, callerID uuid.UUID) error
+
+	// ListPlaylistVideos returns the playlist's videos in playback order, with their
+	// playback URLs. Videos deleted since they were added, and private videos callerID
+	// may not see, are left out.
+	ListPlaylistVideos(ctx context.Context, playlistID, callerID uuid.UUID) ([]*model.Video, error)
+}
+
+type playlistService struct {
+	playlists repository.PlaylistRepository
+	videos    VideoService
+}
+
+// NewPlaylistService creates a new PlaylistService instance.
+// Videos are read through videos, so they come from the cache with their CDN URLs.
+func NewPlaylistService(playlists repository.PlaylistRepository, videos VideoService) PlaylistService {
+	return &playlistService{
+		playlists: playlists,
+		videos:    videos,
+	}
+}
+
+// CreatePlaylist validates the playlist and its videos before persisting it.
+func (s *playlistService) CreatePlaylist(ctx context.Context, input CreatePlaylistInput) (*model.Playlist, error) {
+	playlist, err := model.NewPlaylist(input.UserID, input.Title, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	if input.Visibility != "" {
+		if err := playlist.SetVisibility(input.Visibility); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.setVideos(ctx, playlist, input.VideoIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.playlists.Create(ctx, playlist); err != nil {
+		return nil, fmt.Errorf("create playlist: %w", err)
+	}
+
+	return playlist, nil
+}
+
+// GetPlaylist returns the playlist if callerID may see it.
+func (s *playlistService) GetPlaylist(ctx context.Context, playlistID, callerID uuid.UUID) (*model.Playlist, error) {
+	playlist, err := s.playlists.GetByID(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	if !playlist.VisibleTo(callerID) {
+		return nil, repository.ErrPlaylistNotFound
+	}
+	return playlist, nil
+}
+
+// ListPlaylists filters the user's playlists by what callerID may see.
+func (s *playlistService) ListPlaylists(ctx context.Context, userID, callerID uuid.UUID) ([]*model.Playlist, error) {
+	playlists, err := s.playlists.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := playlists[:0]
+	for _, p := range playlists {
+		if p.VisibleTo(callerID) {
+			visible = append(visible, p)
+		}
+	}
+	return visible, nil
+}
+
+// UpdatePlaylist applies every change before writing, so a rejected field changes nothing.
+func (s *playlistService) UpdatePlaylist(ctx context.Context, playlistID, callerID uuid.UUID, input UpdatePlaylistInput) (*model.Playlist, error) {
+	playlist, err := s.ownedPlaylist(ctx, playlistID, callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		if err := playlist.SetTitle(*input.Title); err != nil {
+			return nil, err
+		}
+	}
+	if input.Description != nil {
+		if err := playlist.SetDescription(*input.Description); err != nil {
+			return nil, err
+		}
+	}
+	if input.Visibility != nil {
+		if err := playlist.SetVisibility(*input.Visibility); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.playlists.Update(ctx, playlist); err != nil {
+		return nil, fmt.Errorf("update playlist: %w", err)
+	}
+
+	return playlist, nil
+}
+
+// SetPlaylistVideos checks every video before replacing the list.
+func (s *playlistService) SetPlaylistVideos(ctx context.Context, playlistID, callerID uuid.UUID, videoIDs []uuid.UUID) (*model.Playlist, error) {
+	playlist, err := s.ownedPlaylist(ctx, playlistID, callerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setVideos(ctx, playlist, videoIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.playlists.Update(ctx, playlist); err != nil {
+		return nil, fmt.Errorf("update playlist videos: %w", err)
+	}
+
+	return playlist, nil
+}
+
+// DeletePlaylist removes a playlist of callerID.
+func (s *playlistService) DeletePlaylist(ctx context.Context, playlistID, callerID uuid.UUID) error {
+	if _, err := s.ownedPlaylist(ctx, playlistID, callerID); err != nil {
+		return err
+	}
+	return s.playlists.Delete(ctx, playlistID)
+}
+
+// ListPlaylistVideos reads each video through the video service, one lookup per video.
+// Playlists are bounded and their videos are usually cached, so this costs less than a
+// join that would bypass the cache and the CDN URL rewriting.
+func (s *playlistService) ListPlaylistVideos(ctx context.Context, playlistID, callerID uuid.UUID) ([]*model.Video, error) {
+	playlist, err := s.GetPlaylist(ctx, playlistID, callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	videos := make([]*model.Video, 0, len(playlist.VideoIDs))
+	for _, id := range playlist.VideoIDs {
+		video, err := s.videos.GetVideo(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrVideoNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("get video %s: %w", id, err)
+		}
+		// Deleted videos stay in the table until they are purged
+		if video.Status != model.StatusDeleted && video.VisibleTo(callerID) {
+			videos = append(videos, video)
+		}
+	}
+	return videos, nil
+}
+
+// ownedPlaylist returns the playlist if callerID owns it. Playlists callerID may not see
+// are reported missing rather than forbidden.
+func (s *playlistService) ownedPlaylist(ctx context.Context, playlistID, callerID uuid.UUID) (*model.Playlist, error) {
+	playlist, err := s.GetPlaylist(ctx, playlistID, callerID)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.UserID != callerID {
+		return nil, ErrNotPlaylistOwner
+	}
+	return playlist, nil
+}
+
+// setVideos validates videoIDs with the model first, so oversized or duplicated lists
+// are rejected without looking any video up.
+func (s *playlistService) setVideos(ctx context.Context, playlist *model.Playlist, videoIDs []uuid.UUID) error {
+	if err := playlist.SetVideos(videoIDs); err != nil {
+		return err
+	}
+
+	for _, id := range playlist.VideoIDs {
+		video, err := s.videos.GetVideo(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrVideoNotFound) {
+				return fmt.Errorf("%w: video %s not found", ErrInvalidPlaylistVideo, id)
+			}
+			return fmt.Errorf("get video %s: %w", id, err)
+		}
+		if video.UserID != playlist.UserID || video.Status == model.StatusDeleted {
+			// Reported like a missing video, so other users' video IDs cannot be probed
+			return fmt.Errorf("%w: video %s not found", ErrInvalidPlaylistVideo, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// playlistFixture is a user with two videos and a deleted one, a video of another user
+// and a playlist.
+type playlistFixture struct {
+	ownerID  uuid.UUID
+	first    *model.Video
+	second   *model.Video
+	foreign  *model.Video
+	deleted  *model.Video
+	playlist *model.Playlist
+}
+
+func newPlaylistFixture(visibility model.Visibility) *playlistFixture {
+	ownerID := uuid.New()
+	f := &playlistFixture{
+		ownerID: ownerID,
+		first:   &model.Video{ID: uuid.New(), UserID: ownerID, Status: model.StatusReady, HLSURL: "https://cdn.example.com/hls/1/master.m3u8"},
+		second:  &model.Video{ID: uuid.New(), UserID: ownerID, Status: model.StatusReady, HLSURL: "https://cdn.example.com/hls/2/master.m3u8"},
+		foreign: &model.Video{ID: uuid.New(), UserID: uuid.New(), Status: model.StatusReady},
+		deleted: &model.Video{ID: uuid.New(), UserID: ownerID, Status: model.StatusDeleted},
+	}
+	f.playlist = &model.Playlist{
+		ID:         uuid.New(),
+		UserID:     ownerID,
+		Title:      "Go course",
+		Visibility: visibility,
+		VideoIDs:   []uuid.UUID{f.second.ID, f.first.ID},
+	}
+	return f
+}
+
+func (f *playlistFixture) videoService() *mockVideoService {
+	videos := map[uuid.UUID]*model.Video{f.first.ID: f.first, f.second.ID: f.second, f.foreign.ID: f.foreign, f.deleted.ID: f.deleted}
+	return &mockVideoService{
+		getVideoFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+			if v, ok := videos[videoID]; ok {
+				return v, nil
+			}
+			return nil, repository.ErrVideoNotFound
+		},
+	}
+}
+
+func (f *playlistFixture) repository() *mockPlaylistRepository {
+	return &mockPlaylistRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Playlist, error) {
+			if id != f.playlist.ID {
+				return nil, repository.ErrPlaylistNotFound
+			}
+			copied := *f.playlist
+			return &copied, nil
+		},
+	}
+}
+
+func TestPlaylistService_CreatePlaylist(t *testing.T) {
+	f := newPlaylistFixture(model.VisibilityUnlisted)
+
+	tests := []struct {
+		name           string
+		input          CreatePlaylistInput
+		repoErr        error
+		wantErr        error
+		wantVisibility model.Visibility
+		wantLookups    int32
+	}{
+		{
+			name:           "with videos",
+			input:          CreatePlaylistInput{UserID: f.ownerID, Title: "Go course", VideoIDs: []uuid.UUID{f.first.ID, f.second.ID}},
+			wantVisibility: model.VisibilityUnlisted,
+			wantLookups:    2,
+		},
+		{
+			name:           "private and empty",
+			input:          CreatePlaylistInput{UserID: f.ownerID, Title: "Drafts", Visibility: model.VisibilityPrivate},
+			wantVisibility: model.VisibilityPrivate,
+		},
+		{
+			name:    "invalid visibility",
+			input:   CreatePlaylistInput{UserID: f.ownerID, Title: "Go course", Visibility: "friends"},
+			wantErr: model.ErrInvalidVisibility,
+		},
+		{
+			name:    "empty title",
+			input:   CreatePlaylistInput{UserID: f.ownerID},
+			wantErr: model.ErrEmptyTitle,
+		},
+		{
+			name:        "video of another user",
+			input:       CreatePlaylistInput{UserID: f.ownerID, Title: "Go course", VideoIDs: []uuid.UUID{f.first.ID, f.foreign.ID}},
+			wantErr:     ErrInvalidPlaylistVideo,
+			wantLookups: 2,
+		},
+		{
+			name:        "deleted video",
+			input:       CreatePlaylistInput{UserID: f.ownerID, Title: "Go course", VideoIDs: []uuid.UUID{f.deleted.ID}},
+			wantErr:     ErrInvalidPlaylistVideo,
+			wantLookups: 1,
+		},
+		{
+			name:        "missing video",
+			input:       CreatePlaylistInput{UserID: f.ownerID, Title: "Go course", VideoIDs: []uuid.UUID{uuid.New()}},
+			wantErr:     ErrInvalidPlaylistVideo,
+			wantLookups: 1,
+		},
+		{
+			name:    "duplicate video is rejected before any lookup",
+			input:   CreatePlaylistInput{UserID: f.ownerID, Title: "Go course", VideoIDs: []uuid.UUID{f.first.ID, f.first.ID}},
+			wantErr: model.ErrDuplicatePlaylistVideo,
+		},
+		{
+			name:        "repository error",
+			input:       CreatePlaylistInput{UserID: f.ownerID, Title: "Go course", VideoIDs: []uuid.UUID{f.first.ID}},
+			repoErr:     errors.New("connection refused"),
+			wantErr:     errors.New("connection refused"),
+			wantLookups: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := f.videoService()
+			var created *model.Playlist
+			repo := &mockPlaylistRepository{
+				createFn: func(ctx context.Context, playlist *model.Playlist) error {
+					created = playlist
+					return tt.repoErr
+				},
+			}
+
+			got, err := NewPlaylistService(repo, videos).CreatePlaylist(context.Background(), tt.input)
+
+			if n := videos.getVideoCount.Load(); n != tt.wantLookups {
+				t.Errorf("video lookups = %d, want %d", n, tt.wantLookups)
+			}
+			if tt.wantErr != nil {
+				if err == nil || !errors.Is(err, tt.wantErr) && err.Error() != "create playlist: "+tt.wantErr.Error() {
+					t.Fatalf("CreatePlaylist() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreatePlaylist() unexpected error: %v", err)
+			}
+			if created != got {
+				t.Error("expected the returned playlist to be persisted")
+			}
+			if got.UserID != f.ownerID || got.Visibility != tt.wantVisibility || len(got.VideoIDs) != len(tt.input.VideoIDs) {
+				t.Errorf("CreatePlaylist() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestPlaylistService_GetPlaylist(t *testing.T) {
+	f := newPlaylistFixture(model.VisibilityPrivate)
+	svc := NewPlaylistService(f.repository(), f.videoService())
+
+	if _, err := svc.GetPlaylist(context.Background(), f.playlist.ID, f.ownerID); err != nil {
+		t.Errorf("GetPlaylist() by owner error = %v", err)
+	}
+	if _, err := svc.GetPlaylist(context.Background(), f.playlist.ID, uuid.New()); !errors.Is(err, repository.ErrPlaylistNotFound) {
+		t.Errorf("GetPlaylist() by another user error = %v, want %v", err, repository.ErrPlaylistNotFound)
+	}
+	if _, err := svc.GetPlaylist(context.Background(), f.playlist.ID, uuid.Nil); !errors.Is(err, repository.ErrPlaylistNotFound) {
+		t.Errorf("GetPlaylist() by anonymous caller error = %v, want %v", err, repository.ErrPlaylistNotFound)
+	}
+}
+
+func TestPlaylistService_ListPlaylists(t *testing.T) {
+	ownerID := uuid.New()
+	newList := func() []*model.Playlist {
+		return []*model.Playlist{
+			{ID: uuid.New(), UserID: ownerID, Title: "Public", Visibility: model.VisibilityPublic},
+			{ID: uuid.New(), UserID: ownerID, Title: "Private", Visibility: model.VisibilityPrivate},
+			{ID: uuid.New(), UserID: ownerID, Title: "Unlisted", Visibility: model.VisibilityUnlisted},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		callerID   uuid.UUID
+		wantTitles []string
+	}{
+		{"owner sees every playlist", ownerID, []string{"Public", "Private", "Unlisted"}},
+		{"another user", uuid.New(), []string{"Public", "Unlisted"}},
+		{"anonymous caller", uuid.Nil, []string{"Public", "Unlisted"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockPlaylistRepository{
+				listByUserFn: func(ctx context.Context, userID uuid.UUID) ([]*model.Playlist, error) {
+					if userID != ownerID {
+						t.Errorf("ListByUser(%s), want %s", userID, ownerID)
+					}
+					return newList(), nil
+				},
+			}
+
+			got, err := NewPlaylistService(repo, &mockVideoService{}).ListPlaylists(context.Background(), ownerID, tt.callerID)
+			if err != nil {
+				t.Fatalf("ListPlaylists() unexpected error: %v", err)
+			}
+			titles := make([]string, len(got))
+			for i, p := range got {
+				titles[i] = p.Title
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("titles = %q, want %q", titles, tt.wantTitles)
+			}
+		})
+	}
+}
+
+func TestPlaylistService_UpdatePlaylist(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	private := model.VisibilityPrivate
+
+	tests := []struct {
+		name        string
+		visibility  model.Visibility
+		asOwner     bool
+		input       UpdatePlaylistInput
+		wantErr     error
+		wantUpdated bool
+	}{
+		{
+			name:        "owner renames and hides",
+			visibility:  model.VisibilityUnlisted,
+			asOwner:     true,
+			input:       UpdatePlaylistInput{Title: ptr("Go course, 2nd edition"), Visibility: &private},
+			wantUpdated: true,
+		},
+		{
+			name:       "another user",
+			visibility: model.VisibilityUnlisted,
+			input:      UpdatePlaylistInput{Title: ptr("Mine now")},
+			wantErr:    ErrNotPlaylistOwner,
+		},
+		{
+			name:       "another user's private playlist is missing",
+			visibility: model.VisibilityPrivate,
+			input:      UpdatePlaylistInput{Title: ptr("Mine now")},
+			wantErr:    repository.ErrPlaylistNotFound,
+		},
+		{
+			name:       "invalid field writes nothing",
+			visibility: model.VisibilityUnlisted,
+			asOwner:    true,
+			input:      UpdatePlaylistInput{Title: ptr("Renamed"), Description: ptr(string(make([]byte, 5001)))},
+			wantErr:    model.ErrDescriptionTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPlaylistFixture(tt.visibility)
+			repo := f.repository()
+			var updated *model.Playlist
+			repo.updateFn = func(ctx context.Context, playlist *model.Playlist) error {
+				updated = playlist
+				return nil
+			}
+			callerID := uuid.New()
+			if tt.asOwner {
+				callerID = f.ownerID
+			}
+
+			got, err := NewPlaylistService(repo, f.videoService()).UpdatePlaylist(context.Background(), f.playlist.ID, callerID, tt.input)
+
+			if (updated != nil) != tt.wantUpdated {
+				t.Errorf("updated = %v, want %v", updated != nil, tt.wantUpdated)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdatePlaylist() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Title != *tt.input.Title || got.Visibility != *tt.input.Visibility || !reflect.DeepEqual(got.VideoIDs, f.playlist.VideoIDs) {
+				t.Errorf("UpdatePlaylist() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestPlaylistService_SetPlaylistVideos(t *testing.T) {
+	f := newPlaylistFixture(model.VisibilityUnlisted)
+
+	tests := []struct {
+		name     string
+		callerID uuid.UUID
+		videoIDs []uuid.UUID
+		wantErr  error
+	}{
+		{name: "reorders", callerID: f.ownerID, videoIDs: []uuid.UUID{f.first.ID, f.second.ID}},
+		{name: "clears", callerID: f.ownerID, videoIDs: []uuid.UUID{}},
+		{name: "another user's video", callerID: f.ownerID, videoIDs: []uuid.UUID{f.foreign.ID}, wantErr: ErrInvalidPlaylistVideo},
+		{name: "not the owner", callerID: f.foreign.UserID, videoIDs: []uuid.UUID{f.foreign.ID}, wantErr: ErrNotPlaylistOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := f.repository()
+			var updated *model.Playlist
+			repo.updateFn = func(ctx context.Context, playlist *model.Playlist) error {
+				updated = playlist
+				return nil
+			}
+
+			got, err := NewPlaylistService(repo, f.videoService()).SetPlaylistVideos(context.Background(), f.playlist.ID, tt.callerID, tt.videoIDs)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetPlaylistVideos() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if updated != nil {
+					t.Error("expected a rejected list not to be written")
+				}
+				return
+			}
+			if updated != got || !reflect.DeepEqual(got.VideoIDs, tt.videoIDs) {
+				t.Errorf("VideoIDs = %v, want %v", got.VideoIDs, tt.videoIDs)
+			}
+		})
+	}
+}
+
+func TestPlaylistService_DeletePlaylist(t *testing.T) {
+	f := newPlaylistFixture(model.VisibilityUnlisted)
+
+	tests := []struct {
+		name        string
+		callerID    uuid.UUID
+		wantErr     error
+		wantDeleted bool
+	}{
+		{name: "owner", callerID: f.ownerID, wantDeleted: true},
+		{name: "another user", callerID: uuid.New(), wantErr: ErrNotPlaylistOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := f.repository()
+			deleted := false
+			repo.deleteFn = func(ctx context.Context, id uuid.UUID) error {
+				deleted = id == f.playlist.ID
+				return nil
+			}
+
+			err := NewPlaylistService(repo, f.videoService()).DeletePlaylist(context.Background(), f.playlist.ID, tt.callerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeletePlaylist() error = %v, want %v", err, tt.wantErr)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestPlaylistService_ListPlaylistVideos(t *testing.T) {
+	f := newPlaylistFixture(model.VisibilityPublic)
+	private := &model.Video{ID: uuid.New(), UserID: f.ownerID, Status: model.StatusReady, Visibility: model.VisibilityPrivate}
+	deleted := &model.Video{ID: uuid.New(), UserID: f.ownerID, Status: model.StatusDeleted}
+	f.playlist.VideoIDs = []uuid.UUID{f.second.ID, uuid.New(), private.ID, deleted.ID, f.first.ID}
+
+	videos := f.videoService()
+	getVideo := videos.getVideoFn
+	videos.getVideoFn = func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+		switch videoID {
+		case private.ID:
+			return private, nil
+		case deleted.ID:
+			return deleted, nil
+		}
+		return getVideo(ctx, videoID)
+	}
+	svc := NewPlaylistService(f.repository(), videos)
+
+	tests := []struct {
+		name     string
+		callerID uuid.UUID
+		want     []*model.Video
+	}{
+		{"owner sees private videos", f.ownerID, []*model.Video{f.second, private, f.first}},
+		{"others do not", uuid.Nil, []*model.Video{f.second, f.first}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.ListPlaylistVideos(context.Background(), f.playlist.ID, tt.callerID)
+			if err != nil {
+				t.Fatalf("ListPlaylistVideos() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListPlaylistVideos() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("lookup failure", func(t *testing.T) {
+		failing := &mockVideoService{
+			getVideoFn: func(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+				return nil, errors.New("redis down")
+			},
+		}
+		_, err := NewPlaylistService(f.repository(), failing).ListPlaylistVideos(context.Background(), f.playlist.ID, f.ownerID)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+const (
+	// DefaultHistoryPageSize is the number of history entries returned when no limit is given.
+	DefaultHistoryPageSize = 20
+	// MaxHistoryPageSize caps the number of history entries returned per page.
+	MaxHistoryPageSize = 100
+)
+
+var (
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+)
+
+// ProgressServiceConfig holds configuration for ProgressService.
+type ProgressServiceConfig struct {
+	// FlushBatchSize is the maximum number of records written to the database per statement.
+	FlushBatchSize int
+}
+
+// DefaultProgressServiceConfig returns the default configuration.
+func DefaultProgressServiceConfig() ProgressServiceConfig {
+	return ProgressServiceConfig{
+		FlushBatchSize: 500,
+	}
+}
+
+// SaveProgressInput contains the input parameters for recording a playback position.
+type SaveProgressInput struct {
+	UserID   uuid.UUID
+	VideoID  uuid.UUID
+	Position time.Duration
+}
+
+// ListHistoryInput contains the input parameters for listing watch history.
+type ListHistoryInput struct {
+	UserID uuid.UUID
+	// Cursor is the opaque NextCursor of the previous page; empty starts from the newest entry.
+	Cursor string
+	// Limit is the page size; zero uses DefaultHistoryPageSize.
+	Limit int
+}
+
+// ListHistoryOutput contains a page of watch history.
+type ListHistoryOutput struct {
+	Entries []*model.WatchHistoryEntry
+	// NextCursor is empty when there are no more entries.
+	NextCursor string
+}
+
+// ProgressService defines the interface for resume-position ("continue watching") operations.
+type ProgressService interface {
+	// SaveProgress records the latest playback position of a user on a video.
+	// Positions are buffered and become durable on the next FlushProgress.
+	// Returns repository.ErrVideoNotFound if the video is deleted or not visible to the user.
+	SaveProgress(ctx context.Context, input SaveProgressInput) (*model.PlaybackProgress, error)
+
+	// GetProgress returns the last known playback position.
+	// Returns repository.ErrProgressNotFound if the user has never played the video.
+	GetProgress(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error)
+
+	// FlushProgress persists all buffered positions to the database.
+	// Returns the number of records flushed.
+	FlushProgress(ctx context.Context) (int, error)
+
+	// ListHistory returns the user's watch history, most recently watched first.
+	// History is read from the database, so heartbeats not yet flushed are not reflected.
+	ListHistory(ctx context.Context, input ListHistoryInput) (*ListHistoryOutput, error)
+
+	// RemoveFromHistory forgets the user's progress on a video, buffered or flushed.
+	// Returns repository.ErrProgressNotFound if the video is not in the user's history.
+	RemoveFromHistory(ctx context.Context, userID, videoID uuid.UUID) error
+}
+
+type progressService struct {
+	videos    VideoService
+	repo      repository.ProgressRepository
+	store     cache.ProgressStore
+	batchSize int
+}
+
+// NewProgressService creates a new ProgressService instance.
+// Videos are read through videos, so checking them is usually a cache hit.
+func NewProgressService(
+	videos VideoService,
+	repo repository.ProgressRepository,
+	store cache.ProgressStore,
+	cfg ProgressServiceConfig,
+) ProgressService {
+	batchSize := cfg.FlushBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultProgressServiceConfig().FlushBatchSize
+	}
+
+	return &progressService{
+		videos:    videos,
+		repo:      repo,
+		store:     store,
+		batchSize: batchSize,
+	}
+}
+
+// SaveProgress writes the heartbeat to the buffer only; the database is updated by FlushProgress.
+// Trade-off: positions recorded since the last flush are lost if Redis loses data,
+// which is acceptable for resume positions in exchange for not writing to PostgreSQL per heartbeat.
+func (s *progressService) SaveProgress(ctx context.Context, input SaveProgressInput) (*model.PlaybackProgress, error) {
+	progress, err := model.NewPlaybackProgress(input.UserID, input.VideoID, input.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := s.videos.GetVideo(ctx, input.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.Status == model.StatusDeleted || !video.VisibleTo(input.UserID) {
+		return nil, repository.ErrVideoNotFound
+	}
+
+	if err := s.store.Save(ctx, progress); err != nil {
+		return nil, fmt.Errorf("save progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// GetProgress reads the buffer first, falling back to the database and priming the buffer.
+func (s *progressService) GetProgress(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+	if userID == uuid.Nil {
+		return nil, model.ErrInvalidUserID
+	}
+
+	progress, err := s.store.Get(ctx, userID, videoID)
+	if err != nil {
+		slog.WarnContext(ctx, "progress cache get failed, falling back to database",
+			"user_id", userID,
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+	if progress != nil {
+		return progress, nil
+	}
+
+	progress, err = s.repo.Get(ctx, userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Prime(ctx, progress); err != nil {
+		slog.WarnContext(ctx, "failed to prime progress cache",
+			"user_id", userID,
+			"video_id", videoID,
+			"error", err,
+		)
+	}
+
+	return progress, nil
+}
+
+// FlushProgress drains the dirty set in batches until the store reports it drained; a
+// short batch alone does not end the flush, as expired records are dropped from it.
+// A failed batch is re-queued so the next flush retries it.
+func (s *progressService) FlushProgress(ctx context.Context) (int, error) {
+	flushed := 0
+
+	for {
+		batch, drained, err := s.store.PopDirty(ctx, s.batchSize)
+		if err != nil {
+			return flushed, fmt.Errorf("pop dirty progress: %w", err)
+		}
+
+		if len(batch) > 0 {
+			if err := s.repo.UpsertBatch(ctx, batch); err != nil {
+				if markErr := s.store.MarkDirty(ctx, batch); markErr != nil {
+					slog.ErrorContext(ctx, "failed to re-queue progress after flush failure",
+						"count", len(batch),
+						"error", markErr,
+					)
+				}
+				return flushed, fmt.Errorf("upsert progress: %w", err)
+			}
+			flushed += len(batch)
+		}
+
+		if drained {
+			return flushed, nil
+		}
+	}
+}
+
+// ListHistory fetches one extra row to decide whether another page exists.
+func (s *progressService) ListHistory(ctx context.Context, input ListHistoryInput) (*ListHistoryOutput, error) {
+	if input.UserID == uuid.Nil {
+		return nil, model.ErrInvalidUserID
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryPageSize
+	}
+	limit = min(limit, MaxHistoryPageSize)
+
+	var cursor *repository.HistoryCursor
+	if input.Cursor != "" {
+		c, err := decodeHistoryCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = c
+	}
+
+	entries, err := s.repo.ListByUser(ctx, input.UserID, cursor, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list history: %w", err)
+	}
+
+	output := &ListHistoryOutput{Entries: entries}
+	if len(entries) > limit {
+		output.Entries = entries[:limit]
+		last := output.Entries[limit-1]
+		output.NextCursor = encodeHistoryCursor(repository.HistoryCursor{
+			UpdatedAt: last.UpdatedAt,
+			VideoID:   last.VideoID,
+		})
+	}
+
+	return output, nil
+}
+
+// RemoveFromHistory deletes the buffered copy first so a later flush cannot re-insert the
+// row; the repository's removal marker covers a flush already in flight. Progress that was
+// only buffered counts as found.
+func (s *progressService) RemoveFromHistory(ctx context.Context, userID, videoID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return model.ErrInvalidUserID
+	}
+
+	buffered, err := s.store.Delete(ctx, userID, videoID)
+	if err != nil {
+		return fmt.Errorf("delete buffered progress: %w", err)
+	}
+
+	if err := s.repo.Delete(ctx, userID, videoID); err != nil {
+		if buffered && errors.Is(err, repository.ErrProgressNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// encodeHistoryCursor serializes a cursor as an opaque URL-safe string.
+func encodeHistoryCursor(c repository.HistoryCursor) string {
+	return encodeKeysetCursor(c.UpdatedAt, c.VideoID)
+}
+
+// decodeHistoryCursor parses a cursor produced by encodeHistoryCursor.
+func decodeHistoryCursor(s string) (*repository.HistoryCursor, error) {
+	updatedAt, videoID, err := decodeKeysetCursor(s)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.HistoryCursor{UpdatedAt: updatedAt, VideoID: videoID}, nil
+}
@@ -0,0 +1,503 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestProgressService_SaveProgress(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name     string
+		input    SaveProgressInput
+		video    *model.Video
+		storeErr error
+		wantErr  bool
+		wantIs   error
+	}{
+		{
+			name:  "successful save",
+			input: SaveProgressInput{UserID: userID, VideoID: videoID, Position: 42 * time.Second},
+		},
+		{
+			name:    "nil user ID",
+			input:   SaveProgressInput{UserID: uuid.Nil, VideoID: videoID, Position: time.Second},
+			wantErr: true,
+			wantIs:  model.ErrInvalidUserID,
+		},
+		{
+			name:    "negative position",
+			input:   SaveProgressInput{UserID: userID, VideoID: videoID, Position: -time.Second},
+			wantErr: true,
+			wantIs:  model.ErrInvalidPosition,
+		},
+		{
+			name:     "store error",
+			input:    SaveProgressInput{UserID: userID, VideoID: videoID, Position: time.Second},
+			storeErr: errors.New("redis down"),
+			wantErr:  true,
+		},
+		{
+			name:    "private video of another user",
+			input:   SaveProgressInput{UserID: userID, VideoID: videoID, Position: time.Second},
+			video:   &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+			wantErr: true,
+			wantIs:  repository.ErrVideoNotFound,
+		},
+		{
+			name:    "deleted video",
+			input:   SaveProgressInput{UserID: userID, VideoID: videoID, Position: time.Second},
+			video:   &model.Video{ID: videoID, UserID: userID, Status: model.StatusDeleted, Visibility: model.VisibilityUnlisted},
+			wantErr: true,
+			wantIs:  repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *model.PlaybackProgress
+			store := &mockProgressStore{
+				saveFn: func(ctx context.Context, progress *model.PlaybackProgress) error {
+					saved = progress
+					return tt.storeErr
+				},
+			}
+
+			repo := &mockProgressRepository{
+				upsertBatchFn: func(ctx context.Context, progress []*model.PlaybackProgress) error {
+					t.Error("SaveProgress must not write to the database")
+					return nil
+				},
+			}
+
+			videos := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.video != nil {
+						return tt.video, nil
+					}
+					return &model.Video{ID: id, UserID: uuid.New(), Status: model.StatusReady, Visibility: model.VisibilityUnlisted}, nil
+				},
+			}
+
+			svc := NewProgressService(videos, repo, store, DefaultProgressServiceConfig())
+			got, err := svc.SaveProgress(context.Background(), tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+					t.Errorf("expected error %v, got %v", tt.wantIs, err)
+				}
+				if tt.video != nil && saved != nil {
+					t.Errorf("progress on a hidden video was buffered: %+v", saved)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if saved == nil || saved.Position != tt.input.Position {
+				t.Errorf("expected position %v to be buffered, got %+v", tt.input.Position, saved)
+			}
+			if got.UpdatedAt.IsZero() {
+				t.Error("expected UpdatedAt to be set")
+			}
+		})
+	}
+}
+
+func TestProgressService_GetProgress(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+	cached := &model.PlaybackProgress{UserID: userID, VideoID: videoID, Position: 90 * time.Second}
+	stored := &model.PlaybackProgress{UserID: userID, VideoID: videoID, Position: 30 * time.Second}
+
+	tests := []struct {
+		name      string
+		cached    *model.PlaybackProgress
+		cacheErr  error
+		stored    *model.PlaybackProgress
+		repoErr   error
+		want      time.Duration
+		wantErr   error
+		wantPrime bool
+	}{
+		{
+			name:   "cache hit",
+			cached: cached,
+			want:   90 * time.Second,
+		},
+		{
+			name:      "cache miss falls back to database",
+			stored:    stored,
+			want:      30 * time.Second,
+			wantPrime: true,
+		},
+		{
+			name:      "cache error falls back to database",
+			cacheErr:  errors.New("redis down"),
+			stored:    stored,
+			want:      30 * time.Second,
+			wantPrime: true,
+		},
+		{
+			name:    "not found",
+			repoErr: repository.ErrProgressNotFound,
+			wantErr: repository.ErrProgressNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primed := false
+			store := &mockProgressStore{
+				getFn: func(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+					return tt.cached, tt.cacheErr
+				},
+				primeFn: func(ctx context.Context, progress *model.PlaybackProgress) error {
+					primed = true
+					return nil
+				},
+			}
+			repo := &mockProgressRepository{
+				getFn: func(ctx context.Context, userID, videoID uuid.UUID) (*model.PlaybackProgress, error) {
+					return tt.stored, tt.repoErr
+				},
+			}
+
+			svc := NewProgressService(&mockVideoService{}, repo, store, DefaultProgressServiceConfig())
+			got, err := svc.GetProgress(context.Background(), userID, videoID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Position != tt.want {
+				t.Errorf("Position: got %v, want %v", got.Position, tt.want)
+			}
+			if primed != tt.wantPrime {
+				t.Errorf("primed: got %v, want %v", primed, tt.wantPrime)
+			}
+		})
+	}
+}
+
+func TestProgressService_FlushProgress(t *testing.T) {
+	newBatch := func(n int) []*model.PlaybackProgress {
+		batch := make([]*model.PlaybackProgress, n)
+		for i := range batch {
+			batch[i] = &model.PlaybackProgress{UserID: uuid.New(), VideoID: uuid.New()}
+		}
+		return batch
+	}
+
+	t.Run("drains batches until the store is drained", func(t *testing.T) {
+		// The second batch comes back short and the third empty, as their records
+		// expired, yet more remain
+		batches := [][]*model.PlaybackProgress{newBatch(2), newBatch(1), nil, newBatch(1)}
+		store := &mockProgressStore{
+			popDirtyFn: func(ctx context.Context, n int) ([]*model.PlaybackProgress, bool, error) {
+				if n != 2 {
+					t.Errorf("PopDirty n: got %d, want 2", n)
+				}
+				if len(batches) == 0 {
+					t.Fatal("PopDirty called after the store was drained")
+				}
+				batch := batches[0]
+				batches = batches[1:]
+				return batch, len(batches) == 0, nil
+			},
+		}
+
+		upserts := 0
+		repo := &mockProgressRepository{
+			upsertBatchFn: func(ctx context.Context, progress []*model.PlaybackProgress) error {
+				upserts++
+				return nil
+			},
+		}
+
+		svc := NewProgressService(&mockVideoService{}, repo, store, ProgressServiceConfig{FlushBatchSize: 2})
+		flushed, err := svc.FlushProgress(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if flushed != 4 {
+			t.Errorf("flushed: got %d, want 4", flushed)
+		}
+		if upserts != 3 {
+			t.Errorf("upserts: got %d, want 3", upserts)
+		}
+		if len(batches) != 0 {
+			t.Errorf("%d batches left undrained", len(batches))
+		}
+	})
+
+	t.Run("re-queues batch on database error", func(t *testing.T) {
+		batch := newBatch(1)
+		var requeued []*model.PlaybackProgress
+		store := &mockProgressStore{
+			popDirtyFn: func(ctx context.Context, n int) ([]*model.PlaybackProgress, bool, error) {
+				return batch, false, nil
+			},
+			markDirtyFn: func(ctx context.Context, progress []*model.PlaybackProgress) error {
+				requeued = progress
+				return nil
+			},
+		}
+		repo := &mockProgressRepository{
+			upsertBatchFn: func(ctx context.Context, progress []*model.PlaybackProgress) error {
+				return errors.New("connection refused")
+			},
+		}
+
+		svc := NewProgressService(&mockVideoService{}, repo, store, DefaultProgressServiceConfig())
+		flushed, err := svc.FlushProgress(context.Background())
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if flushed != 0 {
+			t.Errorf("flushed: got %d, want 0", flushed)
+		}
+		if len(requeued) != 1 || requeued[0] != batch[0] {
+			t.Errorf("expected failed batch to be re-queued, got %+v", requeued)
+		}
+	})
+}
+
+func TestProgressService_ListHistory(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+
+	newEntries := func(n int) []*model.WatchHistoryEntry {
+		entries := make([]*model.WatchHistoryEntry, n)
+		for i := range entries {
+			entries[i] = &model.WatchHistoryEntry{
+				PlaybackProgress: model.PlaybackProgress{
+					UserID:    userID,
+					VideoID:   uuid.New(),
+					UpdatedAt: now.Add(-time.Duration(i) * time.Minute),
+				},
+				Title: "video",
+			}
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name           string
+		input          ListHistoryInput
+		rows           int
+		wantLimit      int
+		wantEntries    int
+		wantNextCursor bool
+		wantErr        error
+	}{
+		{
+			name:        "default page size",
+			input:       ListHistoryInput{UserID: userID},
+			rows:        3,
+			wantLimit:   DefaultHistoryPageSize + 1,
+			wantEntries: 3,
+		},
+		{
+			name:           "more pages available",
+			input:          ListHistoryInput{UserID: userID, Limit: 2},
+			rows:           3,
+			wantLimit:      3,
+			wantEntries:    2,
+			wantNextCursor: true,
+		},
+		{
+			name:        "limit is capped",
+			input:       ListHistoryInput{UserID: userID, Limit: 1000},
+			wantLimit:   MaxHistoryPageSize + 1,
+			wantEntries: 0,
+		},
+		{
+			name:    "nil user ID",
+			input:   ListHistoryInput{UserID: uuid.Nil},
+			wantErr: model.ErrInvalidUserID,
+		},
+		{
+			name:    "malformed cursor",
+			input:   ListHistoryInput{UserID: userID, Cursor: "not-a-cursor"},
+			wantErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockProgressRepository{
+				listByUserFn: func(ctx context.Context, id uuid.UUID, cursor *repository.HistoryCursor, limit int) ([]*model.WatchHistoryEntry, error) {
+					if limit != tt.wantLimit {
+						t.Errorf("limit: got %d, want %d", limit, tt.wantLimit)
+					}
+					return newEntries(tt.rows), nil
+				},
+			}
+
+			svc := NewProgressService(&mockVideoService{}, repo, &mockProgressStore{}, DefaultProgressServiceConfig())
+			got, err := svc.ListHistory(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got.Entries) != tt.wantEntries {
+				t.Errorf("entries: got %d, want %d", len(got.Entries), tt.wantEntries)
+			}
+			if (got.NextCursor != "") != tt.wantNextCursor {
+				t.Errorf("next cursor: got %q, want present=%v", got.NextCursor, tt.wantNextCursor)
+			}
+		})
+	}
+}
+
+func TestProgressService_ListHistory_CursorRoundTrip(t *testing.T) {
+	userID := uuid.New()
+	last := &model.WatchHistoryEntry{
+		PlaybackProgress: model.PlaybackProgress{
+			UserID:    userID,
+			VideoID:   uuid.New(),
+			UpdatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC),
+		},
+	}
+
+	var gotCursor *repository.HistoryCursor
+	repo := &mockProgressRepository{
+		listByUserFn: func(ctx context.Context, id uuid.UUID, cursor *repository.HistoryCursor, limit int) ([]*model.WatchHistoryEntry, error) {
+			gotCursor = cursor
+			return []*model.WatchHistoryEntry{last, {}}, nil
+		},
+	}
+
+	svc := NewProgressService(&mockVideoService{}, repo, &mockProgressStore{}, DefaultProgressServiceConfig())
+	page, err := svc.ListHistory(context.Background(), ListHistoryInput{UserID: userID, Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.ListHistory(context.Background(), ListHistoryInput{UserID: userID, Cursor: page.NextCursor, Limit: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotCursor == nil {
+		t.Fatal("expected cursor to be passed to repository")
+	}
+	if !gotCursor.UpdatedAt.Equal(last.UpdatedAt) || gotCursor.VideoID != last.VideoID {
+		t.Errorf("cursor: got %+v, want %v/%v", gotCursor, last.UpdatedAt, last.VideoID)
+	}
+}
+
+func TestProgressService_RemoveFromHistory(t *testing.T) {
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name         string
+		userID       uuid.UUID
+		buffered     bool
+		storeErr     error
+		repoErr      error
+		wantErr      error
+		wantRepoCall bool
+	}{
+		{
+			name:         "successful remove",
+			userID:       userID,
+			wantRepoCall: true,
+		},
+		{
+			name:         "not in history",
+			userID:       userID,
+			repoErr:      repository.ErrProgressNotFound,
+			wantErr:      repository.ErrProgressNotFound,
+			wantRepoCall: true,
+		},
+		{
+			name:         "only buffered",
+			userID:       userID,
+			buffered:     true,
+			repoErr:      repository.ErrProgressNotFound,
+			wantRepoCall: true,
+		},
+		{
+			name:         "buffered and database error",
+			userID:       userID,
+			buffered:     true,
+			repoErr:      errors.New("connection refused"),
+			wantErr:      errors.New("connection refused"),
+			wantRepoCall: true,
+		},
+		{
+			name:     "buffer delete fails before touching the database",
+			userID:   userID,
+			storeErr: errors.New("redis down"),
+			wantErr:  errors.New("delete buffered progress"),
+		},
+		{
+			name:    "nil user ID",
+			userID:  uuid.Nil,
+			wantErr: model.ErrInvalidUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoCalled := false
+			store := &mockProgressStore{
+				deleteFn: func(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+					return tt.buffered, tt.storeErr
+				},
+			}
+			repo := &mockProgressRepository{
+				deleteFn: func(ctx context.Context, userID, videoID uuid.UUID) error {
+					repoCalled = true
+					return tt.repoErr
+				},
+			}
+
+			svc := NewProgressService(&mockVideoService{}, repo, store, DefaultProgressServiceConfig())
+			err := svc.RemoveFromHistory(context.Background(), tt.userID, videoID)
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error %v, got nil", tt.wantErr)
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if repoCalled != tt.wantRepoCall {
+				t.Errorf("repository called: got %v, want %v", repoCalled, tt.wantRepoCall)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const (
+	// DefaultDownloadConcurrency is the default number of concurrent range requests.
+	DefaultDownloadConcurrency = 4
+	// DefaultDownloadChunkSize is the default size of each range request (16 MiB).
+	DefaultDownloadChunkSize int64 = 16 << 20
+)
+
+var (
+	// ErrChecksumMismatch is returned when a downloaded object does not match its storage checksum.
+	ErrChecksumMismatch = errors.New("downloaded object checksum mismatch")
+)
+
+// rangeDownloader copies an object from storage to a local file.
+// Objects larger than chunkSize are fetched with up to concurrency parallel range GETs,
+// which hides per-request latency on high-RTT links to the object store.
+type rangeDownloader struct {
+	storage     repository.ObjectStorage
+	concurrency int
+	chunkSize   int64
+}
+
+// maxDownloadRestarts bounds how often a ranged download starts over after the object
+// was replaced mid-transfer, so a key that is rewritten continuously cannot spin forever.
+const maxDownloadRestarts = 3
+
+// download fetches key into localPath and verifies its checksum when the backend exposes one.
+// Ranged downloads are pinned to the ETag returned by the initial Stat; if the object is
+// replaced partway through, the download restarts from scratch against the new version.
+// Returns the number of bytes written.
+func (d *rangeDownloader) download(ctx context.Context, key, localPath string) (int64, error) {
+	for attempt := 0; ; attempt++ {
+		written, err := d.downloadOnce(ctx, key, localPath)
+		if !errors.Is(err, repository.ErrObjectChanged) || attempt >= maxDownloadRestarts {
+			return written, err
+		}
+	}
+}
+
+// downloadOnce performs a single download attempt against the object's current version.
+func (d *rangeDownloader) downloadOnce(ctx context.Context, key, localPath string) (int64, error) {
+	info, err := d.storage.Stat(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("stat object: %w", err)
+	}
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		return 0, fmt.Errorf("create local file: %w", err)
+	}
+
+	written := info.Size
+	if d.concurrency <= 1 || d.chunkSize <= 0 || info.Size <= d.chunkSize {
+		written, err = d.downloadSequential(ctx, key, file)
+	} else {
+		err = d.downloadParallel(ctx, key, info.ETag, info.Size, file)
+	}
+	if err != nil {
+		_ = file.Close()
+		return 0, err
+	}
+
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("close local file: %w", err)
+	}
+
+	if err := verifyChecksum(localPath, info.ETag); err != nil {
+		return 0, err
+	}
+
+	return written, nil
+}
+
+// downloadSequential streams the whole object with a single GET.
+func (d *rangeDownloader) downloadSequential(ctx context.Context, key string, file *os.File) (int64, error) {
+	reader, err := d.storage.Download(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("storage download: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	written, err := io.Copy(file, reader)
+	if err != nil {
+		return 0, fmt.Errorf("copy to local file: %w", err)
+	}
+
+	return written, nil
+}
+
+// downloadParallel splits the object into chunkSize ranges and writes each at its offset.
+// Trade-off: the file is preallocated to its final size so chunks can land out of order,
+// at the cost of a sparse file being visible until all ranges complete.
+func (d *rangeDownloader) downloadParallel(ctx context.Context, key, etag string, size int64, file *os.File) error {
+	if err := file.Truncate(size); err != nil {
+		return fmt.Errorf("preallocate local file: %w", err)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(d.concurrency)
+
+	for offset := int64(0); offset < size; offset += d.chunkSize {
+		length := min(d.chunkSize, size-offset)
+		g.Go(func() (err error) {
+			// A panic on a chunk goroutine would otherwise take down the whole worker
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("download range %d-%d panicked: %v", offset, offset+length-1, r)
+				}
+			}()
+
+			return d.downloadChunk(gctx, key, etag, file, offset, length)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// downloadChunk fetches a single byte range of the etag version and writes it at offset.
+func (d *rangeDownloader) downloadChunk(ctx context.Context, key, etag string, file *os.File, offset, length int64) error {
+	reader, err := d.storage.DownloadRange(ctx, key, etag, offset, length)
+	if err != nil {
+		return fmt.Errorf("download range %d-%d: %w", offset, offset+length-1, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	written, err := io.Copy(io.NewOffsetWriter(file, offset), io.LimitReader(reader, length))
+	if err != nil {
+		return fmt.Errorf("write range %d-%d: %w", offset, offset+length-1, err)
+	}
+	if written != length {
+		return fmt.Errorf("short range %d-%d: got %d bytes", offset, offset+length-1, written)
+	}
+
+	return nil
+}
+
+// verifyChecksum compares the MD5 of the local file against the object's ETag.
+// Verification is skipped when the ETag is not a plain MD5 (e.g., multipart uploads
+// produce "<md5>-<parts>", which cannot be recomputed without the original part size).
+func verifyChecksum(localPath, etag string) error {
+	expected := strings.Trim(etag, `"`)
+	if !isMD5Hex(expected) {
+		return nil
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("open local file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return fmt.Errorf("hash local file: %w", err)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+
+	return nil
+}
+
+// isMD5Hex reports whether s looks like a hex-encoded MD5 digest.
+func isMD5Hex(s string) bool {
+	if len(s) != md5.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
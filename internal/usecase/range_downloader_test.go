@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRangeDownloader_Download(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 10) // 100 bytes
+
+	tests := []struct {
+		name          string
+		concurrency   int
+		chunkSize     int64
+		etag          string
+		rangeErr      error
+		wantErr       error
+		wantRangeGETs int32
+		wantFullGETs  int32
+	}{
+		{
+			name:          "parallel ranged download",
+			concurrency:   4,
+			chunkSize:     30,
+			etag:          `"` + md5Hex(content) + `"`,
+			wantRangeGETs: 4,
+		},
+		{
+			name:         "falls back to single GET when concurrency disabled",
+			concurrency:  1,
+			chunkSize:    30,
+			etag:         md5Hex(content),
+			wantFullGETs: 1,
+		},
+		{
+			name:         "falls back to single GET for small objects",
+			concurrency:  4,
+			chunkSize:    1024,
+			etag:         md5Hex(content),
+			wantFullGETs: 1,
+		},
+		{
+			name:          "checksum mismatch",
+			concurrency:   4,
+			chunkSize:     30,
+			etag:          md5Hex([]byte("something else")),
+			wantErr:       ErrChecksumMismatch,
+			wantRangeGETs: 4,
+		},
+		{
+			name:          "multipart etag skips verification",
+			concurrency:   4,
+			chunkSize:     30,
+			etag:          "d41d8cd98f00b204e9800998ecf8427e-3",
+			wantRangeGETs: 4,
+		},
+		{
+			name:        "range request fails",
+			concurrency: 2,
+			chunkSize:   30,
+			rangeErr:    repository.ErrObjectNotFound,
+			wantErr:     repository.ErrObjectNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rangeGETs, fullGETs atomic.Int32
+
+			storage := &mockObjectStorage{
+				statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+					return &repository.ObjectInfo{Key: key, Size: int64(len(content)), ETag: tt.etag}, nil
+				},
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					fullGETs.Add(1)
+					return io.NopCloser(bytes.NewReader(content)), nil
+				},
+				downloadRangeFn: func(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error) {
+					if tt.rangeErr != nil {
+						return nil, tt.rangeErr
+					}
+					if etag != tt.etag {
+						return nil, errors.New("range request not pinned to the stat etag: " + etag)
+					}
+					rangeGETs.Add(1)
+					return io.NopCloser(bytes.NewReader(content[offset : offset+length])), nil
+				},
+			}
+
+			d := &rangeDownloader{storage: storage, concurrency: tt.concurrency, chunkSize: tt.chunkSize}
+			localPath := filepath.Join(t.TempDir(), "original.mp4")
+
+			written, err := d.download(context.Background(), "originals/video.mp4", localPath)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got, err := os.ReadFile(localPath)
+				if err != nil {
+					t.Fatalf("failed to read downloaded file: %v", err)
+				}
+				if !bytes.Equal(got, content) {
+					t.Errorf("downloaded content mismatch: got %q", got)
+				}
+				if written != int64(len(content)) {
+					t.Errorf("written: got %d, expected %d", written, len(content))
+				}
+			}
+
+			if got := rangeGETs.Load(); got != tt.wantRangeGETs {
+				t.Errorf("range GETs: got %d, expected %d", got, tt.wantRangeGETs)
+			}
+			if got := fullGETs.Load(); got != tt.wantFullGETs {
+				t.Errorf("full GETs: got %d, expected %d", got, tt.wantFullGETs)
+			}
+		})
+	}
+}
+
+func TestRangeDownloader_Download_StatError(t *testing.T) {
+	storage := &mockObjectStorage{
+		statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+			return nil, repository.ErrObjectNotFound
+		},
+	}
+
+	d := &rangeDownloader{storage: storage, concurrency: 4, chunkSize: 30}
+	_, err := d.download(context.Background(), "originals/missing.mp4", filepath.Join(t.TempDir(), "out.mp4"))
+
+	if !errors.Is(err, repository.ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestRangeDownloader_Download_RestartsWhenObjectReplaced(t *testing.T) {
+	original := bytes.Repeat([]byte("a"), 100)
+	replacement := bytes.Repeat([]byte("b"), 90)
+
+	var replaced atomic.Bool
+	var stats atomic.Int32
+	current := func() ([]byte, string) {
+		if replaced.Load() {
+			return replacement, md5Hex(replacement)
+		}
+		return original, md5Hex(original)
+	}
+
+	storage := &mockObjectStorage{
+		statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+			stats.Add(1)
+			data, etag := current()
+			return &repository.ObjectInfo{Key: key, Size: int64(len(data)), ETag: etag}, nil
+		},
+		downloadRangeFn: func(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error) {
+			// The object is overwritten once the first chunk of the original has been served.
+			if offset > 0 {
+				replaced.Store(true)
+			}
+			data, currentETag := current()
+			if etag != currentETag {
+				return nil, repository.ErrObjectChanged
+			}
+			return io.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
+		},
+	}
+
+	d := &rangeDownloader{storage: storage, concurrency: 2, chunkSize: 30}
+	localPath := filepath.Join(t.TempDir(), "original.mp4")
+
+	written, err := d.download(context.Background(), "originals/video.mp4", localPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(localPath)
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(got, replacement) {
+		t.Errorf("expected the replacement object, got %q", got)
+	}
+	if written != int64(len(replacement)) {
+		t.Errorf("written: got %d, expected %d", written, len(replacement))
+	}
+	if got := stats.Load(); got != 2 {
+		t.Errorf("stats: got %d, expected 2", got)
+	}
+}
+
+func TestRangeDownloader_Download_GivesUpOnChurningObject(t *testing.T) {
+	var stats atomic.Int32
+
+	storage := &mockObjectStorage{
+		statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+			stats.Add(1)
+			return &repository.ObjectInfo{Key: key, Size: 100, ETag: md5Hex([]byte("v"))}, nil
+		},
+		downloadRangeFn: func(ctx context.Context, key, etag string, offset, length int64) (io.ReadCloser, error) {
+			return nil, repository.ErrObjectChanged
+		},
+	}
+
+	d := &rangeDownloader{storage: storage, concurrency: 2, chunkSize: 30}
+	_, err := d.download(context.Background(), "originals/video.mp4", filepath.Join(t.TempDir(), "out.mp4"))
+
+	if !errors.Is(err, repository.ErrObjectChanged) {
+		t.Fatalf("expected ErrObjectChanged, got %v", err)
+	}
+	if got := stats.Load(); got != maxDownloadRestarts+1 {
+		t.Errorf("stats: got %d, expected %d", got, maxDownloadRestarts+1)
+	}
+}
@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/hls"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// RenditionPruneConfig holds configuration for RenditionPruner.
+type RenditionPruneConfig struct {
+	// MinAge is how old a published output must be before it is pruned, and how far back
+	// views are counted.
+	MinAge time.Duration
+	// BatchSize caps the videos pruned by one run.
+	BatchSize int
+}
+
+// DefaultRenditionPruneConfig returns the default configuration.
+func DefaultRenditionPruneConfig() RenditionPruneConfig {
+	return RenditionPruneConfig{
+		MinAge:    30 * 24 * time.Hour,
+		BatchSize: 50,
+	}
+}
+
+// RenditionPruneResult summarizes a pruning run.
+type RenditionPruneResult struct {
+	Videos     int
+	Renditions int
+	// Bytes is the recorded size of the pruned renditions.
+	Bytes int64
+}
+
+// RenditionPruner removes renditions nobody watches from published HLS output.
+type RenditionPruner interface {
+	// Prune removes, from up to BatchSize videos whose output is older than MinAge, every
+	// rendition above the highest one viewed within MinAge (or above the lowest one, if
+	// none was). The lowest rendition is always kept. Failures of single videos are logged
+	// and left for the next run.
+	Prune(ctx context.Context) (*RenditionPruneResult, error)
+}
+
+type renditionPruner struct {
+	videos     repository.VideoRepository
+	renditions repository.RenditionRepository
+	storage    repository.ObjectStorage
+	replica    repository.ObjectStorage
+	purger     repository.CDNPurger
+	cfg        RenditionPruneConfig
+	now        func() time.Time
+}
+
+// NewRenditionPruner creates a new RenditionPruner instance.
+// The replica parameter is optional - pass nil when output is not replicated.
+// The purger parameter is optional - pass nil to leave the old master playlist to expire from the CDN.
+func NewRenditionPruner(
+	videos repository.VideoRepository,
+	renditions repository.RenditionRepository,
+	storage repository.ObjectStorage,
+	replica repository.ObjectStorage,
+	purger repository.CDNPurger,
+	cfg RenditionPruneConfig,
+) RenditionPruner {
+	return &renditionPruner{
+		videos:     videos,
+		renditions: renditions,
+		storage:    storage,
+		replica:    replica,
+		purger:     purger,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+func (p *renditionPruner) Prune(ctx context.Context) (*RenditionPruneResult, error) {
+	now := p.now()
+	cutoff := now.Add(-p.cfg.MinAge)
+
+	ids, err := p.renditions.ListPruneCandidates(ctx, cutoff, p.cfg.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("list prune candidates: %w", err)
+	}
+
+	result := &RenditionPruneResult{}
+	for _, id := range ids {
+		pruned, err := p.pruneVideo(ctx, id, cutoff, now)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to prune renditions",
+				"video_id", id,
+				"error", err,
+			)
+			continue
+		}
+		if len(pruned) == 0 {
+			continue
+		}
+
+		result.Videos++
+		for _, rd := range pruned {
+			result.Renditions++
+			result.Bytes += rd.Bytes
+		}
+	}
+
+	metrics.RenditionsPrunedTotal.Add(float64(result.Renditions))
+	metrics.RenditionPrunedBytesTotal.Add(float64(result.Bytes))
+	return result, nil
+}
+
+// pruneVideo takes the renditions out of the master playlist before deleting their
+// objects, so players never get a variant that is gone. They are marked pruned last:
+// a run that fails in between leaves them unmarked and the next run finishes the job.
+func (p *renditionPruner) pruneVideo(ctx context.Context, videoID uuid.UUID, cutoff, now time.Time) ([]*model.Rendition, error) {
+	video, err := p.videos.GetByID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	// DASH manifests list the same renditions and are not rewritten, and output in a
+	// tenant's bucket is not ours to delete from
+	if !video.IsReady() || video.HLSURL == "" || video.DashURL != "" || video.ExternalOutput {
+		return nil, nil
+	}
+
+	renditions, err := p.renditions.ListByVideoID(ctx, videoID, video.OutputVersion)
+	if err != nil {
+		return nil, fmt.Errorf("list renditions: %w", err)
+	}
+	pruned := selectPrunableRenditions(renditions, cutoff)
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	outputPrefix := path.Dir(video.HLSURL) + "/"
+	names := make([]string, len(pruned))
+	prefixes := make([]string, len(pruned))
+	for i, rd := range pruned {
+		names[i] = rd.Name
+		prefixes[i] = outputPrefix + rd.Name + "/"
+	}
+
+	master, err := p.download(ctx, video.HLSURL)
+	if err != nil {
+		return nil, fmt.Errorf("download master playlist: %w", err)
+	}
+	master, err = removeVariantStreams(master, names)
+	if err != nil {
+		return nil, fmt.Errorf("rewrite master playlist: %w", err)
+	}
+	if err := p.upload(ctx, video.HLSURL, master, "application/vnd.apple.mpegurl"); err != nil {
+		return nil, fmt.Errorf("upload master playlist: %w", err)
+	}
+	if err := p.updateChecksums(ctx, video.HLSURL, master, prefixes); err != nil {
+		return nil, err
+	}
+
+	if _, err := deletePrefixes(ctx, p.storage, prefixes); err != nil {
+		return nil, err
+	}
+	if p.replica != nil {
+		if _, err := deletePrefixes(ctx, p.replica, prefixes); err != nil {
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+	}
+
+	if err := p.renditions.MarkPruned(ctx, videoID, video.OutputVersion, names, now); err != nil {
+		return nil, fmt.Errorf("mark renditions pruned: %w", err)
+	}
+
+	p.purgeCDN(ctx, videoID, append([]string{video.HLSURL}, prefixes...))
+	slog.InfoContext(ctx, "pruned unwatched renditions",
+		"video_id", videoID,
+		"output_version", video.OutputVersion,
+		"renditions", names,
+	)
+	return pruned, nil
+}
+
+// updateChecksums drops the pruned files from the output's checksum manifest and records
+// the rewritten master playlist, so verification keeps passing. Outputs without a
+// manifest are left without one.
+func (p *renditionPruner) updateChecksums(ctx context.Context, masterKey string, master []byte, prefixes []string) error {
+	key := outputChecksumsKey(masterKey)
+	sums, err := readOutputChecksums(ctx, p.storage, key)
+	if errors.Is(err, ErrOutputChecksumsNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for file := range sums.Files {
+		if slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(file, prefix) }) {
+			delete(sums.Files, file)
+		}
+	}
+	sum := sha256.Sum256(master)
+	sums.add(masterKey, int64(len(master)), sum[:])
+
+	data, err := json.MarshalIndent(sums, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal checksum manifest: %w", err)
+	}
+	if err := p.upload(ctx, key, data, "application/json"); err != nil {
+		return fmt.Errorf("upload checksum manifest: %w", err)
+	}
+	return nil
+}
+
+func (p *renditionPruner) download(ctx context.Context, key string) ([]byte, error) {
+	reader, err := p.storage.Download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxPlaylistBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPlaylistBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes", key, maxPlaylistBytes)
+	}
+	return data, nil
+}
+
+// upload writes to the primary, then the replica, like the transcode upload path.
+func (p *renditionPruner) upload(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := p.storage.Upload(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpUpload).Inc()
+		return err
+	}
+	if p.replica != nil {
+		if err := p.replica.Upload(ctx, key, bytes.NewReader(data), contentType); err != nil {
+			metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpReplicate).Inc()
+			return fmt.Errorf("replica: %w", err)
+		}
+	}
+	return nil
+}
+
+// purgeCDN evicts the rewritten master playlist and the pruned renditions from the CDN.
+// Errors are logged but not propagated - the old playlist still expires with its cache TTL.
+func (p *renditionPruner) purgeCDN(ctx context.Context, videoID uuid.UUID, prefixes []string) {
+	if p.purger == nil {
+		return
+	}
+
+	if err := p.purger.Purge(ctx, prefixes); err != nil {
+		slog.WarnContext(ctx, "failed to purge CDN",
+			"video_id", videoID,
+			"prefixes", prefixes,
+			"error", err,
+		)
+	}
+}
+
+// selectPrunableRenditions applies the pruning rule to the renditions of one output.
+// Nothing is pruned from an output younger than cutoff, from one with a single served
+// rendition, or from one without any recorded view, since then nothing is known about
+// what its viewers pick.
+func selectPrunableRenditions(renditions []*model.Rendition, cutoff time.Time) []*model.Rendition {
+	var served []*model.Rendition
+	tracked := false
+	for _, rd := range renditions {
+		if rd.IsPruned() {
+			continue
+		}
+		if !rd.CreatedAt.Before(cutoff) {
+			return nil
+		}
+		served = append(served, rd)
+		tracked = tracked || !rd.LastViewedAt.IsZero()
+	}
+	if len(served) < 2 || !tracked {
+		return nil
+	}
+
+	keep := served[0].Height
+	for _, rd := range served {
+		keep = min(keep, rd.Height)
+	}
+	for _, rd := range served {
+		if !rd.LastViewedAt.Before(cutoff) {
+			keep = max(keep, rd.Height)
+		}
+	}
+
+	var pruned []*model.Rendition
+	for _, rd := range served {
+		if rd.Height > keep {
+			pruned = append(pruned, rd)
+		}
+	}
+	return pruned
+}
+
+// removeVariantStreams drops the variant streams of the named renditions from a master
+// playlist. Variant playlists live at "{name}/playlist.m3u8".
+func removeVariantStreams(master []byte, names []string) ([]byte, error) {
+	playlist, err := hls.Parse(master)
+	if err != nil {
+		return nil, err
+	}
+	err = playlist.RemoveVariants(func(v hls.Variant) bool {
+		return slices.Contains(names, path.Dir(v.URI))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return playlist.Encode(), nil
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const testMasterPlaylist = "#EXTM3U\n#EXT-X-VERSION:3\n\n" +
+	"#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360\n360p/playlist.m3u8\n\n" +
+	"#EXT-X-STREAM-INF:BANDWIDTH=2800000,RESOLUTION=1280x720\n720p/playlist.m3u8\n\n" +
+	"#EXT-X-STREAM-INF:BANDWIDTH=5000000,RESOLUTION=1920x1080\n1080p/playlist.m3u8\n\n"
+
+func TestSelectPrunableRenditions(t *testing.T) {
+	cutoff := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := cutoff.Add(-24 * time.Hour)
+
+	rendition := func(name string, height int, createdAt, viewedAt time.Time) *model.Rendition {
+		return &model.Rendition{Name: name, Height: height, CreatedAt: createdAt, LastViewedAt: viewedAt}
+	}
+	pruned := rendition("480p", 480, old, time.Time{})
+	pruned.PrunedAt = old
+
+	tests := []struct {
+		name       string
+		renditions []*model.Rendition
+		want       []string
+	}{
+		{
+			name: "watched below the top rungs",
+			renditions: []*model.Rendition{
+				rendition("1080p", 1080, old, time.Time{}),
+				rendition("360p", 360, old, cutoff.Add(time.Hour)),
+				rendition("720p", 720, old, old),
+			},
+			want: []string{"1080p", "720p"},
+		},
+		{
+			name: "views before cutoff only keep the lowest",
+			renditions: []*model.Rendition{
+				rendition("360p", 360, old, time.Time{}),
+				rendition("720p", 720, old, old),
+			},
+			want: []string{"720p"},
+		},
+		{
+			name: "top rung watched",
+			renditions: []*model.Rendition{
+				rendition("360p", 360, old, time.Time{}),
+				rendition("720p", 720, old, cutoff),
+			},
+		},
+		{
+			name: "never viewed",
+			renditions: []*model.Rendition{
+				rendition("360p", 360, old, time.Time{}),
+				rendition("720p", 720, old, time.Time{}),
+			},
+		},
+		{
+			name: "young output",
+			renditions: []*model.Rendition{
+				rendition("360p", 360, cutoff, cutoff.Add(time.Hour)),
+				rendition("720p", 720, cutoff, time.Time{}),
+			},
+		},
+		{
+			name: "single served rendition",
+			renditions: []*model.Rendition{
+				pruned,
+				rendition("360p", 360, old, cutoff.Add(time.Hour)),
+			},
+		},
+		{
+			name: "already pruned renditions are skipped",
+			renditions: []*model.Rendition{
+				pruned,
+				rendition("360p", 360, old, cutoff.Add(time.Hour)),
+				rendition("720p", 720, old, time.Time{}),
+			},
+			want: []string{"720p"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rd := range selectPrunableRenditions(tt.renditions, cutoff) {
+				got = append(got, rd.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pruned: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveVariantStreams(t *testing.T) {
+	got, err := removeVariantStreams([]byte(testMasterPlaylist), []string{"720p", "1080p"})
+	if err != nil {
+		t.Fatalf("removeVariantStreams() error = %v", err)
+	}
+
+	want := "#EXTM3U\n#EXT-X-VERSION:3\n\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360\n360p/playlist.m3u8\n\n"
+	if string(got) != want {
+		t.Errorf("master:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestRenditionPruner_Prune(t *testing.T) {
+	videoID := uuid.New()
+	now := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-60 * 24 * time.Hour)
+
+	video := &model.Video{
+		ID:            videoID,
+		Status:        model.StatusReady,
+		HLSURL:        "hls/abc123/v2/master.m3u8",
+		OutputVersion: 2,
+	}
+	renditions := []*model.Rendition{
+		{Name: "360p", Height: 360, Bytes: 100, CreatedAt: old, LastViewedAt: now.Add(-time.Hour)},
+		{Name: "720p", Height: 720, Bytes: 300, CreatedAt: old},
+		{Name: "1080p", Height: 1080, Bytes: 600, CreatedAt: old},
+	}
+	checksums := OutputChecksums{
+		Algorithm: "sha256",
+		Files: map[string]OutputChecksum{
+			"hls/abc123/v2/master.m3u8":              {Size: 1},
+			"hls/abc123/v2/360p/segment_000.ts":      {Size: 2},
+			"hls/abc123/v2/720p/segment_000.ts":      {Size: 3},
+			"hls/abc123/v2/1080p/segment_000.ts":     {Size: 4},
+			"hls/abc123/v2/1080p/playlist.m3u8":      {Size: 5},
+			"hls/abc123/v2/1080p0/unrelated_file.ts": {Size: 6},
+		},
+	}
+	checksumData, _ := json.Marshal(checksums)
+
+	var (
+		uploaded   = map[string][]byte{}
+		replicated []string
+		deleted    []string
+		purged     []string
+		marked     []string
+	)
+	storage := &mockObjectStorage{
+		downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			switch key {
+			case "hls/abc123/v2/master.m3u8":
+				return io.NopCloser(strings.NewReader(testMasterPlaylist)), nil
+			case "hls/abc123/v2/" + OutputChecksumsFile:
+				return io.NopCloser(bytes.NewReader(checksumData)), nil
+			}
+			return nil, repository.ErrObjectNotFound
+		},
+		uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+			data, _ := io.ReadAll(reader)
+			uploaded[key] = data
+			return nil
+		},
+		deleteByPrefixFn: func(ctx context.Context, prefix string) (int, error) {
+			deleted = append(deleted, prefix)
+			return 1, nil
+		},
+	}
+	replica := &mockObjectStorage{
+		uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+			replicated = append(replicated, key)
+			return nil
+		},
+	}
+	videos := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return video, nil
+		},
+	}
+	renditionRepo := &mockRenditionRepository{
+		listPruneCandidatesFn: func(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+			if !cutoff.Equal(now.Add(-30 * 24 * time.Hour)) {
+				t.Errorf("cutoff: got %v", cutoff)
+			}
+			return []uuid.UUID{videoID}, nil
+		},
+		listByVideoIDFn: func(ctx context.Context, id uuid.UUID, outputVersion int64) ([]*model.Rendition, error) {
+			return renditions, nil
+		},
+		markPrunedFn: func(ctx context.Context, id uuid.UUID, outputVersion int64, names []string, at time.Time) error {
+			if outputVersion != 2 || !at.Equal(now) {
+				t.Errorf("MarkPruned: got version %d at %v", outputVersion, at)
+			}
+			marked = names
+			return nil
+		},
+	}
+	purger := &mockCDNPurger{
+		purgeFn: func(ctx context.Context, prefixes []string) error {
+			purged = prefixes
+			return nil
+		},
+	}
+
+	pruner := NewRenditionPruner(videos, renditionRepo, storage, replica, purger, DefaultRenditionPruneConfig()).(*renditionPruner)
+	pruner.now = func() time.Time { return now }
+
+	result, err := pruner.Prune(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != (RenditionPruneResult{Videos: 1, Renditions: 2, Bytes: 900}) {
+		t.Errorf("result: got %+v", *result)
+	}
+
+	wantMaster := "#EXTM3U\n#EXT-X-VERSION:3\n\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360\n360p/playlist.m3u8\n\n"
+	if got := string(uploaded["hls/abc123/v2/master.m3u8"]); got != wantMaster {
+		t.Errorf("master:\ngot  %q\nwant %q", got, wantMaster)
+	}
+	var gotSums OutputChecksums
+	if err := json.Unmarshal(uploaded["hls/abc123/v2/"+OutputChecksumsFile], &gotSums); err != nil {
+		t.Fatalf("failed to decode checksum manifest: %v", err)
+	}
+	var files []string
+	for file := range gotSums.Files {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+	wantFiles := []string{"hls/abc123/v2/1080p0/unrelated_file.ts", "hls/abc123/v2/360p/segment_000.ts", "hls/abc123/v2/master.m3u8"}
+	if !slices.Equal(files, wantFiles) {
+		t.Errorf("checksum files: got %v, want %v", files, wantFiles)
+	}
+	if got := gotSums.Files["hls/abc123/v2/master.m3u8"].Size; got != int64(len(wantMaster)) {
+		t.Errorf("master checksum size: got %d, want %d", got, len(wantMaster))
+	}
+
+	wantPrefixes := []string{"hls/abc123/v2/720p/", "hls/abc123/v2/1080p/"}
+	if !slices.Equal(deleted, wantPrefixes) {
+		t.Errorf("deleted: got %v, want %v", deleted, wantPrefixes)
+	}
+	if !slices.Equal(replicated, []string{"hls/abc123/v2/master.m3u8", "hls/abc123/v2/" + OutputChecksumsFile}) {
+		t.Errorf("replicated: got %v", replicated)
+	}
+	if !slices.Equal(marked, []string{"720p", "1080p"}) {
+		t.Errorf("marked pruned: got %v", marked)
+	}
+	if !slices.Equal(purged, append([]string{"hls/abc123/v2/master.m3u8"}, wantPrefixes...)) {
+		t.Errorf("purged: got %v", purged)
+	}
+}
+
+func TestRenditionPruner_Prune_Skips(t *testing.T) {
+	old := time.Now().Add(-60 * 24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		video  *model.Video
+		getErr error
+	}{
+		{name: "DASH output", video: &model.Video{Status: model.StatusReady, HLSURL: "hls/x/v1/master.m3u8", DashURL: "dash/x/v1/manifest.mpd"}},
+		{name: "not ready", video: &model.Video{Status: model.StatusDeleted, HLSURL: "hls/x/v1/master.m3u8"}},
+		{name: "lookup fails", getErr: errors.New("db down")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return tt.video, tt.getErr
+				},
+			}
+			renditions := &mockRenditionRepository{
+				listPruneCandidatesFn: func(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+					return []uuid.UUID{uuid.New()}, nil
+				},
+				listByVideoIDFn: func(ctx context.Context, id uuid.UUID, outputVersion int64) ([]*model.Rendition, error) {
+					return []*model.Rendition{
+						{Name: "360p", Height: 360, CreatedAt: old, LastViewedAt: time.Now()},
+						{Name: "720p", Height: 720, CreatedAt: old},
+					}, nil
+				},
+				markPrunedFn: func(ctx context.Context, id uuid.UUID, outputVersion int64, names []string, at time.Time) error {
+					t.Error("MarkPruned called")
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					t.Errorf("unexpected upload of %s", key)
+					return nil
+				},
+			}
+
+			result, err := NewRenditionPruner(videos, renditions, storage, nil, nil, DefaultRenditionPruneConfig()).Prune(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Videos != 0 || result.Renditions != 0 {
+				t.Errorf("result: got %+v", *result)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+// encryptVariants returns variants set to encrypt their segments with the video's AES-128
+// key, creating the key on the video's first encrypted transcode. The key and FFmpeg key
+// info files are written to workDir, outside the uploaded output. Returns variants
+// unchanged when encryption is not configured.
+func (s *transcodeService) encryptVariants(ctx context.Context, videoID uuid.UUID, workDir string, variants []transcoder.Variant) ([]transcoder.Variant, error) {
+	if s.keys == nil {
+		return variants, nil
+	}
+
+	key, err := s.keys.GetOrCreate(ctx, model.NewEncryptionKey(videoID))
+	if err != nil {
+		return nil, fmt.Errorf("get encryption key: %w", err)
+	}
+
+	keyPath := filepath.Join(workDir, "hls.key")
+	if err := os.WriteFile(keyPath, key.Key, 0600); err != nil {
+		return nil, fmt.Errorf("write encryption key: %w", err)
+	}
+	// Without an IV line FFmpeg uses the media sequence number, as RFC 8216 allows
+	keyInfoPath := filepath.Join(workDir, "hls.keyinfo")
+	keyInfo := encryptionKeyURI(videoID) + "\n" + keyPath + "\n"
+	if err := os.WriteFile(keyInfoPath, []byte(keyInfo), 0600); err != nil {
+		return nil, fmt.Errorf("write key info: %w", err)
+	}
+
+	// The ladder may be shared with other tasks
+	encrypted := slices.Clone(variants)
+	for i := range encrypted {
+		encrypted[i].KeyInfoPath = keyInfoPath
+	}
+	return encrypted, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+func TestTranscodeService_EncryptVariants(t *testing.T) {
+	videoID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	ladder := transcoder.DefaultABRVariants()
+	stored := bytes.Repeat([]byte{0x42}, model.EncryptionKeySize)
+
+	keys := &mockEncryptionKeyRepository{
+		getOrCreateFn: func(ctx context.Context, key *model.EncryptionKey) (*model.EncryptionKey, error) {
+			if key.VideoID != videoID || len(key.Key) != model.EncryptionKeySize {
+				t.Errorf("unexpected new key: %+v", key)
+			}
+			// An earlier transcode already created the video's key
+			return &model.EncryptionKey{VideoID: videoID, Key: stored}, nil
+		},
+	}
+	svc := NewTranscodeService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, keys, nil, nil, nil, nil, TranscodeServiceConfig{}).(*transcodeService)
+
+	workDir := t.TempDir()
+	got, err := svc.encryptVariants(context.Background(), videoID, workDir, ladder)
+	if err != nil {
+		t.Fatalf("encryptVariants() error = %v", err)
+	}
+
+	if len(got) != len(ladder) {
+		t.Fatalf("encryptVariants() returned %d variants, want %d", len(got), len(ladder))
+	}
+	for i, v := range got {
+		if v.KeyInfoPath == "" || v.Name != ladder[i].Name {
+			t.Errorf("variant %d = %+v, want %s with a key info file", i, v, ladder[i].Name)
+		}
+		if ladder[i].KeyInfoPath != "" {
+			t.Errorf("ladder variant %s was modified", ladder[i].Name)
+		}
+	}
+
+	info, err := os.ReadFile(got[0].KeyInfoPath)
+	if err != nil {
+		t.Fatalf("read key info: %v", err)
+	}
+	keyPath := filepath.Join(workDir, "hls.key")
+	if want := "/v1/videos/22222222-2222-2222-2222-222222222222/key\n" + keyPath + "\n"; string(info) != want {
+		t.Errorf("key info = %q, want %q", info, want)
+	}
+	if key, err := os.ReadFile(keyPath); err != nil || !bytes.Equal(key, stored) {
+		t.Errorf("key file = %x (%v), want the stored key", key, err)
+	}
+
+	// Without a key store the segments stay in the clear
+	svc.keys = nil
+	got, err = svc.encryptVariants(context.Background(), videoID, t.TempDir(), ladder)
+	if err != nil || got[0].KeyInfoPath != "" {
+		t.Errorf("encryptVariants() without keys = %+v, %v", got, err)
+	}
+}
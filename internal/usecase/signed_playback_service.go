@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// SignedPlaybackConfig holds configuration for SignedPlaybackService.
+type SignedPlaybackConfig struct {
+	// Keys are the accepted HMAC-SHA256 keys; the first signs new URLs. Listing the
+	// previous key after a new one allows rotation without breaking issued URLs.
+	Keys [][]byte
+	// TTL is the lifetime of issued URLs. Every segment request is checked against it,
+	// so it must outlast the longest playback session.
+	TTL time.Duration
+	// BaseURL is where the origin gateway verifying signed URLs is mounted,
+	// e.g. "https://cdn.example.com/s".
+	BaseURL string
+}
+
+// DefaultSignedPlaybackConfig returns the default configuration for keys.
+func DefaultSignedPlaybackConfig(keys ...[]byte) SignedPlaybackConfig {
+	return SignedPlaybackConfig{
+		Keys:    keys,
+		TTL:     4 * time.Hour,
+		BaseURL: "http://localhost:8081/s",
+	}
+}
+
+// IssueSignedPlaybackInput contains the input parameters for issuing a signed playback URL.
+type IssueSignedPlaybackInput struct {
+	VideoID uuid.UUID
+	UserID  uuid.UUID
+}
+
+// SignedPlayback is an issued signed playback URL.
+type SignedPlayback struct {
+	// URL points at the video's master playlist behind the origin gateway:
+	// {BaseURL}/{token}/{master playlist key}. Playlists reference their media relatively,
+	// so every request of the player carries the token in its path.
+	URL    string
+	Claims model.SignedPlaybackClaims
+}
+
+// SignedPlaybackService issues and verifies stateless signed playback URLs.
+type SignedPlaybackService interface {
+	// Issue signs a URL to the video's HLS output for a user. Returns ErrVideoNotReady
+	// if the video has no published HLS output and ErrNotEntitled if the user lacks access.
+	// A private video is reported as repository.ErrVideoNotFound to anyone but its owner.
+	Issue(ctx context.Context, input IssueSignedPlaybackInput) (*SignedPlayback, error)
+
+	// Verify checks that token is correctly signed, unexpired and grants access to the
+	// object key, without any lookup. Returns ErrInvalidPlaybackToken on any failure.
+	Verify(token, key string) (*model.SignedPlaybackClaims, error)
+}
+
+type signedPlaybackService struct {
+	repo         repository.VideoRepository
+	entitlements repository.EntitlementChecker
+
+	keys    [][]byte
+	ttl     time.Duration
+	baseURL string
+	now     func() time.Time
+}
+
+// NewSignedPlaybackService creates a new SignedPlaybackService instance; cfg.Keys must
+// hold at least one key.
+// The entitlements parameter is optional - pass nil to allow any user to stream any READY video.
+func NewSignedPlaybackService(
+	repo repository.VideoRepository,
+	entitlements repository.EntitlementChecker,
+	cfg SignedPlaybackConfig,
+) SignedPlaybackService {
+	return &signedPlaybackService{
+		repo:         repo,
+		entitlements: entitlements,
+		keys:         cfg.Keys,
+		ttl:          cfg.TTL,
+		baseURL:      strings.TrimSuffix(cfg.BaseURL, "/"),
+		now:          time.Now,
+	}
+}
+
+// signedClaims is the wire form of model.SignedPlaybackClaims.
+type signedClaims struct {
+	VideoID   uuid.UUID `json:"vid"`
+	UserID    uuid.UUID `json:"uid"`
+	Prefix    string    `json:"pfx"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+func (s *signedPlaybackService) Issue(ctx context.Context, input IssueSignedPlaybackInput) (*SignedPlayback, error) {
+	if input.UserID == uuid.Nil {
+		return nil, model.ErrInvalidUserID
+	}
+
+	video, err := hideDeleted(s.repo.GetByID(ctx, input.VideoID))
+	if err != nil {
+		return nil, err
+	}
+	if !video.VisibleTo(input.UserID) {
+		return nil, repository.ErrVideoNotFound
+	}
+	if !video.IsReady() || video.HLSURL == "" {
+		return nil, ErrVideoNotReady
+	}
+	if err := checkEntitlement(ctx, s.entitlements, video, input.UserID); err != nil {
+		return nil, err
+	}
+
+	claims := model.SignedPlaybackClaims{
+		VideoID: video.ID,
+		UserID:  input.UserID,
+		Prefix:  path.Dir(video.HLSURL) + "/",
+		// Whole seconds, as carried in the token
+		ExpiresAt: s.now().Add(s.ttl).Truncate(time.Second),
+	}
+	token, err := s.sign(claims)
+	if err != nil {
+		return nil, fmt.Errorf("sign playback URL: %w", err)
+	}
+
+	return &SignedPlayback{
+		URL:    s.baseURL + "/" + token + "/" + video.HLSURL,
+		Claims: claims,
+	}, nil
+}
+
+func (s *signedPlaybackService) Verify(token, key string) (*model.SignedPlaybackClaims, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidPlaybackToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !s.validMAC(payload, mac) {
+		return nil, ErrInvalidPlaybackToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidPlaybackToken
+	}
+	var wire signedClaims
+	if err := json.Unmarshal(raw, &wire); err != nil {
+		return nil, ErrInvalidPlaybackToken
+	}
+	claims := &model.SignedPlaybackClaims{
+		VideoID:   wire.VideoID,
+		UserID:    wire.UserID,
+		Prefix:    wire.Prefix,
+		ExpiresAt: time.Unix(wire.ExpiresAt, 0),
+	}
+
+	// A key that is not already clean could climb out of the prefix with ".."
+	if claims.IsExpired(s.now()) || path.Clean("/"+key) != "/"+key || !strings.HasPrefix(key, claims.Prefix) {
+		return nil, ErrInvalidPlaybackToken
+	}
+	return claims, nil
+}
+
+// sign encodes claims as {base64url(JSON)}.{base64url(HMAC-SHA256 of the first part)}.
+func (s *signedPlaybackService) sign(claims model.SignedPlaybackClaims) (string, error) {
+	raw, err := json.Marshal(signedClaims{
+		VideoID:   claims.VideoID,
+		UserID:    claims.UserID,
+		Prefix:    claims.Prefix,
+		ExpiresAt: claims.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(playbackMAC(s.keys[0], payload)), nil
+}
+
+// validMAC reports whether mac signs payload under any accepted key.
+func (s *signedPlaybackService) validMAC(payload string, mac []byte) bool {
+	for _, key := range s.keys {
+		if hmac.Equal(mac, playbackMAC(key, payload)) {
+			return true
+		}
+	}
+	return false
+}
+
+func playbackMAC(key []byte, payload string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+var (
+	testSigningKey    = []byte("0123456789abcdef0123456789abcdef")
+	testOldSigningKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func TestSignedPlaybackService_Issue(t *testing.T) {
+	videoID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	ownerID := uuid.New()
+	viewerID := uuid.New()
+
+	ready := &model.Video{ID: videoID, UserID: ownerID, Status: model.StatusReady, HLSURL: "hls/abc/v2/master.m3u8"}
+	private := &model.Video{ID: videoID, UserID: ownerID, Status: model.StatusReady, Visibility: model.VisibilityPrivate, HLSURL: "hls/abc/v2/master.m3u8"}
+
+	tests := []struct {
+		name     string
+		video    *model.Video
+		getErr   error
+		userID   uuid.UUID
+		entitled bool
+		wantErr  error
+	}{
+		{name: "owner", video: ready, userID: ownerID},
+		{name: "entitled viewer", video: ready, userID: viewerID, entitled: true},
+		{name: "not entitled", video: ready, userID: viewerID, wantErr: ErrNotEntitled},
+		{name: "missing user", video: ready, wantErr: model.ErrInvalidUserID},
+		{name: "processing", video: &model.Video{ID: videoID, UserID: ownerID, Status: model.StatusProcessing}, userID: ownerID, wantErr: ErrVideoNotReady},
+		{name: "private video for owner", video: private, userID: ownerID},
+		{name: "private video hidden from entitled viewer", video: private, userID: viewerID, entitled: true, wantErr: repository.ErrVideoNotFound},
+		{name: "not found", getErr: repository.ErrVideoNotFound, userID: ownerID, wantErr: repository.ErrVideoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return tt.video, tt.getErr
+				},
+			}
+			entitlements := &mockEntitlementChecker{
+				isEntitledFn: func(ctx context.Context, userID, videoID uuid.UUID) (bool, error) {
+					return tt.entitled, nil
+				},
+			}
+			svc := NewSignedPlaybackService(repo, entitlements, SignedPlaybackConfig{
+				Keys:    [][]byte{testSigningKey},
+				TTL:     time.Hour,
+				BaseURL: "https://cdn.example.com/s/",
+			})
+
+			got, err := svc.Issue(context.Background(), IssueSignedPlaybackInput{VideoID: videoID, UserID: tt.userID})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Issue() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Issue() error = %v", err)
+			}
+
+			rest, ok := strings.CutPrefix(got.URL, "https://cdn.example.com/s/")
+			if !ok {
+				t.Fatalf("URL = %q, want it under the base URL", got.URL)
+			}
+			token, key, _ := strings.Cut(rest, "/")
+			if key != "hls/abc/v2/master.m3u8" {
+				t.Errorf("URL key = %q, want the master playlist", key)
+			}
+
+			claims, err := svc.Verify(token, key)
+			if err != nil {
+				t.Fatalf("Verify() of issued URL error = %v", err)
+			}
+			if claims.VideoID != videoID || claims.UserID != tt.userID || claims.Prefix != "hls/abc/v2/" || !claims.ExpiresAt.Equal(got.Claims.ExpiresAt) {
+				t.Errorf("Verify() claims = %+v, want %+v", claims, got.Claims)
+			}
+		})
+	}
+}
+
+func TestSignedPlaybackService_Verify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	claims := model.SignedPlaybackClaims{
+		VideoID:   uuid.New(),
+		UserID:    uuid.New(),
+		Prefix:    "hls/abc/v2/",
+		ExpiresAt: now.Add(time.Hour),
+	}
+	sign := func(key []byte, c model.SignedPlaybackClaims) string {
+		svc := NewSignedPlaybackService(nil, nil, SignedPlaybackConfig{Keys: [][]byte{key}}).(*signedPlaybackService)
+		token, err := svc.sign(c)
+		if err != nil {
+			t.Fatalf("sign() error = %v", err)
+		}
+		return token
+	}
+	valid := sign(testSigningKey, claims)
+	expired := claims
+	expired.ExpiresAt = now
+	payload, _, _ := strings.Cut(valid, ".")
+
+	tests := []struct {
+		name    string
+		token   string
+		key     string
+		wantErr bool
+	}{
+		{name: "segment under the prefix", token: valid, key: "hls/abc/v2/720p/segment_003.ts"},
+		{name: "signed with a previous key", token: sign(testOldSigningKey, claims), key: "hls/abc/v2/master.m3u8"},
+		{name: "other output", token: valid, key: "hls/abc/v1/master.m3u8", wantErr: true},
+		{name: "path traversal", token: valid, key: "hls/abc/v2/../../xyz/v1/master.m3u8", wantErr: true},
+		{name: "expired", token: sign(testSigningKey, expired), key: "hls/abc/v2/master.m3u8", wantErr: true},
+		{name: "unknown key", token: sign([]byte("another key of at least 32 bytes!"), claims), key: "hls/abc/v2/master.m3u8", wantErr: true},
+		{name: "tampered payload", token: payload + "x." + strings.SplitN(valid, ".", 2)[1], key: "hls/abc/v2/master.m3u8", wantErr: true},
+		{name: "malformed", token: "not-a-token", key: "hls/abc/v2/master.m3u8", wantErr: true},
+		{name: "empty", key: "hls/abc/v2/master.m3u8", wantErr: true},
+	}
+
+	svc := NewSignedPlaybackService(nil, nil, SignedPlaybackConfig{Keys: [][]byte{testSigningKey, testOldSigningKey}}).(*signedPlaybackService)
+	svc.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Verify(tt.token, tt.key)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPlaybackToken) {
+					t.Errorf("Verify() error = %v, want ErrInvalidPlaybackToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if *got != claims {
+				t.Errorf("Verify() = %+v, want %+v", got, claims)
+			}
+		})
+	}
+}
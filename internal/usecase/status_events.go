@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const (
+	// DefaultStatusEventsLimit is the number of status events returned when no limit is given.
+	DefaultStatusEventsLimit = 50
+	// MaxStatusEventsLimit caps the number of status events returned in one request.
+	MaxStatusEventsLimit = 500
+)
+
+// recordStatusEvent appends the video's transition from the given status to its current
+// one to the audit log. Errors are logged but not propagated - the log is for debugging,
+// and the transition itself has already been persisted.
+func recordStatusEvent(ctx context.Context, events repository.VideoStatusEventRepository, video *model.Video, from model.Status, actor model.StatusActor, reason string) {
+	if events == nil {
+		return
+	}
+
+	event := model.NewVideoStatusEvent(video.ID, from, video.Status, actor, reason)
+	if err := events.Create(ctx, event); err != nil {
+		slog.WarnContext(ctx, "failed to record video status event",
+			"video_id", video.ID,
+			"from", from,
+			"to", video.Status,
+			"error", err,
+		)
+	}
+}
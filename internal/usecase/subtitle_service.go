@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/subtitle"
+)
+
+// MaxSubtitleBytes caps the size of an uploaded subtitle file.
+const MaxSubtitleBytes = 2 << 20 // 2 MiB
+
+var (
+	// ErrInvalidSubtitleFile is returned when an uploaded subtitle file cannot be parsed.
+	ErrInvalidSubtitleFile = errs.New(errs.Invalid, "subtitle file cannot be parsed")
+
+	// ErrSubtitleTooLarge is returned when an uploaded subtitle file exceeds MaxSubtitleBytes.
+	ErrSubtitleTooLarge = errs.New(errs.Invalid, "subtitle file is too large")
+)
+
+// UploadSubtitleInput contains the data needed to add a subtitle track to a video.
+type UploadSubtitleInput struct {
+	VideoID  uuid.UUID
+	Language string
+	Label    string
+	// Format is "srt" or "vtt"; empty detects it from the content.
+	Format  string
+	Default bool
+	Data    io.Reader
+}
+
+// SubtitleService manages the subtitle tracks of videos.
+type SubtitleService interface {
+	// Upload stores a subtitle file as the video's track for its language, replacing any
+	// previous one. The worker converts it to segmented WebVTT: at the next transcode, or
+	// right away for a READY video, whose published HLS output gets the track added.
+	// Returns ErrInvalidSubtitleFile or ErrSubtitleTooLarge for unusable files.
+	Upload(ctx context.Context, input UploadSubtitleInput) (*model.Subtitle, error)
+
+	// List returns the subtitle tracks of a video ordered by language.
+	// Returns repository.ErrVideoNotFound if the video is not visible to callerID.
+	List(ctx context.Context, videoID, callerID uuid.UUID) ([]*model.Subtitle, error)
+}
+
+type subtitleService struct {
+	videos    repository.VideoRepository
+	subtitles repository.SubtitleRepository
+	storage   repository.ObjectStorage
+	queue     repository.MessageQueue
+	now       func() time.Time
+}
+
+// NewSubtitleService creates a new SubtitleService instance.
+func NewSubtitleService(
+	videos repository.VideoRepository,
+	subtitles repository.SubtitleRepository,
+	storage repository.ObjectStorage,
+	queue repository.MessageQueue,
+) SubtitleService {
+	return &subtitleService{
+		videos:    videos,
+		subtitles: subtitles,
+		storage:   storage,
+		queue:     queue,
+		now:       time.Now,
+	}
+}
+
+func (s *subtitleService) Upload(ctx context.Context, input UploadSubtitleInput) (*model.Subtitle, error) {
+	video, err := hideDeleted(s.videos.GetByID(ctx, input.VideoID))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(input.Data, MaxSubtitleBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read subtitle: %w", err)
+	}
+	if len(data) > MaxSubtitleBytes {
+		return nil, ErrSubtitleTooLarge
+	}
+
+	format := input.Format
+	if format == "" {
+		format = detectSubtitleFormat(data)
+	}
+	sub, err := model.NewSubtitle(video.ID, input.Language, input.Label, format, input.Default)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := subtitle.Parse(data, subtitle.Format(sub.Format)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSubtitleFile, err)
+	}
+
+	sub.SourceKey = generateSubtitleSourceKey(video.StoragePrefix(), sub.Language, sub.Format)
+	if err := s.storage.Upload(ctx, sub.SourceKey, bytes.NewReader(data), subtitleContentType(sub.Format)); err != nil {
+		return nil, fmt.Errorf("upload subtitle: %w", err)
+	}
+	if err := s.subtitles.Save(ctx, sub); err != nil {
+		return nil, fmt.Errorf("save subtitle: %w", err)
+	}
+
+	// Other videos pick the track up when they are transcoded
+	if video.IsReady() && video.HLSURL != "" {
+		task := repository.TranscodeTask{
+			VideoID:       video.ID,
+			OutputKey:     path.Dir(video.HLSURL) + "/",
+			OutputVersion: video.OutputVersion,
+			EnqueuedAt:    s.now(),
+			SubtitlesOnly: true,
+		}
+		if err := s.queue.PublishTranscodeTask(ctx, task); err != nil {
+			return nil, fmt.Errorf("publish subtitle task: %w", err)
+		}
+	}
+
+	return sub, nil
+}
+
+func (s *subtitleService) List(ctx context.Context, videoID, callerID uuid.UUID) ([]*model.Subtitle, error) {
+	video, err := hideDeleted(s.videos.GetByID(ctx, videoID))
+	if err != nil {
+		return nil, err
+	}
+	if !video.VisibleTo(callerID) {
+		return nil, repository.ErrVideoNotFound
+	}
+	return s.subtitles.ListByVideoID(ctx, videoID)
+}
+
+// detectSubtitleFormat tells WebVTT, which must start with its header, from SRT.
+func detectSubtitleFormat(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if strings.HasPrefix(string(data), "WEBVTT") {
+		return model.SubtitleFormatWebVTT
+	}
+	return model.SubtitleFormatSRT
+}
+
+func subtitleContentType(format string) string {
+	if format == model.SubtitleFormatWebVTT {
+		return "text/vtt"
+	}
+	return "application/x-subrip"
+}
+
+// generateSubtitleSourceKey creates the storage key of an uploaded subtitle file.
+// Uploads are not versioned: every output version is built from the current file.
+// Format: subtitles/{storage_prefix}/{language}.{format}
+func generateSubtitleSourceKey(storagePrefix, language, format string) string {
+	return path.Join("subtitles", storagePrefix, language+"."+format)
+}
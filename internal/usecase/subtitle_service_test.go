@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const testSRT = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+
+func TestSubtitleService_Upload(t *testing.T) {
+	videoID := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	tests := []struct {
+		name       string
+		status     model.Status
+		deleted    bool
+		input      UploadSubtitleInput
+		wantKey    string
+		wantFormat string
+		wantTask   bool
+		wantErr    error
+	}{
+		{
+			name:       "ready video gets the track added",
+			status:     model.StatusReady,
+			input:      UploadSubtitleInput{Language: "en", Data: strings.NewReader(testSRT)},
+			wantKey:    "subtitles/6ba7b810-9dad-11d1-80b4-00c04fd430c8/en.srt",
+			wantFormat: model.SubtitleFormatSRT,
+			wantTask:   true,
+		},
+		{
+			name:       "processing video picks it up when transcoded",
+			status:     model.StatusProcessing,
+			input:      UploadSubtitleInput{Language: "ja", Data: strings.NewReader("WEBVTT\n\n00:01.000 --> 00:02.000\nこんにちは\n")},
+			wantKey:    "subtitles/6ba7b810-9dad-11d1-80b4-00c04fd430c8/ja.vtt",
+			wantFormat: model.SubtitleFormatWebVTT,
+		},
+		{
+			name:    "unparsable file",
+			status:  model.StatusReady,
+			input:   UploadSubtitleInput{Language: "en", Format: "vtt", Data: strings.NewReader(testSRT)},
+			wantErr: ErrInvalidSubtitleFile,
+		},
+		{
+			name:    "too large",
+			status:  model.StatusReady,
+			input:   UploadSubtitleInput{Language: "en", Data: io.LimitReader(zeroReader{}, MaxSubtitleBytes+1)},
+			wantErr: ErrSubtitleTooLarge,
+		},
+		{
+			name:    "invalid language",
+			status:  model.StatusReady,
+			input:   UploadSubtitleInput{Language: "en_US!", Data: strings.NewReader(testSRT)},
+			wantErr: model.ErrInvalidSubtitleLanguage,
+		},
+		{
+			name:    "deleted video",
+			status:  model.StatusDeleted,
+			input:   UploadSubtitleInput{Language: "en", Data: strings.NewReader(testSRT)},
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: videoID, Status: tt.status, OutputVersion: 3}
+			if tt.status == model.StatusReady {
+				video.HLSURL = "hls/6ba7b810-9dad-11d1-80b4-00c04fd430c8/v3/master.m3u8"
+			}
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) { return video, nil },
+			}
+			var uploaded string
+			storage := &mockObjectStorage{
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					uploaded = key
+					return nil
+				},
+			}
+			var saved *model.Subtitle
+			subtitles := &mockSubtitleRepository{
+				saveFn: func(ctx context.Context, sub *model.Subtitle) error {
+					saved = sub
+					return nil
+				},
+			}
+			var task *repository.TranscodeTask
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, tk repository.TranscodeTask) error {
+					task = &tk
+					return nil
+				},
+			}
+
+			tt.input.VideoID = videoID
+			svc := NewSubtitleService(videos, subtitles, storage, queue)
+			sub, err := svc.Upload(context.Background(), tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Upload() error = %v, want %v", err, tt.wantErr)
+				}
+				if saved != nil || task != nil {
+					t.Errorf("failed upload saved %v and published %v", saved, task)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+
+			if sub.SourceKey != tt.wantKey || uploaded != tt.wantKey || saved != sub {
+				t.Errorf("stored %q, saved %v, want %q", uploaded, saved, tt.wantKey)
+			}
+			if sub.Format != tt.wantFormat {
+				t.Errorf("Format = %q, want %q", sub.Format, tt.wantFormat)
+			}
+			if (task != nil) != tt.wantTask {
+				t.Fatalf("published task = %v, want %v", task, tt.wantTask)
+			}
+			if task != nil && (!task.SubtitlesOnly || task.OutputKey != "hls/6ba7b810-9dad-11d1-80b4-00c04fd430c8/v3/" || task.OutputVersion != 3) {
+				t.Errorf("task = %+v, want subtitles-only task for v3", *task)
+			}
+		})
+	}
+}
+
+func TestSubtitleService_List(t *testing.T) {
+	ownerID := uuid.New()
+
+	tests := []struct {
+		name       string
+		visibility model.Visibility
+		callerID   uuid.UUID
+		wantErr    error
+	}{
+		{name: "unlisted video", visibility: model.VisibilityUnlisted},
+		{name: "private video of the caller", visibility: model.VisibilityPrivate, callerID: ownerID},
+		{name: "private video of another user", visibility: model.VisibilityPrivate, callerID: uuid.New(), wantErr: repository.ErrVideoNotFound},
+		{name: "private video, anonymous caller", visibility: model.VisibilityPrivate, wantErr: repository.ErrVideoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, UserID: ownerID, Status: model.StatusReady, Visibility: tt.visibility}, nil
+				},
+			}
+			subtitles := &mockSubtitleRepository{
+				listByVideoIDFn: func(ctx context.Context, id uuid.UUID) ([]*model.Subtitle, error) {
+					return []*model.Subtitle{{VideoID: id, Language: "en"}}, nil
+				},
+			}
+
+			svc := NewSubtitleService(videos, subtitles, &mockObjectStorage{}, &mockMessageQueue{})
+			got, err := svc.List(context.Background(), uuid.New(), tt.callerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("List() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(got) != 1 {
+				t.Errorf("List() = %v, want one track", got)
+			}
+		})
+	}
+}
+
+// zeroReader is an endless stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/hls"
+	"github.com/hszk-dev/gostream/internal/subtitle"
+)
+
+// subtitleGroupID is the EXT-X-MEDIA group every variant stream references for subtitles.
+const subtitleGroupID = "subs"
+
+// subtitleTrack is a subtitle converted to segmented WebVTT in a local directory.
+type subtitleTrack struct {
+	subtitle *model.Subtitle
+	// dir holds playlist.m3u8 and the segments.
+	dir      string
+	segments []string
+}
+
+// subtitleTrackDir is the directory of a track under an output prefix.
+func subtitleTrackDir(language string) string {
+	return "subtitles/" + language + "/"
+}
+
+// buildSubtitleTracks converts every subtitle of a video to segmented WebVTT under
+// workDir, with segments covering duration (or up to the last cue, if zero). Subtitles
+// that no longer parse are skipped with a warning rather than failing the output.
+// Returns nil when subtitles are not configured or the video has none.
+func (s *transcodeService) buildSubtitleTracks(ctx context.Context, videoID uuid.UUID, workDir string, duration time.Duration) ([]subtitleTrack, error) {
+	if s.subtitles == nil {
+		return nil, nil
+	}
+
+	subtitles, err := s.subtitles.ListByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("list subtitles: %w", err)
+	}
+
+	var tracks []subtitleTrack
+	for _, sub := range subtitles {
+		data, err := downloadSmall(ctx, s.storage, sub.SourceKey, MaxSubtitleBytes)
+		if err != nil {
+			return nil, fmt.Errorf("download %s subtitle: %w", sub.Language, err)
+		}
+		cues, err := subtitle.Parse(data, subtitle.Format(sub.Format))
+		if err != nil {
+			slog.WarnContext(ctx, "skipping unparsable subtitle",
+				"video_id", videoID,
+				"language", sub.Language,
+				"error", err,
+			)
+			continue
+		}
+
+		track := subtitleTrack{subtitle: sub, dir: filepath.Join(workDir, "subtitles", sub.Language)}
+		if err := os.MkdirAll(track.dir, 0755); err != nil {
+			return nil, fmt.Errorf("create subtitle directory: %w", err)
+		}
+		segments := subtitle.Split(cues, subtitle.DefaultSegmentDuration, duration)
+		for _, seg := range segments {
+			if err := os.WriteFile(filepath.Join(track.dir, seg.Name), seg.Data, 0644); err != nil {
+				return nil, fmt.Errorf("write subtitle segment: %w", err)
+			}
+			track.segments = append(track.segments, seg.Name)
+		}
+		if err := os.WriteFile(filepath.Join(track.dir, "playlist.m3u8"), subtitle.MediaPlaylist(segments), 0644); err != nil {
+			return nil, fmt.Errorf("write subtitle playlist: %w", err)
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
+
+// uploadSubtitleTracks uploads the tracks under outputKeyPrefix. Returns the bytes uploaded.
+func (s *transcodeService) uploadSubtitleTracks(ctx context.Context, outputKeyPrefix string, tracks []subtitleTrack, sums *OutputChecksums) (int64, error) {
+	var uploaded int64
+	for _, track := range tracks {
+		prefix := outputKeyPrefix + subtitleTrackDir(track.subtitle.Language)
+		for _, name := range track.segments {
+			n, err := s.uploadFile(ctx, filepath.Join(track.dir, name), prefix+name, "text/vtt", sums)
+			if err != nil {
+				return 0, fmt.Errorf("upload %s subtitle segment %s: %w", track.subtitle.Language, name, err)
+			}
+			uploaded += n
+		}
+		// The playlist goes last, so it never lists a segment that is not stored yet
+		n, err := s.uploadFile(ctx, filepath.Join(track.dir, "playlist.m3u8"), prefix+"playlist.m3u8", "application/vnd.apple.mpegurl", sums)
+		if err != nil {
+			return 0, fmt.Errorf("upload %s subtitle playlist: %w", track.subtitle.Language, err)
+		}
+		uploaded += n
+	}
+	return uploaded, nil
+}
+
+// withSubtitleTracks replaces the subtitle renditions of a master playlist with tracks
+// and points every variant stream at them; without tracks the subtitles are removed.
+func withSubtitleTracks(master []byte, tracks []subtitleTrack) ([]byte, error) {
+	playlist, err := hls.Parse(master)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.RemoveMedia(func(m hls.Media) bool { return m.Type == "SUBTITLES" && m.GroupID == subtitleGroupID })
+	media := make([]hls.Media, len(tracks))
+	for i, track := range tracks {
+		media[i] = hls.Media{
+			Type:       "SUBTITLES",
+			GroupID:    subtitleGroupID,
+			Name:       track.subtitle.Label,
+			Language:   track.subtitle.Language,
+			URI:        subtitleTrackDir(track.subtitle.Language) + "playlist.m3u8",
+			Default:    track.subtitle.Default,
+			Autoselect: true,
+		}
+	}
+	playlist.InsertMedia(media...)
+
+	group := ""
+	if len(tracks) > 0 {
+		group = subtitleGroupID
+	}
+	playlist.SetSubtitles(group)
+	return playlist.Encode(), nil
+}
+
+// addSubtitleTracks builds the video's subtitle tracks and lists them in the local master
+// playlist of a fresh HLS output.
+func (s *transcodeService) addSubtitleTracks(ctx context.Context, videoID uuid.UUID, workDir, masterPath string, duration time.Duration) ([]subtitleTrack, error) {
+	tracks, err := s.buildSubtitleTracks(ctx, videoID, workDir, duration)
+	if err != nil || len(tracks) == 0 {
+		return nil, err
+	}
+
+	master, err := os.ReadFile(masterPath)
+	if err != nil {
+		return nil, fmt.Errorf("read master playlist: %w", err)
+	}
+	master, err = withSubtitleTracks(master, tracks)
+	if err != nil {
+		return nil, fmt.Errorf("rewrite master playlist: %w", err)
+	}
+	if err := os.WriteFile(masterPath, master, 0644); err != nil {
+		return nil, fmt.Errorf("write master playlist: %w", err)
+	}
+	return tracks, nil
+}
+
+// processSubtitles handles a SubtitlesOnly task: it rebuilds the subtitle tracks of the
+// published HLS output in place and rewrites its master playlist, like the rendition
+// pruner does. A task for an output that is no longer published is dropped, since the
+// newer output was built with the subtitles stored at the time.
+func (s *transcodeService) processSubtitles(ctx context.Context, task repository.TranscodeTask) error {
+	if task.RetryCount >= s.maxRetries {
+		// The video keeps its output; only the new track is missing
+		return fmt.Errorf("%w: subtitle task retry count %d reached the limit of %d", repository.ErrPermanentTaskFailure, task.RetryCount, s.maxRetries)
+	}
+
+	video, err := s.repo.GetByID(ctx, task.VideoID)
+	if err != nil {
+		if errors.Is(err, repository.ErrVideoNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get video: %w", err)
+	}
+	if !video.IsReady() || video.HLSURL == "" || path.Dir(video.HLSURL)+"/" != task.OutputKey {
+		slog.InfoContext(ctx, "skipping subtitle task for unpublished output",
+			"video_id", task.VideoID,
+			"output_version", task.OutputVersion,
+		)
+		return nil
+	}
+	if video.ExternalOutput {
+		var output repository.ObjectStorage
+		if s.outputs != nil {
+			output, err = s.outputs.OutputStorage(ctx, video.UserID)
+			if err != nil {
+				return fmt.Errorf("open output bucket: %w", err)
+			}
+		}
+		if output == nil {
+			slog.InfoContext(ctx, "skipping subtitle task for output in a removed output bucket",
+				"video_id", task.VideoID,
+				"output_version", task.OutputVersion,
+			)
+			return nil
+		}
+		s = s.withOutput(output)
+	}
+
+	workDir, err := s.createWorkDir(task.VideoID)
+	if err != nil {
+		return fmt.Errorf("create work directory: %w", err)
+	}
+	defer s.cleanup(workDir)
+
+	tracks, err := s.buildSubtitleTracks(ctx, task.VideoID, workDir, video.Source.Duration)
+	if err != nil {
+		return fmt.Errorf("subtitles: %w", err)
+	}
+	sums := newOutputChecksums()
+	if _, err := s.uploadSubtitleTracks(ctx, task.OutputKey, tracks, sums); err != nil {
+		return err
+	}
+
+	master, err := downloadSmall(ctx, s.output, video.HLSURL, maxPlaylistBytes)
+	if err != nil {
+		return fmt.Errorf("download master playlist: %w", err)
+	}
+	master, err = withSubtitleTracks(master, tracks)
+	if err != nil {
+		return fmt.Errorf("rewrite master playlist: %w", err)
+	}
+	masterPath := filepath.Join(workDir, "master.m3u8")
+	if err := os.WriteFile(masterPath, master, 0644); err != nil {
+		return fmt.Errorf("write master playlist: %w", err)
+	}
+	if _, err := s.uploadFile(ctx, masterPath, video.HLSURL, "application/vnd.apple.mpegurl", sums); err != nil {
+		return fmt.Errorf("upload master playlist: %w", err)
+	}
+	if err := s.mergeChecksums(ctx, video.HLSURL, workDir, task.OutputKey+"subtitles/", sums); err != nil {
+		return fmt.Errorf("update checksums: %w", err)
+	}
+
+	s.purgeCDN(ctx, task.VideoID, []string{video.HLSURL, task.OutputKey + "subtitles/"})
+	slog.InfoContext(ctx, "rebuilt subtitle tracks",
+		"video_id", task.VideoID,
+		"output_version", video.OutputVersion,
+		"tracks", len(tracks),
+	)
+	return nil
+}
+
+// mergeChecksums replaces the entries under replacedPrefix of the output's checksum
+// manifest with sums, so verification keeps passing after an in-place rewrite. Outputs
+// without a manifest are left without one.
+func (s *transcodeService) mergeChecksums(ctx context.Context, masterKey, workDir, replacedPrefix string, sums *OutputChecksums) error {
+	existing, err := readOutputChecksums(ctx, s.output, outputChecksumsKey(masterKey))
+	if errors.Is(err, ErrOutputChecksumsNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for key := range existing.Files {
+		if strings.HasPrefix(key, replacedPrefix) {
+			delete(existing.Files, key)
+		}
+	}
+	for key, sum := range sums.Files {
+		existing.Files[key] = sum
+	}
+	return s.uploadChecksums(ctx, masterKey, workDir, existing)
+}
+
+// downloadSmall reads a whole object of at most limit bytes.
+func downloadSmall(ctx context.Context, storage repository.ObjectStorage, key string, limit int64) ([]byte, error) {
+	reader, err := storage.Download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s exceeds %d bytes", key, limit)
+	}
+	return data, nil
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+const testMaster = "#EXTM3U\n#EXT-X-VERSION:3\n" +
+	"#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360\n360p/playlist.m3u8\n" +
+	"#EXT-X-STREAM-INF:BANDWIDTH=2800000,RESOLUTION=1280x720\n720p/playlist.m3u8\n"
+
+func TestWithSubtitleTracks(t *testing.T) {
+	tracks := []subtitleTrack{
+		{subtitle: &model.Subtitle{Language: "en", Label: "English", Default: true}},
+		{subtitle: &model.Subtitle{Language: "ja", Label: "日本語"}},
+	}
+
+	got, err := withSubtitleTracks([]byte(testMaster), tracks)
+	if err != nil {
+		t.Fatalf("withSubtitleTracks() error = %v", err)
+	}
+	master := string(got)
+	for _, want := range []string{
+		`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="English",LANGUAGE="en",DEFAULT=YES,AUTOSELECT=YES,URI="subtitles/en/playlist.m3u8"`,
+		`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="日本語",LANGUAGE="ja",AUTOSELECT=YES,URI="subtitles/ja/playlist.m3u8"`,
+	} {
+		if !strings.Contains(master, want) {
+			t.Errorf("master is missing %s:\n%s", want, master)
+		}
+	}
+	if n := strings.Count(master, `SUBTITLES="subs"`); n != 2 {
+		t.Errorf("%d variants reference the subtitles, want 2:\n%s", n, master)
+	}
+
+	// Rewriting again replaces the tracks instead of adding to them
+	got, err = withSubtitleTracks(got, tracks[1:])
+	if err != nil {
+		t.Fatalf("withSubtitleTracks() error = %v", err)
+	}
+	if n := strings.Count(string(got), "TYPE=SUBTITLES"); n != 1 {
+		t.Errorf("%d subtitle renditions after rewrite, want 1:\n%s", n, got)
+	}
+
+	got, err = withSubtitleTracks(got, nil)
+	if err != nil {
+		t.Fatalf("withSubtitleTracks() error = %v", err)
+	}
+	if strings.Contains(string(got), "SUBTITLES") {
+		t.Errorf("subtitles left after removing every track:\n%s", got)
+	}
+}
+
+func TestTranscodeService_ProcessSubtitles(t *testing.T) {
+	ctx := context.Background()
+	videoID := uuid.New()
+	prefix := "hls/" + videoID.String() + "/v2/"
+
+	tests := []struct {
+		name      string
+		outputKey string
+		wantTrack bool
+	}{
+		{name: "published output gets the track", outputKey: prefix, wantTrack: true},
+		{name: "superseded output is skipped", outputKey: "hls/" + videoID.String() + "/v1/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:            videoID,
+				Status:        model.StatusReady,
+				HLSURL:        prefix + "master.m3u8",
+				OutputVersion: 2,
+				Source:        model.SourceMetadata{Duration: 10 * time.Second},
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) { return video, nil },
+			}
+			subtitles := &mockSubtitleRepository{
+				listByVideoIDFn: func(ctx context.Context, id uuid.UUID) ([]*model.Subtitle, error) {
+					return []*model.Subtitle{{VideoID: videoID, Language: "en", Label: "English", Format: "srt", SourceKey: "subtitles/en.srt"}}, nil
+				},
+			}
+			sums, _ := json.Marshal(OutputChecksums{Algorithm: "sha256", Files: map[string]OutputChecksum{
+				prefix + "master.m3u8":              {Size: 1},
+				prefix + "720p/segment_000.ts":      {Size: 2},
+				prefix + "subtitles/fr/segment.vtt": {Size: 3},
+			}})
+			objects := map[string][]byte{
+				video.HLSURL:                 []byte(testMaster),
+				prefix + OutputChecksumsFile: sums,
+				"subtitles/en.srt":           []byte(testSRT),
+			}
+			var purged []string
+			purger := &mockCDNPurger{
+				purgeFn: func(ctx context.Context, prefixes []string) error {
+					purged = prefixes
+					return nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, memoryStorage(objects), nil, nil, nil, nil, nil, nil, nil, purger, nil, nil, subtitles, nil, nil, nil, nil, nil, TranscodeServiceConfig{
+				TempDir:    t.TempDir(),
+				MaxRetries: 3,
+			})
+			task := repository.TranscodeTask{VideoID: videoID, OutputKey: tt.outputKey, OutputVersion: 2, SubtitlesOnly: true}
+			if err := svc.ProcessTask(ctx, task); err != nil {
+				t.Fatalf("ProcessTask() error = %v", err)
+			}
+
+			master := string(objects[video.HLSURL])
+			if !tt.wantTrack {
+				if master != testMaster || purged != nil {
+					t.Errorf("superseded output was touched:\n%s", master)
+				}
+				return
+			}
+
+			if !strings.Contains(master, `URI="subtitles/en/playlist.m3u8"`) || !strings.Contains(master, `SUBTITLES="subs"`) {
+				t.Errorf("master does not list the track:\n%s", master)
+			}
+			for _, key := range []string{"subtitles/en/playlist.m3u8", "subtitles/en/segment_000.vtt", "subtitles/en/segment_001.vtt"} {
+				if _, ok := objects[prefix+key]; !ok {
+					t.Errorf("%s was not uploaded", key)
+				}
+			}
+
+			var got OutputChecksums
+			if err := json.Unmarshal(objects[prefix+OutputChecksumsFile], &got); err != nil {
+				t.Fatalf("decode checksum manifest: %v", err)
+			}
+			if _, ok := got.Files[prefix+"subtitles/fr/segment.vtt"]; ok {
+				t.Error("checksum manifest still lists a replaced track")
+			}
+			if got.Files[prefix+"master.m3u8"].Size != int64(len(master)) || got.Files[prefix+"720p/segment_000.ts"].Size != 2 {
+				t.Errorf("checksum manifest not merged: %+v", got.Files)
+			}
+			if _, ok := got.Files[prefix+"subtitles/en/playlist.m3u8"]; !ok {
+				t.Error("checksum manifest is missing the new track")
+			}
+			if len(purged) != 2 || purged[0] != video.HLSURL || purged[1] != prefix+"subtitles/" {
+				t.Errorf("purged %v", purged)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// SupersededOutputSweeperConfig holds configuration for SupersededOutputSweeper.
+type SupersededOutputSweeperConfig struct {
+	// BatchSize caps the versions handed off by one run.
+	BatchSize int
+	// Lease is how long claimed versions are hidden from other sweepers; a version whose
+	// delete task failed to publish is claimed again once its lease lapses.
+	Lease time.Duration
+}
+
+// DefaultSupersededOutputSweeperConfig returns the default configuration.
+func DefaultSupersededOutputSweeperConfig() SupersededOutputSweeperConfig {
+	return SupersededOutputSweeperConfig{
+		BatchSize: 100,
+		Lease:     10 * time.Minute,
+	}
+}
+
+// SupersededOutputSweepResult summarizes a run of the SupersededOutputSweeper.
+type SupersededOutputSweepResult struct {
+	Published int
+	Failed    int
+}
+
+// SupersededOutputSweeper deletes output versions replaced by a regenerated one once
+// their grace period has ended.
+type SupersededOutputSweeper interface {
+	// Run claims up to BatchSize versions due for deletion and publishes a delete task
+	// for each, so the objects are removed by the cleanup consumer like those of a deleted
+	// video. Deleting is idempotent, so a version handed off twice is harmless.
+	Run(ctx context.Context) (*SupersededOutputSweepResult, error)
+}
+
+type supersededOutputSweeper struct {
+	outputs repository.SupersededOutputRepository
+	queue   repository.MessageQueue
+	cfg     SupersededOutputSweeperConfig
+	now     func() time.Time
+}
+
+// NewSupersededOutputSweeper creates a new SupersededOutputSweeper instance.
+func NewSupersededOutputSweeper(outputs repository.SupersededOutputRepository, queue repository.MessageQueue, cfg SupersededOutputSweeperConfig) SupersededOutputSweeper {
+	return &supersededOutputSweeper{
+		outputs: outputs,
+		queue:   queue,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+func (s *supersededOutputSweeper) Run(ctx context.Context) (*SupersededOutputSweepResult, error) {
+	now := s.now()
+	outputs, err := s.outputs.Claim(ctx, now, now.Add(s.cfg.Lease), s.cfg.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("claim superseded outputs: %w", err)
+	}
+
+	result := &SupersededOutputSweepResult{}
+	for _, output := range outputs {
+		if s.publish(ctx, output) {
+			result.Published++
+		} else {
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// publish hands the version off to the cleanup consumer and removes its record, and
+// reports whether the delete task was published. A version whose task failed to publish
+// keeps its record and is claimed again after the lease.
+func (s *supersededOutputSweeper) publish(ctx context.Context, output *repository.SupersededOutput) bool {
+	err := s.queue.PublishDeleteTask(ctx, repository.DeleteTask{
+		VideoID:        output.VideoID,
+		UserID:         output.UserID,
+		OutputPrefixes: output.Prefixes,
+		ExternalOutput: output.ExternalOutput,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to publish delete task of superseded output",
+			"video_id", output.VideoID,
+			"output_version", output.OutputVersion,
+			"error", err,
+		)
+		return false
+	}
+
+	// A record that stays is handed off again once its lease lapses
+	if err := s.outputs.Delete(context.WithoutCancel(ctx), output.VideoID, output.OutputVersion); err != nil {
+		slog.WarnContext(ctx, "failed to delete superseded output record",
+			"video_id", output.VideoID,
+			"output_version", output.OutputVersion,
+			"error", err,
+		)
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestSupersededOutputSweeper_Run(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	failing := uuid.New()
+
+	tests := []struct {
+		name        string
+		claimErr    error
+		want        SupersededOutputSweepResult
+		wantErr     bool
+		wantDeleted int
+	}{
+		{
+			name:        "hands claimed versions off to cleanup",
+			want:        SupersededOutputSweepResult{Published: 2, Failed: 1},
+			wantDeleted: 2,
+		},
+		{
+			name:     "claim error",
+			claimErr: errors.New("connection refused"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			outputs := []*repository.SupersededOutput{
+				{VideoID: uuid.New(), OutputVersion: 1, UserID: userID, Prefixes: []string{"hls/a/v1/"}},
+				{VideoID: failing, OutputVersion: 1, UserID: userID, Prefixes: []string{"hls/b/v1/"}},
+				{VideoID: uuid.New(), OutputVersion: 2, UserID: userID, Prefixes: []string{"hls/c/v2/", "previews/c/v2/"}, ExternalOutput: true},
+			}
+
+			deleted := 0
+			repo := &mockSupersededOutputRepository{
+				claimFn: func(ctx context.Context, claimNow, until time.Time, limit int) ([]*repository.SupersededOutput, error) {
+					if !claimNow.Equal(now) || !until.Equal(now.Add(10*time.Minute)) || limit != 100 {
+						t.Errorf("Claim(%v, %v, %d): unexpected arguments", claimNow, until, limit)
+					}
+					return outputs, tt.claimErr
+				},
+				deleteFn: func(ctx context.Context, videoID uuid.UUID, outputVersion int64) error {
+					if videoID == failing {
+						t.Errorf("Delete(%s): record of an unpublished task deleted", videoID)
+					}
+					deleted++
+					return nil
+				},
+			}
+
+			var published []repository.DeleteTask
+			queue := &mockMessageQueue{
+				publishDeleteTaskFn: func(ctx context.Context, task repository.DeleteTask) error {
+					if task.VideoID == failing {
+						return errors.New("channel closed")
+					}
+					published = append(published, task)
+					return nil
+				},
+			}
+
+			sweeper := NewSupersededOutputSweeper(repo, queue, DefaultSupersededOutputSweeperConfig()).(*supersededOutputSweeper)
+			sweeper.now = func() time.Time { return now }
+
+			result, err := sweeper.Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *result != tt.want {
+				t.Errorf("Run() = %+v, want %+v", *result, tt.want)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted %d records, want %d", deleted, tt.wantDeleted)
+			}
+			if len(published) != 2 {
+				t.Fatalf("published %d delete tasks, want 2", len(published))
+			}
+			last := published[1]
+			if last.OriginalKey != "" || len(last.OutputPrefixes) != 2 || !last.ExternalOutput || last.UserID != userID {
+				t.Errorf("delete task = %+v, want only the superseded prefixes", last)
+			}
+		})
+	}
+}
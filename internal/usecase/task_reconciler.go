@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// taskReconcileLock is the lock that keeps reconciliation to one worker at a time.
+const taskReconcileLock = "transcode-task-reconcile"
+
+// TaskReconcileConfig holds configuration for TaskReconciler.
+type TaskReconcileConfig struct {
+	// MinAge is how long a video must have been PROCESSING without an update before its
+	// task is considered lost. It also spaces out reconciliation passes across workers.
+	MinAge time.Duration
+	// MaxTasks caps the tasks republished by one pass.
+	MaxTasks int
+	// TaskTTL is how long republished tasks stay valid; zero never expires.
+	TaskTTL time.Duration
+}
+
+// DefaultTaskReconcileConfig returns the default configuration.
+func DefaultTaskReconcileConfig() TaskReconcileConfig {
+	return TaskReconcileConfig{
+		MinAge:   30 * time.Minute,
+		MaxTasks: 100,
+	}
+}
+
+// TaskReconcileResult summarizes a reconciliation pass.
+type TaskReconcileResult struct {
+	// Skipped is true when another worker held the reconciliation lock.
+	Skipped bool
+	// Queued is the number of tasks waiting in the queue.
+	Queued int
+	// Stale is the number of PROCESSING videos older than MinAge that were examined.
+	Stale int
+	// InFlight is the number of stale videos a worker is still transcoding.
+	InFlight    int
+	Republished int
+}
+
+// TaskReconciler recovers transcode tasks lost by the broker, such as after a broker
+// restart without persistent queues, so their videos do not stay PROCESSING.
+type TaskReconciler interface {
+	// Reconcile compares the videos PROCESSING for longer than MinAge with the queue and
+	// republishes a task for each one that is neither queued nor being transcoded, up
+	// to MaxTasks. Videos whose task may still be queued are left alone.
+	Reconcile(ctx context.Context) (*TaskReconcileResult, error)
+}
+
+type taskReconciler struct {
+	videos    repository.VideoRepository
+	queue     repository.MessageQueue
+	inspector repository.QueueInspector
+	progress  cache.TranscodeProgressStore
+	locks     cache.LockStore
+	cfg       TaskReconcileConfig
+	now       func() time.Time
+}
+
+// NewTaskReconciler creates a new TaskReconciler instance.
+// The progress parameter is optional - pass nil to treat every stale video as idle.
+// The locks parameter is optional - pass nil when only one worker runs.
+func NewTaskReconciler(
+	videos repository.VideoRepository,
+	queue repository.MessageQueue,
+	inspector repository.QueueInspector,
+	progress cache.TranscodeProgressStore,
+	locks cache.LockStore,
+	cfg TaskReconcileConfig,
+) TaskReconciler {
+	return &taskReconciler{
+		videos:    videos,
+		queue:     queue,
+		inspector: inspector,
+		progress:  progress,
+		locks:     locks,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// Reconcile cannot tell which videos the queued tasks belong to, so it assumes they are
+// the most recently created stale videos and skips as many of those. A wrong guess costs
+// a duplicate task, which the worker handles like a redelivery.
+//
+// The lock is held for MinAge and released early only if the pass fails, so workers
+// booting together run a single pass and tasks republished by it are not republished
+// again before they could have been picked up.
+func (r *taskReconciler) Reconcile(ctx context.Context) (*TaskReconcileResult, error) {
+	if r.locks != nil {
+		token, ok, err := r.locks.Acquire(ctx, taskReconcileLock, r.cfg.MinAge)
+		if err != nil {
+			return nil, fmt.Errorf("acquire reconcile lock: %w", err)
+		}
+		if !ok {
+			return &TaskReconcileResult{Skipped: true}, nil
+		}
+
+		result, err := r.reconcile(ctx)
+		if err != nil {
+			if releaseErr := r.locks.Release(context.WithoutCancel(ctx), taskReconcileLock, token); releaseErr != nil {
+				slog.WarnContext(ctx, "failed to release reconcile lock", "error", releaseErr)
+			}
+		}
+		return result, err
+	}
+	return r.reconcile(ctx)
+}
+
+func (r *taskReconciler) reconcile(ctx context.Context) (*TaskReconcileResult, error) {
+	queued, err := r.inspector.Depth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get queue depth: %w", err)
+	}
+
+	now := r.now()
+	stale, err := r.videos.List(ctx, repository.VideoFilter{
+		Status:        model.StatusProcessing,
+		UpdatedBefore: now.Add(-r.cfg.MinAge),
+		Limit:         queued + r.cfg.MaxTasks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list processing videos: %w", err)
+	}
+
+	result := &TaskReconcileResult{Queued: queued, Stale: len(stale)}
+	for _, video := range stale[min(queued, len(stale)):] {
+		if r.inFlight(ctx, video) {
+			result.InFlight++
+			continue
+		}
+
+		// The original request's ladder overrides are not stored and fall back to the
+		// worker's defaults
+		task := buildTranscodeTask(video, now, r.cfg.TaskTTL)
+		task.EnqueuedAt = video.UpdatedAt
+		if err := r.queue.PublishTranscodeTask(ctx, task); err != nil {
+			return result, fmt.Errorf("publish transcode task for %s: %w", video.ID, err)
+		}
+		result.Republished++
+		metrics.TranscodeTasksReconciledTotal.Inc()
+
+		slog.InfoContext(ctx, "republished lost transcode task",
+			"video_id", video.ID,
+			"processing_since", video.UpdatedAt,
+			"output_version", task.OutputVersion,
+		)
+	}
+
+	return result, nil
+}
+
+// inFlight reports whether a worker has recently reported progress on video.
+// Videos whose progress cannot be read count as in flight, to err on the side of no
+// duplicate work.
+func (r *taskReconciler) inFlight(ctx context.Context, video *model.Video) bool {
+	if r.progress == nil {
+		return false
+	}
+
+	_, ok, err := r.progress.Get(ctx, video.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get transcode progress",
+			"video_id", video.ID,
+			"error", err,
+		)
+		return true
+	}
+	return ok
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestTaskReconciler_Reconcile(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	processingSince := now.Add(-2 * time.Hour)
+
+	// Newest first, as List returns them
+	stale := make([]*model.Video, 4)
+	for i := range stale {
+		stale[i] = &model.Video{
+			ID:          uuid.New(),
+			Status:      model.StatusProcessing,
+			OriginalURL: "originals/video.mp4",
+			CreatedAt:   processingSince.Add(-time.Duration(i) * time.Minute),
+			UpdatedAt:   processingSince,
+		}
+	}
+
+	tests := []struct {
+		name            string
+		depth           int
+		inFlight        map[uuid.UUID]bool
+		publishErr      error
+		wantLimit       int
+		wantRepublished []uuid.UUID
+		wantResult      TaskReconcileResult
+		wantErr         bool
+	}{
+		{
+			name:            "empty queue republishes every stale video",
+			wantLimit:       10,
+			wantRepublished: []uuid.UUID{stale[0].ID, stale[1].ID, stale[2].ID, stale[3].ID},
+			wantResult:      TaskReconcileResult{Stale: 4, Republished: 4},
+		},
+		{
+			name:            "queued tasks cover the newest videos",
+			depth:           2,
+			wantLimit:       12,
+			wantRepublished: []uuid.UUID{stale[2].ID, stale[3].ID},
+			wantResult:      TaskReconcileResult{Queued: 2, Stale: 4, Republished: 2},
+		},
+		{
+			name:            "videos being transcoded are left alone",
+			inFlight:        map[uuid.UUID]bool{stale[1].ID: true},
+			wantLimit:       10,
+			wantRepublished: []uuid.UUID{stale[0].ID, stale[2].ID, stale[3].ID},
+			wantResult:      TaskReconcileResult{Stale: 4, InFlight: 1, Republished: 3},
+		},
+		{
+			name:       "publish failure stops the pass",
+			publishErr: errors.New("channel closed"),
+			wantLimit:  10,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter repository.VideoFilter
+			videos := &mockVideoRepository{
+				listFn: func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+					gotFilter = filter
+					return stale, nil
+				},
+			}
+			var republished []repository.TranscodeTask
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					if tt.publishErr != nil {
+						return tt.publishErr
+					}
+					republished = append(republished, task)
+					return nil
+				},
+			}
+			inspector := &mockQueueInspector{
+				depthFn: func(ctx context.Context) (int, error) { return tt.depth, nil },
+			}
+			progress := &mockTranscodeProgressStore{
+				getFn: func(ctx context.Context, videoID uuid.UUID) (int, bool, error) {
+					return 40, tt.inFlight[videoID], nil
+				},
+			}
+
+			r := NewTaskReconciler(videos, queue, inspector, progress, nil, TaskReconcileConfig{
+				MinAge:   30 * time.Minute,
+				MaxTasks: 10,
+				TaskTTL:  time.Hour,
+			}).(*taskReconciler)
+			r.now = func() time.Time { return now }
+
+			result, err := r.Reconcile(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if gotFilter.Status != model.StatusProcessing || !gotFilter.UpdatedBefore.Equal(now.Add(-30*time.Minute)) || gotFilter.Limit != tt.wantLimit {
+				t.Errorf("List() filter = %+v, want PROCESSING updated before %v, limit %d", gotFilter, now.Add(-30*time.Minute), tt.wantLimit)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if *result != tt.wantResult {
+				t.Errorf("Reconcile() = %+v, want %+v", *result, tt.wantResult)
+			}
+			if len(republished) != len(tt.wantRepublished) {
+				t.Fatalf("republished %d tasks, want %d", len(republished), len(tt.wantRepublished))
+			}
+			for i, task := range republished {
+				if task.VideoID != tt.wantRepublished[i] {
+					t.Errorf("task %d video = %s, want %s", i, task.VideoID, tt.wantRepublished[i])
+				}
+				if !task.EnqueuedAt.Equal(processingSince) {
+					t.Errorf("task %d EnqueuedAt = %v, want %v", i, task.EnqueuedAt, processingSince)
+				}
+				if !task.ExpiresAt.Equal(now.Add(time.Hour)) {
+					t.Errorf("task %d ExpiresAt = %v, want %v", i, task.ExpiresAt, now.Add(time.Hour))
+				}
+				if task.OutputKey == "" || task.OutputVersion == 0 {
+					t.Errorf("task %d has no output version: %+v", i, task)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskReconciler_Lock(t *testing.T) {
+	tests := []struct {
+		name        string
+		acquired    bool
+		depthErr    error
+		wantSkipped bool
+		wantRelease bool
+		wantErr     bool
+	}{
+		{name: "held by another worker", wantSkipped: true},
+		{name: "kept after a pass", acquired: true},
+		{name: "released after a failed pass", acquired: true, depthErr: errors.New("channel closed"), wantRelease: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed := false
+			videos := &mockVideoRepository{
+				listFn: func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+					listed = true
+					return nil, nil
+				},
+			}
+			inspector := &mockQueueInspector{
+				depthFn: func(ctx context.Context) (int, error) { return 0, tt.depthErr },
+			}
+			released := false
+			locks := &mockLockStore{
+				acquireFn: func(ctx context.Context, name string, ttl time.Duration) (string, bool, error) {
+					if ttl != 30*time.Minute {
+						t.Errorf("Acquire() ttl = %v, want MinAge", ttl)
+					}
+					return "token", tt.acquired, nil
+				},
+				releaseFn: func(ctx context.Context, name, token string) error {
+					released = true
+					return nil
+				},
+			}
+
+			r := NewTaskReconciler(videos, &mockMessageQueue{}, inspector, nil, locks, DefaultTaskReconcileConfig())
+			result, err := r.Reconcile(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantSkipped && (!result.Skipped || listed) {
+				t.Errorf("expected pass to be skipped, got %+v (listed %v)", result, listed)
+			}
+			if released != tt.wantRelease {
+				t.Errorf("released = %v, want %v", released, tt.wantRelease)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// minSourceTaskTimeout is the least time an attempt is given when its deadline is sized
+// by the source duration, leaving short sources time to download and upload.
+const minSourceTaskTimeout = 10 * time.Minute
+
+// ErrTaskTimeout is returned, wrapping the cancellation it caused, by a transcode attempt
+// that ran past its deadline. It is not coded, so the attempt counts as a retry.
+var ErrTaskTimeout = errors.New("transcode task timed out")
+
+// taskWatchdog cancels the context of a transcode attempt once its deadline passes,
+// which kills the attempt's FFmpeg process groups. The deadline starts at the fixed cap
+// and is tightened once the source duration is known. A nil watchdog never fires.
+type taskWatchdog struct {
+	ctx       context.Context
+	cancel    context.CancelCauseFunc
+	startedAt time.Time
+	factor    float64
+
+	mu       sync.Mutex
+	deadline time.Time
+	timer    *time.Timer
+	stopped  bool
+}
+
+// startWatchdog returns the context of an attempt started at startedAt and its watchdog,
+// which is nil when neither a fixed cap nor a source duration factor is configured.
+func (s *transcodeService) startWatchdog(ctx context.Context, startedAt time.Time) (context.Context, *taskWatchdog) {
+	if s.taskTimeout <= 0 && s.taskTimeoutFactor <= 0 {
+		return ctx, nil
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	w := &taskWatchdog{
+		ctx:       ctx,
+		cancel:    cancel,
+		startedAt: startedAt,
+		factor:    s.taskTimeoutFactor,
+	}
+	if s.taskTimeout > 0 {
+		w.tighten(startedAt.Add(s.taskTimeout))
+	}
+	return ctx, w
+}
+
+// sizeBySource tightens the deadline to the configured multiple of the source duration,
+// and no less than minSourceTaskTimeout, after the attempt started.
+func (w *taskWatchdog) sizeBySource(duration time.Duration) {
+	if w == nil || w.factor <= 0 || duration <= 0 {
+		return
+	}
+	timeout := max(time.Duration(float64(duration)*w.factor), minSourceTaskTimeout)
+	w.tighten(w.startedAt.Add(timeout))
+}
+
+// tighten moves the deadline to deadline if that is earlier.
+func (w *taskWatchdog) tighten(deadline time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stopped || (!w.deadline.IsZero() && !deadline.Before(w.deadline)) {
+		return
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.deadline = deadline
+	cause := fmt.Errorf("%w after %s", ErrTaskTimeout, deadline.Sub(w.startedAt).Round(time.Second))
+	w.timer = time.AfterFunc(time.Until(deadline), func() { w.cancel(cause) })
+}
+
+// stop releases the watchdog, returning the error it cancelled the attempt with, if any.
+func (w *taskWatchdog) stop() error {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	w.stopped = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+
+	// Cancelling keeps the cause of an earlier cancellation
+	w.cancel(nil)
+	if err := context.Cause(w.ctx); errors.Is(err, ErrTaskTimeout) {
+		return err
+	}
+	return nil
+}
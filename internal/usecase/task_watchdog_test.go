@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTaskWatchdog_Deadline(t *testing.T) {
+	startedAt := time.Now()
+
+	tests := []struct {
+		name           string
+		taskTimeout    time.Duration
+		factor         float64
+		sourceDuration time.Duration
+		wantTimeout    time.Duration
+	}{
+		{
+			name:        "fixed cap",
+			taskTimeout: 2 * time.Hour,
+			wantTimeout: 2 * time.Hour,
+		},
+		{
+			name:           "sized by source",
+			taskTimeout:    2 * time.Hour,
+			factor:         3,
+			sourceDuration: 30 * time.Minute,
+			wantTimeout:    90 * time.Minute,
+		},
+		{
+			name:           "cap wins over a longer source deadline",
+			taskTimeout:    2 * time.Hour,
+			factor:         3,
+			sourceDuration: time.Hour,
+			wantTimeout:    2 * time.Hour,
+		},
+		{
+			name:           "short source gets the minimum",
+			factor:         3,
+			sourceDuration: 10 * time.Second,
+			wantTimeout:    minSourceTaskTimeout,
+		},
+		{
+			name:        "unknown source duration without a cap",
+			factor:      3,
+			wantTimeout: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transcodeService{taskTimeout: tt.taskTimeout, taskTimeoutFactor: tt.factor}
+			_, w := s.startWatchdog(context.Background(), startedAt)
+			defer w.stop()
+			w.sizeBySource(tt.sourceDuration)
+
+			var got time.Duration
+			if !w.deadline.IsZero() {
+				got = w.deadline.Sub(startedAt)
+			}
+			if got != tt.wantTimeout {
+				t.Errorf("timeout: got %s, expected %s", got, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestTaskWatchdog_Disabled(t *testing.T) {
+	ctx := context.Background()
+	gotCtx, w := (&transcodeService{}).startWatchdog(ctx, time.Now())
+	if w != nil || gotCtx != ctx {
+		t.Fatalf("expected no watchdog, got %+v", w)
+	}
+	// A nil watchdog is safe to use
+	w.sizeBySource(time.Hour)
+	if err := w.stop(); err != nil {
+		t.Errorf("stop() = %v, expected nil", err)
+	}
+}
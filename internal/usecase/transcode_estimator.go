@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+const (
+	// DefaultEstimateWindow is how far back historical jobs are used for estimates.
+	DefaultEstimateWindow = 7 * 24 * time.Hour
+	// DefaultEstimateMinSamples is the number of jobs required before estimating.
+	DefaultEstimateMinSamples = 5
+)
+
+// estimateResolutionClasses are the upper bounds of the source heights estimated
+// together; sources above the last bound form one more class.
+var estimateResolutionClasses = []int{480, 720, 1080, 1440, 2160}
+
+// TranscodeEstimator predicts the cost of transcoding a source.
+type TranscodeEstimator interface {
+	// Estimate predicts the processing time and ABR output size for source.
+	// Returns nil without an error when there is not enough history.
+	Estimate(ctx context.Context, source transcoder.ProbeResult) (*model.TranscodeEstimate, error)
+}
+
+// TranscodeEstimatorConfig holds configuration for TranscodeEstimator.
+type TranscodeEstimatorConfig struct {
+	// Window is how far back historical jobs are used.
+	Window time.Duration
+	// MinSamples is the number of jobs required before estimating.
+	MinSamples int
+}
+
+// DefaultTranscodeEstimatorConfig returns the default configuration.
+func DefaultTranscodeEstimatorConfig() TranscodeEstimatorConfig {
+	return TranscodeEstimatorConfig{
+		Window:     DefaultEstimateWindow,
+		MinSamples: DefaultEstimateMinSamples,
+	}
+}
+
+type transcodeEstimator struct {
+	jobs       repository.TranscodeJobRepository
+	window     time.Duration
+	minSamples int64
+	now        func() time.Time
+}
+
+// NewTranscodeEstimator creates a TranscodeEstimator backed by recorded transcode jobs.
+func NewTranscodeEstimator(jobs repository.TranscodeJobRepository, cfg TranscodeEstimatorConfig) TranscodeEstimator {
+	return &transcodeEstimator{
+		jobs:       jobs,
+		window:     cfg.Window,
+		minSamples: int64(max(cfg.MinSamples, 1)),
+		now:        time.Now,
+	}
+}
+
+// Estimate scales the source duration by the processing time and output bytes per
+// second of source seen in recent jobs. Jobs in the source's resolution class are
+// preferred; all classes are pooled when that class has fewer than MinSamples jobs.
+func (e *transcodeEstimator) Estimate(ctx context.Context, source transcoder.ProbeResult) (*model.TranscodeEstimate, error) {
+	if source.Duration <= 0 {
+		return nil, nil
+	}
+
+	stats, err := e.jobs.CostStats(ctx, e.now().Add(-e.window))
+	if err != nil {
+		return nil, fmt.Errorf("get transcode cost stats: %w", err)
+	}
+
+	class := resolutionClass(source.Height)
+	var same, all repository.TranscodeCostStats
+	for _, s := range stats {
+		addCostStats(&all, s)
+		if resolutionClass(s.SourceHeight) == class {
+			addCostStats(&same, s)
+		}
+	}
+
+	basis := same
+	if basis.Jobs < e.minSamples {
+		basis = all
+	}
+	if basis.Jobs < e.minSamples || basis.SourceDuration <= 0 {
+		return nil, nil
+	}
+
+	scale := source.Duration.Seconds() / basis.SourceDuration.Seconds()
+	return &model.TranscodeEstimate{
+		ProcessingTime: time.Duration(float64(basis.ProcessingTime) * scale),
+		OutputBytes:    int64(float64(basis.OutputBytes) * scale),
+		Samples:        basis.Jobs,
+	}, nil
+}
+
+// resolutionClass returns the index of the class a source height falls in.
+func resolutionClass(height int) int {
+	for i, bound := range estimateResolutionClasses {
+		if height <= bound {
+			return i
+		}
+	}
+	return len(estimateResolutionClasses)
+}
+
+func addCostStats(total *repository.TranscodeCostStats, s repository.TranscodeCostStats) {
+	total.Jobs += s.Jobs
+	total.SourceDuration += s.SourceDuration
+	total.ProcessingTime += s.ProcessingTime
+	total.OutputBytes += s.OutputBytes
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+func TestTranscodeEstimator_Estimate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// 1080p sources process at 0.5x realtime and 720p sources at 0.25x
+	stats := []repository.TranscodeCostStats{
+		{SourceHeight: 720, Jobs: 3, SourceDuration: 10 * time.Minute, ProcessingTime: 150 * time.Second, OutputBytes: 300_000_000},
+		{SourceHeight: 1080, Jobs: 4, SourceDuration: 20 * time.Minute, ProcessingTime: 10 * time.Minute, OutputBytes: 1_200_000_000},
+	}
+
+	tests := []struct {
+		name       string
+		source     transcoder.ProbeResult
+		minSamples int
+		statsErr   error
+		wantTime   time.Duration
+		wantBytes  int64
+		wantCount  int64
+		wantNil    bool
+		wantErr    bool
+	}{
+		{
+			name:       "uses the source's resolution class",
+			source:     transcoder.ProbeResult{Duration: 2 * time.Minute, Height: 1080},
+			minSamples: 4,
+			wantTime:   time.Minute,
+			wantBytes:  120_000_000,
+			wantCount:  4,
+		},
+		{
+			name:       "pools all classes when the class is too small",
+			source:     transcoder.ProbeResult{Duration: 3 * time.Minute, Height: 720},
+			minSamples: 4,
+			wantTime:   75 * time.Second, // 750s over 30min of source
+			wantBytes:  150_000_000,
+			wantCount:  7,
+		},
+		{
+			name:       "not enough history",
+			source:     transcoder.ProbeResult{Duration: time.Minute, Height: 720},
+			minSamples: 8,
+			wantNil:    true,
+		},
+		{
+			name:       "unknown source duration",
+			source:     transcoder.ProbeResult{Height: 1080},
+			minSamples: 1,
+			wantNil:    true,
+		},
+		{
+			name:       "stats error",
+			source:     transcoder.ProbeResult{Duration: time.Minute, Height: 1080},
+			minSamples: 1,
+			statsErr:   errors.New("connection refused"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &mockTranscodeJobRepository{
+				costStatsFn: func(ctx context.Context, since time.Time) ([]repository.TranscodeCostStats, error) {
+					if want := now.Add(-DefaultEstimateWindow); !since.Equal(want) {
+						t.Errorf("since: got %v, expected %v", since, want)
+					}
+					return stats, tt.statsErr
+				},
+			}
+
+			cfg := DefaultTranscodeEstimatorConfig()
+			cfg.MinSamples = tt.minSamples
+			estimator := NewTranscodeEstimator(jobs, cfg).(*transcodeEstimator)
+			estimator.now = func() time.Time { return now }
+
+			got, err := estimator.Estimate(context.Background(), tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Estimate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("Estimate() = %+v, expected nil", got)
+				}
+				return
+			}
+
+			if got == nil {
+				t.Fatal("Estimate() = nil, expected an estimate")
+			}
+			if got.ProcessingTime != tt.wantTime || got.OutputBytes != tt.wantBytes || got.Samples != tt.wantCount {
+				t.Errorf("Estimate() = %+v, expected %v / %d bytes / %d samples", got, tt.wantTime, tt.wantBytes, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestResolutionClass(t *testing.T) {
+	tests := []struct {
+		height int
+		want   int
+	}{
+		{360, 0},
+		{480, 0},
+		{720, 1},
+		{1080, 2},
+		{1200, 3},
+		{2160, 4},
+		{4320, 5},
+	}
+	for _, tt := range tests {
+		if got := resolutionClass(tt.height); got != tt.want {
+			t.Errorf("resolutionClass(%d) = %d, want %d", tt.height, got, tt.want)
+		}
+	}
+}
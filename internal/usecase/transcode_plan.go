@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+// estimatedAudioBitrate is the AAC bitrate assumed for size estimates of variants without
+// an audio bitrate, matching FFmpeg's default for its native AAC encoder.
+const estimatedAudioBitrate = 128000
+
+// PlannedVariant is one rendition a transcode would produce.
+type PlannedVariant struct {
+	Variant transcoder.Variant
+	// EstimatedBytes is the expected output size, derived from the target bitrates.
+	EstimatedBytes int64
+}
+
+// TranscodePlan describes what processing a video would produce, without enqueueing it.
+type TranscodePlan struct {
+	// Source is the probed original.
+	Source transcoder.ProbeResult
+	// ABRProfile is the name of the ladder the variants come from.
+	ABRProfile string
+	// Variants is the ABR ladder the worker encodes.
+	Variants []PlannedVariant
+	// Preview is the public preview rendition; nil when the video has none.
+	Preview *PlannedVariant
+	// EstimatedDuration is the playback duration of the full output.
+	EstimatedDuration time.Duration
+	// EstimatedOutputBytes is the expected size of all renditions together.
+	EstimatedOutputBytes int64
+	// Estimate predicts processing time and ABR output size from historical jobs;
+	// nil when there is not enough history or no estimator is configured.
+	Estimate *model.TranscodeEstimate
+}
+
+// planTranscode plans the renditions the worker produces for source from ladder, skipping
+// variants taller than the source as the worker does. Sizes are
+// estimated from target bitrates, so they are an upper bound for simple content
+// and may be exceeded by short, complex clips.
+func planTranscode(source transcoder.ProbeResult, ladder []transcoder.Variant, previewSeconds int) *TranscodePlan {
+	plan := &TranscodePlan{
+		Source:            source,
+		EstimatedDuration: source.Duration,
+	}
+
+	for _, v := range transcoder.VariantsForSource(ladder, source.Height) {
+		pv := PlannedVariant{Variant: v, EstimatedBytes: estimateRenditionBytes(v, source.Duration, source.HasAudio)}
+		plan.Variants = append(plan.Variants, pv)
+		plan.EstimatedOutputBytes += pv.EstimatedBytes
+	}
+
+	if previewSeconds > 0 {
+		length := min(time.Duration(previewSeconds)*time.Second, source.Duration)
+		v := transcoder.DefaultPreviewVariant()
+		plan.Preview = &PlannedVariant{Variant: v, EstimatedBytes: estimateRenditionBytes(v, length, source.HasAudio)}
+		plan.EstimatedOutputBytes += plan.Preview.EstimatedBytes
+	}
+
+	return plan
+}
+
+// estimateRenditionBytes estimates the size of a rendition of the given length.
+func estimateRenditionBytes(v transcoder.Variant, length time.Duration, hasAudio bool) int64 {
+	bitrate := int64(v.Bitrate)
+	if hasAudio {
+		bitrate += int64(cmp.Or(v.AudioBitrate, estimatedAudioBitrate))
+	}
+	return int64(float64(bitrate) / 8 * length.Seconds())
+}
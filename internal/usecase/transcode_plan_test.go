@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+func TestPlanTranscode(t *testing.T) {
+	source := transcoder.ProbeResult{Duration: 10 * time.Second, Width: 1280, Height: 720, HasAudio: true}
+
+	plan := planTranscode(source, transcoder.DefaultABRVariants(), 30)
+
+	// A 720p source skips the 1080p rung
+	ladder := transcoder.DefaultABRVariants()[1:]
+	if len(plan.Variants) != len(ladder) {
+		t.Fatalf("variants: got %d, expected %d", len(plan.Variants), len(ladder))
+	}
+
+	var total int64
+	for i, v := range plan.Variants {
+		if v.Variant != ladder[i] {
+			t.Errorf("variant %d: got %+v, expected %+v", i, v.Variant, ladder[i])
+		}
+		// (video + audio bitrate) / 8 bytes per second for 10 seconds
+		want := int64(ladder[i].Bitrate+estimatedAudioBitrate) / 8 * 10
+		if v.EstimatedBytes != want {
+			t.Errorf("%s bytes: got %d, expected %d", v.Variant.Name, v.EstimatedBytes, want)
+		}
+		total += v.EstimatedBytes
+	}
+
+	// The preview is capped at the source duration
+	preview := transcoder.DefaultPreviewVariant()
+	wantPreview := int64(preview.Bitrate+estimatedAudioBitrate) / 8 * 10
+	if plan.Preview == nil || plan.Preview.EstimatedBytes != wantPreview {
+		t.Fatalf("preview: got %+v, expected %d bytes", plan.Preview, wantPreview)
+	}
+	total += wantPreview
+
+	if plan.EstimatedOutputBytes != total {
+		t.Errorf("total bytes: got %d, expected %d", plan.EstimatedOutputBytes, total)
+	}
+	if plan.EstimatedDuration != source.Duration {
+		t.Errorf("duration: got %s, expected %s", plan.EstimatedDuration, source.Duration)
+	}
+}
+
+func TestPlanTranscode_SilentWithoutPreview(t *testing.T) {
+	plan := planTranscode(transcoder.ProbeResult{Duration: 4 * time.Second, Height: 1080}, transcoder.DefaultABRVariants(), 0)
+
+	if plan.Preview != nil {
+		t.Errorf("preview: got %+v, expected none", plan.Preview)
+	}
+	if got, want := plan.Variants[0].EstimatedBytes, int64(plan.Variants[0].Variant.Bitrate)/8*4; got != want {
+		t.Errorf("bytes without audio: got %d, expected %d", got, want)
+	}
+}
+
+func TestPlanTranscode_ProfileAudioBitrate(t *testing.T) {
+	ladder := []transcoder.Variant{{Name: "1080p", Height: 1080, Bitrate: 2000000, AudioBitrate: 64000}}
+
+	plan := planTranscode(transcoder.ProbeResult{Duration: 8 * time.Second, Height: 1080, HasAudio: true}, ladder, 0)
+
+	if len(plan.Variants) != 1 {
+		t.Fatalf("variants: got %d, expected 1", len(plan.Variants))
+	}
+	if got, want := plan.Variants[0].EstimatedBytes, int64(2000000+64000)/8*8; got != want {
+		t.Errorf("bytes: got %d, expected %d", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// maxEncodeProgress is the highest percentage reported while encoding. Uploading and
+// publishing are not measured, so 100 is never reported; a finished video is READY instead.
+const maxEncodeProgress = 99
+
+// transcodeProgress turns per-variant encode positions into an overall percentage and
+// records and publishes it whenever it advances. Every variant re-encodes the whole
+// source, so each contributes an equal share of the total. Either store or events may be nil.
+type transcodeProgress struct {
+	ctx      context.Context
+	store    cache.TranscodeProgressStore
+	events   cache.VideoEventBus
+	videoID  uuid.UUID
+	duration time.Duration
+	variants int
+
+	mu       sync.Mutex
+	encoded  map[string]time.Duration
+	reported int
+}
+
+func newTranscodeProgress(ctx context.Context, store cache.TranscodeProgressStore, events cache.VideoEventBus, videoID uuid.UUID, duration time.Duration, variants int) *transcodeProgress {
+	return &transcodeProgress{
+		ctx:      ctx,
+		store:    store,
+		events:   events,
+		videoID:  videoID,
+		duration: duration,
+		variants: variants,
+		encoded:  make(map[string]time.Duration, variants),
+		reported: -1,
+	}
+}
+
+// start records 0% so clients see progress as soon as encoding begins.
+func (p *transcodeProgress) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report(0)
+}
+
+// update is a transcoder.ProgressFunc.
+func (p *transcodeProgress) update(variant string, encoded time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.encoded[variant] = min(encoded, p.duration)
+	var total time.Duration
+	for _, d := range p.encoded {
+		total += d
+	}
+	percent := int(100 * total / (p.duration * time.Duration(p.variants)))
+	p.report(min(percent, maxEncodeProgress))
+}
+
+// finish removes the recorded progress once the transcode stops, successfully or not.
+func (p *transcodeProgress) finish() {
+	if p.store == nil {
+		return
+	}
+	if err := p.store.Delete(p.ctx, p.videoID); err != nil {
+		slog.WarnContext(p.ctx, "failed to clear transcode progress",
+			"video_id", p.videoID,
+			"error", err,
+		)
+	}
+}
+
+// report records percent if it is higher than the last recorded value. Progress is
+// best-effort, so failures are logged and never fail the transcode.
+// The caller must hold p.mu.
+func (p *transcodeProgress) report(percent int) {
+	if percent <= p.reported {
+		return
+	}
+	p.reported = percent
+	if p.store != nil {
+		if err := p.store.Set(p.ctx, p.videoID, percent); err != nil {
+			slog.WarnContext(p.ctx, "failed to record transcode progress",
+				"video_id", p.videoID,
+				"percent", percent,
+				"error", err,
+			)
+		}
+	}
+	if p.events != nil {
+		event := cache.VideoEvent{Type: cache.VideoEventProgress, VideoID: p.videoID, Progress: percent}
+		if err := p.events.Publish(p.ctx, event); err != nil {
+			slog.WarnContext(p.ctx, "failed to publish transcode progress",
+				"video_id", p.videoID,
+				"percent", percent,
+				"error", err,
+			)
+		}
+	}
+}
@@ -1,95 +1,355 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
 	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
 	"github.com/hszk-dev/gostream/internal/transcoder"
 )
 
 const (
 	// DefaultMaxRetries is the default maximum number of retry attempts before marking as failed.
 	DefaultMaxRetries = 3
+
+	// jobRecordTimeout bounds persisting a job record after the task's context may have been cancelled.
+	jobRecordTimeout = 5 * time.Second
 )
 
+// ErrTaskExpired is recorded as the error of the job that dropped an expired task.
+var ErrTaskExpired = errors.New("task_expired")
+
 // TranscodeServiceConfig holds configuration for TranscodeService.
 type TranscodeServiceConfig struct {
 	// TempDir is the base directory for temporary files during transcoding.
 	TempDir string
 	// MaxRetries is the maximum number of retry attempts before marking video as failed.
 	MaxRetries int
+	// DownloadConcurrency is the number of parallel range requests used to fetch originals.
+	// Values <= 1 disable ranged downloads.
+	DownloadConcurrency int
+	// DownloadChunkSize is the size in bytes of each range request.
+	DownloadChunkSize int64
+	// OutputFormats selects the streaming formats produced; the zero value produces HLS only.
+	OutputFormats transcoder.OutputFormats
+	// ABRProfiles holds the ladders tasks may name; nil offers the built-in ladder only.
+	ABRProfiles *transcoder.ABRProfiles
+	// AudioOnlyBitrate is the bitrate of the audio-only rendition added to the HLS output of
+	// sources with audio; 0 omits it. Requires a prober to tell whether the source has audio.
+	AudioOnlyBitrate int
+	// AudioOnlySources transcodes sources without a video stream (e.g., podcasts) to a
+	// standalone audio-only HLS output instead of failing them. Requires a prober.
+	AudioOnlySources bool
+	// MaxTenantEncodes is the default number of encodes one tenant (the video owner) may
+	// run at once across the fleet; tasks over the limit are deferred. Zero is unlimited.
+	// Requires an encode slot store.
+	MaxTenantEncodes int
+	// TenantEncodeLimits overrides MaxTenantEncodes per tenant ID.
+	TenantEncodeLimits map[string]int
+	// EncodeSlotTimeout is how long the slot of an encode outlives its last heartbeat,
+	// bounding how long a crashed worker holds it; zero uses DefaultEncodeSlotTimeout.
+	EncodeSlotTimeout time.Duration
+	// FFmpegLogs selects the jobs whose FFmpeg output is uploaded to FFmpegLogKey; the zero
+	// value uploads none.
+	FFmpegLogs FFmpegLogMode
+	// TaskTimeout caps the duration of one attempt; an attempt running longer has its FFmpeg
+	// runs killed and fails with ErrTaskTimeout, which counts as a retry. Zero is no cap.
+	TaskTimeout time.Duration
+	// TaskTimeoutFactor tightens the deadline to this multiple of the probed source duration,
+	// and no less than 10 minutes. Zero sizes no deadline by the source. Requires a prober.
+	TaskTimeoutFactor float64
+	// CacheWriteThroughTTL, when positive, writes a video whose output was published into
+	// the video cache for this long instead of evicting it, so its first viewer is served
+	// from cache. Zero only evicts. Requires a video cache.
+	CacheWriteThroughTTL time.Duration
+	// CacheStaleTTL is how long a written-through video is retained for stale reads past
+	// CacheWriteThroughTTL, matching the API's setting.
+	CacheStaleTTL time.Duration
+	// SupersededOutputGrace, when positive, schedules the deletion of the output version a
+	// regenerated one replaced this long after the swap, once CDN caches and players have
+	// moved on. Zero keeps superseded versions until the video is deleted. Requires a
+	// superseded output repository.
+	SupersededOutputGrace time.Duration
 }
 
 // DefaultTranscodeServiceConfig returns the default configuration.
 func DefaultTranscodeServiceConfig() TranscodeServiceConfig {
 	return TranscodeServiceConfig{
-		TempDir:    os.TempDir(),
-		MaxRetries: DefaultMaxRetries,
+		TempDir:             os.TempDir(),
+		MaxRetries:          DefaultMaxRetries,
+		DownloadConcurrency: DefaultDownloadConcurrency,
+		DownloadChunkSize:   DefaultDownloadChunkSize,
+		OutputFormats:       transcoder.OutputFormatsHLS,
 	}
 }
 
 // TranscodeService defines the interface for video transcoding operations.
 type TranscodeService interface {
 	// ProcessTask handles a transcoding task from the message queue.
-	// Returns nil on success or permanent failure (max retries exceeded).
-	// Returns error for transient failures that should trigger a retry.
+	// Returns nil on success, an error wrapping repository.ErrPermanentTaskFailure once
+	// max retries are exceeded, an error errs.Retryable rejects when the input can never
+	// be transcoded, an error wrapping repository.ErrTaskDeferred when the video's tenant
+	// is running its maximum number of encodes, and any other error for failures that
+	// should be retried.
 	ProcessTask(ctx context.Context, task repository.TranscodeTask) error
 }
 
 type transcodeService struct {
 	repo       repository.VideoRepository
 	storage    repository.ObjectStorage
+	replica    repository.ObjectStorage
 	transcoder transcoder.Transcoder
+	prober     transcoder.Prober
 	cache      cache.VideoCache
+	progress   cache.TranscodeProgressStore
+	events     cache.VideoEventBus
+	slots      cache.EncodeSlotStore
+	purger     repository.CDNPurger
+	jobs       repository.TranscodeJobRepository
+	renditions repository.RenditionRepository
+	subtitles  repository.SubtitleRepository
+	keys       repository.EncryptionKeyRepository
+	estimator  TranscodeEstimator
+	outputs    OutputBucketResolver
+	downloader *rangeDownloader
+
+	statusEvents repository.VideoStatusEventRepository
+	superseded   repository.SupersededOutputRepository
+
+	// output is where the task's output is written: storage, or the tenant's output
+	// bucket when externalOutput is set (see forOutput).
+	output         repository.ObjectStorage
+	externalOutput bool
 
 	tempDir    string
 	maxRetries int
+	formats    transcoder.OutputFormats
+	profiles   *transcoder.ABRProfiles
+
+	audioOnlyBitrate int
+	audioOnlySources bool
+
+	maxTenantEncodes   int
+	tenantEncodeLimits map[string]int
+	encodeSlotTimeout  time.Duration
+
+	ffmpegLogs FFmpegLogMode
+
+	taskTimeout       time.Duration
+	taskTimeoutFactor float64
+
+	cacheTTL      time.Duration
+	cacheStaleTTL time.Duration
+
+	supersededGrace time.Duration
 }
 
 // NewTranscodeService creates a new TranscodeService instance.
-// The cache parameter is optional - pass nil to disable cache invalidation.
+// The replica parameter is optional - pass nil to disable cross-region replication of HLS output.
+// The prober parameter is optional - pass nil to transcode the full ABR ladder without
+// recording source metadata on the video.
+// The purger parameter is optional - pass nil to leave superseded output to expire from the CDN.
+// The jobs parameter is optional - pass nil to skip persisting per-attempt timing records.
+// The renditions parameter is optional - pass nil to skip persisting per-rendition records.
+// The subtitles parameter is optional - pass nil to publish HLS output without subtitle tracks.
+// The keys parameter is optional - pass nil to publish HLS segments in the clear. With keys,
+// every HLS variant is encrypted with the video's AES-128 key, served by KeyService.
+// The estimator parameter is optional - pass nil to record jobs without a cost estimate.
+// The outputs parameter is optional - pass nil to write all output to storage. With outputs,
+// the output of tenants with an output bucket is written there, and not replicated.
+// The cache parameter is optional - pass nil to disable cache invalidation and write-through.
+// The progress parameter is optional - pass nil to skip recording encode progress. Progress
+// is also skipped when the source duration is unknown, i.e. without a prober.
+// The events parameter is optional - pass nil to not publish status and progress events.
+// The slots parameter is optional - pass nil to not cap the concurrent encodes of a tenant.
+// The statusEvents parameter is optional - pass nil to not record status transitions.
+// The superseded parameter is optional - pass nil to keep superseded output versions until
+// the video is deleted.
 func NewTranscodeService(
 	repo repository.VideoRepository,
 	storage repository.ObjectStorage,
+	replica repository.ObjectStorage,
 	tc transcoder.Transcoder,
+	prober transcoder.Prober,
 	videoCache cache.VideoCache,
+	progress cache.TranscodeProgressStore,
+	events cache.VideoEventBus,
+	slots cache.EncodeSlotStore,
+	purger repository.CDNPurger,
+	jobs repository.TranscodeJobRepository,
+	renditions repository.RenditionRepository,
+	subtitles repository.SubtitleRepository,
+	keys repository.EncryptionKeyRepository,
+	estimator TranscodeEstimator,
+	outputs OutputBucketResolver,
+	statusEvents repository.VideoStatusEventRepository,
+	superseded repository.SupersededOutputRepository,
 	cfg TranscodeServiceConfig,
 ) TranscodeService {
 	return &transcodeService{
 		repo:       repo,
 		storage:    storage,
+		replica:    replica,
 		transcoder: tc,
+		prober:     prober,
 		cache:      videoCache,
+		progress:   progress,
+		events:     events,
+		slots:      slots,
+		purger:     purger,
+		jobs:       jobs,
+		renditions: renditions,
+		subtitles:  subtitles,
+		keys:       keys,
+		estimator:  estimator,
+		outputs:    outputs,
+		output:     storage,
+		downloader: &rangeDownloader{
+			storage:     storage,
+			concurrency: cfg.DownloadConcurrency,
+			chunkSize:   cfg.DownloadChunkSize,
+		},
+		statusEvents: statusEvents,
+		superseded:   superseded,
+
 		tempDir:    cfg.TempDir,
 		maxRetries: cfg.MaxRetries,
+		formats:    cfg.OutputFormats,
+		profiles:   abrProfilesOrDefault(cfg.ABRProfiles),
+
+		audioOnlyBitrate: cfg.AudioOnlyBitrate,
+		audioOnlySources: cfg.AudioOnlySources,
+
+		maxTenantEncodes:   cfg.MaxTenantEncodes,
+		tenantEncodeLimits: cfg.TenantEncodeLimits,
+		encodeSlotTimeout:  cmp.Or(cfg.EncodeSlotTimeout, DefaultEncodeSlotTimeout),
+
+		ffmpegLogs: cfg.FFmpegLogs,
+
+		taskTimeout:       cfg.TaskTimeout,
+		taskTimeoutFactor: cfg.TaskTimeoutFactor,
+
+		cacheTTL:      cfg.CacheWriteThroughTTL,
+		cacheStaleTTL: cfg.CacheStaleTTL,
+
+		supersededGrace: cfg.SupersededOutputGrace,
 	}
 }
 
 // ProcessTask handles a transcoding task.
-// It downloads the original video, transcodes to ABR (Adaptive Bitrate) HLS,
-// uploads the results, and updates the video status in the database.
+// It downloads the original video, transcodes to ABR (Adaptive Bitrate) HLS and/or
+// MPEG-DASH, uploads the results, and updates the video status in the database.
+// Every attempt is recorded as a transcode job with a per-stage timing breakdown.
+// SubtitlesOnly tasks only rebuild the subtitle tracks of the published output.
+// A task whose tenant is running its maximum number of encodes is deferred before any
+// work is done. An attempt running past its deadline is cancelled by a watchdog and
+// returns an error wrapping ErrTaskTimeout. With an FFmpeg log mode, the output of the attempt's FFmpeg runs is
+// uploaded to FFmpegLogKey.
 func (s *transcodeService) ProcessTask(ctx context.Context, task repository.TranscodeTask) error {
-	// Check if max retries exceeded - mark as failed and return nil (ack the message)
+	if task.SubtitlesOnly {
+		return s.processSubtitles(ctx, task)
+	}
+
+	// Check if max retries exceeded - mark as failed and dead-letter the message
 	if task.RetryCount >= s.maxRetries {
-		if err := s.markVideoFailed(ctx, task.VideoID); err != nil {
-			slog.Error("failed to mark video as failed",
+		reason := fmt.Sprintf("retries exhausted after %d attempts", task.RetryCount)
+		if err := s.markVideoFailed(ctx, task.VideoID, model.FailureCodeRetriesExhausted, reason); err != nil {
+			// The video remains in PROCESSING state until the dead letter is requeued
+			slog.ErrorContext(ctx, "failed to mark video as failed",
 				"video_id", task.VideoID,
 				"retry_count", task.RetryCount,
 				"error", err,
 			)
-			// Still return nil to ack the message
-			// The video remains in PROCESSING state, which is acceptable
-			return nil
 		}
-		return nil
+		return fmt.Errorf("%w: retry count %d reached the limit of %d", repository.ErrPermanentTaskFailure, task.RetryCount, s.maxRetries)
+	}
+
+	if !task.ExpiresAt.IsZero() && time.Now().After(task.ExpiresAt) {
+		return s.expireTask(ctx, task)
+	}
+
+	release, err := s.acquireEncodeSlot(ctx, task)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	job := model.NewTranscodeJob(task.VideoID, task.OutputVersion, task.RetryCount, time.Now())
+	job.QueuedAt = task.EnqueuedAt
+	logCtx, uploadLog := s.startFFmpegLog(ctx, job)
+	taskCtx, watchdog := s.startWatchdog(logCtx, job.StartedAt)
+	err = s.process(taskCtx, task, job, watchdog)
+	if timeoutErr := watchdog.stop(); timeoutErr != nil && err != nil {
+		// Report the deadline rather than the cancellation it caused
+		err = fmt.Errorf("%w: %w", timeoutErr, err)
+		metrics.TranscodeTasksTimedOutTotal.Inc()
+	}
+	uploadLog(err)
+	permanent := err != nil && !errs.Retryable(err)
+	// A failure is final when the redelivery would exceed maxRetries and only mark the video FAILED
+	job.Final = err == nil || permanent || task.RetryCount+1 >= s.maxRetries
+	if err != nil && job.Final && ctx.Err() == nil {
+		// Retrying cannot fix the input or no retry is left, so fail the video now while
+		// the cause is known rather than when the redelivery is dead-lettered
+		if markErr := s.markVideoFailed(ctx, task.VideoID, failureCodeOf(err), err.Error()); markErr != nil {
+			slog.ErrorContext(ctx, "failed to mark video as failed",
+				"video_id", task.VideoID,
+				"error", markErr,
+			)
+		}
+	}
+	s.recordJob(ctx, job, err)
+
+	return err
+}
+
+// expireTask fails the video of a task dequeued past its expiry and acks the task.
+// By then the user may have deleted or re-uploaded the video, so transcoding the
+// original would at best waste an encode. Only a PROCESSING video is failed, which
+// leaves deleted videos and the published output of a stale retranscode untouched.
+func (s *transcodeService) expireTask(ctx context.Context, task repository.TranscodeTask) error {
+	if err := s.markVideoFailed(ctx, task.VideoID, model.FailureCodeExpired, "transcode task expired in the queue"); err != nil {
+		return fmt.Errorf("fail expired task: %w", err)
+	}
+
+	metrics.TranscodeTasksExpiredTotal.Inc()
+	slog.WarnContext(ctx, "dropped expired transcode task",
+		"video_id", task.VideoID,
+		"expires_at", task.ExpiresAt,
+		"enqueued_at", task.EnqueuedAt,
+	)
+
+	job := model.NewTranscodeJob(task.VideoID, task.OutputVersion, task.RetryCount, time.Now())
+	job.QueuedAt = task.EnqueuedAt
+	job.Final = true
+	s.recordJob(ctx, job, ErrTaskExpired)
+	return nil
+}
+
+// process runs the transcode pipeline, recording the duration of each stage, the probed
+// source and the output size into job. The watchdog's deadline is sized by the probed source.
+func (s *transcodeService) process(ctx context.Context, task repository.TranscodeTask, job *model.TranscodeJob, watchdog *taskWatchdog) error {
+	timings := &job.Timings
+
+	s, err := s.forOutput(ctx, task.VideoID)
+	if err != nil {
+		return err
 	}
 
 	// Create temporary working directory for this task
@@ -100,36 +360,426 @@ func (s *transcodeService) ProcessTask(ctx context.Context, task repository.Tran
 	defer s.cleanup(workDir)
 
 	// Download original video
+	start := time.Now()
 	inputPath, err := s.downloadOriginal(ctx, task.OriginalKey, workDir)
+	timings.Download = time.Since(start)
 	if err != nil {
 		return fmt.Errorf("download original: %w", err)
 	}
 
-	// Create output directory for HLS files
+	// Probe the original so the ladder never upscales it
+	var source *transcoder.ProbeResult
+	var audioOnly bool
+	if s.prober != nil {
+		start = time.Now()
+		source, audioOnly, err = s.probeSource(ctx, task.VideoID, inputPath)
+		timings.Probe = time.Since(start)
+		if err != nil {
+			return fmt.Errorf("probe: %w", err)
+		}
+		if source != nil {
+			watchdog.sizeBySource(source.Duration)
+		}
+	}
+
+	// Transcode to ABR (multiple quality variants) in every configured format. An
+	// audio-only source has a single audio rendition, which only HLS carries.
+	formats := s.formats
+	var variants, hlsVariants []transcoder.Variant
+	if audioOnly {
+		formats = transcoder.OutputFormatsHLS
+		hlsVariants = []transcoder.Variant{transcoder.AudioOnlyVariant(cmp.Or(s.audioOnlyBitrate, transcoder.DefaultAudioOnlyBitrate))}
+	} else {
+		ladder, err := resolveLadder(s.profiles, task.ABRProfile)
+		if err != nil {
+			// The API validates overrides, so only a task from a differently configured or
+			// incompatible release lands here
+			return err
+		}
+		variants, err = selectVariants(ladder, task.Variants)
+		if err != nil {
+			return err
+		}
+		if source != nil {
+			variants = transcoder.VariantsForSource(variants, source.Height)
+		}
+		hlsVariants = variants
+		if s.audioOnlyBitrate > 0 && source != nil && source.HasAudio {
+			hlsVariants = append(slices.Clone(variants), transcoder.AudioOnlyVariant(s.audioOnlyBitrate))
+		}
+	}
+	var onProgress transcoder.ProgressFunc
+	if (s.progress != nil || s.events != nil) && source != nil && source.Duration > 0 {
+		// The DASH encode is reported as a single stream next to the HLS variants
+		streams := 0
+		if formats.HLS() {
+			streams += len(hlsVariants)
+		}
+		if formats.DASH() {
+			streams++
+		}
+		tracker := newTranscodeProgress(ctx, s.progress, s.events, task.VideoID, source.Duration, streams)
+		tracker.start()
+		defer tracker.finish()
+		onProgress = tracker.update
+	}
+
+	var abrOutput *transcoder.ABROutput
+	if formats.HLS() {
+		hlsVariants, err = s.encryptVariants(ctx, task.VideoID, workDir, hlsVariants)
+		if err != nil {
+			return err
+		}
+		abrOutput, err = s.transcodeHLS(ctx, inputPath, workDir, hlsVariants, onProgress, job)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			source = abrOutput.Source
+		}
+	}
+	var dashOutput *transcoder.DASHOutput
+	if formats.DASH() {
+		start = time.Now()
+		dashOutput, err = s.transcodeDASH(ctx, inputPath, workDir, variants, onProgress)
+		timings.Transcode += time.Since(start)
+		if err != nil {
+			return fmt.Errorf("transcode DASH: %w", err)
+		}
+	}
+	if source != nil {
+		job.SourceDuration = source.Duration
+		job.SourceHeight = source.Height
+	}
+	var thumbnails []transcoder.ThumbnailOutput
+	if !audioOnly {
+		start = time.Now()
+		thumbnails = s.generateThumbnails(ctx, task.VideoID, inputPath, workDir, source)
+		timings.Transcode += time.Since(start)
+	}
+
+	// List the video's subtitle tracks in the HLS output
+	var subtitleTracks []subtitleTrack
+	if abrOutput != nil {
+		var duration time.Duration
+		if source != nil {
+			duration = source.Duration
+		}
+		subtitleTracks, err = s.addSubtitleTracks(ctx, task.VideoID, workDir, abrOutput.MasterManifestPath, duration)
+		if err != nil {
+			return fmt.Errorf("subtitles: %w", err)
+		}
+	}
+
+	// Upload the output to object storage, recording a checksum of each file
+	sums := newOutputChecksums()
+	var masterKey, dashKey, thumbnailPrefix string
+	variantBytes := make(map[string]int64, len(hlsVariants))
+	start = time.Now()
+	if abrOutput != nil {
+		var hlsBytes map[string]int64
+		masterKey, hlsBytes, err = s.uploadABRFiles(ctx, task.OutputKey, abrOutput, sums)
+		if err != nil {
+			timings.Upload = time.Since(start)
+			return fmt.Errorf("upload ABR files: %w", err)
+		}
+		for name, n := range hlsBytes {
+			variantBytes[name] += n
+			job.OutputBytes += n
+		}
+	}
+	if len(subtitleTracks) > 0 {
+		subtitleBytes, err := s.uploadSubtitleTracks(ctx, task.OutputKey, subtitleTracks, sums)
+		if err != nil {
+			timings.Upload = time.Since(start)
+			return fmt.Errorf("upload subtitles: %w", err)
+		}
+		job.OutputBytes += subtitleBytes
+	}
+	if dashOutput != nil {
+		var dashBytes int64
+		dashKey, dashBytes, err = s.uploadDASHFiles(ctx, task.OutputKey, dashOutput, variantBytes, sums)
+		if err != nil {
+			timings.Upload = time.Since(start)
+			return fmt.Errorf("upload DASH files: %w", err)
+		}
+		job.OutputBytes += dashBytes
+	}
+	if len(thumbnails) > 0 {
+		var thumbnailBytes int64
+		thumbnailPrefix, thumbnailBytes, err = s.uploadThumbnails(ctx, task.OutputKey, thumbnails, sums)
+		if err != nil {
+			timings.Upload = time.Since(start)
+			return fmt.Errorf("upload thumbnails: %w", err)
+		}
+		job.OutputBytes += thumbnailBytes
+	}
+	timings.Upload = time.Since(start)
+
+	// Generate the public preview as an extra output when requested
+	var previewKey string
+	if task.PreviewKey != "" && task.PreviewSeconds > 0 {
+		start = time.Now()
+		previewKey, err = s.processPreview(ctx, task, inputPath, workDir, sums)
+		timings.Preview = time.Since(start)
+		if err != nil {
+			return fmt.Errorf("preview: %w", err)
+		}
+	}
+
+	// Store the checksum manifest and record the renditions before publishing, so a
+	// READY output always has them (first transcode or regeneration), then point the
+	// video at the new output
+	start = time.Now()
+	manifestKey := masterKey
+	if manifestKey == "" {
+		manifestKey = dashKey
+	}
+	if err := s.uploadChecksums(ctx, manifestKey, workDir, sums); err != nil {
+		timings.Finalize = time.Since(start)
+		return fmt.Errorf("upload checksums: %w", err)
+	}
+	var renditionOutputs []transcoder.VariantOutput
+	if abrOutput != nil {
+		renditionOutputs = abrOutput.Variants
+	} else {
+		renditionOutputs = dashOutput.Variants
+	}
+	if err := s.saveRenditions(ctx, task, renditionOutputs, variantBytes); err != nil {
+		timings.Finalize = time.Since(start)
+		return fmt.Errorf("save renditions: %w", err)
+	}
+	err = s.markVideoReady(ctx, task, publishedOutput{
+		hlsKey:          masterKey,
+		dashKey:         dashKey,
+		previewKey:      previewKey,
+		thumbnailPrefix: thumbnailPrefix,
+		bytes:           job.OutputBytes,
+	})
+	timings.Finalize = time.Since(start)
+	if err != nil {
+		return fmt.Errorf("update video status: %w", err)
+	}
+
+	return nil
+}
+
+// forOutput returns the service to run a task for the video with: s itself, or a copy
+// that writes to the output bucket of the video's owner instead of storage and the
+// replica. A bucket that cannot be opened fails the task so it is retried, rather than
+// writing the output where the tenant does not expect it.
+func (s *transcodeService) forOutput(ctx context.Context, videoID uuid.UUID) (*transcodeService, error) {
+	if s.outputs == nil {
+		return s, nil
+	}
+
+	video, err := s.repo.GetByID(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, repository.ErrVideoNotFound) {
+			// Nothing to write for; the pipeline handles the missing video
+			return s, nil
+		}
+		return nil, fmt.Errorf("get video: %w", err)
+	}
+	output, err := s.outputs.OutputStorage(ctx, video.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("open output bucket: %w", err)
+	}
+	if output == nil {
+		return s, nil
+	}
+	return s.withOutput(output), nil
+}
+
+// withOutput returns a copy of s that writes its output to the tenant bucket output.
+func (s *transcodeService) withOutput(output repository.ObjectStorage) *transcodeService {
+	t := *s
+	t.output = output
+	t.replica = nil
+	t.externalOutput = true
+	return &t
+}
+
+// transcodeHLS encodes the ABR ladder to HLS under workDir, recording the transcode and
+// per-variant timings into job.
+func (s *transcodeService) transcodeHLS(ctx context.Context, inputPath, workDir string, variants []transcoder.Variant, onProgress transcoder.ProgressFunc, job *model.TranscodeJob) (*transcoder.ABROutput, error) {
 	outputDir := filepath.Join(workDir, "hls")
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("create output directory: %w", err)
+		return nil, fmt.Errorf("create output directory: %w", err)
 	}
 
-	// Transcode to ABR (multiple quality variants)
-	variants := transcoder.DefaultABRVariants()
-	abrOutput, err := s.transcoder.TranscodeToABR(ctx, inputPath, outputDir, variants)
+	start := time.Now()
+	abrOutput, err := s.transcoder.TranscodeToABR(ctx, inputPath, outputDir, variants, onProgress)
+	elapsed := time.Since(start)
 	if err != nil {
-		return fmt.Errorf("transcode: %w", err)
+		job.Timings.Transcode += elapsed
+		return nil, fmt.Errorf("transcode: %w", err)
+	}
+	// Probing inside the transcoder is carved out of the transcode stage
+	job.Timings.Probe += abrOutput.ProbeDuration
+	job.Timings.Transcode += elapsed - abrOutput.ProbeDuration
+	for _, v := range abrOutput.Variants {
+		job.Timings.Variants[v.Variant.Name] = v.Duration
+	}
+
+	return abrOutput, nil
+}
+
+// generateThumbnails extracts the poster images from a tenth of the way into the source,
+// past any fade-in, or from the first frame when the duration is unknown. Thumbnails are
+// best-effort: failures are logged and the output is published without them.
+func (s *transcodeService) generateThumbnails(ctx context.Context, videoID uuid.UUID, inputPath, workDir string, source *transcoder.ProbeResult) []transcoder.ThumbnailOutput {
+	outputDir := filepath.Join(workDir, "thumbnails")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		slog.WarnContext(ctx, "failed to create thumbnail directory",
+			"video_id", videoID,
+			"error", err,
+		)
+		return nil
 	}
 
-	// Upload ABR files to object storage
-	masterKey, err := s.uploadABRFiles(ctx, task.OutputKey, abrOutput)
+	var at time.Duration
+	if source != nil {
+		at = source.Duration / 10
+	}
+	thumbnails, err := s.transcoder.GenerateThumbnails(ctx, inputPath, outputDir, at, transcoder.DefaultThumbnailSizes())
 	if err != nil {
-		return fmt.Errorf("upload ABR files: %w", err)
+		slog.WarnContext(ctx, "failed to generate thumbnails",
+			"video_id", videoID,
+			"error", err,
+		)
+		return nil
 	}
+	return thumbnails
+}
 
-	// Update video status to READY
-	if err := s.markVideoReady(ctx, task.VideoID, masterKey); err != nil {
-		return fmt.Errorf("update video status: %w", err)
+// transcodeDASH encodes the ABR ladder to MPEG-DASH under workDir.
+func (s *transcodeService) transcodeDASH(ctx context.Context, inputPath, workDir string, variants []transcoder.Variant, onProgress transcoder.ProgressFunc) (*transcoder.DASHOutput, error) {
+	outputDir := filepath.Join(workDir, "dash")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("create output directory: %w", err)
 	}
 
-	return nil
+	return s.transcoder.TranscodeToDASH(ctx, inputPath, outputDir, variants, onProgress)
+}
+
+// recordJob finalizes the job, exports its stage timings as metrics, and persists it.
+// Persistence errors are logged but not propagated - the job record is diagnostic only.
+func (s *transcodeService) recordJob(ctx context.Context, job *model.TranscodeJob, err error) {
+	job.Finish(err, time.Now())
+
+	status := metrics.TranscodeJobSucceeded
+	if err != nil {
+		status = metrics.TranscodeJobFailed
+	}
+	for stage, d := range map[string]time.Duration{
+		model.StageDownload:  job.Timings.Download,
+		model.StageProbe:     job.Timings.Probe,
+		model.StageTranscode: job.Timings.Transcode,
+		model.StageUpload:    job.Timings.Upload,
+		model.StagePreview:   job.Timings.Preview,
+		model.StageFinalize:  job.Timings.Finalize,
+	} {
+		// Skip stages that never ran so failures do not skew the distributions toward zero
+		if d > 0 {
+			metrics.TranscodeStageDurationSeconds.WithLabelValues(stage, status).Observe(d.Seconds())
+		}
+	}
+	for variant, d := range job.Timings.Variants {
+		metrics.TranscodeVariantDurationSeconds.WithLabelValues(variant).Observe(d.Seconds())
+	}
+	metrics.TranscodeAttemptsTotal.WithLabelValues(status).Inc()
+	metrics.TranscodeDurationSeconds.WithLabelValues(status).Observe(job.Duration().Seconds())
+	if err == nil && job.OutputBytes > 0 {
+		metrics.TranscodeOutputBytes.Observe(float64(job.OutputBytes))
+	}
+	recordSLI(job)
+
+	// The task context may already be cancelled (e.g., worker shutdown), which is exactly
+	// when a record of the interrupted attempt is most useful
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobRecordTimeout)
+	defer cancel()
+
+	s.estimateJob(recordCtx, job)
+
+	if s.jobs == nil {
+		return
+	}
+
+	if err := s.jobs.Create(recordCtx, job); err != nil {
+		slog.WarnContext(ctx, "failed to record transcode job",
+			"video_id", job.VideoID,
+			"job_id", job.ID,
+			"error", err,
+		)
+	}
+}
+
+// estimateJob records what the estimator predicts for the job's source next to the
+// actual outcome. The job is not persisted yet, so the prediction only uses earlier jobs.
+func (s *transcodeService) estimateJob(ctx context.Context, job *model.TranscodeJob) {
+	if s.estimator == nil || job.SourceDuration <= 0 {
+		return
+	}
+
+	estimate, err := s.estimator.Estimate(ctx, transcoder.ProbeResult{
+		Duration: job.SourceDuration,
+		Height:   job.SourceHeight,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to estimate transcode cost",
+			"video_id", job.VideoID,
+			"job_id", job.ID,
+			"error", err,
+		)
+		return
+	}
+	if estimate == nil {
+		return
+	}
+	job.Estimate = estimate
+
+	if job.Status != model.JobStatusSucceeded {
+		return
+	}
+	if estimate.ProcessingTime > 0 {
+		metrics.TranscodeEstimateRatio.WithLabelValues(metrics.EstimateProcessingTime).
+			Observe(job.Duration().Seconds() / estimate.ProcessingTime.Seconds())
+	}
+	if estimate.OutputBytes > 0 {
+		metrics.TranscodeEstimateRatio.WithLabelValues(metrics.EstimateOutputBytes).
+			Observe(float64(job.OutputBytes) / float64(estimate.OutputBytes))
+	}
+}
+
+// probeSource probes the downloaded original and records its metadata on the video.
+// It reports whether the source is audio-only, which is accepted when audioOnlySources is
+// set. Any other input without a video stream fails permanently: transcoder.ErrNoVideoStream
+// is coded errs.Invalid, which no retry can fix.
+func (s *transcodeService) probeSource(ctx context.Context, videoID uuid.UUID, inputPath string) (*transcoder.ProbeResult, bool, error) {
+	source, err := s.prober.Probe(ctx, inputPath)
+	audioOnly := errors.Is(err, transcoder.ErrNoVideoStream) && s.audioOnlySources && source != nil && source.HasAudio
+	if err != nil && !audioOnly {
+		return nil, false, err
+	}
+
+	if err := s.repo.UpdateSource(ctx, videoID, sourceMetadata(source)); err != nil {
+		return nil, false, fmt.Errorf("save source metadata: %w", err)
+	}
+
+	return source, audioOnly, nil
+}
+
+// sourceMetadata converts a probe result to the metadata stored on the video.
+func sourceMetadata(p *transcoder.ProbeResult) model.SourceMetadata {
+	return model.SourceMetadata{
+		Duration:  p.Duration,
+		Width:     p.Width,
+		Height:    p.Height,
+		Codec:     p.Codec,
+		Bitrate:   p.Bitrate,
+		FrameRate: p.FrameRate,
+	}
 }
 
 // createWorkDir creates a temporary directory for processing a specific video.
@@ -147,13 +797,8 @@ func (s *transcodeService) cleanup(workDir string) {
 }
 
 // downloadOriginal downloads the original video from object storage to a local file.
+// Large objects are fetched with parallel range requests and verified against the storage checksum.
 func (s *transcodeService) downloadOriginal(ctx context.Context, key, workDir string) (string, error) {
-	reader, err := s.storage.Download(ctx, key)
-	if err != nil {
-		return "", fmt.Errorf("storage download: %w", err)
-	}
-	defer func() { _ = reader.Close() }()
-
 	// Extract filename from key or use default
 	filename := filepath.Base(key)
 	if filename == "." || filename == "/" {
@@ -161,99 +806,385 @@ func (s *transcodeService) downloadOriginal(ctx context.Context, key, workDir st
 	}
 
 	localPath := filepath.Join(workDir, filename)
-	file, err := os.Create(localPath)
+	start := time.Now()
+	size, err := s.downloader.download(ctx, key, localPath)
 	if err != nil {
-		return "", fmt.Errorf("create local file: %w", err)
-	}
-
-	if _, err := io.Copy(file, reader); err != nil {
-		_ = file.Close()
-		return "", fmt.Errorf("copy to local file: %w", err)
-	}
-
-	if err := file.Close(); err != nil {
-		return "", fmt.Errorf("close local file: %w", err)
+		metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpDownload).Inc()
+		return "", err
 	}
+	recordTransfer(metrics.StorageOpDownload, size, time.Since(start))
 
 	return localPath, nil
 }
 
 // uploadABRFiles uploads all ABR files (master manifest, variant playlists, and segments) to object storage.
-// Returns the full key path to the master manifest file.
-func (s *transcodeService) uploadABRFiles(ctx context.Context, outputKeyPrefix string, abrOutput *transcoder.ABROutput) (string, error) {
+// Returns the full key path to the master manifest file and the bytes uploaded per variant.
+func (s *transcodeService) uploadABRFiles(ctx context.Context, outputKeyPrefix string, abrOutput *transcoder.ABROutput, sums *OutputChecksums) (string, map[string]int64, error) {
+	// Throughput is measured across the whole output set rather than per file:
+	// individual segments are small enough that per-request overhead dominates.
+	var uploaded int64
+	start := time.Now()
+	defer func() {
+		recordTransfer(metrics.StorageOpUpload, uploaded, time.Since(start))
+	}()
+
 	// Upload master manifest
 	masterKey := outputKeyPrefix + "master.m3u8"
-	if err := s.uploadFile(ctx, abrOutput.MasterManifestPath, masterKey, "application/vnd.apple.mpegurl"); err != nil {
-		return "", fmt.Errorf("upload master manifest: %w", err)
+	n, err := s.uploadFile(ctx, abrOutput.MasterManifestPath, masterKey, "application/vnd.apple.mpegurl", sums)
+	if err != nil {
+		return "", nil, fmt.Errorf("upload master manifest: %w", err)
 	}
+	uploaded += n
 
 	// Upload each variant's playlist and segments
+	variantBytes := make(map[string]int64, len(abrOutput.Variants))
 	for _, variant := range abrOutput.Variants {
 		variantPrefix := outputKeyPrefix + variant.Variant.Name + "/"
 
 		// Upload variant playlist
 		playlistKey := variantPrefix + "playlist.m3u8"
-		if err := s.uploadFile(ctx, variant.ManifestPath, playlistKey, "application/vnd.apple.mpegurl"); err != nil {
-			return "", fmt.Errorf("upload %s playlist: %w", variant.Variant.Name, err)
+		n, err := s.uploadFile(ctx, variant.ManifestPath, playlistKey, "application/vnd.apple.mpegurl", sums)
+		if err != nil {
+			return "", nil, fmt.Errorf("upload %s playlist: %w", variant.Variant.Name, err)
 		}
+		uploaded += n
+		variantBytes[variant.Variant.Name] += n
 
 		// Upload segments
 		for _, segmentPath := range variant.SegmentPaths {
 			segmentKey := variantPrefix + filepath.Base(segmentPath)
-			if err := s.uploadFile(ctx, segmentPath, segmentKey, "video/mp2t"); err != nil {
-				return "", fmt.Errorf("upload %s segment %s: %w", variant.Variant.Name, filepath.Base(segmentPath), err)
+			n, err := s.uploadFile(ctx, segmentPath, segmentKey, "video/mp2t", sums)
+			if err != nil {
+				return "", nil, fmt.Errorf("upload %s segment %s: %w", variant.Variant.Name, filepath.Base(segmentPath), err)
 			}
+			uploaded += n
+			variantBytes[variant.Variant.Name] += n
+		}
+	}
+
+	return masterKey, variantBytes, nil
+}
+
+// uploadDASHFiles uploads the MPD manifest and all fMP4 segments next to the HLS output.
+// Bytes of each video representation are added to variantBytes. Returns the full key path
+// to the manifest and the total bytes uploaded, audio and manifest included.
+func (s *transcodeService) uploadDASHFiles(ctx context.Context, outputKeyPrefix string, dashOutput *transcoder.DASHOutput, variantBytes map[string]int64, sums *OutputChecksums) (string, int64, error) {
+	var uploaded int64
+	start := time.Now()
+	defer func() {
+		recordTransfer(metrics.StorageOpUpload, uploaded, time.Since(start))
+	}()
+
+	manifestKey := outputKeyPrefix + transcoder.DASHManifestName
+	n, err := s.uploadFile(ctx, dashOutput.ManifestPath, manifestKey, "application/dash+xml", sums)
+	if err != nil {
+		return "", 0, fmt.Errorf("upload manifest: %w", err)
+	}
+	uploaded += n
+
+	variantOf := make(map[string]string, len(dashOutput.SegmentPaths))
+	for _, v := range dashOutput.Variants {
+		for _, segmentPath := range v.SegmentPaths {
+			variantOf[segmentPath] = v.Variant.Name
+		}
+	}
+
+	for _, segmentPath := range dashOutput.SegmentPaths {
+		n, err := s.uploadFile(ctx, segmentPath, outputKeyPrefix+filepath.Base(segmentPath), "video/mp4", sums)
+		if err != nil {
+			return "", 0, fmt.Errorf("upload segment %s: %w", filepath.Base(segmentPath), err)
+		}
+		uploaded += n
+		if name, ok := variantOf[segmentPath]; ok {
+			variantBytes[name] += n
 		}
 	}
 
-	return masterKey, nil
+	return manifestKey, uploaded, nil
 }
 
-// uploadFile uploads a single file to object storage.
-func (s *transcodeService) uploadFile(ctx context.Context, localPath, key, contentType string) error {
+// uploadThumbnails uploads the poster images under the output's thumbnails/ prefix.
+// Returns the prefix and the bytes uploaded.
+func (s *transcodeService) uploadThumbnails(ctx context.Context, outputKeyPrefix string, thumbnails []transcoder.ThumbnailOutput, sums *OutputChecksums) (string, int64, error) {
+	var uploaded int64
+	start := time.Now()
+	defer func() {
+		recordTransfer(metrics.StorageOpUpload, uploaded, time.Since(start))
+	}()
+
+	prefix := outputKeyPrefix + "thumbnails/"
+	for _, thumbnail := range thumbnails {
+		n, err := s.uploadFile(ctx, thumbnail.Path, prefix+thumbnail.Size.Name+".jpg", "image/jpeg", sums)
+		if err != nil {
+			return "", 0, fmt.Errorf("upload %s: %w", thumbnail.Size.Name, err)
+		}
+		uploaded += n
+	}
+
+	return prefix, uploaded, nil
+}
+
+// saveRenditions persists a record of each uploaded variant for the task's output version.
+// Bytes cover every format the variant was uploaded in.
+// Failures fail the task so it is retried: billing depends on every published output having renditions.
+func (s *transcodeService) saveRenditions(ctx context.Context, task repository.TranscodeTask, outputs []transcoder.VariantOutput, variantBytes map[string]int64) error {
+	if s.renditions == nil {
+		return nil
+	}
+
+	renditions := make([]*model.Rendition, 0, len(outputs))
+	for _, v := range outputs {
+		rendition := model.NewRendition(task.VideoID, task.OutputVersion, v.Variant.Name)
+		rendition.Width = v.Variant.Width()
+		rendition.Height = v.Variant.Height
+		rendition.Bitrate = v.Variant.Bitrate
+		if v.Variant.AudioOnly {
+			rendition.Bitrate = v.Variant.AudioBitrate
+		}
+		rendition.Codec = v.Codec
+		rendition.SegmentCount = len(v.SegmentPaths)
+		rendition.Bytes = variantBytes[v.Variant.Name]
+		renditions = append(renditions, rendition)
+	}
+
+	return s.renditions.SaveAll(ctx, renditions)
+}
+
+// processPreview transcodes and uploads the trimmed preview rendition.
+// Returns the full key path to the preview playlist.
+func (s *transcodeService) processPreview(ctx context.Context, task repository.TranscodeTask, inputPath, workDir string, sums *OutputChecksums) (string, error) {
+	previewDir := filepath.Join(workDir, "preview")
+	if err := os.MkdirAll(previewDir, 0755); err != nil {
+		return "", fmt.Errorf("create preview directory: %w", err)
+	}
+
+	duration := time.Duration(task.PreviewSeconds) * time.Second
+	output, err := s.transcoder.TranscodePreview(ctx, inputPath, previewDir, transcoder.DefaultPreviewVariant(), duration)
+	if err != nil {
+		return "", fmt.Errorf("transcode: %w", err)
+	}
+
+	var uploaded int64
+	start := time.Now()
+	defer func() {
+		recordTransfer(metrics.StorageOpUpload, uploaded, time.Since(start))
+	}()
+
+	playlistKey := task.PreviewKey + "playlist.m3u8"
+	n, err := s.uploadFile(ctx, output.ManifestPath, playlistKey, "application/vnd.apple.mpegurl", sums)
+	if err != nil {
+		return "", fmt.Errorf("upload playlist: %w", err)
+	}
+	uploaded += n
+
+	for _, segmentPath := range output.SegmentPaths {
+		n, err := s.uploadFile(ctx, segmentPath, task.PreviewKey+filepath.Base(segmentPath), "video/mp2t", sums)
+		if err != nil {
+			return "", fmt.Errorf("upload segment %s: %w", filepath.Base(segmentPath), err)
+		}
+		uploaded += n
+	}
+
+	return playlistKey, nil
+}
+
+// uploadFile uploads a single file to object storage, and to the replica when configured.
+// Replication is synchronous: a replica failure fails the task so it is retried, which keeps
+// a video from becoming READY before every region can serve it.
+// When sums is non-nil the file's SHA-256 is recorded in it.
+// Returns the number of bytes uploaded to the primary.
+func (s *transcodeService) uploadFile(ctx context.Context, localPath, key, contentType string, sums *OutputChecksums) (int64, error) {
 	file, err := os.Open(localPath)
 	if err != nil {
-		return fmt.Errorf("open file: %w", err)
+		return 0, fmt.Errorf("open file: %w", err)
 	}
 	defer func() { _ = file.Close() }()
 
-	if err := s.storage.Upload(ctx, key, file, contentType); err != nil {
-		return fmt.Errorf("storage upload: %w", err)
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("stat file: %w", err)
 	}
 
-	return nil
+	// Hash the local file rather than the upload stream, so the checksum does not
+	// depend on how much of the reader the storage client consumes
+	if sums != nil {
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return 0, fmt.Errorf("hash file: %w", err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("rewind file: %w", err)
+		}
+		sums.add(key, info.Size(), hash.Sum(nil))
+	}
+
+	if err := s.output.Upload(ctx, key, file, contentType); err != nil {
+		metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpUpload).Inc()
+		return 0, fmt.Errorf("storage upload: %w", err)
+	}
+
+	if s.replica != nil {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("rewind file: %w", err)
+		}
+		if err := s.replica.Upload(ctx, key, file, contentType); err != nil {
+			metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpReplicate).Inc()
+			return 0, fmt.Errorf("replica upload: %w", err)
+		}
+	}
+
+	return info.Size(), nil
 }
 
-// markVideoReady updates the video status to READY and sets the HLS URL.
-func (s *transcodeService) markVideoReady(ctx context.Context, videoID uuid.UUID, hlsKey string) error {
-	video, err := s.repo.GetByID(ctx, videoID)
+// uploadChecksums stores the checksum manifest next to the master playlist (and on the
+// replica), for later verification of the stored output.
+func (s *transcodeService) uploadChecksums(ctx context.Context, masterKey, workDir string, sums *OutputChecksums) error {
+	data, err := json.MarshalIndent(sums, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	localPath := filepath.Join(workDir, OutputChecksumsFile)
+	if err := os.WriteFile(localPath, data, 0644); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	_, err = s.uploadFile(ctx, localPath, outputChecksumsKey(masterKey), "application/json", nil)
+	return err
+}
+
+// recordTransfer records bytes transferred and effective throughput for a storage operation.
+func recordTransfer(operation string, bytes int64, elapsed time.Duration) {
+	if bytes <= 0 {
+		return
+	}
+	metrics.StorageBytesTransferredTotal.WithLabelValues(operation).Add(float64(bytes))
+	if elapsed > 0 {
+		metrics.StorageThroughputBytesPerSecond.WithLabelValues(operation).Observe(float64(bytes) / elapsed.Seconds())
+	}
+}
+
+// publishedOutput holds the keys a video is pointed at once its output is uploaded.
+// hlsKey and dashKey are empty for formats that were not produced, previewKey when no
+// preview was generated, and thumbnailPrefix when thumbnail generation failed.
+type publishedOutput struct {
+	hlsKey          string
+	dashKey         string
+	previewKey      string
+	thumbnailPrefix string
+	// bytes is the size of the uploaded output, counted against the owner's quota.
+	bytes int64
+}
+
+// markVideoReady points the video at the uploaded output version.
+// A PROCESSING video transitions to READY. A READY video is being regenerated:
+// its output pointer is swapped atomically, leaving the previous version's objects
+// in place for CDN caches to drain until their scheduled deletion.
+func (s *transcodeService) markVideoReady(ctx context.Context, task repository.TranscodeTask, output publishedOutput) error {
+	video, err := s.repo.GetByID(ctx, task.VideoID)
 	if err != nil {
 		return fmt.Errorf("get video: %w", err)
 	}
 
-	// Validate current status
-	if video.Status != model.StatusProcessing {
+	switch video.Status {
+	case model.StatusProcessing:
+		video.SetHLSURL(output.hlsKey)
+		video.SetDashURL(output.dashKey)
+		if output.previewKey != "" {
+			video.SetPreviewURL(output.previewKey)
+		}
+		video.SetThumbnailPrefix(output.thumbnailPrefix)
+		video.SetOutputVersion(task.OutputVersion)
+		video.ExternalOutput = s.externalOutput
+		if err := video.TransitionTo(model.StatusReady); err != nil {
+			return fmt.Errorf("transition to ready: %w", err)
+		}
+
+		if err := s.repo.Update(ctx, video); err != nil {
+			return fmt.Errorf("update video: %w", err)
+		}
+		recordStatusEvent(ctx, s.statusEvents, video, model.StatusProcessing, model.StatusActorWorker,
+			fmt.Sprintf("output version %d published after %d retries", task.OutputVersion, task.RetryCount))
+	case model.StatusReady:
+		err := s.repo.PublishOutput(ctx, task.VideoID, task.OutputVersion, output.hlsKey, output.dashKey, output.previewKey, output.thumbnailPrefix, s.externalOutput)
+		if errors.Is(err, repository.ErrStaleOutputVersion) {
+			// A newer regeneration already won; this output is simply never referenced
+			slog.InfoContext(ctx, "skipping stale output version",
+				"video_id", task.VideoID,
+				"output_version", task.OutputVersion,
+			)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("publish output: %w", err)
+		}
+
+		// The pointer has moved; evict the superseded output so edges stop serving it
+		s.purgeCDN(ctx, task.VideoID, supersededPrefixes(video))
+		s.scheduleSupersededDeletion(ctx, video)
+	default:
 		// Video is not in expected state - log but don't fail
 		return nil
 	}
 
-	video.SetHLSURL(hlsKey)
-	if err := video.TransitionTo(model.StatusReady); err != nil {
-		return fmt.Errorf("transition to ready: %w", err)
-	}
+	s.recordOutputSize(ctx, task.VideoID, output.bytes)
 
-	if err := s.repo.Update(ctx, video); err != nil {
-		return fmt.Errorf("update video: %w", err)
+	// Refresh the cache so the next read sees the published output
+	s.refreshCache(ctx, video)
+	publishStatus(ctx, s.events, video)
+
+	return nil
+}
+
+// scheduleSupersededDeletion schedules the deletion of the output version video pointed at
+// before the swap. Failures are logged and not propagated: the version then stays in
+// storage until the video is deleted, as cleanup removes every version.
+func (s *transcodeService) scheduleSupersededDeletion(ctx context.Context, video *model.Video) {
+	if s.superseded == nil || s.supersededGrace <= 0 {
+		return
+	}
+	prefixes := supersededPrefixes(video)
+	if len(prefixes) == 0 {
+		return
 	}
 
-	// Invalidate cache to ensure fresh data on next read
-	s.invalidateCache(ctx, videoID)
+	now := time.Now()
+	err := s.superseded.Schedule(ctx, &repository.SupersededOutput{
+		VideoID:        video.ID,
+		OutputVersion:  video.OutputVersion,
+		UserID:         video.UserID,
+		Prefixes:       prefixes,
+		ExternalOutput: video.ExternalOutput,
+		DeleteAfter:    now.Add(s.supersededGrace),
+		CreatedAt:      now,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to schedule deletion of superseded output",
+			"video_id", video.ID,
+			"output_version", video.OutputVersion,
+			"error", err,
+		)
+	}
+}
 
-	return nil
+// recordOutputSize stores the size of the published output for storage accounting.
+// Output in the owner's own bucket does not take up our storage and is recorded as 0.
+// Failures are logged and not propagated, since the output is already published.
+func (s *transcodeService) recordOutputSize(ctx context.Context, videoID uuid.UUID, size int64) {
+	if s.externalOutput {
+		size = 0
+	}
+	if err := s.repo.SetOutputSize(ctx, videoID, size); err != nil {
+		slog.WarnContext(ctx, "failed to record output size",
+			"video_id", videoID,
+			"error", err,
+		)
+	}
 }
 
-// markVideoFailed updates the video status to FAILED.
-func (s *transcodeService) markVideoFailed(ctx context.Context, videoID uuid.UUID) error {
+// markVideoFailed updates the video status to FAILED with the failure code and reason,
+// which is also recorded in the status log.
+func (s *transcodeService) markVideoFailed(ctx context.Context, videoID uuid.UUID, code model.FailureCode, reason string) error {
 	video, err := s.repo.GetByID(ctx, videoID)
 	if err != nil {
 		return fmt.Errorf("get video: %w", err)
@@ -264,31 +1195,150 @@ func (s *transcodeService) markVideoFailed(ctx context.Context, videoID uuid.UUI
 		return nil
 	}
 
-	if err := video.TransitionTo(model.StatusFailed); err != nil {
+	if err := video.MarkFailed(code, reason); err != nil {
 		return fmt.Errorf("transition to failed: %w", err)
 	}
 
 	if err := s.repo.Update(ctx, video); err != nil {
 		return fmt.Errorf("update video: %w", err)
 	}
+	recordStatusEvent(ctx, s.statusEvents, video, model.StatusProcessing, model.StatusActorWorker, video.FailureReason)
 
 	// Invalidate cache to ensure fresh data on next read
-	s.invalidateCache(ctx, videoID)
+	s.invalidateCache(ctx, video)
+	publishStatus(ctx, s.events, video)
 
 	return nil
 }
 
-// invalidateCache removes a video from cache.
-// Errors are logged but not propagated - cache invalidation is non-critical.
-func (s *transcodeService) invalidateCache(ctx context.Context, videoID uuid.UUID) {
+// failureCodeOf categorizes the error of a failed transcode for the video's failure code.
+func failureCodeOf(err error) model.FailureCode {
+	var execErr *transcoder.ExecError
+	switch {
+	case errors.Is(err, ErrTaskTimeout):
+		return model.FailureCodeTimedOut
+	case errors.As(err, &execErr):
+		return model.FailureCodeEncodeFailed
+	case errors.Is(err, errs.Invalid):
+		return model.FailureCodeInvalidInput
+	default:
+		return model.FailureCodeInternal
+	}
+}
+
+// recordSLI emits the transcode SLI series for jobs that decided their task's outcome.
+func recordSLI(job *model.TranscodeJob) {
+	if !job.Final {
+		return
+	}
+
+	result := metrics.SLIGood
+	if job.Status != model.JobStatusSucceeded {
+		result = metrics.SLIBad
+	}
+	metrics.SLITranscodesTotal.WithLabelValues(result).Inc()
+
+	if d, ok := job.TimeToReady(); ok {
+		metrics.SLITimeToReadySeconds.Observe(d.Seconds())
+	}
+}
+
+// supersededPrefixes returns the storage prefixes of the video's currently published output.
+func supersededPrefixes(video *model.Video) []string {
+	var prefixes []string
+	for _, key := range []string{video.HLSURL, video.DashURL, video.PreviewURL} {
+		if key == "" {
+			continue
+		}
+		// HLS and DASH manifests share the output version's prefix
+		if prefix := path.Dir(key) + "/"; !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// purgeCDN evicts the given prefixes from the CDN.
+// Errors are logged but not propagated - the objects still expire with their cache TTL.
+func (s *transcodeService) purgeCDN(ctx context.Context, videoID uuid.UUID, prefixes []string) {
+	if s.purger == nil || len(prefixes) == 0 {
+		return
+	}
+
+	if err := s.purger.Purge(ctx, prefixes); err != nil {
+		slog.WarnContext(ctx, "failed to purge CDN",
+			"video_id", videoID,
+			"prefixes", prefixes,
+			"error", err,
+		)
+	}
+}
+
+// refreshCache writes the video as stored now into cache when write-through is enabled,
+// and otherwise evicts it like invalidateCache. The video is read back rather than cached
+// as updated here, since a regenerated output is published by a statement that leaves the
+// in-memory copy behind. A video that cannot be read back is evicted instead. The owner's
+// cached first pages are always evicted, as they are cached by the API per page size.
+func (s *transcodeService) refreshCache(ctx context.Context, video *model.Video) {
 	if s.cache == nil {
 		return
 	}
+	if s.cacheTTL <= 0 || !s.writeThrough(ctx, video.ID) {
+		s.invalidateCache(ctx, video)
+		return
+	}
+	s.invalidateFirstPages(ctx, video)
+}
 
-	if err := s.cache.Delete(ctx, videoID); err != nil {
-		slog.Warn("failed to invalidate video cache",
+// writeThrough stores the current video in cache, reporting whether it was written.
+// The read is timed like the API's, so the entry is refreshed early at the same pace.
+func (s *transcodeService) writeThrough(ctx context.Context, videoID uuid.UUID) bool {
+	start := time.Now()
+	current, err := s.repo.GetByID(ctx, videoID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read video for cache write-through",
 			"video_id", videoID,
 			"error", err,
 		)
+		return false
+	}
+	if current.IsDeleted() {
+		// Deleted meanwhile; the API reports it as not found
+		return false
+	}
+	if err := s.cache.Set(ctx, current, s.cacheTTL, s.cacheStaleTTL, time.Since(start)); err != nil {
+		slog.WarnContext(ctx, "failed to write video through to cache",
+			"video_id", videoID,
+			"error", err,
+		)
+		return false
+	}
+	return true
+}
+
+// invalidateCache removes a video and its owner's cached first pages from cache.
+// Errors are logged but not propagated - cache invalidation is non-critical.
+func (s *transcodeService) invalidateCache(ctx context.Context, video *model.Video) {
+	if s.cache == nil {
+		return
+	}
+
+	if err := s.cache.Delete(ctx, video.ID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate video cache",
+			"video_id", video.ID,
+			"error", err,
+		)
+	}
+	s.invalidateFirstPages(ctx, video)
+}
+
+// invalidateFirstPages removes the cached first pages of the video owner's list.
+func (s *transcodeService) invalidateFirstPages(ctx context.Context, video *model.Video) {
+	if err := s.cache.DeleteFirstPages(ctx, video.UserID); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate video list cache",
+			"video_id", video.ID,
+			"user_id", video.UserID,
+			"error", err,
+		)
 	}
 }
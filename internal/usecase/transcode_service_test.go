@@ -3,16 +3,24 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
 	"github.com/hszk-dev/gostream/internal/transcoder"
 )
 
@@ -42,6 +50,7 @@ func TestTranscodeService_ProcessTask_Success(t *testing.T) {
 
 	// Track uploaded files
 	uploadedFiles := make(map[string][]byte)
+	outputSize := int64(-1)
 
 	video := &model.Video{
 		ID:          videoID,
@@ -64,6 +73,10 @@ func TestTranscodeService_ProcessTask_Success(t *testing.T) {
 			video = v
 			return nil
 		},
+		setOutputSizeFn: func(ctx context.Context, id uuid.UUID, size int64) error {
+			outputSize = size
+			return nil
+		},
 	}
 
 	storage := &mockObjectStorage{
@@ -78,7 +91,7 @@ func TestTranscodeService_ProcessTask_Success(t *testing.T) {
 	}
 
 	tc := &mockTranscoder{
-		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant) (*transcoder.ABROutput, error) {
+		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
 			// Create mock output files for ABR
 			masterPath := filepath.Join(outputDir, "master.m3u8")
 			mustWriteFile(t, masterPath, []byte("#EXTM3U\n#EXT-X-VERSION:3\n"))
@@ -114,13 +127,14 @@ func TestTranscodeService_ProcessTask_Success(t *testing.T) {
 		TempDir:    tempDir,
 		MaxRetries: 3,
 	}
-	svc := NewTranscodeService(repo, storage, tc, nil, cfg)
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
 
 	task := repository.TranscodeTask{
-		VideoID:     videoID,
-		OriginalKey: "originals/" + videoID.String() + "/video.mp4",
-		OutputKey:   "hls/" + videoID.String() + "/",
-		RetryCount:  0,
+		VideoID:       videoID,
+		OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+		OutputKey:     "hls/" + videoID.String() + "/",
+		RetryCount:    0,
+		OutputVersion: 1,
 	}
 
 	err := svc.ProcessTask(ctx, task)
@@ -133,6 +147,11 @@ func TestTranscodeService_ProcessTask_Success(t *testing.T) {
 		t.Errorf("video status: got %s, expected %s", video.Status, model.StatusReady)
 	}
 
+	// Verify the output pointer records the version
+	if video.OutputVersion != 1 {
+		t.Errorf("output version: got %d, expected 1", video.OutputVersion)
+	}
+
 	// Verify HLS URL is set (should point to master.m3u8)
 	if video.HLSURL == "" {
 		t.Error("HLS URL should be set")
@@ -154,6 +173,1053 @@ func TestTranscodeService_ProcessTask_Success(t *testing.T) {
 	if _, ok := uploadedFiles["hls/"+videoID.String()+"/720p/segment_000.ts"]; !ok {
 		t.Error("720p segment should be uploaded")
 	}
+
+	// Verify the output size records the uploaded variants
+	var wantSize int64
+	for key, data := range uploadedFiles {
+		if base := path.Base(key); base != "master.m3u8" && base != OutputChecksumsFile {
+			wantSize += int64(len(data))
+		}
+	}
+	if outputSize != wantSize {
+		t.Errorf("output size: got %d, expected %d", outputSize, wantSize)
+	}
+}
+
+// singleVariantABR returns a transcodeToABRFn that writes a minimal one-variant output.
+func singleVariantABR(t *testing.T) func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+	return func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+		masterPath := filepath.Join(outputDir, "master.m3u8")
+		mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
+		return &transcoder.ABROutput{MasterManifestPath: masterPath}, nil
+	}
+}
+
+func TestTranscodeService_ProcessTask_Preview(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name           string
+		previewErr     error
+		wantErr        bool
+		wantPreviewURL string
+	}{
+		{
+			name:           "preview generated and uploaded",
+			wantPreviewURL: "previews/" + videoID.String() + "/playlist.m3u8",
+		},
+		{
+			name:       "preview failure fails the task for retry",
+			previewErr: errors.New("ffmpeg crashed"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: videoID, Status: model.StatusProcessing, PreviewSeconds: 45}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+
+			uploaded := make(map[string]bool)
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					uploaded[key] = true
+					return nil
+				},
+			}
+
+			tc := &mockTranscoder{
+				transcodeToABRFn: singleVariantABR(t),
+				transcodePreviewFn: func(ctx context.Context, inputPath, outputDir string, variant transcoder.Variant, duration time.Duration) (*transcoder.HLSOutput, error) {
+					if tt.previewErr != nil {
+						return nil, tt.previewErr
+					}
+					if duration != 45*time.Second {
+						t.Errorf("preview duration: got %v, expected 45s", duration)
+					}
+					manifestPath := filepath.Join(outputDir, "playlist.m3u8")
+					segmentPath := filepath.Join(outputDir, "segment_000.ts")
+					mustWriteFile(t, manifestPath, []byte("#EXTM3U\n"))
+					mustWriteFile(t, segmentPath, []byte("preview segment"))
+					return &transcoder.HLSOutput{ManifestPath: manifestPath, SegmentPaths: []string{segmentPath}}, nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:        videoID,
+				OriginalKey:    "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:      "hls/" + videoID.String() + "/",
+				PreviewKey:     "previews/" + videoID.String() + "/",
+				PreviewSeconds: 45,
+			})
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if video.Status != model.StatusProcessing {
+					t.Errorf("video status: got %s, expected %s", video.Status, model.StatusProcessing)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if video.PreviewURL != tt.wantPreviewURL {
+				t.Errorf("preview URL: got %q, expected %q", video.PreviewURL, tt.wantPreviewURL)
+			}
+			if !uploaded["previews/"+videoID.String()+"/segment_000.ts"] {
+				t.Error("preview segment should be uploaded")
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_Replication(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name       string
+		replicaErr error
+		wantErr    bool
+	}{
+		{
+			name: "output uploaded to both regions",
+		},
+		{
+			name:       "replica failure fails the task for retry",
+			replicaErr: errors.New("secondary region unreachable"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: videoID, Status: model.StatusProcessing}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+
+			primaryFiles := make(map[string]string)
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					data, _ := io.ReadAll(reader)
+					primaryFiles[key] = string(data)
+					return nil
+				},
+			}
+
+			replicaFiles := make(map[string]string)
+			replica := &mockObjectStorage{
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					if tt.replicaErr != nil {
+						return tt.replicaErr
+					}
+					data, _ := io.ReadAll(reader)
+					replicaFiles[key] = string(data)
+					return nil
+				},
+			}
+
+			tc := &mockTranscoder{transcodeToABRFn: singleVariantABR(t)}
+
+			svc := NewTranscodeService(repo, storage, replica, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+
+			replicateErrorsBefore := testutil.ToFloat64(metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpReplicate))
+
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:     videoID,
+				OriginalKey: "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:   "hls/" + videoID.String() + "/",
+			})
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if video.Status != model.StatusProcessing {
+					t.Errorf("video status: got %s, expected %s", video.Status, model.StatusProcessing)
+				}
+				replicateErrors := testutil.ToFloat64(metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpReplicate)) - replicateErrorsBefore
+				if replicateErrors != 1 {
+					t.Errorf("replicate error counter: got %v, expected 1", replicateErrors)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if video.Status != model.StatusReady {
+				t.Errorf("video status: got %s, expected %s", video.Status, model.StatusReady)
+			}
+			if len(replicaFiles) != len(primaryFiles) {
+				t.Fatalf("replica files: got %d, expected %d", len(replicaFiles), len(primaryFiles))
+			}
+			for key, data := range primaryFiles {
+				if replicaFiles[key] != data {
+					t.Errorf("replica content for %s: got %q, expected %q", key, replicaFiles[key], data)
+				}
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_OutputBucket(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		resolveErr error
+		wantErr    bool
+	}{
+		{
+			name: "output written to the tenant's bucket only",
+		},
+		{
+			name:       "unreachable bucket fails the task for retry",
+			resolveErr: errors.New("connect to output bucket: access denied"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: videoID, UserID: userID, Status: model.StatusProcessing}
+			outputSize := int64(-1)
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+				setOutputSizeFn: func(ctx context.Context, id uuid.UUID, size int64) error {
+					outputSize = size
+					return nil
+				},
+			}
+
+			var platformUploads []string
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					platformUploads = append(platformUploads, key)
+					return nil
+				},
+			}
+			var replicaUploads []string
+			replica := &mockObjectStorage{
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					replicaUploads = append(replicaUploads, key)
+					return nil
+				},
+			}
+			var bucketUploads []string
+			bucket := &mockObjectStorage{
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					bucketUploads = append(bucketUploads, key)
+					return nil
+				},
+			}
+			var tenant uuid.UUID
+			outputs := &mockOutputBucketResolver{
+				outputStorageFn: func(ctx context.Context, tenantID uuid.UUID) (repository.ObjectStorage, error) {
+					tenant = tenantID
+					if tt.resolveErr != nil {
+						return nil, tt.resolveErr
+					}
+					return bucket, nil
+				},
+			}
+
+			tc := &mockTranscoder{transcodeToABRFn: singleVariantABR(t)}
+			svc := NewTranscodeService(repo, storage, replica, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, outputs, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:     videoID,
+				OriginalKey: "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:   "hls/" + videoID.String() + "/",
+			})
+
+			if tenant != userID {
+				t.Errorf("resolved bucket of %s, want owner %s", tenant, userID)
+			}
+			if len(platformUploads) != 0 || len(replicaUploads) != 0 {
+				t.Errorf("uploaded to platform storage %v and replica %v", platformUploads, replicaUploads)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if video.Status != model.StatusProcessing || len(bucketUploads) != 0 {
+					t.Errorf("video status %s with %d uploads after failed resolve", video.Status, len(bucketUploads))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Contains(bucketUploads, "hls/"+videoID.String()+"/master.m3u8") {
+				t.Errorf("bucket uploads %v lack the master playlist", bucketUploads)
+			}
+			if video.Status != model.StatusReady || !video.ExternalOutput {
+				t.Errorf("video status %s, external output %v; want READY in the tenant's bucket", video.Status, video.ExternalOutput)
+			}
+			if outputSize != 0 {
+				t.Errorf("output size %d, want 0 for output outside our storage", outputSize)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_Regeneration(t *testing.T) {
+	videoID := uuid.New()
+	const version int64 = 1700000000000
+	outputKey := fmt.Sprintf("hls/%s/v%d/", videoID, version)
+
+	tests := []struct {
+		name           string
+		publishErr     error
+		purgeErr       error
+		scheduleErr    error
+		wantErr        bool
+		wantInvalidate bool
+		wantPurge      bool
+	}{
+		{
+			name:           "new version swapped in",
+			wantInvalidate: true,
+			wantPurge:      true,
+		},
+		{
+			name:           "purge failure does not fail the task",
+			purgeErr:       errors.New("cdn unavailable"),
+			wantInvalidate: true,
+			wantPurge:      true,
+		},
+		{
+			name:           "scheduling failure does not fail the task",
+			scheduleErr:    errors.New("database error"),
+			wantInvalidate: true,
+			wantPurge:      true,
+		},
+		{
+			name:       "stale version is dropped",
+			publishErr: repository.ErrStaleOutputVersion,
+		},
+		{
+			name:       "publish failure fails the task for retry",
+			publishErr: errors.New("database error"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{
+						ID:            videoID,
+						Status:        model.StatusReady,
+						HLSURL:        "hls/" + videoID.String() + "/v1/master.m3u8",
+						OutputVersion: 1,
+					}, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					t.Error("regeneration must swap the output pointer, not rewrite the video")
+					return nil
+				},
+				publishOutputFn: func(ctx context.Context, id uuid.UUID, v int64, hlsURL, dashURL, previewURL, thumbnailPrefix string, externalOutput bool) error {
+					if v != version {
+						t.Errorf("published version: got %d, expected %d", v, version)
+					}
+					if hlsURL != outputKey+"master.m3u8" {
+						t.Errorf("published HLS URL: got %q, expected %q", hlsURL, outputKey+"master.m3u8")
+					}
+					return tt.publishErr
+				},
+			}
+
+			var uploadedKeys []string
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					uploadedKeys = append(uploadedKeys, key)
+					return nil
+				},
+			}
+
+			invalidated := false
+			videoCache := newMockVideoCache()
+			videoCache.deleteFn = func(ctx context.Context, id uuid.UUID) error {
+				invalidated = true
+				return nil
+			}
+
+			var purged []string
+			purger := &mockCDNPurger{
+				purgeFn: func(ctx context.Context, prefixes []string) error {
+					purged = prefixes
+					return tt.purgeErr
+				},
+			}
+
+			var scheduled *repository.SupersededOutput
+			superseded := &mockSupersededOutputRepository{
+				scheduleFn: func(ctx context.Context, output *repository.SupersededOutput) error {
+					scheduled = output
+					return tt.scheduleErr
+				},
+			}
+
+			tc := &mockTranscoder{transcodeToABRFn: singleVariantABR(t)}
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, videoCache, nil, nil, nil, purger, nil, nil, nil, nil, nil, nil, nil, superseded, TranscodeServiceConfig{
+				TempDir:               t.TempDir(),
+				MaxRetries:            3,
+				SupersededOutputGrace: time.Hour,
+			})
+
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     outputKey,
+				OutputVersion: version,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if invalidated != tt.wantInvalidate {
+				t.Errorf("cache invalidated: got %v, expected %v", invalidated, tt.wantInvalidate)
+			}
+			if tt.wantPurge {
+				want := "hls/" + videoID.String() + "/v1/"
+				if len(purged) != 1 || purged[0] != want {
+					t.Errorf("purged prefixes: got %v, expected [%s]", purged, want)
+				}
+			} else if purged != nil {
+				t.Errorf("unexpected purge: %v", purged)
+			}
+			if tt.wantPurge {
+				want := "hls/" + videoID.String() + "/v1/"
+				if scheduled == nil || scheduled.OutputVersion != 1 || len(scheduled.Prefixes) != 1 || scheduled.Prefixes[0] != want {
+					t.Errorf("scheduled deletion: got %+v, expected version 1 at [%s]", scheduled, want)
+				} else if d := scheduled.DeleteAfter.Sub(scheduled.CreatedAt); d != time.Hour {
+					t.Errorf("scheduled deletion after %v, expected %v", d, time.Hour)
+				}
+			} else if scheduled != nil {
+				t.Errorf("unexpected scheduled deletion: %+v", scheduled)
+			}
+			for _, key := range uploadedKeys {
+				if !strings.HasPrefix(key, outputKey) {
+					t.Errorf("uploaded %q outside the new version prefix %q", key, outputKey)
+				}
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_CacheWriteThrough(t *testing.T) {
+	videoID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		setErr      error
+		wantCached  bool
+		wantDeleted bool
+	}{
+		{
+			name:        "evicted without write-through",
+			wantDeleted: true,
+		},
+		{
+			name:       "READY video written through",
+			ttl:        10 * time.Minute,
+			wantCached: true,
+		},
+		{
+			name:        "failed write evicts instead",
+			ttl:         10 * time.Minute,
+			setErr:      errors.New("redis down"),
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: videoID, UserID: userID, Status: model.StatusProcessing}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					// A copy, like a fresh read from the database
+					v := *video
+					return &v, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+			}
+
+			videoCache := newMockVideoCache()
+			videoCache.pages[userID] = map[int]*cache.VideoPage{20: {}}
+			var cached *model.Video
+			videoCache.setFn = func(ctx context.Context, v *model.Video, ttl, staleTTL, loadTime time.Duration) error {
+				if ttl != tt.ttl || staleTTL != time.Hour {
+					t.Errorf("cached for %v (stale %v), expected %v (stale 1h)", ttl, staleTTL, tt.ttl)
+				}
+				if tt.setErr != nil {
+					return tt.setErr
+				}
+				cached = v
+				return nil
+			}
+			deleted := false
+			videoCache.deleteFn = func(ctx context.Context, id uuid.UUID) error {
+				deleted = true
+				return nil
+			}
+
+			tc := &mockTranscoder{transcodeToABRFn: singleVariantABR(t)}
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, videoCache, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{
+				TempDir:              t.TempDir(),
+				MaxRetries:           3,
+				CacheWriteThroughTTL: tt.ttl,
+				CacheStaleTTL:        time.Hour,
+			})
+
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:     videoID,
+				OriginalKey: "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:   "hls/" + videoID.String() + "/",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if (cached != nil) != tt.wantCached {
+				t.Fatalf("cached: got %+v, expected cached %v", cached, tt.wantCached)
+			}
+			if cached != nil && (cached.Status != model.StatusReady || cached.HLSURL != "hls/"+videoID.String()+"/master.m3u8") {
+				t.Errorf("cached video: status %s, HLS URL %q; want the READY video", cached.Status, cached.HLSURL)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("evicted: got %v, expected %v", deleted, tt.wantDeleted)
+			}
+			if _, ok := videoCache.pages[userID]; ok {
+				t.Error("owner's first pages must be evicted")
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_JobRecord(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name         string
+		retryCount   int
+		uploadErr    error
+		wantStatus   model.TranscodeJobStatus
+		wantFinal    bool
+		wantUpload   bool
+		wantFinalize bool
+	}{
+		{
+			name:         "successful attempt records every stage",
+			wantStatus:   model.JobStatusSucceeded,
+			wantFinal:    true,
+			wantUpload:   true,
+			wantFinalize: true,
+		},
+		{
+			name:       "failed attempt stops at the failing stage",
+			uploadErr:  errors.New("upload failed"),
+			wantStatus: model.JobStatusFailed,
+			wantUpload: true,
+		},
+		{
+			name:       "failure on the last attempt is final",
+			retryCount: 2,
+			uploadErr:  errors.New("upload failed"),
+			wantStatus: model.JobStatusFailed,
+			wantFinal:  true,
+			wantUpload: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: videoID, Status: model.StatusProcessing}, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					time.Sleep(time.Millisecond)
+					return nil
+				},
+			}
+
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					time.Sleep(time.Millisecond)
+					return tt.uploadErr
+				},
+			}
+
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					masterPath := filepath.Join(outputDir, "master.m3u8")
+					mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
+					variantPath := filepath.Join(outputDir, "playlist.m3u8")
+					mustWriteFile(t, variantPath, []byte("#EXTM3U\n"))
+					time.Sleep(2 * time.Millisecond)
+					return &transcoder.ABROutput{
+						MasterManifestPath: masterPath,
+						Variants: []transcoder.VariantOutput{
+							{Variant: transcoder.Variant{Name: "720p"}, ManifestPath: variantPath, Duration: time.Millisecond},
+						},
+						ProbeDuration: time.Millisecond,
+						Source:        &transcoder.ProbeResult{Duration: 90 * time.Second, Height: 1080},
+					}, nil
+				},
+			}
+
+			estimate := &model.TranscodeEstimate{ProcessingTime: 45 * time.Second, OutputBytes: 4096, Samples: 12}
+			estimator := &mockTranscodeEstimator{
+				estimateFn: func(ctx context.Context, source transcoder.ProbeResult) (*model.TranscodeEstimate, error) {
+					if source.Duration != 90*time.Second || source.Height != 1080 {
+						t.Errorf("estimated source: got %+v", source)
+					}
+					return estimate, nil
+				},
+			}
+
+			var recorded *model.TranscodeJob
+			jobs := &mockTranscodeJobRepository{
+				createFn: func(ctx context.Context, job *model.TranscodeJob) error {
+					recorded = job
+					return nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, jobs, nil, nil, nil, estimator, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+			enqueuedAt := time.Now().Add(-time.Minute)
+			_ = svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     "hls/" + videoID.String() + "/v1/",
+				OutputVersion: 1,
+				RetryCount:    tt.retryCount,
+				EnqueuedAt:    enqueuedAt,
+			})
+
+			if recorded == nil {
+				t.Fatal("expected a job to be recorded")
+			}
+			if recorded.Status != tt.wantStatus {
+				t.Errorf("status: got %s, expected %s", recorded.Status, tt.wantStatus)
+			}
+			if recorded.Attempt != tt.retryCount || recorded.OutputVersion != 1 {
+				t.Errorf("attempt/version: got %d/%d, expected %d/1", recorded.Attempt, recorded.OutputVersion, tt.retryCount)
+			}
+			if recorded.Final != tt.wantFinal {
+				t.Errorf("final: got %v, expected %v", recorded.Final, tt.wantFinal)
+			}
+			if !recorded.QueuedAt.Equal(enqueuedAt) {
+				t.Errorf("queued at: got %v, expected %v", recorded.QueuedAt, enqueuedAt)
+			}
+			if (tt.uploadErr != nil) != (recorded.Error != "") {
+				t.Errorf("error: got %q", recorded.Error)
+			}
+
+			timings := recorded.Timings
+			if timings.Probe != time.Millisecond {
+				t.Errorf("probe: got %v, expected 1ms", timings.Probe)
+			}
+			if timings.Transcode <= 0 {
+				t.Errorf("transcode: got %v, expected > 0", timings.Transcode)
+			}
+			if timings.Variants["720p"] != time.Millisecond {
+				t.Errorf("variant timings: got %v", timings.Variants)
+			}
+			if (timings.Upload > 0) != tt.wantUpload {
+				t.Errorf("upload: got %v", timings.Upload)
+			}
+			if (timings.Finalize > 0) != tt.wantFinalize {
+				t.Errorf("finalize: got %v", timings.Finalize)
+			}
+
+			if recorded.SourceDuration != 90*time.Second || recorded.SourceHeight != 1080 {
+				t.Errorf("source: got %v/%d, expected 1m30s/1080", recorded.SourceDuration, recorded.SourceHeight)
+			}
+			// Only the variant playlist ("#EXTM3U\n") counts towards the ABR output
+			var wantBytes int64
+			if tt.uploadErr == nil {
+				wantBytes = 8
+			}
+			if recorded.OutputBytes != wantBytes {
+				t.Errorf("output bytes: got %d, expected %d", recorded.OutputBytes, wantBytes)
+			}
+			if recorded.Estimate != estimate {
+				t.Errorf("estimate: got %+v, expected %+v", recorded.Estimate, estimate)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_Renditions(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name      string
+		saveErr   error
+		wantErr   bool
+		wantReady bool
+	}{
+		{name: "renditions saved before publishing", wantReady: true},
+		{name: "save failure keeps the output unpublished", saveErr: errors.New("db down"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ready bool
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: videoID, Status: model.StatusProcessing}, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					ready = v.Status == model.StatusReady
+					return nil
+				},
+			}
+
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+			}
+
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					masterPath := filepath.Join(outputDir, "master.m3u8")
+					mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
+					playlistPath := filepath.Join(outputDir, "playlist.m3u8")
+					mustWriteFile(t, playlistPath, []byte("#EXTM3U\n"))
+					segments := []string{filepath.Join(outputDir, "segment_000.ts"), filepath.Join(outputDir, "segment_001.ts")}
+					for _, segment := range segments {
+						mustWriteFile(t, segment, make([]byte, 100))
+					}
+					return &transcoder.ABROutput{
+						MasterManifestPath: masterPath,
+						Variants: []transcoder.VariantOutput{
+							{
+								Variant:      transcoder.Variant{Name: "720p", Height: 720, Bitrate: 2500000},
+								ManifestPath: playlistPath,
+								SegmentPaths: segments,
+								Codec:        "libx264",
+							},
+						},
+					}, nil
+				},
+			}
+
+			var saved []*model.Rendition
+			renditions := &mockRenditionRepository{
+				saveAllFn: func(ctx context.Context, r []*model.Rendition) error {
+					if ready {
+						t.Error("renditions must be saved before the video becomes READY")
+					}
+					saved = r
+					return tt.saveErr
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, renditions, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     "hls/" + videoID.String() + "/v5/",
+				OutputVersion: 5,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ready != tt.wantReady {
+				t.Errorf("ready: got %v, expected %v", ready, tt.wantReady)
+			}
+
+			if len(saved) != 1 {
+				t.Fatalf("expected 1 rendition, got %d", len(saved))
+			}
+			got := saved[0]
+			if got.VideoID != videoID || got.OutputVersion != 5 || got.Name != "720p" {
+				t.Errorf("unexpected identity: %+v", got)
+			}
+			if got.Width != 1280 || got.Height != 720 || got.Bitrate != 2500000 || got.Codec != "libx264" {
+				t.Errorf("unexpected encoding: %+v", got)
+			}
+			// Two 100-byte segments plus the 8-byte variant playlist
+			if got.SegmentCount != 2 || got.Bytes != 208 {
+				t.Errorf("segments/bytes: got %d/%d, expected 2/208", got.SegmentCount, got.Bytes)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_OutputFormats(t *testing.T) {
+	videoID := uuid.New()
+	prefix := "hls/" + videoID.String() + "/v2/"
+
+	tests := []struct {
+		name          string
+		formats       transcoder.OutputFormats
+		wantHLSURL    string
+		wantDashURL   string
+		wantRendition string // codec of the recorded rendition shows which output it came from
+	}{
+		{name: "hls only", formats: transcoder.OutputFormatsHLS, wantHLSURL: prefix + "master.m3u8", wantRendition: "hls"},
+		{name: "dash only", formats: transcoder.OutputFormatsDASH, wantDashURL: prefix + "manifest.mpd", wantRendition: "dash"},
+		{name: "both", formats: transcoder.OutputFormatsBoth, wantHLSURL: prefix + "master.m3u8", wantDashURL: prefix + "manifest.mpd", wantRendition: "hls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var video *model.Video
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: videoID, Status: model.StatusProcessing}, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+
+			uploaded := make(map[string]string)
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					uploaded[key] = contentType
+					return nil
+				},
+			}
+
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					masterPath := filepath.Join(outputDir, "master.m3u8")
+					mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
+					playlistPath := filepath.Join(outputDir, "playlist.m3u8")
+					mustWriteFile(t, playlistPath, []byte("#EXTM3U\n"))
+					return &transcoder.ABROutput{
+						MasterManifestPath: masterPath,
+						Variants: []transcoder.VariantOutput{
+							{Variant: variants[0], ManifestPath: playlistPath, Codec: "hls"},
+						},
+					}, nil
+				},
+				transcodeToDASHFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.DASHOutput, error) {
+					manifestPath := filepath.Join(outputDir, transcoder.DASHManifestName)
+					mustWriteFile(t, manifestPath, []byte("<MPD/>"))
+					segment := filepath.Join(outputDir, "chunk-0-00001.m4s")
+					audio := filepath.Join(outputDir, "chunk-1-00001.m4s")
+					mustWriteFile(t, segment, make([]byte, 100))
+					mustWriteFile(t, audio, make([]byte, 10))
+					return &transcoder.DASHOutput{
+						ManifestPath: manifestPath,
+						SegmentPaths: []string{segment, audio},
+						Variants: []transcoder.VariantOutput{
+							{Variant: variants[0], SegmentPaths: []string{segment}, Codec: "dash"},
+						},
+					}, nil
+				},
+			}
+
+			var saved []*model.Rendition
+			renditions := &mockRenditionRepository{
+				saveAllFn: func(ctx context.Context, r []*model.Rendition) error {
+					saved = r
+					return nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, renditions, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{
+				TempDir:       t.TempDir(),
+				MaxRetries:    3,
+				OutputFormats: tt.formats,
+			})
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     prefix,
+				OutputVersion: 2,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if video == nil || video.Status != model.StatusReady {
+				t.Fatalf("video should be READY, got %+v", video)
+			}
+			if video.HLSURL != tt.wantHLSURL || video.DashURL != tt.wantDashURL {
+				t.Errorf("urls: got %q/%q, expected %q/%q", video.HLSURL, video.DashURL, tt.wantHLSURL, tt.wantDashURL)
+			}
+			if tt.wantDashURL != "" {
+				if got := uploaded[tt.wantDashURL]; got != "application/dash+xml" {
+					t.Errorf("manifest content type: got %q", got)
+				}
+				if _, ok := uploaded[prefix+"chunk-1-00001.m4s"]; !ok {
+					t.Error("audio segment should be uploaded")
+				}
+			}
+			// The checksum manifest sits next to whichever manifest was produced
+			if _, ok := uploaded[prefix+OutputChecksumsFile]; !ok {
+				t.Error("checksum manifest should be uploaded")
+			}
+
+			if len(saved) != 1 || saved[0].Codec != tt.wantRendition {
+				t.Fatalf("renditions: got %+v, expected one from %s", saved, tt.wantRendition)
+			}
+			if tt.wantDashURL != "" && saved[0].Bytes < 100 {
+				t.Errorf("rendition bytes should include the DASH segment, got %d", saved[0].Bytes)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_Thumbnails(t *testing.T) {
+	videoID := uuid.New()
+	prefix := "hls/" + videoID.String() + "/v3/"
+
+	tests := []struct {
+		name       string
+		genErr     error
+		wantPrefix string
+	}{
+		{name: "thumbnails uploaded and published", wantPrefix: prefix + "thumbnails/"},
+		{name: "generation failure publishes without thumbnails", genErr: errors.New("no frame")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var video *model.Video
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: videoID, Status: model.StatusProcessing}, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+
+			uploaded := make(map[string]string)
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					uploaded[key] = contentType
+					return nil
+				},
+			}
+
+			var gotAt time.Duration
+			tc := &mockTranscoder{
+				transcodeToABRFn: singleVariantABR(t),
+				thumbnailsFn: func(ctx context.Context, inputPath, outputDir string, at time.Duration, sizes []transcoder.ThumbnailSize) ([]transcoder.ThumbnailOutput, error) {
+					gotAt = at
+					if tt.genErr != nil {
+						return nil, tt.genErr
+					}
+					var outputs []transcoder.ThumbnailOutput
+					for _, size := range sizes {
+						path := filepath.Join(outputDir, size.Name+".jpg")
+						mustWriteFile(t, path, []byte("jpeg"))
+						outputs = append(outputs, transcoder.ThumbnailOutput{Size: size, Path: path})
+					}
+					return outputs, nil
+				},
+			}
+			prober := &mockProber{
+				probeFn: func(ctx context.Context, input string) (*transcoder.ProbeResult, error) {
+					return &transcoder.ProbeResult{Duration: 100 * time.Second, Height: 1080}, nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, prober, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     prefix,
+				OutputVersion: 3,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotAt != 10*time.Second {
+				t.Errorf("thumbnail offset: got %v, expected a tenth of the source", gotAt)
+			}
+			if video == nil || video.Status != model.StatusReady {
+				t.Fatalf("video should be READY, got %+v", video)
+			}
+			if video.ThumbnailPrefix != tt.wantPrefix {
+				t.Errorf("thumbnail prefix: got %q, expected %q", video.ThumbnailPrefix, tt.wantPrefix)
+			}
+			if tt.wantPrefix == "" {
+				return
+			}
+			// Every size the model exposes must have been uploaded
+			for _, size := range model.ThumbnailSizes {
+				if got := uploaded[video.ThumbnailKey(size)]; got != "image/jpeg" {
+					t.Errorf("%s thumbnail: got content type %q", size, got)
+				}
+			}
+		})
+	}
 }
 
 func TestTranscodeService_ProcessTask_MaxRetriesExceeded(t *testing.T) {
@@ -181,28 +1247,628 @@ func TestTranscodeService_ProcessTask_MaxRetriesExceeded(t *testing.T) {
 
 	storage := &mockObjectStorage{}
 	tc := &mockTranscoder{}
+	var recorded []*model.VideoStatusEvent
+	statusEvents := &mockVideoStatusEventRepository{
+		createFn: func(ctx context.Context, event *model.VideoStatusEvent) error {
+			recorded = append(recorded, event)
+			return nil
+		},
+	}
 
 	cfg := TranscodeServiceConfig{
 		TempDir:    t.TempDir(),
 		MaxRetries: 3,
 	}
-	svc := NewTranscodeService(repo, storage, tc, nil, cfg)
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, statusEvents, nil, cfg)
 
 	task := repository.TranscodeTask{
 		VideoID:    videoID,
 		RetryCount: 3, // Already at max retries
 	}
 
-	// Should return nil (ack the message) but mark video as FAILED
+	// Should mark video as FAILED and report a permanent failure (dead-letter the message)
 	err := svc.ProcessTask(ctx, task)
-	if err != nil {
-		t.Fatalf("expected nil error for max retries, got: %v", err)
+	if !errors.Is(err, repository.ErrPermanentTaskFailure) {
+		t.Fatalf("expected ErrPermanentTaskFailure for max retries, got: %v", err)
 	}
 
 	// Verify video status is FAILED
 	if video.Status != model.StatusFailed {
 		t.Errorf("video status: got %s, expected %s", video.Status, model.StatusFailed)
 	}
+
+	// The transition is recorded with the reason the worker gave up
+	if len(recorded) != 1 {
+		t.Fatalf("recorded %d status events, want 1", len(recorded))
+	}
+	if e := recorded[0]; e.From != model.StatusProcessing || e.To != model.StatusFailed || e.Actor != model.StatusActorWorker || e.Reason != "retries exhausted after 3 attempts" {
+		t.Errorf("unexpected status event: %+v", e)
+	}
+}
+
+func TestTranscodeService_ProcessTask_Expired(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      model.Status
+		expiresAt   time.Time
+		wantExpired bool
+		wantStatus  model.Status
+	}{
+		{
+			name:        "expired - video failed",
+			status:      model.StatusProcessing,
+			expiresAt:   time.Now().Add(-time.Minute),
+			wantExpired: true,
+			wantStatus:  model.StatusFailed,
+		},
+		{
+			name:        "expired - deleted video left alone",
+			status:      model.StatusDeleted,
+			expiresAt:   time.Now().Add(-time.Minute),
+			wantExpired: true,
+			wantStatus:  model.StatusDeleted,
+		},
+		{
+			name:       "not yet expired - transcoded",
+			status:     model.StatusProcessing,
+			expiresAt:  time.Now().Add(time.Hour),
+			wantStatus: model.StatusProcessing,
+		},
+		{
+			name:       "no expiry - transcoded",
+			status:     model.StatusProcessing,
+			wantStatus: model.StatusProcessing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:     uuid.New(),
+				UserID: uuid.New(),
+				Title:  "Test Video",
+				Status: tt.status,
+			}
+
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+			// The download fails so that a task that is not expired stops right after it starts
+			downloaded := false
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					downloaded = true
+					return nil, errors.New("storage unavailable")
+				},
+			}
+			var jobs []*model.TranscodeJob
+			jobRepo := &mockTranscodeJobRepository{
+				createFn: func(ctx context.Context, job *model.TranscodeJob) error {
+					jobs = append(jobs, job)
+					return nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, &mockTranscoder{}, nil, nil, nil, nil, nil, nil, jobRepo, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+			task := repository.TranscodeTask{
+				VideoID:     video.ID,
+				OriginalKey: "originals/video.mp4",
+				OutputKey:   "hls/" + video.ID.String() + "/v1",
+				ExpiresAt:   tt.expiresAt,
+			}
+
+			err := svc.ProcessTask(context.Background(), task)
+			if tt.wantExpired && err != nil {
+				t.Fatalf("expired task must be acked, got: %v", err)
+			}
+
+			if video.Status != tt.wantStatus {
+				t.Errorf("video status: got %s, expected %s", video.Status, tt.wantStatus)
+			}
+			if downloaded == tt.wantExpired {
+				t.Errorf("downloaded: got %v, expected %v", downloaded, !tt.wantExpired)
+			}
+			expired := len(jobs) == 1 && jobs[0].Error == ErrTaskExpired.Error() && jobs[0].Final
+			if expired != tt.wantExpired {
+				t.Errorf("expected a final %q job: %v, got %+v", ErrTaskExpired, tt.wantExpired, jobs)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_ProbesSource(t *testing.T) {
+	probed := &transcoder.ProbeResult{
+		Duration:  90 * time.Second,
+		Width:     1280,
+		Height:    720,
+		Codec:     "h264",
+		Bitrate:   3000000,
+		FrameRate: 30,
+		HasAudio:  true,
+	}
+
+	tests := []struct {
+		name          string
+		probeErr      error
+		updateErr     error
+		wantErr       bool
+		wantPermanent bool
+		wantStatus    model.Status
+	}{
+		{
+			name:       "source recorded and ladder capped",
+			wantStatus: model.StatusReady,
+		},
+		{
+			name:          "no video stream fails permanently",
+			probeErr:      transcoder.ErrNoVideoStream,
+			wantErr:       true,
+			wantPermanent: true,
+			wantStatus:    model.StatusFailed,
+		},
+		{
+			name:       "probe error is retried",
+			probeErr:   errors.New("ffprobe execution failed"),
+			wantErr:    true,
+			wantStatus: model.StatusProcessing,
+		},
+		{
+			name:       "saving metadata fails",
+			updateErr:  errors.New("db down"),
+			wantErr:    true,
+			wantStatus: model.StatusProcessing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			video := &model.Video{ID: videoID, UserID: uuid.New(), Title: "Test Video", Status: model.StatusProcessing}
+
+			var saved *model.SourceMetadata
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+				updateSourceFn: func(ctx context.Context, id uuid.UUID, source model.SourceMetadata) error {
+					if id != videoID {
+						t.Errorf("update source: got video %s, expected %s", id, videoID)
+					}
+					saved = &source
+					return tt.updateErr
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+			}
+			prober := &mockProber{
+				probeFn: func(ctx context.Context, input string) (*transcoder.ProbeResult, error) {
+					if tt.probeErr != nil {
+						return nil, tt.probeErr
+					}
+					return probed, nil
+				},
+			}
+
+			var gotVariants []string
+			transcode := singleVariantABR(t)
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					for _, v := range variants {
+						gotVariants = append(gotVariants, v.Name)
+					}
+					return transcode(ctx, inputPath, outputDir, variants, progress)
+				},
+			}
+
+			var recorded *model.TranscodeJob
+			jobs := &mockTranscodeJobRepository{
+				createFn: func(ctx context.Context, job *model.TranscodeJob) error {
+					recorded = job
+					return nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, prober, nil, nil, nil, nil, nil, jobs, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     "hls/" + videoID.String() + "/v1/",
+				OutputVersion: 1,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (err != nil && !errs.Retryable(err)) != tt.wantPermanent {
+				t.Errorf("permanent: got %v, expected %v (err: %v)", !tt.wantPermanent, tt.wantPermanent, err)
+			}
+			if video.Status != tt.wantStatus {
+				t.Errorf("video status: got %s, expected %s", video.Status, tt.wantStatus)
+			}
+			if recorded == nil || recorded.Final != (tt.wantPermanent || !tt.wantErr) {
+				t.Errorf("expected the job to be recorded with final=%v, got %+v", tt.wantPermanent || !tt.wantErr, recorded)
+			}
+			if tt.wantErr {
+				if gotVariants != nil {
+					t.Errorf("expected no transcode, got variants %v", gotVariants)
+				}
+				return
+			}
+
+			want := model.SourceMetadata{Duration: 90 * time.Second, Width: 1280, Height: 720, Codec: "h264", Bitrate: 3000000, FrameRate: 30}
+			if saved == nil || *saved != want {
+				t.Errorf("saved source: got %+v, expected %+v", saved, want)
+			}
+			// The 1080p rung would upscale a 720p source
+			if strings.Join(gotVariants, ",") != "720p,360p" {
+				t.Errorf("variants: got %v, expected [720p 360p]", gotVariants)
+			}
+			if recorded.SourceDuration != 90*time.Second || recorded.SourceHeight != 720 {
+				t.Errorf("job source: got %v/%d, expected 1m30s/720", recorded.SourceDuration, recorded.SourceHeight)
+			}
+			if recorded.Timings.Probe <= 0 {
+				t.Errorf("probe timing: got %v, expected > 0", recorded.Timings.Probe)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_AudioOnly(t *testing.T) {
+	withVideo := &transcoder.ProbeResult{Duration: 90 * time.Second, Width: 1280, Height: 720, HasAudio: true}
+	audioOnly := &transcoder.ProbeResult{Duration: 90 * time.Second, HasAudio: true}
+
+	tests := []struct {
+		name             string
+		probed           *transcoder.ProbeResult
+		probeErr         error
+		audioOnlyBitrate int
+		audioOnlySources bool
+		wantErr          bool
+		wantHLS          string
+		wantDASH         string
+		wantThumbnails   bool
+	}{
+		{
+			name:             "audio rendition added to HLS only",
+			probed:           withVideo,
+			audioOnlyBitrate: 64000,
+			wantHLS:          "720p,360p,audio",
+			wantDASH:         "720p,360p",
+			wantThumbnails:   true,
+		},
+		{
+			name:             "no audio rendition for silent source",
+			probed:           &transcoder.ProbeResult{Duration: 90 * time.Second, Width: 1280, Height: 720},
+			audioOnlyBitrate: 64000,
+			wantHLS:          "720p,360p",
+			wantDASH:         "720p,360p",
+			wantThumbnails:   true,
+		},
+		{
+			name:             "audio-only source transcoded to standalone audio",
+			probed:           audioOnly,
+			probeErr:         transcoder.ErrNoVideoStream,
+			audioOnlySources: true,
+			wantHLS:          "audio",
+		},
+		{
+			name:     "audio-only source rejected when disabled",
+			probed:   audioOnly,
+			probeErr: transcoder.ErrNoVideoStream,
+			wantErr:  true,
+		},
+		{
+			name:             "source without any stream rejected",
+			probed:           &transcoder.ProbeResult{Duration: 90 * time.Second},
+			probeErr:         transcoder.ErrNoVideoStream,
+			audioOnlySources: true,
+			wantErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			video := &model.Video{ID: videoID, Status: model.StatusProcessing}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+			}
+			prober := &mockProber{
+				probeFn: func(ctx context.Context, input string) (*transcoder.ProbeResult, error) {
+					return tt.probed, tt.probeErr
+				},
+			}
+
+			var gotHLS, gotDASH []string
+			var thumbnails bool
+			transcode := singleVariantABR(t)
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					for _, v := range variants {
+						gotHLS = append(gotHLS, v.Name)
+					}
+					return transcode(ctx, inputPath, outputDir, variants, progress)
+				},
+				transcodeToDASHFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.DASHOutput, error) {
+					for _, v := range variants {
+						gotDASH = append(gotDASH, v.Name)
+					}
+					manifestPath := filepath.Join(outputDir, transcoder.DASHManifestName)
+					mustWriteFile(t, manifestPath, []byte("<MPD/>"))
+					return &transcoder.DASHOutput{ManifestPath: manifestPath}, nil
+				},
+				thumbnailsFn: func(ctx context.Context, inputPath, outputDir string, at time.Duration, sizes []transcoder.ThumbnailSize) ([]transcoder.ThumbnailOutput, error) {
+					thumbnails = true
+					return nil, nil
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, prober, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{
+				TempDir:          t.TempDir(),
+				MaxRetries:       3,
+				OutputFormats:    transcoder.OutputFormatsBoth,
+				AudioOnlyBitrate: tt.audioOnlyBitrate,
+				AudioOnlySources: tt.audioOnlySources,
+			})
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     "hls/" + videoID.String() + "/v1/",
+				OutputVersion: 1,
+			})
+
+			if tt.wantErr {
+				if err == nil || errs.Retryable(err) {
+					t.Fatalf("ProcessTask() error = %v, want a permanent error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if video.Status != model.StatusReady {
+				t.Errorf("video status: got %s, expected %s", video.Status, model.StatusReady)
+			}
+			if got := strings.Join(gotHLS, ","); got != tt.wantHLS {
+				t.Errorf("HLS variants: got %s, expected %s", got, tt.wantHLS)
+			}
+			if got := strings.Join(gotDASH, ","); got != tt.wantDASH {
+				t.Errorf("DASH variants: got %s, expected %s", got, tt.wantDASH)
+			}
+			if thumbnails != tt.wantThumbnails {
+				t.Errorf("thumbnails generated: got %v, expected %v", thumbnails, tt.wantThumbnails)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_VariantOverride(t *testing.T) {
+	profiles, err := transcoder.ParseABRProfiles(`{"screen": [
+		{"name": "1440p", "height": 1440, "bitrate": 3000000},
+		{"name": "720p", "height": 720, "bitrate": 900000}
+	]}`, "")
+	if err != nil {
+		t.Fatalf("parse profiles: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		profile       string
+		variants      []string
+		wantVariants  []string
+		wantPermanent bool
+		wantStatus    model.Status
+	}{
+		{
+			name:         "override restricts the ladder",
+			variants:     []string{"360p", "720p"},
+			wantVariants: []string{"720p", "360p"},
+			wantStatus:   model.StatusReady,
+		},
+		{
+			name:          "unknown variant fails permanently",
+			variants:      []string{"4k"},
+			wantPermanent: true,
+			wantStatus:    model.StatusFailed,
+		},
+		{
+			name:         "profile selects its ladder",
+			profile:      "screen",
+			wantVariants: []string{"1440p", "720p"},
+			wantStatus:   model.StatusReady,
+		},
+		{
+			name:         "override restricts the profile's ladder",
+			profile:      "screen",
+			variants:     []string{"720p"},
+			wantVariants: []string{"720p"},
+			wantStatus:   model.StatusReady,
+		},
+		{
+			name:          "unknown profile fails permanently",
+			profile:       "film",
+			wantPermanent: true,
+			wantStatus:    model.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			video := &model.Video{ID: videoID, UserID: uuid.New(), Title: "Test Video", Status: model.StatusProcessing}
+
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					video = v
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+			}
+
+			var gotVariants []string
+			transcode := singleVariantABR(t)
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					for _, v := range variants {
+						gotVariants = append(gotVariants, v.Name)
+					}
+					return transcode(ctx, inputPath, outputDir, variants, progress)
+				},
+			}
+
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3, ABRProfiles: profiles})
+			err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+				VideoID:       videoID,
+				OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:     "hls/" + videoID.String() + "/v1/",
+				OutputVersion: 1,
+				ABRProfile:    tt.profile,
+				Variants:      tt.variants,
+			})
+
+			if (err != nil && !errs.Retryable(err)) != tt.wantPermanent {
+				t.Fatalf("ProcessTask() error = %v, want permanent %v", err, tt.wantPermanent)
+			}
+			if !tt.wantPermanent && err != nil {
+				t.Fatalf("ProcessTask() error = %v", err)
+			}
+			if video.Status != tt.wantStatus {
+				t.Errorf("video status: got %s, expected %s", video.Status, tt.wantStatus)
+			}
+			if !slices.Equal(gotVariants, tt.wantVariants) {
+				t.Errorf("variants: got %v, expected %v", gotVariants, tt.wantVariants)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_RecordsProgress(t *testing.T) {
+	videoID := uuid.New()
+	video := &model.Video{ID: videoID, UserID: uuid.New(), Title: "Test Video", Status: model.StatusProcessing}
+
+	repo := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return video, nil
+		},
+		updateFn: func(ctx context.Context, v *model.Video) error {
+			video = v
+			return nil
+		},
+	}
+	storage := &mockObjectStorage{
+		downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("fake video data")), nil
+		},
+	}
+	prober := &mockProber{
+		probeFn: func(ctx context.Context, input string) (*transcoder.ProbeResult, error) {
+			return &transcoder.ProbeResult{Duration: 100 * time.Second, Width: 1920, Height: 1080}, nil
+		},
+	}
+
+	// Two of three variants report; positions past the duration are clamped,
+	// and repeated or lower percentages are not recorded again
+	transcode := singleVariantABR(t)
+	tc := &mockTranscoder{
+		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+			if progress == nil {
+				t.Fatal("expected a progress callback")
+			}
+			progress("1080p", 30*time.Second)  // 10%
+			progress("720p", 30*time.Second)   // 20%
+			progress("720p", 31*time.Second)   // 20%
+			progress("1080p", 150*time.Second) // 43%
+			progress("360p", 100*time.Second)  // 77%
+			progress("720p", 100*time.Second)  // 100%, capped at 99
+			return transcode(ctx, inputPath, outputDir, variants, progress)
+		},
+	}
+
+	var recorded []int
+	deleted := false
+	store := &mockTranscodeProgressStore{
+		setFn: func(ctx context.Context, id uuid.UUID, percent int) error {
+			if id != videoID {
+				t.Errorf("progress recorded for %s, expected %s", id, videoID)
+			}
+			recorded = append(recorded, percent)
+			return errors.New("redis down") // must not fail the transcode
+		},
+		deleteFn: func(ctx context.Context, id uuid.UUID) error {
+			deleted = true
+			return nil
+		},
+	}
+
+	var published []cache.VideoEvent
+	events := &mockVideoEventBus{
+		publishFn: func(ctx context.Context, event cache.VideoEvent) error {
+			published = append(published, event)
+			return nil
+		},
+	}
+
+	svc := NewTranscodeService(repo, storage, nil, tc, prober, nil, store, events, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, TranscodeServiceConfig{TempDir: t.TempDir(), MaxRetries: 3})
+	err := svc.ProcessTask(context.Background(), repository.TranscodeTask{
+		VideoID:       videoID,
+		OriginalKey:   "originals/" + videoID.String() + "/video.mp4",
+		OutputKey:     "hls/" + videoID.String() + "/v1/",
+		OutputVersion: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []int{0, 10, 20, 43, 77, 99}
+	if !slices.Equal(recorded, want) {
+		t.Errorf("recorded progress: got %v, expected %v", recorded, want)
+	}
+	if !deleted {
+		t.Error("expected progress to be cleared after the transcode")
+	}
+	if video.Status != model.StatusReady {
+		t.Errorf("video status: got %s, expected %s", video.Status, model.StatusReady)
+	}
+
+	var wantEvents []cache.VideoEvent
+	for _, percent := range want {
+		wantEvents = append(wantEvents, cache.VideoEvent{Type: cache.VideoEventProgress, VideoID: videoID, Progress: percent})
+	}
+	wantEvents = append(wantEvents, cache.VideoEvent{Type: cache.VideoEventStatus, VideoID: videoID, Status: model.StatusReady})
+	if !slices.Equal(published, wantEvents) {
+		t.Errorf("published events: got %v, expected %v", published, wantEvents)
+	}
 }
 
 func TestTranscodeService_ProcessTask_DownloadError(t *testing.T) {
@@ -236,7 +1902,7 @@ func TestTranscodeService_ProcessTask_DownloadError(t *testing.T) {
 		TempDir:    t.TempDir(),
 		MaxRetries: 3,
 	}
-	svc := NewTranscodeService(repo, storage, tc, nil, cfg)
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
 
 	task := repository.TranscodeTask{
 		VideoID:     videoID,
@@ -284,7 +1950,7 @@ func TestTranscodeService_ProcessTask_TranscodeError(t *testing.T) {
 	}
 
 	tc := &mockTranscoder{
-		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant) (*transcoder.ABROutput, error) {
+		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
 			return nil, errors.New("transcode failed")
 		},
 	}
@@ -293,7 +1959,7 @@ func TestTranscodeService_ProcessTask_TranscodeError(t *testing.T) {
 		TempDir:    t.TempDir(),
 		MaxRetries: 3,
 	}
-	svc := NewTranscodeService(repo, storage, tc, nil, cfg)
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
 
 	task := repository.TranscodeTask{
 		VideoID:     videoID,
@@ -309,6 +1975,271 @@ func TestTranscodeService_ProcessTask_TranscodeError(t *testing.T) {
 	}
 }
 
+func TestTranscodeService_ProcessTask_FailureReason(t *testing.T) {
+	execErr := &transcoder.ExecError{Err: errors.New("exit status 1"), Stderr: "input.mp4: Invalid data found when processing input"}
+
+	tests := []struct {
+		name       string
+		retryCount int
+		err        error
+		wantStatus model.Status
+		wantCode   model.FailureCode
+	}{
+		{
+			name:       "retried encode failure keeps processing",
+			err:        execErr,
+			wantStatus: model.StatusProcessing,
+		},
+		{
+			name:       "last attempt records the ffmpeg output",
+			retryCount: 2,
+			err:        fmt.Errorf("transcode variant 720p: %w", execErr),
+			wantStatus: model.StatusFailed,
+			wantCode:   model.FailureCodeEncodeFailed,
+		},
+		{
+			name:       "invalid input fails on the first attempt",
+			err:        fmt.Errorf("probe source: %w", transcoder.ErrNoVideoStream),
+			wantStatus: model.StatusFailed,
+			wantCode:   model.FailureCodeInvalidInput,
+		},
+		{
+			name:       "last attempt with an uncategorized error",
+			retryCount: 2,
+			err:        errors.New("upload failed"),
+			wantStatus: model.StatusFailed,
+			wantCode:   model.FailureCodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			video := &model.Video{
+				ID:          videoID,
+				UserID:      uuid.New(),
+				Title:       "Test Video",
+				Status:      model.StatusProcessing,
+				OriginalURL: "originals/" + videoID.String() + "/video.mp4",
+			}
+			var updated *model.Video
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					updated = v
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+			}
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					return nil, tt.err
+				},
+			}
+
+			cfg := TranscodeServiceConfig{
+				TempDir:    t.TempDir(),
+				MaxRetries: 3,
+			}
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+			task := repository.TranscodeTask{
+				VideoID:     videoID,
+				OriginalKey: "originals/" + videoID.String() + "/video.mp4",
+				OutputKey:   "hls/" + videoID.String() + "/",
+				RetryCount:  tt.retryCount,
+			}
+			if err := svc.ProcessTask(context.Background(), task); err == nil {
+				t.Fatal("expected error for transcode failure")
+			}
+
+			if video.Status != tt.wantStatus {
+				t.Fatalf("video status: got %s, expected %s", video.Status, tt.wantStatus)
+			}
+			if tt.wantStatus != model.StatusFailed {
+				return
+			}
+			if updated == nil || updated.FailureCode != tt.wantCode {
+				t.Fatalf("persisted failure code: got %+v, expected %s", updated, tt.wantCode)
+			}
+			if !strings.Contains(updated.FailureReason, tt.err.Error()) {
+				t.Errorf("failure reason %q lacks the error %q", updated.FailureReason, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_FFmpegLogs(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    FFmpegLogMode
+		failed  bool
+		wantLog bool
+	}{
+		{name: "off", mode: FFmpegLogsOff, failed: true},
+		{name: "failed mode skips successful jobs", mode: FFmpegLogsFailed},
+		{name: "failed mode uploads failed jobs", mode: FFmpegLogsFailed, failed: true, wantLog: true},
+		{name: "all uploads successful jobs", mode: FFmpegLogsAll, wantLog: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			tempDir := t.TempDir()
+			video := &model.Video{
+				ID:          videoID,
+				UserID:      uuid.New(),
+				Status:      model.StatusProcessing,
+				OriginalURL: "originals/" + videoID.String() + "/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					return nil
+				},
+			}
+			var logKeys []string
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					if strings.HasPrefix(key, "logs/") {
+						if contentType != "text/plain; charset=utf-8" {
+							t.Errorf("log content type = %q", contentType)
+						}
+						logKeys = append(logKeys, key)
+					}
+					return nil
+				},
+			}
+			tc := &mockTranscoder{transcodeToABRFn: singleVariantABR(t)}
+			if tt.failed {
+				tc.transcodeToABRFn = func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					return nil, errors.New("exit status 1")
+				}
+			}
+			var jobID uuid.UUID
+			jobs := &mockTranscodeJobRepository{
+				createFn: func(ctx context.Context, job *model.TranscodeJob) error {
+					jobID = job.ID
+					return nil
+				},
+			}
+
+			cfg := TranscodeServiceConfig{
+				TempDir:    tempDir,
+				MaxRetries: 3,
+				FFmpegLogs: tt.mode,
+			}
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, jobs, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+			task := repository.TranscodeTask{
+				VideoID:     videoID,
+				OriginalKey: video.OriginalURL,
+				OutputKey:   "hls/" + videoID.String() + "/",
+			}
+			if err := svc.ProcessTask(context.Background(), task); (err != nil) != tt.failed {
+				t.Fatalf("ProcessTask() error = %v, failed %v", err, tt.failed)
+			}
+
+			var want []string
+			if tt.wantLog {
+				want = []string{FFmpegLogKey(videoID, jobID)}
+			}
+			if !slices.Equal(logKeys, want) {
+				t.Errorf("uploaded logs = %v, want %v", logKeys, want)
+			}
+			if leftover, _ := filepath.Glob(filepath.Join(tempDir, "ffmpeg-*.log")); len(leftover) > 0 {
+				t.Errorf("log files left behind: %v", leftover)
+			}
+		})
+	}
+}
+
+func TestTranscodeService_ProcessTask_Timeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryCount int
+		wantStatus model.Status
+	}{
+		{name: "retried", wantStatus: model.StatusProcessing},
+		{name: "last attempt fails the video", retryCount: 2, wantStatus: model.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			video := &model.Video{
+				ID:          videoID,
+				UserID:      uuid.New(),
+				Status:      model.StatusProcessing,
+				OriginalURL: "originals/" + videoID.String() + "/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				downloadFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("fake video data")), nil
+				},
+			}
+			// Hangs like FFmpeg on a corrupt input until its process is killed
+			tc := &mockTranscoder{
+				transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
+					<-ctx.Done()
+					return nil, fmt.Errorf("transcoding cancelled: %w", errs.Wrap(errs.Transient, ctx.Err()))
+				},
+			}
+
+			cfg := TranscodeServiceConfig{
+				TempDir:     t.TempDir(),
+				MaxRetries:  3,
+				TaskTimeout: 50 * time.Millisecond,
+			}
+			svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+			task := repository.TranscodeTask{
+				VideoID:     videoID,
+				OriginalKey: video.OriginalURL,
+				OutputKey:   "hls/" + videoID.String() + "/",
+				RetryCount:  tt.retryCount,
+			}
+			before := testutil.ToFloat64(metrics.TranscodeTasksTimedOutTotal)
+			err := svc.ProcessTask(context.Background(), task)
+
+			if !errors.Is(err, ErrTaskTimeout) {
+				t.Fatalf("expected ErrTaskTimeout, got %v", err)
+			}
+			if !errs.Retryable(err) {
+				t.Errorf("timeout %v is not retryable", err)
+			}
+			if got := testutil.ToFloat64(metrics.TranscodeTasksTimedOutTotal) - before; got != 1 {
+				t.Errorf("timed out tasks counted %v times, expected 1", got)
+			}
+			if video.Status != tt.wantStatus {
+				t.Fatalf("video status: got %s, expected %s", video.Status, tt.wantStatus)
+			}
+			if tt.wantStatus == model.StatusFailed && video.FailureCode != model.FailureCodeTimedOut {
+				t.Errorf("failure code: got %q, expected %q", video.FailureCode, model.FailureCodeTimedOut)
+			}
+		})
+	}
+}
+
 func TestTranscodeService_ProcessTask_UploadError(t *testing.T) {
 	ctx := context.Background()
 	videoID := uuid.New()
@@ -339,7 +2270,7 @@ func TestTranscodeService_ProcessTask_UploadError(t *testing.T) {
 	}
 
 	tc := &mockTranscoder{
-		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant) (*transcoder.ABROutput, error) {
+		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
 			masterPath := filepath.Join(outputDir, "master.m3u8")
 			mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
 			return &transcoder.ABROutput{
@@ -353,7 +2284,7 @@ func TestTranscodeService_ProcessTask_UploadError(t *testing.T) {
 		TempDir:    t.TempDir(),
 		MaxRetries: 3,
 	}
-	svc := NewTranscodeService(repo, storage, tc, nil, cfg)
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
 
 	task := repository.TranscodeTask{
 		VideoID:     videoID,
@@ -362,11 +2293,22 @@ func TestTranscodeService_ProcessTask_UploadError(t *testing.T) {
 		RetryCount:  0,
 	}
 
+	uploadErrorsBefore := testutil.ToFloat64(metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpUpload))
+	failedBefore := testutil.ToFloat64(metrics.TranscodeAttemptsTotal.WithLabelValues(metrics.TranscodeJobFailed))
+
 	// Should return error to trigger retry
 	err := svc.ProcessTask(ctx, task)
 	if err == nil {
 		t.Error("expected error for upload failure")
 	}
+
+	uploadErrors := testutil.ToFloat64(metrics.StorageErrorsTotal.WithLabelValues(metrics.StorageOpUpload)) - uploadErrorsBefore
+	if uploadErrors != 1 {
+		t.Errorf("upload error counter: got %v, expected 1", uploadErrors)
+	}
+	if failed := testutil.ToFloat64(metrics.TranscodeAttemptsTotal.WithLabelValues(metrics.TranscodeJobFailed)) - failedBefore; failed != 1 {
+		t.Errorf("failed attempt counter: got %v, expected 1", failed)
+	}
 }
 
 func TestTranscodeService_ProcessTask_VideoNotInProcessingState(t *testing.T) {
@@ -403,7 +2345,7 @@ func TestTranscodeService_ProcessTask_VideoNotInProcessingState(t *testing.T) {
 	}
 
 	tc := &mockTranscoder{
-		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant) (*transcoder.ABROutput, error) {
+		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
 			masterPath := filepath.Join(outputDir, "master.m3u8")
 			mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
 
@@ -433,7 +2375,7 @@ func TestTranscodeService_ProcessTask_VideoNotInProcessingState(t *testing.T) {
 		TempDir:    t.TempDir(),
 		MaxRetries: 3,
 	}
-	svc := NewTranscodeService(repo, storage, tc, nil, cfg)
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
 
 	task := repository.TranscodeTask{
 		VideoID:     videoID,
@@ -487,7 +2429,7 @@ func TestTranscodeService_ProcessTask_DBUpdateError(t *testing.T) {
 	}
 
 	tc := &mockTranscoder{
-		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant) (*transcoder.ABROutput, error) {
+		transcodeToABRFn: func(ctx context.Context, inputPath, outputDir string, variants []transcoder.Variant, progress transcoder.ProgressFunc) (*transcoder.ABROutput, error) {
 			masterPath := filepath.Join(outputDir, "master.m3u8")
 			mustWriteFile(t, masterPath, []byte("#EXTM3U\n"))
 
@@ -517,7 +2459,7 @@ func TestTranscodeService_ProcessTask_DBUpdateError(t *testing.T) {
 		TempDir:    t.TempDir(),
 		MaxRetries: 3,
 	}
-	svc := NewTranscodeService(repo, storage, tc, nil, cfg)
+	svc := NewTranscodeService(repo, storage, nil, tc, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
 
 	task := repository.TranscodeTask{
 		VideoID:     videoID,
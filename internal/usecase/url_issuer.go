@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/metrics"
+)
+
+// ErrURLRateLimited is returned when a user has been issued too many presigned URLs.
+// The concrete error is a *URLRateLimitedError carrying the suggested retry delay.
+var ErrURLRateLimited = errors.New("presigned URL rate limit exceeded")
+
+// URLRateLimitedError describes the cap a requester hit and when it frees up.
+type URLRateLimitedError struct {
+	Limit      int
+	Window     time.Duration
+	RetryAfter time.Duration
+}
+
+func (e *URLRateLimitedError) Error() string {
+	return fmt.Sprintf("%s: %d URLs per %s", ErrURLRateLimited, e.Limit, e.Window)
+}
+
+func (e *URLRateLimitedError) Unwrap() error {
+	return ErrURLRateLimited
+}
+
+// URLRequest describes a presigned URL to issue.
+type URLRequest struct {
+	Key     string
+	Expiry  time.Duration
+	Purpose model.URLPurpose
+	// RequesterID is the user the URL is handed to; uuid.Nil for URLs the service uses
+	// itself, which are recorded but never rate limited.
+	RequesterID uuid.UUID
+	VideoID     uuid.UUID
+}
+
+// URLIssuer issues presigned URLs, recording every URL in an audit log and capping how
+// many each user is issued.
+type URLIssuer interface {
+	// IssueUploadURL presigns an upload to req.Key.
+	// Returns an *URLRateLimitedError if the requester has reached the cap.
+	IssueUploadURL(ctx context.Context, req URLRequest) (string, error)
+
+	// IssuePartUploadURLs presigns uploads of parts 1 to parts of a multipart upload to
+	// req.Key. The parts are one upload, so they are recorded and counted against the cap
+	// once. Returns an *URLRateLimitedError if the requester has reached the cap.
+	IssuePartUploadURLs(ctx context.Context, req URLRequest, uploadID string, parts int) ([]string, error)
+
+	// IssueDownloadURL presigns a download of req.Key.
+	// Returns an *URLRateLimitedError if the requester has reached the cap.
+	IssueDownloadURL(ctx context.Context, req URLRequest) (string, error)
+
+	// CleanupExpired deletes the records of URLs that expired more than the retention
+	// period ago and returns the number deleted.
+	CleanupExpired(ctx context.Context) (int64, error)
+}
+
+// URLIssuerConfig holds configuration for URLIssuer.
+type URLIssuerConfig struct {
+	// RateLimit is the number of URLs of one purpose a user may be issued within
+	// RateWindow; zero disables the cap.
+	RateLimit  int
+	RateWindow time.Duration
+	// Retention is how long records are kept after their URL expires.
+	Retention time.Duration
+}
+
+// DefaultURLIssuerConfig returns the default configuration.
+func DefaultURLIssuerConfig() URLIssuerConfig {
+	return URLIssuerConfig{
+		RateLimit:  60,
+		RateWindow: time.Hour,
+		Retention:  90 * 24 * time.Hour,
+	}
+}
+
+type urlIssuer struct {
+	storage repository.ObjectStorage
+	issued  repository.IssuedURLRepository
+
+	rateLimit  int
+	rateWindow time.Duration
+	retention  time.Duration
+	now        func() time.Time
+}
+
+// NewURLIssuer creates a URLIssuer that presigns with storage and records to issued.
+func NewURLIssuer(storage repository.ObjectStorage, issued repository.IssuedURLRepository, cfg URLIssuerConfig) URLIssuer {
+	return &urlIssuer{
+		storage:    storage,
+		issued:     issued,
+		rateLimit:  cfg.RateLimit,
+		rateWindow: cfg.RateWindow,
+		retention:  cfg.Retention,
+		now:        time.Now,
+	}
+}
+
+// IssueUploadURL presigns and records an upload URL.
+func (u *urlIssuer) IssueUploadURL(ctx context.Context, req URLRequest) (string, error) {
+	return u.issue(ctx, req, http.MethodPut, u.storage.GeneratePresignedUploadURL)
+}
+
+// IssuePartUploadURLs presigns every part URL and records them as a single upload URL.
+func (u *urlIssuer) IssuePartUploadURLs(ctx context.Context, req URLRequest, uploadID string, parts int) ([]string, error) {
+	urls := make([]string, parts)
+	_, err := u.issue(ctx, req, http.MethodPut, func(ctx context.Context, key string, expiry time.Duration) (string, error) {
+		for i := range urls {
+			url, err := u.storage.PresignPart(ctx, key, uploadID, i+1, expiry)
+			if err != nil {
+				return "", err
+			}
+			urls[i] = url
+		}
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// IssueDownloadURL presigns and records a download URL.
+func (u *urlIssuer) IssueDownloadURL(ctx context.Context, req URLRequest) (string, error) {
+	return u.issue(ctx, req, http.MethodGet, u.storage.GeneratePresignedDownloadURL)
+}
+
+// issue checks the cap, presigns, and records the URL. A URL that cannot be recorded
+// is not returned, so every URL handed out appears in the audit log.
+func (u *urlIssuer) issue(
+	ctx context.Context,
+	req URLRequest,
+	method string,
+	presign func(ctx context.Context, key string, expiry time.Duration) (string, error),
+) (string, error) {
+	if err := u.checkRate(ctx, req); err != nil {
+		return "", err
+	}
+
+	url, err := presign(ctx, req.Key, req.Expiry)
+	if err != nil {
+		return "", err
+	}
+
+	record := model.NewIssuedURL(req.Key, method, req.Purpose, req.RequesterID, req.VideoID, req.Expiry)
+	if err := u.issued.Create(ctx, record); err != nil {
+		return "", fmt.Errorf("record issued URL: %w", err)
+	}
+
+	metrics.PresignedURLsTotal.WithLabelValues(string(req.Purpose), metrics.PresignedURLIssued).Inc()
+	return url, nil
+}
+
+// checkRate counts the requester's recent URLs. The cap is soft: concurrent requests
+// are counted before either is recorded, so a burst may overshoot it slightly.
+func (u *urlIssuer) checkRate(ctx context.Context, req URLRequest) error {
+	if u.rateLimit <= 0 || req.RequesterID == uuid.Nil {
+		return nil
+	}
+
+	now := u.now()
+	usage, err := u.issued.UsageSince(ctx, req.RequesterID, req.Purpose, now.Add(-u.rateWindow))
+	if err != nil {
+		return fmt.Errorf("count issued URLs: %w", err)
+	}
+	if usage.Count < u.rateLimit {
+		return nil
+	}
+
+	metrics.PresignedURLsTotal.WithLabelValues(string(req.Purpose), metrics.PresignedURLRateLimited).Inc()
+
+	// The cap frees up once the oldest URL in the window falls out of it
+	return &URLRateLimitedError{
+		Limit:      u.rateLimit,
+		Window:     u.rateWindow,
+		RetryAfter: max(usage.Oldest.Add(u.rateWindow).Sub(now), time.Second),
+	}
+}
+
+// CleanupExpired deletes records past the retention period.
+func (u *urlIssuer) CleanupExpired(ctx context.Context) (int64, error) {
+	n, err := u.issued.DeleteExpiredBefore(ctx, u.now().Add(-u.retention))
+	if err != nil {
+		return 0, fmt.Errorf("delete expired issued URLs: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestURLIssuer_IssueUploadURL(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	videoID := uuid.New()
+
+	tests := []struct {
+		name        string
+		requesterID uuid.UUID
+		rateLimit   int
+		usage       repository.URLUsage
+		presignErr  error
+		createErr   error
+		wantRetry   time.Duration
+		wantErr     bool
+		wantRecord  bool
+	}{
+		{
+			name:        "under the cap",
+			requesterID: userID,
+			rateLimit:   3,
+			usage:       repository.URLUsage{Count: 2, Oldest: now.Add(-30 * time.Minute)},
+			wantRecord:  true,
+		},
+		{
+			name:        "at the cap",
+			requesterID: userID,
+			rateLimit:   3,
+			usage:       repository.URLUsage{Count: 3, Oldest: now.Add(-50 * time.Minute)},
+			wantRetry:   10 * time.Minute,
+			wantErr:     true,
+		},
+		{
+			name:        "cap disabled",
+			requesterID: userID,
+			usage:       repository.URLUsage{Count: 1000},
+			wantRecord:  true,
+		},
+		{
+			name:       "service URLs are not capped",
+			rateLimit:  1,
+			usage:      repository.URLUsage{Count: 1000},
+			wantRecord: true,
+		},
+		{
+			name:        "presign error",
+			requesterID: userID,
+			presignErr:  errors.New("storage unavailable"),
+			wantErr:     true,
+		},
+		{
+			name:        "unrecorded URL is not returned",
+			requesterID: userID,
+			createErr:   errors.New("connection refused"),
+			wantErr:     true,
+			wantRecord:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded *model.IssuedURL
+			issued := &mockIssuedURLRepository{
+				usageSinceFn: func(ctx context.Context, requesterID uuid.UUID, purpose model.URLPurpose, since time.Time) (repository.URLUsage, error) {
+					if requesterID != tt.requesterID || purpose != model.URLPurposeUpload || !since.Equal(now.Add(-time.Hour)) {
+						t.Errorf("UsageSince(%s, %s, %v): unexpected arguments", requesterID, purpose, since)
+					}
+					return tt.usage, nil
+				},
+				createFn: func(ctx context.Context, u *model.IssuedURL) error {
+					recorded = u
+					return tt.createErr
+				},
+			}
+			storage := &mockObjectStorage{
+				generatePresignedUploadURLFn: func(ctx context.Context, key string, expiry time.Duration) (string, error) {
+					return "https://minio/upload?sig=abc", tt.presignErr
+				},
+			}
+
+			cfg := DefaultURLIssuerConfig()
+			cfg.RateLimit = tt.rateLimit
+			issuer := NewURLIssuer(storage, issued, cfg).(*urlIssuer)
+			issuer.now = func() time.Time { return now }
+
+			url, err := issuer.IssueUploadURL(context.Background(), URLRequest{
+				Key:         "originals/v/video.mp4",
+				Expiry:      15 * time.Minute,
+				Purpose:     model.URLPurposeUpload,
+				RequesterID: tt.requesterID,
+				VideoID:     videoID,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IssueUploadURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantRetry > 0 {
+				var limited *URLRateLimitedError
+				if !errors.As(err, &limited) || !errors.Is(err, ErrURLRateLimited) {
+					t.Fatalf("IssueUploadURL() error = %v, want *URLRateLimitedError", err)
+				}
+				if limited.RetryAfter != tt.wantRetry {
+					t.Errorf("RetryAfter = %v, want %v", limited.RetryAfter, tt.wantRetry)
+				}
+			}
+			if tt.wantErr && url != "" {
+				t.Errorf("IssueUploadURL() = %q, want no URL on error", url)
+			}
+
+			if (recorded != nil) != tt.wantRecord {
+				t.Fatalf("recorded = %+v, want record %v", recorded, tt.wantRecord)
+			}
+			if recorded != nil {
+				if recorded.Key != "originals/v/video.mp4" || recorded.Method != "PUT" || recorded.Purpose != model.URLPurposeUpload {
+					t.Errorf("recorded = %+v, unexpected key, method or purpose", recorded)
+				}
+				if recorded.RequesterID != tt.requesterID || recorded.VideoID != videoID {
+					t.Errorf("recorded requester %s, video %s", recorded.RequesterID, recorded.VideoID)
+				}
+				if got := recorded.ExpiresAt.Sub(recorded.IssuedAt); got != 15*time.Minute {
+					t.Errorf("recorded validity = %v, want 15m", got)
+				}
+			}
+		})
+	}
+}
+
+func TestURLIssuer_IssueDownloadURL(t *testing.T) {
+	var recorded *model.IssuedURL
+	issued := &mockIssuedURLRepository{
+		createFn: func(ctx context.Context, u *model.IssuedURL) error {
+			recorded = u
+			return nil
+		},
+	}
+	storage := &mockObjectStorage{
+		generatePresignedDownloadURLFn: func(ctx context.Context, key string, expiry time.Duration) (string, error) {
+			return "https://minio/download?sig=abc", nil
+		},
+	}
+
+	issuer := NewURLIssuer(storage, issued, DefaultURLIssuerConfig())
+	url, err := issuer.IssueDownloadURL(context.Background(), URLRequest{
+		Key:     "originals/v/video.mp4",
+		Expiry:  5 * time.Minute,
+		Purpose: model.URLPurposeProbe,
+	})
+	if err != nil {
+		t.Fatalf("IssueDownloadURL() error = %v", err)
+	}
+	if url != "https://minio/download?sig=abc" {
+		t.Errorf("IssueDownloadURL() = %q", url)
+	}
+	if recorded == nil || recorded.Method != "GET" || recorded.Purpose != model.URLPurposeProbe {
+		t.Errorf("recorded = %+v, want a GET probe record", recorded)
+	}
+}
+
+func TestURLIssuer_IssuePartUploadURLs(t *testing.T) {
+	records := 0
+	issued := &mockIssuedURLRepository{
+		createFn: func(ctx context.Context, u *model.IssuedURL) error {
+			records++
+			return nil
+		},
+	}
+	storage := &mockObjectStorage{
+		presignPartFn: func(ctx context.Context, key, uploadID string, partNumber int, expiry time.Duration) (string, error) {
+			return fmt.Sprintf("https://minio/upload?uploadId=%s&partNumber=%d", uploadID, partNumber), nil
+		},
+	}
+
+	issuer := NewURLIssuer(storage, issued, DefaultURLIssuerConfig())
+	urls, err := issuer.IssuePartUploadURLs(context.Background(), URLRequest{
+		Key:         "originals/v/video.mp4",
+		Expiry:      6 * time.Hour,
+		Purpose:     model.URLPurposeUpload,
+		RequesterID: uuid.New(),
+	}, "upload-1", 3)
+	if err != nil {
+		t.Fatalf("IssuePartUploadURLs() error = %v", err)
+	}
+	if len(urls) != 3 || urls[2] != "https://minio/upload?uploadId=upload-1&partNumber=3" {
+		t.Errorf("IssuePartUploadURLs() = %v", urls)
+	}
+	if records != 1 {
+		t.Errorf("recorded %d URLs, want 1 for the whole upload", records)
+	}
+}
+
+func TestURLIssuer_CleanupExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	issued := &mockIssuedURLRepository{
+		deleteExpiredBeforeFn: func(ctx context.Context, cutoff time.Time) (int64, error) {
+			if want := now.Add(-90 * 24 * time.Hour); !cutoff.Equal(want) {
+				t.Errorf("cutoff = %v, want %v", cutoff, want)
+			}
+			return 7, nil
+		},
+	}
+
+	issuer := NewURLIssuer(&mockObjectStorage{}, issued, DefaultURLIssuerConfig()).(*urlIssuer)
+	issuer.now = func() time.Time { return now }
+
+	n, err := issuer.CleanupExpired(context.Background())
+	if err != nil {
+		t.Fatalf("CleanupExpired() error = %v", err)
+	}
+	if n != 7 {
+		t.Errorf("CleanupExpired() = %d, want 7", n)
+	}
+}
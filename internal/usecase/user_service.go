@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+// apiKeyTouchInterval is how stale an API key's last use may get before an
+// authentication records it again, so busy keys do not cost a write per request.
+const apiKeyTouchInterval = time.Minute
+
+var (
+	// ErrInvalidAPIKey is returned when an API key is malformed, unknown or revoked.
+	ErrInvalidAPIKey = errs.New(errs.Invalid, "invalid API key")
+)
+
+// CreateUserInput contains the input parameters for creating a user.
+type CreateUserInput struct {
+	Email string
+	Name  string
+	// Plan selects the user's concurrent stream limit; empty uses the default limit.
+	Plan string
+}
+
+// IssuedAPIKey is a newly issued API key. Key is only available here; the key is stored
+// as a hash.
+type IssuedAPIKey struct {
+	APIKey *model.APIKey
+	Key    string
+}
+
+// UserService defines the interface for users and their API keys.
+type UserService interface {
+	// CreateUser creates a user. Returns repository.ErrDuplicateEmail if the email is taken.
+	CreateUser(ctx context.Context, input CreateUserInput) (*model.User, error)
+
+	// GetUser returns a user, or repository.ErrUserNotFound.
+	GetUser(ctx context.Context, userID uuid.UUID) (*model.User, error)
+
+	// IssueAPIKey creates an API key authenticating as the user.
+	// Returns repository.ErrUserNotFound if the user does not exist.
+	IssueAPIKey(ctx context.Context, userID uuid.UUID, name string) (*IssuedAPIKey, error)
+
+	// ListAPIKeys returns every key of the user, revoked ones included.
+	ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error)
+
+	// RevokeAPIKey stops a key of the user from authenticating.
+	// Returns repository.ErrAPIKeyNotFound if the user has no such key.
+	RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) (*model.APIKey, error)
+
+	// AuthenticateAPIKey returns the user an API key authenticates as.
+	// Returns ErrInvalidAPIKey if the key is malformed, unknown or revoked.
+	AuthenticateAPIKey(ctx context.Context, key string) (uuid.UUID, error)
+}
+
+// UserServiceConfig holds configuration for UserService.
+type UserServiceConfig struct {
+	// APIKeyCacheTTL is how long an authenticated key is reused before it is looked up
+	// again. Revocations reach other API instances within this interval.
+	APIKeyCacheTTL time.Duration
+}
+
+// DefaultUserServiceConfig returns the default configuration.
+func DefaultUserServiceConfig() UserServiceConfig {
+	return UserServiceConfig{
+		APIKeyCacheTTL: 30 * time.Second,
+	}
+}
+
+// cachedAPIKey is an authenticated key; only valid keys are cached, so the cache is
+// bounded by the number of issued keys rather than by the keys callers try.
+type cachedAPIKey struct {
+	key       *model.APIKey
+	expiresAt time.Time
+}
+
+type userService struct {
+	users repository.UserRepository
+	keys  repository.APIKeyRepository
+
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	cached map[string]cachedAPIKey
+}
+
+// NewUserService creates a new UserService instance.
+func NewUserService(users repository.UserRepository, keys repository.APIKeyRepository, cfg UserServiceConfig) UserService {
+	return &userService{
+		users:    users,
+		keys:     keys,
+		cacheTTL: cfg.APIKeyCacheTTL,
+		cached:   make(map[string]cachedAPIKey),
+	}
+}
+
+// CreateUser validates and persists a new user.
+func (s *userService) CreateUser(ctx context.Context, input CreateUserInput) (*model.User, error) {
+	user, err := model.NewUser(input.Email, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if user.Plan, err = model.NormalizePlan(input.Plan); err != nil {
+		return nil, err
+	}
+
+	if err := s.users.Create(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetUser returns a user by ID.
+func (s *userService) GetUser(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	return s.users.GetByID(ctx, userID)
+}
+
+// IssueAPIKey creates and persists a key for an existing user.
+func (s *userService) IssueAPIKey(ctx context.Context, userID uuid.UUID, name string) (*IssuedAPIKey, error) {
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	apiKey, key, err := model.NewAPIKey(userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.keys.Create(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("create API key: %w", err)
+	}
+
+	return &IssuedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+// ListAPIKeys returns the keys of a user, oldest first.
+func (s *userService) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	return s.keys.ListByUser(ctx, userID)
+}
+
+// RevokeAPIKey revokes a key and drops it from this instance's cache at once.
+func (s *userService) RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) (*model.APIKey, error) {
+	apiKey, err := s.keys.Revoke(ctx, userID, keyID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.cached, apiKey.Hash)
+	s.mu.Unlock()
+
+	return apiKey, nil
+}
+
+// AuthenticateAPIKey looks a key up by its hash. Repository failures other than an
+// unknown key are returned as is, so callers can tell an outage from a bad key.
+func (s *userService) AuthenticateAPIKey(ctx context.Context, key string) (uuid.UUID, error) {
+	if !strings.HasPrefix(key, model.APIKeyPrefix) {
+		return uuid.Nil, ErrInvalidAPIKey
+	}
+
+	hash := model.HashAPIKey(key)
+	now := time.Now()
+	if apiKey, ok := s.lookup(hash, now); ok {
+		return apiKey.UserID, nil
+	}
+
+	apiKey, err := s.keys.GetByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return uuid.Nil, ErrInvalidAPIKey
+		}
+		return uuid.Nil, fmt.Errorf("get API key: %w", err)
+	}
+	if apiKey.IsRevoked() {
+		return uuid.Nil, ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.keys.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			slog.WarnContext(ctx, "failed to record API key use",
+				slog.String("api_key_id", apiKey.ID.String()),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	s.remember(hash, apiKey, now)
+	return apiKey.UserID, nil
+}
+
+func (s *userService) lookup(hash string, now time.Time) (*model.APIKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.cached[hash]
+	if !ok {
+		return nil, false
+	}
+	if now.After(cached.expiresAt) {
+		delete(s.cached, hash)
+		return nil, false
+	}
+	return cached.key, true
+}
+
+func (s *userService) remember(hash string, apiKey *model.APIKey, now time.Time) {
+	if s.cacheTTL <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cached[hash] = cachedAPIKey{key: apiKey, expiresAt: now.Add(s.cacheTTL)}
+}
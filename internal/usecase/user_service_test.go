@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestUserService_IssueAPIKey(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		userErr error
+		wantErr error
+	}{
+		{
+			name: "issued",
+		},
+		{
+			name:    "unknown user",
+			userErr: repository.ErrUserNotFound,
+			wantErr: repository.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored *model.APIKey
+			users := &mockUserRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.User, error) {
+					if tt.userErr != nil {
+						return nil, tt.userErr
+					}
+					return &model.User{ID: id}, nil
+				},
+			}
+			keys := &mockAPIKeyRepository{
+				createFn: func(ctx context.Context, key *model.APIKey) error {
+					stored = key
+					return nil
+				},
+			}
+
+			issued, err := NewUserService(users, keys, DefaultUserServiceConfig()).IssueAPIKey(context.Background(), userID, "ci")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssueAPIKey() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if stored != nil {
+					t.Error("expected no key to be stored")
+				}
+				return
+			}
+			if stored != issued.APIKey || stored.UserID != userID {
+				t.Errorf("stored %+v, issued %+v", stored, issued.APIKey)
+			}
+			if stored.Hash != model.HashAPIKey(issued.Key) {
+				t.Error("expected the stored hash to match the issued key")
+			}
+		})
+	}
+}
+
+func TestUserService_AuthenticateAPIKey(t *testing.T) {
+	userID := uuid.New()
+	apiKey, key, err := model.NewAPIKey(userID, "ci")
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	recentlyUsed := time.Now().Add(-10 * time.Second)
+	revokedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		key        string
+		lastUsedAt *time.Time
+		revokedAt  *time.Time
+		repoErr    error
+		wantErr    error
+		wantTouch  bool
+	}{
+		{
+			name:      "valid key",
+			key:       key,
+			wantTouch: true,
+		},
+		{
+			name:       "recently used key is not touched again",
+			key:        key,
+			lastUsedAt: &recentlyUsed,
+		},
+		{
+			name:    "malformed key",
+			key:     "not-a-key",
+			wantErr: ErrInvalidAPIKey,
+		},
+		{
+			name:    "unknown key",
+			key:     key,
+			repoErr: repository.ErrAPIKeyNotFound,
+			wantErr: ErrInvalidAPIKey,
+		},
+		{
+			name:      "revoked key",
+			key:       key,
+			revokedAt: &revokedAt,
+			wantErr:   ErrInvalidAPIKey,
+		},
+		{
+			name:    "repository failure",
+			key:     key,
+			repoErr: errors.New("connection refused"),
+			wantErr: errors.New("get API key"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			touched := false
+			keys := &mockAPIKeyRepository{
+				getByHashFn: func(ctx context.Context, hash string) (*model.APIKey, error) {
+					if tt.repoErr != nil {
+						return nil, tt.repoErr
+					}
+					if hash != apiKey.Hash {
+						return nil, repository.ErrAPIKeyNotFound
+					}
+					found := *apiKey
+					found.LastUsedAt = tt.lastUsedAt
+					found.RevokedAt = tt.revokedAt
+					return &found, nil
+				},
+				touchLastUsedFn: func(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+					touched = true
+					return nil
+				},
+			}
+
+			got, err := NewUserService(&mockUserRepository{}, keys, DefaultUserServiceConfig()).AuthenticateAPIKey(context.Background(), tt.key)
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error())) {
+					t.Fatalf("AuthenticateAPIKey() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.repoErr != nil && !errors.Is(tt.repoErr, repository.ErrAPIKeyNotFound) && errors.Is(err, ErrInvalidAPIKey) {
+					t.Error("expected an outage not to look like an invalid key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AuthenticateAPIKey() unexpected error = %v", err)
+			}
+			if got != userID {
+				t.Errorf("AuthenticateAPIKey() = %v, want %v", got, userID)
+			}
+			if touched != tt.wantTouch {
+				t.Errorf("touched = %v, want %v", touched, tt.wantTouch)
+			}
+		})
+	}
+}
+
+func TestUserService_AuthenticateAPIKey_Cache(t *testing.T) {
+	userID := uuid.New()
+	apiKey, key, err := model.NewAPIKey(userID, "ci")
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	lookups := 0
+	keys := &mockAPIKeyRepository{
+		getByHashFn: func(ctx context.Context, hash string) (*model.APIKey, error) {
+			lookups++
+			found := *apiKey
+			return &found, nil
+		},
+		revokeFn: func(ctx context.Context, uid, keyID uuid.UUID, at time.Time) (*model.APIKey, error) {
+			revoked := *apiKey
+			revoked.RevokedAt = &at
+			return &revoked, nil
+		},
+	}
+	svc := NewUserService(&mockUserRepository{}, keys, DefaultUserServiceConfig())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.AuthenticateAPIKey(ctx, key); err != nil {
+			t.Fatalf("AuthenticateAPIKey() unexpected error = %v", err)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("expected 1 lookup for a cached key, got %d", lookups)
+	}
+
+	// Revoking drops the key from the cache, so the next request looks it up again
+	if _, err := svc.RevokeAPIKey(ctx, userID, apiKey.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() unexpected error = %v", err)
+	}
+	if _, err := svc.AuthenticateAPIKey(ctx, key); err != nil {
+		t.Fatalf("AuthenticateAPIKey() unexpected error = %v", err)
+	}
+	if lookups != 2 {
+		t.Errorf("expected a lookup after revocation, got %d lookups", lookups)
+	}
+}
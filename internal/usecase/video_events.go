@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// VideoWatcher streams a video's status and transcode progress as they change.
+type VideoWatcher interface {
+	// Watch returns the video's current status (and progress, while PROCESSING) as the
+	// first events, followed by the changes published after that. The channel is closed
+	// after a final status (READY, FAILED, DELETED) or once ctx is done.
+	// Returns repository.ErrVideoNotFound if the video does not exist, was deleted or is
+	// not visible to callerID.
+	Watch(ctx context.Context, videoID, callerID uuid.UUID) (<-chan cache.VideoEvent, error)
+}
+
+type videoWatcher struct {
+	videos VideoService
+	events cache.VideoEventBus
+}
+
+// NewVideoWatcher creates a VideoWatcher reading the current state from videos and
+// changes from events.
+func NewVideoWatcher(videos VideoService, events cache.VideoEventBus) VideoWatcher {
+	return &videoWatcher{
+		videos: videos,
+		events: events,
+	}
+}
+
+// Watch subscribes before reading the current state, so a change that lands in between
+// is delivered as an event rather than lost; at worst a status is reported twice. A
+// cache miss is read from the primary, since a lagging read replica could still miss a
+// change whose event was published before the subscription.
+func (w *videoWatcher) Watch(ctx context.Context, videoID, callerID uuid.UUID) (<-chan cache.VideoEvent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	updates, err := w.events.Subscribe(ctx, videoID)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("subscribe to video events: %w", err)
+	}
+
+	video, err := w.videos.GetVideo(repository.WithStaleReads(ctx, false), videoID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if !video.VisibleTo(callerID) {
+		cancel()
+		return nil, repository.ErrVideoNotFound
+	}
+
+	out := make(chan cache.VideoEvent)
+	go func() {
+		// Canceling ends the subscription, which drains and closes updates
+		defer cancel()
+		defer close(out)
+
+		send := func(event cache.VideoEvent) bool {
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !send(statusEvent(video)) || isFinalStatus(video.Status) {
+			return
+		}
+		if video.Status == model.StatusProcessing {
+			if percent, ok := w.videos.GetTranscodeProgress(ctx, videoID); ok {
+				if !send(cache.VideoEvent{Type: cache.VideoEventProgress, VideoID: videoID, Progress: percent}) {
+					return
+				}
+			}
+		}
+
+		for event := range updates {
+			if !send(event) {
+				return
+			}
+			if event.Type == cache.VideoEventStatus && isFinalStatus(event.Status) {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// isFinalStatus reports whether a watched video will not change status again on its own.
+// A READY video may be regenerated, but that never changes its status.
+func isFinalStatus(status model.Status) bool {
+	switch status {
+	case model.StatusReady, model.StatusFailed, model.StatusDeleted:
+		return true
+	default:
+		return false
+	}
+}
+
+func statusEvent(video *model.Video) cache.VideoEvent {
+	return cache.VideoEvent{Type: cache.VideoEventStatus, VideoID: video.ID, Status: video.Status}
+}
+
+// publishStatus announces the video's new status to watchers.
+// Errors are logged but not propagated - watchers also see the status on their next connect.
+func publishStatus(ctx context.Context, events cache.VideoEventBus, video *model.Video) {
+	if events == nil {
+		return
+	}
+
+	if err := events.Publish(ctx, statusEvent(video)); err != nil {
+		slog.WarnContext(ctx, "failed to publish video status",
+			"video_id", video.ID,
+			"status", video.Status,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// collectEvents reads events until the channel is closed.
+func collectEvents(t *testing.T, events <-chan cache.VideoEvent) []cache.VideoEvent {
+	t.Helper()
+	var got []cache.VideoEvent
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return got
+			}
+			got = append(got, event)
+		case <-timeout:
+			t.Fatalf("channel not closed, got %v", got)
+		}
+	}
+}
+
+func TestVideoWatcher_Watch(t *testing.T) {
+	videoID := uuid.New()
+	status := func(s model.Status) cache.VideoEvent {
+		return cache.VideoEvent{Type: cache.VideoEventStatus, VideoID: videoID, Status: s}
+	}
+	progress := func(p int) cache.VideoEvent {
+		return cache.VideoEvent{Type: cache.VideoEventProgress, VideoID: videoID, Progress: p}
+	}
+
+	tests := []struct {
+		name     string
+		status   model.Status
+		progress int
+		updates  []cache.VideoEvent
+		want     []cache.VideoEvent
+	}{
+		{
+			name:     "processing until ready",
+			status:   model.StatusProcessing,
+			progress: 40,
+			updates:  []cache.VideoEvent{progress(60), status(model.StatusReady), progress(99)},
+			want:     []cache.VideoEvent{status(model.StatusProcessing), progress(40), progress(60), status(model.StatusReady)},
+		},
+		{
+			name:    "uploaded until failed",
+			status:  model.StatusUploaded,
+			updates: []cache.VideoEvent{status(model.StatusProcessing), status(model.StatusFailed)},
+			want:    []cache.VideoEvent{status(model.StatusUploaded), status(model.StatusProcessing), status(model.StatusFailed)},
+		},
+		{
+			name:   "already ready",
+			status: model.StatusReady,
+			want:   []cache.VideoEvent{status(model.StatusReady)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, Status: tt.status}, nil
+				},
+				transcodeProgressFn: func(ctx context.Context, id uuid.UUID) (int, bool) {
+					return tt.progress, tt.progress > 0
+				},
+			}
+			subscriptionDone := make(chan struct{})
+			bus := &mockVideoEventBus{
+				subscribeFn: func(ctx context.Context, id uuid.UUID) (<-chan cache.VideoEvent, error) {
+					updates := make(chan cache.VideoEvent, len(tt.updates))
+					for _, event := range tt.updates {
+						updates <- event
+					}
+					go func() {
+						<-ctx.Done()
+						close(subscriptionDone)
+					}()
+					return updates, nil
+				},
+			}
+
+			events, err := NewVideoWatcher(svc, bus).Watch(context.Background(), videoID, uuid.Nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := collectEvents(t, events); !slices.Equal(got, tt.want) {
+				t.Errorf("events: got %v, expected %v", got, tt.want)
+			}
+			select {
+			case <-subscriptionDone:
+			case <-time.After(time.Second):
+				t.Error("subscription was not canceled")
+			}
+		})
+	}
+}
+
+func TestVideoWatcher_Watch_Errors(t *testing.T) {
+	subscribeErr := errors.New("redis down")
+
+	tests := []struct {
+		name         string
+		private      bool
+		getErr       error
+		subscribeErr error
+		wantErr      error
+	}{
+		{name: "video not found", getErr: repository.ErrVideoNotFound, wantErr: repository.ErrVideoNotFound},
+		{name: "private video of another user", private: true, wantErr: repository.ErrVideoNotFound},
+		{name: "subscribe fails", subscribeErr: subscribeErr, wantErr: subscribeErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.private {
+						return &model.Video{ID: id, UserID: uuid.New(), Visibility: model.VisibilityPrivate}, nil
+					}
+					return nil, tt.getErr
+				},
+			}
+			bus := &mockVideoEventBus{}
+			if tt.subscribeErr != nil {
+				bus.subscribeFn = func(ctx context.Context, id uuid.UUID) (<-chan cache.VideoEvent, error) {
+					return nil, tt.subscribeErr
+				}
+			}
+
+			_, err := NewVideoWatcher(svc, bus).Watch(context.Background(), uuid.New(), uuid.New())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVideoWatcher_Watch_Cancel(t *testing.T) {
+	svc := &mockVideoService{
+		getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return &model.Video{ID: id, Status: model.StatusUploaded}, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := NewVideoWatcher(svc, &mockVideoEventBus{}).Watch(ctx, uuid.New(), uuid.Nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event := <-events; event.Status != model.StatusUploaded {
+		t.Errorf("first event: got %v, expected the current status", event)
+	}
+
+	cancel()
+	if got := collectEvents(t, events); len(got) != 0 {
+		t.Errorf("unexpected events after cancel: %v", got)
+	}
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// VideoExpirerConfig holds configuration for VideoExpirer.
+type VideoExpirerConfig struct {
+	// Grace is how long an EXPIRED video keeps its stored objects before it is deleted;
+	// zero keeps them until the owner deletes the video.
+	Grace time.Duration
+	// BatchSize caps the videos expired, and separately the videos deleted, by one run.
+	BatchSize int
+}
+
+// DefaultVideoExpirerConfig returns the default configuration.
+func DefaultVideoExpirerConfig() VideoExpirerConfig {
+	return VideoExpirerConfig{
+		BatchSize: 100,
+	}
+}
+
+// VideoExpiryResult summarizes a run of the VideoExpirer.
+type VideoExpiryResult struct {
+	Expired int
+	Deleted int
+}
+
+// VideoTokenRevoker invalidates every playback token issued so far for a video.
+// PlaybackTokenService implements it.
+type VideoTokenRevoker interface {
+	RevokeVideoTokens(ctx context.Context, videoID uuid.UUID) error
+}
+
+// VideoExpirer unpublishes videos whose ExpiresAt has passed.
+type VideoExpirer interface {
+	// Run moves up to BatchSize videos whose ExpiresAt has passed to EXPIRED and revokes
+	// their playback tokens, then deletes up to BatchSize EXPIRED videos whose grace
+	// period has ended. Failures of single videos are logged and retried by the next run.
+	Run(ctx context.Context) (*VideoExpiryResult, error)
+}
+
+// expirableStatuses are the statuses a video expires from. PROCESSING is left out: a video
+// being transcoded expires on the first run after its transcode ends.
+var expirableStatuses = []model.Status{
+	model.StatusPendingUpload,
+	model.StatusUploaded,
+	model.StatusReady,
+	model.StatusFailed,
+	model.StatusArchived,
+}
+
+type videoExpirer struct {
+	videos repository.VideoRepository
+	queue  repository.MessageQueue
+	tokens VideoTokenRevoker
+	cache  cache.VideoCache
+	events cache.VideoEventBus
+	// statusEvents is optional; nil records no status transitions.
+	statusEvents repository.VideoStatusEventRepository
+	cfg          VideoExpirerConfig
+	now          func() time.Time
+}
+
+// NewVideoExpirer creates a new VideoExpirer instance.
+// The tokens parameter is optional - pass nil to let issued tokens lapse with their TTL.
+// The videoCache parameter is optional - pass nil when videos are not cached.
+// The events parameter is optional - pass nil to not publish status changes.
+// The statusEvents parameter is optional - pass nil to not record status transitions.
+func NewVideoExpirer(
+	videos repository.VideoRepository,
+	queue repository.MessageQueue,
+	tokens VideoTokenRevoker,
+	videoCache cache.VideoCache,
+	events cache.VideoEventBus,
+	statusEvents repository.VideoStatusEventRepository,
+	cfg VideoExpirerConfig,
+) VideoExpirer {
+	return &videoExpirer{
+		videos:       videos,
+		queue:        queue,
+		tokens:       tokens,
+		cache:        videoCache,
+		events:       events,
+		statusEvents: statusEvents,
+		cfg:          cfg,
+		now:          time.Now,
+	}
+}
+
+func (e *videoExpirer) Run(ctx context.Context) (*VideoExpiryResult, error) {
+	result := &VideoExpiryResult{}
+	now := e.now()
+
+	expiring, err := e.videos.List(ctx, repository.VideoFilter{
+		Statuses:      expirableStatuses,
+		ExpiresBefore: now,
+		Limit:         e.cfg.BatchSize,
+	})
+	if err != nil {
+		return result, fmt.Errorf("list expiring videos: %w", err)
+	}
+	for _, video := range expiring {
+		if err := e.expire(ctx, video); err != nil {
+			slog.WarnContext(ctx, "failed to expire video",
+				"video_id", video.ID,
+				"error", err,
+			)
+			continue
+		}
+		result.Expired++
+	}
+
+	if e.cfg.Grace <= 0 {
+		return result, nil
+	}
+
+	lapsed, err := e.videos.List(ctx, repository.VideoFilter{
+		Status:        model.StatusExpired,
+		ExpiresBefore: now.Add(-e.cfg.Grace),
+		Limit:         e.cfg.BatchSize,
+	})
+	if err != nil {
+		return result, fmt.Errorf("list lapsed videos: %w", err)
+	}
+	for _, video := range lapsed {
+		if err := e.delete(ctx, video); err != nil {
+			slog.WarnContext(ctx, "failed to delete expired video",
+				"video_id", video.ID,
+				"error", err,
+			)
+			continue
+		}
+		result.Deleted++
+	}
+
+	return result, nil
+}
+
+// expire unpublishes the video. Tokens are revoked after the status change, so none can
+// be issued in between; a failed revocation is logged, since the tokens also lapse with
+// their TTL.
+func (e *videoExpirer) expire(ctx context.Context, video *model.Video) error {
+	from := video.Status
+	if err := video.TransitionTo(model.StatusExpired); err != nil {
+		return err
+	}
+	if err := e.videos.UpdateStatus(ctx, video.ID, video.Status); err != nil {
+		return fmt.Errorf("update video status: %w", err)
+	}
+	recordStatusEvent(ctx, e.statusEvents, video, from, model.StatusActorExpirer, "expires_at passed")
+	invalidateVideo(ctx, e.cache, video)
+	publishStatus(ctx, e.events, video)
+
+	if e.tokens != nil {
+		if err := e.tokens.RevokeVideoTokens(ctx, video.ID); err != nil {
+			slog.WarnContext(ctx, "failed to revoke playback tokens of expired video",
+				"video_id", video.ID,
+				"error", err,
+			)
+		}
+	}
+
+	slog.InfoContext(ctx, "expired video",
+		"video_id", video.ID,
+		"expires_at", video.ExpiresAt,
+	)
+	return nil
+}
+
+// delete removes the video like an owner's delete does. As there, the status is persisted
+// before the cleanup task is published.
+func (e *videoExpirer) delete(ctx context.Context, video *model.Video) error {
+	if err := video.TransitionTo(model.StatusDeleted); err != nil {
+		return err
+	}
+	if err := e.videos.UpdateStatus(ctx, video.ID, video.Status); err != nil {
+		return fmt.Errorf("update video status: %w", err)
+	}
+	recordStatusEvent(ctx, e.statusEvents, video, model.StatusExpired, model.StatusActorExpirer, "expiry grace period ended")
+	if err := e.queue.PublishDeleteTask(ctx, newDeleteTask(video)); err != nil {
+		return fmt.Errorf("publish delete task: %w", err)
+	}
+	invalidateVideo(ctx, e.cache, video)
+	publishStatus(ctx, e.events, video)
+
+	slog.InfoContext(ctx, "deleted expired video",
+		"video_id", video.ID,
+		"expires_at", video.ExpiresAt,
+	)
+	return nil
+}
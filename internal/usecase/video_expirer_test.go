@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestVideoExpirer_Run(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	grace := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name           string
+		grace          time.Duration
+		expiring       []model.Status
+		lapsed         int
+		listErr        error
+		updateErr      error
+		revokeErr      error
+		publishErr     error
+		want           VideoExpiryResult
+		wantErr        bool
+		wantStatuses   []model.Status
+		wantRevoked    int
+		wantDeleteTask int
+	}{
+		{
+			name:         "expires due videos and revokes their tokens",
+			expiring:     []model.Status{model.StatusReady, model.StatusArchived},
+			want:         VideoExpiryResult{Expired: 2},
+			wantStatuses: []model.Status{model.StatusExpired, model.StatusExpired},
+			wantRevoked:  2,
+		},
+		{
+			name:         "never-uploaded video expires too",
+			expiring:     []model.Status{model.StatusPendingUpload},
+			want:         VideoExpiryResult{Expired: 1},
+			wantStatuses: []model.Status{model.StatusExpired},
+			wantRevoked:  1,
+		},
+		{
+			name:           "deletes expired videos past the grace period",
+			grace:          grace,
+			lapsed:         1,
+			want:           VideoExpiryResult{Deleted: 1},
+			wantStatuses:   []model.Status{model.StatusDeleted},
+			wantDeleteTask: 1,
+		},
+		{
+			name:         "failed revocation still expires the video",
+			expiring:     []model.Status{model.StatusReady},
+			revokeErr:    errors.New("redis down"),
+			want:         VideoExpiryResult{Expired: 1},
+			wantStatuses: []model.Status{model.StatusExpired},
+			wantRevoked:  1,
+		},
+		{
+			name:      "failed status update is left for the next run",
+			expiring:  []model.Status{model.StatusReady},
+			updateErr: errors.New("connection refused"),
+			want:      VideoExpiryResult{},
+		},
+		{
+			name:           "failed delete task publish is not counted",
+			grace:          grace,
+			lapsed:         1,
+			publishErr:     errors.New("queue unavailable"),
+			want:           VideoExpiryResult{},
+			wantStatuses:   []model.Status{model.StatusDeleted},
+			wantDeleteTask: 1,
+		},
+		{
+			name:    "list failure",
+			listErr: errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				filters     []repository.VideoFilter
+				statuses    []model.Status
+				revoked     int
+				deleteTasks int
+			)
+			repo := &mockVideoRepository{
+				listFn: func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+					filters = append(filters, filter)
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					var videos []*model.Video
+					if filter.Status == model.StatusExpired {
+						for range tt.lapsed {
+							videos = append(videos, &model.Video{ID: uuid.New(), UserID: uuid.New(), Status: model.StatusExpired, ExpiresAt: now.Add(-grace - time.Hour)})
+						}
+						return videos, nil
+					}
+					for _, status := range tt.expiring {
+						videos = append(videos, &model.Video{ID: uuid.New(), UserID: uuid.New(), Status: status, ExpiresAt: now.Add(-time.Minute)})
+					}
+					return videos, nil
+				},
+				updateStatusFn: func(ctx context.Context, id uuid.UUID, status model.Status) error {
+					if tt.updateErr != nil {
+						return tt.updateErr
+					}
+					statuses = append(statuses, status)
+					return nil
+				},
+			}
+			queue := &mockMessageQueue{
+				publishDeleteTaskFn: func(ctx context.Context, task repository.DeleteTask) error {
+					deleteTasks++
+					if len(task.OutputPrefixes) == 0 {
+						t.Error("delete task has no output prefixes")
+					}
+					return tt.publishErr
+				},
+			}
+			tokens := &mockVideoTokenRevoker{
+				revokeVideoTokensFn: func(ctx context.Context, videoID uuid.UUID) error {
+					revoked++
+					return tt.revokeErr
+				},
+			}
+
+			cfg := DefaultVideoExpirerConfig()
+			cfg.Grace = tt.grace
+			expirer := NewVideoExpirer(repo, queue, tokens, nil, nil, nil, cfg).(*videoExpirer)
+			expirer.now = func() time.Time { return now }
+
+			got, err := expirer.Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if *got != tt.want {
+				t.Errorf("Run() = %+v, want %+v", *got, tt.want)
+			}
+			if !slices.Equal(statuses, tt.wantStatuses) {
+				t.Errorf("status updates = %v, want %v", statuses, tt.wantStatuses)
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("revocations = %d, want %d", revoked, tt.wantRevoked)
+			}
+			if deleteTasks != tt.wantDeleteTask {
+				t.Errorf("delete tasks = %d, want %d", deleteTasks, tt.wantDeleteTask)
+			}
+
+			if !slices.Equal(filters[0].Statuses, expirableStatuses) || !filters[0].ExpiresBefore.Equal(now) {
+				t.Errorf("expiring filter = %+v, want expirable statuses before %v", filters[0], now)
+			}
+			wantLists := 1
+			if tt.grace > 0 {
+				wantLists = 2
+				if filters[1].Status != model.StatusExpired || !filters[1].ExpiresBefore.Equal(now.Add(-tt.grace)) {
+					t.Errorf("lapsed filter = %+v, want EXPIRED before %v", filters[1], now.Add(-tt.grace))
+				}
+			}
+			if len(filters) != wantLists {
+				t.Errorf("List() called %d times, want %d", len(filters), wantLists)
+			}
+		})
+	}
+}
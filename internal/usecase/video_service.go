@@ -1,20 +1,120 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"path"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/errs"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/transcoder"
+)
+
+const (
+	// DefaultVideoPageSize is the number of videos returned when no limit is given.
+	DefaultVideoPageSize = 20
+	// MaxVideoPageSize caps the number of videos returned per page.
+	MaxVideoPageSize = 100
+
+	// probeURLExpiry is the lifetime of the download URL handed to the prober.
+	probeURLExpiry = 5 * time.Minute
+
+	// MaxSearchQueryLength caps search queries, in characters.
+	MaxSearchQueryLength = 200
+
+	// MaxMultipartUploadSize is the largest original a multipart upload accepts, the S3
+	// object size limit.
+	MaxMultipartUploadSize = 5 << 40 // 5 TiB
+	// defaultPartSize is the part size of multipart uploads, grown for originals that
+	// would otherwise need more than maxUploadParts parts.
+	defaultPartSize = 64 << 20 // 64 MiB
+	// maxUploadParts is the S3 limit on parts per multipart upload.
+	maxUploadParts = 10000
 )
 
 var (
 	// ErrVideoAlreadyCompleted is returned when attempting to process a video that has already completed.
 	ErrVideoAlreadyCompleted = errors.New("video processing has already completed")
+
+	// ErrUploadMissing is returned when an upload is confirmed but the original object is
+	// missing or empty in storage.
+	ErrUploadMissing = errors.New("uploaded original not found in storage")
+
+	// ErrNoVideoStream is returned when the uploaded original contains no video to transcode.
+	ErrNoVideoStream = errors.New("uploaded original has no video stream")
+
+	// ErrPlanningUnavailable is returned by PlanProcess when no prober is configured.
+	ErrPlanningUnavailable = errors.New("transcode planning is not available")
+
+	// ErrVideoProcessing is returned when deleting a video that is being transcoded.
+	ErrVideoProcessing = errors.New("video is being processed")
+
+	// ErrNotVideoOwner is returned when a user asks for a video they do not own.
+	ErrNotVideoOwner = errors.New("user does not own the video")
+
+	// ErrOriginalNotUploaded is returned when downloading the original of a video still
+	// waiting for its upload.
+	ErrOriginalNotUploaded = errors.New("original has not been uploaded")
+
+	// ErrUploadClosed is returned when starting a multipart upload for a video that is no
+	// longer waiting for its original.
+	ErrUploadClosed = errors.New("video is not awaiting an upload")
+
+	// ErrInvalidUploadSize is returned when a multipart upload is started for a size that is
+	// not positive or exceeds MaxMultipartUploadSize.
+	ErrInvalidUploadSize = errors.New("upload size must be positive and at most 5 TiB")
+
+	// ErrInvalidUploadParts is returned when a multipart upload is completed without parts,
+	// with repeated or out-of-range part numbers, or with a part missing its ETag.
+	ErrInvalidUploadParts = errors.New("upload parts must be distinct part numbers with ETags")
+
+	// ErrInvalidSearchQuery is returned when a search query is blank or longer than
+	// MaxSearchQueryLength characters.
+	ErrInvalidSearchQuery = errors.New("search query must be 1 to 200 characters")
+
+	// ErrInlineUploadDisabled is returned by UploadVideo when no inline upload limit is configured.
+	ErrInlineUploadDisabled = errors.New("inline uploads are disabled")
+
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent with a different
+	// request than the one that first used it.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+
+	// ErrInlineUploadTooLarge is returned when an inline upload exceeds the configured limit.
+	ErrInlineUploadTooLarge = errors.New("inline upload exceeds the size limit")
+
+	// ErrVideoExpired is returned when changing the expiry of a video that has already expired.
+	ErrVideoExpired = errors.New("video has expired")
+
+	// ErrQuotaExceeded is returned when creating or uploading a video while the user's
+	// storage quota is used up.
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+	// ErrUploadExceedsQuota is returned when an original does not fit in the rest of the
+	// user's storage quota.
+	ErrUploadExceedsQuota = errors.New("upload exceeds the remaining storage quota")
+
+	// ErrUnknownVariant is returned when a retranscode names a variant outside the ABR ladder.
+	// It is coded errs.Invalid, so a worker handed such a task gives up instead of retrying.
+	ErrUnknownVariant = errs.New(errs.Invalid, "unknown transcode variant")
+
+	// ErrUnknownABRProfile is returned when a process or retranscode names an ABR profile
+	// that is not configured. Like ErrUnknownVariant it is coded errs.Invalid.
+	ErrUnknownABRProfile = errs.New(errs.Invalid, "unknown ABR profile")
 )
 
 // CreateVideoInput contains the input parameters for creating a video.
@@ -22,81 +122,408 @@ type CreateVideoInput struct {
 	UserID   uuid.UUID
 	Title    string
 	FileName string
+	// PreviewSeconds requests a public preview rendition of the given length; zero disables it.
+	PreviewSeconds int
+	// ExpiresAt unpublishes the video at the given time; zero never expires it.
+	ExpiresAt time.Time
+	// IdempotencyKey makes retries of the request return the video it created instead of
+	// creating another; empty creates a video every time. Only CreateVideo honours it.
+	IdempotencyKey string
 }
 
 // CreateVideoOutput contains the result of creating a video.
 type CreateVideoOutput struct {
 	Video     *model.Video
 	UploadURL string
+	// Replayed is set when the video was created by an earlier request with the same
+	// idempotency key.
+	Replayed bool
+}
+
+// UploadVideoInput contains the input parameters for creating a video with its file.
+type UploadVideoInput struct {
+	CreateVideoInput
+	// Content is the original file; ContentType is stored with it.
+	Content     io.Reader
+	ContentType string
+	// Process starts transcoding with ABRProfile once the original is stored.
+	Process    bool
+	ABRProfile string
+}
+
+// MultipartUpload is a started multipart upload of an original. Part i+1 is uploaded to
+// PartURLs[i] with a PUT of PartSize bytes, except for the last part, which holds the rest.
+type MultipartUpload struct {
+	UploadID  string
+	PartSize  int64
+	PartURLs  []string
+	ExpiresAt time.Time
+}
+
+// OriginalURLInput identifies an original download and who asks for it.
+type OriginalURLInput struct {
+	VideoID uuid.UUID
+	UserID  uuid.UUID
+}
+
+// OriginalURL is a presigned download URL of a video's original.
+type OriginalURL struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// ProcessInput contains the optional overrides of a transcode.
+type ProcessInput struct {
+	// ABRProfile names the ABR ladder to encode (e.g., "screen"); empty uses the default profile.
+	ABRProfile string
+}
+
+// RetranscodeInput contains the optional overrides of a retranscode.
+type RetranscodeInput struct {
+	// ABRProfile names the ABR ladder to encode; empty uses the default profile.
+	ABRProfile string
+	// Variants names the variants of the ladder to encode (e.g., "720p"); empty encodes all of them.
+	Variants []string
+}
+
+// ListVideosInput contains the input parameters for listing a user's videos.
+type ListVideosInput struct {
+	UserID uuid.UUID
+	// CallerID is who lists the videos; callers other than UserID, anonymous ones
+	// included, only get public videos.
+	CallerID uuid.UUID
+	// Cursor is the opaque NextCursor of the previous page; empty starts from the newest video.
+	Cursor string
+	// Limit is the page size; zero uses DefaultVideoPageSize.
+	Limit int
+}
+
+// ListVideosOutput contains a page of videos.
+type ListVideosOutput struct {
+	Videos []*model.Video
+	// NextCursor is empty when there are no more videos.
+	NextCursor string
+}
+
+// SearchVideosInput contains the input parameters for searching a user's videos.
+type SearchVideosInput struct {
+	UserID uuid.UUID
+	// CallerID is who searches; callers other than UserID, anonymous ones included,
+	// only get public videos.
+	CallerID uuid.UUID
+	// Query uses web search syntax: words, "quoted phrases", OR and -excluded words.
+	Query string
+	// Cursor is the opaque NextCursor of the previous page; empty starts from the best match.
+	Cursor string
+	// Limit is the page size; zero uses DefaultVideoPageSize.
+	Limit int
+}
+
+// UpdateMetadataInput contains the metadata to change; nil fields are left as they are.
+type UpdateMetadataInput struct {
+	Title *string
+	// Description replaces the description; an empty string clears it.
+	Description *string
+	// Tags replace the tags; an empty slice clears them.
+	Tags *[]string
+	// Visibility changes who can see and play the video.
+	Visibility *model.Visibility
 }
 
 // VideoService defines the interface for video business logic operations.
 type VideoService interface {
 	// CreateVideo creates video metadata and returns a presigned upload URL.
+	// With an idempotency key the user already created a video with, that video is
+	// returned instead, or ErrIdempotencyKeyReused if the key came with another request.
+	// Returns ErrQuotaExceeded if the user's storage quota is used up.
 	CreateVideo(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error)
 
+	// UploadVideo creates a video from a small file sent with the request instead of through
+	// a presigned URL, leaving it UPLOADED, or PROCESSING when input.Process is set. Returns
+	// ErrInlineUploadTooLarge past the configured limit, ErrUploadMissing for an empty file,
+	// ErrQuotaExceeded or ErrUploadExceedsQuota when the file does not fit in the user's
+	// quota, and ErrInlineUploadDisabled when no limit is configured. A failure to start processing
+	// does not fail the upload; the video is returned UPLOADED so it can be processed later.
+	UploadVideo(ctx context.Context, input UploadVideoInput) (*model.Video, error)
+
+	// CompleteUpload confirms that the original has been uploaded, records its size and
+	// ETag, and transitions the video to UPLOADED. Returns ErrUploadMissing if the object
+	// does not exist, and ErrQuotaExceeded or ErrUploadExceedsQuota if it does not fit in
+	// the user's quota; the video then stays PENDING_UPLOAD, so the upload can be completed
+	// once space has been freed. Videos already past PENDING_UPLOAD are returned unchanged.
+	CompleteUpload(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+
+	// InitiateMultipartUpload starts a multipart upload of the original of a PENDING_UPLOAD
+	// video and presigns a URL per part, for originals too large for a single PUT. Returns
+	// ErrUploadClosed for videos past PENDING_UPLOAD, ErrInvalidUploadSize for an unusable
+	// size, ErrQuotaExceeded or ErrUploadExceedsQuota if size does not fit in the user's
+	// quota, or an *URLRateLimitedError if the user has reached the URL cap.
+	InitiateMultipartUpload(ctx context.Context, videoID uuid.UUID, size int64) (*MultipartUpload, error)
+
+	// CompleteMultipartUpload assembles the uploaded parts into the original, then confirms
+	// the upload like CompleteUpload, including its quota check. Videos already past
+	// PENDING_UPLOAD are returned unchanged. Returns ErrInvalidUploadParts for malformed parts,
+	// repository.ErrMultipartUploadNotFound for an unknown upload, and
+	// repository.ErrInvalidMultipartParts for parts storage does not have.
+	CompleteMultipartUpload(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error)
+
 	// TriggerProcess initiates transcoding for an uploaded video.
 	// This operation is idempotent - calling it on an already processing video returns nil.
-	TriggerProcess(ctx context.Context, videoID uuid.UUID) error
+	// Returns ErrUnknownABRProfile if the input names a profile that is not configured.
+	TriggerProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) error
+
+	// PlanProcess probes the uploaded original and returns what TriggerProcess would
+	// produce, without changing the video or enqueueing anything. Returns
+	// ErrUploadMissing if the original does not exist yet, ErrNoVideoStream if it has
+	// no video, ErrUnknownABRProfile for an unconfigured profile, and
+	// ErrPlanningUnavailable if the service has no prober.
+	PlanProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) (*TranscodePlan, error)
+
+	// Retranscode regenerates the HLS output of a READY or FAILED video (e.g., after a
+	// watermark change or a transient encoder failure). A READY video keeps serving its
+	// current output until the new version is published; a FAILED video has its partial
+	// output removed and moves back to PROCESSING. Returns ErrVideoNotReady for any other
+	// status, ErrUnknownABRProfile or ErrUnknownVariant if the input names a profile or a
+	// variant that is not configured, or an *OverloadedError when the work is being shed.
+	Retranscode(ctx context.Context, videoID uuid.UUID, input RetranscodeInput) error
 
 	// GetVideo retrieves video information by ID.
 	GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+
+	// GetTranscodeProgress returns the percentage of the video's current transcode that has
+	// been encoded. Returns false if no transcode is reporting progress. Progress is
+	// best-effort: lookup failures are logged and reported as no progress.
+	GetTranscodeProgress(ctx context.Context, videoID uuid.UUID) (int, bool)
+
+	// GetOriginalURL returns a presigned URL to download the video's original upload.
+	// Returns ErrNotVideoOwner unless the user owns the video, ErrOriginalNotUploaded
+	// before the upload is confirmed, or an *URLRateLimitedError if the user has been
+	// issued too many URLs.
+	GetOriginalURL(ctx context.Context, input OriginalURLInput) (*OriginalURL, error)
+
+	// ResolveShareSlug retrieves the video a share link points at.
+	// Returns repository.ErrVideoNotFound for unknown or malformed slugs.
+	ResolveShareSlug(ctx context.Context, slug string) (*model.Video, error)
+
+	// ResolveTitleSlug retrieves a user's video by its title slug.
+	// Returns repository.ErrVideoNotFound for unknown or malformed slugs.
+	ResolveTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error)
+
+	// ListVideos returns a page of a user's videos, newest first.
+	// Returns ErrInvalidCursor if the cursor was not produced by a previous page.
+	ListVideos(ctx context.Context, input ListVideosInput) (*ListVideosOutput, error)
+
+	// SearchVideos returns a page of a user's videos whose titles or descriptions match
+	// the query, best match first. Returns ErrInvalidSearchQuery for a blank or overlong
+	// query and ErrInvalidCursor if the cursor was not produced by a previous page.
+	SearchVideos(ctx context.Context, input SearchVideosInput) (*ListVideosOutput, error)
+
+	// SetExpiration schedules the video to be unpublished at expiresAt; the zero time
+	// clears the expiry. Returns model.ErrExpiryInPast for a time that has passed and
+	// ErrVideoExpired if the video has already expired.
+	SetExpiration(ctx context.Context, videoID uuid.UUID, expiresAt time.Time) (*model.Video, error)
+
+	// UpdateMetadata changes the title, description, tags and visibility of the video,
+	// returning the updated video. Returns the model's validation error (e.g. model.ErrInvalidTag) for
+	// an invalid value, in which case nothing is changed.
+	UpdateMetadata(ctx context.Context, videoID uuid.UUID, input UpdateMetadataInput) (*model.Video, error)
+
+	// DeleteVideo marks the video DELETED and enqueues the removal of its stored objects,
+	// returning the deleted video. Deleted videos are reported as repository.ErrVideoNotFound
+	// by every method. Returns ErrVideoProcessing while a transcode is running.
+	DeleteVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error)
+
+	// ListStatusEvents returns the video's recorded status transitions, most recent first.
+	// A zero limit uses DefaultStatusEventsLimit; larger limits are capped at MaxStatusEventsLimit.
+	// Returns an empty slice when status events are not recorded, and
+	// repository.ErrVideoNotFound if the video is not visible to callerID.
+	ListStatusEvents(ctx context.Context, videoID, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error)
+
+	// GetStorageUsage returns the storage the user's videos take up, with the user's
+	// quota; a zero QuotaBytes means unlimited.
+	GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error)
 }
 
 // VideoServiceConfig holds configuration for VideoService.
 type VideoServiceConfig struct {
 	UploadURLExpiry time.Duration
+	// MultipartURLExpiry is the lifetime of multipart upload part URLs, long enough to
+	// upload the largest originals.
+	MultipartURLExpiry time.Duration
+	// OriginalURLExpiry is the lifetime of an original's download URL.
+	OriginalURLExpiry time.Duration
+	// IDStrategy selects the scheme for each video's sortable ID.
+	IDStrategy model.IDStrategy
+	// TitleSlugs gives new videos a slug derived from their title, unique per user.
+	TitleSlugs bool
+	// TaskTTL is how long an enqueued transcode task stays valid; 0 never expires tasks.
+	TaskTTL time.Duration
+	// StorageKeySecret, when set, stores new videos under an HMAC of their ID instead of
+	// the ID itself, so knowing one video's ID reveals nothing about other objects' keys.
+	StorageKeySecret []byte
+	// ABRProfiles holds the ladders a transcode may name; nil offers the built-in ladder only.
+	ABRProfiles *transcoder.ABRProfiles
+	// InlineUploadMaxBytes caps files uploaded with the create request; 0 disables UploadVideo.
+	InlineUploadMaxBytes int64
+	// StorageQuotaBytes caps the bytes of originals and output each user may store;
+	// 0 means unlimited.
+	StorageQuotaBytes int64
 }
 
 // DefaultVideoServiceConfig returns the default configuration.
 func DefaultVideoServiceConfig() VideoServiceConfig {
 	return VideoServiceConfig{
-		UploadURLExpiry: 15 * time.Minute,
+		UploadURLExpiry:    15 * time.Minute,
+		MultipartURLExpiry: 6 * time.Hour,
+		OriginalURLExpiry:  time.Hour,
+		IDStrategy:         model.IDStrategyULID,
 	}
 }
 
+// maxShareSlugAttempts bounds retries on share slug collisions. With 32^8 possible
+// slugs a single retry is already rare; repeated collisions indicate a bug.
+const maxShareSlugAttempts = 5
+
+const (
+	// maxNumberedTitleSlugs is the highest numeric suffix tried on title slug collisions
+	// ("my-video-2" ... "my-video-10") before falling back to random suffixes.
+	maxNumberedTitleSlugs = 10
+	// maxTitleSlugAttempts bounds inserts on title slug collisions, including the
+	// random-suffix attempts after the numbered ones.
+	maxTitleSlugAttempts = maxNumberedTitleSlugs + 3
+)
+
 type videoService struct {
 	repo    repository.VideoRepository
 	storage repository.ObjectStorage
 	queue   repository.MessageQueue
+	// admission is optional; nil admits all work.
+	admission AdmissionController
+	// prober is optional; nil disables PlanProcess.
+	prober transcoder.Prober
+	// estimator is optional; nil plans without cost estimates.
+	estimator TranscodeEstimator
+	// urls is optional; nil presigns with storage directly, without audit or rate limits.
+	urls URLIssuer
+	// progress is optional; nil reports no transcode progress.
+	progress cache.TranscodeProgressStore
+	// events is optional; nil publishes no status events.
+	events cache.VideoEventBus
+	// statusEvents is optional; nil records no status transitions.
+	statusEvents repository.VideoStatusEventRepository
+	// outbox is optional; nil publishes transcode tasks straight to the queue after the
+	// video update, so a failed publish leaves the video PROCESSING without a task.
+	outbox repository.OutboxRepository
+	// purger is optional; nil leaves output cached at the edge after a visibility change.
+	purger repository.CDNPurger
 
-	uploadURLExpiry time.Duration
+	uploadURLExpiry    time.Duration
+	multipartURLExpiry time.Duration
+	originalURLExpiry  time.Duration
+	idStrategy         model.IDStrategy
+	titleSlugs         bool
+	taskTTL            time.Duration
+	storageSecret      []byte
+	profiles           *transcoder.ABRProfiles
+	inlineUploadMax    int64
+	storageQuota       int64
+	now                func() time.Time
 }
 
 // NewVideoService creates a new VideoService instance.
+// The admission parameter is optional - pass nil to never shed background work.
+// The prober parameter is optional - pass nil to disable PlanProcess.
+// The estimator parameter is optional - pass nil to plan without cost estimates.
+// The urls parameter is optional - pass nil to presign URLs without auditing or rate limits.
+// The progress parameter is optional - pass nil to never report transcode progress.
+// The events parameter is optional - pass nil to not publish status changes.
+// The statusEvents parameter is optional - pass nil to not record status transitions.
+// The outbox parameter is optional - pass nil to publish transcode tasks without an outbox.
+// The purger parameter is optional - pass nil to leave output to expire from the CDN when
+// a video's visibility changes.
 func NewVideoService(
 	repo repository.VideoRepository,
 	storage repository.ObjectStorage,
 	queue repository.MessageQueue,
+	admission AdmissionController,
+	prober transcoder.Prober,
+	estimator TranscodeEstimator,
+	urls URLIssuer,
+	progress cache.TranscodeProgressStore,
+	events cache.VideoEventBus,
+	statusEvents repository.VideoStatusEventRepository,
+	outbox repository.OutboxRepository,
+	purger repository.CDNPurger,
 	cfg VideoServiceConfig,
 ) VideoService {
 	return &videoService{
-		repo:            repo,
-		storage:         storage,
-		queue:           queue,
-		uploadURLExpiry: cfg.UploadURLExpiry,
+		repo:               repo,
+		storage:            storage,
+		queue:              queue,
+		admission:          admission,
+		prober:             prober,
+		estimator:          estimator,
+		urls:               urls,
+		progress:           progress,
+		events:             events,
+		statusEvents:       statusEvents,
+		outbox:             outbox,
+		purger:             purger,
+		uploadURLExpiry:    cfg.UploadURLExpiry,
+		multipartURLExpiry: cfg.MultipartURLExpiry,
+		originalURLExpiry:  cfg.OriginalURLExpiry,
+		idStrategy:         cfg.IDStrategy,
+		titleSlugs:         cfg.TitleSlugs,
+		taskTTL:            cfg.TaskTTL,
+		storageSecret:      cfg.StorageKeySecret,
+		profiles:           abrProfilesOrDefault(cfg.ABRProfiles),
+		inlineUploadMax:    cfg.InlineUploadMaxBytes,
+		storageQuota:       cfg.StorageQuotaBytes,
+		now:                time.Now,
 	}
 }
 
 // CreateVideo creates video metadata and generates a presigned upload URL.
 func (s *videoService) CreateVideo(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error) {
-	video, err := model.NewVideo(input.UserID, input.Title)
-	if err != nil {
+	if input.IdempotencyKey != "" {
+		output, err := s.replayCreate(ctx, input)
+		if !errors.Is(err, repository.ErrVideoNotFound) {
+			return output, err
+		}
+	}
+
+	if err := s.checkQuota(ctx, input.UserID, 0); err != nil {
 		return nil, err
 	}
 
-	key := s.generateOriginalKey(video.ID, input.FileName)
+	video, err := s.newVideo(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.IdempotencyKey != "" {
+		video.IdempotencyKey = input.IdempotencyKey
+		video.IdempotencyHash = createRequestHash(input)
+	}
 
-	uploadURL, err := s.storage.GeneratePresignedUploadURL(ctx, key, s.uploadURLExpiry)
+	uploadURL, err := s.issueUploadURL(ctx, URLRequest{
+		Key:         video.OriginalURL,
+		Expiry:      s.uploadURLExpiry,
+		Purpose:     model.URLPurposeUpload,
+		RequesterID: video.UserID,
+		VideoID:     video.ID,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("generate presigned upload URL: %w", err)
 	}
 
-	video.SetOriginalURL(key)
-
-	if err := s.repo.Create(ctx, video); err != nil {
+	if err := s.createWithSlugs(ctx, video); err != nil {
+		if errors.Is(err, repository.ErrDuplicateIdempotencyKey) {
+			// A concurrent retry created the video first
+			return s.replayCreate(ctx, input)
+		}
 		return nil, fmt.Errorf("create video: %w", err)
 	}
+	recordStatusEvent(ctx, s.statusEvents, video, "", model.StatusActorAPI, "created")
 
 	return &CreateVideoOutput{
 		Video:     video,
@@ -104,10 +531,279 @@ func (s *videoService) CreateVideo(ctx context.Context, input CreateVideoInput)
 	}, nil
 }
 
+// replayCreate returns the video the user created with the input's idempotency key, with
+// a fresh upload URL while it still awaits its original. The video is returned as it is
+// now, which may differ from the first response if it has been uploaded or edited since.
+// Returns repository.ErrVideoNotFound if the key is unused.
+func (s *videoService) replayCreate(ctx context.Context, input CreateVideoInput) (*CreateVideoOutput, error) {
+	video, err := s.repo.GetByIdempotencyKey(ctx, input.UserID, input.IdempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("get video by idempotency key: %w", err)
+	}
+	if video.IdempotencyHash != createRequestHash(input) {
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	output := &CreateVideoOutput{Video: video, Replayed: true}
+	if video.Status == model.StatusPendingUpload {
+		output.UploadURL, err = s.issueUploadURL(ctx, URLRequest{
+			Key:         video.OriginalURL,
+			Expiry:      s.uploadURLExpiry,
+			Purpose:     model.URLPurposeUpload,
+			RequesterID: video.UserID,
+			VideoID:     video.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("generate presigned upload URL: %w", err)
+		}
+	}
+	return output, nil
+}
+
+// createRequestHash fingerprints the fields of a create request, so a retry is told
+// apart from a reuse of its idempotency key for another video.
+func createRequestHash(input CreateVideoInput) string {
+	var expiresAt string
+	if !input.ExpiresAt.IsZero() {
+		expiresAt = input.ExpiresAt.UTC().Format(time.RFC3339Nano)
+	}
+	h := sha256.New()
+	for _, field := range []string{input.UserID.String(), input.Title, input.FileName, strconv.Itoa(input.PreviewSeconds), expiresAt} {
+		// Length-prefixed, so fields cannot run into each other
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// UploadVideo stores the original before the video row exists, so a failed upload leaves
+// no PENDING_UPLOAD video behind; the object is removed again if the row cannot be created.
+// One byte past the limit is read so an oversized file is told apart from one at the limit.
+func (s *videoService) UploadVideo(ctx context.Context, input UploadVideoInput) (*model.Video, error) {
+	if s.inlineUploadMax <= 0 {
+		return nil, ErrInlineUploadDisabled
+	}
+	if input.Process {
+		if _, err := resolveLadder(s.profiles, input.ABRProfile); err != nil {
+			return nil, err
+		}
+	}
+
+	// Refuse early when nothing fits, before reading the file
+	if err := s.checkQuota(ctx, input.UserID, 0); err != nil {
+		return nil, err
+	}
+
+	video, err := s.newVideo(input.CreateVideoInput)
+	if err != nil {
+		return nil, err
+	}
+
+	key := video.OriginalURL
+	if err := s.storage.Upload(ctx, key, io.LimitReader(input.Content, s.inlineUploadMax+1), input.ContentType); err != nil {
+		return nil, fmt.Errorf("upload original: %w", err)
+	}
+	info, err := s.storage.Stat(ctx, key)
+	if err != nil {
+		s.deleteOriginal(ctx, video)
+		return nil, fmt.Errorf("stat original: %w", err)
+	}
+	switch {
+	case info.Size > s.inlineUploadMax:
+		s.deleteOriginal(ctx, video)
+		return nil, ErrInlineUploadTooLarge
+	case info.Size == 0:
+		s.deleteOriginal(ctx, video)
+		return nil, ErrUploadMissing
+	}
+	if err := s.checkQuota(ctx, video.UserID, info.Size); err != nil {
+		s.deleteOriginal(ctx, video)
+		return nil, err
+	}
+
+	if err := video.MarkUploaded(info.Size, info.ETag); err != nil {
+		return nil, err
+	}
+	if err := s.createWithSlugs(ctx, video); err != nil {
+		s.deleteOriginal(ctx, video)
+		return nil, fmt.Errorf("create video: %w", err)
+	}
+	recordStatusEvent(ctx, s.statusEvents, video, "", model.StatusActorAPI, "created with inline upload")
+	publishStatus(ctx, s.events, video)
+
+	if input.Process {
+		// A copy, so a failed start still returns the video as stored
+		processing := *video
+		if err := s.startProcess(ctx, &processing, input.ABRProfile); err != nil {
+			slog.WarnContext(ctx, "failed to start processing of uploaded video",
+				"video_id", video.ID,
+				"error", err,
+			)
+		} else {
+			video = &processing
+		}
+	}
+
+	return video, nil
+}
+
+// newVideo builds a validated video whose original key is set, ready to be created.
+func (s *videoService) newVideo(input CreateVideoInput) (*model.Video, error) {
+	video, err := model.NewVideo(input.UserID, input.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := video.SetPreviewSeconds(input.PreviewSeconds); err != nil {
+		return nil, err
+	}
+	if err := video.SetExpiresAt(input.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	video.StorageKey = s.deriveStorageKey(video.ID)
+	video.SetOriginalURL(s.generateOriginalKey(video.StoragePrefix(), input.FileName))
+
+	video.SortableID, err = s.idStrategy.NewID(video.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("generate sortable ID: %w", err)
+	}
+	return video, nil
+}
+
+// deleteOriginal removes the original of a video that was never created.
+func (s *videoService) deleteOriginal(ctx context.Context, video *model.Video) {
+	if err := s.storage.Delete(ctx, video.OriginalURL); err != nil {
+		slog.WarnContext(ctx, "failed to delete original of uncreated video",
+			"video_id", video.ID,
+			"key", video.OriginalURL,
+			"error", err,
+		)
+	}
+}
+
+// CompleteUpload stats the original rather than trusting the client, so a video only
+// reaches UPLOADED once storage has the object.
+func (s *videoService) CompleteUpload(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.Status != model.StatusPendingUpload {
+		return video, nil
+	}
+	return s.markUploaded(ctx, video)
+}
+
+// InitiateMultipartUpload sizes parts at defaultPartSize, or larger in whole MiB when the
+// original would need more than maxUploadParts. An upload that is never completed is left
+// to the bucket's lifecycle rule for incomplete multipart uploads.
+func (s *videoService) InitiateMultipartUpload(ctx context.Context, videoID uuid.UUID, size int64) (*MultipartUpload, error) {
+	if size <= 0 || size > MaxMultipartUploadSize {
+		return nil, ErrInvalidUploadSize
+	}
+
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.Status != model.StatusPendingUpload {
+		return nil, ErrUploadClosed
+	}
+	if err := s.checkQuota(ctx, video.UserID, size); err != nil {
+		return nil, err
+	}
+
+	partSize := multipartPartSize(size)
+	parts := int((size + partSize - 1) / partSize)
+
+	uploadID, err := s.storage.InitiateMultipartUpload(ctx, video.OriginalURL, "")
+	if err != nil {
+		return nil, fmt.Errorf("initiate multipart upload: %w", err)
+	}
+
+	req := URLRequest{
+		Key:         video.OriginalURL,
+		Expiry:      s.multipartURLExpiry,
+		Purpose:     model.URLPurposeUpload,
+		RequesterID: video.UserID,
+		VideoID:     video.ID,
+	}
+	urls, err := s.issuePartUploadURLs(ctx, req, uploadID, parts)
+	if err != nil {
+		return nil, fmt.Errorf("generate presigned part URLs: %w", err)
+	}
+
+	return &MultipartUpload{
+		UploadID:  uploadID,
+		PartSize:  partSize,
+		PartURLs:  urls,
+		ExpiresAt: s.now().Add(s.multipartURLExpiry),
+	}, nil
+}
+
+// CompleteMultipartUpload sorts the parts, since storage requires ascending part numbers.
+func (s *videoService) CompleteMultipartUpload(ctx context.Context, videoID uuid.UUID, uploadID string, parts []repository.CompletedPart) (*model.Video, error) {
+	parts, err := sortedUploadParts(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.Status != model.StatusPendingUpload {
+		return video, nil
+	}
+
+	if err := s.storage.CompleteMultipartUpload(ctx, video.OriginalURL, uploadID, parts); err != nil {
+		return nil, fmt.Errorf("complete multipart upload: %w", err)
+	}
+	return s.markUploaded(ctx, video)
+}
+
+// markUploaded records the stored original of a PENDING_UPLOAD video and moves it to UPLOADED.
+func (s *videoService) markUploaded(ctx context.Context, video *model.Video) (*model.Video, error) {
+	info, err := s.storage.Stat(ctx, video.OriginalURL)
+	if err != nil {
+		if errors.Is(err, repository.ErrObjectNotFound) {
+			return nil, ErrUploadMissing
+		}
+		return nil, fmt.Errorf("stat original: %w", err)
+	}
+	if info.Size == 0 {
+		return nil, ErrUploadMissing
+	}
+	// The video's own original is not counted yet, as its size is only recorded below
+	if err := s.checkQuota(ctx, video.UserID, info.Size); err != nil {
+		return nil, err
+	}
+
+	from := video.Status
+	if err := video.MarkUploaded(info.Size, info.ETag); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, video); err != nil {
+		return nil, fmt.Errorf("update video status: %w", err)
+	}
+	recordStatusEvent(ctx, s.statusEvents, video, from, model.StatusActorAPI, "upload confirmed")
+	publishStatus(ctx, s.events, video)
+
+	return video, nil
+}
+
 // TriggerProcess initiates async transcoding for a video.
 // Idempotency: returns nil if video is already processing.
-func (s *videoService) TriggerProcess(ctx context.Context, videoID uuid.UUID) error {
-	video, err := s.repo.GetByID(ctx, videoID)
+// A PENDING_UPLOAD video is first confirmed like CompleteUpload, so its original is
+// stated and counted against the quota before it is transcoded.
+func (s *videoService) TriggerProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) error {
+	if _, err := resolveLadder(s.profiles, input.ABRProfile); err != nil {
+		return err
+	}
+
+	video, err := s.getVideo(ctx, videoID)
 	if err != nil {
 		return err
 	}
@@ -120,40 +816,795 @@ func (s *videoService) TriggerProcess(ctx context.Context, videoID uuid.UUID) er
 		return ErrVideoAlreadyCompleted
 	}
 
+	if video.Status == model.StatusPendingUpload {
+		if video, err = s.markUploaded(ctx, video); err != nil {
+			return err
+		}
+	}
+
+	return s.startProcess(ctx, video, input.ABRProfile)
+}
+
+// startProcess moves an uploaded video to PROCESSING and enqueues its transcode.
+func (s *videoService) startProcess(ctx context.Context, video *model.Video, abrProfile string) error {
+	if err := s.admit(ctx, WorkInteractive); err != nil {
+		return err
+	}
+
+	from := video.Status
 	if err := video.TransitionTo(model.StatusProcessing); err != nil {
 		return err
 	}
 
-	if err := s.repo.Update(ctx, video); err != nil {
+	task := s.newTranscodeTask(video)
+	task.ABRProfile = abrProfile
+	if err := s.updateAndEnqueue(ctx, video, task); err != nil {
+		return err
+	}
+	recordStatusEvent(ctx, s.statusEvents, video, from, model.StatusActorAPI, "processing requested")
+	publishStatus(ctx, s.events, video)
+
+	return nil
+}
+
+// PlanProcess applies the same status checks as TriggerProcess but skips admission,
+// since nothing is enqueued. The original is probed through a presigned URL, so only
+// its headers are read rather than the whole object.
+func (s *videoService) PlanProcess(ctx context.Context, videoID uuid.UUID, input ProcessInput) (*TranscodePlan, error) {
+	if s.prober == nil {
+		return nil, ErrPlanningUnavailable
+	}
+
+	ladder, err := resolveLadder(s.profiles, input.ABRProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.Status == model.StatusReady || video.Status == model.StatusFailed {
+		return nil, ErrVideoAlreadyCompleted
+	}
+
+	exists, err := s.storage.Exists(ctx, video.OriginalURL)
+	if err != nil {
+		return nil, fmt.Errorf("check original: %w", err)
+	}
+	if !exists {
+		return nil, ErrUploadMissing
+	}
+
+	url, err := s.issueDownloadURL(ctx, URLRequest{
+		Key:     video.OriginalURL,
+		Expiry:  probeURLExpiry,
+		Purpose: model.URLPurposeProbe,
+		VideoID: video.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate presigned download URL: %w", err)
+	}
+
+	source, err := s.prober.Probe(ctx, url)
+	if err != nil {
+		if errors.Is(err, transcoder.ErrNoVideoStream) {
+			return nil, ErrNoVideoStream
+		}
+		return nil, fmt.Errorf("probe original: %w", err)
+	}
+
+	plan := planTranscode(*source, ladder, video.PreviewSeconds)
+	plan.ABRProfile = cmp.Or(input.ABRProfile, s.profiles.DefaultName())
+
+	// The estimate is informational, so the plan is returned without it on failure
+	if s.estimator != nil {
+		plan.Estimate, err = s.estimator.Estimate(ctx, *source)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to estimate transcode cost",
+				"video_id", videoID,
+				"error", err,
+			)
+		}
+	}
+
+	return plan, nil
+}
+
+// Retranscode enqueues a transcode into a fresh output version.
+// A READY video keeps its status and the worker swaps the output pointer once the new
+// version is fully uploaded. A FAILED video has nothing to serve, so whatever the failed
+// attempts uploaded is removed before it moves back to PROCESSING.
+func (s *videoService) Retranscode(ctx context.Context, videoID uuid.UUID, input RetranscodeInput) error {
+	ladder, err := resolveLadder(s.profiles, input.ABRProfile)
+	if err != nil {
+		return err
+	}
+	if _, err := selectVariants(ladder, input.Variants); err != nil {
+		return err
+	}
+
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return err
+	}
+
+	var class WorkClass
+	switch video.Status {
+	case model.StatusReady:
+		// Reprocesses are deferrable, so they are shed first when the pipeline is struggling
+		class = WorkBackground
+	case model.StatusFailed:
+		// The owner is waiting on a video that cannot be played at all
+		class = WorkInteractive
+	default:
+		return ErrVideoNotReady
+	}
+
+	if err := s.admit(ctx, class); err != nil {
+		return err
+	}
+
+	task := s.newTranscodeTask(video)
+	task.ABRProfile = input.ABRProfile
+	task.Variants = input.Variants
+
+	// A READY video keeps serving its output whether or not the task is published, so
+	// only the transition out of FAILED needs the outbox
+	if video.Status == model.StatusReady {
+		if err := s.queue.PublishTranscodeTask(ctx, task); err != nil {
+			return fmt.Errorf("publish transcode task: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.removeFailedOutput(ctx, video); err != nil {
+		return err
+	}
+	if err := video.TransitionTo(model.StatusProcessing); err != nil {
+		return err
+	}
+	if err := s.updateAndEnqueue(ctx, video, task); err != nil {
+		return err
+	}
+	recordStatusEvent(ctx, s.statusEvents, video, model.StatusFailed, model.StatusActorAPI, "retranscode of failed video requested")
+	publishStatus(ctx, s.events, video)
+
+	return nil
+}
+
+// updateAndEnqueue persists video and publishes task. With an outbox, the task is written
+// in the same transaction as the update and published right after the commit; a failed
+// publish is left to the OutboxRelay rather than failing the request, as the video is
+// already PROCESSING.
+func (s *videoService) updateAndEnqueue(ctx context.Context, video *model.Video, task repository.TranscodeTask) error {
+	if s.outbox == nil {
+		if err := s.repo.Update(ctx, video); err != nil {
+			return fmt.Errorf("update video status: %w", err)
+		}
+		if err := s.queue.PublishTranscodeTask(ctx, task); err != nil {
+			return fmt.Errorf("publish transcode task: %w", err)
+		}
+		return nil
+	}
+
+	msg := &repository.OutboxMessage{
+		ID:        uuid.New(),
+		Task:      task,
+		CreatedAt: s.now(),
+	}
+	if err := s.outbox.UpdateVideoWithTask(ctx, video, msg, msg.CreatedAt.Add(outboxPublishGrace)); err != nil {
 		return fmt.Errorf("update video status: %w", err)
 	}
+	publishOutboxMessage(ctx, s.outbox, s.queue, msg, s.now)
+	return nil
+}
+
+// removeFailedOutput deletes the output a failed video's attempts left behind. Nothing of
+// a FAILED video was ever published, so every version under its prefixes is partial.
+func (s *videoService) removeFailedOutput(ctx context.Context, video *model.Video) error {
+	for _, prefix := range []string{
+		path.Join("hls", video.StoragePrefix()) + "/",
+		path.Join("previews", video.StoragePrefix()) + "/",
+	} {
+		if _, err := s.storage.DeleteByPrefix(ctx, prefix); err != nil {
+			return fmt.Errorf("remove failed output %s: %w", prefix, err)
+		}
+	}
+	return nil
+}
+
+// GetVideo retrieves video information by ID. The lookup may be served by a read
+// replica unless ctx forces the primary with repository.WithStaleReads(ctx, false).
+func (s *videoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	return s.getVideo(tolerateStaleReads(ctx), videoID)
+}
+
+// GetTranscodeProgress reads the progress the worker records while encoding.
+func (s *videoService) GetTranscodeProgress(ctx context.Context, videoID uuid.UUID) (int, bool) {
+	if s.progress == nil {
+		return 0, false
+	}
+
+	percent, ok, err := s.progress.Get(ctx, videoID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get transcode progress",
+			"video_id", videoID,
+			"error", err,
+		)
+		return 0, false
+	}
+	return percent, ok
+}
+
+// GetOriginalURL presigns through the URL issuer, so the download is audited and counts
+// against the owner's cap like an upload URL.
+func (s *videoService) GetOriginalURL(ctx context.Context, input OriginalURLInput) (*OriginalURL, error) {
+	video, err := s.getVideo(ctx, input.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.UserID != input.UserID {
+		return nil, ErrNotVideoOwner
+	}
+	if video.Status == model.StatusPendingUpload || video.OriginalURL == "" {
+		return nil, ErrOriginalNotUploaded
+	}
+
+	expiresAt := s.now().Add(s.originalURLExpiry)
+	url, err := s.issueDownloadURL(ctx, URLRequest{
+		Key:         video.OriginalURL,
+		Expiry:      s.originalURLExpiry,
+		Purpose:     model.URLPurposeDownload,
+		RequesterID: input.UserID,
+		VideoID:     video.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate presigned download URL: %w", err)
+	}
+
+	return &OriginalURL{URL: url, ExpiresAt: expiresAt}, nil
+}
+
+// ResolveShareSlug retrieves a video by its share slug.
+// Malformed slugs are rejected without a database round trip.
+func (s *videoService) ResolveShareSlug(ctx context.Context, slug string) (*model.Video, error) {
+	if !model.IsValidShareSlug(slug) {
+		return nil, repository.ErrVideoNotFound
+	}
+	return hideDeleted(s.repo.GetByShareSlug(ctx, slug))
+}
+
+// ResolveTitleSlug retrieves a user's video by its title slug.
+// Malformed slugs are rejected without a database round trip.
+func (s *videoService) ResolveTitleSlug(ctx context.Context, userID uuid.UUID, slug string) (*model.Video, error) {
+	if !model.IsValidTitleSlug(slug) {
+		return nil, repository.ErrVideoNotFound
+	}
+	return hideDeleted(s.repo.GetByTitleSlug(ctx, userID, slug))
+}
+
+// SetExpiration records the expiry; the VideoExpirer unpublishes the video once it passes.
+func (s *videoService) SetExpiration(ctx context.Context, videoID uuid.UUID, expiresAt time.Time) (*model.Video, error) {
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.IsExpired() {
+		return nil, ErrVideoExpired
+	}
+
+	if err := video.SetExpiresAt(expiresAt); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, video); err != nil {
+		return nil, fmt.Errorf("update video expiry: %w", err)
+	}
+	return video, nil
+}
+
+// UpdateMetadata validates every field before writing, so a rejected update leaves the
+// stored video untouched. A visibility change purges the output from the CDN, so a
+// video made private stops being served from the edge to holders of its URLs.
+func (s *videoService) UpdateMetadata(ctx context.Context, videoID uuid.UUID, input UpdateMetadataInput) (*model.Video, error) {
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	visibility := video.Visibility
+
+	if input.Title != nil {
+		if err := video.SetTitle(*input.Title); err != nil {
+			return nil, err
+		}
+	}
+	if input.Description != nil {
+		if err := video.SetDescription(*input.Description); err != nil {
+			return nil, err
+		}
+	}
+	if input.Tags != nil {
+		if err := video.SetTags(*input.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if input.Visibility != nil {
+		if err := video.SetVisibility(*input.Visibility); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Update(ctx, video); err != nil {
+		return nil, fmt.Errorf("update video metadata: %w", err)
+	}
+
+	if video.Visibility != visibility {
+		s.purgeCDN(ctx, video.ID, supersededPrefixes(video))
+	}
+	return video, nil
+}
+
+// purgeCDN evicts the given prefixes from the CDN.
+// Errors are logged but not propagated - the objects still expire with their cache TTL.
+func (s *videoService) purgeCDN(ctx context.Context, videoID uuid.UUID, prefixes []string) {
+	if s.purger == nil || len(prefixes) == 0 {
+		return
+	}
+
+	if err := s.purger.Purge(ctx, prefixes); err != nil {
+		slog.WarnContext(ctx, "failed to purge CDN",
+			"video_id", videoID,
+			"prefixes", prefixes,
+			"error", err,
+		)
+	}
+}
+
+// DeleteVideo soft-deletes the video; the row is kept so the deletion stays auditable.
+// The status is persisted before the cleanup task is published, so a publish failure
+// leaves orphaned objects rather than a visible video without its files.
+func (s *videoService) DeleteVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.Status == model.StatusProcessing {
+		return nil, ErrVideoProcessing
+	}
+
+	from := video.Status
+	if err := video.TransitionTo(model.StatusDeleted); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, video); err != nil {
+		return nil, fmt.Errorf("update video status: %w", err)
+	}
+	recordStatusEvent(ctx, s.statusEvents, video, from, model.StatusActorAPI, "deleted by owner")
+
+	if err := s.queue.PublishDeleteTask(ctx, newDeleteTask(video)); err != nil {
+		return nil, fmt.Errorf("publish delete task: %w", err)
+	}
+	publishStatus(ctx, s.events, video)
+
+	return video, nil
+}
+
+// ListStatusEvents checks the video exists so an unknown ID is distinguishable from one
+// without recorded transitions.
+func (s *videoService) ListStatusEvents(ctx context.Context, videoID, callerID uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+	video, err := s.getVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if !video.VisibleTo(callerID) {
+		return nil, repository.ErrVideoNotFound
+	}
+	if s.statusEvents == nil {
+		return []*model.VideoStatusEvent{}, nil
+	}
+
+	if limit <= 0 {
+		limit = DefaultStatusEventsLimit
+	}
+	limit = min(limit, MaxStatusEventsLimit)
+
+	events, err := s.statusEvents.ListByVideoID(ctx, videoID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list status events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetStorageUsage reads the usage from the videos table; originals count once their
+// upload is confirmed and output once the worker has published it.
+func (s *videoService) GetStorageUsage(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error) {
+	usage, err := s.repo.GetStorageUsage(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get storage usage: %w", err)
+	}
+	usage.QuotaBytes = s.storageQuota
+	return usage, nil
+}
+
+// checkQuota returns ErrQuotaExceeded if the user's quota is used up, or
+// ErrUploadExceedsQuota if size more bytes do not fit. Without a quota nothing is read.
+// Concurrent uploads are checked independently and may together overshoot the quota.
+func (s *videoService) checkQuota(ctx context.Context, userID uuid.UUID, size int64) error {
+	if s.storageQuota <= 0 {
+		return nil
+	}
+	usage, err := s.GetStorageUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case usage.Exhausted():
+		return ErrQuotaExceeded
+	case !usage.Allows(size):
+		return ErrUploadExceedsQuota
+	}
+	return nil
+}
+
+// getVideo retrieves a video by ID, reporting deleted videos as not found.
+func (s *videoService) getVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
+	return hideDeleted(s.repo.GetByID(ctx, videoID))
+}
+
+// tolerateStaleReads lets the reads of a lookup that writes nothing be served by a read
+// replica, unless the caller already decided where they go.
+func tolerateStaleReads(ctx context.Context) context.Context {
+	if _, set := repository.StaleReads(ctx); set {
+		return ctx
+	}
+	return repository.WithStaleReads(ctx, true)
+}
+
+// hideDeleted turns a lookup of a deleted video into repository.ErrVideoNotFound.
+func hideDeleted(video *model.Video, err error) (*model.Video, error) {
+	if err != nil {
+		return nil, err
+	}
+	if video.IsDeleted() {
+		return nil, repository.ErrVideoNotFound
+	}
+	return video, nil
+}
+
+// ListVideos returns a page of a user's videos using keyset pagination.
+// One extra row is fetched to tell whether another page follows. Like GetVideo, the page
+// may be served by a read replica.
+func (s *videoService) ListVideos(ctx context.Context, input ListVideosInput) (*ListVideosOutput, error) {
+	if input.UserID == uuid.Nil {
+		return nil, model.ErrInvalidUserID
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultVideoPageSize
+	}
+	limit = min(limit, MaxVideoPageSize)
+
+	filter := repository.VideoFilter{UserID: input.UserID, ExcludeDeleted: true, ExcludeExpired: true, Limit: limit + 1}
+	// Filtered in the query rather than from the page, so pages stay full
+	if input.CallerID != input.UserID {
+		filter.Visibility = model.VisibilityPublic
+	}
+	if input.Cursor != "" {
+		createdAt, id, err := decodeKeysetCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &repository.VideoCursor{CreatedAt: createdAt, ID: id}
+	}
+
+	videos, err := s.repo.List(tolerateStaleReads(ctx), filter)
+	if err != nil {
+		return nil, fmt.Errorf("list videos: %w", err)
+	}
+
+	output := &ListVideosOutput{Videos: videos}
+	if len(videos) > limit {
+		output.Videos = videos[:limit]
+		last := output.Videos[limit-1]
+		output.NextCursor = encodeKeysetCursor(last.CreatedAt, last.ID)
+	}
+
+	return output, nil
+}
+
+// SearchVideos returns a page of matching videos using keyset pagination on the rank.
+// Like ListVideos, one extra row is fetched to tell whether another page follows.
+func (s *videoService) SearchVideos(ctx context.Context, input SearchVideosInput) (*ListVideosOutput, error) {
+	if input.UserID == uuid.Nil {
+		return nil, model.ErrInvalidUserID
+	}
+	query := strings.TrimSpace(input.Query)
+	if query == "" || utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultVideoPageSize
+	}
+	limit = min(limit, MaxVideoPageSize)
+
+	search := repository.VideoSearch{Query: query, UserID: input.UserID, Limit: limit + 1}
+	if input.CallerID != input.UserID {
+		search.Visibility = model.VisibilityPublic
+	}
+	if input.Cursor != "" {
+		rank, createdAt, id, err := decodeSearchCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		search.After = &repository.VideoSearchCursor{Rank: rank, CreatedAt: createdAt, ID: id}
+	}
+
+	results, err := s.repo.Search(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("search videos: %w", err)
+	}
+
+	output := &ListVideosOutput{Videos: make([]*model.Video, 0, min(len(results), limit))}
+	for _, result := range results[:min(len(results), limit)] {
+		output.Videos = append(output.Videos, result.Video)
+	}
+	if len(results) > limit {
+		last := results[limit-1]
+		output.NextCursor = encodeSearchCursor(last.Rank, last.Video.CreatedAt, last.Video.ID)
+	}
+
+	return output, nil
+}
+
+// createWithSlugs persists video under a fresh share slug and, if enabled, a title slug.
+// A share slug collision draws a new share slug; a title slug collision moves on to
+// the next candidate from titleSlugCandidate.
+func (s *videoService) createWithSlugs(ctx context.Context, video *model.Video) error {
+	var base string
+	if s.titleSlugs {
+		base = model.TitleSlug(video.Title)
+	}
+
+	shareAttempts, titleAttempts := 1, 1
+	video.ShareSlug = model.NewShareSlug()
+	video.TitleSlug = titleSlugCandidate(base, titleAttempts)
+	for {
+		err := s.repo.Create(ctx, video)
+		switch {
+		case errors.Is(err, repository.ErrDuplicateShareSlug) && shareAttempts < maxShareSlugAttempts:
+			shareAttempts++
+			video.ShareSlug = model.NewShareSlug()
+		case errors.Is(err, repository.ErrDuplicateTitleSlug) && titleAttempts < maxTitleSlugAttempts:
+			titleAttempts++
+			video.TitleSlug = titleSlugCandidate(base, titleAttempts)
+		default:
+			return err
+		}
+	}
+}
+
+// titleSlugCandidate returns the title slug to try on the given attempt: base itself,
+// then base with numeric suffixes, then base with random suffixes so users with many
+// identically titled videos still get a slug in a bounded number of attempts.
+func titleSlugCandidate(base string, attempt int) string {
+	switch {
+	case base == "":
+		return ""
+	case attempt == 1:
+		return base
+	case attempt <= maxNumberedTitleSlugs:
+		return model.TitleSlugWithSuffix(base, strconv.Itoa(attempt))
+	default:
+		return model.TitleSlugWithSuffix(base, model.NewShareSlug())
+	}
+}
+
+// issueUploadURL presigns an upload through the URL issuer, if one is configured.
+func (s *videoService) issueUploadURL(ctx context.Context, req URLRequest) (string, error) {
+	if s.urls == nil {
+		return s.storage.GeneratePresignedUploadURL(ctx, req.Key, req.Expiry)
+	}
+	return s.urls.IssueUploadURL(ctx, req)
+}
+
+// issuePartUploadURLs presigns part uploads through the URL issuer, if one is configured.
+func (s *videoService) issuePartUploadURLs(ctx context.Context, req URLRequest, uploadID string, parts int) ([]string, error) {
+	if s.urls != nil {
+		return s.urls.IssuePartUploadURLs(ctx, req, uploadID, parts)
+	}
+
+	urls := make([]string, parts)
+	for i := range urls {
+		url, err := s.storage.PresignPart(ctx, req.Key, uploadID, i+1, req.Expiry)
+		if err != nil {
+			return nil, err
+		}
+		urls[i] = url
+	}
+	return urls, nil
+}
+
+// issueDownloadURL presigns a download through the URL issuer, if one is configured.
+func (s *videoService) issueDownloadURL(ctx context.Context, req URLRequest) (string, error) {
+	if s.urls == nil {
+		return s.storage.GeneratePresignedDownloadURL(ctx, req.Key, req.Expiry)
+	}
+	return s.urls.IssueDownloadURL(ctx, req)
+}
+
+// admit consults the admission controller, if one is configured.
+func (s *videoService) admit(ctx context.Context, class WorkClass) error {
+	if s.admission == nil {
+		return nil
+	}
+	return s.admission.Admit(ctx, class)
+}
+
+// newTranscodeTask builds a task that writes to a newly allocated output version.
+func (s *videoService) newTranscodeTask(video *model.Video) repository.TranscodeTask {
+	return buildTranscodeTask(video, time.Now(), s.taskTTL)
+}
+
+// buildTranscodeTask builds a task enqueued at now that writes to a newly allocated
+// output version and expires taskTTL later; a zero taskTTL never expires.
+func buildTranscodeTask(video *model.Video, now time.Time, taskTTL time.Duration) repository.TranscodeTask {
+	version := nextOutputVersion(video.OutputVersion, now)
 
 	task := repository.TranscodeTask{
+		VideoID:       video.ID,
+		OriginalKey:   video.OriginalURL,
+		OutputKey:     generateHLSOutputKey(video.StoragePrefix(), version),
+		OutputVersion: version,
+		EnqueuedAt:    now,
+	}
+	if taskTTL > 0 {
+		task.ExpiresAt = now.Add(taskTTL)
+	}
+	if video.PreviewSeconds > 0 {
+		task.PreviewKey = generatePreviewOutputKey(video.StoragePrefix(), version)
+		task.PreviewSeconds = video.PreviewSeconds
+	}
+	return task
+}
+
+// newDeleteTask builds the cleanup task for every object the video may have stored.
+// The output prefixes span all versions, so superseded output is removed as well.
+func newDeleteTask(video *model.Video) repository.DeleteTask {
+	return repository.DeleteTask{
 		VideoID:     video.ID,
+		UserID:      video.UserID,
 		OriginalKey: video.OriginalURL,
-		OutputKey:   s.generateHLSOutputKey(video.ID),
+		OutputPrefixes: []string{
+			path.Join("hls", video.StoragePrefix()) + "/",
+			path.Join("previews", video.StoragePrefix()) + "/",
+			path.Join("subtitles", video.StoragePrefix()) + "/",
+			archiveKey(path.Join("hls", video.StoragePrefix()) + "/"),
+			ffmpegLogPrefix(video.ID),
+		},
+		ExternalOutput: video.ExternalOutput,
 	}
+}
 
-	if err := s.queue.PublishTranscodeTask(ctx, task); err != nil {
-		return fmt.Errorf("publish transcode task: %w", err)
+// abrProfilesOrDefault returns profiles, or the built-in ladder alone if it is nil.
+func abrProfilesOrDefault(profiles *transcoder.ABRProfiles) *transcoder.ABRProfiles {
+	if profiles == nil {
+		return transcoder.DefaultABRProfiles()
 	}
+	return profiles
+}
 
-	return nil
+// resolveLadder returns the ladder of the named ABR profile; an empty name selects the
+// default profile. Returns ErrUnknownABRProfile if the profile is not configured.
+func resolveLadder(profiles *transcoder.ABRProfiles, name string) ([]transcoder.Variant, error) {
+	ladder, err := profiles.Ladder(name)
+	if errors.Is(err, transcoder.ErrUnknownABRProfile) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownABRProfile, name)
+	}
+	return ladder, err
 }
 
-// GetVideo retrieves video information by ID.
-func (s *videoService) GetVideo(ctx context.Context, videoID uuid.UUID) (*model.Video, error) {
-	return s.repo.GetByID(ctx, videoID)
+// selectVariants returns the variants of ladder with the given names, in ladder order.
+// No names selects the whole ladder. Returns ErrUnknownVariant for a name outside the
+// ladder.
+func selectVariants(ladder []transcoder.Variant, names []string) ([]transcoder.Variant, error) {
+	if len(names) == 0 {
+		return ladder, nil
+	}
+
+	known := make(map[string]bool, len(ladder))
+	for _, v := range ladder {
+		known[v.Name] = true
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !known[name] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownVariant, name)
+		}
+		wanted[name] = true
+	}
+
+	var selected []transcoder.Variant
+	for _, v := range ladder {
+		if wanted[v.Name] {
+			selected = append(selected, v)
+		}
+	}
+	return selected, nil
+}
+
+// multipartPartSize returns the part size for an original of size bytes: defaultPartSize,
+// or the smallest whole MiB that fits the original into maxUploadParts parts.
+func multipartPartSize(size int64) int64 {
+	const mib = 1 << 20
+	partSize := max(defaultPartSize, (size+maxUploadParts-1)/maxUploadParts)
+	return (partSize + mib - 1) / mib * mib
+}
+
+// sortedUploadParts validates the parts of a multipart upload and returns them in
+// ascending part number order.
+func sortedUploadParts(parts []repository.CompletedPart) ([]repository.CompletedPart, error) {
+	if len(parts) == 0 || len(parts) > maxUploadParts {
+		return nil, ErrInvalidUploadParts
+	}
+
+	sorted := slices.Clone(parts)
+	slices.SortFunc(sorted, func(a, b repository.CompletedPart) int {
+		return cmp.Compare(a.PartNumber, b.PartNumber)
+	})
+	for i, p := range sorted {
+		if p.PartNumber < 1 || p.PartNumber > maxUploadParts || p.ETag == "" {
+			return nil, ErrInvalidUploadParts
+		}
+		if i > 0 && sorted[i-1].PartNumber == p.PartNumber {
+			return nil, ErrInvalidUploadParts
+		}
+	}
+	return sorted, nil
+}
+
+// nextOutputVersion allocates an output version newer than current.
+// Versions are enqueue timestamps (Unix milliseconds) so concurrent regenerations
+// get distinct prefixes without a shared counter; the max() guards against clock skew.
+func nextOutputVersion(current int64, now time.Time) int64 {
+	return max(now.UnixMilli(), current+1)
+}
+
+// deriveStorageKey returns the storage key of a new video: the first 128 bits of
+// HMAC-SHA256(secret, id), hex-encoded. Returns "" when no secret is configured, which
+// stores the video under its ID. The key is saved on the video, so rotating the secret
+// only affects videos created afterwards.
+func (s *videoService) deriveStorageKey(videoID uuid.UUID) string {
+	if len(s.storageSecret) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, s.storageSecret)
+	mac.Write(videoID[:])
+	return hex.EncodeToString(mac.Sum(nil)[:16])
 }
 
 // generateOriginalKey creates the storage key for original video files.
-// Format: originals/{video_id}/{filename}
-func (s *videoService) generateOriginalKey(videoID uuid.UUID, filename string) string {
-	return path.Join("originals", videoID.String(), filename)
+// Format: originals/{storage_prefix}/{filename}
+func (s *videoService) generateOriginalKey(storagePrefix, filename string) string {
+	return path.Join("originals", storagePrefix, filename)
+}
+
+// generateHLSOutputKey creates the storage key prefix for a version of the HLS output.
+// Each version gets its own prefix, so published objects are never overwritten and
+// CDN caches stay valid without invalidation.
+// Format: hls/{storage_prefix}/v{version}/
+func generateHLSOutputKey(storagePrefix string, version int64) string {
+	return path.Join("hls", storagePrefix, fmt.Sprintf("v%d", version)) + "/"
 }
 
-// generateHLSOutputKey creates the storage key prefix for HLS output.
-// Format: hls/{video_id}/
-func (s *videoService) generateHLSOutputKey(videoID uuid.UUID) string {
-	return path.Join("hls", videoID.String()) + "/"
+// generatePreviewOutputKey creates the storage key prefix for a version of the preview rendition.
+// Previews live outside hls/ so the CDN can serve them publicly while gating the full stream.
+// Format: previews/{storage_prefix}/v{version}/
+func generatePreviewOutputKey(storagePrefix string, version int64) string {
+	return path.Join("previews", storagePrefix, fmt.Sprintf("v%d", version)) + "/"
 }
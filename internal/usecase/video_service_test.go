@@ -3,6 +3,10 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -10,6 +14,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/hszk-dev/gostream/internal/domain/model"
 	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+	"github.com/hszk-dev/gostream/internal/transcoder"
 )
 
 func TestVideoService_CreateVideo(t *testing.T) {
@@ -51,6 +57,17 @@ func TestVideoService_CreateVideo(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "invalid preview duration",
+			input: CreateVideoInput{
+				UserID:         uuid.New(),
+				Title:          "Test Video",
+				FileName:       "video.mp4",
+				PreviewSeconds: model.MaxPreviewSeconds + 1,
+			},
+			setupMock: func(repo *mockVideoRepository, storage *mockObjectStorage) {},
+			wantErr:   model.ErrInvalidPreviewDuration,
+		},
 		{
 			name: "invalid user ID",
 			input: CreateVideoInput{
@@ -122,7 +139,7 @@ func TestVideoService_CreateVideo(t *testing.T) {
 
 			tt.setupMock(repo, storage)
 
-			svc := NewVideoService(repo, storage, queue, DefaultVideoServiceConfig())
+			svc := NewVideoService(repo, storage, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
 
 			output, err := svc.CreateVideo(context.Background(), tt.input)
 
@@ -147,266 +164,3086 @@ func TestVideoService_CreateVideo(t *testing.T) {
 	}
 }
 
-func TestVideoService_TriggerProcess(t *testing.T) {
+func TestVideoService_CreateVideo_ShareIDs(t *testing.T) {
 	tests := []struct {
-		name      string
-		videoID   uuid.UUID
-		setupMock func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video
-		wantErr   error
+		name       string
+		collisions int
+		strategy   model.IDStrategy
+		wantErr    error
+		wantLen    int
 	}{
 		{
-			name:    "successful trigger from pending upload",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
-				video := &model.Video{
-					ID:          uuid.New(),
-					UserID:      uuid.New(),
-					Title:       "Test Video",
-					Status:      model.StatusPendingUpload,
-					OriginalURL: "originals/video-id/video.mp4",
-					CreatedAt:   time.Now(),
-					UpdatedAt:   time.Now(),
-				}
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return video, nil
-				}
-				repo.updateFn = func(ctx context.Context, v *model.Video) error {
-					if v.Status != model.StatusProcessing {
-						t.Errorf("expected status %s, got %s", model.StatusProcessing, v.Status)
-					}
-					return nil
-				}
-				queue.publishTranscodeTaskFn = func(ctx context.Context, task repository.TranscodeTask) error {
-					if task.VideoID != video.ID {
-						t.Errorf("expected video ID %s, got %s", video.ID, task.VideoID)
-					}
-					if task.OriginalKey != video.OriginalURL {
-						t.Errorf("expected original key %s, got %s", video.OriginalURL, task.OriginalKey)
-					}
-					return nil
-				}
-				return video
-			},
-			wantErr: nil,
-		},
-		{
-			name:    "idempotent - already processing",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
-				video := &model.Video{
-					ID:          uuid.New(),
-					UserID:      uuid.New(),
-					Title:       "Test Video",
-					Status:      model.StatusProcessing,
-					OriginalURL: "originals/video-id/video.mp4",
-					CreatedAt:   time.Now(),
-					UpdatedAt:   time.Now(),
-				}
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return video, nil
-				}
-				return video
-			},
-			wantErr: nil,
-		},
-		{
-			name:    "error - already ready",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
-				video := &model.Video{
-					ID:        uuid.New(),
-					UserID:    uuid.New(),
-					Title:     "Test Video",
-					Status:    model.StatusReady,
-					HLSURL:    "hls/video-id/master.m3u8",
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-				}
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return video, nil
-				}
-				return video
-			},
-			wantErr: ErrVideoAlreadyCompleted,
+			name:     "assigns ULID and share slug",
+			strategy: model.IDStrategyULID,
+			wantLen:  26,
 		},
 		{
-			name:    "error - already failed",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
-				video := &model.Video{
-					ID:        uuid.New(),
-					UserID:    uuid.New(),
-					Title:     "Test Video",
-					Status:    model.StatusFailed,
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-				}
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return video, nil
-				}
-				return video
-			},
-			wantErr: ErrVideoAlreadyCompleted,
+			name:     "assigns KSUID",
+			strategy: model.IDStrategyKSUID,
+			wantLen:  27,
 		},
 		{
-			name:    "error - video not found",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return nil, repository.ErrVideoNotFound
-				}
-				return nil
-			},
-			wantErr: repository.ErrVideoNotFound,
+			name:       "retries share slug collisions",
+			collisions: 2,
+			strategy:   model.IDStrategyULID,
+			wantLen:    26,
 		},
 		{
-			name:    "error - repository update fails",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
-				video := &model.Video{
-					ID:          uuid.New(),
-					UserID:      uuid.New(),
-					Title:       "Test Video",
-					Status:      model.StatusPendingUpload,
-					OriginalURL: "originals/video-id/video.mp4",
-					CreatedAt:   time.Now(),
-					UpdatedAt:   time.Now(),
-				}
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return video, nil
-				}
-				repo.updateFn = func(ctx context.Context, v *model.Video) error {
-					return errors.New("database error")
-				}
-				return video
-			},
-			wantErr: errors.New("update video status"),
+			name:       "gives up after repeated collisions",
+			collisions: maxShareSlugAttempts,
+			strategy:   model.IDStrategyULID,
+			wantErr:    repository.ErrDuplicateShareSlug,
 		},
 		{
-			name:    "error - queue publish fails",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
-				video := &model.Video{
-					ID:          uuid.New(),
-					UserID:      uuid.New(),
-					Title:       "Test Video",
-					Status:      model.StatusPendingUpload,
-					OriginalURL: "originals/video-id/video.mp4",
-					CreatedAt:   time.Now(),
-					UpdatedAt:   time.Now(),
-				}
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return video, nil
-				}
-				repo.updateFn = func(ctx context.Context, v *model.Video) error {
-					return nil
-				}
-				queue.publishTranscodeTaskFn = func(ctx context.Context, task repository.TranscodeTask) error {
-					return errors.New("queue unavailable")
-				}
-				return video
-			},
-			wantErr: errors.New("publish transcode task"),
+			name:     "unknown strategy",
+			strategy: model.IDStrategy("snowflake"),
+			wantErr:  model.ErrUnknownIDStrategy,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo := &mockVideoRepository{}
-			storage := &mockObjectStorage{}
-			queue := &mockMessageQueue{}
-
-			tt.setupMock(repo, queue)
+			var slugs []string
+			repo := &mockVideoRepository{
+				createFn: func(ctx context.Context, video *model.Video) error {
+					slugs = append(slugs, video.ShareSlug)
+					if len(slugs) <= tt.collisions {
+						return repository.ErrDuplicateShareSlug
+					}
+					return nil
+				},
+			}
 
-			svc := NewVideoService(repo, storage, queue, DefaultVideoServiceConfig())
+			cfg := DefaultVideoServiceConfig()
+			cfg.IDStrategy = tt.strategy
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
 
-			err := svc.TriggerProcess(context.Background(), tt.videoID)
+			output, err := svc.CreateVideo(context.Background(), CreateVideoInput{
+				UserID:   uuid.New(),
+				Title:    "Test Video",
+				FileName: "video.mp4",
+			})
 
 			if tt.wantErr != nil {
-				if err == nil {
-					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
-				}
-				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
-					t.Fatalf("expected error containing %q, got %q", tt.wantErr, err)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
 				}
 				return
 			}
-
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
+
+			if len(output.Video.SortableID) != tt.wantLen {
+				t.Errorf("sortable ID %q: got length %d, expected %d", output.Video.SortableID, len(output.Video.SortableID), tt.wantLen)
+			}
+			if !model.IsValidShareSlug(output.Video.ShareSlug) {
+				t.Errorf("invalid share slug %q", output.Video.ShareSlug)
+			}
+			if len(slugs) != tt.collisions+1 {
+				t.Errorf("create attempts: got %d, expected %d", len(slugs), tt.collisions+1)
+			}
+			if output.Video.ShareSlug != slugs[len(slugs)-1] {
+				t.Errorf("share slug: got %q, expected the last attempted %q", output.Video.ShareSlug, slugs[len(slugs)-1])
+			}
 		})
 	}
 }
 
-func TestVideoService_GetVideo(t *testing.T) {
+func TestVideoService_CreateVideo_IdempotencyKey(t *testing.T) {
+	userID := uuid.New()
+	input := CreateVideoInput{
+		UserID:         userID,
+		Title:          "Test Video",
+		FileName:       "video.mp4",
+		IdempotencyKey: "create-7f3c",
+	}
+	other := input
+	other.Title = "Another Video"
+
+	stored := func(status model.Status) *model.Video {
+		return &model.Video{
+			ID:              uuid.New(),
+			UserID:          userID,
+			Title:           input.Title,
+			Status:          status,
+			OriginalURL:     "originals/video.mp4",
+			IdempotencyKey:  input.IdempotencyKey,
+			IdempotencyHash: createRequestHash(input),
+		}
+	}
+
 	tests := []struct {
-		name      string
-		videoID   uuid.UUID
-		setupMock func(repo *mockVideoRepository) *model.Video
-		wantErr   error
+		name string
+		// existing is the video created with the key before the request; winner is one
+		// created by a concurrent request between the lookup and the insert.
+		existing      *model.Video
+		winner        *model.Video
+		input         CreateVideoInput
+		wantErr       error
+		wantCreated   bool
+		wantReplayed  bool
+		wantUploadURL bool
 	}{
 		{
-			name:    "successful retrieval",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository) *model.Video {
-				video := &model.Video{
-					ID:        uuid.New(),
-					UserID:    uuid.New(),
-					Title:     "Test Video",
-					Status:    model.StatusReady,
-					HLSURL:    "hls/video-id/master.m3u8",
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-				}
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return video, nil
-				}
-				return video
-			},
-			wantErr: nil,
+			name:          "first request creates the video",
+			input:         input,
+			wantCreated:   true,
+			wantUploadURL: true,
 		},
 		{
-			name:    "video not found",
-			videoID: uuid.New(),
-			setupMock: func(repo *mockVideoRepository) *model.Video {
-				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
-					return nil, repository.ErrVideoNotFound
-				}
-				return nil
-			},
-			wantErr: repository.ErrVideoNotFound,
+			name:          "retry replays the video",
+			existing:      stored(model.StatusPendingUpload),
+			input:         input,
+			wantReplayed:  true,
+			wantUploadURL: true,
+		},
+		{
+			name:         "retry after the upload has no upload URL",
+			existing:     stored(model.StatusUploaded),
+			input:        input,
+			wantReplayed: true,
+		},
+		{
+			name:     "key reused for another request",
+			existing: stored(model.StatusPendingUpload),
+			input:    other,
+			wantErr:  ErrIdempotencyKeyReused,
+		},
+		{
+			name:          "concurrent retry wins the insert",
+			winner:        stored(model.StatusPendingUpload),
+			input:         input,
+			wantReplayed:  true,
+			wantUploadURL: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo := &mockVideoRepository{}
-			storage := &mockObjectStorage{}
-			queue := &mockMessageQueue{}
-
-			expectedVideo := tt.setupMock(repo)
-
-			svc := NewVideoService(repo, storage, queue, DefaultVideoServiceConfig())
+			existing := tt.existing
+			var created *model.Video
+			repo := &mockVideoRepository{
+				getByIdempotencyKeyFn: func(ctx context.Context, id uuid.UUID, key string) (*model.Video, error) {
+					if id != userID || key != input.IdempotencyKey || existing == nil {
+						return nil, repository.ErrVideoNotFound
+					}
+					return existing, nil
+				},
+				createFn: func(ctx context.Context, video *model.Video) error {
+					if tt.winner != nil {
+						existing = tt.winner
+						return repository.ErrDuplicateIdempotencyKey
+					}
+					created = video
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				generatePresignedUploadURLFn: func(ctx context.Context, key string, expiry time.Duration) (string, error) {
+					return "http://minio:9000/bucket/" + key + "?signature=xyz", nil
+				},
+			}
+			svc := NewVideoService(repo, storage, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
 
-			video, err := svc.GetVideo(context.Background(), tt.videoID)
+			output, err := svc.CreateVideo(context.Background(), tt.input)
 
 			if tt.wantErr != nil {
-				if err == nil {
-					t.Fatalf("expected error %v, got nil", tt.wantErr)
-				}
 				if !errors.Is(err, tt.wantErr) {
 					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
 				}
 				return
 			}
-
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			if video.ID != expectedVideo.ID {
-				t.Errorf("expected video ID %s, got %s", expectedVideo.ID, video.ID)
+			if (created != nil) != tt.wantCreated {
+				t.Errorf("video created: got %v, expected %v", created != nil, tt.wantCreated)
+			}
+			if created != nil && (created.IdempotencyKey != input.IdempotencyKey || created.IdempotencyHash != createRequestHash(input)) {
+				t.Errorf("created with key %q and hash %q", created.IdempotencyKey, created.IdempotencyHash)
+			}
+			if output.Replayed != tt.wantReplayed {
+				t.Errorf("replayed: got %v, expected %v", output.Replayed, tt.wantReplayed)
+			}
+			if tt.wantReplayed && output.Video != existing {
+				t.Errorf("replayed %+v, expected the stored video", output.Video)
+			}
+			if (output.UploadURL != "") != tt.wantUploadURL {
+				t.Errorf("upload URL %q, expected one: %v", output.UploadURL, tt.wantUploadURL)
+			}
+		})
+	}
+}
+
+func TestCreateRequestHash(t *testing.T) {
+	base := CreateVideoInput{UserID: uuid.New(), Title: "Test Video", FileName: "video.mp4"}
+	expiring := base
+	expiring.ExpiresAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("JST", 9*60*60))
+	sameInstant := base
+	sameInstant.ExpiresAt = expiring.ExpiresAt.UTC()
+	// Without length prefixes, the title and file name would run together the same way
+	shifted := base
+	shifted.Title, shifted.FileName = "Test Videov", "ideo.mp4"
+
+	if createRequestHash(expiring) != createRequestHash(sameInstant) {
+		t.Error("expiry in another time zone changed the hash")
+	}
+	for name, input := range map[string]CreateVideoInput{"expiring": expiring, "shifted": shifted} {
+		if createRequestHash(input) == createRequestHash(base) {
+			t.Errorf("%s request hashes like the base request", name)
+		}
+	}
+}
+
+func TestVideoService_CreateVideo_TitleSlugs(t *testing.T) {
+	tests := []struct {
+		name       string
+		disabled   bool
+		title      string
+		collisions int
+		wantSlugs  []string // attempted title slugs; "*" matches a random suffix
+		wantErr    error
+	}{
+		{
+			name:      "derives slug from title",
+			title:     "My First Video!",
+			wantSlugs: []string{"my-first-video"},
+		},
+		{
+			name:      "disabled",
+			disabled:  true,
+			title:     "My First Video!",
+			wantSlugs: []string{""},
+		},
+		{
+			name:      "title without letters or digits",
+			title:     "!!!",
+			wantSlugs: []string{""},
+		},
+		{
+			name:       "numbers collisions",
+			title:      "My Video",
+			collisions: 2,
+			wantSlugs:  []string{"my-video", "my-video-2", "my-video-3"},
+		},
+		{
+			name:       "falls back to random suffixes",
+			title:      "My Video",
+			collisions: maxNumberedTitleSlugs,
+			wantSlugs: []string{
+				"my-video", "my-video-2", "my-video-3", "my-video-4", "my-video-5",
+				"my-video-6", "my-video-7", "my-video-8", "my-video-9", "my-video-10", "*",
+			},
+		},
+		{
+			name:       "gives up after repeated collisions",
+			title:      "My Video",
+			collisions: maxTitleSlugAttempts,
+			wantErr:    repository.ErrDuplicateTitleSlug,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var slugs []string
+			repo := &mockVideoRepository{
+				createFn: func(ctx context.Context, video *model.Video) error {
+					slugs = append(slugs, video.TitleSlug)
+					if len(slugs) <= tt.collisions {
+						return repository.ErrDuplicateTitleSlug
+					}
+					return nil
+				},
+			}
+
+			cfg := DefaultVideoServiceConfig()
+			cfg.TitleSlugs = !tt.disabled
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+			output, err := svc.CreateVideo(context.Background(), CreateVideoInput{
+				UserID:   uuid.New(),
+				Title:    tt.title,
+				FileName: "video.mp4",
+			})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if len(slugs) != maxTitleSlugAttempts {
+					t.Errorf("create attempts: got %d, expected %d", len(slugs), maxTitleSlugAttempts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(slugs) != len(tt.wantSlugs) {
+				t.Fatalf("attempted slugs: got %q, expected %q", slugs, tt.wantSlugs)
+			}
+			for i, want := range tt.wantSlugs {
+				if want == "*" {
+					if !strings.HasPrefix(slugs[i], "my-video-") || !model.IsValidTitleSlug(slugs[i]) {
+						t.Errorf("attempt %d: got %q, expected a random suffix", i+1, slugs[i])
+					}
+					continue
+				}
+				if slugs[i] != want {
+					t.Errorf("attempt %d: got %q, expected %q", i+1, slugs[i], want)
+				}
+			}
+			if output.Video.TitleSlug != slugs[len(slugs)-1] {
+				t.Errorf("title slug: got %q, expected the last attempted %q", output.Video.TitleSlug, slugs[len(slugs)-1])
+			}
+		})
+	}
+}
+
+func TestVideoService_CreateVideo_StorageKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  []byte
+		wantKey bool
+	}{
+		{name: "derived from secret", secret: []byte("0123456789abcdef0123456789abcdef"), wantKey: true},
+		{name: "no secret - stored under ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *model.Video
+			repo := &mockVideoRepository{
+				createFn: func(ctx context.Context, video *model.Video) error {
+					created = video
+					return nil
+				},
+			}
+			cfg := DefaultVideoServiceConfig()
+			cfg.StorageKeySecret = tt.secret
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+			output, err := svc.CreateVideo(context.Background(), CreateVideoInput{
+				UserID:   uuid.New(),
+				Title:    "Test Video",
+				FileName: "video.mp4",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			video := output.Video
+			if created == nil || created.StorageKey != video.StorageKey {
+				t.Fatal("storage key was not persisted with the video")
+			}
+			if !tt.wantKey {
+				if video.StorageKey != "" {
+					t.Errorf("StorageKey = %q, want empty", video.StorageKey)
+				}
+				if want := "originals/" + video.ID.String() + "/video.mp4"; video.OriginalURL != want {
+					t.Errorf("OriginalURL = %q, want %q", video.OriginalURL, want)
+				}
+				return
+			}
+
+			if len(video.StorageKey) != 32 {
+				t.Errorf("StorageKey %q: got length %d, want 32", video.StorageKey, len(video.StorageKey))
+			}
+			if strings.Contains(video.OriginalURL, video.ID.String()) {
+				t.Errorf("OriginalURL %q contains the video ID", video.OriginalURL)
+			}
+			if want := "originals/" + video.StorageKey + "/video.mp4"; video.OriginalURL != want {
+				t.Errorf("OriginalURL = %q, want %q", video.OriginalURL, want)
+			}
+			if again := svc.(*videoService).deriveStorageKey(video.ID); again != video.StorageKey {
+				t.Errorf("derivation is not deterministic: %q, then %q", video.StorageKey, again)
+			}
+		})
+	}
+}
+
+func TestVideoService_StorageKeyPaths(t *testing.T) {
+	video := &model.Video{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Status:         model.StatusReady,
+		OriginalURL:    "originals/9f86d081884c7d659a2feaa0c55ad015/video.mp4",
+		PreviewSeconds: 30,
+		StorageKey:     "9f86d081884c7d659a2feaa0c55ad015",
+	}
+	svc := NewVideoService(&mockVideoRepository{}, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig()).(*videoService)
+
+	task := svc.newTranscodeTask(video)
+	version := fmt.Sprintf("v%d/", task.OutputVersion)
+	if want := "hls/9f86d081884c7d659a2feaa0c55ad015/" + version; task.OutputKey != want {
+		t.Errorf("OutputKey = %q, want %q", task.OutputKey, want)
+	}
+	if want := "previews/9f86d081884c7d659a2feaa0c55ad015/" + version; task.PreviewKey != want {
+		t.Errorf("PreviewKey = %q, want %q", task.PreviewKey, want)
+	}
+
+	deleteTask := newDeleteTask(video)
+	want := []string{
+		"hls/9f86d081884c7d659a2feaa0c55ad015/",
+		"previews/9f86d081884c7d659a2feaa0c55ad015/",
+		"subtitles/9f86d081884c7d659a2feaa0c55ad015/",
+		"archive/hls/9f86d081884c7d659a2feaa0c55ad015/",
+		"logs/" + video.ID.String() + "/",
+	}
+	if !reflect.DeepEqual(deleteTask.OutputPrefixes, want) {
+		t.Errorf("OutputPrefixes = %v, want %v", deleteTask.OutputPrefixes, want)
+	}
+}
+
+func TestVideoService_CreateVideo_URLIssuer(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		issueErr error
+		wantErr  error
+	}{
+		{name: "issues the upload URL for the owner"},
+		{
+			name:     "rate limited",
+			issueErr: &URLRateLimitedError{Limit: 60, Window: time.Hour, RetryAfter: time.Minute},
+			wantErr:  ErrURLRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created bool
+			repo := &mockVideoRepository{
+				createFn: func(ctx context.Context, video *model.Video) error {
+					created = true
+					return nil
+				},
+			}
+
+			var req URLRequest
+			urls := &mockURLIssuer{
+				issueUploadURLFn: func(ctx context.Context, r URLRequest) (string, error) {
+					req = r
+					if tt.issueErr != nil {
+						return "", tt.issueErr
+					}
+					return "https://minio/upload?sig=abc", nil
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, urls, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			output, err := svc.CreateVideo(context.Background(), CreateVideoInput{
+				UserID:   userID,
+				Title:    "Test Video",
+				FileName: "video.mp4",
+			})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if created {
+					t.Error("video must not be created without an upload URL")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if output.UploadURL != "https://minio/upload?sig=abc" {
+				t.Errorf("upload URL: got %q", output.UploadURL)
+			}
+			if req.Purpose != model.URLPurposeUpload || req.RequesterID != userID || req.VideoID != output.Video.ID {
+				t.Errorf("URL request: got %+v", req)
+			}
+			if req.Key != output.Video.OriginalURL || req.Expiry != DefaultVideoServiceConfig().UploadURLExpiry {
+				t.Errorf("URL request key/expiry: got %s/%v", req.Key, req.Expiry)
+			}
+		})
+	}
+}
+
+func TestVideoService_ResolveShareSlug(t *testing.T) {
+	video := &model.Video{ID: uuid.New(), ShareSlug: "a1b2c3d4"}
+
+	tests := []struct {
+		name      string
+		slug      string
+		wantErr   error
+		wantQuery bool
+	}{
+		{
+			name:      "known slug",
+			slug:      "a1b2c3d4",
+			wantQuery: true,
+		},
+		{
+			name:      "unknown slug",
+			slug:      "zzzzzzzz",
+			wantErr:   repository.ErrVideoNotFound,
+			wantQuery: true,
+		},
+		{
+			name:    "malformed slug skips the database",
+			slug:    "../admin",
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queried bool
+			repo := &mockVideoRepository{
+				getByShareSlugFn: func(ctx context.Context, slug string) (*model.Video, error) {
+					queried = true
+					if slug == video.ShareSlug {
+						return video, nil
+					}
+					return nil, repository.ErrVideoNotFound
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.ResolveShareSlug(context.Background(), tt.slug)
+
+			if queried != tt.wantQuery {
+				t.Errorf("queried repository: got %v, expected %v", queried, tt.wantQuery)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ID != video.ID {
+				t.Errorf("video ID: got %s, expected %s", got.ID, video.ID)
+			}
+		})
+	}
+}
+
+func TestVideoService_ResolveTitleSlug(t *testing.T) {
+	userID := uuid.New()
+	video := &model.Video{ID: uuid.New(), UserID: userID, TitleSlug: "my-video"}
+
+	tests := []struct {
+		name      string
+		slug      string
+		wantErr   error
+		wantQuery bool
+	}{
+		{
+			name:      "known slug",
+			slug:      "my-video",
+			wantQuery: true,
+		},
+		{
+			name:      "unknown slug",
+			slug:      "other-video",
+			wantErr:   repository.ErrVideoNotFound,
+			wantQuery: true,
+		},
+		{
+			name:    "malformed slug skips the database",
+			slug:    "My Video",
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queried bool
+			repo := &mockVideoRepository{
+				getByTitleSlugFn: func(ctx context.Context, id uuid.UUID, slug string) (*model.Video, error) {
+					queried = true
+					if id == userID && slug == video.TitleSlug {
+						return video, nil
+					}
+					return nil, repository.ErrVideoNotFound
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.ResolveTitleSlug(context.Background(), userID, tt.slug)
+
+			if queried != tt.wantQuery {
+				t.Errorf("queried repository: got %v, expected %v", queried, tt.wantQuery)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ID != video.ID {
+				t.Errorf("video ID: got %s, expected %s", got.ID, video.ID)
+			}
+		})
+	}
+}
+
+func TestVideoService_TriggerProcess_Preview(t *testing.T) {
+	video := &model.Video{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Title:          "Test Video",
+		Status:         model.StatusUploaded,
+		OriginalURL:    "originals/video-id/video.mp4",
+		PreviewSeconds: 60,
+	}
+
+	repo := &mockVideoRepository{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+			return video, nil
+		},
+	}
+
+	var published repository.TranscodeTask
+	queue := &mockMessageQueue{
+		publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+			published = task
+			return nil
+		},
+	}
+
+	svc := NewVideoService(repo, &mockObjectStorage{}, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+	if err := svc.TriggerProcess(context.Background(), video.ID, ProcessInput{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if published.OutputVersion <= 0 {
+		t.Fatalf("output version: got %d, expected a positive version", published.OutputVersion)
+	}
+	expectedKey := fmt.Sprintf("previews/%s/v%d/", video.ID, published.OutputVersion)
+	if published.PreviewKey != expectedKey {
+		t.Errorf("preview key: got %q, expected %q", published.PreviewKey, expectedKey)
+	}
+	if published.PreviewSeconds != 60 {
+		t.Errorf("preview seconds: got %d, expected 60", published.PreviewSeconds)
+	}
+}
+
+func TestVideoService_TriggerProcess_ABRProfile(t *testing.T) {
+	profiles, err := transcoder.ParseABRProfiles(`{"screen": [{"name": "1080p", "height": 1080, "bitrate": 2000000}]}`, "")
+	if err != nil {
+		t.Fatalf("parse profiles: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		profile     string
+		wantErr     error
+		wantProfile string
+	}{
+		{name: "default profile", profile: ""},
+		{name: "configured profile", profile: "screen", wantProfile: "screen"},
+		{name: "unknown profile", profile: "film", wantErr: ErrUnknownABRProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Status:      model.StatusUploaded,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			var published *repository.TranscodeTask
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					published = &task
+					return nil
+				},
+			}
+
+			cfg := DefaultVideoServiceConfig()
+			cfg.ABRProfiles = profiles
+			svc := NewVideoService(repo, &mockObjectStorage{}, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+			err := svc.TriggerProcess(context.Background(), video.ID, ProcessInput{ABRProfile: tt.profile})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error: got %v, expected %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if published != nil || video.Status != model.StatusUploaded {
+					t.Error("an unknown profile must leave the video untouched")
+				}
+				return
+			}
+			if published == nil {
+				t.Fatal("expected a transcode task to be published")
+			}
+			if published.ABRProfile != tt.wantProfile {
+				t.Errorf("ABR profile: got %q, expected %q", published.ABRProfile, tt.wantProfile)
+			}
+		})
+	}
+}
+
+func TestVideoService_TriggerProcess_TaskTTL(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{name: "expiry from TTL", ttl: 6 * time.Hour},
+		{name: "no TTL - never expires"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Title:       "Test Video",
+				Status:      model.StatusUploaded,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			var published repository.TranscodeTask
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					published = task
+					return nil
+				},
+			}
+
+			cfg := DefaultVideoServiceConfig()
+			cfg.TaskTTL = tt.ttl
+			svc := NewVideoService(repo, &mockObjectStorage{}, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+			if err := svc.TriggerProcess(context.Background(), video.ID, ProcessInput{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var want time.Time
+			if tt.ttl > 0 {
+				want = published.EnqueuedAt.Add(tt.ttl)
+			}
+			if !published.ExpiresAt.Equal(want) {
+				t.Errorf("ExpiresAt = %v, want %v", published.ExpiresAt, want)
+			}
+		})
+	}
+}
+
+func TestVideoService_PublishesStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status model.Status
+		call   func(svc VideoService, id uuid.UUID) error
+		want   model.Status
+	}{
+		{
+			name:   "trigger process",
+			status: model.StatusUploaded,
+			call: func(svc VideoService, id uuid.UUID) error {
+				return svc.TriggerProcess(context.Background(), id, ProcessInput{})
+			},
+			want: model.StatusProcessing,
+		},
+		{
+			name:   "delete",
+			status: model.StatusReady,
+			call: func(svc VideoService, id uuid.UUID) error {
+				_, err := svc.DeleteVideo(context.Background(), id)
+				return err
+			},
+			want: model.StatusDeleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{ID: uuid.New(), UserID: uuid.New(), Title: "Test Video", Status: tt.status}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			var published []cache.VideoEvent
+			events := &mockVideoEventBus{
+				publishFn: func(ctx context.Context, event cache.VideoEvent) error {
+					published = append(published, event)
+					return errors.New("redis down") // must not fail the request
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, events, nil, nil, nil, DefaultVideoServiceConfig())
+			if err := tt.call(svc, video.ID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := []cache.VideoEvent{{Type: cache.VideoEventStatus, VideoID: video.ID, Status: tt.want}}
+			if !reflect.DeepEqual(published, want) {
+				t.Errorf("published events: got %v, expected %v", published, want)
+			}
+		})
+	}
+}
+
+func TestVideoService_CompleteUpload(t *testing.T) {
+	storageErr := errors.New("storage unavailable")
+
+	tests := []struct {
+		name        string
+		status      model.Status
+		info        *repository.ObjectInfo
+		statErr     error
+		wantErr     error
+		wantStatus  model.Status
+		wantUpdated bool
+	}{
+		{
+			name:        "records object and marks uploaded",
+			status:      model.StatusPendingUpload,
+			info:        &repository.ObjectInfo{Size: 1024, ETag: "abc123"},
+			wantStatus:  model.StatusUploaded,
+			wantUpdated: true,
+		},
+		{
+			name:    "object missing",
+			status:  model.StatusPendingUpload,
+			statErr: repository.ErrObjectNotFound,
+			wantErr: ErrUploadMissing,
+		},
+		{
+			name:    "object empty",
+			status:  model.StatusPendingUpload,
+			info:    &repository.ObjectInfo{Size: 0},
+			wantErr: ErrUploadMissing,
+		},
+		{
+			name:    "storage error",
+			status:  model.StatusPendingUpload,
+			statErr: storageErr,
+			wantErr: storageErr,
+		},
+		{
+			name:       "idempotent - already uploaded",
+			status:     model.StatusUploaded,
+			wantStatus: model.StatusUploaded,
+		},
+		{
+			name:       "idempotent - already processing",
+			status:     model.StatusProcessing,
+			wantStatus: model.StatusProcessing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Title:       "Test Video",
+				Status:      tt.status,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+
+			updated := false
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					updated = true
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+					if key != video.OriginalURL {
+						t.Errorf("stat key: got %q, expected %q", key, video.OriginalURL)
+					}
+					return tt.info, tt.statErr
+				},
+			}
+
+			svc := NewVideoService(repo, storage, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.CompleteUpload(context.Background(), video.ID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if updated {
+					t.Error("video must not be updated on failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Status != tt.wantStatus {
+				t.Errorf("status: got %s, expected %s", got.Status, tt.wantStatus)
+			}
+			if updated != tt.wantUpdated {
+				t.Errorf("updated: got %v, expected %v", updated, tt.wantUpdated)
+			}
+			if tt.info != nil && (got.OriginalSize != tt.info.Size || got.OriginalETag != tt.info.ETag) {
+				t.Errorf("original object: got %d/%q, expected %d/%q", got.OriginalSize, got.OriginalETag, tt.info.Size, tt.info.ETag)
+			}
+		})
+	}
+}
+
+func TestVideoService_UploadVideo(t *testing.T) {
+	const maxBytes = 16
+	createErr := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		content     string
+		process     bool
+		abrProfile  string
+		maxBytes    int64
+		createErr   error
+		publishErr  error
+		wantErr     error
+		wantStatus  model.Status
+		wantDeleted bool
+	}{
+		{
+			name:       "stores original and marks uploaded",
+			content:    "tiny clip",
+			maxBytes:   maxBytes,
+			wantStatus: model.StatusUploaded,
+		},
+		{
+			name:       "starts processing",
+			content:    "tiny clip",
+			process:    true,
+			maxBytes:   maxBytes,
+			wantStatus: model.StatusProcessing,
+		},
+		{
+			name:       "failed processing start still creates the video",
+			content:    "tiny clip",
+			process:    true,
+			maxBytes:   maxBytes,
+			publishErr: errors.New("queue unavailable"),
+			wantStatus: model.StatusUploaded,
+		},
+		{
+			name:       "file at the limit",
+			content:    strings.Repeat("x", maxBytes),
+			maxBytes:   maxBytes,
+			wantStatus: model.StatusUploaded,
+		},
+		{
+			name:        "file over the limit is removed",
+			content:     strings.Repeat("x", maxBytes+10),
+			maxBytes:    maxBytes,
+			wantErr:     ErrInlineUploadTooLarge,
+			wantDeleted: true,
+		},
+		{
+			name:        "empty file is removed",
+			maxBytes:    maxBytes,
+			wantErr:     ErrUploadMissing,
+			wantDeleted: true,
+		},
+		{
+			name:        "create failure removes the original",
+			content:     "tiny clip",
+			maxBytes:    maxBytes,
+			createErr:   createErr,
+			wantErr:     createErr,
+			wantDeleted: true,
+		},
+		{
+			name:       "unknown ABR profile uploads nothing",
+			content:    "tiny clip",
+			process:    true,
+			abrProfile: "missing",
+			maxBytes:   maxBytes,
+			wantErr:    ErrUnknownABRProfile,
+		},
+		{
+			name:    "disabled",
+			content: "tiny clip",
+			wantErr: ErrInlineUploadDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *model.Video
+			repo := &mockVideoRepository{
+				createFn: func(ctx context.Context, v *model.Video) error {
+					created = v
+					return tt.createErr
+				},
+			}
+			var stored []byte
+			var deleted bool
+			storage := &mockObjectStorage{
+				uploadFn: func(ctx context.Context, key string, reader io.Reader, contentType string) error {
+					stored, _ = io.ReadAll(reader)
+					if contentType != "video/mp4" {
+						t.Errorf("content type: got %q, expected video/mp4", contentType)
+					}
+					return nil
+				},
+				statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+					return &repository.ObjectInfo{Key: key, Size: int64(len(stored)), ETag: "etag"}, nil
+				},
+				deleteFn: func(ctx context.Context, key string) error {
+					deleted = true
+					return nil
+				},
+			}
+			var published bool
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					published = true
+					return tt.publishErr
+				},
+			}
+
+			cfg := DefaultVideoServiceConfig()
+			cfg.InlineUploadMaxBytes = tt.maxBytes
+			svc := NewVideoService(repo, storage, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+			got, err := svc.UploadVideo(context.Background(), UploadVideoInput{
+				CreateVideoInput: CreateVideoInput{UserID: uuid.New(), Title: "Clip", FileName: "clip.mp4"},
+				Content:          strings.NewReader(tt.content),
+				ContentType:      "video/mp4",
+				Process:          tt.process,
+				ABRProfile:       tt.abrProfile,
+			})
+
+			if deleted != tt.wantDeleted {
+				t.Errorf("original deleted: got %v, expected %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Status != tt.wantStatus {
+				t.Errorf("status: got %s, expected %s", got.Status, tt.wantStatus)
+			}
+			if created == nil || created.Status != model.StatusUploaded || created.OriginalSize != int64(len(tt.content)) {
+				t.Errorf("created video: %+v", created)
+			}
+			if published != tt.process {
+				t.Errorf("task published: got %v, expected %v", published, tt.process)
+			}
+		})
+	}
+}
+
+func TestVideoService_InitiateMultipartUpload(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        model.Status
+		size          int64
+		wantErr       error
+		wantPartSize  int64
+		wantPartCount int
+	}{
+		{
+			name:          "default part size",
+			status:        model.StatusPendingUpload,
+			size:          4<<30 + 1,
+			wantPartSize:  64 << 20,
+			wantPartCount: 65,
+		},
+		{
+			name:          "small original is one part",
+			status:        model.StatusPendingUpload,
+			size:          1 << 20,
+			wantPartSize:  64 << 20,
+			wantPartCount: 1,
+		},
+		{
+			name:    "video already uploaded",
+			status:  model.StatusUploaded,
+			size:    1 << 30,
+			wantErr: ErrUploadClosed,
+		},
+		{
+			name:    "zero size",
+			status:  model.StatusPendingUpload,
+			wantErr: ErrInvalidUploadSize,
+		},
+		{
+			name:    "larger than S3 allows",
+			status:  model.StatusPendingUpload,
+			size:    MaxMultipartUploadSize + 1,
+			wantErr: ErrInvalidUploadSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Status:      tt.status,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			storage := &mockObjectStorage{
+				initiateMultipartUploadFn: func(ctx context.Context, key, contentType string) (string, error) {
+					if key != video.OriginalURL {
+						t.Errorf("upload key: got %q, expected %q", key, video.OriginalURL)
+					}
+					return "upload-1", nil
+				},
+			}
+			var issued URLRequest
+			urls := &mockURLIssuer{
+				issuePartUploadURLsFn: func(ctx context.Context, req URLRequest, uploadID string, parts int) ([]string, error) {
+					issued = req
+					return make([]string, parts), nil
+				},
+			}
+
+			svc := NewVideoService(repo, storage, &mockMessageQueue{}, nil, nil, nil, urls, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.InitiateMultipartUpload(context.Background(), video.ID, tt.size)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.UploadID != "upload-1" || got.PartSize != tt.wantPartSize || len(got.PartURLs) != tt.wantPartCount {
+				t.Errorf("upload: got %s/%d/%d parts, expected upload-1/%d/%d parts", got.UploadID, got.PartSize, len(got.PartURLs), tt.wantPartSize, tt.wantPartCount)
+			}
+			if issued.RequesterID != video.UserID || issued.Expiry != DefaultVideoServiceConfig().MultipartURLExpiry {
+				t.Errorf("issued part URLs with %+v", issued)
+			}
+		})
+	}
+}
+
+func TestMultipartPartSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want int64
+	}{
+		{size: 1, want: 64 << 20},
+		{size: 10 << 30, want: 64 << 20},
+		// 1 TiB needs 105 MiB parts to stay within 10000 parts
+		{size: 1 << 40, want: 105 << 20},
+	}
+
+	for _, tt := range tests {
+		got := multipartPartSize(tt.size)
+		if got != tt.want {
+			t.Errorf("multipartPartSize(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+		if parts := (tt.size + got - 1) / got; parts > maxUploadParts {
+			t.Errorf("multipartPartSize(%d) needs %d parts", tt.size, parts)
+		}
+	}
+}
+
+func TestVideoService_CompleteMultipartUpload(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        model.Status
+		parts         []repository.CompletedPart
+		completeErr   error
+		wantErr       error
+		wantCompleted bool
+		wantStatus    model.Status
+	}{
+		{
+			name:          "parts are sorted and the upload confirmed",
+			status:        model.StatusPendingUpload,
+			parts:         []repository.CompletedPart{{PartNumber: 2, ETag: "b"}, {PartNumber: 1, ETag: "a"}},
+			wantCompleted: true,
+			wantStatus:    model.StatusUploaded,
+		},
+		{
+			name:    "duplicate part numbers",
+			status:  model.StatusPendingUpload,
+			parts:   []repository.CompletedPart{{PartNumber: 1, ETag: "a"}, {PartNumber: 1, ETag: "b"}},
+			wantErr: ErrInvalidUploadParts,
+		},
+		{
+			name:    "missing ETag",
+			status:  model.StatusPendingUpload,
+			parts:   []repository.CompletedPart{{PartNumber: 1}},
+			wantErr: ErrInvalidUploadParts,
+		},
+		{
+			name:    "no parts",
+			status:  model.StatusPendingUpload,
+			wantErr: ErrInvalidUploadParts,
+		},
+		{
+			name:          "unknown upload",
+			status:        model.StatusPendingUpload,
+			parts:         []repository.CompletedPart{{PartNumber: 1, ETag: "a"}},
+			completeErr:   repository.ErrMultipartUploadNotFound,
+			wantErr:       repository.ErrMultipartUploadNotFound,
+			wantCompleted: true,
+		},
+		{
+			name:       "idempotent - already uploaded",
+			status:     model.StatusUploaded,
+			parts:      []repository.CompletedPart{{PartNumber: 1, ETag: "a"}},
+			wantStatus: model.StatusUploaded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Status:      tt.status,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			completed := false
+			storage := &mockObjectStorage{
+				completeMultipartUploadFn: func(ctx context.Context, key, uploadID string, parts []repository.CompletedPart) error {
+					completed = true
+					for i, p := range parts {
+						if p.PartNumber != i+1 {
+							t.Errorf("parts not in order: %+v", parts)
+						}
+					}
+					return tt.completeErr
+				},
+				statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+					return &repository.ObjectInfo{Key: key, Size: 8 << 30, ETag: "abc-2"}, nil
+				},
+			}
+
+			svc := NewVideoService(repo, storage, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.CompleteMultipartUpload(context.Background(), video.ID, "upload-1", tt.parts)
+
+			if completed != tt.wantCompleted {
+				t.Errorf("completed: got %v, expected %v", completed, tt.wantCompleted)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status: got %s, expected %s", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestVideoService_TriggerProcess(t *testing.T) {
+	tests := []struct {
+		name      string
+		videoID   uuid.UUID
+		setupMock func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video
+		wantErr   error
+	}{
+		{
+			name:    "successful trigger from pending upload",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:          uuid.New(),
+					UserID:      uuid.New(),
+					Title:       "Test Video",
+					Status:      model.StatusPendingUpload,
+					OriginalURL: "originals/video-id/video.mp4",
+					CreatedAt:   time.Now(),
+					UpdatedAt:   time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				// The upload is confirmed before the video moves to PROCESSING
+				var statuses []model.Status
+				repo.updateFn = func(ctx context.Context, v *model.Video) error {
+					statuses = append(statuses, v.Status)
+					return nil
+				}
+				queue.publishTranscodeTaskFn = func(ctx context.Context, task repository.TranscodeTask) error {
+					if !reflect.DeepEqual(statuses, []model.Status{model.StatusUploaded, model.StatusProcessing}) {
+						t.Errorf("statuses: got %v, expected UPLOADED then PROCESSING", statuses)
+					}
+					if task.VideoID != video.ID {
+						t.Errorf("expected video ID %s, got %s", video.ID, task.VideoID)
+					}
+					if task.OriginalKey != video.OriginalURL {
+						t.Errorf("expected original key %s, got %s", video.OriginalURL, task.OriginalKey)
+					}
+					return nil
+				}
+				return video
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "error - pending upload without original",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:          uuid.New(),
+					UserID:      uuid.New(),
+					Title:       "Test Video",
+					Status:      model.StatusPendingUpload,
+					OriginalURL: "originals/video-id/missing.mp4",
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				repo.updateFn = func(ctx context.Context, v *model.Video) error {
+					t.Errorf("unexpected update to %s", v.Status)
+					return nil
+				}
+				return video
+			},
+			wantErr: ErrUploadMissing,
+		},
+		{
+			name:    "successful trigger from uploaded",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:          uuid.New(),
+					UserID:      uuid.New(),
+					Title:       "Test Video",
+					Status:      model.StatusUploaded,
+					OriginalURL: "originals/video-id/video.mp4",
+					CreatedAt:   time.Now(),
+					UpdatedAt:   time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				repo.updateFn = func(ctx context.Context, v *model.Video) error {
+					if v.Status != model.StatusProcessing {
+						t.Errorf("expected status %s, got %s", model.StatusProcessing, v.Status)
+					}
+					return nil
+				}
+				return video
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "idempotent - already processing",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:          uuid.New(),
+					UserID:      uuid.New(),
+					Title:       "Test Video",
+					Status:      model.StatusProcessing,
+					OriginalURL: "originals/video-id/video.mp4",
+					CreatedAt:   time.Now(),
+					UpdatedAt:   time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				return video
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "error - already ready",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:        uuid.New(),
+					UserID:    uuid.New(),
+					Title:     "Test Video",
+					Status:    model.StatusReady,
+					HLSURL:    "hls/video-id/master.m3u8",
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				return video
+			},
+			wantErr: ErrVideoAlreadyCompleted,
+		},
+		{
+			name:    "error - already failed",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:        uuid.New(),
+					UserID:    uuid.New(),
+					Title:     "Test Video",
+					Status:    model.StatusFailed,
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				return video
+			},
+			wantErr: ErrVideoAlreadyCompleted,
+		},
+		{
+			name:    "error - video not found",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+				return nil
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "error - repository update fails",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:          uuid.New(),
+					UserID:      uuid.New(),
+					Title:       "Test Video",
+					Status:      model.StatusPendingUpload,
+					OriginalURL: "originals/video-id/video.mp4",
+					CreatedAt:   time.Now(),
+					UpdatedAt:   time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				repo.updateFn = func(ctx context.Context, v *model.Video) error {
+					return errors.New("database error")
+				}
+				return video
+			},
+			wantErr: errors.New("update video status"),
+		},
+		{
+			name:    "error - queue publish fails",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository, queue *mockMessageQueue) *model.Video {
+				video := &model.Video{
+					ID:          uuid.New(),
+					UserID:      uuid.New(),
+					Title:       "Test Video",
+					Status:      model.StatusPendingUpload,
+					OriginalURL: "originals/video-id/video.mp4",
+					CreatedAt:   time.Now(),
+					UpdatedAt:   time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				repo.updateFn = func(ctx context.Context, v *model.Video) error {
+					return nil
+				}
+				queue.publishTranscodeTaskFn = func(ctx context.Context, task repository.TranscodeTask) error {
+					return errors.New("queue unavailable")
+				}
+				return video
+			},
+			wantErr: errors.New("publish transcode task"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{}
+			storage := &mockObjectStorage{
+				statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+					if strings.HasSuffix(key, "missing.mp4") {
+						return nil, repository.ErrObjectNotFound
+					}
+					return &repository.ObjectInfo{Key: key, Size: 1024, ETag: "etag"}, nil
+				},
+			}
+			queue := &mockMessageQueue{}
+
+			tt.setupMock(repo, queue)
+
+			svc := NewVideoService(repo, storage, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+
+			err := svc.TriggerProcess(context.Background(), tt.videoID, ProcessInput{})
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestVideoService_PlanProcess(t *testing.T) {
+	storageErr := errors.New("storage unavailable")
+	source := &transcoder.ProbeResult{Duration: 8 * time.Second, Width: 1920, Height: 1080, HasAudio: true}
+
+	tests := []struct {
+		name       string
+		status     model.Status
+		exists     bool
+		existsErr  error
+		probeErr   error
+		noProber   bool
+		wantErr    error
+		wantProbed bool
+	}{
+		{name: "plans uploaded video", status: model.StatusUploaded, exists: true, wantProbed: true},
+		{name: "plans processing video", status: model.StatusProcessing, exists: true, wantProbed: true},
+		{name: "original missing", status: model.StatusPendingUpload, wantErr: ErrUploadMissing},
+		{name: "storage error", status: model.StatusUploaded, existsErr: storageErr, wantErr: storageErr},
+		{name: "already completed", status: model.StatusReady, exists: true, wantErr: ErrVideoAlreadyCompleted},
+		{name: "no video stream", status: model.StatusUploaded, exists: true, probeErr: transcoder.ErrNoVideoStream, wantErr: ErrNoVideoStream, wantProbed: true},
+		{name: "no prober", status: model.StatusUploaded, exists: true, noProber: true, wantErr: ErrPlanningUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:             uuid.New(),
+				Status:         tt.status,
+				OriginalURL:    "originals/video-id/video.mp4",
+				PreviewSeconds: 30,
+			}
+
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					t.Error("dry run must not update the video")
+					return nil
+				},
+			}
+			storage := &mockObjectStorage{
+				existsFn: func(ctx context.Context, key string) (bool, error) {
+					return tt.exists, tt.existsErr
+				},
+				generatePresignedDownloadURLFn: func(ctx context.Context, key string, expiry time.Duration) (string, error) {
+					return "https://storage.example/" + key, nil
+				},
+			}
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					t.Error("dry run must not enqueue a task")
+					return nil
+				},
+			}
+
+			probed := false
+			var prober transcoder.Prober = &mockProber{
+				probeFn: func(ctx context.Context, input string) (*transcoder.ProbeResult, error) {
+					probed = true
+					if input != "https://storage.example/"+video.OriginalURL {
+						t.Errorf("probe input: got %q", input)
+					}
+					if tt.probeErr != nil {
+						return nil, tt.probeErr
+					}
+					return source, nil
+				},
+			}
+			if tt.noProber {
+				prober = nil
+			}
+
+			estimate := &model.TranscodeEstimate{ProcessingTime: 4 * time.Second, OutputBytes: 1 << 20, Samples: 9}
+			estimator := &mockTranscodeEstimator{
+				estimateFn: func(ctx context.Context, s transcoder.ProbeResult) (*model.TranscodeEstimate, error) {
+					if s != *source {
+						t.Errorf("estimated source: got %+v, expected %+v", s, *source)
+					}
+					return estimate, nil
+				},
+			}
+
+			svc := NewVideoService(repo, storage, queue, nil, prober, estimator, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			plan, err := svc.PlanProcess(context.Background(), video.ID, ProcessInput{})
+
+			if probed != tt.wantProbed {
+				t.Errorf("probed: got %v, expected %v", probed, tt.wantProbed)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(plan.Variants) != len(transcoder.DefaultABRVariants()) || plan.Preview == nil {
+				t.Errorf("plan: got %d variants (preview %v), expected the default ladder with a preview", len(plan.Variants), plan.Preview != nil)
+			}
+			if plan.EstimatedDuration != source.Duration || plan.Source != *source {
+				t.Errorf("plan source: got %+v, expected %+v", plan.Source, *source)
+			}
+			if plan.Estimate != estimate {
+				t.Errorf("plan estimate: got %+v, expected %+v", plan.Estimate, estimate)
+			}
+		})
+	}
+}
+
+func TestVideoService_Retranscode(t *testing.T) {
+	tests := []struct {
+		name         string
+		video        *model.Video
+		input        RetranscodeInput
+		repoErr      error
+		deleteErr    error
+		publishErr   error
+		admitErr     error
+		wantClass    WorkClass
+		wantStatus   model.Status
+		wantDeleted  []string
+		wantVariants []string
+		wantErr      error
+	}{
+		{
+			name: "ready video gets a new output version",
+			video: &model.Video{
+				ID:            uuid.New(),
+				Status:        model.StatusReady,
+				OriginalURL:   "originals/video-id/video.mp4",
+				HLSURL:        "hls/video-id/v1/master.m3u8",
+				OutputVersion: 1,
+			},
+			wantClass:  WorkBackground,
+			wantStatus: model.StatusReady,
+		},
+		{
+			name:         "ready video with a variant override",
+			video:        &model.Video{ID: uuid.New(), Status: model.StatusReady, OriginalURL: "originals/video-id/video.mp4"},
+			input:        RetranscodeInput{Variants: []string{"360p", "720p"}},
+			wantClass:    WorkBackground,
+			wantStatus:   model.StatusReady,
+			wantVariants: []string{"360p", "720p"},
+		},
+		{
+			name:        "failed video is cleaned and reprocessed",
+			video:       &model.Video{ID: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), Status: model.StatusFailed, OriginalURL: "originals/video-id/video.mp4"},
+			wantClass:   WorkInteractive,
+			wantStatus:  model.StatusProcessing,
+			wantDeleted: []string{"hls/6ba7b810-9dad-11d1-80b4-00c04fd430c8/", "previews/6ba7b810-9dad-11d1-80b4-00c04fd430c8/"},
+		},
+		{
+			name:      "failed output cleanup fails",
+			video:     &model.Video{ID: uuid.New(), Status: model.StatusFailed, OriginalURL: "originals/video-id/video.mp4"},
+			deleteErr: errors.New("storage unavailable"),
+			wantClass: WorkInteractive,
+			wantErr:   errors.New("remove failed output"),
+		},
+		{
+			name:    "unknown variant",
+			video:   &model.Video{ID: uuid.New(), Status: model.StatusReady},
+			input:   RetranscodeInput{Variants: []string{"4k"}},
+			wantErr: ErrUnknownVariant,
+		},
+		{
+			name:    "video not ready",
+			video:   &model.Video{ID: uuid.New(), Status: model.StatusProcessing},
+			wantErr: ErrVideoNotReady,
+		},
+		{
+			name:    "video not found",
+			repoErr: repository.ErrVideoNotFound,
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:       "queue publish fails",
+			video:      &model.Video{ID: uuid.New(), Status: model.StatusReady, OriginalURL: "originals/video-id/video.mp4"},
+			publishErr: errors.New("queue unavailable"),
+			wantClass:  WorkBackground,
+			wantErr:    errors.New("publish transcode task"),
+		},
+		{
+			name:      "shed by admission control",
+			video:     &model.Video{ID: uuid.New(), Status: model.StatusReady, OriginalURL: "originals/video-id/video.mp4"},
+			admitErr:  &OverloadedError{Reason: "transcode backlog 900 exceeds 500", RetryAfter: time.Minute},
+			wantClass: WorkBackground,
+			wantErr:   ErrOverloaded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *model.Video
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return tt.video, tt.repoErr
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					if v.Status != model.StatusProcessing {
+						t.Errorf("only a failed video may be updated, back to PROCESSING; got %s", v.Status)
+					}
+					updated = v
+					return nil
+				},
+			}
+
+			var deleted []string
+			storage := &mockObjectStorage{
+				deleteByPrefixFn: func(ctx context.Context, prefix string) (int, error) {
+					deleted = append(deleted, prefix)
+					return 0, tt.deleteErr
+				},
+			}
+
+			var published *repository.TranscodeTask
+			queue := &mockMessageQueue{
+				publishTranscodeTaskFn: func(ctx context.Context, task repository.TranscodeTask) error {
+					published = &task
+					return tt.publishErr
+				},
+			}
+
+			admission := &mockAdmissionController{
+				admitFn: func(ctx context.Context, class WorkClass) error {
+					if class != tt.wantClass {
+						t.Errorf("work class: got %q, expected %q", class, tt.wantClass)
+					}
+					return tt.admitErr
+				},
+			}
+
+			svc := NewVideoService(repo, storage, queue, admission, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			err := svc.Retranscode(context.Background(), uuid.New(), tt.input)
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+				}
+				if !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErr, err)
+				}
+				if (tt.admitErr != nil || tt.deleteErr != nil) && published != nil {
+					t.Error("rejected work must not be published")
+				}
+				if tt.deleteErr != nil && updated != nil {
+					t.Error("video must stay FAILED when its output could not be removed")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.video.Status != tt.wantStatus {
+				t.Errorf("status: got %s, expected %s", tt.video.Status, tt.wantStatus)
+			}
+			if !slices.Equal(deleted, tt.wantDeleted) {
+				t.Errorf("deleted prefixes: got %v, expected %v", deleted, tt.wantDeleted)
+			}
+			if published == nil {
+				t.Fatal("expected a transcode task to be published")
+			}
+			if published.OutputVersion <= tt.video.OutputVersion {
+				t.Errorf("output version: got %d, expected newer than %d", published.OutputVersion, tt.video.OutputVersion)
+			}
+			expectedKey := fmt.Sprintf("hls/%s/v%d/", tt.video.ID, published.OutputVersion)
+			if published.OutputKey != expectedKey {
+				t.Errorf("output key: got %q, expected %q", published.OutputKey, expectedKey)
+			}
+			if !slices.Equal(published.Variants, tt.wantVariants) {
+				t.Errorf("variants: got %v, expected %v", published.Variants, tt.wantVariants)
+			}
+		})
+	}
+}
+
+func TestSelectVariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr error
+	}{
+		{name: "no names selects the ladder", want: []string{"1080p", "720p", "360p"}},
+		{name: "ladder order is kept", names: []string{"360p", "1080p"}, want: []string{"1080p", "360p"}},
+		{name: "duplicates collapse", names: []string{"720p", "720p"}, want: []string{"720p"}},
+		{name: "unknown name", names: []string{"720p", "2160p"}, wantErr: ErrUnknownVariant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := selectVariants(transcoder.DefaultABRVariants(), tt.names)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error: got %v, expected %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, v := range variants {
+				got = append(got, v.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("variants: got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextOutputVersion(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+
+	tests := []struct {
+		name    string
+		current int64
+		want    int64
+	}{
+		{name: "first version uses timestamp", current: 0, want: now.UnixMilli()},
+		{name: "older version uses timestamp", current: now.UnixMilli() - 1000, want: now.UnixMilli()},
+		{name: "clock behind current version", current: now.UnixMilli() + 5000, want: now.UnixMilli() + 5001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextOutputVersion(tt.current, now); got != tt.want {
+				t.Errorf("nextOutputVersion(%d) = %d, want %d", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideoService_GetVideo(t *testing.T) {
+	tests := []struct {
+		name      string
+		videoID   uuid.UUID
+		setupMock func(repo *mockVideoRepository) *model.Video
+		wantErr   error
+	}{
+		{
+			name:    "successful retrieval",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository) *model.Video {
+				video := &model.Video{
+					ID:        uuid.New(),
+					UserID:    uuid.New(),
+					Title:     "Test Video",
+					Status:    model.StatusReady,
+					HLSURL:    "hls/video-id/master.m3u8",
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				}
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				}
+				return video
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "video not found",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository) *model.Video {
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return nil, repository.ErrVideoNotFound
+				}
+				return nil
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "deleted video",
+			videoID: uuid.New(),
+			setupMock: func(repo *mockVideoRepository) *model.Video {
+				repo.getByIDFn = func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return &model.Video{ID: id, Status: model.StatusDeleted}, nil
+				}
+				return nil
+			},
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{}
+			storage := &mockObjectStorage{}
+			queue := &mockMessageQueue{}
+
+			expectedVideo := tt.setupMock(repo)
+
+			svc := NewVideoService(repo, storage, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+
+			video, err := svc.GetVideo(context.Background(), tt.videoID)
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error %v, got nil", tt.wantErr)
+				}
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if video.ID != expectedVideo.ID {
+				t.Errorf("expected video ID %s, got %s", expectedVideo.ID, video.ID)
+			}
+		})
+	}
+}
+
+func TestVideoService_GetVideo_StaleReads(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantStale bool
+	}{
+		{name: "tolerated by default", ctx: context.Background(), wantStale: true},
+		{name: "primary forced by the caller", ctx: repository.WithStaleReads(context.Background(), false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStale bool
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					gotStale, _ = repository.StaleReads(ctx)
+					return &model.Video{ID: id, Status: model.StatusReady}, nil
+				},
+			}
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+
+			if _, err := svc.GetVideo(tt.ctx, uuid.New()); err != nil {
+				t.Fatalf("GetVideo() error = %v", err)
+			}
+			if gotStale != tt.wantStale {
+				t.Errorf("stale reads = %v, want %v", gotStale, tt.wantStale)
+			}
+
+			// Lookups that precede a write never tolerate stale reads
+			gotStale = true
+			if _, err := svc.UpdateMetadata(context.Background(), uuid.New(), UpdateMetadataInput{}); err != nil {
+				t.Fatalf("UpdateMetadata() error = %v", err)
+			}
+			if gotStale {
+				t.Error("UpdateMetadata read the video with stale reads allowed")
+			}
+		})
+	}
+}
+
+func TestVideoService_DeleteVideo(t *testing.T) {
+	publishErr := errors.New("broker unavailable")
+
+	tests := []struct {
+		name        string
+		status      model.Status
+		publishErr  error
+		wantErr     error
+		wantUpdated bool
+		wantTask    bool
+	}{
+		{
+			name:        "ready video",
+			status:      model.StatusReady,
+			wantUpdated: true,
+			wantTask:    true,
+		},
+		{
+			name:        "pending upload",
+			status:      model.StatusPendingUpload,
+			wantUpdated: true,
+			wantTask:    true,
+		},
+		{
+			name:        "failed video",
+			status:      model.StatusFailed,
+			wantUpdated: true,
+			wantTask:    true,
+		},
+		{
+			name:    "processing video",
+			status:  model.StatusProcessing,
+			wantErr: ErrVideoProcessing,
+		},
+		{
+			name:    "already deleted",
+			status:  model.StatusDeleted,
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:        "publish failure",
+			status:      model.StatusReady,
+			publishErr:  publishErr,
+			wantErr:     publishErr,
+			wantUpdated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Title:       "Test Video",
+				Status:      tt.status,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+
+			updated := false
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					if v.Status != model.StatusDeleted {
+						t.Errorf("updated status: got %s, expected %s", v.Status, model.StatusDeleted)
+					}
+					updated = true
+					return nil
+				},
+			}
+			var task *repository.DeleteTask
+			queue := &mockMessageQueue{
+				publishDeleteTaskFn: func(ctx context.Context, tk repository.DeleteTask) error {
+					if tt.publishErr != nil {
+						return tt.publishErr
+					}
+					task = &tk
+					return nil
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, queue, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.DeleteVideo(context.Background(), video.ID)
+
+			if updated != tt.wantUpdated {
+				t.Errorf("updated: got %v, expected %v", updated, tt.wantUpdated)
+			}
+			if (task != nil) != tt.wantTask {
+				t.Errorf("task published: got %v, expected %v", task != nil, tt.wantTask)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Status != model.StatusDeleted {
+				t.Errorf("status: got %s, expected %s", got.Status, model.StatusDeleted)
+			}
+			wantPrefixes := []string{
+				"hls/" + video.ID.String() + "/",
+				"previews/" + video.ID.String() + "/",
+				"subtitles/" + video.ID.String() + "/",
+				"archive/hls/" + video.ID.String() + "/",
+				"logs/" + video.ID.String() + "/",
+			}
+			if task.VideoID != video.ID || task.UserID != video.UserID || task.OriginalKey != video.OriginalURL ||
+				!reflect.DeepEqual(task.OutputPrefixes, wantPrefixes) {
+				t.Errorf("task = %+v, want original %q and prefixes %v", task, video.OriginalURL, wantPrefixes)
+			}
+		})
+	}
+}
+
+func TestVideoService_GetOriginalURL(t *testing.T) {
+	owner := uuid.New()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		status    model.Status
+		userID    uuid.UUID
+		issuerErr error
+		wantErr   error
+	}{
+		{
+			name:   "owner downloads original",
+			status: model.StatusReady,
+			userID: owner,
+		},
+		{
+			name:   "original of a failed video",
+			status: model.StatusFailed,
+			userID: owner,
+		},
+		{
+			name:    "other user",
+			status:  model.StatusReady,
+			userID:  uuid.New(),
+			wantErr: ErrNotVideoOwner,
+		},
+		{
+			name:    "not uploaded yet",
+			status:  model.StatusPendingUpload,
+			userID:  owner,
+			wantErr: ErrOriginalNotUploaded,
+		},
+		{
+			name:    "deleted video",
+			status:  model.StatusDeleted,
+			userID:  owner,
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:      "rate limited",
+			status:    model.StatusReady,
+			userID:    owner,
+			issuerErr: &URLRateLimitedError{RetryAfter: time.Minute},
+			wantErr:   ErrURLRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      owner,
+				Status:      tt.status,
+				OriginalURL: "originals/abc/video.mp4",
+			}
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+			}
+			cfg := DefaultVideoServiceConfig()
+			urls := &mockURLIssuer{
+				issueDownloadURLFn: func(ctx context.Context, req URLRequest) (string, error) {
+					if tt.issuerErr != nil {
+						return "", tt.issuerErr
+					}
+					want := URLRequest{
+						Key:         video.OriginalURL,
+						Expiry:      cfg.OriginalURLExpiry,
+						Purpose:     model.URLPurposeDownload,
+						RequesterID: owner,
+						VideoID:     video.ID,
+					}
+					if req != want {
+						t.Errorf("URL request = %+v, want %+v", req, want)
+					}
+					return "https://storage.example.com/" + req.Key, nil
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, urls, nil, nil, nil, nil, nil, cfg).(*videoService)
+			svc.now = func() time.Time { return now }
+
+			got, err := svc.GetOriginalURL(context.Background(), OriginalURLInput{VideoID: video.ID, UserID: tt.userID})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.URL != "https://storage.example.com/originals/abc/video.mp4" {
+				t.Errorf("URL = %q", got.URL)
+			}
+			if !got.ExpiresAt.Equal(now.Add(cfg.OriginalURLExpiry)) {
+				t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, now.Add(cfg.OriginalURLExpiry))
+			}
+		})
+	}
+}
+
+func TestVideoService_UpdateMetadata(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	tags := func(t ...string) *[]string { return &t }
+	visibility := func(v model.Visibility) *model.Visibility { return &v }
+
+	tests := []struct {
+		name            string
+		input           UpdateMetadataInput
+		status          model.Status
+		wantErr         error
+		wantUpdated     bool
+		wantTitle       string
+		wantDescription string
+		wantTags        []string
+		wantVisibility  model.Visibility
+		wantPurged      []string
+	}{
+		{
+			name:            "updates every field",
+			input:           UpdateMetadataInput{Title: ptr("Renamed"), Description: ptr("New description"), Tags: tags("Go", "go", "Live")},
+			status:          model.StatusReady,
+			wantUpdated:     true,
+			wantTitle:       "Renamed",
+			wantDescription: "New description",
+			wantTags:        []string{"go", "live"},
+			wantVisibility:  model.VisibilityUnlisted,
+		},
+		{
+			name:            "leaves nil fields unchanged",
+			input:           UpdateMetadataInput{Description: ptr("")},
+			status:          model.StatusReady,
+			wantUpdated:     true,
+			wantTitle:       "Original",
+			wantDescription: "",
+			wantTags:        []string{"old"},
+			wantVisibility:  model.VisibilityUnlisted,
+		},
+		{
+			name:            "clears tags",
+			input:           UpdateMetadataInput{Tags: tags()},
+			status:          model.StatusPendingUpload,
+			wantUpdated:     true,
+			wantTitle:       "Original",
+			wantDescription: "Original description",
+			wantTags:        []string{},
+			wantVisibility:  model.VisibilityUnlisted,
+		},
+		{
+			name:            "makes the video private",
+			input:           UpdateMetadataInput{Visibility: visibility(model.VisibilityPrivate)},
+			status:          model.StatusReady,
+			wantUpdated:     true,
+			wantTitle:       "Original",
+			wantDescription: "Original description",
+			wantTags:        []string{"old"},
+			wantVisibility:  model.VisibilityPrivate,
+			wantPurged:      []string{"videos/v1/"},
+		},
+		{
+			name:    "invalid visibility",
+			input:   UpdateMetadataInput{Visibility: visibility("hidden")},
+			status:  model.StatusReady,
+			wantErr: model.ErrInvalidVisibility,
+		},
+		{
+			name:    "invalid title",
+			input:   UpdateMetadataInput{Title: ptr(""), Tags: tags("new")},
+			status:  model.StatusReady,
+			wantErr: model.ErrEmptyTitle,
+		},
+		{
+			name:    "invalid tag after a valid title",
+			input:   UpdateMetadataInput{Title: ptr("Renamed"), Tags: tags("a,b")},
+			status:  model.StatusReady,
+			wantErr: model.ErrInvalidTag,
+		},
+		{
+			name:    "deleted video",
+			input:   UpdateMetadataInput{Title: ptr("Renamed")},
+			status:  model.StatusDeleted,
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Title:       "Original",
+				Description: "Original description",
+				Tags:        []string{"old"},
+				Status:      tt.status,
+				Visibility:  model.VisibilityUnlisted,
+				HLSURL:      "videos/v1/master.m3u8",
+			}
+
+			updated := false
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					updated = true
+					return nil
+				},
+			}
+
+			var purged []string
+			purger := &mockCDNPurger{
+				purgeFn: func(ctx context.Context, prefixes []string) error {
+					purged = prefixes
+					return nil
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, purger, DefaultVideoServiceConfig())
+			got, err := svc.UpdateMetadata(context.Background(), video.ID, tt.input)
+
+			if updated != tt.wantUpdated {
+				t.Errorf("updated: got %v, expected %v", updated, tt.wantUpdated)
+			}
+			if !reflect.DeepEqual(purged, tt.wantPurged) {
+				t.Errorf("purged = %q, want %q", purged, tt.wantPurged)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Description != tt.wantDescription {
+				t.Errorf("Description = %q, want %q", got.Description, tt.wantDescription)
+			}
+			if !reflect.DeepEqual(got.Tags, tt.wantTags) {
+				t.Errorf("Tags = %q, want %q", got.Tags, tt.wantTags)
+			}
+			if got.Visibility != tt.wantVisibility {
+				t.Errorf("Visibility = %q, want %q", got.Visibility, tt.wantVisibility)
+			}
+		})
+	}
+}
+
+func TestVideoService_SetExpiration(t *testing.T) {
+	future := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		status      model.Status
+		expiresAt   time.Time
+		wantErr     error
+		wantUpdated bool
+	}{
+		{
+			name:        "schedules expiry",
+			status:      model.StatusReady,
+			expiresAt:   future,
+			wantUpdated: true,
+		},
+		{
+			name:        "clears expiry",
+			status:      model.StatusReady,
+			wantUpdated: true,
+		},
+		{
+			name:        "before upload",
+			status:      model.StatusPendingUpload,
+			expiresAt:   future,
+			wantUpdated: true,
+		},
+		{
+			name:      "time in the past",
+			status:    model.StatusReady,
+			expiresAt: time.Now().Add(-time.Hour),
+			wantErr:   model.ErrExpiryInPast,
+		},
+		{
+			name:      "already expired",
+			status:    model.StatusExpired,
+			expiresAt: future,
+			wantErr:   ErrVideoExpired,
+		},
+		{
+			name:      "deleted video",
+			status:    model.StatusDeleted,
+			expiresAt: future,
+			wantErr:   repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:        uuid.New(),
+				UserID:    uuid.New(),
+				Status:    tt.status,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+
+			updated := false
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					if !v.ExpiresAt.Equal(tt.expiresAt) {
+						t.Errorf("updated ExpiresAt = %v, want %v", v.ExpiresAt, tt.expiresAt)
+					}
+					updated = true
+					return nil
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.SetExpiration(context.Background(), video.ID, tt.expiresAt)
+
+			if updated != tt.wantUpdated {
+				t.Errorf("updated: got %v, expected %v", updated, tt.wantUpdated)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.ExpiresAt.Equal(tt.expiresAt) {
+				t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, tt.expiresAt)
+			}
+		})
+	}
+}
+
+func TestVideoService_GetTranscodeProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		store       *mockTranscodeProgressStore
+		wantPercent int
+		wantOK      bool
+	}{
+		{
+			name: "progress recorded",
+			store: &mockTranscodeProgressStore{
+				getFn: func(ctx context.Context, videoID uuid.UUID) (int, bool, error) {
+					return 42, true, nil
+				},
+			},
+			wantPercent: 42,
+			wantOK:      true,
+		},
+		{
+			name:  "nothing recorded",
+			store: &mockTranscodeProgressStore{},
+		},
+		{
+			name: "store error reports no progress",
+			store: &mockTranscodeProgressStore{
+				getFn: func(ctx context.Context, videoID uuid.UUID) (int, bool, error) {
+					return 0, false, errors.New("redis down")
+				},
+			},
+		},
+		{
+			name: "no store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var store cache.TranscodeProgressStore
+			if tt.store != nil {
+				store = tt.store
+			}
+			svc := NewVideoService(&mockVideoRepository{}, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, store, nil, nil, nil, nil, DefaultVideoServiceConfig())
+
+			percent, ok := svc.GetTranscodeProgress(context.Background(), uuid.New())
+			if percent != tt.wantPercent || ok != tt.wantOK {
+				t.Errorf("got %d, %v; expected %d, %v", percent, ok, tt.wantPercent, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestVideoService_ListVideos(t *testing.T) {
+	userID := uuid.New()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	videos := make([]*model.Video, 3)
+	for i := range videos {
+		videos[i] = &model.Video{ID: uuid.New(), UserID: userID, CreatedAt: base.Add(-time.Duration(i) * time.Hour)}
+	}
+
+	tests := []struct {
+		name           string
+		input          ListVideosInput
+		rows           []*model.Video
+		wantLimit      int
+		wantAfter      *repository.VideoCursor
+		wantVisibility model.Visibility
+		wantCount      int
+		wantNextCursor bool
+		wantErr        error
+	}{
+		{
+			name:      "default page size",
+			input:     ListVideosInput{UserID: userID, CallerID: userID},
+			rows:      videos,
+			wantLimit: DefaultVideoPageSize + 1,
+			wantCount: 3,
+		},
+		{
+			name:           "another caller sees public videos only",
+			input:          ListVideosInput{UserID: userID, CallerID: uuid.New()},
+			rows:           videos,
+			wantLimit:      DefaultVideoPageSize + 1,
+			wantVisibility: model.VisibilityPublic,
+			wantCount:      3,
+		},
+		{
+			name:           "anonymous caller sees public videos only",
+			input:          ListVideosInput{UserID: userID},
+			rows:           videos,
+			wantLimit:      DefaultVideoPageSize + 1,
+			wantVisibility: model.VisibilityPublic,
+			wantCount:      3,
+		},
+		{
+			name:           "more pages follow",
+			input:          ListVideosInput{UserID: userID, CallerID: userID, Limit: 2},
+			rows:           videos,
+			wantLimit:      3,
+			wantCount:      2,
+			wantNextCursor: true,
+		},
+		{
+			name:      "limit is capped",
+			input:     ListVideosInput{UserID: userID, CallerID: userID, Limit: 1000},
+			wantLimit: MaxVideoPageSize + 1,
+		},
+		{
+			name:      "continues after cursor",
+			input:     ListVideosInput{UserID: userID, CallerID: userID, Cursor: encodeKeysetCursor(videos[1].CreatedAt, videos[1].ID), Limit: 2},
+			rows:      videos[2:],
+			wantLimit: 3,
+			wantAfter: &repository.VideoCursor{CreatedAt: videos[1].CreatedAt, ID: videos[1].ID},
+			wantCount: 1,
+		},
+		{
+			name:    "malformed cursor",
+			input:   ListVideosInput{UserID: userID, CallerID: userID, Cursor: "not-a-cursor"},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "missing user",
+			input:   ListVideosInput{},
+			wantErr: model.ErrInvalidUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got repository.VideoFilter
+			repo := &mockVideoRepository{
+				listFn: func(ctx context.Context, filter repository.VideoFilter) ([]*model.Video, error) {
+					got = filter
+					return tt.rows, nil
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			output, err := svc.ListVideos(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.UserID != userID || got.Limit != tt.wantLimit {
+				t.Errorf("filter = %+v, want user %s and limit %d", got, userID, tt.wantLimit)
+			}
+			if !got.ExcludeDeleted || !got.ExcludeExpired {
+				t.Error("listing must exclude deleted and expired videos")
+			}
+			if got.Visibility != tt.wantVisibility {
+				t.Errorf("Visibility = %q, want %q", got.Visibility, tt.wantVisibility)
+			}
+			if !reflect.DeepEqual(got.After, tt.wantAfter) {
+				t.Errorf("After = %+v, want %+v", got.After, tt.wantAfter)
+			}
+			if len(output.Videos) != tt.wantCount {
+				t.Errorf("expected %d videos, got %d", tt.wantCount, len(output.Videos))
+			}
+			if (output.NextCursor != "") != tt.wantNextCursor {
+				t.Errorf("NextCursor = %q, wantNextCursor %v", output.NextCursor, tt.wantNextCursor)
+			}
+
+			if tt.wantNextCursor {
+				last := output.Videos[len(output.Videos)-1]
+				createdAt, id, err := decodeKeysetCursor(output.NextCursor)
+				if err != nil || !createdAt.Equal(last.CreatedAt) || id != last.ID {
+					t.Errorf("NextCursor does not point at the last video: %v %v %v", createdAt, id, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVideoService_SearchVideos(t *testing.T) {
+	userID := uuid.New()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := make([]repository.VideoSearchResult, 3)
+	for i := range results {
+		results[i] = repository.VideoSearchResult{
+			Video: &model.Video{ID: uuid.New(), UserID: userID, CreatedAt: base.Add(-time.Duration(i) * time.Hour)},
+			Rank:  0.1 / float32(i+1),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		input          SearchVideosInput
+		rows           []repository.VideoSearchResult
+		wantQuery      string
+		wantLimit      int
+		wantAfter      *repository.VideoSearchCursor
+		wantVisibility model.Visibility
+		wantCount      int
+		wantNextCursor bool
+		wantErr        error
+	}{
+		{
+			name:      "default page size",
+			input:     SearchVideosInput{UserID: userID, CallerID: userID, Query: "  cats  "},
+			rows:      results,
+			wantQuery: "cats",
+			wantLimit: DefaultVideoPageSize + 1,
+			wantCount: 3,
+		},
+		{
+			name:           "another caller sees public videos only",
+			input:          SearchVideosInput{UserID: userID, CallerID: uuid.New(), Query: "cats"},
+			rows:           results,
+			wantQuery:      "cats",
+			wantLimit:      DefaultVideoPageSize + 1,
+			wantVisibility: model.VisibilityPublic,
+			wantCount:      3,
+		},
+		{
+			name:           "more pages follow",
+			input:          SearchVideosInput{UserID: userID, CallerID: userID, Query: "cats", Limit: 2},
+			rows:           results,
+			wantQuery:      "cats",
+			wantLimit:      3,
+			wantCount:      2,
+			wantNextCursor: true,
+		},
+		{
+			name: "continues after cursor",
+			input: SearchVideosInput{
+				UserID:   userID,
+				CallerID: userID,
+				Query:    "cats",
+				Cursor:   encodeSearchCursor(results[1].Rank, results[1].Video.CreatedAt, results[1].Video.ID),
+				Limit:    2,
+			},
+			rows:      results[2:],
+			wantQuery: "cats",
+			wantLimit: 3,
+			wantAfter: &repository.VideoSearchCursor{Rank: results[1].Rank, CreatedAt: results[1].Video.CreatedAt, ID: results[1].Video.ID},
+			wantCount: 1,
+		},
+		{
+			name:    "list cursor",
+			input:   SearchVideosInput{UserID: userID, CallerID: userID, Query: "cats", Cursor: encodeKeysetCursor(base, uuid.New())},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "blank query",
+			input:   SearchVideosInput{UserID: userID, CallerID: userID, Query: "   "},
+			wantErr: ErrInvalidSearchQuery,
+		},
+		{
+			name:    "overlong query",
+			input:   SearchVideosInput{UserID: userID, CallerID: userID, Query: strings.Repeat("é", MaxSearchQueryLength+1)},
+			wantErr: ErrInvalidSearchQuery,
+		},
+		{
+			name:    "missing user",
+			input:   SearchVideosInput{Query: "cats"},
+			wantErr: model.ErrInvalidUserID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got repository.VideoSearch
+			repo := &mockVideoRepository{
+				searchFn: func(ctx context.Context, search repository.VideoSearch) ([]repository.VideoSearchResult, error) {
+					got = search
+					return tt.rows, nil
+				},
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultVideoServiceConfig())
+			output, err := svc.SearchVideos(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.UserID != userID || got.Query != tt.wantQuery || got.Limit != tt.wantLimit {
+				t.Errorf("search = %+v, want user %s, query %q and limit %d", got, userID, tt.wantQuery, tt.wantLimit)
+			}
+			if got.Visibility != tt.wantVisibility {
+				t.Errorf("Visibility = %q, want %q", got.Visibility, tt.wantVisibility)
+			}
+			if !reflect.DeepEqual(got.After, tt.wantAfter) {
+				t.Errorf("After = %+v, want %+v", got.After, tt.wantAfter)
+			}
+			if len(output.Videos) != tt.wantCount {
+				t.Errorf("expected %d videos, got %d", tt.wantCount, len(output.Videos))
+			}
+			if (output.NextCursor != "") != tt.wantNextCursor {
+				t.Errorf("NextCursor = %q, wantNextCursor %v", output.NextCursor, tt.wantNextCursor)
+			}
+
+			if tt.wantNextCursor {
+				last := tt.rows[len(output.Videos)-1]
+				rank, createdAt, id, err := decodeSearchCursor(output.NextCursor)
+				if err != nil || rank != last.Rank || !createdAt.Equal(last.Video.CreatedAt) || id != last.Video.ID {
+					t.Errorf("NextCursor does not point at the last result: %v %v %v %v", rank, createdAt, id, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVideoService_ListStatusEvents(t *testing.T) {
+	videoID := uuid.New()
+
+	tests := []struct {
+		name         string
+		limit        int
+		noEvents     bool
+		private      bool
+		getErr       error
+		wantLimit    int
+		wantErr      error
+		wantNumItems int
+	}{
+		{
+			name:         "default limit",
+			wantLimit:    DefaultStatusEventsLimit,
+			wantNumItems: 1,
+		},
+		{
+			name:         "limit is capped",
+			limit:        MaxStatusEventsLimit + 1,
+			wantLimit:    MaxStatusEventsLimit,
+			wantNumItems: 1,
+		},
+		{
+			name:     "events not recorded",
+			noEvents: true,
+		},
+		{
+			name:    "unknown video",
+			getErr:  repository.ErrVideoNotFound,
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "private video of another user",
+			private: true,
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					video := &model.Video{ID: id, UserID: uuid.New(), Status: model.StatusReady, Visibility: model.VisibilityUnlisted}
+					if tt.private {
+						video.Visibility = model.VisibilityPrivate
+					}
+					return video, nil
+				},
+			}
+			var statusEvents repository.VideoStatusEventRepository
+			if !tt.noEvents {
+				statusEvents = &mockVideoStatusEventRepository{
+					listByVideoIDFn: func(ctx context.Context, id uuid.UUID, limit int) ([]*model.VideoStatusEvent, error) {
+						if limit != tt.wantLimit {
+							t.Errorf("limit: got %d, expected %d", limit, tt.wantLimit)
+						}
+						return []*model.VideoStatusEvent{
+							model.NewVideoStatusEvent(id, model.StatusProcessing, model.StatusReady, model.StatusActorWorker, "output version 1 published after 0 retries"),
+						}, nil
+					},
+				}
+			}
+
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, statusEvents, nil, nil, DefaultVideoServiceConfig())
+			got, err := svc.ListStatusEvents(context.Background(), videoID, uuid.New(), tt.limit)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got == nil || len(got) != tt.wantNumItems {
+				t.Errorf("events: got %v, expected %d", got, tt.wantNumItems)
+			}
+		})
+	}
+}
+
+func TestVideoService_GetStorageUsage(t *testing.T) {
+	userID := uuid.New()
+	repoErr := errors.New("database error")
+
+	tests := []struct {
+		name      string
+		quota     int64
+		repoErr   error
+		wantUsage *model.StorageUsage
+		wantErr   error
+	}{
+		{
+			name:      "with quota",
+			quota:     1 << 30,
+			wantUsage: &model.StorageUsage{UserID: userID, Videos: 2, OriginalBytes: 300, OutputBytes: 200, QuotaBytes: 1 << 30},
+		},
+		{
+			name:      "unlimited",
+			wantUsage: &model.StorageUsage{UserID: userID, Videos: 2, OriginalBytes: 300, OutputBytes: 200},
+		},
+		{
+			name:    "repository error",
+			repoErr: repoErr,
+			wantErr: repoErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVideoRepository{
+				getStorageUsageFn: func(ctx context.Context, id uuid.UUID) (*model.StorageUsage, error) {
+					if tt.repoErr != nil {
+						return nil, tt.repoErr
+					}
+					return &model.StorageUsage{UserID: id, Videos: 2, OriginalBytes: 300, OutputBytes: 200}, nil
+				},
+			}
+
+			cfg := DefaultVideoServiceConfig()
+			cfg.StorageQuotaBytes = tt.quota
+			svc := NewVideoService(repo, &mockObjectStorage{}, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+			got, err := svc.GetStorageUsage(context.Background(), userID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != *tt.wantUsage {
+				t.Errorf("usage: got %+v, expected %+v", got, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestVideoService_StorageQuota(t *testing.T) {
+	const quota = 1000
+
+	tests := []struct {
+		name string
+		// used is the bytes the user already stores
+		used int64
+		// call runs the operation under test against a PENDING_UPLOAD video of 400 bytes
+		call        func(svc VideoService, video *model.Video) error
+		wantErr     error
+		wantUpdated bool
+		wantDeleted bool
+	}{
+		{
+			name: "create within quota",
+			used: 999,
+			call: func(svc VideoService, video *model.Video) error {
+				_, err := svc.CreateVideo(context.Background(), CreateVideoInput{UserID: video.UserID, Title: "Clip", FileName: "clip.mp4"})
+				return err
+			},
+		},
+		{
+			name: "create with quota used up",
+			used: 1000,
+			call: func(svc VideoService, video *model.Video) error {
+				_, err := svc.CreateVideo(context.Background(), CreateVideoInput{UserID: video.UserID, Title: "Clip", FileName: "clip.mp4"})
+				return err
+			},
+			wantErr: ErrQuotaExceeded,
+		},
+		{
+			name: "complete upload that fits",
+			used: 600,
+			call: func(svc VideoService, video *model.Video) error {
+				_, err := svc.CompleteUpload(context.Background(), video.ID)
+				return err
+			},
+			wantUpdated: true,
+		},
+		{
+			name: "complete upload that does not fit",
+			used: 601,
+			call: func(svc VideoService, video *model.Video) error {
+				_, err := svc.CompleteUpload(context.Background(), video.ID)
+				return err
+			},
+			wantErr: ErrUploadExceedsQuota,
+		},
+		{
+			name: "process of a pending upload that fits",
+			used: 600,
+			call: func(svc VideoService, video *model.Video) error {
+				return svc.TriggerProcess(context.Background(), video.ID, ProcessInput{})
+			},
+			wantUpdated: true,
+		},
+		{
+			name: "process of a pending upload that does not fit",
+			used: 601,
+			call: func(svc VideoService, video *model.Video) error {
+				return svc.TriggerProcess(context.Background(), video.ID, ProcessInput{})
+			},
+			wantErr: ErrUploadExceedsQuota,
+		},
+		{
+			name: "multipart upload that does not fit",
+			used: 601,
+			call: func(svc VideoService, video *model.Video) error {
+				_, err := svc.InitiateMultipartUpload(context.Background(), video.ID, 400)
+				return err
+			},
+			wantErr: ErrUploadExceedsQuota,
+		},
+		{
+			name: "inline upload that does not fit is removed",
+			used: 601,
+			call: func(svc VideoService, video *model.Video) error {
+				_, err := svc.UploadVideo(context.Background(), UploadVideoInput{
+					CreateVideoInput: CreateVideoInput{UserID: video.UserID, Title: "Clip", FileName: "clip.mp4"},
+					Content:          strings.NewReader(strings.Repeat("x", 400)),
+					ContentType:      "video/mp4",
+				})
+				return err
+			},
+			wantErr:     ErrUploadExceedsQuota,
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &model.Video{
+				ID:          uuid.New(),
+				UserID:      uuid.New(),
+				Title:       "Test Video",
+				Status:      model.StatusPendingUpload,
+				OriginalURL: "originals/video-id/video.mp4",
+			}
+
+			updated := false
+			repo := &mockVideoRepository{
+				getByIDFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return video, nil
+				},
+				updateFn: func(ctx context.Context, v *model.Video) error {
+					updated = true
+					return nil
+				},
+				getStorageUsageFn: func(ctx context.Context, userID uuid.UUID) (*model.StorageUsage, error) {
+					if userID != video.UserID {
+						t.Errorf("usage of %s, expected owner %s", userID, video.UserID)
+					}
+					return &model.StorageUsage{UserID: userID, OriginalBytes: tt.used}, nil
+				},
+			}
+			deleted := false
+			storage := &mockObjectStorage{
+				statFn: func(ctx context.Context, key string) (*repository.ObjectInfo, error) {
+					return &repository.ObjectInfo{Key: key, Size: 400, ETag: "etag"}, nil
+				},
+				deleteFn: func(ctx context.Context, key string) error {
+					deleted = true
+					return nil
+				},
+			}
+
+			cfg := DefaultVideoServiceConfig()
+			cfg.StorageQuotaBytes = quota
+			cfg.InlineUploadMaxBytes = 1 << 20
+			svc := NewVideoService(repo, storage, &mockMessageQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+			err := tt.call(svc, video)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if updated != tt.wantUpdated {
+				t.Errorf("updated: got %v, expected %v", updated, tt.wantUpdated)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("original deleted: got %v, expected %v", deleted, tt.wantDeleted)
 			}
 		})
 	}
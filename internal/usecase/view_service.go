@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+	"github.com/hszk-dev/gostream/internal/infrastructure/cache"
+)
+
+// ViewServiceConfig holds configuration for ViewService.
+type ViewServiceConfig struct {
+	// FlushBatchSize is the maximum number of videos written to the database per statement.
+	FlushBatchSize int
+}
+
+// DefaultViewServiceConfig returns the default configuration.
+func DefaultViewServiceConfig() ViewServiceConfig {
+	return ViewServiceConfig{
+		FlushBatchSize: 500,
+	}
+}
+
+// ViewService defines the interface for view counting.
+// Videos callerID may not see are reported as repository.ErrVideoNotFound.
+type ViewService interface {
+	// RecordView counts a view of a READY video.
+	// Views are buffered and become durable on the next FlushViews.
+	RecordView(ctx context.Context, videoID, callerID uuid.UUID) error
+
+	// GetStats returns the views of a video, including those not yet flushed.
+	GetStats(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoStats, error)
+
+	// FlushViews persists all buffered views to the database.
+	// Returns the number of videos flushed.
+	FlushViews(ctx context.Context) (int, error)
+}
+
+type viewService struct {
+	videos    VideoService
+	repo      repository.VideoStatsRepository
+	counter   cache.ViewCounter
+	batchSize int
+}
+
+// NewViewService creates a new ViewService instance.
+// Videos are read through videos, so checking them is usually a cache hit.
+func NewViewService(
+	videos VideoService,
+	repo repository.VideoStatsRepository,
+	counter cache.ViewCounter,
+	cfg ViewServiceConfig,
+) ViewService {
+	batchSize := cfg.FlushBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultViewServiceConfig().FlushBatchSize
+	}
+
+	return &viewService{
+		videos:    videos,
+		repo:      repo,
+		counter:   counter,
+		batchSize: batchSize,
+	}
+}
+
+// RecordView increments the buffered counter only; the database is updated by FlushViews.
+// Trade-off: views counted since the last flush are lost if Redis loses data, which is
+// acceptable for counts in exchange for not writing to PostgreSQL per view.
+func (s *viewService) RecordView(ctx context.Context, videoID, callerID uuid.UUID) error {
+	video, err := s.visibleVideo(ctx, videoID, callerID)
+	if err != nil {
+		return err
+	}
+	if video.Status != model.StatusReady {
+		return ErrVideoNotReady
+	}
+
+	if err := s.counter.Add(ctx, videoID, 1, time.Now()); err != nil {
+		return fmt.Errorf("count view: %w", err)
+	}
+
+	return nil
+}
+
+// GetStats adds the buffered views to the stored ones. If the buffer cannot be read, the
+// stored counts are returned, as they are only behind by one flush interval.
+func (s *viewService) GetStats(ctx context.Context, videoID, callerID uuid.UUID) (*model.VideoStats, error) {
+	if _, err := s.visibleVideo(ctx, videoID, callerID); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.Get(ctx, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("get video stats: %w", err)
+	}
+
+	pending, err := s.counter.Pending(ctx, videoID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read pending views",
+			"video_id", videoID,
+			"error", err,
+		)
+		return stats, nil
+	}
+	stats.Add(pending)
+
+	return stats, nil
+}
+
+// FlushViews drains the dirty set in batches until the counter reports it drained, like
+// FlushProgress.
+// A failed batch is counted again so the next flush retries it.
+func (s *viewService) FlushViews(ctx context.Context) (int, error) {
+	flushed := 0
+
+	for {
+		batch, drained, err := s.counter.PopDirty(ctx, s.batchSize)
+		if err != nil {
+			return flushed, fmt.Errorf("pop dirty views: %w", err)
+		}
+
+		if len(batch) > 0 {
+			if err := s.repo.AddBatch(ctx, batch); err != nil {
+				for _, stats := range batch {
+					if addErr := s.counter.Add(ctx, stats.VideoID, stats.Views, stats.LastViewedAt); addErr != nil {
+						slog.ErrorContext(ctx, "failed to re-queue views after flush failure",
+							"video_id", stats.VideoID,
+							"views", stats.Views,
+							"error", addErr,
+						)
+					}
+				}
+				return flushed, fmt.Errorf("add views: %w", err)
+			}
+			flushed += len(batch)
+		}
+
+		if drained {
+			return flushed, nil
+		}
+	}
+}
+
+// visibleVideo returns the video if callerID may see it; deleted videos are not found.
+func (s *viewService) visibleVideo(ctx context.Context, videoID, callerID uuid.UUID) (*model.Video, error) {
+	video, err := s.videos.GetVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.Status == model.StatusDeleted || !video.VisibleTo(callerID) {
+		return nil, repository.ErrVideoNotFound
+	}
+	return video, nil
+}
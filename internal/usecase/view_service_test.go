@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hszk-dev/gostream/internal/domain/model"
+	"github.com/hszk-dev/gostream/internal/domain/repository"
+)
+
+func TestViewService_RecordView(t *testing.T) {
+	ownerID := uuid.New()
+
+	tests := []struct {
+		name       string
+		video      *model.Video
+		callerID   uuid.UUID
+		counterErr error
+		wantErr    error
+		wantCount  bool
+	}{
+		{
+			name:      "ready video",
+			video:     &model.Video{UserID: ownerID, Status: model.StatusReady},
+			wantCount: true,
+		},
+		{
+			name:      "owner views a private video",
+			video:     &model.Video{UserID: ownerID, Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+			callerID:  ownerID,
+			wantCount: true,
+		},
+		{
+			name:    "another user's private video",
+			video:   &model.Video{UserID: ownerID, Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "deleted video",
+			video:   &model.Video{UserID: ownerID, Status: model.StatusDeleted},
+			wantErr: repository.ErrVideoNotFound,
+		},
+		{
+			name:    "not ready",
+			video:   &model.Video{UserID: ownerID, Status: model.StatusProcessing},
+			wantErr: ErrVideoNotReady,
+		},
+		{
+			name:       "counter error",
+			video:      &model.Video{UserID: ownerID, Status: model.StatusReady},
+			counterErr: errors.New("redis down"),
+			wantErr:    errors.New("redis down"),
+			wantCount:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoID := uuid.New()
+			tt.video.ID = videoID
+			videos := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return tt.video, nil
+				},
+			}
+			counted := false
+			counter := &mockViewCounter{
+				addFn: func(ctx context.Context, id uuid.UUID, views int64, lastViewedAt time.Time) error {
+					counted = id == videoID && views == 1 && !lastViewedAt.IsZero()
+					return tt.counterErr
+				},
+			}
+			repo := &mockVideoStatsRepository{
+				addBatchFn: func(ctx context.Context, deltas []*model.VideoStats) error {
+					t.Error("RecordView must not write to the database")
+					return nil
+				},
+			}
+
+			err := NewViewService(videos, repo, counter, DefaultViewServiceConfig()).RecordView(context.Background(), videoID, tt.callerID)
+
+			if counted != tt.wantCount {
+				t.Errorf("counted = %v, want %v", counted, tt.wantCount)
+			}
+			if tt.wantErr != nil {
+				if err == nil || !errors.Is(err, tt.wantErr) && err.Error() != "count view: "+tt.wantErr.Error() {
+					t.Fatalf("RecordView() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecordView() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestViewService_GetStats(t *testing.T) {
+	videoID := uuid.New()
+	flushedAt := time.Now().Add(-time.Minute)
+	pendingAt := time.Now()
+
+	tests := []struct {
+		name       string
+		video      *model.Video
+		pendingErr error
+		wantErr    error
+		wantViews  int64
+		wantLast   time.Time
+	}{
+		{
+			name:      "stored and pending views",
+			video:     &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusReady},
+			wantViews: 12,
+			wantLast:  pendingAt,
+		},
+		{
+			name:       "pending views unavailable",
+			video:      &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusReady},
+			pendingErr: errors.New("redis down"),
+			wantViews:  10,
+			wantLast:   flushedAt,
+		},
+		{
+			name:    "private video",
+			video:   &model.Video{ID: videoID, UserID: uuid.New(), Status: model.StatusReady, Visibility: model.VisibilityPrivate},
+			wantErr: repository.ErrVideoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := &mockVideoService{
+				getVideoFn: func(ctx context.Context, id uuid.UUID) (*model.Video, error) {
+					return tt.video, nil
+				},
+			}
+			repo := &mockVideoStatsRepository{
+				getFn: func(ctx context.Context, id uuid.UUID) (*model.VideoStats, error) {
+					return &model.VideoStats{VideoID: id, Views: 10, LastViewedAt: flushedAt}, nil
+				},
+			}
+			counter := &mockViewCounter{
+				pendingFn: func(ctx context.Context, id uuid.UUID) (*model.VideoStats, error) {
+					if tt.pendingErr != nil {
+						return nil, tt.pendingErr
+					}
+					return &model.VideoStats{VideoID: id, Views: 2, LastViewedAt: pendingAt}, nil
+				},
+			}
+
+			got, err := NewViewService(videos, repo, counter, DefaultViewServiceConfig()).GetStats(context.Background(), videoID, uuid.Nil)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetStats() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Views != tt.wantViews || !got.LastViewedAt.Equal(tt.wantLast) {
+				t.Errorf("GetStats() = %+v, want %d views, last %v", got, tt.wantViews, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestViewService_FlushViews(t *testing.T) {
+	newBatch := func(n int) []*model.VideoStats {
+		batch := make([]*model.VideoStats, n)
+		for i := range batch {
+			batch[i] = &model.VideoStats{VideoID: uuid.New(), Views: 3, LastViewedAt: time.Now()}
+		}
+		return batch
+	}
+
+	t.Run("drains batches until the counter is drained", func(t *testing.T) {
+		// The second batch comes back short, as a popped video had no views, yet more remain
+		batches := [][]*model.VideoStats{newBatch(2), newBatch(1), newBatch(1)}
+		counter := &mockViewCounter{
+			popDirtyFn: func(ctx context.Context, n int) ([]*model.VideoStats, bool, error) {
+				if n != 2 {
+					t.Errorf("PopDirty n: got %d, want 2", n)
+				}
+				if len(batches) == 0 {
+					t.Fatal("PopDirty called after the counter was drained")
+				}
+				batch := batches[0]
+				batches = batches[1:]
+				return batch, len(batches) == 0, nil
+			},
+		}
+
+		writes := 0
+		repo := &mockVideoStatsRepository{
+			addBatchFn: func(ctx context.Context, deltas []*model.VideoStats) error {
+				writes++
+				return nil
+			},
+		}
+
+		svc := NewViewService(&mockVideoService{}, repo, counter, ViewServiceConfig{FlushBatchSize: 2})
+		flushed, err := svc.FlushViews(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if flushed != 4 {
+			t.Errorf("flushed: got %d, want 4", flushed)
+		}
+		if writes != 3 {
+			t.Errorf("writes: got %d, want 3", writes)
+		}
+	})
+
+	t.Run("counts batch again on database error", func(t *testing.T) {
+		batch := newBatch(2)
+		requeued := map[uuid.UUID]int64{}
+		counter := &mockViewCounter{
+			popDirtyFn: func(ctx context.Context, n int) ([]*model.VideoStats, bool, error) {
+				return batch, false, nil
+			},
+			addFn: func(ctx context.Context, videoID uuid.UUID, views int64, lastViewedAt time.Time) error {
+				requeued[videoID] += views
+				return nil
+			},
+		}
+		repo := &mockVideoStatsRepository{
+			addBatchFn: func(ctx context.Context, deltas []*model.VideoStats) error {
+				return errors.New("connection refused")
+			},
+		}
+
+		svc := NewViewService(&mockVideoService{}, repo, counter, DefaultViewServiceConfig())
+		flushed, err := svc.FlushViews(context.Background())
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if flushed != 0 {
+			t.Errorf("flushed: got %d, want 0", flushed)
+		}
+		for _, stats := range batch {
+			if requeued[stats.VideoID] != stats.Views {
+				t.Errorf("expected %d views of %s re-queued, got %d", stats.Views, stats.VideoID, requeued[stats.VideoID])
+			}
+		}
+	})
+}